@@ -0,0 +1,89 @@
+package attachment
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Attachment Entity
+// ============================================================================
+
+// ScanStatus is the outcome of scanning a stored file
+type ScanStatus string
+
+const (
+	ScanPending  ScanStatus = "PENDING"  // Not scanned yet
+	ScanClean    ScanStatus = "CLEAN"    // The scanner found nothing
+	ScanInfected ScanStatus = "INFECTED" // The scanner found a threat; the file is not kept
+	ScanFailed   ScanStatus = "FAILED"   // The scanner errored; the file is kept but withheld
+	ScanSkipped  ScanStatus = "SKIPPED"  // No scanner is configured
+)
+
+// Attachment is an inbound media file copied out of the provider, whose
+// URLs expire within minutes
+type Attachment struct {
+	ID                string           `json:"id" db:"id"`
+	TenantID          kernel.TenantID  `json:"tenant_id" db:"tenant_id"`
+	ChannelID         kernel.ChannelID `json:"channel_id" db:"channel_id"`
+	ProviderMessageID string           `json:"provider_message_id,omitempty" db:"provider_message_id"`
+	Type              string           `json:"type" db:"type"` // image, audio, video, document
+	MimeType          string           `json:"mime_type,omitempty" db:"mime_type"`
+	Filename          string           `json:"filename,omitempty" db:"filename"`
+	Size              int64            `json:"size" db:"size"`
+	SHA256            string           `json:"sha256" db:"sha256"`
+	StorageKey        string           `json:"-" db:"storage_key"` // Empty when the file was discarded
+	ScanStatus        ScanStatus       `json:"scan_status" db:"scan_status"`
+	ScanDetail        string           `json:"scan_detail,omitempty" db:"scan_detail"` // Threat name or scanner error
+	ScannedAt         *time.Time       `json:"scanned_at,omitempty" db:"scanned_at"`
+	Transcript        *Transcript      `json:"transcript,omitempty" db:"-"`
+	ImageAnalysis     *ImageAnalysis   `json:"image_analysis,omitempty" db:"-"`
+	CreatedAt         time.Time        `json:"created_at" db:"created_at"`
+}
+
+// IsAccessible reports whether workflows and operators may read the file
+func (a *Attachment) IsAccessible() bool {
+	if a.StorageKey == "" {
+		return false
+	}
+	return a.ScanStatus == ScanClean || a.ScanStatus == ScanSkipped
+}
+
+// RecordScan stores the outcome of a scan
+func (a *Attachment) RecordScan(result ScanResult, err error, now time.Time) {
+	a.ScannedAt = &now
+	switch {
+	case err != nil:
+		a.ScanStatus = ScanFailed
+		a.ScanDetail = err.Error()
+	case result.Clean:
+		a.ScanStatus = ScanClean
+		a.ScanDetail = ""
+	default:
+		a.ScanStatus = ScanInfected
+		a.ScanDetail = result.Threat
+	}
+}
+
+// NewStorageKey places a file under its tenant, so one tenant's keys never
+// address another's files
+func NewStorageKey(tenantID kernel.TenantID, id, filename string, now time.Time) string {
+	ext := strings.ToLower(path.Ext(filename))
+	if len(ext) > 10 || strings.ContainsAny(ext, "/\\?#%") {
+		ext = ""
+	}
+	return path.Join(tenantID.String(), now.UTC().Format("2006/01/02"), id+ext)
+}
+
+// ============================================================================
+// Scanning
+// ============================================================================
+
+// ScanResult is what a scanner reports for one file
+type ScanResult struct {
+	Clean  bool   `json:"clean"`
+	Threat string `json:"threat,omitempty"`
+}
@@ -0,0 +1,75 @@
+package attachmentapi
+
+import (
+	"net/url"
+
+	"github.com/Abraxas-365/relay/attachment"
+	"github.com/Abraxas-365/relay/attachment/attachmentsrv"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AttachmentHandler exposes stored inbound attachments
+type AttachmentHandler struct {
+	service *attachmentsrv.AttachmentService
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(service *attachmentsrv.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		service: service,
+	}
+}
+
+// Get returns an attachment's metadata and a fresh signed URL
+// GET /api/attachments/:id
+func (h *AttachmentHandler) Get(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	response, err := h.service.Get(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response)
+}
+
+// Rescan scans a stored attachment again
+// POST /api/attachments/:id/rescan
+func (h *AttachmentHandler) Rescan(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	response, err := h.service.Rescan(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response)
+}
+
+// ServeFile streams a locally stored file behind a signed link. The link
+// itself is the credential, so the route is public.
+// GET /attachments/files/*?expires=...&signature=...
+func (h *AttachmentHandler) ServeFile(c *fiber.Ctx) error {
+	key, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		return attachment.ErrInvalidSignature()
+	}
+	expires := int64(c.QueryInt("expires", 0))
+
+	body, contentType, err := h.service.OpenSigned(c.Context(), key, expires, c.Query("signature"))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.SendStream(body)
+}
@@ -0,0 +1,34 @@
+package attachmentapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AttachmentRoutes handles attachment route setup
+type AttachmentRoutes struct {
+	handler        *AttachmentHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewAttachmentRoutes creates a new attachment routes instance
+func NewAttachmentRoutes(handler *AttachmentHandler, authMiddleware *auth.AuthMiddleware) *AttachmentRoutes {
+	return &AttachmentRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers attachment routes on an authenticated router.
+// Rescanning requires an admin.
+func (r *AttachmentRoutes) RegisterRoutes(router fiber.Router) {
+	attachments := router.Group("/attachments")
+
+	attachments.Get("/:id", r.handler.Get)
+	attachments.Post("/:id/rescan", r.authMiddleware.RequireAdmin(), r.handler.Rescan)
+}
+
+// RegisterPublicRoutes registers the signed file links of local storage
+func (r *AttachmentRoutes) RegisterPublicRoutes(app *fiber.App) {
+	app.Get("/attachments/files/*", r.handler.ServeFile)
+}
@@ -0,0 +1,135 @@
+package attachmentinfra
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/attachment"
+)
+
+// localFilesPath is where the API serves local files behind signed URLs
+const localFilesPath = "/attachments/files/"
+
+// LocalStorage keeps attachments on disk. Signed URLs point back at this
+// server and carry an HMAC of the key and expiry.
+type LocalStorage struct {
+	dir       string
+	publicURL string
+	secret    []byte
+}
+
+var (
+	_ attachment.Storage      = (*LocalStorage)(nil)
+	_ attachment.LinkVerifier = (*LocalStorage)(nil)
+)
+
+// NewLocalStorage stores files under dir. publicURL is the externally
+// reachable base URL of this server.
+func NewLocalStorage(dir, publicURL, secret string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create attachment dir: %w", err)
+	}
+	return &LocalStorage{
+		dir:       dir,
+		publicURL: strings.TrimRight(publicURL, "/"),
+		secret:    []byte(secret),
+	}, nil
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return attachment.ErrStorageFailed().WithCause(err)
+	}
+
+	// Write aside and rename so readers never see a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return attachment.ErrStorageFailed().WithCause(err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return attachment.ErrStorageFailed().WithCause(err)
+	}
+	if err := tmp.Close(); err != nil {
+		return attachment.ErrStorageFailed().WithCause(err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return attachment.ErrStorageFailed().WithCause(err)
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, attachment.ErrAttachmentNotFound().WithDetail("key", key)
+		}
+		return nil, attachment.ErrStorageFailed().WithCause(err)
+	}
+	return file, nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return attachment.ErrStorageFailed().WithCause(err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	expires := time.Now().Add(ttl).Unix()
+	return fmt.Sprintf("%s%s%s?expires=%d&signature=%s",
+		s.publicURL, localFilesPath, key, expires, s.sign(key, expires)), nil
+}
+
+// VerifySignedURL checks a link produced by SignedURL
+func (s *LocalStorage) VerifySignedURL(key string, expires int64, signature string) error {
+	if time.Now().Unix() > expires {
+		return attachment.ErrInvalidSignature().WithDetail("reason", "link expired")
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return attachment.ErrInvalidSignature()
+	}
+	return nil
+}
+
+func (s *LocalStorage) sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path resolves a key inside the storage dir, rejecting keys that escape it
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || clean != "/"+key {
+		return "", attachment.ErrAttachmentNotFound().WithDetail("key", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
@@ -0,0 +1,102 @@
+package attachmentinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/attachment"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresAttachmentRepository is the PostgreSQL implementation of attachment.Repository
+type PostgresAttachmentRepository struct {
+	db *sqlx.DB
+}
+
+var _ attachment.Repository = (*PostgresAttachmentRepository)(nil)
+
+func NewPostgresAttachmentRepository(db *sqlx.DB) *PostgresAttachmentRepository {
+	return &PostgresAttachmentRepository{db: db}
+}
+
+// dbAttachment adds the JSONB transcript and image analysis columns
+type dbAttachment struct {
+	attachment.Attachment
+	TranscriptJSON    []byte `db:"transcript"`
+	ImageAnalysisJSON []byte `db:"image_analysis"`
+}
+
+const attachmentColumns = `
+	id, tenant_id, channel_id, provider_message_id, type, mime_type, filename,
+	size, sha256, storage_key, scan_status, scan_detail, scanned_at, transcript, image_analysis, created_at`
+
+func (r *PostgresAttachmentRepository) Save(ctx context.Context, a attachment.Attachment) error {
+	row := dbAttachment{Attachment: a}
+	if a.Transcript != nil {
+		transcript, err := json.Marshal(a.Transcript)
+		if err != nil {
+			return errx.Wrap(err, "failed to marshal transcript", errx.TypeInternal)
+		}
+		row.TranscriptJSON = transcript
+	}
+	if a.ImageAnalysis != nil {
+		analysis, err := json.Marshal(a.ImageAnalysis)
+		if err != nil {
+			return errx.Wrap(err, "failed to marshal image analysis", errx.TypeInternal)
+		}
+		row.ImageAnalysisJSON = analysis
+	}
+
+	query := `
+		INSERT INTO attachments (` + attachmentColumns + `)
+		VALUES (
+			:id, :tenant_id, :channel_id, :provider_message_id, :type, :mime_type, :filename,
+			:size, :sha256, :storage_key, :scan_status, :scan_detail, :scanned_at, :transcript, :image_analysis, :created_at
+		)
+		ON CONFLICT (id) DO UPDATE SET
+			storage_key = EXCLUDED.storage_key,
+			scan_status = EXCLUDED.scan_status,
+			scan_detail = EXCLUDED.scan_detail,
+			scanned_at = EXCLUDED.scanned_at,
+			transcript = EXCLUDED.transcript,
+			image_analysis = EXCLUDED.image_analysis`
+
+	if _, err := r.db.NamedExecContext(ctx, query, row); err != nil {
+		return errx.Wrap(err, "failed to save attachment", errx.TypeInternal).
+			WithDetail("attachment_id", a.ID)
+	}
+
+	return nil
+}
+
+func (r *PostgresAttachmentRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*attachment.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE id = $1 AND tenant_id = $2`
+
+	var row dbAttachment
+	if err := r.db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, attachment.ErrAttachmentNotFound().WithDetail("attachment_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find attachment", errx.TypeInternal).
+			WithDetail("attachment_id", id)
+	}
+
+	a := row.Attachment
+	if len(row.TranscriptJSON) > 0 {
+		if err := json.Unmarshal(row.TranscriptJSON, &a.Transcript); err != nil {
+			return nil, errx.Wrap(err, "failed to unmarshal transcript", errx.TypeInternal).
+				WithDetail("attachment_id", id)
+		}
+	}
+	if len(row.ImageAnalysisJSON) > 0 {
+		if err := json.Unmarshal(row.ImageAnalysisJSON, &a.ImageAnalysis); err != nil {
+			return nil, errx.Wrap(err, "failed to unmarshal image analysis", errx.TypeInternal).
+				WithDetail("attachment_id", id)
+		}
+	}
+
+	return &a, nil
+}
@@ -0,0 +1,155 @@
+package attachmentinfra
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/attachment"
+)
+
+// ============================================================================
+// ClamAV
+// ============================================================================
+
+// clamChunkSize stays under clamd's default StreamMaxLength chunking
+const clamChunkSize = 64 * 1024
+
+// ClamAVScanner streams files to clamd with the INSTREAM command
+type ClamAVScanner struct {
+	addr    string
+	timeout time.Duration
+}
+
+var _ attachment.Scanner = (*ClamAVScanner)(nil)
+
+// NewClamAVScanner connects to clamd at addr (host:port) for every scan
+func NewClamAVScanner(addr string, timeout time.Duration) *ClamAVScanner {
+	return &ClamAVScanner{addr: addr, timeout: timeout}
+}
+
+func (s *ClamAVScanner) Scan(ctx context.Context, body io.Reader) (attachment.ScanResult, error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return attachment.ScanResult{}, fmt.Errorf("clamd unreachable: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return attachment.ScanResult{}, fmt.Errorf("clamd write failed: %w", err)
+	}
+
+	buf := make([]byte, clamChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := body.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return attachment.ScanResult{}, fmt.Errorf("clamd write failed: %w", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return attachment.ScanResult{}, fmt.Errorf("clamd write failed: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return attachment.ScanResult{}, readErr
+		}
+	}
+
+	// A zero-length chunk ends the stream
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return attachment.ScanResult{}, fmt.Errorf("clamd write failed: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return attachment.ScanResult{}, fmt.Errorf("clamd read failed: %w", err)
+	}
+	return parseClamReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamReply reads "stream: OK" or "stream: <signature> FOUND"
+func parseClamReply(reply string) (attachment.ScanResult, error) {
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return attachment.ScanResult{Clean: true}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return attachment.ScanResult{Threat: strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		return attachment.ScanResult{}, fmt.Errorf("clamd: %s", result)
+	}
+}
+
+// ============================================================================
+// External HTTP scanner
+// ============================================================================
+
+// HTTPScanner posts files to an external scanning service, which answers
+// with {"clean": bool, "threat": "..."}
+type HTTPScanner struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+var _ attachment.Scanner = (*HTTPScanner)(nil)
+
+// NewHTTPScanner sends files to url, with token as a bearer token when set
+func NewHTTPScanner(url, token string, timeout time.Duration) *HTTPScanner {
+	return &HTTPScanner{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *HTTPScanner) Scan(ctx context.Context, body io.Reader) (attachment.ScanResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, body)
+	if err != nil {
+		return attachment.ScanResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return attachment.ScanResult{}, fmt.Errorf("scanner unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return attachment.ScanResult{}, fmt.Errorf("scanner returned status %d", resp.StatusCode)
+	}
+
+	var result attachment.ScanResult
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&result); err != nil {
+		return attachment.ScanResult{}, fmt.Errorf("invalid scanner response: %w", err)
+	}
+	if !result.Clean && result.Threat == "" {
+		result.Threat = "unspecified"
+	}
+
+	return result, nil
+}
@@ -0,0 +1,231 @@
+package attachmentinfra
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/attachment"
+)
+
+// transcriptionTimeout bounds one provider call; voice notes are short
+const transcriptionTimeout = 2 * time.Minute
+
+// ============================================================================
+// Whisper (OpenAI)
+// ============================================================================
+
+const whisperURL = "https://api.openai.com/v1/audio/transcriptions"
+
+// WhisperTranscriber uses OpenAI's transcription API
+type WhisperTranscriber struct {
+	apiKey   string
+	model    string
+	language string // Empty = detected
+	client   *http.Client
+}
+
+var _ attachment.Transcriber = (*WhisperTranscriber)(nil)
+
+func NewWhisperTranscriber(apiKey, model, language string) *WhisperTranscriber {
+	if model == "" {
+		model = "whisper-1"
+	}
+	return &WhisperTranscriber{
+		apiKey:   apiKey,
+		model:    model,
+		language: language,
+		client:   &http.Client{Timeout: transcriptionTimeout},
+	}
+}
+
+func (t *WhisperTranscriber) Transcribe(ctx context.Context, audio io.Reader, filename, mimeType string) (*attachment.Transcript, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+
+	part, err := form.CreateFormFile("file", audioFilename(filename, mimeType))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, audio); err != nil {
+		return nil, err
+	}
+	form.WriteField("model", t.model)
+	// verbose_json carries per-segment log probabilities
+	form.WriteField("response_format", "verbose_json")
+	if t.language != "" {
+		form.WriteField("language", t.language)
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, whisperURL, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+t.apiKey)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	var result struct {
+		Text     string `json:"text"`
+		Language string `json:"language"`
+		Segments []struct {
+			Start      float64 `json:"start"`
+			End        float64 `json:"end"`
+			AvgLogprob float64 `json:"avg_logprob"`
+		} `json:"segments"`
+	}
+	if err := doTranscription(t.client, req, &result); err != nil {
+		return nil, err
+	}
+
+	// Duration-weighted mean of the segments' average token probability
+	confidence := 1.0
+	var weighted, total float64
+	for _, segment := range result.Segments {
+		length := segment.End - segment.Start
+		weighted += segment.AvgLogprob * length
+		total += length
+	}
+	if total > 0 {
+		confidence = math.Exp(weighted / total)
+	}
+
+	return &attachment.Transcript{
+		Text:       result.Text,
+		Language:   result.Language,
+		Confidence: confidence,
+		Provider:   "whisper",
+	}, nil
+}
+
+// audioFilename gives Whisper the extension it detects the format from
+func audioFilename(filename, mimeType string) string {
+	if path.Ext(filename) != "" {
+		return filename
+	}
+	base, _, _ := strings.Cut(mimeType, ";")
+	switch strings.TrimSpace(base) {
+	case "audio/ogg", "audio/opus":
+		return filename + ".ogg"
+	case "audio/mpeg", "audio/mp3":
+		return filename + ".mp3"
+	case "audio/mp4", "audio/m4a", "audio/x-m4a":
+		return filename + ".m4a"
+	case "audio/webm":
+		return filename + ".webm"
+	case "audio/wav", "audio/x-wav", "audio/wave":
+		return filename + ".wav"
+	}
+	return filename
+}
+
+// ============================================================================
+// Deepgram
+// ============================================================================
+
+const deepgramURL = "https://api.deepgram.com/v1/listen"
+
+// DeepgramTranscriber uses Deepgram's pre-recorded audio API
+type DeepgramTranscriber struct {
+	apiKey   string
+	model    string
+	language string // Empty = detected
+	client   *http.Client
+}
+
+var _ attachment.Transcriber = (*DeepgramTranscriber)(nil)
+
+func NewDeepgramTranscriber(apiKey, model, language string) *DeepgramTranscriber {
+	if model == "" {
+		model = "nova-2"
+	}
+	return &DeepgramTranscriber{
+		apiKey:   apiKey,
+		model:    model,
+		language: language,
+		client:   &http.Client{Timeout: transcriptionTimeout},
+	}
+}
+
+func (t *DeepgramTranscriber) Transcribe(ctx context.Context, audio io.Reader, filename, mimeType string) (*attachment.Transcript, error) {
+	query := url.Values{}
+	query.Set("model", t.model)
+	query.Set("smart_format", "true")
+	if t.language != "" {
+		query.Set("language", t.language)
+	} else {
+		query.Set("detect_language", "true")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, deepgramURL+"?"+query.Encode(), audio)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Token "+t.apiKey)
+	if mimeType != "" {
+		req.Header.Set("Content-Type", mimeType)
+	}
+
+	var result struct {
+		Results struct {
+			Channels []struct {
+				DetectedLanguage string `json:"detected_language"`
+				Alternatives     []struct {
+					Transcript string  `json:"transcript"`
+					Confidence float64 `json:"confidence"`
+				} `json:"alternatives"`
+			} `json:"channels"`
+		} `json:"results"`
+	}
+	if err := doTranscription(t.client, req, &result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Results.Channels) == 0 || len(result.Results.Channels[0].Alternatives) == 0 {
+		return nil, fmt.Errorf("deepgram returned no transcript")
+	}
+	channel := result.Results.Channels[0]
+	best := channel.Alternatives[0]
+
+	language := channel.DetectedLanguage
+	if language == "" {
+		language = t.language
+	}
+
+	return &attachment.Transcript{
+		Text:       best.Transcript,
+		Language:   language,
+		Confidence: best.Confidence,
+		Provider:   "deepgram",
+	}, nil
+}
+
+// doTranscription sends a provider request and decodes its JSON answer
+func doTranscription(client *http.Client, req *http.Request, result any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("transcription provider unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("transcription provider returned status %d: %s", resp.StatusCode, body)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("invalid transcription response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package attachmentinfra
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/attachment"
+)
+
+const (
+	// visionTimeout bounds one provider call
+	visionTimeout = time.Minute
+
+	// visionMaxImageSize is the largest image sent inline to a provider
+	visionMaxImageSize = 20 * 1024 * 1024
+)
+
+// ============================================================================
+// OpenAI (LLM vision)
+// ============================================================================
+
+const openAIChatURL = "https://api.openai.com/v1/chat/completions"
+
+const visionPrompt = `You read images sent by customers to a business: receipts, IDs, product photos, screenshots.
+Reply with a JSON object with two fields:
+"text": all legible text in the image, line by line, exactly as written ("" when there is none);
+"description": one or two sentences saying what the image shows, in the language of its text when it has any.`
+
+// OpenAIVisionAnalyzer asks a vision-capable chat model to transcribe and
+// describe the image
+type OpenAIVisionAnalyzer struct {
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+var _ attachment.ImageAnalyzer = (*OpenAIVisionAnalyzer)(nil)
+
+func NewOpenAIVisionAnalyzer(apiKey, model string) *OpenAIVisionAnalyzer {
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	return &OpenAIVisionAnalyzer{
+		apiKey: apiKey,
+		model:  model,
+		client: &http.Client{Timeout: visionTimeout},
+	}
+}
+
+func (a *OpenAIVisionAnalyzer) AnalyzeImage(ctx context.Context, image io.Reader, mimeType string) (*attachment.ImageAnalysis, error) {
+	encoded, err := readImageBase64(image)
+	if err != nil {
+		return nil, err
+	}
+	if mimeType == "" {
+		mimeType = "image/jpeg"
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"model":           a.model,
+		"max_tokens":      1000,
+		"response_format": map[string]string{"type": "json_object"},
+		"messages": []map[string]any{
+			{"role": "system", "content": visionPrompt},
+			{"role": "user", "content": []map[string]any{
+				{"type": "image_url", "image_url": map[string]string{
+					"url": "data:" + mimeType + ";base64," + encoded,
+				}},
+			}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, openAIChatURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := doVision(a.client, req, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("vision model returned no answer")
+	}
+
+	var answer struct {
+		Text        string `json:"text"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal([]byte(result.Choices[0].Message.Content), &answer); err != nil {
+		return nil, fmt.Errorf("vision model answer is not valid JSON: %w", err)
+	}
+
+	return &attachment.ImageAnalysis{
+		Text:        strings.TrimSpace(answer.Text),
+		Description: strings.TrimSpace(answer.Description),
+		Provider:    "openai",
+	}, nil
+}
+
+// ============================================================================
+// Google Cloud Vision (OCR)
+// ============================================================================
+
+const googleVisionURL = "https://vision.googleapis.com/v1/images:annotate"
+
+// GoogleVisionAnalyzer runs Cloud Vision's document OCR. The description
+// is built from the image's top labels, so it is terser than an LLM's.
+type GoogleVisionAnalyzer struct {
+	apiKey string
+	client *http.Client
+}
+
+var _ attachment.ImageAnalyzer = (*GoogleVisionAnalyzer)(nil)
+
+func NewGoogleVisionAnalyzer(apiKey string) *GoogleVisionAnalyzer {
+	return &GoogleVisionAnalyzer{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: visionTimeout},
+	}
+}
+
+func (a *GoogleVisionAnalyzer) AnalyzeImage(ctx context.Context, image io.Reader, mimeType string) (*attachment.ImageAnalysis, error) {
+	encoded, err := readImageBase64(image)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"requests": []map[string]any{{
+			"image": map[string]string{"content": encoded},
+			"features": []map[string]any{
+				{"type": "DOCUMENT_TEXT_DETECTION"},
+				{"type": "LABEL_DETECTION", "maxResults": 5},
+			},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := googleVisionURL + "?key=" + url.QueryEscape(a.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var result struct {
+		Responses []struct {
+			FullTextAnnotation struct {
+				Text string `json:"text"`
+			} `json:"fullTextAnnotation"`
+			LabelAnnotations []struct {
+				Description string `json:"description"`
+			} `json:"labelAnnotations"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"responses"`
+	}
+	if err := doVision(a.client, req, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Responses) == 0 {
+		return nil, fmt.Errorf("cloud vision returned no result")
+	}
+	response := result.Responses[0]
+	if response.Error != nil {
+		return nil, fmt.Errorf("cloud vision: %s", response.Error.Message)
+	}
+
+	labels := make([]string, 0, len(response.LabelAnnotations))
+	for _, label := range response.LabelAnnotations {
+		labels = append(labels, label.Description)
+	}
+
+	return &attachment.ImageAnalysis{
+		Text:        strings.TrimSpace(response.FullTextAnnotation.Text),
+		Description: strings.Join(labels, ", "),
+		Provider:    "google_vision",
+	}, nil
+}
+
+// readImageBase64 reads an image for inline upload, refusing oversized ones
+func readImageBase64(image io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(image, visionMaxImageSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > visionMaxImageSize {
+		return "", fmt.Errorf("image exceeds %d bytes", visionMaxImageSize)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// doVision sends a provider request and decodes its JSON answer
+func doVision(client *http.Client, req *http.Request, result any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vision provider unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("vision provider returned status %d: %s", resp.StatusCode, body)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("invalid vision response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package attachmentsrv
+
+import (
+	"context"
+	"log"
+
+	"github.com/Abraxas-365/relay/attachment"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/featureflag"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ImageUnderstandingConfigKey is the channel trigger config key a workflow
+// sets to true to receive image analysis
+const ImageUnderstandingConfigKey = "image_understanding"
+
+// WorkflowImagePolicy analyzes a tenant's images only when the tenant has
+// the image_understanding flag on and an active channel workflow opts in
+// through its trigger config
+type WorkflowImagePolicy struct {
+	flags        featureflag.Checker
+	workflowRepo engine.WorkflowRepository
+}
+
+var _ attachment.ImagePolicy = (*WorkflowImagePolicy)(nil)
+
+func NewWorkflowImagePolicy(flags featureflag.Checker, workflowRepo engine.WorkflowRepository) *WorkflowImagePolicy {
+	return &WorkflowImagePolicy{
+		flags:        flags,
+		workflowRepo: workflowRepo,
+	}
+}
+
+func (p *WorkflowImagePolicy) ShouldAnalyzeImages(ctx context.Context, tenantID kernel.TenantID) bool {
+	if p.flags == nil || !p.flags.IsEnabled(ctx, tenantID, featureflag.FlagImageUnderstanding) {
+		return false
+	}
+
+	trigger := engine.WorkflowTrigger{Type: engine.TriggerTypeChannelWebhook}
+	workflows, err := p.workflowRepo.FindActiveByTrigger(ctx, trigger, tenantID)
+	if err != nil {
+		log.Printf("⚠️  Failed to look up image understanding opt-ins for tenant %s: %v", tenantID.String(), err)
+		return false
+	}
+
+	for _, wf := range workflows {
+		if enabled, _ := wf.Trigger.Config[ImageUnderstandingConfigKey].(bool); enabled {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,442 @@
+package attachmentsrv
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"time"
+
+	"github.com/Abraxas-365/relay/attachment"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// defaultMaxSize applies to channels that do not declare a limit
+const defaultMaxSize = 25 * 1024 * 1024
+
+// AttachmentService copies inbound media into tenant-scoped storage, scans
+// it and hands out signed URLs to files that passed
+type AttachmentService struct {
+	repo           attachment.Repository
+	storage        attachment.Storage
+	scanner        attachment.Scanner      // nil = files are not scanned
+	channelManager channels.ChannelManager // nil = media is fetched by URL only
+	linkTTL        time.Duration
+	httpClient     *http.Client
+
+	transcriber   attachment.Transcriber // nil = voice notes stay audio only
+	minConfidence float64
+
+	imageAnalyzer attachment.ImageAnalyzer // nil = images are not analyzed
+	imagePolicy   attachment.ImagePolicy
+}
+
+var _ channels.AttachmentIngester = (*AttachmentService)(nil)
+
+func NewAttachmentService(
+	repo attachment.Repository,
+	storage attachment.Storage,
+	scanner attachment.Scanner,
+	channelManager channels.ChannelManager,
+	linkTTL time.Duration,
+) *AttachmentService {
+	return &AttachmentService{
+		repo:           repo,
+		storage:        storage,
+		scanner:        scanner,
+		channelManager: channelManager,
+		linkTTL:        linkTTL,
+		httpClient:     &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// SetTranscriber enables voice note transcription. Transcripts scoring
+// below minConfidence are flagged low_confidence.
+func (s *AttachmentService) SetTranscriber(transcriber attachment.Transcriber, minConfidence float64) {
+	s.transcriber = transcriber
+	s.minConfidence = minConfidence
+}
+
+// SetImageAnalyzer enables image understanding for the tenants and
+// workflows the policy allows
+func (s *AttachmentService) SetImageAnalyzer(analyzer attachment.ImageAnalyzer, policy attachment.ImagePolicy) {
+	s.imageAnalyzer = analyzer
+	s.imagePolicy = policy
+}
+
+// ============================================================================
+// Ingestion
+// ============================================================================
+
+// IngestAttachments implements channels.AttachmentIngester. Each attachment
+// is replaced by the stored copy: workflows and the transcript see its ID,
+// scan status and a signed URL, or no URL when the file was withheld.
+func (s *AttachmentService) IngestAttachments(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage) {
+	content := &msg.Content
+	if len(content.Attachments) == 0 && content.MediaURL != "" {
+		content.Attachments = []channels.Attachment{{
+			Type:     content.Type,
+			URL:      content.MediaURL,
+			MimeType: content.MimeType,
+			Filename: content.Filename,
+			Caption:  content.Caption,
+		}}
+	}
+	if len(content.Attachments) == 0 {
+		return
+	}
+
+	maxSize := int64(defaultMaxSize)
+	if features, err := channel.GetFeatures(); err == nil && features.MaxAttachmentSize > 0 {
+		maxSize = features.MaxAttachmentSize
+	}
+	analyzeImages := s.shouldAnalyzeImages(ctx, channel, content.Attachments)
+
+	for i := range content.Attachments {
+		att := &content.Attachments[i]
+
+		stored, err := s.ingest(ctx, channel, msg, *att, maxSize, analyzeImages)
+		if err != nil {
+			log.Printf("⚠️  Failed to store attachment from %s: %v", msg.SenderID, err)
+			// The provider link is never passed on unscanned
+			att.URL = ""
+			att.ScanStatus = string(attachment.ScanFailed)
+			continue
+		}
+
+		att.AttachmentID = stored.ID
+		att.ScanStatus = string(stored.ScanStatus)
+		att.Size = stored.Size
+		att.MimeType = stored.MimeType
+		att.URL = ""
+		if stored.IsAccessible() {
+			if att.URL, err = s.storage.SignedURL(ctx, stored.StorageKey, s.linkTTL); err != nil {
+				log.Printf("⚠️  Failed to sign attachment %s: %v", stored.ID, err)
+			}
+		}
+
+		if stored.Transcript != nil {
+			applyTranscript(msg, stored)
+		}
+		if stored.ImageAnalysis != nil {
+			applyImageAnalysis(msg, stored)
+		}
+	}
+
+	if content.MediaURL != "" {
+		content.MediaURL = content.Attachments[0].URL
+	}
+}
+
+// ingest downloads one attachment to a temp file, scans it and stores it.
+// Infected files are recorded but not kept.
+func (s *AttachmentService) ingest(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage, att channels.Attachment, maxSize int64, analyzeImages bool) (*attachment.Attachment, error) {
+	body, mimeType, err := s.fetch(ctx, channel, att)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp("", "relay-attachment-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("download interrupted: %w", err)
+	}
+	if size > maxSize {
+		return nil, attachment.ErrAttachmentTooLarge().
+			WithDetail("max_bytes", maxSize)
+	}
+
+	now := time.Now()
+	stored := &attachment.Attachment{
+		ID:                uuid.NewString(),
+		TenantID:          channel.TenantID,
+		ChannelID:         channel.ID,
+		ProviderMessageID: msg.MessageID.String(),
+		Type:              att.Type,
+		MimeType:          mimeType,
+		Filename:          att.Filename,
+		Size:              size,
+		SHA256:            hex.EncodeToString(hash.Sum(nil)),
+		ScanStatus:        attachment.ScanSkipped,
+		CreatedAt:         now,
+	}
+	if stored.Filename == "" {
+		stored.Filename = defaultFilename(stored.ID, mimeType)
+	}
+
+	if s.scanner != nil {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		result, err := s.scanner.Scan(ctx, tmp)
+		stored.RecordScan(result, err, time.Now())
+		if stored.ScanStatus == attachment.ScanInfected {
+			log.Printf("🦠 Infected attachment from %s discarded: %s", msg.SenderID, stored.ScanDetail)
+		}
+	}
+
+	if stored.ScanStatus != attachment.ScanInfected {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		key := attachment.NewStorageKey(channel.TenantID, stored.ID, stored.Filename, now)
+		if err := s.storage.Put(ctx, key, tmp, size, mimeType); err != nil {
+			return nil, err
+		}
+		stored.StorageKey = key
+	}
+
+	if s.transcriber != nil && stored.IsAccessible() && attachment.IsAudio(att.Type, mimeType) {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		stored.Transcript = s.transcribe(ctx, tmp, stored)
+	}
+
+	if analyzeImages && stored.IsAccessible() && attachment.IsImage(att.Type, mimeType) {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		stored.ImageAnalysis = s.analyzeImage(ctx, tmp, stored)
+	}
+
+	if err := s.repo.Save(ctx, *stored); err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// transcribe runs the transcriber on a voice note. A failed transcription
+// leaves the note as audio only.
+func (s *AttachmentService) transcribe(ctx context.Context, audio io.Reader, stored *attachment.Attachment) *attachment.Transcript {
+	transcript, err := s.transcriber.Transcribe(ctx, audio, stored.Filename, stored.MimeType)
+	if err != nil {
+		log.Printf("⚠️  Failed to transcribe attachment %s: %v", stored.ID, err)
+		return nil
+	}
+	if transcript.Text == "" {
+		return nil
+	}
+
+	transcript.LowConfidence = transcript.Confidence < s.minConfidence
+	if transcript.LowConfidence {
+		log.Printf("🎙️  Low-confidence transcript for attachment %s (%.2f)", stored.ID, transcript.Confidence)
+	}
+	return transcript
+}
+
+// shouldAnalyzeImages asks the policy once per message, and only when the
+// message carries an image
+func (s *AttachmentService) shouldAnalyzeImages(ctx context.Context, channel *channels.Channel, attachments []channels.Attachment) bool {
+	if s.imageAnalyzer == nil || s.imagePolicy == nil {
+		return false
+	}
+	for _, att := range attachments {
+		if attachment.IsImage(att.Type, att.MimeType) {
+			return s.imagePolicy.ShouldAnalyzeImages(ctx, channel.TenantID)
+		}
+	}
+	return false
+}
+
+// analyzeImage runs the vision provider on an image. A failed analysis
+// leaves the image as is.
+func (s *AttachmentService) analyzeImage(ctx context.Context, image io.Reader, stored *attachment.Attachment) *attachment.ImageAnalysis {
+	analysis, err := s.imageAnalyzer.AnalyzeImage(ctx, image, stored.MimeType)
+	if err != nil {
+		log.Printf("⚠️  Failed to analyze attachment %s: %v", stored.ID, err)
+		return nil
+	}
+	if analysis.Text == "" && analysis.Description == "" {
+		return nil
+	}
+	return analysis
+}
+
+// fetch opens the media through the channel's adapter when it needs
+// credentials, otherwise by its URL
+func (s *AttachmentService) fetch(ctx context.Context, channel *channels.Channel, att channels.Attachment) (io.ReadCloser, string, error) {
+	if s.channelManager != nil {
+		if adapter, err := s.channelManager.GetAdapter(channel.ID); err == nil {
+			if fetcher, ok := adapter.(channels.MediaFetcher); ok {
+				return fetcher.FetchMedia(ctx, att)
+			}
+		}
+	}
+
+	if att.URL == "" {
+		return nil, "", fmt.Errorf("attachment has no url")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, att.URL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download attachment: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("attachment download returned status %d", resp.StatusCode)
+	}
+
+	mimeType := att.MimeType
+	if mimeType == "" {
+		mimeType = resp.Header.Get("Content-Type")
+	}
+	return resp.Body, mimeType, nil
+}
+
+// ============================================================================
+// Access
+// ============================================================================
+
+// Get returns an attachment with a fresh signed URL when it may be read
+func (s *AttachmentService) Get(ctx context.Context, id string, tenantID kernel.TenantID) (*attachment.AttachmentResponse, error) {
+	stored, err := s.repo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.respond(ctx, stored)
+}
+
+// Rescan scans a stored file again, e.g. after the scanner was down.
+// A file found infected is deleted.
+func (s *AttachmentService) Rescan(ctx context.Context, id string, tenantID kernel.TenantID) (*attachment.AttachmentResponse, error) {
+	stored, err := s.repo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if stored.StorageKey == "" {
+		return nil, attachment.ErrAttachmentNotRescannable().WithDetail("attachment_id", id)
+	}
+	if s.scanner == nil {
+		return s.respond(ctx, stored)
+	}
+
+	body, err := s.storage.Open(ctx, stored.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	result, scanErr := s.scanner.Scan(ctx, body)
+	body.Close()
+	stored.RecordScan(result, scanErr, time.Now())
+
+	if stored.ScanStatus == attachment.ScanInfected {
+		if err := s.storage.Delete(ctx, stored.StorageKey); err != nil {
+			return nil, err
+		}
+		stored.StorageKey = ""
+		log.Printf("🦠 Attachment %s found infected on rescan: %s", stored.ID, stored.ScanDetail)
+	}
+
+	if err := s.repo.Save(ctx, *stored); err != nil {
+		return nil, err
+	}
+
+	return s.respond(ctx, stored)
+}
+
+// OpenSigned serves a file behind a link signed by this server (local
+// storage). Other storages hand out their own links.
+func (s *AttachmentService) OpenSigned(ctx context.Context, key string, expires int64, signature string) (io.ReadCloser, string, error) {
+	verifier, ok := s.storage.(attachment.LinkVerifier)
+	if !ok {
+		return nil, "", attachment.ErrAttachmentNotFound()
+	}
+	if err := verifier.VerifySignedURL(key, expires, signature); err != nil {
+		return nil, "", err
+	}
+
+	body, err := s.storage.Open(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return body, contentType, nil
+}
+
+func (s *AttachmentService) respond(ctx context.Context, stored *attachment.Attachment) (*attachment.AttachmentResponse, error) {
+	response := &attachment.AttachmentResponse{Attachment: stored}
+	if !stored.IsAccessible() {
+		return response, nil
+	}
+
+	url, err := s.storage.SignedURL(ctx, stored.StorageKey, s.linkTTL)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(s.linkTTL)
+	response.URL = url
+	response.URLExpiresAt = &expiresAt
+
+	return response, nil
+}
+
+// applyTranscript makes a voice note readable as text. The audio stays in
+// the attachments; the transcript's provenance goes under
+// metadata.transcription so workflows can treat low-confidence text with care.
+func applyTranscript(msg *channels.IncomingMessage, stored *attachment.Attachment) {
+	transcript := stored.Transcript
+	if msg.Content.Text == "" {
+		msg.Content.Text = transcript.Text
+	}
+
+	if msg.Metadata == nil {
+		msg.Metadata = make(map[string]any)
+	}
+	msg.Metadata["transcription"] = map[string]any{
+		"attachment_id":  stored.ID,
+		"text":           transcript.Text,
+		"language":       transcript.Language,
+		"confidence":     transcript.Confidence,
+		"provider":       transcript.Provider,
+		"low_confidence": transcript.LowConfidence,
+	}
+}
+
+// applyImageAnalysis puts what was read from an image under
+// metadata.image_analysis. The caption stays the message text.
+func applyImageAnalysis(msg *channels.IncomingMessage, stored *attachment.Attachment) {
+	analysis := stored.ImageAnalysis
+	if msg.Metadata == nil {
+		msg.Metadata = make(map[string]any)
+	}
+	msg.Metadata["image_analysis"] = map[string]any{
+		"attachment_id": stored.ID,
+		"text":          analysis.Text,
+		"description":   analysis.Description,
+		"provider":      analysis.Provider,
+	}
+}
+
+// defaultFilename names files that arrive without one, keeping an extension
+// so signed URLs serve the right content type
+func defaultFilename(id, mimeType string) string {
+	if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+		return id + exts[0]
+	}
+	return id
+}
@@ -0,0 +1,15 @@
+package attachment
+
+import "time"
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+// AttachmentResponse is an attachment with a fresh signed URL when it may
+// be read
+type AttachmentResponse struct {
+	*Attachment
+	URL          string     `json:"url,omitempty"`
+	URLExpiresAt *time.Time `json:"url_expires_at,omitempty"`
+}
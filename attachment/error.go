@@ -0,0 +1,54 @@
+package attachment
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("ATTACHMENT")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeAttachmentNotFound       = ErrRegistry.Register("ATTACHMENT_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Attachment not found")
+	CodeAttachmentWithheld       = ErrRegistry.Register("ATTACHMENT_WITHHELD", errx.TypeBusiness, http.StatusConflict, "Attachment did not pass scanning")
+	CodeAttachmentTooLarge       = ErrRegistry.Register("ATTACHMENT_TOO_LARGE", errx.TypeValidation, http.StatusRequestEntityTooLarge, "Attachment exceeds the channel's size limit")
+	CodeInvalidSignature         = ErrRegistry.Register("INVALID_SIGNATURE", errx.TypeAuthorization, http.StatusForbidden, "Invalid or expired attachment link")
+	CodeStorageFailed            = ErrRegistry.Register("STORAGE_FAILED", errx.TypeExternal, http.StatusBadGateway, "Attachment storage failed")
+	CodeAttachmentNotRescannable = ErrRegistry.Register("ATTACHMENT_NOT_RESCANNABLE", errx.TypeBusiness, http.StatusConflict, "Attachment file is no longer stored")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrAttachmentNotFound() *errx.Error {
+	return ErrRegistry.New(CodeAttachmentNotFound)
+}
+
+func ErrAttachmentWithheld() *errx.Error {
+	return ErrRegistry.New(CodeAttachmentWithheld)
+}
+
+func ErrAttachmentTooLarge() *errx.Error {
+	return ErrRegistry.New(CodeAttachmentTooLarge)
+}
+
+func ErrInvalidSignature() *errx.Error {
+	return ErrRegistry.New(CodeInvalidSignature)
+}
+
+func ErrStorageFailed() *errx.Error {
+	return ErrRegistry.New(CodeStorageFailed)
+}
+
+func ErrAttachmentNotRescannable() *errx.Error {
+	return ErrRegistry.New(CodeAttachmentNotRescannable)
+}
@@ -0,0 +1,65 @@
+package attachment
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// Repository persists attachment metadata
+type Repository interface {
+	// Save creates or replaces an attachment
+	Save(ctx context.Context, attachment Attachment) error
+
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*Attachment, error)
+}
+
+// ============================================================================
+// Storage and Scanning
+// ============================================================================
+
+// Storage holds attachment files. Keys come from NewStorageKey.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+
+	// SignedURL returns a URL that reads the file without credentials until it expires
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Scanner inspects a file before workflows can read it (ClamAV, an
+// external scanning service, ...)
+type Scanner interface {
+	Scan(ctx context.Context, body io.Reader) (ScanResult, error)
+}
+
+// Transcriber turns audio into text (Whisper, Deepgram, ...). Confidence
+// is left to the caller to judge.
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio io.Reader, filename, mimeType string) (*Transcript, error)
+}
+
+// ImageAnalyzer reads the text on an image and describes it (an LLM with
+// vision, an OCR service, ...)
+type ImageAnalyzer interface {
+	AnalyzeImage(ctx context.Context, image io.Reader, mimeType string) (*ImageAnalysis, error)
+}
+
+// ImagePolicy decides whether a tenant's inbound images are analyzed.
+// Vision calls are billed per image, so analysis is opt-in.
+type ImagePolicy interface {
+	ShouldAnalyzeImages(ctx context.Context, tenantID kernel.TenantID) bool
+}
+
+// LinkVerifier is implemented by storages that serve their own signed URLs
+// instead of handing out provider links (local disk)
+type LinkVerifier interface {
+	VerifySignedURL(key string, expires int64, signature string) error
+}
@@ -0,0 +1,24 @@
+package attachment
+
+// ============================================================================
+// Transcription
+// ============================================================================
+
+// Transcript is the text of an audio attachment (a voice note)
+type Transcript struct {
+	Text          string  `json:"text"`
+	Language      string  `json:"language,omitempty"`
+	Confidence    float64 `json:"confidence"` // 0-1; providers without scores report 1
+	Provider      string  `json:"provider"`
+	LowConfidence bool    `json:"low_confidence"`
+}
+
+// IsAudio reports whether an attachment of this type and mime type can be
+// transcribed
+func IsAudio(attachmentType, mimeType string) bool {
+	switch attachmentType {
+	case "audio", "voice", "ptt":
+		return true
+	}
+	return len(mimeType) > 6 && mimeType[:6] == "audio/"
+}
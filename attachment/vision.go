@@ -0,0 +1,25 @@
+package attachment
+
+import "strings"
+
+// ============================================================================
+// Image Understanding
+// ============================================================================
+
+// ImageAnalysis is what a vision provider read from an image: the text on
+// it (receipts, IDs, labels) and a short description of what it shows
+type ImageAnalysis struct {
+	Text        string `json:"text,omitempty"`
+	Description string `json:"description,omitempty"`
+	Provider    string `json:"provider"`
+}
+
+// IsImage reports whether an attachment of this type and mime type can be
+// analyzed
+func IsImage(attachmentType, mimeType string) bool {
+	switch attachmentType {
+	case "image", "sticker":
+		return true
+	}
+	return strings.HasPrefix(mimeType, "image/")
+}
@@ -0,0 +1,76 @@
+package channels
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Message Buffering
+// ============================================================================
+
+// DefaultBufferWindow ventana de agrupación si el canal no la configura
+const DefaultBufferWindow = 5 * time.Second
+
+// BufferKey identifica la ráfaga de un remitente en un canal. Lleva el
+// tenant para que cualquier instancia pueda cargar el canal al vaciarla.
+type BufferKey struct {
+	TenantID  kernel.TenantID  `json:"tenant_id"`
+	ChannelID kernel.ChannelID `json:"channel_id"`
+	SenderID  string           `json:"sender_id"`
+}
+
+// BufferSettings ventana de agrupación de un canal
+type BufferSettings struct {
+	Window         time.Duration // Tiempo que se espera antes de vaciar
+	ResetOnMessage bool          // Cada mensaje reinicia la ventana
+	MaxMessages    int           // Con este número se vacía sin esperar; 0 = sin límite
+}
+
+// NewBufferSettings convierte la configuración de un canal en ajustes de
+// buffer; los segundos sin configurar usan DefaultBufferWindow
+func NewBufferSettings(timeSeconds int, resetOnMessage bool, maxMessages int) BufferSettings {
+	window := time.Duration(timeSeconds) * time.Second
+	if window <= 0 {
+		window = DefaultBufferWindow
+	}
+	return BufferSettings{
+		Window:         window,
+		ResetOnMessage: resetOnMessage,
+		MaxMessages:    maxMessages,
+	}
+}
+
+// BufferedMessage mensaje en espera dentro de una ráfaga
+type BufferedMessage struct {
+	Message    IncomingMessage `json:"message"`
+	ReceivedAt time.Time       `json:"received_at"`
+}
+
+// BufferedBatch ráfaga vencida. Solo la instancia que la reclama la recibe.
+type BufferedBatch struct {
+	Key      BufferKey
+	Messages []BufferedMessage // En orden de llegada
+	ClaimID  string            // Identifica el reclamo ante Ack y Requeue
+}
+
+// FirstAt hora de llegada del primer mensaje
+func (b BufferedBatch) FirstAt() time.Time {
+	if len(b.Messages) == 0 {
+		return time.Time{}
+	}
+	return b.Messages[0].ReceivedAt
+}
+
+// LastAt hora de llegada del último mensaje
+func (b BufferedBatch) LastAt() time.Time {
+	if len(b.Messages) == 0 {
+		return time.Time{}
+	}
+	return b.Messages[len(b.Messages)-1].ReceivedAt
+}
+
+// BufferCombiner une los mensajes de una ráfaga en uno solo; cada tipo de
+// canal registra el suyo
+type BufferCombiner func(batch BufferedBatch) *IncomingMessage
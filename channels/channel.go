@@ -1,6 +1,7 @@
 package channels
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"time"
 
@@ -13,16 +14,17 @@ import (
 
 // Channel estructura única que se guarda en la DB
 type Channel struct {
-	ID          kernel.ChannelID `db:"id" json:"id"`
-	TenantID    kernel.TenantID  `db:"tenant_id" json:"tenant_id"`
-	Type        ChannelType      `db:"type" json:"type"`
-	Name        string           `db:"name" json:"name"`
-	Description string           `db:"description" json:"description"`
-	Config      json.RawMessage  `db:"config" json:"config"` // JSON que se deserializa según Type
-	IsActive    bool             `db:"is_active" json:"is_active"`
-	WebhookURL  string           `db:"webhook_url" json:"webhook_url"`
-	CreatedAt   time.Time        `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time        `db:"updated_at" json:"updated_at"`
+	ID          kernel.ChannelID   `db:"id" json:"id"`
+	TenantID    kernel.TenantID    `db:"tenant_id" json:"tenant_id"`
+	Type        ChannelType        `db:"type" json:"type"`
+	Name        string             `db:"name" json:"name"`
+	Description string             `db:"description" json:"description"`
+	Config      json.RawMessage    `db:"config" json:"config"` // JSON que se deserializa según Type
+	IsActive    bool               `db:"is_active" json:"is_active"`
+	Environment kernel.Environment `db:"environment" json:"environment"` // sandbox o production
+	WebhookURL  string             `db:"webhook_url" json:"webhook_url"`
+	CreatedAt   time.Time          `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `db:"updated_at" json:"updated_at"`
 }
 
 // ChannelType tipo de canal
@@ -38,6 +40,8 @@ const (
 	ChannelTypeWebChat   ChannelType = "WEBCHAT"
 	ChannelTypeVoice     ChannelType = "VOICE"
 	ChannelTypeTestHTTP  ChannelType = "TEST_HTTP"
+	ChannelTypeRCS       ChannelType = "RCS"
+	ChannelTypeApple     ChannelType = "APPLE_MESSAGES"
 )
 
 // ============================================================================
@@ -60,6 +64,11 @@ type ChannelFeatures struct {
 	SupportsContacts            bool     `json:"supports_contacts"`
 	SupportsReactions           bool     `json:"supports_reactions"`
 	SupportsThreads             bool     `json:"supports_threads"`
+	SupportsMessageEditing      bool     `json:"supports_message_editing"`     // Adapter implementa MessageEditor
+	SupportsTimePicker          bool     `json:"supports_time_picker"`         // Interactive con TimeSlots
+	SupportsRichLinks           bool     `json:"supports_rich_links"`          // Interactive con URL
+	RequiresMessageBody         bool     `json:"requires_message_body"`        // Los interactivos llevan texto (body) para dispositivos que no los muestran
+	EntryPointFields            []string `json:"entry_point_fields,omitempty"` // Datos del punto de entrada en la metadata de cada mensaje entrante
 	MaxMessageLength            int      `json:"max_message_length"`
 	MaxAttachmentSize           int64    `json:"max_attachment_size_bytes"`
 	SupportedMimeTypes          []string `json:"supported_mime_types,omitempty"`
@@ -81,7 +90,14 @@ type ChannelConfig interface {
 // WhatsApp Config
 // ============================================================================
 
-// WhatsAppConfig configuración para WhatsApp
+// Proveedores de WhatsApp con implementación en el adapter
+const (
+	WhatsAppProviderMeta   = "meta"
+	WhatsAppProviderTwilio = "twilio"
+)
+
+// WhatsAppConfig configuración para WhatsApp. Con provider twilio se usan los
+// campos de Twilio; con cualquier otro, los de la Cloud API de Meta.
 type WhatsAppConfig struct {
 	Provider           string `json:"provider"` // meta, twilio, infobip
 	PhoneNumberID      string `json:"phone_number_id"`
@@ -91,6 +107,12 @@ type WhatsAppConfig struct {
 	WebhookVerifyToken string `json:"webhook_verify_token"`
 	APIVersion         string `json:"api_version,omitempty"` // v24.0
 
+	// Twilio
+	AccountSID          string `json:"account_sid,omitempty"`
+	AuthToken           string `json:"auth_token,omitempty"`            // Firma los webhooks (X-Twilio-Signature)
+	PhoneNumber         string `json:"phone_number,omitempty"`          // Remitente en formato E.164
+	MessagingServiceSID string `json:"messaging_service_sid,omitempty"` // Alternativa al remitente
+
 	// Buffer configuration
 	BufferEnabled        bool `json:"buffer_enabled,omitempty"`          // Enable message buffering
 	BufferTimeSeconds    int  `json:"buffer_time_seconds,omitempty"`     // Time window to buffer messages (e.g., 5 seconds)
@@ -101,11 +123,23 @@ func (c WhatsAppConfig) Validate() error {
 	if c.Provider == "" {
 		return ErrInvalidChannelConfig().WithDetail("reason", "provider is required")
 	}
-	if c.PhoneNumberID == "" {
-		return ErrInvalidChannelConfig().WithDetail("reason", "phone_number_id is required")
-	}
-	if c.AccessToken == "" {
-		return ErrInvalidChannelConfig().WithDetail("reason", "access_token is required")
+	if c.Provider == WhatsAppProviderTwilio {
+		if c.AccountSID == "" {
+			return ErrInvalidChannelConfig().WithDetail("reason", "account_sid is required")
+		}
+		if c.AuthToken == "" {
+			return ErrInvalidChannelConfig().WithDetail("reason", "auth_token is required")
+		}
+		if c.PhoneNumber == "" && c.MessagingServiceSID == "" {
+			return ErrInvalidChannelConfig().WithDetail("reason", "phone_number or messaging_service_sid is required")
+		}
+	} else {
+		if c.PhoneNumberID == "" {
+			return ErrInvalidChannelConfig().WithDetail("reason", "phone_number_id is required")
+		}
+		if c.AccessToken == "" {
+			return ErrInvalidChannelConfig().WithDetail("reason", "access_token is required")
+		}
 	}
 
 	// Validate buffer config
@@ -130,7 +164,7 @@ func (c WhatsAppConfig) GetType() ChannelType {
 }
 
 func (c WhatsAppConfig) GetFeatures() ChannelFeatures {
-	return ChannelFeatures{
+	features := ChannelFeatures{
 		SupportsText:                true,
 		SupportsAttachments:         true,
 		SupportsImages:              true,
@@ -154,6 +188,17 @@ func (c WhatsAppConfig) GetFeatures() ChannelFeatures {
 			"application/pdf",
 		},
 	}
+
+	// Twilio solo envía botones y listas como plantillas de contenido
+	if c.Provider == WhatsAppProviderTwilio {
+		features.SupportsInteractiveMessages = false
+		features.SupportsButtons = false
+		features.SupportsQuickReplies = false
+		features.SupportsContacts = false
+		features.SupportsReactions = false
+	}
+
+	return features
 }
 
 // ============================================================================
@@ -514,6 +559,139 @@ func (c WebChatConfig) GetFeatures() ChannelFeatures {
 	}
 }
 
+// ============================================================================
+// RCS Config
+// ============================================================================
+
+// RCSConfig configuración para RCS Business Messaging de Google. El agente
+// se autentica con la clave de su cuenta de servicio.
+type RCSConfig struct {
+	Provider          string `json:"provider"`               // google
+	AgentID           string `json:"agent_id"`               // ID del agente en la consola de RBM
+	ServiceAccountKey string `json:"service_account_key"`    // JSON de la clave de la cuenta de servicio
+	ClientToken       string `json:"client_token,omitempty"` // Token del webhook; firma los eventos (X-Goog-Signature)
+}
+
+func (c RCSConfig) Validate() error {
+	if c.AgentID == "" {
+		return ErrInvalidChannelConfig().WithDetail("reason", "agent_id is required")
+	}
+	if c.ServiceAccountKey == "" {
+		return ErrInvalidChannelConfig().WithDetail("reason", "service_account_key is required")
+	}
+
+	var key struct {
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+	}
+	if err := json.Unmarshal([]byte(c.ServiceAccountKey), &key); err != nil {
+		return ErrInvalidChannelConfig().WithDetail("reason", "service_account_key is not a JSON key file")
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return ErrInvalidChannelConfig().WithDetail("reason", "service_account_key needs client_email and private_key")
+	}
+
+	return nil
+}
+
+func (c RCSConfig) GetProvider() string {
+	return c.Provider
+}
+
+func (c RCSConfig) GetType() ChannelType {
+	return ChannelTypeRCS
+}
+
+func (c RCSConfig) GetFeatures() ChannelFeatures {
+	return ChannelFeatures{
+		SupportsText:                true,
+		SupportsAttachments:         true,
+		SupportsImages:              true,
+		SupportsAudio:               true,
+		SupportsVideo:               true,
+		SupportsDocuments:           true,
+		SupportsInteractiveMessages: true, // Rich cards y sugerencias
+		SupportsButtons:             true,
+		SupportsQuickReplies:        true,
+		SupportsTemplates:           false,
+		SupportsLocation:            true, // Se envía como acción "ver ubicación"
+		SupportsContacts:            false,
+		SupportsReactions:           false,
+		SupportsThreads:             false,
+		MaxMessageLength:            3072,
+		MaxAttachmentSize:           100 * 1024 * 1024, // 100MB
+		SupportedMimeTypes: []string{
+			"image/jpeg", "image/png", "image/gif",
+			"video/mp4", "video/mpeg", "video/webm",
+			"audio/mpeg", "audio/mp4", "audio/aac", "audio/ogg",
+			"application/pdf",
+		},
+	}
+}
+
+// ============================================================================
+// Apple Messages for Business Config
+// ============================================================================
+
+// AppleConfig configuración para Apple Messages for Business a través de la
+// API de proveedores de mensajería (CSP). Apple firma sus webhooks y el
+// proveedor sus envíos con JWT (HS256) sobre el mismo secreto.
+type AppleConfig struct {
+	Provider   string `json:"provider"`    // apple
+	BusinessID string `json:"business_id"` // ID de la empresa en Apple Business Register
+	CSPID      string `json:"csp_id"`      // ID del proveedor de mensajería
+	Secret     string `json:"secret"`      // Secreto del proveedor en base64
+}
+
+func (c AppleConfig) Validate() error {
+	if c.BusinessID == "" {
+		return ErrInvalidChannelConfig().WithDetail("reason", "business_id is required")
+	}
+	if c.CSPID == "" {
+		return ErrInvalidChannelConfig().WithDetail("reason", "csp_id is required")
+	}
+	if c.Secret == "" {
+		return ErrInvalidChannelConfig().WithDetail("reason", "secret is required")
+	}
+	if _, err := base64.StdEncoding.DecodeString(c.Secret); err != nil {
+		return ErrInvalidChannelConfig().WithDetail("reason", "secret must be base64")
+	}
+	return nil
+}
+
+func (c AppleConfig) GetProvider() string {
+	return c.Provider
+}
+
+func (c AppleConfig) GetType() ChannelType {
+	return ChannelTypeApple
+}
+
+func (c AppleConfig) GetFeatures() ChannelFeatures {
+	return ChannelFeatures{
+		SupportsText:                true,
+		SupportsAttachments:         true, // Se envían como rich link
+		SupportsImages:              true,
+		SupportsAudio:               false,
+		SupportsVideo:               false,
+		SupportsDocuments:           true,
+		SupportsInteractiveMessages: true, // List picker, quick reply, time picker y rich link
+		SupportsButtons:             true,
+		SupportsQuickReplies:        true,
+		SupportsTemplates:           false,
+		SupportsLocation:            true, // Rich link a Apple Maps
+		SupportsContacts:            false,
+		SupportsReactions:           false,
+		SupportsThreads:             false,
+		SupportsTimePicker:          true,
+		SupportsRichLinks:           true,
+		RequiresMessageBody:         true,
+		EntryPointFields:            []string{"intent", "group"},
+		MaxMessageLength:            10000,
+		MaxAttachmentSize:           100 * 1024 * 1024, // 100MB, adjuntos entrantes
+	}
+}
+
 // ============================================================================
 // Channel Domain Methods
 // ============================================================================
@@ -559,99 +737,126 @@ func (c *Channel) UpdateConfig(config ChannelConfig) error {
 
 // GetConfigStruct deserializa el config según el tipo
 func (c *Channel) GetConfigStruct() (ChannelConfig, error) {
-	switch c.Type {
+	return ParseChannelConfig(c.Type, c.Config)
+}
+
+// GetFeatures obtiene las features del canal
+func (c *Channel) GetFeatures() (ChannelFeatures, error) {
+	config, err := c.GetConfigStruct()
+	if err != nil {
+		return ChannelFeatures{}, err
+	}
+	return config.GetFeatures(), nil
+}
+
+// HasCredentials verifica si tiene credenciales configuradas
+func (c *Channel) HasCredentials() bool {
+	config, err := c.GetConfigStruct()
+	if err != nil {
+		return false
+	}
+	return config.GetProvider() != ""
+}
+
+// GetProvider retorna el proveedor
+func (c *Channel) GetProvider() string {
+	config, err := c.GetConfigStruct()
+	if err != nil {
+		return ""
+	}
+	return config.GetProvider()
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// ParseChannelConfig deserializa un config crudo según el tipo de canal
+func ParseChannelConfig(channelType ChannelType, raw json.RawMessage) (ChannelConfig, error) {
+	switch channelType {
 	case ChannelTypeWhatsApp:
 		var config WhatsAppConfig
-		if err := json.Unmarshal(c.Config, &config); err != nil {
+		if err := json.Unmarshal(raw, &config); err != nil {
 			return nil, err
 		}
 		return config, nil
 
 	case ChannelTypeInstagram:
 		var config InstagramConfig
-		if err := json.Unmarshal(c.Config, &config); err != nil {
+		if err := json.Unmarshal(raw, &config); err != nil {
 			return nil, err
 		}
 		return config, nil
 
 	case ChannelTypeTelegram:
 		var config TelegramConfig
-		if err := json.Unmarshal(c.Config, &config); err != nil {
+		if err := json.Unmarshal(raw, &config); err != nil {
 			return nil, err
 		}
 		return config, nil
 
 	case ChannelTypeInfobip:
 		var config InfobipConfig
-		if err := json.Unmarshal(c.Config, &config); err != nil {
+		if err := json.Unmarshal(raw, &config); err != nil {
 			return nil, err
 		}
 		return config, nil
 
 	case ChannelTypeEmail:
 		var config EmailConfig
-		if err := json.Unmarshal(c.Config, &config); err != nil {
+		if err := json.Unmarshal(raw, &config); err != nil {
 			return nil, err
 		}
 		return config, nil
 
 	case ChannelTypeSMS:
 		var config SMSConfig
-		if err := json.Unmarshal(c.Config, &config); err != nil {
+		if err := json.Unmarshal(raw, &config); err != nil {
 			return nil, err
 		}
 		return config, nil
 
 	case ChannelTypeWebChat:
 		var config WebChatConfig
-		if err := json.Unmarshal(c.Config, &config); err != nil {
+		if err := json.Unmarshal(raw, &config); err != nil {
 			return nil, err
 		}
 		return config, nil
 	case ChannelTypeTestHTTP:
 		var config TestHTTPConfig
-		if err := json.Unmarshal(c.Config, &config); err != nil {
+		if err := json.Unmarshal(raw, &config); err != nil {
 			return nil, err
 		}
 		return config, nil
 
-	default:
-		return nil, ErrChannelNotSupported().WithDetail("type", string(c.Type))
-	}
-
-}
+	case ChannelTypeRCS:
+		var config RCSConfig
+		if err := json.Unmarshal(raw, &config); err != nil {
+			return nil, err
+		}
+		return config, nil
 
-// GetFeatures obtiene las features del canal
-func (c *Channel) GetFeatures() (ChannelFeatures, error) {
-	config, err := c.GetConfigStruct()
-	if err != nil {
-		return ChannelFeatures{}, err
-	}
-	return config.GetFeatures(), nil
-}
+	case ChannelTypeApple:
+		var config AppleConfig
+		if err := json.Unmarshal(raw, &config); err != nil {
+			return nil, err
+		}
+		return config, nil
 
-// HasCredentials verifica si tiene credenciales configuradas
-func (c *Channel) HasCredentials() bool {
-	config, err := c.GetConfigStruct()
-	if err != nil {
-		return false
+	default:
+		return nil, ErrChannelNotSupported().WithDetail("type", string(channelType))
 	}
-	return config.GetProvider() != ""
 }
 
-// GetProvider retorna el proveedor
-func (c *Channel) GetProvider() string {
-	config, err := c.GetConfigStruct()
-	if err != nil {
-		return ""
+// DefaultEnvironment entorno de un canal creado sin indicarlo: los canales
+// TEST_HTTP solo sirven para pruebas
+func DefaultEnvironment(channelType ChannelType) kernel.Environment {
+	if channelType == ChannelTypeTestHTTP {
+		return kernel.EnvironmentSandbox
 	}
-	return config.GetProvider()
+	return kernel.EnvironmentProduction
 }
 
-// ============================================================================
-// Helper Functions
-// ============================================================================
-
 // NewChannelFromConfig crea un canal desde una config
 func NewChannelFromConfig(
 	id kernel.ChannelID,
@@ -674,6 +879,7 @@ func NewChannelFromConfig(
 		Description: description,
 		Config:      configJSON,
 		IsActive:    true,
+		Environment: DefaultEnvironment(config.GetType()),
 		WebhookURL:  webhookURL,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
@@ -699,9 +905,10 @@ func (c TestHTTPConfig) GetType() ChannelType {
 
 func (c TestHTTPConfig) GetFeatures() ChannelFeatures {
 	return ChannelFeatures{
-		SupportsText:        true,
-		SupportsAttachments: false,
-		MaxMessageLength:    10000,
-		SupportedMimeTypes:  []string{},
+		SupportsText:           true,
+		SupportsAttachments:    false,
+		SupportsMessageEditing: true,
+		MaxMessageLength:       10000,
+		SupportedMimeTypes:     []string{},
 	}
 }
@@ -0,0 +1,942 @@
+package apple
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/httpclient"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// gatewayURL is the base URL of Apple's Messages for Business gateway
+	gatewayURL = "https://mspgw.push.apple.com/v1"
+
+	// interactiveBID is the Messages extension that renders list pickers,
+	// time pickers and quick replies
+	interactiveBID = "com.apple.messages.MSMessageExtensionBalloonPlugin:0000000000:com.apple.icloud.apps.messages.business.extension"
+
+	// CapabilityHeader lists what the customer's device can show, e.g.
+	// LIST,TIME,QUICK
+	CapabilityHeader = "Capability-List"
+
+	// attachmentPlaceholder marks where each attachment sits in a body
+	attachmentPlaceholder = "\ufffc"
+
+	// timeSlotLayout is the start time format of time picker slots
+	timeSlotLayout = "2006-01-02T15:04-0700"
+
+	// Apple limits
+	minQuickReplies      = 2
+	maxQuickReplies      = 5
+	maxRichLinkImageSize = 1 << 20 // Rich link images travel inline
+)
+
+// mediaDownloadClient streams attachments without the size limit of the
+// provider client
+var mediaDownloadClient = &http.Client{Timeout: 5 * time.Minute}
+
+// AppleAdapter implements ChannelAdapter for Apple Messages for Business
+// through the messaging service provider (CSP) API. Interactive content maps
+// onto Apple's own message types: list pickers for lists, quick replies for
+// two to five buttons, time pickers for time slots and rich links for URLs,
+// media and locations.
+type AppleAdapter struct {
+	config     channels.AppleConfig
+	httpClient *httpclient.Client
+	apiURL     string
+}
+
+// NewAppleAdapter creates an adapter for one business
+func NewAppleAdapter(config channels.AppleConfig) *AppleAdapter {
+	return &AppleAdapter{
+		config:     config,
+		httpClient: httpclient.For(channels.ChannelTypeApple),
+		apiURL:     gatewayURL,
+	}
+}
+
+var (
+	_ channels.ChannelAdapter         = (*AppleAdapter)(nil)
+	_ channels.ProviderMessageSender  = (*AppleAdapter)(nil)
+	_ channels.WebhookEventClassifier = (*AppleAdapter)(nil)
+	_ channels.WebhookReplayer        = (*AppleAdapter)(nil)
+	_ channels.MediaFetcher           = (*AppleAdapter)(nil)
+)
+
+// knownMessageTypes are the message types handled or deliberately ignored
+var knownMessageTypes = map[string]bool{
+	"text":         true,
+	"interactive":  true,
+	"richLink":     true,
+	"typing_start": true,
+	"typing_end":   true,
+	"close":        true,
+}
+
+// ============================================================================
+// ChannelAdapter Interface Implementation
+// ============================================================================
+
+// GetType returns the channel type for this adapter
+func (a *AppleAdapter) GetType() channels.ChannelType {
+	return channels.ChannelTypeApple
+}
+
+// SendMessage sends a message to the customer's opaque ID
+func (a *AppleAdapter) SendMessage(ctx context.Context, msg channels.OutgoingMessage) error {
+	_, err := a.SendMessageWithID(ctx, msg)
+	return err
+}
+
+// SendMessageWithID sends a message and returns the ID it was sent with
+func (a *AppleAdapter) SendMessageWithID(ctx context.Context, msg channels.OutgoingMessage) (string, error) {
+	token, err := a.token()
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := a.buildMessage(ctx, msg.Content)
+	if err != nil {
+		return "", err
+	}
+
+	messageID := uuid.NewString()
+	payload["v"] = 1
+	payload["id"] = messageID
+	payload["sourceId"] = a.config.BusinessID
+	payload["destinationId"] = msg.RecipientID
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal message payload: %w", err)
+	}
+
+	resp, err := a.httpClient.Do(ctx, httpclient.Request{
+		Method: http.MethodPost,
+		URL:    a.apiURL + "/message",
+		Header: http.Header{
+			"Authorization":  {"Bearer " + token},
+			"Content-Type":   {"application/json"},
+			"Id":             {messageID},
+			"Source-Id":      {a.config.BusinessID},
+			"Destination-Id": {msg.RecipientID},
+		},
+		Body: jsonData,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	if !resp.IsSuccess() {
+		log.Printf("❌ Apple Messages API Error - Status: %d, Body: %s", resp.StatusCode, string(resp.Body))
+		return "", resp.Err().WithDetail("body", string(resp.Body))
+	}
+
+	log.Printf("✅ Apple message %s sent to %s", messageID, msg.RecipientID)
+	return messageID, nil
+}
+
+// ValidateConfig validates the Apple Messages channel configuration
+func (a *AppleAdapter) ValidateConfig(config channels.ChannelConfig) error {
+	appleConfig, ok := config.(channels.AppleConfig)
+	if !ok {
+		return channels.ErrInvalidChannelConfig().WithDetail("reason", "invalid config type")
+	}
+
+	return appleConfig.Validate()
+}
+
+// ProcessWebhook verifies and parses a message from Apple's gateway
+func (a *AppleAdapter) ProcessWebhook(
+	ctx context.Context,
+	payload []byte,
+	headers map[string]string,
+) (*channels.IncomingMessage, error) {
+	if err := a.verifyToken(headers); err != nil {
+		log.Printf("❌ Apple webhook authentication failed: %v", err)
+		return nil, err
+	}
+
+	incomingMsg, err := a.ParseWebhook(payload)
+	if err != nil {
+		return nil, err
+	}
+	if incomingMsg == nil {
+		log.Printf("ℹ️  Apple webhook contained no processable message (likely typing or close)")
+		return nil, nil
+	}
+
+	if capabilities := header(headers, CapabilityHeader); capabilities != "" {
+		incomingMsg.Metadata["capabilities"] = strings.Split(capabilities, ",")
+	}
+
+	log.Printf("✅ Apple message extracted - From: %s, Type: %s", incomingMsg.SenderID, incomingMsg.Content.Type)
+	return incomingMsg, nil
+}
+
+// ParseWebhook extracts the message of an already verified webhook. Stored
+// webhooks are reprocessed through it.
+func (a *AppleAdapter) ParseWebhook(payload []byte) (*channels.IncomingMessage, error) {
+	data, err := Inflate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var message Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, fmt.Errorf("failed to parse Apple message: %w", err)
+	}
+	if a.config.BusinessID != "" && message.DestinationID != a.config.BusinessID {
+		return nil, channels.ErrInvalidWebhookSignature().
+			WithDetail("reason", "message is for another business").
+			WithDetail("destination_id", message.DestinationID)
+	}
+
+	incomingMsg := a.extractIncomingMessage(message)
+	if incomingMsg == nil {
+		return nil, nil
+	}
+
+	incomingMsg.RawPayload = channels.DecodeRawPayload(data)
+	return incomingMsg, nil
+}
+
+// UnsupportedEvents lists the message types this adapter does not handle,
+// and interactive replies too large to travel inline
+func (a *AppleAdapter) UnsupportedEvents(payload []byte) []string {
+	data, err := Inflate(payload)
+	if err != nil {
+		return nil
+	}
+
+	var message Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil
+	}
+
+	var events []string
+	if !knownMessageTypes[message.Type] {
+		events = append(events, message.Type)
+	}
+	if len(message.InteractiveDataRef) > 0 {
+		events = append(events, "interactiveDataRef")
+	}
+	return events
+}
+
+// GetFeatures returns the capabilities of the Apple Messages channel
+func (a *AppleAdapter) GetFeatures() channels.ChannelFeatures {
+	return a.config.GetFeatures()
+}
+
+// TestConnection checks the credentials can sign gateway requests. The
+// gateway has no endpoint that accepts a request without a customer.
+func (a *AppleAdapter) TestConnection(ctx context.Context, config channels.ChannelConfig) error {
+	appleConfig, ok := config.(channels.AppleConfig)
+	if !ok {
+		return channels.ErrInvalidChannelConfig().WithDetail("reason", "invalid config type")
+	}
+	if err := appleConfig.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := NewAppleAdapter(appleConfig).token(); err != nil {
+		return err
+	}
+
+	log.Printf("✅ Apple Messages credentials valid for business %s", appleConfig.BusinessID)
+	return nil
+}
+
+// ============================================================================
+// Message Payload Building
+// ============================================================================
+
+// buildMessage maps message content onto an Apple message without its
+// addressing fields
+func (a *AppleAdapter) buildMessage(ctx context.Context, content channels.MessageContent) (map[string]any, error) {
+	if interactive := content.Interactive; interactive != nil {
+		body := interactive.Body
+		if body == "" {
+			body = content.Text
+		}
+
+		switch {
+		case len(interactive.TimeSlots) > 0 || interactive.Type == "time_picker":
+			return a.buildTimePicker(body, interactive)
+		case interactive.URL != "" || interactive.Type == "rich_link":
+			title := interactive.Header
+			if title == "" {
+				title = body
+			}
+			return a.buildRichLink(ctx, interactive.URL, title, content.MediaURL)
+		case len(interactive.ListItems()) > 0 || interactive.Type == "list":
+			return a.buildListPicker(body, interactive, sectionsOf(interactive))
+		case len(interactive.Buttons) > 0:
+			return a.buildButtons(ctx, body, interactive)
+		}
+	}
+
+	switch content.Type {
+	case "image", "video", "audio", "document", "file":
+		if content.MediaURL == "" {
+			return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "media_url is required")
+		}
+		title := content.Caption
+		if title == "" {
+			title = content.Filename
+		}
+		image := ""
+		if content.Type == "image" {
+			image = content.MediaURL
+		}
+		return a.buildRichLink(ctx, content.MediaURL, title, image)
+
+	case "location":
+		if content.Location == nil {
+			return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "location is required")
+		}
+		return a.buildLocation(ctx, content.Location)
+	}
+
+	if content.Text == "" {
+		return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "text is required")
+	}
+	return map[string]any{"type": "text", "body": content.Text}, nil
+}
+
+// buildButtons sends two to five reply buttons as quick replies and any
+// other number as a list picker. A lone URL button becomes a rich link;
+// other URL and call buttons have no Apple equivalent and are dropped.
+func (a *AppleAdapter) buildButtons(ctx context.Context, body string, interactive *channels.Interactive) (map[string]any, error) {
+	var replies []channels.Button
+	for _, btn := range interactive.Buttons {
+		if btn.Type == "url" || btn.Type == "call" || btn.URL != "" || btn.Phone != "" {
+			continue
+		}
+		replies = append(replies, btn)
+	}
+
+	if len(replies) == 0 {
+		if btn := interactive.Buttons[0]; len(interactive.Buttons) == 1 && btn.URL != "" {
+			return a.buildRichLink(ctx, btn.URL, btn.Title, "")
+		}
+		return nil, channels.ErrInvalidMessageFormat().
+			WithDetail("reason", "Apple Messages supports reply buttons only")
+	}
+	if dropped := len(interactive.Buttons) - len(replies); dropped > 0 {
+		log.Printf("⚠️  Apple Messages has no URL or call buttons, %d dropped", dropped)
+	}
+
+	if len(replies) < minQuickReplies || len(replies) > maxQuickReplies {
+		items := make([]channels.Item, 0, len(replies))
+		for _, btn := range replies {
+			items = append(items, channels.Item{ID: btn.ID, Title: btn.Title})
+		}
+		return a.buildListPicker(body, interactive, []channels.Section{{Items: items}})
+	}
+
+	if body == "" {
+		return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "body is required")
+	}
+
+	items := make([]map[string]any, 0, len(replies))
+	for i, btn := range replies {
+		items = append(items, map[string]any{
+			"identifier": identifier(btn.ID, btn.Title),
+			"title":      btn.Title,
+			"order":      i,
+		})
+	}
+	return interactiveMessage(body, map[string]any{
+		"quick-reply": map[string]any{
+			"summaryText": body,
+			"items":       items,
+		},
+	}, nil), nil
+}
+
+// buildListPicker sends list rows grouped in sections; the bubble shows the
+// header (or body) and footer
+func (a *AppleAdapter) buildListPicker(body string, interactive *channels.Interactive, sections []channels.Section) (map[string]any, error) {
+	if body == "" {
+		body = interactive.Header
+	}
+	if body == "" {
+		return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "body is required")
+	}
+
+	listSections := make([]map[string]any, 0, len(sections))
+	order := 0
+	for i, section := range sections {
+		items := make([]map[string]any, 0, len(section.Items))
+		for _, item := range section.Items {
+			items = append(items, map[string]any{
+				"identifier": identifier(item.ID, item.Title),
+				"title":      item.Title,
+				"subtitle":   item.Description,
+				"order":      order,
+				"style":      "default",
+			})
+			order++
+		}
+		listSections = append(listSections, map[string]any{
+			"title":             section.Title,
+			"order":             i,
+			"multipleSelection": false,
+			"items":             items,
+		})
+	}
+
+	return interactiveMessage(body, map[string]any{
+		"listPicker": map[string]any{"sections": listSections},
+	}, bubble(body, interactive)), nil
+}
+
+// buildTimePicker offers the time slots; the time zone shown is the first
+// slot's
+func (a *AppleAdapter) buildTimePicker(body string, interactive *channels.Interactive) (map[string]any, error) {
+	if len(interactive.TimeSlots) == 0 {
+		return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "time_slots is required")
+	}
+	if body == "" {
+		body = interactive.Header
+	}
+	if body == "" {
+		return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "body is required")
+	}
+
+	slots := make([]map[string]any, 0, len(interactive.TimeSlots))
+	for i, slot := range interactive.TimeSlots {
+		slots = append(slots, map[string]any{
+			"identifier": identifier(slot.ID, fmt.Sprint(i)),
+			"startTime":  slot.StartTime.UTC().Format(timeSlotLayout),
+			"duration":   slot.Duration,
+		})
+	}
+	_, offset := interactive.TimeSlots[0].StartTime.Zone()
+
+	title := interactive.Header
+	if title == "" {
+		title = body
+	}
+
+	return interactiveMessage(body, map[string]any{
+		"event": map[string]any{
+			"identifier":     uuid.NewString(),
+			"title":          title,
+			"timezoneOffset": offset / 60,
+			"timeslots":      slots,
+		},
+	}, bubble(body, interactive)), nil
+}
+
+// buildRichLink sends a link preview. The image, if any, is fetched and sent
+// inline; one that cannot be fetched leaves the preview without it.
+func (a *AppleAdapter) buildRichLink(ctx context.Context, link, title, imageURL string) (map[string]any, error) {
+	if link == "" {
+		return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "url is required")
+	}
+	if title == "" {
+		title = link
+	}
+
+	richLink := map[string]any{"url": link, "title": title}
+	if imageURL != "" {
+		if image, mimeType, err := a.fetchImage(ctx, imageURL); err != nil {
+			log.Printf("⚠️  Sending Apple rich link without its image: %v", err)
+		} else {
+			richLink["assets"] = map[string]any{
+				"image": map[string]any{
+					"data":     base64.StdEncoding.EncodeToString(image),
+					"mimeType": mimeType,
+				},
+			}
+		}
+	}
+
+	return map[string]any{
+		"type":         "richLink",
+		"body":         link,
+		"richLinkData": richLink,
+	}, nil
+}
+
+// buildLocation sends the place as a rich link that opens Apple Maps
+func (a *AppleAdapter) buildLocation(ctx context.Context, location *channels.Location) (map[string]any, error) {
+	query := url.Values{"ll": {fmt.Sprintf("%f,%f", location.Latitude, location.Longitude)}}
+	title := location.Name
+	if title == "" {
+		title = location.Address
+	}
+	if title != "" {
+		query.Set("q", title)
+	}
+
+	return a.buildRichLink(ctx, "https://maps.apple.com/?"+query.Encode(), title, "")
+}
+
+// fetchImage downloads a rich link image, which must be small enough to
+// travel inline
+func (a *AppleAdapter) fetchImage(ctx context.Context, imageURL string) ([]byte, string, error) {
+	resp, err := a.httpClient.Do(ctx, httpclient.Request{Method: http.MethodGet, URL: imageURL})
+	if err != nil {
+		return nil, "", err
+	}
+	if !resp.IsSuccess() {
+		return nil, "", fmt.Errorf("image download returned status %d", resp.StatusCode)
+	}
+	if len(resp.Body) > maxRichLinkImageSize {
+		return nil, "", fmt.Errorf("image is larger than %d bytes", maxRichLinkImageSize)
+	}
+
+	mimeType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(mimeType, "image/") {
+		mimeType = http.DetectContentType(resp.Body)
+	}
+	return resp.Body, mimeType, nil
+}
+
+// interactiveMessage wraps the data of a Messages extension message. The
+// body is what devices that cannot show it display instead.
+func interactiveMessage(body string, data map[string]any, bubble map[string]any) map[string]any {
+	data["version"] = "1.0"
+	data["requestIdentifier"] = uuid.NewString()
+
+	interactiveData := map[string]any{
+		"bid":  interactiveBID,
+		"data": data,
+	}
+	if bubble != nil {
+		interactiveData["receivedMessage"] = bubble
+		interactiveData["replyMessage"] = bubble
+	}
+
+	return map[string]any{
+		"type":            "interactive",
+		"body":            body,
+		"interactiveData": interactiveData,
+	}
+}
+
+// bubble is how a picker shows in the conversation before and after the
+// customer answers
+func bubble(body string, interactive *channels.Interactive) map[string]any {
+	title := interactive.Header
+	if title == "" {
+		title = body
+	}
+	return map[string]any{
+		"title":    title,
+		"subtitle": interactive.Footer,
+		"style":    "icon",
+	}
+}
+
+// sectionsOf returns the list's sections, or its rows as one section
+func sectionsOf(interactive *channels.Interactive) []channels.Section {
+	if len(interactive.Sections) > 0 {
+		return interactive.Sections
+	}
+	return []channels.Section{{Title: interactive.ButtonText, Items: interactive.Items}}
+}
+
+// identifier is the ID a reply refers to, falling back when the workflow
+// gave none
+func identifier(id, fallback string) string {
+	if id != "" {
+		return id
+	}
+	return fallback
+}
+
+// ============================================================================
+// Webhook Processing
+// ============================================================================
+
+// extractIncomingMessage converts a customer message to an IncomingMessage;
+// nil for typing and close events
+func (a *AppleAdapter) extractIncomingMessage(message Message) *channels.IncomingMessage {
+	incomingMsg := &channels.IncomingMessage{
+		MessageID: kernel.MessageID(message.ID),
+		SenderID:  message.SourceID,
+		Content:   channels.MessageContent{Type: "text"},
+		Timestamp: time.Now().Unix(), // Apple messages carry no timestamp
+		Metadata: map[string]any{
+			"apple_message_id": message.ID,
+			"business_id":      message.DestinationID,
+		},
+	}
+	// Entry point parameters, for routing
+	for key, value := range map[string]string{"intent": message.Intent, "group": message.Group, "locale": message.Locale} {
+		if value != "" {
+			incomingMsg.Metadata[key] = value
+		}
+	}
+
+	text := strings.TrimSpace(strings.ReplaceAll(message.Body, attachmentPlaceholder, ""))
+
+	switch message.Type {
+	case "text":
+		incomingMsg.Content.Text = text
+		if attachments := extractAttachments(message.Attachments); len(attachments) > 0 {
+			first := attachments[0]
+			incomingMsg.Content.Type = first.Type
+			incomingMsg.Content.MimeType = first.MimeType
+			incomingMsg.Content.Filename = first.Filename
+			incomingMsg.Content.Caption = text
+			incomingMsg.Content.Attachments = attachments
+		} else if text == "" {
+			return nil
+		}
+
+	case "interactive":
+		if message.InteractiveData == nil {
+			return nil // Too large to travel inline, see UnsupportedEvents
+		}
+		postback := extractPostback(message.InteractiveData.Data, text)
+		if postback == nil {
+			return nil
+		}
+		incomingMsg.Content.Text = postback.Title
+		incomingMsg.Content.Postback = postback
+		incomingMsg.Metadata["postback_payload"] = postback.ID
+
+	case "richLink":
+		if message.RichLinkData == nil {
+			return nil
+		}
+		incomingMsg.Content.Text = message.RichLinkData.URL
+
+	default:
+		return nil
+	}
+
+	return incomingMsg
+}
+
+// extractPostback reads the customer's answer to a list picker, time picker
+// or quick reply. text is the reply's body, which names the quick reply
+// chosen.
+func extractPostback(data InteractiveReply, text string) *channels.Postback {
+	switch {
+	case data.ListPicker != nil:
+		// The reply holds the selected rows only
+		for _, section := range data.ListPicker.Sections {
+			if len(section.Items) == 0 {
+				continue
+			}
+			item := section.Items[0]
+			return &channels.Postback{
+				Type:        "list",
+				ID:          item.Identifier,
+				Title:       item.Title,
+				Description: item.Subtitle,
+			}
+		}
+
+	case data.Event != nil && len(data.Event.Timeslots) > 0:
+		slot := data.Event.Timeslots[0]
+		title := slot.StartTime
+		if startTime, err := time.Parse(timeSlotLayout, slot.StartTime); err == nil {
+			title = startTime.Format(time.RFC3339)
+		}
+		return &channels.Postback{
+			Type:  "time_slot",
+			ID:    slot.Identifier,
+			Title: title,
+		}
+
+	case data.QuickReply != nil:
+		title := text
+		for _, item := range data.QuickReply.Items {
+			if item.Identifier == data.QuickReply.SelectedIdentifier && item.Title != "" {
+				title = item.Title
+			}
+		}
+		return &channels.Postback{
+			Type:  "quick_reply",
+			ID:    data.QuickReply.SelectedIdentifier,
+			Title: title,
+		}
+	}
+	return nil
+}
+
+// extractAttachments maps the message's attachments. They are end-to-end
+// encrypted; FetchMedia downloads and decrypts them.
+func extractAttachments(attachments []MessageAttachment) []channels.Attachment {
+	result := make([]channels.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		ref, err := json.Marshal(attachment)
+		if err != nil {
+			continue
+		}
+		result = append(result, channels.Attachment{
+			Type:            mediaTypeOf(attachment.MimeType),
+			MimeType:        attachment.MimeType,
+			Filename:        attachment.Name,
+			Size:            attachment.Size,
+			ProviderMediaID: string(ref),
+		})
+	}
+	return result
+}
+
+// FetchMedia downloads an inbound attachment: the gateway exchanges its
+// reference for a download URL, and the file is decrypted (AES-256-CTR)
+// while it is read
+func (a *AppleAdapter) FetchMedia(ctx context.Context, attachment channels.Attachment) (io.ReadCloser, string, error) {
+	var ref MessageAttachment
+	if err := json.Unmarshal([]byte(attachment.ProviderMediaID), &ref); err != nil || ref.URL == "" {
+		return nil, "", fmt.Errorf("attachment has no Apple reference")
+	}
+
+	key, err := hex.DecodeString(strings.TrimPrefix(ref.Key, "00"))
+	if err != nil || len(key) != 32 {
+		return nil, "", fmt.Errorf("attachment has an invalid key")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := a.token()
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := a.httpClient.Do(ctx, httpclient.Request{
+		Method: http.MethodGet,
+		URL:    a.apiURL + "/preDownload",
+		Header: http.Header{
+			"Authorization": {"Bearer " + token},
+			"Source-Id":     {a.config.BusinessID},
+			"Url":           {ref.URL},
+			"Signature":     {ref.signature()},
+			"Owner":         {ref.Owner},
+		},
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to look up attachment: %w", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, "", resp.Err().WithDetail("body", string(resp.Body))
+	}
+
+	var download struct {
+		URL string `json:"download-url"`
+	}
+	if err := json.Unmarshal(resp.Body, &download); err != nil || download.URL == "" {
+		return nil, "", fmt.Errorf("failed to parse attachment lookup")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, download.URL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	fileResp, err := mediaDownloadClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download attachment: %w", err)
+	}
+	if fileResp.StatusCode != http.StatusOK {
+		fileResp.Body.Close()
+		return nil, "", fmt.Errorf("attachment download returned status %d", fileResp.StatusCode)
+	}
+
+	// The files are encrypted with a zero IV; each key is used once
+	stream := cipher.NewCTR(block, make([]byte, aes.BlockSize))
+	return decryptingReader{
+		Reader: cipher.StreamReader{S: stream, R: fileResp.Body},
+		Closer: fileResp.Body,
+	}, attachment.MimeType, nil
+}
+
+type decryptingReader struct {
+	io.Reader
+	io.Closer
+}
+
+// Inflate returns the payload uncompressed; the gateway gzips the messages
+// it delivers
+func Inflate(payload []byte) ([]byte, error) {
+	if !bytes.HasPrefix(payload, []byte{0x1f, 0x8b}) {
+		return payload, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress Apple message: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress Apple message: %w", err)
+	}
+	return data, nil
+}
+
+// mediaTypeOf maps a mime type to the content types used across channels
+func mediaTypeOf(mimeType string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return "image"
+	case strings.HasPrefix(mimeType, "video/"):
+		return "video"
+	case strings.HasPrefix(mimeType, "audio/"):
+		return "audio"
+	default:
+		return "document"
+	}
+}
+
+// ============================================================================
+// Security & Validation
+// ============================================================================
+
+// token signs the JWT that authenticates a gateway request: HS256 over the
+// provider secret, with the CSP ID as audience
+func (a *AppleAdapter) token() (string, error) {
+	secret, err := base64.StdEncoding.DecodeString(a.config.Secret)
+	if err != nil || len(secret) == 0 {
+		return "", channels.ErrInvalidChannelConfig().WithDetail("reason", "secret must be base64")
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"aud": a.config.CSPID,
+		"iat": time.Now().Unix(),
+	}).SignedString(secret)
+}
+
+// verifyToken checks the JWT Apple signs each delivered message with
+func (a *AppleAdapter) verifyToken(headers map[string]string) error {
+	token, ok := strings.CutPrefix(header(headers, "Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return channels.ErrInvalidWebhookSignature().WithDetail("reason", "missing bearer token")
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(a.config.Secret)
+	if err != nil || len(secret) == 0 {
+		return channels.ErrInvalidChannelConfig().WithDetail("reason", "secret must be base64")
+	}
+
+	_, err = jwt.Parse(token,
+		func(*jwt.Token) (any, error) { return secret, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(a.config.CSPID),
+	)
+	if err != nil {
+		return channels.ErrInvalidWebhookSignature().WithDetail("reason", err.Error())
+	}
+	return nil
+}
+
+// header reads a header by its canonical or lowercase name
+func header(headers map[string]string, name string) string {
+	if value := headers[name]; value != "" {
+		return value
+	}
+	return headers[strings.ToLower(name)]
+}
+
+// ============================================================================
+// Apple Message Data Structures
+// ============================================================================
+
+// Message is a message exchanged with the gateway, in either direction
+type Message struct {
+	V                  int                 `json:"v"`
+	Type               string              `json:"type"` // text, interactive, richLink, typing_start, typing_end, close
+	ID                 string              `json:"id"`
+	SourceID           string              `json:"sourceId"`      // The customer's opaque ID on inbound messages
+	DestinationID      string              `json:"destinationId"` // The business ID on inbound messages
+	Body               string              `json:"body,omitempty"`
+	Locale             string              `json:"locale,omitempty"`
+	Intent             string              `json:"intent,omitempty"` // Set by the entry point the customer came from
+	Group              string              `json:"group,omitempty"`
+	Attachments        []MessageAttachment `json:"attachments,omitempty"`
+	InteractiveData    *InteractiveData    `json:"interactiveData,omitempty"`
+	InteractiveDataRef json.RawMessage     `json:"interactiveDataRef,omitempty"`
+	RichLinkData       *RichLinkData       `json:"richLinkData,omitempty"`
+}
+
+// MessageAttachment is an encrypted file the customer sent
+type MessageAttachment struct {
+	Name            string `json:"name"`
+	MimeType        string `json:"mimeType"`
+	Size            int64  `json:"size"`
+	URL             string `json:"url"`
+	Owner           string `json:"owner"`
+	Key             string `json:"key"` // Hex, prefixed with 00
+	Signature       string `json:"signature,omitempty"`
+	SignatureBase64 string `json:"signature-base64,omitempty"`
+}
+
+// signature returns the file's signature in the base64 form the gateway
+// expects
+func (m MessageAttachment) signature() string {
+	if m.SignatureBase64 != "" {
+		return m.SignatureBase64
+	}
+	raw, err := hex.DecodeString(m.Signature)
+	if err != nil {
+		return m.Signature
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+// InteractiveData is the Messages extension data of an interactive message
+type InteractiveData struct {
+	BID  string           `json:"bid"`
+	Data InteractiveReply `json:"data"`
+}
+
+// InteractiveReply is the customer's answer to a picker or quick reply
+type InteractiveReply struct {
+	RequestIdentifier string `json:"requestIdentifier"`
+	ListPicker        *struct {
+		Sections []struct {
+			Items []struct {
+				Identifier string `json:"identifier"`
+				Title      string `json:"title"`
+				Subtitle   string `json:"subtitle"`
+			} `json:"items"`
+		} `json:"sections"`
+	} `json:"listPicker,omitempty"`
+	Event *struct {
+		Timeslots []struct {
+			Identifier string `json:"identifier"`
+			StartTime  string `json:"startTime"`
+			Duration   int    `json:"duration"`
+		} `json:"timeslots"`
+	} `json:"event,omitempty"`
+	QuickReply *struct {
+		SelectedIdentifier string `json:"selectedIdentifier"`
+		Items              []struct {
+			Identifier string `json:"identifier"`
+			Title      string `json:"title"`
+		} `json:"items"`
+	} `json:"quick-reply,omitempty"`
+}
+
+// RichLinkData is a link the customer shared
+type RichLinkData struct {
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+}
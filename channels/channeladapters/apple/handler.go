@@ -0,0 +1,115 @@
+package apple
+
+import (
+	"log"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/featureflag"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebhookHandler handles the messages Apple's gateway delivers for a
+// business
+type WebhookHandler struct {
+	channelRepo channels.ChannelRepository
+	flags       featureflag.Checker // Optional; nil enables everything
+	events      channels.WebhookEventRecorder
+}
+
+// NewWebhookHandler creates a new Apple Messages webhook handler
+func NewWebhookHandler(channelRepo channels.ChannelRepository, flags featureflag.Checker) *WebhookHandler {
+	return &WebhookHandler{
+		channelRepo: channelRepo,
+		flags:       flags,
+	}
+}
+
+// SetEventRecorder keeps the webhook events the adapter does not handle
+func (h *WebhookHandler) SetEventRecorder(recorder channels.WebhookEventRecorder) {
+	h.events = recorder
+}
+
+// ReceiveWebhook handles incoming Apple messages (parsing only)
+// POST /webhooks/apple/:tenantId/:channelId/message
+//
+// Always answers 200 so the gateway does not retry
+func (h *WebhookHandler) ReceiveWebhook(c *fiber.Ctx) error {
+	tenantID := kernel.TenantID(c.Params("tenantId"))
+	channelID := kernel.NewChannelID(c.Params("channelId"))
+
+	log.Printf("📥 Received Apple Messages webhook - Tenant: %s, Channel: %s", tenantID, channelID)
+
+	channel, err := h.channelRepo.FindByID(c.Context(), channelID, tenantID)
+	if err != nil {
+		log.Printf("❌ Channel not found: %s", channelID)
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	if !channel.IsActive {
+		log.Printf("⚠️  Channel is inactive: %s", channelID)
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	// Feature flags are read per request so toggles apply without a restart
+	if !h.featureEnabled(c, tenantID, featureflag.ChannelFlag(string(channels.ChannelTypeApple))) {
+		log.Printf("🚩 Apple Messages adapter disabled for tenant %s, dropping webhook", tenantID)
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	config, err := channel.GetConfigStruct()
+	if err != nil {
+		log.Printf("❌ Invalid channel config: %v", err)
+		return c.SendStatus(fiber.StatusOK)
+	}
+	appleConfig, ok := config.(channels.AppleConfig)
+	if !ok {
+		log.Printf("❌ Not an Apple Messages channel: %s", channelID)
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	// Stored events must be plain JSON
+	body, err := Inflate(c.Body())
+	if err != nil {
+		log.Printf("❌ Failed to read Apple webhook: %v", err)
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	adapter := NewAppleAdapter(appleConfig)
+
+	headers := make(map[string]string)
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		headers[string(key)] = string(value)
+	})
+
+	incomingMsg, err := adapter.ProcessWebhook(c.Context(), body, headers)
+	if err != nil {
+		log.Printf("❌ Failed to process Apple webhook: %v", err)
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	// Events the adapter ignores are stored so they can be reprocessed later
+	if h.events != nil {
+		if eventTypes := adapter.UnsupportedEvents(body); len(eventTypes) > 0 {
+			h.events.RecordUnsupported(c.Context(), channel, eventTypes, body)
+		}
+	}
+
+	if incomingMsg == nil {
+		return c.SendStatus(fiber.StatusOK)
+	}
+	incomingMsg.ChannelID = channel.ID
+
+	log.Printf("✅ Apple message parsed - From: %s, Type: %s", incomingMsg.SenderID, incomingMsg.Content.Type)
+
+	// Store parsed message and channel in context for the next handler
+	c.Locals("incoming_message", incomingMsg)
+	c.Locals("channel", channel)
+
+	return c.Next()
+}
+
+// featureEnabled checks a tenant flag; without a checker everything is enabled
+func (h *WebhookHandler) featureEnabled(c *fiber.Ctx, tenantID kernel.TenantID, flag featureflag.Flag) bool {
+	return h.flags == nil || h.flags.IsEnabled(c.Context(), tenantID, flag)
+}
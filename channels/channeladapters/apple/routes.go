@@ -0,0 +1,34 @@
+package apple
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebhookRoutes handles Apple Messages webhook route setup
+type WebhookRoutes struct {
+	handler               *WebhookHandler
+	messageProcessHandler fiber.Handler // Generic handler from channelapi
+}
+
+// NewWebhookRoutes creates a new webhook routes instance
+func NewWebhookRoutes(
+	handler *WebhookHandler,
+	messageProcessHandler fiber.Handler,
+) *WebhookRoutes {
+	return &WebhookRoutes{
+		handler:               handler,
+		messageProcessHandler: messageProcessHandler,
+	}
+}
+
+// RegisterRoutes configures Apple Messages webhook routes. The gateway posts
+// to the /message path of the provider URL, so the channel's webhook URL is
+// registered as that base.
+func (wr *WebhookRoutes) RegisterRoutes(app *fiber.App) {
+	webhooks := app.Group("/webhooks/apple")
+
+	webhooks.Post("/:tenantId/:channelId/message",
+		wr.handler.ReceiveWebhook, // Parse Apple message
+		wr.messageProcessHandler,  // Process generic message
+	)
+}
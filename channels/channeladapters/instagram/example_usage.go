@@ -10,7 +10,6 @@ import (
 
 	"github.com/Abraxas-365/relay/channels"
 	"github.com/Abraxas-365/relay/pkg/kernel"
-	"github.com/go-redis/redis/v8"
 )
 
 // ============================================================================
@@ -41,12 +40,9 @@ func ExampleSetup() {
 		log.Fatalf("Invalid configuration: %v", err)
 	}
 
-	// Step 3: Create adapter instance (with Redis client for buffering)
-	// For production, pass actual Redis client. For testing without buffering, can pass nil.
-	redisClient := redis.NewClient(&redis.Options{
-		Addr: "localhost:6379",
-	})
-	adapter := NewInstagramAdapter(config, redisClient)
+	// Step 3: Create adapter instance. Buffering is done by the webhook
+	// handler through the shared buffer service, not by the adapter.
+	adapter := NewInstagramAdapter(config)
 
 	// Step 4: Test connection
 	ctx := context.Background()
@@ -531,8 +527,7 @@ func ExampleTestConfiguration() {
 	log.Printf("Max attachment size: %d MB", features.MaxAttachmentSize/(1024*1024))
 
 	// Create adapter and test connection
-	// Pass nil for Redis if buffering is not needed for testing
-	adapter := NewInstagramAdapter(config, nil)
+	adapter := NewInstagramAdapter(config)
 	ctx := context.Background()
 
 	if err := adapter.TestConnection(ctx, config); err != nil {
@@ -5,8 +5,9 @@ import (
 	"net/http"
 
 	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/channeladapters/meta"
+	"github.com/Abraxas-365/relay/featureflag"
 	"github.com/Abraxas-365/relay/pkg/kernel"
-	"github.com/go-redis/redis/v8"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -15,7 +16,9 @@ import (
 type WebhookHandler struct {
 	channelRepo channels.ChannelRepository
 	adapter     *InstagramAdapter
-	redisClient *redis.Client
+	flags       featureflag.Checker
+	events      channels.WebhookEventRecorder
+	buffer      channels.InboundBuffer
 }
 
 // NewWebhookHandler creates a new Instagram webhook handler
@@ -23,22 +26,34 @@ type WebhookHandler struct {
 // Parameters:
 //   - channelRepo: Repository for channel data access
 //   - adapter: Instagram adapter instance (can be nil, will be created per-request)
-//   - redisClient: Redis client for message buffering
+//   - flags: Per-tenant feature flags (can be nil, everything enabled)
 //
 // Returns:
 //   - *WebhookHandler: Configured handler ready to process webhooks
 func NewWebhookHandler(
 	channelRepo channels.ChannelRepository,
 	adapter *InstagramAdapter,
-	redisClient *redis.Client,
+	flags featureflag.Checker,
 ) *WebhookHandler {
 	return &WebhookHandler{
 		channelRepo: channelRepo,
 		adapter:     adapter,
-		redisClient: redisClient,
+		flags:       flags,
 	}
 }
 
+// SetEventRecorder keeps the webhook events the adapter does not handle
+// (referrals, comments, new Meta event types) so they can be reprocessed
+func (h *WebhookHandler) SetEventRecorder(recorder channels.WebhookEventRecorder) {
+	h.events = recorder
+}
+
+// SetBuffer groups the messages a sender sends in quick succession when the
+// channel enables buffering. Without it every message is processed alone.
+func (h *WebhookHandler) SetBuffer(buffer channels.InboundBuffer) {
+	h.buffer = buffer
+}
+
 // VerifyWebhook handles Meta's webhook verification challenge
 //
 // Instagram/Meta sends a GET request with verification parameters when you
@@ -83,15 +98,8 @@ func (h *WebhookHandler) VerifyWebhook(c *fiber.Ctx) error {
 		return fiber.NewError(http.StatusBadRequest, "Not an Instagram channel")
 	}
 
-	// Extract verification parameters from query string
-	mode := c.Query("hub.mode")
-	token := c.Query("hub.verify_token")
-	challenge := c.Query("hub.challenge")
-
-	log.Printf("📝 Verification attempt - Mode: %s, Token matches: %t", mode, token == instagramConfig.VerifyToken)
-
 	// Verify the token matches the configured token
-	if mode == "subscribe" && token == instagramConfig.VerifyToken {
+	if challenge, ok := meta.VerifyChallenge(c, instagramConfig.VerifyToken); ok {
 		log.Printf("✅ Instagram webhook verified successfully for channel: %s", channelID)
 		// Return the challenge to complete verification
 		return c.SendString(challenge)
@@ -161,8 +169,17 @@ func (h *WebhookHandler) ReceiveWebhook(c *fiber.Ctx) error {
 		return c.SendStatus(fiber.StatusOK)
 	}
 
-	// Create adapter instance with this channel's specific config (with Redis for buffering)
-	adapter := NewInstagramAdapter(instagramConfig, h.redisClient)
+	// Feature flags are read per request so toggles apply without a restart
+	if !h.featureEnabled(c, tenantID, featureflag.ChannelFlag(string(channels.ChannelTypeInstagram))) {
+		log.Printf("🚩 Instagram adapter disabled for tenant %s, dropping webhook", tenantID)
+		return c.SendStatus(fiber.StatusOK)
+	}
+	if !h.featureEnabled(c, tenantID, featureflag.FlagMessageBuffering) {
+		instagramConfig.BufferEnabled = false
+	}
+
+	// Create adapter instance with this channel's specific config
+	adapter := NewInstagramAdapter(instagramConfig)
 
 	// Read raw webhook payload
 	body := c.Body()
@@ -181,6 +198,13 @@ func (h *WebhookHandler) ReceiveWebhook(c *fiber.Ctx) error {
 		return c.SendStatus(fiber.StatusOK)
 	}
 
+	// Events the adapter ignores are stored so they can be reprocessed later
+	if h.events != nil {
+		if eventTypes := adapter.UnsupportedEvents(body); len(eventTypes) > 0 {
+			h.events.RecordUnsupported(c.Context(), channel, eventTypes, body)
+		}
+	}
+
 	// If message is nil, it means it's not a message event (status update, echo, etc.)
 	if incomingMsg == nil {
 		log.Printf("ℹ️  Instagram webhook contained no message (likely echo or status update) for channel: %s", channelID)
@@ -193,6 +217,17 @@ func (h *WebhookHandler) ReceiveWebhook(c *fiber.Ctx) error {
 		incomingMsg.Content.Text,
 	)
 
+	// Buffered messages are delivered combined once the channel's window ends
+	if instagramConfig.BufferEnabled && h.buffer != nil {
+		settings := channels.NewBufferSettings(instagramConfig.BufferTimeSeconds, instagramConfig.BufferResetOnMessage, maxMessagesPerBuffer)
+		err := h.buffer.Add(c.Context(), channel, *incomingMsg, settings)
+		if err == nil {
+			log.Printf("📦 Instagram message from %s buffered for channel: %s", incomingMsg.SenderID, channelID)
+			return c.SendStatus(fiber.StatusOK)
+		}
+		log.Printf("⚠️  Failed to buffer Instagram message, processing it now: %v", err)
+	}
+
 	// Store parsed message and channel in context for the next handler
 	c.Locals("incoming_message", incomingMsg)
 	c.Locals("channel", channel)
@@ -200,3 +235,8 @@ func (h *WebhookHandler) ReceiveWebhook(c *fiber.Ctx) error {
 	// Continue to next handler (generic message processor from channelapi)
 	return c.Next()
 }
+
+// featureEnabled checks a tenant flag; without a checker everything is enabled
+func (h *WebhookHandler) featureEnabled(c *fiber.Ctx, tenantID kernel.TenantID, flag featureflag.Flag) bool {
+	return h.flags == nil || h.flags.IsEnabled(c.Context(), tenantID, flag)
+}
@@ -1,22 +1,16 @@
 package instagram
 
 import (
-	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
-	"strings"
-	"time"
 
 	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/channeladapters/meta"
+	"github.com/Abraxas-365/relay/channels/httpclient"
 	"github.com/Abraxas-365/relay/pkg/kernel"
-	"github.com/go-redis/redis/v8"
 )
 
 const (
@@ -25,49 +19,53 @@ const (
 
 	// defaultAPIVersion is the default Instagram API version to use
 	defaultAPIVersion = "v24.0"
-
-	// maxRetries defines maximum retry attempts for API calls
-	maxRetries = 3
-
-	// requestTimeout defines the timeout for HTTP requests
-	requestTimeout = 30 * time.Second
 )
 
 // InstagramAdapter implements ChannelAdapter for Instagram Messaging API
 // It handles Instagram Direct Messages through Meta's Graph API
 type InstagramAdapter struct {
-	config        channels.InstagramConfig
-	httpClient    *http.Client
-	bufferService *BufferService
-	apiURL        string
+	config     channels.InstagramConfig
+	httpClient *httpclient.Client
+	apiURL     string
 }
 
 // NewInstagramAdapter creates a new Instagram adapter instance
 //
 // Parameters:
 //   - config: Instagram channel configuration containing page credentials
-//   - redisClient: Redis client for message buffering (can be nil if buffering disabled)
 //
 // Returns:
 //   - *InstagramAdapter: Configured adapter ready to send/receive messages
-func NewInstagramAdapter(config channels.InstagramConfig, redisClient *redis.Client) *InstagramAdapter {
+func NewInstagramAdapter(config channels.InstagramConfig) *InstagramAdapter {
 	apiVersion := defaultAPIVersion
 
-	// Create buffer service configuration
-	bufferConfig := BufferConfig{
-		Enabled:        config.BufferEnabled,
-		TimeSeconds:    config.BufferTimeSeconds,
-		ResetOnMessage: config.BufferResetOnMessage,
-	}
-
 	return &InstagramAdapter{
-		config:        config,
-		httpClient:    &http.Client{Timeout: requestTimeout},
-		bufferService: NewBufferService(redisClient, bufferConfig),
-		apiURL:        fmt.Sprintf("%s/%s/%s", instagramAPIBaseURL, apiVersion, config.PageID),
+		config:     config,
+		httpClient: httpclient.For(channels.ChannelTypeInstagram),
+		apiURL:     fmt.Sprintf("%s/%s/%s", instagramAPIBaseURL, apiVersion, config.PageID),
 	}
 }
 
+var (
+	_ channels.ProviderMessageSender  = (*InstagramAdapter)(nil)
+	_ channels.WebhookEventClassifier = (*InstagramAdapter)(nil)
+	_ channels.WebhookReplayer        = (*InstagramAdapter)(nil)
+	_ channels.MessageRenderer        = (*InstagramAdapter)(nil)
+)
+
+// knownMessagingFields are the keys of a messaging event this adapter
+// understands; reads and deliveries are known and deliberately ignored
+var knownMessagingFields = map[string]bool{
+	"sender":    true,
+	"recipient": true,
+	"timestamp": true,
+	"message":   true,
+	"postback":  true,
+	"reaction":  true,
+	"read":      true,
+	"delivery":  true,
+}
+
 // ============================================================================
 // ChannelAdapter Interface Implementation
 // ============================================================================
@@ -93,6 +91,13 @@ func (a *InstagramAdapter) GetType() channels.ChannelType {
 // Returns:
 //   - error: nil if successful, error with details if failed
 func (a *InstagramAdapter) SendMessage(ctx context.Context, msg channels.OutgoingMessage) error {
+	_, err := a.SendMessageWithID(ctx, msg)
+	return err
+}
+
+// SendMessageWithID sends a message and returns the mid Instagram assigned
+// to it, which replies quoting the message refer to
+func (a *InstagramAdapter) SendMessageWithID(ctx context.Context, msg channels.OutgoingMessage) (string, error) {
 	// Build Instagram API payload based on message type
 	payload := a.buildMessagePayload(msg)
 
@@ -105,49 +110,88 @@ func (a *InstagramAdapter) SendMessage(ctx context.Context, msg channels.Outgoin
 	// Marshal payload to JSON
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal message payload: %w", err)
+		return "", fmt.Errorf("failed to marshal message payload: %w", err)
 	}
 
-	// Create HTTP request with context
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	// Execute request (the shared client retries transient failures)
+	resp, err := a.httpClient.Do(ctx, httpclient.Request{
+		Method: http.MethodPost,
+		URL:    url,
+		Header: http.Header{
+			"Authorization": {"Bearer " + a.config.PageToken},
+			"Content-Type":  {"application/json"},
+		},
+		Body: jsonData,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %w", err)
+		return "", fmt.Errorf("failed to send request: %w", err)
 	}
 
-	// Set required headers
-	req.Header.Set("Authorization", "Bearer "+a.config.PageToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Execute request with retry logic
-	var resp *http.Response
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		resp, err = a.httpClient.Do(req)
-		if err == nil {
-			break
-		}
+	body := resp.Body
 
-		if attempt < maxRetries {
-			log.Printf("⚠️  Instagram API request failed (attempt %d/%d): %v", attempt+1, maxRetries, err)
-			time.Sleep(time.Duration(attempt+1) * time.Second)
-		}
+	// Check response status
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		log.Printf("❌ Instagram API Error - Status: %d, Body: %s", resp.StatusCode, string(body))
+		return "", a.parseAPIError(resp)
 	}
 
-	if err != nil {
-		return fmt.Errorf("failed to send request after %d attempts: %w", maxRetries, err)
+	log.Printf("✅ Instagram message sent successfully - Response: %s", string(body))
+
+	var sent struct {
+		MessageID string `json:"message_id"`
 	}
-	defer resp.Body.Close()
+	if err := json.Unmarshal(body, &sent); err != nil {
+		return "", nil
+	}
+	return sent.MessageID, nil
+}
 
-	// Read response body
-	body, _ := io.ReadAll(resp.Body)
+// RenderMessage builds the payload SendMessage would send and lists the
+// content Instagram shows differently or not at all
+func (a *InstagramAdapter) RenderMessage(msg channels.OutgoingMessage) (channels.RenderedMessage, error) {
+	var changes []channels.RenderChange
+	add := func(field, action, detail string) {
+		changes = append(changes, channels.RenderChange{Field: field, Action: action, Detail: detail})
+	}
 
-	// Check response status
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		log.Printf("❌ Instagram API Error - Status: %d, Body: %s", resp.StatusCode, string(body))
-		return a.parseAPIError(resp.StatusCode, body)
+	content := msg.Content
+	interactive := content.Interactive
+	switch content.Type {
+	case "text":
+		if interactive != nil && len(interactive.Buttons) > 0 {
+			add("content.interactive.buttons", channels.RenderConverted, "sent as quick replies")
+			if len(interactive.Buttons) > maxQuickReplies {
+				add("content.interactive.buttons", channels.RenderOverLimit,
+					fmt.Sprintf("%d quick replies; Instagram allows %d", len(interactive.Buttons), maxQuickReplies))
+			}
+		} else if interactive != nil && len(interactive.ListItems()) > 0 {
+			items := len(interactive.ListItems())
+			add("content.interactive.items", channels.RenderConverted, "sent as quick replies")
+			if items > maxQuickReplies {
+				add("content.interactive.items", channels.RenderDropped,
+					fmt.Sprintf("%d of %d items; Instagram shows at most %d quick replies", items-maxQuickReplies, items, maxQuickReplies))
+			}
+		}
+	case "image", "video":
+		if content.Text != "" || content.Caption != "" {
+			add("content.caption", channels.RenderDropped, "Instagram attachments carry no text")
+		}
+		if interactive != nil {
+			add("content.interactive", channels.RenderDropped, "Instagram attachments carry no buttons")
+		}
+	case "template":
+		add("content", channels.RenderConverted, "sent as a generic template")
+	default:
+		add("content", channels.RenderConverted, fmt.Sprintf("%s content sent as its text", content.Type))
+	}
+	if interactive != nil && interactive.Body != "" && content.Type == "text" {
+		add("content.interactive.body", channels.RenderDropped, "Instagram sends content.text")
 	}
 
-	log.Printf("✅ Instagram message sent successfully - Response: %s", string(body))
-	return nil
+	return channels.RenderedMessage{
+		Payload: a.buildMessagePayload(msg),
+		Changes: changes,
+	}, nil
 }
 
 // ValidateConfig validates the Instagram channel configuration
@@ -165,7 +209,8 @@ func (a *InstagramAdapter) ValidateConfig(config channels.ChannelConfig) error {
 	return instagramConfig.Validate()
 }
 
-// ProcessWebhook processes incoming Instagram webhook events
+// ProcessWebhook verifies and parses incoming Instagram webhook events.
+// Buffering happens afterwards in the webhook handler.
 //
 // Handles:
 //   - Message events (text, images, videos)
@@ -193,7 +238,24 @@ func (a *InstagramAdapter) ProcessWebhook(
 		return nil, err
 	}
 
-	// Parse webhook payload
+	// Parse webhook payload and extract its message
+	incomingMsg, err := a.ParseWebhook(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if incomingMsg == nil {
+		log.Printf("ℹ️  Instagram webhook contained no processable message (likely status update)")
+		return nil, nil // No message to process (status update, echo, etc.)
+	}
+
+	log.Printf("✅ Instagram message extracted - From: %s, Type: %s", incomingMsg.SenderID, incomingMsg.Content.Type)
+	return incomingMsg, nil
+}
+
+// ParseWebhook extracts the message of an already verified webhook, without
+// buffering. Stored webhooks are reprocessed through it.
+func (a *InstagramAdapter) ParseWebhook(payload []byte) (*channels.IncomingMessage, error) {
 	var webhook InstagramWebhook
 	if err := json.Unmarshal(payload, &webhook); err != nil {
 		return nil, fmt.Errorf("failed to parse Instagram webhook: %w", err)
@@ -201,38 +263,58 @@ func (a *InstagramAdapter) ProcessWebhook(
 
 	log.Printf("📥 Instagram webhook received - Object: %s", webhook.Object)
 
-	// Extract incoming message from webhook
 	incomingMsg, err := a.extractIncomingMessage(webhook)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract message from webhook: %w", err)
 	}
-
 	if incomingMsg == nil {
-		log.Printf("ℹ️  Instagram webhook contained no processable message (likely status update)")
-		return nil, nil // No message to process (status update, echo, etc.)
+		return nil, nil
 	}
 
-	log.Printf("✅ Instagram message extracted - From: %s, Type: %s", incomingMsg.SenderID, incomingMsg.Content.Type)
+	incomingMsg.RawPayload = channels.DecodeRawPayload(payload)
+	return incomingMsg, nil
+}
 
-	// Add to buffer if buffering is enabled
-	processedMsg, shouldProcess, err := a.bufferService.AddMessage(
-		ctx,
-		incomingMsg.ChannelID,
-		*incomingMsg,
-	)
+// UnsupportedEvents lists the webhook's events this adapter ignores:
+// messaging events it has no handler for (referral, optin, message_edit,
+// ...) by their key, and entry changes (comments, mentions) as
+// "changes:<field>"
+func (a *InstagramAdapter) UnsupportedEvents(payload []byte) []string {
+	var webhook struct {
+		Entry []struct {
+			Messaging []map[string]json.RawMessage `json:"messaging"`
+			Changes   []struct {
+				Field string `json:"field"`
+			} `json:"changes"`
+		} `json:"entry"`
+	}
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return nil
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("buffer error: %w", err)
+	var events []string
+	seen := make(map[string]bool)
+	add := func(event string) {
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
 	}
 
-	// If shouldProcess is false, message is buffered - return nil
-	if !shouldProcess {
-		log.Printf("📦 Instagram message buffered for channel: %s, sender: %s", incomingMsg.ChannelID, incomingMsg.SenderID)
-		return nil, nil
+	for _, entry := range webhook.Entry {
+		for _, messaging := range entry.Messaging {
+			for field := range messaging {
+				if !knownMessagingFields[field] {
+					add(field)
+				}
+			}
+		}
+		for _, change := range entry.Changes {
+			add("changes:" + change.Field)
+		}
 	}
 
-	// Message should be processed immediately
-	return processedMsg, nil
+	return events
 }
 
 // GetFeatures returns the capabilities of the Instagram channel
@@ -274,23 +356,19 @@ func (a *InstagramAdapter) TestConnection(ctx context.Context, config channels.C
 		instagramConfig.PageID,
 	)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create test request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+instagramConfig.PageToken)
-
-	resp, err := a.httpClient.Do(req)
+	resp, err := a.httpClient.Do(ctx, httpclient.Request{
+		Method: http.MethodGet,
+		URL:    url,
+		Header: http.Header{"Authorization": {"Bearer " + instagramConfig.PageToken}},
+	})
 	if err != nil {
 		return channels.ErrProviderAPIError().
 			WithDetail("reason", "failed to connect to Instagram API").
 			WithCause(err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
+		body := resp.Body
 		log.Printf("❌ Instagram API test failed - Status: %d, Body: %s", resp.StatusCode, string(body))
 
 		return channels.ErrProviderAuthFailed().
@@ -336,6 +414,11 @@ func (a *InstagramAdapter) buildMessagePayload(msg channels.OutgoingMessage) map
 		}
 	}
 
+	// Quote the message being replied to
+	if msg.ReplyToID != "" {
+		payload["reply_to"] = map[string]any{"mid": msg.ReplyToID}
+	}
+
 	return payload
 }
 
@@ -348,6 +431,11 @@ func (a *InstagramAdapter) buildTextMessage(msg channels.OutgoingMessage) map[st
 	// Add quick replies if present (using buttons as quick replies)
 	if msg.Content.Interactive != nil && len(msg.Content.Interactive.Buttons) > 0 {
 		message["quick_replies"] = a.buildQuickReplies(msg.Content.Interactive.Buttons)
+	} else if msg.Content.Interactive != nil {
+		// Instagram has no list picker; list items become quick replies
+		if items := msg.Content.Interactive.ListItems(); len(items) > 0 {
+			message["quick_replies"] = a.buildListQuickReplies(items)
+		}
 	}
 
 	return message
@@ -425,6 +513,29 @@ func (a *InstagramAdapter) buildQuickReplies(buttons []channels.Button) []map[st
 	return quickReplies
 }
 
+// maxQuickReplies is Instagram's limit per message
+const maxQuickReplies = 13
+
+// buildListQuickReplies converts list items to quick replies, dropping the
+// ones past Instagram's limit
+func (a *InstagramAdapter) buildListQuickReplies(items []channels.Item) []map[string]any {
+	if len(items) > maxQuickReplies {
+		log.Printf("⚠️  Instagram shows at most %d quick replies, %d list items dropped", maxQuickReplies, len(items)-maxQuickReplies)
+		items = items[:maxQuickReplies]
+	}
+
+	quickReplies := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		quickReplies = append(quickReplies, map[string]any{
+			"content_type": "text",
+			"title":        item.Title,
+			"payload":      item.ID,
+		})
+	}
+
+	return quickReplies
+}
+
 // buildButtons converts buttons to Instagram format
 func (a *InstagramAdapter) buildButtons(buttons []channels.Button) []map[string]any {
 	igButtons := make([]map[string]any, 0, len(buttons))
@@ -506,6 +617,11 @@ func (a *InstagramAdapter) processMessage(messaging WebhookMessaging) (*channels
 		},
 	}
 
+	// Handle reply to an earlier message
+	if msg.ReplyTo != nil && msg.ReplyTo.Mid != "" {
+		incomingMsg.Content.ReplyTo = &channels.ReplyTo{MessageID: msg.ReplyTo.Mid}
+	}
+
 	// Extract content based on message type
 	if msg.Text != "" {
 		incomingMsg.Content.Text = msg.Text
@@ -521,6 +637,11 @@ func (a *InstagramAdapter) processMessage(messaging WebhookMessaging) (*channels
 	// Handle quick reply
 	if msg.QuickReply != nil {
 		incomingMsg.Metadata["quick_reply_payload"] = msg.QuickReply.Payload
+		incomingMsg.Content.Postback = &channels.Postback{
+			Type:  "quick_reply",
+			ID:    msg.QuickReply.Payload,
+			Title: msg.Text,
+		}
 	}
 
 	return incomingMsg, nil
@@ -537,6 +658,11 @@ func (a *InstagramAdapter) processPostback(messaging WebhookMessaging) (*channel
 		Content: channels.MessageContent{
 			Type: "postback",
 			Text: postback.Title,
+			Postback: &channels.Postback{
+				Type:  "button",
+				ID:    postback.Payload,
+				Title: postback.Title,
+			},
 		},
 		Timestamp: messaging.Timestamp,
 		Metadata: map[string]any{
@@ -578,39 +704,12 @@ func (a *InstagramAdapter) processReaction(messaging WebhookMessaging) (*channel
 func (a *InstagramAdapter) verifySignature(payload []byte, headers map[string]string) error {
 	if a.config.AppSecret == "" {
 		log.Printf("⚠️  Instagram app secret not configured, skipping signature verification")
-		return nil // Skip verification if no secret configured
-	}
-
-	// Get signature from headers (try both cases)
-	signature := headers["X-Hub-Signature-256"]
-	if signature == "" {
-		signature = headers["x-hub-signature-256"]
-	}
-
-	if signature == "" {
-		return channels.ErrInvalidWebhookSignature().
-			WithDetail("reason", "missing X-Hub-Signature-256 header")
-	}
-
-	// Remove "sha256=" prefix
-	signature = strings.TrimPrefix(signature, "sha256=")
-
-	// Calculate expected signature using HMAC-SHA256
-	mac := hmac.New(sha256.New, []byte(a.config.AppSecret))
-	mac.Write(payload)
-	expectedSignature := hex.EncodeToString(mac.Sum(nil))
-
-	// Compare signatures using constant-time comparison
-	if !hmac.Equal([]byte(signature), []byte(expectedSignature)) {
-		return channels.ErrInvalidWebhookSignature().
-			WithDetail("reason", "signature mismatch")
 	}
-
-	return nil
+	return meta.VerifySignature(a.config.AppSecret, payload, headers)
 }
 
 // parseAPIError parses Instagram API error responses
-func (a *InstagramAdapter) parseAPIError(statusCode int, body []byte) error {
+func (a *InstagramAdapter) parseAPIError(resp *httpclient.Response) error {
 	var apiError struct {
 		Error struct {
 			Message      string `json:"message"`
@@ -621,14 +720,11 @@ func (a *InstagramAdapter) parseAPIError(statusCode int, body []byte) error {
 		} `json:"error"`
 	}
 
-	if err := json.Unmarshal(body, &apiError); err != nil {
-		return channels.ErrProviderAPIError().
-			WithDetail("status", statusCode).
-			WithDetail("body", string(body))
+	if err := json.Unmarshal(resp.Body, &apiError); err != nil {
+		return resp.Err().WithDetail("body", string(resp.Body))
 	}
 
-	return channels.ErrProviderAPIError().
-		WithDetail("status", statusCode).
+	return resp.Err().
 		WithDetail("error_type", apiError.Error.Type).
 		WithDetail("error_code", apiError.Error.Code).
 		WithDetail("error_message", apiError.Error.Message).
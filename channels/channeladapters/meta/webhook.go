@@ -0,0 +1,62 @@
+// Package meta holds the webhook handshake shared by Meta's messaging
+// platforms (WhatsApp Cloud API, Instagram, Messenger): the hub.challenge
+// subscription check and the X-Hub-Signature-256 payload signature.
+package meta
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the body, keyed with the app secret
+const SignatureHeader = "X-Hub-Signature-256"
+
+// VerifyChallenge checks the subscription request Meta sends when a webhook
+// is configured and returns the challenge to echo back. A channel without a
+// verify token never verifies.
+func VerifyChallenge(c *fiber.Ctx, verifyToken string) (string, bool) {
+	if verifyToken == "" || c.Query("hub.mode") != "subscribe" {
+		return "", false
+	}
+
+	token := c.Query("hub.verify_token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(verifyToken)) != 1 {
+		return "", false
+	}
+
+	return c.Query("hub.challenge"), true
+}
+
+// VerifySignature checks the body against its X-Hub-Signature-256 header.
+// Without an app secret there is nothing to check against and it passes.
+func VerifySignature(appSecret string, payload []byte, headers map[string]string) error {
+	if appSecret == "" {
+		return nil
+	}
+
+	signature := headers[SignatureHeader]
+	if signature == "" {
+		signature = headers[strings.ToLower(SignatureHeader)]
+	}
+	if signature == "" {
+		return channels.ErrInvalidWebhookSignature().
+			WithDetail("reason", "missing "+SignatureHeader+" header")
+	}
+
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	mac.Write(payload)
+	expectedSignature := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(strings.TrimPrefix(signature, "sha256=")), []byte(expectedSignature)) {
+		return channels.ErrInvalidWebhookSignature().
+			WithDetail("reason", "signature mismatch")
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package rcs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/httpclient"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// rbmScope is the OAuth scope of the RCS Business Messaging API
+	rbmScope = "https://www.googleapis.com/auth/rcsbusinessmessaging"
+
+	// defaultTokenURI is Google's OAuth token endpoint, used when the key
+	// file does not name one
+	defaultTokenURI = "https://oauth2.googleapis.com/token"
+
+	// tokenRefreshMargin renews a token this long before it expires
+	tokenRefreshMargin = time.Minute
+)
+
+// serviceAccountKey is the part of a Google service account key file the
+// token exchange needs
+type serviceAccountKey struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// tokenSource exchanges the agent's service account key for access tokens
+// (OAuth 2.0 JWT bearer grant) and caches each one until shortly before it
+// expires
+type tokenSource struct {
+	key        serviceAccountKey
+	httpClient *httpclient.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func newTokenSource(serviceAccountJSON string, httpClient *httpclient.Client) (*tokenSource, error) {
+	var key serviceAccountKey
+	if err := json.Unmarshal([]byte(serviceAccountJSON), &key); err != nil {
+		return nil, channels.ErrInvalidChannelConfig().
+			WithDetail("reason", "service_account_key is not a JSON key file")
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = defaultTokenURI
+	}
+
+	return &tokenSource{key: key, httpClient: httpClient}, nil
+}
+
+// Token returns a valid access token, requesting a new one when needed
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Add(tokenRefreshMargin).Before(s.expiry) {
+		return s.token, nil
+	}
+
+	assertion, err := s.assertion()
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	resp, err := s.httpClient.Do(ctx, httpclient.Request{
+		Method: http.MethodPost,
+		URL:    s.key.TokenURI,
+		Header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+		Body:   []byte(form.Encode()),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to request RCS access token: %w", err)
+	}
+	if !resp.IsSuccess() {
+		return "", channels.ErrProviderAuthFailed().
+			WithDetail("reason", "service account token exchange was rejected").
+			WithDetail("status", resp.StatusCode).
+			WithDetail("response", string(resp.Body))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(resp.Body, &token); err != nil || token.AccessToken == "" {
+		return "", channels.ErrProviderAuthFailed().WithDetail("reason", "invalid token response")
+	}
+
+	s.token = token.AccessToken
+	s.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return s.token, nil
+}
+
+// assertion signs the JWT that proves the service account's identity
+func (s *tokenSource) assertion() (string, error) {
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(s.key.PrivateKey))
+	if err != nil {
+		return "", channels.ErrInvalidChannelConfig().
+			WithDetail("reason", "service_account_key has an invalid private_key")
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.key.ClientEmail,
+		"scope": rbmScope,
+		"aud":   s.key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if s.key.PrivateKeyID != "" {
+		token.Header["kid"] = s.key.PrivateKeyID
+	}
+
+	return token.SignedString(privateKey)
+}
@@ -0,0 +1,146 @@
+package rcs
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/featureflag"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebhookHandler handles RCS-specific webhook operations: the verification
+// request sent when the agent's webhook is configured, user messages and
+// delivery events
+type WebhookHandler struct {
+	channelRepo channels.ChannelRepository
+	flags       featureflag.Checker // Optional; nil enables everything
+	events      channels.WebhookEventRecorder
+	bus         eventx.EventBus
+}
+
+// NewWebhookHandler creates a new RCS webhook handler
+func NewWebhookHandler(channelRepo channels.ChannelRepository, flags featureflag.Checker) *WebhookHandler {
+	return &WebhookHandler{
+		channelRepo: channelRepo,
+		flags:       flags,
+	}
+}
+
+// SetEventRecorder keeps the webhook events the adapter does not handle
+func (h *WebhookHandler) SetEventRecorder(recorder channels.WebhookEventRecorder) {
+	h.events = recorder
+}
+
+// SetEventBus publishes the DELIVERED and READ events of sent messages as
+// message.status events. Without it they are dropped.
+func (h *WebhookHandler) SetEventBus(bus eventx.EventBus) {
+	h.bus = bus
+}
+
+// ReceiveWebhook handles incoming RBM webhooks (parsing only)
+// POST /webhooks/rcs/:tenantId/:channelId
+//
+// Always answers 200 so RBM does not retry, except the configuration
+// request, which is answered with its secret only when the client token
+// matches the channel's.
+func (h *WebhookHandler) ReceiveWebhook(c *fiber.Ctx) error {
+	tenantID := kernel.TenantID(c.Params("tenantId"))
+	channelID := kernel.NewChannelID(c.Params("channelId"))
+
+	log.Printf("📥 Received RCS webhook - Tenant: %s, Channel: %s", tenantID, channelID)
+
+	channel, err := h.channelRepo.FindByID(c.Context(), channelID, tenantID)
+	if err != nil {
+		log.Printf("❌ Channel not found: %s", channelID)
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	config, err := channel.GetConfigStruct()
+	if err != nil {
+		log.Printf("❌ Invalid channel config: %v", err)
+		return c.SendStatus(fiber.StatusOK)
+	}
+	rcsConfig, ok := config.(channels.RCSConfig)
+	if !ok {
+		log.Printf("❌ Not an RCS channel: %s", channelID)
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	body := c.Body()
+
+	// The configuration request carries no message
+	var verification WebhookVerification
+	if json.Unmarshal(body, &verification) == nil && verification.Secret != "" {
+		return h.verifyWebhook(c, channelID, rcsConfig, verification)
+	}
+
+	if !channel.IsActive {
+		log.Printf("⚠️  Channel is inactive: %s", channelID)
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	// Feature flags are read per request so toggles apply without a restart
+	if !h.featureEnabled(c, tenantID, featureflag.ChannelFlag(string(channels.ChannelTypeRCS))) {
+		log.Printf("🚩 RCS adapter disabled for tenant %s, dropping webhook", tenantID)
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	adapter := NewRCSAdapter(rcsConfig)
+
+	headers := make(map[string]string)
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		headers[string(key)] = string(value)
+	})
+
+	incomingMsg, err := adapter.ProcessWebhook(c.Context(), body, headers)
+	if err != nil {
+		log.Printf("❌ Failed to process RCS webhook: %v", err)
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	// Events the adapter ignores are stored so they can be reprocessed later
+	if h.events != nil {
+		if eventTypes := adapter.UnsupportedEvents(body); len(eventTypes) > 0 {
+			h.events.RecordUnsupported(c.Context(), channel, eventTypes, body)
+		}
+	}
+
+	if receipts := adapter.DeliveryReceipts(body); len(receipts) > 0 {
+		channels.PublishDeliveryReceipts(c.Context(), h.bus, channel, receipts)
+	}
+
+	if incomingMsg == nil {
+		return c.SendStatus(fiber.StatusOK)
+	}
+	incomingMsg.ChannelID = channel.ID
+
+	log.Printf("✅ RCS message parsed - From: %s, Type: %s", incomingMsg.SenderID, incomingMsg.Content.Type)
+
+	// Store parsed message and channel in context for the next handler
+	c.Locals("incoming_message", incomingMsg)
+	c.Locals("channel", channel)
+
+	return c.Next()
+}
+
+// verifyWebhook echoes the secret of the configuration request when its
+// client token is the channel's
+func (h *WebhookHandler) verifyWebhook(c *fiber.Ctx, channelID kernel.ChannelID, config channels.RCSConfig, verification WebhookVerification) error {
+	if config.ClientToken == "" ||
+		subtle.ConstantTimeCompare([]byte(verification.ClientToken), []byte(config.ClientToken)) != 1 {
+		log.Printf("❌ RCS webhook verification failed - Invalid client token for channel: %s", channelID)
+		return c.SendStatus(fiber.StatusForbidden)
+	}
+
+	log.Printf("✅ RCS webhook verified successfully for channel: %s", channelID)
+	return c.Status(fiber.StatusOK).SendString(verification.Secret)
+}
+
+// featureEnabled checks a tenant flag; without a checker everything is enabled
+func (h *WebhookHandler) featureEnabled(c *fiber.Ctx, tenantID kernel.TenantID, flag featureflag.Flag) bool {
+	return h.flags == nil || h.flags.IsEnabled(c.Context(), tenantID, flag)
+}
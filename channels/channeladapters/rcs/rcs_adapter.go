@@ -0,0 +1,824 @@
+package rcs
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/httpclient"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+const (
+	// rbmAPIBaseURL is the base URL of the RCS Business Messaging API
+	rbmAPIBaseURL = "https://rcsbusinessmessaging.googleapis.com/v1"
+
+	// SignatureHeader carries the base64 HMAC-SHA512 of the event, keyed
+	// with the webhook's client token
+	SignatureHeader = "X-Goog-Signature"
+
+	// RBM limits
+	maxSuggestions         = 11 // Per message
+	maxCardSuggestions     = 4  // Per rich card
+	maxSuggestionTextChars = 25
+	maxCardTitleChars      = 200
+	maxCardDescChars       = 2000
+)
+
+// RCSAdapter implements ChannelAdapter for Google's RCS Business Messaging.
+// Outgoing content maps onto RBM messages: text with suggested replies and
+// actions, files, and standalone rich cards for media with a caption or a
+// header. Incoming events arrive through the agent's webhook.
+type RCSAdapter struct {
+	config     channels.RCSConfig
+	httpClient *httpclient.Client
+	tokens     *tokenSource
+	apiURL     string
+}
+
+// NewRCSAdapter creates an adapter for one RBM agent. A key that cannot be
+// read fails every send and connection test with an invalid config error.
+func NewRCSAdapter(config channels.RCSConfig) *RCSAdapter {
+	httpClient := httpclient.For(channels.ChannelTypeRCS)
+	tokens, err := newTokenSource(config.ServiceAccountKey, httpClient)
+	if err != nil {
+		log.Printf("⚠️  RCS agent %s has an unreadable service account key: %v", config.AgentID, err)
+	}
+
+	return &RCSAdapter{
+		config:     config,
+		httpClient: httpClient,
+		tokens:     tokens,
+		apiURL:     rbmAPIBaseURL,
+	}
+}
+
+var (
+	_ channels.ChannelAdapter         = (*RCSAdapter)(nil)
+	_ channels.ProviderMessageSender  = (*RCSAdapter)(nil)
+	_ channels.WebhookEventClassifier = (*RCSAdapter)(nil)
+	_ channels.WebhookReplayer        = (*RCSAdapter)(nil)
+	_ channels.DeliveryReporter       = (*RCSAdapter)(nil)
+	_ channels.MessageRenderer        = (*RCSAdapter)(nil)
+)
+
+// knownEventFields are the keys of a user event this adapter understands
+var knownEventFields = map[string]bool{
+	"senderPhoneNumber":  true,
+	"messageId":          true,
+	"eventId":            true,
+	"sendTime":           true,
+	"agentId":            true,
+	"context":            true,
+	"text":               true,
+	"userFile":           true,
+	"location":           true,
+	"suggestionResponse": true,
+	"eventType":          true,
+}
+
+// knownEventTypes are the eventType values handled or deliberately ignored
+var knownEventTypes = map[string]bool{
+	"DELIVERED": true,
+	"READ":      true,
+	"IS_TYPING": true,
+}
+
+// ============================================================================
+// ChannelAdapter Interface Implementation
+// ============================================================================
+
+// GetType returns the channel type for this adapter
+func (a *RCSAdapter) GetType() channels.ChannelType {
+	return channels.ChannelTypeRCS
+}
+
+// SendMessage sends a message to the recipient's phone
+func (a *RCSAdapter) SendMessage(ctx context.Context, msg channels.OutgoingMessage) error {
+	_, err := a.SendMessageWithID(ctx, msg)
+	return err
+}
+
+// SendMessageWithID sends a message and returns the ID it was sent with;
+// the delivery and read events of the webhook refer to it
+func (a *RCSAdapter) SendMessageWithID(ctx context.Context, msg channels.OutgoingMessage) (string, error) {
+	token, err := a.accessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := a.buildContentMessage(msg.Content)
+	if err != nil {
+		return "", err
+	}
+	jsonData, err := json.Marshal(map[string]any{"contentMessage": content})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal message payload: %w", err)
+	}
+
+	messageID := uuid.NewString()
+	endpoint := fmt.Sprintf("%s/phones/%s/agentMessages?%s",
+		a.apiURL,
+		url.PathEscape(phoneNumber(msg.RecipientID)),
+		url.Values{"messageId": {messageID}, "agentId": {a.config.AgentID}}.Encode(),
+	)
+
+	resp, err := a.httpClient.Do(ctx, httpclient.Request{
+		Method: http.MethodPost,
+		URL:    endpoint,
+		Header: http.Header{
+			"Authorization": {"Bearer " + token},
+			"Content-Type":  {"application/json"},
+		},
+		Body: jsonData,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	if !resp.IsSuccess() {
+		log.Printf("❌ RCS API Error - Status: %d, Body: %s", resp.StatusCode, string(resp.Body))
+		return "", a.parseAPIError(resp)
+	}
+
+	log.Printf("✅ RCS message %s sent to %s", messageID, msg.RecipientID)
+	return messageID, nil
+}
+
+// RenderMessage builds the agent message SendMessage would send and lists
+// what RBM's limits cut from the content
+func (a *RCSAdapter) RenderMessage(msg channels.OutgoingMessage) (channels.RenderedMessage, error) {
+	content, err := a.buildContentMessage(msg.Content)
+	if err != nil {
+		return channels.RenderedMessage{}, err
+	}
+
+	changes := renderChanges(msg.Content, content)
+	if msg.ReplyToID != "" {
+		changes = append(changes, channels.RenderChange{
+			Field:  "reply_to_id",
+			Action: channels.RenderDropped,
+			Detail: "RBM messages cannot quote another message",
+		})
+	}
+
+	return channels.RenderedMessage{
+		Payload: map[string]any{"contentMessage": content},
+		Changes: changes,
+	}, nil
+}
+
+// ValidateConfig validates the RCS channel configuration
+func (a *RCSAdapter) ValidateConfig(config channels.ChannelConfig) error {
+	rcsConfig, ok := config.(channels.RCSConfig)
+	if !ok {
+		return channels.ErrInvalidChannelConfig().WithDetail("reason", "invalid config type")
+	}
+
+	return rcsConfig.Validate()
+}
+
+// ProcessWebhook verifies and parses an RBM webhook event
+func (a *RCSAdapter) ProcessWebhook(
+	ctx context.Context,
+	payload []byte,
+	headers map[string]string,
+) (*channels.IncomingMessage, error) {
+	if err := a.verifySignature(payload, headers); err != nil {
+		log.Printf("❌ RCS webhook signature verification failed: %v", err)
+		return nil, err
+	}
+
+	incomingMsg, err := a.ParseWebhook(payload)
+	if err != nil {
+		return nil, err
+	}
+	if incomingMsg == nil {
+		log.Printf("ℹ️  RCS webhook contained no processable message (likely a delivery event)")
+		return nil, nil
+	}
+
+	log.Printf("✅ RCS message extracted - From: %s, Type: %s", incomingMsg.SenderID, incomingMsg.Content.Type)
+	return incomingMsg, nil
+}
+
+// ParseWebhook extracts the message of an already verified webhook. Stored
+// webhooks are reprocessed through it.
+func (a *RCSAdapter) ParseWebhook(payload []byte) (*channels.IncomingMessage, error) {
+	data, err := decodeEvent(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var event UserEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse RCS event: %w", err)
+	}
+	if event.EventType != "" {
+		return nil, nil // Delivery, read and typing events
+	}
+
+	incomingMsg := a.extractIncomingMessage(event)
+	if incomingMsg == nil {
+		return nil, nil
+	}
+
+	incomingMsg.RawPayload = channels.DecodeRawPayload(data)
+	return incomingMsg, nil
+}
+
+// UnsupportedEvents lists the event's fields and event types this adapter
+// does not handle
+func (a *RCSAdapter) UnsupportedEvents(payload []byte) []string {
+	data, err := decodeEvent(payload)
+	if err != nil {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+
+	var events []string
+	for field := range fields {
+		if !knownEventFields[field] {
+			events = append(events, field)
+		}
+	}
+
+	slices.Sort(events)
+
+	var eventType string
+	if raw, ok := fields["eventType"]; ok && json.Unmarshal(raw, &eventType) == nil && !knownEventTypes[eventType] {
+		events = append(events, "eventType:"+eventType)
+	}
+
+	return events
+}
+
+// DeliveryReceipts returns the DELIVERED and READ events of the webhook
+func (a *RCSAdapter) DeliveryReceipts(payload []byte) []channels.DeliveryReceipt {
+	data, err := decodeEvent(payload)
+	if err != nil {
+		return nil
+	}
+
+	var event UserEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil
+	}
+
+	var status channels.DeliveryStatus
+	switch event.EventType {
+	case "DELIVERED":
+		status = channels.DeliveryStatusDelivered
+	case "READ":
+		status = channels.DeliveryStatusRead
+	default:
+		return nil
+	}
+
+	return []channels.DeliveryReceipt{{
+		ProviderMessageID: event.MessageID,
+		RecipientID:       event.SenderPhoneNumber,
+		Status:            status,
+		At:                event.SendTime,
+	}}
+}
+
+// GetFeatures returns the capabilities of the RCS channel
+func (a *RCSAdapter) GetFeatures() channels.ChannelFeatures {
+	return a.config.GetFeatures()
+}
+
+// TestConnection checks the service account key by exchanging it for an
+// access token
+func (a *RCSAdapter) TestConnection(ctx context.Context, config channels.ChannelConfig) error {
+	rcsConfig, ok := config.(channels.RCSConfig)
+	if !ok {
+		return channels.ErrInvalidChannelConfig().WithDetail("reason", "invalid config type")
+	}
+	if err := rcsConfig.Validate(); err != nil {
+		return err
+	}
+
+	tokens, err := newTokenSource(rcsConfig.ServiceAccountKey, a.httpClient)
+	if err != nil {
+		return err
+	}
+	if _, err := tokens.Token(ctx); err != nil {
+		return err
+	}
+
+	log.Printf("✅ RCS API connection test successful for agent %s", rcsConfig.AgentID)
+	return nil
+}
+
+// accessToken returns a token for the API, or why the key is unusable
+func (a *RCSAdapter) accessToken(ctx context.Context) (string, error) {
+	if a.tokens == nil {
+		return "", channels.ErrInvalidChannelConfig().
+			WithDetail("reason", "service_account_key is not a JSON key file")
+	}
+	return a.tokens.Token(ctx)
+}
+
+// ============================================================================
+// Message Payload Building
+// ============================================================================
+
+// buildContentMessage maps message content onto an RBM contentMessage,
+// which holds exactly one of text, a file or a rich card, plus suggestions
+func (a *RCSAdapter) buildContentMessage(content channels.MessageContent) (map[string]any, error) {
+	interactive := content.Interactive
+
+	switch content.Type {
+	case "image", "video":
+		if content.MediaURL == "" {
+			return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "media_url is required")
+		}
+		// Captions and buttons need a card; a bare file is sent as is
+		if content.Caption != "" || (interactive != nil && (interactive.Header != "" || len(interactive.Buttons) > 0)) {
+			return a.buildRichCard(content), nil
+		}
+		return a.buildFile(content.MediaURL, interactive), nil
+
+	case "audio", "document", "file":
+		if content.MediaURL == "" {
+			return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "media_url is required")
+		}
+		if content.Caption != "" {
+			log.Printf("⚠️  RCS files carry no caption, dropping it")
+		}
+		return a.buildFile(content.MediaURL, interactive), nil
+
+	case "location":
+		if content.Location == nil {
+			return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "location is required")
+		}
+		return a.buildLocation(content), nil
+	}
+
+	// Interactive messages with a header become a card titled with it
+	if interactive != nil && interactive.Header != "" {
+		return a.buildRichCard(content), nil
+	}
+
+	text := content.Text
+	if interactive != nil && interactive.Body != "" {
+		text = interactive.Body
+	}
+	if text == "" {
+		return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "text is required")
+	}
+
+	message := map[string]any{"text": text}
+	if suggestions := a.buildSuggestions(interactive, maxSuggestions); len(suggestions) > 0 {
+		message["suggestions"] = suggestions
+	}
+	return message, nil
+}
+
+// buildFile sends a file by URL; RBM fetches and caches it
+func (a *RCSAdapter) buildFile(fileURL string, interactive *channels.Interactive) map[string]any {
+	message := map[string]any{
+		"contentInfo": map[string]any{"fileUrl": fileURL},
+	}
+	if suggestions := a.buildSuggestions(interactive, maxSuggestions); len(suggestions) > 0 {
+		message["suggestions"] = suggestions
+	}
+	return message
+}
+
+// buildRichCard builds a standalone card: the interactive header as title,
+// the body, caption or text as description, the media on top and the
+// buttons as the card's suggestions
+func (a *RCSAdapter) buildRichCard(content channels.MessageContent) map[string]any {
+	interactive := content.Interactive
+
+	title, description := "", content.Caption
+	if description == "" {
+		description = content.Text
+	}
+	if interactive != nil {
+		title = interactive.Header
+		if interactive.Body != "" {
+			description = interactive.Body
+		}
+	}
+
+	card := map[string]any{}
+	if title != "" {
+		card["title"] = truncate(title, maxCardTitleChars)
+	}
+	if description != "" {
+		card["description"] = truncate(description, maxCardDescChars)
+	}
+	if content.MediaURL != "" && (content.Type == "image" || content.Type == "video") {
+		card["media"] = map[string]any{
+			"height":      "MEDIUM",
+			"contentInfo": map[string]any{"fileUrl": content.MediaURL},
+		}
+	}
+	if suggestions := a.buildSuggestions(interactive, maxCardSuggestions); len(suggestions) > 0 {
+		card["suggestions"] = suggestions
+	}
+
+	return map[string]any{
+		"richCard": map[string]any{
+			"standaloneCard": map[string]any{
+				"cardOrientation": "VERTICAL",
+				"cardContent":     card,
+			},
+		},
+	}
+}
+
+// buildLocation sends the place's name or address with an action that
+// opens it in the user's maps app; RBM has no location message
+func (a *RCSAdapter) buildLocation(content channels.MessageContent) map[string]any {
+	location := content.Location
+
+	text := content.Text
+	if text == "" {
+		text = strings.TrimSpace(strings.Join([]string{location.Name, location.Address}, "\n"))
+	}
+	if text == "" {
+		text = fmt.Sprintf("%f, %f", location.Latitude, location.Longitude)
+	}
+
+	label := location.Name
+	if label == "" {
+		label = "Location"
+	}
+
+	return map[string]any{
+		"text": text,
+		"suggestions": []map[string]any{{
+			"action": map[string]any{
+				"text":         truncate("View location", maxSuggestionTextChars),
+				"postbackData": "view_location",
+				"viewLocationAction": map[string]any{
+					"latLong": map[string]any{
+						"latitude":  location.Latitude,
+						"longitude": location.Longitude,
+					},
+					"label": label,
+				},
+			},
+		}},
+	}
+}
+
+// buildSuggestions maps buttons to suggested replies and actions (url opens
+// a page, call dials a number) and list rows to suggested replies, up to
+// limit
+func (a *RCSAdapter) buildSuggestions(interactive *channels.Interactive, limit int) []map[string]any {
+	if interactive == nil {
+		return nil
+	}
+
+	var suggestions []map[string]any
+	for _, btn := range interactive.Buttons {
+		suggestions = append(suggestions, buildSuggestion(btn))
+	}
+	// RCS has no list picker; list rows become suggested replies
+	for _, item := range interactive.ListItems() {
+		suggestions = append(suggestions, buildSuggestion(channels.Button{ID: item.ID, Title: item.Title}))
+	}
+
+	if len(suggestions) > limit {
+		log.Printf("⚠️  RCS shows at most %d suggestions here, %d dropped", limit, len(suggestions)-limit)
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+func buildSuggestion(btn channels.Button) map[string]any {
+	postbackData := btn.ID
+	if postbackData == "" {
+		postbackData = btn.Title
+	}
+	text := truncate(btn.Title, maxSuggestionTextChars)
+
+	switch {
+	case btn.Type == "url" || (btn.Type == "" && btn.URL != ""):
+		return map[string]any{"action": map[string]any{
+			"text":          text,
+			"postbackData":  postbackData,
+			"openUrlAction": map[string]any{"url": btn.URL},
+		}}
+	case btn.Type == "call" || (btn.Type == "" && btn.Phone != ""):
+		return map[string]any{"action": map[string]any{
+			"text":         text,
+			"postbackData": postbackData,
+			"dialAction":   map[string]any{"phoneNumber": phoneNumber(btn.Phone)},
+		}}
+	default:
+		return map[string]any{"reply": map[string]any{
+			"text":         text,
+			"postbackData": postbackData,
+		}}
+	}
+}
+
+// renderChanges compares the content with the message built from it
+func renderChanges(content channels.MessageContent, message map[string]any) []channels.RenderChange {
+	var changes []channels.RenderChange
+	add := func(field, action, detail string) {
+		changes = append(changes, channels.RenderChange{Field: field, Action: action, Detail: detail})
+	}
+
+	interactive := content.Interactive
+	_, isCard := message["richCard"]
+
+	switch {
+	case content.Type == "location":
+		add("content.location", channels.RenderConverted, "sent as text with a view location action")
+	case (content.Type == "audio" || content.Type == "document" || content.Type == "file") && content.Caption != "":
+		add("content.caption", channels.RenderDropped, "RBM files carry no caption")
+	case isCard:
+		add("content", channels.RenderConverted, "sent as a rich card")
+		if interactive != nil && len([]rune(interactive.Header)) > maxCardTitleChars {
+			add("content.interactive.header", channels.RenderTruncated, fmt.Sprintf("to %d characters", maxCardTitleChars))
+		}
+		for _, text := range []string{content.Caption, content.Text} {
+			if len([]rune(text)) > maxCardDescChars {
+				add("content", channels.RenderTruncated, fmt.Sprintf("card description to %d characters", maxCardDescChars))
+				break
+			}
+		}
+	}
+	if interactive != nil && interactive.Body != "" && content.Text != "" && content.Type != "location" {
+		add("content.text", channels.RenderDropped, "interactive.body is sent instead")
+	}
+	if interactive == nil {
+		return changes
+	}
+
+	if len(interactive.ListItems()) > 0 {
+		add("content.interactive.items", channels.RenderConverted, "list items sent as suggested replies")
+	}
+	limit := maxSuggestions
+	if isCard {
+		limit = maxCardSuggestions
+	}
+	if count := len(interactive.Buttons) + len(interactive.ListItems()); count > limit {
+		add("content.interactive.buttons", channels.RenderDropped, fmt.Sprintf("%d of %d suggestions; RBM shows at most %d here", count-limit, count, limit))
+	}
+	for _, btn := range interactive.Buttons {
+		if len([]rune(btn.Title)) > maxSuggestionTextChars {
+			add("content.interactive.buttons", channels.RenderTruncated, fmt.Sprintf("%q to %d characters", btn.Title, maxSuggestionTextChars))
+		}
+	}
+	for _, item := range interactive.ListItems() {
+		if len([]rune(item.Title)) > maxSuggestionTextChars {
+			add("content.interactive.items", channels.RenderTruncated, fmt.Sprintf("%q to %d characters", item.Title, maxSuggestionTextChars))
+		}
+	}
+
+	return changes
+}
+
+// ============================================================================
+// Webhook Processing
+// ============================================================================
+
+// extractIncomingMessage converts a user message event to an IncomingMessage;
+// nil for events without content
+func (a *RCSAdapter) extractIncomingMessage(event UserEvent) *channels.IncomingMessage {
+	sentAt := event.SendTime
+	if sentAt.IsZero() {
+		sentAt = time.Now()
+	}
+
+	incomingMsg := &channels.IncomingMessage{
+		MessageID: kernel.MessageID(event.MessageID),
+		SenderID:  event.SenderPhoneNumber,
+		Content:   channels.MessageContent{Type: "text"},
+		Timestamp: sentAt.Unix(),
+		Metadata: map[string]any{
+			"rcs_message_id": event.MessageID,
+			"agent_id":       event.AgentID,
+		},
+	}
+
+	switch {
+	case event.SuggestionResponse != nil:
+		response := event.SuggestionResponse
+		postbackType := "quick_reply"
+		if response.Type == "ACTION" {
+			postbackType = "button"
+		}
+		incomingMsg.Content.Text = response.Text
+		incomingMsg.Content.Postback = &channels.Postback{
+			Type:  postbackType,
+			ID:    response.PostbackData,
+			Title: response.Text,
+		}
+		incomingMsg.Metadata["postback_payload"] = response.PostbackData
+
+	case event.UserFile != nil:
+		file := event.UserFile.Payload
+		mediaType := mediaTypeOf(file.MimeType)
+		incomingMsg.Content.Type = mediaType
+		incomingMsg.Content.MediaURL = file.FileURI
+		incomingMsg.Content.MimeType = file.MimeType
+		incomingMsg.Content.Filename = file.FileName
+		incomingMsg.Content.Attachments = []channels.Attachment{{
+			Type:     mediaType,
+			URL:      file.FileURI,
+			MimeType: file.MimeType,
+			Filename: file.FileName,
+			Size:     file.FileSizeBytes,
+		}}
+
+	case event.Location != nil:
+		incomingMsg.Content.Type = "location"
+		incomingMsg.Content.Location = &channels.Location{
+			Latitude:  event.Location.Latitude,
+			Longitude: event.Location.Longitude,
+		}
+
+	case event.Text != "":
+		incomingMsg.Content.Text = event.Text
+
+	default:
+		return nil
+	}
+
+	return incomingMsg
+}
+
+// decodeEvent unwraps the Pub/Sub push envelope RBM delivers events in
+func decodeEvent(payload []byte) ([]byte, error) {
+	var envelope WebhookEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse RCS webhook: %w", err)
+	}
+	if envelope.Message.Data == "" {
+		return nil, fmt.Errorf("RCS webhook has no message data")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(envelope.Message.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode RCS event: %w", err)
+	}
+	return data, nil
+}
+
+// mediaTypeOf maps a mime type to the content types used across channels
+func mediaTypeOf(mimeType string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return "image"
+	case strings.HasPrefix(mimeType, "video/"):
+		return "video"
+	case strings.HasPrefix(mimeType, "audio/"):
+		return "audio"
+	default:
+		return "document"
+	}
+}
+
+// ============================================================================
+// Security & Validation
+// ============================================================================
+
+// verifySignature checks X-Goog-Signature, the base64 HMAC-SHA512 of the
+// decoded event keyed with the webhook's client token
+func (a *RCSAdapter) verifySignature(payload []byte, headers map[string]string) error {
+	if a.config.ClientToken == "" {
+		log.Printf("⚠️  RCS client token not configured, skipping signature verification")
+		return nil
+	}
+
+	signature := headers[SignatureHeader]
+	if signature == "" {
+		signature = headers[strings.ToLower(SignatureHeader)]
+	}
+	if signature == "" {
+		return channels.ErrInvalidWebhookSignature().
+			WithDetail("reason", "missing "+SignatureHeader+" header")
+	}
+
+	data, err := decodeEvent(payload)
+	if err != nil {
+		return channels.ErrInvalidWebhookSignature().WithDetail("reason", err.Error())
+	}
+
+	mac := hmac.New(sha512.New, []byte(a.config.ClientToken))
+	mac.Write(data)
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return channels.ErrInvalidWebhookSignature().WithDetail("reason", "signature mismatch")
+	}
+
+	return nil
+}
+
+// parseAPIError parses Google API error responses
+func (a *RCSAdapter) parseAPIError(resp *httpclient.Response) error {
+	var apiError struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+			Status  string `json:"status"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(resp.Body, &apiError); err != nil {
+		return resp.Err().WithDetail("body", string(resp.Body))
+	}
+
+	return resp.Err().
+		WithDetail("error_status", apiError.Error.Status).
+		WithDetail("error_message", apiError.Error.Message)
+}
+
+// phoneNumber puts a number in the E.164 form RBM addresses users by
+func phoneNumber(phone string) string {
+	phone = strings.TrimSpace(phone)
+	if phone == "" || strings.HasPrefix(phone, "+") {
+		return phone
+	}
+	return "+" + phone
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// ============================================================================
+// RCS Webhook Data Structures
+// ============================================================================
+
+// WebhookEnvelope is the Pub/Sub push message RBM posts to the webhook
+type WebhookEnvelope struct {
+	Message struct {
+		Data        string `json:"data"` // Base64 of the UserEvent
+		MessageID   string `json:"messageId"`
+		PublishTime string `json:"publishTime"`
+	} `json:"message"`
+	Subscription string `json:"subscription"`
+}
+
+// WebhookVerification is the request RBM sends when the webhook is
+// configured; the secret is echoed back
+type WebhookVerification struct {
+	ClientToken string `json:"clientToken"`
+	Secret      string `json:"secret"`
+}
+
+// UserEvent is a message or event from a user's phone
+type UserEvent struct {
+	SenderPhoneNumber  string              `json:"senderPhoneNumber"`
+	MessageID          string              `json:"messageId"` // The agent message an event refers to
+	EventID            string              `json:"eventId,omitempty"`
+	EventType          string              `json:"eventType,omitempty"` // DELIVERED, READ, IS_TYPING
+	SendTime           time.Time           `json:"sendTime"`
+	AgentID            string              `json:"agentId"`
+	Text               string              `json:"text,omitempty"`
+	UserFile           *UserFile           `json:"userFile,omitempty"`
+	Location           *LatLong            `json:"location,omitempty"`
+	SuggestionResponse *SuggestionResponse `json:"suggestionResponse,omitempty"`
+}
+
+// UserFile is a file the user sent
+type UserFile struct {
+	Payload struct {
+		MimeType      string `json:"mimeType"`
+		FileSizeBytes int64  `json:"fileSizeBytes"`
+		FileURI       string `json:"fileUri"`
+		FileName      string `json:"fileName"`
+	} `json:"payload"`
+}
+
+// LatLong is a location the user shared
+type LatLong struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// SuggestionResponse is the suggested reply or action the user tapped
+type SuggestionResponse struct {
+	PostbackData string `json:"postbackData"`
+	Text         string `json:"text"`
+	Type         string `json:"type"` // REPLY, ACTION
+}
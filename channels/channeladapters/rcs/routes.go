@@ -0,0 +1,33 @@
+package rcs
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebhookRoutes handles RCS webhook route setup
+type WebhookRoutes struct {
+	handler               *WebhookHandler
+	messageProcessHandler fiber.Handler // Generic handler from channelapi
+}
+
+// NewWebhookRoutes creates a new webhook routes instance
+func NewWebhookRoutes(
+	handler *WebhookHandler,
+	messageProcessHandler fiber.Handler,
+) *WebhookRoutes {
+	return &WebhookRoutes{
+		handler:               handler,
+		messageProcessHandler: messageProcessHandler,
+	}
+}
+
+// RegisterRoutes configures RCS webhook routes. RBM posts both the webhook
+// verification and the events to the same URL.
+func (wr *WebhookRoutes) RegisterRoutes(app *fiber.App) {
+	webhooks := app.Group("/webhooks/rcs")
+
+	webhooks.Post("/:tenantId/:channelId",
+		wr.handler.ReceiveWebhook, // Parse RBM event
+		wr.messageProcessHandler,  // Process generic message
+	)
+}
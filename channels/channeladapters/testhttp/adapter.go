@@ -0,0 +1,105 @@
+package testhttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// TestHTTPAdapter implements ChannelAdapter for the TEST_HTTP channel type.
+// It never talks to an external provider: outgoing messages are only logged.
+type TestHTTPAdapter struct {
+	config channels.TestHTTPConfig
+}
+
+var (
+	_ channels.ChannelAdapter = (*TestHTTPAdapter)(nil)
+	_ channels.MessageEditor  = (*TestHTTPAdapter)(nil)
+)
+
+// NewTestHTTPAdapter creates a new TEST_HTTP adapter
+func NewTestHTTPAdapter(config channels.TestHTTPConfig) *TestHTTPAdapter {
+	return &TestHTTPAdapter{config: config}
+}
+
+// inboundPayload is the JSON body accepted by ProcessWebhook
+type inboundPayload struct {
+	MessageID string                   `json:"message_id,omitempty"`
+	SenderID  string                   `json:"sender_id"`
+	Text      string                   `json:"text,omitempty"`
+	Content   *channels.MessageContent `json:"content,omitempty"`
+	Metadata  map[string]any           `json:"metadata,omitempty"`
+}
+
+func (a *TestHTTPAdapter) GetType() channels.ChannelType {
+	return channels.ChannelTypeTestHTTP
+}
+
+func (a *TestHTTPAdapter) SendMessage(ctx context.Context, msg channels.OutgoingMessage) error {
+	log.Printf("🧪 [TEST_HTTP] Message to %s: %s", msg.RecipientID, msg.Content.Text)
+	return nil
+}
+
+// SendPartial logs the beginning of a streamed message and makes up its ID
+func (a *TestHTTPAdapter) SendPartial(ctx context.Context, msg channels.OutgoingMessage) (string, error) {
+	messageID := uuid.NewString()
+	log.Printf("🧪 [TEST_HTTP] Partial message %s to %s: %s", messageID, msg.RecipientID, msg.Content.Text)
+	return messageID, nil
+}
+
+// EditMessage logs the new content of a streamed message
+func (a *TestHTTPAdapter) EditMessage(ctx context.Context, recipientID, providerMessageID string, content channels.MessageContent) error {
+	log.Printf("🧪 [TEST_HTTP] Edited message %s to %s: %s", providerMessageID, recipientID, content.Text)
+	return nil
+}
+
+func (a *TestHTTPAdapter) ValidateConfig(config channels.ChannelConfig) error {
+	testConfig, ok := config.(channels.TestHTTPConfig)
+	if !ok {
+		return channels.ErrInvalidChannelConfig().WithDetail("reason", "invalid config type")
+	}
+	return testConfig.Validate()
+}
+
+func (a *TestHTTPAdapter) ProcessWebhook(ctx context.Context, payload []byte, headers map[string]string) (*channels.IncomingMessage, error) {
+	var body inboundPayload
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, fmt.Errorf("failed to parse test payload: %w", err)
+	}
+
+	if body.SenderID == "" {
+		return nil, channels.ErrInvalidRecipient().WithDetail("reason", "sender_id is required")
+	}
+
+	content := channels.MessageContent{Type: "text", Text: body.Text}
+	if body.Content != nil {
+		content = *body.Content
+	}
+
+	messageID := body.MessageID
+	if messageID == "" {
+		messageID = uuid.NewString()
+	}
+
+	return &channels.IncomingMessage{
+		MessageID: kernel.NewMessageID(messageID),
+		SenderID:  body.SenderID,
+		Content:   content,
+		Timestamp: time.Now().Unix(),
+		Metadata:  body.Metadata,
+	}, nil
+}
+
+func (a *TestHTTPAdapter) GetFeatures() channels.ChannelFeatures {
+	return a.config.GetFeatures()
+}
+
+func (a *TestHTTPAdapter) TestConnection(ctx context.Context, config channels.ChannelConfig) error {
+	return nil
+}
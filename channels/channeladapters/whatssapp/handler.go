@@ -4,7 +4,10 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/Abraxas-365/craftable/eventx"
 	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/channeladapters/meta"
+	"github.com/Abraxas-365/relay/featureflag"
 	"github.com/Abraxas-365/relay/pkg/kernel"
 	"github.com/gofiber/fiber/v2"
 )
@@ -13,19 +16,42 @@ import (
 type WebhookHandler struct {
 	channelRepo channels.ChannelRepository
 	adapter     *WhatsAppAdapter
+	flags       featureflag.Checker // Optional; nil enables everything
+	events      channels.WebhookEventRecorder
+	buffer      channels.InboundBuffer
+	bus         eventx.EventBus
 }
 
 // NewWebhookHandler creates a new WhatsApp webhook handler
 func NewWebhookHandler(
 	channelRepo channels.ChannelRepository,
 	adapter *WhatsAppAdapter,
+	flags featureflag.Checker,
 ) *WebhookHandler {
 	return &WebhookHandler{
 		channelRepo: channelRepo,
 		adapter:     adapter,
+		flags:       flags,
 	}
 }
 
+// SetEventRecorder keeps the webhook events the adapter does not handle
+func (h *WebhookHandler) SetEventRecorder(recorder channels.WebhookEventRecorder) {
+	h.events = recorder
+}
+
+// SetBuffer groups the messages a sender sends in quick succession when the
+// channel enables buffering. Without it every message is processed alone.
+func (h *WebhookHandler) SetBuffer(buffer channels.InboundBuffer) {
+	h.buffer = buffer
+}
+
+// SetEventBus publishes the statuses of sent messages as message.status
+// events. Without it they are dropped.
+func (h *WebhookHandler) SetEventBus(bus eventx.EventBus) {
+	h.bus = bus
+}
+
 // VerifyWebhook handles Meta's webhook verification challenge
 // GET /webhooks/whatsapp/:tenantId/:channelId
 func (h *WebhookHandler) VerifyWebhook(c *fiber.Ctx) error {
@@ -53,13 +79,8 @@ func (h *WebhookHandler) VerifyWebhook(c *fiber.Ctx) error {
 		return fiber.NewError(http.StatusBadRequest, "Not a WhatsApp channel")
 	}
 
-	// Extract verification parameters from query
-	mode := c.Query("hub.mode")
-	token := c.Query("hub.verify_token")
-	challenge := c.Query("hub.challenge")
-
 	// Verify the token matches
-	if mode == "subscribe" && token == whatsappConfig.WebhookVerifyToken {
+	if challenge, ok := meta.VerifyChallenge(c, whatsappConfig.WebhookVerifyToken); ok {
 		log.Printf("✅ Webhook verified successfully for channel: %s", channelID)
 		return c.SendString(challenge)
 	}
@@ -103,8 +124,17 @@ func (h *WebhookHandler) ReceiveWebhook(c *fiber.Ctx) error {
 		return c.SendStatus(fiber.StatusOK)
 	}
 
+	// Feature flags are read per request so toggles apply without a restart
+	if !h.featureEnabled(c, tenantID, featureflag.ChannelFlag(string(channels.ChannelTypeWhatsApp))) {
+		log.Printf("🚩 WhatsApp adapter disabled for tenant %s, dropping webhook", tenantID)
+		return c.SendStatus(fiber.StatusOK)
+	}
+	if !h.featureEnabled(c, tenantID, featureflag.FlagMessageBuffering) {
+		whatsappConfig.BufferEnabled = false
+	}
+
 	// Create adapter instance with this channel's config
-	adapter := NewWhatsAppAdapter(whatsappConfig, h.adapter.bufferService.redis)
+	adapter := NewWhatsAppAdapter(whatsappConfig)
 
 	// Read payload
 	body := c.Body()
@@ -115,6 +145,18 @@ func (h *WebhookHandler) ReceiveWebhook(c *fiber.Ctx) error {
 		headers[string(key)] = string(value)
 	})
 
+	// Twilio posts forms and signs them along with the webhook URL
+	if whatsappConfig.Provider == channels.WhatsAppProviderTwilio {
+		if body, err = formToJSON(body); err != nil {
+			log.Printf("❌ Failed to read Twilio webhook: %v", err)
+			return c.SendStatus(fiber.StatusOK)
+		}
+		headers[RequestURLHeader] = channel.WebhookURL
+		if headers[RequestURLHeader] == "" {
+			headers[RequestURLHeader] = c.BaseURL() + c.OriginalURL()
+		}
+	}
+
 	// Process webhook using adapter (WhatsApp-specific parsing)
 	incomingMsg, err := adapter.ProcessWebhook(c.Context(), body, headers)
 	if err != nil {
@@ -123,12 +165,34 @@ func (h *WebhookHandler) ReceiveWebhook(c *fiber.Ctx) error {
 		return c.SendStatus(fiber.StatusOK)
 	}
 
-	// If message is nil, it means it's buffered or not a message event
+	// Events the adapter ignores are stored so they can be reprocessed later
+	if h.events != nil {
+		if eventTypes := adapter.UnsupportedEvents(body); len(eventTypes) > 0 {
+			h.events.RecordUnsupported(c.Context(), channel, eventTypes, body)
+		}
+	}
+
+	if receipts := adapter.DeliveryReceipts(body); len(receipts) > 0 {
+		channels.PublishDeliveryReceipts(c.Context(), h.bus, channel, receipts)
+	}
+
+	// If message is nil, it is not a message event (status update, etc.)
 	if incomingMsg == nil {
-		log.Printf("📦 Message buffered or status update for channel: %s", channelID)
+		log.Printf("ℹ️  No message in webhook for channel: %s", channelID)
 		return c.SendStatus(fiber.StatusOK)
 	}
 
+	// Buffered messages are delivered combined once the channel's window ends
+	if whatsappConfig.BufferEnabled && h.buffer != nil {
+		settings := channels.NewBufferSettings(whatsappConfig.BufferTimeSeconds, whatsappConfig.BufferResetOnMessage, 0)
+		err := h.buffer.Add(c.Context(), channel, *incomingMsg, settings)
+		if err == nil {
+			log.Printf("📦 WhatsApp message from %s buffered for channel: %s", incomingMsg.SenderID, channelID)
+			return c.SendStatus(fiber.StatusOK)
+		}
+		log.Printf("⚠️  Failed to buffer WhatsApp message, processing it now: %v", err)
+	}
+
 	// Store parsed message in context for the next handler
 	c.Locals("incoming_message", incomingMsg)
 	c.Locals("channel", channel)
@@ -137,3 +201,7 @@ func (h *WebhookHandler) ReceiveWebhook(c *fiber.Ctx) error {
 	return c.Next()
 }
 
+// featureEnabled checks a tenant flag; without a checker everything is enabled
+func (h *WebhookHandler) featureEnabled(c *fiber.Ctx, tenantID kernel.TenantID, flag featureflag.Flag) bool {
+	return h.flags == nil || h.flags.IsEnabled(c.Context(), tenantID, flag)
+}
@@ -0,0 +1,920 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/channeladapters/meta"
+	"github.com/Abraxas-365/relay/channels/httpclient"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+const (
+	whatsappAPIBaseURL = "https://graph.facebook.com"
+	defaultAPIVersion  = "v24.0"
+)
+
+// supportedMessageTypes are the message types extractIncomingMessage maps.
+// Anything else is reported by UnsupportedEvents instead of being delivered empty.
+var supportedMessageTypes = map[string]bool{
+	"text":        true,
+	"image":       true,
+	"document":    true,
+	"audio":       true,
+	"video":       true,
+	"location":    true,
+	"contacts":    true,
+	"interactive": true,
+	"button":      true,
+}
+
+// metaProvider talks to Meta's WhatsApp Cloud API
+type metaProvider struct {
+	config     channels.WhatsAppConfig
+	httpClient *httpclient.Client
+	apiURL     string
+	graphURL   string
+}
+
+var _ provider = (*metaProvider)(nil)
+
+func newMetaProvider(config channels.WhatsAppConfig) *metaProvider {
+	apiVersion := config.APIVersion
+	if apiVersion == "" {
+		apiVersion = defaultAPIVersion
+	}
+
+	return &metaProvider{
+		config:     config,
+		httpClient: httpclient.For(channels.ChannelTypeWhatsApp),
+		apiURL:     fmt.Sprintf("%s/%s/%s", whatsappAPIBaseURL, apiVersion, config.PhoneNumberID),
+		graphURL:   fmt.Sprintf("%s/%s", whatsappAPIBaseURL, apiVersion),
+	}
+}
+
+// send sends a message and returns the wamid WhatsApp assigned to it, which
+// replies quoting the message refer to
+func (p *metaProvider) send(ctx context.Context, msg channels.OutgoingMessage) (string, error) {
+	if msg.Content.Interactive != nil {
+		if err := validateInteractive(msg.Content.Interactive); err != nil {
+			return "", err
+		}
+	}
+
+	// Build WhatsApp API payload
+	payload := p.buildMessagePayload(msg)
+
+	// Build URL using the pre-configured apiURL
+	url := fmt.Sprintf("%s/messages", p.apiURL)
+
+	// ✅ LOG THE ACTUAL URL BEING CALLED
+	log.Printf("🌐 WhatsApp API URL: %s", url)
+	log.Printf("📦 Payload: %+v", payload)
+	log.Printf("🔑 Token (first 20 chars): %s...", p.config.AccessToken[:20])
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	resp, err := p.httpClient.Do(ctx, httpclient.Request{
+		Method: http.MethodPost,
+		URL:    url,
+		Header: http.Header{
+			"Authorization": {"Bearer " + p.config.AccessToken},
+			"Content-Type":  {"application/json"},
+		},
+		Body: jsonData,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+
+	body := resp.Body
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		log.Printf("❌ WhatsApp API Error - Status: %d, Body: %s", resp.StatusCode, string(body))
+		return "", resp.Err().WithDetail("response", string(body))
+	}
+
+	log.Printf("✅ WhatsApp message sent successfully - Response: %s", string(body))
+
+	var sent struct {
+		Messages []struct {
+			ID string `json:"id"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &sent); err != nil || len(sent.Messages) == 0 {
+		return "", nil
+	}
+	return sent.Messages[0].ID, nil
+}
+
+// verifyWebhook checks the X-Hub-Signature-256 of the payload
+func (p *metaProvider) verifyWebhook(payload []byte, headers map[string]string) error {
+	return meta.VerifySignature(p.config.AppSecret, payload, headers)
+}
+
+// parseWebhook extracts the message of a webhook; nil for status updates
+func (p *metaProvider) parseWebhook(payload []byte) (*channels.IncomingMessage, error) {
+	var webhook WhatsAppWebhook
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook: %w", err)
+	}
+
+	incomingMsg, err := p.extractIncomingMessage(webhook)
+	if err != nil || incomingMsg == nil {
+		return nil, err
+	}
+
+	incomingMsg.RawPayload = channels.DecodeRawPayload(payload)
+	return incomingMsg, nil
+}
+
+// unsupportedEvents lists the webhook's events this adapter ignores: fields
+// other than messages (template status, account updates, ...) as the field
+// name, and unmapped message types as "message:<type>"
+func (p *metaProvider) unsupportedEvents(payload []byte) []string {
+	var webhook WhatsAppWebhook
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return nil
+	}
+
+	var events []string
+	seen := make(map[string]bool)
+	add := func(event string) {
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+
+	for _, entry := range webhook.Entry {
+		for _, change := range entry.Changes {
+			if change.Field != "" && change.Field != "messages" {
+				add(change.Field)
+				continue
+			}
+			for _, msg := range change.Value.Messages {
+				if !supportedMessageTypes[msg.Type] {
+					add("message:" + msg.Type)
+				}
+			}
+		}
+	}
+
+	return events
+}
+
+// deliveryReceipts maps the statuses of sent messages
+func (p *metaProvider) deliveryReceipts(payload []byte) []channels.DeliveryReceipt {
+	var webhook WhatsAppWebhook
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return nil
+	}
+
+	var receipts []channels.DeliveryReceipt
+	for _, entry := range webhook.Entry {
+		for _, change := range entry.Changes {
+			for _, status := range change.Value.Statuses {
+				receipt := channels.DeliveryReceipt{
+					ProviderMessageID: status.ID,
+					RecipientID:       status.RecipientID,
+					At:                time.Unix(status.Timestamp, 0),
+				}
+				switch status.Status {
+				case "sent":
+					receipt.Status = channels.DeliveryStatusSent
+				case "delivered":
+					receipt.Status = channels.DeliveryStatusDelivered
+				case "read":
+					receipt.Status = channels.DeliveryStatusRead
+				case "failed":
+					receipt.Status = channels.DeliveryStatusFailed
+					if len(status.Errors) > 0 {
+						receipt.Error = fmt.Sprintf("%d: %s", status.Errors[0].Code, status.Errors[0].Title)
+					}
+				default:
+					continue
+				}
+				receipts = append(receipts, receipt)
+			}
+		}
+	}
+	return receipts
+}
+
+// testConnection fetches the phone number's info
+func (p *metaProvider) testConnection(ctx context.Context) error {
+	resp, err := p.httpClient.Do(ctx, httpclient.Request{
+		Method: http.MethodGet,
+		URL:    p.apiURL,
+		Header: http.Header{"Authorization": {"Bearer " + p.config.AccessToken}},
+	})
+	if err != nil {
+		return channels.ErrProviderAPIError().WithCause(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return channels.ErrProviderAuthFailed().
+			WithDetail("status", resp.StatusCode).
+			WithDetail("response", string(resp.Body))
+	}
+
+	return nil
+}
+
+// buildMessagePayload builds WhatsApp API payload
+func (p *metaProvider) buildMessagePayload(msg channels.OutgoingMessage) map[string]any {
+	payload := map[string]any{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                msg.RecipientID,
+	}
+
+	// Quoted reply
+	if msg.ReplyToID != "" {
+		payload["context"] = map[string]string{"message_id": msg.ReplyToID}
+	}
+
+	// Handle different content types
+	if msg.Content.Interactive != nil && msg.Content.Type != "template" {
+		payload["type"] = "interactive"
+		payload["interactive"] = buildInteractivePayload(msg.Content)
+	} else if msg.Content.Type == "text" {
+		payload["type"] = "text"
+		payload["text"] = map[string]any{
+			"body": msg.Content.Text,
+		}
+	} else if msg.Content.Type == "template" && msg.TemplateID != "" {
+		payload["type"] = "template"
+		payload["template"] = p.buildTemplatePayload(msg)
+	} else if msg.Content.Type == "location" && msg.Content.Location != nil {
+		payload["type"] = "location"
+		payload["location"] = buildLocationPayload(*msg.Content.Location)
+	} else if msg.Content.Type == "contacts" && len(msg.Content.Contacts) > 0 {
+		payload["type"] = "contacts"
+		payload["contacts"] = buildContactsPayload(msg.Content.Contacts)
+	}
+	// Add more content types as needed
+
+	return payload
+}
+
+// WhatsApp interactive message limits
+const (
+	maxReplyButtons   = 3
+	maxListRows       = 10
+	maxListSections   = 10
+	maxButtonTitleLen = 20
+	maxRowTitleLen    = 24
+	maxListButtonLen  = 20
+	defaultListButton = "Options"
+	interactiveButton = "button"
+	interactiveList   = "list"
+)
+
+// interactiveType picks reply buttons or a list: lists are used when asked
+// for or when there is no button to show
+func interactiveType(interactive *channels.Interactive) string {
+	if interactive.Type == interactiveList || len(interactive.Buttons) == 0 {
+		return interactiveList
+	}
+	return interactiveButton
+}
+
+// validateInteractive checks WhatsApp's limits before the API rejects the message
+func validateInteractive(interactive *channels.Interactive) error {
+	invalid := func(reason string) error {
+		return channels.ErrInvalidMessageFormat().WithDetail("reason", reason)
+	}
+
+	if interactiveType(interactive) == interactiveButton {
+		if len(interactive.Buttons) > maxReplyButtons {
+			return invalid(fmt.Sprintf("at most %d reply buttons", maxReplyButtons))
+		}
+		for _, btn := range interactive.Buttons {
+			if btn.ID == "" || btn.Title == "" || len([]rune(btn.Title)) > maxButtonTitleLen {
+				return invalid(fmt.Sprintf("buttons need an id and a title of up to %d characters", maxButtonTitleLen))
+			}
+		}
+		return nil
+	}
+
+	rows := interactive.ListItems()
+	if len(rows) == 0 {
+		return invalid("a list needs at least one item")
+	}
+	if len(rows) > maxListRows {
+		return invalid(fmt.Sprintf("at most %d list items", maxListRows))
+	}
+	if len(interactive.Sections) > maxListSections {
+		return invalid(fmt.Sprintf("at most %d list sections", maxListSections))
+	}
+	if len(interactive.Sections) > 1 {
+		for _, section := range interactive.Sections {
+			if section.Title == "" {
+				return invalid("sections need a title when there is more than one")
+			}
+		}
+	}
+	for _, row := range rows {
+		if row.ID == "" || row.Title == "" || len([]rune(row.Title)) > maxRowTitleLen {
+			return invalid(fmt.Sprintf("list items need an id and a title of up to %d characters", maxRowTitleLen))
+		}
+	}
+	if len([]rune(interactive.ButtonText)) > maxListButtonLen {
+		return invalid(fmt.Sprintf("button_text is limited to %d characters", maxListButtonLen))
+	}
+	return nil
+}
+
+// buildInteractivePayload builds reply buttons or a list picker. The body
+// falls back to the message text.
+func buildInteractivePayload(content channels.MessageContent) map[string]any {
+	interactive := content.Interactive
+
+	body := interactive.Body
+	if body == "" {
+		body = content.Text
+	}
+
+	kind := interactiveType(interactive)
+	payload := map[string]any{
+		"type": kind,
+		"body": map[string]string{"text": body},
+	}
+	if interactive.Header != "" {
+		payload["header"] = map[string]string{"type": "text", "text": interactive.Header}
+	}
+	if interactive.Footer != "" {
+		payload["footer"] = map[string]string{"text": interactive.Footer}
+	}
+
+	if kind == interactiveButton {
+		buttons := make([]map[string]any, 0, len(interactive.Buttons))
+		for _, btn := range interactive.Buttons {
+			buttons = append(buttons, map[string]any{
+				"type":  "reply",
+				"reply": map[string]string{"id": btn.ID, "title": btn.Title},
+			})
+		}
+		payload["action"] = map[string]any{"buttons": buttons}
+		return payload
+	}
+
+	sections := interactive.Sections
+	if len(sections) == 0 {
+		sections = []channels.Section{{Items: interactive.Items}}
+	}
+	listSections := make([]map[string]any, 0, len(sections))
+	for _, section := range sections {
+		rows := make([]map[string]string, 0, len(section.Items))
+		for _, item := range section.Items {
+			row := map[string]string{"id": item.ID, "title": item.Title}
+			if item.Description != "" {
+				row["description"] = item.Description
+			}
+			rows = append(rows, row)
+		}
+		listSection := map[string]any{"rows": rows}
+		if section.Title != "" {
+			listSection["title"] = section.Title
+		}
+		listSections = append(listSections, listSection)
+	}
+
+	buttonText := interactive.ButtonText
+	if buttonText == "" {
+		buttonText = defaultListButton
+	}
+	payload["action"] = map[string]any{
+		"button":   buttonText,
+		"sections": listSections,
+	}
+	return payload
+}
+
+// buildLocationPayload builds a location pin payload
+func buildLocationPayload(location channels.Location) map[string]any {
+	pin := map[string]any{
+		"latitude":  location.Latitude,
+		"longitude": location.Longitude,
+	}
+	if location.Name != "" {
+		pin["name"] = location.Name
+	}
+	if location.Address != "" {
+		pin["address"] = location.Address
+	}
+	return pin
+}
+
+// buildContactsPayload builds contact cards. WhatsApp requires a formatted
+// name plus at least one other name field.
+func buildContactsPayload(contacts []channels.Contact) []map[string]any {
+	cards := make([]map[string]any, 0, len(contacts))
+	for _, contact := range contacts {
+		firstName := contact.FirstName
+		if firstName == "" && contact.LastName == "" {
+			firstName = contact.Name
+		}
+		name := map[string]any{"formatted_name": contact.Name}
+		if firstName != "" {
+			name["first_name"] = firstName
+		}
+		if contact.LastName != "" {
+			name["last_name"] = contact.LastName
+		}
+
+		card := map[string]any{"name": name}
+		if contact.PhoneNumber != "" {
+			card["phones"] = []map[string]string{{"phone": contact.PhoneNumber, "type": "WORK"}}
+		}
+		if contact.Email != "" {
+			card["emails"] = []map[string]string{{"email": contact.Email, "type": "WORK"}}
+		}
+		if contact.Organization != "" || contact.Title != "" {
+			card["org"] = map[string]string{"company": contact.Organization, "title": contact.Title}
+		}
+		if contact.URL != "" {
+			card["urls"] = []map[string]string{{"url": contact.URL, "type": "WORK"}}
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+// buildTemplatePayload builds template message payload
+func (p *metaProvider) buildTemplatePayload(msg channels.OutgoingMessage) map[string]any {
+	template := map[string]any{
+		"name":     msg.TemplateID,
+		"language": map[string]string{"code": "en"},
+	}
+
+	if len(msg.Variables) > 0 {
+		components := []map[string]any{}
+		parameters := []map[string]any{}
+
+		for _, name := range templateVariableOrder(msg.Variables) {
+			parameters = append(parameters, map[string]any{
+				"type": "text",
+				"text": msg.Variables[name],
+			})
+		}
+
+		components = append(components, map[string]any{
+			"type":       "body",
+			"parameters": parameters,
+		})
+
+		template["components"] = components
+	}
+
+	return template
+}
+
+// templateVariableOrder sorts the variables into the template's positional
+// parameters: numbered ones ("1", "2", ..., "10") by number, the rest by name
+func templateVariableOrder(variables map[string]string) []string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		x, errX := strconv.Atoi(a)
+		y, errY := strconv.Atoi(b)
+		switch {
+		case errX == nil && errY == nil:
+			return x - y
+		case errX == nil:
+			return -1
+		case errY == nil:
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	return names
+}
+
+// render builds the Cloud API payload send would post and lists what
+// WhatsApp shows differently
+func (p *metaProvider) render(msg channels.OutgoingMessage) (channels.RenderedMessage, error) {
+	content := msg.Content
+	interactive := content.Interactive
+	if interactive != nil && content.Type != "template" {
+		if err := validateInteractive(interactive); err != nil {
+			return channels.RenderedMessage{}, err
+		}
+	}
+
+	payload := p.buildMessagePayload(msg)
+	if _, ok := payload["type"]; !ok {
+		return channels.RenderedMessage{}, channels.ErrInvalidMessageFormat().
+			WithDetail("reason", fmt.Sprintf("%s content is not sent on WhatsApp", content.Type))
+	}
+
+	var changes []channels.RenderChange
+	add := func(field, action, detail string) {
+		changes = append(changes, channels.RenderChange{Field: field, Action: action, Detail: detail})
+	}
+
+	switch payload["type"] {
+	case "interactive":
+		if interactive.Body != "" && content.Text != "" {
+			add("content.text", channels.RenderDropped, "interactive.body is sent instead")
+		}
+		if content.MediaURL != "" {
+			add("content.media_url", channels.RenderDropped, "interactive messages are sent without media")
+		}
+		if interactiveType(interactive) == interactiveList {
+			if len(interactive.Buttons) > 0 {
+				add("content.interactive.buttons", channels.RenderDropped, "the message is sent as a list")
+			}
+			if interactive.ButtonText == "" {
+				add("content.interactive.button_text", channels.RenderSubstituted, defaultListButton)
+			}
+		}
+	case "template":
+		if names := templateVariableOrder(msg.Variables); len(names) > 0 {
+			add("variables", channels.RenderSubstituted, "body parameters in order: "+strings.Join(names, ", "))
+		}
+	case "location":
+		if content.Text != "" {
+			add("content.text", channels.RenderDropped, "location pins carry no text")
+		}
+	}
+
+	return channels.RenderedMessage{Payload: payload, Changes: changes}, nil
+}
+
+// extractIncomingMessage extracts message from webhook
+func (p *metaProvider) extractIncomingMessage(webhook WhatsAppWebhook) (*channels.IncomingMessage, error) {
+	for _, entry := range webhook.Entry {
+		for _, change := range entry.Changes {
+			if change.Value.MessagingProduct != "whatsapp" {
+				continue
+			}
+
+			for _, msg := range change.Value.Messages {
+				if !supportedMessageTypes[msg.Type] {
+					continue // Reported by UnsupportedEvents
+				}
+
+				return &channels.IncomingMessage{
+					MessageID:  msg.ID,
+					ChannelID:  kernel.NewChannelID(p.config.PhoneNumberID),
+					SenderID:   msg.From,
+					SenderName: senderName(change.Value.Contacts, msg.From),
+					Content: channels.MessageContent{
+						Type:        msg.Type,
+						Text:        p.extractText(msg),
+						Attachments: p.extractMedia(msg),
+						Location:    p.extractLocation(msg),
+						Contacts:    p.extractContacts(msg),
+						Postback:    p.extractPostback(msg),
+						ReplyTo:     p.extractReplyTo(msg),
+					},
+					Timestamp: msg.Timestamp,
+					Metadata: map[string]any{
+						"whatsapp_message_id": msg.ID,
+					},
+				}, nil
+			}
+		}
+	}
+
+	return nil, nil // No message found
+}
+
+// senderName finds the profile name of the message's sender
+func senderName(senders []WebhookSender, from string) string {
+	for _, sender := range senders {
+		if sender.WaID == from {
+			return sender.Profile.Name
+		}
+	}
+	return ""
+}
+
+// extractText extracts text from message
+func (p *metaProvider) extractText(msg WebhookMessage) string {
+	if msg.Text != nil {
+		return msg.Text.Body
+	}
+	if msg.Image != nil && msg.Image.Caption != "" {
+		return msg.Image.Caption
+	}
+	if postback := p.extractPostback(msg); postback != nil {
+		return postback.Title
+	}
+	return ""
+}
+
+// extractReplyTo reads the quoted message of a reply. Forwarded messages
+// also carry a context, but without a message ID.
+func (p *metaProvider) extractReplyTo(msg WebhookMessage) *channels.ReplyTo {
+	if msg.Context == nil || msg.Context.ID == "" {
+		return nil
+	}
+	return &channels.ReplyTo{
+		MessageID: msg.Context.ID,
+		SenderID:  msg.Context.From,
+	}
+}
+
+// extractPostback normalizes a tapped reply button, list row or template
+// quick reply
+func (p *metaProvider) extractPostback(msg WebhookMessage) *channels.Postback {
+	if msg.Interactive != nil {
+		switch {
+		case msg.Interactive.ButtonReply != nil:
+			return &channels.Postback{
+				Type:  "button",
+				ID:    msg.Interactive.ButtonReply.ID,
+				Title: msg.Interactive.ButtonReply.Title,
+			}
+		case msg.Interactive.ListReply != nil:
+			return &channels.Postback{
+				Type:        "list",
+				ID:          msg.Interactive.ListReply.ID,
+				Title:       msg.Interactive.ListReply.Title,
+				Description: msg.Interactive.ListReply.Description,
+			}
+		}
+	}
+	if msg.Button != nil {
+		return &channels.Postback{
+			Type:  "quick_reply",
+			ID:    msg.Button.Payload,
+			Title: msg.Button.Text,
+		}
+	}
+	return nil
+}
+
+// extractMedia maps the message's media to attachments. WhatsApp sends a
+// media ID; FetchMedia resolves it to the file.
+func (p *metaProvider) extractMedia(msg WebhookMessage) []channels.Attachment {
+	media := map[string]*WebhookMedia{
+		"image":    msg.Image,
+		"document": msg.Document,
+		"audio":    msg.Audio,
+		"video":    msg.Video,
+	}[msg.Type]
+	if media == nil {
+		return nil
+	}
+
+	return []channels.Attachment{{
+		Type:            msg.Type,
+		MimeType:        media.MimeType,
+		Filename:        media.Filename,
+		Caption:         media.Caption,
+		ProviderMediaID: media.ID,
+	}}
+}
+
+// extractLocation maps a shared location pin
+func (p *metaProvider) extractLocation(msg WebhookMessage) *channels.Location {
+	if msg.Location == nil {
+		return nil
+	}
+	return &channels.Location{
+		Latitude:  msg.Location.Latitude,
+		Longitude: msg.Location.Longitude,
+		Name:      msg.Location.Name,
+		Address:   msg.Location.Address,
+	}
+}
+
+// extractContacts maps shared contact cards, keeping the first phone,
+// email and URL of each
+func (p *metaProvider) extractContacts(msg WebhookMessage) []channels.Contact {
+	if len(msg.Contacts) == 0 {
+		return nil
+	}
+
+	contacts := make([]channels.Contact, 0, len(msg.Contacts))
+	for _, card := range msg.Contacts {
+		contact := channels.Contact{
+			Name:         card.Name.FormattedName,
+			FirstName:    card.Name.FirstName,
+			LastName:     card.Name.LastName,
+			Organization: card.Org.Company,
+			Title:        card.Org.Title,
+		}
+		if len(card.Phones) > 0 {
+			contact.PhoneNumber = card.Phones[0].Phone
+		}
+		if len(card.Emails) > 0 {
+			contact.Email = card.Emails[0].Email
+		}
+		if len(card.URLs) > 0 {
+			contact.URL = card.URLs[0].URL
+		}
+		contacts = append(contacts, contact)
+	}
+	return contacts
+}
+
+// fetchMedia downloads an inbound media file. Both the lookup and the
+// download need the channel's access token.
+func (p *metaProvider) fetchMedia(ctx context.Context, attachment channels.Attachment) (io.ReadCloser, string, error) {
+	mediaURL := attachment.URL
+	mimeType := attachment.MimeType
+
+	if attachment.ProviderMediaID != "" {
+		resp, err := p.httpClient.Do(ctx, httpclient.Request{
+			Method: http.MethodGet,
+			URL:    fmt.Sprintf("%s/%s", p.graphURL, attachment.ProviderMediaID),
+			Header: http.Header{"Authorization": {"Bearer " + p.config.AccessToken}},
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to look up media: %w", err)
+		}
+		if !resp.IsSuccess() {
+			return nil, "", resp.Err().WithDetail("media_id", attachment.ProviderMediaID)
+		}
+
+		var media struct {
+			URL      string `json:"url"`
+			MimeType string `json:"mime_type"`
+		}
+		if err := json.Unmarshal(resp.Body, &media); err != nil {
+			return nil, "", fmt.Errorf("failed to parse media lookup: %w", err)
+		}
+		mediaURL = media.URL
+		if media.MimeType != "" {
+			mimeType = media.MimeType
+		}
+	}
+
+	if mediaURL == "" {
+		return nil, "", fmt.Errorf("attachment has no media id or url")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mediaURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.config.AccessToken)
+
+	resp, err := mediaDownloadClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download media: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("media download returned status %d", resp.StatusCode)
+	}
+
+	if mimeType == "" {
+		mimeType = resp.Header.Get("Content-Type")
+	}
+	return resp.Body, mimeType, nil
+}
+
+// WhatsApp webhook structures
+type WhatsAppWebhook struct {
+	Object string         `json:"object"`
+	Entry  []WebhookEntry `json:"entry"`
+}
+
+type WebhookEntry struct {
+	ID      string          `json:"id"`
+	Changes []WebhookChange `json:"changes"`
+}
+
+type WebhookChange struct {
+	Value WebhookValue `json:"value"`
+	Field string       `json:"field"`
+}
+
+type WebhookValue struct {
+	MessagingProduct string           `json:"messaging_product"`
+	Metadata         WebhookMetadata  `json:"metadata"`
+	Contacts         []WebhookSender  `json:"contacts"`
+	Messages         []WebhookMessage `json:"messages"`
+	Statuses         []WebhookStatus  `json:"statuses"`
+}
+
+// WebhookSender is the profile WhatsApp sends along with a user's messages
+type WebhookSender struct {
+	WaID    string `json:"wa_id"`
+	Profile struct {
+		Name string `json:"name"`
+	} `json:"profile"`
+}
+
+type WebhookMetadata struct {
+	DisplayPhoneNumber string `json:"display_phone_number"`
+	PhoneNumberID      string `json:"phone_number_id"`
+}
+
+type WebhookMessage struct {
+	ID        kernel.MessageID `json:"id"`
+	From      string           `json:"from"`
+	Timestamp int64            `json:"timestamp,string"`
+	Type      string           `json:"type"`
+	Text      *WebhookText     `json:"text,omitempty"`
+	Image     *WebhookMedia    `json:"image,omitempty"`
+	Document  *WebhookMedia    `json:"document,omitempty"`
+	Audio     *WebhookMedia    `json:"audio,omitempty"`
+	Video     *WebhookMedia    `json:"video,omitempty"`
+	Location  *WebhookLocation `json:"location,omitempty"`
+	Contacts  []WebhookContact `json:"contacts,omitempty"`
+
+	Interactive *WebhookInteractive `json:"interactive,omitempty"`
+	Button      *WebhookButton      `json:"button,omitempty"` // Quick reply of a template
+	Context     *WebhookContext     `json:"context,omitempty"`
+}
+
+type WebhookText struct {
+	Body string `json:"body"`
+}
+
+type WebhookMedia struct {
+	ID       string `json:"id"`
+	MimeType string `json:"mime_type"`
+	SHA256   string `json:"sha256"`
+	Caption  string `json:"caption,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+type WebhookLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Name      string  `json:"name,omitempty"`
+	Address   string  `json:"address,omitempty"`
+	URL       string  `json:"url,omitempty"`
+}
+
+type WebhookContact struct {
+	Name struct {
+		FormattedName string `json:"formatted_name"`
+		FirstName     string `json:"first_name,omitempty"`
+		LastName      string `json:"last_name,omitempty"`
+	} `json:"name"`
+	Phones []struct {
+		Phone string `json:"phone"`
+		WaID  string `json:"wa_id,omitempty"`
+		Type  string `json:"type,omitempty"`
+	} `json:"phones,omitempty"`
+	Emails []struct {
+		Email string `json:"email"`
+		Type  string `json:"type,omitempty"`
+	} `json:"emails,omitempty"`
+	Org struct {
+		Company string `json:"company,omitempty"`
+		Title   string `json:"title,omitempty"`
+	} `json:"org,omitempty"`
+	URLs []struct {
+		URL  string `json:"url"`
+		Type string `json:"type,omitempty"`
+	} `json:"urls,omitempty"`
+}
+
+type WebhookInteractive struct {
+	Type        string `json:"type"` // button_reply, list_reply
+	ButtonReply *struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	} `json:"button_reply,omitempty"`
+	ListReply *struct {
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description,omitempty"`
+	} `json:"list_reply,omitempty"`
+}
+
+// WebhookContext identifies the message a reply quotes
+type WebhookContext struct {
+	From string `json:"from,omitempty"`
+	ID   string `json:"id,omitempty"`
+}
+
+type WebhookButton struct {
+	Payload string `json:"payload"`
+	Text    string `json:"text"`
+}
+
+type WebhookStatus struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"` // sent, delivered, read, failed
+	Timestamp   int64  `json:"timestamp,string"`
+	RecipientID string `json:"recipient_id"`
+	Errors      []struct {
+		Code  int    `json:"code"`
+		Title string `json:"title"`
+	} `json:"errors,omitempty"`
+}
@@ -0,0 +1,574 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/httpclient"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+const (
+	twilioAPIBaseURL = "https://api.twilio.com/2010-04-01"
+
+	// TwilioSignatureHeader carries the base64 HMAC-SHA1 of the webhook URL
+	// and its sorted parameters, keyed with the account's auth token
+	TwilioSignatureHeader = "X-Twilio-Signature"
+
+	// twilioAddressPrefix marks WhatsApp addresses on Twilio
+	twilioAddressPrefix = "whatsapp:"
+)
+
+// twilioProvider talks to WhatsApp through Twilio's Programmable Messaging
+// API. Buttons and lists can only be sent as content templates (ContentSid).
+//
+// Twilio posts webhooks as forms; the handler turns them into a JSON object
+// of their fields (see formToJSON) so they can be stored and replayed like
+// any other webhook.
+type twilioProvider struct {
+	config     channels.WhatsAppConfig
+	httpClient *httpclient.Client
+	apiURL     string
+}
+
+var _ provider = (*twilioProvider)(nil)
+
+func newTwilioProvider(config channels.WhatsAppConfig) *twilioProvider {
+	return &twilioProvider{
+		config:     config,
+		httpClient: httpclient.For(channels.ChannelTypeWhatsApp),
+		apiURL:     fmt.Sprintf("%s/Accounts/%s", twilioAPIBaseURL, config.AccountSID),
+	}
+}
+
+// ============================================================================
+// Sending
+// ============================================================================
+
+// send sends a message and returns its message SID, which status callbacks
+// refer to
+func (p *twilioProvider) send(ctx context.Context, msg channels.OutgoingMessage) (string, error) {
+	form, err := p.buildMessageForm(msg)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := p.httpClient.Do(ctx, httpclient.Request{
+		Method: http.MethodPost,
+		URL:    p.apiURL + "/Messages.json",
+		Header: p.header(http.Header{"Content-Type": {"application/x-www-form-urlencoded"}}),
+		Body:   []byte(form.Encode()),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	if !resp.IsSuccess() {
+		log.Printf("❌ Twilio API Error - Status: %d, Body: %s", resp.StatusCode, string(resp.Body))
+		return "", p.parseAPIError(resp)
+	}
+
+	var sent struct {
+		SID string `json:"sid"`
+	}
+	if err := json.Unmarshal(resp.Body, &sent); err != nil {
+		return "", nil
+	}
+
+	log.Printf("✅ WhatsApp message %s sent via Twilio to %s", sent.SID, msg.RecipientID)
+	return sent.SID, nil
+}
+
+// render builds the form send would post and lists what Twilio sends
+// differently
+func (p *twilioProvider) render(msg channels.OutgoingMessage) (channels.RenderedMessage, error) {
+	form, err := p.buildMessageForm(msg)
+	if err != nil {
+		return channels.RenderedMessage{}, err
+	}
+
+	payload := make(map[string]string, len(form))
+	for key := range form {
+		payload[key] = form.Get(key)
+	}
+
+	var changes []channels.RenderChange
+	add := func(field, action, detail string) {
+		changes = append(changes, channels.RenderChange{Field: field, Action: action, Detail: detail})
+	}
+
+	content := msg.Content
+	switch {
+	case form.Has("ContentSid"):
+		if len(msg.Variables) > 0 {
+			add("variables", channels.RenderSubstituted, "sent as the content template's variables")
+		}
+	case form.Has("PersistentAction"):
+		add("content.location", channels.RenderConverted, "sent as text with a map action")
+	case form.Has("MediaUrl") && content.Caption != "" && content.Text != "":
+		add("content.text", channels.RenderDropped, "the caption is sent as the body")
+	}
+	if msg.ReplyToID != "" {
+		add("reply_to_id", channels.RenderDropped, "Twilio messages cannot quote another message")
+	}
+
+	return channels.RenderedMessage{Payload: payload, Changes: changes}, nil
+}
+
+// buildMessageForm maps message content onto the Messages resource. A
+// template is sent by its content SID with the variables as ContentVariables.
+func (p *twilioProvider) buildMessageForm(msg channels.OutgoingMessage) (url.Values, error) {
+	form := url.Values{"To": {twilioAddress(msg.RecipientID)}}
+	if p.config.MessagingServiceSID != "" {
+		form.Set("MessagingServiceSid", p.config.MessagingServiceSID)
+	} else {
+		form.Set("From", twilioAddress(p.config.PhoneNumber))
+	}
+
+	content := msg.Content
+	switch {
+	case content.Type == "template":
+		if msg.TemplateID == "" {
+			return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "template_id (content SID) is required")
+		}
+		form.Set("ContentSid", msg.TemplateID)
+		if len(msg.Variables) > 0 {
+			variables, err := json.Marshal(msg.Variables)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal content variables: %w", err)
+			}
+			form.Set("ContentVariables", string(variables))
+		}
+
+	case content.Interactive != nil:
+		return nil, channels.ErrInvalidMessageFormat().
+			WithDetail("reason", "Twilio sends buttons and lists as content templates; set template_id")
+
+	case content.Type == "location" && content.Location != nil:
+		location := content.Location
+		body := content.Text
+		if body == "" {
+			body = strings.TrimSpace(strings.Join([]string{location.Name, location.Address}, "\n"))
+		}
+		if body == "" {
+			body = fmt.Sprintf("%f, %f", location.Latitude, location.Longitude)
+		}
+		form.Set("Body", body)
+		form.Set("PersistentAction", fmt.Sprintf("geo:%f,%f|%s", location.Latitude, location.Longitude, location.Name))
+
+	case content.MediaURL != "":
+		form.Set("MediaUrl", content.MediaURL)
+		if caption := firstNonEmpty(content.Caption, content.Text); caption != "" {
+			form.Set("Body", caption)
+		}
+
+	case content.Type == "contacts":
+		return nil, channels.ErrFeatureNotSupported().
+			WithDetail("feature", "contacts").
+			WithDetail("provider", channels.WhatsAppProviderTwilio)
+
+	default:
+		if content.Text == "" {
+			return nil, channels.ErrInvalidMessageFormat().WithDetail("reason", "text is required")
+		}
+		form.Set("Body", content.Text)
+	}
+
+	return form, nil
+}
+
+// testConnection fetches the account with the configured credentials
+func (p *twilioProvider) testConnection(ctx context.Context) error {
+	resp, err := p.httpClient.Do(ctx, httpclient.Request{
+		Method: http.MethodGet,
+		URL:    p.apiURL + ".json",
+		Header: p.header(nil),
+	})
+	if err != nil {
+		return channels.ErrProviderAPIError().WithCause(err)
+	}
+
+	if !resp.IsSuccess() {
+		return channels.ErrProviderAuthFailed().
+			WithDetail("status", resp.StatusCode).
+			WithDetail("response", string(resp.Body))
+	}
+
+	return nil
+}
+
+// fetchMedia downloads an inbound media file; Twilio protects media URLs
+// with the account's credentials
+func (p *twilioProvider) fetchMedia(ctx context.Context, attachment channels.Attachment) (io.ReadCloser, string, error) {
+	if attachment.URL == "" {
+		return nil, "", fmt.Errorf("attachment has no url")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, attachment.URL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.SetBasicAuth(p.config.AccountSID, p.config.AuthToken)
+
+	resp, err := mediaDownloadClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download media: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("media download returned status %d", resp.StatusCode)
+	}
+
+	mimeType := attachment.MimeType
+	if mimeType == "" {
+		mimeType = resp.Header.Get("Content-Type")
+	}
+	return resp.Body, mimeType, nil
+}
+
+// header adds the account's basic auth to a request's headers
+func (p *twilioProvider) header(header http.Header) http.Header {
+	if header == nil {
+		header = http.Header{}
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte(p.config.AccountSID + ":" + p.config.AuthToken))
+	header.Set("Authorization", "Basic "+credentials)
+	return header
+}
+
+// parseAPIError parses Twilio API error responses
+func (p *twilioProvider) parseAPIError(resp *httpclient.Response) error {
+	var apiError struct {
+		Code     int    `json:"code"`
+		Message  string `json:"message"`
+		MoreInfo string `json:"more_info"`
+	}
+
+	if err := json.Unmarshal(resp.Body, &apiError); err != nil {
+		return resp.Err().WithDetail("response", string(resp.Body))
+	}
+
+	return resp.Err().
+		WithDetail("error_code", apiError.Code).
+		WithDetail("error_message", apiError.Message)
+}
+
+// ============================================================================
+// Webhooks
+// ============================================================================
+
+// verifyWebhook checks X-Twilio-Signature against the URL the webhook was
+// posted to. Without an auth token there is nothing to check against and it
+// passes.
+func (p *twilioProvider) verifyWebhook(payload []byte, headers map[string]string) error {
+	if p.config.AuthToken == "" {
+		return nil
+	}
+
+	signature := header(headers, TwilioSignatureHeader)
+	if signature == "" {
+		return channels.ErrInvalidWebhookSignature().
+			WithDetail("reason", "missing "+TwilioSignatureHeader+" header")
+	}
+	requestURL := header(headers, RequestURLHeader)
+	if requestURL == "" {
+		return channels.ErrInvalidWebhookSignature().WithDetail("reason", "unknown webhook url")
+	}
+
+	fields, err := decodeTwilioWebhook(payload)
+	if err != nil {
+		return channels.ErrInvalidWebhookSignature().WithDetail("reason", err.Error())
+	}
+
+	// The URL followed by every parameter, sorted by name
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	signed := requestURL
+	for _, key := range keys {
+		signed += key + fields[key]
+	}
+
+	mac := hmac.New(sha1.New, []byte(p.config.AuthToken))
+	mac.Write([]byte(signed))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return channels.ErrInvalidWebhookSignature().WithDetail("reason", "signature mismatch")
+	}
+
+	return nil
+}
+
+// parseWebhook extracts an incoming message; status callbacks return nil
+func (p *twilioProvider) parseWebhook(payload []byte) (*channels.IncomingMessage, error) {
+	fields, err := decodeTwilioWebhook(payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkAccount(fields); err != nil {
+		return nil, err
+	}
+	if fields["MessageStatus"] != "" {
+		return nil, nil // Status callback, see deliveryReceipts
+	}
+
+	messageType := twilioMessageType(fields)
+	if !supportedMessageTypes[messageType] {
+		return nil, nil // Reported by unsupportedEvents
+	}
+
+	sender := fields["WaId"]
+	if sender == "" {
+		sender = strings.TrimPrefix(strings.TrimPrefix(fields["From"], twilioAddressPrefix), "+")
+	}
+
+	incomingMsg := &channels.IncomingMessage{
+		MessageID:  kernel.MessageID(fields["MessageSid"]),
+		SenderID:   sender,
+		SenderName: fields["ProfileName"],
+		Content: channels.MessageContent{
+			Type:        messageType,
+			Text:        fields["Body"],
+			Attachments: twilioMedia(fields),
+			Location:    twilioLocation(fields),
+			Postback:    twilioPostback(fields),
+		},
+		Timestamp: time.Now().Unix(), // Twilio webhooks carry no timestamp
+		Metadata: map[string]any{
+			"whatsapp_message_id": fields["MessageSid"],
+			"twilio_message_sid":  fields["MessageSid"],
+		},
+		RawPayload: channels.DecodeRawPayload(payload),
+	}
+	if incomingMsg.Content.Text == "" && incomingMsg.Content.Postback != nil {
+		incomingMsg.Content.Text = incomingMsg.Content.Postback.Title
+	}
+	if replyTo := fields["OriginalRepliedMessageSid"]; replyTo != "" {
+		incomingMsg.Content.ReplyTo = &channels.ReplyTo{
+			MessageID: replyTo,
+			SenderID:  strings.TrimPrefix(fields["OriginalRepliedMessageSender"], twilioAddressPrefix),
+		}
+	}
+
+	return incomingMsg, nil
+}
+
+// unsupportedEvents reports message types the adapter does not map as
+// "message:<type>"
+func (p *twilioProvider) unsupportedEvents(payload []byte) []string {
+	fields, err := decodeTwilioWebhook(payload)
+	if err != nil || fields["MessageStatus"] != "" {
+		return nil
+	}
+
+	if messageType := twilioMessageType(fields); !supportedMessageTypes[messageType] {
+		return []string{"message:" + messageType}
+	}
+	return nil
+}
+
+// deliveryReceipts maps a status callback; queued and sending are skipped
+func (p *twilioProvider) deliveryReceipts(payload []byte) []channels.DeliveryReceipt {
+	fields, err := decodeTwilioWebhook(payload)
+	if err != nil || p.checkAccount(fields) != nil {
+		return nil
+	}
+
+	receipt := channels.DeliveryReceipt{
+		ProviderMessageID: fields["MessageSid"],
+		RecipientID:       strings.TrimPrefix(strings.TrimPrefix(fields["To"], twilioAddressPrefix), "+"),
+		At:                time.Now(),
+	}
+	switch fields["MessageStatus"] {
+	case "sent":
+		receipt.Status = channels.DeliveryStatusSent
+	case "delivered":
+		receipt.Status = channels.DeliveryStatusDelivered
+	case "read":
+		receipt.Status = channels.DeliveryStatusRead
+	case "failed", "undelivered":
+		receipt.Status = channels.DeliveryStatusFailed
+		receipt.Error = strings.TrimSpace(fields["ErrorCode"] + " " + fields["ErrorMessage"])
+	default:
+		return nil
+	}
+
+	return []channels.DeliveryReceipt{receipt}
+}
+
+// checkAccount rejects webhooks of another Twilio account
+func (p *twilioProvider) checkAccount(fields map[string]string) error {
+	if accountSID := fields["AccountSid"]; accountSID != "" && accountSID != p.config.AccountSID {
+		return channels.ErrInvalidWebhookSignature().
+			WithDetail("reason", "webhook is for another Twilio account").
+			WithDetail("account_sid", accountSID)
+	}
+	return nil
+}
+
+// twilioMessageType reads MessageType, or infers it from the fields
+func twilioMessageType(fields map[string]string) string {
+	if messageType := fields["MessageType"]; messageType != "" {
+		if messageType == "interactive" && fields["ButtonPayload"] != "" {
+			return "button"
+		}
+		return messageType
+	}
+
+	switch {
+	case fields["ButtonPayload"] != "":
+		return "button"
+	case fields["ListId"] != "":
+		return "interactive"
+	case fields["Latitude"] != "":
+		return "location"
+	case fields["NumMedia"] != "" && fields["NumMedia"] != "0":
+		return mediaTypeOf(fields["MediaContentType0"])
+	default:
+		return "text"
+	}
+}
+
+// twilioMedia maps the message's media; FetchMedia downloads them
+func twilioMedia(fields map[string]string) []channels.Attachment {
+	count, _ := strconv.Atoi(fields["NumMedia"])
+
+	var attachments []channels.Attachment
+	for i := range count {
+		mediaURL := fields[fmt.Sprintf("MediaUrl%d", i)]
+		if mediaURL == "" {
+			continue
+		}
+		mimeType := fields[fmt.Sprintf("MediaContentType%d", i)]
+		attachments = append(attachments, channels.Attachment{
+			Type:     mediaTypeOf(mimeType),
+			URL:      mediaURL,
+			MimeType: mimeType,
+		})
+	}
+	return attachments
+}
+
+// twilioLocation maps a shared location pin
+func twilioLocation(fields map[string]string) *channels.Location {
+	latitude, err := strconv.ParseFloat(fields["Latitude"], 64)
+	if err != nil {
+		return nil
+	}
+	longitude, err := strconv.ParseFloat(fields["Longitude"], 64)
+	if err != nil {
+		return nil
+	}
+	return &channels.Location{
+		Latitude:  latitude,
+		Longitude: longitude,
+		Name:      fields["Label"],
+		Address:   fields["Address"],
+	}
+}
+
+// twilioPostback normalizes a tapped button or list row of a content
+// template
+func twilioPostback(fields map[string]string) *channels.Postback {
+	switch {
+	case fields["ButtonPayload"] != "":
+		return &channels.Postback{
+			Type:  "button",
+			ID:    fields["ButtonPayload"],
+			Title: fields["ButtonText"],
+		}
+	case fields["ListId"] != "":
+		return &channels.Postback{
+			Type:        "list",
+			ID:          fields["ListId"],
+			Title:       fields["ListTitle"],
+			Description: fields["ListDescription"],
+		}
+	}
+	return nil
+}
+
+// mediaTypeOf maps a mime type to the content types used across channels
+func mediaTypeOf(mimeType string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return "image"
+	case strings.HasPrefix(mimeType, "video/"):
+		return "video"
+	case strings.HasPrefix(mimeType, "audio/"):
+		return "audio"
+	default:
+		return "document"
+	}
+}
+
+// formToJSON turns a form-encoded Twilio webhook into a JSON object of its
+// fields. A JSON payload (a stored webhook) is returned as is.
+func formToJSON(body []byte) ([]byte, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return body, nil
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Twilio webhook: %w", err)
+	}
+
+	fields := make(map[string]string, len(values))
+	for key := range values {
+		fields[key] = values.Get(key)
+	}
+	return json.Marshal(fields)
+}
+
+// decodeTwilioWebhook reads the fields of a webhook converted by formToJSON
+func decodeTwilioWebhook(payload []byte) (map[string]string, error) {
+	var fields map[string]string
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse Twilio webhook: %w", err)
+	}
+	return fields, nil
+}
+
+// twilioAddress addresses a phone number on WhatsApp
+func twilioAddress(phone string) string {
+	phone = strings.TrimPrefix(strings.TrimSpace(phone), twilioAddressPrefix)
+	if !strings.HasPrefix(phone, "+") {
+		phone = "+" + phone
+	}
+	return twilioAddressPrefix + phone
+}
+
+// header reads a header by its canonical or lowercase name
+func header(headers map[string]string, name string) string {
+	if value := headers[name]; value != "" {
+		return value
+	}
+	return headers[strings.ToLower(name)]
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -5,22 +5,57 @@ import (
 	"log"
 
 	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/conversation"
 	"github.com/Abraxas-365/relay/engine/triggerhandler"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 // ChannelHandler handles generic channel operations
 type ChannelHandler struct {
 	triggerHandler *triggerhandler.TriggerHandler
+	messageRepo    conversation.MessageRepository
+	listeners      []channels.InboundListener
+	interceptors   []channels.InboundInterceptor
+	ingester       channels.AttachmentIngester
+	filter         channels.InboundFilter
 }
 
+var _ channels.InboundDispatcher = (*ChannelHandler)(nil)
+
 // NewChannelHandler creates a new channel handler
-func NewChannelHandler(triggerHandler *triggerhandler.TriggerHandler) *ChannelHandler {
+func NewChannelHandler(
+	triggerHandler *triggerhandler.TriggerHandler,
+	messageRepo conversation.MessageRepository,
+) *ChannelHandler {
 	return &ChannelHandler{
 		triggerHandler: triggerHandler,
+		messageRepo:    messageRepo,
 	}
 }
 
+// AddInboundListener registers listeners notified of every incoming message
+func (h *ChannelHandler) AddInboundListener(listeners ...channels.InboundListener) {
+	h.listeners = append(h.listeners, listeners...)
+}
+
+// AddInboundInterceptor registers interceptors that may consume a message
+// before workflows are triggered
+func (h *ChannelHandler) AddInboundInterceptor(interceptors ...channels.InboundInterceptor) {
+	h.interceptors = append(h.interceptors, interceptors...)
+}
+
+// SetAttachmentIngester stores inbound media before workflows see it
+func (h *ChannelHandler) SetAttachmentIngester(ingester channels.AttachmentIngester) {
+	h.ingester = ingester
+}
+
+// SetInboundFilter screens every message for spam and abuse before it is
+// recorded
+func (h *ChannelHandler) SetInboundFilter(filter channels.InboundFilter) {
+	h.filter = filter
+}
+
 // ProcessIncomingMessage processes incoming messages from ANY channel
 func (h *ChannelHandler) ProcessIncomingMessage(c *fiber.Ctx) error {
 	// Get message from context (set by channel-specific handler)
@@ -40,33 +75,63 @@ func (h *ChannelHandler) ProcessIncomingMessage(c *fiber.Ctx) error {
 	log.Printf("📨 Processing incoming message from %s via channel %s",
 		incomingMsg.SenderID, channel.Name)
 
-	// Prepare trigger data
-	triggerData := map[string]any{
-		"text":            incomingMsg.Content.Text,
-		"message_id":      incomingMsg.MessageID.String(),
-		"channel_id":      channel.ID.String(),
-		"sender_id":       incomingMsg.SenderID,
-		"message_type":    incomingMsg.Content.Type,
-		"conversation_id": incomingMsg.SenderID, // For AI memory
+	channels.Normalize(incomingMsg)
+
+	// Media is downloaded and scanned before anything sees the message,
+	// which can outlast the provider's webhook timeout
+	if h.ingester != nil && hasMedia(incomingMsg) {
+		go func() {
+			ctx := context.Background()
+			h.ingester.IngestAttachments(ctx, channel, incomingMsg)
+			h.dispatch(ctx, channel, incomingMsg)
+		}()
+	} else {
+		h.dispatch(c.Context(), channel, incomingMsg)
+	}
+
+	// Respond immediately
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "received",
+	})
+}
+
+// DispatchInbound runs an already parsed message through the inbound flow
+// synchronously. Reprocessed webhook events arrive here.
+func (h *ChannelHandler) DispatchInbound(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage) {
+	channels.Normalize(msg)
+	if h.ingester != nil && hasMedia(msg) {
+		h.ingester.IngestAttachments(ctx, channel, msg)
+	}
+	h.dispatch(ctx, channel, msg)
+}
+
+// dispatch records the message, notifies listeners and triggers workflows
+// unless the filter drops it or an interceptor consumes it
+func (h *ChannelHandler) dispatch(ctx context.Context, channel *channels.Channel, incomingMsg *channels.IncomingMessage) {
+	if h.filter != nil && h.filter.FilterInbound(ctx, channel, incomingMsg) == channels.FilterDrop {
+		log.Printf("🚫 Message from %s dropped by the inbound filter", incomingMsg.SenderID)
+		return
 	}
 
-	// Add attachments
-	if len(incomingMsg.Content.Attachments) > 0 {
-		attachments := make([]map[string]any, len(incomingMsg.Content.Attachments))
-		for i, att := range incomingMsg.Content.Attachments {
-			attachments[i] = map[string]any{
-				"type":      att.Type,
-				"url":       att.URL,
-				"mime_type": att.MimeType,
-				"filename":  att.Filename,
-			}
+	// Record in the conversation transcript
+	h.recordInbound(ctx, channel, incomingMsg)
+
+	for _, listener := range h.listeners {
+		listener.OnInboundMessage(ctx, channel, incomingMsg)
+	}
+
+	// A consumed message (e.g. a survey answer) resumes its own run instead
+	for _, interceptor := range h.interceptors {
+		if interceptor.InterceptInbound(ctx, channel, incomingMsg) {
+			log.Printf("📥 Message from %s consumed before triggering workflows", incomingMsg.SenderID)
+			return
 		}
-		triggerData["attachments"] = attachments
 	}
 
-	// Add metadata
-	if incomingMsg.Metadata != nil {
-		triggerData["metadata"] = incomingMsg.Metadata
+	// Prepare trigger data
+	triggerData := channels.TriggerPayload(channel, incomingMsg)
+	if incomingMsg.Content.ReplyTo != nil {
+		triggerData["reply_to"] = h.resolveReplyTo(ctx, channel, incomingMsg.Content.ReplyTo)
 	}
 
 	// ✅ FIX: Create independent context for goroutine
@@ -83,14 +148,61 @@ func (h *ChannelHandler) ProcessIncomingMessage(c *fiber.Ctx) error {
 			workflowCtx, // ← FIX: Use background context
 			channel.TenantID,
 			channel.ID,
+			channel.Environment,
 			triggerData,
 		); err != nil {
 			log.Printf("❌ Failed to trigger workflows: %v", err)
 		}
 	}()
+}
 
-	// Respond immediately
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": "received",
-	})
+// recordInbound stores the incoming message in the conversation transcript.
+// Persistence failures are logged and never block workflow triggering.
+func (h *ChannelHandler) recordInbound(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage) {
+	if h.messageRepo == nil {
+		return
+	}
+
+	record := conversation.NewInboundMessage(uuid.NewString(), channel.TenantID, channel.ID, *msg)
+	if err := h.messageRepo.Save(ctx, *record); err != nil {
+		log.Printf("⚠️ Failed to record inbound message from %s: %v", msg.SenderID, err)
+	}
+}
+
+// resolveReplyTo describes the quoted message, with its content when the
+// transcript has it. Messages sent before recording began, or by adapters
+// that do not report provider IDs, resolve to found = false.
+func (h *ChannelHandler) resolveReplyTo(ctx context.Context, channel *channels.Channel, replyTo *channels.ReplyTo) map[string]any {
+	reply := map[string]any{
+		"message_id": replyTo.MessageID,
+		"sender_id":  replyTo.SenderID,
+		"found":      false,
+	}
+	if h.messageRepo == nil {
+		return reply
+	}
+
+	quoted, err := h.messageRepo.FindByProviderMessageID(ctx, channel.TenantID, channel.ID, replyTo.MessageID)
+	if err != nil {
+		return reply
+	}
+
+	reply["found"] = true
+	reply["text"] = quoted.Content.Text
+	reply["message_type"] = quoted.Content.Type
+	reply["direction"] = string(quoted.Direction)
+	reply["origin"] = string(quoted.Origin)
+	reply["sender_id"] = quoted.SenderID
+	reply["workflow_id"] = quoted.WorkflowID
+	reply["node_id"] = quoted.NodeID
+	reply["sent_at"] = quoted.CreatedAt
+	if urls := quoted.AttachmentURLs(); len(urls) > 0 {
+		reply["attachment_urls"] = urls
+	}
+	return reply
+}
+
+// hasMedia reports whether the message carries files to ingest
+func hasMedia(msg *channels.IncomingMessage) bool {
+	return msg.Content.MediaURL != "" || len(msg.Content.Attachments) > 0
 }
@@ -0,0 +1,316 @@
+package channelapi
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/channelsrv"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ChannelManagementHandler handles channel CRUD for authenticated tenants
+type ChannelManagementHandler struct {
+	channelService *channelsrv.ChannelService
+}
+
+// NewChannelManagementHandler creates a new channel management handler
+func NewChannelManagementHandler(channelService *channelsrv.ChannelService) *ChannelManagementHandler {
+	return &ChannelManagementHandler{
+		channelService: channelService,
+	}
+}
+
+// ListChannels lists the tenant's channels
+// GET /api/channels?page=1&page_size=20&type=WHATSAPP&is_active=true&provider=meta&search=
+func (h *ChannelManagementHandler) ListChannels(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	req := channels.ListChannelsRequest{
+		PaginationOptions: storex.PaginationOptions{
+			Page:     c.QueryInt("page", 1),
+			PageSize: c.QueryInt("page_size", 20),
+		},
+		TenantID: tenantID,
+		Search:   c.Query("search"),
+	}
+
+	if channelType := c.Query("type"); channelType != "" {
+		t := channels.ChannelType(channelType)
+		req.Type = &t
+	}
+	if isActive := c.Query("is_active"); isActive != "" {
+		active := c.QueryBool("is_active")
+		req.IsActive = &active
+	}
+	if provider := c.Query("provider"); provider != "" {
+		req.Provider = &provider
+	}
+	if environment := c.Query("environment"); environment != "" {
+		env := kernel.Environment(environment)
+		req.Environment = &env
+	}
+
+	result, err := h.channelService.ListChannels(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	for i := range result.Data {
+		result.Data[i] = result.Data[i].Redacted()
+	}
+	return c.JSON(result)
+}
+
+// CreateChannel creates a channel for the tenant
+// POST /api/channels
+func (h *ChannelManagementHandler) CreateChannel(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	var req channels.CreateChannelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return channels.ErrInvalidChannelConfig().WithDetail("reason", err.Error())
+	}
+
+	// Tenant always comes from the token, never from the body
+	req.TenantID = tenantID
+
+	channel, err := h.channelService.CreateChannel(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(channel.Redacted())
+}
+
+// ValidateConfig validates a channel config without persisting it
+// POST /api/channels/validate
+func (h *ChannelManagementHandler) ValidateConfig(c *fiber.Ctx) error {
+	if _, err := tenantFromAuth(c); err != nil {
+		return err
+	}
+
+	var req channels.ValidateChannelConfigRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.JSON(channels.ValidateChannelConfigResponse{
+			IsValid: false,
+			Errors:  []string{err.Error()},
+		})
+	}
+
+	return c.JSON(h.channelService.ValidateChannelConfig(req))
+}
+
+// GetChannel returns a channel with its features
+// GET /api/channels/:id
+func (h *ChannelManagementHandler) GetChannel(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.channelService.GetChannelByID(c.Context(), kernel.NewChannelID(c.Params("id")), tenantID)
+	if err != nil {
+		return err
+	}
+
+	response.Channel = response.Channel.Redacted()
+	return c.JSON(response)
+}
+
+// UpdateChannel updates a channel
+// PUT /api/channels/:id
+func (h *ChannelManagementHandler) UpdateChannel(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	var req channels.UpdateChannelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return channels.ErrInvalidChannelConfig().WithDetail("reason", err.Error())
+	}
+
+	channel, err := h.channelService.UpdateChannel(c.Context(), kernel.NewChannelID(c.Params("id")), req, tenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(channel.Redacted())
+}
+
+// DeleteChannel deletes a channel
+// DELETE /api/channels/:id
+func (h *ChannelManagementHandler) DeleteChannel(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.channelService.DeleteChannel(c.Context(), kernel.NewChannelID(c.Params("id")), tenantID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ActivateChannel activates a channel
+// POST /api/channels/:id/activate
+func (h *ChannelManagementHandler) ActivateChannel(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.channelService.ActivateChannel(c.Context(), kernel.NewChannelID(c.Params("id")), tenantID); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"status": "activated"})
+}
+
+// DeactivateChannel deactivates a channel
+// POST /api/channels/:id/deactivate
+func (h *ChannelManagementHandler) DeactivateChannel(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.channelService.DeactivateChannel(c.Context(), kernel.NewChannelID(c.Params("id")), tenantID); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"status": "deactivated"})
+}
+
+// TestChannel tests the provider connection using the stored config
+// POST /api/channels/:id/test
+func (h *ChannelManagementHandler) TestChannel(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.channelService.TestChannel(c.Context(), kernel.NewChannelID(c.Params("id")), tenantID)
+	if response != nil {
+		// A failed connection test is still a valid test result
+		return c.JSON(response)
+	}
+
+	return err
+}
+
+// GetFeatures returns the features supported by the channel
+// GET /api/channels/:id/features
+func (h *ChannelManagementHandler) GetFeatures(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.channelService.GetChannelFeatures(c.Context(), kernel.NewChannelID(c.Params("id")), tenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response)
+}
+
+// GetWebhookURL returns the webhook URL to configure in the provider
+// GET /api/channels/:id/webhook
+func (h *ChannelManagementHandler) GetWebhookURL(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.channelService.GetWebhookURL(c.Context(), kernel.NewChannelID(c.Params("id")), tenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response)
+}
+
+// RegenerateWebhookURL regenerates the channel webhook URL
+// POST /api/channels/:id/webhook
+func (h *ChannelManagementHandler) RegenerateWebhookURL(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.channelService.RegenerateWebhookURL(c.Context(), kernel.NewChannelID(c.Params("id")), tenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response)
+}
+
+// SendMessage sends an operator message to a contact through the channel
+// POST /api/channels/:id/messages
+func (h *ChannelManagementHandler) SendMessage(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req channels.SendMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return channels.ErrInvalidMessageFormat().WithDetail("reason", err.Error())
+	}
+
+	// Channel always comes from the path
+	req.ChannelID = kernel.NewChannelID(c.Params("id"))
+
+	response, err := h.channelService.SendManualMessage(c.Context(), authContext.TenantID, authContext.UserID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(response)
+}
+
+// RenderMessage previews how a draft message would be delivered on the
+// channel, without sending it
+// POST /api/channels/:id/render
+func (h *ChannelManagementHandler) RenderMessage(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	var msg channels.OutgoingMessage
+	if err := c.BodyParser(&msg); err != nil {
+		return channels.ErrInvalidMessageFormat().WithDetail("reason", err.Error())
+	}
+
+	response, err := h.channelService.RenderMessage(c.Context(), kernel.NewChannelID(c.Params("id")), tenantID, msg)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// tenantFromAuth extracts the tenant from the authenticated request
+func tenantFromAuth(c *fiber.Ctx) (kernel.TenantID, error) {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return "", iam.ErrUnauthorized()
+	}
+	return authContext.TenantID, nil
+}
@@ -0,0 +1,88 @@
+package channelapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ChannelRoutes handles channel management route setup
+type ChannelRoutes struct {
+	handler        *ChannelManagementHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewChannelRoutes creates a new channel routes instance
+func NewChannelRoutes(handler *ChannelManagementHandler, authMiddleware *auth.AuthMiddleware) *ChannelRoutes {
+	return &ChannelRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers channel management routes on an authenticated router.
+// Changing a channel, its credentials or its webhook, and sending through it,
+// requires an admin.
+func (r *ChannelRoutes) RegisterRoutes(router fiber.Router) {
+	channels := router.Group("/channels")
+	admin := r.authMiddleware.RequireAdmin()
+
+	channels.Get("/", r.handler.ListChannels)
+	channels.Post("/", admin, r.handler.CreateChannel)
+	channels.Post("/validate", r.handler.ValidateConfig)
+
+	channels.Get("/:id", r.handler.GetChannel)
+	channels.Put("/:id", admin, r.handler.UpdateChannel)
+	channels.Delete("/:id", admin, r.handler.DeleteChannel)
+
+	channels.Post("/:id/activate", admin, r.handler.ActivateChannel)
+	channels.Post("/:id/deactivate", admin, r.handler.DeactivateChannel)
+	channels.Post("/:id/test", r.handler.TestChannel)
+	channels.Get("/:id/features", r.handler.GetFeatures)
+	channels.Get("/:id/webhook", r.handler.GetWebhookURL)
+	channels.Post("/:id/webhook", admin, r.handler.RegenerateWebhookURL)
+	channels.Post("/:id/messages", admin, r.handler.SendMessage)
+	channels.Post("/:id/render", r.handler.RenderMessage)
+}
+
+// SimulationRoutes handles test console route setup
+type SimulationRoutes struct {
+	handler *SimulationHandler
+}
+
+// NewSimulationRoutes creates a new simulation routes instance
+func NewSimulationRoutes(handler *SimulationHandler) *SimulationRoutes {
+	return &SimulationRoutes{
+		handler: handler,
+	}
+}
+
+// RegisterRoutes registers the test console routes on an authenticated router
+func (r *SimulationRoutes) RegisterRoutes(router fiber.Router) {
+	router.Post("/simulate", r.handler.Simulate)
+}
+
+// WebhookEventRoutes handles stored webhook event route setup
+type WebhookEventRoutes struct {
+	handler        *WebhookEventHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewWebhookEventRoutes creates a new webhook event routes instance
+func NewWebhookEventRoutes(handler *WebhookEventHandler, authMiddleware *auth.AuthMiddleware) *WebhookEventRoutes {
+	return &WebhookEventRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers webhook event routes on an authenticated router.
+// Reprocessing can run workflows, so it requires an admin.
+func (r *WebhookEventRoutes) RegisterRoutes(router fiber.Router) {
+	events := router.Group("/webhook-events")
+
+	events.Get("/", r.handler.List)
+	events.Get("/metrics", r.handler.Metrics)
+	events.Post("/reprocess", r.authMiddleware.RequireAdmin(), r.handler.ReprocessPending)
+	events.Get("/:id", r.handler.Get)
+	events.Post("/:id/reprocess", r.authMiddleware.RequireAdmin(), r.handler.Reprocess)
+}
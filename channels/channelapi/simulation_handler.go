@@ -0,0 +1,124 @@
+package channelapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/engine/triggerhandler"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// simulationTimeout bounds a synchronous simulation run
+const simulationTimeout = 60 * time.Second
+
+// SimulateRequest synthetic inbound message for the test console
+type SimulateRequest struct {
+	ChannelID  kernel.ChannelID         `json:"channel_id" validate:"required"`
+	WorkflowID *kernel.WorkflowID       `json:"workflow_id,omitempty"`
+	SenderID   string                   `json:"sender_id,omitempty"`
+	Text       string                   `json:"text,omitempty"`
+	Content    *channels.MessageContent `json:"content,omitempty"`
+	Metadata   map[string]any           `json:"metadata,omitempty"`
+}
+
+// SimulateResponse result of a simulated inbound message
+type SimulateResponse struct {
+	ChannelID   kernel.ChannelID                   `json:"channel_id"`
+	SenderID    string                             `json:"sender_id"`
+	TriggerData map[string]any                     `json:"trigger_data"`
+	Executions  []engine.WorkflowExecutionResponse `json:"executions"`
+	Responses   []channels.CapturedMessage         `json:"responses"`
+	DurationMs  int64                              `json:"duration_ms"`
+}
+
+// SimulationHandler runs inbound messages through the real pipeline without
+// reaching any provider
+type SimulationHandler struct {
+	channelRepo    channels.ChannelRepository
+	triggerHandler *triggerhandler.TriggerHandler
+}
+
+// NewSimulationHandler creates a new simulation handler
+func NewSimulationHandler(
+	channelRepo channels.ChannelRepository,
+	triggerHandler *triggerhandler.TriggerHandler,
+) *SimulationHandler {
+	return &SimulationHandler{
+		channelRepo:    channelRepo,
+		triggerHandler: triggerHandler,
+	}
+}
+
+// Simulate injects a synthetic inbound message on a TEST_HTTP channel
+// POST /api/simulate
+func (h *SimulationHandler) Simulate(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	var req SimulateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return channels.ErrInvalidMessageFormat().WithDetail("reason", err.Error())
+	}
+	if req.ChannelID.IsEmpty() {
+		return channels.ErrChannelNotFound().WithDetail("reason", "channel_id is required")
+	}
+
+	channel, err := h.channelRepo.FindByID(c.Context(), req.ChannelID, tenantID)
+	if err != nil {
+		return channels.ErrChannelNotFound().WithDetail("channel_id", req.ChannelID.String())
+	}
+
+	// Only TEST_HTTP channels can be simulated
+	if channel.Type != channels.ChannelTypeTestHTTP {
+		return channels.ErrInvalidChannelType().
+			WithDetail("type", string(channel.Type)).
+			WithDetail("reason", "simulation requires a TEST_HTTP channel")
+	}
+
+	content := channels.MessageContent{Type: "text", Text: req.Text}
+	if req.Content != nil {
+		content = *req.Content
+	}
+
+	senderID := req.SenderID
+	if senderID == "" {
+		senderID = "simulator"
+	}
+
+	incomingMsg := &channels.IncomingMessage{
+		MessageID: kernel.NewMessageID(uuid.NewString()),
+		ChannelID: channel.ID,
+		SenderID:  senderID,
+		Content:   content,
+		Timestamp: time.Now().Unix(),
+		Metadata:  req.Metadata,
+	}
+
+	channels.Normalize(incomingMsg)
+	triggerData := channels.TriggerPayload(channel, incomingMsg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), simulationTimeout)
+	defer cancel()
+	ctx, outbox := channels.WithOutbox(ctx)
+
+	startTime := time.Now()
+	executions, err := h.triggerHandler.SimulateChannelTrigger(ctx, tenantID, channel.ID, channel.Environment, req.WorkflowID, triggerData)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(SimulateResponse{
+		ChannelID:   channel.ID,
+		SenderID:    senderID,
+		TriggerData: triggerData,
+		Executions:  executions,
+		Responses:   outbox.Messages(),
+		DurationMs:  time.Since(startTime).Milliseconds(),
+	})
+}
@@ -0,0 +1,142 @@
+package channelapi
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/channelsrv"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultWebhookEventPageSize = 50
+	maxWebhookEventPageSize     = 200
+)
+
+// WebhookEventHandler exposes the webhook events no adapter handled
+type WebhookEventHandler struct {
+	service *channelsrv.WebhookEventService
+}
+
+// NewWebhookEventHandler creates a new webhook event handler
+func NewWebhookEventHandler(service *channelsrv.WebhookEventService) *WebhookEventHandler {
+	return &WebhookEventHandler{
+		service: service,
+	}
+}
+
+// List returns the tenant's stored webhook events, oldest first
+// GET /api/webhook-events?status=PENDING&provider=WHATSAPP&event_type=message:order&channel_id=&page=1&page_size=50
+func (h *WebhookEventHandler) List(c *fiber.Ctx) error {
+	req, err := webhookEventQuery(c)
+	if err != nil {
+		return err
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", defaultWebhookEventPageSize)
+	if pageSize < 1 {
+		pageSize = defaultWebhookEventPageSize
+	}
+	if pageSize > maxWebhookEventPageSize {
+		pageSize = maxWebhookEventPageSize
+	}
+	req.PaginationOptions = storex.PaginationOptions{Page: page, PageSize: pageSize}
+
+	if status := c.Query("status"); status != "" {
+		s := channels.WebhookEventStatus(status)
+		if !s.IsValid() {
+			return channels.ErrInvalidWebhookEventQuery().WithDetail("status", status)
+		}
+		req.Status = &s
+	}
+
+	events, err := h.service.List(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(events)
+}
+
+// Get returns a stored webhook event with its payload
+// GET /api/webhook-events/:id
+func (h *WebhookEventHandler) Get(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	event, err := h.service.Get(c.Context(), c.Params("id"), tenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(event)
+}
+
+// Reprocess runs a stored event through its channel's current adapter and
+// returns it with the outcome
+// POST /api/webhook-events/:id/reprocess
+func (h *WebhookEventHandler) Reprocess(c *fiber.Ctx) error {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return err
+	}
+
+	event, err := h.service.Reprocess(c.Context(), c.Params("id"), tenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(event)
+}
+
+// ReprocessPending backfills the pending events matching the filters, for
+// use after an adapter learns a new event type
+// POST /api/webhook-events/reprocess?provider=WHATSAPP&event_type=message:order&channel_id=
+func (h *WebhookEventHandler) ReprocessPending(c *fiber.Ctx) error {
+	req, err := webhookEventQuery(c)
+	if err != nil {
+		return err
+	}
+
+	summary, err := h.service.ReprocessPending(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(summary)
+}
+
+// Metrics returns the unsupported event counters by provider and event type
+// GET /api/webhook-events/metrics
+func (h *WebhookEventHandler) Metrics(c *fiber.Ctx) error {
+	return c.JSON(h.service.Metrics())
+}
+
+// webhookEventQuery reads the tenant and the channel, provider and event type filters
+func webhookEventQuery(c *fiber.Ctx) (channels.ListWebhookEventsRequest, error) {
+	tenantID, err := tenantFromAuth(c)
+	if err != nil {
+		return channels.ListWebhookEventsRequest{}, err
+	}
+
+	req := channels.ListWebhookEventsRequest{TenantID: tenantID}
+	if channelID := c.Query("channel_id"); channelID != "" {
+		id := kernel.NewChannelID(channelID)
+		req.ChannelID = &id
+	}
+	if provider := c.Query("provider"); provider != "" {
+		p := channels.ChannelType(provider)
+		req.Provider = &p
+	}
+	if eventType := c.Query("event_type"); eventType != "" {
+		req.EventType = &eventType
+	}
+
+	return req, nil
+}
@@ -4,13 +4,22 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/Abraxas-365/craftable/eventx"
 	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/channeladapters/apple"
 	instagram "github.com/Abraxas-365/relay/channels/channeladapters/instagram"
+	"github.com/Abraxas-365/relay/channels/channeladapters/rcs"
+	"github.com/Abraxas-365/relay/channels/channeladapters/testhttp"
 	whatsapp "github.com/Abraxas-365/relay/channels/channeladapters/whatssapp"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/featureflag"
+	"github.com/Abraxas-365/relay/pkg/circuitbreaker"
 	"github.com/Abraxas-365/relay/pkg/kernel"
-	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
 )
 
 // DefaultChannelManager implementación del ChannelManager
@@ -26,23 +35,51 @@ type DefaultChannelManager struct {
 	// Channel repository para persistencia
 	channelRepo channels.ChannelRepository
 
-	// ✅ Redis client para crear adapters de WhatsApp
-	redisClient *redis.Client
+	// Repositorio de mensajes para el historial de conversaciones (opcional)
+	messageRepo conversation.MessageRepository
+
+	// Feature flags por tenant (opcional): adapters habilitados y buffering
+	flags featureflag.Checker
+
+	// Circuitos por canal (opcional): una caída del proveedor corta los envíos
+	// en vez de acumular timeouts
+	breakers *circuitbreaker.Registry
+
+	// Event bus (opcional): se publica response.sent por cada envío exitoso
+	events eventx.EventBus
 }
 
+var (
+	_ featureflag.ChangeListener = (*DefaultChannelManager)(nil)
+	_ channels.MessageStreamer   = (*DefaultChannelManager)(nil)
+)
+
+// streamEditInterval limita las ediciones de un mensaje en streaming; los
+// proveedores limitan la frecuencia de edición
+const streamEditInterval = 500 * time.Millisecond
+
 // NewDefaultChannelManager crea una nueva instancia
 func NewDefaultChannelManager(
 	channelRepo channels.ChannelRepository,
-	redisClient *redis.Client,
+	messageRepo conversation.MessageRepository,
+	flags featureflag.Checker,
+	breakers *circuitbreaker.Registry,
 ) *DefaultChannelManager {
 	return &DefaultChannelManager{
 		adapters:    make(map[kernel.ChannelID]channels.ChannelAdapter),
 		channels:    make(map[kernel.ChannelID]*channels.Channel),
 		channelRepo: channelRepo,
-		redisClient: redisClient,
+		messageRepo: messageRepo,
+		flags:       flags,
+		breakers:    breakers,
 	}
 }
 
+// SetEventBus publica response.sent en el bus por cada mensaje entregado
+func (cm *DefaultChannelManager) SetEventBus(bus eventx.EventBus) {
+	cm.events = bus
+}
+
 // RegisterChannel registra un canal en el manager y crea su adapter
 func (cm *DefaultChannelManager) RegisterChannel(ctx context.Context, channel channels.Channel) error {
 	cm.mu.Lock()
@@ -54,7 +91,7 @@ func (cm *DefaultChannelManager) RegisterChannel(ctx context.Context, channel ch
 	}
 
 	// ✅ Crear adapter específico para este canal
-	adapter, err := cm.createAdapterForChannel(channel)
+	adapter, err := cm.createAdapterForChannel(ctx, channel)
 	if err != nil {
 		log.Printf("❌ Failed to create adapter for channel %s: %v", channel.ID.String(), err)
 		return fmt.Errorf("failed to create adapter: %w", err)
@@ -70,7 +107,14 @@ func (cm *DefaultChannelManager) RegisterChannel(ctx context.Context, channel ch
 }
 
 // ✅ createAdapterForChannel crea un adapter con la config específica del canal
-func (cm *DefaultChannelManager) createAdapterForChannel(channel channels.Channel) (channels.ChannelAdapter, error) {
+func (cm *DefaultChannelManager) createAdapterForChannel(ctx context.Context, channel channels.Channel) (channels.ChannelAdapter, error) {
+	// El adapter puede estar deshabilitado para el tenant
+	if !cm.featureEnabled(ctx, channel.TenantID, featureflag.ChannelFlag(string(channel.Type))) {
+		return nil, featureflag.ErrFeatureDisabled().
+			WithDetail("flag", string(featureflag.ChannelFlag(string(channel.Type)))).
+			WithDetail("channel_id", channel.ID.String())
+	}
+
 	switch channel.Type {
 	case channels.ChannelTypeWhatsApp:
 		// Obtener config tipada
@@ -88,6 +132,9 @@ func (cm *DefaultChannelManager) createAdapterForChannel(channel channels.Channe
 		if err := whatsappConfig.Validate(); err != nil {
 			return nil, fmt.Errorf("invalid WhatsApp config: %w", err)
 		}
+		if !cm.featureEnabled(ctx, channel.TenantID, featureflag.FlagMessageBuffering) {
+			whatsappConfig.BufferEnabled = false
+		}
 
 		// Log config details
 		log.Printf("🔧 Creating WhatsApp adapter for channel: %s", channel.ID)
@@ -99,7 +146,7 @@ func (cm *DefaultChannelManager) createAdapterForChannel(channel channels.Channe
 			len(whatsappConfig.AccessToken))
 
 		// Crear adapter
-		adapter := whatsapp.NewWhatsAppAdapter(whatsappConfig, cm.redisClient)
+		adapter := whatsapp.NewWhatsAppAdapter(whatsappConfig)
 		if adapter == nil {
 			return nil, fmt.Errorf("failed to create WhatsApp adapter")
 		}
@@ -122,6 +169,9 @@ func (cm *DefaultChannelManager) createAdapterForChannel(channel channels.Channe
 		if err := instagramConfig.Validate(); err != nil {
 			return nil, fmt.Errorf("invalid Instagram config: %w", err)
 		}
+		if !cm.featureEnabled(ctx, channel.TenantID, featureflag.FlagMessageBuffering) {
+			instagramConfig.BufferEnabled = false
+		}
 
 		// Log config details
 		log.Printf("🔧 Creating Instagram adapter for channel: %s", channel.ID)
@@ -132,13 +182,68 @@ func (cm *DefaultChannelManager) createAdapterForChannel(channel channels.Channe
 			len(instagramConfig.PageToken))
 
 		// Crear adapter with Redis client for buffering
-		adapter := instagram.NewInstagramAdapter(instagramConfig, cm.redisClient)
+		adapter := instagram.NewInstagramAdapter(instagramConfig)
 		if adapter == nil {
 			return nil, fmt.Errorf("failed to create Instagram adapter")
 		}
 
 		return adapter, nil
 
+	case channels.ChannelTypeTestHTTP:
+		config, err := channel.GetConfigStruct()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config struct: %w", err)
+		}
+
+		testConfig, ok := config.(channels.TestHTTPConfig)
+		if !ok {
+			return nil, fmt.Errorf("invalid config type for TEST_HTTP channel")
+		}
+
+		return testhttp.NewTestHTTPAdapter(testConfig), nil
+
+	case channels.ChannelTypeRCS:
+		config, err := channel.GetConfigStruct()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config struct: %w", err)
+		}
+
+		rcsConfig, ok := config.(channels.RCSConfig)
+		if !ok {
+			return nil, fmt.Errorf("invalid RCS config type")
+		}
+
+		// Validar config
+		if err := rcsConfig.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid RCS config: %w", err)
+		}
+
+		log.Printf("🔧 Creating RCS adapter for channel: %s", channel.ID)
+		log.Printf("   🤖 Agent ID: %s", rcsConfig.AgentID)
+
+		return rcs.NewRCSAdapter(rcsConfig), nil
+
+	case channels.ChannelTypeApple:
+		config, err := channel.GetConfigStruct()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config struct: %w", err)
+		}
+
+		appleConfig, ok := config.(channels.AppleConfig)
+		if !ok {
+			return nil, fmt.Errorf("invalid Apple Messages config type")
+		}
+
+		// Validar config
+		if err := appleConfig.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid Apple Messages config: %w", err)
+		}
+
+		log.Printf("🔧 Creating Apple Messages adapter for channel: %s", channel.ID)
+		log.Printf("   🍎 Business ID: %s", appleConfig.BusinessID)
+
+		return apple.NewAppleAdapter(appleConfig), nil
+
 	// ✅ Agregar más tipos de canales aquí
 	// case channels.ChannelTypeTelegram:
 	//     ...
@@ -157,6 +262,229 @@ func (cm *DefaultChannelManager) SendMessage(
 	channelID kernel.ChannelID,
 	msg channels.OutgoingMessage,
 ) error {
+	// En simulación el mensaje se captura y nunca llega al proveedor
+	if outbox, ok := channels.OutboxFromContext(ctx); ok {
+		log.Printf("🧪 Simulated message to %s via channel %s captured", msg.RecipientID, channelID.String())
+		outbox.Capture(channelID, msg)
+		return nil
+	}
+
+	channel, adapter, err := cm.activeChannel(ctx, tenantID, channelID)
+	if err != nil {
+		return err
+	}
+
+	// Los pines de ubicación, tarjetas de contacto y mensajes interactivos
+	// solo salen por canales que los soportan
+	if err := checkContentSupported(channel, msg.Content); err != nil {
+		return err
+	}
+
+	// Enviar mensaje usando el adapter específico del canal
+	log.Printf("📤 Sending message via channel %s (type: %s) to %s",
+		channel.Name, channel.Type, msg.RecipientID)
+
+	breaker := cm.breakers.Breaker("channel:" + channelID.String())
+	if err := breaker.Allow(); err != nil {
+		log.Printf("⛔ Channel %s circuit is open, message to %s not sent", channelID, msg.RecipientID)
+		cm.recordOutbound(ctx, channel, msg, "", conversation.MessageStatusFailed)
+		return channels.Classified(err.WithDetail("channel_id", channelID.String()), channels.FailureTransient, 0)
+	}
+
+	// Los adapters que conocen el ID del proveedor lo guardan en el historial,
+	// así las respuestas que citan este mensaje se pueden resolver
+	var providerMessageID string
+	if sender, ok := adapter.(channels.ProviderMessageSender); ok {
+		providerMessageID, err = sender.SendMessageWithID(ctx, msg)
+	} else {
+		err = adapter.SendMessage(ctx, msg)
+	}
+	// Solo los fallos transitorios indican que el proveedor está caído; un
+	// rechazo de autenticación o de validación es una respuesta
+	breaker.Record(err != nil && channels.ClassifyError(err) == channels.FailureTransient)
+
+	if err != nil {
+		log.Printf("❌ Failed to send message: %v", err)
+		cm.recordOutbound(ctx, channel, msg, "", conversation.MessageStatusFailed)
+		sendErr := channels.ErrMessageSendFailed().
+			WithDetail("channel_id", channelID.String()).
+			WithDetail("error", err.Error()).
+			WithCause(err)
+		return channels.CopyClassification(sendErr, err)
+	}
+
+	log.Printf("✅ Message sent successfully via %s", channel.Name)
+	cm.recordOutbound(ctx, channel, msg, providerMessageID, conversation.MessageStatusProcessed)
+	channels.PublishMessageEvent(ctx, cm.events, channels.EventResponseSent, channels.NewSentEvent(channel, msg, providerMessageID))
+	return nil
+}
+
+// StreamMessage entrega un mensaje cuyo texto llega por partes. Si el canal
+// permite editar mensajes, el primer fragmento se envía enseguida y el mensaje
+// se actualiza a medida que llegan los demás; si no, se envía completo cuando
+// parts se cierra. En el historial queda solo el texto final.
+func (cm *DefaultChannelManager) StreamMessage(
+	ctx context.Context,
+	tenantID kernel.TenantID,
+	channelID kernel.ChannelID,
+	msg channels.OutgoingMessage,
+	parts <-chan string,
+) error {
+	// En simulación no hay proveedor que edite: se captura el mensaje completo
+	if _, ok := channels.OutboxFromContext(ctx); ok {
+		return cm.sendWhole(ctx, tenantID, channelID, msg, parts)
+	}
+
+	channel, adapter, err := cm.activeChannel(ctx, tenantID, channelID)
+	if err != nil {
+		return err
+	}
+
+	editor, ok := adapter.(channels.MessageEditor)
+	features, _ := channel.GetFeatures()
+	if !ok || !features.SupportsMessageEditing {
+		return cm.sendWhole(ctx, tenantID, channelID, msg, parts)
+	}
+
+	breaker := cm.breakers.Breaker("channel:" + channelID.String())
+	if err := breaker.Allow(); err != nil {
+		log.Printf("⛔ Channel %s circuit is open, message to %s not sent", channelID, msg.RecipientID)
+		cm.recordOutbound(ctx, channel, msg, "", conversation.MessageStatusFailed)
+		return channels.Classified(err.WithDetail("channel_id", channelID.String()), channels.FailureTransient, 0)
+	}
+
+	log.Printf("📤 Streaming message via channel %s (type: %s) to %s",
+		channel.Name, channel.Type, msg.RecipientID)
+
+	providerMessageID, text, err := streamEdits(ctx, editor, msg, parts)
+	breaker.Record(err != nil && channels.ClassifyError(err) == channels.FailureTransient)
+
+	msg.Content.Text = text
+	if err != nil {
+		log.Printf("❌ Failed to stream message: %v", err)
+		cm.recordOutbound(ctx, channel, msg, providerMessageID, conversation.MessageStatusFailed)
+		sendErr := channels.ErrMessageSendFailed().
+			WithDetail("channel_id", channelID.String()).
+			WithDetail("error", err.Error()).
+			WithCause(err)
+		return channels.CopyClassification(sendErr, err)
+	}
+	if providerMessageID == "" {
+		// El modelo no generó texto: no hay nada que guardar
+		return nil
+	}
+
+	log.Printf("✅ Message streamed successfully via %s", channel.Name)
+	cm.recordOutbound(ctx, channel, msg, providerMessageID, conversation.MessageStatusProcessed)
+	channels.PublishMessageEvent(ctx, cm.events, channels.EventResponseSent, channels.NewSentEvent(channel, msg, providerMessageID))
+	return nil
+}
+
+// streamEdits envía el primer fragmento con SendPartial y edita el mensaje
+// como mucho cada streamEditInterval; al cerrarse parts hace la edición final.
+// Retorna el ID del mensaje en el proveedor y el texto acumulado.
+func streamEdits(
+	ctx context.Context,
+	editor channels.MessageEditor,
+	msg channels.OutgoingMessage,
+	parts <-chan string,
+) (string, string, error) {
+	ticker := time.NewTicker(streamEditInterval)
+	defer ticker.Stop()
+
+	var text strings.Builder
+	var providerMessageID, shown string
+
+	// edit muestra el texto acumulado si cambió desde la última edición
+	edit := func() error {
+		current := text.String()
+		if current == shown || strings.TrimSpace(current) == "" {
+			return nil
+		}
+		content := msg.Content
+		content.Text = current
+
+		if providerMessageID == "" {
+			partial := msg
+			partial.Content = content
+			id, err := editor.SendPartial(ctx, partial)
+			if err != nil {
+				return err
+			}
+			providerMessageID = id
+		} else if err := editor.EditMessage(ctx, msg.RecipientID, providerMessageID, content); err != nil {
+			return err
+		}
+		shown = current
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return providerMessageID, text.String(), ctx.Err()
+
+		case part, ok := <-parts:
+			if !ok {
+				return providerMessageID, text.String(), edit()
+			}
+			text.WriteString(part)
+			// El primer fragmento se muestra sin esperar al ticker
+			if providerMessageID == "" {
+				if err := edit(); err != nil {
+					return providerMessageID, text.String(), err
+				}
+			}
+
+		case <-ticker.C:
+			if err := edit(); err != nil {
+				return providerMessageID, text.String(), err
+			}
+		}
+	}
+}
+
+// sendWhole espera el texto completo y lo envía como un mensaje normal
+func (cm *DefaultChannelManager) sendWhole(
+	ctx context.Context,
+	tenantID kernel.TenantID,
+	channelID kernel.ChannelID,
+	msg channels.OutgoingMessage,
+	parts <-chan string,
+) error {
+	text, err := drainParts(ctx, parts)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	msg.Content.Text = text
+	return cm.SendMessage(ctx, tenantID, channelID, msg)
+}
+
+// drainParts acumula los fragmentos hasta que parts se cierra
+func drainParts(ctx context.Context, parts <-chan string) (string, error) {
+	var text strings.Builder
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case part, ok := <-parts:
+			if !ok {
+				return text.String(), nil
+			}
+			text.WriteString(part)
+		}
+	}
+}
+
+// activeChannel obtiene el canal y su adapter, cargándolos si no están en memoria
+func (cm *DefaultChannelManager) activeChannel(
+	ctx context.Context,
+	tenantID kernel.TenantID,
+	channelID kernel.ChannelID,
+) (*channels.Channel, channels.ChannelAdapter, error) {
 	// Obtener canal
 	cm.mu.RLock()
 	channel, channelExists := cm.channels[channelID]
@@ -172,13 +500,13 @@ func (cm *DefaultChannelManager) SendMessage(
 		var err error
 		channel, err = cm.channelRepo.FindByID(ctx, channelID, tenantID) // ⚠️ Fix tenantID
 		if err != nil {
-			return channels.ErrChannelNotFound().
+			return nil, nil, channels.ErrChannelNotFound().
 				WithDetail("channel_id", channelID.String())
 		}
 
 		// Registrar el canal (esto creará el adapter)
 		if err := cm.RegisterChannel(ctx, *channel); err != nil {
-			return err
+			return nil, nil, err
 		}
 
 		// Obtener el adapter recién creado
@@ -191,9 +519,9 @@ func (cm *DefaultChannelManager) SendMessage(
 	if !adapterExists {
 		log.Printf("⚠️  Adapter not found for channel %s, creating...", channelID)
 
-		newAdapter, err := cm.createAdapterForChannel(*channel)
+		newAdapter, err := cm.createAdapterForChannel(ctx, *channel)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 
 		cm.mu.Lock()
@@ -204,22 +532,49 @@ func (cm *DefaultChannelManager) SendMessage(
 
 	// Verificar que el canal esté activo
 	if !channel.IsActive {
-		return channels.ErrChannelInactive().WithDetail("channel_id", channelID.String())
+		return nil, nil, channels.ErrChannelInactive().WithDetail("channel_id", channelID.String())
 	}
 
-	// Enviar mensaje usando el adapter específico del canal
-	log.Printf("📤 Sending message via channel %s (type: %s) to %s",
-		channel.Name, channel.Type, msg.RecipientID)
+	return channel, adapter, nil
+}
 
-	if err := adapter.SendMessage(ctx, msg); err != nil {
-		log.Printf("❌ Failed to send message: %v", err)
-		return channels.ErrMessageSendFailed().
-			WithDetail("channel_id", channelID.String()).
-			WithDetail("error", err.Error())
+// checkContentSupported rechaza contenido que el tipo de canal no puede enviar
+func checkContentSupported(channel *channels.Channel, content channels.MessageContent) error {
+	features, err := channel.GetFeatures()
+	if err != nil {
+		return nil
 	}
 
-	log.Printf("✅ Message sent successfully via %s", channel.Name)
-	return nil
+	feature := channels.UnsupportedFeature(features, content)
+	if feature == "" {
+		return nil
+	}
+
+	return channels.ErrFeatureNotSupported().
+		WithDetail("feature", feature).
+		WithDetail("channel_type", string(channel.Type))
+}
+
+// recordOutbound guarda el mensaje saliente en el historial de la conversación.
+// Un fallo al persistir nunca debe afectar el envío.
+func (cm *DefaultChannelManager) recordOutbound(
+	ctx context.Context,
+	channel *channels.Channel,
+	msg channels.OutgoingMessage,
+	providerMessageID string,
+	status conversation.MessageStatus,
+) {
+	if cm.messageRepo == nil {
+		return
+	}
+
+	record := conversation.NewOutboundMessage(uuid.NewString(), channel.TenantID, channel.ID, msg)
+	record.Status = status
+	record.ProviderMessageID = providerMessageID
+
+	if err := cm.messageRepo.Save(ctx, *record); err != nil {
+		log.Printf("⚠️  Failed to record outbound message for channel %s: %v", channel.ID.String(), err)
+	}
 }
 
 // ProcessIncomingMessage procesa un mensaje entrante
@@ -368,10 +723,39 @@ func (cm *DefaultChannelManager) ReloadChannel(ctx context.Context, channelID ke
 	return cm.RegisterChannel(ctx, *channel)
 }
 
+// OnFlagsChanged implementa featureflag.ChangeListener: descarta los canales en
+// cache del tenant para que se vuelvan a crear con los flags nuevos al usarse
+func (cm *DefaultChannelManager) OnFlagsChanged(ctx context.Context, tenantID kernel.TenantID) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	dropped := 0
+	for channelID, channel := range cm.channels {
+		if channel.TenantID != tenantID {
+			continue
+		}
+		delete(cm.channels, channelID)
+		delete(cm.adapters, channelID)
+		dropped++
+	}
+
+	if dropped > 0 {
+		log.Printf("🚩 Feature flags changed for tenant %s, %d channel(s) will be reloaded", tenantID, dropped)
+	}
+}
+
 // ============================================================================
 // Helper Functions
 // ============================================================================
 
+// featureEnabled consulta un flag; sin checker configurado todo está habilitado
+func (cm *DefaultChannelManager) featureEnabled(ctx context.Context, tenantID kernel.TenantID, flag featureflag.Flag) bool {
+	if cm.flags == nil {
+		return true
+	}
+	return cm.flags.IsEnabled(ctx, tenantID, flag)
+}
+
 // safeSubstring extrae substring de forma segura
 func safeSubstring(s string, length int) string {
 	if len(s) <= length {
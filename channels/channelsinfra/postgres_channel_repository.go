@@ -40,16 +40,16 @@ func (r *PostgresChannelRepository) create(ctx context.Context, channel channels
 	query := `
 		INSERT INTO channels (
 			id, tenant_id, type, name, description, config, 
-			is_active, webhook_url, created_at, updated_at
+			is_active, environment, webhook_url, created_at, updated_at
 		) VALUES (
 			:id, :tenant_id, :type, :name, :description, :config,
-			:is_active, :webhook_url, :created_at, :updated_at
+			:is_active, :environment, :webhook_url, :created_at, :updated_at
 		)`
 
 	_, err := r.db.NamedExecContext(ctx, query, channel)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
-			if pqErr.Code == "23505" && pqErr.Constraint == "channels_name_tenant_id_key" {
+			if pqErr.Code == "23505" && pqErr.Constraint == "channels_tenant_environment_name_key" {
 				return channels.ErrChannelAlreadyExists().
 					WithDetail("name", channel.Name).
 					WithDetail("tenant_id", channel.TenantID.String())
@@ -70,6 +70,7 @@ func (r *PostgresChannelRepository) update(ctx context.Context, channel channels
 			description = :description,
 			config = :config,
 			is_active = :is_active,
+			environment = :environment,
 			webhook_url = :webhook_url,
 			updated_at = :updated_at
 		WHERE id = :id AND tenant_id = :tenant_id`
@@ -101,7 +102,7 @@ func (r *PostgresChannelRepository) FindByID(ctx context.Context, id kernel.Chan
 	query := `
 		SELECT 
 			id, tenant_id, type, name, description, config,
-			is_active, webhook_url, created_at, updated_at
+			is_active, environment, webhook_url, created_at, updated_at
 		FROM channels
 		WHERE id = $1 AND tenant_id = $2`
 
@@ -118,16 +119,16 @@ func (r *PostgresChannelRepository) FindByID(ctx context.Context, id kernel.Chan
 	return &channel, nil
 }
 
-func (r *PostgresChannelRepository) FindByName(ctx context.Context, name string, tenantID kernel.TenantID) (*channels.Channel, error) {
+func (r *PostgresChannelRepository) FindByName(ctx context.Context, name string, tenantID kernel.TenantID, environment kernel.Environment) (*channels.Channel, error) {
 	query := `
 		SELECT 
 			id, tenant_id, type, name, description, config,
-			is_active, webhook_url, created_at, updated_at
+			is_active, environment, webhook_url, created_at, updated_at
 		FROM channels
-		WHERE name = $1 AND tenant_id = $2`
+		WHERE name = $1 AND tenant_id = $2 AND environment = $3`
 
 	var channel channels.Channel
-	err := r.db.GetContext(ctx, &channel, query, name, tenantID.String())
+	err := r.db.GetContext(ctx, &channel, query, name, tenantID.String(), environment.OrDefault())
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, channels.ErrChannelNotFound().WithDetail("name", name)
@@ -160,11 +161,11 @@ func (r *PostgresChannelRepository) Delete(ctx context.Context, id kernel.Channe
 	return nil
 }
 
-func (r *PostgresChannelRepository) ExistsByName(ctx context.Context, name string, tenantID kernel.TenantID) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM channels WHERE name = $1 AND tenant_id = $2)`
+func (r *PostgresChannelRepository) ExistsByName(ctx context.Context, name string, tenantID kernel.TenantID, environment kernel.Environment) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM channels WHERE name = $1 AND tenant_id = $2 AND environment = $3)`
 
 	var exists bool
-	err := r.db.GetContext(ctx, &exists, query, name, tenantID.String())
+	err := r.db.GetContext(ctx, &exists, query, name, tenantID.String(), environment.OrDefault())
 	if err != nil {
 		return false, errx.Wrap(err, "failed to check channel existence by name", errx.TypeInternal).
 			WithDetail("name", name)
@@ -177,7 +178,7 @@ func (r *PostgresChannelRepository) FindByTenant(ctx context.Context, tenantID k
 	query := `
 		SELECT 
 			id, tenant_id, type, name, description, config,
-			is_active, webhook_url, created_at, updated_at
+			is_active, environment, webhook_url, created_at, updated_at
 		FROM channels
 		WHERE tenant_id = $1
 		ORDER BY name ASC`
@@ -201,7 +202,7 @@ func (r *PostgresChannelRepository) FindByType(ctx context.Context, channelType
 	query := `
 		SELECT 
 			id, tenant_id, type, name, description, config,
-			is_active, webhook_url, created_at, updated_at
+			is_active, environment, webhook_url, created_at, updated_at
 		FROM channels
 		WHERE type = $1 AND tenant_id = $2
 		ORDER BY name ASC`
@@ -225,7 +226,7 @@ func (r *PostgresChannelRepository) FindActive(ctx context.Context, tenantID ker
 	query := `
 		SELECT 
 			id, tenant_id, type, name, description, config,
-			is_active, webhook_url, created_at, updated_at
+			is_active, environment, webhook_url, created_at, updated_at
 		FROM channels
 		WHERE tenant_id = $1 AND is_active = true
 		ORDER BY name ASC`
@@ -248,7 +249,7 @@ func (r *PostgresChannelRepository) FindByProvider(ctx context.Context, provider
 	query := `
 		SELECT 
 			id, tenant_id, type, name, description, config,
-			is_active, webhook_url, created_at, updated_at
+			is_active, environment, webhook_url, created_at, updated_at
 		FROM channels
 		WHERE tenant_id = $1 AND config->>'provider' = $2
 		ORDER BY name ASC`
@@ -296,6 +297,12 @@ func (r *PostgresChannelRepository) List(ctx context.Context, req channels.ListC
 		argPos++
 	}
 
+	if req.Environment != nil {
+		conditions = append(conditions, fmt.Sprintf("environment = $%d", argPos))
+		args = append(args, *req.Environment)
+		argPos++
+	}
+
 	if req.Search != "" {
 		conditions = append(conditions, fmt.Sprintf("(name ILIKE $%d OR description ILIKE $%d)", argPos, argPos+1))
 		searchPattern := "%" + req.Search + "%"
@@ -317,7 +324,7 @@ func (r *PostgresChannelRepository) List(ctx context.Context, req channels.ListC
 	dataQuery := fmt.Sprintf(`
 		SELECT 
 			id, tenant_id, type, name, description, config,
-			is_active, webhook_url, created_at, updated_at
+			is_active, environment, webhook_url, created_at, updated_at
 		FROM channels
 		WHERE %s
 		ORDER BY name ASC
@@ -332,7 +339,7 @@ func (r *PostgresChannelRepository) List(ctx context.Context, req channels.ListC
 		return channels.ChannelListResponse{}, errx.Wrap(err, "failed to list channels", errx.TypeInternal)
 	}
 
-	return storex.NewPaginated(channelList, total, req.Page, req.PageSize), nil
+	return storex.NewPaginated(channelList, req.Page, req.PageSize, total), nil
 }
 
 func (r *PostgresChannelRepository) BulkUpdateStatus(ctx context.Context, ids []kernel.ChannelID, tenantID kernel.TenantID, isActive bool) error {
@@ -0,0 +1,127 @@
+package channelsinfra
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresSuppressionRepository struct {
+	db     *sqlx.DB
+	router *database.Router // nil = las supresiones de todos los tenants están en db
+}
+
+var _ channels.SuppressionRepository = (*PostgresSuppressionRepository)(nil)
+
+func NewPostgresSuppressionRepository(db *sqlx.DB) *PostgresSuppressionRepository {
+	return &PostgresSuppressionRepository{db: db}
+}
+
+// SetRouter guarda las supresiones de cada tenant en la base de su región de
+// datos: son identificadores de contactos, como los mensajes
+func (r *PostgresSuppressionRepository) SetRouter(router *database.Router) {
+	r.router = router
+}
+
+func (r *PostgresSuppressionRepository) IsSuppressed(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, recipientID string) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM contact_suppressions
+			WHERE tenant_id = $1
+			  AND recipient_id = $2
+			  AND (channel_id IS NULL OR channel_id = $3)
+		)`
+
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return false, err
+	}
+
+	var suppressed bool
+	if err := db.GetContext(ctx, &suppressed, query, tenantID.String(), recipientID, channelID.String()); err != nil {
+		return false, errx.Wrap(err, "failed to check suppression list", errx.TypeInternal).
+			WithDetail("recipient_id", recipientID)
+	}
+
+	return suppressed, nil
+}
+
+func (r *PostgresSuppressionRepository) Add(ctx context.Context, suppression channels.Suppression) error {
+	query := `
+		INSERT INTO contact_suppressions (
+			id, tenant_id, channel_id, recipient_id, reason, created_at
+		) VALUES (
+			:id, :tenant_id, :channel_id, :recipient_id, :reason, :created_at
+		)
+		ON CONFLICT DO NOTHING`
+
+	db, err := r.conn(ctx, suppression.TenantID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.NamedExecContext(ctx, query, suppression); err != nil {
+		return errx.Wrap(err, "failed to add suppression", errx.TypeInternal).
+			WithDetail("recipient_id", suppression.RecipientID)
+	}
+
+	return nil
+}
+
+func (r *PostgresSuppressionRepository) Remove(ctx context.Context, tenantID kernel.TenantID, recipientID string, channelID *kernel.ChannelID) error {
+	query := `
+		DELETE FROM contact_suppressions
+		WHERE tenant_id = $1 AND recipient_id = $2 AND channel_id IS NULL`
+	args := []any{tenantID.String(), recipientID}
+
+	if channelID != nil {
+		query = `
+			DELETE FROM contact_suppressions
+			WHERE tenant_id = $1 AND recipient_id = $2 AND channel_id = $3`
+		args = append(args, channelID.String())
+	}
+
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
+		return errx.Wrap(err, "failed to remove suppression", errx.TypeInternal).
+			WithDetail("recipient_id", recipientID)
+	}
+
+	return nil
+}
+
+func (r *PostgresSuppressionRepository) RemoveByChannel(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID) error {
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM contact_suppressions WHERE tenant_id = $1 AND channel_id = $2`
+	if _, err := db.ExecContext(ctx, query, tenantID.String(), channelID.String()); err != nil {
+		return errx.Wrap(err, "failed to remove channel suppressions", errx.TypeInternal).
+			WithDetail("channel_id", channelID.String())
+	}
+
+	return nil
+}
+
+// conn devuelve la base que guarda las supresiones del tenant
+func (r *PostgresSuppressionRepository) conn(ctx context.Context, tenantID kernel.TenantID) (*sqlx.DB, error) {
+	if r.router == nil {
+		return r.db, nil
+	}
+	db, err := r.router.For(ctx, tenantID)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to resolve tenant database", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+	return db, nil
+}
@@ -0,0 +1,177 @@
+package channelsinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresWebhookEventRepository struct {
+	db *sqlx.DB
+}
+
+var _ channels.RawWebhookEventRepository = (*PostgresWebhookEventRepository)(nil)
+
+func NewPostgresWebhookEventRepository(db *sqlx.DB) *PostgresWebhookEventRepository {
+	return &PostgresWebhookEventRepository{db: db}
+}
+
+// dbWebhookEvent struct intermedio para la base de datos
+type dbWebhookEvent struct {
+	ID              string          `db:"id"`
+	TenantID        string          `db:"tenant_id"`
+	ChannelID       string          `db:"channel_id"`
+	Provider        string          `db:"provider"`
+	EventType       string          `db:"event_type"`
+	Payload         json.RawMessage `db:"payload"`
+	Error           sql.NullString  `db:"error"`
+	Status          string          `db:"status"`
+	ReprocessCount  int             `db:"reprocess_count"`
+	LastReprocessAt *time.Time      `db:"last_reprocess_at"`
+	CreatedAt       time.Time       `db:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at"`
+}
+
+const webhookEventColumns = `
+	id, tenant_id, channel_id, provider, event_type, payload, error,
+	status, reprocess_count, last_reprocess_at, created_at, updated_at`
+
+func (r *PostgresWebhookEventRepository) Save(ctx context.Context, event channels.RawWebhookEvent) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal webhook event payload", errx.TypeInternal)
+	}
+
+	// Un reproceso solo cambia el resultado; el payload queda como llegó
+	query := `
+		INSERT INTO raw_webhook_events (
+			id, tenant_id, channel_id, provider, event_type, payload, error,
+			status, reprocess_count, last_reprocess_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO UPDATE SET
+			error = EXCLUDED.error,
+			status = EXCLUDED.status,
+			reprocess_count = EXCLUDED.reprocess_count,
+			last_reprocess_at = EXCLUDED.last_reprocess_at`
+
+	_, err = r.db.ExecContext(ctx, query,
+		event.ID, event.TenantID.String(), event.ChannelID.String(), string(event.Provider), event.EventType,
+		payload, sql.NullString{String: event.Error, Valid: event.Error != ""},
+		string(event.Status), event.ReprocessCount, event.LastReprocessAt, event.CreatedAt,
+	)
+	if err != nil {
+		return errx.Wrap(err, "failed to save webhook event", errx.TypeInternal).
+			WithDetail("webhook_event_id", event.ID)
+	}
+
+	return nil
+}
+
+func (r *PostgresWebhookEventRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*channels.RawWebhookEvent, error) {
+	query := fmt.Sprintf(`SELECT %s FROM raw_webhook_events WHERE id = $1 AND tenant_id = $2`, webhookEventColumns)
+
+	var row dbWebhookEvent
+	if err := r.db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, channels.ErrWebhookEventNotFound().WithDetail("webhook_event_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find webhook event", errx.TypeInternal).
+			WithDetail("webhook_event_id", id)
+	}
+
+	return toDomainWebhookEvent(&row)
+}
+
+func (r *PostgresWebhookEventRepository) List(ctx context.Context, req channels.ListWebhookEventsRequest) (channels.WebhookEventListResponse, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.ChannelID != nil {
+		conditions = append(conditions, fmt.Sprintf("channel_id = $%d", argPos))
+		args = append(args, req.ChannelID.String())
+		argPos++
+	}
+	if req.Provider != nil {
+		conditions = append(conditions, fmt.Sprintf("provider = $%d", argPos))
+		args = append(args, string(*req.Provider))
+		argPos++
+	}
+	if req.EventType != nil {
+		conditions = append(conditions, fmt.Sprintf("event_type = $%d", argPos))
+		args = append(args, *req.EventType)
+		argPos++
+	}
+	if req.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argPos))
+		args = append(args, string(*req.Status))
+		argPos++
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM raw_webhook_events WHERE %s", whereClause)
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return channels.WebhookEventListResponse{}, errx.Wrap(err, "failed to count webhook events", errx.TypeInternal)
+	}
+
+	// Del más antiguo al más nuevo, el orden en que se reprocesan
+	dataQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM raw_webhook_events
+		WHERE %s
+		ORDER BY created_at ASC, id ASC
+		LIMIT $%d OFFSET $%d`,
+		webhookEventColumns, whereClause, argPos, argPos+1)
+
+	args = append(args, req.PageSize, req.GetOffset())
+
+	var rows []dbWebhookEvent
+	if err := r.db.SelectContext(ctx, &rows, dataQuery, args...); err != nil {
+		return channels.WebhookEventListResponse{}, errx.Wrap(err, "failed to list webhook events", errx.TypeInternal)
+	}
+
+	events := make([]channels.RawWebhookEvent, 0, len(rows))
+	for i := range rows {
+		event, err := toDomainWebhookEvent(&rows[i])
+		if err != nil {
+			return channels.WebhookEventListResponse{}, err
+		}
+		events = append(events, *event)
+	}
+
+	return storex.NewPaginated(events, req.Page, req.PageSize, total), nil
+}
+
+func toDomainWebhookEvent(row *dbWebhookEvent) (*channels.RawWebhookEvent, error) {
+	event := &channels.RawWebhookEvent{
+		ID:              row.ID,
+		TenantID:        kernel.TenantID(row.TenantID),
+		ChannelID:       kernel.ChannelID(row.ChannelID),
+		Provider:        channels.ChannelType(row.Provider),
+		EventType:       row.EventType,
+		Error:           row.Error.String,
+		Status:          channels.WebhookEventStatus(row.Status),
+		ReprocessCount:  row.ReprocessCount,
+		LastReprocessAt: row.LastReprocessAt,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+
+	if err := json.Unmarshal(row.Payload, &event.Payload); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal webhook event payload", errx.TypeInternal).
+			WithDetail("webhook_event_id", row.ID)
+	}
+
+	return event, nil
+}
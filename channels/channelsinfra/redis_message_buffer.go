@@ -0,0 +1,231 @@
+package channelsinfra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	// bufferDueKey sorted set de ráfagas pendientes; el score es el
+	// vencimiento en milisegundos y el miembro la BufferKey en JSON
+	bufferDueKey = "relay:buffer:due"
+
+	// bufferMessagesPrefix lista de mensajes de cada ráfaga, seguida del miembro
+	bufferMessagesPrefix = "relay:buffer:messages:"
+
+	// bufferProcessingKey sorted set de ráfagas reclamadas sin Ack; el score
+	// es el fin de su visibilidad y el miembro "<reclamo>|<BufferKey en JSON>"
+	bufferProcessingKey = "relay:buffer:processing"
+
+	// bufferInflightPrefix lista de mensajes de una ráfaga reclamada, seguida
+	// del reclamo
+	bufferInflightPrefix = "relay:buffer:inflight:"
+
+	// bufferRetention margen sobre la ventana antes de que Redis borre una
+	// ráfaga que nadie vació (todas las instancias caídas)
+	bufferRetention = 10 * time.Minute
+)
+
+// appendScript añade el mensaje y agenda el vaciado en una sola operación,
+// así dos instancias que reciben mensajes del mismo remitente no se pisan
+var appendScript = redis.NewScript(`
+local count = redis.call("RPUSH", KEYS[1], ARGV[2])
+redis.call("PEXPIRE", KEYS[1], ARGV[5])
+if ARGV[4] == "1" then
+	redis.call("ZADD", KEYS[2], ARGV[3], ARGV[1])
+else
+	redis.call("ZADD", KEYS[2], "NX", ARGV[3], ARGV[1])
+end
+local max = tonumber(ARGV[6])
+if max > 0 and count >= max then
+	redis.call("ZADD", KEYS[2], 0, ARGV[1])
+end
+return count
+`)
+
+// claimScript primero devuelve a la cola las ráfagas cuya visibilidad venció
+// sin Ack, delante de los mensajes que llegaron después. Luego mueve las
+// ráfagas vencidas a procesamiento y las devuelve con sus mensajes. Al ser
+// atómico, cada ráfaga la recibe una sola instancia sin un lock aparte.
+var claimScript = redis.NewScript(`
+local expired = redis.call("ZRANGEBYSCORE", KEYS[2], "-inf", ARGV[1], "LIMIT", 0, ARGV[2])
+for _, entry in ipairs(expired) do
+	redis.call("ZREM", KEYS[2], entry)
+	local sep = string.find(entry, "|", 1, true)
+	local inflight = ARGV[4] .. string.sub(entry, 1, sep - 1)
+	local member = string.sub(entry, sep + 1)
+	local messages = redis.call("LRANGE", inflight, 0, -1)
+	redis.call("DEL", inflight)
+	if #messages > 0 then
+		local key = ARGV[3] .. member
+		for i = #messages, 1, -1 do
+			redis.call("LPUSH", key, messages[i])
+		end
+		redis.call("PEXPIRE", key, ARGV[7])
+		redis.call("ZADD", KEYS[1], ARGV[1], member)
+	end
+end
+
+local members = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, ARGV[2])
+local result = {}
+for i, member in ipairs(members) do
+	redis.call("ZREM", KEYS[1], member)
+	local key = ARGV[3] .. member
+	if redis.call("EXISTS", key) == 1 then
+		local claim = ARGV[6] .. ":" .. i
+		local inflight = ARGV[4] .. claim
+		redis.call("RENAME", key, inflight)
+		redis.call("PEXPIRE", inflight, ARGV[7])
+		local entry = claim .. "|" .. member
+		redis.call("ZADD", KEYS[2], ARGV[5], entry)
+		table.insert(result, entry)
+		table.insert(result, redis.call("LRANGE", inflight, 0, -1))
+	end
+end
+return result
+`)
+
+// RedisMessageBuffer guarda las ráfagas en Redis, compartidas por todas las
+// instancias. Los scripts tocan claves calculadas, así que requiere un Redis
+// sin cluster, como el resto de la aplicación.
+type RedisMessageBuffer struct {
+	client *redis.Client
+}
+
+var _ channels.MessageBufferStore = (*RedisMessageBuffer)(nil)
+
+func NewRedisMessageBuffer(client *redis.Client) *RedisMessageBuffer {
+	return &RedisMessageBuffer{client: client}
+}
+
+func (b *RedisMessageBuffer) Append(ctx context.Context, key channels.BufferKey, msg channels.BufferedMessage, settings channels.BufferSettings) error {
+	member, err := json.Marshal(key)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal buffer key", errx.TypeInternal)
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal buffered message", errx.TypeInternal)
+	}
+
+	reset := "0"
+	if settings.ResetOnMessage {
+		reset = "1"
+	}
+	flushAt := msg.ReceivedAt.Add(settings.Window).UnixMilli()
+	retention := (settings.Window + bufferRetention).Milliseconds()
+
+	err = appendScript.Run(ctx, b.client,
+		[]string{bufferMessagesPrefix + string(member), bufferDueKey},
+		string(member), data, flushAt, reset, retention, settings.MaxMessages,
+	).Err()
+	if err != nil {
+		return errx.Wrap(err, "failed to buffer message", errx.TypeUnavailable).
+			WithDetail("channel_id", key.ChannelID.String())
+	}
+	return nil
+}
+
+func (b *RedisMessageBuffer) ClaimDue(ctx context.Context, now time.Time, limit int, visibility time.Duration) ([]channels.BufferedBatch, error) {
+	result, err := claimScript.Run(ctx, b.client,
+		[]string{bufferDueKey, bufferProcessingKey},
+		strconv.FormatInt(now.UnixMilli(), 10), limit, bufferMessagesPrefix, bufferInflightPrefix,
+		now.Add(visibility).UnixMilli(), uuid.NewString(), (visibility + bufferRetention).Milliseconds(),
+	).Slice()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, errx.Wrap(err, "failed to claim message buffers", errx.TypeUnavailable)
+	}
+
+	batches := make([]channels.BufferedBatch, 0, len(result)/2)
+	for i := 0; i+1 < len(result); i += 2 {
+		batch, err := decodeBatch(result[i], result[i+1])
+		if err != nil {
+			// Una ráfaga ilegible se descarta para que no vuelva en cada pasada
+			log.Printf("⚠️  Dropping unreadable message buffer: %v", err)
+			if batch.ClaimID != "" {
+				b.Ack(ctx, batch)
+			}
+			continue
+		}
+		if len(batch.Messages) > 0 {
+			batches = append(batches, batch)
+		}
+	}
+
+	return batches, nil
+}
+
+func (b *RedisMessageBuffer) Ack(ctx context.Context, batch channels.BufferedBatch) error {
+	claim, _, _ := strings.Cut(batch.ClaimID, "|")
+	_, err := b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, bufferProcessingKey, batch.ClaimID)
+		pipe.Del(ctx, bufferInflightPrefix+claim)
+		return nil
+	})
+	if err != nil {
+		return errx.Wrap(err, "failed to acknowledge message buffer", errx.TypeUnavailable).
+			WithDetail("channel_id", batch.Key.ChannelID.String())
+	}
+	return nil
+}
+
+// Requeue adelanta o retrasa el fin de la visibilidad: la siguiente pasada
+// de ClaimDue después de retryAt devuelve la ráfaga a la cola
+func (b *RedisMessageBuffer) Requeue(ctx context.Context, batch channels.BufferedBatch, retryAt time.Time) error {
+	err := b.client.ZAddXX(ctx, bufferProcessingKey, &redis.Z{
+		Score:  float64(retryAt.UnixMilli()),
+		Member: batch.ClaimID,
+	}).Err()
+	if err != nil {
+		return errx.Wrap(err, "failed to requeue message buffer", errx.TypeUnavailable).
+			WithDetail("channel_id", batch.Key.ChannelID.String())
+	}
+	return nil
+}
+
+// decodeBatch convierte el par reclamo/mensajes que devuelve claimScript
+func decodeBatch(rawEntry, rawMessages any) (channels.BufferedBatch, error) {
+	var batch channels.BufferedBatch
+
+	entry, ok := rawEntry.(string)
+	if !ok {
+		return batch, fmt.Errorf("unexpected buffer claim %T", rawEntry)
+	}
+	batch.ClaimID = entry
+
+	_, member, _ := strings.Cut(entry, "|")
+	if err := json.Unmarshal([]byte(member), &batch.Key); err != nil {
+		return batch, fmt.Errorf("invalid buffer key %q: %w", member, err)
+	}
+
+	messages, ok := rawMessages.([]any)
+	if !ok {
+		return batch, fmt.Errorf("unexpected buffer messages %T", rawMessages)
+	}
+	for _, raw := range messages {
+		data, ok := raw.(string)
+		if !ok {
+			continue
+		}
+		var msg channels.BufferedMessage
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			return batch, fmt.Errorf("invalid buffered message: %w", err)
+		}
+		batch.Messages = append(batch.Messages, msg)
+	}
+
+	return batch, nil
+}
@@ -0,0 +1,246 @@
+package channelsrv
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/channels"
+)
+
+const (
+	// bufferFlushInterval cada cuánto se buscan ráfagas vencidas
+	bufferFlushInterval = time.Second
+
+	// bufferClaimLimit máximo de ráfagas que reclama una pasada
+	bufferClaimLimit = 100
+
+	// bufferVisibilityTimeout tiempo que tiene una instancia para entregar una
+	// ráfaga reclamada antes de que otra la reintente. Cubre la descarga de
+	// adjuntos de DispatchInbound
+	bufferVisibilityTimeout = 5 * time.Minute
+
+	// bufferRetryDelay espera antes de reintentar una ráfaga cuyo canal no
+	// se pudo cargar
+	bufferRetryDelay = 10 * time.Second
+)
+
+// BufferService agrupa los mensajes seguidos de un remitente y los entrega
+// como uno solo al vencer la ventana del canal. Las ráfagas viven en el
+// store compartido, así que el mensaje puede llegar por una instancia y
+// vaciarse en otra. Una ráfaga se borra del store solo después de entregarla;
+// si la instancia cae antes, otra la reintenta al vencer su visibilidad.
+type BufferService struct {
+	store       channels.MessageBufferStore
+	channelRepo channels.ChannelRepository
+	dispatcher  channels.InboundDispatcher
+	interval    time.Duration
+
+	mu        sync.RWMutex
+	combiners map[channels.ChannelType]channels.BufferCombiner
+
+	stopChan chan struct{}
+	running  atomic.Bool // Start corre en su goroutine y Stop en el apagado
+}
+
+var _ channels.InboundBuffer = (*BufferService)(nil)
+
+func NewBufferService(store channels.MessageBufferStore, channelRepo channels.ChannelRepository) *BufferService {
+	return &BufferService{
+		store:       store,
+		channelRepo: channelRepo,
+		interval:    bufferFlushInterval,
+		combiners:   make(map[channels.ChannelType]channels.BufferCombiner),
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// SetDispatcher define a quién se entregan las ráfagas vaciadas
+func (s *BufferService) SetDispatcher(dispatcher channels.InboundDispatcher) {
+	s.dispatcher = dispatcher
+}
+
+// RegisterCombiner define cómo se unen las ráfagas de un tipo de canal; sin
+// combinador se usa combineText
+func (s *BufferService) RegisterCombiner(channelType channels.ChannelType, combiner channels.BufferCombiner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.combiners[channelType] = combiner
+}
+
+// Add guarda el mensaje en la ráfaga de su remitente
+func (s *BufferService) Add(ctx context.Context, channel *channels.Channel, msg channels.IncomingMessage, settings channels.BufferSettings) error {
+	key := channels.BufferKey{
+		TenantID:  channel.TenantID,
+		ChannelID: channel.ID,
+		SenderID:  msg.SenderID,
+	}
+	buffered := channels.BufferedMessage{
+		Message:    msg,
+		ReceivedAt: time.Now(),
+	}
+	return s.store.Append(ctx, key, buffered, settings)
+}
+
+// Start vacía las ráfagas vencidas hasta que se llame a Stop o ctx termine.
+// Cada instancia ejecuta uno; el store reparte las ráfagas entre ellas. El
+// servicio no se reinicia: después de Stop, Start no hace nada.
+func (s *BufferService) Start(ctx context.Context) {
+	select {
+	case <-s.stopChan:
+		log.Println("⚠️  Message buffer flusher was stopped and cannot be restarted")
+		return
+	default:
+	}
+
+	if !s.running.CompareAndSwap(false, true) {
+		log.Println("⚠️  Message buffer flusher already running")
+		return
+	}
+
+	log.Printf("📦 Starting message buffer flusher (every %s)...", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("⏹️  Message buffer flusher stopped (context done)")
+			return
+		case <-s.stopChan:
+			log.Println("⏹️  Message buffer flusher stopped")
+			return
+		case <-ticker.C:
+			s.Flush(ctx)
+		}
+	}
+}
+
+// Stop detiene el vaciado; las ráfagas pendientes quedan en el store para
+// la siguiente instancia
+func (s *BufferService) Stop() {
+	if !s.running.CompareAndSwap(true, false) {
+		return
+	}
+	close(s.stopChan)
+}
+
+// Flush reclama las ráfagas vencidas y las entrega. Devuelve cuántas entregó.
+func (s *BufferService) Flush(ctx context.Context) int {
+	batches, err := s.store.ClaimDue(ctx, time.Now(), bufferClaimLimit, bufferVisibilityTimeout)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("❌ Failed to claim message buffers: %v", err)
+		}
+		return 0
+	}
+
+	delivered := 0
+	for _, batch := range batches {
+		if s.deliver(ctx, batch) {
+			delivered++
+		}
+	}
+
+	return delivered
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// deliver entrega una ráfaga reclamada y la confirma en el store cuando
+// DispatchInbound la recibió. Si el canal no se pudo cargar la devuelve a la
+// cola; solo se descarta si el canal ya no existe o está inactivo.
+func (s *BufferService) deliver(ctx context.Context, batch channels.BufferedBatch) bool {
+	if s.dispatcher == nil {
+		log.Printf("⚠️  No inbound dispatcher configured, dropping buffer of %s", batch.Key.SenderID)
+		s.ack(ctx, batch)
+		return false
+	}
+
+	channel, err := s.channelRepo.FindByID(ctx, batch.Key.ChannelID, batch.Key.TenantID)
+	if err != nil {
+		if errx.IsCode(err, channels.CodeChannelNotFound) {
+			log.Printf("⚠️  Channel %s of buffered messages not found, dropping buffer of %s", batch.Key.ChannelID.String(), batch.Key.SenderID)
+			s.ack(ctx, batch)
+			return false
+		}
+		log.Printf("❌ Failed to load channel %s of buffered messages, retrying: %v", batch.Key.ChannelID.String(), err)
+		if err := s.store.Requeue(ctx, batch, time.Now().Add(bufferRetryDelay)); err != nil {
+			// Sin Requeue vuelve igual al vencer su visibilidad
+			log.Printf("⚠️  Failed to requeue buffer of %s: %v", batch.Key.SenderID, err)
+		}
+		return false
+	}
+	if !channel.IsActive {
+		log.Printf("⚠️  Channel %s is inactive, dropping buffer of %s", channel.ID.String(), batch.Key.SenderID)
+		s.ack(ctx, batch)
+		return false
+	}
+
+	msg := s.combinerFor(channel.Type)(batch)
+	if msg == nil {
+		s.ack(ctx, batch)
+		return false
+	}
+
+	log.Printf("📦 Flushing %d buffered messages from %s on channel %s",
+		len(batch.Messages), batch.Key.SenderID, channel.ID.String())
+
+	// La entrega descarga adjuntos y puede tardar; no frena las demás ráfagas
+	go func() {
+		s.dispatcher.DispatchInbound(context.Background(), channel, msg)
+		s.ack(context.Background(), batch)
+	}()
+	return true
+}
+
+// ack borra una ráfaga terminada. Si falla, la ráfaga se reintenta al
+// vencer su visibilidad
+func (s *BufferService) ack(ctx context.Context, batch channels.BufferedBatch) {
+	if err := s.store.Ack(ctx, batch); err != nil {
+		log.Printf("⚠️  Failed to acknowledge buffer of %s: %v", batch.Key.SenderID, err)
+	}
+}
+
+func (s *BufferService) combinerFor(channelType channels.ChannelType) channels.BufferCombiner {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if combiner, ok := s.combiners[channelType]; ok {
+		return combiner
+	}
+	return combineText
+}
+
+// combineText une los textos con saltos de línea sobre el primer mensaje
+func combineText(batch channels.BufferedBatch) *channels.IncomingMessage {
+	if len(batch.Messages) == 0 {
+		return nil
+	}
+
+	combined := batch.Messages[0].Message
+	texts := make([]string, 0, len(batch.Messages))
+	for _, buffered := range batch.Messages {
+		if text := buffered.Message.Content.Text; text != "" {
+			texts = append(texts, text)
+		}
+		if buffered.Message.Content.Type != "text" && buffered.Message.Content.Type != "" && buffered.Message.Content.Text == "" {
+			texts = append(texts, fmt.Sprintf("[%s]", buffered.Message.Content.Type))
+		}
+	}
+
+	combined.Content.Text = strings.Join(texts, "\n")
+	if combined.Metadata == nil {
+		combined.Metadata = make(map[string]any)
+	}
+	combined.Metadata["buffered"] = true
+	combined.Metadata["message_count"] = len(batch.Messages)
+	return &combined
+}
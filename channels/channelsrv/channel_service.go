@@ -9,6 +9,7 @@ import (
 	"github.com/Abraxas-365/craftable/errx"
 	"github.com/Abraxas-365/craftable/logx"
 	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/conversation"
 	"github.com/Abraxas-365/relay/iam/tenant"
 	"github.com/Abraxas-365/relay/pkg/kernel"
 	"github.com/google/uuid"
@@ -19,6 +20,12 @@ type ChannelService struct {
 	channelRepo    channels.ChannelRepository
 	tenantRepo     tenant.TenantRepository
 	channelManager channels.ChannelManager
+
+	// Lista de supresión (opcional)
+	suppressionRepo channels.SuppressionRepository
+
+	// Borra los mensajes y tags de un canal eliminado (opcional)
+	channelPurger conversation.ChannelPurger
 }
 
 // NewChannelService crea una nueva instancia del servicio de canales
@@ -26,14 +33,23 @@ func NewChannelService(
 	channelRepo channels.ChannelRepository,
 	tenantRepo tenant.TenantRepository,
 	channelManager channels.ChannelManager,
+	suppressionRepo channels.SuppressionRepository,
 ) *ChannelService {
 	return &ChannelService{
-		channelRepo:    channelRepo,
-		tenantRepo:     tenantRepo,
-		channelManager: channelManager,
+		channelRepo:     channelRepo,
+		tenantRepo:      tenantRepo,
+		channelManager:  channelManager,
+		suppressionRepo: suppressionRepo,
 	}
 }
 
+// SetChannelPurger registra quién borra las conversaciones de los canales
+// eliminados. Viven en la región de datos del tenant, donde no hay foreign key
+// a channels que las borre en cascada
+func (s *ChannelService) SetChannelPurger(purger conversation.ChannelPurger) {
+	s.channelPurger = purger
+}
+
 // ============================================================================
 // CRUD Operations
 // ============================================================================
@@ -50,8 +66,28 @@ func (s *ChannelService) CreateChannel(ctx context.Context, req channels.CreateC
 		return nil, tenant.ErrTenantSuspended()
 	}
 
-	// Verificar que no exista un canal con el mismo nombre
-	exists, err := s.channelRepo.ExistsByName(ctx, req.Name, req.TenantID)
+	if req.Config == nil {
+		return nil, channels.ErrInvalidChannelConfig().WithDetail("reason", "config is required")
+	}
+	if req.Config.GetType() != req.Type {
+		return nil, channels.ErrInvalidChannelType().
+			WithDetail("type", string(req.Type)).
+			WithDetail("config_type", string(req.Config.GetType()))
+	}
+	if err := req.Config.Validate(); err != nil {
+		return nil, err
+	}
+
+	environment := req.Environment
+	if environment == "" {
+		environment = channels.DefaultEnvironment(req.Type)
+	}
+	if !environment.IsValid() {
+		return nil, channels.ErrInvalidChannelConfig().WithDetail("reason", "environment must be sandbox or production")
+	}
+
+	// Verificar que no exista un canal con el mismo nombre en el entorno
+	exists, err := s.channelRepo.ExistsByName(ctx, req.Name, req.TenantID, environment)
 	if err != nil {
 		return nil, errx.Wrap(err, "failed to check channel name existence", errx.TypeInternal)
 	}
@@ -74,14 +110,7 @@ func (s *ChannelService) CreateChannel(ctx context.Context, req channels.CreateC
 	if err != nil {
 		return nil, errx.Wrap(err, "failed to create channel", errx.TypeInternal)
 	}
-
-	// Validar config usando el adapter si está disponible
-	if adapter, err := s.channelManager.GetAdapter(channelID); err == nil {
-		if err := adapter.ValidateConfig(req.Config); err != nil {
-			return nil, channels.ErrInvalidChannelConfig().
-				WithDetail("reason", err.Error())
-		}
-	}
+	newChannel.Environment = environment
 
 	// Guardar canal
 	if err := s.channelRepo.Save(ctx, *newChannel); err != nil {
@@ -115,9 +144,9 @@ func (s *ChannelService) GetChannelByID(ctx context.Context, channelID kernel.Ch
 	}, nil
 }
 
-// GetChannelByName obtiene un canal por nombre
-func (s *ChannelService) GetChannelByName(ctx context.Context, name string, tenantID kernel.TenantID) (*channels.ChannelResponse, error) {
-	channel, err := s.channelRepo.FindByName(ctx, name, tenantID)
+// GetChannelByName obtiene un canal por nombre dentro de un entorno
+func (s *ChannelService) GetChannelByName(ctx context.Context, name string, tenantID kernel.TenantID, environment kernel.Environment) (*channels.ChannelResponse, error) {
+	channel, err := s.channelRepo.FindByName(ctx, name, tenantID, environment)
 	if err != nil {
 		return nil, channels.ErrChannelNotFound().WithDetail("name", name)
 	}
@@ -156,7 +185,7 @@ func (s *ChannelService) UpdateChannel(ctx context.Context, channelID kernel.Cha
 	if req.Name != nil {
 		// Verificar que no exista otro canal con el mismo nombre
 		if *req.Name != channel.Name {
-			exists, err := s.channelRepo.ExistsByName(ctx, *req.Name, tenantID)
+			exists, err := s.channelRepo.ExistsByName(ctx, *req.Name, tenantID, channel.Environment)
 			if err != nil {
 				return nil, errx.Wrap(err, "failed to check channel name", errx.TypeInternal)
 			}
@@ -171,15 +200,25 @@ func (s *ChannelService) UpdateChannel(ctx context.Context, channelID kernel.Cha
 		channel.Description = *req.Description
 	}
 
-	if req.Config != nil {
+	configChanged := len(req.Config) > 0
+	if configChanged {
+		// Las respuestas ocultan los secretos: si no llega uno nuevo se conserva el guardado
+		raw, err := channels.KeepStoredSecrets(channel.Config, req.Config)
+		if err != nil {
+			return nil, channels.ErrInvalidChannelConfig().WithDetail("reason", err.Error())
+		}
+
+		config, err := channels.ParseChannelConfig(channel.Type, raw)
+		if err != nil {
+			return nil, channels.ErrInvalidChannelConfig().WithDetail("reason", err.Error())
+		}
+
 		// Validar config
-		if adapter, err := s.channelManager.GetAdapter(channelID); err == nil {
-			if err := adapter.ValidateConfig(*req.Config); err != nil {
-				return nil, channels.ErrInvalidChannelConfig().WithDetail("reason", err.Error())
-			}
+		if err := config.Validate(); err != nil {
+			return nil, err
 		}
 
-		if err := channel.UpdateConfig(*req.Config); err != nil {
+		if err := channel.UpdateConfig(config); err != nil {
 			return nil, errx.Wrap(err, "failed to update config", errx.TypeInternal)
 		}
 	}
@@ -199,6 +238,13 @@ func (s *ChannelService) UpdateChannel(ctx context.Context, channelID kernel.Cha
 		return nil, errx.Wrap(err, "failed to update channel", errx.TypeInternal)
 	}
 
+	// Recrear el adapter para que use el nuevo config
+	if configChanged {
+		if err := s.channelManager.RegisterChannel(ctx, *channel); err != nil {
+			logx.Warn("failed to re-register channel in manager: %v", err)
+		}
+	}
+
 	return channel, nil
 }
 
@@ -233,7 +279,127 @@ func (s *ChannelService) DeleteChannel(ctx context.Context, channelID kernel.Cha
 	}
 
 	// Eliminar canal
-	return s.channelRepo.Delete(ctx, channelID, tenantID)
+	if err := s.channelRepo.Delete(ctx, channelID, tenantID); err != nil {
+		return err
+	}
+
+	s.channelManager.UnregisterChannel(channelID)
+
+	// El canal ya no existe: un fallo aquí deja datos huérfanos pero no revierte el borrado
+	if s.channelPurger != nil {
+		if err := s.channelPurger.PurgeChannel(ctx, tenantID, channelID); err != nil {
+			logx.Warn("failed to purge conversations of channel %s: %v", channelID, err)
+		}
+	}
+	if s.suppressionRepo != nil {
+		if err := s.suppressionRepo.RemoveByChannel(ctx, tenantID, channelID); err != nil {
+			logx.Warn("failed to remove suppressions of channel %s: %v", channelID, err)
+		}
+	}
+	return nil
+}
+
+// ListChannels lista canales de un tenant con paginación y filtros
+func (s *ChannelService) ListChannels(ctx context.Context, req channels.ListChannelsRequest) (channels.ChannelListResponse, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	return s.channelRepo.List(ctx, req)
+}
+
+// GetChannelFeatures obtiene las características soportadas por un canal
+func (s *ChannelService) GetChannelFeatures(ctx context.Context, channelID kernel.ChannelID, tenantID kernel.TenantID) (*channels.ChannelFeaturesResponse, error) {
+	channel, err := s.channelRepo.FindByID(ctx, channelID, tenantID)
+	if err != nil {
+		return nil, channels.ErrChannelNotFound().WithDetail("channel_id", channelID.String())
+	}
+
+	// Preferir las features del adapter registrado
+	if adapter, err := s.channelManager.GetAdapter(channelID); err == nil {
+		return &channels.ChannelFeaturesResponse{
+			ChannelType: channel.Type,
+			Features:    adapter.GetFeatures(),
+		}, nil
+	}
+
+	features, err := channel.GetFeatures()
+	if err != nil {
+		return nil, err
+	}
+
+	return &channels.ChannelFeaturesResponse{
+		ChannelType: channel.Type,
+		Features:    features,
+	}, nil
+}
+
+// ValidateChannelConfig valida un config sin crear el canal
+func (s *ChannelService) ValidateChannelConfig(req channels.ValidateChannelConfigRequest) *channels.ValidateChannelConfigResponse {
+	response := &channels.ValidateChannelConfigResponse{IsValid: true}
+
+	if req.Config == nil {
+		response.IsValid = false
+		response.Errors = append(response.Errors, "config is required")
+		return response
+	}
+
+	if req.Config.GetType() != req.Type {
+		response.IsValid = false
+		response.Errors = append(response.Errors, fmt.Sprintf("config type %s does not match channel type %s", req.Config.GetType(), req.Type))
+	}
+
+	if err := req.Config.Validate(); err != nil {
+		response.IsValid = false
+		response.Errors = append(response.Errors, errorReason(err))
+	}
+
+	if req.Config.GetProvider() == "" {
+		response.Warnings = append(response.Warnings, "provider is not set")
+	}
+
+	return response
+}
+
+// GetWebhookURL obtiene la URL de webhook de un canal
+func (s *ChannelService) GetWebhookURL(ctx context.Context, channelID kernel.ChannelID, tenantID kernel.TenantID) (*channels.ChannelWebhookResponse, error) {
+	channel, err := s.channelRepo.FindByID(ctx, channelID, tenantID)
+	if err != nil {
+		return nil, channels.ErrChannelNotFound().WithDetail("channel_id", channelID.String())
+	}
+
+	return &channels.ChannelWebhookResponse{
+		ChannelID:   channel.ID,
+		ChannelType: channel.Type,
+		WebhookURL:  channel.WebhookURL,
+	}, nil
+}
+
+// RegenerateWebhookURL vuelve a generar la URL de webhook (p.ej. tras cambiar APP_BASE_URL)
+func (s *ChannelService) RegenerateWebhookURL(ctx context.Context, channelID kernel.ChannelID, tenantID kernel.TenantID) (*channels.ChannelWebhookResponse, error) {
+	channel, err := s.channelRepo.FindByID(ctx, channelID, tenantID)
+	if err != nil {
+		return nil, channels.ErrChannelNotFound().WithDetail("channel_id", channelID.String())
+	}
+
+	channel.WebhookURL = s.generateWebhookURL(channel.TenantID, channel.ID, channel.Type)
+	channel.UpdatedAt = time.Now()
+
+	if err := s.channelRepo.Save(ctx, *channel); err != nil {
+		return nil, errx.Wrap(err, "failed to update webhook url", errx.TypeInternal)
+	}
+
+	return &channels.ChannelWebhookResponse{
+		ChannelID:   channel.ID,
+		ChannelType: channel.Type,
+		WebhookURL:  channel.WebhookURL,
+	}, nil
 }
 
 // ============================================================================
@@ -243,7 +409,7 @@ func (s *ChannelService) DeleteChannel(ctx context.Context, channelID kernel.Cha
 // SendMessage envía un mensaje a través de un canal
 func (s *ChannelService) SendMessage(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, msg channels.OutgoingMessage) (*channels.SendMessageResponse, error) {
 	// Verificar que el canal existe y está activo
-	channel, err := s.channelRepo.FindByID(ctx, channelID, msg.Metadata["tenant_id"].(kernel.TenantID))
+	channel, err := s.channelRepo.FindByID(ctx, channelID, tenantID)
 	if err != nil {
 		return nil, channels.ErrChannelNotFound().WithDetail("channel_id", channelID.String())
 	}
@@ -271,6 +437,74 @@ func (s *ChannelService) SendMessage(ctx context.Context, tenantID kernel.Tenant
 	}, nil
 }
 
+// SendManualMessage envía un mensaje de un operador a un contacto.
+// Respeta la lista de supresión y marca el mensaje con origin=manual.
+func (s *ChannelService) SendManualMessage(
+	ctx context.Context,
+	tenantID kernel.TenantID,
+	userID kernel.UserID,
+	req channels.SendMessageRequest,
+) (*channels.SendMessageResponse, error) {
+	if req.RecipientID == "" {
+		return nil, channels.ErrInvalidRecipient().WithDetail("reason", "recipient_id is required")
+	}
+
+	if req.TemplateID != "" {
+		req.Content.Type = "template"
+	}
+	if err := validateManualContent(req.Content, req.TemplateID); err != nil {
+		return nil, err
+	}
+
+	if s.suppressionRepo != nil {
+		suppressed, err := s.suppressionRepo.IsSuppressed(ctx, tenantID, req.ChannelID, req.RecipientID)
+		if err != nil {
+			return nil, err
+		}
+		if suppressed {
+			return nil, channels.ErrRecipientSuppressed().
+				WithDetail("channel_id", req.ChannelID.String()).
+				WithDetail("recipient_id", req.RecipientID)
+		}
+	}
+
+	msg := channels.OutgoingMessage{
+		RecipientID: req.RecipientID,
+		Content:     req.Content,
+		ReplyToID:   req.ReplyToID,
+		TemplateID:  req.TemplateID,
+		Variables:   req.Variables,
+		Metadata: map[string]any{
+			"origin":    string(conversation.OriginManual),
+			"sent_by":   userID.String(),
+			"timestamp": time.Now().Unix(),
+		},
+	}
+
+	return s.SendMessage(ctx, tenantID, req.ChannelID, msg)
+}
+
+// validateManualContent verifica que el contenido tenga algo que enviar
+func validateManualContent(content channels.MessageContent, templateID string) error {
+	switch content.Type {
+	case "", "text":
+		if content.Text == "" {
+			return channels.ErrInvalidMessageFormat().WithDetail("reason", "text is required")
+		}
+	case "image", "audio", "video", "document":
+		if content.MediaURL == "" && len(content.Attachments) == 0 {
+			return channels.ErrInvalidMessageFormat().WithDetail("reason", "media_url or attachments required")
+		}
+	case "template":
+		if templateID == "" {
+			return channels.ErrInvalidMessageFormat().WithDetail("reason", "template_id is required")
+		}
+	default:
+		return channels.ErrInvalidMessageFormat().WithDetail("type", content.Type)
+	}
+	return nil
+}
+
 // TestChannel prueba la conexión de un canal
 func (s *ChannelService) TestChannel(ctx context.Context, channelID kernel.ChannelID, tenantID kernel.TenantID) (*channels.TestChannelResponse, error) {
 	channel, err := s.channelRepo.FindByID(ctx, channelID, tenantID)
@@ -278,8 +512,13 @@ func (s *ChannelService) TestChannel(ctx context.Context, channelID kernel.Chann
 		return nil, channels.ErrChannelNotFound().WithDetail("channel_id", channelID.String())
 	}
 
-	// Obtener adapter
+	// Obtener adapter (registrando el canal si aún no está en memoria)
 	adapter, err := s.channelManager.GetAdapter(channelID)
+	if err != nil {
+		if regErr := s.channelManager.RegisterChannel(ctx, *channel); regErr == nil {
+			adapter, err = s.channelManager.GetAdapter(channelID)
+		}
+	}
 	if err != nil {
 		return &channels.TestChannelResponse{
 			Success: false,
@@ -315,6 +554,66 @@ func (s *ChannelService) TestChannel(ctx context.Context, channelID kernel.Chann
 	}, nil
 }
 
+// RenderMessage muestra cómo llegaría un borrador al canal sin enviarlo:
+// variables reemplazadas, contenido que el canal no soporta, límites y, si
+// el adapter implementa MessageRenderer, la petición exacta al proveedor
+func (s *ChannelService) RenderMessage(ctx context.Context, channelID kernel.ChannelID, tenantID kernel.TenantID, msg channels.OutgoingMessage) (*channels.RenderMessageResponse, error) {
+	channel, err := s.channelRepo.FindByID(ctx, channelID, tenantID)
+	if err != nil {
+		return nil, channels.ErrChannelNotFound().WithDetail("channel_id", channelID.String())
+	}
+
+	// Obtener adapter (registrando el canal si aún no está en memoria). Sin
+	// adapter se previsualiza con las features del config.
+	adapter, err := s.channelManager.GetAdapter(channelID)
+	if err != nil {
+		if regErr := s.channelManager.RegisterChannel(ctx, *channel); regErr == nil {
+			adapter, err = s.channelManager.GetAdapter(channelID)
+		}
+	}
+
+	var features channels.ChannelFeatures
+	if err == nil {
+		features = adapter.GetFeatures()
+	} else if features, err = channel.GetFeatures(); err != nil {
+		return nil, err
+	}
+
+	rendered, changes := channels.SubstituteVariables(msg)
+	response := &channels.RenderMessageResponse{
+		ChannelType: channel.Type,
+		Message:     rendered,
+		Changes:     changes,
+		Sendable:    true,
+	}
+
+	if feature := channels.UnsupportedFeature(features, rendered.Content); feature != "" {
+		response.Sendable = false
+		response.Error = channels.ErrFeatureNotSupported().
+			WithDetail("feature", feature).
+			WithDetail("channel_type", string(channel.Type)).
+			Error()
+		return response, nil
+	}
+	response.Changes = append(response.Changes, channels.CheckLimits(features, rendered.Content)...)
+
+	if renderer, ok := adapter.(channels.MessageRenderer); ok {
+		providerMessage, err := renderer.RenderMessage(rendered)
+		if err != nil {
+			response.Sendable = false
+			response.Error = err.Error()
+			return response, nil
+		}
+		response.Payload = providerMessage.Payload
+		response.Changes = append(response.Changes, providerMessage.Changes...)
+	}
+
+	if response.Changes == nil {
+		response.Changes = []channels.RenderChange{}
+	}
+	return response, nil
+}
+
 // ============================================================================
 // Bulk Operations
 // ============================================================================
@@ -381,7 +680,22 @@ func (s *ChannelService) generateWebhookURL(tenantID kernel.TenantID, channelID
 		return fmt.Sprintf("%s/webhooks/instagram/%s/%s", baseURL, tenantID, channelID)
 	case channels.ChannelTypeTelegram:
 		return fmt.Sprintf("%s/webhooks/telegram/%s/%s", baseURL, tenantID, channelID)
+	case channels.ChannelTypeRCS:
+		return fmt.Sprintf("%s/webhooks/rcs/%s/%s", baseURL, tenantID, channelID)
+	case channels.ChannelTypeApple:
+		// El gateway de Apple agrega /message a la URL del proveedor
+		return fmt.Sprintf("%s/webhooks/apple/%s/%s", baseURL, tenantID, channelID)
 	default:
 		return fmt.Sprintf("%s/webhooks/%s/%s/%s", baseURL, channelType, tenantID, channelID)
 	}
 }
+
+// errorReason extrae el detalle "reason" de un error de validación si existe
+func errorReason(err error) string {
+	if e, ok := err.(*errx.Error); ok {
+		if reason, ok := e.Details["reason"].(string); ok && reason != "" {
+			return reason
+		}
+	}
+	return err.Error()
+}
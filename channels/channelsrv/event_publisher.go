@@ -0,0 +1,26 @@
+package channelsrv
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/relay/channels"
+)
+
+// MessageEventPublisher publica message.received por cada mensaje entrante
+// que pasa el filtro, para que analítica y webhooks no dependan del handler
+type MessageEventPublisher struct {
+	bus eventx.EventBus
+}
+
+var _ channels.InboundListener = (*MessageEventPublisher)(nil)
+
+// NewMessageEventPublisher crea el publicador de mensajes entrantes
+func NewMessageEventPublisher(bus eventx.EventBus) *MessageEventPublisher {
+	return &MessageEventPublisher{bus: bus}
+}
+
+// OnInboundMessage implementa channels.InboundListener
+func (p *MessageEventPublisher) OnInboundMessage(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage) {
+	channels.PublishMessageEvent(ctx, p.bus, channels.EventMessageReceived, channels.NewReceivedEvent(channel, msg))
+}
@@ -0,0 +1,137 @@
+package channelsrv
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Abraxas-365/craftable/logx"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// pausedChannelsSetting guarda en tenant_config los canales deshabilitados por
+// la suspensión, para reactivar solo esos y no los que el tenant ya tenía apagados
+const pausedChannelsSetting = "lifecycle.paused_channels"
+
+// TenantLifecycleHook deshabilita los canales de un tenant suspendido o borrado
+type TenantLifecycleHook struct {
+	channelRepo      channels.ChannelRepository
+	channelManager   channels.ChannelManager
+	tenantConfigRepo tenant.TenantConfigRepository
+}
+
+// NewTenantLifecycleHook crea el hook de canales del ciclo de vida del tenant
+func NewTenantLifecycleHook(
+	channelRepo channels.ChannelRepository,
+	channelManager channels.ChannelManager,
+	tenantConfigRepo tenant.TenantConfigRepository,
+) *TenantLifecycleHook {
+	return &TenantLifecycleHook{
+		channelRepo:      channelRepo,
+		channelManager:   channelManager,
+		tenantConfigRepo: tenantConfigRepo,
+	}
+}
+
+// OnTenantLifecycle implementa tenant.LifecycleHook
+func (h *TenantLifecycleHook) OnTenantLifecycle(ctx context.Context, tenantID kernel.TenantID, event tenant.LifecycleEvent) error {
+	switch event {
+	case tenant.LifecycleSuspended:
+		return h.pause(ctx, tenantID)
+	case tenant.LifecycleReactivated:
+		return h.resume(ctx, tenantID)
+	case tenant.LifecycleDeleted:
+		return h.unregisterAll(ctx, tenantID)
+	}
+	return nil
+}
+
+// pause deshabilita los canales activos y los saca del manager
+func (h *TenantLifecycleHook) pause(ctx context.Context, tenantID kernel.TenantID) error {
+	active, err := h.channelRepo.FindActive(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if len(active) == 0 {
+		return nil
+	}
+
+	ids := make([]kernel.ChannelID, len(active))
+	values := make([]string, len(active))
+	for i, ch := range active {
+		ids[i] = ch.ID
+		values[i] = ch.ID.String()
+	}
+
+	// Recordar antes de deshabilitar: si falla el guardado no se pierde el estado
+	if err := h.tenantConfigRepo.SaveSetting(ctx, tenantID, pausedChannelsSetting, strings.Join(values, ",")); err != nil {
+		return err
+	}
+
+	if err := h.channelRepo.BulkUpdateStatus(ctx, ids, tenantID, false); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		h.channelManager.UnregisterChannel(id)
+	}
+
+	logx.Info("Paused %d channels of suspended tenant %s", len(ids), tenantID)
+	return nil
+}
+
+// resume reactiva solo los canales pausados por la suspensión
+func (h *TenantLifecycleHook) resume(ctx context.Context, tenantID kernel.TenantID) error {
+	config, err := h.tenantConfigRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	paused := config[pausedChannelsSetting]
+	if paused == "" {
+		return nil
+	}
+
+	var resumed []*channels.Channel
+	var ids []kernel.ChannelID
+	for _, value := range strings.Split(paused, ",") {
+		// Canales borrados durante la suspensión se omiten
+		channel, err := h.channelRepo.FindByID(ctx, kernel.ChannelID(value), tenantID)
+		if err != nil {
+			continue
+		}
+		resumed = append(resumed, channel)
+		ids = append(ids, channel.ID)
+	}
+
+	if len(ids) > 0 {
+		if err := h.channelRepo.BulkUpdateStatus(ctx, ids, tenantID, true); err != nil {
+			return err
+		}
+	}
+
+	for _, channel := range resumed {
+		channel.Activate()
+		if err := h.channelManager.RegisterChannel(ctx, *channel); err != nil {
+			logx.Warn("Failed to register channel %s after tenant reactivation: %v", channel.ID, err)
+		}
+	}
+
+	logx.Info("Resumed %d channels of reactivated tenant %s", len(ids), tenantID)
+	return h.tenantConfigRepo.DeleteSetting(ctx, tenantID, pausedChannelsSetting)
+}
+
+// unregisterAll libera los adapters en memoria; los registros se borran en cascada
+func (h *TenantLifecycleHook) unregisterAll(ctx context.Context, tenantID kernel.TenantID) error {
+	all, err := h.channelRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	for _, ch := range all {
+		h.channelManager.UnregisterChannel(ch.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package channelsrv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// reprocessBatchLimit máximo de eventos que reprocesa una sola llamada
+const reprocessBatchLimit = 500
+
+// WebhookEventService guarda los eventos de webhook no soportados y los
+// reprocesa cuando un adapter actualizado los entiende
+type WebhookEventService struct {
+	repo           channels.RawWebhookEventRepository
+	channelRepo    channels.ChannelRepository
+	channelManager channels.ChannelManager
+	metrics        *channels.WebhookEventMetrics
+	dispatcher     channels.InboundDispatcher
+}
+
+var _ channels.WebhookEventRecorder = (*WebhookEventService)(nil)
+
+// NewWebhookEventService crea el servicio; metrics puede ser nil
+func NewWebhookEventService(
+	repo channels.RawWebhookEventRepository,
+	channelRepo channels.ChannelRepository,
+	channelManager channels.ChannelManager,
+	metrics *channels.WebhookEventMetrics,
+) *WebhookEventService {
+	return &WebhookEventService{
+		repo:           repo,
+		channelRepo:    channelRepo,
+		channelManager: channelManager,
+		metrics:        metrics,
+	}
+}
+
+// SetDispatcher define a quién se entregan los mensajes reprocesados
+func (s *WebhookEventService) SetDispatcher(dispatcher channels.InboundDispatcher) {
+	s.dispatcher = dispatcher
+}
+
+// WebhookReprocessSummary resultado de un reproceso en lote
+type WebhookReprocessSummary struct {
+	Attempted   int `json:"attempted"`
+	Reprocessed int `json:"reprocessed"`
+	Pending     int `json:"pending"`
+}
+
+// RecordUnsupported guarda un evento por cada tipo no soportado del webhook.
+// Los fallos se registran en el log y nunca bloquean la respuesta al proveedor.
+func (s *WebhookEventService) RecordUnsupported(ctx context.Context, channel *channels.Channel, eventTypes []string, payload []byte) {
+	raw := channels.DecodeRawPayload(payload)
+	if raw == nil {
+		log.Printf("⚠️  Unsupported webhook event on channel %s is not a JSON object, dropping", channel.ID.String())
+		return
+	}
+
+	now := time.Now()
+	for _, eventType := range eventTypes {
+		s.metrics.RecordReceived(channel.Type, eventType)
+
+		event := channels.RawWebhookEvent{
+			ID:        uuid.NewString(),
+			TenantID:  channel.TenantID,
+			ChannelID: channel.ID,
+			Provider:  channel.Type,
+			EventType: eventType,
+			Payload:   raw,
+			Status:    channels.WebhookEventPending,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := s.repo.Save(ctx, event); err != nil {
+			log.Printf("❌ Failed to store unsupported %s event %s for channel %s: %v",
+				channel.Type, eventType, channel.ID.String(), err)
+			continue
+		}
+
+		log.Printf("🗃️  Stored unsupported %s webhook event %s for channel %s", channel.Type, eventType, channel.ID.String())
+	}
+}
+
+// List retorna los eventos del tenant, del más antiguo al más nuevo
+func (s *WebhookEventService) List(ctx context.Context, req channels.ListWebhookEventsRequest) (channels.WebhookEventListResponse, error) {
+	return s.repo.List(ctx, req)
+}
+
+// Get retorna un evento del tenant con su payload
+func (s *WebhookEventService) Get(ctx context.Context, id string, tenantID kernel.TenantID) (*channels.RawWebhookEvent, error) {
+	return s.repo.FindByID(ctx, id, tenantID)
+}
+
+// Metrics retorna los contadores por proveedor y tipo de evento
+func (s *WebhookEventService) Metrics() map[string]channels.WebhookEventCounts {
+	return s.metrics.Snapshot()
+}
+
+// Reprocess vuelve a pasar un evento pendiente por el adapter de su canal y
+// registra el resultado. Si el adapter sigue sin soportarlo, el evento queda
+// PENDING con el motivo; no es un error.
+func (s *WebhookEventService) Reprocess(ctx context.Context, id string, tenantID kernel.TenantID) (*channels.RawWebhookEvent, error) {
+	event, err := s.repo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status == channels.WebhookEventReprocessed {
+		return nil, channels.ErrWebhookEventReprocessed().WithDetail("webhook_event_id", id)
+	}
+
+	reprocessErr := s.replay(ctx, event)
+
+	now := time.Now()
+	event.ReprocessCount++
+	event.LastReprocessAt = &now
+	event.UpdatedAt = now
+	if reprocessErr != nil {
+		log.Printf("⚠️  Webhook event %s reprocess %d failed: %v", event.ID, event.ReprocessCount, reprocessErr)
+		event.Error = reprocessErr.Error()
+	} else {
+		log.Printf("✅ Webhook event %s reprocessed", event.ID)
+		event.Status = channels.WebhookEventReprocessed
+		event.Error = ""
+		s.metrics.RecordReprocessed(event.Provider, event.EventType)
+	}
+
+	if err := s.repo.Save(ctx, *event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// ReprocessPending reprocesa los eventos pendientes que coinciden con el
+// filtro, para rellenar lo perdido después de actualizar un adapter
+func (s *WebhookEventService) ReprocessPending(ctx context.Context, req channels.ListWebhookEventsRequest) (WebhookReprocessSummary, error) {
+	pending := channels.WebhookEventPending
+	req.Status = &pending
+	req.PaginationOptions = storex.PaginationOptions{Page: 1, PageSize: reprocessBatchLimit}
+
+	// Se leen antes de reprocesar: los que se resuelven salen del filtro
+	events, err := s.repo.List(ctx, req)
+	if err != nil {
+		return WebhookReprocessSummary{}, err
+	}
+
+	var summary WebhookReprocessSummary
+	for _, event := range events.Data {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		summary.Attempted++
+		result, err := s.Reprocess(ctx, event.ID, req.TenantID)
+		if err != nil {
+			log.Printf("❌ Failed to reprocess webhook event %s: %v", event.ID, err)
+			summary.Pending++
+			continue
+		}
+		if result.Status == channels.WebhookEventReprocessed {
+			summary.Reprocessed++
+		} else {
+			summary.Pending++
+		}
+	}
+
+	return summary, nil
+}
+
+// replay parsea el payload guardado con el adapter actual del canal. Solo
+// entrega el mensaje cuando el adapter ya no marca el tipo como no soportado,
+// para no repetir mensajes que el webhook original sí entregó.
+func (s *WebhookEventService) replay(ctx context.Context, event *channels.RawWebhookEvent) error {
+	channel, err := s.channelRepo.FindByID(ctx, event.ChannelID, event.TenantID)
+	if err != nil {
+		return err
+	}
+
+	adapter, err := s.adapterFor(ctx, channel)
+	if err != nil {
+		return err
+	}
+	replayer, ok := adapter.(channels.WebhookReplayer)
+	if !ok {
+		return fmt.Errorf("%s adapter cannot reprocess webhooks", channel.Type)
+	}
+
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return err
+	}
+
+	if classifier, ok := adapter.(channels.WebhookEventClassifier); ok {
+		if slices.Contains(classifier.UnsupportedEvents(payload), event.EventType) {
+			return fmt.Errorf("%s adapter still does not support %s events", channel.Type, event.EventType)
+		}
+	}
+
+	msg, err := replayer.ParseWebhook(payload)
+	if err != nil {
+		return err
+	}
+
+	// El adapter ahora reconoce el evento pero no produce mensaje (un aviso de estado, por ejemplo)
+	if msg == nil {
+		return nil
+	}
+	if s.dispatcher == nil {
+		return fmt.Errorf("no inbound dispatcher configured")
+	}
+
+	s.dispatcher.DispatchInbound(ctx, channel, msg)
+	return nil
+}
+
+// adapterFor obtiene el adapter del canal, registrándolo si aún no está en memoria
+func (s *WebhookEventService) adapterFor(ctx context.Context, channel *channels.Channel) (channels.ChannelAdapter, error) {
+	if adapter, err := s.channelManager.GetAdapter(channel.ID); err == nil {
+		return adapter, nil
+	}
+	if err := s.channelManager.RegisterChannel(ctx, *channel); err != nil {
+		return nil, err
+	}
+	return s.channelManager.GetAdapter(channel.ID)
+}
@@ -1,6 +1,9 @@
 package channels
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/Abraxas-365/craftable/storex"
 	"github.com/Abraxas-365/relay/pkg/kernel"
 )
@@ -24,10 +27,11 @@ type IncomingMessage struct {
 	MessageID  kernel.MessageID `json:"message_id"`
 	ChannelID  kernel.ChannelID `json:"channel_id"`
 	SenderID   string           `json:"sender_id"`
+	SenderName string           `json:"sender_name,omitempty"` // Nombre de perfil, si el proveedor lo envía
 	Content    MessageContent   `json:"content"`
 	Timestamp  int64            `json:"timestamp"`
 	Metadata   map[string]any   `json:"metadata,omitempty"`
-	RawPayload map[string]any   `json:"raw_payload,omitempty"`
+	RawPayload map[string]any   `json:"raw_payload,omitempty"` // Evento del proveedor tal cual llegó; solo para depuración
 }
 
 // MessageContent contenido del mensaje
@@ -41,7 +45,10 @@ type MessageContent struct {
 	Attachments []Attachment   `json:"attachments,omitempty"`
 	Location    *Location      `json:"location,omitempty"`
 	Contact     *Contact       `json:"contact,omitempty"`
+	Contacts    []Contact      `json:"contacts,omitempty"` // Tarjetas de contacto (vCard); WhatsApp admite varias por mensaje
 	Interactive *Interactive   `json:"interactive,omitempty"`
+	Postback    *Postback      `json:"postback,omitempty"` // Botón o fila de lista elegida por el usuario
+	ReplyTo     *ReplyTo       `json:"reply_to,omitempty"` // Mensaje que el usuario citó al responder
 	Metadata    map[string]any `json:"metadata,omitempty"`
 }
 
@@ -53,6 +60,11 @@ type Attachment struct {
 	Filename string `json:"filename,omitempty"`
 	Size     int64  `json:"size,omitempty"`
 	Caption  string `json:"caption,omitempty"`
+
+	// Medios entrantes
+	ProviderMediaID string `json:"provider_media_id,omitempty"` // Proveedores que entregan un ID en vez de URL (WhatsApp)
+	AttachmentID    string `json:"attachment_id,omitempty"`     // Copia almacenada por el servicio de adjuntos
+	ScanStatus      string `json:"scan_status,omitempty"`       // Resultado del escaneo antivirus
 }
 
 // Location ubicación geográfica
@@ -66,19 +78,39 @@ type Location struct {
 // Contact contacto compartido
 type Contact struct {
 	Name         string `json:"name"`
+	FirstName    string `json:"first_name,omitempty"`
+	LastName     string `json:"last_name,omitempty"`
 	PhoneNumber  string `json:"phone_number,omitempty"`
 	Email        string `json:"email,omitempty"`
 	Organization string `json:"organization,omitempty"`
+	Title        string `json:"title,omitempty"` // Cargo en la organización
+	URL          string `json:"url,omitempty"`
 }
 
 // Interactive mensaje interactivo (botones, listas, etc)
 type Interactive struct {
-	Type    string   `json:"type"` // button, list, template
-	Header  string   `json:"header,omitempty"`
-	Body    string   `json:"body"`
-	Footer  string   `json:"footer,omitempty"`
-	Buttons []Button `json:"buttons,omitempty"`
-	Items   []Item   `json:"items,omitempty"`
+	Type       string     `json:"type"` // button, list, template, time_picker, rich_link
+	Header     string     `json:"header,omitempty"`
+	Body       string     `json:"body"`
+	Footer     string     `json:"footer,omitempty"`
+	Buttons    []Button   `json:"buttons,omitempty"`
+	Items      []Item     `json:"items,omitempty"`       // Lista de una sola sección
+	Sections   []Section  `json:"sections,omitempty"`    // Lista agrupada en secciones
+	ButtonText string     `json:"button_text,omitempty"` // Texto del botón que abre la lista
+	TimeSlots  []TimeSlot `json:"time_slots,omitempty"`  // Horarios a elegir (time_picker)
+	URL        string     `json:"url,omitempty"`         // Página a previsualizar (rich_link)
+}
+
+// ListItems devuelve todas las filas de la lista, de Sections o de Items
+func (i *Interactive) ListItems() []Item {
+	if len(i.Sections) == 0 {
+		return i.Items
+	}
+	var items []Item
+	for _, section := range i.Sections {
+		items = append(items, section.Items...)
+	}
+	return items
 }
 
 // Button botón interactivo
@@ -97,6 +129,34 @@ type Item struct {
 	Description string `json:"description,omitempty"`
 }
 
+// Section sección de una lista
+type Section struct {
+	Title string `json:"title,omitempty"`
+	Items []Item `json:"items"`
+}
+
+// TimeSlot horario ofrecido en un selector de horarios
+type TimeSlot struct {
+	ID        string    `json:"id"`
+	StartTime time.Time `json:"start_time"`
+	Duration  int       `json:"duration_seconds"`
+}
+
+// ReplyTo referencia al mensaje citado en una respuesta
+type ReplyTo struct {
+	MessageID string `json:"message_id"`          // ID del proveedor del mensaje citado
+	SenderID  string `json:"sender_id,omitempty"` // Autor del mensaje citado, si el proveedor lo informa
+}
+
+// Postback selección normalizada de un botón, respuesta rápida o fila de
+// lista, igual en todos los canales
+type Postback struct {
+	Type        string `json:"type"` // button, list, quick_reply, time_slot
+	ID          string `json:"id"`   // ID del botón o fila definido por el workflow
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
 // ============================================================================
 // Request DTOs
 // ============================================================================
@@ -108,14 +168,49 @@ type CreateChannelRequest struct {
 	Description string          `json:"description"`
 	Type        ChannelType     `json:"type" validate:"required"`
 	Config      ChannelConfig   `json:"config" validate:"required"`
+
+	// Environment por defecto: sandbox para TEST_HTTP, production para el resto
+	Environment kernel.Environment `json:"environment,omitempty"`
+}
+
+// UnmarshalJSON deserializa el config según el tipo de canal
+func (r *CreateChannelRequest) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		TenantID    kernel.TenantID    `json:"tenant_id"`
+		Name        string             `json:"name"`
+		Description string             `json:"description"`
+		Type        ChannelType        `json:"type"`
+		Config      json.RawMessage    `json:"config"`
+		Environment kernel.Environment `json:"environment"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	r.TenantID = raw.TenantID
+	r.Name = raw.Name
+	r.Description = raw.Description
+	r.Type = raw.Type
+	r.Environment = raw.Environment
+
+	if len(raw.Config) == 0 {
+		return nil
+	}
+
+	config, err := ParseChannelConfig(raw.Type, raw.Config)
+	if err != nil {
+		return err
+	}
+	r.Config = config
+	return nil
 }
 
 // UpdateChannelRequest request para actualizar un canal
 type UpdateChannelRequest struct {
-	Name        *string        `json:"name,omitempty"`
-	Description *string        `json:"description,omitempty"`
-	Config      *ChannelConfig `json:"config,omitempty"`
-	IsActive    *bool          `json:"is_active,omitempty"`
+	Name        *string         `json:"name,omitempty"`
+	Description *string         `json:"description,omitempty"`
+	Config      json.RawMessage `json:"config,omitempty"` // Se deserializa según el tipo del canal
+	IsActive    *bool           `json:"is_active,omitempty"`
 }
 
 // SendMessageRequest request para enviar mensaje
@@ -151,11 +246,12 @@ type ProcessWebhookRequest struct {
 type ListChannelsRequest struct {
 	storex.PaginationOptions
 
-	TenantID kernel.TenantID `json:"tenant_id" validate:"required"`
-	Type     *ChannelType    `json:"type,omitempty"`
-	IsActive *bool           `json:"is_active,omitempty"`
-	Provider *string         `json:"provider,omitempty"`
-	Search   string          `json:"search,omitempty"`
+	TenantID    kernel.TenantID     `json:"tenant_id" validate:"required"`
+	Type        *ChannelType        `json:"type,omitempty"`
+	IsActive    *bool               `json:"is_active,omitempty"`
+	Provider    *string             `json:"provider,omitempty"`
+	Environment *kernel.Environment `json:"environment,omitempty"`
+	Search      string              `json:"search,omitempty"`
 }
 
 func (lcr ListChannelsRequest) GetOffset() int {
@@ -164,6 +260,21 @@ func (lcr ListChannelsRequest) GetOffset() int {
 	return (page - 1) * size
 }
 
+// ListWebhookEventsRequest request para listar eventos de webhook no soportados
+type ListWebhookEventsRequest struct {
+	storex.PaginationOptions
+
+	TenantID  kernel.TenantID     `json:"tenant_id" validate:"required"`
+	ChannelID *kernel.ChannelID   `json:"channel_id,omitempty"`
+	Provider  *ChannelType        `json:"provider,omitempty"`
+	EventType *string             `json:"event_type,omitempty"`
+	Status    *WebhookEventStatus `json:"status,omitempty"`
+}
+
+func (r ListWebhookEventsRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
 // ============================================================================
 // Response DTOs
 // ============================================================================
@@ -178,6 +289,9 @@ type ChannelResponse struct {
 // ChannelListResponse lista paginada de canales
 type ChannelListResponse = storex.Paginated[Channel]
 
+// WebhookEventListResponse lista paginada de eventos de webhook no soportados
+type WebhookEventListResponse = storex.Paginated[RawWebhookEvent]
+
 // SendMessageResponse respuesta de envío de mensaje
 type SendMessageResponse struct {
 	Success       bool           `json:"success"`
@@ -295,6 +409,29 @@ type ValidateChannelConfigRequest struct {
 	Config ChannelConfig `json:"config" validate:"required"`
 }
 
+// UnmarshalJSON deserializa el config según el tipo de canal
+func (r *ValidateChannelConfigRequest) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type   ChannelType     `json:"type"`
+		Config json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	r.Type = raw.Type
+	if len(raw.Config) == 0 {
+		return nil
+	}
+
+	config, err := ParseChannelConfig(raw.Type, raw.Config)
+	if err != nil {
+		return err
+	}
+	r.Config = config
+	return nil
+}
+
 // ValidateChannelConfigResponse respuesta de validación
 type ValidateChannelConfigResponse struct {
 	IsValid  bool     `json:"is_valid"`
@@ -302,6 +439,13 @@ type ValidateChannelConfigResponse struct {
 	Warnings []string `json:"warnings,omitempty"`
 }
 
+// ChannelWebhookResponse URL de webhook que se configura en el proveedor
+type ChannelWebhookResponse struct {
+	ChannelID   kernel.ChannelID `json:"channel_id"`
+	ChannelType ChannelType      `json:"channel_type"`
+	WebhookURL  string           `json:"webhook_url"`
+}
+
 // ChannelFeaturesResponse características de un tipo de canal
 type ChannelFeaturesResponse struct {
 	ChannelType ChannelType     `json:"channel_type"`
@@ -31,6 +31,7 @@ var (
 	CodeInvalidMessageFormat = ErrRegistry.Register("INVALID_MESSAGE_FORMAT", errx.TypeValidation, http.StatusBadRequest, "Formato de mensaje inválido")
 	CodeAttachmentTooLarge   = ErrRegistry.Register("ATTACHMENT_TOO_LARGE", errx.TypeValidation, http.StatusRequestEntityTooLarge, "Archivo adjunto muy grande")
 	CodeUnsupportedMediaType = ErrRegistry.Register("UNSUPPORTED_MEDIA_TYPE", errx.TypeValidation, http.StatusUnsupportedMediaType, "Tipo de medio no soportado")
+	CodeRecipientSuppressed  = ErrRegistry.Register("RECIPIENT_SUPPRESSED", errx.TypeBusiness, http.StatusForbidden, "Destinatario en lista de supresión")
 
 	// Provider errors
 	CodeProviderNotConfigured = ErrRegistry.Register("PROVIDER_NOT_CONFIGURED", errx.TypeValidation, http.StatusBadRequest, "Proveedor no configurado")
@@ -39,8 +40,11 @@ var (
 	CodeProviderRateLimited   = ErrRegistry.Register("PROVIDER_RATE_LIMITED", errx.TypeExternal, http.StatusTooManyRequests, "Proveedor limitó la tasa de requests")
 
 	// Webhook errors
-	CodeInvalidWebhookSignature = ErrRegistry.Register("INVALID_WEBHOOK_SIGNATURE", errx.TypeValidation, http.StatusUnauthorized, "Firma de webhook inválida")
-	CodeWebhookProcessingFailed = ErrRegistry.Register("WEBHOOK_PROCESSING_FAILED", errx.TypeInternal, http.StatusInternalServerError, "Procesamiento de webhook falló")
+	CodeInvalidWebhookSignature  = ErrRegistry.Register("INVALID_WEBHOOK_SIGNATURE", errx.TypeValidation, http.StatusUnauthorized, "Firma de webhook inválida")
+	CodeWebhookProcessingFailed  = ErrRegistry.Register("WEBHOOK_PROCESSING_FAILED", errx.TypeInternal, http.StatusInternalServerError, "Procesamiento de webhook falló")
+	CodeWebhookEventNotFound     = ErrRegistry.Register("WEBHOOK_EVENT_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Evento de webhook no encontrado")
+	CodeWebhookEventReprocessed  = ErrRegistry.Register("WEBHOOK_EVENT_REPROCESSED", errx.TypeConflict, http.StatusConflict, "Evento de webhook ya fue reprocesado")
+	CodeInvalidWebhookEventQuery = ErrRegistry.Register("INVALID_WEBHOOK_EVENT_QUERY", errx.TypeValidation, http.StatusBadRequest, "Consulta de eventos de webhook inválida")
 
 	// Feature errors
 	CodeFeatureNotSupported = ErrRegistry.Register("FEATURE_NOT_SUPPORTED", errx.TypeBusiness, http.StatusNotImplemented, "Característica no soportada por el canal")
@@ -96,6 +100,10 @@ func ErrUnsupportedMediaType() *errx.Error {
 	return ErrRegistry.New(CodeUnsupportedMediaType)
 }
 
+func ErrRecipientSuppressed() *errx.Error {
+	return ErrRegistry.New(CodeRecipientSuppressed)
+}
+
 // Provider errors
 func ErrProviderNotConfigured() *errx.Error {
 	return ErrRegistry.New(CodeProviderNotConfigured)
@@ -122,6 +130,18 @@ func ErrWebhookProcessingFailed() *errx.Error {
 	return ErrRegistry.New(CodeWebhookProcessingFailed)
 }
 
+func ErrWebhookEventNotFound() *errx.Error {
+	return ErrRegistry.New(CodeWebhookEventNotFound)
+}
+
+func ErrWebhookEventReprocessed() *errx.Error {
+	return ErrRegistry.New(CodeWebhookEventReprocessed)
+}
+
+func ErrInvalidWebhookEventQuery() *errx.Error {
+	return ErrRegistry.New(CodeInvalidWebhookEventQuery)
+}
+
 // Feature errors
 func ErrFeatureNotSupported() *errx.Error {
 	return ErrRegistry.New(CodeFeatureNotSupported)
@@ -0,0 +1,151 @@
+package channels
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Eventos de mensajes
+// ============================================================================
+
+// Tipos de evento que se publican en el event bus por cada mensaje
+const (
+	EventMessageReceived = "message.received"
+	EventResponseSent    = "response.sent"
+	EventMessageStatus   = "message.status"
+)
+
+// MessageEvent mensaje recibido o enviado por un canal. No lleva el texto:
+// los consumidores que lo necesiten lo leen del historial.
+type MessageEvent struct {
+	TenantID       kernel.TenantID  `json:"tenant_id"`
+	ChannelID      kernel.ChannelID `json:"channel_id"`
+	ChannelType    ChannelType      `json:"channel_type"`
+	ConversationID string           `json:"conversation_id"` // Remitente o destinatario
+	MessageID      string           `json:"message_id,omitempty"`
+	ContentType    string           `json:"content_type"`
+	WorkflowID     string           `json:"workflow_id,omitempty"` // Workflow que envió la respuesta
+	Origin         string           `json:"origin,omitempty"`
+	At             time.Time        `json:"at"`
+}
+
+// NewReceivedEvent arma el evento de un mensaje entrante
+func NewReceivedEvent(channel *Channel, msg *IncomingMessage) MessageEvent {
+	return MessageEvent{
+		TenantID:       channel.TenantID,
+		ChannelID:      channel.ID,
+		ChannelType:    channel.Type,
+		ConversationID: msg.SenderID,
+		MessageID:      msg.MessageID.String(),
+		ContentType:    msg.Content.Type,
+		At:             time.Now(),
+	}
+}
+
+// NewSentEvent arma el evento de una respuesta entregada al proveedor
+func NewSentEvent(channel *Channel, msg OutgoingMessage, providerMessageID string) MessageEvent {
+	workflowID, _ := msg.Metadata["workflow_id"].(string)
+	origin, _ := msg.Metadata["origin"].(string)
+	return MessageEvent{
+		TenantID:       channel.TenantID,
+		ChannelID:      channel.ID,
+		ChannelType:    channel.Type,
+		ConversationID: msg.RecipientID,
+		MessageID:      providerMessageID,
+		ContentType:    msg.Content.Type,
+		WorkflowID:     workflowID,
+		Origin:         origin,
+		At:             time.Now(),
+	}
+}
+
+// PublishMessageEvent publica el evento con el tenant, el canal y la
+// conversación como metadatos. Un fallo al publicar nunca afecta al mensaje.
+func PublishMessageEvent(ctx context.Context, bus eventx.EventBus, eventType string, event MessageEvent) {
+	if bus == nil {
+		return
+	}
+
+	opts := eventx.DefaultEventOptions()
+	opts.Source = "channels"
+	opts.Metadata = map[string]any{
+		"tenant_id":       event.TenantID.String(),
+		"channel_id":      event.ChannelID.String(),
+		"conversation_id": event.ConversationID,
+	}
+	if err := bus.Publish(ctx, eventx.NewEvent(eventType, event, opts)); err != nil {
+		log.Printf("⚠️  Failed to publish %s event: %v", eventType, err)
+	}
+}
+
+// ============================================================================
+// Eventos de entrega
+// ============================================================================
+
+// DeliveryStatus estado de un mensaje enviado, informado por el proveedor
+type DeliveryStatus string
+
+const (
+	DeliveryStatusSent      DeliveryStatus = "sent" // Aceptado por la red del proveedor
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusRead      DeliveryStatus = "read"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// DeliveryReceipt aviso del proveedor sobre un mensaje enviado
+type DeliveryReceipt struct {
+	ProviderMessageID string         `json:"provider_message_id"`
+	RecipientID       string         `json:"recipient_id"`
+	Status            DeliveryStatus `json:"status"`
+	Error             string         `json:"error,omitempty"` // Motivo del proveedor cuando falló
+	At                time.Time      `json:"at"`
+}
+
+// StatusEvent cambio de estado de un mensaje enviado por un canal
+type StatusEvent struct {
+	TenantID          kernel.TenantID  `json:"tenant_id"`
+	ChannelID         kernel.ChannelID `json:"channel_id"`
+	ChannelType       ChannelType      `json:"channel_type"`
+	ConversationID    string           `json:"conversation_id"` // Destinatario
+	ProviderMessageID string           `json:"provider_message_id"`
+	Status            DeliveryStatus   `json:"status"`
+	Error             string           `json:"error,omitempty"`
+	At                time.Time        `json:"at"`
+}
+
+// PublishDeliveryReceipts publica un evento message.status por cada aviso.
+// Un fallo al publicar nunca afecta al webhook.
+func PublishDeliveryReceipts(ctx context.Context, bus eventx.EventBus, channel *Channel, receipts []DeliveryReceipt) {
+	if bus == nil {
+		return
+	}
+
+	for _, receipt := range receipts {
+		event := StatusEvent{
+			TenantID:          channel.TenantID,
+			ChannelID:         channel.ID,
+			ChannelType:       channel.Type,
+			ConversationID:    receipt.RecipientID,
+			ProviderMessageID: receipt.ProviderMessageID,
+			Status:            receipt.Status,
+			Error:             receipt.Error,
+			At:                receipt.At,
+		}
+
+		opts := eventx.DefaultEventOptions()
+		opts.Source = "channels"
+		opts.Metadata = map[string]any{
+			"tenant_id":       event.TenantID.String(),
+			"channel_id":      event.ChannelID.String(),
+			"conversation_id": event.ConversationID,
+		}
+		if err := bus.Publish(ctx, eventx.NewEvent(EventMessageStatus, event, opts)); err != nil {
+			log.Printf("⚠️  Failed to publish %s event: %v", EventMessageStatus, err)
+		}
+	}
+}
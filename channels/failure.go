@@ -0,0 +1,71 @@
+package channels
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Clasificación de fallos de proveedores
+// ============================================================================
+
+// FailureClass indica si un fallo del proveedor puede resolverse reintentando
+type FailureClass string
+
+const (
+	FailureAuth        FailureClass = "auth"         // Credenciales inválidas o sin permisos: nunca se reintenta
+	FailureRateLimited FailureClass = "rate_limited" // Reintentar después de RetryAfter
+	FailureTransient   FailureClass = "transient"    // Error de red o 5xx: reintentar
+	FailurePermanent   FailureClass = "permanent"    // La petición es inválida: reintentar no cambia nada
+)
+
+// Claves de detalle con las que los errores tipados exponen la clasificación
+const (
+	DetailFailureClass      = "failure_class"
+	DetailRetryable         = "retryable"
+	DetailRetryAfterSeconds = "retry_after_seconds"
+)
+
+// IsRetryable indica si vale la pena reintentar un fallo de esta clase
+func (c FailureClass) IsRetryable() bool {
+	return c == FailureRateLimited || c == FailureTransient
+}
+
+// Classified agrega la clasificación a un error tipado
+func Classified(err *errx.Error, class FailureClass, retryAfter time.Duration) *errx.Error {
+	err = err.
+		WithDetail(DetailFailureClass, string(class)).
+		WithDetail(DetailRetryable, class.IsRetryable())
+	if retryAfter > 0 {
+		err = err.WithDetail(DetailRetryAfterSeconds, int(retryAfter.Round(time.Second).Seconds()))
+	}
+	return err
+}
+
+// ClassifyError retorna la clasificación de un error de canal. Los errores sin
+// clasificar (validación, canal inactivo, etc.) se consideran permanentes.
+func ClassifyError(err error) FailureClass {
+	var e *errx.Error
+	if errors.As(err, &e) {
+		if class, ok := e.Details[DetailFailureClass].(string); ok {
+			return FailureClass(class)
+		}
+	}
+	return FailurePermanent
+}
+
+// CopyClassification pasa la clasificación de cause a err al envolverlo
+func CopyClassification(err *errx.Error, cause error) *errx.Error {
+	var e *errx.Error
+	if !errors.As(cause, &e) {
+		return err
+	}
+	for _, key := range []string{DetailFailureClass, DetailRetryable, DetailRetryAfterSeconds} {
+		if value, ok := e.Details[key]; ok {
+			err = err.WithDetail(key, value)
+		}
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Abraxas-365/relay/channels"
+)
+
+// Classifier clasifica una respuesta fallida de un proveedor
+type Classifier func(resp *Response) channels.FailureClass
+
+// StatusClassifier clasifica solo por código HTTP: 401/403 autenticación,
+// 429 tasa, 5xx (y 408) transitorio, el resto de 4xx permanente
+func StatusClassifier(resp *Response) channels.FailureClass {
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return channels.FailureAuth
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return channels.FailureRateLimited
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode >= 500:
+		return channels.FailureTransient
+	default:
+		return channels.FailurePermanent
+	}
+}
+
+// Códigos de error de la Graph API de Meta
+// (https://developers.facebook.com/docs/graph-api/guides/error-handling)
+var (
+	graphAuthCodes = map[int]bool{
+		102: true, // Sesión de API inválida
+		190: true, // Token de acceso inválido o expirado
+		10:  true, // Permiso denegado
+	}
+
+	graphRateLimitCodes = map[int]bool{
+		4:      true, // Límite de la aplicación
+		17:     true, // Límite del usuario
+		32:     true, // Límite de la página
+		613:    true, // Límite de llamadas
+		80007:  true, // Límite de mensajería de Instagram
+		130429: true, // Límite de throughput de WhatsApp
+		131048: true, // Límite por spam de WhatsApp
+		131056: true, // Límite por par emisor/destinatario de WhatsApp
+	}
+
+	graphTransientCodes = map[int]bool{
+		1:      true, // Error desconocido
+		2:      true, // Servicio no disponible temporalmente
+		131000: true, // Error genérico de WhatsApp
+		131016: true, // Servicio de WhatsApp sobrecargado
+	}
+)
+
+// GraphAPIClassifier usa el código de error de Meta cuando viene en la
+// respuesta, ya que Meta responde 400 tanto a errores permanentes como a
+// límites de tasa y fallos temporales
+func GraphAPIClassifier(resp *Response) channels.FailureClass {
+	var body struct {
+		Error struct {
+			Code        int  `json:"code"`
+			IsTransient bool `json:"is_transient"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(resp.Body, &body); err != nil || body.Error.Code == 0 {
+		return StatusClassifier(resp)
+	}
+
+	code := body.Error.Code
+	switch {
+	case graphAuthCodes[code] || (code >= 200 && code <= 299): // 2xx = permisos
+		return channels.FailureAuth
+	case graphRateLimitCodes[code]:
+		return channels.FailureRateLimited
+	case graphTransientCodes[code] || body.Error.IsTransient:
+		return channels.FailureTransient
+	default:
+		return StatusClassifier(resp)
+	}
+}
@@ -0,0 +1,323 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/channels"
+)
+
+// ============================================================================
+// Configuración
+// ============================================================================
+
+// Config configuración del cliente HTTP de un proveedor
+type Config struct {
+	Timeout          time.Duration // Por intento
+	MaxRetries       int           // Reintentos además del primer intento
+	RetryBackoff     time.Duration // Espera base; crece linealmente por intento
+	MaxResponseBytes int64         // Respuestas más grandes se rechazan
+	MaxIdleConns     int           // Conexiones ociosas por host
+	ProxyURL         string        // Vacío = HTTP_PROXY/HTTPS_PROXY del entorno
+	MaxRetryAfter    time.Duration // Un Retry-After mayor no se espera: se retorna el fallo
+	Classifier       Classifier    // nil = StatusClassifier
+}
+
+// DefaultConfig configuración de los proveedores sin ajustes propios
+var DefaultConfig = Config{
+	Timeout:          30 * time.Second,
+	MaxRetries:       3,
+	RetryBackoff:     time.Second,
+	MaxResponseBytes: 1 << 20,
+	MaxIdleConns:     32,
+	MaxRetryAfter:    30 * time.Second,
+}
+
+// graphAPIConfig WhatsApp e Instagram comparten la Graph API de Meta
+var graphAPIConfig = func() Config {
+	cfg := DefaultConfig
+	cfg.Classifier = GraphAPIClassifier
+	return cfg
+}()
+
+// providerConfigs ajustes por tipo de canal
+var providerConfigs = map[channels.ChannelType]Config{
+	channels.ChannelTypeWhatsApp:  graphAPIConfig,
+	channels.ChannelTypeInstagram: graphAPIConfig,
+	channels.ChannelTypeTestHTTP: {
+		Timeout:          10 * time.Second,
+		MaxRetries:       1,
+		RetryBackoff:     500 * time.Millisecond,
+		MaxResponseBytes: 1 << 20,
+		MaxIdleConns:     8,
+		MaxRetryAfter:    5 * time.Second,
+	},
+}
+
+// ============================================================================
+// Registro de clientes compartidos
+// ============================================================================
+
+var registry = struct {
+	sync.Mutex
+	proxyURL string
+	clients  map[channels.ChannelType]*Client
+}{clients: make(map[channels.ChannelType]*Client)}
+
+// SetProxy define el proxy de salida de todos los proveedores. Los clientes
+// ya creados se reemplazan en su próximo uso con For.
+func SetProxy(proxyURL string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.proxyURL = proxyURL
+	registry.clients = make(map[channels.ChannelType]*Client)
+}
+
+// For retorna el cliente compartido del proveedor. Todos los adaptadores del
+// mismo tipo comparten el pool de conexiones aunque haya uno por canal.
+func For(channelType channels.ChannelType) *Client {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if client, ok := registry.clients[channelType]; ok {
+		return client
+	}
+
+	cfg, ok := providerConfigs[channelType]
+	if !ok {
+		cfg = DefaultConfig
+	}
+	if cfg.ProxyURL == "" {
+		cfg.ProxyURL = registry.proxyURL
+	}
+
+	client, err := New(channelType, cfg)
+	if err != nil {
+		log.Printf("⚠️  Invalid HTTP proxy for %s, connecting directly: %v", channelType, err)
+		cfg.ProxyURL = ""
+		client, _ = New(channelType, cfg)
+	}
+
+	registry.clients[channelType] = client
+	return client
+}
+
+// ============================================================================
+// Cliente
+// ============================================================================
+
+// Request petición a un proveedor. El cuerpo se guarda como bytes para poder
+// reconstruir la petición en cada intento.
+type Request struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// Response respuesta ya leída (y acotada a MaxResponseBytes)
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Class      channels.FailureClass // Vacío en respuestas exitosas
+	RetryAfter time.Duration         // Retry-After indicado por el proveedor
+}
+
+// IsSuccess indica un código 2xx
+func (r *Response) IsSuccess() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Err construye el error tipado que corresponde a la clasificación de una
+// respuesta fallida; el adaptador agrega los detalles propios del proveedor
+func (r *Response) Err() *errx.Error {
+	var err *errx.Error
+	switch r.Class {
+	case channels.FailureAuth:
+		err = channels.ErrProviderAuthFailed()
+	case channels.FailureRateLimited:
+		err = channels.ErrProviderRateLimited()
+	default:
+		err = channels.ErrProviderAPIError()
+	}
+
+	class := r.Class
+	if class == "" {
+		class = channels.FailurePermanent
+	}
+	return channels.Classified(err, class, r.RetryAfter).WithDetail("status", r.StatusCode)
+}
+
+// Client cliente HTTP con pool de conexiones, reintentos y límite de respuesta
+type Client struct {
+	name   string
+	cfg    Config
+	client *http.Client
+}
+
+// New crea un cliente con su propio transporte
+func New(channelType channels.ChannelType, cfg Config) (*Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = cfg.MaxIdleConns
+	transport.DialContext = (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+
+	if cfg.ProxyURL != "" {
+		proxy, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return &Client{
+		name:   string(channelType),
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout, Transport: transport},
+	}, nil
+}
+
+// Do ejecuta la petición. Los errores de red y las respuestas transitorias o
+// limitadas por tasa se reintentan (respetando Retry-After); las demás
+// respuestas, incluidos los fallos de autenticación, se retornan clasificadas
+// en el primer intento para que el adaptador las interprete.
+func (c *Client) Do(ctx context.Context, req Request) (*Response, error) {
+	var lastErr error
+	var delay time.Duration
+
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := c.wait(ctx, max(delay, time.Duration(attempt)*c.cfg.RetryBackoff)); err != nil {
+				return nil, err
+			}
+		}
+		delay = 0
+
+		resp, err := c.send(ctx, req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			var providerErr *errx.Error
+			if errors.As(err, &providerErr) {
+				return nil, err // Respuesta recibida pero inválida: reintentar no cambia nada
+			}
+			lastErr = err
+			log.Printf("⚠️  %s API request failed (attempt %d/%d): %v", c.name, attempt+1, c.cfg.MaxRetries+1, err)
+			continue
+		}
+
+		if !resp.IsSuccess() {
+			resp.Class = c.classify(resp)
+			resp.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+
+		retry := resp.Class.IsRetryable() && attempt < c.cfg.MaxRetries && resp.RetryAfter <= c.cfg.MaxRetryAfter
+		if !retry {
+			return resp, nil
+		}
+
+		log.Printf("⚠️  %s API returned %d (%s, attempt %d/%d), retrying", c.name, resp.StatusCode, resp.Class, attempt+1, c.cfg.MaxRetries+1)
+		delay = resp.RetryAfter
+	}
+
+	return nil, channels.Classified(channels.ErrProviderAPIError(), channels.FailureTransient, 0).
+		WithDetail("provider", c.name).
+		WithDetail("attempts", c.cfg.MaxRetries+1).
+		WithCause(lastErr)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// send ejecuta un intento con una petición nueva, ya que el cuerpo de la
+// anterior se consumió
+func (c *Client) send(ctx context.Context, req Request) (*Response, error) {
+	var body io.Reader
+	if req.Body != nil {
+		body = bytes.NewReader(req.Body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, body)
+	if err != nil {
+		return nil, channels.Classified(channels.ErrProviderAPIError(), channels.FailurePermanent, 0).
+			WithDetail("provider", c.name).
+			WithCause(err)
+	}
+	for key, values := range req.Header {
+		httpReq.Header[key] = values
+	}
+
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(httpResp.Body, c.cfg.MaxResponseBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > c.cfg.MaxResponseBytes {
+		return nil, channels.Classified(channels.ErrProviderAPIError(), channels.FailurePermanent, 0).
+			WithDetail("provider", c.name).
+			WithDetail("reason", fmt.Sprintf("response larger than %d bytes", c.cfg.MaxResponseBytes))
+	}
+
+	return &Response{
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+		Body:       data,
+	}, nil
+}
+
+func (c *Client) classify(resp *Response) channels.FailureClass {
+	if c.cfg.Classifier != nil {
+		return c.cfg.Classifier(resp)
+	}
+	return StatusClassifier(resp)
+}
+
+func (c *Client) wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter acepta segundos o una fecha HTTP
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,162 @@
+package channels
+
+import "encoding/json"
+
+// ============================================================================
+// Normalización de mensajes entrantes
+// ============================================================================
+
+// Normalize completa los campos canónicos que cada adapter llena a su
+// manera, para que las expresiones de los workflows no dependan del canal.
+// Se aplica una vez, antes de guardar los adjuntos y de disparar workflows.
+func Normalize(msg *IncomingMessage) {
+	content := &msg.Content
+
+	if content.Type == "" {
+		content.Type = "text"
+	}
+
+	// Algunos adapters entregan el medio solo como MediaURL
+	if len(content.Attachments) == 0 && content.MediaURL != "" {
+		content.Attachments = []Attachment{{
+			Type:     content.Type,
+			URL:      content.MediaURL,
+			MimeType: content.MimeType,
+			Filename: content.Filename,
+			Caption:  content.Caption,
+		}}
+	}
+
+	// El pie de foto es el texto de un mensaje de medios
+	if content.Text == "" {
+		if content.Caption != "" {
+			content.Text = content.Caption
+		} else if len(content.Attachments) > 0 {
+			content.Text = content.Attachments[0].Caption
+		}
+	}
+
+	// Un botón o fila elegida se lee como su título
+	if content.Text == "" && content.Postback != nil {
+		content.Text = content.Postback.Title
+	}
+}
+
+// TriggerPayload arma el payload canónico que reciben los workflows:
+//
+//	text, message_id, message_type, channel_id, channel_type, timestamp
+//	sender {id, name, locale}, sender_id, conversation_id
+//	attachments [{type, url, mime_type, filename, size, caption, attachment_id, scan_status}]
+//	location {latitude, longitude, name, address}
+//	contacts [{name, first_name, last_name, phone_number, email, organization, title, url}]
+//	postback {type, id, title, description}
+//	reply_to {message_id, sender_id}
+//	metadata  datos propios del canal
+//
+// El payload crudo del proveedor no se incluye; queda guardado aparte para depuración.
+func TriggerPayload(channel *Channel, msg *IncomingMessage) map[string]any {
+	sender := map[string]any{
+		"id":   msg.SenderID,
+		"name": msg.SenderName,
+	}
+	// Idioma del remitente cuando el canal lo informa (metadata "locale");
+	// elige la variante de las plantillas de mensaje
+	if locale, ok := msg.Metadata["locale"].(string); ok && locale != "" {
+		sender["locale"] = locale
+	}
+
+	triggerData := map[string]any{
+		"text":            msg.Content.Text,
+		"message_id":      msg.MessageID.String(),
+		"channel_id":      channel.ID.String(),
+		"channel_type":    string(channel.Type),
+		"environment":     string(channel.Environment.OrDefault()),
+		"sender_id":       msg.SenderID,
+		"message_type":    msg.Content.Type,
+		"conversation_id": msg.SenderID, // Para la memoria de la IA
+		"timestamp":       msg.Timestamp,
+		"sender":          sender,
+	}
+
+	// Adjuntos
+	if len(msg.Content.Attachments) > 0 {
+		attachments := make([]map[string]any, len(msg.Content.Attachments))
+		for i, att := range msg.Content.Attachments {
+			attachments[i] = map[string]any{
+				"type":          att.Type,
+				"url":           att.URL,
+				"mime_type":     att.MimeType,
+				"filename":      att.Filename,
+				"size":          att.Size,
+				"caption":       att.Caption,
+				"attachment_id": att.AttachmentID,
+				"scan_status":   att.ScanStatus,
+			}
+		}
+		triggerData["attachments"] = attachments
+	}
+
+	// Pin de ubicación
+	if loc := msg.Content.Location; loc != nil {
+		triggerData["location"] = map[string]any{
+			"latitude":  loc.Latitude,
+			"longitude": loc.Longitude,
+			"name":      loc.Name,
+			"address":   loc.Address,
+		}
+	}
+
+	// Tarjetas de contacto compartidas
+	if len(msg.Content.Contacts) > 0 {
+		contacts := make([]map[string]any, len(msg.Content.Contacts))
+		for i, contact := range msg.Content.Contacts {
+			contacts[i] = map[string]any{
+				"name":         contact.Name,
+				"first_name":   contact.FirstName,
+				"last_name":    contact.LastName,
+				"phone_number": contact.PhoneNumber,
+				"email":        contact.Email,
+				"organization": contact.Organization,
+				"title":        contact.Title,
+				"url":          contact.URL,
+			}
+		}
+		triggerData["contacts"] = contacts
+	}
+
+	// Botón o fila de lista elegida, igual en todos los canales
+	if postback := msg.Content.Postback; postback != nil {
+		triggerData["postback"] = map[string]any{
+			"type":        postback.Type,
+			"id":          postback.ID,
+			"title":       postback.Title,
+			"description": postback.Description,
+		}
+	}
+
+	// Mensaje citado; el handler lo completa con el contenido si lo conoce
+	if replyTo := msg.Content.ReplyTo; replyTo != nil {
+		triggerData["reply_to"] = map[string]any{
+			"message_id": replyTo.MessageID,
+			"sender_id":  replyTo.SenderID,
+			"found":      false,
+		}
+	}
+
+	// Metadata propia del canal
+	if msg.Metadata != nil {
+		triggerData["metadata"] = msg.Metadata
+	}
+
+	return triggerData
+}
+
+// DecodeRawPayload guarda el evento del proveedor tal cual llegó, para
+// depuración. Devuelve nil si el cuerpo no es un objeto JSON.
+func DecodeRawPayload(payload []byte) map[string]any {
+	var raw map[string]any
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil
+	}
+	return raw
+}
@@ -0,0 +1,63 @@
+package channels
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Outbox (simulación)
+// ============================================================================
+
+// CapturedMessage mensaje saliente capturado en lugar de enviarse al proveedor
+type CapturedMessage struct {
+	ChannelID  kernel.ChannelID `json:"channel_id"`
+	Message    OutgoingMessage  `json:"message"`
+	CapturedAt time.Time        `json:"captured_at"`
+}
+
+// Outbox acumula los mensajes salientes de una simulación.
+// Cuando el contexto lleva un Outbox, el ChannelManager no llama al proveedor.
+type Outbox struct {
+	mu       sync.Mutex
+	messages []CapturedMessage
+}
+
+type outboxKey struct{}
+
+// WithOutbox retorna un contexto que captura los mensajes salientes
+func WithOutbox(ctx context.Context) (context.Context, *Outbox) {
+	outbox := &Outbox{}
+	return context.WithValue(ctx, outboxKey{}, outbox), outbox
+}
+
+// OutboxFromContext obtiene el Outbox del contexto, si existe
+func OutboxFromContext(ctx context.Context) (*Outbox, bool) {
+	outbox, ok := ctx.Value(outboxKey{}).(*Outbox)
+	return outbox, ok && outbox != nil
+}
+
+// Capture registra un mensaje saliente
+func (o *Outbox) Capture(channelID kernel.ChannelID, msg OutgoingMessage) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.messages = append(o.messages, CapturedMessage{
+		ChannelID:  channelID,
+		Message:    msg,
+		CapturedAt: time.Now(),
+	})
+}
+
+// Messages retorna una copia de los mensajes capturados en orden
+func (o *Outbox) Messages() []CapturedMessage {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	messages := make([]CapturedMessage, len(o.messages))
+	copy(messages, o.messages)
+	return messages
+}
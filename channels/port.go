@@ -2,6 +2,8 @@ package channels
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/Abraxas-365/relay/pkg/kernel"
 )
@@ -15,9 +17,9 @@ type ChannelRepository interface {
 	// CRUD básico
 	Save(ctx context.Context, channel Channel) error
 	FindByID(ctx context.Context, id kernel.ChannelID, tenantID kernel.TenantID) (*Channel, error)
-	FindByName(ctx context.Context, name string, tenantID kernel.TenantID) (*Channel, error)
+	FindByName(ctx context.Context, name string, tenantID kernel.TenantID, environment kernel.Environment) (*Channel, error)
 	Delete(ctx context.Context, id kernel.ChannelID, tenantID kernel.TenantID) error
-	ExistsByName(ctx context.Context, name string, tenantID kernel.TenantID) (bool, error)
+	ExistsByName(ctx context.Context, name string, tenantID kernel.TenantID, environment kernel.Environment) (bool, error)
 
 	// Búsquedas específicas
 	FindByTenant(ctx context.Context, tenantID kernel.TenantID) ([]*Channel, error)
@@ -36,6 +38,99 @@ type ChannelRepository interface {
 	CountByTenant(ctx context.Context, tenantID kernel.TenantID) (int, error)
 }
 
+// MessageBufferStore guarda las ráfagas en un almacén compartido por todas
+// las instancias
+type MessageBufferStore interface {
+	// Append añade el mensaje a la ráfaga y fija cuándo vence: en el primer
+	// mensaje, o en cada uno si settings.ResetOnMessage
+	Append(ctx context.Context, key BufferKey, msg BufferedMessage, settings BufferSettings) error
+
+	// ClaimDue pasa de forma atómica hasta limit ráfagas vencidas a
+	// procesamiento; cada una la recibe una sola instancia. Una ráfaga sin
+	// Ack pasado visibility (instancia caída) vuelve a la cola
+	ClaimDue(ctx context.Context, now time.Time, limit int, visibility time.Duration) ([]BufferedBatch, error)
+
+	// Ack borra una ráfaga reclamada una vez entregada
+	Ack(ctx context.Context, batch BufferedBatch) error
+
+	// Requeue devuelve una ráfaga reclamada a la cola a partir de retryAt
+	Requeue(ctx context.Context, batch BufferedBatch, retryAt time.Time) error
+}
+
+// SuppressionRepository define el contrato para la lista de supresión
+type SuppressionRepository interface {
+	// IsSuppressed indica si el destinatario no debe recibir mensajes por el canal
+	IsSuppressed(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, recipientID string) (bool, error)
+
+	Add(ctx context.Context, suppression Suppression) error
+	Remove(ctx context.Context, tenantID kernel.TenantID, recipientID string, channelID *kernel.ChannelID) error
+
+	// RemoveByChannel borra las supresiones de un canal eliminado; viven en la
+	// región de datos del tenant, donde ninguna foreign key a channels las borra
+	RemoveByChannel(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID) error
+}
+
+// RawWebhookEventRepository persiste los eventos de webhook no soportados
+type RawWebhookEventRepository interface {
+	// Save inserta el evento o actualiza el resultado de un reproceso
+	Save(ctx context.Context, event RawWebhookEvent) error
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*RawWebhookEvent, error)
+	List(ctx context.Context, req ListWebhookEventsRequest) (WebhookEventListResponse, error)
+}
+
+// WebhookEventRecorder guarda los eventos de webhook que el adapter no procesa
+type WebhookEventRecorder interface {
+	RecordUnsupported(ctx context.Context, channel *Channel, eventTypes []string, payload []byte)
+}
+
+// InboundDispatcher entrega un mensaje entrante ya parseado al flujo normal:
+// transcripción, listeners y workflows
+type InboundDispatcher interface {
+	DispatchInbound(ctx context.Context, channel *Channel, msg *IncomingMessage)
+}
+
+// InboundListener recibe cada mensaje entrante antes de disparar workflows
+type InboundListener interface {
+	OnInboundMessage(ctx context.Context, channel *Channel, msg *IncomingMessage)
+}
+
+// InboundInterceptor puede quedarse con un mensaje entrante, como la
+// respuesta a una encuesta pendiente. Si devuelve true no se disparan
+// workflows con el mensaje.
+type InboundInterceptor interface {
+	InterceptInbound(ctx context.Context, channel *Channel, msg *IncomingMessage) bool
+}
+
+// InboundFilter revisa cada mensaje entrante antes de registrarlo (spam,
+// abuso). Los mensajes marcados siguen el flujo normal con el motivo en sus
+// metadatos; los descartados no se registran ni disparan workflows.
+type InboundFilter interface {
+	FilterInbound(ctx context.Context, channel *Channel, msg *IncomingMessage) FilterVerdict
+}
+
+// FilterVerdict decisión de un InboundFilter
+type FilterVerdict string
+
+const (
+	FilterAllow FilterVerdict = "ALLOW"
+	FilterFlag  FilterVerdict = "FLAG"
+	FilterDrop  FilterVerdict = "DROP"
+)
+
+// InboundBuffer agrupa los mensajes que un remitente envía seguidos y los
+// entrega como uno solo cuando vence la ventana del canal, en la instancia
+// que sea
+type InboundBuffer interface {
+	Add(ctx context.Context, channel *Channel, msg IncomingMessage, settings BufferSettings) error
+}
+
+// AttachmentIngester descarga y almacena los adjuntos de un mensaje entrante
+// antes de registrarlo y disparar workflows. Reescribe los adjuntos del
+// mensaje con la copia almacenada.
+type AttachmentIngester interface {
+	IngestAttachments(ctx context.Context, channel *Channel, msg *IncomingMessage)
+}
+
 // ============================================================================
 // Adapter Interfaces
 // ============================================================================
@@ -61,6 +156,59 @@ type ChannelAdapter interface {
 	TestConnection(ctx context.Context, config ChannelConfig) error
 }
 
+// ProviderMessageSender lo implementan los adapters que conocen el ID que el
+// proveedor asigna al mensaje enviado, para que las respuestas que citan
+// mensajes del bot se puedan resolver
+type ProviderMessageSender interface {
+	SendMessageWithID(ctx context.Context, msg OutgoingMessage) (string, error)
+}
+
+// WebhookEventClassifier lo implementan los adapters que reconocen los
+// eventos de webhook que no saben procesar, para guardarlos en vez de descartarlos
+type WebhookEventClassifier interface {
+	// UnsupportedEvents retorna los tipos de evento del payload que el adapter ignora
+	UnsupportedEvents(payload []byte) []string
+}
+
+// WebhookReplayer lo implementan los adapters que pueden volver a parsear un
+// webhook guardado. El payload ya fue verificado y no pasa por el buffer.
+type WebhookReplayer interface {
+	ParseWebhook(payload []byte) (*IncomingMessage, error)
+}
+
+// DeliveryReporter lo implementan los adapters cuyos webhooks informan la
+// entrega y lectura de los mensajes enviados
+type DeliveryReporter interface {
+	// DeliveryReceipts retorna los avisos de entrega del payload ya verificado
+	DeliveryReceipts(payload []byte) []DeliveryReceipt
+}
+
+// MediaFetcher lo implementan los adapters cuyos medios entrantes requieren
+// autenticación o no llegan como URL pública
+type MediaFetcher interface {
+	// FetchMedia abre el contenido del adjunto y retorna su mime type
+	FetchMedia(ctx context.Context, attachment Attachment) (io.ReadCloser, string, error)
+}
+
+// MessageEditor lo implementan los adapters cuyos canales permiten editar un
+// mensaje ya enviado (WebChat, editMessageText de Telegram). Con él las
+// respuestas de IA se muestran mientras el modelo las genera.
+type MessageEditor interface {
+	// SendPartial envía el comienzo de un mensaje y retorna su ID en el proveedor
+	SendPartial(ctx context.Context, msg OutgoingMessage) (string, error)
+
+	// EditMessage reemplaza el contenido de un mensaje enviado con SendPartial
+	EditMessage(ctx context.Context, recipientID, providerMessageID string, content MessageContent) error
+}
+
+// MessageRenderer lo implementan los adapters que pueden armar la petición al
+// proveedor sin enviarla, para previsualizar un mensaje
+type MessageRenderer interface {
+	// RenderMessage retorna el payload que SendMessage enviaría y los
+	// recortes o conversiones que el adapter aplica. Falla como fallaría el envío.
+	RenderMessage(msg OutgoingMessage) (RenderedMessage, error)
+}
+
 // ============================================================================
 // Manager Interfaces
 // ============================================================================
@@ -78,4 +226,14 @@ type ChannelManager interface {
 
 	// GetAdapter obtiene el adapter para un tipo de canal
 	GetAdapter(channelID kernel.ChannelID) (ChannelAdapter, error)
+
+	// UnregisterChannel elimina un canal y su adapter de memoria
+	UnregisterChannel(channelID kernel.ChannelID)
+}
+
+// MessageStreamer entrega un mensaje cuyo texto llega por partes. En canales
+// con SupportsMessageEditing el mensaje se edita a medida que llegan; en los
+// demás se envía completo cuando parts se cierra.
+type MessageStreamer interface {
+	StreamMessage(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, msg OutgoingMessage, parts <-chan string) error
 }
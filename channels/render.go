@@ -0,0 +1,141 @@
+package channels
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// ============================================================================
+// Previsualización de mensajes
+// ============================================================================
+
+// Acciones que el render aplica a un borrador antes de enviarlo
+const (
+	RenderSubstituted = "substituted" // Variable reemplazada en el texto
+	RenderUnresolved  = "unresolved"  // Placeholder sin variable; sale tal cual
+	RenderTruncated   = "truncated"   // Texto recortado al límite del canal
+	RenderDropped     = "dropped"     // Botón, fila o campo que el canal no muestra
+	RenderConverted   = "converted"   // Contenido enviado con otra forma (lista como quick replies, ...)
+	RenderOverLimit   = "over_limit"  // Excede un límite del canal; el proveedor puede rechazarlo
+)
+
+// RenderChange un cambio entre el borrador y lo que recibe el contacto
+type RenderChange struct {
+	Field  string `json:"field"` // content.text, content.interactive.buttons, ...
+	Action string `json:"action"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// RenderedMessage lo que un adapter enviaría al proveedor
+type RenderedMessage struct {
+	Payload any            `json:"payload"` // Cuerpo de la petición al proveedor
+	Changes []RenderChange `json:"changes,omitempty"`
+}
+
+// RenderMessageResponse previsualización de un borrador en un canal
+type RenderMessageResponse struct {
+	ChannelType ChannelType     `json:"channel_type"`
+	Message     OutgoingMessage `json:"message"`                    // Borrador con las variables reemplazadas
+	Payload     any             `json:"provider_payload,omitempty"` // Solo si el adapter implementa MessageRenderer
+	Changes     []RenderChange  `json:"changes"`
+	Sendable    bool            `json:"sendable"`
+	Error       string          `json:"error,omitempty"` // Por qué el envío fallaría
+}
+
+// placeholderPattern placeholders {{nombre}}, como los de los snippets
+var placeholderPattern = regexp.MustCompile(`{{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*}}`)
+
+// SubstituteVariables reemplaza los placeholders {{nombre}} del texto del
+// borrador con msg.Variables. Las plantillas del proveedor reciben las
+// variables como parámetros, así que su texto no se toca.
+func SubstituteVariables(msg OutgoingMessage) (OutgoingMessage, []RenderChange) {
+	if msg.Content.Type == "template" && msg.TemplateID != "" {
+		return msg, nil
+	}
+
+	var changes []RenderChange
+	substitute := func(field, text string) string {
+		if text == "" {
+			return text
+		}
+		var substituted, unresolved []string
+		text = placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+			name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+			value, ok := msg.Variables[name]
+			if !ok {
+				if !slices.Contains(unresolved, name) {
+					unresolved = append(unresolved, name)
+				}
+				return placeholder
+			}
+			if !slices.Contains(substituted, name) {
+				substituted = append(substituted, name)
+			}
+			return value
+		})
+		for _, name := range substituted {
+			changes = append(changes, RenderChange{Field: field, Action: RenderSubstituted, Detail: name})
+		}
+		for _, name := range unresolved {
+			changes = append(changes, RenderChange{Field: field, Action: RenderUnresolved, Detail: name})
+		}
+		return text
+	}
+
+	content := msg.Content
+	content.Text = substitute("content.text", content.Text)
+	content.Caption = substitute("content.caption", content.Caption)
+	if content.Interactive != nil {
+		interactive := *content.Interactive
+		interactive.Header = substitute("content.interactive.header", interactive.Header)
+		interactive.Body = substitute("content.interactive.body", interactive.Body)
+		interactive.Footer = substitute("content.interactive.footer", interactive.Footer)
+		content.Interactive = &interactive
+	}
+	msg.Content = content
+
+	return msg, changes
+}
+
+// UnsupportedFeature retorna la característica del contenido que el canal no
+// puede enviar, o "" si puede enviarlo todo
+func UnsupportedFeature(features ChannelFeatures, content MessageContent) string {
+	switch {
+	case content.Location != nil && !features.SupportsLocation:
+		return "location"
+	case len(content.Contacts) > 0 && !features.SupportsContacts:
+		return "contacts"
+	case content.Interactive != nil && !features.SupportsInteractiveMessages:
+		return "interactive"
+	case content.Interactive != nil && len(content.Interactive.TimeSlots) > 0 && !features.SupportsTimePicker:
+		return "time_picker"
+	case content.Interactive != nil && content.Interactive.URL != "" && !features.SupportsRichLinks:
+		return "rich_link"
+	}
+	return ""
+}
+
+// CheckLimits reporta el texto que excede el largo máximo del canal
+func CheckLimits(features ChannelFeatures, content MessageContent) []RenderChange {
+	if features.MaxMessageLength <= 0 {
+		return nil
+	}
+
+	var changes []RenderChange
+	check := func(field, text string) {
+		if length := len([]rune(text)); length > features.MaxMessageLength {
+			changes = append(changes, RenderChange{
+				Field:  field,
+				Action: RenderOverLimit,
+				Detail: fmt.Sprintf("%d of %d characters", length, features.MaxMessageLength),
+			})
+		}
+	}
+
+	check("content.text", content.Text)
+	if content.Interactive != nil {
+		check("content.interactive.body", content.Interactive.Body)
+	}
+	return changes
+}
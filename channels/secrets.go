@@ -0,0 +1,71 @@
+package channels
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+// ============================================================================
+// Secretos de la configuración
+// ============================================================================
+
+// secretConfigKeyPattern reconoce las claves de configuración con credenciales
+var secretConfigKeyPattern = regexp.MustCompile(`(?i)(token|secret|password|api_?key|private|credential|service_account)`)
+
+// RedactedSecret reemplaza el valor de un secreto en las respuestas. Si se
+// devuelve tal cual al actualizar, se conserva el secreto guardado
+const RedactedSecret = "(sensitive)"
+
+// IsSecretConfigKey indica si una clave de configuración guarda una credencial
+func IsSecretConfigKey(key string) bool {
+	return secretConfigKeyPattern.MatchString(key)
+}
+
+// Redacted devuelve una copia del canal con los secretos de su configuración
+// ocultos; es lo único que la API devuelve de un canal
+func (c Channel) Redacted() Channel {
+	var config map[string]any
+	if err := json.Unmarshal(c.Config, &config); err != nil {
+		c.Config = json.RawMessage("{}")
+		return c
+	}
+
+	redactSecrets(config)
+	c.Config, _ = json.Marshal(config)
+	return c
+}
+
+// KeepStoredSecrets completa la configuración nueva con los secretos
+// guardados que no se enviaron, se enviaron vacíos o se devolvieron ocultos
+func KeepStoredSecrets(stored, updated json.RawMessage) (json.RawMessage, error) {
+	var before, after map[string]any
+	if err := json.Unmarshal(updated, &after); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(stored, &before); err != nil {
+		return updated, nil
+	}
+
+	for key, value := range before {
+		if !IsSecretConfigKey(key) {
+			continue
+		}
+		if current, ok := after[key]; !ok || current == "" || current == RedactedSecret {
+			after[key] = value
+		}
+	}
+	return json.Marshal(after)
+}
+
+func redactSecrets(config map[string]any) {
+	for key, value := range config {
+		switch v := value.(type) {
+		case map[string]any:
+			redactSecrets(v)
+		case string:
+			if v != "" && IsSecretConfigKey(key) {
+				config[key] = RedactedSecret
+			}
+		}
+	}
+}
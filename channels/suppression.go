@@ -0,0 +1,18 @@
+package channels
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// Suppression contacto que no debe recibir mensajes del tenant.
+// Si ChannelID es nil la supresión aplica a todos los canales.
+type Suppression struct {
+	ID          string            `db:"id" json:"id"`
+	TenantID    kernel.TenantID   `db:"tenant_id" json:"tenant_id"`
+	ChannelID   *kernel.ChannelID `db:"channel_id" json:"channel_id,omitempty"`
+	RecipientID string            `db:"recipient_id" json:"recipient_id"`
+	Reason      string            `db:"reason" json:"reason,omitempty"`
+	CreatedAt   time.Time         `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,112 @@
+package channels
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Eventos de webhook no soportados
+// ============================================================================
+
+// WebhookEventStatus estado de un evento guardado
+type WebhookEventStatus string
+
+const (
+	WebhookEventPending     WebhookEventStatus = "PENDING"     // Ningún adapter lo procesa todavía
+	WebhookEventReprocessed WebhookEventStatus = "REPROCESSED" // Un adapter actualizado lo convirtió en mensaje
+)
+
+// IsValid verifica que el estado sea conocido
+func (s WebhookEventStatus) IsValid() bool {
+	return s == WebhookEventPending || s == WebhookEventReprocessed
+}
+
+// RawWebhookEvent evento de webhook que el adapter no supo procesar (nuevos
+// tipos de evento de Meta, por ejemplo). Se guarda tal cual llegó, después
+// de verificar la firma, para poder reprocesarlo cuando el adapter lo soporte.
+type RawWebhookEvent struct {
+	ID              string             `json:"id"`
+	TenantID        kernel.TenantID    `json:"tenant_id"`
+	ChannelID       kernel.ChannelID   `json:"channel_id"`
+	Provider        ChannelType        `json:"provider"`
+	EventType       string             `json:"event_type"`
+	Payload         map[string]any     `json:"payload"`
+	Status          WebhookEventStatus `json:"status"`
+	Error           string             `json:"error,omitempty"` // Resultado del último reproceso
+	ReprocessCount  int                `json:"reprocess_count"`
+	LastReprocessAt *time.Time         `json:"last_reprocess_at,omitempty"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+// ============================================================================
+// Métricas
+// ============================================================================
+
+// WebhookEventCounts contadores de un tipo de evento
+type WebhookEventCounts struct {
+	Received    uint64 `json:"received"`
+	Reprocessed uint64 `json:"reprocessed"`
+}
+
+type webhookEventCounters struct {
+	received    atomic.Uint64
+	reprocessed atomic.Uint64
+}
+
+// WebhookEventMetrics acumula contadores por proveedor y tipo de evento
+// ("WHATSAPP:message_template_status_update", ...)
+type WebhookEventMetrics struct {
+	events sync.Map // string -> *webhookEventCounters
+}
+
+// NewWebhookEventMetrics crea un acumulador de métricas vacío
+func NewWebhookEventMetrics() *WebhookEventMetrics {
+	return &WebhookEventMetrics{}
+}
+
+// RecordReceived cuenta un evento no soportado recibido
+func (m *WebhookEventMetrics) RecordReceived(provider ChannelType, eventType string) {
+	if m != nil {
+		m.counters(provider, eventType).received.Add(1)
+	}
+}
+
+// RecordReprocessed cuenta un evento reprocesado con éxito
+func (m *WebhookEventMetrics) RecordReprocessed(provider ChannelType, eventType string) {
+	if m != nil {
+		m.counters(provider, eventType).reprocessed.Add(1)
+	}
+}
+
+// Snapshot devuelve una copia de los contadores actuales
+func (m *WebhookEventMetrics) Snapshot() map[string]WebhookEventCounts {
+	snapshot := make(map[string]WebhookEventCounts)
+	if m == nil {
+		return snapshot
+	}
+
+	m.events.Range(func(key, value any) bool {
+		counters := value.(*webhookEventCounters)
+		snapshot[key.(string)] = WebhookEventCounts{
+			Received:    counters.received.Load(),
+			Reprocessed: counters.reprocessed.Load(),
+		}
+		return true
+	})
+
+	return snapshot
+}
+
+func (m *WebhookEventMetrics) counters(provider ChannelType, eventType string) *webhookEventCounters {
+	key := string(provider) + ":" + eventType
+	if counters, ok := m.events.Load(key); ok {
+		return counters.(*webhookEventCounters)
+	}
+	counters, _ := m.events.LoadOrStore(key, &webhookEventCounters{})
+	return counters.(*webhookEventCounters)
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/client"
+)
+
+// Variables de entorno de los comandos que llaman a la API
+const (
+	envURL   = "RELAY_URL"
+	envToken = "RELAY_TOKEN"
+)
+
+// apiFlags flags comunes de los comandos que llaman a la API
+type apiFlags struct {
+	url     string
+	token   string
+	timeout time.Duration
+	asJSON  bool
+}
+
+func (f *apiFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.url, "url", envOr(envURL, "http://localhost:8080"), "API base URL ($"+envURL+")")
+	fs.StringVar(&f.token, "token", os.Getenv(envToken), "access token ($"+envToken+"); see 'relay login'")
+	fs.DurationVar(&f.timeout, "timeout", 60*time.Second, "request timeout")
+	fs.BoolVar(&f.asJSON, "json", false, "print the raw JSON response")
+}
+
+// client crea el cliente; los comandos autenticados exigen token
+func (f *apiFlags) client(requireToken bool) (*client.Client, error) {
+	if requireToken && f.token == "" {
+		return nil, fmt.Errorf("no access token: pass -token or set $%s (see 'relay login')", envToken)
+	}
+	return client.New(f.url,
+		client.WithToken(f.token),
+		client.WithUserAgent("relay-cli"),
+		client.WithHTTPClient(&http.Client{Timeout: f.timeout}),
+	), nil
+}
+
+// context se cancela con Ctrl+C
+func (f *apiFlags) context() (context.Context, context.CancelFunc) {
+	return interruptContext()
+}
+
+// ============================================================================
+// Subcomandos anidados
+// ============================================================================
+
+// runGroup despacha "relay <group> <sub> ..." a uno de los subcomandos
+func runGroup(group string, subs []command, args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		groupUsage(group, subs)
+		return nil
+	}
+
+	for _, sub := range subs {
+		if sub.name == args[0] {
+			return sub.run(args[1:])
+		}
+	}
+
+	groupUsage(group, subs)
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+func groupUsage(group string, subs []command) {
+	fmt.Fprintf(os.Stderr, "Usage: relay %s <command> [flags]\n\nCommands:\n", group)
+	for _, sub := range subs {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", sub.name, sub.summary)
+	}
+}
+
+// ============================================================================
+// Login
+// ============================================================================
+
+func runLogin(args []string) error {
+	var api apiFlags
+	fs := flag.NewFlagSet("login", flag.ContinueOnError)
+	api.register(fs)
+	email := fs.String("email", "", "user email")
+	tenantRUC := fs.String("tenant", "", "tenant RUC, when the email exists in several tenants")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("-email is required")
+	}
+
+	password := os.Getenv("RELAY_PASSWORD")
+	if password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := readLine(os.Stdin)
+		if err != nil {
+			return err
+		}
+		password = line
+	}
+
+	c, err := api.client(false)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := api.context()
+	defer cancel()
+
+	tokens, err := c.Login(ctx, auth.PasswordLoginRequest{
+		Email:     *email,
+		Password:  password,
+		TenantRUC: *tenantRUC,
+	})
+	if err != nil {
+		return err
+	}
+	if tokens.AccessToken == "" {
+		return fmt.Errorf("login needs a second factor; complete it in the web app")
+	}
+
+	if api.asJSON {
+		return printJSON(tokens)
+	}
+	fmt.Fprintf(os.Stderr, "Logged in as %s (tenant %s), token expires in %ds\n",
+		tokens.User.Email, tokens.Tenant.CompanyName, tokens.ExpiresIn)
+	fmt.Printf("export %s=%s\n", envToken, tokens.AccessToken)
+	return nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func interruptContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// readLine lee una línea de r sin el salto final
+func readLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("no input")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readJSONFile decodifica un archivo JSON; "-" lee de stdin
+func readJSONFile(path string, v any) error {
+	data, err := readFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func readFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Abraxas-365/relay/manifest"
+)
+
+// runApply reconcilia el tenant con un manifiesto: muestra el plan, pide
+// confirmación y aplica. ${VAR} se expande con el entorno local para que los
+// secretos no vivan en el repositorio.
+func runApply(args []string) error {
+	var api apiFlags
+	fs := flag.NewFlagSet("apply", flag.ContinueOnError)
+	api.register(fs)
+	file := fs.String("f", "", "manifest file, YAML or JSON (- reads stdin)")
+	prune := fs.Bool("prune", false, "delete channels, roles and workflows the manifest does not declare")
+	dryRun := fs.Bool("dry-run", false, "print the plan and exit")
+	yes := fs.Bool("yes", false, "apply without asking for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("usage: relay apply -f <tenant.yaml> [-prune] [-dry-run] [-yes]")
+	}
+	if *file == "-" && !*dryRun && !*yes {
+		return fmt.Errorf("reading the manifest from stdin needs -yes or -dry-run")
+	}
+
+	data, err := readFile(*file)
+	if err != nil {
+		return err
+	}
+	if data, err = manifest.ExpandEnv(data); err != nil {
+		return err
+	}
+	// Los errores de formato se reportan sin llamar al servidor
+	if _, err := manifest.Parse(data); err != nil {
+		return fmt.Errorf("%s: %w", *file, err)
+	}
+
+	c, err := api.client(true)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := api.context()
+	defer cancel()
+
+	plan, err := c.PlanManifest(ctx, data, *prune)
+	if err != nil {
+		return err
+	}
+	if api.asJSON && *dryRun {
+		return printJSON(plan)
+	}
+
+	printPlan(plan)
+	if !plan.HasChanges() || *dryRun {
+		return nil
+	}
+
+	if !*yes {
+		fmt.Fprint(os.Stderr, "\nApply these changes? Only 'yes' is accepted: ")
+		answer, err := readLine(os.Stdin)
+		if err != nil || strings.TrimSpace(answer) != "yes" {
+			return fmt.Errorf("apply cancelled")
+		}
+	}
+
+	result, err := c.ApplyManifest(ctx, data, *prune)
+	if result == nil {
+		return err
+	}
+	if api.asJSON {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println()
+		for _, change := range result.Applied {
+			fmt.Printf("✓ %s %s %q\n", change.Action, change.Kind, change.Name)
+		}
+	}
+
+	if result.Failed != nil {
+		return fmt.Errorf("%s %s %q failed after %d changes: %s",
+			result.Failed.Action, result.Failed.Kind, result.Failed.Name, len(result.Applied), result.Error)
+	}
+	fmt.Printf("\nApply complete: %s\n", result.Plan.Summary)
+	return nil
+}
+
+// printPlan muestra el plan al estilo diff: + crear, ~ actualizar, - eliminar
+func printPlan(plan *manifest.Plan) {
+	symbols := map[manifest.Action]string{
+		manifest.ActionCreate: "+",
+		manifest.ActionUpdate: "~",
+		manifest.ActionDelete: "-",
+	}
+
+	for _, change := range plan.Changes {
+		symbol, ok := symbols[change.Action]
+		if !ok {
+			continue
+		}
+		fmt.Printf("%s %s %q\n", symbol, change.Kind, change.Name)
+		for _, diff := range change.Diff {
+			switch {
+			case diff.Before == nil:
+				fmt.Printf("    + %s: %s\n", diff.Field, compact(diff.After))
+			case diff.After == nil:
+				fmt.Printf("    - %s: %s\n", diff.Field, compact(diff.Before))
+			default:
+				fmt.Printf("    ~ %s: %s → %s\n", diff.Field, compact(diff.Before), compact(diff.After))
+			}
+		}
+	}
+
+	if !plan.HasChanges() {
+		fmt.Printf("No changes. %d resources match the manifest.\n", plan.Summary.Unchanged)
+		return
+	}
+	fmt.Printf("\nPlan (%s): %s\n", plan.Environment, plan.Summary)
+}
+
+// compact JSON de una línea, recortado para no inundar la terminal
+func compact(value any) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	const limit = 120
+	if len(raw) > limit {
+		return string(raw[:limit]) + "…"
+	}
+	return string(raw)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/channelapi"
+	"github.com/Abraxas-365/relay/pkg/client"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+var channelCommands = []command{
+	{name: "list", summary: "List the tenant's channels", run: runChannelsList},
+	{name: "test", summary: "Test a channel's provider connection", run: runChannelsTest},
+}
+
+func runChannels(args []string) error {
+	return runGroup("channels", channelCommands, args)
+}
+
+func runChannelsList(args []string) error {
+	var api apiFlags
+	fs := flag.NewFlagSet("channels list", flag.ContinueOnError)
+	api.register(fs)
+	channelType := fs.String("type", "", "only channels of this type, e.g. WHATSAPP")
+	page := fs.Int("page", 1, "page number")
+	pageSize := fs.Int("page-size", 50, "channels per page")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := api.client(true)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := api.context()
+	defer cancel()
+
+	result, err := c.ListChannels(ctx, client.ListChannelsOptions{
+		Page:     *page,
+		PageSize: *pageSize,
+		Type:     channels.ChannelType(*channelType),
+	})
+	if err != nil {
+		return err
+	}
+	if api.asJSON {
+		return printJSON(result)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tNAME\tACTIVE")
+	for _, channel := range result.Data {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%v\n", channel.ID, channel.Type, channel.Name, channel.IsActive)
+	}
+	return w.Flush()
+}
+
+func runChannelsTest(args []string) error {
+	var api apiFlags
+	fs := flag.NewFlagSet("channels test", flag.ContinueOnError)
+	api.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: relay channels test [flags] <channel-id>")
+	}
+
+	c, err := api.client(true)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := api.context()
+	defer cancel()
+
+	result, err := c.TestChannel(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if api.asJSON {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	} else if result.Success {
+		fmt.Printf("✓ %s (%dms)\n", result.Message, result.ResponseTime)
+	} else {
+		fmt.Printf("✗ %s: %s\n", result.Message, result.Error)
+	}
+
+	if !result.Success {
+		return fmt.Errorf("channel test failed")
+	}
+	return nil
+}
+
+// ============================================================================
+// Simulate
+// ============================================================================
+
+// runSimulate inyecta un mensaje en un canal TEST_HTTP y muestra las
+// respuestas que se habrían enviado
+func runSimulate(args []string) error {
+	var api apiFlags
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	api.register(fs)
+	channelID := fs.String("channel", "", "TEST_HTTP channel ID")
+	workflowID := fs.String("workflow", "", "run only this workflow")
+	senderID := fs.String("sender", "", "sender ID (default: generated)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *channelID == "" || fs.NArg() != 1 {
+		return fmt.Errorf("usage: relay simulate -channel <id> [flags] <text>")
+	}
+
+	req := channelapi.SimulateRequest{
+		ChannelID: kernel.ChannelID(*channelID),
+		SenderID:  *senderID,
+		Text:      fs.Arg(0),
+	}
+	if *workflowID != "" {
+		id := kernel.WorkflowID(*workflowID)
+		req.WorkflowID = &id
+	}
+
+	c, err := api.client(true)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := api.context()
+	defer cancel()
+
+	result, err := c.Simulate(ctx, req)
+	if err != nil {
+		return err
+	}
+	if api.asJSON {
+		return printJSON(result)
+	}
+
+	for _, execution := range result.Executions {
+		mark := "✓"
+		if !execution.Success {
+			mark = "✗"
+		}
+		fmt.Printf("%s workflow %s (%d nodes)\n", mark, execution.WorkflowID, len(execution.ExecutedNodes))
+		if execution.Error != "" {
+			fmt.Printf("    error: %s\n", execution.Error)
+		}
+	}
+	for _, response := range result.Responses {
+		fmt.Printf("→ %s\n", response.Message.Content.Text)
+	}
+	fmt.Printf("\n%d executions, %d responses in %dms\n", len(result.Executions), len(result.Responses), result.DurationMs)
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+var continuationCommands = []command{
+	{name: "list", summary: "List pending workflow continuations (delays, waits)", run: runContinuationsList},
+}
+
+func runContinuations(args []string) error {
+	return runGroup("continuations", continuationCommands, args)
+}
+
+func runContinuationsList(args []string) error {
+	var api apiFlags
+	fs := flag.NewFlagSet("continuations list", flag.ContinueOnError)
+	api.register(fs)
+	page := fs.Int("page", 1, "page number")
+	pageSize := fs.Int("page-size", 50, "continuations per page")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := api.client(true)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := api.context()
+	defer cancel()
+
+	result, err := c.ListContinuations(ctx, *page, *pageSize)
+	if err != nil {
+		return err
+	}
+	if api.asJSON {
+		return printJSON(result)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tWORKFLOW\tNEXT NODE\tSCHEDULED FOR\tIN")
+	for _, cont := range result.Data {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", cont.ID, cont.WorkflowID, cont.NextNodeID,
+			cont.ScheduledFor.Format(time.RFC3339), time.Until(cont.ScheduledFor).Round(time.Second))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\npage %d of %d, %d total\n", result.Page.Number, result.Page.Pages, result.Page.Total)
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/pkg/client"
+)
+
+var jobCommands = []command{
+	{name: "list", summary: "List background jobs", run: runJobsList},
+	{name: "runs", summary: "Show the run history of a job", run: runJobsRuns},
+	{name: "run", summary: "Run a job now", run: runJobsRun},
+}
+
+func runJobs(args []string) error {
+	return runGroup("jobs", jobCommands, args)
+}
+
+func runJobsList(args []string) error {
+	var api apiFlags
+	fs := flag.NewFlagSet("jobs list", flag.ContinueOnError)
+	api.register(fs)
+	page := fs.Int("page", 1, "page number")
+	pageSize := fs.Int("page-size", 50, "jobs per page")
+	status := fs.String("status", "", "pending, running, succeeded or failed")
+	kind := fs.String("kind", "", "job kind")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := api.client(true)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := api.context()
+	defer cancel()
+
+	result, err := c.ListJobs(ctx, client.ListJobsOptions{
+		Page:     *page,
+		PageSize: *pageSize,
+		Status:   jobs.Status(*status),
+		Kind:     *kind,
+	})
+	if err != nil {
+		return err
+	}
+	if api.asJSON {
+		return printJSON(result)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tKIND\tSTATUS\tSCHEDULE\tATTEMPTS\tNEXT RUN\tLAST ERROR")
+	for _, job := range result.Data {
+		schedule := job.Schedule
+		if schedule == "" {
+			schedule = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%d\t%s\t%s\n", job.ID, job.Kind, job.Status, schedule,
+			job.Attempts, job.MaxAttempts, job.RunAt.Format(time.RFC3339), job.LastError)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\npage %d of %d, %d total\n", result.Page.Number, result.Page.Pages, result.Page.Total)
+	return nil
+}
+
+func runJobsRuns(args []string) error {
+	var api apiFlags
+	fs := flag.NewFlagSet("jobs runs", flag.ContinueOnError)
+	api.register(fs)
+	page := fs.Int("page", 1, "page number")
+	pageSize := fs.Int("page-size", 50, "runs per page")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: relay jobs runs [flags] <job-id>")
+	}
+
+	c, err := api.client(true)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := api.context()
+	defer cancel()
+
+	result, err := c.ListJobRuns(ctx, fs.Arg(0), *page, *pageSize)
+	if err != nil {
+		return err
+	}
+	if api.asJSON {
+		return printJSON(result)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STARTED\tATTEMPT\tSTATUS\tDURATION\tWORKER\tERROR")
+	for _, run := range result.Data {
+		duration := "-"
+		if run.DurationMs != nil {
+			duration = (time.Duration(*run.DurationMs) * time.Millisecond).String()
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", run.StartedAt.Format(time.RFC3339), run.Attempt,
+			run.Status, duration, run.Worker, run.Error)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\npage %d of %d, %d total\n", result.Page.Number, result.Page.Pages, result.Page.Total)
+	return nil
+}
+
+func runJobsRun(args []string) error {
+	var api apiFlags
+	fs := flag.NewFlagSet("jobs run", flag.ContinueOnError)
+	api.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: relay jobs run [flags] <job-id>")
+	}
+
+	c, err := api.client(true)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := api.context()
+	defer cancel()
+
+	job, err := c.RunJob(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if api.asJSON {
+		return printJSON(job)
+	}
+
+	fmt.Printf("✓ %s (%s) queued to run now\n", job.ID, job.Kind)
+	return nil
+}
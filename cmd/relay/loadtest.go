@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/engine/loadtest"
+)
+
+func runLoadTest(args []string) error {
+	cfg := loadtest.DefaultConfig
+
+	fs := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	fs.IntVar(&cfg.Rate, "rate", cfg.Rate, "messages per second")
+	fs.DurationVar(&cfg.Duration, "duration", cfg.Duration, "how long to send traffic for")
+	fs.IntVar(&cfg.Tenants, "tenants", cfg.Tenants, "tenants to spread traffic over")
+	fs.IntVar(&cfg.Senders, "senders", cfg.Senders, "distinct senders per tenant")
+	fs.IntVar(&cfg.Nodes, "nodes", cfg.Nodes, "nodes per workflow (complexity)")
+	fs.IntVar(&cfg.MaxInFlight, "max-in-flight", cfg.MaxInFlight, "messages executing at once before new ones are dropped")
+	fs.DurationVar(&cfg.ChannelLatency, "channel-latency", cfg.ChannelLatency, "simulated provider latency per send")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	verbose := fs.Bool("verbose", false, "keep executor logs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Los logs del executor por nodo distorsionan las latencias
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "Running %d msg/s for %s over %d tenants, %d nodes per workflow...\n",
+		cfg.Rate, cfg.Duration, cfg.Tenants, cfg.Nodes)
+
+	report, err := loadtest.Run(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	printReport(report)
+	return nil
+}
+
+func printReport(report *loadtest.Report) {
+	fmt.Printf("Sent %d, succeeded %d, failed %d, dropped %d in %s (%.1f msg/s)\n\n",
+		report.Sent, report.Succeeded, report.Failed, report.Dropped,
+		report.Elapsed.Round(time.Millisecond), report.Throughput())
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "\tcount\tp50\tp95\tp99\tmax\t")
+	printLatencies(w, "end-to-end", report.EndToEnd)
+
+	nodeTypes := make([]engine.NodeType, 0, len(report.Nodes))
+	for nodeType := range report.Nodes {
+		nodeTypes = append(nodeTypes, nodeType)
+	}
+	slices.Sort(nodeTypes)
+	for _, nodeType := range nodeTypes {
+		printLatencies(w, string(nodeType), report.Nodes[nodeType])
+	}
+	w.Flush()
+}
+
+func printLatencies(w io.Writer, name string, l loadtest.Latencies) {
+	fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t\n", name, l.Count, round(l.P50), round(l.P95), round(l.P99), round(l.Max))
+}
+
+func round(d time.Duration) time.Duration {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond)
+	}
+	return d.Round(100 * time.Microsecond)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// command es un subcomando de la CLI
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{name: "login", summary: "Log in and print an access token for the other commands", run: runLogin},
+	{name: "apply", summary: "Reconcile a tenant with a declarative manifest", run: runApply},
+	{name: "workflows", summary: "Validate workflow files and run conversation tests", run: runWorkflows},
+	{name: "tenants", summary: "List tenants", run: runTenants},
+	{name: "channels", summary: "List and test channels", run: runChannels},
+	{name: "simulate", summary: "Send a simulated message through a TEST_HTTP channel", run: runSimulate},
+	{name: "continuations", summary: "List pending workflow continuations", run: runContinuations},
+	{name: "jobs", summary: "List background jobs and their run history", run: runJobs},
+	{name: "migrations", summary: "Apply and inspect database migrations", run: runMigrations},
+	{name: "loadtest", summary: "Replay synthetic traffic against an in-process executor", run: runLoadTest},
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		usage()
+		return
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "relay %s: %v\n", cmd.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "relay: unknown command %q\n\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: relay <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/Abraxas-365/relay/pkg/config"
+	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/jmoiron/sqlx"
+)
+
+var migrationCommands = []command{
+	{name: "run", summary: "Apply pending migrations to the primary and regional databases", run: runMigrationsRun},
+	{name: "status", summary: "Show applied and pending migrations", run: runMigrationsStatus},
+}
+
+func runMigrations(args []string) error {
+	return runGroup("migrations", migrationCommands, args)
+}
+
+// migrationFlags flags comunes de run y status
+type migrationFlags struct {
+	dir string
+}
+
+func (f *migrationFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.dir, "dir", "migrations", "directory with NNN_name.up.sql files")
+}
+
+// migrationTarget una base a migrar: la principal o una región de datos
+type migrationTarget struct {
+	name string
+	cfg  config.DatabaseConfig
+}
+
+// migrationTargets devuelve la principal seguida de las regiones configuradas;
+// todas comparten el mismo esquema
+func migrationTargets() ([]migrationTarget, error) {
+	residency := config.LoadResidencyConfig()
+	if err := residency.Validate(); err != nil {
+		return nil, err
+	}
+
+	primary := residency.PrimaryRegion
+	if primary == "" {
+		primary = "primary"
+	}
+	targets := []migrationTarget{{name: primary, cfg: config.LoadDatabaseConfig()}}
+	for _, name := range slices.Sorted(maps.Keys(residency.Regions)) {
+		targets = append(targets, migrationTarget{name: name, cfg: residency.Regions[name]})
+	}
+	return targets, nil
+}
+
+// forEachMigrationTarget abre cada base y ejecuta fn; el nombre solo se
+// imprime si hay regiones configuradas
+func forEachMigrationTarget(fn func(ctx context.Context, db *sqlx.DB) error) error {
+	targets, err := migrationTargets()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	for i, target := range targets {
+		if len(targets) > 1 {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("📍 %s\n", target.name)
+		}
+		if err := migrateTarget(ctx, target, fn); err != nil {
+			return fmt.Errorf("%s: %w", target.name, err)
+		}
+	}
+	return nil
+}
+
+func migrateTarget(ctx context.Context, target migrationTarget, fn func(ctx context.Context, db *sqlx.DB) error) error {
+	db, err := database.NewPostgresDB(target.cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := database.EnsureMigrationsTable(ctx, db); err != nil {
+		return err
+	}
+	return fn(ctx, db)
+}
+
+func runMigrationsRun(args []string) error {
+	var flags migrationFlags
+	fs := flag.NewFlagSet("migrations run", flag.ContinueOnError)
+	flags.register(fs)
+	baseline := fs.String("baseline", "", "mark migrations up to this version as applied without running them (databases migrated with 'make migrate')")
+	dryRun := fs.Bool("dry-run", false, "list pending migrations without applying them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	migrations, err := database.LoadMigrations(flags.dir)
+	if err != nil {
+		return err
+	}
+
+	return forEachMigrationTarget(func(ctx context.Context, db *sqlx.DB) error {
+		applied, err := database.AppliedMigrations(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		pending := 0
+		for _, m := range migrations {
+			if applied[m.Version] {
+				continue
+			}
+			pending++
+
+			if *baseline != "" && m.Version <= *baseline {
+				if *dryRun {
+					fmt.Printf("  baseline %s\n", filepath.Base(m.Path))
+					continue
+				}
+				if err := database.RecordMigration(ctx, db, m.Version); err != nil {
+					return fmt.Errorf("baseline %s: %w", m.Version, err)
+				}
+				fmt.Printf("  baseline %s\n", filepath.Base(m.Path))
+				continue
+			}
+
+			if *dryRun {
+				fmt.Printf("  pending  %s\n", filepath.Base(m.Path))
+				continue
+			}
+			if err := database.ApplyMigration(ctx, db, m); err != nil {
+				return err
+			}
+			fmt.Printf("  → %s\n", filepath.Base(m.Path))
+		}
+
+		if pending == 0 {
+			fmt.Println("✅ Database is up to date")
+		} else if !*dryRun {
+			fmt.Printf("✅ %d migrations applied\n", pending)
+		}
+		return nil
+	})
+}
+
+func runMigrationsStatus(args []string) error {
+	var flags migrationFlags
+	fs := flag.NewFlagSet("migrations status", flag.ContinueOnError)
+	flags.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	migrations, err := database.LoadMigrations(flags.dir)
+	if err != nil {
+		return err
+	}
+
+	return forEachMigrationTarget(func(ctx context.Context, db *sqlx.DB) error {
+		applied, err := database.AppliedMigrations(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		pending := 0
+		for _, m := range migrations {
+			state := "applied"
+			if !applied[m.Version] {
+				state = "pending"
+				pending++
+			}
+			fmt.Printf("  %-8s %s\n", state, filepath.Base(m.Path))
+		}
+		fmt.Printf("\n%d of %d migrations pending\n", pending, len(migrations))
+		return nil
+	})
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+var tenantCommands = []command{
+	{name: "list", summary: "List the tenants the logged-in user belongs to", run: runTenantsList},
+}
+
+func runTenants(args []string) error {
+	return runGroup("tenants", tenantCommands, args)
+}
+
+func runTenantsList(args []string) error {
+	var api apiFlags
+	fs := flag.NewFlagSet("tenants list", flag.ContinueOnError)
+	api.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := api.client(true)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := api.context()
+	defer cancel()
+
+	tenants, err := c.ListTenants(ctx)
+	if err != nil {
+		return err
+	}
+	if api.asJSON {
+		return printJSON(tenants)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tRUC\tCOMPANY\tSTATUS\tPLAN\t")
+	for _, entry := range tenants {
+		marks := ""
+		if entry.IsCurrent {
+			marks = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", entry.Tenant.ID, entry.Tenant.RUC,
+			entry.Tenant.CompanyName, entry.Tenant.Status, entry.Tenant.SubscriptionPlan, marks)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/engine/node"
+	"github.com/Abraxas-365/relay/engine/promotion"
+	"github.com/Abraxas-365/relay/engine/workflowexec"
+	"github.com/Abraxas-365/relay/engine/workflowtest"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+var workflowCommands = []command{
+	{name: "validate", summary: "Validate workflow JSON files offline", run: runWorkflowsValidate},
+	{name: "test", summary: "Run a conversation test script against a stored workflow", run: runWorkflowsTest},
+	{name: "promote", summary: "Copy a sandbox workflow to production", run: runWorkflowsPromote},
+}
+
+func runWorkflows(args []string) error {
+	return runGroup("workflows", workflowCommands, args)
+}
+
+// runWorkflowsValidate valida archivos con las mismas reglas que el
+// executor (estructura, tipos de nodo, config y plantillas), sin servidor.
+// Las referencias a canales, snippets y plantillas solo se comprueban en
+// el servidor.
+func runWorkflowsValidate(args []string) error {
+	fs := flag.NewFlagSet("workflows validate", flag.ContinueOnError)
+	tenantID := fs.String("tenant", "local", "tenant ID for files without tenant_id")
+	verbose := fs.Bool("verbose", false, "keep executor logs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: relay workflows validate [flags] <file.json>... (- reads stdin)")
+	}
+
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+	validator := newOfflineExecutor()
+
+	invalid := 0
+	for _, path := range fs.Args() {
+		var workflow engine.Workflow
+		if err := readJSONFile(path, &workflow); err != nil {
+			fmt.Printf("✗ %v\n", err)
+			invalid++
+			continue
+		}
+		if workflow.TenantID.IsEmpty() {
+			workflow.TenantID = kernel.TenantID(*tenantID)
+		}
+
+		if err := validator.ValidateWorkflow(context.Background(), workflow); err != nil {
+			fmt.Printf("✗ %s: %v\n", path, err)
+			invalid++
+			continue
+		}
+		fmt.Printf("✓ %s: %q, %d nodes\n", path, workflow.Name, len(workflow.Nodes))
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d workflows are invalid", invalid, fs.NArg())
+	}
+	return nil
+}
+
+// newOfflineExecutor registra todos los tipos de nodo sin dependencias:
+// ValidateConfig no las usa
+func newOfflineExecutor() *workflowexec.DefaultWorkflowExecutor {
+	evaluator := engine.NewCelEvaluator(nil, nil)
+	executors := []engine.NodeExecutor{
+		node.NewActionExecutor(nil),
+		node.NewConditionExecutor(),
+		node.NewDelayExecutor(nil),
+		node.NewAIAgentExecutor(nil, evaluator, nil, nil, nil),
+		node.NewSendMessageExecutor(nil, evaluator, nil, nil),
+		node.NewHTTPExecutor(evaluator, nil),
+		node.NewTransformExecutor(evaluator),
+		node.NewSwitchExecutor(),
+		node.NewLoopExecutor(),
+		node.NewValidateExecutor(),
+		node.NewBusinessHoursExecutor(nil),
+		node.NewTagExecutor(nil),
+		node.NewSurveyExecutor(nil, nil, nil),
+		node.NewExperimentExecutor(nil),
+		node.NewCRMSyncExecutor(nil),
+		node.NewTicketExecutor(nil),
+		node.NewOrderLookupExecutor(nil),
+	}
+	executors = append(executors, node.Plugins()...)
+
+	return workflowexec.NewDefaultWorkflowExecutor(evaluator, executors...)
+}
+
+// runWorkflowsTest ejecuta un script en el servidor; sale con error si
+// alguna expectativa falla, para usarlo en CI
+func runWorkflowsTest(args []string) error {
+	var api apiFlags
+	fs := flag.NewFlagSet("workflows test", flag.ContinueOnError)
+	api.register(fs)
+	workflowID := fs.String("workflow", "", "workflow ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *workflowID == "" || fs.NArg() != 1 {
+		return fmt.Errorf("usage: relay workflows test -workflow <id> [flags] <script.json>")
+	}
+
+	var script workflowtest.Script
+	if err := readJSONFile(fs.Arg(0), &script); err != nil {
+		return err
+	}
+
+	c, err := api.client(true)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := api.context()
+	defer cancel()
+
+	report, err := c.RunWorkflowTest(ctx, *workflowID, script)
+	if err != nil {
+		return err
+	}
+
+	if api.asJSON {
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	} else {
+		for i, turn := range report.Turns {
+			mark := "✓"
+			if len(turn.Failures) > 0 || !turn.Success {
+				mark = "✗"
+			}
+			fmt.Printf("%s turn %d: %q → %d responses, nodes %s\n",
+				mark, i+1, turn.Message, len(turn.Responses), strings.Join(turn.ExecutedNodes, ","))
+			if turn.Error != "" {
+				fmt.Printf("    error: %s\n", turn.Error)
+			}
+			for _, failure := range turn.Failures {
+				fmt.Printf("    %s\n", failure)
+			}
+		}
+		fmt.Printf("\n%d turns in %dms\n", len(report.Turns), report.DurationMs)
+	}
+
+	if !report.Passed {
+		return fmt.Errorf("workflow test failed")
+	}
+	return nil
+}
+
+// runWorkflowsPromote copia un workflow sandbox a producción. Los canales
+// se emparejan por nombre; -channel sandbox=produccion fuerza un par.
+func runWorkflowsPromote(args []string) error {
+	var api apiFlags
+	fs := flag.NewFlagSet("workflows promote", flag.ContinueOnError)
+	api.register(fs)
+	activate := fs.Bool("activate", false, "activate the production workflow")
+	channelMap := make(map[string]string)
+	fs.Func("channel", "sandbox=production channel ID pair (repeatable)", func(value string) error {
+		from, to, ok := strings.Cut(value, "=")
+		if !ok || from == "" || to == "" {
+			return fmt.Errorf("expected sandbox_id=production_id, got %q", value)
+		}
+		channelMap[from] = to
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: relay workflows promote [flags] <workflow-id>")
+	}
+
+	c, err := api.client(true)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := api.context()
+	defer cancel()
+
+	result, err := c.PromoteWorkflow(ctx, fs.Arg(0), promotion.PromoteRequest{
+		ChannelMap: channelMap,
+		Activate:   *activate,
+	})
+	if err != nil {
+		return err
+	}
+
+	if api.asJSON {
+		return printJSON(result)
+	}
+
+	action := "updated"
+	if result.Created {
+		action = "created"
+	}
+	fmt.Printf("✓ %q %s in production: %s (version %d, active=%v)\n",
+		result.Workflow.Name, action, result.Workflow.ID, result.Workflow.Version, result.Workflow.IsActive)
+	for _, relink := range result.Relinked {
+		fmt.Printf("    %s: %s → %s\n", relink.Channel, relink.From, relink.To)
+	}
+	return nil
+}
@@ -4,27 +4,92 @@ import (
 	"context"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/Abraxas-365/craftable/ai/llm"
 	"github.com/Abraxas-365/craftable/ai/providers/aiopenai"
+	"github.com/Abraxas-365/craftable/errx"
 	"github.com/Abraxas-365/craftable/eventx"
 	"github.com/Abraxas-365/craftable/eventx/providers/eventxmemory"
 
+	"github.com/Abraxas-365/relay/attachment"
+	"github.com/Abraxas-365/relay/attachment/attachmentapi"
+	"github.com/Abraxas-365/relay/attachment/attachmentinfra"
+	"github.com/Abraxas-365/relay/attachment/attachmentsrv"
+
 	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/channeladapters/apple"
+	"github.com/Abraxas-365/relay/channels/channeladapters/instagram"
+	"github.com/Abraxas-365/relay/channels/channeladapters/rcs"
 	whatsapp "github.com/Abraxas-365/relay/channels/channeladapters/whatssapp"
 	"github.com/Abraxas-365/relay/channels/channelapi"
 	"github.com/Abraxas-365/relay/channels/channelmanager"
 	"github.com/Abraxas-365/relay/channels/channelsinfra"
 	"github.com/Abraxas-365/relay/channels/channelsrv"
-
+	"github.com/Abraxas-365/relay/channels/httpclient"
+
+	"github.com/Abraxas-365/relay/commerce"
+	"github.com/Abraxas-365/relay/commerce/commerceapi"
+	"github.com/Abraxas-365/relay/commerce/commerceinfra"
+	"github.com/Abraxas-365/relay/commerce/commercesrv"
+
+	"github.com/Abraxas-365/relay/contact"
+	"github.com/Abraxas-365/relay/contact/contactapi"
+	"github.com/Abraxas-365/relay/contact/contactinfra"
+	"github.com/Abraxas-365/relay/contact/contactsrv"
+
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/conversation/conversationapi"
+	"github.com/Abraxas-365/relay/conversation/conversationinfra"
+	"github.com/Abraxas-365/relay/conversation/conversationsrv"
+
+	"github.com/Abraxas-365/relay/crm"
+	"github.com/Abraxas-365/relay/crm/crmapi"
+	"github.com/Abraxas-365/relay/crm/crminfra"
+	"github.com/Abraxas-365/relay/crm/crmsrv"
+
+	"github.com/Abraxas-365/relay/encryption"
+	"github.com/Abraxas-365/relay/encryption/encryptionapi"
+	"github.com/Abraxas-365/relay/encryption/encryptioninfra"
+	"github.com/Abraxas-365/relay/encryption/encryptionsrv"
 	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/engine/businesshours"
+	"github.com/Abraxas-365/relay/engine/continuation"
+	"github.com/Abraxas-365/relay/engine/deadletter"
 	"github.com/Abraxas-365/relay/engine/delayscheduler"
 	"github.com/Abraxas-365/relay/engine/engineinfra"
+	"github.com/Abraxas-365/relay/engine/faultinject"
+	"github.com/Abraxas-365/relay/engine/impact"
+	"github.com/Abraxas-365/relay/engine/inactivity"
 	"github.com/Abraxas-365/relay/engine/node"
+	"github.com/Abraxas-365/relay/engine/nodecatalog"
+	"github.com/Abraxas-365/relay/engine/promotion"
+	"github.com/Abraxas-365/relay/engine/replay"
 	"github.com/Abraxas-365/relay/engine/scheduler"
 	"github.com/Abraxas-365/relay/engine/triggerhandler"
 	"github.com/Abraxas-365/relay/engine/webhooktrigger"
 	"github.com/Abraxas-365/relay/engine/workflowexec"
+	"github.com/Abraxas-365/relay/engine/workflowtest"
+
+	"github.com/Abraxas-365/relay/experiment"
+	"github.com/Abraxas-365/relay/experiment/experimentapi"
+	"github.com/Abraxas-365/relay/experiment/experimentinfra"
+	"github.com/Abraxas-365/relay/experiment/experimentsrv"
+
+	"github.com/Abraxas-365/relay/fallback"
+	"github.com/Abraxas-365/relay/fallback/fallbackapi"
+	"github.com/Abraxas-365/relay/fallback/fallbackinfra"
+	"github.com/Abraxas-365/relay/fallback/fallbacksrv"
+
+	"github.com/Abraxas-365/relay/featureflag/featureflagapi"
+	"github.com/Abraxas-365/relay/featureflag/featureflaginfra"
+	"github.com/Abraxas-365/relay/featureflag/featureflagsrv"
+
+	"github.com/Abraxas-365/relay/helpdesk"
+	"github.com/Abraxas-365/relay/helpdesk/helpdeskapi"
+	"github.com/Abraxas-365/relay/helpdesk/helpdeskinfra"
+	"github.com/Abraxas-365/relay/helpdesk/helpdesksrv"
 
 	"github.com/Abraxas-365/relay/iam"
 	"github.com/Abraxas-365/relay/iam/auth"
@@ -33,18 +98,74 @@ import (
 	"github.com/Abraxas-365/relay/iam/role/roleinfra"
 	"github.com/Abraxas-365/relay/iam/role/rolesrv"
 	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/iam/tenant/tenantapi"
 	"github.com/Abraxas-365/relay/iam/tenant/tenantinfra"
 	"github.com/Abraxas-365/relay/iam/tenant/tenantsrv"
 	"github.com/Abraxas-365/relay/iam/user"
+	"github.com/Abraxas-365/relay/iam/user/userapi"
 	"github.com/Abraxas-365/relay/iam/user/userinfra"
 	"github.com/Abraxas-365/relay/iam/user/usersrv"
 
 	"github.com/Abraxas-365/relay/pkg/agent"
 	"github.com/Abraxas-365/relay/pkg/agent/agentinfra"
+	"github.com/Abraxas-365/relay/pkg/circuitbreaker"
 	"github.com/Abraxas-365/relay/pkg/config"
+	"github.com/Abraxas-365/relay/pkg/database"
 	"github.com/Abraxas-365/relay/pkg/kernel"
-
+	"github.com/Abraxas-365/relay/pkg/ratelimit"
+	"github.com/Abraxas-365/relay/retention"
+	"github.com/Abraxas-365/relay/retention/retentionapi"
+	"github.com/Abraxas-365/relay/retention/retentioninfra"
+	"github.com/Abraxas-365/relay/retention/retentionsrv"
+
+	"github.com/Abraxas-365/relay/manifest/manifestapi"
+	"github.com/Abraxas-365/relay/manifest/manifestsrv"
+
+	"github.com/Abraxas-365/relay/inbox"
+	"github.com/Abraxas-365/relay/inbox/inboxapi"
+	"github.com/Abraxas-365/relay/inbox/inboxinfra"
+	"github.com/Abraxas-365/relay/inbox/inboxsrv"
+	"github.com/Abraxas-365/relay/msgtemplate"
+	"github.com/Abraxas-365/relay/msgtemplate/msgtemplateapi"
+	"github.com/Abraxas-365/relay/msgtemplate/msgtemplateinfra"
+	"github.com/Abraxas-365/relay/msgtemplate/msgtemplatesrv"
+	"github.com/Abraxas-365/relay/search/searchapi"
+	"github.com/Abraxas-365/relay/search/searchsrv"
+	"github.com/Abraxas-365/relay/segment"
+	"github.com/Abraxas-365/relay/segment/segmentapi"
+	"github.com/Abraxas-365/relay/segment/segmentinfra"
+	"github.com/Abraxas-365/relay/segment/segmentsrv"
+	"github.com/Abraxas-365/relay/sequence"
+	"github.com/Abraxas-365/relay/sequence/sequenceapi"
+	"github.com/Abraxas-365/relay/sequence/sequenceinfra"
+	"github.com/Abraxas-365/relay/sequence/sequencesrv"
+	"github.com/Abraxas-365/relay/snippet"
+	"github.com/Abraxas-365/relay/snippet/snippetapi"
+	"github.com/Abraxas-365/relay/snippet/snippetinfra"
+	"github.com/Abraxas-365/relay/snippet/snippetsrv"
+	"github.com/Abraxas-365/relay/spamfilter"
+	"github.com/Abraxas-365/relay/spamfilter/spamfilterapi"
+	"github.com/Abraxas-365/relay/spamfilter/spamfilterinfra"
+	"github.com/Abraxas-365/relay/spamfilter/spamfiltersrv"
+	"github.com/Abraxas-365/relay/survey"
+	"github.com/Abraxas-365/relay/survey/surveyapi"
+	"github.com/Abraxas-365/relay/survey/surveyinfra"
+	"github.com/Abraxas-365/relay/survey/surveysrv"
+	"github.com/Abraxas-365/relay/throttle"
+	"github.com/Abraxas-365/relay/throttle/throttleapi"
+	"github.com/Abraxas-365/relay/throttle/throttleinfra"
+	"github.com/Abraxas-365/relay/throttle/throttlesrv"
+	"github.com/Abraxas-365/relay/transcript"
+	"github.com/Abraxas-365/relay/transcript/transcriptapi"
+	"github.com/Abraxas-365/relay/transcript/transcriptinfra"
+	"github.com/Abraxas-365/relay/transcript/transcriptsrv"
+
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/jobs/jobsapi"
+	"github.com/Abraxas-365/relay/jobs/jobsinfra"
+	"github.com/Abraxas-365/relay/jobs/jobssrv"
 	"github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -55,18 +176,41 @@ type Container struct {
 	// =================================================================
 	Config      *config.Config
 	DB          *sqlx.DB
+	DataRouter  *database.Router // Regional databases for messages, agent sessions and contacts
 	RedisClient *redis.Client
+	Workers     *WorkerRegistry
 
 	// =================================================================
 	// EVENT BUS ⚡
 	// =================================================================
 	EventBus eventx.EventBus
 
+	// =================================================================
+	// BACKGROUND JOBS 🧰
+	// =================================================================
+	JobRepo    jobs.Repository
+	JobService *jobssrv.JobService
+	JobRunner  *jobssrv.Runner
+	JobHandler *jobsapi.JobHandler
+	JobRoutes  *jobsapi.JobRoutes
+
+	// =================================================================
+	// RATE LIMITING 🚦
+	// =================================================================
+	RateLimiter      ratelimit.Limiter
+	RateLimitMetrics *ratelimit.Metrics
+
+	// =================================================================
+	// CIRCUIT BREAKERS 🔌
+	// =================================================================
+	CircuitBreakers *circuitbreaker.Registry // nil = disabled
+
 	// =================================================================
 	// IAM - REPOSITORIES
 	// =================================================================
 	UserRepo         user.UserRepository
 	UserRoleRepo     user.UserRoleRepository
+	MembershipRepo   user.MembershipRepository
 	TenantRepo       tenant.TenantRepository
 	TenantConfigRepo tenant.TenantConfigRepository
 	RoleRepo         role.RoleRepository
@@ -80,17 +224,59 @@ type Container struct {
 	TenantService   *tenantsrv.TenantService
 	RoleService     *rolesrv.RoleService
 
+	// =================================================================
+	// TENANT LIFECYCLE (offboarding)
+	// =================================================================
+	ExportRepo    tenant.DataExportRepository
+	ExportService *tenantsrv.ExportService
+	TenantHandler *tenantapi.TenantHandler
+	TenantRoutes  *tenantapi.TenantRoutes
+	MemberHandler *userapi.MemberHandler
+	MemberRoutes  *userapi.MemberRoutes
+
 	// =================================================================
 	// AUTH
 	// =================================================================
 	TokenRepo         auth.TokenRepository
 	SessionRepo       auth.SessionRepository
+	SessionValidator  auth.SessionValidator
 	PasswordResetRepo auth.PasswordResetRepository
+	VerificationRepo  auth.EmailVerificationRepository
+	LoginRateLimiter  auth.LoginRateLimiter
+	AuthMailer        auth.AuthMailer
 	StateManager      auth.StateManager
 	TokenService      auth.TokenService
 	OAuthServices     map[iam.OAuthProvider]auth.OAuthService
 	AuthHandlers      *auth.AuthHandlers
 	AuthMiddleware    *auth.AuthMiddleware
+	SSOConfigRepo     auth.SSOConfigRepository
+	MFARepo           auth.MFARepository
+	MFAService        *auth.MFAService
+	SSOHandlers       *auth.SSOHandlers
+
+	// =================================================================
+	// FIELD ENCRYPTION 🔐
+	// =================================================================
+	EncryptionKeyRepo encryption.KeyRepository
+	FieldCipher       *encryption.FieldCipher
+	EncryptionService *encryptionsrv.EncryptionService
+	EncryptionHandler *encryptionapi.EncryptionHandler
+	EncryptionRoutes  *encryptionapi.EncryptionRoutes
+
+	// =================================================================
+	// FEATURE FLAGS 🚩
+	// =================================================================
+	FeatureFlagService *featureflagsrv.FlagService
+	FeatureFlagHandler *featureflagapi.FeatureFlagHandler
+	FeatureFlagRoutes  *featureflagapi.FeatureFlagRoutes
+
+	// =================================================================
+	// DATA RETENTION 🗑️
+	// =================================================================
+	RetentionPolicyRepo retention.PolicyRepository
+	RetentionService    *retentionsrv.RetentionService
+	RetentionHandler    *retentionapi.RetentionHandler
+	RetentionRoutes     *retentionapi.RetentionRoutes
 
 	// =================================================================
 	// AGENT 🤖
@@ -100,45 +286,294 @@ type Container struct {
 	// =================================================================
 	// CHANNELS (Optional integration)
 	// =================================================================
-	ChannelRepo    channels.ChannelRepository
-	ChannelManager channels.ChannelManager
-	ChannelService *channelsrv.ChannelService
+	ChannelRepo     channels.ChannelRepository
+	SuppressionRepo channels.SuppressionRepository
+	ChannelManager  channels.ChannelManager
+	ChannelService  *channelsrv.ChannelService
 
 	// Channel Adapters
 	WhatsAppAdapter *whatsapp.WhatsAppAdapter
 
 	// Channel API Handlers
-	ChannelHandler         *channelapi.ChannelHandler
-	WhatsAppWebhookHandler *whatsapp.WebhookHandler
-	WhatsAppWebhookRoutes  *whatsapp.WebhookRoutes
+	ChannelHandler           *channelapi.ChannelHandler
+	ChannelManagementHandler *channelapi.ChannelManagementHandler
+	ChannelRoutes            *channelapi.ChannelRoutes
+	SimulationHandler        *channelapi.SimulationHandler
+	SimulationRoutes         *channelapi.SimulationRoutes
+	WhatsAppWebhookHandler   *whatsapp.WebhookHandler
+	WhatsAppWebhookRoutes    *whatsapp.WebhookRoutes
+	InstagramWebhookHandler  *instagram.WebhookHandler
+	InstagramWebhookRoutes   *instagram.WebhookRoutes
+	RCSWebhookHandler        *rcs.WebhookHandler
+	RCSWebhookRoutes         *rcs.WebhookRoutes
+	AppleWebhookHandler      *apple.WebhookHandler
+	AppleWebhookRoutes       *apple.WebhookRoutes
+
+	// Webhook events no adapter handles yet
+	WebhookEventRepo    channels.RawWebhookEventRepository
+	WebhookEventMetrics *channels.WebhookEventMetrics
+	WebhookEventService *channelsrv.WebhookEventService
+	WebhookEventHandler *channelapi.WebhookEventHandler
+	WebhookEventRoutes  *channelapi.WebhookEventRoutes
+
+	// Message bursts buffered in Redis, flushed by any instance
+	MessageBufferStore   channels.MessageBufferStore
+	MessageBufferService *channelsrv.BufferService
+
+	// =================================================================
+	// ATTACHMENTS 📎 (nil when ATTACHMENT_STORAGE is empty)
+	// =================================================================
+	AttachmentStorage attachment.Storage
+	AttachmentRepo    attachment.Repository
+	AttachmentService *attachmentsrv.AttachmentService
+	AttachmentHandler *attachmentapi.AttachmentHandler
+	AttachmentRoutes  *attachmentapi.AttachmentRoutes
+
+	// =================================================================
+	// CONVERSATIONS 💬
+	// =================================================================
+	MessageRepo         conversation.MessageRepository
+	MessageBatchWriter  *conversationinfra.BatchMessageWriter // nil when batching is disabled
+	TagRepo             conversation.TagRepository
+	TagService          *conversationsrv.TagService
+	ConversationHandler *conversationapi.ConversationHandler
+	ConversationRoutes  *conversationapi.ConversationRoutes
+
+	// =================================================================
+	// SEARCH 🔎
+	// =================================================================
+	SearchService *searchsrv.SearchService
+	SearchHandler *searchapi.SearchHandler
+	SearchRoutes  *searchapi.SearchRoutes
 
 	// =================================================================
 	// ENGINE (n8n-style)
 	// =================================================================
 	WorkflowRepo          engine.WorkflowRepository
+	WorkflowCache         *engineinfra.CachedWorkflowRepository
 	WorkflowExecutor      engine.WorkflowExecutor
 	ExpressionEvaluator   engine.ExpressionEvaluator
 	DelayScheduler        engine.DelayScheduler
 	TriggerHandler        *triggerhandler.TriggerHandler
 	WebhookTriggerHandler *webhooktrigger.WebhookTriggerHandler
 	WebhookTriggerRoutes  *webhooktrigger.WebhookTriggerRoutes
+	InactivityService     *inactivity.InactivityService
+
+	// Dead Letter Components
+	DeadLetterRepo    engine.DeadLetterRepository
+	DeadLetterService *deadletter.DeadLetterService
+	DeadLetterHandler *deadletter.DeadLetterHandler
+	DeadLetterRoutes  *deadletter.DeadLetterRoutes
+
+	// Continuation Components
+	ContinuationService *continuation.ContinuationService
+	ContinuationHandler *continuation.ContinuationHandler
+	ContinuationRoutes  *continuation.ContinuationRoutes
+
+	// Workflow Test Components
+	WorkflowTestHandler *workflowtest.WorkflowTestHandler
+	WorkflowTestRoutes  *workflowtest.WorkflowTestRoutes
+
+	// 🎞️ Snapshot & Replay Components
+	SnapshotRepo  engine.SnapshotRepository
+	ReplayService *replay.ReplayService
+	ReplayHandler *replay.ReplayHandler
+	ReplayRoutes  *replay.ReplayRoutes
+
+	// Promotion Components
+	PromotionService *promotion.PromotionService
+	PromotionHandler *promotion.PromotionHandler
+	PromotionRoutes  *promotion.PromotionRoutes
+
+	// Business Hours Components
+	BusinessHoursService *businesshours.BusinessHoursService
+	BusinessHoursHandler *businesshours.BusinessHoursHandler
+	BusinessHoursRoutes  *businesshours.BusinessHoursRoutes
+
+	// Fault Injection Components
+	FaultInjectionService *faultinject.FaultInjectionService
+	FaultInjectionHandler *faultinject.FaultInjectionHandler
+	FaultInjectionRoutes  *faultinject.FaultInjectionRoutes
+
+	// 🧩 Node Catalog Components
+	NodeCatalogHandler *nodecatalog.NodeCatalogHandler
+	NodeCatalogRoutes  *nodecatalog.NodeCatalogRoutes
 
 	// ✅ Schedule Components
 	ScheduleRepo      engine.WorkflowScheduleRepository
 	ScheduleService   *scheduler.ScheduleService
+	ScheduleHandler   *scheduler.ScheduleHandler
+	ScheduleRoutes    *scheduler.ScheduleRoutes
 	WorkflowScheduler *scheduler.WorkflowScheduler
 
 	// Node Executors
-	ActionExecutor      engine.NodeExecutor
-	ConditionExecutor   engine.NodeExecutor
-	DelayExecutor       engine.NodeExecutor
-	AIAgentExecutor     engine.NodeExecutor
-	SendMessageExecutor engine.NodeExecutor
-	HTTPExecutor        engine.NodeExecutor
-	TransformExecutor   engine.NodeExecutor
-	SwitchExecutor      engine.NodeExecutor
-	LoopExecutor        engine.NodeExecutor
-	ValidateExecutor    engine.NodeExecutor
+	ActionExecutor        engine.NodeExecutor
+	ConditionExecutor     engine.NodeExecutor
+	DelayExecutor         engine.NodeExecutor
+	AIAgentExecutor       engine.NodeExecutor
+	SendMessageExecutor   engine.NodeExecutor
+	HTTPExecutor          engine.NodeExecutor
+	TransformExecutor     engine.NodeExecutor
+	SwitchExecutor        engine.NodeExecutor
+	LoopExecutor          engine.NodeExecutor
+	ValidateExecutor      engine.NodeExecutor
+	BusinessHoursExecutor engine.NodeExecutor
+	TagExecutor           engine.NodeExecutor
+	SurveyExecutor        engine.NodeExecutor
+	ExperimentExecutor    engine.NodeExecutor
+	CRMSyncExecutor       engine.NodeExecutor
+	TicketExecutor        engine.NodeExecutor
+	OrderLookupExecutor   engine.NodeExecutor
+
+	// =================================================================
+	// SEQUENCES 📬
+	// =================================================================
+	SequenceRepo    sequence.SequenceRepository
+	EnrollmentRepo  sequence.EnrollmentRepository
+	SequenceService *sequencesrv.SequenceService
+	SequenceHandler *sequenceapi.SequenceHandler
+	SequenceRoutes  *sequenceapi.SequenceRoutes
+
+	// =================================================================
+	// CHANGE IMPACT 🔍
+	// =================================================================
+	ImpactService *impact.ImpactService
+	ImpactHandler *impact.ImpactHandler
+	ImpactRoutes  *impact.ImpactRoutes
+
+	// =================================================================
+	// SNIPPETS 📝
+	// =================================================================
+	SnippetRepo    snippet.SnippetRepository
+	SnippetService *snippetsrv.SnippetService
+	SnippetHandler *snippetapi.SnippetHandler
+	SnippetRoutes  *snippetapi.SnippetRoutes
+
+	// =================================================================
+	// MESSAGE TEMPLATES 🌐
+	// =================================================================
+	MessageTemplateRepo    msgtemplate.TemplateRepository
+	MessageTemplateService *msgtemplatesrv.TemplateService
+	MessageTemplateHandler *msgtemplateapi.TemplateHandler
+	MessageTemplateRoutes  *msgtemplateapi.TemplateRoutes
+
+	// =================================================================
+	// SURVEYS ⭐
+	// =================================================================
+	SurveyRepo    survey.SurveyRepository
+	SurveyService *surveysrv.SurveyService
+	SurveyHandler *surveyapi.SurveyHandler
+	SurveyRoutes  *surveyapi.SurveyRoutes
+
+	// =================================================================
+	// EXPERIMENTS 🧪
+	// =================================================================
+	ExperimentEventRepo experiment.EventRepository
+	ExperimentService   *experimentsrv.ExperimentService
+	ExperimentHandler   *experimentapi.ExperimentHandler
+	ExperimentRoutes    *experimentapi.ExperimentRoutes
+
+	// =================================================================
+	// CRM 🤝
+	// =================================================================
+	CRMConnectionRepo crm.ConnectionRepository
+	CRMService        *crmsrv.CRMService
+	CRMHandler        *crmapi.CRMHandler
+	CRMRoutes         *crmapi.CRMRoutes
+
+	// =================================================================
+	// HELPDESK 🎫
+	// =================================================================
+	HelpdeskConnectionRepo helpdesk.ConnectionRepository
+	HelpdeskTicketLinkRepo helpdesk.TicketLinkRepository
+	HelpdeskService        *helpdesksrv.HelpdeskService
+	HelpdeskHandler        *helpdeskapi.HelpdeskHandler
+	HelpdeskRoutes         *helpdeskapi.HelpdeskRoutes
+
+	// =================================================================
+	// COMMERCE 🛍️
+	// =================================================================
+	CommerceConnectionRepo commerce.ConnectionRepository
+	CommerceService        *commercesrv.CommerceService
+	CommerceHandler        *commerceapi.CommerceHandler
+	CommerceRoutes         *commerceapi.CommerceRoutes
+
+	// =================================================================
+	// TRANSCRIPT EXPORTS 📦
+	// =================================================================
+	TranscriptExportRepo    transcript.ExportJobRepository
+	TranscriptExportService *transcriptsrv.ExportService
+	TranscriptHandler       *transcriptapi.TranscriptHandler
+	TranscriptRoutes        *transcriptapi.TranscriptRoutes
+
+	// =================================================================
+	// MESSAGE ARCHIVES 🗄️
+	// =================================================================
+	MessageArchiveService *conversationsrv.ArchiveService
+	ArchiveHandler        *conversationapi.ArchiveHandler
+	ArchiveRoutes         *conversationapi.ArchiveRoutes
+
+	// =================================================================
+	// CONTACTS 📇
+	// =================================================================
+	ContactRepo          contact.ContactRepository
+	ContactImportRepo    contact.ImportRepository
+	ContactService       *contactsrv.ContactService
+	ContactImportService *contactsrv.ImportService
+	ContactHandler       *contactapi.ContactHandler
+	ContactRoutes        *contactapi.ContactRoutes
+
+	// =================================================================
+	// SEGMENTS 🎯
+	// =================================================================
+	SegmentRepo       segment.SegmentRepository
+	SegmentMemberRepo segment.MemberRepository
+	SegmentService    *segmentsrv.SegmentService
+	SegmentHandler    *segmentapi.SegmentHandler
+	SegmentRoutes     *segmentapi.SegmentRoutes
+
+	// =================================================================
+	// AGENT INBOX 🙋
+	// =================================================================
+	ClaimRepo    inbox.ClaimRepository
+	InboxService *inboxsrv.InboxService
+	InboxHub     *inboxsrv.Hub
+	InboxHandler *inboxapi.InboxHandler
+	InboxRoutes  *inboxapi.InboxRoutes
+
+	// =================================================================
+	// SPAM FILTER 🚫
+	// =================================================================
+	SpamPolicyRepo    spamfilter.PolicyRepository
+	SpamBlockRepo     spamfilter.BlockRepository
+	SpamFilterMetrics *spamfilter.Metrics
+	SpamFilterService *spamfiltersrv.FilterService
+	SpamFilterHandler *spamfilterapi.SpamFilterHandler
+	SpamFilterRoutes  *spamfilterapi.SpamFilterRoutes
+
+	// =================================================================
+	// THROTTLING 🐢
+	// =================================================================
+	ThrottleSettingsRepo throttle.SettingsRepository
+	ThrottleService      *throttlesrv.ThrottleService
+	ThrottleHandler      *throttleapi.ThrottleHandler
+	ThrottleRoutes       *throttleapi.ThrottleRoutes
+
+	// =================================================================
+	// FALLBACK ↪️
+	// =================================================================
+	FallbackSettingsRepo fallback.SettingsRepository
+	UnmatchedCounterRepo fallback.CounterRepository
+	FallbackService      *fallbacksrv.FallbackService
+	FallbackHandler      *fallbackapi.FallbackHandler
+	FallbackRoutes       *fallbackapi.FallbackRoutes
+
+	// =================================================================
+	// DECLARATIVE CONFIGURATION 📋
+	// =================================================================
+	ManifestService *manifestsrv.ManifestService
+	ManifestHandler *manifestapi.ManifestHandler
+	ManifestRoutes  *manifestapi.ManifestRoutes
 
 	// =================================================================
 	// AI/LLM 🤖
@@ -152,19 +587,49 @@ func NewContainer(cfg *config.Config, db *sqlx.DB, redisClient *redis.Client) *C
 		Config:      cfg,
 		DB:          db,
 		RedisClient: redisClient,
+		Workers:     NewWorkerRegistry(),
 	}
 
 	// Initialize dependencies in the correct order
 	log.Println("📦 Initializing dependency container...")
 
 	c.initEventBus()
+	c.initRateLimiting()
+	c.initCircuitBreakers()
 	c.initIAMRepositories()
 	c.initIAMServices()
+	c.initDataRegions() // 🌍 Tenant data routed to its region's database
 	c.initAuthServices()
-	c.initAgentComponents()   // 🤖 Agent components (needed by AI executor)
-	c.initLLMComponents()     // LLM (needed by AI executor)
-	c.initChannelComponents() // ⚡ Channels (optional integration)
-	c.initEngineComponents()  // ⚙️ Engine components
+	c.initJobComponents()        // 🧰 Shared queue for recurring and background work
+	c.initFeatureFlags()         // 🚩 Consulted by channels and engine at runtime
+	c.initEncryptionComponents() // 🔐 Field cipher used by the message store
+	c.initRetentionComponents()  // 🗑️ Redaction wraps the stores built below
+	c.initAgentComponents()      // 🤖 Agent components (needed by AI executor)
+	c.initLLMComponents()        // LLM (needed by AI executor)
+	c.initChannelComponents()    // ⚡ Channels (optional integration)
+	c.initAttachmentComponents() // 📎 Inbound media, fetched through channel adapters
+	c.initTranscriptComponents() // 📦 Transcript exports, stored with the attachments
+	c.initArchiveComponents()    // 🗄️ Monthly message partitions, old ones archived with the attachments
+	c.initContactComponents()    // 📇 Contacts, imported from files kept with the attachments
+	c.initSegmentComponents()    // 🎯 Contact segments, checked by contact.in_segment()
+	c.initSnippetComponents()    // 📝 Canned replies used by operators and SEND_MESSAGE nodes
+	c.initTemplateComponents()   // 🌐 Localized messages used by SEND_MESSAGE nodes
+	c.initExperimentComponents() // 🧪 A/B test events recorded by EXPERIMENT nodes
+	c.initCRMComponents()        // 🤝 HubSpot and Salesforce written by CRM_SYNC nodes
+	c.initHelpdeskComponents()   // 🎫 Zendesk and Freshdesk tickets written by TICKET nodes
+	c.initCommerceComponents()   // 🛍️ Shopify and WooCommerce orders found by ORDER_LOOKUP nodes
+	c.initEngineComponents()     // ⚙️ Engine components
+	c.initSequenceComponents()   // 📬 Drip sequences send through channels and run workflows
+	c.initImpactComponents()     // 🔍 Checks paused runs, schedules and sequences before publishing
+	c.initInboxComponents()      // 🙋 Operators claim conversations and follow them live
+	c.initSpamFilterComponents() // 🚫 Screens inbound messages before they are recorded
+	c.initThrottleComponents()   // 🐢 Caps how often one sender triggers workflows
+	c.initFallbackComponents()   // ↪️ Answers channel messages no workflow matched
+	c.initManifestComponents()   // 📋 Applies channels, roles and workflows declared in YAML
+	c.initTenantLifecycle()      // 🏢 Cascades need channels, schedules and sessions
+
+	// 🧰 Started last, once every feature registered its job handlers
+	go c.Workers.Run("job_runner", func() { c.JobRunner.Start(context.Background()) })
 
 	log.Println("✅ Dependency container initialized successfully")
 
@@ -197,6 +662,56 @@ func (c *Container) initEventBus() {
 	log.Println("  ✅ Event bus initialized and connected")
 }
 
+// =================================================================
+// RATE LIMITING INITIALIZATION 🚦
+// =================================================================
+
+func (c *Container) initRateLimiting() {
+	log.Println("  🚦 Initializing rate limiting...")
+
+	c.RateLimiter = ratelimit.NewRedisLimiter(c.RedisClient)
+	c.RateLimitMetrics = ratelimit.NewMetrics()
+
+	if !c.Config.RateLimit.Enabled {
+		log.Println("  ⚠️  RATE_LIMIT_ENABLED=false, requests will not be throttled")
+	}
+}
+
+// RateLimit crea el middleware de un ámbito; con el rate limiting desactivado no limita nada
+func (c *Container) RateLimit(scope string, rule ratelimit.Rule, keyFunc ratelimit.KeyFunc) fiber.Handler {
+	if !c.Config.RateLimit.Enabled {
+		rule = ratelimit.Rule{}
+	}
+	return ratelimit.New(c.RateLimiter, ratelimit.Config{
+		Scope:   scope,
+		Rule:    rule,
+		KeyFunc: keyFunc,
+		Metrics: c.RateLimitMetrics,
+	})
+}
+
+// =================================================================
+// CIRCUIT BREAKER INITIALIZATION 🔌
+// =================================================================
+
+func (c *Container) initCircuitBreakers() {
+	if !c.Config.Channels.CircuitBreakerEnabled {
+		log.Println("  ⚠️  CIRCUIT_BREAKER_ENABLED=false, provider outages will not be short-circuited")
+		return
+	}
+
+	log.Println("  🔌 Initializing circuit breakers...")
+	c.CircuitBreakers = circuitbreaker.NewRegistry(c.Config.Channels.CircuitBreaker)
+
+	// State changes are published so alerting can subscribe to them
+	c.CircuitBreakers.OnStateChange(func(change circuitbreaker.StateChange) {
+		event := eventx.NewEvent(circuitbreaker.EventStateChanged, change)
+		if err := c.EventBus.Publish(context.Background(), event); err != nil {
+			log.Printf("⚠️  Failed to publish circuit state change for %s: %v", change.Name, err)
+		}
+	})
+}
+
 // =================================================================
 // IAM INITIALIZATION
 // =================================================================
@@ -205,6 +720,7 @@ func (c *Container) initIAMRepositories() {
 	log.Println("  👥 Initializing IAM repositories...")
 	c.UserRepo = userinfra.NewPostgresUserRepository(c.DB)
 	c.UserRoleRepo = userinfra.NewPostgresUserRoleRepository(c.DB)
+	c.MembershipRepo = userinfra.NewPostgresMembershipRepository(c.DB)
 	c.TenantRepo = tenantinfra.NewPostgresTenantRepository(c.DB)
 	c.TenantConfigRepo = tenantinfra.NewPostgresTenantConfigRepository(c.DB)
 	c.RoleRepo = roleinfra.NewPostgresRoleRepository(c.DB)
@@ -221,6 +737,7 @@ func (c *Container) initIAMServices() {
 		c.TenantRepo,
 		c.RoleRepo,
 		c.PasswordService,
+		c.MembershipRepo,
 	)
 
 	c.TenantService = tenantsrv.NewTenantService(
@@ -234,6 +751,35 @@ func (c *Container) initIAMServices() {
 		c.RolePermRepo,
 		c.TenantRepo,
 	)
+	c.RoleService.SetUserRoleRepository(c.UserRoleRepo)
+}
+
+// =================================================================
+// DATA RESIDENCY INITIALIZATION 🌍
+// =================================================================
+
+func (c *Container) initDataRegions() {
+	log.Println("  🌍 Initializing data regions...")
+
+	regions, err := database.OpenRegions(c.Config.Residency)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to data regions: %v", err)
+	}
+
+	// A tenant without data.region lives in the primary database
+	c.DataRouter = database.NewRouter(c.Config.Residency.PrimaryRegion, c.DB, regions, func(ctx context.Context, tenantID kernel.TenantID) (string, error) {
+		settings, err := c.TenantConfigRepo.FindByTenant(ctx, tenantID)
+		if err != nil {
+			return "", err
+		}
+		return settings[tenant.TenantSettingDataRegion], nil
+	})
+	c.TenantService.SetDataRegions(c.Config.Residency.PrimaryRegion, c.DataRouter.RegionNames())
+
+	if len(regions) > 0 {
+		log.Printf("    ✅ Data regions: %s", strings.Join(c.DataRouter.RegionNames(), ", "))
+	}
+	log.Println("  ✅ Data regions initialized")
 }
 
 func (c *Container) initAuthServices() {
@@ -241,8 +787,31 @@ func (c *Container) initAuthServices() {
 
 	c.TokenRepo = authinfra.NewPostgresTokenRepository(c.DB)
 	c.SessionRepo = authinfra.NewPostgresSessionRepository(c.DB)
+	c.SessionValidator = authinfra.NewRedisSessionValidator(c.RedisClient, c.SessionRepo, 30*time.Second)
 	c.PasswordResetRepo = authinfra.NewPostgresPasswordResetRepository(c.DB)
+	c.VerificationRepo = authinfra.NewPostgresEmailVerificationRepository(c.DB)
 	c.StateManager = authinfra.NewRedisStateManager(c.RedisClient)
+	c.LoginRateLimiter = authinfra.NewRedisLoginRateLimiter(
+		c.RedisClient,
+		c.Config.Auth.Password.LoginRateLimit,
+		c.Config.Auth.Password.LoginRateWindow,
+	)
+	c.AuthMailer = authinfra.NewLogMailer(c.Config.Auth.Password.AppBaseURL)
+
+	// 2FA: sin clave dedicada se deriva del secreto JWT
+	mfaKey := c.Config.Auth.MFA.EncryptionKey
+	if mfaKey == "" {
+		log.Println("  ⚠️  MFA_ENCRYPTION_KEY not set, deriving TOTP encryption key from JWT secret")
+		mfaKey = c.Config.Auth.JWT.SecretKey
+	}
+	c.MFARepo = authinfra.NewPostgresMFARepository(c.DB)
+	c.MFAService = auth.NewMFAService(
+		c.MFARepo,
+		c.TenantConfigRepo,
+		authinfra.NewAESSecretCipher(mfaKey),
+		c.StateManager,
+		c.Config.Auth.MFA.Issuer,
+	)
 
 	c.TokenService = auth.NewJWTService(
 		c.Config.Auth.JWT.SecretKey,
@@ -274,10 +843,143 @@ func (c *Container) initAuthServices() {
 		c.TenantRepo,
 		c.TokenRepo,
 		c.SessionRepo,
+		c.SessionValidator,
 		c.StateManager,
+		c.MembershipRepo,
+		c.PasswordService,
+		c.PasswordResetRepo,
+		c.VerificationRepo,
+		c.LoginRateLimiter,
+		c.AuthMailer,
+		c.Config.Auth.Password,
+		c.MFAService,
+	)
+	c.AuthHandlers.SetTenantConfigRepository(c.TenantConfigRepo) // Password signup only where the tenant allows it
+
+	c.AuthMiddleware = auth.NewAuthMiddleware(c.TokenService, c.SessionValidator)
+
+	// SSO empresarial (OIDC / SAML) configurado por tenant
+	c.SSOConfigRepo = authinfra.NewPostgresSSOConfigRepository(c.DB)
+	c.SSOHandlers = auth.NewSSOHandlers(
+		c.AuthHandlers,
+		c.SSOConfigRepo,
+		c.UserRoleRepo,
+		c.RoleRepo,
+		auth.NewOIDCClient(c.Config.Auth.SSO.OIDCCallbackURL()),
+		auth.NewSAMLServiceProvider(c.Config.Auth.SSO.GetSPEntityID(), c.Config.Auth.SSO.SAMLACSURL()),
+	)
+}
+
+// =================================================================
+// FEATURE FLAGS INITIALIZATION 🚩
+// =================================================================
+
+func (c *Container) initFeatureFlags() {
+	log.Println("  🚩 Initializing feature flags...")
+
+	c.FeatureFlagService = featureflagsrv.NewFlagService(
+		featureflaginfra.NewPostgresGlobalRepository(c.DB),
+		c.TenantConfigRepo,
+		featureflaginfra.NewRedisCache(c.RedisClient, time.Minute),
+	)
+	c.FeatureFlagHandler = featureflagapi.NewFeatureFlagHandler(c.FeatureFlagService)
+	c.FeatureFlagRoutes = featureflagapi.NewFeatureFlagRoutes(c.FeatureFlagHandler, c.AuthMiddleware)
+
+	go c.Workers.Run("feature_flag_listener", func() { c.FeatureFlagService.Start(context.Background()) })
+
+	log.Println("  ✅ Feature flags initialized")
+}
+
+// =================================================================
+// BACKGROUND JOBS INITIALIZATION 🧰
+// =================================================================
+
+func (c *Container) initJobComponents() {
+	log.Println("  🧰 Initializing background jobs...")
+
+	c.JobRepo = jobsinfra.NewPostgresJobRepository(c.DB)
+	c.JobService = jobssrv.NewJobService(c.JobRepo, c.Config.Jobs.RunHistory)
+	c.JobRunner = jobssrv.NewRunner(c.JobRepo, c.Config.Jobs.Concurrency, c.Config.Jobs.PollInterval)
+	c.JobHandler = jobsapi.NewJobHandler(c.JobService)
+	c.JobRoutes = jobsapi.NewJobRoutes(c.JobHandler, c.AuthMiddleware)
+
+	c.scheduleSystemJob(jobssrv.KindPruneRuns, "@hourly", c.JobService.PruneRuns, jobs.Options{})
+
+	log.Println("  ✅ Background jobs initialized")
+}
+
+// scheduleSystemJob registers a handler and makes sure its recurring system
+// job exists. Every instance does it; the job is stored once.
+func (c *Container) scheduleSystemJob(kind, schedule string, handler jobs.Handler, options jobs.Options) {
+	c.JobRunner.Register(kind, handler, options)
+
+	if _, err := c.JobService.Schedule(context.Background(), jobs.ScheduleRequest{
+		Kind:     kind,
+		Schedule: schedule,
+	}); err != nil {
+		log.Printf("    ⚠️  Failed to schedule %s: %v", kind, err)
+		return
+	}
+	log.Printf("    ✅ %s scheduled (%s)", kind, schedule)
+}
+
+// =================================================================
+// FIELD ENCRYPTION INITIALIZATION 🔐
+// =================================================================
+
+func (c *Container) initEncryptionComponents() {
+	log.Println("  🔐 Initializing field encryption...")
+
+	var wrapper encryption.KeyWrapper
+	if masterKey := c.Config.Encryption.MasterKey; masterKey != "" {
+		wrapper = encryptioninfra.NewAESKeyWrapper(masterKey)
+	} else {
+		log.Println("  ⚠️  FIELD_ENCRYPTION_KEY not set, per-tenant encryption will be unavailable")
+	}
+
+	reencryptor := encryptioninfra.NewPostgresMessageReencryptor(c.DB)
+	reencryptor.SetRouter(c.DataRouter)
+
+	c.EncryptionKeyRepo = encryptioninfra.NewPostgresKeyRepository(c.DB)
+	c.FieldCipher = encryption.NewFieldCipher(c.EncryptionKeyRepo, wrapper)
+	c.EncryptionService = encryptionsrv.NewEncryptionService(
+		c.EncryptionKeyRepo,
+		wrapper,
+		c.FieldCipher,
+		reencryptor,
+	)
+	c.EncryptionHandler = encryptionapi.NewEncryptionHandler(c.EncryptionService)
+	c.EncryptionRoutes = encryptionapi.NewEncryptionRoutes(c.EncryptionHandler, c.AuthMiddleware)
+
+	log.Println("  ✅ Field encryption initialized")
+}
+
+// =================================================================
+// DATA RETENTION INITIALIZATION 🗑️
+// =================================================================
+
+func (c *Container) initRetentionComponents() {
+	log.Println("  🗑️  Initializing data retention...")
+
+	purger := retentioninfra.NewPostgresPurger(c.DB)
+	purger.SetRouter(c.DataRouter)
+
+	c.RetentionPolicyRepo = retentioninfra.NewPostgresPolicyRepository(c.DB)
+	c.RetentionService = retentionsrv.NewRetentionService(
+		c.RetentionPolicyRepo,
+		purger,
+	)
+	c.RetentionHandler = retentionapi.NewRetentionHandler(c.RetentionService)
+	c.RetentionRoutes = retentionapi.NewRetentionRoutes(c.RetentionHandler, c.AuthMiddleware)
+
+	c.scheduleSystemJob(
+		retentionsrv.PurgeJobKind,
+		"@every "+c.Config.Retention.PurgeInterval.String(),
+		c.RetentionService.RunPurgeJob,
+		jobs.Options{},
 	)
 
-	c.AuthMiddleware = auth.NewAuthMiddleware(c.TokenService)
+	log.Println("  ✅ Data retention initialized")
 }
 
 // =================================================================
@@ -287,8 +989,10 @@ func (c *Container) initAuthServices() {
 func (c *Container) initAgentComponents() {
 	log.Println("  🤖 Initializing agent components...")
 
-	// Initialize agent chat repository
-	c.AgentChatRepo = agentinfra.NewPostgresAgentChatRepository(c.DB)
+	// Initialize agent chat repository (PII redacted per tenant policy)
+	chatRepo := agentinfra.NewPostgresAgentChatRepository(c.DB)
+	chatRepo.SetRouter(c.DataRouter)
+	c.AgentChatRepo = retentionsrv.NewRedactingAgentChatRepository(chatRepo, c.RetentionService)
 	log.Println("    ✅ AgentChatRepo initialized")
 
 	log.Println("  ✅ Agent components initialized")
@@ -322,16 +1026,57 @@ func (c *Container) initChannelComponents() {
 
 	// Initialize channel repository
 	c.ChannelRepo = channelsinfra.NewPostgresChannelRepository(c.DB)
+	suppressionRepo := channelsinfra.NewPostgresSuppressionRepository(c.DB)
+	suppressionRepo.SetRouter(c.DataRouter)
+	c.SuppressionRepo = suppressionRepo
 	log.Println("    ✅ Channel repository initialized")
 
+	// Initialize conversation message store (transcripts, PII redacted per tenant policy)
+	postgresMessages := conversationinfra.NewPostgresMessageRepository(c.DB, c.FieldCipher)
+	postgresMessages.SetRetentionWindow(c.RetentionService) // Expired messages are never read back
+	postgresMessages.SetRouter(c.DataRouter)
+	var messageStore conversation.MessageRepository = postgresMessages
+	if batch := c.Config.MessageBatch; batch.Enabled {
+		// Concurrent webhook saves share COPY batches
+		c.MessageBatchWriter = conversationinfra.NewBatchMessageWriter(postgresMessages, batch.MaxSize, batch.MaxDelay)
+		go c.Workers.Run("message_batch_writer", func() { c.MessageBatchWriter.Start(context.Background()) })
+		messageStore = c.MessageBatchWriter
+	}
+	c.MessageRepo = retentionsrv.NewRedactingMessageRepository(messageStore, c.RetentionService)
+	tagRepo := conversationinfra.NewPostgresTagRepository(c.DB)
+	tagRepo.SetRouter(c.DataRouter)
+	c.TagRepo = tagRepo
+	c.TagService = conversationsrv.NewTagService(c.TagRepo)
+	c.ConversationHandler = conversationapi.NewConversationHandler(c.MessageRepo, c.TagService)
+	c.ConversationRoutes = conversationapi.NewConversationRoutes(c.ConversationHandler)
+	log.Println("    ✅ Conversation message repository initialized")
+
+	// Full-text search over the same store, bounded by the same retention
+	c.SearchService = searchsrv.NewSearchService(conversationinfra.NewPostgresMessageIndex(postgresMessages))
+	c.SearchHandler = searchapi.NewSearchHandler(c.SearchService)
+	c.SearchRoutes = searchapi.NewSearchRoutes(c.SearchHandler)
+	log.Println("    ✅ Message search initialized")
+
+	// Outgoing provider calls share one pooled client per channel type
+	if proxy := c.Config.Channels.HTTPProxy; proxy != "" {
+		httpclient.SetProxy(proxy)
+	}
+
 	// Initialize the channel manager
-	c.ChannelManager = channelmanager.NewDefaultChannelManager(c.ChannelRepo, c.RedisClient)
+	channelManager := channelmanager.NewDefaultChannelManager(
+		c.ChannelRepo,
+		c.MessageRepo,
+		c.FeatureFlagService,
+		c.CircuitBreakers,
+	)
+	c.FeatureFlagService.AddListener(channelManager) // Cached adapters follow flag changes
+	channelManager.SetEventBus(c.EventBus)           // response.sent for every delivered message
+	c.ChannelManager = channelManager
 	log.Println("    ✅ Channel manager initialized")
 
 	// Initialize WhatsApp adapter (base instance)
 	c.WhatsAppAdapter = whatsapp.NewWhatsAppAdapter(
 		channels.WhatsAppConfig{}, // Empty config, overridden per channel
-		c.RedisClient,
 	)
 
 	// Initialize channel service
@@ -339,9 +1084,16 @@ func (c *Container) initChannelComponents() {
 		c.ChannelRepo,
 		c.TenantRepo,
 		c.ChannelManager,
+		c.SuppressionRepo,
 	)
+	c.ChannelService.SetChannelPurger(postgresMessages) // Messages have no foreign key to their channel
 	log.Println("    ✅ Channel service initialized")
 
+	// Initialize channel management API
+	c.ChannelManagementHandler = channelapi.NewChannelManagementHandler(c.ChannelService)
+	c.ChannelRoutes = channelapi.NewChannelRoutes(c.ChannelManagementHandler, c.AuthMiddleware)
+	log.Println("    ✅ Channel management routes initialized")
+
 	log.Println("  ✅ Channel components initialized")
 }
 
@@ -352,16 +1104,28 @@ func (c *Container) initChannelComponents() {
 func (c *Container) initEngineComponents() {
 	log.Println("  ⚙️  Initializing engine components (n8n-style)...")
 
-	// Initialize workflow repository
-	c.WorkflowRepo = engineinfra.NewPostgresWorkflowRepository(c.DB)
+	// Initialize workflow repository (cached: every incoming message looks workflows up)
+	c.WorkflowCache = engineinfra.NewCachedWorkflowRepository(
+		engineinfra.NewPostgresWorkflowRepository(c.DB),
+		c.RedisClient,
+		time.Minute,
+	)
+	c.WorkflowRepo = c.WorkflowCache
+	go c.Workers.Run("workflow_cache_listener", func() { c.WorkflowCache.Start(context.Background()) })
 	log.Println("    ✅ Workflow repository initialized")
 
 	// ✅ Initialize schedule repository
 	c.ScheduleRepo = engineinfra.NewPostgresScheduleRepository(c.DB)
 	log.Println("    ✅ Schedule repository initialized")
 
+	// Initialize business hours (used by BUSINESS_HOURS nodes and in_business_hours())
+	c.BusinessHoursService = businesshours.NewBusinessHoursService(c.TenantConfigRepo)
+	c.BusinessHoursHandler = businesshours.NewBusinessHoursHandler(c.BusinessHoursService)
+	c.BusinessHoursRoutes = businesshours.NewBusinessHoursRoutes(c.BusinessHoursHandler, c.AuthMiddleware)
+	log.Println("    ✅ Business hours service initialized")
+
 	// Initialize expression evaluator
-	c.ExpressionEvaluator = engine.NewCelEvaluator()
+	c.ExpressionEvaluator = engine.NewCelEvaluator(c.BusinessHoursService, c.SegmentService)
 	log.Println("    ✅ Expression evaluator initialized")
 
 	// ⏰ Initialize delay scheduler with continuation handler
@@ -374,25 +1138,40 @@ func (c *Container) initEngineComponents() {
 	// Start delay scheduler worker
 	ctx := context.Background()
 	c.DelayScheduler.StartWorker(ctx)
+	c.Workers.Started("delay_scheduler")
 	log.Println("    ✅ Delay scheduler worker started")
 
+	c.ContinuationService = continuation.NewContinuationService(c.DelayScheduler)
+	c.ContinuationHandler = continuation.NewContinuationHandler(c.ContinuationService)
+	c.ContinuationRoutes = continuation.NewContinuationRoutes(c.ContinuationHandler, c.AuthMiddleware)
+
+	// Surveys pause on the delay scheduler, so they come right after it
+	c.initSurveyComponents()
+
 	// Initialize node executors
-	c.ActionExecutor = node.NewActionExecutor()
+	c.ActionExecutor = node.NewActionExecutor(c.TagService)
 	c.ConditionExecutor = node.NewConditionExecutor()
 	c.DelayExecutor = node.NewDelayExecutor(c.DelayScheduler)
-	c.AIAgentExecutor = node.NewAIAgentExecutor(c.AgentChatRepo, c.ExpressionEvaluator)
-	c.SendMessageExecutor = node.NewSendMessageExecutor(c.ChannelManager, c.ExpressionEvaluator)
-	c.HTTPExecutor = node.NewHTTPExecutor(c.ExpressionEvaluator)
+	// Las respuestas de IA en streaming se entregan a través del channel manager
+	streamer, _ := c.ChannelManager.(channels.MessageStreamer)
+	c.AIAgentExecutor = node.NewAIAgentExecutor(c.AgentChatRepo, c.ExpressionEvaluator, c.FeatureFlagService, c.CircuitBreakers, streamer)
+	c.SendMessageExecutor = node.NewSendMessageExecutor(c.ChannelManager, c.ExpressionEvaluator, c.SnippetService, c.MessageTemplateService)
+	c.HTTPExecutor = node.NewHTTPExecutor(c.ExpressionEvaluator, c.CircuitBreakers)
 	c.TransformExecutor = node.NewTransformExecutor(c.ExpressionEvaluator)
 	c.SwitchExecutor = node.NewSwitchExecutor()
 	c.LoopExecutor = node.NewLoopExecutor()
 	c.ValidateExecutor = node.NewValidateExecutor()
+	c.BusinessHoursExecutor = node.NewBusinessHoursExecutor(c.BusinessHoursService)
+	c.TagExecutor = node.NewTagExecutor(c.TagService)
+	c.SurveyExecutor = node.NewSurveyExecutor(c.ChannelManager, c.DelayScheduler, c.SurveyService)
+	c.ExperimentExecutor = node.NewExperimentExecutor(c.ExperimentService)
+	c.CRMSyncExecutor = node.NewCRMSyncExecutor(c.CRMService)
+	c.TicketExecutor = node.NewTicketExecutor(c.HelpdeskService)
+	c.OrderLookupExecutor = node.NewOrderLookupExecutor(c.CommerceService)
 
-	log.Println("    ✅ Node executors initialized (10 types)")
+	log.Printf("    ✅ Node executors initialized (%d types)", len(engine.BuiltinNodeTypes))
 
-	// Initialize workflow executor (n8n-style)
-	c.WorkflowExecutor = workflowexec.NewDefaultWorkflowExecutor(
-		c.ExpressionEvaluator,
+	nodeExecutors := []engine.NodeExecutor{
 		c.ActionExecutor,
 		c.ConditionExecutor,
 		c.DelayExecutor,
@@ -403,14 +1182,102 @@ func (c *Container) initEngineComponents() {
 		c.SwitchExecutor,
 		c.LoopExecutor,
 		c.ValidateExecutor,
+		c.BusinessHoursExecutor,
+		c.TagExecutor,
+		c.SurveyExecutor,
+		c.ExperimentExecutor,
+		c.CRMSyncExecutor,
+		c.TicketExecutor,
+		c.OrderLookupExecutor,
+	}
+
+	// Custom node types registered by plugin packages (node.RegisterPlugin)
+	if plugins := node.Plugins(); len(plugins) > 0 {
+		nodeExecutors = append(nodeExecutors, plugins...)
+		log.Printf("    🧩 %d custom node plugin(s) loaded", len(plugins))
+	}
+
+	// Rules can be managed in any environment; they only apply where enabled
+	c.FaultInjectionService = faultinject.NewFaultInjectionService(
+		c.TenantConfigRepo,
+		c.FeatureFlagService,
+		c.Config.Engine.FaultInjectionEnabled,
 	)
+	c.FaultInjectionHandler = faultinject.NewFaultInjectionHandler(c.FaultInjectionService)
+	c.FaultInjectionRoutes = faultinject.NewFaultInjectionRoutes(c.FaultInjectionHandler, c.AuthMiddleware)
+	if c.Config.Engine.FaultInjectionEnabled {
+		nodeExecutors = faultinject.Wrap(c.FaultInjectionService, nodeExecutors...)
+		log.Printf("    🧪 Fault injection enabled (environment: %s)", c.Config.Server.Environment)
+	}
+
+	// Initialize workflow executor (n8n-style)
+	workflowExecutor := workflowexec.NewDefaultWorkflowExecutor(
+		c.ExpressionEvaluator,
+		nodeExecutors...,
+	)
+
+	// Validation reports every channel and snippet the workflow references
+	// that does not exist for the tenant
+	workflowExecutor.SetResourceResolver(engine.ResourceResolvers{
+		engine.ResourceChannel: func(ctx context.Context, tenantID kernel.TenantID, id string) (bool, error) {
+			_, err := c.ChannelRepo.FindByID(ctx, kernel.ChannelID(id), tenantID)
+			return resourceFound(err)
+		},
+		engine.ResourceSnippet: func(ctx context.Context, tenantID kernel.TenantID, id string) (bool, error) {
+			_, err := c.SnippetRepo.FindByID(ctx, id, tenantID)
+			return resourceFound(err)
+		},
+		engine.ResourceMessageTemplate: func(ctx context.Context, tenantID kernel.TenantID, key string) (bool, error) {
+			_, err := c.MessageTemplateRepo.FindByKey(ctx, key, tenantID)
+			return resourceFound(err)
+		},
+	})
+
+	// Large HTTP/AI outputs are truncated before they reach the context,
+	// optionally spilled to the attachment storage
+	var spiller engine.OutputSpiller
+	if c.Config.Engine.SpillNodeOutputs && c.AttachmentStorage != nil {
+		spiller = engineinfra.NewStorageOutputSpiller(c.AttachmentStorage)
+	}
+	workflowExecutor.SetOutputLimit(c.Config.Engine.MaxNodeOutputBytes, spiller)
+
+	// Runs are published on the event bus so consumers don't couple to the executor
+	workflowExecutor.SetEventBus(c.EventBus)
+
+	// Workflows with debug on record a snapshot at every node for replay
+	c.SnapshotRepo = engineinfra.NewPostgresSnapshotRepository(c.DB)
+	workflowExecutor.SetSnapshotRepository(c.SnapshotRepo)
+	c.WorkflowExecutor = workflowExecutor
 	log.Println("    ✅ Workflow executor initialized (n8n-style)")
 
+	c.NodeCatalogHandler = nodecatalog.NewNodeCatalogHandler(workflowExecutor)
+	c.NodeCatalogRoutes = nodecatalog.NewNodeCatalogRoutes(c.NodeCatalogHandler, c.AuthMiddleware)
+
+	c.DeadLetterRepo = engineinfra.NewPostgresDeadLetterRepository(c.DB)
 	c.TriggerHandler = triggerhandler.NewTriggerHandler(
 		c.WorkflowRepo,
 		c.WorkflowExecutor,
+		engineinfra.NewRedisConversationLock(c.RedisClient),
+		c.DeadLetterRepo,
 	)
-	log.Println("    ✅ Trigger handler initialized")
+	log.Println("    ✅ Trigger handler initialized (per-conversation serialization, dead letters)")
+
+	c.DeadLetterService = deadletter.NewDeadLetterService(c.DeadLetterRepo, c.TriggerHandler)
+	c.DeadLetterHandler = deadletter.NewDeadLetterHandler(c.DeadLetterService)
+	c.DeadLetterRoutes = deadletter.NewDeadLetterRoutes(c.DeadLetterHandler, c.AuthMiddleware)
+
+	c.WorkflowTestHandler = workflowtest.NewWorkflowTestHandler(c.WorkflowRepo, workflowtest.NewRunner(c.WorkflowExecutor))
+	c.WorkflowTestRoutes = workflowtest.NewWorkflowTestRoutes(c.WorkflowTestHandler)
+
+	c.ReplayService = replay.NewReplayService(c.SnapshotRepo, c.WorkflowRepo, c.WorkflowExecutor, c.Config.Engine.SnapshotRetention)
+	c.ReplayHandler = replay.NewReplayHandler(c.ReplayService)
+	c.ReplayRoutes = replay.NewReplayRoutes(c.ReplayHandler, c.AuthMiddleware)
+	c.scheduleSystemJob(replay.PruneJobKind, "@hourly", c.ReplayService.RunPruneJob, jobs.Options{})
+	log.Println("    ✅ Snapshot replay initialized")
+
+	c.PromotionService = promotion.NewPromotionService(c.WorkflowRepo, c.ChannelRepo, c.WorkflowExecutor)
+	c.PromotionHandler = promotion.NewPromotionHandler(c.PromotionService)
+	c.PromotionRoutes = promotion.NewPromotionRoutes(c.PromotionHandler, c.AuthMiddleware)
 
 	c.WebhookTriggerHandler = webhooktrigger.NewWebhookTriggerHandler(
 		c.WorkflowRepo,
@@ -430,6 +1297,9 @@ func (c *Container) initEngineComponents() {
 	)
 	log.Println("    ✅ Schedule service initialized")
 
+	c.ScheduleHandler = scheduler.NewScheduleHandler(c.ScheduleService)
+	c.ScheduleRoutes = scheduler.NewScheduleRoutes(c.ScheduleHandler)
+
 	// ✅ Initialize workflow scheduler
 	c.WorkflowScheduler = scheduler.NewWorkflowScheduler(
 		c.ScheduleRepo,
@@ -438,55 +1308,788 @@ func (c *Container) initEngineComponents() {
 	log.Println("    ✅ Workflow scheduler initialized")
 
 	// ✅ Start workflow scheduler worker
-	go c.WorkflowScheduler.Start(ctx)
+	go c.Workers.Run("workflow_scheduler", func() { c.WorkflowScheduler.Start(ctx) })
 	log.Println("    ✅ Workflow scheduler worker started")
 
+	// Initialize test console (simulated inbound messages)
+	if c.ChannelRepo != nil {
+		c.SimulationHandler = channelapi.NewSimulationHandler(c.ChannelRepo, c.TriggerHandler)
+		c.SimulationRoutes = channelapi.NewSimulationRoutes(c.SimulationHandler)
+		log.Println("    ✅ Simulation routes initialized")
+	}
+
 	// Initialize channel webhook handler (for channel trigger workflows)
 	if c.ChannelRepo != nil && c.WhatsAppAdapter != nil {
 		c.WhatsAppWebhookHandler = whatsapp.NewWebhookHandler(
 			c.ChannelRepo,
 			c.WhatsAppAdapter,
+			c.FeatureFlagService,
 		)
 		log.Println("    ✅ WhatsApp webhook handler initialized")
 
 		// ✅ Initialize ChannelHandler
-		c.ChannelHandler = channelapi.NewChannelHandler(c.TriggerHandler)
+		c.ChannelHandler = channelapi.NewChannelHandler(c.TriggerHandler, c.MessageRepo)
+		c.ChannelHandler.AddInboundListener(channelsrv.NewMessageEventPublisher(c.EventBus))
+		if c.AttachmentService != nil {
+			c.ChannelHandler.SetAttachmentIngester(c.AttachmentService)
+			c.initImageUnderstanding()
+		}
 		log.Println("    ✅ Channel handler initialized")
 
+		// Unsupported webhook events are stored and reprocessed through the channel handler
+		c.WebhookEventRepo = channelsinfra.NewPostgresWebhookEventRepository(c.DB)
+		c.WebhookEventMetrics = channels.NewWebhookEventMetrics()
+		c.WebhookEventService = channelsrv.NewWebhookEventService(
+			c.WebhookEventRepo,
+			c.ChannelRepo,
+			c.ChannelManager,
+			c.WebhookEventMetrics,
+		)
+		c.WebhookEventService.SetDispatcher(c.ChannelHandler)
+		c.WhatsAppWebhookHandler.SetEventRecorder(c.WebhookEventService)
+		c.WebhookEventHandler = channelapi.NewWebhookEventHandler(c.WebhookEventService)
+		c.WebhookEventRoutes = channelapi.NewWebhookEventRoutes(c.WebhookEventHandler, c.AuthMiddleware)
+		log.Println("    ✅ Webhook event store initialized")
+
+		// Buffered bursts are combined per channel type and flushed by
+		// whichever instance claims them first
+		c.MessageBufferStore = channelsinfra.NewRedisMessageBuffer(c.RedisClient)
+		c.MessageBufferService = channelsrv.NewBufferService(c.MessageBufferStore, c.ChannelRepo)
+		c.MessageBufferService.SetDispatcher(c.ChannelHandler)
+		c.MessageBufferService.RegisterCombiner(channels.ChannelTypeWhatsApp, whatsapp.CombineBuffered)
+		c.MessageBufferService.RegisterCombiner(channels.ChannelTypeInstagram, instagram.CombineBuffered)
+		c.WhatsAppWebhookHandler.SetBuffer(c.MessageBufferService)
+		c.WhatsAppWebhookHandler.SetEventBus(c.EventBus)
+		go c.Workers.Run("message_buffer_flusher", func() { c.MessageBufferService.Start(context.Background()) })
+		log.Println("    ✅ Message buffer initialized")
+
 		// ✅ Initialize WhatsAppWebhookRoutes with both handlers
 		c.WhatsAppWebhookRoutes = whatsapp.NewWebhookRoutes(
 			c.WhatsAppWebhookHandler,
 			c.ChannelHandler.ProcessIncomingMessage, // Pass the fiber.Handler
 		)
 		log.Println("    ✅ WhatsApp webhook routes initialized")
+
+		// Instagram shares the generic processor; verify tokens and app
+		// secrets come from each channel's config
+		c.InstagramWebhookHandler = instagram.NewWebhookHandler(
+			c.ChannelRepo,
+			nil, // Created per channel
+			c.FeatureFlagService,
+		)
+		c.InstagramWebhookHandler.SetEventRecorder(c.WebhookEventService)
+		c.InstagramWebhookHandler.SetBuffer(c.MessageBufferService)
+		c.InstagramWebhookRoutes = instagram.NewWebhookRoutes(
+			c.InstagramWebhookHandler,
+			c.ChannelHandler.ProcessIncomingMessage,
+		)
+		log.Println("    ✅ Instagram webhook routes initialized")
+
+		// RCS agents are verified with each channel's client token; delivery
+		// and read events are published as message.status
+		c.RCSWebhookHandler = rcs.NewWebhookHandler(c.ChannelRepo, c.FeatureFlagService)
+		c.RCSWebhookHandler.SetEventRecorder(c.WebhookEventService)
+		c.RCSWebhookHandler.SetEventBus(c.EventBus)
+		c.RCSWebhookRoutes = rcs.NewWebhookRoutes(
+			c.RCSWebhookHandler,
+			c.ChannelHandler.ProcessIncomingMessage,
+		)
+		log.Println("    ✅ RCS webhook routes initialized")
+
+		// Apple Messages for Business: the gateway signs each message with a
+		// JWT over the channel's provider secret
+		c.AppleWebhookHandler = apple.NewWebhookHandler(c.ChannelRepo, c.FeatureFlagService)
+		c.AppleWebhookHandler.SetEventRecorder(c.WebhookEventService)
+		c.AppleWebhookRoutes = apple.NewWebhookRoutes(
+			c.AppleWebhookHandler,
+			c.ChannelHandler.ProcessIncomingMessage,
+		)
+		log.Println("    ✅ Apple Messages webhook routes initialized")
+
+		// Inbound messages re-arm the INACTIVITY follow-ups and session expiry
+		c.InactivityService = inactivity.NewInactivityService(c.WorkflowRepo, c.DelayScheduler, c.TriggerHandler)
+		c.InactivityService.SetEventBus(c.EventBus)
+		c.ChannelHandler.AddInboundListener(c.InactivityService)
+		log.Println("    ✅ Inactivity triggers initialized")
 	}
 
 	log.Println("  ✅ Engine components initialized")
 }
 
+// resourceFound turns a repository lookup into an existence check
+func resourceFound(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	if errx.IsType(err, errx.TypeNotFound) {
+		return false, nil
+	}
+	return false, err
+}
+
 // =================================================================
-// WORKFLOW CONTINUATION HANDLER ⏰
+// ATTACHMENTS INITIALIZATION 📎
 // =================================================================
 
-// handleWorkflowContinuation is called when a delayed execution is ready
-func (c *Container) handleWorkflowContinuation(
-	ctx context.Context,
-	continuation *engine.WorkflowContinuation,
-) error {
-	log.Printf("📥 Resuming workflow %s from node %s",
-		continuation.WorkflowID, continuation.NextNodeID)
+func (c *Container) initAttachmentComponents() {
+	cfg := c.Config.Attachments
+	if cfg.Storage == "" {
+		log.Println("  📎 Attachment storage disabled, inbound media links are passed through")
+		return
+	}
 
-	// Get workflow
-	workflow, err := c.WorkflowRepo.FindByID(ctx, kernel.WorkflowID(continuation.WorkflowID))
+	log.Printf("  📎 Initializing attachment components (%s storage)...", cfg.Storage)
+
+	var storage attachment.Storage
+	var err error
+	switch cfg.Storage {
+	case "local":
+		storage, err = attachmentinfra.NewLocalStorage(cfg.LocalDir, cfg.PublicURL, cfg.SigningSecret)
+	case "s3", "gcs":
+		s3cfg := attachmentinfra.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		}
+		if cfg.Storage == "gcs" {
+			if s3cfg.Endpoint == "" {
+				s3cfg.Endpoint = attachmentinfra.GCSEndpoint
+			}
+			s3cfg.Region = "auto"
+			s3cfg.PathStyle = true
+		}
+		storage, err = attachmentinfra.NewS3Storage(s3cfg)
+	default:
+		log.Printf("    ⚠️  Unknown attachment storage %q, attachments disabled", cfg.Storage)
+		return
+	}
 	if err != nil {
-		return engine.ErrWorkflowNotFound().
-			WithDetail("workflow_id", continuation.WorkflowID).
-			WithCause(err)
+		log.Printf("    ⚠️  Attachment storage unavailable, attachments disabled: %v", err)
+		return
 	}
 
+	var scanner attachment.Scanner
+	switch cfg.Scanner {
+	case "clamav":
+		scanner = attachmentinfra.NewClamAVScanner(cfg.ClamAVAddr, cfg.ScanTimeout)
+		log.Printf("    ✅ ClamAV scanning via %s", cfg.ClamAVAddr)
+	case "http":
+		scanner = attachmentinfra.NewHTTPScanner(cfg.ScannerURL, cfg.ScannerToken, cfg.ScanTimeout)
+		log.Println("    ✅ External attachment scanning enabled")
+	case "":
+		log.Println("    ⚠️  No attachment scanner configured, files are stored unscanned")
+	default:
+		log.Printf("    ⚠️  Unknown attachment scanner %q, files are stored unscanned", cfg.Scanner)
+	}
+
+	c.AttachmentStorage = storage
+	c.AttachmentRepo = attachmentinfra.NewPostgresAttachmentRepository(c.DB)
+	c.AttachmentService = attachmentsrv.NewAttachmentService(
+		c.AttachmentRepo,
+		storage,
+		scanner,
+		c.ChannelManager,
+		cfg.LinkTTL,
+	)
+	c.initTranscription()
+	c.AttachmentHandler = attachmentapi.NewAttachmentHandler(c.AttachmentService)
+	c.AttachmentRoutes = attachmentapi.NewAttachmentRoutes(c.AttachmentHandler, c.AuthMiddleware)
+
+	log.Println("  ✅ Attachment components initialized")
+}
+
+// initTranscription turns inbound voice notes into text
+func (c *Container) initTranscription() {
+	cfg := c.Config.Attachments
+
+	var transcriber attachment.Transcriber
+	switch cfg.Transcriber {
+	case "":
+		return
+	case "whisper":
+		apiKey := cfg.TranscriptionAPIKey
+		if apiKey == "" {
+			apiKey = os.Getenv("OPENAI_API_KEY")
+		}
+		if apiKey == "" {
+			log.Println("    ⚠️  No API key for Whisper, voice notes are not transcribed")
+			return
+		}
+		transcriber = attachmentinfra.NewWhisperTranscriber(apiKey, cfg.TranscriptionModel, cfg.TranscriptionLanguage)
+	case "deepgram":
+		if cfg.TranscriptionAPIKey == "" {
+			log.Println("    ⚠️  TRANSCRIPTION_API_KEY not set, voice notes are not transcribed")
+			return
+		}
+		transcriber = attachmentinfra.NewDeepgramTranscriber(cfg.TranscriptionAPIKey, cfg.TranscriptionModel, cfg.TranscriptionLanguage)
+	default:
+		log.Printf("    ⚠️  Unknown transcription provider %q, voice notes are not transcribed", cfg.Transcriber)
+		return
+	}
+
+	c.AttachmentService.SetTranscriber(transcriber, cfg.TranscriptMinConfidence)
+	log.Printf("    ✅ Voice note transcription via %s", cfg.Transcriber)
+}
+
+// initImageUnderstanding reads inbound images for tenants with the
+// image_understanding flag whose channel workflows opt in. It runs once the
+// workflow repository exists.
+func (c *Container) initImageUnderstanding() {
+	cfg := c.Config.Attachments
+
+	var analyzer attachment.ImageAnalyzer
+	switch cfg.Vision {
+	case "":
+		return
+	case "openai":
+		apiKey := cfg.VisionAPIKey
+		if apiKey == "" {
+			apiKey = os.Getenv("OPENAI_API_KEY")
+		}
+		if apiKey == "" {
+			log.Println("    ⚠️  No API key for OpenAI vision, images are not analyzed")
+			return
+		}
+		analyzer = attachmentinfra.NewOpenAIVisionAnalyzer(apiKey, cfg.VisionModel)
+	case "google":
+		if cfg.VisionAPIKey == "" {
+			log.Println("    ⚠️  VISION_API_KEY not set, images are not analyzed")
+			return
+		}
+		analyzer = attachmentinfra.NewGoogleVisionAnalyzer(cfg.VisionAPIKey)
+	default:
+		log.Printf("    ⚠️  Unknown vision provider %q, images are not analyzed", cfg.Vision)
+		return
+	}
+
+	policy := attachmentsrv.NewWorkflowImagePolicy(c.FeatureFlagService, c.WorkflowRepo)
+	c.AttachmentService.SetImageAnalyzer(analyzer, policy)
+	log.Printf("    ✅ Image understanding via %s", cfg.Vision)
+}
+
+// =================================================================
+// SEQUENCES INITIALIZATION 📬
+// =================================================================
+
+func (c *Container) initSequenceComponents() {
+	log.Println("  📬 Initializing sequence components...")
+
+	c.SequenceRepo = sequenceinfra.NewPostgresSequenceRepository(c.DB)
+	c.EnrollmentRepo = sequenceinfra.NewPostgresEnrollmentRepository(c.DB)
+	log.Println("    ✅ Sequence repositories initialized")
+
+	c.SequenceService = sequencesrv.NewSequenceService(
+		c.SequenceRepo,
+		c.EnrollmentRepo,
+		c.ChannelRepo,
+		c.ChannelManager,
+		c.SuppressionRepo,
+		c.TriggerHandler,
+		c.ExpressionEvaluator,
+	)
+	c.SequenceHandler = sequenceapi.NewSequenceHandler(c.SequenceService)
+	c.SequenceRoutes = sequenceapi.NewSequenceRoutes(c.SequenceHandler, c.AuthMiddleware)
+	log.Println("    ✅ Sequence service initialized")
+
+	// Replies end enrollments in sequences that exit on reply
+	if c.ChannelHandler != nil {
+		c.ChannelHandler.AddInboundListener(c.SequenceService)
+	}
+
+	// Segments are enrolled into sequences as their audience
+	c.SegmentService.SetEnroller(c.SequenceService)
+
+	c.scheduleSystemJob(sequencesrv.StepsJobKind, "@every 30s", c.SequenceService.RunStepsJob, jobs.Options{})
+
+	log.Println("  ✅ Sequence components initialized")
+}
+
+// =================================================================
+// CHANGE IMPACT INITIALIZATION 🔍
+// =================================================================
+
+func (c *Container) initImpactComponents() {
+	log.Println("  🔍 Initializing change impact components...")
+
+	c.ImpactService = impact.NewImpactService(
+		c.WorkflowRepo,
+		c.WorkflowExecutor,
+		c.DelayScheduler,
+		c.SurveyRepo,
+		c.ScheduleRepo,
+		c.SequenceRepo,
+	)
+	c.ImpactHandler = impact.NewImpactHandler(c.ImpactService)
+	c.ImpactRoutes = impact.NewImpactRoutes(c.ImpactHandler, c.AuthMiddleware)
+
+	log.Println("  ✅ Change impact components initialized")
+}
+
+// =================================================================
+// TRANSCRIPT EXPORTS INITIALIZATION 📦
+// =================================================================
+
+func (c *Container) initTranscriptComponents() {
+	log.Println("  📦 Initializing transcript export components...")
+
+	c.TranscriptExportRepo = transcriptinfra.NewPostgresExportJobRepository(c.DB)
+	c.TranscriptExportService = transcriptsrv.NewExportService(
+		c.TranscriptExportRepo,
+		c.MessageRepo,
+		c.TagRepo,
+		c.AttachmentStorage, // nil = exports answer EXPORTS_NOT_CONFIGURED
+		c.JobService,
+		c.Config.Attachments.LinkTTL,
+	)
+	c.JobRunner.Register(
+		transcriptsrv.ExportJobKind,
+		c.TranscriptExportService.RunExportJob,
+		jobs.Options{Timeout: transcriptsrv.ExportTimeout},
+	)
+	c.TranscriptHandler = transcriptapi.NewTranscriptHandler(c.TranscriptExportService)
+	c.TranscriptRoutes = transcriptapi.NewTranscriptRoutes(c.TranscriptHandler, c.AuthMiddleware)
+
+	if c.AttachmentStorage == nil {
+		log.Println("    ⚠️  No file storage configured, transcript exports disabled")
+	}
+
+	log.Println("  ✅ Transcript export components initialized")
+}
+
+// =================================================================
+// MESSAGE ARCHIVES INITIALIZATION 🗄️
+// =================================================================
+
+func (c *Container) initArchiveComponents() {
+	log.Println("  🗄️  Initializing message archive components...")
+
+	partitions := conversationinfra.NewPostgresPartitionStore(c.DB)
+	partitions.SetRouter(c.DataRouter) // Every region has its own monthly partitions
+
+	c.MessageArchiveService = conversationsrv.NewArchiveService(
+		partitions,
+		conversationinfra.NewPostgresArchiveRepository(c.DB),
+		c.RetentionService,
+		c.AttachmentStorage, // nil = partitions are kept, never archived
+		time.Duration(c.Config.Retention.MessageArchiveDays)*24*time.Hour,
+		c.Config.Attachments.LinkTTL,
+	)
+	c.ArchiveHandler = conversationapi.NewArchiveHandler(c.MessageArchiveService)
+	c.ArchiveRoutes = conversationapi.NewArchiveRoutes(c.ArchiveHandler, c.AuthMiddleware)
+
+	// Also creates the upcoming partitions, so it runs even with archiving off
+	c.scheduleSystemJob(
+		conversationsrv.ArchiveJobKind,
+		"@daily",
+		c.MessageArchiveService.RunArchiveJob,
+		jobs.Options{Timeout: conversationsrv.ArchiveTimeout},
+	)
+
+	if !c.MessageArchiveService.Enabled() {
+		log.Println("    ⚠️  MESSAGE_ARCHIVE_DAYS or file storage not set, old months stay in the database")
+	}
+
+	log.Println("  ✅ Message archive components initialized")
+}
+
+// =================================================================
+// CONTACTS INITIALIZATION 📇
+// =================================================================
+
+func (c *Container) initContactComponents() {
+	log.Println("  📇 Initializing contact components...")
+
+	contactRepo := contactinfra.NewPostgresContactRepository(c.DB)
+	contactRepo.SetRouter(c.DataRouter)
+	c.ContactRepo = contactRepo
+	c.ContactImportRepo = contactinfra.NewPostgresImportRepository(c.DB)
+	c.ContactService = contactsrv.NewContactService(c.ContactRepo)
+	c.ContactImportService = contactsrv.NewImportService(
+		c.ContactImportRepo,
+		c.ContactRepo,
+		c.AttachmentStorage, // nil = imports answer IMPORTS_NOT_CONFIGURED
+		c.JobService,
+	)
+	c.JobRunner.Register(
+		contactsrv.ImportJobKind,
+		c.ContactImportService.RunImportJob,
+		jobs.Options{Timeout: contactsrv.ImportTimeout},
+	)
+	c.ContactHandler = contactapi.NewContactHandler(c.ContactService, c.ContactImportService)
+	c.ContactRoutes = contactapi.NewContactRoutes(c.ContactHandler, c.AuthMiddleware)
+
+	if c.AttachmentStorage == nil {
+		log.Println("    ⚠️  No file storage configured, contact imports disabled")
+	}
+
+	log.Println("  ✅ Contact components initialized")
+}
+
+// =================================================================
+// SEGMENTS INITIALIZATION 🎯
+// =================================================================
+
+func (c *Container) initSegmentComponents() {
+	log.Println("  🎯 Initializing segment components...")
+
+	c.SegmentRepo = segmentinfra.NewPostgresSegmentRepository(c.DB)
+	memberRepo := segmentinfra.NewPostgresMemberRepository(c.DB)
+	memberRepo.SetRouter(c.DataRouter) // Memberships stay central, contacts are read from the region
+	c.SegmentMemberRepo = memberRepo
+	stats := segmentinfra.NewPostgresStatsProvider(c.DB)
+	stats.SetRouter(c.DataRouter)
+	c.SegmentService = segmentsrv.NewSegmentService(
+		c.SegmentRepo,
+		c.SegmentMemberRepo,
+		c.ContactRepo,
+		stats,
+		c.JobService,
+	)
+	c.JobRunner.Register(
+		segmentsrv.RebuildJobKind,
+		c.SegmentService.RunRebuildJob,
+		jobs.Options{Timeout: segmentsrv.RebuildTimeout},
+	)
+	c.JobRunner.Register(
+		segmentsrv.EnrollJobKind,
+		c.SegmentService.RunEnrollJob,
+		jobs.Options{Timeout: segmentsrv.EnrollTimeout},
+	)
+	c.SegmentHandler = segmentapi.NewSegmentHandler(c.SegmentService)
+	c.SegmentRoutes = segmentapi.NewSegmentRoutes(c.SegmentHandler, c.AuthMiddleware)
+
+	// Imports rebuild the tenant's segments; inbound messages re-evaluate
+	// their sender
+	c.ContactImportService.AddObserver(c.SegmentService)
+	if c.ChannelHandler != nil {
+		c.ChannelHandler.AddInboundListener(c.SegmentService)
+	}
+
+	// Time-based predicates drift without events
+	c.scheduleSystemJob(segmentsrv.RefreshJobKind, "@hourly", c.SegmentService.RunRefreshJob, jobs.Options{Timeout: segmentsrv.RebuildTimeout})
+
+	log.Println("  ✅ Segment components initialized")
+}
+
+// =================================================================
+// SNIPPETS INITIALIZATION 📝
+// =================================================================
+
+func (c *Container) initSnippetComponents() {
+	log.Println("  📝 Initializing snippet components...")
+
+	c.SnippetRepo = snippetinfra.NewPostgresSnippetRepository(c.DB)
+	c.SnippetService = snippetsrv.NewSnippetService(c.SnippetRepo)
+	c.SnippetHandler = snippetapi.NewSnippetHandler(c.SnippetService)
+	c.SnippetRoutes = snippetapi.NewSnippetRoutes(c.SnippetHandler, c.AuthMiddleware)
+
+	log.Println("  ✅ Snippet components initialized")
+}
+
+// =================================================================
+// MESSAGE TEMPLATES INITIALIZATION 🌐
+// =================================================================
+
+func (c *Container) initTemplateComponents() {
+	log.Println("  🌐 Initializing message template components...")
+
+	c.MessageTemplateRepo = msgtemplateinfra.NewPostgresTemplateRepository(c.DB)
+	c.MessageTemplateService = msgtemplatesrv.NewTemplateService(c.MessageTemplateRepo)
+	c.MessageTemplateHandler = msgtemplateapi.NewTemplateHandler(c.MessageTemplateService)
+	c.MessageTemplateRoutes = msgtemplateapi.NewTemplateRoutes(c.MessageTemplateHandler, c.AuthMiddleware)
+
+	log.Println("  ✅ Message template components initialized")
+}
+
+// =================================================================
+// EXPERIMENT INITIALIZATION 🧪
+// =================================================================
+
+func (c *Container) initExperimentComponents() {
+	log.Println("  🧪 Initializing experiment components...")
+
+	c.ExperimentEventRepo = experimentinfra.NewPostgresEventRepository(c.DB)
+	c.ExperimentService = experimentsrv.NewExperimentService(c.ExperimentEventRepo)
+	c.ExperimentHandler = experimentapi.NewExperimentHandler(c.ExperimentService)
+	c.ExperimentRoutes = experimentapi.NewExperimentRoutes(c.ExperimentHandler, c.AuthMiddleware)
+
+	log.Println("  ✅ Experiment components initialized")
+}
+
+// =================================================================
+// CRM INITIALIZATION 🤝
+// =================================================================
+
+func (c *Container) initCRMComponents() {
+	log.Println("  🤝 Initializing CRM components...")
+
+	cfg := c.Config.Integrations
+	var connectors []crm.Connector
+	if cfg.HubSpotClientID != "" {
+		connectors = append(connectors, crminfra.NewHubSpotConnector(cfg.HubSpotClientID, cfg.HubSpotClientSecret))
+	}
+	if cfg.SalesforceClientID != "" {
+		connectors = append(connectors, crminfra.NewSalesforceConnector(cfg.SalesforceClientID, cfg.SalesforceClientSecret, cfg.SalesforceLoginURL))
+	}
+
+	c.CRMConnectionRepo = crminfra.NewPostgresConnectionRepository(c.DB, c.FieldCipher)
+	c.CRMService = crmsrv.NewCRMService(
+		c.CRMConnectionRepo,
+		c.StateManager,
+		strings.TrimSuffix(cfg.PublicURL, "/")+crmapi.CallbackPath,
+		connectors...,
+	)
+	c.CRMHandler = crmapi.NewCRMHandler(c.CRMService)
+	c.CRMRoutes = crmapi.NewCRMRoutes(c.CRMHandler, c.AuthMiddleware)
+
+	log.Printf("  ✅ CRM components initialized (providers: %v)", c.CRMService.Available())
+}
+
+// =================================================================
+// HELPDESK INITIALIZATION 🎫
+// =================================================================
+
+func (c *Container) initHelpdeskComponents() {
+	log.Println("  🎫 Initializing helpdesk components...")
+
+	// Tenants connect with their own API credentials
+	c.HelpdeskConnectionRepo = helpdeskinfra.NewPostgresConnectionRepository(c.DB, c.FieldCipher)
+	c.HelpdeskTicketLinkRepo = helpdeskinfra.NewPostgresTicketLinkRepository(c.DB)
+	c.HelpdeskService = helpdesksrv.NewHelpdeskService(
+		c.HelpdeskConnectionRepo,
+		c.HelpdeskTicketLinkRepo,
+		c.MessageRepo,
+		c.TagRepo,
+		helpdeskinfra.NewZendeskConnector(),
+		helpdeskinfra.NewFreshdeskConnector(),
+	)
+	c.HelpdeskHandler = helpdeskapi.NewHelpdeskHandler(c.HelpdeskService)
+	c.HelpdeskRoutes = helpdeskapi.NewHelpdeskRoutes(c.HelpdeskHandler, c.AuthMiddleware)
+
+	log.Println("  ✅ Helpdesk components initialized")
+}
+
+// =================================================================
+// COMMERCE INITIALIZATION 🛍️
+// =================================================================
+
+func (c *Container) initCommerceComponents() {
+	log.Println("  🛍️ Initializing commerce components...")
+
+	// Tenants connect with their own API credentials
+	c.CommerceConnectionRepo = commerceinfra.NewPostgresConnectionRepository(c.DB, c.FieldCipher)
+	c.CommerceService = commercesrv.NewCommerceService(
+		c.CommerceConnectionRepo,
+		commerceinfra.NewShopifyConnector(),
+		commerceinfra.NewWooCommerceConnector(),
+	)
+	c.CommerceHandler = commerceapi.NewCommerceHandler(c.CommerceService)
+	c.CommerceRoutes = commerceapi.NewCommerceRoutes(c.CommerceHandler, c.AuthMiddleware)
+
+	log.Println("  ✅ Commerce components initialized")
+}
+
+// =================================================================
+// SURVEY INITIALIZATION ⭐
+// =================================================================
+
+func (c *Container) initSurveyComponents() {
+	log.Println("    ⭐ Initializing survey components...")
+
+	c.SurveyRepo = surveyinfra.NewPostgresSurveyRepository(c.DB)
+	c.SurveyService = surveysrv.NewSurveyService(
+		c.SurveyRepo,
+		c.DelayScheduler,
+		c.ChannelManager,
+		c.handleWorkflowContinuation,
+	)
+	c.SurveyHandler = surveyapi.NewSurveyHandler(c.SurveyService)
+	c.SurveyRoutes = surveyapi.NewSurveyRoutes(c.SurveyHandler, c.AuthMiddleware)
+
+	// Answers to pending surveys resume their run instead of triggering workflows
+	if c.ChannelHandler != nil {
+		c.ChannelHandler.AddInboundInterceptor(c.SurveyService)
+	}
+
+	log.Println("    ✅ Survey components initialized")
+}
+
+// =================================================================
+// AGENT INBOX INITIALIZATION 🙋
+// =================================================================
+
+func (c *Container) initInboxComponents() {
+	log.Println("  🙋 Initializing agent inbox components...")
+
+	c.ClaimRepo = inboxinfra.NewPostgresClaimRepository(c.DB)
+	c.InboxService = inboxsrv.NewInboxService(c.ClaimRepo, c.EventBus, c.ChannelService, c.SnippetService)
+	c.InboxHub = inboxsrv.NewHub(c.EventBus)
+	c.InboxHandler = inboxapi.NewInboxHandler(c.InboxService, c.InboxHub)
+	c.InboxRoutes = inboxapi.NewInboxRoutes(c.InboxHandler, c.AuthMiddleware)
+
+	// Messages in claimed conversations are streamed to their operator
+	if c.ChannelHandler != nil {
+		c.ChannelHandler.AddInboundListener(c.InboxService)
+	}
+
+	// Survey scores are credited to the operator who owns the conversation
+	c.SurveyService.SetClaimRepository(c.ClaimRepo)
+
+	log.Println("  ✅ Agent inbox components initialized")
+}
+
+// =================================================================
+// SPAM FILTER INITIALIZATION 🚫
+// =================================================================
+
+func (c *Container) initSpamFilterComponents() {
+	log.Println("  🚫 Initializing spam filter components...")
+
+	cfg := c.Config.SpamFilter
+	var classifier spamfilter.Classifier
+	if cfg.ClassifierURL != "" {
+		classifier = spamfilterinfra.NewHTTPClassifier(cfg.ClassifierURL, cfg.ClassifierToken, cfg.ClassifierTimeout)
+		log.Println("    ✅ Spam classifier configured")
+	}
+
+	c.SpamPolicyRepo = spamfilterinfra.NewPostgresPolicyRepository(c.DB)
+	c.SpamBlockRepo = spamfilterinfra.NewPostgresBlockRepository(c.DB)
+	c.SpamFilterMetrics = spamfilter.NewMetrics()
+	c.SpamFilterService = spamfiltersrv.NewFilterService(
+		c.SpamPolicyRepo,
+		c.SpamBlockRepo,
+		c.RateLimiter,
+		classifier,
+		c.SpamFilterMetrics,
+	)
+	c.SpamFilterHandler = spamfilterapi.NewSpamFilterHandler(c.SpamFilterService)
+	c.SpamFilterRoutes = spamfilterapi.NewSpamFilterRoutes(c.SpamFilterHandler, c.AuthMiddleware)
+
+	// Spam is screened out before it is recorded or reaches workflows
+	if c.ChannelHandler != nil {
+		c.ChannelHandler.SetInboundFilter(c.SpamFilterService)
+	}
+
+	log.Println("  ✅ Spam filter components initialized")
+}
+
+// =================================================================
+// THROTTLING INITIALIZATION 🐢
+// =================================================================
+
+func (c *Container) initThrottleComponents() {
+	log.Println("  🐢 Initializing throttle components...")
+
+	// Tenants without settings get the server rule, unlimited when rate limiting is off
+	defaultRule := c.Config.RateLimit.Sender
+	if !c.Config.RateLimit.Enabled {
+		defaultRule = ratelimit.Rule{}
+	}
+
+	c.ThrottleSettingsRepo = throttleinfra.NewPostgresSettingsRepository(c.DB)
+	c.ThrottleService = throttlesrv.NewThrottleService(
+		c.ThrottleSettingsRepo,
+		c.ChannelRepo,
+		c.RateLimiter,
+		c.ChannelManager,
+		defaultRule,
+	)
+	c.ThrottleHandler = throttleapi.NewThrottleHandler(c.ThrottleService)
+	c.ThrottleRoutes = throttleapi.NewThrottleRoutes(c.ThrottleHandler, c.AuthMiddleware)
+
+	// Registered after the survey interceptor so survey answers are never throttled
+	if c.ChannelHandler != nil {
+		c.ChannelHandler.AddInboundInterceptor(c.ThrottleService)
+	}
+
+	log.Println("  ✅ Throttle components initialized")
+}
+
+// =================================================================
+// FALLBACK INITIALIZATION ↪️
+// =================================================================
+
+func (c *Container) initFallbackComponents() {
+	log.Println("  ↪️ Initializing fallback components...")
+
+	c.FallbackSettingsRepo = fallbackinfra.NewPostgresSettingsRepository(c.DB)
+	c.UnmatchedCounterRepo = fallbackinfra.NewPostgresCounterRepository(c.DB)
+	c.FallbackService = fallbacksrv.NewFallbackService(
+		c.FallbackSettingsRepo,
+		c.UnmatchedCounterRepo,
+		c.ChannelRepo,
+		c.WorkflowRepo,
+		c.ChannelManager,
+	)
+	c.FallbackHandler = fallbackapi.NewFallbackHandler(c.FallbackService)
+	c.FallbackRoutes = fallbackapi.NewFallbackRoutes(c.FallbackHandler, c.AuthMiddleware)
+
+	c.TriggerHandler.SetFallbackResolver(c.FallbackService)
+
+	log.Println("  ✅ Fallback components initialized")
+}
+
+// =================================================================
+// DECLARATIVE CONFIGURATION INITIALIZATION 📋
+// =================================================================
+
+func (c *Container) initManifestComponents() {
+	log.Println("  📋 Initializing manifest components...")
+
+	c.ManifestService = manifestsrv.NewManifestService(
+		c.ChannelRepo,
+		c.ChannelService,
+		c.RoleRepo,
+		c.RolePermRepo,
+		c.RoleService,
+		c.WorkflowRepo,
+		c.WorkflowExecutor,
+	)
+	c.ManifestHandler = manifestapi.NewManifestHandler(c.ManifestService)
+	c.ManifestRoutes = manifestapi.NewManifestRoutes(c.ManifestHandler, c.AuthMiddleware)
+
+	log.Println("  ✅ Manifest components initialized")
+}
+
+// =================================================================
+// WORKFLOW CONTINUATION HANDLER ⏰
+// =================================================================
+
+// handleWorkflowContinuation is called when a delayed execution is ready
+func (c *Container) handleWorkflowContinuation(
+	ctx context.Context,
+	continuation *engine.WorkflowContinuation,
+) error {
+	if continuation.Kind != engine.ContinuationDelay && c.InactivityService != nil {
+		return c.InactivityService.HandleContinuation(ctx, continuation)
+	}
+
+	log.Printf("📥 Resuming workflow %s from node %s",
+		continuation.WorkflowID, continuation.NextNodeID)
+
+	// Get workflow
+	workflow, err := c.WorkflowRepo.FindByID(ctx, kernel.WorkflowID(continuation.WorkflowID))
+	if err != nil {
+		return engine.ErrWorkflowNotFound().
+			WithDetail("workflow_id", continuation.WorkflowID).
+			WithCause(err)
+	}
+
+	// The saved context carries the original trigger; reload the message it
+	// came from and mark the run as resumed
+	nodeContext := engine.DeepCopyMap(continuation.NodeContext)
+	if nodeContext == nil {
+		nodeContext = make(map[string]any)
+	}
+	trigger := c.resumeTrigger(ctx, continuation)
+	nodeContext["trigger"] = trigger
+
 	// Prepare workflow input from saved context
 	input := engine.WorkflowInput{
-		TriggerData: continuation.NodeContext,
+		TriggerData: trigger,
 		TenantID:    kernel.TenantID(continuation.TenantID),
 		Metadata: map[string]any{
 			"resumed_from_delay": true,
@@ -503,7 +2106,7 @@ func (c *Container) handleWorkflowContinuation(
 			*workflow,
 			input,
 			continuation.NextNodeID,
-			continuation.NodeContext,
+			nodeContext,
 		)
 	} else {
 		log.Printf("✅ Workflow %s completed (no next node after delay)", workflow.ID.String())
@@ -522,6 +2125,107 @@ func (c *Container) handleWorkflowContinuation(
 	return nil
 }
 
+// resumeTrigger rebuilds the trigger of a delayed run. The saved trigger is
+// kept as is, the stored inbound message is added as trigger.message when it
+// can be found, and trigger.resumed always tells expressions the run was
+// resumed rather than started by a new message.
+func (c *Container) resumeTrigger(
+	ctx context.Context,
+	continuation *engine.WorkflowContinuation,
+) map[string]any {
+	trigger, _ := continuation.NodeContext["trigger"].(map[string]any)
+	trigger = engine.DeepCopyMap(trigger)
+	if trigger == nil {
+		trigger = make(map[string]any)
+	}
+
+	resumed := map[string]any{
+		"type":            "delay",
+		"continuation_id": continuation.ID,
+		"delayed_node_id": continuation.NodeID,
+		"message_found":   false,
+	}
+	trigger["resumed"] = resumed
+
+	if continuation.MessageID == "" || continuation.ChannelID == "" || c.MessageRepo == nil {
+		return trigger
+	}
+
+	msg, err := c.MessageRepo.FindByProviderMessageID(
+		ctx,
+		kernel.TenantID(continuation.TenantID),
+		kernel.ChannelID(continuation.ChannelID),
+		continuation.MessageID,
+	)
+	if err != nil {
+		log.Printf("⚠️  Original message %s not found for continuation %s: %v",
+			continuation.MessageID, continuation.ID, err)
+		return trigger
+	}
+
+	resumed["message_found"] = true
+	trigger["message"] = map[string]any{
+		"id":              msg.ID,
+		"text":            msg.Content.Text,
+		"message_type":    msg.Content.Type,
+		"sender_id":       msg.SenderID,
+		"conversation_id": msg.ConversationID,
+		"created_at":      msg.CreatedAt.Format(time.RFC3339),
+	}
+	if _, ok := trigger["text"]; !ok {
+		trigger["text"] = msg.Content.Text
+	}
+
+	return trigger
+}
+
+// =================================================================
+// TENANT LIFECYCLE INITIALIZATION 🏢
+// =================================================================
+
+func (c *Container) initTenantLifecycle() {
+	log.Println("  🏢 Initializing tenant lifecycle...")
+
+	c.ExportRepo = tenantinfra.NewPostgresDataExportRepository(c.DB)
+	c.ExportService = tenantsrv.NewExportService(
+		c.ExportRepo,
+		tenantinfra.NewPostgresDataExporter(c.DB, c.DataRouter, c.FieldCipher),
+		c.TenantRepo,
+		c.Config.Tenant.ExportDir,
+	)
+
+	c.TenantService.AddLifecycleHook(
+		auth.NewTenantSessionHook(c.SessionRepo, c.TokenRepo, c.SessionValidator),
+		c.ExportService,
+		c.TranscriptExportService,
+		c.MessageArchiveService,
+		tenantinfra.NewPostgresRegionalDataCleaner(c.DataRouter), // Regional tables have no foreign key to tenants
+	)
+	if c.ChannelManager != nil {
+		c.TenantService.AddLifecycleHook(
+			channelsrv.NewTenantLifecycleHook(c.ChannelRepo, c.ChannelManager, c.TenantConfigRepo),
+		)
+	}
+	if c.ScheduleService != nil {
+		c.TenantService.AddLifecycleHook(
+			scheduler.NewTenantLifecycleHook(c.ScheduleService, c.TenantConfigRepo),
+		)
+	}
+	if url := c.Config.Tenant.LifecycleWebhookURL; url != "" {
+		c.TenantService.AddLifecycleHook(
+			tenantinfra.NewWebhookLifecycleNotifier(url, c.Config.Tenant.LifecycleWebhookSecret),
+		)
+		log.Println("    ✅ Tenant lifecycle webhook enabled")
+	}
+
+	c.TenantHandler = tenantapi.NewTenantHandler(c.TenantService, c.ExportService)
+	c.TenantRoutes = tenantapi.NewTenantRoutes(c.TenantHandler, c.AuthMiddleware)
+	c.MemberHandler = userapi.NewMemberHandler(c.UserService)
+	c.MemberRoutes = userapi.NewMemberRoutes(c.MemberHandler, c.AuthMiddleware)
+
+	log.Println("  ✅ Tenant lifecycle initialized")
+}
+
 // =================================================================
 // UTILITY METHODS
 // =================================================================
@@ -529,6 +2233,41 @@ func (c *Container) handleWorkflowContinuation(
 func (c *Container) GetAllRoutes() []RouteGroup {
 	routes := []RouteGroup{
 		{Name: "auth", Handler: c.AuthHandlers},
+		{Name: "sso", Handler: c.SSOHandlers},
+		{Name: "tenant", Handler: c.TenantHandler},
+		{Name: "members", Handler: c.MemberHandler},
+		{Name: "jobs", Handler: c.JobHandler},
+		{Name: "retention", Handler: c.RetentionHandler},
+		{Name: "encryption", Handler: c.EncryptionHandler},
+		{Name: "features", Handler: c.FeatureFlagHandler},
+		{Name: "business_hours", Handler: c.BusinessHoursHandler},
+		{Name: "fault_injection", Handler: c.FaultInjectionHandler},
+		{Name: "node_types", Handler: c.NodeCatalogHandler},
+		{Name: "dead_letters", Handler: c.DeadLetterHandler},
+		{Name: "continuations", Handler: c.ContinuationHandler},
+		{Name: "workflow_tests", Handler: c.WorkflowTestHandler},
+		{Name: "workflow_runs", Handler: c.ReplayHandler},
+		{Name: "workflow_impact", Handler: c.ImpactHandler},
+		{Name: "promotion", Handler: c.PromotionHandler},
+		{Name: "schedules", Handler: c.ScheduleHandler},
+		{Name: "sequences", Handler: c.SequenceHandler},
+		{Name: "snippets", Handler: c.SnippetHandler},
+		{Name: "message_templates", Handler: c.MessageTemplateHandler},
+		{Name: "surveys", Handler: c.SurveyHandler},
+		{Name: "experiments", Handler: c.ExperimentHandler},
+		{Name: "crm", Handler: c.CRMHandler},
+		{Name: "helpdesk", Handler: c.HelpdeskHandler},
+		{Name: "commerce", Handler: c.CommerceHandler},
+		{Name: "transcripts", Handler: c.TranscriptHandler},
+		{Name: "message_archives", Handler: c.ArchiveHandler},
+		{Name: "contacts", Handler: c.ContactHandler},
+		{Name: "segments", Handler: c.SegmentHandler},
+		{Name: "inbox", Handler: c.InboxHandler},
+		{Name: "search", Handler: c.SearchHandler},
+		{Name: "spam_filter", Handler: c.SpamFilterHandler},
+		{Name: "throttle", Handler: c.ThrottleHandler},
+		{Name: "fallback", Handler: c.FallbackHandler},
+		{Name: "manifest", Handler: c.ManifestHandler},
 	}
 
 	// Add channel routes if available
@@ -546,6 +2285,62 @@ func (c *Container) GetAllRoutes() []RouteGroup {
 		})
 	}
 
+	if c.InstagramWebhookHandler != nil {
+		routes = append(routes, RouteGroup{
+			Name:    "instagram_webhook",
+			Handler: c.InstagramWebhookHandler,
+		})
+	}
+
+	if c.RCSWebhookHandler != nil {
+		routes = append(routes, RouteGroup{
+			Name:    "rcs_webhook",
+			Handler: c.RCSWebhookHandler,
+		})
+	}
+
+	if c.AppleWebhookHandler != nil {
+		routes = append(routes, RouteGroup{
+			Name:    "apple_webhook",
+			Handler: c.AppleWebhookHandler,
+		})
+	}
+
+	if c.WebhookEventHandler != nil {
+		routes = append(routes, RouteGroup{
+			Name:    "webhook_events",
+			Handler: c.WebhookEventHandler,
+		})
+	}
+
+	if c.ChannelManagementHandler != nil {
+		routes = append(routes, RouteGroup{
+			Name:    "channel_management",
+			Handler: c.ChannelManagementHandler,
+		})
+	}
+
+	if c.SimulationHandler != nil {
+		routes = append(routes, RouteGroup{
+			Name:    "simulation",
+			Handler: c.SimulationHandler,
+		})
+	}
+
+	if c.ConversationHandler != nil {
+		routes = append(routes, RouteGroup{
+			Name:    "conversations",
+			Handler: c.ConversationHandler,
+		})
+	}
+
+	if c.AttachmentHandler != nil {
+		routes = append(routes, RouteGroup{
+			Name:    "attachments",
+			Handler: c.AttachmentHandler,
+		})
+	}
+
 	return routes
 }
 
@@ -557,6 +2352,21 @@ type RouteGroup struct {
 func (c *Container) Cleanup() {
 	log.Println("🧹 Cleaning up container resources...")
 
+	if c.FeatureFlagService != nil {
+		log.Println("  🚩 Stopping feature flag listener...")
+		c.FeatureFlagService.Stop()
+	}
+
+	if c.WorkflowCache != nil {
+		log.Println("  🗂️  Stopping workflow cache listener...")
+		c.WorkflowCache.Stop()
+	}
+
+	if c.JobRunner != nil {
+		log.Println("  🧰 Stopping job runner...")
+		c.JobRunner.Stop()
+	}
+
 	// ✅ Stop workflow scheduler
 	if c.WorkflowScheduler != nil {
 		log.Println("  ⏰ Stopping workflow scheduler...")
@@ -569,6 +2379,18 @@ func (c *Container) Cleanup() {
 		c.DelayScheduler.StopWorker()
 	}
 
+	// Pending bursts stay in Redis for the remaining instances
+	if c.MessageBufferService != nil {
+		log.Println("  📦 Stopping message buffer flusher...")
+		c.MessageBufferService.Stop()
+	}
+
+	// Flush queued messages while the database is still open
+	if c.MessageBatchWriter != nil {
+		log.Println("  💬 Flushing message batch writer...")
+		c.MessageBatchWriter.Stop()
+	}
+
 	if c.EventBus != nil {
 		log.Println("  ⚡ Disconnecting event bus...")
 		ctx := context.Background()
@@ -577,6 +2399,11 @@ func (c *Container) Cleanup() {
 		}
 	}
 
+	if c.DataRouter != nil {
+		log.Println("  🌍 Closing regional database connections...")
+		c.DataRouter.Close()
+	}
+
 	if c.DB != nil {
 		log.Println("  🗄️  Closing database connections...")
 		c.DB.Close()
@@ -619,6 +2446,9 @@ func (c *Container) HealthCheck() map[string]bool {
 	health["whatsapp_adapter"] = c.WhatsAppAdapter != nil
 	health["agent_chat_repo"] = c.AgentChatRepo != nil
 	health["delay_scheduler"] = c.DelayScheduler != nil
+	health["job_runner"] = c.JobRunner != nil
+	health["message_buffer"] = c.MessageBufferService != nil
+	health["attachment_storage"] = c.AttachmentService != nil
 
 	return health
 }
@@ -642,18 +2472,85 @@ func (c *Container) GetServiceNames() []string {
 		"EventBus",
 		"AgentChatRepo",
 		"DelayScheduler",
+		"JobService",
+		"RetentionService",
+		"EncryptionService",
+		"FeatureFlagService",
+		"BusinessHoursService",
+		"FaultInjectionService",
+		"DeadLetterService",
+		"ContinuationService",
+		"PromotionService",
+		"ReplayService",
+		"ImpactService",
+		"InactivityService",
+		"SequenceService",
+		"TagService",
+		"SearchService",
+		"SnippetService",
+		"MessageTemplateService",
+		"SurveyService",
+		"ExperimentService",
+		"CRMService",
+		"HelpdeskService",
+		"CommerceService",
+		"TranscriptExportService",
+		"MessageArchiveService",
+		"ContactService",
+		"ContactImportService",
+		"SegmentService",
+		"InboxService",
+		"SpamFilterService",
+		"ThrottleService",
+		"FallbackService",
+		"ManifestService",
+		"AttachmentService",
+		"WebhookEventService",
+		"MessageBufferService",
 	}
 }
 
 func (c *Container) GetRepositoryNames() []string {
 	return []string{
 		"UserRepo",
+		"MembershipRepo",
 		"TenantRepo",
 		"RoleRepo",
 		"ChannelRepo",
+		"SuppressionRepo",
+		"MessageRepo",
 		"WorkflowRepo",
 		"ScheduleRepo", // ✅ Added
 		"AgentChatRepo",
+		"JobRepo",
+		"RetentionPolicyRepo",
+		"EncryptionKeyRepo",
+		"DeadLetterRepo",
+		"SnapshotRepo",
+		"SequenceRepo",
+		"EnrollmentRepo",
+		"TagRepo",
+		"SnippetRepo",
+		"MessageTemplateRepo",
+		"SurveyRepo",
+		"ExperimentEventRepo",
+		"CRMConnectionRepo",
+		"HelpdeskConnectionRepo",
+		"HelpdeskTicketLinkRepo",
+		"CommerceConnectionRepo",
+		"TranscriptExportRepo",
+		"ContactRepo",
+		"ContactImportRepo",
+		"SegmentRepo",
+		"SegmentMemberRepo",
+		"ClaimRepo",
+		"SpamPolicyRepo",
+		"SpamBlockRepo",
+		"ThrottleSettingsRepo",
+		"FallbackSettingsRepo",
+		"UnmatchedCounterRepo",
+		"AttachmentRepo",
+		"WebhookEventRepo",
 	}
 }
 
@@ -669,5 +2566,12 @@ func (c *Container) GetNodeExecutorNames() []string {
 		"SwitchExecutor",    // ✅ Added
 		"LoopExecutor",      // ✅ Added
 		"ValidateExecutor",  // ✅ Added
+		"BusinessHoursExecutor",
+		"TagExecutor",
+		"SurveyExecutor",
+		"ExperimentExecutor",
+		"CRMSyncExecutor",
+		"TicketExecutor",
+		"OrderLookupExecutor",
 	}
 }
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"log"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// readinessTimeout bounds every dependency check of /readyz
+const readinessTimeout = 2 * time.Second
+
+// ReadinessCheck is the result of checking one dependency
+type ReadinessCheck struct {
+	Ready    bool   `json:"ready"`
+	Error    string `json:"error,omitempty"`
+	Detail   any    `json:"detail,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// =================================================================
+// LIVENESS & READINESS 🏥
+// =================================================================
+
+// livenessHandler answers as long as the process serves requests; it never
+// checks dependencies, so an outage doesn't get every instance restarted
+func livenessHandler() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		return ctx.JSON(fiber.Map{
+			"status": "ok",
+			"uptime": time.Since(startTime).String(),
+		})
+	}
+}
+
+// readinessHandler responds 503 until the databases, Redis and the event bus
+// answer and every migration in the migrations directory is applied
+func readinessHandler(c *Container) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		ready, checks := c.Readiness(ctx.Context())
+
+		status, statusCode := "ready", fiber.StatusOK
+		if !ready {
+			status, statusCode = "not_ready", fiber.StatusServiceUnavailable
+		}
+
+		return ctx.Status(statusCode).JSON(fiber.Map{
+			"status":    status,
+			"timestamp": time.Now(),
+			"checks":    checks,
+		})
+	}
+}
+
+// Readiness runs every readiness check
+func (c *Container) Readiness(ctx context.Context) (bool, map[string]ReadinessCheck) {
+	checks := map[string]ReadinessCheck{
+		"database": runCheck(ctx, func(ctx context.Context) (any, error) {
+			return nil, c.DB.PingContext(ctx)
+		}),
+		"redis": runCheck(ctx, func(ctx context.Context) (any, error) {
+			return nil, c.RedisClient.Ping(ctx).Err()
+		}),
+		"event_bus": runCheck(ctx, func(ctx context.Context) (any, error) {
+			if c.EventBus == nil || !c.EventBus.IsConnected() {
+				return nil, errNotConnected
+			}
+			return nil, nil
+		}),
+		"migrations": c.migrationCheck(ctx, c.DB),
+	}
+
+	// Regional databases hold tenants' messages and contacts: each one must
+	// answer and be migrated too
+	if c.DataRouter != nil {
+		for _, region := range c.DataRouter.RegionNames() {
+			db, _ := c.DataRouter.Regional(region)
+			checks["database_"+region] = runCheck(ctx, func(ctx context.Context) (any, error) {
+				return nil, db.PingContext(ctx)
+			})
+			checks["migrations_"+region] = c.migrationCheck(ctx, db)
+		}
+	}
+
+	ready := true
+	for _, check := range checks {
+		ready = ready && check.Ready
+	}
+	return ready, checks
+}
+
+// migrationCheck fails while migrations are pending in db. It passes, with a
+// note, when there are no migration files to compare with or the database
+// was migrated without the schema_migrations table.
+func (c *Container) migrationCheck(ctx context.Context, db *sqlx.DB) ReadinessCheck {
+	dir := c.Config.Server.MigrationsDir
+	if dir == "" {
+		return ReadinessCheck{Ready: true, Detail: "disabled", Duration: "0s"}
+	}
+	if _, err := database.LoadMigrations(dir); err != nil {
+		return ReadinessCheck{Ready: true, Detail: "skipped: " + err.Error(), Duration: "0s"}
+	}
+
+	return runCheck(ctx, func(ctx context.Context) (any, error) {
+		status, err := database.CheckMigrations(ctx, db, dir)
+		if err != nil {
+			return nil, err
+		}
+		if len(status.Pending) > 0 {
+			return status, errPendingMigrations
+		}
+		return status, nil
+	})
+}
+
+func runCheck(ctx context.Context, check func(ctx context.Context) (any, error)) ReadinessCheck {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := check(ctx)
+	result := ReadinessCheck{Ready: err == nil, Detail: detail, Duration: time.Since(start).String()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+type readinessError string
+
+func (e readinessError) Error() string { return string(e) }
+
+const (
+	errNotConnected      readinessError = "not connected"
+	errPendingMigrations readinessError = "migrations pending"
+)
+
+// =================================================================
+// DEBUG 🐞
+// =================================================================
+
+// registerDebugRoutes exposes runtime state under /debug. The group is only
+// registered when enabled and, with a DEBUG_TOKEN, requires it as a bearer
+// token.
+func registerDebugRoutes(app *fiber.App, c *Container) {
+	if !c.Config.Server.DebugEnabled {
+		return
+	}
+
+	debugGroup := app.Group("/debug", debugAuth(c.Config.Server.DebugToken))
+
+	debugGroup.Get("/container", func(ctx *fiber.Ctx) error {
+		return ctx.JSON(fiber.Map{
+			"services":      c.GetServiceNames(),
+			"repositories":  c.GetRepositoryNames(),
+			"health":        c.HealthCheck(),
+			"event_metrics": c.GetEventBusMetrics(),
+			"rate_limits":   c.RateLimitMetrics.Snapshot(),
+			"circuits":      c.CircuitBreakers.Snapshot(),
+			"webhooks":      c.WebhookEventMetrics.Snapshot(),
+			"spam_filter":   c.SpamFilterMetrics.Snapshot(),
+		})
+	})
+
+	debugGroup.Get("/continuations", func(ctx *fiber.Ctx) error {
+		if c.DelayScheduler == nil {
+			return ctx.JSON(fiber.Map{"enabled": false})
+		}
+		pending, err := c.DelayScheduler.GetPendingCount(ctx.Context())
+		if err != nil {
+			return err
+		}
+		return ctx.JSON(fiber.Map{"enabled": true, "pending": pending})
+	})
+
+	debugGroup.Get("/workers", func(ctx *fiber.Ctx) error {
+		return ctx.JSON(fiber.Map{"workers": c.Workers.Snapshot()})
+	})
+
+	debugGroup.Get("/cache", func(ctx *fiber.Ctx) error {
+		if c.WorkflowCache == nil {
+			return ctx.JSON(fiber.Map{})
+		}
+		return ctx.JSON(fiber.Map{"workflows": c.WorkflowCache.Stats()})
+	})
+
+	debugGroup.Get("/build", func(ctx *fiber.Ctx) error {
+		return ctx.JSON(buildInfo())
+	})
+
+	if c.Config.Server.DebugToken == "" {
+		log.Println("⚠️  Debug endpoints enabled without DEBUG_TOKEN")
+	}
+}
+
+// debugAuth requires "Authorization: Bearer <token>" when token is set
+func debugAuth(token string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if token == "" {
+			return ctx.Next()
+		}
+		given, ok := strings.CutPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid debug token"})
+		}
+		return ctx.Next()
+	}
+}
+
+// buildInfo reports the binary's module and VCS stamp and the runtime state
+func buildInfo() fiber.Map {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	info := fiber.Map{
+		"go_version": runtime.Version(),
+		"uptime":     time.Since(startTime).String(),
+		"started_at": startTime,
+		"goroutines": runtime.NumGoroutine(),
+		"memory": fiber.Map{
+			"alloc_bytes": mem.Alloc,
+			"sys_bytes":   mem.Sys,
+			"num_gc":      mem.NumGC,
+		},
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info["module"] = build.Main.Path
+		info["version"] = build.Main.Version
+		vcs := fiber.Map{}
+		for _, setting := range build.Settings {
+			if name, ok := strings.CutPrefix(setting.Key, "vcs."); ok {
+				vcs[name] = setting.Value
+			}
+		}
+		info["vcs"] = vcs
+	}
+	return info
+}
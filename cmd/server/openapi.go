@@ -0,0 +1,232 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/channelapi"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/engine/continuation"
+	"github.com/Abraxas-365/relay/engine/nodecatalog"
+	"github.com/Abraxas-365/relay/engine/promotion"
+	"github.com/Abraxas-365/relay/engine/workflowtest"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/manifest"
+	"github.com/Abraxas-365/relay/pkg/openapi"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Paths of the generated API document and its Swagger UI
+const (
+	openAPIPath   = "/api/openapi.json"
+	swaggerUIPath = "/api/docs"
+)
+
+// pageQuery pagination parameters shared by the list endpoints
+var pageQuery = []openapi.Parameter{
+	openapi.QueryParam("page", "integer", "1-based page number"),
+	openapi.QueryParam("page_size", "integer", "Items per page"),
+}
+
+// newOpenAPISpec documents every route registered on the app. Routes without
+// an annotation are still listed, only without request/response schemas;
+// annotate the ones external integrations (and pkg/client) rely on.
+func newOpenAPISpec(c *Container) *openapi.Spec {
+	spec := openapi.NewSpec(openapi.Info{
+		Title:       "Relay API",
+		Version:     "1.0.0",
+		Description: "Multi-channel messaging and workflow automation API",
+	}).Public(
+		"/",
+		"/health",
+		"/healthz",
+		"/readyz",
+		"/debug",
+		"/auth/login",
+		"/auth/callback",
+		"/auth/refresh",
+		"/auth/register",
+		"/auth/verify-email",
+		"/auth/forgot-password",
+		"/auth/reset-password",
+		"/auth/sso",
+		"/webhooks",
+		"/attachments/files",
+		openAPIPath,
+		swaggerUIPath,
+	)
+	if baseURL := c.Config.Auth.Password.AppBaseURL; baseURL != "" {
+		spec.Server(baseURL, c.Config.Server.Environment)
+	}
+
+	// Auth
+	spec.Describe(fiber.MethodPost, "/auth/login/password", openapi.Annotation{
+		Summary:  "Log in with email and password",
+		Request:  auth.PasswordLoginRequest{},
+		Response: auth.TokenResponse{},
+	})
+	spec.Describe(fiber.MethodPost, "/auth/refresh", openapi.Annotation{
+		Summary:  "Exchange a refresh token for a new access token",
+		Request:  auth.RefreshTokenRequest{},
+		Response: auth.TokenResponse{},
+	})
+
+	spec.Describe(fiber.MethodGet, "/auth/tenants", openapi.Annotation{
+		Summary: "List the tenants the user can switch to",
+		Response: struct {
+			Tenants []auth.UserTenantDTO `json:"tenants"`
+			Total   int                  `json:"total"`
+		}{},
+	})
+
+	// Channels
+	spec.Describe(fiber.MethodGet, "/api/channels", openapi.Annotation{
+		Summary: "List the tenant's channels",
+		Query: append(pageQuery,
+			openapi.QueryParam("type", "string", "Channel type, e.g. WHATSAPP"),
+			openapi.QueryParam("is_active", "boolean", "Only active or inactive channels"),
+			openapi.QueryParam("provider", "string", "Provider name"),
+			openapi.QueryParam("environment", "string", "sandbox or production"),
+			openapi.QueryParam("search", "string", "Name search"),
+		),
+		Response: channels.ChannelListResponse{},
+	})
+	spec.Describe(fiber.MethodPost, "/api/channels", openapi.Annotation{
+		Summary:  "Create a channel",
+		Request:  channels.CreateChannelRequest{},
+		Response: channels.Channel{},
+		Status:   http.StatusCreated,
+	})
+	spec.Describe(fiber.MethodPost, "/api/channels/validate", openapi.Annotation{
+		Summary:  "Validate a channel config without saving it",
+		Request:  channels.ValidateChannelConfigRequest{},
+		Response: channels.ValidateChannelConfigResponse{},
+	})
+	spec.Describe(fiber.MethodGet, "/api/channels/:id", openapi.Annotation{
+		Summary:  "Get a channel with its features",
+		Response: channels.ChannelResponse{},
+	})
+	spec.Describe(fiber.MethodPut, "/api/channels/:id", openapi.Annotation{
+		Summary:  "Update a channel",
+		Request:  channels.UpdateChannelRequest{},
+		Response: channels.Channel{},
+	})
+	spec.Describe(fiber.MethodDelete, "/api/channels/:id", openapi.Annotation{
+		Summary: "Delete a channel",
+		Status:  http.StatusNoContent,
+	})
+	spec.Describe(fiber.MethodPost, "/api/channels/:id/test", openapi.Annotation{
+		Summary:  "Test the provider connection with the stored config",
+		Response: channels.TestChannelResponse{},
+	})
+	spec.Describe(fiber.MethodGet, "/api/channels/:id/features", openapi.Annotation{
+		Summary:  "Get the features the channel supports",
+		Response: channels.ChannelFeaturesResponse{},
+	})
+	spec.Describe(fiber.MethodGet, "/api/channels/:id/webhook", openapi.Annotation{
+		Summary:  "Get the webhook URL to configure in the provider",
+		Response: channels.ChannelWebhookResponse{},
+	})
+	spec.Describe(fiber.MethodPost, "/api/channels/:id/messages", openapi.Annotation{
+		Summary:  "Send an operator message through the channel",
+		Request:  channels.SendMessageRequest{},
+		Response: channels.SendMessageResponse{},
+		Status:   http.StatusAccepted,
+	})
+
+	// Simulation
+	spec.Describe(fiber.MethodPost, "/api/simulate", openapi.Annotation{
+		Summary:     "Simulate an inbound message",
+		Description: "Runs a synthetic message on a TEST_HTTP channel through the real pipeline; nothing reaches a provider.",
+		Tags:        []string{"simulate"},
+		Request:     channelapi.SimulateRequest{},
+		Response:    channelapi.SimulateResponse{},
+	})
+
+	// Workflows
+	spec.Describe(fiber.MethodPost, "/api/workflows/:id/tests", openapi.Annotation{
+		Summary:     "Run a conversation test script against a workflow",
+		Description: "Failed expectations are reported in the body with status 200.",
+		Request:     workflowtest.Script{},
+		Response:    workflowtest.Report{},
+	})
+	spec.Describe(fiber.MethodPost, "/api/workflows/:id/promote", openapi.Annotation{
+		Summary:     "Promote a sandbox workflow to production",
+		Description: "Creates or updates the production workflow of the same name, re-linking sandbox channels to the production channels with the same name or to the ones in channel_map.",
+		Request:     promotion.PromoteRequest{},
+		Response:    promotion.PromoteResponse{},
+	})
+	spec.Describe(fiber.MethodGet, "/api/node-types", openapi.Annotation{
+		Summary: "List the node types workflows can use, with their schemas",
+		Response: struct {
+			NodeTypes []nodecatalog.NodeTypeInfo `json:"node_types"`
+			Total     int                        `json:"total"`
+		}{},
+	})
+	spec.Describe(fiber.MethodGet, "/api/node-types/:type", openapi.Annotation{
+		Summary:  "Get a node type and its schema",
+		Response: nodecatalog.NodeTypeInfo{},
+	})
+
+	// Continuations
+	spec.Describe(fiber.MethodGet, "/api/continuations", openapi.Annotation{
+		Summary:  "List pending continuations, soonest first",
+		Query:    pageQuery,
+		Response: engine.ContinuationListResponse{},
+	})
+	spec.Describe(fiber.MethodGet, "/api/continuations/stats", openapi.Annotation{
+		Summary:  "Count pending continuations by workflow",
+		Response: continuation.Stats{},
+	})
+	spec.Describe(fiber.MethodGet, "/api/continuations/:id", openapi.Annotation{
+		Summary:  "Get a pending continuation",
+		Response: engine.WorkflowContinuation{},
+	})
+
+	// Background jobs
+	spec.Describe(fiber.MethodGet, "/api/jobs", openapi.Annotation{
+		Summary: "List the tenant's background jobs and the system jobs",
+		Query: append([]openapi.Parameter{
+			openapi.QueryParam("status", "string", "pending, running, succeeded or failed"),
+			openapi.QueryParam("kind", "string", "Job kind, e.g. transcript.export"),
+			openapi.QueryParam("system", "boolean", "Include system jobs (default true)"),
+		}, pageQuery...),
+		Response: jobs.JobListResponse{},
+	})
+	spec.Describe(fiber.MethodGet, "/api/jobs/:id", openapi.Annotation{
+		Summary:  "Get a background job",
+		Response: jobs.Job{},
+	})
+	spec.Describe(fiber.MethodGet, "/api/jobs/:id/runs", openapi.Annotation{
+		Summary:  "List a job's runs, newest first",
+		Query:    pageQuery,
+		Response: jobs.RunListResponse{},
+	})
+	spec.Describe(fiber.MethodPost, "/api/jobs/:id/run", openapi.Annotation{
+		Summary:     "Run one of the tenant's jobs now",
+		Description: "Retries a failed job or brings a recurring one forward. System jobs and running jobs answer 409.",
+		Response:    jobs.Job{},
+	})
+
+	// Declarative configuration
+	pruneQuery := []openapi.Parameter{
+		openapi.QueryParam("prune", "boolean", "Delete channels, roles and workflows the manifest does not declare"),
+	}
+	spec.Describe(fiber.MethodPost, "/api/manifest/plan", openapi.Annotation{
+		Summary:     "Plan the changes a tenant manifest would make",
+		Description: "The body is the manifest as YAML or JSON.",
+		Query:       pruneQuery,
+		Request:     manifest.Manifest{},
+		Response:    manifest.Plan{},
+	})
+	spec.Describe(fiber.MethodPost, "/api/manifest/apply", openapi.Annotation{
+		Summary:     "Reconcile the tenant with a manifest",
+		Description: "The body is the manifest as YAML or JSON. Responds 422 with the applied changes if one fails.",
+		Query:       pruneQuery,
+		Request:     manifest.Manifest{},
+		Response:    manifest.ApplyResult{},
+	})
+
+	return spec
+}
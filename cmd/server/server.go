@@ -12,6 +12,8 @@ import (
 	"github.com/Abraxas-365/craftable/errx/errxfiber"
 	"github.com/Abraxas-365/relay/pkg/config"
 	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/Abraxas-365/relay/pkg/openapi"
+	"github.com/Abraxas-365/relay/pkg/ratelimit"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -153,6 +155,8 @@ func setupMiddleware(app *fiber.App, cfg *config.Config) {
 func setupRoutes(app *fiber.App, c *Container) {
 	// Health check
 	app.Get("/health", healthCheckHandler(c))
+	app.Get("/healthz", livenessHandler())
+	app.Get("/readyz", readinessHandler(c))
 
 	// Root endpoint
 	app.Get("/", func(ctx *fiber.Ctx) error {
@@ -165,15 +169,45 @@ func setupRoutes(app *fiber.App, c *Container) {
 		})
 	})
 
+	// =================================================================
+	// RATE LIMITING (antes de registrar las rutas de cada prefijo)
+	// =================================================================
+	limits := c.Config.RateLimit
+	app.Use("/auth", c.RateLimit("auth_ip", limits.AuthIP, ratelimit.ByIP()))
+	app.Use("/webhooks",
+		c.RateLimit("webhook_ip", limits.WebhookIP, ratelimit.ByIP()),
+		// /webhooks/<provider>/:tenantId/...
+		c.RateLimit("webhook_tenant", limits.WebhookTenant, ratelimit.ByPathSegment(2)),
+	)
+
 	// =================================================================
 	// AUTH ROUTES
 	// =================================================================
 	c.AuthHandlers.RegisterRoutes(app)
+	c.SSOHandlers.RegisterRoutes(app)
 	c.WhatsAppWebhookRoutes.RegisterRoutes(app)
+	if c.InstagramWebhookRoutes != nil {
+		c.InstagramWebhookRoutes.RegisterRoutes(app)
+		log.Println("    ✅ Instagram webhook routes registered")
+	}
+	if c.RCSWebhookRoutes != nil {
+		c.RCSWebhookRoutes.RegisterRoutes(app)
+		log.Println("    ✅ RCS webhook routes registered")
+	}
+	if c.AppleWebhookRoutes != nil {
+		c.AppleWebhookRoutes.RegisterRoutes(app)
+		log.Println("    ✅ Apple Messages webhook routes registered")
+	}
 	if c.WebhookTriggerRoutes != nil {
 		c.WebhookTriggerRoutes.RegisterRoutes(app)
 		log.Println("    ✅ Webhook trigger routes registered")
 	}
+	if c.AttachmentRoutes != nil {
+		// Signed links carry their own credential
+		c.AttachmentRoutes.RegisterPublicRoutes(app)
+	}
+	// The OAuth state identifies the tenant
+	c.CRMRoutes.RegisterPublicRoutes(app)
 
 	// =================================================================
 	// TEST ROUTES (Development/Testing)
@@ -181,31 +215,95 @@ func setupRoutes(app *fiber.App, c *Container) {
 	if c.Config.Server.Environment == "development" {
 	}
 
+	// =================================================================
+	// API DOCS (antes del grupo /api, que exige autenticación)
+	// =================================================================
+	app.Get(openAPIPath, openapi.Handler(newOpenAPISpec(c), app))
+	app.Get(swaggerUIPath, openapi.SwaggerUI("Relay API", openAPIPath))
+
 	// =================================================================
 	// PROTECTED API ROUTES
 	// =================================================================
 	api := app.Group("/api")
+	api.Use(c.RateLimit("api_ip", limits.APIIP, ratelimit.ByIP()))
 	api.Use(c.AuthMiddleware.Authenticate())
+	api.Use(c.RateLimit("api_tenant", limits.APITenant, ratelimit.ByTenant()))
+
+	c.SSOHandlers.RegisterAdminRoutes(api, c.AuthMiddleware)
+	c.AuthHandlers.RegisterAdminRoutes(api, c.AuthMiddleware)
+	c.TenantRoutes.RegisterRoutes(api)
+	c.MemberRoutes.RegisterRoutes(api)
+	c.JobRoutes.RegisterRoutes(api)
+	c.RetentionRoutes.RegisterRoutes(api)
+	c.EncryptionRoutes.RegisterRoutes(api)
+	c.FeatureFlagRoutes.RegisterRoutes(api)
+	c.BusinessHoursRoutes.RegisterRoutes(api)
+	c.FaultInjectionRoutes.RegisterRoutes(api)
+	c.NodeCatalogRoutes.RegisterRoutes(api)
+	c.DeadLetterRoutes.RegisterRoutes(api)
+	c.ContinuationRoutes.RegisterRoutes(api)
+	c.WorkflowTestRoutes.RegisterRoutes(api)
+	c.ReplayRoutes.RegisterRoutes(api)
+	c.ImpactRoutes.RegisterRoutes(api)
+	c.PromotionRoutes.RegisterRoutes(api)
+	c.ScheduleRoutes.RegisterRoutes(api)
+	c.SequenceRoutes.RegisterRoutes(api)
+	c.SnippetRoutes.RegisterRoutes(api)
+	c.MessageTemplateRoutes.RegisterRoutes(api)
+	c.SurveyRoutes.RegisterRoutes(api)
+	c.ExperimentRoutes.RegisterRoutes(api)
+	c.CRMRoutes.RegisterRoutes(api)
+	c.HelpdeskRoutes.RegisterRoutes(api)
+	c.CommerceRoutes.RegisterRoutes(api)
+	c.TranscriptRoutes.RegisterRoutes(api)
+	c.ArchiveRoutes.RegisterRoutes(api)
+	c.ContactRoutes.RegisterRoutes(api)
+	c.SegmentRoutes.RegisterRoutes(api)
+	c.InboxRoutes.RegisterRoutes(api)
+	c.SpamFilterRoutes.RegisterRoutes(api)
+	c.ThrottleRoutes.RegisterRoutes(api)
+	c.FallbackRoutes.RegisterRoutes(api)
+	c.ManifestRoutes.RegisterRoutes(api)
+
+	if c.ChannelRoutes != nil {
+		c.ChannelRoutes.RegisterRoutes(api)
+		log.Println("    ✅ Channel management routes registered")
+	}
+
+	if c.WebhookEventRoutes != nil {
+		c.WebhookEventRoutes.RegisterRoutes(api)
+		log.Println("    ✅ Webhook event routes registered")
+	}
+
+	if c.ConversationRoutes != nil {
+		c.ConversationRoutes.RegisterRoutes(api)
+		log.Println("    ✅ Conversation routes registered")
+	}
+
+	if c.SearchRoutes != nil {
+		c.SearchRoutes.RegisterRoutes(api)
+		log.Println("    ✅ Search routes registered")
+	}
+
+	if c.SimulationRoutes != nil {
+		c.SimulationRoutes.RegisterRoutes(api)
+		log.Println("    ✅ Simulation routes registered")
+	}
+
+	if c.AttachmentRoutes != nil {
+		c.AttachmentRoutes.RegisterRoutes(api)
+		log.Println("    ✅ Attachment routes registered")
+	}
 
 	// TODO: Add your business routes here
-	// api.Get("/channels", channelHandlers.List)
 	// api.Post("/workflows", workflowHandlers.Create)
 	// api.Post("/messages", messageHandlers.Create)
 	// etc...
 
 	// =================================================================
-	// DEBUG ROUTES (only in development)
+	// DEBUG ROUTES (development, or DEBUG_ENDPOINTS_ENABLED)
 	// =================================================================
-	if c.Config.Server.Environment == "development" {
-		app.Get("/debug/container", func(ctx *fiber.Ctx) error {
-			return ctx.JSON(fiber.Map{
-				"services":      c.GetServiceNames(),
-				"repositories":  c.GetRepositoryNames(),
-				"health":        c.HealthCheck(),
-				"event_metrics": c.GetEventBusMetrics(),
-			})
-		})
-	}
+	registerDebugRoutes(app, c)
 
 	// =================================================================
 	// 404 HANDLER
@@ -240,11 +338,13 @@ func healthCheckHandler(c *Container) fiber.Handler {
 		}
 
 		return ctx.Status(statusCode).JSON(fiber.Map{
-			"status":    status,
-			"timestamp": time.Now(),
-			"uptime":    time.Since(startTime).String(),
-			"services":  health,
-			"version":   "1.0.0",
+			"status":      status,
+			"timestamp":   time.Now(),
+			"uptime":      time.Since(startTime).String(),
+			"services":    health,
+			"rate_limits": c.RateLimitMetrics.Snapshot(),
+			"circuits":    c.CircuitBreakers.Snapshot(),
+			"version":     "1.0.0",
 			"components": fiber.Map{
 				"services":     c.GetServiceNames(),
 				"repositories": c.GetRepositoryNames(),
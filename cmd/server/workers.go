@@ -0,0 +1,64 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// WorkerStatus is the state of one background worker
+type WorkerStatus struct {
+	Name      string     `json:"name"`
+	Running   bool       `json:"running"`
+	StartedAt time.Time  `json:"started_at"`
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+}
+
+// WorkerRegistry records the background workers the container starts, so
+// /debug/workers can tell a stopped worker from one that never started
+type WorkerRegistry struct {
+	mu      sync.Mutex
+	workers map[string]*WorkerStatus
+}
+
+func NewWorkerRegistry() *WorkerRegistry {
+	return &WorkerRegistry{workers: make(map[string]*WorkerStatus)}
+}
+
+// Run calls a blocking Start function and marks the worker stopped when it
+// returns. Call it with go.
+func (r *WorkerRegistry) Run(name string, start func()) {
+	r.Started(name)
+	defer r.stopped(name)
+	start()
+}
+
+// Started marks a worker that runs its own goroutines as running
+func (r *WorkerRegistry) Started(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.workers[name] = &WorkerStatus{Name: name, Running: true, StartedAt: time.Now()}
+}
+
+func (r *WorkerRegistry) stopped(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if status, ok := r.workers[name]; ok {
+		now := time.Now()
+		status.Running = false
+		status.StoppedAt = &now
+	}
+}
+
+// Snapshot returns every worker sorted by name
+func (r *WorkerRegistry) Snapshot() []WorkerStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make([]WorkerStatus, 0, len(r.workers))
+	for _, status := range r.workers {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
@@ -0,0 +1,179 @@
+package commerce
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Providers
+// ============================================================================
+
+// Provider is a store platform a tenant can connect
+type Provider string
+
+const (
+	ProviderShopify     Provider = "shopify"
+	ProviderWooCommerce Provider = "woocommerce"
+)
+
+func (p Provider) IsValid() bool {
+	return p == ProviderShopify || p == ProviderWooCommerce
+}
+
+// ============================================================================
+// Connection
+// ============================================================================
+
+// domainPattern is a store host (acme.myshopify.com, shop.acme.com) or a
+// Shopify store name (acme)
+var domainPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+
+// Connection is a tenant's API access to one store. Credentials are never
+// serialized; the repository stores them sealed with the tenant's key.
+type Connection struct {
+	TenantID        kernel.TenantID `json:"tenant_id"`
+	Provider        Provider        `json:"provider"`
+	Domain          string          `json:"domain"`
+	Credentials     Credentials     `json:"-"`
+	ResponseMapping ResponseMapping `json:"response_mapping"`
+	ConnectedAt     time.Time       `json:"connected_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// Credentials authenticate API calls: Shopify uses an Admin API access token
+// of a custom app, WooCommerce a REST API consumer key and secret
+type Credentials struct {
+	AccessToken    string `json:"access_token,omitempty"`
+	ConsumerKey    string `json:"consumer_key,omitempty"`
+	ConsumerSecret string `json:"consumer_secret,omitempty"`
+}
+
+// Host is the store's API host; a bare Shopify store name is completed with
+// myshopify.com
+func (c Connection) Host() string {
+	if c.Provider == ProviderShopify && !strings.Contains(c.Domain, ".") {
+		return c.Domain + ".myshopify.com"
+	}
+	return c.Domain
+}
+
+// NormalizeDomain lowercases a domain and strips a scheme and path pasted
+// with it, so https://acme.myshopify.com/admin is acme.myshopify.com
+func NormalizeDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	domain = strings.TrimPrefix(domain, "https://")
+	domain = strings.TrimPrefix(domain, "http://")
+	domain, _, _ = strings.Cut(domain, "/")
+	return domain
+}
+
+func ValidDomain(domain string) bool {
+	return len(domain) <= 253 && domainPattern.MatchString(domain)
+}
+
+// ============================================================================
+// Response Mapping
+// ============================================================================
+
+// outputFieldPattern is the name a mapped value gets in an order's fields
+var outputFieldPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// ResponseMapping extracts extra values from the store's own order
+// representation: output field → dotted path, indexes included, e.g.
+// {"carrier": "fulfillments.0.tracking_company"}
+type ResponseMapping map[string]string
+
+func (m ResponseMapping) Validate() error {
+	for field, path := range m {
+		if !outputFieldPattern.MatchString(field) {
+			return ErrInvalidMapping().
+				WithDetail("field", field).
+				WithDetail("reason", "field names are lower snake case")
+		}
+		if strings.TrimSpace(path) == "" {
+			return ErrInvalidMapping().
+				WithDetail("field", field).
+				WithDetail("reason", "path is required")
+		}
+	}
+	return nil
+}
+
+// Apply returns the mapped values found in raw, skipping missing paths
+func (m ResponseMapping) Apply(raw map[string]any) map[string]any {
+	if len(m) == 0 {
+		return nil
+	}
+	fields := make(map[string]any, len(m))
+	for field, path := range m {
+		if value, ok := valueAt(raw, path); ok {
+			fields[field] = value
+		}
+	}
+	return fields
+}
+
+// valueAt walks a dotted path through nested objects and arrays
+func valueAt(raw map[string]any, path string) (any, bool) {
+	var current any = raw
+	for _, key := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, current != nil
+}
+
+// ============================================================================
+// Orders
+// ============================================================================
+
+// FoundOrder is an order as a connector found it: normalized, plus the
+// store's representation the response mapping reads
+type FoundOrder struct {
+	Order engine.Order
+	Raw   map[string]any
+}
+
+// Lookup is a connector's search, already validated
+type Lookup struct {
+	By    engine.OrderLookupBy
+	Value string
+	Limit int
+}
+
+// NormalizeOrderNumber strips the # customers type before order numbers
+func NormalizeOrderNumber(number string) string {
+	return strings.TrimPrefix(strings.TrimSpace(number), "#")
+}
+
+// PhoneDigits keeps the digits of a phone number, for comparing numbers
+// written differently
+func PhoneDigits(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	return digits.String()
+}
@@ -0,0 +1,94 @@
+package commerceapi
+
+import (
+	"github.com/Abraxas-365/relay/commerce"
+	"github.com/Abraxas-365/relay/commerce/commercesrv"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CommerceHandler connects tenants to their store
+type CommerceHandler struct {
+	service *commercesrv.CommerceService
+}
+
+// NewCommerceHandler creates a new commerce handler
+func NewCommerceHandler(service *commercesrv.CommerceService) *CommerceHandler {
+	return &CommerceHandler{
+		service: service,
+	}
+}
+
+// ListConnections returns the tenant's connections and the providers it can connect
+// GET /api/commerce/connections
+func (h *CommerceHandler) ListConnections(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	connections, err := h.service.ListConnections(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(connections)
+}
+
+// Connect verifies API credentials and saves the connection
+// PUT /api/commerce/connections/:provider
+func (h *CommerceHandler) Connect(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req commerce.ConnectRequest
+	if err := c.BodyParser(&req); err != nil {
+		return commerce.ErrInvalidConnection().WithDetail("reason", err.Error())
+	}
+
+	connection, err := h.service.Connect(c.Context(), authContext.TenantID, commerce.Provider(c.Params("provider")), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(connection)
+}
+
+// SaveMapping replaces the connection's response mapping
+// PUT /api/commerce/connections/:provider/mapping
+func (h *CommerceHandler) SaveMapping(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req commerce.SaveMappingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return commerce.ErrInvalidMapping().WithDetail("reason", err.Error())
+	}
+
+	connection, err := h.service.SaveMapping(c.Context(), authContext.TenantID, commerce.Provider(c.Params("provider")), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(connection)
+}
+
+// Disconnect forgets the tenant's credentials for the store
+// DELETE /api/commerce/connections/:provider
+func (h *CommerceHandler) Disconnect(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := h.service.Disconnect(c.Context(), authContext.TenantID, commerce.Provider(c.Params("provider"))); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,31 @@
+package commerceapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CommerceRoutes handles commerce route setup
+type CommerceRoutes struct {
+	handler        *CommerceHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewCommerceRoutes creates a new commerce routes instance
+func NewCommerceRoutes(handler *CommerceHandler, authMiddleware *auth.AuthMiddleware) *CommerceRoutes {
+	return &CommerceRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers commerce routes on an authenticated router.
+// Connecting, mapping and disconnecting requires an admin.
+func (r *CommerceRoutes) RegisterRoutes(router fiber.Router) {
+	connections := router.Group("/commerce/connections")
+
+	connections.Get("/", r.handler.ListConnections)
+	connections.Put("/:provider", r.authMiddleware.RequireAdmin(), r.handler.Connect)
+	connections.Put("/:provider/mapping", r.authMiddleware.RequireAdmin(), r.handler.SaveMapping)
+	connections.Delete("/:provider", r.authMiddleware.RequireAdmin(), r.handler.Disconnect)
+}
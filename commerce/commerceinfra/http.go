@@ -0,0 +1,100 @@
+package commerceinfra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/commerce"
+)
+
+// requestTimeout bounds every call to a store; agents wait for the answer
+const requestTimeout = 15 * time.Second
+
+// maxResponseBytes bounds what is read from a store response
+const maxResponseBytes = 4 << 20
+
+// apiClient reads from a store's REST API
+type apiClient struct {
+	http *http.Client
+}
+
+func newAPIClient() apiClient {
+	return apiClient{http: &http.Client{Timeout: requestTimeout}}
+}
+
+// get decodes the JSON response into out after authorize adds the
+// credentials. A 401 or 403 fails with commerce.ErrCredentialsRejected,
+// other failures with commerce.ErrRequestFailed carrying the status.
+func (c apiClient) get(ctx context.Context, endpoint string, authorize func(*http.Request), out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	authorize(req)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return commerce.ErrRequestFailed().WithDetail("reason", err.Error())
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return commerce.ErrRequestFailed().WithDetail("reason", err.Error())
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return commerce.ErrCredentialsRejected().WithDetail("status", resp.StatusCode)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return commerce.ErrRequestFailed().
+			WithDetail("status", resp.StatusCode).
+			WithDetail("response", truncate(string(data), 500))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return commerce.ErrRequestFailed().WithDetail("reason", fmt.Sprintf("invalid response: %v", err))
+	}
+	return nil
+}
+
+// responseStatus is the HTTP status a failed request answered with, 0 when
+// it got no answer
+func responseStatus(err error) int {
+	var e *errx.Error
+	if errors.As(err, &e) {
+		status, _ := e.Details["status"].(int)
+		return status
+	}
+	return 0
+}
+
+// decodeOrder reads a store order twice: into the connector's typed view
+// and into the raw representation the response mapping reads
+func decodeOrder(data json.RawMessage, typed any) (map[string]any, error) {
+	if err := json.Unmarshal(data, typed); err != nil {
+		return nil, commerce.ErrRequestFailed().WithDetail("reason", fmt.Sprintf("invalid order: %v", err))
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, commerce.ErrRequestFailed().WithDetail("reason", fmt.Sprintf("invalid order: %v", err))
+	}
+	return raw, nil
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
@@ -0,0 +1,156 @@
+package commerceinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/commerce"
+	"github.com/Abraxas-365/relay/encryption"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresConnectionRepository is the PostgreSQL implementation of
+// commerce.ConnectionRepository. Credentials are sealed with the tenant's
+// data key when the tenant has encryption enabled; the response mapping is
+// stored as is.
+type PostgresConnectionRepository struct {
+	db     *sqlx.DB
+	cipher *encryption.FieldCipher
+}
+
+var _ commerce.ConnectionRepository = (*PostgresConnectionRepository)(nil)
+
+func NewPostgresConnectionRepository(db *sqlx.DB, cipher *encryption.FieldCipher) *PostgresConnectionRepository {
+	return &PostgresConnectionRepository{db: db, cipher: cipher}
+}
+
+const connectionColumns = `tenant_id, provider, domain, credentials, response_mapping, connected_at, updated_at`
+
+type connectionRow struct {
+	TenantID    string    `db:"tenant_id"`
+	Provider    string    `db:"provider"`
+	Domain      string    `db:"domain"`
+	Credentials []byte    `db:"credentials"`
+	Mapping     []byte    `db:"response_mapping"`
+	ConnectedAt time.Time `db:"connected_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (r *PostgresConnectionRepository) Find(ctx context.Context, tenantID kernel.TenantID, provider commerce.Provider) (*commerce.Connection, error) {
+	query := `SELECT ` + connectionColumns + ` FROM commerce_connections WHERE tenant_id = $1 AND provider = $2`
+
+	var row connectionRow
+	if err := r.db.GetContext(ctx, &row, query, tenantID.String(), string(provider)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, commerce.ErrConnectionNotFound().WithDetail("provider", string(provider))
+		}
+		return nil, errx.Wrap(err, "failed to find store connection", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	return r.toConnection(ctx, row)
+}
+
+func (r *PostgresConnectionRepository) List(ctx context.Context, tenantID kernel.TenantID) ([]commerce.Connection, error) {
+	query := `SELECT ` + connectionColumns + ` FROM commerce_connections WHERE tenant_id = $1 ORDER BY provider`
+
+	var rows []connectionRow
+	if err := r.db.SelectContext(ctx, &rows, query, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to list store connections", errx.TypeInternal)
+	}
+
+	connections := make([]commerce.Connection, 0, len(rows))
+	for _, row := range rows {
+		connection, err := r.toConnection(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		connections = append(connections, *connection)
+	}
+
+	return connections, nil
+}
+
+func (r *PostgresConnectionRepository) Save(ctx context.Context, connection commerce.Connection) error {
+	credentials, err := json.Marshal(connection.Credentials)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal store credentials", errx.TypeInternal)
+	}
+	credentials, err = r.cipher.EncryptJSON(ctx, connection.TenantID, credentials)
+	if err != nil {
+		return err
+	}
+	mapping, err := json.Marshal(connection.ResponseMapping)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal response mapping", errx.TypeInternal)
+	}
+
+	query := `
+		INSERT INTO commerce_connections (` + connectionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (tenant_id, provider) DO UPDATE SET
+			domain = EXCLUDED.domain,
+			credentials = EXCLUDED.credentials,
+			response_mapping = EXCLUDED.response_mapping,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.ExecContext(ctx, query,
+		connection.TenantID.String(),
+		string(connection.Provider),
+		connection.Domain,
+		credentials,
+		mapping,
+		connection.ConnectedAt,
+		connection.UpdatedAt,
+	); err != nil {
+		return errx.Wrap(err, "failed to save store connection", errx.TypeInternal).
+			WithDetail("tenant_id", connection.TenantID.String()).
+			WithDetail("provider", string(connection.Provider))
+	}
+
+	return nil
+}
+
+func (r *PostgresConnectionRepository) Delete(ctx context.Context, tenantID kernel.TenantID, provider commerce.Provider) error {
+	query := `DELETE FROM commerce_connections WHERE tenant_id = $1 AND provider = $2`
+
+	result, err := r.db.ExecContext(ctx, query, tenantID.String(), string(provider))
+	if err != nil {
+		return errx.Wrap(err, "failed to delete store connection", errx.TypeInternal)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return commerce.ErrConnectionNotFound().WithDetail("provider", string(provider))
+	}
+
+	return nil
+}
+
+func (r *PostgresConnectionRepository) toConnection(ctx context.Context, row connectionRow) (*commerce.Connection, error) {
+	tenantID := kernel.TenantID(row.TenantID)
+	connection := &commerce.Connection{
+		TenantID:    tenantID,
+		Provider:    commerce.Provider(row.Provider),
+		Domain:      row.Domain,
+		ConnectedAt: row.ConnectedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+
+	credentials, err := r.cipher.DecryptJSON(ctx, tenantID, row.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(credentials, &connection.Credentials); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal store credentials", errx.TypeInternal)
+	}
+	if len(row.Mapping) > 0 {
+		if err := json.Unmarshal(row.Mapping, &connection.ResponseMapping); err != nil {
+			return nil, errx.Wrap(err, "failed to unmarshal response mapping", errx.TypeInternal)
+		}
+	}
+
+	return connection, nil
+}
@@ -0,0 +1,193 @@
+package commerceinfra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Abraxas-365/relay/commerce"
+	"github.com/Abraxas-365/relay/engine"
+)
+
+const shopifyAPIVersion = "2024-07"
+
+// ShopifyConnector searches orders through the Shopify Admin REST API with
+// the access token of a custom app granted read_orders and read_customers.
+// Contacts are found among the store's customers, then their orders listed.
+type ShopifyConnector struct {
+	api apiClient
+}
+
+var _ commerce.Connector = (*ShopifyConnector)(nil)
+
+func NewShopifyConnector() *ShopifyConnector {
+	return &ShopifyConnector{
+		api: newAPIClient(),
+	}
+}
+
+func (c *ShopifyConnector) Provider() commerce.Provider {
+	return commerce.ProviderShopify
+}
+
+func (c *ShopifyConnector) Verify(ctx context.Context, connection commerce.Connection) error {
+	return c.api.get(ctx, c.url(connection, "/shop.json", nil), c.authorize(connection), nil)
+}
+
+// ============================================================================
+// Orders
+// ============================================================================
+
+type shopifyOrder struct {
+	ID                int64     `json:"id"`
+	Name              string    `json:"name"` // #1001
+	Email             string    `json:"email"`
+	Phone             string    `json:"phone"`
+	CreatedAt         time.Time `json:"created_at"`
+	CancelledAt       *string   `json:"cancelled_at"`
+	FinancialStatus   string    `json:"financial_status"`
+	FulfillmentStatus *string   `json:"fulfillment_status"` // null until something ships
+	TotalPrice        string    `json:"total_price"`
+	Currency          string    `json:"currency"`
+	OrderStatusURL    string    `json:"order_status_url"`
+	LineItems         []struct {
+		Title    string `json:"title"`
+		Quantity int    `json:"quantity"`
+		Price    string `json:"price"`
+	} `json:"line_items"`
+	Fulfillments []struct {
+		TrackingCompany string   `json:"tracking_company"`
+		TrackingNumbers []string `json:"tracking_numbers"`
+		TrackingURLs    []string `json:"tracking_urls"`
+	} `json:"fulfillments"`
+}
+
+func (c *ShopifyConnector) Orders(ctx context.Context, connection commerce.Connection, lookup commerce.Lookup) ([]commerce.FoundOrder, error) {
+	switch lookup.By {
+	case engine.OrderLookupByNumber:
+		name := commerce.NormalizeOrderNumber(lookup.Value)
+		if commerce.PhoneDigits(name) == name {
+			name = "#" + name // Default order names; stores may set a prefix
+		}
+		query := url.Values{"status": {"any"}, "name": {name}, "limit": {"1"}}
+		return c.list(ctx, connection, c.url(connection, "/orders.json", query))
+	case engine.OrderLookupByEmail:
+		return c.customerOrders(ctx, connection, fmt.Sprintf("email:%q", lookup.Value), lookup.Limit)
+	case engine.OrderLookupByPhone:
+		// Shopify keeps phones in E.164
+		return c.customerOrders(ctx, connection, "phone:+"+commerce.PhoneDigits(lookup.Value), lookup.Limit)
+	default:
+		return nil, commerce.ErrInvalidLookup().WithDetail("by", string(lookup.By))
+	}
+}
+
+// customerOrders lists the recent orders of the customer the search query
+// finds
+func (c *ShopifyConnector) customerOrders(ctx context.Context, connection commerce.Connection, search string, limit int) ([]commerce.FoundOrder, error) {
+	var found struct {
+		Customers []struct {
+			ID int64 `json:"id"`
+		} `json:"customers"`
+	}
+	query := url.Values{"query": {search}, "fields": {"id"}, "limit": {"1"}}
+	if err := c.api.get(ctx, c.url(connection, "/customers/search.json", query), c.authorize(connection), &found); err != nil {
+		return nil, err
+	}
+	if len(found.Customers) == 0 {
+		return nil, nil
+	}
+
+	path := fmt.Sprintf("/customers/%d/orders.json", found.Customers[0].ID)
+	return c.list(ctx, connection, c.url(connection, path, url.Values{"status": {"any"}, "limit": {strconv.Itoa(limit)}}))
+}
+
+func (c *ShopifyConnector) list(ctx context.Context, connection commerce.Connection, endpoint string) ([]commerce.FoundOrder, error) {
+	var page struct {
+		Orders []json.RawMessage `json:"orders"`
+	}
+	if err := c.api.get(ctx, endpoint, c.authorize(connection), &page); err != nil {
+		return nil, err
+	}
+
+	orders := make([]commerce.FoundOrder, 0, len(page.Orders))
+	for _, data := range page.Orders {
+		var order shopifyOrder
+		raw, err := decodeOrder(data, &order)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, commerce.FoundOrder{Order: c.normalize(order), Raw: raw})
+	}
+	return orders, nil
+}
+
+func (c *ShopifyConnector) normalize(order shopifyOrder) engine.Order {
+	fulfillment := "unfulfilled"
+	if order.FulfillmentStatus != nil {
+		fulfillment = *order.FulfillmentStatus
+	}
+	status := fulfillment
+	if order.CancelledAt != nil {
+		status = "cancelled"
+	}
+
+	normalized := engine.Order{
+		ID:                strconv.FormatInt(order.ID, 10),
+		Number:            order.Name,
+		Status:            status,
+		PaymentStatus:     order.FinancialStatus,
+		FulfillmentStatus: fulfillment,
+		Total:             order.TotalPrice,
+		Currency:          order.Currency,
+		Email:             order.Email,
+		Phone:             order.Phone,
+		StatusURL:         order.OrderStatusURL,
+		CreatedAt:         order.CreatedAt,
+		Items:             make([]engine.OrderItem, 0, len(order.LineItems)),
+	}
+	for _, item := range order.LineItems {
+		normalized.Items = append(normalized.Items, engine.OrderItem{
+			Name:     item.Title,
+			Quantity: item.Quantity,
+			Total:    lineTotal(item.Price, item.Quantity),
+		})
+	}
+	for _, fulfillment := range order.Fulfillments {
+		for i, number := range fulfillment.TrackingNumbers {
+			tracking := engine.OrderTracking{Company: fulfillment.TrackingCompany, Number: number}
+			if i < len(fulfillment.TrackingURLs) {
+				tracking.URL = fulfillment.TrackingURLs[i]
+			}
+			normalized.Tracking = append(normalized.Tracking, tracking)
+		}
+	}
+	return normalized
+}
+
+func (c *ShopifyConnector) url(connection commerce.Connection, path string, query url.Values) string {
+	endpoint := fmt.Sprintf("https://%s/admin/api/%s%s", connection.Host(), shopifyAPIVersion, path)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return endpoint
+}
+
+func (c *ShopifyConnector) authorize(connection commerce.Connection) func(*http.Request) {
+	return func(req *http.Request) {
+		req.Header.Set("X-Shopify-Access-Token", connection.Credentials.AccessToken)
+	}
+}
+
+// lineTotal is a line's unit price times its quantity, the unit price when
+// it cannot be parsed
+func lineTotal(price string, quantity int) string {
+	unit, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		return price
+	}
+	return strconv.FormatFloat(unit*float64(quantity), 'f', 2, 64)
+}
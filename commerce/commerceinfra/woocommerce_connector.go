@@ -0,0 +1,264 @@
+package commerceinfra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/commerce"
+	"github.com/Abraxas-365/relay/engine"
+)
+
+// wooTimeLayout is WooCommerce's date format; *_gmt fields are UTC
+const wooTimeLayout = "2006-01-02T15:04:05"
+
+// wooPhoneSuffix is how many trailing digits a phone search sends, so a
+// number stored without its country code still matches
+const wooPhoneSuffix = 9
+
+// WooCommerceConnector searches orders through the WooCommerce REST API (v3)
+// with a read-only consumer key and secret. The API has no customer filter
+// for guests, so contacts are searched in orders and matched on billing
+// details.
+type WooCommerceConnector struct {
+	api apiClient
+}
+
+var _ commerce.Connector = (*WooCommerceConnector)(nil)
+
+func NewWooCommerceConnector() *WooCommerceConnector {
+	return &WooCommerceConnector{
+		api: newAPIClient(),
+	}
+}
+
+func (c *WooCommerceConnector) Provider() commerce.Provider {
+	return commerce.ProviderWooCommerce
+}
+
+func (c *WooCommerceConnector) Verify(ctx context.Context, connection commerce.Connection) error {
+	return c.api.get(ctx, c.url(connection, "/orders", url.Values{"per_page": {"1"}}), c.authorize(connection), nil)
+}
+
+// ============================================================================
+// Orders
+// ============================================================================
+
+type wooOrder struct {
+	ID             int64   `json:"id"`
+	Number         string  `json:"number"` // The order ID unless a plugin numbers orders
+	Status         string  `json:"status"` // pending, processing, on-hold, completed, cancelled, refunded, failed
+	Currency       string  `json:"currency"`
+	Total          string  `json:"total"`
+	DateCreatedGMT string  `json:"date_created_gmt"`
+	DatePaidGMT    *string `json:"date_paid_gmt"`
+	Billing        struct {
+		Email string `json:"email"`
+		Phone string `json:"phone"`
+	} `json:"billing"`
+	LineItems []struct {
+		Name     string `json:"name"`
+		Quantity int    `json:"quantity"`
+		Total    string `json:"total"`
+	} `json:"line_items"`
+	MetaData []struct {
+		Key   string          `json:"key"`
+		Value json.RawMessage `json:"value"`
+	} `json:"meta_data"`
+}
+
+func (c *WooCommerceConnector) Orders(ctx context.Context, connection commerce.Connection, lookup commerce.Lookup) ([]commerce.FoundOrder, error) {
+	switch lookup.By {
+	case engine.OrderLookupByNumber:
+		return c.byNumber(ctx, connection, commerce.NormalizeOrderNumber(lookup.Value))
+	case engine.OrderLookupByEmail:
+		email := strings.TrimSpace(lookup.Value)
+		return c.search(ctx, connection, email, lookup.Limit, func(order wooOrder) bool {
+			return strings.EqualFold(order.Billing.Email, email)
+		})
+	case engine.OrderLookupByPhone:
+		digits := commerce.PhoneDigits(lookup.Value)
+		term := digits
+		if len(term) > wooPhoneSuffix {
+			term = term[len(term)-wooPhoneSuffix:]
+		}
+		return c.search(ctx, connection, term, lookup.Limit, func(order wooOrder) bool {
+			return samePhone(commerce.PhoneDigits(order.Billing.Phone), digits)
+		})
+	default:
+		return nil, commerce.ErrInvalidLookup().WithDetail("by", string(lookup.By))
+	}
+}
+
+// byNumber reads the order whose ID is the number, then searches when a
+// plugin gave orders their own numbers
+func (c *WooCommerceConnector) byNumber(ctx context.Context, connection commerce.Connection, number string) ([]commerce.FoundOrder, error) {
+	matches := func(order wooOrder) bool {
+		return order.Number == number
+	}
+
+	if _, err := strconv.ParseInt(number, 10, 64); err == nil {
+		var data json.RawMessage
+		err := c.api.get(ctx, c.url(connection, "/orders/"+number, nil), c.authorize(connection), &data)
+		switch {
+		case err == nil:
+			found, err := c.decode(data)
+			if err != nil {
+				return nil, err
+			}
+			if found.order.Number == number {
+				return []commerce.FoundOrder{found.FoundOrder}, nil
+			}
+		case responseStatus(err) != http.StatusNotFound:
+			return nil, err
+		}
+	}
+
+	return c.search(ctx, connection, number, 1, matches)
+}
+
+// search lists the most recent orders matching the term and keeps those
+// matching exactly, as WooCommerce searches every field by substring
+func (c *WooCommerceConnector) search(ctx context.Context, connection commerce.Connection, term string, limit int, matches func(wooOrder) bool) ([]commerce.FoundOrder, error) {
+	if term == "" {
+		return nil, nil
+	}
+
+	query := url.Values{
+		"search":   {term},
+		"per_page": {strconv.Itoa(min(limit*2, 100))}, // Room for loose matches
+		"orderby":  {"date"},
+		"order":    {"desc"},
+	}
+	var page []json.RawMessage
+	if err := c.api.get(ctx, c.url(connection, "/orders", query), c.authorize(connection), &page); err != nil {
+		return nil, err
+	}
+
+	orders := make([]commerce.FoundOrder, 0, limit)
+	for _, data := range page {
+		found, err := c.decode(data)
+		if err != nil {
+			return nil, err
+		}
+		if !matches(found.order) {
+			continue
+		}
+		orders = append(orders, found.FoundOrder)
+		if len(orders) == limit {
+			break
+		}
+	}
+	return orders, nil
+}
+
+type wooFound struct {
+	commerce.FoundOrder
+	order wooOrder
+}
+
+func (c *WooCommerceConnector) decode(data json.RawMessage) (wooFound, error) {
+	var order wooOrder
+	raw, err := decodeOrder(data, &order)
+	if err != nil {
+		return wooFound{}, err
+	}
+	return wooFound{
+		FoundOrder: commerce.FoundOrder{Order: c.normalize(order), Raw: raw},
+		order:      order,
+	}, nil
+}
+
+func (c *WooCommerceConnector) normalize(order wooOrder) engine.Order {
+	payment := "pending"
+	if order.DatePaidGMT != nil && *order.DatePaidGMT != "" {
+		payment = "paid"
+	}
+	if order.Status == "refunded" {
+		payment = "refunded"
+	}
+	fulfillment := "unfulfilled"
+	if order.Status == "completed" {
+		fulfillment = "fulfilled"
+	}
+	createdAt, _ := time.Parse(wooTimeLayout, order.DateCreatedGMT)
+
+	normalized := engine.Order{
+		ID:                strconv.FormatInt(order.ID, 10),
+		Number:            order.Number,
+		Status:            order.Status,
+		PaymentStatus:     payment,
+		FulfillmentStatus: fulfillment,
+		Total:             order.Total,
+		Currency:          order.Currency,
+		Email:             order.Billing.Email,
+		Phone:             order.Billing.Phone,
+		CreatedAt:         createdAt,
+		Items:             make([]engine.OrderItem, 0, len(order.LineItems)),
+	}
+	for _, item := range order.LineItems {
+		normalized.Items = append(normalized.Items, engine.OrderItem{
+			Name:     item.Name,
+			Quantity: item.Quantity,
+			Total:    item.Total,
+		})
+	}
+	normalized.Tracking = wooTracking(order)
+	return normalized
+}
+
+// wooTracking reads the shipments the WooCommerce Shipment Tracking
+// extension stores in the order's metadata
+func wooTracking(order wooOrder) []engine.OrderTracking {
+	for _, meta := range order.MetaData {
+		if meta.Key != "_wc_shipment_tracking_items" {
+			continue
+		}
+		var items []struct {
+			Provider       string `json:"tracking_provider"`
+			CustomProvider string `json:"custom_tracking_provider"`
+			Number         string `json:"tracking_number"`
+			Link           string `json:"custom_tracking_link"`
+		}
+		if err := json.Unmarshal(meta.Value, &items); err != nil {
+			return nil
+		}
+		tracking := make([]engine.OrderTracking, 0, len(items))
+		for _, item := range items {
+			company := item.Provider
+			if company == "" {
+				company = item.CustomProvider
+			}
+			tracking = append(tracking, engine.OrderTracking{Company: company, Number: item.Number, URL: item.Link})
+		}
+		return tracking
+	}
+	return nil
+}
+
+// samePhone compares digits with and without a country code
+func samePhone(a, b string) bool {
+	if len(a) < 7 || len(b) < 7 {
+		return a != "" && a == b
+	}
+	return strings.HasSuffix(a, b) || strings.HasSuffix(b, a)
+}
+
+func (c *WooCommerceConnector) url(connection commerce.Connection, path string, query url.Values) string {
+	endpoint := fmt.Sprintf("https://%s/wp-json/wc/v3%s", connection.Host(), path)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return endpoint
+}
+
+func (c *WooCommerceConnector) authorize(connection commerce.Connection) func(*http.Request) {
+	return func(req *http.Request) {
+		req.SetBasicAuth(connection.Credentials.ConsumerKey, connection.Credentials.ConsumerSecret)
+	}
+}
@@ -0,0 +1,304 @@
+package commercesrv
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/commerce"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/tool"
+)
+
+// CommerceService connects tenants to their store and answers the order
+// lookups of ORDER_LOOKUP nodes and SHOPIFY / WOOCOMMERCE tools
+type CommerceService struct {
+	connectionRepo commerce.ConnectionRepository
+	connectors     map[commerce.Provider]commerce.Connector
+}
+
+var _ engine.OrderLooker = (*CommerceService)(nil)
+
+// NewCommerceService creates the service; only the providers with a
+// connector can be connected
+func NewCommerceService(connectionRepo commerce.ConnectionRepository, connectors ...commerce.Connector) *CommerceService {
+	s := &CommerceService{
+		connectionRepo: connectionRepo,
+		connectors:     make(map[commerce.Provider]commerce.Connector, len(connectors)),
+	}
+	for _, connector := range connectors {
+		s.connectors[connector.Provider()] = connector
+	}
+	return s
+}
+
+// ============================================================================
+// Connections
+// ============================================================================
+
+// Available lists the providers configured on this server
+func (s *CommerceService) Available() []commerce.Provider {
+	providers := make([]commerce.Provider, 0, len(s.connectors))
+	for provider := range s.connectors {
+		providers = append(providers, provider)
+	}
+	slices.Sort(providers)
+	return providers
+}
+
+// ListConnections returns the tenant's connections, without credentials
+func (s *CommerceService) ListConnections(ctx context.Context, tenantID kernel.TenantID) (*commerce.ConnectionsResponse, error) {
+	connections, err := s.connectionRepo.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &commerce.ConnectionsResponse{Connections: connections, Available: s.Available()}, nil
+}
+
+// Connect verifies the credentials against the store and saves the
+// connection, replacing the tenant's previous one to the provider. The
+// previous response mapping is kept unless the request sets one.
+func (s *CommerceService) Connect(ctx context.Context, tenantID kernel.TenantID, provider commerce.Provider, req commerce.ConnectRequest) (*commerce.Connection, error) {
+	connector, err := s.connector(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	domain := commerce.NormalizeDomain(req.Domain)
+	credentials := commerce.Credentials{
+		AccessToken:    strings.TrimSpace(req.AccessToken),
+		ConsumerKey:    strings.TrimSpace(req.ConsumerKey),
+		ConsumerSecret: strings.TrimSpace(req.ConsumerSecret),
+	}
+	switch {
+	case !commerce.ValidDomain(domain):
+		return nil, commerce.ErrInvalidConnection().WithDetail("field", "domain")
+	case provider == commerce.ProviderShopify && credentials.AccessToken == "":
+		return nil, commerce.ErrInvalidConnection().WithDetail("field", "access_token")
+	case provider == commerce.ProviderWooCommerce && credentials.ConsumerKey == "":
+		return nil, commerce.ErrInvalidConnection().WithDetail("field", "consumer_key")
+	case provider == commerce.ProviderWooCommerce && credentials.ConsumerSecret == "":
+		return nil, commerce.ErrInvalidConnection().WithDetail("field", "consumer_secret")
+	}
+	if err := req.ResponseMapping.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	connection := &commerce.Connection{
+		TenantID:        tenantID,
+		Provider:        provider,
+		Domain:          domain,
+		Credentials:     credentials,
+		ResponseMapping: req.ResponseMapping,
+		ConnectedAt:     now,
+		UpdatedAt:       now,
+	}
+	if existing, err := s.connectionRepo.Find(ctx, tenantID, provider); err == nil {
+		connection.ConnectedAt = existing.ConnectedAt
+		if req.ResponseMapping == nil {
+			connection.ResponseMapping = existing.ResponseMapping
+		}
+	}
+
+	if err := connector.Verify(ctx, *connection); err != nil {
+		return nil, err
+	}
+	if err := s.connectionRepo.Save(ctx, *connection); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✅ Tenant %s connected %s store %s", tenantID, provider, domain)
+	return connection, nil
+}
+
+// SaveMapping replaces the response mapping of the tenant's connection to
+// provider; an empty mapping removes it
+func (s *CommerceService) SaveMapping(ctx context.Context, tenantID kernel.TenantID, provider commerce.Provider, req commerce.SaveMappingRequest) (*commerce.Connection, error) {
+	if !provider.IsValid() {
+		return nil, commerce.ErrInvalidProvider().WithDetail("provider", string(provider))
+	}
+	if err := req.ResponseMapping.Validate(); err != nil {
+		return nil, err
+	}
+
+	connection, err := s.connectionRepo.Find(ctx, tenantID, provider)
+	if err != nil {
+		return nil, err
+	}
+	connection.ResponseMapping = req.ResponseMapping
+	connection.UpdatedAt = time.Now()
+
+	if err := s.connectionRepo.Save(ctx, *connection); err != nil {
+		return nil, err
+	}
+	return connection, nil
+}
+
+// Disconnect forgets the tenant's credentials for provider
+func (s *CommerceService) Disconnect(ctx context.Context, tenantID kernel.TenantID, provider commerce.Provider) error {
+	if !provider.IsValid() {
+		return commerce.ErrInvalidProvider().WithDetail("provider", string(provider))
+	}
+	return s.connectionRepo.Delete(ctx, tenantID, provider)
+}
+
+// ============================================================================
+// Orders
+// ============================================================================
+
+// LookupOrders implements engine.OrderLooker. Each order's Fields carries
+// the values of the connection's response mapping.
+func (s *CommerceService) LookupOrders(ctx context.Context, tenantID kernel.TenantID, req engine.OrderLookupRequest) (*engine.OrderLookupResult, error) {
+	if !slices.Contains(engine.OrderLookups, req.By) {
+		return nil, commerce.ErrInvalidLookup().
+			WithDetail("by", string(req.By)).
+			WithDetail("allowed", engine.OrderLookups)
+	}
+	value := strings.TrimSpace(req.Value)
+	if value == "" {
+		return nil, commerce.ErrInvalidLookup().WithDetail("reason", "value is required")
+	}
+	if req.By == engine.OrderLookupByPhone && commerce.PhoneDigits(value) == "" {
+		return nil, commerce.ErrInvalidLookup().WithDetail("reason", "phone has no digits")
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = engine.DefaultOrderLimit
+	}
+	limit = min(limit, engine.MaxOrderLimit)
+
+	connection, err := s.resolveConnection(ctx, tenantID, commerce.Provider(req.Provider))
+	if err != nil {
+		return nil, err
+	}
+	connector, err := s.connector(connection.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := connector.Orders(ctx, *connection, commerce.Lookup{By: req.By, Value: value, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	if len(found) > limit {
+		found = found[:limit]
+	}
+
+	result := &engine.OrderLookupResult{
+		Provider: string(connection.Provider),
+		Orders:   make([]engine.Order, 0, len(found)),
+	}
+	for _, f := range found {
+		order := f.Order
+		order.Fields = connection.ResponseMapping.Apply(f.Raw)
+		result.Orders = append(result.Orders, order)
+	}
+	return result, nil
+}
+
+// ExecuteTool runs a tool of type tool.ToolTypeShopify or
+// tool.ToolTypeWooCommerce against the tenant's store of that provider.
+// input holds order_number, email or phone, or by and value, plus an
+// optional limit.
+func (s *CommerceService) ExecuteTool(ctx context.Context, t *tool.Tool, input map[string]any) (map[string]any, error) {
+	var provider commerce.Provider
+	switch t.Type {
+	case tool.ToolTypeShopify:
+		provider = commerce.ProviderShopify
+	case tool.ToolTypeWooCommerce:
+		provider = commerce.ProviderWooCommerce
+	default:
+		return nil, tool.ErrInvalidToolType().WithDetail("type", string(t.Type))
+	}
+	if !t.IsActive {
+		return nil, tool.ErrToolInactive()
+	}
+
+	by, value := toolLookup(engine.OrderLookupBy(t.Config.OrderLookupBy), input)
+	limit := t.Config.OrderLimit
+	if n, ok := input["limit"].(float64); ok {
+		limit = int(n)
+	}
+
+	result, err := s.LookupOrders(ctx, t.TenantID, engine.OrderLookupRequest{
+		Provider: string(provider),
+		By:       by,
+		Value:    value,
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output := map[string]any{
+		"provider": result.Provider,
+		"found":    len(result.Orders) > 0,
+		"count":    len(result.Orders),
+		"orders":   result.Orders,
+	}
+	if len(result.Orders) > 0 {
+		output["order"] = result.Orders[0]
+	}
+	return output, nil
+}
+
+// toolLookup reads what a tool looks up by: the configured field, the by and
+// value of the input, or the first lookup field the input has
+func toolLookup(configured engine.OrderLookupBy, input map[string]any) (engine.OrderLookupBy, string) {
+	field := func(name string) string {
+		value, _ := input[name].(string)
+		return value
+	}
+
+	if configured != "" {
+		if value := field(string(configured)); value != "" {
+			return configured, value
+		}
+		return configured, field("value")
+	}
+	if by := field("by"); by != "" {
+		return engine.OrderLookupBy(by), field("value")
+	}
+	for _, by := range engine.OrderLookups {
+		if value := field(string(by)); value != "" {
+			return by, value
+		}
+	}
+	return "", ""
+}
+
+// resolveConnection finds the connection to provider, or the tenant's only
+// connection when provider is empty
+func (s *CommerceService) resolveConnection(ctx context.Context, tenantID kernel.TenantID, provider commerce.Provider) (*commerce.Connection, error) {
+	if provider != "" {
+		if !provider.IsValid() {
+			return nil, commerce.ErrInvalidProvider().WithDetail("provider", string(provider))
+		}
+		return s.connectionRepo.Find(ctx, tenantID, provider)
+	}
+
+	connections, err := s.connectionRepo.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	switch len(connections) {
+	case 0:
+		return nil, commerce.ErrConnectionNotFound()
+	case 1:
+		return &connections[0], nil
+	default:
+		return nil, commerce.ErrAmbiguousConnection()
+	}
+}
+
+func (s *CommerceService) connector(provider commerce.Provider) (commerce.Connector, error) {
+	connector, ok := s.connectors[provider]
+	if !ok {
+		return nil, commerce.ErrInvalidProvider().WithDetail("provider", string(provider))
+	}
+	return connector, nil
+}
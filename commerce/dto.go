@@ -0,0 +1,29 @@
+package commerce
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// ConnectRequest connects a store with API credentials
+type ConnectRequest struct {
+	Domain          string          `json:"domain"`                     // acme, acme.myshopify.com or the WooCommerce site host
+	AccessToken     string          `json:"access_token,omitempty"`     // Shopify Admin API token
+	ConsumerKey     string          `json:"consumer_key,omitempty"`     // WooCommerce
+	ConsumerSecret  string          `json:"consumer_secret,omitempty"`  // WooCommerce
+	ResponseMapping ResponseMapping `json:"response_mapping,omitempty"` // Replaces the current mapping when set
+}
+
+// SaveMappingRequest replaces a connection's response mapping
+type SaveMappingRequest struct {
+	ResponseMapping ResponseMapping `json:"response_mapping"`
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+// ConnectionsResponse is what a tenant connected and what it can connect
+type ConnectionsResponse struct {
+	Connections []Connection `json:"connections"`
+	Available   []Provider   `json:"available"`
+}
@@ -0,0 +1,64 @@
+package commerce
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("COMMERCE")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeConnectionNotFound  = ErrRegistry.Register("CONNECTION_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Store is not connected")
+	CodeAmbiguousConnection = ErrRegistry.Register("AMBIGUOUS_CONNECTION", errx.TypeValidation, http.StatusBadRequest, "Several stores are connected; choose a provider")
+	CodeInvalidProvider     = ErrRegistry.Register("INVALID_PROVIDER", errx.TypeValidation, http.StatusBadRequest, "Invalid store provider")
+	CodeInvalidConnection   = ErrRegistry.Register("INVALID_CONNECTION", errx.TypeValidation, http.StatusBadRequest, "Invalid store connection")
+	CodeInvalidMapping      = ErrRegistry.Register("INVALID_MAPPING", errx.TypeValidation, http.StatusBadRequest, "Invalid response mapping")
+	CodeInvalidLookup       = ErrRegistry.Register("INVALID_LOOKUP", errx.TypeValidation, http.StatusBadRequest, "Invalid order lookup")
+	CodeCredentialsRejected = ErrRegistry.Register("CREDENTIALS_REJECTED", errx.TypeAuthorization, http.StatusUnauthorized, "Store rejected the credentials")
+	CodeRequestFailed       = ErrRegistry.Register("REQUEST_FAILED", errx.TypeExternal, http.StatusBadGateway, "Store request failed")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrConnectionNotFound() *errx.Error {
+	return ErrRegistry.New(CodeConnectionNotFound)
+}
+
+func ErrAmbiguousConnection() *errx.Error {
+	return ErrRegistry.New(CodeAmbiguousConnection)
+}
+
+func ErrInvalidProvider() *errx.Error {
+	return ErrRegistry.New(CodeInvalidProvider)
+}
+
+func ErrInvalidConnection() *errx.Error {
+	return ErrRegistry.New(CodeInvalidConnection)
+}
+
+func ErrInvalidMapping() *errx.Error {
+	return ErrRegistry.New(CodeInvalidMapping)
+}
+
+func ErrInvalidLookup() *errx.Error {
+	return ErrRegistry.New(CodeInvalidLookup)
+}
+
+func ErrCredentialsRejected() *errx.Error {
+	return ErrRegistry.New(CodeCredentialsRejected)
+}
+
+func ErrRequestFailed() *errx.Error {
+	return ErrRegistry.New(CodeRequestFailed)
+}
@@ -0,0 +1,43 @@
+package commerce
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// ConnectionRepository persists tenants' store connections
+type ConnectionRepository interface {
+	// Find returns the tenant's connection to provider, ErrConnectionNotFound
+	// when there is none
+	Find(ctx context.Context, tenantID kernel.TenantID, provider Provider) (*Connection, error)
+
+	List(ctx context.Context, tenantID kernel.TenantID) ([]Connection, error)
+
+	// Save creates or replaces the tenant's connection to the provider
+	Save(ctx context.Context, connection Connection) error
+
+	Delete(ctx context.Context, tenantID kernel.TenantID, provider Provider) error
+}
+
+// ============================================================================
+// Connector Interfaces
+// ============================================================================
+
+// Connector searches one store platform's orders through its REST API.
+// Calls fail with ErrCredentialsRejected when the credentials are no longer
+// valid.
+type Connector interface {
+	Provider() Provider
+
+	// Verify checks the connection's credentials against the store
+	Verify(ctx context.Context, connection Connection) error
+
+	// Orders returns the orders matching the lookup, most recent first; none
+	// is not an error
+	Orders(ctx context.Context, connection Connection, lookup Lookup) ([]FoundOrder, error)
+}
@@ -0,0 +1,215 @@
+package contact
+
+import (
+	"maps"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Contacts
+// ============================================================================
+
+// Contact is a person a tenant talks to. ExternalID, Phone and Email
+// identify it when records are imported or synced; their conversations are
+// keyed by the phone number or the channel's sender ID.
+type Contact struct {
+	ID         string            `json:"id"`
+	TenantID   kernel.TenantID   `json:"tenant_id"`
+	ExternalID string            `json:"external_id,omitempty"` // ID in the tenant's own systems, unique per tenant
+	Name       string            `json:"name,omitempty"`
+	Phone      string            `json:"phone,omitempty"` // E.164, e.g. +51999888777
+	Email      string            `json:"email,omitempty"` // Lowercase
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// NewContact creates an empty contact of the tenant
+func NewContact(tenantID kernel.TenantID) *Contact {
+	now := time.Now()
+	return &Contact{
+		ID:         uuid.NewString(),
+		TenantID:   tenantID,
+		Attributes: map[string]string{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// Identity is what an incoming record is matched against existing contacts
+// with, in order: the external ID, else the phone, else the email
+type Identity struct {
+	ExternalID string
+	Phone      string
+	Email      string
+}
+
+// IsEmpty reports whether the identity cannot match any contact
+func (i Identity) IsEmpty() bool {
+	return i.ExternalID == "" && i.Phone == "" && i.Email == ""
+}
+
+// Identity returns the fields the contact is matched by
+func (c *Contact) Identity() Identity {
+	return Identity{ExternalID: c.ExternalID, Phone: c.Phone, Email: c.Email}
+}
+
+// IdentityOf is the identity a channel's conversation ID matches: phone
+// numbers (with or without "+") match the phone, addresses the email, and
+// anything else, like an Instagram ID, the external ID
+func IdentityOf(conversationID string) Identity {
+	id := strings.TrimSpace(conversationID)
+	if strings.Contains(id, "@") {
+		email, err := NormalizeEmail(id)
+		if err == nil {
+			return Identity{Email: email}
+		}
+		return Identity{ExternalID: id}
+	}
+	if phone, err := NormalizePhone("+"+strings.TrimPrefix(id, "+"), ""); err == nil {
+		return Identity{Phone: phone}
+	}
+	return Identity{ExternalID: id}
+}
+
+// ConversationIDs are the IDs the contact's conversations may have on the
+// channels: the phone with and without "+", and the email
+func (c *Contact) ConversationIDs() []string {
+	var ids []string
+	if c.Phone != "" {
+		ids = append(ids, strings.TrimPrefix(c.Phone, "+"), c.Phone)
+	}
+	if c.Email != "" {
+		ids = append(ids, c.Email)
+	}
+	return ids
+}
+
+// Merge copies the non-empty fields of update onto the contact and adds its
+// tags; existing tags are kept. It reports whether anything changed.
+func (c *Contact) Merge(update Contact) bool {
+	changed := false
+	set := func(field *string, value string) {
+		if value != "" && *field != value {
+			*field = value
+			changed = true
+		}
+	}
+
+	set(&c.ExternalID, update.ExternalID)
+	set(&c.Name, update.Name)
+	set(&c.Phone, update.Phone)
+	set(&c.Email, update.Email)
+
+	if c.Attributes == nil {
+		c.Attributes = map[string]string{}
+	}
+	for name, value := range update.Attributes {
+		if value != "" && c.Attributes[name] != value {
+			c.Attributes[name] = value
+			changed = true
+		}
+	}
+
+	for _, tag := range update.Tags {
+		if !slices.Contains(c.Tags, tag) {
+			c.Tags = append(c.Tags, tag)
+			changed = true
+		}
+	}
+
+	if changed {
+		c.UpdatedAt = time.Now()
+	}
+	return changed
+}
+
+// Clone returns a copy that shares no maps or slices with the contact
+func (c Contact) Clone() Contact {
+	c.Attributes = maps.Clone(c.Attributes)
+	c.Tags = slices.Clone(c.Tags)
+	return c
+}
+
+// ============================================================================
+// Normalization
+// ============================================================================
+
+// NormalizePhone turns a phone number into E.164: separators are dropped,
+// a leading 00 becomes +, and numbers without a country code get
+// defaultCountryCode. Without one, numbers must start with + or 00.
+func NormalizePhone(raw, defaultCountryCode string) (string, error) {
+	phone := strings.TrimSpace(raw)
+	if phone == "" {
+		return "", nil
+	}
+
+	international := strings.HasPrefix(phone, "+")
+	var digits strings.Builder
+	for _, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' || r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", ErrInvalidContact().WithDetail("phone", raw).WithDetail("reason", "unexpected character")
+		}
+	}
+
+	number := digits.String()
+	switch {
+	case international:
+	case strings.HasPrefix(number, "00"):
+		number = number[2:]
+	case defaultCountryCode != "":
+		number = strings.TrimPrefix(defaultCountryCode, "+") + strings.TrimLeft(number, "0")
+	default:
+		return "", ErrInvalidContact().WithDetail("phone", raw).WithDetail("reason", "country code is missing")
+	}
+
+	// E.164 numbers have at most 15 digits; shorter than 8 is no full number
+	if len(number) < 8 || len(number) > 15 || number[0] == '0' {
+		return "", ErrInvalidContact().WithDetail("phone", raw).WithDetail("reason", "not a valid phone number")
+	}
+	return "+" + number, nil
+}
+
+// NormalizeEmail trims and lowercases an email address
+func NormalizeEmail(raw string) (string, error) {
+	email := strings.ToLower(strings.TrimSpace(raw))
+	if email == "" {
+		return "", nil
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", ErrInvalidContact().WithDetail("email", raw).WithDetail("reason", "not a valid email address")
+	}
+	return email, nil
+}
+
+// NormalizeTags normalizes tags like conversation tags and drops duplicates
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			continue
+		}
+		name, err := conversation.NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized, nil
+}
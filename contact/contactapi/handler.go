@@ -0,0 +1,198 @@
+package contactapi
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/contact"
+	"github.com/Abraxas-365/relay/contact/contactsrv"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// ContactHandler exposes contacts and their imports
+type ContactHandler struct {
+	contactService *contactsrv.ContactService
+	importService  *contactsrv.ImportService
+}
+
+// NewContactHandler creates a new contact handler
+func NewContactHandler(contactService *contactsrv.ContactService, importService *contactsrv.ImportService) *ContactHandler {
+	return &ContactHandler{
+		contactService: contactService,
+		importService:  importService,
+	}
+}
+
+// ============================================================================
+// Contacts
+// ============================================================================
+
+// List returns the tenant's contacts, filtered by ?search= and ?tag=
+// GET /api/contacts
+func (h *ContactHandler) List(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	req := contact.ListContactsRequest{
+		PaginationOptions: paginationOptions(c),
+		TenantID:          authContext.TenantID,
+		Search:            c.Query("search"),
+		Tag:               c.Query("tag"),
+	}
+
+	contacts, err := h.contactService.List(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(contacts)
+}
+
+// Get returns a contact
+// GET /api/contacts/:id
+func (h *ContactHandler) Get(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	found, err := h.contactService.Get(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(found)
+}
+
+// Delete removes a contact
+// DELETE /api/contacts/:id
+func (h *ContactHandler) Delete(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := h.contactService.Delete(c.Context(), c.Params("id"), authContext.TenantID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ============================================================================
+// Imports
+// ============================================================================
+
+// Import starts importing an uploaded CSV or XLSX file. The multipart form
+// carries the "file" and its "mapping" as JSON (contact.ImportRequest).
+// POST /api/contacts/import
+func (h *ContactHandler) Import(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return contact.ErrInvalidImport().WithDetail("file", "is required")
+	}
+	if header.Size > contact.MaxImportBytes {
+		return contact.ErrInvalidImport().WithDetail("file", "too large").
+			WithDetail("max_bytes", contact.MaxImportBytes)
+	}
+
+	var req contact.ImportRequest
+	if err := json.Unmarshal([]byte(c.FormValue("mapping")), &req); err != nil {
+		return contact.ErrInvalidImport().WithDetail("mapping", err.Error())
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return contact.ErrInvalidImport().WithDetail("file", err.Error())
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, contact.MaxImportBytes+1))
+	if err != nil {
+		return contact.ErrInvalidImport().WithDetail("file", err.Error())
+	}
+
+	imp, err := h.importService.RequestImport(c.Context(), authContext.TenantID, authContext.UserID, header.Filename, data, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(imp)
+}
+
+// ListImports returns the tenant's imports, filtered by ?status=
+// GET /api/contacts/imports
+func (h *ContactHandler) ListImports(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	req := contact.ListImportsRequest{
+		PaginationOptions: paginationOptions(c),
+		TenantID:          authContext.TenantID,
+		Status:            contact.ImportStatus(c.Query("status")),
+	}
+	switch req.Status {
+	case "", contact.ImportPending, contact.ImportRunning, contact.ImportCompleted, contact.ImportFailed:
+	default:
+		return contact.ErrInvalidImport().WithDetail("status", "must be PENDING, RUNNING, COMPLETED or FAILED")
+	}
+
+	imports, err := h.importService.ListImports(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(imports)
+}
+
+// GetImport returns an import's progress and row errors
+// GET /api/contacts/imports/:id
+func (h *ContactHandler) GetImport(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	imp, err := h.importService.GetImport(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(imp)
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func paginationOptions(c *fiber.Ctx) storex.PaginationOptions {
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", defaultPageSize)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return storex.PaginationOptions{Page: page, PageSize: pageSize}
+}
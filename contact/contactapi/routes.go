@@ -0,0 +1,34 @@
+package contactapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ContactRoutes handles contact route setup
+type ContactRoutes struct {
+	handler        *ContactHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewContactRoutes creates a new contact routes instance
+func NewContactRoutes(handler *ContactHandler, authMiddleware *auth.AuthMiddleware) *ContactRoutes {
+	return &ContactRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers contact routes on an authenticated router.
+// Importing and deleting contacts requires an admin; reading them does not.
+func (r *ContactRoutes) RegisterRoutes(router fiber.Router) {
+	contacts := router.Group("/contacts")
+
+	contacts.Post("/import", r.authMiddleware.RequireAdmin(), r.handler.Import)
+	contacts.Get("/imports", r.authMiddleware.RequireAdmin(), r.handler.ListImports)
+	contacts.Get("/imports/:id", r.authMiddleware.RequireAdmin(), r.handler.GetImport)
+
+	contacts.Get("/", r.handler.List)
+	contacts.Get("/:id", r.handler.Get)
+	contacts.Delete("/:id", r.authMiddleware.RequireAdmin(), r.handler.Delete)
+}
@@ -0,0 +1,301 @@
+package contactinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/contact"
+	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PostgresContactRepository struct {
+	db     *sqlx.DB
+	router *database.Router // nil = every tenant's contacts are in db
+}
+
+var _ contact.ContactRepository = (*PostgresContactRepository)(nil)
+
+func NewPostgresContactRepository(db *sqlx.DB) *PostgresContactRepository {
+	return &PostgresContactRepository{db: db}
+}
+
+// SetRouter keeps each tenant's contacts in the database of its data region.
+// Segment memberships stay in db.
+func (r *PostgresContactRepository) SetRouter(router *database.Router) {
+	r.router = router
+}
+
+// dbContact is an intermediate struct for database operations
+type dbContact struct {
+	ID         string          `db:"id"`
+	TenantID   string          `db:"tenant_id"`
+	ExternalID string          `db:"external_id"`
+	Name       string          `db:"name"`
+	Phone      string          `db:"phone"`
+	Email      string          `db:"email"`
+	Attributes json.RawMessage `db:"attributes"`
+	Tags       pq.StringArray  `db:"tags"`
+	CreatedAt  time.Time       `db:"created_at"`
+	UpdatedAt  time.Time       `db:"updated_at"`
+}
+
+const contactColumns = `
+	id, tenant_id, external_id, name, phone, email, attributes, tags,
+	created_at, updated_at`
+
+func (r *PostgresContactRepository) Save(ctx context.Context, c contact.Contact) error {
+	attributes := c.Attributes
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	attributesJSON, err := json.Marshal(attributes)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal contact attributes", errx.TypeInternal)
+	}
+
+	tags := c.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	query := `
+		INSERT INTO contacts (` + contactColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE SET
+			external_id = EXCLUDED.external_id,
+			name = EXCLUDED.name,
+			phone = EXCLUDED.phone,
+			email = EXCLUDED.email,
+			attributes = EXCLUDED.attributes,
+			tags = EXCLUDED.tags`
+
+	db, err := r.conn(ctx, c.TenantID)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, query,
+		c.ID, c.TenantID.String(), c.ExternalID, c.Name, c.Phone, c.Email, attributesJSON,
+		pq.Array(tags), c.CreatedAt, c.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return contact.ErrExternalIDTaken().WithDetail("external_id", c.ExternalID)
+		}
+		return errx.Wrap(err, "failed to save contact", errx.TypeInternal).
+			WithDetail("contact_id", c.ID)
+	}
+
+	return nil
+}
+
+func (r *PostgresContactRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*contact.Contact, error) {
+	query := `SELECT ` + contactColumns + ` FROM contacts WHERE id = $1 AND tenant_id = $2`
+
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var row dbContact
+	if err := db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, contact.ErrContactNotFound().WithDetail("contact_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find contact", errx.TypeInternal).
+			WithDetail("contact_id", id)
+	}
+
+	return toDomainContact(&row)
+}
+
+func (r *PostgresContactRepository) FindByIdentity(ctx context.Context, tenantID kernel.TenantID, identity contact.Identity) (*contact.Contact, error) {
+	var column, value string
+	switch {
+	case identity.ExternalID != "":
+		column, value = "external_id", identity.ExternalID
+	case identity.Phone != "":
+		column, value = "phone", identity.Phone
+	case identity.Email != "":
+		column, value = "email", identity.Email
+	default:
+		return nil, contact.ErrContactNotFound()
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM contacts WHERE tenant_id = $1 AND %s = $2
+		ORDER BY created_at ASC
+		LIMIT 1`, contactColumns, column)
+
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var row dbContact
+	if err := db.GetContext(ctx, &row, query, tenantID.String(), value); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, contact.ErrContactNotFound().WithDetail(column, value)
+		}
+		return nil, errx.Wrap(err, "failed to find contact", errx.TypeInternal).
+			WithDetail(column, value)
+	}
+
+	return toDomainContact(&row)
+}
+
+func (r *PostgresContactRepository) List(ctx context.Context, req contact.ListContactsRequest) (contact.ContactListResponse, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.Search != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			"(name ILIKE $%d OR phone ILIKE $%d OR email ILIKE $%d OR external_id ILIKE $%d)",
+			argPos, argPos, argPos, argPos))
+		args = append(args, "%"+req.Search+"%")
+		argPos++
+	}
+	if req.Tag != "" {
+		conditions = append(conditions, fmt.Sprintf("$%d = ANY(tags)", argPos))
+		args = append(args, req.Tag)
+		argPos++
+	}
+	where := strings.Join(conditions, " AND ")
+
+	db, err := r.conn(ctx, req.TenantID)
+	if err != nil {
+		return contact.ContactListResponse{}, err
+	}
+
+	var total int
+	if err := db.GetContext(ctx, &total, `SELECT COUNT(*) FROM contacts WHERE `+where, args...); err != nil {
+		return contact.ContactListResponse{}, errx.Wrap(err, "failed to count contacts", errx.TypeInternal)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM contacts WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d`, contactColumns, where, argPos, argPos+1)
+	args = append(args, req.PageSize, req.GetOffset())
+
+	var rows []dbContact
+	if err := db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return contact.ContactListResponse{}, errx.Wrap(err, "failed to list contacts", errx.TypeInternal)
+	}
+
+	contacts := make([]contact.Contact, 0, len(rows))
+	for i := range rows {
+		c, err := toDomainContact(&rows[i])
+		if err != nil {
+			return contact.ContactListResponse{}, err
+		}
+		contacts = append(contacts, *c)
+	}
+
+	return storex.NewPaginated(contacts, req.Page, req.PageSize, total), nil
+}
+
+func (r *PostgresContactRepository) ListAfter(ctx context.Context, tenantID kernel.TenantID, afterID string, limit int) ([]contact.Contact, error) {
+	query := `SELECT ` + contactColumns + ` FROM contacts
+		WHERE tenant_id = $1 AND id > $2
+		ORDER BY id ASC
+		LIMIT $3`
+
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []dbContact
+	if err := db.SelectContext(ctx, &rows, query, tenantID.String(), afterID, limit); err != nil {
+		return nil, errx.Wrap(err, "failed to list contacts", errx.TypeInternal)
+	}
+
+	contacts := make([]contact.Contact, 0, len(rows))
+	for i := range rows {
+		c, err := toDomainContact(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, *c)
+	}
+
+	return contacts, nil
+}
+
+func (r *PostgresContactRepository) Delete(ctx context.Context, id string, tenantID kernel.TenantID) error {
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.ExecContext(ctx, `DELETE FROM contacts WHERE id = $1 AND tenant_id = $2`, id, tenantID.String())
+	if err != nil {
+		return errx.Wrap(err, "failed to delete contact", errx.TypeInternal).
+			WithDetail("contact_id", id)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+	if rows == 0 {
+		return contact.ErrContactNotFound().WithDetail("contact_id", id)
+	}
+
+	// Memberships stay in the main database, where no foreign key to the
+	// contact cascades; a failure here is fixed by the segment's next rebuild
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM segment_members WHERE contact_id = $1 AND tenant_id = $2`, id, tenantID.String()); err != nil {
+		return errx.Wrap(err, "failed to delete contact segment memberships", errx.TypeInternal).
+			WithDetail("contact_id", id)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// conn is the database holding the tenant's contacts
+func (r *PostgresContactRepository) conn(ctx context.Context, tenantID kernel.TenantID) (*sqlx.DB, error) {
+	if r.router == nil {
+		return r.db, nil
+	}
+	db, err := r.router.For(ctx, tenantID)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to resolve tenant database", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+	return db, nil
+}
+
+func toDomainContact(row *dbContact) (*contact.Contact, error) {
+	c := &contact.Contact{
+		ID:         row.ID,
+		TenantID:   kernel.TenantID(row.TenantID),
+		ExternalID: row.ExternalID,
+		Name:       row.Name,
+		Phone:      row.Phone,
+		Email:      row.Email,
+		Attributes: map[string]string{},
+		Tags:       []string(row.Tags),
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+	}
+	if len(row.Attributes) > 0 {
+		if err := json.Unmarshal(row.Attributes, &c.Attributes); err != nil {
+			return nil, errx.Wrap(err, "failed to unmarshal contact attributes", errx.TypeInternal).
+				WithDetail("contact_id", row.ID)
+		}
+	}
+	return c, nil
+}
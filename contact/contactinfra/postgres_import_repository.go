@@ -0,0 +1,229 @@
+package contactinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/contact"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresImportRepository struct {
+	db *sqlx.DB
+}
+
+var _ contact.ImportRepository = (*PostgresImportRepository)(nil)
+
+func NewPostgresImportRepository(db *sqlx.DB) *PostgresImportRepository {
+	return &PostgresImportRepository{db: db}
+}
+
+// dbImport is an intermediate struct for database operations
+type dbImport struct {
+	ID             string          `db:"id"`
+	TenantID       string          `db:"tenant_id"`
+	RequestedBy    string          `db:"requested_by"`
+	Filename       string          `db:"filename"`
+	Format         string          `db:"format"`
+	Mapping        json.RawMessage `db:"mapping"`
+	Options        json.RawMessage `db:"options"`
+	CohortTag      string          `db:"cohort_tag"`
+	Status         string          `db:"status"`
+	TotalRows      int             `db:"total_rows"`
+	ProcessedRows  int             `db:"processed_rows"`
+	CreatedCount   int             `db:"created_count"`
+	UpdatedCount   int             `db:"updated_count"`
+	UnchangedCount int             `db:"unchanged_count"`
+	FailedCount    int             `db:"failed_count"`
+	RowErrors      json.RawMessage `db:"row_errors"`
+	StorageKey     string          `db:"storage_key"`
+	Error          string          `db:"error"`
+	CreatedAt      time.Time       `db:"created_at"`
+	StartedAt      sql.NullTime    `db:"started_at"`
+	CompletedAt    sql.NullTime    `db:"completed_at"`
+}
+
+const importColumns = `
+	id, tenant_id, requested_by, filename, format, mapping, options, cohort_tag,
+	status, total_rows, processed_rows, created_count, updated_count,
+	unchanged_count, failed_count, row_errors, storage_key, error,
+	created_at, started_at, completed_at`
+
+func (r *PostgresImportRepository) Save(ctx context.Context, imp contact.Import) error {
+	mappingJSON, err := json.Marshal(imp.Mapping)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal import mapping", errx.TypeInternal)
+	}
+	optionsJSON, err := json.Marshal(imp.Options)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal import options", errx.TypeInternal)
+	}
+	rowErrors := imp.Errors
+	if rowErrors == nil {
+		rowErrors = []contact.RowError{}
+	}
+	rowErrorsJSON, err := json.Marshal(rowErrors)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal import row errors", errx.TypeInternal)
+	}
+
+	var startedAt, completedAt sql.NullTime
+	if imp.StartedAt != nil {
+		startedAt = sql.NullTime{Time: *imp.StartedAt, Valid: true}
+	}
+	if imp.CompletedAt != nil {
+		completedAt = sql.NullTime{Time: *imp.CompletedAt, Valid: true}
+	}
+
+	query := `
+		INSERT INTO contact_imports (` + importColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			total_rows = EXCLUDED.total_rows,
+			processed_rows = EXCLUDED.processed_rows,
+			created_count = EXCLUDED.created_count,
+			updated_count = EXCLUDED.updated_count,
+			unchanged_count = EXCLUDED.unchanged_count,
+			failed_count = EXCLUDED.failed_count,
+			row_errors = EXCLUDED.row_errors,
+			storage_key = EXCLUDED.storage_key,
+			error = EXCLUDED.error,
+			started_at = EXCLUDED.started_at,
+			completed_at = EXCLUDED.completed_at`
+
+	_, err = r.db.ExecContext(ctx, query,
+		imp.ID, imp.TenantID.String(), imp.RequestedBy.String(), imp.Filename, string(imp.Format),
+		mappingJSON, optionsJSON, imp.CohortTag, string(imp.Status),
+		imp.Progress.TotalRows, imp.Progress.ProcessedRows, imp.Progress.Created, imp.Progress.Updated,
+		imp.Progress.Unchanged, imp.Progress.Failed, rowErrorsJSON, imp.StorageKey, imp.Error,
+		imp.CreatedAt, startedAt, completedAt,
+	)
+	if err != nil {
+		return errx.Wrap(err, "failed to save contact import", errx.TypeInternal).
+			WithDetail("import_id", imp.ID)
+	}
+
+	return nil
+}
+
+func (r *PostgresImportRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*contact.Import, error) {
+	query := `SELECT ` + importColumns + ` FROM contact_imports WHERE id = $1 AND tenant_id = $2`
+
+	var row dbImport
+	if err := r.db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, contact.ErrImportNotFound().WithDetail("import_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find contact import", errx.TypeInternal).
+			WithDetail("import_id", id)
+	}
+
+	return toDomainImport(&row)
+}
+
+func (r *PostgresImportRepository) List(ctx context.Context, req contact.ListImportsRequest) (contact.ImportListResponse, error) {
+	where := "tenant_id = $1"
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.Status != "" {
+		where += fmt.Sprintf(" AND status = $%d", argPos)
+		args = append(args, string(req.Status))
+		argPos++
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM contact_imports WHERE `+where, args...); err != nil {
+		return contact.ImportListResponse{}, errx.Wrap(err, "failed to count contact imports", errx.TypeInternal)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM contact_imports WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d`, importColumns, where, argPos, argPos+1)
+	args = append(args, req.PageSize, req.GetOffset())
+
+	var rows []dbImport
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return contact.ImportListResponse{}, errx.Wrap(err, "failed to list contact imports", errx.TypeInternal)
+	}
+
+	imports := make([]contact.Import, 0, len(rows))
+	for i := range rows {
+		imp, err := toDomainImport(&rows[i])
+		if err != nil {
+			return contact.ImportListResponse{}, err
+		}
+		imports = append(imports, *imp)
+	}
+
+	return storex.NewPaginated(imports, req.Page, req.PageSize, total), nil
+}
+
+func (r *PostgresImportRepository) CountActive(ctx context.Context, tenantID kernel.TenantID) (int, error) {
+	query := `SELECT COUNT(*) FROM contact_imports
+		WHERE tenant_id = $1 AND status IN ('PENDING', 'RUNNING')`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, tenantID.String()); err != nil {
+		return 0, errx.Wrap(err, "failed to count active contact imports", errx.TypeInternal)
+	}
+
+	return count, nil
+}
+
+func toDomainImport(row *dbImport) (*contact.Import, error) {
+	imp := &contact.Import{
+		ID:          row.ID,
+		TenantID:    kernel.TenantID(row.TenantID),
+		RequestedBy: kernel.UserID(row.RequestedBy),
+		Filename:    row.Filename,
+		Format:      contact.FileFormat(row.Format),
+		CohortTag:   row.CohortTag,
+		Status:      contact.ImportStatus(row.Status),
+		StorageKey:  row.StorageKey,
+		Error:       row.Error,
+		CreatedAt:   row.CreatedAt,
+	}
+	imp.Progress = contact.ImportProgress{
+		TotalRows:     row.TotalRows,
+		ProcessedRows: row.ProcessedRows,
+		Created:       row.CreatedCount,
+		Updated:       row.UpdatedCount,
+		Unchanged:     row.UnchangedCount,
+		Failed:        row.FailedCount,
+	}
+	imp.RefreshPercent()
+	if row.StartedAt.Valid {
+		imp.StartedAt = &row.StartedAt.Time
+	}
+	if row.CompletedAt.Valid {
+		imp.CompletedAt = &row.CompletedAt.Time
+	}
+
+	if len(row.Mapping) > 0 {
+		if err := json.Unmarshal(row.Mapping, &imp.Mapping); err != nil {
+			return nil, errx.Wrap(err, "failed to unmarshal import mapping", errx.TypeInternal).
+				WithDetail("import_id", row.ID)
+		}
+	}
+	if len(row.Options) > 0 {
+		if err := json.Unmarshal(row.Options, &imp.Options); err != nil {
+			return nil, errx.Wrap(err, "failed to unmarshal import options", errx.TypeInternal).
+				WithDetail("import_id", row.ID)
+		}
+	}
+	if len(row.RowErrors) > 0 {
+		if err := json.Unmarshal(row.RowErrors, &imp.Errors); err != nil {
+			return nil, errx.Wrap(err, "failed to unmarshal import row errors", errx.TypeInternal).
+				WithDetail("import_id", row.ID)
+		}
+	}
+	return imp, nil
+}
@@ -0,0 +1,427 @@
+package contactsrv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/attachment"
+	"github.com/Abraxas-365/relay/contact"
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ImportJobKind is the background job that imports one uploaded file
+const ImportJobKind = "contact.import"
+
+const (
+	// ImportTimeout bounds a single import job
+	ImportTimeout = 30 * time.Minute
+
+	// importAttempts is how many times an import is tried; a retry starts
+	// the file over and finds the rows imported before unchanged
+	importAttempts = 2
+
+	// importProgressRows is how many rows are imported between progress
+	// saves
+	importProgressRows = 200
+
+	// maxActiveImports bounds the pending and running imports of a tenant
+	maxActiveImports = 3
+)
+
+// ImportService loads contacts from uploaded CSV and XLSX files through
+// the job queue. Rows are matched to existing contacts by external ID, else
+// phone, else email, and merged into them.
+type ImportService struct {
+	importRepo  contact.ImportRepository
+	contactRepo contact.ContactRepository
+	storage     attachment.Storage // nil = imports are unavailable
+	queue       jobs.Queue
+	observers   []contact.ImportObserver
+}
+
+func NewImportService(
+	importRepo contact.ImportRepository,
+	contactRepo contact.ContactRepository,
+	storage attachment.Storage,
+	queue jobs.Queue,
+) *ImportService {
+	return &ImportService{
+		importRepo:  importRepo,
+		contactRepo: contactRepo,
+		storage:     storage,
+		queue:       queue,
+	}
+}
+
+// AddObserver registers observers told of every completed import
+func (s *ImportService) AddObserver(observers ...contact.ImportObserver) {
+	s.observers = append(s.observers, observers...)
+}
+
+// ============================================================================
+// Imports
+// ============================================================================
+
+// RequestImport validates the file against the mapping, stores it, records
+// a pending import and queues it
+func (s *ImportService) RequestImport(ctx context.Context, tenantID kernel.TenantID, requestedBy kernel.UserID, filename string, data []byte, req contact.ImportRequest) (*contact.Import, error) {
+	if s.storage == nil {
+		return nil, contact.ErrImportsNotConfigured()
+	}
+
+	format, ok := contact.FormatOf(filename)
+	if !ok {
+		return nil, contact.ErrInvalidImport().WithDetail("file", "must be a .csv or .xlsx file")
+	}
+	if len(data) > contact.MaxImportBytes {
+		return nil, contact.ErrInvalidImport().WithDetail("file", "too large").
+			WithDetail("max_bytes", contact.MaxImportBytes)
+	}
+	if err := req.Mapping.Validate(); err != nil {
+		return nil, err
+	}
+
+	countryCode, err := normalizeCountryCode(req.DefaultCountryCode)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := contact.NormalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the file now so a wrong mapping fails the request, not the job
+	rows, err := readRows(format, data)
+	if err != nil {
+		return nil, err
+	}
+	if missing := missingColumns(req.Mapping, headerIndex(rows[0])); len(missing) > 0 {
+		return nil, contact.ErrInvalidImport().WithDetail("missing_columns", missing)
+	}
+	if len(dataRows(rows)) == 0 {
+		return nil, contact.ErrInvalidImport().WithDetail("file", "no rows below the header")
+	}
+
+	active, err := s.importRepo.CountActive(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if active >= maxActiveImports {
+		return nil, contact.ErrTooManyImports().WithDetail("max_active", maxActiveImports)
+	}
+
+	imp := contact.NewImport(tenantID, requestedBy, filename, format, req.Mapping, contact.ImportOptions{
+		DefaultCountryCode: countryCode,
+		Tags:               tags,
+	})
+	if err := s.storage.Put(ctx, imp.StorageKey, bytes.NewReader(data), int64(len(data)), format.ContentType()); err != nil {
+		return nil, fmt.Errorf("failed to store import file: %w", err)
+	}
+	if err := s.importRepo.Save(ctx, *imp); err != nil {
+		s.deleteFile(imp)
+		return nil, err
+	}
+
+	// The job runner imports the rows, outliving the HTTP request
+	if _, err := s.queue.Enqueue(ctx, jobs.EnqueueRequest{
+		Kind:        ImportJobKind,
+		TenantID:    &tenantID,
+		Payload:     importPayload{ImportID: imp.ID},
+		MaxAttempts: importAttempts,
+	}); err != nil {
+		s.finish(imp, err)
+		s.deleteFile(imp)
+		return nil, err
+	}
+
+	return imp, nil
+}
+
+func (s *ImportService) GetImport(ctx context.Context, id string, tenantID kernel.TenantID) (*contact.Import, error) {
+	return s.importRepo.FindByID(ctx, id, tenantID)
+}
+
+// ListImports returns the tenant's imports, newest first
+func (s *ImportService) ListImports(ctx context.Context, req contact.ListImportsRequest) (contact.ImportListResponse, error) {
+	return s.importRepo.List(ctx, req)
+}
+
+// RunImportJob is the handler of ImportJobKind. An import found running
+// was interrupted by a restart and starts over; a completed one is left
+// alone. The file is deleted once the import completes or runs out of
+// attempts.
+func (s *ImportService) RunImportJob(ctx context.Context, job jobs.Job) error {
+	var payload importPayload
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+	if job.TenantID == nil {
+		return jobs.ErrInvalidJob().WithDetail("job_id", job.ID).WithDetail("reason", "tenant_id is required")
+	}
+
+	imp, err := s.importRepo.FindByID(ctx, payload.ImportID, *job.TenantID)
+	if err != nil {
+		return err
+	}
+	if imp.Status == contact.ImportCompleted {
+		return nil
+	}
+	if s.storage == nil {
+		err := contact.ErrImportsNotConfigured()
+		s.finish(imp, err)
+		return err
+	}
+
+	err = s.run(ctx, imp)
+	if err == nil || job.Attempts >= job.MaxAttempts {
+		s.deleteFile(imp)
+	}
+	return err
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// importPayload is the payload of an ImportJobKind job
+type importPayload struct {
+	ImportID string `json:"import_id"`
+}
+
+// dataRow is a row below the header with its spreadsheet row number
+type dataRow struct {
+	number int
+	cells  []string
+}
+
+// run imports every row; the error is returned too so the job queue can
+// retry it. Rows that cannot be imported are recorded, not errors.
+func (s *ImportService) run(ctx context.Context, imp *contact.Import) error {
+	rows, err := s.readFile(ctx, imp)
+	if err != nil {
+		s.finish(imp, err)
+		return err
+	}
+	columns := headerIndex(rows[0])
+	if missing := missingColumns(imp.Mapping, columns); len(missing) > 0 {
+		err := contact.ErrInvalidImport().WithDetail("missing_columns", missing)
+		s.finish(imp, err)
+		return err
+	}
+	records := dataRows(rows)
+
+	imp.Start(len(records))
+	if err := s.importRepo.Save(ctx, *imp); err != nil {
+		return err
+	}
+	log.Printf("📇 Importing %d contacts of tenant %s from %s (%s)", len(records), imp.TenantID, imp.Filename, imp.ID)
+
+	for i, record := range records {
+		if err := ctx.Err(); err != nil {
+			s.finish(imp, err)
+			return err
+		}
+		if err := s.importRow(ctx, imp, columns, record); err != nil {
+			s.finish(imp, err)
+			return err
+		}
+
+		if (i+1)%importProgressRows == 0 {
+			if err := s.importRepo.Save(ctx, *imp); err != nil {
+				log.Printf("⚠️  Failed to save progress of contact import %s: %v", imp.ID, err)
+			}
+		}
+	}
+
+	imp.Complete()
+	s.finish(imp, nil)
+
+	for _, observer := range s.observers {
+		observer.ContactsImported(ctx, *imp)
+	}
+	return nil
+}
+
+func (s *ImportService) readFile(ctx context.Context, imp *contact.Import) ([][]string, error) {
+	file, err := s.storage.Open(ctx, imp.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, contact.MaxImportBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+	return readRows(imp.Format, data)
+}
+
+// importRow maps a row onto a contact and merges it into the contact it
+// matches, or creates one. Only storage failures are returned.
+func (s *ImportService) importRow(ctx context.Context, imp *contact.Import, columns map[string]int, row dataRow) error {
+	mapping := imp.Mapping
+	cell := func(column string) string {
+		index, ok := columns[strings.ToLower(column)]
+		if column == "" || !ok || index >= len(row.cells) {
+			return ""
+		}
+		return strings.TrimSpace(row.cells[index])
+	}
+
+	update := contact.Contact{
+		ExternalID: cell(mapping.ExternalID),
+		Name:       cell(mapping.Name),
+		Attributes: make(map[string]string, len(mapping.Attributes)),
+	}
+	for name, column := range mapping.Attributes {
+		update.Attributes[name] = cell(column)
+	}
+
+	var err error
+	if update.Phone, err = contact.NormalizePhone(cell(mapping.Phone), imp.Options.DefaultCountryCode); err != nil {
+		imp.RecordFailed(row.number, mapping.Phone, rowReason(err))
+		return nil
+	}
+	if update.Email, err = contact.NormalizeEmail(cell(mapping.Email)); err != nil {
+		imp.RecordFailed(row.number, mapping.Email, rowReason(err))
+		return nil
+	}
+	if update.Tags, err = contact.NormalizeTags(strings.Split(cell(mapping.Tags), ",")); err != nil {
+		imp.RecordFailed(row.number, mapping.Tags, rowReason(err))
+		return nil
+	}
+	update.Tags = append(update.Tags, imp.Options.Tags...)
+	update.Tags = append(update.Tags, imp.CohortTag)
+
+	if update.Identity().IsEmpty() {
+		imp.RecordFailed(row.number, "", "no external ID, phone or email")
+		return nil
+	}
+
+	target, err := s.contactRepo.FindByIdentity(ctx, imp.TenantID, update.Identity())
+	created := false
+	switch {
+	case err == nil:
+		if !target.Merge(update) {
+			imp.RecordUnchanged()
+			return nil
+		}
+	case errx.IsCode(err, contact.CodeContactNotFound):
+		target = contact.NewContact(imp.TenantID)
+		target.Merge(update)
+		created = true
+	default:
+		return err
+	}
+
+	if err := s.contactRepo.Save(ctx, *target); err != nil {
+		// Only reachable when another import or sync took the ID meanwhile
+		if errx.IsCode(err, contact.CodeExternalIDTaken) {
+			imp.RecordFailed(row.number, mapping.ExternalID, "another contact has this external ID")
+			return nil
+		}
+		return err
+	}
+
+	if created {
+		imp.RecordCreated()
+	} else {
+		imp.RecordUpdated()
+	}
+	return nil
+}
+
+// finish records the outcome; the job's own context may be what expired
+func (s *ImportService) finish(imp *contact.Import, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err != nil {
+		log.Printf("⚠️  Contact import %s of tenant %s failed: %v", imp.ID, imp.TenantID, err)
+		imp.Fail(err)
+	} else {
+		log.Printf("✅ Contact import %s of tenant %s completed (%d created, %d updated, %d unchanged, %d failed)",
+			imp.ID, imp.TenantID, imp.Progress.Created, imp.Progress.Updated, imp.Progress.Unchanged, imp.Progress.Failed)
+	}
+
+	if err := s.importRepo.Save(ctx, *imp); err != nil {
+		log.Printf("⚠️  Failed to save contact import %s: %v", imp.ID, err)
+	}
+}
+
+func (s *ImportService) deleteFile(imp *contact.Import) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.storage.Delete(ctx, imp.StorageKey); err != nil {
+		log.Printf("⚠️  Failed to delete file of contact import %s: %v", imp.ID, err)
+	}
+}
+
+// headerIndex maps each header, lowercased, to its column; the first of
+// duplicate headers wins
+func headerIndex(header []string) map[string]int {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(name)
+		if _, ok := index[key]; !ok && key != "" {
+			index[key] = i
+		}
+	}
+	return index
+}
+
+// missingColumns lists the mapped columns the header lacks
+func missingColumns(mapping contact.Mapping, columns map[string]int) []string {
+	var missing []string
+	for _, column := range mapping.Columns() {
+		if _, ok := columns[strings.ToLower(strings.TrimSpace(column))]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	return missing
+}
+
+// dataRows returns the rows below the header, skipping blank ones
+func dataRows(rows [][]string) []dataRow {
+	records := make([]dataRow, 0, len(rows))
+	for i, cells := range rows[1:] {
+		if strings.TrimSpace(strings.Join(cells, "")) == "" {
+			continue
+		}
+		records = append(records, dataRow{number: i + 2, cells: cells})
+	}
+	return records
+}
+
+// normalizeCountryCode accepts a calling code with or without "+"
+func normalizeCountryCode(code string) (string, error) {
+	code = strings.TrimPrefix(strings.TrimSpace(code), "+")
+	if code == "" {
+		return "", nil
+	}
+	if len(code) > 3 || strings.Trim(code, "0123456789") != "" || code[0] == '0' {
+		return "", contact.ErrInvalidImport().WithDetail("default_country_code", "must be a calling code such as 51")
+	}
+	return code, nil
+}
+
+// rowReason is the short reason of a row error
+func rowReason(err error) string {
+	var e *errx.Error
+	if errors.As(err, &e) {
+		if reason, ok := e.Details["reason"].(string); ok {
+			return reason
+		}
+		return e.Message
+	}
+	return err.Error()
+}
@@ -0,0 +1,295 @@
+package contactsrv
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Abraxas-365/relay/contact"
+)
+
+// readRows reads every row of an uploaded file; the first one is the
+// header. Files are small enough (contact.MaxImportBytes) to be read whole.
+func readRows(format contact.FileFormat, data []byte) ([][]string, error) {
+	var rows [][]string
+	var err error
+	if format == contact.FormatXLSX {
+		rows, err = readXLSX(data)
+	} else {
+		rows, err = readCSV(data)
+	}
+	if err != nil {
+		return nil, contact.ErrInvalidImport().WithDetail("file", err.Error())
+	}
+	if len(rows) == 0 {
+		return nil, contact.ErrInvalidImport().WithDetail("file", "the file is empty")
+	}
+
+	header := rows[0]
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	return rows, nil
+}
+
+// ============================================================================
+// CSV
+// ============================================================================
+
+func readCSV(data []byte) ([][]string, error) {
+	// Spreadsheet programs often start UTF-8 files with a byte order mark
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var rows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, record)
+	}
+}
+
+// ============================================================================
+// XLSX
+// ============================================================================
+
+// An XLSX file is a zip of XML parts: the workbook lists the sheets, its
+// relationships point at their parts, and text cells index the shared
+// strings. Only cell values are read; formulas keep their cached value.
+//
+// Row numbers and cell references come from the file, so they are bounded
+// before they size anything: a small upload must not allocate more than the
+// limits below.
+const (
+	xlsxMaxRows      = 1 << 20  // Excel's own row limit
+	xlsxMaxColumns   = 16384    // Excel's own column limit (XFD)
+	xlsxMaxCells     = 4 << 20  // Cells kept across the sheet, empty ones included
+	xlsxMaxPartBytes = 32 << 20 // Decompressed size of one XML part
+)
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name  string `xml:"name,attr"`
+		RelID string `xml:"id,attr"` // r:id
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxText is plain text or runs of formatted text
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not an xlsx file: %w", err)
+	}
+	parts := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		parts[file.Name] = file
+	}
+
+	sheetPart, err := firstSheetPart(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if _, ok := parts["xl/sharedStrings.xml"]; ok {
+		if err := decodePart(parts, "xl/sharedStrings.xml", &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	var sheet xlsxSheet
+	if err := decodePart(parts, sheetPart, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	cells := 0
+	for _, row := range sheet.Rows {
+		if row.Number < 0 || row.Number > xlsxMaxRows {
+			return nil, fmt.Errorf("row %d is out of range", row.Number)
+		}
+		// Empty rows are left out of the file; keep numbering so row
+		// errors point at the right spreadsheet row
+		if row.Number > 0 {
+			for len(rows) < row.Number-1 {
+				rows = append(rows, nil)
+			}
+		}
+
+		var values []string
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				column = columnIndex(cell.Ref)
+			}
+			if column < 0 || column >= xlsxMaxColumns {
+				return nil, fmt.Errorf("cell reference %q is invalid", cell.Ref)
+			}
+			if len(values) <= column {
+				cells += column + 1 - len(values)
+				if cells > xlsxMaxCells {
+					return nil, fmt.Errorf("the sheet has more than %d cells", xlsxMaxCells)
+				}
+			}
+			for len(values) <= column {
+				values = append(values, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(shared.Items) {
+					return nil, fmt.Errorf("cell %s references a missing shared string", cell.Ref)
+				}
+				values[column] = shared.Items[index].String()
+			case "inlineStr":
+				values[column] = cell.Inline.String()
+			case "", "n":
+				values[column] = numberText(cell.Value)
+			default: // str (formula text), b (boolean), e (error)
+				values[column] = cell.Value
+			}
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// firstSheetPart finds the part of the workbook's first sheet
+func firstSheetPart(parts map[string]*zip.File) (string, error) {
+	var workbook xlsxWorkbook
+	if err := decodePart(parts, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("the workbook has no sheets")
+	}
+
+	var rels xlsxRelationships
+	if err := decodePart(parts, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RelID {
+			continue
+		}
+		// Targets are relative to xl/ unless absolute
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+
+	if _, ok := parts["xl/worksheets/sheet1.xml"]; ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+	return "", fmt.Errorf("sheet %q not found", workbook.Sheets[0].Name)
+}
+
+func decodePart(parts map[string]*zip.File, name string, v any) error {
+	file, ok := parts[name]
+	if !ok {
+		return fmt.Errorf("not an xlsx file: %s is missing", name)
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// The zip header's size can't be trusted; stop inflating past the limit
+	limited := &io.LimitedReader{R: reader, N: xlsxMaxPartBytes + 1}
+	err = xml.NewDecoder(limited).Decode(v)
+	if limited.N == 0 {
+		return fmt.Errorf("%s is larger than %d bytes uncompressed", name, xlsxMaxPartBytes)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return nil
+}
+
+// columnIndex turns a cell reference such as "AB12" into its zero-based
+// column. It is -1 when the reference has no column letters, and stops
+// growing past xlsxMaxColumns so long references can't overflow.
+func columnIndex(ref string) int {
+	column := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+		if column > xlsxMaxColumns {
+			return xlsxMaxColumns
+		}
+	}
+	return column - 1
+}
+
+// numberText writes numbers stored in scientific notation in full, as
+// long phone numbers and IDs often are
+func numberText(value string) string {
+	if !strings.ContainsAny(value, "eE") {
+		return value
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
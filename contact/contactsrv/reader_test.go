@@ -0,0 +1,122 @@
+package contactsrv
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Abraxas-365/relay/contact"
+)
+
+const (
+	testWorkbook = `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Contacts" r:id="rId1"/></sheets></workbook>`
+	testRels = `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`
+)
+
+// xlsxFile builds a minimal workbook whose first sheet holds sheetData
+func xlsxFile(t *testing.T, sheetData string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	parts := map[string]string{
+		"xl/workbook.xml":            testWorkbook,
+		"xl/_rels/workbook.xml.rels": testRels,
+		"xl/worksheets/sheet1.xml":   "<worksheet><sheetData>" + sheetData + "</sheetData></worksheet>",
+	}
+	for name, content := range parts {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadRowsXLSX(t *testing.T) {
+	data := xlsxFile(t, `<row r="1"><c r="A1" t="inlineStr"><is><t>phone</t></is></c><c r="C1" t="inlineStr"><is><t>name</t></is></c></row>`+
+		`<row r="3"><c r="A3"><v>5.1987654321E10</v></c><c r="C3" t="inlineStr"><is><t>Ana</t></is></c></row>`)
+
+	rows, err := readRows(contact.FormatXLSX, data)
+	if err != nil {
+		t.Fatalf("readRows() error = %v", err)
+	}
+
+	want := [][]string{{"phone", "", "name"}, nil, {"51987654321", "", "Ana"}}
+	if len(rows) != len(want) {
+		t.Fatalf("readRows() = %q, want %q", rows, want)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q, want %q", i+1, rows[i], want[i])
+		}
+	}
+}
+
+func TestReadRowsXLSXRejectsMalformedPositions(t *testing.T) {
+	tests := []struct {
+		name      string
+		sheetData string
+		wantErr   string
+	}{
+		{name: "huge row number", sheetData: `<row r="2000000000"><c r="A1"><v>1</v></c></row>`, wantErr: "out of range"},
+		{name: "negative row number", sheetData: `<row r="-5"><c r="A1"><v>1</v></c></row>`, wantErr: "out of range"},
+		{name: "huge column", sheetData: `<row r="1"><c r="ZZZZZZZ1"><v>1</v></c></row>`, wantErr: "is invalid"},
+		{name: "column past XFD", sheetData: `<row r="1"><c r="XFE1"><v>1</v></c></row>`, wantErr: "is invalid"},
+		{name: "reference without letters", sheetData: `<row r="1"><c r="5"><v>1</v></c></row>`, wantErr: "is invalid"},
+		{name: "lowercase reference", sheetData: `<row r="1"><c r="a1"><v>1</v></c></row>`, wantErr: "is invalid"},
+		{name: "too many padded cells", sheetData: strings.Repeat(`<row><c r="XFD1"><v>1</v></c></row>`, xlsxMaxCells/xlsxMaxColumns+1), wantErr: "cells"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := xlsxFile(t, tt.sheetData)
+			if _, err := readXLSX(data); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("readXLSX() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+			// The HTTP validation and the import job both read through readRows
+			if _, err := readRows(contact.FormatXLSX, data); err == nil {
+				t.Error("readRows() should fail")
+			}
+		})
+	}
+}
+
+func TestReadRowsXLSXRejectsZipBomb(t *testing.T) {
+	// Highly compressible whitespace inflates past the part limit while the
+	// upload stays small
+	sheetData := `<row r="1"><c r="A1"><v>1</v></c></row>` + strings.Repeat(" ", xlsxMaxPartBytes)
+	data := xlsxFile(t, sheetData)
+	if len(data) > contact.MaxImportBytes {
+		t.Fatalf("test file is %d bytes, over the upload limit", len(data))
+	}
+
+	if _, err := readXLSX(data); err == nil || !strings.Contains(err.Error(), "uncompressed") {
+		t.Errorf("readXLSX() error = %v, want the part size limit", err)
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := map[string]int{
+		"A1":       0,
+		"Z9":       25,
+		"AA10":     26,
+		"XFD1":     xlsxMaxColumns - 1,
+		"5":        -1,
+		"":         -1,
+		"ZZZZZZZ1": xlsxMaxColumns,
+	}
+	for ref, want := range tests {
+		if got := columnIndex(ref); got != want {
+			t.Errorf("columnIndex(%q) = %d, want %d", ref, got, want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package contactsrv
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Abraxas-365/relay/contact"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ContactService reads and removes a tenant's contacts. Contacts are
+// written by imports (ImportService).
+type ContactService struct {
+	repo contact.ContactRepository
+}
+
+func NewContactService(repo contact.ContactRepository) *ContactService {
+	return &ContactService{repo: repo}
+}
+
+// ============================================================================
+// Contacts
+// ============================================================================
+
+func (s *ContactService) Get(ctx context.Context, id string, tenantID kernel.TenantID) (*contact.Contact, error) {
+	return s.repo.FindByID(ctx, id, tenantID)
+}
+
+// List pages through the tenant's contacts; the tag filter is normalized
+// like stored tags
+func (s *ContactService) List(ctx context.Context, req contact.ListContactsRequest) (contact.ContactListResponse, error) {
+	req.Search = strings.TrimSpace(req.Search)
+	if req.Tag != "" {
+		tag, err := conversation.NormalizeTag(req.Tag)
+		if err != nil {
+			return contact.ContactListResponse{}, err
+		}
+		req.Tag = tag
+	}
+	return s.repo.List(ctx, req)
+}
+
+func (s *ContactService) Delete(ctx context.Context, id string, tenantID kernel.TenantID) error {
+	return s.repo.Delete(ctx, id, tenantID)
+}
@@ -0,0 +1,56 @@
+package contact
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// ImportRequest describes an uploaded file: how its columns map onto
+// contact fields and what applies to every row. It is sent as the "mapping"
+// field of the multipart upload, next to the "file".
+type ImportRequest struct {
+	Mapping            Mapping  `json:"mapping"`
+	DefaultCountryCode string   `json:"default_country_code,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+}
+
+// ListContactsRequest pages through a tenant's contacts. Search matches
+// the name, phone, email or external ID; a contact matches Tag when it
+// carries it.
+type ListContactsRequest struct {
+	storex.PaginationOptions
+
+	TenantID kernel.TenantID `json:"tenant_id" validate:"required"`
+	Search   string          `json:"search,omitempty"`
+	Tag      string          `json:"tag,omitempty"`
+}
+
+func (r ListContactsRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+// ListImportsRequest pages through a tenant's imports, newest first
+type ListImportsRequest struct {
+	storex.PaginationOptions
+
+	TenantID kernel.TenantID `json:"tenant_id" validate:"required"`
+	Status   ImportStatus    `json:"status,omitempty"`
+}
+
+func (r ListImportsRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+// ContactListResponse paginated list of contacts
+type ContactListResponse = storex.Paginated[Contact]
+
+// ImportListResponse paginated list of imports
+type ImportListResponse = storex.Paginated[Import]
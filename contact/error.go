@@ -0,0 +1,59 @@
+package contact
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("CONTACT")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeContactNotFound      = ErrRegistry.Register("CONTACT_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Contact not found")
+	CodeInvalidContact       = ErrRegistry.Register("INVALID_CONTACT", errx.TypeValidation, http.StatusBadRequest, "Invalid contact")
+	CodeExternalIDTaken      = ErrRegistry.Register("EXTERNAL_ID_TAKEN", errx.TypeConflict, http.StatusConflict, "Another contact has this external ID")
+	CodeImportNotFound       = ErrRegistry.Register("IMPORT_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Contact import not found")
+	CodeInvalidImport        = ErrRegistry.Register("INVALID_IMPORT", errx.TypeValidation, http.StatusBadRequest, "Invalid contact import")
+	CodeTooManyImports       = ErrRegistry.Register("TOO_MANY_IMPORTS", errx.TypeConflict, http.StatusConflict, "Too many contact imports in progress")
+	CodeImportsNotConfigured = ErrRegistry.Register("IMPORTS_NOT_CONFIGURED", errx.TypeBusiness, http.StatusServiceUnavailable, "Contact imports need file storage")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrContactNotFound() *errx.Error {
+	return ErrRegistry.New(CodeContactNotFound)
+}
+
+func ErrInvalidContact() *errx.Error {
+	return ErrRegistry.New(CodeInvalidContact)
+}
+
+func ErrExternalIDTaken() *errx.Error {
+	return ErrRegistry.New(CodeExternalIDTaken)
+}
+
+func ErrImportNotFound() *errx.Error {
+	return ErrRegistry.New(CodeImportNotFound)
+}
+
+func ErrInvalidImport() *errx.Error {
+	return ErrRegistry.New(CodeInvalidImport)
+}
+
+func ErrTooManyImports() *errx.Error {
+	return ErrRegistry.New(CodeTooManyImports)
+}
+
+func ErrImportsNotConfigured() *errx.Error {
+	return ErrRegistry.New(CodeImportsNotConfigured)
+}
@@ -0,0 +1,260 @@
+package contact
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Imports
+// ============================================================================
+
+// MaxImportBytes bounds an uploaded file, the server's request body limit
+const MaxImportBytes = 4 << 20
+
+// MaxRowErrors bounds the row errors kept per import; the failed count
+// keeps going
+const MaxRowErrors = 500
+
+// FileFormat of an uploaded contact file
+type FileFormat string
+
+const (
+	FormatCSV  FileFormat = "csv"
+	FormatXLSX FileFormat = "xlsx" // The first worksheet is read
+)
+
+// FormatOf picks the format from a file name's extension
+func FormatOf(filename string) (FileFormat, bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, true
+	case ".xlsx":
+		return FormatXLSX, true
+	}
+	return "", false
+}
+
+// ContentType is the MIME type the file is stored with
+func (f FileFormat) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv"
+}
+
+// ImportStatus of an import job
+type ImportStatus string
+
+const (
+	ImportPending   ImportStatus = "PENDING"
+	ImportRunning   ImportStatus = "RUNNING"
+	ImportCompleted ImportStatus = "COMPLETED"
+	ImportFailed    ImportStatus = "FAILED"
+)
+
+// IsActive reports whether the import has not finished yet
+func (s ImportStatus) IsActive() bool {
+	return s == ImportPending || s == ImportRunning
+}
+
+// attributeNamePattern matches attribute names, usable in expressions
+var attributeNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Mapping names the file's column for each contact field, by header.
+// Unmapped columns are ignored.
+type Mapping struct {
+	ExternalID string            `json:"external_id,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Phone      string            `json:"phone,omitempty"`
+	Email      string            `json:"email,omitempty"`
+	Tags       string            `json:"tags,omitempty"`       // Column of comma-separated tags
+	Attributes map[string]string `json:"attributes,omitempty"` // Attribute name -> column
+}
+
+// Validate checks contacts can be matched and attribute names are valid
+func (m Mapping) Validate() error {
+	if m.ExternalID == "" && m.Phone == "" && m.Email == "" {
+		return ErrInvalidImport().WithDetail("mapping", "map at least one of external_id, phone and email")
+	}
+	for name, column := range m.Attributes {
+		if !attributeNamePattern.MatchString(name) {
+			return ErrInvalidImport().
+				WithDetail("attribute", name).
+				WithDetail("reason", "attribute names are letters, digits and underscores")
+		}
+		if column == "" {
+			return ErrInvalidImport().WithDetail("attribute", name).WithDetail("reason", "column is required")
+		}
+	}
+	return nil
+}
+
+// Columns lists the mapped column headers
+func (m Mapping) Columns() []string {
+	var columns []string
+	for _, column := range []string{m.ExternalID, m.Name, m.Phone, m.Email, m.Tags} {
+		if column != "" {
+			columns = append(columns, column)
+		}
+	}
+	for _, column := range m.Attributes {
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// ImportOptions apply to every row of an import
+type ImportOptions struct {
+	DefaultCountryCode string   `json:"default_country_code,omitempty"` // For phone numbers without one, e.g. "51"
+	Tags               []string `json:"tags,omitempty"`                 // Added to every imported contact
+}
+
+// ImportProgress counts the rows of an import. Rows matching a contact
+// already imported or created are merged into it.
+type ImportProgress struct {
+	TotalRows     int     `json:"total_rows"`
+	ProcessedRows int     `json:"processed_rows"`
+	Created       int     `json:"created"`
+	Updated       int     `json:"updated"`
+	Unchanged     int     `json:"unchanged"`
+	Failed        int     `json:"failed"`
+	Percent       float64 `json:"percent"`
+}
+
+// RowError is why a row was not imported. Row is the spreadsheet row
+// number: the header is row 1.
+type RowError struct {
+	Row    int    `json:"row"`
+	Column string `json:"column,omitempty"`
+	Error  string `json:"error"`
+}
+
+// Import loads contacts from an uploaded CSV or XLSX file. Every imported
+// contact gets the import's cohort tag, so the batch can be targeted.
+type Import struct {
+	ID          string          `json:"id"`
+	TenantID    kernel.TenantID `json:"tenant_id"`
+	RequestedBy kernel.UserID   `json:"requested_by"`
+	Filename    string          `json:"filename"`
+	Format      FileFormat      `json:"format"`
+	Mapping     Mapping         `json:"mapping"`
+	Options     ImportOptions   `json:"options"`
+	CohortTag   string          `json:"cohort_tag"`
+	Status      ImportStatus    `json:"status"`
+	Progress    ImportProgress  `json:"progress"`
+	Errors      []RowError      `json:"errors,omitempty"`
+	StorageKey  string          `json:"-"`
+	Error       string          `json:"error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+}
+
+// NewImport creates a pending import of an uploaded file
+func NewImport(tenantID kernel.TenantID, requestedBy kernel.UserID, filename string, format FileFormat, mapping Mapping, options ImportOptions) *Import {
+	id := uuid.NewString()
+	now := time.Now()
+	imp := &Import{
+		ID:          id,
+		TenantID:    tenantID,
+		RequestedBy: requestedBy,
+		Filename:    filename,
+		Format:      format,
+		Mapping:     mapping,
+		Options:     options,
+		CohortTag:   "import-" + now.Format("20060102") + "-" + id[:8],
+		Status:      ImportPending,
+		CreatedAt:   now,
+	}
+	imp.StorageKey = imp.FileKey()
+	return imp
+}
+
+// ============================================================================
+// Domain Methods
+// ============================================================================
+
+// Start marks the import running over total rows. A retry starts over;
+// rows imported before are matched and left unchanged.
+func (i *Import) Start(total int) {
+	now := time.Now()
+	i.Status = ImportRunning
+	i.StartedAt = &now
+	i.CompletedAt = nil
+	i.Error = ""
+	i.Errors = nil
+	i.Progress = ImportProgress{TotalRows: total}
+}
+
+// RecordCreated counts a row that created a contact
+func (i *Import) RecordCreated() {
+	i.Progress.Created++
+	i.advance()
+}
+
+// RecordUpdated counts a row merged into an existing contact
+func (i *Import) RecordUpdated() {
+	i.Progress.Updated++
+	i.advance()
+}
+
+// RecordUnchanged counts a row that matched a contact with the same data
+func (i *Import) RecordUnchanged() {
+	i.Progress.Unchanged++
+	i.advance()
+}
+
+// RecordFailed counts a row that was not imported, keeping its error while
+// fewer than MaxRowErrors were kept
+func (i *Import) RecordFailed(row int, column, reason string) {
+	i.Progress.Failed++
+	if len(i.Errors) < MaxRowErrors {
+		i.Errors = append(i.Errors, RowError{Row: row, Column: column, Error: reason})
+	}
+	i.advance()
+}
+
+func (i *Import) advance() {
+	i.Progress.ProcessedRows++
+	i.RefreshPercent()
+}
+
+// RefreshPercent derives the progress percentage from the row counts
+func (i *Import) RefreshPercent() {
+	switch {
+	case i.Status == ImportCompleted:
+		i.Progress.Percent = 100
+	case i.Progress.TotalRows > 0:
+		percent := 100 * float64(i.Progress.ProcessedRows) / float64(i.Progress.TotalRows)
+		// Only completion reports 100
+		i.Progress.Percent = min(percent, 99)
+	}
+}
+
+// Complete marks the import finished
+func (i *Import) Complete() {
+	now := time.Now()
+	i.Status = ImportCompleted
+	i.Progress.Percent = 100
+	i.CompletedAt = &now
+}
+
+// Fail marks the import failed
+func (i *Import) Fail(err error) {
+	now := time.Now()
+	i.Status = ImportFailed
+	i.Error = err.Error()
+	i.CompletedAt = &now
+}
+
+// FileKey is where the uploaded file is kept until the import finishes
+func (i *Import) FileKey() string {
+	return path.Join("imports", i.TenantID.String(), i.ID+"."+string(i.Format))
+}
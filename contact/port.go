@@ -0,0 +1,58 @@
+package contact
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// ContactRepository persists contacts
+type ContactRepository interface {
+	// Save creates or updates a contact; ErrExternalIDTaken when another
+	// contact of the tenant has its external ID
+	Save(ctx context.Context, contact Contact) error
+
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*Contact, error)
+
+	// FindByIdentity returns the contact matching the identity's external
+	// ID, else its phone, else its email. Only the first non-empty field is
+	// used. When several contacts share a phone or email, the oldest wins.
+	FindByIdentity(ctx context.Context, tenantID kernel.TenantID, identity Identity) (*Contact, error)
+
+	// List pages through the tenant's contacts, newest first
+	List(ctx context.Context, req ListContactsRequest) (ContactListResponse, error)
+
+	// ListAfter returns up to limit of the tenant's contacts in ID order,
+	// starting after afterID (from the first when empty)
+	ListAfter(ctx context.Context, tenantID kernel.TenantID, afterID string, limit int) ([]Contact, error)
+
+	Delete(ctx context.Context, id string, tenantID kernel.TenantID) error
+}
+
+// ImportRepository persists contact import jobs
+type ImportRepository interface {
+	// Save creates or updates an import
+	Save(ctx context.Context, imp Import) error
+
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*Import, error)
+
+	// List pages through the tenant's imports, newest first
+	List(ctx context.Context, req ListImportsRequest) (ImportListResponse, error)
+
+	// CountActive counts the tenant's pending and running imports
+	CountActive(ctx context.Context, tenantID kernel.TenantID) (int, error)
+}
+
+// ============================================================================
+// Observer Interfaces
+// ============================================================================
+
+// ImportObserver is told when an import finished writing contacts, so what
+// is derived from them can catch up
+type ImportObserver interface {
+	ContactsImported(ctx context.Context, imp Import)
+}
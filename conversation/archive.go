@@ -0,0 +1,93 @@
+package conversation
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Archives
+// ============================================================================
+
+// ArchiveContentType is the MIME type archive files are stored with
+const ArchiveContentType = "application/vnd.apache.parquet"
+
+// Archive is one tenant's messages of a month whose partition was dropped
+// from the database, kept as a Parquet file in the attachment storage.
+// Content is stored as it was in the database, still encrypted for tenants
+// with encryption enabled.
+type Archive struct {
+	ID           string          `json:"id"`
+	TenantID     kernel.TenantID `json:"tenant_id"`
+	Month        time.Time       `json:"month"`
+	StorageKey   string          `json:"-"`
+	MessageCount int             `json:"message_count"`
+	SizeBytes    int64           `json:"size_bytes"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// NewArchive creates the archive of the tenant's month
+func NewArchive(tenantID kernel.TenantID, month time.Time, messageCount int, sizeBytes int64) *Archive {
+	month = MonthOf(month)
+	return &Archive{
+		ID:           uuid.NewString(),
+		TenantID:     tenantID,
+		Month:        month,
+		StorageKey:   ArchiveKey(tenantID, month),
+		MessageCount: messageCount,
+		SizeBytes:    sizeBytes,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// ArchiveKey is where the file of a tenant's month is stored. Archiving a
+// month again replaces the file.
+func ArchiveKey(tenantID kernel.TenantID, month time.Time) string {
+	return path.Join("archives", "messages", tenantID.String(), MonthOf(month).Format("2006-01")+".parquet")
+}
+
+// ExpiredBy reports whether every message of the archive is older than the
+// retention cutoff
+func (a *Archive) ExpiredBy(cutoff time.Time) bool {
+	return !NextMonth(a.Month).After(cutoff)
+}
+
+// ============================================================================
+// Months
+// ============================================================================
+
+// MonthOf is the first instant of t's calendar month in UTC, the lower bound
+// of the partition t is stored in
+func MonthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// NextMonth is the first instant of the month after t's
+func NextMonth(t time.Time) time.Time {
+	return MonthOf(t).AddDate(0, 1, 0)
+}
+
+// PartitionName is the table holding the messages of t's month
+func PartitionName(t time.Time) string {
+	return fmt.Sprintf("messages_%s", MonthOf(t).Format("2006_01"))
+}
+
+// ParsePartitionName returns the month of a partition table; false for any
+// other table, like the default partition
+func ParsePartitionName(name string) (time.Time, bool) {
+	suffix, ok := strings.CutPrefix(name, "messages_")
+	if !ok {
+		return time.Time{}, false
+	}
+	month, err := time.Parse("2006_01", suffix)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return month, true
+}
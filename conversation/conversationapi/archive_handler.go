@@ -0,0 +1,52 @@
+package conversationapi
+
+import (
+	"github.com/Abraxas-365/relay/conversation/conversationsrv"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ArchiveHandler exposes the months of messages moved to cold storage
+type ArchiveHandler struct {
+	service *conversationsrv.ArchiveService
+}
+
+// NewArchiveHandler creates a new archive handler
+func NewArchiveHandler(service *conversationsrv.ArchiveService) *ArchiveHandler {
+	return &ArchiveHandler{
+		service: service,
+	}
+}
+
+// ListArchives returns the tenant's archived months, newest first
+// GET /api/conversations/archives
+func (h *ArchiveHandler) ListArchives(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	archives, err := h.service.ListArchives(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"archives": archives})
+}
+
+// GetArchive returns an archive with a link to download its Parquet file
+// GET /api/conversations/archives/:id
+func (h *ArchiveHandler) GetArchive(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	archive, err := h.service.GetArchive(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(archive)
+}
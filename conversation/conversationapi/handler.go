@@ -0,0 +1,253 @@
+package conversationapi
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/conversation/conversationsrv"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// ConversationHandler exposes conversation transcripts and their tags
+type ConversationHandler struct {
+	messageRepo conversation.MessageRepository
+	tagService  *conversationsrv.TagService
+}
+
+// NewConversationHandler creates a new conversation handler
+func NewConversationHandler(messageRepo conversation.MessageRepository, tagService *conversationsrv.TagService) *ConversationHandler {
+	return &ConversationHandler{
+		messageRepo: messageRepo,
+		tagService:  tagService,
+	}
+}
+
+// GetMessages returns the inbound and outbound messages of a conversation in order
+// GET /api/conversations/:session_id/messages?page=1&page_size=50&channel_id=
+func (h *ConversationHandler) GetMessages(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	conversationID := strings.TrimSpace(c.Params("session_id"))
+	if conversationID == "" {
+		return conversation.ErrInvalidConversationID()
+	}
+
+	req := conversation.ListMessagesRequest{
+		PaginationOptions: paginationFromQuery(c),
+		TenantID:          authContext.TenantID,
+		ConversationID:    conversationID,
+		ChannelID:         channelFromQuery(c),
+	}
+
+	messages, err := h.messageRepo.ListByConversation(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(conversation.ToTranscript(messages))
+}
+
+// GetRawPayload returns the provider event an inbound message was built from,
+// for debugging channel integrations
+// GET /api/conversations/messages/:message_id/raw
+func (h *ConversationHandler) GetRawPayload(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	messageID := strings.TrimSpace(c.Params("message_id"))
+	if messageID == "" {
+		return conversation.ErrMessageNotFound()
+	}
+
+	payload, err := h.messageRepo.FindRawPayload(c.Context(), authContext.TenantID, messageID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message_id":  messageID,
+		"raw_payload": payload,
+	})
+}
+
+// ============================================================================
+// Tags
+// ============================================================================
+
+// GetTags returns a conversation's tags; without channel_id, the contact's
+// across all channels
+// GET /api/conversations/:session_id/tags?channel_id=
+func (h *ConversationHandler) GetTags(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	conversationID := strings.TrimSpace(c.Params("session_id"))
+	if conversationID == "" {
+		return conversation.ErrInvalidConversationID()
+	}
+
+	tags, err := h.tagService.Tags(c.Context(), authContext.TenantID, channelFromQuery(c), conversationID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"tags": tags})
+}
+
+// UpdateTags adds and removes tags or sets the disposition as the operator
+// POST /api/conversations/:channel_id/:session_id/tags
+func (h *ConversationHandler) UpdateTags(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req conversation.TagUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return conversation.ErrInvalidTag().WithDetail("reason", err.Error())
+	}
+
+	tags, err := h.tagService.TagConversation(c.Context(), authContext.TenantID,
+		kernel.ChannelID(c.Params("channel_id")), strings.TrimSpace(c.Params("session_id")),
+		engine.TagUpdate{
+			Add:         req.Add,
+			Remove:      req.Remove,
+			Disposition: req.Disposition,
+			Source:      string(conversation.TagSourceOperator),
+			SetBy:       authContext.UserID.String(),
+		})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(tags)
+}
+
+// RemoveTag removes one tag or the disposition
+// DELETE /api/conversations/:channel_id/:session_id/tags/:tag
+func (h *ConversationHandler) RemoveTag(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	err := h.tagService.Remove(c.Context(), authContext.TenantID,
+		kernel.ChannelID(c.Params("channel_id")), strings.TrimSpace(c.Params("session_id")), c.Params("tag"))
+	if err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListTagged returns the conversations carrying all the tags and the
+// disposition, most recently tagged first
+// GET /api/conversations/tagged?tags=refund,vip&disposition=resolved&channel_id=
+func (h *ConversationHandler) ListTagged(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	req := conversation.ListTaggedRequest{
+		PaginationOptions: paginationFromQuery(c),
+		TenantID:          authContext.TenantID,
+		ChannelID:         channelFromQuery(c),
+		Disposition:       c.Query("disposition"),
+	}
+	for _, tag := range strings.Split(c.Query("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			req.Tags = append(req.Tags, tag)
+		}
+	}
+
+	conversations, err := h.tagService.ListConversations(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(conversations)
+}
+
+// TagStats counts conversations per tag and disposition applied in the
+// period (RFC 3339 from/to)
+// GET /api/conversations/tags/stats?from=&to=&channel_id=
+func (h *ConversationHandler) TagStats(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	req := conversation.TagStatsRequest{
+		TenantID:  authContext.TenantID,
+		ChannelID: channelFromQuery(c),
+	}
+	for param, target := range map[string]**time.Time{"from": &req.From, "to": &req.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return conversation.ErrInvalidTag().WithDetail(param, "must be an RFC 3339 timestamp")
+		}
+		*target = &parsed
+	}
+
+	stats, err := h.tagService.Stats(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(stats)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func paginationFromQuery(c *fiber.Ctx) storex.PaginationOptions {
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", defaultPageSize)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	return storex.PaginationOptions{
+		Page:     page,
+		PageSize: pageSize,
+	}
+}
+
+func channelFromQuery(c *fiber.Ctx) *kernel.ChannelID {
+	channelID := c.Query("channel_id")
+	if channelID == "" {
+		return nil
+	}
+	id := kernel.NewChannelID(channelID)
+	return &id
+}
@@ -0,0 +1,56 @@
+package conversationapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConversationRoutes handles conversation route setup
+type ConversationRoutes struct {
+	handler *ConversationHandler
+}
+
+// NewConversationRoutes creates a new conversation routes instance
+func NewConversationRoutes(handler *ConversationHandler) *ConversationRoutes {
+	return &ConversationRoutes{
+		handler: handler,
+	}
+}
+
+// RegisterRoutes registers conversation routes on an authenticated router
+func (r *ConversationRoutes) RegisterRoutes(router fiber.Router) {
+	conversations := router.Group("/conversations")
+
+	conversations.Get("/tagged", r.handler.ListTagged)
+	conversations.Get("/tags/stats", r.handler.TagStats)
+
+	conversations.Get("/:session_id/messages", r.handler.GetMessages)
+	conversations.Get("/messages/:message_id/raw", r.handler.GetRawPayload)
+
+	conversations.Get("/:session_id/tags", r.handler.GetTags)
+	conversations.Post("/:channel_id/:session_id/tags", r.handler.UpdateTags)
+	conversations.Delete("/:channel_id/:session_id/tags/:tag", r.handler.RemoveTag)
+}
+
+// ArchiveRoutes handles message archive route setup
+type ArchiveRoutes struct {
+	handler        *ArchiveHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewArchiveRoutes creates a new archive routes instance
+func NewArchiveRoutes(handler *ArchiveHandler, authMiddleware *auth.AuthMiddleware) *ArchiveRoutes {
+	return &ArchiveRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers archive routes on an authenticated router.
+// Archives hold every conversation of a month, so they require an admin.
+func (r *ArchiveRoutes) RegisterRoutes(router fiber.Router) {
+	archives := router.Group("/conversations/archives", r.authMiddleware.RequireAdmin())
+
+	archives.Get("/", r.handler.ListArchives)
+	archives.Get("/:id", r.handler.GetArchive)
+}
@@ -0,0 +1,243 @@
+package conversationinfra
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// defaultBatchSize flushes a batch as soon as it holds this many messages
+	defaultBatchSize = 200
+
+	// defaultBatchDelay is the longest a message waits for its batch to fill
+	defaultBatchDelay = 10 * time.Millisecond
+
+	// batchFlushTimeout bounds a single flush, which serves several callers and
+	// therefore can't use any one caller's context
+	batchFlushTimeout = 10 * time.Second
+)
+
+// pendingMessage is a prepared row waiting for its batch to be written
+type pendingMessage struct {
+	row    *dbMessage
+	result chan error
+}
+
+// BatchMessageWriter groups concurrent message saves into COPY batches.
+//
+// Save blocks until the batch holding its message is committed, so a caller
+// never reports a message as stored before it is (a crash loses nothing that
+// was acknowledged) and the only cost is up to maxDelay of added latency. When
+// a batch fails as a whole, its rows are retried one by one so each caller
+// gets its own result and one bad row does not drop the others.
+type BatchMessageWriter struct {
+	*PostgresMessageRepository
+
+	maxSize  int
+	maxDelay time.Duration
+	queue    chan pendingMessage
+
+	mu       sync.RWMutex
+	running  bool
+	stopChan chan struct{}
+	done     chan struct{}
+}
+
+var _ conversation.MessageRepository = (*BatchMessageWriter)(nil)
+
+// NewBatchMessageWriter wraps repo; reads go straight to it. Zero values for
+// maxSize and maxDelay use the defaults.
+func NewBatchMessageWriter(repo *PostgresMessageRepository, maxSize int, maxDelay time.Duration) *BatchMessageWriter {
+	if maxSize <= 0 {
+		maxSize = defaultBatchSize
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultBatchDelay
+	}
+
+	return &BatchMessageWriter{
+		PostgresMessageRepository: repo,
+		maxSize:                   maxSize,
+		maxDelay:                  maxDelay,
+		queue:                     make(chan pendingMessage, maxSize*4),
+		stopChan:                  make(chan struct{}),
+		done:                      make(chan struct{}),
+	}
+}
+
+// Save queues the message for the next batch and waits until it is written.
+// Before Start and after Stop messages are written directly.
+func (w *BatchMessageWriter) Save(ctx context.Context, msg conversation.Message) error {
+	row, err := w.prepare(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	pending := pendingMessage{row: row, result: make(chan error, 1)}
+
+	w.mu.RLock()
+	if !w.running {
+		w.mu.RUnlock()
+		return w.insert(ctx, row)
+	}
+	select {
+	case w.queue <- pending:
+		w.mu.RUnlock()
+	case <-ctx.Done():
+		w.mu.RUnlock()
+		return conversation.ErrMessagePersistenceFailed().
+			WithDetail("message_id", msg.ID).
+			WithCause(ctx.Err())
+	}
+
+	// Once queued the message is written even if the caller stops waiting
+	select {
+	case err := <-pending.result:
+		return err
+	case <-ctx.Done():
+		return conversation.ErrMessagePersistenceFailed().
+			WithDetail("message_id", msg.ID).
+			WithCause(ctx.Err())
+	}
+}
+
+// Start runs the flush loop until Stop is called. Messages still queued at
+// that point are flushed before Stop returns.
+func (w *BatchMessageWriter) Start(ctx context.Context) {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		log.Println("⚠️  Message batch writer already running")
+		return
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	defer close(w.done)
+
+	log.Printf("💬 Starting message batch writer (up to %d messages every %s)...", w.maxSize, w.maxDelay)
+
+	batch := make([]pendingMessage, 0, w.maxSize)
+	timer := time.NewTimer(w.maxDelay)
+	timer.Stop()
+
+	ctxDone := ctx.Done()
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		w.flush(batch)
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case pending := <-w.queue:
+			if len(batch) == 0 {
+				timer.Reset(w.maxDelay)
+			}
+			batch = append(batch, pending)
+			if len(batch) >= w.maxSize {
+				timer.Stop()
+				flush()
+			}
+
+		case <-timer.C:
+			flush()
+
+		case <-w.stopChan:
+			timer.Stop()
+			w.drain(&batch)
+			flush()
+			log.Println("⏹️  Message batch writer stopped")
+			return
+
+		case <-ctxDone:
+			// Stop has to wait for blocked enqueues, which need this loop
+			// running; the final flush happens once it closes stopChan
+			ctxDone = nil
+			go w.Stop()
+		}
+	}
+}
+
+// Stop stops accepting messages and waits for the queue to be flushed
+func (w *BatchMessageWriter) Stop() {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return
+	}
+	// Taking the write lock waits for every in-flight enqueue, so nothing can
+	// be added after the final drain
+	w.running = false
+	w.mu.Unlock()
+
+	close(w.stopChan)
+	<-w.done
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// drain moves everything already queued into the batch
+func (w *BatchMessageWriter) drain(batch *[]pendingMessage) {
+	for {
+		select {
+		case pending := <-w.queue:
+			*batch = append(*batch, pending)
+		default:
+			return
+		}
+	}
+}
+
+// flush writes the batch with one COPY per database: tenants in different
+// data regions can share a batch
+func (w *BatchMessageWriter) flush(batch []pendingMessage) {
+	ctx, cancel := context.WithTimeout(context.Background(), batchFlushTimeout)
+	defer cancel()
+
+	groups := make(map[*sqlx.DB][]pendingMessage)
+	for _, pending := range batch {
+		db, err := w.conn(ctx, kernel.TenantID(pending.row.TenantID))
+		if err != nil {
+			pending.result <- conversation.ErrMessagePersistenceFailed().
+				WithDetail("message_id", pending.row.ID).
+				WithCause(err)
+			continue
+		}
+		groups[db] = append(groups[db], pending)
+	}
+
+	for db, group := range groups {
+		w.flushTo(ctx, db, group)
+	}
+}
+
+func (w *BatchMessageWriter) flushTo(ctx context.Context, db *sqlx.DB, batch []pendingMessage) {
+	rows := make([]*dbMessage, len(batch))
+	for i, pending := range batch {
+		rows[i] = pending.row
+	}
+
+	if err := w.copyRows(ctx, db, rows); err == nil {
+		for _, pending := range batch {
+			pending.result <- nil
+		}
+		return
+	} else if len(batch) > 1 {
+		log.Printf("⚠️  Message batch of %d failed, retrying row by row: %v", len(batch), err)
+	}
+
+	for _, pending := range batch {
+		pending.result <- w.insert(ctx, pending.row)
+	}
+}
@@ -0,0 +1,447 @@
+package conversationinfra
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ============================================================================
+// Parquet Writer
+// ============================================================================
+
+// parquetWriter writes a Parquet file of flat, optional columns. It covers
+// only what message archives need: UTF8 and JSON strings and microsecond
+// timestamps, one PLAIN encoded, GZIP compressed data page per column chunk,
+// no statistics. Rows are buffered until a row group is full.
+//
+// See https://github.com/apache/parquet-format for the layout and the Thrift
+// definitions of the footer.
+type parquetWriter struct {
+	w            *countingWriter
+	columns      []parquetColumn
+	rowGroupSize int
+	rows         [][]any
+	rowGroups    []parquetRowGroup
+	numRows      int64
+}
+
+// parquetKind is how a column's values are stored
+type parquetKind int
+
+const (
+	parquetString    parquetKind = iota // string, UTF8 byte array
+	parquetJSON                         // []byte, JSON byte array
+	parquetTimestamp                    // time.Time, INT64 microseconds since the epoch in UTC
+)
+
+// parquetColumn is one column of the file; every column is optional and a
+// nil value is stored as null
+type parquetColumn struct {
+	Name string
+	Kind parquetKind
+}
+
+type parquetRowGroup struct {
+	chunks   []parquetChunk
+	numRows  int64
+	byteSize int64
+}
+
+type parquetChunk struct {
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+// parquetMagic opens and closes every Parquet file
+const parquetMagic = "PAR1"
+
+// Parquet physical types, converted types, encodings and codecs used
+const (
+	parquetTypeInt64     = 2
+	parquetTypeByteArray = 6
+
+	parquetConvertedUTF8            = 0
+	parquetConvertedTimestampMicros = 10
+	parquetConvertedJSON            = 19
+
+	parquetOptional = 1
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetCodecGzip = 2
+
+	parquetDataPage = 0
+)
+
+func newParquetWriter(w io.Writer, columns []parquetColumn, rowGroupSize int) (*parquetWriter, error) {
+	cw := &countingWriter{w: w}
+	if _, err := io.WriteString(cw, parquetMagic); err != nil {
+		return nil, err
+	}
+
+	return &parquetWriter{
+		w:            cw,
+		columns:      columns,
+		rowGroupSize: rowGroupSize,
+	}, nil
+}
+
+// Write buffers a row, one value per column in order
+func (pw *parquetWriter) Write(row []any) error {
+	if len(row) != len(pw.columns) {
+		return fmt.Errorf("parquet row has %d values, want %d", len(row), len(pw.columns))
+	}
+
+	pw.rows = append(pw.rows, row)
+	if len(pw.rows) >= pw.rowGroupSize {
+		return pw.flushRowGroup()
+	}
+	return nil
+}
+
+// Close writes the buffered rows and the footer. The underlying writer is
+// left open.
+func (pw *parquetWriter) Close() error {
+	if err := pw.flushRowGroup(); err != nil {
+		return err
+	}
+
+	footer := pw.fileMetaData()
+	if _, err := pw.w.Write(footer); err != nil {
+		return err
+	}
+	if err := binary.Write(pw.w, binary.LittleEndian, uint32(len(footer))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(pw.w, parquetMagic)
+	return err
+}
+
+// ============================================================================
+// Row Groups
+// ============================================================================
+
+func (pw *parquetWriter) flushRowGroup() error {
+	if len(pw.rows) == 0 {
+		return nil
+	}
+
+	group := parquetRowGroup{numRows: int64(len(pw.rows))}
+	for i := range pw.columns {
+		chunk, err := pw.writeChunk(i)
+		if err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, chunk)
+		group.byteSize += chunk.uncompressedSize
+	}
+
+	pw.rowGroups = append(pw.rowGroups, group)
+	pw.numRows += group.numRows
+	pw.rows = pw.rows[:0]
+	return nil
+}
+
+// writeChunk writes the buffered values of a column as a single data page:
+// the definition levels (1 = present, 0 = null) followed by the values
+func (pw *parquetWriter) writeChunk(col int) (parquetChunk, error) {
+	defined := make([]bool, len(pw.rows))
+	var values bytes.Buffer
+	for i, row := range pw.rows {
+		present, err := pw.encodeValue(&values, pw.columns[col], row[col])
+		if err != nil {
+			return parquetChunk{}, err
+		}
+		defined[i] = present
+	}
+
+	var page bytes.Buffer
+	writeDefinitionLevels(&page, defined)
+	page.Write(values.Bytes())
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	if _, err := gz.Write(page.Bytes()); err != nil {
+		return parquetChunk{}, err
+	}
+	if err := gz.Close(); err != nil {
+		return parquetChunk{}, err
+	}
+
+	header := newThriftWriter()
+	header.i32(1, parquetDataPage)
+	header.i32(2, int32(page.Len()))
+	header.i32(3, int32(compressed.Len()))
+	header.beginStruct(5)
+	header.i32(1, int32(len(pw.rows)))
+	header.i32(2, parquetEncodingPlain)
+	header.i32(3, parquetEncodingRLE)
+	header.i32(4, parquetEncodingRLE)
+	header.endStruct()
+	headerBytes := header.finish()
+
+	chunk := parquetChunk{
+		offset:           pw.w.n,
+		numValues:        int64(len(pw.rows)),
+		uncompressedSize: int64(len(headerBytes) + page.Len()),
+		compressedSize:   int64(len(headerBytes) + compressed.Len()),
+	}
+	if _, err := pw.w.Write(headerBytes); err != nil {
+		return parquetChunk{}, err
+	}
+	if _, err := pw.w.Write(compressed.Bytes()); err != nil {
+		return parquetChunk{}, err
+	}
+
+	return chunk, nil
+}
+
+// encodeValue appends a PLAIN encoded value; false when it is null
+func (pw *parquetWriter) encodeValue(buf *bytes.Buffer, column parquetColumn, value any) (bool, error) {
+	if value == nil {
+		return false, nil
+	}
+
+	switch column.Kind {
+	case parquetString, parquetJSON:
+		var data []byte
+		switch v := value.(type) {
+		case string:
+			data = []byte(v)
+		case []byte:
+			if v == nil {
+				return false, nil
+			}
+			data = v
+		default:
+			return false, fmt.Errorf("parquet column %s: unexpected %T", column.Name, value)
+		}
+		binary.Write(buf, binary.LittleEndian, uint32(len(data)))
+		buf.Write(data)
+
+	case parquetTimestamp:
+		t, ok := value.(time.Time)
+		if !ok {
+			return false, fmt.Errorf("parquet column %s: unexpected %T", column.Name, value)
+		}
+		binary.Write(buf, binary.LittleEndian, t.UnixMicro())
+	}
+
+	return true, nil
+}
+
+// writeDefinitionLevels writes the levels of an optional flat column with
+// the RLE hybrid encoding, as runs of equal levels, behind their length
+func writeDefinitionLevels(buf *bytes.Buffer, defined []bool) {
+	var runs bytes.Buffer
+	for i := 0; i < len(defined); {
+		j := i
+		for j < len(defined) && defined[j] == defined[i] {
+			j++
+		}
+		// An RLE run header is the run length shifted left once; bit width 1
+		// takes one byte per value
+		runs.Write(binary.AppendUvarint(nil, uint64(j-i)<<1))
+		if defined[i] {
+			runs.WriteByte(1)
+		} else {
+			runs.WriteByte(0)
+		}
+		i = j
+	}
+
+	binary.Write(buf, binary.LittleEndian, uint32(runs.Len()))
+	buf.Write(runs.Bytes())
+}
+
+// ============================================================================
+// Footer
+// ============================================================================
+
+// fileMetaData encodes the FileMetaData struct: the schema, a flat list of
+// columns under the root, and where each row group's chunks are
+func (pw *parquetWriter) fileMetaData() []byte {
+	t := newThriftWriter()
+	t.i32(1, 1)
+
+	t.beginList(2, thriftStruct, len(pw.columns)+1)
+	t.beginElement()
+	t.binary(4, "schema")
+	t.i32(5, int32(len(pw.columns)))
+	t.endStruct()
+	for _, column := range pw.columns {
+		physical, converted := column.types()
+		t.beginElement()
+		t.i32(1, physical)
+		t.i32(3, parquetOptional)
+		t.binary(4, column.Name)
+		t.i32(6, converted)
+		t.endStruct()
+	}
+
+	t.i64(3, pw.numRows)
+
+	t.beginList(4, thriftStruct, len(pw.rowGroups))
+	for _, group := range pw.rowGroups {
+		t.beginElement()
+		t.beginList(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			physical, _ := pw.columns[i].types()
+			t.beginElement()
+			t.i64(2, chunk.offset)
+			t.beginStruct(3)
+			t.i32(1, physical)
+			t.beginList(2, thriftI32, 2)
+			t.listI32(parquetEncodingPlain)
+			t.listI32(parquetEncodingRLE)
+			t.beginList(3, thriftBinary, 1)
+			t.listBinary(pw.columns[i].Name)
+			t.i32(4, parquetCodecGzip)
+			t.i64(5, chunk.numValues)
+			t.i64(6, chunk.uncompressedSize)
+			t.i64(7, chunk.compressedSize)
+			t.i64(9, chunk.offset)
+			t.endStruct()
+			t.endStruct()
+		}
+		t.i64(2, group.byteSize)
+		t.i64(3, group.numRows)
+		t.endStruct()
+	}
+
+	t.binary(6, "relay")
+	return t.finish()
+}
+
+// types returns the physical and converted type of the column
+func (c parquetColumn) types() (int32, int32) {
+	switch c.Kind {
+	case parquetJSON:
+		return parquetTypeByteArray, parquetConvertedJSON
+	case parquetTimestamp:
+		return parquetTypeInt64, parquetConvertedTimestampMicros
+	default:
+		return parquetTypeByteArray, parquetConvertedUTF8
+	}
+}
+
+// ============================================================================
+// Thrift Compact Protocol
+// ============================================================================
+
+// Compact protocol type IDs
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes the few Thrift compact protocol constructs Parquet
+// metadata needs. Field IDs are written as deltas from the previous field
+// of the same struct, so one is tracked per open struct.
+type thriftWriter struct {
+	buf    bytes.Buffer
+	fields []int16
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{fields: []int16{0}}
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.fieldHeader(id, thriftBinary)
+	t.listBinary(s)
+}
+
+// beginStruct opens a struct field; endStruct closes it
+func (t *thriftWriter) beginStruct(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.fields = append(t.fields, 0)
+}
+
+func (t *thriftWriter) endStruct() {
+	t.buf.WriteByte(0)
+	t.fields = t.fields[:len(t.fields)-1]
+}
+
+// beginList opens a list field of size elements, written right after
+func (t *thriftWriter) beginList(id int16, elemType byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	t.buf.WriteByte(0xF0 | elemType)
+	t.buf.Write(binary.AppendUvarint(nil, uint64(size)))
+}
+
+// beginElement opens a struct element of a list; endStruct closes it
+func (t *thriftWriter) beginElement() {
+	t.fields = append(t.fields, 0)
+}
+
+func (t *thriftWriter) listI32(v int32) {
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) listBinary(s string) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64(len(s))))
+	t.buf.WriteString(s)
+}
+
+// finish closes the top-level struct and returns the encoding
+func (t *thriftWriter) finish() []byte {
+	t.buf.WriteByte(0)
+	return t.buf.Bytes()
+}
+
+func (t *thriftWriter) fieldHeader(id int16, fieldType byte) {
+	last := &t.fields[len(t.fields)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.buf.WriteByte(fieldType)
+		t.varint(int64(id))
+	}
+	*last = id
+}
+
+// varint writes a zigzag encoded integer
+func (t *thriftWriter) varint(v int64) {
+	t.buf.Write(binary.AppendVarint(nil, v))
+}
+
+// ============================================================================
+// Helper Types
+// ============================================================================
+
+// countingWriter tracks the offset chunks start at
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
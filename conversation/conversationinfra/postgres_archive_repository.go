@@ -0,0 +1,115 @@
+package conversationinfra
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresArchiveRepository struct {
+	db *sqlx.DB
+}
+
+var _ conversation.ArchiveRepository = (*PostgresArchiveRepository)(nil)
+
+func NewPostgresArchiveRepository(db *sqlx.DB) *PostgresArchiveRepository {
+	return &PostgresArchiveRepository{db: db}
+}
+
+// dbArchive is an intermediate struct for database operations
+type dbArchive struct {
+	ID           string    `db:"id"`
+	TenantID     string    `db:"tenant_id"`
+	Month        time.Time `db:"month"`
+	StorageKey   string    `db:"storage_key"`
+	MessageCount int       `db:"message_count"`
+	SizeBytes    int64     `db:"size_bytes"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+const archiveColumnList = `id, tenant_id, month, storage_key, message_count, size_bytes, created_at`
+
+func (r *PostgresArchiveRepository) Save(ctx context.Context, archive conversation.Archive) error {
+	query := `
+		INSERT INTO message_archives (` + archiveColumnList + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (tenant_id, month) DO UPDATE SET
+			storage_key = EXCLUDED.storage_key,
+			message_count = EXCLUDED.message_count,
+			size_bytes = EXCLUDED.size_bytes,
+			created_at = EXCLUDED.created_at`
+
+	_, err := r.db.ExecContext(ctx, query,
+		archive.ID, archive.TenantID.String(), archive.Month.Format("2006-01-02"), archive.StorageKey,
+		archive.MessageCount, archive.SizeBytes, archive.CreatedAt,
+	)
+	if err != nil {
+		return errx.Wrap(err, "failed to save message archive", errx.TypeInternal).
+			WithDetail("storage_key", archive.StorageKey)
+	}
+
+	return nil
+}
+
+func (r *PostgresArchiveRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*conversation.Archive, error) {
+	query := `SELECT ` + archiveColumnList + ` FROM message_archives WHERE id = $1 AND tenant_id = $2`
+
+	var row dbArchive
+	if err := r.db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, conversation.ErrArchiveNotFound().WithDetail("archive_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find message archive", errx.TypeInternal).
+			WithDetail("archive_id", id)
+	}
+
+	return toDomainArchive(&row), nil
+}
+
+func (r *PostgresArchiveRepository) ListByTenant(ctx context.Context, tenantID kernel.TenantID) ([]conversation.Archive, error) {
+	query := `SELECT ` + archiveColumnList + ` FROM message_archives WHERE tenant_id = $1 ORDER BY month DESC`
+
+	var rows []dbArchive
+	if err := r.db.SelectContext(ctx, &rows, query, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to list message archives", errx.TypeInternal)
+	}
+
+	archives := make([]conversation.Archive, len(rows))
+	for i := range rows {
+		archives[i] = *toDomainArchive(&rows[i])
+	}
+	return archives, nil
+}
+
+func (r *PostgresArchiveRepository) ListTenants(ctx context.Context) ([]kernel.TenantID, error) {
+	var tenantIDs []kernel.TenantID
+	if err := r.db.SelectContext(ctx, &tenantIDs, `SELECT DISTINCT tenant_id FROM message_archives`); err != nil {
+		return nil, errx.Wrap(err, "failed to list archived tenants", errx.TypeInternal)
+	}
+	return tenantIDs, nil
+}
+
+func (r *PostgresArchiveRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM message_archives WHERE id = $1`, id); err != nil {
+		return errx.Wrap(err, "failed to delete message archive", errx.TypeInternal).
+			WithDetail("archive_id", id)
+	}
+	return nil
+}
+
+func toDomainArchive(row *dbArchive) *conversation.Archive {
+	return &conversation.Archive{
+		ID:           row.ID,
+		TenantID:     kernel.TenantID(row.TenantID),
+		Month:        conversation.MonthOf(row.Month),
+		StorageKey:   row.StorageKey,
+		MessageCount: row.MessageCount,
+		SizeBytes:    row.SizeBytes,
+		CreatedAt:    row.CreatedAt,
+	}
+}
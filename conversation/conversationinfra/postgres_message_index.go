@@ -0,0 +1,95 @@
+package conversationinfra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/search"
+)
+
+// PostgresMessageIndex searches the messages table through its search_vector
+// column, which a trigger keeps in sync with the text and caption of each
+// message. Encrypted messages have an empty vector and never match. Reads
+// share the repository's decryption and retention window.
+type PostgresMessageIndex struct {
+	repo *PostgresMessageRepository
+}
+
+var _ search.MessageIndex = (*PostgresMessageIndex)(nil)
+
+func NewPostgresMessageIndex(repo *PostgresMessageRepository) *PostgresMessageIndex {
+	return &PostgresMessageIndex{repo: repo}
+}
+
+// dbMessageHit is a matching message with its rank and highlighted excerpt
+type dbMessageHit struct {
+	dbMessage
+	Rank      float64 `db:"rank"`
+	Highlight string  `db:"highlight"`
+}
+
+// searchText is what message_search_vector() indexes
+const searchText = `COALESCE(m.content->>'text', '') || ' ' || COALESCE(m.content->>'caption', '')`
+
+func (i *PostgresMessageIndex) SearchMessages(ctx context.Context, query search.MessageQuery) (search.MessageHitListResponse, error) {
+	where, args := matchingConditions(i.repo.bounded(ctx, query.MessageFilter()))
+	argPos := len(args) + 1
+
+	where += fmt.Sprintf(" AND m.search_vector @@ websearch_to_tsquery('simple', $%d)", argPos)
+	args = append(args, query.Text)
+	queryPos := argPos
+	argPos++
+
+	db, err := i.repo.conn(ctx, query.TenantID)
+	if err != nil {
+		return search.MessageHitListResponse{}, err
+	}
+
+	var total int
+	if err := db.GetContext(ctx, &total, "SELECT COUNT(*) FROM messages m WHERE "+where, args...); err != nil {
+		return search.MessageHitListResponse{}, errx.Wrap(err, "failed to count matching messages", errx.TypeInternal)
+	}
+
+	dataQuery := fmt.Sprintf(`
+		SELECT
+			m.id, m.tenant_id, m.channel_id, m.conversation_id, m.sender_id, m.direction, m.origin,
+			m.content, m.context, m.status, m.provider_message_id, m.workflow_id, m.node_id,
+			m.created_at, m.updated_at,
+			ts_rank(m.search_vector, websearch_to_tsquery('simple', $%[1]d)) AS rank,
+			ts_headline('simple', %[2]s, websearch_to_tsquery('simple', $%[1]d),
+				'MaxFragments=2, MinWords=5, MaxWords=20') AS highlight
+		FROM messages m
+		WHERE %[3]s
+		ORDER BY rank DESC, m.created_at DESC, m.id
+		LIMIT $%[4]d OFFSET $%[5]d`,
+		queryPos, searchText, where, argPos, argPos+1)
+	args = append(args, query.PageSize, query.GetOffset())
+
+	var rows []dbMessageHit
+	if err := db.SelectContext(ctx, &rows, dataQuery, args...); err != nil {
+		return search.MessageHitListResponse{}, errx.Wrap(err, "failed to search messages", errx.TypeInternal)
+	}
+
+	hits := make([]search.MessageHit, 0, len(rows))
+	for j := range rows {
+		if err := i.repo.open(ctx, query.TenantID, &rows[j].dbMessage); err != nil {
+			return search.MessageHitListResponse{}, errx.Wrap(err, "failed to decrypt message", errx.TypeInternal).
+				WithDetail("message_id", rows[j].ID)
+		}
+
+		msg, err := toDomainMessage(&rows[j].dbMessage)
+		if err != nil {
+			return search.MessageHitListResponse{}, errx.Wrap(err, "failed to convert message", errx.TypeInternal).
+				WithDetail("message_id", rows[j].ID)
+		}
+		hits = append(hits, search.MessageHit{
+			Message:   *msg,
+			Highlight: rows[j].Highlight,
+			Rank:      rows[j].Rank,
+		})
+	}
+
+	return storex.NewPaginated(hits, query.Page, query.PageSize, total), nil
+}
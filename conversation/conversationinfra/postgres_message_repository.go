@@ -0,0 +1,622 @@
+package conversationinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/encryption"
+	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PostgresMessageRepository struct {
+	db     *sqlx.DB
+	cipher *encryption.FieldCipher
+	window conversation.RetentionWindow // nil = every stored message is readable
+	router *database.Router             // nil = every tenant's messages are in db
+}
+
+var (
+	_ conversation.MessageRepository = (*PostgresMessageRepository)(nil)
+	_ conversation.ChannelPurger     = (*PostgresMessageRepository)(nil)
+)
+
+// NewPostgresMessageRepository creates the repository. Content and context are
+// encrypted at rest for tenants with encryption enabled; cipher may be nil.
+func NewPostgresMessageRepository(db *sqlx.DB, cipher *encryption.FieldCipher) *PostgresMessageRepository {
+	return &PostgresMessageRepository{db: db, cipher: cipher}
+}
+
+// SetRetentionWindow bounds reads by the tenants' message retention. Besides
+// hiding expired messages the purge has not reached yet, the lower bound on
+// created_at lets Postgres skip the partitions of older months.
+func (r *PostgresMessageRepository) SetRetentionWindow(window conversation.RetentionWindow) {
+	r.window = window
+}
+
+// SetRouter keeps each tenant's messages in the database of its data region
+func (r *PostgresMessageRepository) SetRouter(router *database.Router) {
+	r.router = router
+}
+
+// dbMessage is an intermediate struct for database operations
+type dbMessage struct {
+	ID                string          `db:"id"`
+	TenantID          string          `db:"tenant_id"`
+	ChannelID         string          `db:"channel_id"`
+	ConversationID    string          `db:"conversation_id"`
+	SenderID          string          `db:"sender_id"`
+	Direction         string          `db:"direction"`
+	Origin            string          `db:"origin"`
+	Content           json.RawMessage `db:"content"`
+	Context           json.RawMessage `db:"context"`
+	RawPayload        json.RawMessage `db:"raw_payload"`
+	Status            string          `db:"status"`
+	ProviderMessageID sql.NullString  `db:"provider_message_id"`
+	WorkflowID        sql.NullString  `db:"workflow_id"`
+	NodeID            sql.NullString  `db:"node_id"`
+	CreatedAt         time.Time       `db:"created_at"`
+	UpdatedAt         time.Time       `db:"updated_at"`
+}
+
+func toDBMessage(msg conversation.Message) (*dbMessage, error) {
+	contentJSON, err := json.Marshal(msg.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal content: %w", err)
+	}
+
+	contextJSON := []byte("{}")
+	if len(msg.Context) > 0 {
+		contextJSON, err = json.Marshal(msg.Context)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal context: %w", err)
+		}
+	}
+
+	var rawJSON json.RawMessage
+	if len(msg.RawPayload) > 0 {
+		rawJSON, err = json.Marshal(msg.RawPayload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal raw payload: %w", err)
+		}
+	}
+
+	return &dbMessage{
+		ID:                msg.ID,
+		TenantID:          msg.TenantID.String(),
+		ChannelID:         msg.ChannelID.String(),
+		ConversationID:    msg.ConversationID,
+		SenderID:          msg.SenderID,
+		Direction:         string(msg.Direction),
+		Origin:            string(msg.Origin),
+		Content:           contentJSON,
+		Context:           contextJSON,
+		RawPayload:        rawJSON,
+		Status:            string(msg.Status),
+		ProviderMessageID: nullString(msg.ProviderMessageID),
+		WorkflowID:        nullString(msg.WorkflowID),
+		NodeID:            nullString(msg.NodeID),
+		CreatedAt:         msg.CreatedAt,
+		UpdatedAt:         msg.CreatedAt,
+	}, nil
+}
+
+func toDomainMessage(row *dbMessage) (*conversation.Message, error) {
+	var content channels.MessageContent
+	if len(row.Content) > 0 {
+		if err := json.Unmarshal(row.Content, &content); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal content: %w", err)
+		}
+	}
+
+	var msgContext map[string]any
+	if len(row.Context) > 0 && string(row.Context) != "null" {
+		if err := json.Unmarshal(row.Context, &msgContext); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal context: %w", err)
+		}
+	}
+
+	return &conversation.Message{
+		ID:                row.ID,
+		TenantID:          kernel.TenantID(row.TenantID),
+		ChannelID:         kernel.ChannelID(row.ChannelID),
+		ConversationID:    row.ConversationID,
+		SenderID:          row.SenderID,
+		Direction:         conversation.Direction(row.Direction),
+		Origin:            conversation.Origin(row.Origin),
+		Content:           content,
+		Context:           msgContext,
+		Status:            conversation.MessageStatus(row.Status),
+		ProviderMessageID: row.ProviderMessageID.String,
+		WorkflowID:        row.WorkflowID.String,
+		NodeID:            row.NodeID.String,
+		CreatedAt:         row.CreatedAt,
+	}, nil
+}
+
+func (r *PostgresMessageRepository) Save(ctx context.Context, msg conversation.Message) error {
+	row, err := r.prepare(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	return r.insert(ctx, row)
+}
+
+func (r *PostgresMessageRepository) ListByConversation(ctx context.Context, req conversation.ListMessagesRequest) (conversation.MessageListResponse, error) {
+	conditions := []string{"tenant_id = $1", "conversation_id = $2"}
+	args := []any{req.TenantID.String(), req.ConversationID}
+	argPos := 3
+
+	if req.ChannelID != nil {
+		conditions = append(conditions, fmt.Sprintf("channel_id = $%d", argPos))
+		args = append(args, req.ChannelID.String())
+		argPos++
+	}
+	if cutoff := r.cutoff(ctx, req.TenantID); cutoff != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argPos))
+		args = append(args, *cutoff)
+		argPos++
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	db, err := r.conn(ctx, req.TenantID)
+	if err != nil {
+		return conversation.MessageListResponse{}, err
+	}
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM messages WHERE %s", whereClause)
+	if err := db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return conversation.MessageListResponse{}, errx.Wrap(err, "failed to count messages", errx.TypeInternal)
+	}
+
+	dataQuery := fmt.Sprintf(`
+		SELECT
+			id, tenant_id, channel_id, conversation_id, sender_id, direction, origin,
+			content, context, status, provider_message_id, workflow_id, node_id,
+			created_at, updated_at
+		FROM messages
+		WHERE %s
+		ORDER BY created_at ASC, id ASC
+		LIMIT $%d OFFSET $%d`,
+		whereClause, argPos, argPos+1)
+
+	args = append(args, req.PageSize, req.GetOffset())
+
+	var rows []dbMessage
+	if err := db.SelectContext(ctx, &rows, dataQuery, args...); err != nil {
+		return conversation.MessageListResponse{}, errx.Wrap(err, "failed to list messages", errx.TypeInternal)
+	}
+
+	messages := make([]conversation.Message, 0, len(rows))
+	for i := range rows {
+		if err := r.open(ctx, req.TenantID, &rows[i]); err != nil {
+			return conversation.MessageListResponse{}, errx.Wrap(err, "failed to decrypt message", errx.TypeInternal).
+				WithDetail("message_id", rows[i].ID)
+		}
+
+		msg, err := toDomainMessage(&rows[i])
+		if err != nil {
+			return conversation.MessageListResponse{}, errx.Wrap(err, "failed to convert message", errx.TypeInternal).
+				WithDetail("message_id", rows[i].ID)
+		}
+		messages = append(messages, *msg)
+	}
+
+	return storex.NewPaginated(messages, req.Page, req.PageSize, total), nil
+}
+
+func (r *PostgresMessageRepository) FindByProviderMessageID(
+	ctx context.Context,
+	tenantID kernel.TenantID,
+	channelID kernel.ChannelID,
+	providerMessageID string,
+) (*conversation.Message, error) {
+	query := `
+		SELECT
+			id, tenant_id, channel_id, conversation_id, sender_id, direction, origin,
+			content, context, status, provider_message_id, workflow_id, node_id,
+			created_at, updated_at
+		FROM messages
+		WHERE tenant_id = $1 AND channel_id = $2 AND provider_message_id = $3
+			AND created_at >= $4
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var row dbMessage
+	if err := db.GetContext(ctx, &row, query, tenantID.String(), channelID.String(), providerMessageID, r.lowerBound(ctx, tenantID)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, conversation.ErrMessageNotFound().WithDetail("provider_message_id", providerMessageID)
+		}
+		return nil, errx.Wrap(err, "failed to find message", errx.TypeInternal).
+			WithDetail("provider_message_id", providerMessageID)
+	}
+
+	if err := r.open(ctx, tenantID, &row); err != nil {
+		return nil, errx.Wrap(err, "failed to decrypt message", errx.TypeInternal).
+			WithDetail("message_id", row.ID)
+	}
+
+	msg, err := toDomainMessage(&row)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to convert message", errx.TypeInternal).
+			WithDetail("message_id", row.ID)
+	}
+
+	return msg, nil
+}
+
+func (r *PostgresMessageRepository) FindRawPayload(ctx context.Context, tenantID kernel.TenantID, messageID string) (map[string]any, error) {
+	query := `SELECT raw_payload FROM messages WHERE tenant_id = $1 AND id = $2 AND created_at >= $3`
+
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []byte
+	if err := db.GetContext(ctx, &raw, query, tenantID.String(), messageID, r.lowerBound(ctx, tenantID)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, conversation.ErrMessageNotFound().WithDetail("message_id", messageID)
+		}
+		return nil, errx.Wrap(err, "failed to find raw payload", errx.TypeInternal).
+			WithDetail("message_id", messageID)
+	}
+	if len(raw) == 0 {
+		return nil, conversation.ErrRawPayloadNotFound().WithDetail("message_id", messageID)
+	}
+
+	if r.cipher != nil {
+		decrypted, err := r.cipher.DecryptJSON(ctx, tenantID, raw)
+		if err != nil {
+			return nil, errx.Wrap(err, "failed to decrypt raw payload", errx.TypeInternal).
+				WithDetail("message_id", messageID)
+		}
+		raw = decrypted
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal raw payload", errx.TypeInternal).
+			WithDetail("message_id", messageID)
+	}
+
+	return payload, nil
+}
+
+func (r *PostgresMessageRepository) CountMatching(ctx context.Context, filter conversation.MessageFilter) (int, error) {
+	where, args := matchingConditions(r.bounded(ctx, filter))
+
+	db, err := r.conn(ctx, filter.TenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int
+	if err := db.GetContext(ctx, &total, "SELECT COUNT(*) FROM messages m WHERE "+where, args...); err != nil {
+		return 0, errx.Wrap(err, "failed to count messages", errx.TypeInternal)
+	}
+
+	return total, nil
+}
+
+func (r *PostgresMessageRepository) ListMatching(
+	ctx context.Context,
+	filter conversation.MessageFilter,
+	after *conversation.MessageCursor,
+	limit int,
+) ([]conversation.Message, error) {
+	where, args := matchingConditions(r.bounded(ctx, filter))
+	argPos := len(args) + 1
+
+	// Keyset pagination: offsets would rescan every page already exported
+	if after != nil {
+		where += fmt.Sprintf(" AND (m.channel_id, m.conversation_id, m.created_at, m.id) > ($%d, $%d, $%d, $%d)",
+			argPos, argPos+1, argPos+2, argPos+3)
+		args = append(args, after.ChannelID.String(), after.ConversationID, after.CreatedAt, after.ID)
+		argPos += 4
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			m.id, m.tenant_id, m.channel_id, m.conversation_id, m.sender_id, m.direction, m.origin,
+			m.content, m.context, m.status, m.provider_message_id, m.workflow_id, m.node_id,
+			m.created_at, m.updated_at
+		FROM messages m
+		WHERE %s
+		ORDER BY m.channel_id, m.conversation_id, m.created_at, m.id
+		LIMIT $%d`,
+		where, argPos)
+	args = append(args, limit)
+
+	db, err := r.conn(ctx, filter.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []dbMessage
+	if err := db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, errx.Wrap(err, "failed to list messages", errx.TypeInternal)
+	}
+
+	messages := make([]conversation.Message, 0, len(rows))
+	for i := range rows {
+		if err := r.open(ctx, filter.TenantID, &rows[i]); err != nil {
+			return nil, errx.Wrap(err, "failed to decrypt message", errx.TypeInternal).
+				WithDetail("message_id", rows[i].ID)
+		}
+
+		msg, err := toDomainMessage(&rows[i])
+		if err != nil {
+			return nil, errx.Wrap(err, "failed to convert message", errx.TypeInternal).
+				WithDetail("message_id", rows[i].ID)
+		}
+		messages = append(messages, *msg)
+	}
+
+	return messages, nil
+}
+
+// PurgeChannel deletes the channel's messages and conversation tags together
+func (r *PostgresMessageRepository) PurgeChannel(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID) error {
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errx.Wrap(err, "failed to begin transaction", errx.TypeInternal)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"messages", "conversation_tags"} {
+		query := `DELETE FROM ` + table + ` WHERE tenant_id = $1 AND channel_id = $2`
+		if _, err := tx.ExecContext(ctx, query, tenantID.String(), channelID.String()); err != nil {
+			return errx.Wrap(err, "failed to purge channel "+table, errx.TypeInternal).
+				WithDetail("channel_id", channelID.String())
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errx.Wrap(err, "failed to commit channel purge", errx.TypeInternal)
+	}
+	return nil
+}
+
+// conn is the database holding the tenant's messages
+func (r *PostgresMessageRepository) conn(ctx context.Context, tenantID kernel.TenantID) (*sqlx.DB, error) {
+	if r.router == nil {
+		return r.db, nil
+	}
+	db, err := r.router.For(ctx, tenantID)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to resolve tenant database", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+	return db, nil
+}
+
+// cutoff is the tenant's retention cutoff, nil when messages are kept forever
+func (r *PostgresMessageRepository) cutoff(ctx context.Context, tenantID kernel.TenantID) *time.Time {
+	if r.window == nil {
+		return nil
+	}
+	return r.window.MessageCutoff(ctx, tenantID)
+}
+
+// lowerBound is the cutoff for queries that always bound created_at; the zero
+// time when there is none
+func (r *PostgresMessageRepository) lowerBound(ctx context.Context, tenantID kernel.TenantID) time.Time {
+	if cutoff := r.cutoff(ctx, tenantID); cutoff != nil {
+		return *cutoff
+	}
+	return time.Time{}
+}
+
+// bounded raises the filter's From to the tenant's retention cutoff
+func (r *PostgresMessageRepository) bounded(ctx context.Context, filter conversation.MessageFilter) conversation.MessageFilter {
+	if cutoff := r.cutoff(ctx, filter.TenantID); cutoff != nil && (filter.From == nil || filter.From.Before(*cutoff)) {
+		filter.From = cutoff
+	}
+	return filter
+}
+
+// matchingConditions builds the WHERE clause of a MessageFilter over messages m
+func matchingConditions(filter conversation.MessageFilter) (string, []any) {
+	conditions := []string{"m.tenant_id = $1"}
+	args := []any{filter.TenantID.String()}
+	argPos := 2
+
+	if filter.ChannelID != nil {
+		conditions = append(conditions, fmt.Sprintf("m.channel_id = $%d", argPos))
+		args = append(args, filter.ChannelID.String())
+		argPos++
+	}
+	if filter.SenderID != "" {
+		conditions = append(conditions, fmt.Sprintf("m.sender_id = $%d", argPos))
+		args = append(args, filter.SenderID)
+		argPos++
+	}
+	if filter.From != nil {
+		conditions = append(conditions, fmt.Sprintf("m.created_at >= $%d", argPos))
+		args = append(args, *filter.From)
+		argPos++
+	}
+	if filter.To != nil {
+		conditions = append(conditions, fmt.Sprintf("m.created_at < $%d", argPos))
+		args = append(args, *filter.To)
+		argPos++
+	}
+	if len(filter.Tags) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`(
+			SELECT COUNT(DISTINCT t.tag) FROM conversation_tags t
+			WHERE t.tenant_id = m.tenant_id AND t.channel_id = m.channel_id
+				AND t.conversation_id = m.conversation_id
+				AND t.kind = 'TAG' AND t.tag = ANY($%d::text[])
+		) = $%d`, argPos, argPos+1))
+		args = append(args, pq.StringArray(filter.Tags), len(filter.Tags))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// messageColumns is the column order of inserts and COPY batches
+var messageColumns = []string{
+	"id", "tenant_id", "channel_id", "conversation_id", "sender_id", "direction", "origin",
+	"content", "context", "status", "provider_message_id", "workflow_id", "node_id",
+	"created_at", "updated_at", "raw_payload",
+}
+
+// prepare converts and encrypts a message into the row that gets stored
+func (r *PostgresMessageRepository) prepare(ctx context.Context, msg conversation.Message) (*dbMessage, error) {
+	row, err := toDBMessage(msg)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to convert message", errx.TypeInternal).
+			WithDetail("message_id", msg.ID)
+	}
+
+	if err := r.seal(ctx, msg.TenantID, row); err != nil {
+		return nil, conversation.ErrMessagePersistenceFailed().
+			WithDetail("message_id", msg.ID).
+			WithCause(err)
+	}
+
+	return row, nil
+}
+
+// insert stores a single prepared row
+func (r *PostgresMessageRepository) insert(ctx context.Context, row *dbMessage) error {
+	query := fmt.Sprintf(`
+		INSERT INTO messages (%s)
+		VALUES (:%s)`,
+		strings.Join(messageColumns, ", "), strings.Join(messageColumns, ", :"))
+
+	db, err := r.conn(ctx, kernel.TenantID(row.TenantID))
+	if err != nil {
+		return conversation.ErrMessagePersistenceFailed().
+			WithDetail("message_id", row.ID).
+			WithCause(err)
+	}
+
+	if _, err := db.NamedExecContext(ctx, query, row); err != nil {
+		return conversation.ErrMessagePersistenceFailed().
+			WithDetail("message_id", row.ID).
+			WithCause(err)
+	}
+
+	return nil
+}
+
+// copyRows stores prepared rows with a single COPY in one transaction. Either
+// every row is stored or none is. Every row must belong to db.
+func (r *PostgresMessageRepository) copyRows(ctx context.Context, db *sqlx.DB, rows []*dbMessage) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("messages", messageColumns...))
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		// JSON goes as text; lib/pq would send []byte as bytea
+		_, err := stmt.ExecContext(ctx,
+			row.ID, row.TenantID, row.ChannelID, row.ConversationID, row.SenderID, row.Direction, row.Origin,
+			string(row.Content), string(row.Context), row.Status, row.ProviderMessageID, row.WorkflowID, row.NodeID,
+			row.CreatedAt, row.UpdatedAt, nullJSON(row.RawPayload),
+		)
+		if err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return err
+	}
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// seal encrypts the content, context and raw payload columns in place
+func (r *PostgresMessageRepository) seal(ctx context.Context, tenantID kernel.TenantID, row *dbMessage) error {
+	if r.cipher == nil {
+		return nil
+	}
+
+	content, err := r.cipher.EncryptJSON(ctx, tenantID, row.Content)
+	if err != nil {
+		return err
+	}
+	msgContext, err := r.cipher.EncryptJSON(ctx, tenantID, row.Context)
+	if err != nil {
+		return err
+	}
+
+	if len(row.RawPayload) > 0 {
+		rawPayload, err := r.cipher.EncryptJSON(ctx, tenantID, row.RawPayload)
+		if err != nil {
+			return err
+		}
+		row.RawPayload = rawPayload
+	}
+
+	row.Content, row.Context = content, msgContext
+	return nil
+}
+
+// open decrypts the content and context columns in place; plaintext passes through
+func (r *PostgresMessageRepository) open(ctx context.Context, tenantID kernel.TenantID, row *dbMessage) error {
+	if r.cipher == nil {
+		return nil
+	}
+
+	content, err := r.cipher.DecryptJSON(ctx, tenantID, row.Content)
+	if err != nil {
+		return err
+	}
+	msgContext, err := r.cipher.DecryptJSON(ctx, tenantID, row.Context)
+	if err != nil {
+		return err
+	}
+
+	row.Content, row.Context = content, msgContext
+	return nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullJSON passes an absent JSON column to COPY as NULL
+func nullJSON(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
@@ -0,0 +1,286 @@
+package conversationinfra
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// archiveRowGroupSize is how many messages are buffered per Parquet row group
+const archiveRowGroupSize = 5000
+
+// PostgresPartitionStore manages the monthly partitions of the messages
+// table through the create_message_partition() function of the migrations.
+// With a router every database holding messages is maintained: each has its
+// own partitions, and a month is listed when any of them has it.
+type PostgresPartitionStore struct {
+	db     *sqlx.DB
+	router *database.Router // nil = every tenant's messages are in db
+}
+
+var _ conversation.PartitionStore = (*PostgresPartitionStore)(nil)
+
+func NewPostgresPartitionStore(db *sqlx.DB) *PostgresPartitionStore {
+	return &PostgresPartitionStore{db: db}
+}
+
+// SetRouter maintains the partitions of every data region
+func (s *PostgresPartitionStore) SetRouter(router *database.Router) {
+	s.router = router
+}
+
+// dbArchivedMessage is a stored message with every column, as archived
+type dbArchivedMessage struct {
+	dbMessage
+	RedactedAt sql.NullTime `db:"redacted_at"`
+}
+
+// archiveColumns is the schema of archive files. Content, context and raw
+// payload are kept as stored, encrypted for tenants with encryption enabled.
+var archiveColumns = []parquetColumn{
+	{Name: "id", Kind: parquetString},
+	{Name: "tenant_id", Kind: parquetString},
+	{Name: "channel_id", Kind: parquetString},
+	{Name: "conversation_id", Kind: parquetString},
+	{Name: "sender_id", Kind: parquetString},
+	{Name: "direction", Kind: parquetString},
+	{Name: "origin", Kind: parquetString},
+	{Name: "content", Kind: parquetJSON},
+	{Name: "context", Kind: parquetJSON},
+	{Name: "raw_payload", Kind: parquetJSON},
+	{Name: "status", Kind: parquetString},
+	{Name: "provider_message_id", Kind: parquetString},
+	{Name: "workflow_id", Kind: parquetString},
+	{Name: "node_id", Kind: parquetString},
+	{Name: "created_at", Kind: parquetTimestamp},
+	{Name: "updated_at", Kind: parquetTimestamp},
+	{Name: "redacted_at", Kind: parquetTimestamp},
+}
+
+// values returns the row in archiveColumns order
+func (row *dbArchivedMessage) values() []any {
+	return []any{
+		row.ID, row.TenantID, row.ChannelID, row.ConversationID, row.SenderID, row.Direction, row.Origin,
+		[]byte(row.Content), []byte(row.Context), []byte(row.RawPayload), row.Status,
+		nullValue(row.ProviderMessageID), nullValue(row.WorkflowID), nullValue(row.NodeID),
+		row.CreatedAt, row.UpdatedAt, nullTimeValue(row.RedactedAt),
+	}
+}
+
+func (s *PostgresPartitionStore) EnsurePartitions(ctx context.Context, until time.Time) error {
+	for _, db := range s.databases() {
+		for month := conversation.MonthOf(time.Now()); !month.After(until); month = month.AddDate(0, 1, 0) {
+			if _, err := db.ExecContext(ctx, `SELECT create_message_partition($1::date)`, month.Format("2006-01-02")); err != nil {
+				return errx.Wrap(err, "failed to create message partition", errx.TypeInternal).
+					WithDetail("partition", conversation.PartitionName(month))
+			}
+		}
+	}
+	return nil
+}
+
+func (s *PostgresPartitionStore) ListPartitions(ctx context.Context) ([]time.Time, error) {
+	query := `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'messages'::regclass
+		ORDER BY c.relname`
+
+	var months []time.Time
+	for _, db := range s.databases() {
+		var names []string
+		if err := db.SelectContext(ctx, &names, query); err != nil {
+			return nil, errx.Wrap(err, "failed to list message partitions", errx.TypeInternal)
+		}
+
+		for _, name := range names {
+			if month, ok := conversation.ParsePartitionName(name); ok && !slices.ContainsFunc(months, month.Equal) {
+				months = append(months, month)
+			}
+		}
+	}
+
+	slices.SortFunc(months, func(a, b time.Time) int { return a.Compare(b) })
+	return months, nil
+}
+
+func (s *PostgresPartitionStore) PartitionTenants(ctx context.Context, month time.Time) ([]kernel.TenantID, error) {
+	query := fmt.Sprintf(`SELECT DISTINCT tenant_id FROM %s`, pq.QuoteIdentifier(conversation.PartitionName(month)))
+
+	var tenantIDs []kernel.TenantID
+	for _, db := range s.databases() {
+		exists, err := partitionExists(ctx, db, month)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+
+		var ids []kernel.TenantID
+		if err := db.SelectContext(ctx, &ids, query); err != nil {
+			return nil, errx.Wrap(err, "failed to list partition tenants", errx.TypeInternal).
+				WithDetail("partition", conversation.PartitionName(month))
+		}
+		tenantIDs = append(tenantIDs, ids...)
+	}
+	return tenantIDs, nil
+}
+
+func (s *PostgresPartitionStore) ExportPartition(
+	ctx context.Context,
+	month time.Time,
+	tenantID kernel.TenantID,
+	since *time.Time,
+	w io.Writer,
+) (int, error) {
+	from := conversation.MonthOf(month)
+	if since != nil && since.After(from) {
+		from = *since
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			id, tenant_id, channel_id, conversation_id, sender_id, direction, origin,
+			content, context, raw_payload, status, provider_message_id, workflow_id, node_id,
+			created_at, updated_at, redacted_at
+		FROM %s
+		WHERE tenant_id = $1 AND created_at >= $2
+		ORDER BY created_at, id`,
+		pq.QuoteIdentifier(conversation.PartitionName(month)))
+
+	db, err := s.conn(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := db.QueryxContext(ctx, query, tenantID.String(), from)
+	if err != nil {
+		return 0, errx.Wrap(err, "failed to read message partition", errx.TypeInternal).
+			WithDetail("partition", conversation.PartitionName(month))
+	}
+	defer rows.Close()
+
+	writer, err := newParquetWriter(w, archiveColumns, archiveRowGroupSize)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for rows.Next() {
+		var row dbArchivedMessage
+		if err := rows.StructScan(&row); err != nil {
+			return count, errx.Wrap(err, "failed to scan message", errx.TypeInternal)
+		}
+		if err := writer.Write(row.values()); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, errx.Wrap(err, "failed to read message partition", errx.TypeInternal).
+			WithDetail("partition", conversation.PartitionName(month))
+	}
+
+	return count, writer.Close()
+}
+
+// DropPartition drops the month's partition from every database that has it
+func (s *PostgresPartitionStore) DropPartition(ctx context.Context, month time.Time) error {
+	for _, db := range s.databases() {
+		exists, err := partitionExists(ctx, db, month)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if err := dropPartition(ctx, db, month); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// databases are the databases holding messages
+func (s *PostgresPartitionStore) databases() []*sqlx.DB {
+	if s.router == nil {
+		return []*sqlx.DB{s.db}
+	}
+	return s.router.All()
+}
+
+// conn is the database holding the tenant's messages
+func (s *PostgresPartitionStore) conn(ctx context.Context, tenantID kernel.TenantID) (*sqlx.DB, error) {
+	if s.router == nil {
+		return s.db, nil
+	}
+	db, err := s.router.For(ctx, tenantID)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to resolve tenant database", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+	return db, nil
+}
+
+// partitionExists reports whether db has the month's partition; a data region
+// added later lacks the months before it
+func partitionExists(ctx context.Context, db *sqlx.DB, month time.Time) (bool, error) {
+	var exists bool
+	if err := db.GetContext(ctx, &exists, `SELECT to_regclass($1) IS NOT NULL`, conversation.PartitionName(month)); err != nil {
+		return false, errx.Wrap(err, "failed to look up message partition", errx.TypeInternal).
+			WithDetail("partition", conversation.PartitionName(month))
+	}
+	return exists, nil
+}
+
+func dropPartition(ctx context.Context, db *sqlx.DB, month time.Time) error {
+	name := pq.QuoteIdentifier(conversation.PartitionName(month))
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errx.Wrap(err, "failed to begin transaction", errx.TypeInternal)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE messages DETACH PARTITION %s`, name)); err != nil {
+		return errx.Wrap(err, "failed to detach message partition", errx.TypeInternal).
+			WithDetail("partition", conversation.PartitionName(month))
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DROP TABLE %s`, name)); err != nil {
+		return errx.Wrap(err, "failed to drop message partition", errx.TypeInternal).
+			WithDetail("partition", conversation.PartitionName(month))
+	}
+
+	return tx.Commit()
+}
+
+// nullValue is a NULL column as a nil archive value
+func nullValue(s sql.NullString) any {
+	if !s.Valid {
+		return nil
+	}
+	return s.String
+}
+
+func nullTimeValue(t sql.NullTime) any {
+	if !t.Valid {
+		return nil
+	}
+	return t.Time
+}
@@ -0,0 +1,310 @@
+package conversationinfra
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PostgresTagRepository struct {
+	db     *sqlx.DB
+	router *database.Router // nil = every tenant's tags are in db
+}
+
+var _ conversation.TagRepository = (*PostgresTagRepository)(nil)
+
+func NewPostgresTagRepository(db *sqlx.DB) *PostgresTagRepository {
+	return &PostgresTagRepository{db: db}
+}
+
+// SetRouter keeps each tenant's tags in the database of its data region, next
+// to the messages they are filtered with
+func (r *PostgresTagRepository) SetRouter(router *database.Router) {
+	r.router = router
+}
+
+// dbTag is an intermediate struct for database operations
+type dbTag struct {
+	TenantID       string    `db:"tenant_id"`
+	ChannelID      string    `db:"channel_id"`
+	ConversationID string    `db:"conversation_id"`
+	Tag            string    `db:"tag"`
+	Kind           string    `db:"kind"`
+	Source         string    `db:"source"`
+	SetBy          string    `db:"set_by"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+const tagColumns = `tenant_id, channel_id, conversation_id, tag, kind, source, set_by, created_at`
+
+// Add stores tags of a single tenant
+func (r *PostgresTagRepository) Add(ctx context.Context, tags []conversation.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	db, err := r.conn(ctx, tags[0].TenantID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errx.Wrap(err, "failed to begin transaction", errx.TypeInternal)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO conversation_tags (` + tagColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (tenant_id, channel_id, conversation_id, kind, tag) DO NOTHING`
+
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, query,
+			tag.TenantID.String(), tag.ChannelID.String(), tag.ConversationID, tag.Name,
+			string(conversation.TagKindTag), string(tag.Source), tag.SetBy, tag.CreatedAt,
+		); err != nil {
+			return errx.Wrap(err, "failed to add conversation tag", errx.TypeInternal).
+				WithDetail("tag", tag.Name)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errx.Wrap(err, "failed to commit conversation tags", errx.TypeInternal)
+	}
+
+	return nil
+}
+
+func (r *PostgresTagRepository) SetDisposition(ctx context.Context, disposition conversation.Tag) error {
+	// The partial unique index keeps one disposition per conversation
+	query := `
+		INSERT INTO conversation_tags (` + tagColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (tenant_id, channel_id, conversation_id) WHERE kind = 'DISPOSITION' DO UPDATE SET
+			tag = EXCLUDED.tag,
+			source = EXCLUDED.source,
+			set_by = EXCLUDED.set_by,
+			created_at = EXCLUDED.created_at`
+
+	db, err := r.conn(ctx, disposition.TenantID)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, query,
+		disposition.TenantID.String(), disposition.ChannelID.String(), disposition.ConversationID, disposition.Name,
+		string(conversation.TagKindDisposition), string(disposition.Source), disposition.SetBy, disposition.CreatedAt,
+	)
+	if err != nil {
+		return errx.Wrap(err, "failed to set conversation disposition", errx.TypeInternal).
+			WithDetail("disposition", disposition.Name)
+	}
+
+	return nil
+}
+
+func (r *PostgresTagRepository) Remove(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID, name string) (bool, error) {
+	query := `
+		DELETE FROM conversation_tags
+		WHERE tenant_id = $1 AND channel_id = $2 AND conversation_id = $3 AND tag = $4`
+
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := db.ExecContext(ctx, query, tenantID.String(), channelID.String(), conversationID, name)
+	if err != nil {
+		return false, errx.Wrap(err, "failed to remove conversation tag", errx.TypeInternal).
+			WithDetail("tag", name)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *PostgresTagRepository) ListByConversation(ctx context.Context, tenantID kernel.TenantID, channelID *kernel.ChannelID, conversationID string) ([]conversation.Tag, error) {
+	query := `SELECT ` + tagColumns + ` FROM conversation_tags WHERE tenant_id = $1 AND conversation_id = $2`
+	args := []any{tenantID.String(), conversationID}
+	if channelID != nil {
+		query += ` AND channel_id = $3`
+		args = append(args, channelID.String())
+	}
+	query += ` ORDER BY kind DESC, created_at ASC`
+
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []dbTag
+	if err := db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, errx.Wrap(err, "failed to list conversation tags", errx.TypeInternal).
+			WithDetail("conversation_id", conversationID)
+	}
+
+	tags := make([]conversation.Tag, len(rows))
+	for i, row := range rows {
+		tags[i] = conversation.Tag{
+			TenantID:       kernel.TenantID(row.TenantID),
+			ChannelID:      kernel.ChannelID(row.ChannelID),
+			ConversationID: row.ConversationID,
+			Name:           row.Tag,
+			Kind:           conversation.TagKind(row.Kind),
+			Source:         conversation.TagSource(row.Source),
+			SetBy:          row.SetBy,
+			CreatedAt:      row.CreatedAt,
+		}
+	}
+
+	return tags, nil
+}
+
+// dbTaggedConversation is one conversation with its tags aggregated
+type dbTaggedConversation struct {
+	ChannelID      string         `db:"channel_id"`
+	ConversationID string         `db:"conversation_id"`
+	Tags           pq.StringArray `db:"tags"`
+	Disposition    string         `db:"disposition"`
+	LastTaggedAt   time.Time      `db:"last_tagged_at"`
+}
+
+func (r *PostgresTagRepository) ListConversations(ctx context.Context, req conversation.ListTaggedRequest) (conversation.TaggedConversationListResponse, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.ChannelID != nil {
+		conditions = append(conditions, fmt.Sprintf("channel_id = $%d", argPos))
+		args = append(args, req.ChannelID.String())
+		argPos++
+	}
+
+	// Filters apply to the aggregated tags, so the response lists every tag
+	// of a matching conversation
+	having := []string{"TRUE"}
+	if len(req.Tags) > 0 {
+		having = append(having, fmt.Sprintf("COALESCE(array_agg(tag) FILTER (WHERE kind = 'TAG'), '{}') @> $%d::text[]", argPos))
+		args = append(args, pq.StringArray(req.Tags))
+		argPos++
+	}
+	if req.Disposition != "" {
+		having = append(having, fmt.Sprintf("MAX(tag) FILTER (WHERE kind = 'DISPOSITION') = $%d", argPos))
+		args = append(args, req.Disposition)
+		argPos++
+	}
+
+	grouped := fmt.Sprintf(`
+		SELECT
+			channel_id,
+			conversation_id,
+			COALESCE(array_agg(tag ORDER BY tag) FILTER (WHERE kind = 'TAG'), '{}') AS tags,
+			COALESCE(MAX(tag) FILTER (WHERE kind = 'DISPOSITION'), '') AS disposition,
+			MAX(created_at) AS last_tagged_at
+		FROM conversation_tags
+		WHERE %s
+		GROUP BY channel_id, conversation_id
+		HAVING %s`,
+		strings.Join(conditions, " AND "), strings.Join(having, " AND "))
+
+	db, err := r.conn(ctx, req.TenantID)
+	if err != nil {
+		return conversation.TaggedConversationListResponse{}, err
+	}
+
+	var total int
+	if err := db.GetContext(ctx, &total, `SELECT COUNT(*) FROM (`+grouped+`) tagged`, args...); err != nil {
+		return conversation.TaggedConversationListResponse{}, errx.Wrap(err, "failed to count tagged conversations", errx.TypeInternal)
+	}
+
+	dataQuery := fmt.Sprintf(`%s
+		ORDER BY last_tagged_at DESC, conversation_id
+		LIMIT $%d OFFSET $%d`, grouped, argPos, argPos+1)
+	args = append(args, req.PageSize, req.GetOffset())
+
+	var rows []dbTaggedConversation
+	if err := db.SelectContext(ctx, &rows, dataQuery, args...); err != nil {
+		return conversation.TaggedConversationListResponse{}, errx.Wrap(err, "failed to list tagged conversations", errx.TypeInternal)
+	}
+
+	conversations := make([]conversation.TaggedConversation, len(rows))
+	for i, row := range rows {
+		conversations[i] = conversation.TaggedConversation{
+			ChannelID:      kernel.ChannelID(row.ChannelID),
+			ConversationID: row.ConversationID,
+			Tags:           []string(row.Tags),
+			Disposition:    row.Disposition,
+			LastTaggedAt:   row.LastTaggedAt,
+		}
+	}
+
+	return storex.NewPaginated(conversations, req.Page, req.PageSize, total), nil
+}
+
+func (r *PostgresTagRepository) Stats(ctx context.Context, req conversation.TagStatsRequest) ([]conversation.TagCount, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.ChannelID != nil {
+		conditions = append(conditions, fmt.Sprintf("channel_id = $%d", argPos))
+		args = append(args, req.ChannelID.String())
+		argPos++
+	}
+	if req.From != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argPos))
+		args = append(args, *req.From)
+		argPos++
+	}
+	if req.To != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", argPos))
+		args = append(args, *req.To)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT tag AS name, kind, COUNT(*) AS conversations
+		FROM conversation_tags
+		WHERE %s
+		GROUP BY tag, kind
+		ORDER BY conversations DESC, tag ASC`,
+		strings.Join(conditions, " AND "))
+
+	db, err := r.conn(ctx, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []conversation.TagCount
+	if err := db.SelectContext(ctx, &counts, query, args...); err != nil {
+		return nil, errx.Wrap(err, "failed to aggregate conversation tags", errx.TypeInternal)
+	}
+
+	return counts, nil
+}
+
+// conn is the database holding the tenant's tags
+func (r *PostgresTagRepository) conn(ctx context.Context, tenantID kernel.TenantID) (*sqlx.DB, error) {
+	if r.router == nil {
+		return r.db, nil
+	}
+	db, err := r.router.For(ctx, tenantID)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to resolve tenant database", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+	return db, nil
+}
@@ -0,0 +1,265 @@
+package conversationsrv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Abraxas-365/relay/attachment"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ArchiveJobKind is the recurring system job that maintains the message
+// partitions and archives old months
+const ArchiveJobKind = "message.archive"
+
+const (
+	// ArchiveTimeout bounds a single archive run
+	ArchiveTimeout = 2 * time.Hour
+
+	// partitionsAhead is how many months past the current one always have a
+	// partition, so inserts never fall into the default one
+	partitionsAhead = 2
+)
+
+// ArchiveService keeps the messages table split into monthly partitions and
+// moves old months out of the database: each tenant's messages of the month
+// are written as a Parquet file to the attachment storage, then the month's
+// partition is dropped. Messages past the tenant's retention are left out,
+// and archives whose whole month expired are deleted.
+type ArchiveService struct {
+	partitions   conversation.PartitionStore
+	archiveRepo  conversation.ArchiveRepository
+	window       conversation.RetentionWindow
+	storage      attachment.Storage // nil = partitions are maintained but never archived
+	archiveAfter time.Duration      // 0 = partitions are maintained but never archived
+	linkTTL      time.Duration
+}
+
+var _ tenant.LifecycleHook = (*ArchiveService)(nil)
+
+func NewArchiveService(
+	partitions conversation.PartitionStore,
+	archiveRepo conversation.ArchiveRepository,
+	window conversation.RetentionWindow,
+	storage attachment.Storage,
+	archiveAfter time.Duration,
+	linkTTL time.Duration,
+) *ArchiveService {
+	return &ArchiveService{
+		partitions:   partitions,
+		archiveRepo:  archiveRepo,
+		window:       window,
+		storage:      storage,
+		archiveAfter: archiveAfter,
+		linkTTL:      linkTTL,
+	}
+}
+
+// Enabled reports whether old months are archived
+func (s *ArchiveService) Enabled() bool {
+	return s.storage != nil && s.archiveAfter > 0
+}
+
+// ============================================================================
+// Archives
+// ============================================================================
+
+// ListArchives returns the tenant's archived months, newest first
+func (s *ArchiveService) ListArchives(ctx context.Context, tenantID kernel.TenantID) ([]conversation.Archive, error) {
+	return s.archiveRepo.ListByTenant(ctx, tenantID)
+}
+
+// GetArchive returns an archive with a signed link to its file
+func (s *ArchiveService) GetArchive(ctx context.Context, id string, tenantID kernel.TenantID) (*conversation.ArchiveResponse, error) {
+	archive, err := s.archiveRepo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &conversation.ArchiveResponse{Archive: archive}
+	if s.storage == nil {
+		return response, nil
+	}
+
+	url, err := s.storage.SignedURL(ctx, archive.StorageKey, s.linkTTL)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(s.linkTTL)
+	response.URL = url
+	response.URLExpiresAt = &expiresAt
+
+	return response, nil
+}
+
+// OnTenantLifecycle implements tenant.LifecycleHook: deleting a tenant
+// deletes its archive files too
+func (s *ArchiveService) OnTenantLifecycle(ctx context.Context, tenantID kernel.TenantID, event tenant.LifecycleEvent) error {
+	if event != tenant.LifecycleDeleted || s.storage == nil {
+		return nil
+	}
+
+	archives, err := s.archiveRepo.ListByTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	for _, archive := range archives {
+		if err := s.storage.Delete(ctx, archive.StorageKey); err != nil {
+			log.Printf("⚠️  Failed to delete message archive %s: %v", archive.StorageKey, err)
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// Jobs
+// ============================================================================
+
+// RunArchiveJob is the handler of ArchiveJobKind. Upcoming partitions are
+// created first; a month that fails to archive keeps its partition and is
+// tried again on the next run.
+func (s *ArchiveService) RunArchiveJob(ctx context.Context, _ jobs.Job) error {
+	now := time.Now()
+	if err := s.partitions.EnsurePartitions(ctx, conversation.MonthOf(now).AddDate(0, partitionsAhead, 0)); err != nil {
+		return err
+	}
+	if !s.Enabled() {
+		return nil
+	}
+
+	months, err := s.partitions.ListPartitions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Only months that ended before the threshold are archived
+	threshold := now.Add(-s.archiveAfter)
+	for _, month := range months {
+		if conversation.NextMonth(month).After(threshold) {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.archiveMonth(ctx, month); err != nil {
+			log.Printf("⚠️  Failed to archive messages of %s: %v", month.Format("2006-01"), err)
+		}
+	}
+
+	return s.expireArchives(ctx)
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// archiveMonth writes every tenant's file of the month, then drops the
+// partition. A failure leaves the partition in place; files already written
+// are replaced when it is tried again.
+func (s *ArchiveService) archiveMonth(ctx context.Context, month time.Time) error {
+	tenantIDs, err := s.partitions.PartitionTenants(ctx, month)
+	if err != nil {
+		return err
+	}
+
+	archived := 0
+	for _, tenantID := range tenantIDs {
+		archive, err := s.archiveTenant(ctx, month, tenantID)
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+		if archive != nil {
+			archived += archive.MessageCount
+		}
+	}
+
+	if err := s.partitions.DropPartition(ctx, month); err != nil {
+		return err
+	}
+
+	log.Printf("🗄️  Archived %d messages of %s from %d tenants, partition dropped",
+		archived, month.Format("2006-01"), len(tenantIDs))
+	return nil
+}
+
+// archiveTenant writes the tenant's file of the month aside, then stores it.
+// Nil when every message of the month is past the tenant's retention.
+func (s *ArchiveService) archiveTenant(ctx context.Context, month time.Time, tenantID kernel.TenantID) (*conversation.Archive, error) {
+	file, err := os.CreateTemp("", "relay-archive-*.parquet")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	count, err := s.partitions.ExportPartition(ctx, month, tenantID, s.window.MessageCutoff(ctx, tenantID), file)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	archive := conversation.NewArchive(tenantID, month, count, size)
+	if err := s.storage.Put(ctx, archive.StorageKey, file, size, conversation.ArchiveContentType); err != nil {
+		return nil, fmt.Errorf("failed to store message archive: %w", err)
+	}
+	if err := s.archiveRepo.Save(ctx, *archive); err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// expireArchives deletes the archives whose whole month is past their
+// tenant's retention. One tenant failing does not hold back the others.
+func (s *ArchiveService) expireArchives(ctx context.Context) error {
+	tenantIDs, err := s.archiveRepo.ListTenants(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, tenantID := range tenantIDs {
+		cutoff := s.window.MessageCutoff(ctx, tenantID)
+		if cutoff == nil {
+			continue
+		}
+
+		archives, err := s.archiveRepo.ListByTenant(ctx, tenantID)
+		if err != nil {
+			log.Printf("⚠️  Failed to list message archives of tenant %s: %v", tenantID, err)
+			continue
+		}
+		for _, archive := range archives {
+			if !archive.ExpiredBy(*cutoff) {
+				continue
+			}
+			if err := s.storage.Delete(ctx, archive.StorageKey); err != nil {
+				log.Printf("⚠️  Failed to delete message archive %s: %v", archive.StorageKey, err)
+				continue
+			}
+			if err := s.archiveRepo.Delete(ctx, archive.ID); err != nil {
+				log.Printf("⚠️  Failed to delete message archive %s: %v", archive.ID, err)
+				continue
+			}
+			log.Printf("🗑️  Message archive %s of tenant %s expired", archive.Month.Format("2006-01"), tenantID)
+		}
+	}
+
+	return nil
+}
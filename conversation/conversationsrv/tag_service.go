@@ -0,0 +1,228 @@
+package conversationsrv
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// TagService applies tags and dispositions to conversations for workflows
+// and operators, and reports on them
+type TagService struct {
+	repo conversation.TagRepository
+}
+
+var _ engine.ConversationTagger = (*TagService)(nil)
+
+func NewTagService(repo conversation.TagRepository) *TagService {
+	return &TagService{repo: repo}
+}
+
+// ============================================================================
+// Tagging
+// ============================================================================
+
+// TagConversation implements engine.ConversationTagger. Removals apply
+// before additions, so an update can swap one tag for another; removing a
+// tag the conversation does not have is not an error.
+func (s *TagService) TagConversation(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string, update engine.TagUpdate) (*engine.ConversationTags, error) {
+	if channelID == "" || conversationID == "" {
+		return nil, conversation.ErrInvalidConversationID().WithDetail("reason", "channel_id and conversation_id are required")
+	}
+	source := conversation.TagSource(update.Source)
+	if !source.IsValid() {
+		return nil, conversation.ErrInvalidTag().WithDetail("source", update.Source)
+	}
+
+	remove, err := normalizeTags(update.Remove)
+	if err != nil {
+		return nil, err
+	}
+	add, err := normalizeTags(update.Add)
+	if err != nil {
+		return nil, err
+	}
+	var disposition string
+	if update.Disposition != "" {
+		if disposition, err = conversation.NormalizeTag(update.Disposition); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range remove {
+		if _, err := s.repo.Remove(ctx, tenantID, channelID, conversationID, name); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	if len(add) > 0 {
+		current, err := s.repo.ListByConversation(ctx, tenantID, &channelID, conversationID)
+		if err != nil {
+			return nil, err
+		}
+		if countTags(current, add) > conversation.MaxTagsPerConversation {
+			return nil, conversation.ErrTooManyTags().
+				WithDetail("conversation_id", conversationID).
+				WithDetail("max", conversation.MaxTagsPerConversation)
+		}
+
+		tags := make([]conversation.Tag, len(add))
+		for i, name := range add {
+			tags[i] = s.newTag(tenantID, channelID, conversationID, name, conversation.TagKindTag, source, update.SetBy, now)
+		}
+		if err := s.repo.Add(ctx, tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if disposition != "" {
+		tag := s.newTag(tenantID, channelID, conversationID, disposition, conversation.TagKindDisposition, source, update.SetBy, now)
+		if err := s.repo.SetDisposition(ctx, tag); err != nil {
+			return nil, err
+		}
+	}
+
+	if !update.IsEmpty() {
+		log.Printf("🏷️  Conversation %s tagged by %s (+%v -%v disposition=%q)", conversationID, source, add, remove, disposition)
+	}
+
+	current, err := s.repo.ListByConversation(ctx, tenantID, &channelID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	return summarize(current), nil
+}
+
+// Remove deletes one tag or the disposition; ErrTagNotFound when the
+// conversation does not have it
+func (s *TagService) Remove(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID, name string) error {
+	normalized, err := conversation.NormalizeTag(name)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.repo.Remove(ctx, tenantID, channelID, conversationID, normalized)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return conversation.ErrTagNotFound().
+			WithDetail("conversation_id", conversationID).
+			WithDetail("tag", normalized)
+	}
+	return nil
+}
+
+// ============================================================================
+// Queries
+// ============================================================================
+
+// Tags returns a conversation's tags, or a contact's across channels when
+// channelID is nil
+func (s *TagService) Tags(ctx context.Context, tenantID kernel.TenantID, channelID *kernel.ChannelID, conversationID string) ([]conversation.Tag, error) {
+	return s.repo.ListByConversation(ctx, tenantID, channelID, conversationID)
+}
+
+// ListConversations finds conversations by tag and disposition
+func (s *TagService) ListConversations(ctx context.Context, req conversation.ListTaggedRequest) (conversation.TaggedConversationListResponse, error) {
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return conversation.TaggedConversationListResponse{}, err
+	}
+	req.Tags = tags
+
+	if req.Disposition != "" {
+		if req.Disposition, err = conversation.NormalizeTag(req.Disposition); err != nil {
+			return conversation.TaggedConversationListResponse{}, err
+		}
+	}
+
+	return s.repo.ListConversations(ctx, req)
+}
+
+// Stats counts the conversations that got each tag and disposition in the
+// period
+func (s *TagService) Stats(ctx context.Context, req conversation.TagStatsRequest) (*conversation.TagStats, error) {
+	counts, err := s.repo.Stats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &conversation.TagStats{
+		Tags:         []conversation.TagCount{},
+		Dispositions: []conversation.TagCount{},
+	}
+	for _, count := range counts {
+		if count.Kind == conversation.TagKindDisposition {
+			stats.Dispositions = append(stats.Dispositions, count)
+		} else {
+			stats.Tags = append(stats.Tags, count)
+		}
+	}
+	return stats, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func (s *TagService) newTag(tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID, name string, kind conversation.TagKind, source conversation.TagSource, setBy string, at time.Time) conversation.Tag {
+	return conversation.Tag{
+		TenantID:       tenantID,
+		ChannelID:      channelID,
+		ConversationID: conversationID,
+		Name:           name,
+		Kind:           kind,
+		Source:         source,
+		SetBy:          setBy,
+		CreatedAt:      at,
+	}
+}
+
+// normalizeTags normalizes and dedupes tag names
+func normalizeTags(names []string) ([]string, error) {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		tag, err := conversation.NormalizeTag(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// countTags is how many tags the conversation would carry after adding
+func countTags(current []conversation.Tag, add []string) int {
+	names := make(map[string]bool, len(current)+len(add))
+	for _, tag := range current {
+		if tag.Kind == conversation.TagKindTag {
+			names[tag.Name] = true
+		}
+	}
+	for _, name := range add {
+		names[name] = true
+	}
+	return len(names)
+}
+
+func summarize(tags []conversation.Tag) *engine.ConversationTags {
+	summary := &engine.ConversationTags{Tags: []string{}}
+	for _, tag := range tags {
+		if tag.Kind == conversation.TagKindDisposition {
+			summary.Disposition = tag.Name
+		} else {
+			summary.Tags = append(summary.Tags, tag.Name)
+		}
+	}
+	return summary
+}
@@ -0,0 +1,149 @@
+package conversation
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// List Request DTOs
+// ============================================================================
+
+// ListMessagesRequest request to page through a conversation transcript
+type ListMessagesRequest struct {
+	storex.PaginationOptions
+
+	TenantID       kernel.TenantID   `json:"tenant_id" validate:"required"`
+	ConversationID string            `json:"conversation_id" validate:"required"`
+	ChannelID      *kernel.ChannelID `json:"channel_id,omitempty"`
+}
+
+func (r ListMessagesRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+// ListTaggedRequest request to find conversations by tag. A conversation
+// must carry all Tags and, when set, the Disposition.
+type ListTaggedRequest struct {
+	storex.PaginationOptions
+
+	TenantID    kernel.TenantID   `json:"tenant_id" validate:"required"`
+	ChannelID   *kernel.ChannelID `json:"channel_id,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Disposition string            `json:"disposition,omitempty"`
+}
+
+func (r ListTaggedRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+// MessageFilter selects messages across conversations, for bulk reads like
+// transcript exports. A conversation matches Tags when it carries all of them.
+type MessageFilter struct {
+	TenantID  kernel.TenantID   `json:"tenant_id" validate:"required"`
+	ChannelID *kernel.ChannelID `json:"channel_id,omitempty"`
+	SenderID  string            `json:"sender_id,omitempty"`
+	From      *time.Time        `json:"from,omitempty"`
+	To        *time.Time        `json:"to,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+}
+
+// MessageCursor is the position of the last message read by ListMatching
+type MessageCursor struct {
+	ChannelID      kernel.ChannelID `json:"channel_id"`
+	ConversationID string           `json:"conversation_id"`
+	CreatedAt      time.Time        `json:"created_at"`
+	ID             string           `json:"id"`
+}
+
+// CursorOf returns the cursor that continues after msg
+func CursorOf(msg Message) *MessageCursor {
+	return &MessageCursor{
+		ChannelID:      msg.ChannelID,
+		ConversationID: msg.ConversationID,
+		CreatedAt:      msg.CreatedAt,
+		ID:             msg.ID,
+	}
+}
+
+// TagStatsRequest request to aggregate tags applied in a period
+type TagStatsRequest struct {
+	TenantID  kernel.TenantID   `json:"tenant_id" validate:"required"`
+	ChannelID *kernel.ChannelID `json:"channel_id,omitempty"`
+	From      *time.Time        `json:"from,omitempty"`
+	To        *time.Time        `json:"to,omitempty"`
+}
+
+// TagUpdateRequest request from an operator to change a conversation's tags
+type TagUpdateRequest struct {
+	Add         []string `json:"add,omitempty"`
+	Remove      []string `json:"remove,omitempty"`
+	Disposition string   `json:"disposition,omitempty"`
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+// TaggedConversation a conversation with its current tags
+type TaggedConversation struct {
+	ChannelID      kernel.ChannelID `json:"channel_id"`
+	ConversationID string           `json:"conversation_id"`
+	Tags           []string         `json:"tags"`
+	Disposition    string           `json:"disposition,omitempty"`
+	LastTaggedAt   time.Time        `json:"last_tagged_at"`
+}
+
+// TaggedConversationListResponse paginated list of tagged conversations
+type TaggedConversationListResponse = storex.Paginated[TaggedConversation]
+
+// TagCount how many conversations got a tag or disposition
+type TagCount struct {
+	Name          string  `db:"name" json:"name"`
+	Kind          TagKind `db:"kind" json:"kind"`
+	Conversations int     `db:"conversations" json:"conversations"`
+}
+
+// TagStats tag and disposition counts for a period
+type TagStats struct {
+	Tags         []TagCount `json:"tags"`
+	Dispositions []TagCount `json:"dispositions"`
+}
+
+// MessageListResponse paginated list of messages
+type MessageListResponse = storex.Paginated[Message]
+
+// TranscriptMessage message as shown in a transcript
+type TranscriptMessage struct {
+	Message
+	AttachmentURLs []string `json:"attachment_urls,omitempty"`
+}
+
+// TranscriptResponse paginated conversation transcript
+type TranscriptResponse = storex.Paginated[TranscriptMessage]
+
+// ToTranscript converts a page of messages into a transcript page
+func ToTranscript(page MessageListResponse) TranscriptResponse {
+	items := make([]TranscriptMessage, len(page.Data))
+	for i, msg := range page.Data {
+		items[i] = TranscriptMessage{
+			Message:        msg,
+			AttachmentURLs: msg.AttachmentURLs(),
+		}
+	}
+
+	return TranscriptResponse{
+		Data:  items,
+		Page:  page.Page,
+		Empty: page.Empty,
+	}
+}
+
+// ArchiveResponse an archive with a fresh download link
+type ArchiveResponse struct {
+	*Archive
+	URL          string     `json:"url,omitempty"`
+	URLExpiresAt *time.Time `json:"url_expires_at,omitempty"`
+}
@@ -0,0 +1,69 @@
+package conversation
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("CONVERSATION")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeConversationNotFound   = ErrRegistry.Register("NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Conversation not found")
+	CodeInvalidConversationID  = ErrRegistry.Register("INVALID_CONVERSATION_ID", errx.TypeValidation, http.StatusBadRequest, "Invalid conversation id")
+	CodeMessagePersistenceFail = ErrRegistry.Register("MESSAGE_PERSISTENCE_FAILED", errx.TypeInternal, http.StatusInternalServerError, "Failed to persist message")
+	CodeMessageNotFound        = ErrRegistry.Register("MESSAGE_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Message not found")
+	CodeRawPayloadNotFound     = ErrRegistry.Register("RAW_PAYLOAD_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Message has no raw provider payload")
+	CodeInvalidTag             = ErrRegistry.Register("INVALID_TAG", errx.TypeValidation, http.StatusBadRequest, "Tags are lowercase letters, digits, dashes, underscores and colons")
+	CodeTagNotFound            = ErrRegistry.Register("TAG_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Conversation does not have this tag")
+	CodeTooManyTags            = ErrRegistry.Register("TOO_MANY_TAGS", errx.TypeValidation, http.StatusBadRequest, "Conversation has too many tags")
+	CodeArchiveNotFound        = ErrRegistry.Register("ARCHIVE_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Message archive not found")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrConversationNotFound() *errx.Error {
+	return ErrRegistry.New(CodeConversationNotFound)
+}
+
+func ErrInvalidConversationID() *errx.Error {
+	return ErrRegistry.New(CodeInvalidConversationID)
+}
+
+func ErrMessagePersistenceFailed() *errx.Error {
+	return ErrRegistry.New(CodeMessagePersistenceFail)
+}
+
+func ErrMessageNotFound() *errx.Error {
+	return ErrRegistry.New(CodeMessageNotFound)
+}
+
+func ErrRawPayloadNotFound() *errx.Error {
+	return ErrRegistry.New(CodeRawPayloadNotFound)
+}
+
+func ErrInvalidTag() *errx.Error {
+	return ErrRegistry.New(CodeInvalidTag)
+}
+
+func ErrTagNotFound() *errx.Error {
+	return ErrRegistry.New(CodeTagNotFound)
+}
+
+func ErrTooManyTags() *errx.Error {
+	return ErrRegistry.New(CodeTooManyTags)
+}
+
+func ErrArchiveNotFound() *errx.Error {
+	return ErrRegistry.New(CodeArchiveNotFound)
+}
@@ -0,0 +1,135 @@
+package conversation
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Message Entity
+// ============================================================================
+
+// Message is a single inbound or outbound message of a conversation.
+// A conversation is identified by the contact's external ID on a channel
+// (phone number, IGSID, etc.), the same value workflows receive as
+// conversation_id.
+type Message struct {
+	ID                string                  `json:"id"`
+	TenantID          kernel.TenantID         `json:"tenant_id"`
+	ChannelID         kernel.ChannelID        `json:"channel_id"`
+	ConversationID    string                  `json:"conversation_id"`
+	SenderID          string                  `json:"sender_id"`
+	Direction         Direction               `json:"direction"`
+	Origin            Origin                  `json:"origin"`
+	Content           channels.MessageContent `json:"content"`
+	Status            MessageStatus           `json:"status"`
+	ProviderMessageID string                  `json:"provider_message_id,omitempty"`
+	WorkflowID        string                  `json:"workflow_id,omitempty"`
+	NodeID            string                  `json:"node_id,omitempty"`
+	Context           map[string]any          `json:"context,omitempty"`
+	RawPayload        map[string]any          `json:"-"` // Provider event of an inbound message, served separately
+	CreatedAt         time.Time               `json:"created_at"`
+}
+
+// Direction of a message relative to the tenant
+type Direction string
+
+const (
+	DirectionInbound  Direction = "INBOUND"
+	DirectionOutbound Direction = "OUTBOUND"
+)
+
+// Origin indicates who produced a message
+type Origin string
+
+const (
+	OriginContact  Origin = "contact"  // Sent by the end user
+	OriginWorkflow Origin = "workflow" // Sent by a workflow node
+	OriginManual   Origin = "manual"   // Sent by an operator through the API
+	OriginSequence Origin = "sequence" // Sent by a drip sequence step
+	OriginSystem   Origin = "system"   // Sent by the platform itself, like throttling notices
+)
+
+// MessageStatus processing status of a message
+type MessageStatus string
+
+const (
+	MessageStatusPending    MessageStatus = "PENDING"
+	MessageStatusProcessing MessageStatus = "PROCESSING"
+	MessageStatusProcessed  MessageStatus = "PROCESSED"
+	MessageStatusFailed     MessageStatus = "FAILED"
+)
+
+// ============================================================================
+// Domain Methods
+// ============================================================================
+
+// IsInbound reports whether the message was received from the contact
+func (m *Message) IsInbound() bool {
+	return m.Direction == DirectionInbound
+}
+
+// AttachmentURLs returns every media URL referenced by the message
+func (m *Message) AttachmentURLs() []string {
+	var urls []string
+	if m.Content.MediaURL != "" {
+		urls = append(urls, m.Content.MediaURL)
+	}
+	for _, att := range m.Content.Attachments {
+		if att.URL != "" {
+			urls = append(urls, att.URL)
+		}
+	}
+	return urls
+}
+
+// NewInboundMessage builds a message received from a contact
+func NewInboundMessage(id string, tenantID kernel.TenantID, channelID kernel.ChannelID, msg channels.IncomingMessage) *Message {
+	return &Message{
+		ID:                id,
+		TenantID:          tenantID,
+		ChannelID:         channelID,
+		ConversationID:    msg.SenderID,
+		SenderID:          msg.SenderID,
+		Direction:         DirectionInbound,
+		Origin:            OriginContact,
+		Content:           msg.Content,
+		Status:            MessageStatusProcessed,
+		ProviderMessageID: msg.MessageID.String(),
+		Context:           msg.Metadata,
+		RawPayload:        msg.RawPayload,
+		CreatedAt:         time.Now(),
+	}
+}
+
+// NewOutboundMessage builds a message sent to a contact. Workflow and node
+// attribution is taken from the outgoing message metadata when present.
+func NewOutboundMessage(id string, tenantID kernel.TenantID, channelID kernel.ChannelID, msg channels.OutgoingMessage) *Message {
+	m := &Message{
+		ID:             id,
+		TenantID:       tenantID,
+		ChannelID:      channelID,
+		ConversationID: msg.RecipientID,
+		SenderID:       channelID.String(),
+		Direction:      DirectionOutbound,
+		Origin:         OriginWorkflow,
+		Content:        msg.Content,
+		Status:         MessageStatusProcessed,
+		Context:        msg.Metadata,
+		CreatedAt:      time.Now(),
+	}
+
+	if origin, ok := msg.Metadata["origin"].(string); ok && origin != "" {
+		m.Origin = Origin(origin)
+	}
+	if workflowID, ok := msg.Metadata["workflow_id"].(string); ok {
+		m.WorkflowID = workflowID
+	}
+	if nodeID, ok := msg.Metadata["workflow_node_id"].(string); ok {
+		m.NodeID = nodeID
+	}
+
+	return m
+}
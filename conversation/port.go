@@ -0,0 +1,116 @@
+package conversation
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// MessageRepository persists conversation messages
+type MessageRepository interface {
+	// Save stores a message
+	Save(ctx context.Context, msg Message) error
+
+	// ListByConversation returns a page of a conversation's messages in chronological order
+	ListByConversation(ctx context.Context, req ListMessagesRequest) (MessageListResponse, error)
+
+	// FindByProviderMessageID returns the message a channel delivered with the
+	// provider's ID, the message_id workflows receive in their trigger
+	FindByProviderMessageID(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, providerMessageID string) (*Message, error)
+
+	// FindRawPayload returns the provider event an inbound message was built from
+	FindRawPayload(ctx context.Context, tenantID kernel.TenantID, messageID string) (map[string]any, error)
+
+	// CountMatching counts the messages a filter selects across conversations
+	CountMatching(ctx context.Context, filter MessageFilter) (int, error)
+
+	// ListMatching returns up to limit messages a filter selects, grouped by
+	// conversation and in chronological order within each, starting after the
+	// cursor (from the beginning when nil)
+	ListMatching(ctx context.Context, filter MessageFilter, after *MessageCursor, limit int) ([]Message, error)
+}
+
+// TagRepository persists conversations' tags and dispositions
+type TagRepository interface {
+	// Add stores tags, keeping the original of any the conversation already has
+	Add(ctx context.Context, tags []Tag) error
+
+	// SetDisposition replaces the conversation's disposition
+	SetDisposition(ctx context.Context, disposition Tag) error
+
+	// Remove deletes a tag or disposition; false when the conversation did not have it
+	Remove(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID, name string) (bool, error)
+
+	// ListByConversation returns a conversation's tags, across all channels
+	// (the contact's) when channelID is nil
+	ListByConversation(ctx context.Context, tenantID kernel.TenantID, channelID *kernel.ChannelID, conversationID string) ([]Tag, error)
+
+	// ListConversations pages through the conversations carrying the requested tags
+	ListConversations(ctx context.Context, req ListTaggedRequest) (TaggedConversationListResponse, error)
+
+	// Stats counts tagged conversations per tag and disposition
+	Stats(ctx context.Context, req TagStatsRequest) ([]TagCount, error)
+}
+
+// ChannelPurger deletes a deleted channel's messages and tags. They live in
+// the tenant's data region, where no foreign key to channels cascades.
+type ChannelPurger interface {
+	PurgeChannel(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID) error
+}
+
+// PartitionStore manages the monthly partitions the messages table is split
+// into. Months are the first instant of the month in UTC.
+type PartitionStore interface {
+	// EnsurePartitions creates any missing partition from this month through
+	// the month of until
+	EnsurePartitions(ctx context.Context, until time.Time) error
+
+	// ListPartitions returns the months with a partition, oldest first
+	ListPartitions(ctx context.Context) ([]time.Time, error)
+
+	// PartitionTenants returns the tenants with messages in the month's partition
+	PartitionTenants(ctx context.Context, month time.Time) ([]kernel.TenantID, error)
+
+	// ExportPartition writes the tenant's messages of the month as Parquet,
+	// skipping those created before since when set, and returns how many
+	// were written
+	ExportPartition(ctx context.Context, month time.Time, tenantID kernel.TenantID, since *time.Time, w io.Writer) (int, error)
+
+	// DropPartition detaches and drops the month's partition with its messages
+	DropPartition(ctx context.Context, month time.Time) error
+}
+
+// ArchiveRepository records the archive files of dropped partitions
+type ArchiveRepository interface {
+	// Save creates the archive, replacing the one of the same tenant and month
+	Save(ctx context.Context, archive Archive) error
+
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*Archive, error)
+
+	// ListByTenant returns the tenant's archives, newest month first
+	ListByTenant(ctx context.Context, tenantID kernel.TenantID) ([]Archive, error)
+
+	// ListTenants returns the tenants with at least one archive
+	ListTenants(ctx context.Context) ([]kernel.TenantID, error)
+
+	Delete(ctx context.Context, id string) error
+}
+
+// ============================================================================
+// Service Interfaces
+// ============================================================================
+
+// RetentionWindow tells how far back a tenant's messages may be read. Reads
+// never return messages past the cutoff, even before the purge removes
+// them, and the bound lets Postgres skip older partitions.
+type RetentionWindow interface {
+	// MessageCutoff is the creation time messages must not be older than;
+	// nil keeps them forever
+	MessageCutoff(ctx context.Context, tenantID kernel.TenantID) *time.Time
+}
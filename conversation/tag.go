@@ -0,0 +1,64 @@
+package conversation
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Tags and Dispositions
+// ============================================================================
+
+// MaxTagsPerConversation bounds the tags one conversation can carry
+const MaxTagsPerConversation = 50
+
+// tagPattern is what a normalized tag looks like: "sales-lead", "vip"
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_:-]{0,62}$`)
+
+// TagKind distinguishes free labels from the conversation's outcome
+type TagKind string
+
+const (
+	TagKindTag         TagKind = "TAG"         // Any number per conversation
+	TagKindDisposition TagKind = "DISPOSITION" // At most one; setting another replaces it
+)
+
+// TagSource is who applied a tag
+type TagSource string
+
+const (
+	TagSourceWorkflow TagSource = "workflow" // A TAG node
+	TagSourceAction   TagSource = "action"   // An ACTION node's tag action
+	TagSourceOperator TagSource = "operator" // A human agent through the API
+)
+
+// IsValid checks the source is known
+func (s TagSource) IsValid() bool {
+	return s == TagSourceWorkflow || s == TagSourceAction || s == TagSourceOperator
+}
+
+// Tag is a label on a conversation. A conversation is the contact's thread
+// on one channel, so a contact's tags are those of its conversations.
+type Tag struct {
+	TenantID       kernel.TenantID  `json:"tenant_id"`
+	ChannelID      kernel.ChannelID `json:"channel_id"`
+	ConversationID string           `json:"conversation_id"`
+	Name           string           `json:"name"`
+	Kind           TagKind          `json:"kind"`
+	Source         TagSource        `json:"source"`
+	SetBy          string           `json:"set_by,omitempty"` // User or workflow ID
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+// NormalizeTag lowercases a tag and joins its words with dashes, so
+// "Sales Lead" and "sales-lead" are the same tag
+func NormalizeTag(name string) (string, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(name)), "-")
+	if !tagPattern.MatchString(normalized) {
+		return "", ErrInvalidTag().WithDetail("tag", name)
+	}
+	return normalized, nil
+}
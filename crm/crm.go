@@ -0,0 +1,148 @@
+package crm
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Providers
+// ============================================================================
+
+// Provider is a CRM a tenant can connect
+type Provider string
+
+const (
+	ProviderHubSpot    Provider = "hubspot"
+	ProviderSalesforce Provider = "salesforce"
+)
+
+func (p Provider) IsValid() bool {
+	return p == ProviderHubSpot || p == ProviderSalesforce
+}
+
+// ============================================================================
+// Connection
+// ============================================================================
+
+// Connection is a tenant's authorized access to one CRM. Credentials are
+// never serialized; the repository stores them sealed with the tenant's key.
+type Connection struct {
+	TenantID     kernel.TenantID `json:"tenant_id"`
+	Provider     Provider        `json:"provider"`
+	AccountID    string          `json:"account_id,omitempty"` // HubSpot portal or Salesforce org
+	Credentials  Credentials     `json:"-"`
+	FieldMapping FieldMapping    `json:"field_mapping"`
+	ConnectedAt  time.Time       `json:"connected_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// Credentials are the OAuth tokens of a connection
+type Credentials struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`   // Zero = unknown; refreshed when rejected
+	InstanceURL  string    `json:"instance_url,omitempty"` // Salesforce API host of the org
+}
+
+// tokenExpiryMargin refreshes tokens a little before they expire, so a call
+// never starts with a token about to die
+const tokenExpiryMargin = time.Minute
+
+// Expired reports whether the access token must be refreshed before use
+func (c Credentials) Expired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && now.Add(tokenExpiryMargin).After(c.ExpiresAt)
+}
+
+// ============================================================================
+// Field Mapping
+// ============================================================================
+
+// RecordType is the kind of CRM record an operation writes
+type RecordType string
+
+const (
+	RecordContact  RecordType = "contact"
+	RecordActivity RecordType = "activity"
+	RecordDeal     RecordType = "deal"
+	RecordTicket   RecordType = "ticket"
+)
+
+// RecordTypeOf is the record an operation writes
+func RecordTypeOf(operation engine.CRMOperation) RecordType {
+	switch operation {
+	case engine.CRMLogActivity:
+		return RecordActivity
+	case engine.CRMCreateDeal:
+		return RecordDeal
+	case engine.CRMCreateTicket:
+		return RecordTicket
+	default:
+		return RecordContact
+	}
+}
+
+// FieldMapping translates relay fields (email, first_name, ...) to CRM
+// properties, per record type: {"contact": {"phone": "mobilephone"}}
+type FieldMapping map[RecordType]map[string]string
+
+// Validate checks record types and property names
+func (m FieldMapping) Validate() error {
+	for recordType, fields := range m {
+		switch recordType {
+		case RecordContact, RecordActivity, RecordDeal, RecordTicket:
+		default:
+			return ErrInvalidMapping().WithDetail("record_type", string(recordType))
+		}
+		for field, property := range fields {
+			if field == "" || property == "" {
+				return ErrInvalidMapping().
+					WithDetail("record_type", string(recordType)).
+					WithDetail("reason", "fields and properties cannot be empty")
+			}
+		}
+	}
+	return nil
+}
+
+// Property is the CRM property of a field: the tenant's mapping, else the
+// connector's default, else the field itself, so CRM properties can be used
+// directly
+func (m FieldMapping) Property(defaults FieldMapping, recordType RecordType, field string) string {
+	if property, ok := m[recordType][field]; ok {
+		return property
+	}
+	if property, ok := defaults[recordType][field]; ok {
+		return property
+	}
+	return field
+}
+
+// Apply translates fields to CRM properties
+func (m FieldMapping) Apply(defaults FieldMapping, recordType RecordType, fields map[string]any) map[string]any {
+	properties := make(map[string]any, len(fields))
+	for field, value := range fields {
+		properties[m.Property(defaults, recordType, field)] = value
+	}
+	return properties
+}
+
+// ============================================================================
+// Records
+// ============================================================================
+
+// Record is one write to a CRM, with properties already mapped
+type Record struct {
+	Operation  engine.CRMOperation
+	Properties map[string]any
+	MatchKey   string // upsert_contact: property identifying the contact
+	ContactID  string // CRM contact to link; empty = unlinked
+}
+
+// MatchValue is the value of the property identifying the contact
+func (r Record) MatchValue() string {
+	value, _ := r.Properties[r.MatchKey].(string)
+	return value
+}
@@ -0,0 +1,106 @@
+package crmapi
+
+import (
+	"github.com/Abraxas-365/relay/crm"
+	"github.com/Abraxas-365/relay/crm/crmsrv"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CRMHandler connects tenants to their CRM and manages field mappings
+type CRMHandler struct {
+	service *crmsrv.CRMService
+}
+
+// NewCRMHandler creates a new CRM handler
+func NewCRMHandler(service *crmsrv.CRMService) *CRMHandler {
+	return &CRMHandler{
+		service: service,
+	}
+}
+
+// ListConnections returns the tenant's connections and the providers it can connect
+// GET /api/crm/connections
+func (h *CRMHandler) ListConnections(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	connections, err := h.service.ListConnections(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(connections)
+}
+
+// Authorize returns the URL where an admin grants access to the CRM
+// POST /api/crm/connections/:provider/authorize
+func (h *CRMHandler) Authorize(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	authorization, err := h.service.Authorize(c.Context(), authContext.TenantID, crm.Provider(c.Params("provider")))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(authorization)
+}
+
+// Callback completes the authorization the CRM redirected back from
+// GET /crm/oauth/callback?code=&state=
+func (h *CRMHandler) Callback(c *fiber.Ctx) error {
+	if reason := c.Query("error"); reason != "" {
+		return crm.ErrAuthorizationFailed().
+			WithDetail("reason", reason).
+			WithDetail("description", c.Query("error_description"))
+	}
+
+	connection, err := h.service.CompleteAuthorization(c.Context(), c.Query("state"), c.Query("code"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(connection)
+}
+
+// SaveMapping replaces the connection's field mapping
+// PUT /api/crm/connections/:provider/mapping
+func (h *CRMHandler) SaveMapping(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req crm.SaveMappingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return crm.ErrInvalidMapping().WithDetail("reason", err.Error())
+	}
+
+	connection, err := h.service.SaveMapping(c.Context(), authContext.TenantID, crm.Provider(c.Params("provider")), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(connection)
+}
+
+// Disconnect forgets the tenant's credentials for the CRM
+// DELETE /api/crm/connections/:provider
+func (h *CRMHandler) Disconnect(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := h.service.Disconnect(c.Context(), authContext.TenantID, crm.Provider(c.Params("provider"))); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
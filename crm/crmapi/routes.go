@@ -0,0 +1,41 @@
+package crmapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CallbackPath is where CRMs redirect after an admin grants access; it must
+// be registered as the redirect URI of the OAuth apps
+const CallbackPath = "/crm/oauth/callback"
+
+// CRMRoutes handles CRM route setup
+type CRMRoutes struct {
+	handler        *CRMHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewCRMRoutes creates a new CRM routes instance
+func NewCRMRoutes(handler *CRMHandler, authMiddleware *auth.AuthMiddleware) *CRMRoutes {
+	return &CRMRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers CRM routes on an authenticated router. Connecting
+// and changing connections requires an admin.
+func (r *CRMRoutes) RegisterRoutes(router fiber.Router) {
+	connections := router.Group("/crm/connections")
+
+	connections.Get("/", r.handler.ListConnections)
+	connections.Post("/:provider/authorize", r.authMiddleware.RequireAdmin(), r.handler.Authorize)
+	connections.Put("/:provider/mapping", r.authMiddleware.RequireAdmin(), r.handler.SaveMapping)
+	connections.Delete("/:provider", r.authMiddleware.RequireAdmin(), r.handler.Disconnect)
+}
+
+// RegisterPublicRoutes registers the OAuth callback; the state issued by
+// Authorize identifies the tenant
+func (r *CRMRoutes) RegisterPublicRoutes(app *fiber.App) {
+	app.Get(CallbackPath, r.handler.Callback)
+}
@@ -0,0 +1,148 @@
+package crminfra
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/crm"
+)
+
+// requestTimeout bounds every call to a CRM
+const requestTimeout = 20 * time.Second
+
+// maxResponseBytes bounds what is read from a CRM response
+const maxResponseBytes = 1 << 20
+
+// apiClient sends JSON requests to a CRM's REST API
+type apiClient struct {
+	http *http.Client
+}
+
+func newAPIClient() apiClient {
+	return apiClient{http: &http.Client{Timeout: requestTimeout}}
+}
+
+// do sends body as JSON with the access token and decodes the response into
+// out when it is not nil. A 401 fails with crm.ErrTokenRejected.
+func (c apiClient) do(ctx context.Context, method, endpoint, accessToken string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return crm.ErrSyncFailed().WithDetail("reason", err.Error())
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return crm.ErrSyncFailed().WithDetail("reason", err.Error())
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return crm.ErrTokenRejected()
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return crm.ErrSyncFailed().
+			WithDetail("status", resp.StatusCode).
+			WithDetail("response", truncate(string(data), 500))
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return crm.ErrSyncFailed().WithDetail("reason", fmt.Sprintf("invalid response: %v", err))
+	}
+	return nil
+}
+
+// tokenResponse is an OAuth token endpoint's answer
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`   // HubSpot
+	InstanceURL  string `json:"instance_url"` // Salesforce
+	ID           string `json:"id"`           // Salesforce identity URL
+}
+
+// credentials keeps previous's refresh token when the CRM does not rotate it
+func (t tokenResponse) credentials(previous crm.Credentials) *crm.Credentials {
+	credentials := &crm.Credentials{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.RefreshToken,
+		InstanceURL:  t.InstanceURL,
+	}
+	if credentials.RefreshToken == "" {
+		credentials.RefreshToken = previous.RefreshToken
+	}
+	if credentials.InstanceURL == "" {
+		credentials.InstanceURL = previous.InstanceURL
+	}
+	if t.ExpiresIn > 0 {
+		credentials.ExpiresAt = time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+	return credentials
+}
+
+// requestToken posts a form to an OAuth token endpoint
+func (c apiClient) requestToken(ctx context.Context, tokenURL string, form url.Values) (*tokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, crm.ErrAuthorizationFailed().WithDetail("reason", err.Error())
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, crm.ErrAuthorizationFailed().WithDetail("reason", err.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, crm.ErrAuthorizationFailed().
+			WithDetail("status", resp.StatusCode).
+			WithDetail("response", truncate(string(data), 500))
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(data, &token); err != nil || token.AccessToken == "" {
+		return nil, crm.ErrAuthorizationFailed().WithDetail("reason", "token response has no access_token")
+	}
+	return &token, nil
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
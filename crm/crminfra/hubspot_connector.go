@@ -0,0 +1,231 @@
+package crminfra
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Abraxas-365/relay/crm"
+	"github.com/Abraxas-365/relay/engine"
+)
+
+const (
+	hubSpotAuthorizeURL = "https://app.hubspot.com/oauth/authorize"
+	hubSpotAPIURL       = "https://api.hubapi.com"
+	hubSpotScopes       = "oauth crm.objects.contacts.read crm.objects.contacts.write crm.objects.deals.read crm.objects.deals.write tickets"
+)
+
+// HubSpot default association types, from the written record to the contact
+const (
+	hubSpotNoteToContact   = 202
+	hubSpotDealToContact   = 3
+	hubSpotTicketToContact = 16
+)
+
+// HubSpotConnector writes to HubSpot through its CRM v3 API. Activities are
+// notes; deals and tickets default to the first stage of the default
+// pipeline.
+type HubSpotConnector struct {
+	clientID     string
+	clientSecret string
+	api          apiClient
+}
+
+var _ crm.Connector = (*HubSpotConnector)(nil)
+
+func NewHubSpotConnector(clientID, clientSecret string) *HubSpotConnector {
+	return &HubSpotConnector{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		api:          newAPIClient(),
+	}
+}
+
+func (c *HubSpotConnector) Provider() crm.Provider {
+	return crm.ProviderHubSpot
+}
+
+func (c *HubSpotConnector) DefaultMapping() crm.FieldMapping {
+	return crm.FieldMapping{
+		crm.RecordContact: {
+			"first_name": "firstname",
+			"last_name":  "lastname",
+		},
+		crm.RecordActivity: {
+			"body": "hs_note_body",
+		},
+		crm.RecordDeal: {
+			"name":     "dealname",
+			"stage":    "dealstage",
+			"pipeline": "pipeline",
+		},
+		crm.RecordTicket: {
+			"description": "content",
+			"stage":       "hs_pipeline_stage",
+			"pipeline":    "hs_pipeline",
+			"priority":    "hs_ticket_priority",
+		},
+	}
+}
+
+// ============================================================================
+// OAuth
+// ============================================================================
+
+func (c *HubSpotConnector) AuthorizeURL(state, redirectURI string) string {
+	query := url.Values{
+		"client_id":    {c.clientID},
+		"redirect_uri": {redirectURI},
+		"scope":        {hubSpotScopes},
+		"state":        {state},
+	}
+	return hubSpotAuthorizeURL + "?" + query.Encode()
+}
+
+func (c *HubSpotConnector) Exchange(ctx context.Context, code, redirectURI string) (*crm.Credentials, string, error) {
+	token, err := c.api.requestToken(ctx, hubSpotAPIURL+"/oauth/v1/token", url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {c.clientID},
+		"client_secret": {c.clientSecret},
+		"redirect_uri":  {redirectURI},
+		"code":          {code},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	credentials := token.credentials(crm.Credentials{})
+
+	// The portal the token grants access to
+	var info struct {
+		HubID int64 `json:"hub_id"`
+	}
+	endpoint := hubSpotAPIURL + "/oauth/v1/access-tokens/" + url.PathEscape(credentials.AccessToken)
+	if err := c.api.do(ctx, http.MethodGet, endpoint, credentials.AccessToken, nil, &info); err != nil {
+		return nil, "", err
+	}
+
+	return credentials, strconv.FormatInt(info.HubID, 10), nil
+}
+
+func (c *HubSpotConnector) Refresh(ctx context.Context, credentials crm.Credentials) (*crm.Credentials, error) {
+	token, err := c.api.requestToken(ctx, hubSpotAPIURL+"/oauth/v1/token", url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {c.clientID},
+		"client_secret": {c.clientSecret},
+		"refresh_token": {credentials.RefreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token.credentials(credentials), nil
+}
+
+// ============================================================================
+// Records
+// ============================================================================
+
+func (c *HubSpotConnector) Write(ctx context.Context, credentials crm.Credentials, record crm.Record) (*engine.CRMSyncResult, error) {
+	properties := record.Properties
+	var objectType string
+	var associationType int
+
+	switch record.Operation {
+	case engine.CRMUpsertContact:
+		return c.upsertContact(ctx, credentials, record)
+	case engine.CRMLogActivity:
+		objectType, associationType = "notes", hubSpotNoteToContact
+		setDefault(properties, "hs_timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	case engine.CRMCreateDeal:
+		objectType, associationType = "deals", hubSpotDealToContact
+		setDefault(properties, "pipeline", "default")
+		setDefault(properties, "dealstage", "appointmentscheduled")
+	case engine.CRMCreateTicket:
+		objectType, associationType = "tickets", hubSpotTicketToContact
+		setDefault(properties, "hs_pipeline", "0")
+		setDefault(properties, "hs_pipeline_stage", "1")
+	default:
+		return nil, crm.ErrSyncFailed().WithDetail("operation", string(record.Operation))
+	}
+
+	body := map[string]any{"properties": properties}
+	if record.ContactID != "" {
+		body["associations"] = []map[string]any{{
+			"to": map[string]any{"id": record.ContactID},
+			"types": []map[string]any{{
+				"associationCategory": "HUBSPOT_DEFINED",
+				"associationTypeId":   associationType,
+			}},
+		}}
+	}
+
+	var created hubSpotObject
+	if err := c.api.do(ctx, http.MethodPost, c.objectsURL(objectType), credentials.AccessToken, body, &created); err != nil {
+		return nil, err
+	}
+
+	return c.result(record, created.ID, true), nil
+}
+
+type hubSpotObject struct {
+	ID string `json:"id"`
+}
+
+// upsertContact updates the contact whose match property equals the
+// record's, or creates it
+func (c *HubSpotConnector) upsertContact(ctx context.Context, credentials crm.Credentials, record crm.Record) (*engine.CRMSyncResult, error) {
+	search := map[string]any{
+		"filterGroups": []map[string]any{{
+			"filters": []map[string]any{{
+				"propertyName": record.MatchKey,
+				"operator":     "EQ",
+				"value":        record.MatchValue(),
+			}},
+		}},
+		"properties": []string{record.MatchKey},
+		"limit":      1,
+	}
+	var found struct {
+		Results []hubSpotObject `json:"results"`
+	}
+	if err := c.api.do(ctx, http.MethodPost, c.objectsURL("contacts")+"/search", credentials.AccessToken, search, &found); err != nil {
+		return nil, err
+	}
+
+	body := map[string]any{"properties": record.Properties}
+	if len(found.Results) > 0 {
+		id := found.Results[0].ID
+		if err := c.api.do(ctx, http.MethodPatch, c.objectsURL("contacts")+"/"+url.PathEscape(id), credentials.AccessToken, body, nil); err != nil {
+			return nil, err
+		}
+		return c.result(record, id, false), nil
+	}
+
+	var created hubSpotObject
+	if err := c.api.do(ctx, http.MethodPost, c.objectsURL("contacts"), credentials.AccessToken, body, &created); err != nil {
+		return nil, err
+	}
+	return c.result(record, created.ID, true), nil
+}
+
+func (c *HubSpotConnector) objectsURL(objectType string) string {
+	return fmt.Sprintf("%s/crm/v3/objects/%s", hubSpotAPIURL, objectType)
+}
+
+func (c *HubSpotConnector) result(record crm.Record, id string, created bool) *engine.CRMSyncResult {
+	return &engine.CRMSyncResult{
+		Provider:  string(crm.ProviderHubSpot),
+		Operation: record.Operation,
+		RecordID:  id,
+		Created:   created,
+	}
+}
+
+// setDefault sets a required property the record did not set
+func setDefault(properties map[string]any, property string, value any) {
+	if _, ok := properties[property]; !ok {
+		properties[property] = value
+	}
+}
@@ -0,0 +1,158 @@
+package crminfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/crm"
+	"github.com/Abraxas-365/relay/encryption"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresConnectionRepository is the PostgreSQL implementation of
+// crm.ConnectionRepository. Credentials are sealed with the tenant's data key
+// when the tenant has encryption enabled.
+type PostgresConnectionRepository struct {
+	db     *sqlx.DB
+	cipher *encryption.FieldCipher
+}
+
+var _ crm.ConnectionRepository = (*PostgresConnectionRepository)(nil)
+
+func NewPostgresConnectionRepository(db *sqlx.DB, cipher *encryption.FieldCipher) *PostgresConnectionRepository {
+	return &PostgresConnectionRepository{db: db, cipher: cipher}
+}
+
+const connectionColumns = `tenant_id, provider, account_id, credentials, field_mapping, connected_at, updated_at`
+
+type connectionRow struct {
+	TenantID     string    `db:"tenant_id"`
+	Provider     string    `db:"provider"`
+	AccountID    string    `db:"account_id"`
+	Credentials  []byte    `db:"credentials"`
+	FieldMapping []byte    `db:"field_mapping"`
+	ConnectedAt  time.Time `db:"connected_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+func (r *PostgresConnectionRepository) Find(ctx context.Context, tenantID kernel.TenantID, provider crm.Provider) (*crm.Connection, error) {
+	query := `SELECT ` + connectionColumns + ` FROM crm_connections WHERE tenant_id = $1 AND provider = $2`
+
+	var row connectionRow
+	if err := r.db.GetContext(ctx, &row, query, tenantID.String(), string(provider)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, crm.ErrConnectionNotFound().WithDetail("provider", string(provider))
+		}
+		return nil, errx.Wrap(err, "failed to find CRM connection", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	return r.toConnection(ctx, row)
+}
+
+func (r *PostgresConnectionRepository) List(ctx context.Context, tenantID kernel.TenantID) ([]crm.Connection, error) {
+	query := `SELECT ` + connectionColumns + ` FROM crm_connections WHERE tenant_id = $1 ORDER BY provider`
+
+	var rows []connectionRow
+	if err := r.db.SelectContext(ctx, &rows, query, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to list CRM connections", errx.TypeInternal)
+	}
+
+	connections := make([]crm.Connection, 0, len(rows))
+	for _, row := range rows {
+		connection, err := r.toConnection(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		connections = append(connections, *connection)
+	}
+
+	return connections, nil
+}
+
+func (r *PostgresConnectionRepository) Save(ctx context.Context, connection crm.Connection) error {
+	credentials, err := json.Marshal(connection.Credentials)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal CRM credentials", errx.TypeInternal)
+	}
+	credentials, err = r.cipher.EncryptJSON(ctx, connection.TenantID, credentials)
+	if err != nil {
+		return err
+	}
+
+	mapping := connection.FieldMapping
+	if mapping == nil {
+		mapping = crm.FieldMapping{}
+	}
+	fieldMapping, err := json.Marshal(mapping)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal CRM field mapping", errx.TypeInternal)
+	}
+
+	query := `
+		INSERT INTO crm_connections (` + connectionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (tenant_id, provider) DO UPDATE SET
+			account_id = EXCLUDED.account_id,
+			credentials = EXCLUDED.credentials,
+			field_mapping = EXCLUDED.field_mapping,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.ExecContext(ctx, query,
+		connection.TenantID.String(),
+		string(connection.Provider),
+		connection.AccountID,
+		credentials,
+		fieldMapping,
+		connection.ConnectedAt,
+		connection.UpdatedAt,
+	); err != nil {
+		return errx.Wrap(err, "failed to save CRM connection", errx.TypeInternal).
+			WithDetail("tenant_id", connection.TenantID.String()).
+			WithDetail("provider", string(connection.Provider))
+	}
+
+	return nil
+}
+
+func (r *PostgresConnectionRepository) Delete(ctx context.Context, tenantID kernel.TenantID, provider crm.Provider) error {
+	query := `DELETE FROM crm_connections WHERE tenant_id = $1 AND provider = $2`
+
+	result, err := r.db.ExecContext(ctx, query, tenantID.String(), string(provider))
+	if err != nil {
+		return errx.Wrap(err, "failed to delete CRM connection", errx.TypeInternal)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return crm.ErrConnectionNotFound().WithDetail("provider", string(provider))
+	}
+
+	return nil
+}
+
+func (r *PostgresConnectionRepository) toConnection(ctx context.Context, row connectionRow) (*crm.Connection, error) {
+	tenantID := kernel.TenantID(row.TenantID)
+	connection := &crm.Connection{
+		TenantID:    tenantID,
+		Provider:    crm.Provider(row.Provider),
+		AccountID:   row.AccountID,
+		ConnectedAt: row.ConnectedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+
+	credentials, err := r.cipher.DecryptJSON(ctx, tenantID, row.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(credentials, &connection.Credentials); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal CRM credentials", errx.TypeInternal)
+	}
+	if err := json.Unmarshal(row.FieldMapping, &connection.FieldMapping); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal CRM field mapping", errx.TypeInternal)
+	}
+
+	return connection, nil
+}
@@ -0,0 +1,243 @@
+package crminfra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/crm"
+	"github.com/Abraxas-365/relay/engine"
+)
+
+const (
+	salesforceAPIVersion = "v59.0"
+	salesforceScopes     = "api refresh_token"
+
+	// DefaultSalesforceLoginURL is production; sandboxes use test.salesforce.com
+	DefaultSalesforceLoginURL = "https://login.salesforce.com"
+)
+
+// salesforceFieldPattern is a Salesforce field API name. Match fields are
+// placed in SOQL, so anything else is rejected.
+var salesforceFieldPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// SalesforceConnector writes to Salesforce through its REST API. Activities
+// are completed tasks, deals are opportunities and tickets are cases.
+type SalesforceConnector struct {
+	clientID     string
+	clientSecret string
+	loginURL     string
+	api          apiClient
+}
+
+var _ crm.Connector = (*SalesforceConnector)(nil)
+
+// NewSalesforceConnector authorizes through loginURL,
+// DefaultSalesforceLoginURL when empty
+func NewSalesforceConnector(clientID, clientSecret, loginURL string) *SalesforceConnector {
+	if loginURL == "" {
+		loginURL = DefaultSalesforceLoginURL
+	}
+	return &SalesforceConnector{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		loginURL:     strings.TrimSuffix(loginURL, "/"),
+		api:          newAPIClient(),
+	}
+}
+
+func (c *SalesforceConnector) Provider() crm.Provider {
+	return crm.ProviderSalesforce
+}
+
+func (c *SalesforceConnector) DefaultMapping() crm.FieldMapping {
+	return crm.FieldMapping{
+		crm.RecordContact: {
+			"email":      "Email",
+			"phone":      "Phone",
+			"first_name": "FirstName",
+			"last_name":  "LastName",
+		},
+		crm.RecordActivity: {
+			"subject": "Subject",
+			"body":    "Description",
+		},
+		crm.RecordDeal: {
+			"name":       "Name",
+			"amount":     "Amount",
+			"stage":      "StageName",
+			"close_date": "CloseDate",
+		},
+		crm.RecordTicket: {
+			"subject":     "Subject",
+			"description": "Description",
+			"priority":    "Priority",
+			"status":      "Status",
+		},
+	}
+}
+
+// ============================================================================
+// OAuth
+// ============================================================================
+
+func (c *SalesforceConnector) AuthorizeURL(state, redirectURI string) string {
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {c.clientID},
+		"redirect_uri":  {redirectURI},
+		"scope":         {salesforceScopes},
+		"state":         {state},
+	}
+	return c.loginURL + "/services/oauth2/authorize?" + query.Encode()
+}
+
+func (c *SalesforceConnector) Exchange(ctx context.Context, code, redirectURI string) (*crm.Credentials, string, error) {
+	token, err := c.api.requestToken(ctx, c.loginURL+"/services/oauth2/token", url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {c.clientID},
+		"client_secret": {c.clientSecret},
+		"redirect_uri":  {redirectURI},
+		"code":          {code},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if token.InstanceURL == "" {
+		return nil, "", crm.ErrAuthorizationFailed().WithDetail("reason", "token response has no instance_url")
+	}
+
+	// The identity URL ends in /id/<org>/<user>
+	orgID := path.Base(path.Dir(token.ID))
+	return token.credentials(crm.Credentials{}), orgID, nil
+}
+
+func (c *SalesforceConnector) Refresh(ctx context.Context, credentials crm.Credentials) (*crm.Credentials, error) {
+	token, err := c.api.requestToken(ctx, c.loginURL+"/services/oauth2/token", url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {c.clientID},
+		"client_secret": {c.clientSecret},
+		"refresh_token": {credentials.RefreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token.credentials(credentials), nil
+}
+
+// ============================================================================
+// Records
+// ============================================================================
+
+func (c *SalesforceConnector) Write(ctx context.Context, credentials crm.Credentials, record crm.Record) (*engine.CRMSyncResult, error) {
+	properties := record.Properties
+	today := time.Now().Format(time.DateOnly)
+
+	switch record.Operation {
+	case engine.CRMUpsertContact:
+		return c.upsertContact(ctx, credentials, record)
+	case engine.CRMLogActivity:
+		setDefault(properties, "Subject", "Conversation")
+		setDefault(properties, "Status", "Completed")
+		setDefault(properties, "ActivityDate", today)
+		if record.ContactID != "" {
+			properties["WhoId"] = record.ContactID
+		}
+		return c.create(ctx, credentials, record, "Task")
+	case engine.CRMCreateDeal:
+		setDefault(properties, "StageName", "Prospecting")
+		setDefault(properties, "CloseDate", time.Now().AddDate(0, 1, 0).Format(time.DateOnly))
+		result, err := c.create(ctx, credentials, record, "Opportunity")
+		if err == nil && record.ContactID != "" {
+			c.linkOpportunity(ctx, credentials, result.RecordID, record.ContactID)
+		}
+		return result, err
+	case engine.CRMCreateTicket:
+		setDefault(properties, "Origin", "Chat")
+		if record.ContactID != "" {
+			properties["ContactId"] = record.ContactID
+		}
+		return c.create(ctx, credentials, record, "Case")
+	default:
+		return nil, crm.ErrSyncFailed().WithDetail("operation", string(record.Operation))
+	}
+}
+
+type salesforceCreated struct {
+	ID string `json:"id"`
+}
+
+func (c *SalesforceConnector) create(ctx context.Context, credentials crm.Credentials, record crm.Record, object string) (*engine.CRMSyncResult, error) {
+	var created salesforceCreated
+	if err := c.api.do(ctx, http.MethodPost, c.sobjectURL(credentials, object), credentials.AccessToken, record.Properties, &created); err != nil {
+		return nil, err
+	}
+	return c.result(record, created.ID, true), nil
+}
+
+// upsertContact updates the contact whose match field equals the record's,
+// or creates it. LastName is required on creation and defaults to the match
+// value.
+func (c *SalesforceConnector) upsertContact(ctx context.Context, credentials crm.Credentials, record crm.Record) (*engine.CRMSyncResult, error) {
+	if !salesforceFieldPattern.MatchString(record.MatchKey) {
+		return nil, crm.ErrSyncFailed().
+			WithDetail("match_field", record.MatchKey).
+			WithDetail("reason", "not a Salesforce field name")
+	}
+
+	soql := fmt.Sprintf("SELECT Id FROM Contact WHERE %s = '%s' LIMIT 1", record.MatchKey, escapeSOQL(record.MatchValue()))
+	endpoint := fmt.Sprintf("%s/services/data/%s/query?q=%s", credentials.InstanceURL, salesforceAPIVersion, url.QueryEscape(soql))
+
+	var found struct {
+		Records []struct {
+			ID string `json:"Id"`
+		} `json:"records"`
+	}
+	if err := c.api.do(ctx, http.MethodGet, endpoint, credentials.AccessToken, nil, &found); err != nil {
+		return nil, err
+	}
+
+	if len(found.Records) > 0 {
+		id := found.Records[0].ID
+		if err := c.api.do(ctx, http.MethodPatch, c.sobjectURL(credentials, "Contact")+"/"+url.PathEscape(id), credentials.AccessToken, record.Properties, nil); err != nil {
+			return nil, err
+		}
+		return c.result(record, id, false), nil
+	}
+
+	setDefault(record.Properties, "LastName", record.MatchValue())
+	return c.create(ctx, credentials, record, "Contact")
+}
+
+// linkOpportunity adds the contact to the opportunity's contact roles. The
+// opportunity exists either way, so a failure is only logged.
+func (c *SalesforceConnector) linkOpportunity(ctx context.Context, credentials crm.Credentials, opportunityID, contactID string) {
+	role := map[string]any{"OpportunityId": opportunityID, "ContactId": contactID}
+	if err := c.api.do(ctx, http.MethodPost, c.sobjectURL(credentials, "OpportunityContactRole"), credentials.AccessToken, role, nil); err != nil {
+		log.Printf("⚠️  Failed to link Salesforce opportunity %s to contact %s: %v", opportunityID, contactID, err)
+	}
+}
+
+func (c *SalesforceConnector) sobjectURL(credentials crm.Credentials, object string) string {
+	return fmt.Sprintf("%s/services/data/%s/sobjects/%s", credentials.InstanceURL, salesforceAPIVersion, object)
+}
+
+func (c *SalesforceConnector) result(record crm.Record, id string, created bool) *engine.CRMSyncResult {
+	return &engine.CRMSyncResult{
+		Provider:  string(crm.ProviderSalesforce),
+		Operation: record.Operation,
+		RecordID:  id,
+		Created:   created,
+	}
+}
+
+// escapeSOQL escapes a string literal of a SOQL query
+func escapeSOQL(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+}
@@ -0,0 +1,319 @@
+package crmsrv
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/crm"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/tool"
+)
+
+// authorizationStateType marks the OAuth states issued by Authorize among
+// the others kept by the state manager
+const authorizationStateType = "crm_authorization"
+
+// CRMService connects tenants to their CRM through OAuth and writes the
+// records of CRM_SYNC nodes and CRM tools, refreshing tokens as they expire
+type CRMService struct {
+	connectionRepo crm.ConnectionRepository
+	stateManager   auth.StateManager
+	callbackURL    string
+	connectors     map[crm.Provider]crm.Connector
+}
+
+var _ engine.CRMSyncer = (*CRMService)(nil)
+
+// NewCRMService creates the service. callbackURL is the public URL of the
+// OAuth callback; only the providers with a connector can be connected.
+func NewCRMService(
+	connectionRepo crm.ConnectionRepository,
+	stateManager auth.StateManager,
+	callbackURL string,
+	connectors ...crm.Connector,
+) *CRMService {
+	s := &CRMService{
+		connectionRepo: connectionRepo,
+		stateManager:   stateManager,
+		callbackURL:    callbackURL,
+		connectors:     make(map[crm.Provider]crm.Connector, len(connectors)),
+	}
+	for _, connector := range connectors {
+		s.connectors[connector.Provider()] = connector
+	}
+	return s
+}
+
+// ============================================================================
+// Connections
+// ============================================================================
+
+// Available lists the providers configured on this server
+func (s *CRMService) Available() []crm.Provider {
+	providers := make([]crm.Provider, 0, len(s.connectors))
+	for provider := range s.connectors {
+		providers = append(providers, provider)
+	}
+	slices.Sort(providers)
+	return providers
+}
+
+// ListConnections returns the tenant's connections, without credentials
+func (s *CRMService) ListConnections(ctx context.Context, tenantID kernel.TenantID) (*crm.ConnectionsResponse, error) {
+	connections, err := s.connectionRepo.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &crm.ConnectionsResponse{Connections: connections, Available: s.Available()}, nil
+}
+
+// Authorize starts connecting the tenant to provider: the admin is sent to
+// the returned URL, and the CRM redirects back to the callback
+func (s *CRMService) Authorize(ctx context.Context, tenantID kernel.TenantID, provider crm.Provider) (*crm.AuthorizeResponse, error) {
+	connector, err := s.connector(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	state := s.stateManager.GenerateState()
+	data := map[string]any{
+		"type":      authorizationStateType,
+		"tenant_id": tenantID.String(),
+		"provider":  string(provider),
+	}
+	if err := s.stateManager.StoreState(ctx, state, data); err != nil {
+		return nil, errx.Wrap(err, "failed to store CRM authorization state", errx.TypeInternal)
+	}
+
+	return &crm.AuthorizeResponse{URL: connector.AuthorizeURL(state, s.callbackURL)}, nil
+}
+
+// CompleteAuthorization exchanges the code the CRM redirected with and saves
+// the connection. Reconnecting keeps the field mapping.
+func (s *CRMService) CompleteAuthorization(ctx context.Context, state, code string) (*crm.Connection, error) {
+	if state == "" || code == "" {
+		return nil, crm.ErrInvalidState()
+	}
+
+	data, err := s.stateManager.GetStateData(ctx, state)
+	if err != nil {
+		return nil, crm.ErrInvalidState()
+	}
+	if t, _ := data["type"].(string); t != authorizationStateType {
+		return nil, crm.ErrInvalidState()
+	}
+	tenantID, _ := data["tenant_id"].(string)
+	provider, _ := data["provider"].(string)
+
+	connector, err := s.connector(crm.Provider(provider))
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, accountID, err := connector.Exchange(ctx, code, s.callbackURL)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	connection := &crm.Connection{
+		TenantID:     kernel.TenantID(tenantID),
+		Provider:     connector.Provider(),
+		FieldMapping: crm.FieldMapping{},
+		ConnectedAt:  now,
+	}
+	if existing, err := s.connectionRepo.Find(ctx, connection.TenantID, connection.Provider); err == nil {
+		connection.FieldMapping = existing.FieldMapping
+		connection.ConnectedAt = existing.ConnectedAt
+	}
+	connection.AccountID = accountID
+	connection.Credentials = *credentials
+	connection.UpdatedAt = now
+
+	if err := s.connectionRepo.Save(ctx, *connection); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✅ Tenant %s connected %s account %s", tenantID, provider, accountID)
+	return connection, nil
+}
+
+// SaveMapping replaces the connection's field mapping
+func (s *CRMService) SaveMapping(ctx context.Context, tenantID kernel.TenantID, provider crm.Provider, req crm.SaveMappingRequest) (*crm.Connection, error) {
+	if err := req.FieldMapping.Validate(); err != nil {
+		return nil, err
+	}
+
+	connection, err := s.connectionRepo.Find(ctx, tenantID, provider)
+	if err != nil {
+		return nil, err
+	}
+	connection.FieldMapping = req.FieldMapping
+	connection.UpdatedAt = time.Now()
+
+	if err := s.connectionRepo.Save(ctx, *connection); err != nil {
+		return nil, err
+	}
+	return connection, nil
+}
+
+// Disconnect forgets the tenant's credentials for provider. Access stays
+// granted in the CRM until revoked there.
+func (s *CRMService) Disconnect(ctx context.Context, tenantID kernel.TenantID, provider crm.Provider) error {
+	return s.connectionRepo.Delete(ctx, tenantID, provider)
+}
+
+// ============================================================================
+// Sync
+// ============================================================================
+
+// SyncCRM implements engine.CRMSyncer
+func (s *CRMService) SyncCRM(ctx context.Context, tenantID kernel.TenantID, req engine.CRMSyncRequest) (*engine.CRMSyncResult, error) {
+	connection, err := s.resolveConnection(ctx, tenantID, crm.Provider(req.Provider))
+	if err != nil {
+		return nil, err
+	}
+	connector, err := s.connector(connection.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := connector.DefaultMapping()
+	recordType := crm.RecordTypeOf(req.Operation)
+	record := crm.Record{
+		Operation:  req.Operation,
+		Properties: connection.FieldMapping.Apply(defaults, recordType, req.Fields),
+		ContactID:  req.ContactID,
+	}
+	if req.Operation == engine.CRMUpsertContact {
+		matchField := req.MatchField
+		if matchField == "" {
+			matchField = engine.DefaultCRMMatchField
+		}
+		record.MatchKey = connection.FieldMapping.Property(defaults, crm.RecordContact, matchField)
+		if record.MatchValue() == "" {
+			return nil, crm.ErrSyncFailed().
+				WithDetail("match_field", matchField).
+				WithDetail("reason", "the match field has no value")
+		}
+	}
+
+	return s.write(ctx, connection, connector, record)
+}
+
+// ExecuteTool runs a tool of type tool.ToolTypeCRM: input holds the fields,
+// plus contact_id to link the record
+func (s *CRMService) ExecuteTool(ctx context.Context, t *tool.Tool, input map[string]any) (map[string]any, error) {
+	if t.Type != tool.ToolTypeCRM {
+		return nil, tool.ErrInvalidToolType().WithDetail("type", string(t.Type))
+	}
+	if !t.IsActive {
+		return nil, tool.ErrToolInactive()
+	}
+
+	fields := make(map[string]any, len(input))
+	for name, value := range input {
+		if name != "contact_id" {
+			fields[name] = value
+		}
+	}
+	contactID, _ := input["contact_id"].(string)
+
+	result, err := s.SyncCRM(ctx, t.TenantID, engine.CRMSyncRequest{
+		Provider:   t.Config.CRMProvider,
+		Operation:  engine.CRMOperation(t.Config.CRMOperation),
+		Fields:     fields,
+		MatchField: t.Config.CRMMatchField,
+		ContactID:  contactID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"provider":  result.Provider,
+		"operation": string(result.Operation),
+		"record_id": result.RecordID,
+		"created":   result.Created,
+	}, nil
+}
+
+// write performs the record's operation, refreshing the access token when it
+// expired or the CRM rejects it
+func (s *CRMService) write(ctx context.Context, connection *crm.Connection, connector crm.Connector, record crm.Record) (*engine.CRMSyncResult, error) {
+	refreshed := false
+	if connection.Credentials.Expired(time.Now()) {
+		if err := s.refresh(ctx, connection, connector); err != nil {
+			return nil, err
+		}
+		refreshed = true
+	}
+
+	result, err := connector.Write(ctx, connection.Credentials, record)
+	if errx.IsCode(err, crm.CodeTokenRejected) && !refreshed {
+		if err := s.refresh(ctx, connection, connector); err != nil {
+			return nil, err
+		}
+		result, err = connector.Write(ctx, connection.Credentials, record)
+	}
+	return result, err
+}
+
+// refresh renews the connection's access token and stores it
+func (s *CRMService) refresh(ctx context.Context, connection *crm.Connection, connector crm.Connector) error {
+	if connection.Credentials.RefreshToken == "" {
+		return crm.ErrTokenRejected().WithDetail("reason", "reconnect the CRM")
+	}
+
+	credentials, err := connector.Refresh(ctx, connection.Credentials)
+	if err != nil {
+		return err
+	}
+	connection.Credentials = *credentials
+	connection.UpdatedAt = time.Now()
+
+	if err := s.connectionRepo.Save(context.WithoutCancel(ctx), *connection); err != nil {
+		log.Printf("⚠️  Failed to store refreshed %s token of tenant %s: %v", connection.Provider, connection.TenantID, err)
+	}
+	return nil
+}
+
+// resolveConnection finds the connection to provider, or the tenant's only
+// connection when provider is empty
+func (s *CRMService) resolveConnection(ctx context.Context, tenantID kernel.TenantID, provider crm.Provider) (*crm.Connection, error) {
+	if provider != "" {
+		if !provider.IsValid() {
+			return nil, crm.ErrInvalidProvider().WithDetail("provider", string(provider))
+		}
+		return s.connectionRepo.Find(ctx, tenantID, provider)
+	}
+
+	connections, err := s.connectionRepo.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	switch len(connections) {
+	case 0:
+		return nil, crm.ErrConnectionNotFound()
+	case 1:
+		return &connections[0], nil
+	default:
+		return nil, crm.ErrAmbiguousConnection()
+	}
+}
+
+func (s *CRMService) connector(provider crm.Provider) (crm.Connector, error) {
+	if !provider.IsValid() {
+		return nil, crm.ErrInvalidProvider().WithDetail("provider", string(provider))
+	}
+	connector, ok := s.connectors[provider]
+	if !ok {
+		return nil, crm.ErrProviderNotConfigured().WithDetail("provider", string(provider))
+	}
+	return connector, nil
+}
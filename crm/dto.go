@@ -0,0 +1,25 @@
+package crm
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// SaveMappingRequest replaces a connection's field mapping
+type SaveMappingRequest struct {
+	FieldMapping FieldMapping `json:"field_mapping"`
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+// ConnectionsResponse is what a tenant connected and what it can connect
+type ConnectionsResponse struct {
+	Connections []Connection `json:"connections"`
+	Available   []Provider   `json:"available"` // Providers configured on this server
+}
+
+// AuthorizeResponse is where to send the admin to grant access
+type AuthorizeResponse struct {
+	URL string `json:"url"`
+}
@@ -0,0 +1,69 @@
+package crm
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("CRM")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeConnectionNotFound    = ErrRegistry.Register("CONNECTION_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "CRM is not connected")
+	CodeAmbiguousConnection   = ErrRegistry.Register("AMBIGUOUS_CONNECTION", errx.TypeValidation, http.StatusBadRequest, "Several CRMs are connected; choose a provider")
+	CodeInvalidProvider       = ErrRegistry.Register("INVALID_PROVIDER", errx.TypeValidation, http.StatusBadRequest, "Invalid CRM provider")
+	CodeProviderNotConfigured = ErrRegistry.Register("PROVIDER_NOT_CONFIGURED", errx.TypeBusiness, http.StatusServiceUnavailable, "CRM provider is not configured on this server")
+	CodeInvalidMapping        = ErrRegistry.Register("INVALID_MAPPING", errx.TypeValidation, http.StatusBadRequest, "Invalid field mapping")
+	CodeInvalidState          = ErrRegistry.Register("INVALID_STATE", errx.TypeValidation, http.StatusBadRequest, "Invalid or expired authorization state")
+	CodeAuthorizationFailed   = ErrRegistry.Register("AUTHORIZATION_FAILED", errx.TypeExternal, http.StatusBadGateway, "CRM authorization failed")
+	CodeTokenRejected         = ErrRegistry.Register("TOKEN_REJECTED", errx.TypeAuthorization, http.StatusUnauthorized, "CRM rejected the access token")
+	CodeSyncFailed            = ErrRegistry.Register("SYNC_FAILED", errx.TypeExternal, http.StatusBadGateway, "CRM request failed")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrConnectionNotFound() *errx.Error {
+	return ErrRegistry.New(CodeConnectionNotFound)
+}
+
+func ErrAmbiguousConnection() *errx.Error {
+	return ErrRegistry.New(CodeAmbiguousConnection)
+}
+
+func ErrInvalidProvider() *errx.Error {
+	return ErrRegistry.New(CodeInvalidProvider)
+}
+
+func ErrProviderNotConfigured() *errx.Error {
+	return ErrRegistry.New(CodeProviderNotConfigured)
+}
+
+func ErrInvalidMapping() *errx.Error {
+	return ErrRegistry.New(CodeInvalidMapping)
+}
+
+func ErrInvalidState() *errx.Error {
+	return ErrRegistry.New(CodeInvalidState)
+}
+
+func ErrAuthorizationFailed() *errx.Error {
+	return ErrRegistry.New(CodeAuthorizationFailed)
+}
+
+func ErrTokenRejected() *errx.Error {
+	return ErrRegistry.New(CodeTokenRejected)
+}
+
+func ErrSyncFailed() *errx.Error {
+	return ErrRegistry.New(CodeSyncFailed)
+}
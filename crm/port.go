@@ -0,0 +1,53 @@
+package crm
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// ConnectionRepository persists tenants' CRM connections
+type ConnectionRepository interface {
+	// Find returns the tenant's connection to provider, ErrConnectionNotFound
+	// when there is none
+	Find(ctx context.Context, tenantID kernel.TenantID, provider Provider) (*Connection, error)
+
+	List(ctx context.Context, tenantID kernel.TenantID) ([]Connection, error)
+
+	// Save creates or replaces the tenant's connection to the provider
+	Save(ctx context.Context, connection Connection) error
+
+	Delete(ctx context.Context, tenantID kernel.TenantID, provider Provider) error
+}
+
+// ============================================================================
+// Connector Interfaces
+// ============================================================================
+
+// Connector talks to one CRM's OAuth and REST APIs. API calls fail with
+// ErrTokenRejected when the access token is no longer valid.
+type Connector interface {
+	Provider() Provider
+
+	// DefaultMapping maps the common relay fields to the CRM's properties
+	DefaultMapping() FieldMapping
+
+	// AuthorizeURL is where an admin grants access; the CRM redirects to
+	// redirectURI with a code and state
+	AuthorizeURL(state, redirectURI string) string
+
+	// Exchange trades an authorization code for credentials and the ID of
+	// the account they grant access to
+	Exchange(ctx context.Context, code, redirectURI string) (*Credentials, string, error)
+
+	// Refresh renews the access token
+	Refresh(ctx context.Context, credentials Credentials) (*Credentials, error)
+
+	// Write performs the record's operation
+	Write(ctx context.Context, credentials Credentials, record Record) (*engine.CRMSyncResult, error)
+}
@@ -0,0 +1,208 @@
+package encryption
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/pkg/aesgcm"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// keyringTTL bounds how long an instance keeps sealing with a key after another
+// instance rotated it. Values sealed in that window stay readable because
+// retired keys are kept, and the next rotation re-encrypts them.
+const keyringTTL = 5 * time.Minute
+
+// tenantKeyring holds a tenant's unwrapped keys
+type tenantKeyring struct {
+	active   int // 0 = encryption disabled
+	aeads    map[int]cipher.AEAD
+	loadedAt time.Time
+}
+
+// FieldCipher transparently encrypts JSON column values with the tenant's
+// active data key. Tenants without a key, or a server without a master key,
+// get their values stored and returned unchanged.
+type FieldCipher struct {
+	keyRepo KeyRepository
+	wrapper KeyWrapper
+
+	mu       sync.RWMutex
+	keyrings map[kernel.TenantID]*tenantKeyring
+}
+
+// NewFieldCipher creates a field cipher. wrapper may be nil when no master key
+// is configured; encrypted values then fail to decrypt with ErrKeyUnavailable.
+func NewFieldCipher(keyRepo KeyRepository, wrapper KeyWrapper) *FieldCipher {
+	return &FieldCipher{
+		keyRepo:  keyRepo,
+		wrapper:  wrapper,
+		keyrings: make(map[kernel.TenantID]*tenantKeyring),
+	}
+}
+
+// Configured reports whether a master key is available
+func (c *FieldCipher) Configured() bool {
+	return c != nil && c.wrapper != nil
+}
+
+// EncryptJSON seals a JSON value with the tenant's active key. The result is
+// an Envelope, or the input itself when the tenant has encryption disabled.
+func (c *FieldCipher) EncryptJSON(ctx context.Context, tenantID kernel.TenantID, plaintext []byte) ([]byte, error) {
+	if !c.Configured() || len(plaintext) == 0 {
+		return plaintext, nil
+	}
+
+	keyring, err := c.keyring(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if keyring.active == 0 {
+		return plaintext, nil
+	}
+
+	// The tenant ID is bound as associated data: a value copied to another
+	// tenant's row does not decrypt
+	sealed, err := aesgcm.SealBase64(keyring.aeads[keyring.active], plaintext, []byte(tenantID))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(Envelope{
+		Algorithm:  Algorithm,
+		KeyVersion: keyring.active,
+		Ciphertext: sealed,
+	})
+}
+
+// DecryptJSON opens a value produced by EncryptJSON. Plaintext JSON is
+// returned unchanged, so rows written before encryption was enabled still read.
+func (c *FieldCipher) DecryptJSON(ctx context.Context, tenantID kernel.TenantID, stored []byte) ([]byte, error) {
+	env, ok := ParseEnvelope(stored)
+	if !ok {
+		return stored, nil
+	}
+	if !c.Configured() {
+		return nil, ErrKeyUnavailable().WithDetail("reason", "master key not configured")
+	}
+
+	aead, err := c.aeadFor(ctx, tenantID, env.KeyVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aesgcm.OpenBase64(aead, env.Ciphertext, []byte(tenantID))
+	if err != nil {
+		return nil, ErrDecryptionFailed().
+			WithDetail("tenant_id", tenantID.String()).
+			WithDetail("key_version", env.KeyVersion).
+			WithCause(err)
+	}
+
+	return plaintext, nil
+}
+
+// ActiveVersion returns the tenant's active key version, 0 when disabled
+func (c *FieldCipher) ActiveVersion(ctx context.Context, tenantID kernel.TenantID) (int, error) {
+	if !c.Configured() {
+		return 0, nil
+	}
+	keyring, err := c.keyring(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	return keyring.active, nil
+}
+
+// Invalidate drops the cached keys of a tenant after a rotation
+func (c *FieldCipher) Invalidate(tenantID kernel.TenantID) {
+	c.mu.Lock()
+	delete(c.keyrings, tenantID)
+	c.mu.Unlock()
+}
+
+// NewDataKey generates a random AES-256 data key
+func NewDataKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errx.Wrap(err, "failed to generate data key", errx.TypeInternal)
+	}
+	return key, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func (c *FieldCipher) aeadFor(ctx context.Context, tenantID kernel.TenantID, version int) (cipher.AEAD, error) {
+	keyring, err := c.keyring(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, ok := keyring.aeads[version]
+	if !ok {
+		// Sealed by another instance with a key created after our cache
+		c.Invalidate(tenantID)
+		if keyring, err = c.keyring(ctx, tenantID); err != nil {
+			return nil, err
+		}
+		if aead, ok = keyring.aeads[version]; !ok {
+			return nil, ErrKeyUnavailable().
+				WithDetail("tenant_id", tenantID.String()).
+				WithDetail("key_version", version)
+		}
+	}
+
+	return aead, nil
+}
+
+func (c *FieldCipher) keyring(ctx context.Context, tenantID kernel.TenantID) (*tenantKeyring, error) {
+	c.mu.RLock()
+	keyring, ok := c.keyrings[tenantID]
+	c.mu.RUnlock()
+
+	if ok && time.Since(keyring.loadedAt) < keyringTTL {
+		return keyring, nil
+	}
+
+	keys, err := c.keyRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring = &tenantKeyring{
+		aeads:    make(map[int]cipher.AEAD, len(keys)),
+		loadedAt: time.Now(),
+	}
+	for _, key := range keys {
+		raw, err := c.wrapper.Unwrap(key.WrappedKey)
+		if err != nil {
+			return nil, ErrKeyUnavailable().
+				WithDetail("tenant_id", tenantID.String()).
+				WithDetail("key_version", key.Version).
+				WithCause(err)
+		}
+
+		aead, err := aesgcm.New(raw)
+		if err != nil {
+			return nil, ErrKeyUnavailable().WithCause(err)
+		}
+
+		keyring.aeads[key.Version] = aead
+		if key.IsActive() {
+			keyring.active = key.Version
+		}
+	}
+
+	c.mu.Lock()
+	c.keyrings[tenantID] = keyring
+	c.mu.Unlock()
+
+	return keyring, nil
+}
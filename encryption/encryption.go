@@ -0,0 +1,80 @@
+package encryption
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Tenant Keys
+// ============================================================================
+
+// KeyStatus lifecycle of a tenant data key
+type KeyStatus string
+
+const (
+	// KeyStatusActive is the key new values are sealed with; one per tenant
+	KeyStatusActive KeyStatus = "ACTIVE"
+	// KeyStatusRetired keys only decrypt values not yet re-encrypted
+	KeyStatusRetired KeyStatus = "RETIRED"
+)
+
+// TenantKey is a per-tenant AES-256 data key stored wrapped by the master key
+type TenantKey struct {
+	TenantID   kernel.TenantID `db:"tenant_id" json:"tenant_id"`
+	Version    int             `db:"version" json:"version"`
+	WrappedKey string          `db:"wrapped_key" json:"-"`
+	Status     KeyStatus       `db:"status" json:"status"`
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+	RetiredAt  *time.Time      `db:"retired_at" json:"retired_at,omitempty"`
+}
+
+// IsActive reports whether new values are sealed with this key
+func (k *TenantKey) IsActive() bool {
+	return k.Status == KeyStatusActive
+}
+
+// ============================================================================
+// Envelope
+// ============================================================================
+
+// Algorithm identifies the envelope format
+const Algorithm = "aes-256-gcm"
+
+// Envelope replaces an encrypted JSONB value. It is itself valid JSON so the
+// column type does not change, and plaintext rows keep working side by side.
+type Envelope struct {
+	Algorithm  string `json:"$enc"`
+	KeyVersion int    `json:"kv"`
+	Ciphertext string `json:"ct"` // base64(nonce || sealed)
+}
+
+// ParseEnvelope returns the envelope stored in raw, or false for plaintext JSON
+func ParseEnvelope(raw []byte) (*Envelope, bool) {
+	if len(raw) == 0 || raw[0] != '{' {
+		return nil, false
+	}
+
+	var env Envelope
+	if err := json.Unmarshal(raw, &env); err != nil || env.Algorithm == "" || env.Ciphertext == "" {
+		return nil, false
+	}
+
+	return &env, true
+}
+
+// ============================================================================
+// DTOs
+// ============================================================================
+
+// Status describes a tenant's encryption state
+type Status struct {
+	TenantID        kernel.TenantID `json:"tenant_id"`
+	Enabled         bool            `json:"enabled"`
+	ActiveVersion   int             `json:"active_version,omitempty"`
+	Keys            []*TenantKey    `json:"keys"`
+	PendingMessages int64           `json:"pending_messages"` // Not yet sealed with the active key
+	Reencrypting    bool            `json:"reencrypting"`
+}
@@ -0,0 +1,68 @@
+package encryptionapi
+
+import (
+	"github.com/Abraxas-365/relay/encryption/encryptionsrv"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// EncryptionHandler exposes per-tenant field encryption management
+type EncryptionHandler struct {
+	service *encryptionsrv.EncryptionService
+}
+
+// NewEncryptionHandler creates a new encryption handler
+func NewEncryptionHandler(service *encryptionsrv.EncryptionService) *EncryptionHandler {
+	return &EncryptionHandler{
+		service: service,
+	}
+}
+
+// GetStatus returns the tenant's keys and re-encryption progress
+// GET /api/encryption
+func (h *EncryptionHandler) GetStatus(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	status, err := h.service.GetStatus(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(status)
+}
+
+// Enable turns on encryption of message content for the tenant
+// POST /api/encryption/enable
+func (h *EncryptionHandler) Enable(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	key, err := h.service.Enable(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(key)
+}
+
+// Rotate replaces the tenant's data key and re-encrypts stored messages
+// POST /api/encryption/rotate
+func (h *EncryptionHandler) Rotate(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	key, err := h.service.Rotate(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(key)
+}
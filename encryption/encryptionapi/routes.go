@@ -0,0 +1,29 @@
+package encryptionapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// EncryptionRoutes handles encryption route setup
+type EncryptionRoutes struct {
+	handler        *EncryptionHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewEncryptionRoutes creates a new encryption routes instance
+func NewEncryptionRoutes(handler *EncryptionHandler, authMiddleware *auth.AuthMiddleware) *EncryptionRoutes {
+	return &EncryptionRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers encryption routes on an authenticated router; admins only
+func (r *EncryptionRoutes) RegisterRoutes(router fiber.Router) {
+	encryption := router.Group("/encryption", r.authMiddleware.RequireAdmin())
+
+	encryption.Get("/", r.handler.GetStatus)
+	encryption.Post("/enable", r.handler.Enable)
+	encryption.Post("/rotate", r.handler.Rotate)
+}
@@ -0,0 +1,29 @@
+package encryptioninfra
+
+import (
+	"crypto/cipher"
+
+	"github.com/Abraxas-365/relay/encryption"
+	"github.com/Abraxas-365/relay/pkg/aesgcm"
+)
+
+// AESKeyWrapper wraps tenant data keys with AES-256-GCM under the master key
+type AESKeyWrapper struct {
+	aead cipher.AEAD
+}
+
+var _ encryption.KeyWrapper = (*AESKeyWrapper)(nil)
+
+// NewAESKeyWrapper derives the 32-byte wrapping key from the master key with SHA-256
+func NewAESKeyWrapper(masterKey string) *AESKeyWrapper {
+	return &AESKeyWrapper{aead: aesgcm.NewFromSecret(masterKey)}
+}
+
+func (w *AESKeyWrapper) Wrap(key []byte) (string, error) {
+	return aesgcm.SealBase64(w.aead, key, nil)
+}
+
+// Unwrap fails with a wrong master key, or when the row was tampered with
+func (w *AESKeyWrapper) Unwrap(wrapped string) ([]byte, error) {
+	return aesgcm.OpenBase64(w.aead, wrapped, nil)
+}
@@ -0,0 +1,75 @@
+package encryptioninfra
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/encryption"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresKeyRepository is the PostgreSQL implementation of encryption.KeyRepository
+type PostgresKeyRepository struct {
+	db *sqlx.DB
+}
+
+var _ encryption.KeyRepository = (*PostgresKeyRepository)(nil)
+
+func NewPostgresKeyRepository(db *sqlx.DB) *PostgresKeyRepository {
+	return &PostgresKeyRepository{db: db}
+}
+
+func (r *PostgresKeyRepository) FindByTenant(ctx context.Context, tenantID kernel.TenantID) ([]*encryption.TenantKey, error) {
+	query := `
+		SELECT tenant_id, version, wrapped_key, status, created_at, retired_at
+		FROM tenant_encryption_keys
+		WHERE tenant_id = $1
+		ORDER BY version DESC`
+
+	var keys []*encryption.TenantKey
+	if err := r.db.SelectContext(ctx, &keys, query, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to find tenant keys", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	return keys, nil
+}
+
+func (r *PostgresKeyRepository) Rotate(ctx context.Context, tenantID kernel.TenantID, wrappedKey string) (*encryption.TenantKey, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to begin transaction", errx.TypeInternal)
+	}
+	defer tx.Rollback()
+
+	// Serializes concurrent rotations of the same tenant
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, "tenant_key:"+tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to lock tenant keys", errx.TypeInternal)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE tenant_encryption_keys
+		SET status = 'RETIRED', retired_at = NOW()
+		WHERE tenant_id = $1 AND status = 'ACTIVE'`, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to retire tenant key", errx.TypeInternal)
+	}
+
+	var key encryption.TenantKey
+	err = tx.GetContext(ctx, &key, `
+		INSERT INTO tenant_encryption_keys (tenant_id, version, wrapped_key, status)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2, 'ACTIVE'
+		FROM tenant_encryption_keys
+		WHERE tenant_id = $1
+		RETURNING tenant_id, version, wrapped_key, status, created_at, retired_at`,
+		tenantID.String(), wrappedKey)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to create tenant key", errx.TypeInternal)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errx.Wrap(err, "failed to commit key rotation", errx.TypeInternal)
+	}
+
+	return &key, nil
+}
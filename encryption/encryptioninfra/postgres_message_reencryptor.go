@@ -0,0 +1,171 @@
+package encryptioninfra
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/encryption"
+	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+// reencryptBatchSize rows rewritten per transaction
+const reencryptBatchSize = 500
+
+// pendingCondition matches message rows whose content, context or raw payload is not sealed
+// with the active key version ($2, compared as text so plaintext never fails a cast)
+const pendingCondition = `
+	tenant_id = $1 AND (
+		content->>'$enc' IS NULL OR content->>'kv' <> $2
+		OR (context IS NOT NULL AND (context->>'$enc' IS NULL OR context->>'kv' <> $2))
+		OR (raw_payload IS NOT NULL AND (raw_payload->>'$enc' IS NULL OR raw_payload->>'kv' <> $2))
+	)`
+
+// PostgresMessageReencryptor rewrites the encrypted columns of the messages table
+type PostgresMessageReencryptor struct {
+	db     *sqlx.DB
+	router *database.Router // nil = every tenant's messages are in db
+}
+
+var _ encryption.Reencryptor = (*PostgresMessageReencryptor)(nil)
+
+func NewPostgresMessageReencryptor(db *sqlx.DB) *PostgresMessageReencryptor {
+	return &PostgresMessageReencryptor{db: db}
+}
+
+// SetRouter rewrites each tenant's messages in the database of its data region
+func (r *PostgresMessageReencryptor) SetRouter(router *database.Router) {
+	r.router = router
+}
+
+type pendingMessage struct {
+	ID      string          `db:"id"`
+	Content json.RawMessage `db:"content"`
+	Context json.RawMessage `db:"context"`
+	Raw     json.RawMessage `db:"raw_payload"`
+}
+
+func (r *PostgresMessageReencryptor) ReencryptTenant(ctx context.Context, tenantID kernel.TenantID, cipher *encryption.FieldCipher, activeVersion int) (int64, error) {
+	version := strconv.Itoa(activeVersion)
+
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	lastID := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		var rows []pendingMessage
+		err := db.SelectContext(ctx, &rows, `
+			SELECT id, content, context, raw_payload
+			FROM messages
+			WHERE `+pendingCondition+` AND id > $3
+			ORDER BY id
+			LIMIT $4`,
+			tenantID.String(), version, lastID, reencryptBatchSize)
+		if err != nil {
+			return total, errx.Wrap(err, "failed to load messages to re-encrypt", errx.TypeInternal)
+		}
+		if len(rows) == 0 {
+			return total, nil
+		}
+
+		n, err := rewriteBatch(ctx, db, tenantID, cipher, rows)
+		total += n
+		if err != nil {
+			return total, err
+		}
+
+		lastID = rows[len(rows)-1].ID
+	}
+}
+
+func (r *PostgresMessageReencryptor) CountPending(ctx context.Context, tenantID kernel.TenantID, activeVersion int) (int64, error) {
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = db.GetContext(ctx, &count, `SELECT COUNT(*) FROM messages WHERE `+pendingCondition,
+		tenantID.String(), strconv.Itoa(activeVersion))
+	if err != nil {
+		return 0, errx.Wrap(err, "failed to count messages to re-encrypt", errx.TypeInternal)
+	}
+	return count, nil
+}
+
+// conn is the database holding the tenant's messages
+func (r *PostgresMessageReencryptor) conn(ctx context.Context, tenantID kernel.TenantID) (*sqlx.DB, error) {
+	if r.router == nil {
+		return r.db, nil
+	}
+	db, err := r.router.For(ctx, tenantID)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to resolve tenant database", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+	return db, nil
+}
+
+func rewriteBatch(ctx context.Context, db *sqlx.DB, tenantID kernel.TenantID, cipher *encryption.FieldCipher, rows []pendingMessage) (int64, error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, errx.Wrap(err, "failed to begin transaction", errx.TypeInternal)
+	}
+	defer tx.Rollback()
+
+	for _, row := range rows {
+		content, err := reseal(ctx, cipher, tenantID, row.Content)
+		if err != nil {
+			return 0, errx.Wrap(err, "failed to re-encrypt content", errx.TypeInternal).
+				WithDetail("message_id", row.ID)
+		}
+		msgContext, err := reseal(ctx, cipher, tenantID, row.Context)
+		if err != nil {
+			return 0, errx.Wrap(err, "failed to re-encrypt context", errx.TypeInternal).
+				WithDetail("message_id", row.ID)
+		}
+
+		raw, err := reseal(ctx, cipher, tenantID, row.Raw)
+		if err != nil {
+			return 0, errx.Wrap(err, "failed to re-encrypt raw payload", errx.TypeInternal).
+				WithDetail("message_id", row.ID)
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE messages SET content = $1, context = $2, raw_payload = $3 WHERE id = $4 AND tenant_id = $5`,
+			content, msgContext, raw, row.ID, tenantID.String()); err != nil {
+			return 0, errx.Wrap(err, "failed to update message", errx.TypeInternal).
+				WithDetail("message_id", row.ID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, errx.Wrap(err, "failed to commit re-encryption batch", errx.TypeInternal)
+	}
+
+	return int64(len(rows)), nil
+}
+
+// reseal opens a value with whatever key sealed it and seals it with the active one
+func reseal(ctx context.Context, cipher *encryption.FieldCipher, tenantID kernel.TenantID, stored json.RawMessage) ([]byte, error) {
+	if len(stored) == 0 {
+		return nil, nil
+	}
+
+	plaintext, err := cipher.DecryptJSON(ctx, tenantID, stored)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.EncryptJSON(ctx, tenantID, plaintext)
+}
@@ -0,0 +1,199 @@
+package encryptionsrv
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/relay/encryption"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// reencryptTimeout upper bound of a single re-encryption run
+const reencryptTimeout = 6 * time.Hour
+
+// EncryptionService manages tenant data keys and the re-encryption job
+type EncryptionService struct {
+	keyRepo     encryption.KeyRepository
+	wrapper     encryption.KeyWrapper
+	cipher      *encryption.FieldCipher
+	reencryptor encryption.Reencryptor
+
+	mu      sync.Mutex
+	running map[kernel.TenantID]bool
+}
+
+// NewEncryptionService creates the service. wrapper is nil when the server has
+// no master key, in which case enabling encryption is refused.
+func NewEncryptionService(
+	keyRepo encryption.KeyRepository,
+	wrapper encryption.KeyWrapper,
+	cipher *encryption.FieldCipher,
+	reencryptor encryption.Reencryptor,
+) *EncryptionService {
+	return &EncryptionService{
+		keyRepo:     keyRepo,
+		wrapper:     wrapper,
+		cipher:      cipher,
+		reencryptor: reencryptor,
+		running:     make(map[kernel.TenantID]bool),
+	}
+}
+
+// GetStatus returns the tenant's keys and re-encryption progress
+func (s *EncryptionService) GetStatus(ctx context.Context, tenantID kernel.TenantID) (*encryption.Status, error) {
+	keys, err := s.keyRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &encryption.Status{
+		TenantID:     tenantID,
+		Keys:         keys,
+		Reencrypting: s.isRunning(tenantID),
+	}
+	for _, key := range keys {
+		if key.IsActive() {
+			status.Enabled = true
+			status.ActiveVersion = key.Version
+		}
+	}
+
+	if status.Enabled {
+		if status.PendingMessages, err = s.reencryptor.CountPending(ctx, tenantID, status.ActiveVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
+
+// Enable creates the tenant's first data key and encrypts existing messages
+func (s *EncryptionService) Enable(ctx context.Context, tenantID kernel.TenantID) (*encryption.TenantKey, error) {
+	if s.wrapper == nil {
+		return nil, encryption.ErrNotConfigured()
+	}
+
+	version, err := s.activeVersion(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 {
+		return nil, encryption.ErrAlreadyEnabled().WithDetail("active_version", version)
+	}
+
+	return s.newKey(ctx, tenantID)
+}
+
+// Rotate replaces the active key and re-encrypts every message with the new one.
+// Retired keys are kept to read values written by instances with a stale cache.
+func (s *EncryptionService) Rotate(ctx context.Context, tenantID kernel.TenantID) (*encryption.TenantKey, error) {
+	if s.wrapper == nil {
+		return nil, encryption.ErrNotConfigured()
+	}
+
+	version, err := s.activeVersion(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 {
+		return nil, encryption.ErrNotEnabled()
+	}
+
+	return s.newKey(ctx, tenantID)
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func (s *EncryptionService) newKey(ctx context.Context, tenantID kernel.TenantID) (*encryption.TenantKey, error) {
+	if !s.markRunning(tenantID) {
+		return nil, encryption.ErrRotationInProgress()
+	}
+
+	key, err := s.createKey(ctx, tenantID)
+	if err != nil {
+		s.markDone(tenantID)
+		return nil, err
+	}
+
+	log.Printf("🔐 Tenant %s data key v%d active", tenantID, key.Version)
+
+	go s.reencrypt(tenantID, key.Version)
+
+	return key, nil
+}
+
+func (s *EncryptionService) createKey(ctx context.Context, tenantID kernel.TenantID) (*encryption.TenantKey, error) {
+	raw, err := encryption.NewDataKey()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := s.wrapper.Wrap(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyRepo.Rotate(ctx, tenantID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cipher.Invalidate(tenantID)
+	return key, nil
+}
+
+func (s *EncryptionService) reencrypt(tenantID kernel.TenantID, version int) {
+	defer s.markDone(tenantID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), reencryptTimeout)
+	defer cancel()
+
+	start := time.Now()
+	count, err := s.reencryptor.ReencryptTenant(ctx, tenantID, s.cipher, version)
+	if err != nil {
+		log.Printf("⚠️  Re-encryption of tenant %s stopped after %d messages: %v", tenantID, count, err)
+		return
+	}
+
+	log.Printf("✅ Re-encrypted %d messages of tenant %s with key v%d in %s",
+		count, tenantID, version, time.Since(start).Round(time.Second))
+}
+
+func (s *EncryptionService) activeVersion(ctx context.Context, tenantID kernel.TenantID) (int, error) {
+	keys, err := s.keyRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range keys {
+		if key.IsActive() {
+			return key.Version, nil
+		}
+	}
+	return 0, nil
+}
+
+func (s *EncryptionService) markRunning(tenantID kernel.TenantID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[tenantID] {
+		return false
+	}
+	s.running[tenantID] = true
+	return true
+}
+
+func (s *EncryptionService) markDone(tenantID kernel.TenantID) {
+	s.mu.Lock()
+	delete(s.running, tenantID)
+	s.mu.Unlock()
+}
+
+func (s *EncryptionService) isRunning(tenantID kernel.TenantID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running[tenantID]
+}
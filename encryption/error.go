@@ -0,0 +1,54 @@
+package encryption
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("ENCRYPTION")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeNotConfigured      = ErrRegistry.Register("NOT_CONFIGURED", errx.TypeBusiness, http.StatusServiceUnavailable, "Field encryption is not configured on this server")
+	CodeAlreadyEnabled     = ErrRegistry.Register("ALREADY_ENABLED", errx.TypeConflict, http.StatusConflict, "Encryption is already enabled for this tenant")
+	CodeNotEnabled         = ErrRegistry.Register("NOT_ENABLED", errx.TypeBusiness, http.StatusConflict, "Encryption is not enabled for this tenant")
+	CodeRotationInProgress = ErrRegistry.Register("ROTATION_IN_PROGRESS", errx.TypeConflict, http.StatusConflict, "A key rotation is already in progress")
+	CodeKeyUnavailable     = ErrRegistry.Register("KEY_UNAVAILABLE", errx.TypeInternal, http.StatusInternalServerError, "Encryption key unavailable")
+	CodeDecryptionFailed   = ErrRegistry.Register("DECRYPTION_FAILED", errx.TypeInternal, http.StatusInternalServerError, "Failed to decrypt field")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrNotConfigured() *errx.Error {
+	return ErrRegistry.New(CodeNotConfigured)
+}
+
+func ErrAlreadyEnabled() *errx.Error {
+	return ErrRegistry.New(CodeAlreadyEnabled)
+}
+
+func ErrNotEnabled() *errx.Error {
+	return ErrRegistry.New(CodeNotEnabled)
+}
+
+func ErrRotationInProgress() *errx.Error {
+	return ErrRegistry.New(CodeRotationInProgress)
+}
+
+func ErrKeyUnavailable() *errx.Error {
+	return ErrRegistry.New(CodeKeyUnavailable)
+}
+
+func ErrDecryptionFailed() *errx.Error {
+	return ErrRegistry.New(CodeDecryptionFailed)
+}
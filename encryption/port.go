@@ -0,0 +1,41 @@
+package encryption
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// KeyRepository persists wrapped tenant data keys
+type KeyRepository interface {
+	// FindByTenant returns every key of the tenant, newest first
+	FindByTenant(ctx context.Context, tenantID kernel.TenantID) ([]*TenantKey, error)
+
+	// Rotate atomically retires the active key (if any) and stores a new
+	// active key with the next version
+	Rotate(ctx context.Context, tenantID kernel.TenantID, wrappedKey string) (*TenantKey, error)
+}
+
+// ============================================================================
+// Key Management
+// ============================================================================
+
+// KeyWrapper seals tenant data keys with the server master key
+type KeyWrapper interface {
+	Wrap(key []byte) (string, error)
+	Unwrap(wrapped string) ([]byte, error)
+}
+
+// Reencryptor rewrites stored encrypted fields with a tenant's active key
+type Reencryptor interface {
+	// ReencryptTenant seals every value of the tenant not already sealed with
+	// activeVersion, including plaintext rows written before encryption was enabled
+	ReencryptTenant(ctx context.Context, tenantID kernel.TenantID, cipher *FieldCipher, activeVersion int) (int64, error)
+
+	// CountPending counts the rows ReencryptTenant would rewrite
+	CountPending(ctx context.Context, tenantID kernel.TenantID, activeVersion int) (int64, error)
+}
@@ -0,0 +1,211 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Business Hours
+// ============================================================================
+
+// holidayDateLayout is the format of BusinessHours.Holidays entries
+const holidayDateLayout = "2006-01-02"
+
+// maxNextOpenSearchDays bounds the search for the next opening (a tenant could
+// configure a year of holidays)
+const maxNextOpenSearchDays = 370
+
+// BusinessHours is a tenant's weekly opening schedule and holiday calendar.
+// Times and holiday dates are interpreted in Timezone.
+type BusinessHours struct {
+	TenantID  kernel.TenantID        `json:"tenant_id"`
+	Timezone  string                 `json:"timezone"`           // IANA name, e.g. "America/Lima"
+	Weekly    map[string][]TimeRange `json:"weekly"`             // "monday" ... "sunday"
+	Holidays  []string               `json:"holidays,omitempty"` // "2025-12-25"
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// TimeRange is an opening window within a day, "09:00" to "18:00". End may be
+// "24:00" to stay open until midnight.
+type TimeRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// BusinessHoursStatus is the result of checking a schedule at a given instant
+type BusinessHoursStatus struct {
+	Open       bool       `json:"open"`
+	Holiday    bool       `json:"holiday"`
+	Configured bool       `json:"configured"` // false = no schedule, always open
+	Timezone   string     `json:"timezone"`
+	LocalTime  time.Time  `json:"local_time"`
+	NextOpenAt *time.Time `json:"next_open_at,omitempty"`
+}
+
+// AlwaysOpenStatus is reported for tenants without a schedule
+func AlwaysOpenStatus(at time.Time) BusinessHoursStatus {
+	return BusinessHoursStatus{
+		Open:      true,
+		Timezone:  "UTC",
+		LocalTime: at.UTC(),
+	}
+}
+
+// Validate checks the timezone, day names, ranges and holiday dates
+func (b BusinessHours) Validate() error {
+	if _, err := b.Location(); err != nil {
+		return ErrInvalidBusinessHours().WithDetail("reason", fmt.Sprintf("unknown timezone %q", b.Timezone))
+	}
+
+	for day, ranges := range b.Weekly {
+		if _, ok := parseWeekday(day); !ok {
+			return ErrInvalidBusinessHours().WithDetail("reason", fmt.Sprintf("unknown day %q", day))
+		}
+		for _, r := range ranges {
+			start, end, err := r.minutes()
+			if err != nil {
+				return ErrInvalidBusinessHours().
+					WithDetail("day", day).
+					WithDetail("reason", err.Error())
+			}
+			if start >= end {
+				return ErrInvalidBusinessHours().
+					WithDetail("day", day).
+					WithDetail("reason", fmt.Sprintf("range %s-%s must end after it starts", r.Start, r.End))
+			}
+		}
+	}
+
+	for _, date := range b.Holidays {
+		if _, err := time.Parse(holidayDateLayout, date); err != nil {
+			return ErrInvalidBusinessHours().WithDetail("reason", fmt.Sprintf("invalid holiday date %q, expected YYYY-MM-DD", date))
+		}
+	}
+
+	return nil
+}
+
+// Location returns the schedule's timezone; empty means UTC
+func (b BusinessHours) Location() (*time.Location, error) {
+	if b.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(b.Timezone)
+}
+
+// StatusAt reports whether the business is open at the given instant and,
+// when closed, when it opens next
+func (b BusinessHours) StatusAt(at time.Time) BusinessHoursStatus {
+	loc, err := b.Location()
+	if err != nil {
+		loc = time.UTC
+	}
+	local := at.In(loc)
+
+	status := BusinessHoursStatus{
+		Configured: true,
+		Timezone:   loc.String(),
+		LocalTime:  local,
+		Holiday:    b.isHoliday(local),
+	}
+
+	if !status.Holiday {
+		minute := local.Hour()*60 + local.Minute()
+		for _, r := range b.rangesFor(local.Weekday()) {
+			start, end, err := r.minutes()
+			if err == nil && minute >= start && minute < end {
+				status.Open = true
+				break
+			}
+		}
+	}
+
+	if !status.Open {
+		status.NextOpenAt = b.nextOpening(local)
+	}
+
+	return status
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func (b BusinessHours) rangesFor(weekday time.Weekday) []TimeRange {
+	for day, ranges := range b.Weekly {
+		if d, ok := parseWeekday(day); ok && d == weekday {
+			return ranges
+		}
+	}
+	return nil
+}
+
+func (b BusinessHours) isHoliday(local time.Time) bool {
+	return slices.Contains(b.Holidays, local.Format(holidayDateLayout))
+}
+
+func (b BusinessHours) nextOpening(local time.Time) *time.Time {
+	year, month, day := local.Date()
+
+	for offset := 0; offset <= maxNextOpenSearchDays; offset++ {
+		date := time.Date(year, month, day+offset, 0, 0, 0, 0, local.Location())
+		if b.isHoliday(date) {
+			continue
+		}
+
+		var next *time.Time
+		for _, r := range b.rangesFor(date.Weekday()) {
+			start, _, err := r.minutes()
+			if err != nil {
+				continue
+			}
+			candidate := time.Date(date.Year(), date.Month(), date.Day(), start/60, start%60, 0, 0, local.Location())
+			if candidate.After(local) && (next == nil || candidate.Before(*next)) {
+				next = &candidate
+			}
+		}
+		if next != nil {
+			return next
+		}
+	}
+
+	return nil
+}
+
+func (r TimeRange) minutes() (int, int, error) {
+	start, err := parseClock(r.Start, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(r.End, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// parseClock converts "HH:MM" to minutes since midnight
+func parseClock(s string, allowMidnightEnd bool) (int, error) {
+	if allowMidnightEnd && s == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), name) {
+			return d, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,66 @@
+package businesshours
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// BusinessHoursHandler exposes the tenant's business hours
+type BusinessHoursHandler struct {
+	service *BusinessHoursService
+}
+
+func NewBusinessHoursHandler(service *BusinessHoursService) *BusinessHoursHandler {
+	return &BusinessHoursHandler{
+		service: service,
+	}
+}
+
+// Get returns the tenant's schedule and whether it is open right now
+// GET /api/business-hours
+func (h *BusinessHoursHandler) Get(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	hours, err := h.service.Get(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	status, err := h.service.Status(c.Context(), authContext.TenantID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"business_hours": hours,
+		"status":         status,
+	})
+}
+
+// Update replaces the tenant's schedule
+// PUT /api/business-hours
+func (h *BusinessHoursHandler) Update(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req engine.BusinessHours
+	if err := c.BodyParser(&req); err != nil {
+		return engine.ErrInvalidBusinessHours().WithDetail("reason", err.Error())
+	}
+
+	hours, err := h.service.Update(c.Context(), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(hours)
+}
@@ -0,0 +1,27 @@
+package businesshours
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+type BusinessHoursRoutes struct {
+	handler        *BusinessHoursHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+func NewBusinessHoursRoutes(handler *BusinessHoursHandler, authMiddleware *auth.AuthMiddleware) *BusinessHoursRoutes {
+	return &BusinessHoursRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers business hours routes on an authenticated router.
+// Changing the schedule requires an admin.
+func (r *BusinessHoursRoutes) RegisterRoutes(router fiber.Router) {
+	hours := router.Group("/business-hours")
+
+	hours.Get("/", r.handler.Get)
+	hours.Put("/", r.authMiddleware.RequireAdmin(), r.handler.Update)
+}
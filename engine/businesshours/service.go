@@ -0,0 +1,136 @@
+package businesshours
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// settingKey is the tenant_config key holding the JSON schedule
+const settingKey = "business_hours"
+
+// cacheTTL bounds how long an update made on another instance takes to apply.
+// BUSINESS_HOURS nodes and in_business_hours() run on every message, so the
+// schedule is not read from the database each time.
+const cacheTTL = time.Minute
+
+type cachedHours struct {
+	hours     *engine.BusinessHours // nil = not configured
+	expiresAt time.Time
+}
+
+// BusinessHoursService stores tenant schedules and answers whether a tenant is
+// open at a given instant
+type BusinessHoursService struct {
+	tenantConfigRepo tenant.TenantConfigRepository
+
+	mu    sync.RWMutex
+	cache map[kernel.TenantID]cachedHours
+}
+
+var _ engine.BusinessHoursProvider = (*BusinessHoursService)(nil)
+
+func NewBusinessHoursService(tenantConfigRepo tenant.TenantConfigRepository) *BusinessHoursService {
+	return &BusinessHoursService{
+		tenantConfigRepo: tenantConfigRepo,
+		cache:            make(map[kernel.TenantID]cachedHours),
+	}
+}
+
+// Get returns the tenant's schedule, nil when none is configured
+func (s *BusinessHoursService) Get(ctx context.Context, tenantID kernel.TenantID) (*engine.BusinessHours, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[tenantID]
+	s.mu.RUnlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.hours, nil
+	}
+
+	hours, err := s.load(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[tenantID] = cachedHours{hours: hours, expiresAt: time.Now().Add(cacheTTL)}
+	s.mu.Unlock()
+
+	return hours, nil
+}
+
+// Update validates and replaces the tenant's schedule
+func (s *BusinessHoursService) Update(ctx context.Context, tenantID kernel.TenantID, hours engine.BusinessHours) (*engine.BusinessHours, error) {
+	if err := hours.Validate(); err != nil {
+		return nil, err
+	}
+
+	hours.TenantID = tenantID
+	hours.UpdatedAt = time.Now()
+
+	data, err := json.Marshal(hours)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to encode business hours", errx.TypeInternal)
+	}
+
+	if err := s.tenantConfigRepo.SaveSetting(ctx, tenantID, settingKey, string(data)); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[tenantID] = cachedHours{hours: &hours, expiresAt: time.Now().Add(cacheTTL)}
+	s.mu.Unlock()
+
+	log.Printf("🕘 Business hours updated for tenant %s (timezone: %s)", tenantID, hours.Timezone)
+	return &hours, nil
+}
+
+// Status implements engine.BusinessHoursProvider. Tenants without a schedule
+// are always open.
+func (s *BusinessHoursService) Status(ctx context.Context, tenantID kernel.TenantID, at time.Time) (*engine.BusinessHoursStatus, error) {
+	hours, err := s.Get(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var status engine.BusinessHoursStatus
+	if hours == nil {
+		status = engine.AlwaysOpenStatus(at)
+	} else {
+		status = hours.StatusAt(at)
+	}
+
+	return &status, nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func (s *BusinessHoursService) load(ctx context.Context, tenantID kernel.TenantID) (*engine.BusinessHours, error) {
+	settings, err := s.tenantConfigRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, ok := settings[settingKey]
+	if !ok || raw == "" {
+		return nil, nil
+	}
+
+	var hours engine.BusinessHours
+	if err := json.Unmarshal([]byte(raw), &hours); err != nil {
+		return nil, errx.Wrap(err, "failed to decode business hours", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+	hours.TenantID = tenantID
+
+	return &hours, nil
+}
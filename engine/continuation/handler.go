@@ -0,0 +1,132 @@
+package continuation
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// ContinuationHandler exposes the tenant's paused workflows
+type ContinuationHandler struct {
+	service *ContinuationService
+}
+
+func NewContinuationHandler(service *ContinuationService) *ContinuationHandler {
+	return &ContinuationHandler{
+		service: service,
+	}
+}
+
+// List returns the tenant's pending continuations, soonest first
+// GET /api/continuations?workflow_id=&page=1&page_size=50
+func (h *ContinuationHandler) List(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", defaultPageSize)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	req := engine.ContinuationListRequest{
+		PaginationOptions: storex.PaginationOptions{
+			Page:     page,
+			PageSize: pageSize,
+		},
+		TenantID: authContext.TenantID,
+	}
+	if workflowID := c.Query("workflow_id"); workflowID != "" {
+		id := kernel.NewWorkflowID(workflowID)
+		req.WorkflowID = &id
+	}
+
+	continuations, err := h.service.List(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(continuations)
+}
+
+// Stats returns how many continuations are pending per workflow
+// GET /api/continuations/stats
+func (h *ContinuationHandler) Stats(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	stats, err := h.service.Stats(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(stats)
+}
+
+// Get returns a continuation with its saved context
+// GET /api/continuations/:id
+func (h *ContinuationHandler) Get(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	continuation, err := h.service.Get(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(continuation)
+}
+
+// Cancel drops a pending continuation
+// DELETE /api/continuations/:id
+func (h *ContinuationHandler) Cancel(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := h.service.Cancel(c.Context(), c.Params("id"), authContext.TenantID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Resume resumes a pending continuation now
+// POST /api/continuations/:id/resume
+func (h *ContinuationHandler) Resume(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	continuation, err := h.service.ResumeNow(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"continuation": continuation,
+		"resuming":     true,
+	})
+}
@@ -0,0 +1,30 @@
+package continuation
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ContinuationRoutes struct {
+	handler        *ContinuationHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+func NewContinuationRoutes(handler *ContinuationHandler, authMiddleware *auth.AuthMiddleware) *ContinuationRoutes {
+	return &ContinuationRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers continuation routes on an authenticated router.
+// Cancelling or resuming changes what workflows do, so it requires an admin.
+func (r *ContinuationRoutes) RegisterRoutes(router fiber.Router) {
+	continuations := router.Group("/continuations")
+
+	continuations.Get("/", r.handler.List)
+	continuations.Get("/stats", r.handler.Stats)
+	continuations.Get("/:id", r.handler.Get)
+	continuations.Delete("/:id", r.authMiddleware.RequireAdmin(), r.handler.Cancel)
+	continuations.Post("/:id/resume", r.authMiddleware.RequireAdmin(), r.handler.Resume)
+}
@@ -0,0 +1,87 @@
+package continuation
+
+import (
+	"context"
+	"log"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ContinuationService inspects and manages the workflows paused on a DELAY
+// node. Continuations are only visible to their own tenant.
+type ContinuationService struct {
+	scheduler engine.DelayScheduler
+}
+
+func NewContinuationService(scheduler engine.DelayScheduler) *ContinuationService {
+	return &ContinuationService{
+		scheduler: scheduler,
+	}
+}
+
+// Stats is the tenant's pending continuations by workflow
+type Stats struct {
+	Total      int64            `json:"total"`
+	ByWorkflow map[string]int64 `json:"by_workflow"`
+}
+
+// List returns the tenant's pending continuations, soonest first
+func (s *ContinuationService) List(ctx context.Context, req engine.ContinuationListRequest) (engine.ContinuationListResponse, error) {
+	return s.scheduler.ListPending(ctx, req)
+}
+
+// Stats counts the tenant's pending continuations per workflow
+func (s *ContinuationService) Stats(ctx context.Context, tenantID kernel.TenantID) (*Stats, error) {
+	byWorkflow, err := s.scheduler.GetPendingCountByWorkflow(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{ByWorkflow: byWorkflow}
+	for _, count := range byWorkflow {
+		stats.Total += count
+	}
+	return stats, nil
+}
+
+// Get returns one of the tenant's continuations
+func (s *ContinuationService) Get(ctx context.Context, id string, tenantID kernel.TenantID) (*engine.WorkflowContinuation, error) {
+	continuation, err := s.scheduler.GetContinuation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if continuation.TenantID != tenantID.String() {
+		return nil, engine.ErrContinuationNotFound().WithDetail("continuation_id", id)
+	}
+	return continuation, nil
+}
+
+// Cancel drops a pending continuation; the workflow never resumes
+func (s *ContinuationService) Cancel(ctx context.Context, id string, tenantID kernel.TenantID) error {
+	continuation, err := s.Get(ctx, id, tenantID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.scheduler.Cancel(ctx, id); err != nil {
+		return err
+	}
+
+	log.Printf("🚫 Continuation %s of workflow %s cancelled", id, continuation.WorkflowID)
+	return nil
+}
+
+// ResumeNow resumes a pending continuation without waiting for its delay
+func (s *ContinuationService) ResumeNow(ctx context.Context, id string, tenantID kernel.TenantID) (*engine.WorkflowContinuation, error) {
+	continuation, err := s.Get(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduler.ResumeNow(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return continuation, nil
+}
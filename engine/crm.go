@@ -0,0 +1,41 @@
+package engine
+
+// ============================================================================
+// CRM Sync
+// ============================================================================
+
+// CRMOperation is what a CRM_SYNC node does in the tenant's CRM
+type CRMOperation string
+
+const (
+	CRMUpsertContact CRMOperation = "upsert_contact" // Create or update the contact matching match_field
+	CRMLogActivity   CRMOperation = "log_activity"   // Note (HubSpot) or completed task (Salesforce)
+	CRMCreateDeal    CRMOperation = "create_deal"    // Deal (HubSpot) or opportunity (Salesforce)
+	CRMCreateTicket  CRMOperation = "create_ticket"  // Ticket (HubSpot) or case (Salesforce)
+)
+
+// CRMOperations are the operations every connector supports
+var CRMOperations = []CRMOperation{CRMUpsertContact, CRMLogActivity, CRMCreateDeal, CRMCreateTicket}
+
+// DefaultCRMMatchField identifies the contact to upsert when none is given
+const DefaultCRMMatchField = "email"
+
+// CRMSyncRequest is one write to the tenant's CRM. Fields are keyed by relay
+// field (email, first_name, ...) or by CRM property; the tenant's field
+// mapping translates the former.
+type CRMSyncRequest struct {
+	Provider  string // Empty = the tenant's only connection
+	Operation CRMOperation
+	Fields    map[string]any
+
+	MatchField string // upsert_contact; DefaultCRMMatchField when empty
+	ContactID  string // CRM contact the activity, deal or ticket is linked to
+}
+
+// CRMSyncResult is the record written
+type CRMSyncResult struct {
+	Provider  string       `json:"provider"`
+	Operation CRMOperation `json:"operation"`
+	RecordID  string       `json:"record_id"`
+	Created   bool         `json:"created"` // false when upsert_contact updated a contact
+}
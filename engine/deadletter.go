@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Dead Letters
+// ============================================================================
+
+// DeadLetterStatus is the state of a dead-lettered trigger
+type DeadLetterStatus string
+
+const (
+	DeadLetterPending     DeadLetterStatus = "PENDING"     // Failed, waiting for a retry
+	DeadLetterReprocessed DeadLetterStatus = "REPROCESSED" // A retry succeeded
+)
+
+// IsValid checks the status is known
+func (s DeadLetterStatus) IsValid() bool {
+	return s == DeadLetterPending || s == DeadLetterReprocessed
+}
+
+// DeadLetter is a trigger whose processing failed, kept with its payload so it
+// can be reprocessed instead of being lost. WorkflowID is nil when the failure
+// happened before any workflow was matched.
+type DeadLetter struct {
+	ID            string             `json:"id"`
+	TenantID      kernel.TenantID    `json:"tenant_id"`
+	TriggerType   TriggerType        `json:"trigger_type"`
+	WorkflowID    *kernel.WorkflowID `json:"workflow_id,omitempty"`
+	Filters       map[string]any     `json:"filters,omitempty"`
+	Payload       map[string]any     `json:"payload"`
+	Error         string             `json:"error"`
+	Status        DeadLetterStatus   `json:"status"`
+	RetryCount    int                `json:"retry_count"`
+	LastRetriedAt *time.Time         `json:"last_retried_at,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
@@ -0,0 +1,105 @@
+package deadletter
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// DeadLetterHandler exposes the tenant's failed triggers
+type DeadLetterHandler struct {
+	service *DeadLetterService
+}
+
+func NewDeadLetterHandler(service *DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		service: service,
+	}
+}
+
+// List returns the tenant's dead letters, newest first
+// GET /api/dead-letters?status=PENDING&trigger_type=CHANNEL_WEBHOOK&page=1&page_size=50
+func (h *DeadLetterHandler) List(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", defaultPageSize)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	req := engine.DeadLetterListRequest{
+		PaginationOptions: storex.PaginationOptions{
+			Page:     page,
+			PageSize: pageSize,
+		},
+		TenantID: authContext.TenantID,
+	}
+
+	if status := c.Query("status"); status != "" {
+		s := engine.DeadLetterStatus(status)
+		if !s.IsValid() {
+			return engine.ErrInvalidDeadLetterQuery().WithDetail("status", status)
+		}
+		req.Status = &s
+	}
+	if triggerType := c.Query("trigger_type"); triggerType != "" {
+		t := engine.TriggerType(triggerType)
+		req.TriggerType = &t
+	}
+
+	letters, err := h.service.List(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(letters)
+}
+
+// Get returns a dead letter with its payload
+// GET /api/dead-letters/:id
+func (h *DeadLetterHandler) Get(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	dl, err := h.service.Get(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(dl)
+}
+
+// Retry reprocesses a dead letter and returns it with the outcome
+// POST /api/dead-letters/:id/retry
+func (h *DeadLetterHandler) Retry(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	dl, err := h.service.Retry(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(dl)
+}
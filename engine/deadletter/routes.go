@@ -0,0 +1,28 @@
+package deadletter
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+type DeadLetterRoutes struct {
+	handler        *DeadLetterHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+func NewDeadLetterRoutes(handler *DeadLetterHandler, authMiddleware *auth.AuthMiddleware) *DeadLetterRoutes {
+	return &DeadLetterRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers dead letter routes on an authenticated router.
+// Retrying runs workflows again, so it requires an admin.
+func (r *DeadLetterRoutes) RegisterRoutes(router fiber.Router) {
+	letters := router.Group("/dead-letters")
+
+	letters.Get("/", r.handler.List)
+	letters.Get("/:id", r.handler.Get)
+	letters.Post("/:id/retry", r.authMiddleware.RequireAdmin(), r.handler.Retry)
+}
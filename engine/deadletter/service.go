@@ -0,0 +1,66 @@
+package deadletter
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/engine/triggerhandler"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// DeadLetterService lists failed triggers and reprocesses them
+type DeadLetterService struct {
+	repo           engine.DeadLetterRepository
+	triggerHandler *triggerhandler.TriggerHandler
+}
+
+func NewDeadLetterService(repo engine.DeadLetterRepository, triggerHandler *triggerhandler.TriggerHandler) *DeadLetterService {
+	return &DeadLetterService{
+		repo:           repo,
+		triggerHandler: triggerHandler,
+	}
+}
+
+// List returns the tenant's dead letters, newest first
+func (s *DeadLetterService) List(ctx context.Context, req engine.DeadLetterListRequest) (engine.DeadLetterListResponse, error) {
+	return s.repo.List(ctx, req)
+}
+
+// Get returns one of the tenant's dead letters
+func (s *DeadLetterService) Get(ctx context.Context, id string, tenantID kernel.TenantID) (*engine.DeadLetter, error) {
+	return s.repo.FindByID(ctx, id, tenantID)
+}
+
+// Retry reprocesses a pending dead letter and records the outcome on it. A
+// failed retry is not an error: the letter stays PENDING with the new error.
+func (s *DeadLetterService) Retry(ctx context.Context, id string, tenantID kernel.TenantID) (*engine.DeadLetter, error) {
+	dl, err := s.repo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if dl.Status == engine.DeadLetterReprocessed {
+		return nil, engine.ErrDeadLetterReprocessed().WithDetail("dead_letter_id", id)
+	}
+
+	retryErr := s.triggerHandler.Reprocess(ctx, dl)
+
+	now := time.Now()
+	dl.RetryCount++
+	dl.LastRetriedAt = &now
+	dl.UpdatedAt = now
+	if retryErr != nil {
+		log.Printf("⚠️  Dead letter %s retry %d failed: %v", dl.ID, dl.RetryCount, retryErr)
+		dl.Error = retryErr.Error()
+	} else {
+		log.Printf("✅ Dead letter %s reprocessed", dl.ID)
+		dl.Status = engine.DeadLetterReprocessed
+	}
+
+	if err := s.repo.Save(ctx, *dl); err != nil {
+		return nil, err
+	}
+
+	return dl, nil
+}
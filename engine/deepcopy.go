@@ -0,0 +1,84 @@
+package engine
+
+import "reflect"
+
+// ============================================================================
+// Deep Copy
+// ============================================================================
+
+// DeepCopyMap returns a copy of m that shares no maps or slices with it. The
+// executor uses it so node executors can't mutate the stored workflow or data
+// shared with other executions.
+func DeepCopyMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = DeepCopy(v)
+	}
+	return out
+}
+
+// DeepCopy copies maps and slices recursively. Other values (scalars,
+// structs, pointers) are returned as is.
+func DeepCopy(v any) any {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		return DeepCopyMap(val)
+	case []any:
+		if val == nil {
+			return val
+		}
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = DeepCopy(item)
+		}
+		return out
+	case string, bool, float64, int, int64:
+		return val
+	}
+
+	return deepCopyReflect(reflect.ValueOf(v)).Interface()
+}
+
+// deepCopyReflect handles typed maps and slices (map[string]string,
+// []map[string]any, ...)
+func deepCopyReflect(val reflect.Value) reflect.Value {
+	switch val.Kind() {
+	case reflect.Map:
+		if val.IsNil() {
+			return val
+		}
+		out := reflect.MakeMapWithSize(val.Type(), val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), deepCopyElem(iter.Value(), val.Type().Elem()))
+		}
+		return out
+
+	case reflect.Slice:
+		if val.IsNil() {
+			return val
+		}
+		out := reflect.MakeSlice(val.Type(), val.Len(), val.Len())
+		for i := 0; i < val.Len(); i++ {
+			out.Index(i).Set(deepCopyElem(val.Index(i), val.Type().Elem()))
+		}
+		return out
+	}
+
+	return val
+}
+
+func deepCopyElem(val reflect.Value, elemType reflect.Type) reflect.Value {
+	if val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return reflect.Zero(elemType)
+		}
+		return reflect.ValueOf(DeepCopy(val.Interface()))
+	}
+	return deepCopyReflect(val)
+}
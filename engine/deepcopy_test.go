@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func nestedContext() map[string]any {
+	return map[string]any{
+		"message": map[string]any{
+			"text":        "hello",
+			"attachments": []any{map[string]any{"url": "https://example.com/a.png"}},
+		},
+		"headers": map[string]string{"x-request-id": "abc"},
+		"items":   []map[string]any{{"sku": "A1", "qty": 1.0}},
+		"tags":    []string{"vip"},
+		"count":   3,
+	}
+}
+
+// Parallel branches copy the same shared context and mutate every nested
+// level of their copy. Run with -race: any map or slice still shared with
+// the source or with another branch is reported.
+func TestDeepCopyMapParallelMutation(t *testing.T) {
+	shared := nestedContext()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(branch int) {
+			defer wg.Done()
+
+			ctx := DeepCopyMap(shared)
+			value := fmt.Sprintf("branch-%d", branch)
+
+			message := ctx["message"].(map[string]any)
+			message["text"] = value
+			attachment := message["attachments"].([]any)[0].(map[string]any)
+			attachment["url"] = value
+			message["attachments"] = append(message["attachments"].([]any), value)
+
+			ctx["headers"].(map[string]string)["x-request-id"] = value
+			ctx["items"].([]map[string]any)[0]["qty"] = float64(branch)
+			ctx["tags"].([]string)[0] = value
+			ctx["count"] = branch
+
+			if got := ctx["message"].(map[string]any)["text"]; got != value {
+				t.Errorf("branch %d sees text %v", branch, got)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if !reflect.DeepEqual(shared, nestedContext()) {
+		t.Errorf("shared context was mutated: %#v", shared)
+	}
+}
+
+func TestDeepCopyKeepsNilAndScalars(t *testing.T) {
+	var nilMap map[string]string
+	src := map[string]any{
+		"nil":       nil,
+		"nil_map":   nilMap,
+		"nil_slice": []any(nil),
+		"string":    "text",
+		"float":     1.5,
+		"bool":      true,
+	}
+
+	got := DeepCopyMap(src)
+	if !reflect.DeepEqual(got, src) {
+		t.Errorf("DeepCopyMap() = %#v, want %#v", got, src)
+	}
+	if DeepCopyMap(nil) != nil {
+		t.Error("DeepCopyMap(nil) should be nil")
+	}
+}
@@ -3,18 +3,24 @@ package delayscheduler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"time"
 
+	"github.com/Abraxas-365/craftable/storex"
 	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 )
 
 const (
-	delayedExecutionsKey = "relay:delayed_executions" // Sorted set
-	continuationPrefix   = "relay:continuation:"      // Hash keys
+	delayedExecutionsKey = "relay:delayed_executions"    // Sorted set
+	continuationPrefix   = "relay:continuation:"         // Hash keys
+	tenantIndexPrefix    = "relay:continuations:tenant:" // Sorted set of a tenant's pending IDs, same scores
+	tenantWorkflowSuffix = ":workflows"                  // Hash of a tenant's pending ID -> workflow ID
 	syncDelayThreshold   = 30 * time.Second
 )
 
@@ -74,6 +80,10 @@ func (r *RedisDelayScheduler) Schedule(
 		return fmt.Errorf("failed to schedule continuation: %w", err)
 	}
 
+	if err := r.index(ctx, continuation, score); err != nil {
+		log.Printf("⚠️  Failed to index continuation %s for tenant %s: %v", continuation.ID, continuation.TenantID, err)
+	}
+
 	log.Printf("⏰ Scheduled continuation %s for %v (delay: %v)",
 		continuation.ID, continuation.ScheduledFor, delay)
 
@@ -182,6 +192,9 @@ func (r *RedisDelayScheduler) executeJob(ctx context.Context, jobID string) {
 		return
 	}
 
+	// Claimed, so it is no longer pending
+	r.unindex(ctx, continuation.TenantID, jobID)
+
 	// Execute continuation handler
 	if r.onContinuation != nil {
 		if err := r.onContinuation(ctx, &continuation); err != nil {
@@ -205,6 +218,9 @@ func (r *RedisDelayScheduler) GetContinuation(ctx context.Context, id string) (*
 	key := fmt.Sprintf("%s%s", continuationPrefix, id)
 	data, err := r.redis.Get(ctx, key).Result()
 	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, engine.ErrContinuationNotFound().WithDetail("continuation_id", id)
+		}
 		return nil, err
 	}
 
@@ -218,13 +234,215 @@ func (r *RedisDelayScheduler) GetContinuation(ctx context.Context, id string) (*
 
 // Cancel cancels a scheduled continuation
 func (r *RedisDelayScheduler) Cancel(ctx context.Context, id string) error {
-	// Remove from sorted set
-	if err := r.redis.ZRem(ctx, delayedExecutionsKey, id).Err(); err != nil {
+	continuation, err := r.GetContinuation(ctx, id)
+	if err != nil {
 		return err
 	}
 
+	// Remove from sorted set; zero removed means the worker already claimed it
+	removed, err := r.redis.ZRem(ctx, delayedExecutionsKey, id).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return engine.ErrContinuationNotFound().
+			WithDetail("continuation_id", id).
+			WithDetail("reason", "continuation is already resuming")
+	}
+
+	r.unindex(ctx, continuation.TenantID, id)
+
 	// Delete continuation data
 	key := fmt.Sprintf("%s%s", continuationPrefix, id)
 	return r.redis.Del(ctx, key).Err()
 }
 
+// ============================================================================
+// Tenant Inspection
+// ============================================================================
+
+// ListPending returns the tenant's pending continuations, soonest first.
+// Continuations scheduled before tenant indexing existed are not listed.
+func (r *RedisDelayScheduler) ListPending(ctx context.Context, req engine.ContinuationListRequest) (engine.ContinuationListResponse, error) {
+	if req.WorkflowID != nil {
+		return r.listPendingForWorkflow(ctx, req)
+	}
+
+	indexKey := tenantIndexKey(req.TenantID.String())
+
+	total, err := r.redis.ZCard(ctx, indexKey).Result()
+	if err != nil {
+		return engine.ContinuationListResponse{}, fmt.Errorf("failed to count continuations: %w", err)
+	}
+
+	start := int64(req.GetOffset())
+	ids, err := r.redis.ZRange(ctx, indexKey, start, start+int64(req.PageSize)-1).Result()
+	if err != nil {
+		return engine.ContinuationListResponse{}, fmt.Errorf("failed to list continuations: %w", err)
+	}
+
+	continuations := make([]engine.WorkflowContinuation, 0, len(ids))
+	if len(ids) > 0 {
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = continuationPrefix + id
+		}
+
+		values, err := r.redis.MGet(ctx, keys...).Result()
+		if err != nil {
+			return engine.ContinuationListResponse{}, fmt.Errorf("failed to load continuations: %w", err)
+		}
+
+		for i, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				// Resumed or expired since it was indexed
+				r.unindex(ctx, req.TenantID.String(), ids[i])
+				total--
+				continue
+			}
+
+			var continuation engine.WorkflowContinuation
+			if err := json.Unmarshal([]byte(data), &continuation); err != nil {
+				log.Printf("⚠️  Skipping unreadable continuation %s: %v", ids[i], err)
+				continue
+			}
+			continuations = append(continuations, continuation)
+		}
+	}
+
+	return storex.NewPaginated(continuations, req.Page, req.PageSize, int(max(total, 0))), nil
+}
+
+// listPendingForWorkflow pages through the continuations of one workflow. The
+// sorted index has no workflow, so they are picked from the workflows hash
+// and ordered in memory.
+func (r *RedisDelayScheduler) listPendingForWorkflow(ctx context.Context, req engine.ContinuationListRequest) (engine.ContinuationListResponse, error) {
+	workflows, err := r.redis.HGetAll(ctx, tenantWorkflowsKey(req.TenantID.String())).Result()
+	if err != nil {
+		return engine.ContinuationListResponse{}, fmt.Errorf("failed to list continuations: %w", err)
+	}
+
+	var keys []string
+	for id, workflowID := range workflows {
+		if workflowID == req.WorkflowID.String() {
+			keys = append(keys, continuationPrefix+id)
+		}
+	}
+
+	continuations := make([]engine.WorkflowContinuation, 0, len(keys))
+	if len(keys) > 0 {
+		values, err := r.redis.MGet(ctx, keys...).Result()
+		if err != nil {
+			return engine.ContinuationListResponse{}, fmt.Errorf("failed to load continuations: %w", err)
+		}
+
+		for i, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				// Resumed or expired since it was indexed
+				continue
+			}
+
+			var continuation engine.WorkflowContinuation
+			if err := json.Unmarshal([]byte(data), &continuation); err != nil {
+				log.Printf("⚠️  Skipping unreadable continuation %s: %v", keys[i], err)
+				continue
+			}
+			continuations = append(continuations, continuation)
+		}
+	}
+
+	slices.SortFunc(continuations, func(a, b engine.WorkflowContinuation) int {
+		return a.ScheduledFor.Compare(b.ScheduledFor)
+	})
+
+	total := len(continuations)
+	start := min(req.GetOffset(), total)
+	end := min(start+req.PageSize, total)
+	return storex.NewPaginated(continuations[start:end], req.Page, req.PageSize, total), nil
+}
+
+// GetPendingCountByWorkflow breaks the tenant's pending continuations down by
+// workflow ID
+func (r *RedisDelayScheduler) GetPendingCountByWorkflow(ctx context.Context, tenantID kernel.TenantID) (map[string]int64, error) {
+	workflowIDs, err := r.redis.HVals(ctx, tenantWorkflowsKey(tenantID.String())).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count continuations by workflow: %w", err)
+	}
+
+	counts := make(map[string]int64)
+	for _, workflowID := range workflowIDs {
+		counts[workflowID]++
+	}
+	return counts, nil
+}
+
+// ResumeNow moves a pending continuation to the front so the worker picks it
+// up on its next tick
+func (r *RedisDelayScheduler) ResumeNow(ctx context.Context, id string) error {
+	continuation, err := r.GetContinuation(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// XX only updates: a continuation claimed meanwhile is not scheduled again
+	score := float64(time.Now().Unix())
+	updated, err := r.redis.ZAddArgs(ctx, delayedExecutionsKey, redis.ZAddArgs{
+		XX:      true,
+		Ch:      true,
+		Members: []redis.Z{{Score: score, Member: id}},
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to resume continuation: %w", err)
+	}
+	if updated == 0 {
+		if _, err := r.redis.ZScore(ctx, delayedExecutionsKey, id).Result(); err != nil {
+			return engine.ErrContinuationNotFound().
+				WithDetail("continuation_id", id).
+				WithDetail("reason", "continuation is already resuming")
+		}
+	}
+
+	r.redis.ZAddArgs(ctx, tenantIndexKey(continuation.TenantID), redis.ZAddArgs{
+		XX:      true,
+		Members: []redis.Z{{Score: score, Member: id}},
+	})
+
+	log.Printf("⏩ Continuation %s of workflow %s forced to resume now", id, continuation.WorkflowID)
+	return nil
+}
+
+func (r *RedisDelayScheduler) index(ctx context.Context, continuation *engine.WorkflowContinuation, score float64) error {
+	if continuation.TenantID == "" {
+		return nil
+	}
+
+	pipe := r.redis.TxPipeline()
+	pipe.ZAdd(ctx, tenantIndexKey(continuation.TenantID), &redis.Z{Score: score, Member: continuation.ID})
+	pipe.HSet(ctx, tenantWorkflowsKey(continuation.TenantID), continuation.ID, continuation.WorkflowID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisDelayScheduler) unindex(ctx context.Context, tenantID, id string) {
+	if tenantID == "" {
+		return
+	}
+
+	pipe := r.redis.TxPipeline()
+	pipe.ZRem(ctx, tenantIndexKey(tenantID), id)
+	pipe.HDel(ctx, tenantWorkflowsKey(tenantID), id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("⚠️  Failed to unindex continuation %s for tenant %s: %v", id, tenantID, err)
+	}
+}
+
+func tenantIndexKey(tenantID string) string {
+	return tenantIndexPrefix + tenantID
+}
+
+func tenantWorkflowsKey(tenantID string) string {
+	return tenantIndexPrefix + tenantID + tenantWorkflowSuffix
+}
+
@@ -37,9 +37,10 @@ type WorkflowResponse struct {
 
 type WorkflowListRequest struct {
 	storex.PaginationOptions
-	TenantID kernel.TenantID `json:"tenant_id" validate:"required"`
-	IsActive *bool           `json:"is_active,omitempty"`
-	Search   string          `json:"search,omitempty"`
+	TenantID    kernel.TenantID     `json:"tenant_id" validate:"required"`
+	IsActive    *bool               `json:"is_active,omitempty"`
+	Environment *kernel.Environment `json:"environment,omitempty"`
+	Search      string              `json:"search,omitempty"`
 }
 
 func (wlr WorkflowListRequest) GetOffset() int {
@@ -48,6 +49,52 @@ func (wlr WorkflowListRequest) GetOffset() int {
 
 type WorkflowListResponse = storex.Paginated[Workflow]
 
+type DeadLetterListRequest struct {
+	storex.PaginationOptions
+	TenantID    kernel.TenantID   `json:"tenant_id" validate:"required"`
+	Status      *DeadLetterStatus `json:"status,omitempty"`
+	TriggerType *TriggerType      `json:"trigger_type,omitempty"`
+}
+
+func (r DeadLetterListRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+type DeadLetterListResponse = storex.Paginated[DeadLetter]
+
+type ContinuationListRequest struct {
+	storex.PaginationOptions
+	TenantID   kernel.TenantID    `json:"tenant_id" validate:"required"`
+	WorkflowID *kernel.WorkflowID `json:"workflow_id,omitempty"`
+}
+
+func (r ContinuationListRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+type ContinuationListResponse = storex.Paginated[WorkflowContinuation]
+
+type RecordedRunListRequest struct {
+	storex.PaginationOptions
+	TenantID   kernel.TenantID    `json:"tenant_id" validate:"required"`
+	WorkflowID *kernel.WorkflowID `json:"workflow_id,omitempty"`
+}
+
+func (r RecordedRunListRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+type RecordedRunListResponse = storex.Paginated[RecordedRun]
+
+// RunAtRequest schedules one run of a workflow. RunAt is RFC 3339, or a local
+// "2006-01-02T15:04" / "2006-01-02 15:04" time read in Timezone.
+type RunAtRequest struct {
+	RunAt    string         `json:"run_at" validate:"required"`
+	Timezone string         `json:"timezone,omitempty"`
+	Payload  map[string]any `json:"payload,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
 type WorkflowExecutionResponse struct {
 	WorkflowID    kernel.WorkflowID `json:"workflow_id"`
 	Success       bool              `json:"success"`
@@ -54,15 +54,19 @@ func (m *Message) HasTextContent() bool {
 // ============================================================================
 
 type Workflow struct {
-	ID          kernel.WorkflowID `db:"id" json:"id"`
-	TenantID    kernel.TenantID   `db:"tenant_id" json:"tenant_id"`
-	Name        string            `db:"name" json:"name"`
-	Description string            `db:"description" json:"description"`
-	Trigger     WorkflowTrigger   `db:"trigger" json:"trigger"`
-	Nodes       []WorkflowNode    `db:"nodes" json:"nodes"`
-	IsActive    bool              `db:"is_active" json:"is_active"`
-	CreatedAt   time.Time         `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time         `db:"updated_at" json:"updated_at"`
+	ID          kernel.WorkflowID  `db:"id" json:"id"`
+	TenantID    kernel.TenantID    `db:"tenant_id" json:"tenant_id"`
+	Name        string             `db:"name" json:"name"`
+	Description string             `db:"description" json:"description"`
+	Trigger     WorkflowTrigger    `db:"trigger" json:"trigger"`
+	Nodes       []WorkflowNode     `db:"nodes" json:"nodes"`
+	Variables   VariableSchema     `db:"variables" json:"variables,omitempty"` // typed vars nodes read as {{vars.<name>}}
+	IsActive    bool               `db:"is_active" json:"is_active"`
+	Debug       bool               `db:"debug" json:"debug,omitempty"`   // records a context snapshot at every node for replay
+	Environment kernel.Environment `db:"environment" json:"environment"` // only triggered by channels of the same environment
+	Version     int                `db:"version" json:"version"`         // incremented on every update; stale saves are rejected
+	CreatedAt   time.Time          `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `db:"updated_at" json:"updated_at"`
 }
 
 // WorkflowTrigger defines when workflow executes
@@ -80,33 +84,43 @@ const (
 	TriggerTypeSchedule       TriggerType = "SCHEDULE"
 	TriggerTypeManual         TriggerType = "MANUAL"
 	TriggerTypeChannelWebhook TriggerType = "CHANNEL_WEBHOOK" // For channel integrations
+	TriggerTypeInactivity     TriggerType = "INACTIVITY"      // Contact idle on a channel conversation
+	TriggerTypeSessionExpired TriggerType = "SESSION_EXPIRED" // Messaging window of a conversation closed
 )
 
 // WorkflowNode represents a workflow step
 type WorkflowNode struct {
-	ID        string         `json:"id"`
-	Name      string         `json:"name"`
-	Type      NodeType       `json:"type"`
-	Config    map[string]any `json:"config"`
-	OnSuccess string         `json:"on_success,omitempty"`
-	OnFailure string         `json:"on_failure,omitempty"`
-	Timeout   *int           `json:"timeout,omitempty"`
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	Type           NodeType       `json:"type"`
+	Config         map[string]any `json:"config"`
+	OnSuccess      string         `json:"on_success,omitempty"`
+	OnFailure      string         `json:"on_failure,omitempty"`
+	Timeout        *int           `json:"timeout,omitempty"`
+	MaxOutputBytes *int           `json:"max_output_bytes,omitempty"` // Overrides the executor's output limit; 0 = unlimited
 }
 
 // NodeType defines node types
 type NodeType string
 
 const (
-	NodeTypeCondition   NodeType = "CONDITION"
-	NodeTypeAction      NodeType = "ACTION"
-	NodeTypeDelay       NodeType = "DELAY"
-	NodeTypeSwitch      NodeType = "SWITCH"
-	NodeTypeTransform   NodeType = "TRANSFORM"
-	NodeTypeHTTP        NodeType = "HTTP"
-	NodeTypeLoop        NodeType = "LOOP"
-	NodeTypeValidate    NodeType = "VALIDATE"
-	NodeTypeAIAgent     NodeType = "AI_AGENT"
-	NodeTypeSendMessage NodeType = "SEND_MESSAGE"
+	NodeTypeCondition     NodeType = "CONDITION"
+	NodeTypeAction        NodeType = "ACTION"
+	NodeTypeDelay         NodeType = "DELAY"
+	NodeTypeSwitch        NodeType = "SWITCH"
+	NodeTypeTransform     NodeType = "TRANSFORM"
+	NodeTypeHTTP          NodeType = "HTTP"
+	NodeTypeLoop          NodeType = "LOOP"
+	NodeTypeValidate      NodeType = "VALIDATE"
+	NodeTypeAIAgent       NodeType = "AI_AGENT"
+	NodeTypeSendMessage   NodeType = "SEND_MESSAGE"
+	NodeTypeBusinessHours NodeType = "BUSINESS_HOURS"
+	NodeTypeTag           NodeType = "TAG"
+	NodeTypeSurvey        NodeType = "SURVEY"
+	NodeTypeExperiment    NodeType = "EXPERIMENT"
+	NodeTypeCRMSync       NodeType = "CRM_SYNC"
+	NodeTypeTicket        NodeType = "TICKET"
+	NodeTypeOrderLookup   NodeType = "ORDER_LOOKUP"
 )
 
 // ============================================================================
@@ -119,6 +133,7 @@ type ExecutionResult struct {
 	Error         error          `json:"-"`
 	ErrorMessage  string         `json:"error,omitempty"`
 	ExecutedNodes []NodeResult   `json:"executed_nodes,omitempty"`
+	RunID         string         `json:"run_id,omitempty"` // Set when the run was recorded for replay
 }
 
 type NodeResult struct {
@@ -126,6 +141,7 @@ type NodeResult struct {
 	NodeName  string         `json:"node_name"`
 	Success   bool           `json:"success"`
 	Output    map[string]any `json:"output,omitempty"`
+	Truncated []string       `json:"truncated,omitempty"` // Output fields replaced by a truncation marker
 	Error     string         `json:"error,omitempty"`
 	Duration  int64          `json:"duration_ms"`
 	Timestamp time.Time      `json:"timestamp"`
@@ -0,0 +1,348 @@
+package engineinfra
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// workflowInvalidationChannel carries a workflowInvalidation for every write
+	workflowInvalidationChannel = "relay:workflows:invalidate"
+
+	// workflowCacheResubscribeDelay wait before re-subscribing after the
+	// connection dropped
+	workflowCacheResubscribeDelay = 5 * time.Second
+)
+
+// workflowInvalidation identifies what another instance must drop. An empty
+// WorkflowIDs drops every workflow of the tenant.
+type workflowInvalidation struct {
+	TenantID    kernel.TenantID     `json:"tenant_id"`
+	WorkflowIDs []kernel.WorkflowID `json:"workflow_ids,omitempty"`
+}
+
+type cachedWorkflow struct {
+	workflow  *engine.Workflow
+	expiresAt time.Time
+}
+
+type cachedWorkflowList struct {
+	workflows []*engine.Workflow
+	expiresAt time.Time
+}
+
+// triggerCacheKey identifies a FindActiveByTrigger result
+type triggerCacheKey struct {
+	tenantID    kernel.TenantID
+	triggerType engine.TriggerType
+}
+
+// CachedWorkflowRepository is a read-through cache in front of a
+// WorkflowRepository for the lookups every incoming message does (FindByID and
+// FindActiveByTrigger). Writes through this repository drop the affected
+// entries on every instance over Redis pub/sub; ttl bounds how long a missed
+// invalidation or a direct edit of the workflows table stays visible.
+type CachedWorkflowRepository struct {
+	engine.WorkflowRepository
+
+	client *redis.Client // nil = this instance only
+	ttl    time.Duration
+
+	mu        sync.RWMutex
+	byID      map[kernel.WorkflowID]cachedWorkflow
+	byTrigger map[triggerCacheKey]cachedWorkflowList
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+var _ engine.WorkflowRepository = (*CachedWorkflowRepository)(nil)
+
+func NewCachedWorkflowRepository(next engine.WorkflowRepository, client *redis.Client, ttl time.Duration) *CachedWorkflowRepository {
+	return &CachedWorkflowRepository{
+		WorkflowRepository: next,
+		client:             client,
+		ttl:                ttl,
+		byID:               make(map[kernel.WorkflowID]cachedWorkflow),
+		byTrigger:          make(map[triggerCacheKey]cachedWorkflowList),
+		stopChan:           make(chan struct{}),
+	}
+}
+
+// ============================================================================
+// Cached Reads
+// ============================================================================
+
+func (r *CachedWorkflowRepository) FindByID(ctx context.Context, id kernel.WorkflowID) (*engine.Workflow, error) {
+	r.mu.RLock()
+	cached, ok := r.byID[id]
+	r.mu.RUnlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		r.hits.Add(1)
+		return copyWorkflow(cached.workflow), nil
+	}
+	r.misses.Add(1)
+
+	wf, err := r.WorkflowRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.byID[id] = cachedWorkflow{workflow: copyWorkflow(wf), expiresAt: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return wf, nil
+}
+
+func (r *CachedWorkflowRepository) FindActiveByTrigger(ctx context.Context, trigger engine.WorkflowTrigger, tenantID kernel.TenantID) ([]*engine.Workflow, error) {
+	key := triggerCacheKey{tenantID: tenantID, triggerType: trigger.Type}
+
+	r.mu.RLock()
+	cached, ok := r.byTrigger[key]
+	r.mu.RUnlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		r.hits.Add(1)
+		return copyWorkflows(cached.workflows), nil
+	}
+	r.misses.Add(1)
+
+	workflows, err := r.WorkflowRepository.FindActiveByTrigger(ctx, trigger, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.byTrigger[key] = cachedWorkflowList{workflows: copyWorkflows(workflows), expiresAt: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return workflows, nil
+}
+
+// ============================================================================
+// Invalidating Writes
+// ============================================================================
+
+func (r *CachedWorkflowRepository) Save(ctx context.Context, wf engine.Workflow) error {
+	err := r.WorkflowRepository.Save(ctx, wf)
+	// A version conflict means the cached copy is stale too
+	r.invalidate(ctx, workflowInvalidation{TenantID: wf.TenantID, WorkflowIDs: []kernel.WorkflowID{wf.ID}})
+	return err
+}
+
+func (r *CachedWorkflowRepository) Delete(ctx context.Context, id kernel.WorkflowID, tenantID kernel.TenantID) error {
+	err := r.WorkflowRepository.Delete(ctx, id, tenantID)
+	r.invalidate(ctx, workflowInvalidation{TenantID: tenantID, WorkflowIDs: []kernel.WorkflowID{id}})
+	return err
+}
+
+func (r *CachedWorkflowRepository) BulkUpdateStatus(ctx context.Context, ids []kernel.WorkflowID, tenantID kernel.TenantID, isActive bool) error {
+	err := r.WorkflowRepository.BulkUpdateStatus(ctx, ids, tenantID, isActive)
+	r.invalidate(ctx, workflowInvalidation{TenantID: tenantID, WorkflowIDs: ids})
+	return err
+}
+
+// ============================================================================
+// Invalidation Listener
+// ============================================================================
+
+// Start listens for invalidations from other instances until Stop is called
+// or ctx is done
+func (r *CachedWorkflowRepository) Start(ctx context.Context) {
+	if r.client == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-r.stopChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	log.Println("🗂️  Listening for workflow cache invalidations...")
+	for {
+		if err := r.subscribe(ctx); err != nil {
+			log.Printf("⚠️  Workflow cache subscription failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("⏹️  Workflow cache listener stopped")
+			return
+		case <-time.After(workflowCacheResubscribeDelay):
+		}
+	}
+}
+
+// Stop stops the invalidation listener
+func (r *CachedWorkflowRepository) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopChan)
+	})
+}
+
+// ============================================================================
+// Stats
+// ============================================================================
+
+// WorkflowCacheStats counts lookups since startup. Invalidations include the
+// ones received from other instances.
+type WorkflowCacheStats struct {
+	Hits           int64  `json:"hits"`
+	Misses         int64  `json:"misses"`
+	Invalidations  int64  `json:"invalidations"`
+	ByIDEntries    int    `json:"by_id_entries"`
+	TriggerEntries int    `json:"trigger_entries"`
+	TTL            string `json:"ttl"`
+	Shared         bool   `json:"shared"` // Invalidations are broadcast over Redis
+}
+
+// Stats reports the cache's hit rate and size
+func (r *CachedWorkflowRepository) Stats() WorkflowCacheStats {
+	r.mu.RLock()
+	byID, byTrigger := len(r.byID), len(r.byTrigger)
+	r.mu.RUnlock()
+
+	return WorkflowCacheStats{
+		Hits:           r.hits.Load(),
+		Misses:         r.misses.Load(),
+		Invalidations:  r.invalidations.Load(),
+		ByIDEntries:    byID,
+		TriggerEntries: byTrigger,
+		TTL:            r.ttl.String(),
+		Shared:         r.client != nil,
+	}
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func (r *CachedWorkflowRepository) subscribe(ctx context.Context) error {
+	pubsub := r.client.Subscribe(ctx, workflowInvalidationChannel)
+	defer pubsub.Close()
+
+	// Wait for the subscription so invalidations published right after
+	// startup are not missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			var inv workflowInvalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+				log.Printf("⚠️  Ignoring malformed workflow invalidation: %v", err)
+				continue
+			}
+			r.drop(inv)
+		}
+	}
+}
+
+// invalidate drops the entries here and broadcasts the change. A failed
+// broadcast only delays other instances until their entries expire.
+func (r *CachedWorkflowRepository) invalidate(ctx context.Context, inv workflowInvalidation) {
+	r.drop(inv)
+
+	if r.client == nil {
+		return
+	}
+
+	payload, err := json.Marshal(inv)
+	if err != nil {
+		return
+	}
+	if err := r.client.Publish(ctx, workflowInvalidationChannel, payload).Err(); err != nil {
+		log.Printf("⚠️  Failed to broadcast workflow cache invalidation for tenant %s: %v", inv.TenantID, err)
+	}
+}
+
+func (r *CachedWorkflowRepository) drop(inv workflowInvalidation) {
+	r.invalidations.Add(1)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(inv.WorkflowIDs) == 0 {
+		for id, cached := range r.byID {
+			if cached.workflow.TenantID == inv.TenantID {
+				delete(r.byID, id)
+			}
+		}
+	}
+	for _, id := range inv.WorkflowIDs {
+		delete(r.byID, id)
+	}
+
+	// Any change can add or remove a workflow from a trigger list
+	for key := range r.byTrigger {
+		if key.tenantID == inv.TenantID {
+			delete(r.byTrigger, key)
+		}
+	}
+}
+
+// copyWorkflow keeps callers from mutating cached entries. Configs, filters
+// and variable defaults are nested maps, so they are deep-copied like
+// RelinkResourceRefs does.
+func copyWorkflow(wf *engine.Workflow) *engine.Workflow {
+	c := *wf
+	c.Trigger.Config = engine.DeepCopyMap(wf.Trigger.Config)
+	c.Trigger.Filters = engine.DeepCopyMap(wf.Trigger.Filters)
+
+	c.Nodes = slices.Clone(wf.Nodes)
+	for i := range c.Nodes {
+		c.Nodes[i].Config = engine.DeepCopyMap(c.Nodes[i].Config)
+		c.Nodes[i].Timeout = clonePtr(c.Nodes[i].Timeout)
+		c.Nodes[i].MaxOutputBytes = clonePtr(c.Nodes[i].MaxOutputBytes)
+	}
+
+	c.Variables = slices.Clone(wf.Variables)
+	for i := range c.Variables {
+		c.Variables[i].Default = engine.DeepCopy(c.Variables[i].Default)
+	}
+	return &c
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func copyWorkflows(workflows []*engine.Workflow) []*engine.Workflow {
+	result := make([]*engine.Workflow, len(workflows))
+	for i, wf := range workflows {
+		result[i] = copyWorkflow(wf)
+	}
+	return result
+}
@@ -0,0 +1,43 @@
+package engineinfra
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/relay/engine"
+)
+
+func sampleWorkflow() *engine.Workflow {
+	timeout := 30
+	return &engine.Workflow{
+		Name: "cached",
+		Trigger: engine.WorkflowTrigger{
+			Type:    engine.TriggerTypeChannelWebhook,
+			Config:  map[string]any{"keywords": []any{"hi"}},
+			Filters: map[string]any{"channel_ids": []any{"ch-1"}},
+		},
+		Nodes: []engine.WorkflowNode{{
+			ID:      "reply",
+			Type:    engine.NodeTypeSendMessage,
+			Config:  map[string]any{"message": map[string]any{"text": "hello"}},
+			Timeout: &timeout,
+		}},
+		Variables: engine.VariableSchema{{Name: "tags", Default: []any{"new"}}},
+	}
+}
+
+// Editing every nested value of a copy must leave the cached entry intact
+func TestCopyWorkflowIsDeep(t *testing.T) {
+	cached := sampleWorkflow()
+	c := copyWorkflow(cached)
+
+	c.Trigger.Config["keywords"].([]any)[0] = "changed"
+	c.Trigger.Filters["channel_ids"].([]any)[0] = "changed"
+	c.Nodes[0].Config["message"].(map[string]any)["text"] = "changed"
+	*c.Nodes[0].Timeout = 1
+	c.Variables[0].Default.([]any)[0] = "changed"
+
+	if !reflect.DeepEqual(cached, sampleWorkflow()) {
+		t.Errorf("cached workflow was mutated through its copy: %#v", cached)
+	}
+}
@@ -0,0 +1,192 @@
+package engineinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresDeadLetterRepository struct {
+	db *sqlx.DB
+}
+
+var _ engine.DeadLetterRepository = (*PostgresDeadLetterRepository)(nil)
+
+func NewPostgresDeadLetterRepository(db *sqlx.DB) *PostgresDeadLetterRepository {
+	return &PostgresDeadLetterRepository{db: db}
+}
+
+// dbDeadLetter is an intermediate struct for database operations
+type dbDeadLetter struct {
+	ID            string          `db:"id"`
+	TenantID      string          `db:"tenant_id"`
+	TriggerType   string          `db:"trigger_type"`
+	WorkflowID    sql.NullString  `db:"workflow_id"`
+	Filters       json.RawMessage `db:"filters"`
+	Payload       json.RawMessage `db:"payload"`
+	Error         string          `db:"error"`
+	Status        string          `db:"status"`
+	RetryCount    int             `db:"retry_count"`
+	LastRetriedAt *time.Time      `db:"last_retried_at"`
+	CreatedAt     time.Time       `db:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at"`
+}
+
+const deadLetterColumns = `
+	id, tenant_id, trigger_type, workflow_id, filters, payload, error,
+	status, retry_count, last_retried_at, created_at, updated_at`
+
+func (r *PostgresDeadLetterRepository) Save(ctx context.Context, dl engine.DeadLetter) error {
+	filters, err := json.Marshal(orEmpty(dl.Filters))
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal dead letter filters", errx.TypeInternal)
+	}
+	payload, err := json.Marshal(orEmpty(dl.Payload))
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal dead letter payload", errx.TypeInternal)
+	}
+
+	var workflowID sql.NullString
+	if dl.WorkflowID != nil {
+		workflowID = sql.NullString{String: dl.WorkflowID.String(), Valid: true}
+	}
+
+	// Retries only change the outcome; the payload stays as received
+	query := `
+		INSERT INTO dead_letters (
+			id, tenant_id, trigger_type, workflow_id, filters, payload, error,
+			status, retry_count, last_retried_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO UPDATE SET
+			error = EXCLUDED.error,
+			status = EXCLUDED.status,
+			retry_count = EXCLUDED.retry_count,
+			last_retried_at = EXCLUDED.last_retried_at`
+
+	_, err = r.db.ExecContext(ctx, query,
+		dl.ID, dl.TenantID.String(), string(dl.TriggerType), workflowID, filters, payload, dl.Error,
+		string(dl.Status), dl.RetryCount, dl.LastRetriedAt, dl.CreatedAt,
+	)
+	if err != nil {
+		return errx.Wrap(err, "failed to save dead letter", errx.TypeInternal).
+			WithDetail("dead_letter_id", dl.ID)
+	}
+
+	return nil
+}
+
+func (r *PostgresDeadLetterRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*engine.DeadLetter, error) {
+	query := fmt.Sprintf(`SELECT %s FROM dead_letters WHERE id = $1 AND tenant_id = $2`, deadLetterColumns)
+
+	var row dbDeadLetter
+	if err := r.db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, engine.ErrDeadLetterNotFound().WithDetail("dead_letter_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find dead letter", errx.TypeInternal).
+			WithDetail("dead_letter_id", id)
+	}
+
+	return toDomainDeadLetter(&row)
+}
+
+func (r *PostgresDeadLetterRepository) List(ctx context.Context, req engine.DeadLetterListRequest) (engine.DeadLetterListResponse, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argPos))
+		args = append(args, string(*req.Status))
+		argPos++
+	}
+	if req.TriggerType != nil {
+		conditions = append(conditions, fmt.Sprintf("trigger_type = $%d", argPos))
+		args = append(args, string(*req.TriggerType))
+		argPos++
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM dead_letters WHERE %s", whereClause)
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return engine.DeadLetterListResponse{}, errx.Wrap(err, "failed to count dead letters", errx.TypeInternal)
+	}
+
+	dataQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM dead_letters
+		WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`,
+		deadLetterColumns, whereClause, argPos, argPos+1)
+
+	args = append(args, req.PageSize, req.GetOffset())
+
+	var rows []dbDeadLetter
+	if err := r.db.SelectContext(ctx, &rows, dataQuery, args...); err != nil {
+		return engine.DeadLetterListResponse{}, errx.Wrap(err, "failed to list dead letters", errx.TypeInternal)
+	}
+
+	letters := make([]engine.DeadLetter, 0, len(rows))
+	for i := range rows {
+		dl, err := toDomainDeadLetter(&rows[i])
+		if err != nil {
+			return engine.DeadLetterListResponse{}, err
+		}
+		letters = append(letters, *dl)
+	}
+
+	return storex.NewPaginated(letters, req.Page, req.PageSize, total), nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func toDomainDeadLetter(row *dbDeadLetter) (*engine.DeadLetter, error) {
+	dl := &engine.DeadLetter{
+		ID:            row.ID,
+		TenantID:      kernel.TenantID(row.TenantID),
+		TriggerType:   engine.TriggerType(row.TriggerType),
+		Error:         row.Error,
+		Status:        engine.DeadLetterStatus(row.Status),
+		RetryCount:    row.RetryCount,
+		LastRetriedAt: row.LastRetriedAt,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}
+
+	if row.WorkflowID.Valid {
+		workflowID := kernel.NewWorkflowID(row.WorkflowID.String)
+		dl.WorkflowID = &workflowID
+	}
+
+	if err := json.Unmarshal(row.Filters, &dl.Filters); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal dead letter filters", errx.TypeInternal).
+			WithDetail("dead_letter_id", row.ID)
+	}
+	if err := json.Unmarshal(row.Payload, &dl.Payload); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal dead letter payload", errx.TypeInternal).
+			WithDetail("dead_letter_id", row.ID)
+	}
+
+	return dl, nil
+}
+
+func orEmpty(m map[string]any) map[string]any {
+	if m == nil {
+		return map[string]any{}
+	}
+	return m
+}
@@ -0,0 +1,280 @@
+package engineinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresSnapshotRepository struct {
+	db *sqlx.DB
+}
+
+var _ engine.SnapshotRepository = (*PostgresSnapshotRepository)(nil)
+
+func NewPostgresSnapshotRepository(db *sqlx.DB) *PostgresSnapshotRepository {
+	return &PostgresSnapshotRepository{db: db}
+}
+
+// dbRecordedRun is an intermediate struct for database operations
+type dbRecordedRun struct {
+	ID              string          `db:"id"`
+	TenantID        string          `db:"tenant_id"`
+	WorkflowID      string          `db:"workflow_id"`
+	WorkflowVersion int             `db:"workflow_version"`
+	TriggerData     json.RawMessage `db:"trigger_data"`
+	Metadata        json.RawMessage `db:"metadata"`
+	ResumedAt       sql.NullString  `db:"resumed_at"`
+	Success         bool            `db:"success"`
+	Error           sql.NullString  `db:"error"`
+	StartedAt       time.Time       `db:"started_at"`
+	FinishedAt      *time.Time      `db:"finished_at"`
+}
+
+// dbNodeSnapshot is an intermediate struct for database operations
+type dbNodeSnapshot struct {
+	RunID      string          `db:"run_id"`
+	Sequence   int             `db:"sequence"`
+	NodeID     string          `db:"node_id"`
+	NodeType   string          `db:"node_type"`
+	Context    json.RawMessage `db:"context"`
+	Config     json.RawMessage `db:"config"`
+	NextNode   sql.NullString  `db:"next_node"`
+	Result     json.RawMessage `db:"result"`
+	RecordedAt time.Time       `db:"recorded_at"`
+}
+
+const recordedRunColumns = `
+	id, tenant_id, workflow_id, workflow_version, trigger_data, metadata,
+	resumed_at, success, error, started_at, finished_at`
+
+const nodeSnapshotColumns = `
+	run_id, sequence, node_id, node_type, context, config, next_node, result, recorded_at`
+
+func (r *PostgresSnapshotRepository) SaveRun(ctx context.Context, run engine.RecordedRun) error {
+	triggerData, err := json.Marshal(orEmpty(run.TriggerData))
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal run trigger data", errx.TypeInternal)
+	}
+	metadata, err := json.Marshal(orEmpty(run.Metadata))
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal run metadata", errx.TypeInternal)
+	}
+
+	// Finishing only changes the outcome; the input stays as recorded
+	query := `
+		INSERT INTO workflow_runs (
+			id, tenant_id, workflow_id, workflow_version, trigger_data, metadata,
+			resumed_at, success, error, started_at, finished_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO UPDATE SET
+			success = EXCLUDED.success,
+			error = EXCLUDED.error,
+			finished_at = EXCLUDED.finished_at`
+
+	_, err = r.db.ExecContext(ctx, query,
+		run.ID, run.TenantID.String(), run.WorkflowID.String(), run.WorkflowVersion, triggerData, metadata,
+		nullString(run.ResumedAt), run.Success, nullString(run.Error), run.StartedAt, run.FinishedAt,
+	)
+	if err != nil {
+		return errx.Wrap(err, "failed to save recorded run", errx.TypeInternal).
+			WithDetail("run_id", run.ID)
+	}
+
+	return nil
+}
+
+func (r *PostgresSnapshotRepository) SaveSnapshot(ctx context.Context, snapshot engine.NodeSnapshot) error {
+	nodeContext, err := json.Marshal(orEmpty(snapshot.Context))
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal snapshot context", errx.TypeInternal)
+	}
+	var config []byte
+	if snapshot.Config != nil {
+		if config, err = json.Marshal(snapshot.Config); err != nil {
+			return errx.Wrap(err, "failed to marshal snapshot config", errx.TypeInternal)
+		}
+	}
+	result, err := json.Marshal(snapshot.Result)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal snapshot result", errx.TypeInternal)
+	}
+
+	query := `
+		INSERT INTO workflow_run_snapshots (
+			run_id, sequence, node_id, node_type, context, config, next_node, result, recorded_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		snapshot.RunID, snapshot.Sequence, snapshot.NodeID, string(snapshot.NodeType),
+		nodeContext, config, nullString(snapshot.NextNode), result, snapshot.RecordedAt,
+	)
+	if err != nil {
+		return errx.Wrap(err, "failed to save node snapshot", errx.TypeInternal).
+			WithDetail("run_id", snapshot.RunID).
+			WithDetail("node_id", snapshot.NodeID)
+	}
+
+	return nil
+}
+
+func (r *PostgresSnapshotRepository) FindRun(ctx context.Context, id string, tenantID kernel.TenantID) (*engine.RecordedRun, error) {
+	query := fmt.Sprintf(`SELECT %s FROM workflow_runs WHERE id = $1 AND tenant_id = $2`, recordedRunColumns)
+
+	var row dbRecordedRun
+	if err := r.db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, engine.ErrRunNotFound().WithDetail("run_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find recorded run", errx.TypeInternal).
+			WithDetail("run_id", id)
+	}
+
+	return toDomainRecordedRun(&row)
+}
+
+func (r *PostgresSnapshotRepository) FindSnapshots(ctx context.Context, runID string) ([]engine.NodeSnapshot, error) {
+	query := fmt.Sprintf(`SELECT %s FROM workflow_run_snapshots WHERE run_id = $1 ORDER BY sequence`, nodeSnapshotColumns)
+
+	var rows []dbNodeSnapshot
+	if err := r.db.SelectContext(ctx, &rows, query, runID); err != nil {
+		return nil, errx.Wrap(err, "failed to find node snapshots", errx.TypeInternal).
+			WithDetail("run_id", runID)
+	}
+
+	snapshots := make([]engine.NodeSnapshot, 0, len(rows))
+	for i := range rows {
+		snapshot, err := toDomainNodeSnapshot(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, *snapshot)
+	}
+
+	return snapshots, nil
+}
+
+func (r *PostgresSnapshotRepository) ListRuns(ctx context.Context, req engine.RecordedRunListRequest) (engine.RecordedRunListResponse, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.WorkflowID != nil {
+		conditions = append(conditions, fmt.Sprintf("workflow_id = $%d", argPos))
+		args = append(args, req.WorkflowID.String())
+		argPos++
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM workflow_runs WHERE %s", whereClause)
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return engine.RecordedRunListResponse{}, errx.Wrap(err, "failed to count recorded runs", errx.TypeInternal)
+	}
+
+	dataQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM workflow_runs
+		WHERE %s
+		ORDER BY started_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`,
+		recordedRunColumns, whereClause, argPos, argPos+1)
+
+	args = append(args, req.PageSize, req.GetOffset())
+
+	var rows []dbRecordedRun
+	if err := r.db.SelectContext(ctx, &rows, dataQuery, args...); err != nil {
+		return engine.RecordedRunListResponse{}, errx.Wrap(err, "failed to list recorded runs", errx.TypeInternal)
+	}
+
+	runs := make([]engine.RecordedRun, 0, len(rows))
+	for i := range rows {
+		run, err := toDomainRecordedRun(&rows[i])
+		if err != nil {
+			return engine.RecordedRunListResponse{}, err
+		}
+		runs = append(runs, *run)
+	}
+
+	return storex.NewPaginated(runs, req.Page, req.PageSize, total), nil
+}
+
+func (r *PostgresSnapshotRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	// Snapshots go with their run
+	res, err := r.db.ExecContext(ctx, `DELETE FROM workflow_runs WHERE started_at < $1`, before)
+	if err != nil {
+		return 0, errx.Wrap(err, "failed to prune recorded runs", errx.TypeInternal)
+	}
+	return res.RowsAffected()
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func toDomainRecordedRun(row *dbRecordedRun) (*engine.RecordedRun, error) {
+	run := &engine.RecordedRun{
+		ID:              row.ID,
+		TenantID:        kernel.TenantID(row.TenantID),
+		WorkflowID:      kernel.NewWorkflowID(row.WorkflowID),
+		WorkflowVersion: row.WorkflowVersion,
+		ResumedAt:       row.ResumedAt.String,
+		Success:         row.Success,
+		Error:           row.Error.String,
+		StartedAt:       row.StartedAt,
+		FinishedAt:      row.FinishedAt,
+	}
+
+	if err := json.Unmarshal(row.TriggerData, &run.TriggerData); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal run trigger data", errx.TypeInternal).
+			WithDetail("run_id", row.ID)
+	}
+	if err := json.Unmarshal(row.Metadata, &run.Metadata); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal run metadata", errx.TypeInternal).
+			WithDetail("run_id", row.ID)
+	}
+
+	return run, nil
+}
+
+func toDomainNodeSnapshot(row *dbNodeSnapshot) (*engine.NodeSnapshot, error) {
+	snapshot := &engine.NodeSnapshot{
+		RunID:      row.RunID,
+		Sequence:   row.Sequence,
+		NodeID:     row.NodeID,
+		NodeType:   engine.NodeType(row.NodeType),
+		NextNode:   row.NextNode.String,
+		RecordedAt: row.RecordedAt,
+	}
+
+	if err := json.Unmarshal(row.Context, &snapshot.Context); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal snapshot context", errx.TypeInternal).
+			WithDetail("run_id", row.RunID)
+	}
+	if len(row.Config) > 0 {
+		if err := json.Unmarshal(row.Config, &snapshot.Config); err != nil {
+			return nil, errx.Wrap(err, "failed to unmarshal snapshot config", errx.TypeInternal).
+				WithDetail("run_id", row.RunID)
+		}
+	}
+	if err := json.Unmarshal(row.Result, &snapshot.Result); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal snapshot result", errx.TypeInternal).
+			WithDetail("run_id", row.RunID)
+	}
+
+	return snapshot, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -33,7 +33,11 @@ type dbWorkflow struct {
 	Description string          `db:"description"`
 	Trigger     json.RawMessage `db:"trigger"`
 	Nodes       json.RawMessage `db:"nodes"` // ✅ Changed from steps
+	Variables   json.RawMessage `db:"variables"`
 	IsActive    bool            `db:"is_active"`
+	Debug       bool            `db:"debug"`
+	Environment string          `db:"environment"`
+	Version     int             `db:"version"`
 	CreatedAt   string          `db:"created_at"`
 	UpdatedAt   string          `db:"updated_at"`
 }
@@ -53,6 +57,14 @@ func toDBWorkflow(wf engine.Workflow) (*dbWorkflow, error) {
 		}
 	}
 
+	variablesJSON := []byte("[]")
+	if len(wf.Variables) > 0 {
+		variablesJSON, err = json.Marshal(wf.Variables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal variables: %w", err)
+		}
+	}
+
 	return &dbWorkflow{
 		ID:          wf.ID.String(),
 		TenantID:    wf.TenantID.String(),
@@ -60,7 +72,11 @@ func toDBWorkflow(wf engine.Workflow) (*dbWorkflow, error) {
 		Description: wf.Description,
 		Trigger:     triggerJSON,
 		Nodes:       nodesJSON, // ✅ Changed from Steps
+		Variables:   variablesJSON,
 		IsActive:    wf.IsActive,
+		Debug:       wf.Debug,
+		Environment: wf.Environment.OrDefault().String(),
+		Version:     wf.Version,
 		CreatedAt:   wf.CreatedAt.Format("2006-01-02 15:04:05.999999"),
 		UpdatedAt:   wf.UpdatedAt.Format("2006-01-02 15:04:05.999999"),
 	}, nil
@@ -80,6 +96,13 @@ func toDomainWorkflow(dbWf *dbWorkflow) (*engine.Workflow, error) {
 		}
 	}
 
+	var variables engine.VariableSchema
+	if len(dbWf.Variables) > 0 && string(dbWf.Variables) != "null" {
+		if err := json.Unmarshal(dbWf.Variables, &variables); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal variables: %w", err)
+		}
+	}
+
 	wf := &engine.Workflow{
 		ID:          kernel.WorkflowID(dbWf.ID),
 		TenantID:    kernel.TenantID(dbWf.TenantID),
@@ -87,7 +110,11 @@ func toDomainWorkflow(dbWf *dbWorkflow) (*engine.Workflow, error) {
 		Description: dbWf.Description,
 		Trigger:     trigger,
 		Nodes:       nodes,
+		Variables:   variables,
 		IsActive:    dbWf.IsActive,
+		Debug:       dbWf.Debug,
+		Environment: kernel.Environment(dbWf.Environment),
+		Version:     dbWf.Version,
 	}
 
 	return wf, nil
@@ -106,6 +133,10 @@ func (r *PostgresWorkflowRepository) Save(ctx context.Context, wf engine.Workflo
 }
 
 func (r *PostgresWorkflowRepository) create(ctx context.Context, wf engine.Workflow) error {
+	if wf.Version < 1 {
+		wf.Version = 1
+	}
+
 	dbWf, err := toDBWorkflow(wf)
 	if err != nil {
 		return errx.Wrap(err, "failed to convert workflow", errx.TypeInternal).
@@ -114,17 +145,17 @@ func (r *PostgresWorkflowRepository) create(ctx context.Context, wf engine.Workf
 
 	query := `
 		INSERT INTO workflows (
-			id, tenant_id, name, description, trigger, nodes,
-			is_active, created_at, updated_at
+			id, tenant_id, name, description, trigger, nodes, variables,
+			is_active, debug, environment, version, created_at, updated_at
 		) VALUES (
-			:id, :tenant_id, :name, :description, :trigger, :nodes,
-			:is_active, :created_at, :updated_at
+			:id, :tenant_id, :name, :description, :trigger, :nodes, :variables,
+			:is_active, :debug, :environment, :version, :created_at, :updated_at
 		)` // ✅ Changed steps to nodes
 
 	_, err = r.db.NamedExecContext(ctx, query, dbWf)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
-			if pqErr.Code == "23505" && pqErr.Constraint == "workflows_name_tenant_id_key" {
+			if pqErr.Code == "23505" && pqErr.Constraint == "workflows_tenant_environment_name_key" {
 				return engine.ErrWorkflowAlreadyExists().
 					WithDetail("name", wf.Name).
 					WithDetail("tenant_id", wf.TenantID.String())
@@ -150,9 +181,13 @@ func (r *PostgresWorkflowRepository) update(ctx context.Context, wf engine.Workf
 			description = :description,
 			trigger = :trigger,
 			nodes = :nodes,
+			variables = :variables,
 			is_active = :is_active,
+			debug = :debug,
+			environment = :environment,
+			version = version + 1,
 			updated_at = :updated_at
-		WHERE id = :id AND tenant_id = :tenant_id` // ✅ Changed steps to nodes
+		WHERE id = :id AND tenant_id = :tenant_id AND version = :version` // ✅ Changed steps to nodes
 
 	result, err := r.db.NamedExecContext(ctx, query, dbWf)
 	if err != nil {
@@ -171,17 +206,38 @@ func (r *PostgresWorkflowRepository) update(ctx context.Context, wf engine.Workf
 	}
 
 	if rowsAffected == 0 {
-		return engine.ErrWorkflowNotFound().WithDetail("workflow_id", wf.ID.String())
+		return r.updateMissError(ctx, wf)
 	}
 
 	return nil
 }
 
+// updateMissError explains why an update matched no row: either the workflow
+// is gone or it was saved by someone else since wf was loaded
+func (r *PostgresWorkflowRepository) updateMissError(ctx context.Context, wf engine.Workflow) error {
+	query := `SELECT version FROM workflows WHERE id = $1 AND tenant_id = $2`
+
+	var current int
+	err := r.db.GetContext(ctx, &current, query, wf.ID.String(), wf.TenantID.String())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return engine.ErrWorkflowNotFound().WithDetail("workflow_id", wf.ID.String())
+		}
+		return errx.Wrap(err, "failed to check workflow version", errx.TypeInternal).
+			WithDetail("workflow_id", wf.ID.String())
+	}
+
+	return engine.ErrWorkflowVersionConflict().
+		WithDetail("workflow_id", wf.ID.String()).
+		WithDetail("expected_version", wf.Version).
+		WithDetail("current_version", current)
+}
+
 func (r *PostgresWorkflowRepository) FindByID(ctx context.Context, id kernel.WorkflowID) (*engine.Workflow, error) {
 	query := `
 		SELECT 
-			id, tenant_id, name, description, trigger, nodes,
-			is_active, created_at, updated_at
+			id, tenant_id, name, description, trigger, nodes, variables,
+			is_active, debug, environment, version, created_at, updated_at
 		FROM workflows
 		WHERE id = $1` // ✅ Changed steps to nodes
 
@@ -198,16 +254,16 @@ func (r *PostgresWorkflowRepository) FindByID(ctx context.Context, id kernel.Wor
 	return toDomainWorkflow(&dbWf)
 }
 
-func (r *PostgresWorkflowRepository) FindByName(ctx context.Context, name string, tenantID kernel.TenantID) (*engine.Workflow, error) {
+func (r *PostgresWorkflowRepository) FindByName(ctx context.Context, name string, tenantID kernel.TenantID, environment kernel.Environment) (*engine.Workflow, error) {
 	query := `
 		SELECT 
-			id, tenant_id, name, description, trigger, nodes,
-			is_active, created_at, updated_at
+			id, tenant_id, name, description, trigger, nodes, variables,
+			is_active, debug, environment, version, created_at, updated_at
 		FROM workflows
-		WHERE name = $1 AND tenant_id = $2` // ✅ Changed steps to nodes
+		WHERE name = $1 AND tenant_id = $2 AND environment = $3` // ✅ Changed steps to nodes
 
 	var dbWf dbWorkflow
-	err := r.db.GetContext(ctx, &dbWf, query, name, tenantID.String())
+	err := r.db.GetContext(ctx, &dbWf, query, name, tenantID.String(), environment.OrDefault())
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, engine.ErrWorkflowNotFound().WithDetail("name", name)
@@ -240,11 +296,11 @@ func (r *PostgresWorkflowRepository) Delete(ctx context.Context, id kernel.Workf
 	return nil
 }
 
-func (r *PostgresWorkflowRepository) ExistsByName(ctx context.Context, name string, tenantID kernel.TenantID) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM workflows WHERE name = $1 AND tenant_id = $2)`
+func (r *PostgresWorkflowRepository) ExistsByName(ctx context.Context, name string, tenantID kernel.TenantID, environment kernel.Environment) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM workflows WHERE name = $1 AND tenant_id = $2 AND environment = $3)`
 
 	var exists bool
-	err := r.db.GetContext(ctx, &exists, query, name, tenantID.String())
+	err := r.db.GetContext(ctx, &exists, query, name, tenantID.String(), environment.OrDefault())
 	if err != nil {
 		return false, errx.Wrap(err, "failed to check workflow existence by name", errx.TypeInternal).
 			WithDetail("name", name)
@@ -256,8 +312,8 @@ func (r *PostgresWorkflowRepository) ExistsByName(ctx context.Context, name stri
 func (r *PostgresWorkflowRepository) FindByTenant(ctx context.Context, tenantID kernel.TenantID) ([]*engine.Workflow, error) {
 	query := `
 		SELECT 
-			id, tenant_id, name, description, trigger, nodes,
-			is_active, created_at, updated_at
+			id, tenant_id, name, description, trigger, nodes, variables,
+			is_active, debug, environment, version, created_at, updated_at
 		FROM workflows
 		WHERE tenant_id = $1
 		ORDER BY name ASC` // ✅ Changed steps to nodes
@@ -284,8 +340,8 @@ func (r *PostgresWorkflowRepository) FindByTenant(ctx context.Context, tenantID
 func (r *PostgresWorkflowRepository) FindActive(ctx context.Context, tenantID kernel.TenantID) ([]*engine.Workflow, error) {
 	query := `
 		SELECT 
-			id, tenant_id, name, description, trigger, nodes,
-			is_active, created_at, updated_at
+			id, tenant_id, name, description, trigger, nodes, variables,
+			is_active, debug, environment, version, created_at, updated_at
 		FROM workflows
 		WHERE tenant_id = $1 AND is_active = true
 		ORDER BY name ASC` // ✅ Changed steps to nodes
@@ -311,8 +367,8 @@ func (r *PostgresWorkflowRepository) FindActive(ctx context.Context, tenantID ke
 func (r *PostgresWorkflowRepository) FindByTriggerType(ctx context.Context, triggerType engine.TriggerType, tenantID kernel.TenantID) ([]*engine.Workflow, error) {
 	query := `
 		SELECT 
-			id, tenant_id, name, description, trigger, nodes,
-			is_active, created_at, updated_at
+			id, tenant_id, name, description, trigger, nodes, variables,
+			is_active, debug, environment, version, created_at, updated_at
 		FROM workflows
 		WHERE tenant_id = $1 AND trigger->>'type' = $2
 		ORDER BY name ASC` // ✅ Changed steps to nodes
@@ -339,8 +395,8 @@ func (r *PostgresWorkflowRepository) FindByTriggerType(ctx context.Context, trig
 func (r *PostgresWorkflowRepository) FindActiveByTrigger(ctx context.Context, trigger engine.WorkflowTrigger, tenantID kernel.TenantID) ([]*engine.Workflow, error) {
 	query := `
 		SELECT 
-			id, tenant_id, name, description, trigger, nodes,
-			is_active, created_at, updated_at
+			id, tenant_id, name, description, trigger, nodes, variables,
+			is_active, debug, environment, version, created_at, updated_at
 		FROM workflows
 		WHERE tenant_id = $1 
 			AND is_active = true 
@@ -381,6 +437,12 @@ func (r *PostgresWorkflowRepository) List(ctx context.Context, req engine.Workfl
 		argPos++
 	}
 
+	if req.Environment != nil {
+		conditions = append(conditions, fmt.Sprintf("environment = $%d", argPos))
+		args = append(args, *req.Environment)
+		argPos++
+	}
+
 	if req.Search != "" {
 		conditions = append(conditions, fmt.Sprintf("(name ILIKE $%d OR description ILIKE $%d)", argPos, argPos+1))
 		searchPattern := "%" + req.Search + "%"
@@ -401,8 +463,8 @@ func (r *PostgresWorkflowRepository) List(ctx context.Context, req engine.Workfl
 	// Data query
 	dataQuery := fmt.Sprintf(`
 		SELECT 
-			id, tenant_id, name, description, trigger, nodes,
-			is_active, created_at, updated_at
+			id, tenant_id, name, description, trigger, nodes, variables,
+			is_active, debug, environment, version, created_at, updated_at
 		FROM workflows
 		WHERE %s
 		ORDER BY name ASC
@@ -441,7 +503,7 @@ func (r *PostgresWorkflowRepository) BulkUpdateStatus(ctx context.Context, ids [
 
 	query := `
 		UPDATE workflows 
-		SET is_active = $1, updated_at = NOW()
+		SET is_active = $1, version = version + 1, updated_at = NOW()
 		WHERE tenant_id = $2 AND id = ANY($3)`
 
 	_, err := r.db.ExecContext(ctx, query, isActive, tenantID.String(), pq.Array(idStrings))
@@ -0,0 +1,120 @@
+package engineinfra
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	conversationLockPrefix = "relay:conversation_lock:"
+
+	// conversationLockTTL is the lease; it is renewed while the holder runs so
+	// only a crashed instance lets it expire
+	conversationLockTTL = 30 * time.Second
+
+	// conversationLockMaxWait bounds how long a message waits behind the
+	// previous one before it is processed anyway
+	conversationLockMaxWait = 2 * time.Minute
+
+	conversationLockMinPoll = 20 * time.Millisecond
+	conversationLockMaxPoll = 250 * time.Millisecond
+)
+
+// releaseScript deletes the lock only if this holder still owns it
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// renewScript extends the lease only if this holder still owns it
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// RedisConversationLock is a Redis lease lock shared by every instance
+type RedisConversationLock struct {
+	client *redis.Client
+}
+
+var _ engine.ConversationLock = (*RedisConversationLock)(nil)
+
+func NewRedisConversationLock(client *redis.Client) *RedisConversationLock {
+	return &RedisConversationLock{client: client}
+}
+
+// Acquire implements engine.ConversationLock
+func (l *RedisConversationLock) Acquire(ctx context.Context, key string) (func(), error) {
+	redisKey := conversationLockPrefix + key
+	token := uuid.NewString()
+
+	ctx, cancel := context.WithTimeout(ctx, conversationLockMaxWait)
+	defer cancel()
+
+	poll := conversationLockMinPoll
+	for {
+		ok, err := l.client.SetNX(ctx, redisKey, token, conversationLockTTL).Result()
+		if err != nil {
+			return nil, errx.Wrap(err, "failed to acquire conversation lock", errx.TypeUnavailable).
+				WithDetail("key", key)
+		}
+		if ok {
+			return l.hold(redisKey, token), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errx.Wrap(ctx.Err(), "timed out waiting for conversation lock", errx.TypeTimeout).
+				WithDetail("key", key)
+		case <-time.After(poll):
+		}
+
+		if poll *= 2; poll > conversationLockMaxPoll {
+			poll = conversationLockMaxPoll
+		}
+	}
+}
+
+// hold renews the lease until the returned release func is called
+func (l *RedisConversationLock) hold(redisKey, token string) func() {
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(conversationLockTTL / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				ttl := conversationLockTTL.Milliseconds()
+				if err := renewScript.Run(context.Background(), l.client, []string{redisKey}, token, ttl).Err(); err != nil {
+					log.Printf("⚠️  Failed to renew conversation lock %s: %v", redisKey, err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+
+			if err := releaseScript.Run(context.Background(), l.client, []string{redisKey}, token).Err(); err != nil {
+				log.Printf("⚠️  Failed to release conversation lock %s: %v", redisKey, err)
+			}
+		})
+	}
+}
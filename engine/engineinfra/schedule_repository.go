@@ -32,14 +32,14 @@ func (r *PostgresScheduleRepository) Save(ctx context.Context, schedule engine.W
             id, tenant_id, workflow_id,
             schedule_type, cron_expression, interval_seconds, scheduled_at,
             is_active, last_run_at, next_run_at, run_count,
-            timezone, metadata,
+            timezone, metadata, payload,
             created_at, updated_at
         ) VALUES (
             $1, $2, $3,
             $4, $5, $6, $7,
             $8, $9, $10, $11,
-            $12, $13,
-            $14, $15
+            $12, $13, $14,
+            $15, $16
         )
     `
 
@@ -50,6 +50,13 @@ func (r *PostgresScheduleRepository) Save(ctx context.Context, schedule engine.W
 			WithCause(err)
 	}
 
+	payloadJSON, err := json.Marshal(schedule.Payload)
+	if err != nil {
+		return engine.ErrInvalidScheduleConfig().
+			WithDetail("reason", "failed to marshal payload").
+			WithCause(err)
+	}
+
 	_, err = r.db.ExecContext(ctx, query,
 		schedule.ID,
 		schedule.TenantID,
@@ -64,6 +71,7 @@ func (r *PostgresScheduleRepository) Save(ctx context.Context, schedule engine.W
 		schedule.RunCount,
 		schedule.Timezone,
 		metadataJSON,
+		payloadJSON,
 		schedule.CreatedAt,
 		schedule.UpdatedAt,
 	)
@@ -92,8 +100,9 @@ func (r *PostgresScheduleRepository) Update(ctx context.Context, schedule engine
             run_count = $8,
             timezone = $9,
             metadata = $10,
-            updated_at = $11
-        WHERE id = $12
+            payload = $11,
+            updated_at = $12
+        WHERE id = $13
     `
 
 	metadataJSON, err := json.Marshal(schedule.Metadata)
@@ -103,6 +112,13 @@ func (r *PostgresScheduleRepository) Update(ctx context.Context, schedule engine
 			WithCause(err)
 	}
 
+	payloadJSON, err := json.Marshal(schedule.Payload)
+	if err != nil {
+		return engine.ErrInvalidScheduleConfig().
+			WithDetail("reason", "failed to marshal payload").
+			WithCause(err)
+	}
+
 	result, err := r.db.ExecContext(ctx, query,
 		schedule.ScheduleType,
 		schedule.CronExpression,
@@ -114,6 +130,7 @@ func (r *PostgresScheduleRepository) Update(ctx context.Context, schedule engine
 		schedule.RunCount,
 		schedule.Timezone,
 		metadataJSON,
+		payloadJSON,
 		time.Now(),
 		schedule.ID,
 	)
@@ -147,14 +164,14 @@ func (r *PostgresScheduleRepository) FindByID(ctx context.Context, id string) (*
             id, tenant_id, workflow_id,
             schedule_type, cron_expression, interval_seconds, scheduled_at,
             is_active, last_run_at, next_run_at, run_count,
-            timezone, metadata,
+            timezone, metadata, payload,
             created_at, updated_at
         FROM workflow_schedules
         WHERE id = $1
     `
 
 	var schedule engine.WorkflowSchedule
-	var metadataJSON []byte
+	var metadataJSON, payloadJSON []byte
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&schedule.ID,
@@ -170,6 +187,7 @@ func (r *PostgresScheduleRepository) FindByID(ctx context.Context, id string) (*
 		&schedule.RunCount,
 		&schedule.Timezone,
 		&metadataJSON,
+		&payloadJSON,
 		&schedule.CreatedAt,
 		&schedule.UpdatedAt,
 	)
@@ -195,6 +213,15 @@ func (r *PostgresScheduleRepository) FindByID(ctx context.Context, id string) (*
 		}
 	}
 
+	// Unmarshal payload
+	if len(payloadJSON) > 0 && string(payloadJSON) != "null" {
+		if err := json.Unmarshal(payloadJSON, &schedule.Payload); err != nil {
+			return nil, engine.ErrInvalidScheduleConfig().
+				WithDetail("reason", "failed to unmarshal payload").
+				WithCause(err)
+		}
+	}
+
 	return &schedule, nil
 }
 
@@ -239,7 +266,7 @@ func (r *PostgresScheduleRepository) FindByWorkflow(
             id, tenant_id, workflow_id,
             schedule_type, cron_expression, interval_seconds, scheduled_at,
             is_active, last_run_at, next_run_at, run_count,
-            timezone, metadata,
+            timezone, metadata, payload,
             created_at, updated_at
         FROM workflow_schedules
         WHERE workflow_id = $1
@@ -283,7 +310,7 @@ func (r *PostgresScheduleRepository) FindByTenant(
             s.id, s.tenant_id, s.workflow_id,
             s.schedule_type, s.cron_expression, s.interval_seconds, s.scheduled_at,
             s.is_active, s.last_run_at, s.next_run_at, s.run_count,
-            s.timezone, s.metadata,
+            s.timezone, s.metadata, s.payload,
             s.created_at, s.updated_at
         FROM workflow_schedules s
         WHERE s.tenant_id = $1
@@ -327,7 +354,7 @@ func (r *PostgresScheduleRepository) FindDue(
             id, tenant_id, workflow_id,
             schedule_type, cron_expression, interval_seconds, scheduled_at,
             is_active, last_run_at, next_run_at, run_count,
-            timezone, metadata,
+            timezone, metadata, payload,
             created_at, updated_at
         FROM workflow_schedules
         WHERE is_active = true
@@ -373,7 +400,7 @@ func (r *PostgresScheduleRepository) FindActive(
             id, tenant_id, workflow_id,
             schedule_type, cron_expression, interval_seconds, scheduled_at,
             is_active, last_run_at, next_run_at, run_count,
-            timezone, metadata,
+            timezone, metadata, payload,
             created_at, updated_at
         FROM workflow_schedules
         WHERE tenant_id = $1
@@ -419,7 +446,7 @@ func (r *PostgresScheduleRepository) FindByType(
             id, tenant_id, workflow_id,
             schedule_type, cron_expression, interval_seconds, scheduled_at,
             is_active, last_run_at, next_run_at, run_count,
-            timezone, metadata,
+            timezone, metadata, payload,
             created_at, updated_at
         FROM workflow_schedules
         WHERE tenant_id = $1
@@ -595,7 +622,7 @@ func (r *PostgresScheduleRepository) GetNextRunTimes(
             id, tenant_id, workflow_id,
             schedule_type, cron_expression, interval_seconds, scheduled_at,
             is_active, last_run_at, next_run_at, run_count,
-            timezone, metadata,
+            timezone, metadata, payload,
             created_at, updated_at
         FROM workflow_schedules
         WHERE tenant_id = $1
@@ -641,7 +668,7 @@ func (r *PostgresScheduleRepository) scanSchedule(scanner interface {
 	Scan(dest ...interface{}) error
 }) (*engine.WorkflowSchedule, error) {
 	var schedule engine.WorkflowSchedule
-	var metadataJSON []byte
+	var metadataJSON, payloadJSON []byte
 
 	err := scanner.Scan(
 		&schedule.ID,
@@ -657,6 +684,7 @@ func (r *PostgresScheduleRepository) scanSchedule(scanner interface {
 		&schedule.RunCount,
 		&schedule.Timezone,
 		&metadataJSON,
+		&payloadJSON,
 		&schedule.CreatedAt,
 		&schedule.UpdatedAt,
 	)
@@ -676,6 +704,15 @@ func (r *PostgresScheduleRepository) scanSchedule(scanner interface {
 		}
 	}
 
+	// Unmarshal payload
+	if len(payloadJSON) > 0 && string(payloadJSON) != "null" {
+		if err := json.Unmarshal(payloadJSON, &schedule.Payload); err != nil {
+			return nil, engine.ErrInvalidScheduleConfig().
+				WithDetail("reason", "failed to unmarshal payload").
+				WithCause(err)
+		}
+	}
+
 	return &schedule, nil
 }
 
@@ -0,0 +1,34 @@
+package engineinfra
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Abraxas-365/relay/attachment"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// StorageOutputSpiller writes oversized node outputs to the attachment
+// storage as JSON files under the tenant's prefix
+type StorageOutputSpiller struct {
+	storage attachment.Storage
+}
+
+var _ engine.OutputSpiller = (*StorageOutputSpiller)(nil)
+
+func NewStorageOutputSpiller(storage attachment.Storage) *StorageOutputSpiller {
+	return &StorageOutputSpiller{storage: storage}
+}
+
+func (s *StorageOutputSpiller) SpillOutput(ctx context.Context, tenantID kernel.TenantID, nodeID, field string, data []byte) (string, error) {
+	key := attachment.NewStorageKey(tenantID, uuid.NewString(), "output.json", time.Now())
+
+	if err := s.storage.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/json"); err != nil {
+		return "", fmt.Errorf("failed to store node output: %w", err)
+	}
+	return key, nil
+}
@@ -12,12 +12,16 @@ var (
 	// Workflow errors
 	CodeWorkflowNotFound        = ErrRegistry.Register("WORKFLOW_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Workflow not found")
 	CodeWorkflowAlreadyExists   = ErrRegistry.Register("WORKFLOW_ALREADY_EXISTS", errx.TypeConflict, http.StatusConflict, "Workflow already exists")
+	CodeWorkflowVersionConflict = ErrRegistry.Register("WORKFLOW_VERSION_CONFLICT", errx.TypeConflict, http.StatusConflict, "Workflow was modified by another request")
 	CodeInvalidWorkflowConfig   = ErrRegistry.Register("INVALID_WORKFLOW_CONFIG", errx.TypeValidation, http.StatusBadRequest, "Invalid workflow configuration")
 	CodeWorkflowInactive        = ErrRegistry.Register("WORKFLOW_INACTIVE", errx.TypeBusiness, http.StatusForbidden, "Workflow is inactive")
 	CodeWorkflowExecutionFailed = ErrRegistry.Register("WORKFLOW_EXECUTION_FAILED", errx.TypeInternal, http.StatusInternalServerError, "Workflow execution failed")
 	CodeInvalidWorkflowNode     = ErrRegistry.Register("INVALID_WORKFLOW_NODE", errx.TypeValidation, http.StatusBadRequest, "Invalid workflow node")
 	CodeNodeNotFound            = ErrRegistry.Register("NODE_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Node not found")
 	CodeCyclicWorkflow          = ErrRegistry.Register("CYCLIC_WORKFLOW", errx.TypeValidation, http.StatusBadRequest, "Workflow has cycles")
+	CodeMissingResources        = ErrRegistry.Register("MISSING_RESOURCES", errx.TypeValidation, http.StatusBadRequest, "Workflow references resources that do not exist")
+	CodeNodeTypeNotFound        = ErrRegistry.Register("NODE_TYPE_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Node type is not registered")
+	CodeInvalidNodeType         = ErrRegistry.Register("INVALID_NODE_TYPE", errx.TypeValidation, http.StatusBadRequest, "Invalid node type")
 
 	// Trigger errors
 	CodeInvalidTrigger     = ErrRegistry.Register("INVALID_TRIGGER", errx.TypeValidation, http.StatusBadRequest, "Invalid trigger")
@@ -26,6 +30,9 @@ var (
 	// Execution errors
 	CodeExecutionTimeout    = ErrRegistry.Register("EXECUTION_TIMEOUT", errx.TypeInternal, http.StatusRequestTimeout, "Execution timeout")
 	CodeNodeExecutionFailed = ErrRegistry.Register("NODE_EXECUTION_FAILED", errx.TypeInternal, http.StatusInternalServerError, "Node execution failed")
+	CodeExpressionFailed    = ErrRegistry.Register("EXPRESSION_EVALUATION_FAILED", errx.TypeValidation, http.StatusUnprocessableEntity, "Expression evaluation failed")
+	CodeRegexCompileFailed  = ErrRegistry.Register("REGEX_COMPILE_FAILED", errx.TypeValidation, http.StatusBadRequest, "Regular expression could not be compiled")
+	CodeLegacyTemplate      = ErrRegistry.Register("LEGACY_TEMPLATE_SYNTAX", errx.TypeValidation, http.StatusBadRequest, "Template uses legacy mustache syntax, use CEL expressions instead")
 
 	// ✅ Schedule errors
 	CodeScheduleNotFound        = ErrRegistry.Register("SCHEDULE_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Schedule not found")
@@ -38,6 +45,32 @@ var (
 	CodeScheduleExecutionFailed = ErrRegistry.Register("SCHEDULE_EXECUTION_FAILED", errx.TypeInternal, http.StatusInternalServerError, "Schedule execution failed")
 	CodeScheduleNotActive       = ErrRegistry.Register("SCHEDULE_NOT_ACTIVE", errx.TypeBusiness, http.StatusForbidden, "Schedule is not active")
 	CodeTooManySchedules        = ErrRegistry.Register("TOO_MANY_SCHEDULES", errx.TypeBusiness, http.StatusTooManyRequests, "Too many schedules for workflow")
+
+	// Business hours errors
+	CodeInvalidBusinessHours = ErrRegistry.Register("INVALID_BUSINESS_HOURS", errx.TypeValidation, http.StatusBadRequest, "Invalid business hours configuration")
+
+	// Dead letter errors
+	CodeDeadLetterNotFound     = ErrRegistry.Register("DEAD_LETTER_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Dead letter not found")
+	CodeDeadLetterReprocessed  = ErrRegistry.Register("DEAD_LETTER_REPROCESSED", errx.TypeConflict, http.StatusConflict, "Dead letter was already reprocessed")
+	CodeInvalidDeadLetterQuery = ErrRegistry.Register("INVALID_DEAD_LETTER_QUERY", errx.TypeValidation, http.StatusBadRequest, "Invalid dead letter query")
+
+	// Continuation errors
+	CodeContinuationNotFound = ErrRegistry.Register("CONTINUATION_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Pending continuation not found")
+
+	// Fault injection errors
+	CodeInvalidFaultInjection = ErrRegistry.Register("INVALID_FAULT_INJECTION", errx.TypeValidation, http.StatusBadRequest, "Invalid fault injection rules")
+
+	// Promotion errors
+	CodeInvalidPromotion = ErrRegistry.Register("INVALID_PROMOTION", errx.TypeValidation, http.StatusUnprocessableEntity, "Workflow cannot be promoted to production")
+
+	// Context variable errors
+	CodeInvalidVariableSchema = ErrRegistry.Register("INVALID_VARIABLE_SCHEMA", errx.TypeValidation, http.StatusBadRequest, "Invalid context variable schema")
+	CodeUndeclaredVariable    = ErrRegistry.Register("UNDECLARED_VARIABLE", errx.TypeValidation, http.StatusBadRequest, "Workflow uses context variables it does not declare")
+	CodeInvalidVariableValue  = ErrRegistry.Register("INVALID_VARIABLE_VALUE", errx.TypeValidation, http.StatusUnprocessableEntity, "Value does not match the context variable's type")
+
+	// Snapshot errors
+	CodeRunNotFound   = ErrRegistry.Register("RUN_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Recorded run not found")
+	CodeInvalidReplay = ErrRegistry.Register("INVALID_REPLAY", errx.TypeValidation, http.StatusBadRequest, "Run cannot be replayed from that node")
 )
 
 // ============================================================================
@@ -52,6 +85,10 @@ func ErrWorkflowAlreadyExists() *errx.Error {
 	return ErrRegistry.New(CodeWorkflowAlreadyExists)
 }
 
+func ErrWorkflowVersionConflict() *errx.Error {
+	return ErrRegistry.New(CodeWorkflowVersionConflict)
+}
+
 func ErrInvalidWorkflowConfig() *errx.Error {
 	return ErrRegistry.New(CodeInvalidWorkflowConfig)
 }
@@ -76,6 +113,18 @@ func ErrCyclicWorkflow() *errx.Error {
 	return ErrRegistry.New(CodeCyclicWorkflow)
 }
 
+func ErrMissingResources() *errx.Error {
+	return ErrRegistry.New(CodeMissingResources)
+}
+
+func ErrNodeTypeNotFound() *errx.Error {
+	return ErrRegistry.New(CodeNodeTypeNotFound)
+}
+
+func ErrInvalidNodeType() *errx.Error {
+	return ErrRegistry.New(CodeInvalidNodeType)
+}
+
 // ============================================================================
 // Trigger Error Constructors
 // ============================================================================
@@ -100,6 +149,18 @@ func ErrNodeExecutionFailed() *errx.Error {
 	return ErrRegistry.New(CodeNodeExecutionFailed)
 }
 
+func ErrExpressionFailed() *errx.Error {
+	return ErrRegistry.New(CodeExpressionFailed)
+}
+
+func ErrRegexCompileFailed() *errx.Error {
+	return ErrRegistry.New(CodeRegexCompileFailed)
+}
+
+func ErrLegacyTemplate() *errx.Error {
+	return ErrRegistry.New(CodeLegacyTemplate)
+}
+
 // ============================================================================
 // ✅ Schedule Error Constructors
 // ============================================================================
@@ -143,3 +204,79 @@ func ErrScheduleNotActive() *errx.Error {
 func ErrTooManySchedules() *errx.Error {
 	return ErrRegistry.New(CodeTooManySchedules)
 }
+
+// ============================================================================
+// Business Hours Error Constructors
+// ============================================================================
+
+func ErrInvalidBusinessHours() *errx.Error {
+	return ErrRegistry.New(CodeInvalidBusinessHours)
+}
+
+// ============================================================================
+// Dead Letter Error Constructors
+// ============================================================================
+
+func ErrDeadLetterNotFound() *errx.Error {
+	return ErrRegistry.New(CodeDeadLetterNotFound)
+}
+
+func ErrDeadLetterReprocessed() *errx.Error {
+	return ErrRegistry.New(CodeDeadLetterReprocessed)
+}
+
+func ErrInvalidDeadLetterQuery() *errx.Error {
+	return ErrRegistry.New(CodeInvalidDeadLetterQuery)
+}
+
+// ============================================================================
+// Continuation Error Constructors
+// ============================================================================
+
+func ErrContinuationNotFound() *errx.Error {
+	return ErrRegistry.New(CodeContinuationNotFound)
+}
+
+// ============================================================================
+// Fault Injection Error Constructors
+// ============================================================================
+
+func ErrInvalidFaultInjection() *errx.Error {
+	return ErrRegistry.New(CodeInvalidFaultInjection)
+}
+
+// ============================================================================
+// Promotion Error Constructors
+// ============================================================================
+
+func ErrInvalidPromotion() *errx.Error {
+	return ErrRegistry.New(CodeInvalidPromotion)
+}
+
+// ============================================================================
+// Context Variable Error Constructors
+// ============================================================================
+
+func ErrInvalidVariableSchema() *errx.Error {
+	return ErrRegistry.New(CodeInvalidVariableSchema)
+}
+
+func ErrUndeclaredVariable() *errx.Error {
+	return ErrRegistry.New(CodeUndeclaredVariable)
+}
+
+func ErrInvalidVariableValue() *errx.Error {
+	return ErrRegistry.New(CodeInvalidVariableValue)
+}
+
+// ============================================================================
+// Snapshot Error Constructors
+// ============================================================================
+
+func ErrRunNotFound() *errx.Error {
+	return ErrRegistry.New(CodeRunNotFound)
+}
+
+func ErrInvalidReplay() *errx.Error {
+	return ErrRegistry.New(CodeInvalidReplay)
+}
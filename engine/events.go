@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Execution Events
+// ============================================================================
+
+// Event types the executor publishes on the event bus
+const (
+	EventWorkflowStarted   = "workflow.started"
+	EventNodeCompleted     = "node.completed" // Published for failed nodes too, with success=false
+	EventWorkflowCompleted = "workflow.completed"
+	EventWorkflowFailed    = "workflow.failed"
+)
+
+// ExecutionEvent describes one step of a workflow run. Node fields are only
+// set on node.completed.
+type ExecutionEvent struct {
+	TenantID       kernel.TenantID   `json:"tenant_id"`
+	WorkflowID     kernel.WorkflowID `json:"workflow_id"`
+	WorkflowName   string            `json:"workflow_name"`
+	TriggerType    TriggerType       `json:"trigger_type,omitempty"`
+	ChannelID      string            `json:"channel_id,omitempty"`
+	ConversationID string            `json:"conversation_id,omitempty"` // The trigger's sender
+	NodeID         string            `json:"node_id,omitempty"`
+	NodeType       NodeType          `json:"node_type,omitempty"`
+	Success        bool              `json:"success"`
+	Error          string            `json:"error,omitempty"`
+	DurationMs     int64             `json:"duration_ms,omitempty"`
+	At             time.Time         `json:"at"`
+}
+
+// NewExecutionEvent fills the attributes every event of a run shares
+func NewExecutionEvent(workflow Workflow, input WorkflowInput) ExecutionEvent {
+	event := ExecutionEvent{
+		TenantID:     workflow.TenantID,
+		WorkflowID:   workflow.ID,
+		WorkflowName: workflow.Name,
+	}
+	switch triggerType := input.Metadata["trigger_type"].(type) {
+	case TriggerType:
+		event.TriggerType = triggerType
+	case string:
+		event.TriggerType = TriggerType(triggerType)
+	}
+	event.ChannelID, _ = input.TriggerData["channel_id"].(string)
+	event.ConversationID, _ = input.TriggerData["sender_id"].(string)
+	return event
+}
+
+// ============================================================================
+// Session Events
+// ============================================================================
+
+// EventSessionExpired is published when the messaging window of a
+// conversation closes without a new message from the contact
+const EventSessionExpired = "session.expired"
+
+// SessionExpiredEvent describes a conversation whose session expired.
+// Context is the trigger data of the contact's last message.
+type SessionExpiredEvent struct {
+	TenantID       kernel.TenantID `json:"tenant_id"`
+	ChannelID      string          `json:"channel_id"`
+	ConversationID string          `json:"conversation_id"`
+	LastMessageID  string          `json:"last_message_id,omitempty"`
+	LastMessageAt  time.Time       `json:"last_message_at"`
+	Context        map[string]any  `json:"context"`
+	At             time.Time       `json:"at"`
+}
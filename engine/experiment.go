@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"hash/fnv"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Experiments
+// ============================================================================
+
+// experimentBuckets is the resolution of a traffic split, so weights can go
+// down to a hundredth of a percent
+const experimentBuckets = 10000
+
+// ExperimentVariant is one arm of an EXPERIMENT node. Weight is the percent
+// of contacts it receives; NextNode is where they go, on_success when empty.
+type ExperimentVariant struct {
+	Name     string  `json:"name"`
+	Weight   float64 `json:"weight"`
+	NextNode string  `json:"next_node,omitempty"`
+}
+
+// AssignVariant picks the contact's variant. The same contact always lands
+// in the same variant of an experiment, and different experiments split
+// independently. Weights must add up to 100.
+func AssignVariant(tenantID kernel.TenantID, experiment, contactID string, variants []ExperimentVariant) ExperimentVariant {
+	hash := fnv.New32a()
+	hash.Write([]byte(tenantID.String() + "\x00" + experiment + "\x00" + contactID))
+	bucket := float64(hash.Sum32() % experimentBuckets)
+
+	var upper float64
+	for _, variant := range variants {
+		upper += variant.Weight * experimentBuckets / 100
+		if bucket < upper {
+			return variant
+		}
+	}
+	// Rounding can leave the last bucket uncovered
+	return variants[len(variants)-1]
+}
+
+// ExperimentEventKind is what happened to a contact in an experiment
+type ExperimentEventKind string
+
+const (
+	ExperimentExposure   ExperimentEventKind = "EXPOSURE"   // Routed to a variant
+	ExperimentConversion ExperimentEventKind = "CONVERSION" // Reached a goal
+)
+
+// ExperimentEvent is an exposure or conversion recorded by an EXPERIMENT
+// node. A conversion's variant is the contact's latest exposure.
+type ExperimentEvent struct {
+	TenantID   kernel.TenantID
+	WorkflowID string
+	Experiment string
+	Variant    string // Exposures only
+	ContactID  string
+	ChannelID  string
+	Goal       string  // Conversions only; empty = the experiment's main goal
+	Value      float64 // Conversions only, e.g. an order amount
+}
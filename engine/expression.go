@@ -2,12 +2,16 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/Abraxas-365/craftable/errx"
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types/ref"
 )
@@ -23,22 +27,29 @@ type ExpressionEvaluator interface {
 // celEvaluator is an implementation of ExpressionEvaluator using CEL-Go.
 type celEvaluator struct {
 	expressionRegex *regexp.Regexp
+	businessHours   BusinessHoursProvider // nil = in_business_hours() not available
+	segments        SegmentChecker        // nil = contact.in_segment() not available
 }
 
-// NewCelEvaluator creates a new expression evaluator.
-func NewCelEvaluator() ExpressionEvaluator {
+// NewCelEvaluator creates a new expression evaluator. businessHours backs the
+// in_business_hours() and is_holiday() functions and the holiday check of
+// next_business_day_at(), and segments backs contact.in_segment(); both may
+// be nil.
+func NewCelEvaluator(businessHours BusinessHoursProvider, segments SegmentChecker) ExpressionEvaluator {
 	return &celEvaluator{
 		// Regex to find expressions like {{ expression }}
 		expressionRegex: regexp.MustCompile(`\{\{([^}]+)\}\}`),
+		businessHours:   businessHours,
+		segments:        segments,
 	}
 }
 
 func (e *celEvaluator) Evaluate(ctx context.Context, data any, context map[string]any) (any, error) {
-	return e.evaluateRecursive(reflect.ValueOf(data), context)
+	return e.evaluateRecursive(ctx, reflect.ValueOf(data), context)
 }
 
 // evaluateRecursive is the core evaluation logic.
-func (e *celEvaluator) evaluateRecursive(val reflect.Value, context map[string]any) (any, error) {
+func (e *celEvaluator) evaluateRecursive(ctx context.Context, val reflect.Value, context map[string]any) (any, error) {
 	// Handle pointers and interfaces
 	if val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
 		if val.IsNil() {
@@ -50,13 +61,13 @@ func (e *celEvaluator) evaluateRecursive(val reflect.Value, context map[string]a
 	switch val.Kind() {
 	case reflect.String:
 		// This is where we find and replace expressions
-		return e.evaluateString(val.String(), context)
+		return e.evaluateString(ctx, val.String(), context)
 
 	case reflect.Map:
 		newMap := make(map[string]any)
 		for _, key := range val.MapKeys() {
 			// Evaluate the value of each map entry
-			evaluatedVal, err := e.evaluateRecursive(val.MapIndex(key), context)
+			evaluatedVal, err := e.evaluateRecursive(ctx, val.MapIndex(key), context)
 			if err != nil {
 				return nil, err
 			}
@@ -68,7 +79,7 @@ func (e *celEvaluator) evaluateRecursive(val reflect.Value, context map[string]a
 		newSlice := make([]any, val.Len())
 		for i := 0; i < val.Len(); i++ {
 			// Evaluate each item in the slice
-			evaluatedItem, err := e.evaluateRecursive(val.Index(i), context)
+			evaluatedItem, err := e.evaluateRecursive(ctx, val.Index(i), context)
 			if err != nil {
 				return nil, err
 			}
@@ -83,7 +94,7 @@ func (e *celEvaluator) evaluateRecursive(val reflect.Value, context map[string]a
 }
 
 // evaluateString finds and evaluates all expressions in a single string.
-func (e *celEvaluator) evaluateString(s string, context map[string]any) (any, error) {
+func (e *celEvaluator) evaluateString(ctx context.Context, s string, context map[string]any) (any, error) {
 	matches := e.expressionRegex.FindStringSubmatch(s)
 
 	// If the string is *only* an expression (e.g., "{{step_1.output}}"),
@@ -97,7 +108,7 @@ func (e *celEvaluator) evaluateString(s string, context map[string]any) (any, er
 			return value, nil
 		}
 
-		return e.evaluateCEL(expr, context)
+		return e.evaluateCEL(ctx, expr, context)
 	}
 
 	// Otherwise, replace all occurrences of expressions inside the string.
@@ -110,7 +121,7 @@ func (e *celEvaluator) evaluateString(s string, context map[string]any) (any, er
 			return fmt.Sprintf("%v", value)
 		}
 
-		evaluatedVal, err := e.evaluateCEL(expr, context)
+		evaluatedVal, err := e.evaluateCEL(ctx, expr, context)
 		if err != nil {
 			evalError = err
 			return match // Return original on error
@@ -126,7 +137,7 @@ func (e *celEvaluator) evaluateString(s string, context map[string]any) (any, er
 }
 
 // evaluateCEL compiles and runs a single CEL expression.
-func (e *celEvaluator) evaluateCEL(expression string, context map[string]any) (any, error) {
+func (e *celEvaluator) evaluateCEL(ctx context.Context, expression string, context map[string]any) (any, error) {
 	log.Printf("🔍 Evaluating CEL expression: '%s'", expression)
 	log.Printf("   Available context keys: %v", getContextKeys(context))
 
@@ -138,6 +149,9 @@ func (e *celEvaluator) evaluateCEL(expression string, context map[string]any) (a
 		envOptions = append(envOptions, cel.Variable(key, cel.DynType))
 	}
 
+	// Helper functions bound to the tenant of this evaluation
+	envOptions = append(envOptions, e.functionOptions(ctx, context)...)
+
 	env, err := cel.NewEnv(envOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
@@ -146,7 +160,7 @@ func (e *celEvaluator) evaluateCEL(expression string, context map[string]any) (a
 	parsed, issues := env.Parse(expression)
 	if issues != nil && issues.Err() != nil {
 		log.Printf("❌ CEL parse error for '%s': %v", expression, issues.Err())
-		return nil, fmt.Errorf("failed to parse expression '%s': %w", expression, issues.Err())
+		return nil, expressionError(expression, "failed to parse expression", issues.Err())
 	}
 
 	checked, issues := env.Check(parsed)
@@ -158,14 +172,18 @@ func (e *celEvaluator) evaluateCEL(expression string, context map[string]any) (a
 	prg, err := env.Program(checked)
 	if err != nil {
 		log.Printf("❌ CEL program error for '%s': %v", expression, err)
-		return nil, fmt.Errorf("failed to create program for '%s': %w", expression, err)
+		// A failed check leaves nothing to plan; its issues say why
+		if issues != nil && issues.Err() != nil {
+			err = issues.Err()
+		}
+		return nil, expressionError(expression, "failed to create program", err)
 	}
 
 	out, _, err := prg.Eval(context)
 	if err != nil {
 		log.Printf("❌ CEL eval error for '%s': %v", expression, err)
 		log.Printf("   Context: %+v", context)
-		return nil, fmt.Errorf("failed to evaluate expression '%s': %w", expression, err)
+		return nil, expressionError(expression, "failed to evaluate expression", err)
 	}
 
 	// Convert CEL type to native Go type
@@ -220,3 +238,110 @@ func getContextKeys(m map[string]any) []string {
 	return keys
 }
 
+// missingVariableRegex extracts the variable name from CEL lookup errors
+var missingVariableRegex = regexp.MustCompile(`(?:no such key:|undeclared reference to|no such attribute[^:]*:)\s*'?([\w.\[\]"-]+)'?`)
+
+// expressionError wraps a CEL failure with the expression and, when CEL
+// reports it, the variable that could not be resolved
+func expressionError(expression, reason string, err error) error {
+	xerr := ErrExpressionFailed().
+		WithDetail("expression", expression).
+		WithDetail("reason", fmt.Sprintf("%s: %v", reason, err)).
+		WithCause(err)
+
+	if matches := missingVariableRegex.FindStringSubmatch(err.Error()); len(matches) > 1 {
+		xerr.WithDetail("variable", matches[1])
+	}
+
+	return xerr
+}
+
+// templatePattern matches {{ expression }} placeholders, as the evaluator does
+var templatePattern = regexp.MustCompile(`\{\{([^}]+)\}\}`)
+
+// IsTemplate reports whether s holds an expression evaluated at run time
+func IsTemplate(s string) bool {
+	return templatePattern.MatchString(s)
+}
+
+// legacyTemplatePattern matches mustache and Go template constructs that are
+// not CEL and were never substituted: sections ({{#x}}, {{^x}}, {{/x}}),
+// comments, partials, unescaped {{{x}}} / {{&x}} and {{.Field}}
+var legacyTemplatePattern = regexp.MustCompile(`\{\{\s*(?:[#^/!>&{]|\.[A-Za-z_])`)
+
+// templateEnv is shared by every CheckTemplate call, parsing needs no variables
+var templateEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv()
+})
+
+// CheckTemplate parses every expression in s so syntax errors surface when a
+// workflow is saved rather than when the node runs. Variables are not
+// resolved, they only exist at run time. Legacy mustache syntax is rejected.
+func CheckTemplate(s string) error {
+	if legacy := legacyTemplatePattern.FindString(s); legacy != "" {
+		return ErrLegacyTemplate().
+			WithDetail("template", s).
+			WithDetail("hint", "templates are CEL expressions, e.g. {{trigger.body.name}} or {{size(items) > 0 ? 'yes' : 'no'}}")
+	}
+
+	env, err := templateEnv()
+	if err != nil {
+		return fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	for _, match := range templatePattern.FindAllStringSubmatch(s, -1) {
+		expression := strings.TrimSpace(match[1])
+		if _, issues := env.Parse(expression); issues != nil && issues.Err() != nil {
+			return expressionError(expression, "failed to parse expression", issues.Err())
+		}
+	}
+	return nil
+}
+
+// CheckConfigTemplates runs CheckTemplate on every string of a node config,
+// nested maps and lists included, and reports the path of the first field
+// that fails
+func CheckConfigTemplates(config map[string]any) error {
+	return checkTemplates(config, "")
+}
+
+func checkTemplates(value any, path string) error {
+	switch v := value.(type) {
+	case string:
+		if !strings.Contains(v, "{{") {
+			return nil
+		}
+		if err := CheckTemplate(v); err != nil {
+			var xerr *errx.Error
+			if errors.As(err, &xerr) {
+				return xerr.WithDetail("field", path)
+			}
+			return err
+		}
+
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			field := key
+			if path != "" {
+				field = path + "." + key
+			}
+			if err := checkTemplates(v[key], field); err != nil {
+				return err
+			}
+		}
+
+	case []any:
+		for i, item := range v {
+			if err := checkTemplates(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
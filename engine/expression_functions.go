@@ -0,0 +1,282 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// ============================================================================
+// CEL Helper Functions
+// ============================================================================
+
+// functionOptions declares the helper functions available to expressions.
+// They are bound per evaluation because they depend on the tenant in context.
+//
+//	now()                                  the current time
+//	next_business_day_at(clock, timezone)  clock ("09:00") on the first weekday
+//	                                       after today in timezone that is not
+//	                                       a tenant holiday
+//	in_business_hours()                    true when the tenant is open now
+//	is_holiday()                           true when today is a holiday for the tenant
+//	distance_km(lat1, lng1, lat2, lng2)    great-circle distance between two points
+//	within_km(lat1, lng1, lat2, lng2, km)  true when the points are at most km apart
+//	contact.in_segment(name)               true when the conversation's contact is
+//	                                       a member of the tenant's named segment
+func (e *celEvaluator) functionOptions(ctx context.Context, context map[string]any) []cel.EnvOption {
+	options := []cel.EnvOption{
+		cel.Function("now",
+			cel.Overload("now", []*cel.Type{}, cel.TimestampType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					return types.Timestamp{Time: time.Now()}
+				}),
+			),
+		),
+		cel.Function("next_business_day_at",
+			cel.Overload("next_business_day_at_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.TimestampType,
+				cel.BinaryBinding(func(clock, timezone ref.Val) ref.Val {
+					at, err := e.nextBusinessDayAt(ctx, context, fmt.Sprint(clock.Value()), fmt.Sprint(timezone.Value()), time.Now())
+					if err != nil {
+						return types.NewErr("next_business_day_at: %v", err)
+					}
+					return types.Timestamp{Time: at}
+				}),
+			),
+		),
+		cel.Function("distance_km",
+			cel.Overload("distance_km_dyn_dyn_dyn_dyn", []*cel.Type{cel.DynType, cel.DynType, cel.DynType, cel.DynType}, cel.DoubleType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					coords, err := celNumbers(args)
+					if err != nil {
+						return types.NewErr("distance_km: %v", err)
+					}
+					return types.Double(distanceKm(coords[0], coords[1], coords[2], coords[3]))
+				}),
+			),
+		),
+		cel.Function("within_km",
+			cel.Overload("within_km_dyn_dyn_dyn_dyn_dyn", []*cel.Type{cel.DynType, cel.DynType, cel.DynType, cel.DynType, cel.DynType}, cel.BoolType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					coords, err := celNumbers(args)
+					if err != nil {
+						return types.NewErr("within_km: %v", err)
+					}
+					return types.Bool(distanceKm(coords[0], coords[1], coords[2], coords[3]) <= coords[4])
+				}),
+			),
+		),
+	}
+
+	if e.segments != nil {
+		options = append(options, e.segmentOptions(ctx, context)...)
+	}
+
+	if e.businessHours == nil {
+		return options
+	}
+
+	status := func() (*BusinessHoursStatus, error) {
+		tenantID, ok := contextTenantID(context)
+		if !ok {
+			return nil, ErrInvalidWorkflowConfig().WithDetail("reason", "tenant_id not available in expression context")
+		}
+		return e.businessHours.Status(ctx, tenantID, time.Now())
+	}
+
+	return append(options,
+		cel.Function("in_business_hours",
+			cel.Overload("in_business_hours", []*cel.Type{}, cel.BoolType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					s, err := status()
+					if err != nil {
+						return types.NewErr("in_business_hours: %v", err)
+					}
+					return types.Bool(s.Open)
+				}),
+			),
+		),
+		cel.Function("is_holiday",
+			cel.Overload("is_holiday", []*cel.Type{}, cel.BoolType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					s, err := status()
+					if err != nil {
+						return types.NewErr("is_holiday: %v", err)
+					}
+					return types.Bool(s.Holiday)
+				}),
+			),
+		),
+	)
+}
+
+// nextBusinessDayAt skips weekends and, when the tenant has a schedule, its
+// holidays. An empty timezone means UTC.
+func (e *celEvaluator) nextBusinessDayAt(ctx context.Context, context map[string]any, clock, timezone string, now time.Time) (time.Time, error) {
+	minutes, err := parseClock(clock, false)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timezone %q", timezone)
+	}
+
+	tenantID, hasTenant := contextTenantID(context)
+	local := now.In(loc)
+	year, month, day := local.Date()
+
+	for offset := 1; offset <= maxNextOpenSearchDays; offset++ {
+		candidate := time.Date(year, month, day+offset, minutes/60, minutes%60, 0, 0, loc)
+		if candidate.Weekday() == time.Saturday || candidate.Weekday() == time.Sunday {
+			continue
+		}
+
+		if e.businessHours != nil && hasTenant {
+			status, err := e.businessHours.Status(ctx, tenantID, candidate)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if status.Holiday {
+				continue
+			}
+		}
+
+		return candidate, nil
+	}
+
+	return time.Time{}, fmt.Errorf("no business day found")
+}
+
+// ============================================================================
+// Segments
+// ============================================================================
+
+// segmentOptions declares contact.in_segment(name). The contact is the one
+// of the receiver's "conversation_id", else of the trigger's conversation;
+// expressions without a "contact" variable get one holding the latter.
+func (e *celEvaluator) segmentOptions(ctx context.Context, context map[string]any) []cel.EnvOption {
+	options := []cel.EnvOption{
+		cel.Function("in_segment",
+			cel.MemberOverload("dyn_in_segment_string", []*cel.Type{cel.DynType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(func(receiver, name ref.Val) ref.Val {
+					tenantID, ok := contextTenantID(context)
+					if !ok {
+						return types.NewErr("in_segment: tenant_id not available in expression context")
+					}
+					conversationID := receiverConversationID(receiver)
+					if conversationID == "" {
+						conversationID = contextConversationID(context)
+					}
+					if conversationID == "" {
+						return types.NewErr("in_segment: conversation not available in expression context")
+					}
+
+					member, err := e.segments.InSegment(ctx, tenantID, conversationID, fmt.Sprint(name.Value()))
+					if err != nil {
+						return types.NewErr("in_segment: %v", err)
+					}
+					return types.Bool(member)
+				}),
+			),
+		),
+	}
+
+	if _, ok := context["contact"]; !ok {
+		contact := map[string]string{"conversation_id": contextConversationID(context)}
+		options = append(options, cel.Constant("contact",
+			cel.MapType(cel.StringType, cel.StringType),
+			types.NewStringStringMap(types.DefaultTypeAdapter, contact)))
+	}
+	return options
+}
+
+// receiverConversationID reads the "conversation_id" of the map
+// in_segment() is called on
+func receiverConversationID(receiver ref.Val) string {
+	switch v := receiver.Value().(type) {
+	case map[string]string:
+		return v["conversation_id"]
+	case map[string]any:
+		id, _ := v["conversation_id"].(string)
+		return id
+	}
+	return ""
+}
+
+// contextConversationID finds the conversation of the running workflow
+func contextConversationID(context map[string]any) string {
+	trigger, ok := context["trigger"].(map[string]any)
+	if !ok {
+		return ""
+	}
+	if id, ok := trigger["conversation_id"].(string); ok && id != "" {
+		return id
+	}
+	id, _ := trigger["sender_id"].(string)
+	return id
+}
+
+// ============================================================================
+// Geo
+// ============================================================================
+
+// earthRadiusKm is the mean Earth radius
+const earthRadiusKm = 6371.0088
+
+// distanceKm is the haversine distance between two coordinates in degrees
+func distanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// celNumbers converts numeric arguments. Coordinates arrive as doubles,
+// ints or strings depending on where they came from.
+func celNumbers(args []ref.Val) ([]float64, error) {
+	numbers := make([]float64, len(args))
+	for i, arg := range args {
+		switch v := arg.Value().(type) {
+		case float64:
+			numbers[i] = v
+		case int64:
+			numbers[i] = float64(v)
+		case uint64:
+			numbers[i] = float64(v)
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("argument %d is not a number: %q", i+1, v)
+			}
+			numbers[i] = f
+		default:
+			return nil, fmt.Errorf("argument %d is not a number", i+1)
+		}
+	}
+	return numbers, nil
+}
+
+// contextTenantID finds the tenant of the running workflow
+func contextTenantID(context map[string]any) (kernel.TenantID, bool) {
+	if tenantID, ok := context["tenant_id"].(string); ok && tenantID != "" {
+		return kernel.TenantID(tenantID), true
+	}
+	if trigger, ok := context["trigger"].(map[string]any); ok {
+		if tenantID, ok := trigger["tenant_id"].(string); ok && tenantID != "" {
+			return kernel.TenantID(tenantID), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,92 @@
+package faultinject
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// Wrap puts the injector in front of each executor. Executors keep their own
+// SupportsType and ValidateConfig, so the wrapped ones register as before.
+func Wrap(service *FaultInjectionService, executors ...engine.NodeExecutor) []engine.NodeExecutor {
+	wrapped := make([]engine.NodeExecutor, len(executors))
+	for i, executor := range executors {
+		wrapped[i] = &faultyExecutor{NodeExecutor: executor, service: service}
+	}
+	return wrapped
+}
+
+// faultyExecutor applies the tenant's first matching rule that fires. Delays
+// still run the node; failures return a failed result without running it, so
+// the node's on_failure edge is taken as for a real failure.
+type faultyExecutor struct {
+	engine.NodeExecutor
+	service *FaultInjectionService
+}
+
+// NodeTypes forwards the custom types the wrapped executor declares, so
+// wrapping does not unregister them
+func (e *faultyExecutor) NodeTypes() []engine.NodeType {
+	if declarer, ok := e.NodeExecutor.(engine.NodeTypeDeclarer); ok {
+		return declarer.NodeTypes()
+	}
+	return nil
+}
+
+func (e *faultyExecutor) Execute(ctx context.Context, node engine.WorkflowNode, input map[string]any) (*engine.NodeResult, error) {
+	tenantID, _ := input["tenant_id"].(string)
+	if tenantID == "" {
+		return e.NodeExecutor.Execute(ctx, node, input)
+	}
+
+	rule, ok := e.service.match(ctx, kernel.TenantID(tenantID), node)
+	if !ok {
+		return e.NodeExecutor.Execute(ctx, node, input)
+	}
+
+	log.Printf("🧪 Injecting %s into node %s (type: %s) for tenant %s", rule.Action, node.ID, node.Type, tenantID)
+
+	if rule.DelayMs > 0 {
+		select {
+		case <-ctx.Done():
+			return &engine.NodeResult{
+				NodeID:    node.ID,
+				NodeName:  node.Name,
+				Success:   false,
+				Error:     ctx.Err().Error(),
+				Output:    map[string]any{},
+				Timestamp: time.Now(),
+			}, nil
+		case <-time.After(rule.delay()):
+		}
+	}
+
+	if rule.Action == ActionDelay {
+		return e.NodeExecutor.Execute(ctx, node, input)
+	}
+
+	message := rule.Message
+	if message == "" {
+		message = "injected fault"
+	}
+	class := rule.failureClass()
+
+	return &engine.NodeResult{
+		NodeID:   node.ID,
+		NodeName: node.Name,
+		Success:  false,
+		Error:    message,
+		Output: map[string]any{
+			"error": map[string]any{
+				"type":          "fault_injection",
+				"message":       message,
+				"failure_class": string(class),
+				"retryable":     class.IsRetryable(),
+			},
+		},
+		Timestamp: time.Now(),
+	}, nil
+}
@@ -0,0 +1,135 @@
+package faultinject
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Rules
+// ============================================================================
+
+// Action is what a rule does to a matching node
+type Action string
+
+const (
+	ActionDelay Action = "delay" // Wait DelayMs, then run the node normally
+	ActionFail  Action = "fail"  // Wait DelayMs if set, then fail without running the node
+)
+
+// maxDelay keeps an injected delay below any sensible node timeout ceiling
+const maxDelay = 5 * time.Minute
+
+// Rule delays or fails a share of the executions of one node type. Provider
+// narrows it further: the AI provider for AI_AGENT, the URL host for HTTP and
+// the channel ID for SEND_MESSAGE. Empty matches every node of the type.
+type Rule struct {
+	NodeType     engine.NodeType       `json:"node_type"`
+	Provider     string                `json:"provider,omitempty"`
+	Action       Action                `json:"action"`
+	Rate         float64               `json:"rate"` // 0 < rate <= 1
+	DelayMs      int                   `json:"delay_ms,omitempty"`
+	FailureClass channels.FailureClass `json:"failure_class,omitempty"` // Fail only, defaults to transient
+	Message      string                `json:"message,omitempty"`
+}
+
+// Config is a tenant's fault injection setup. Rules only apply while
+// injection is enabled for the environment and the tenant's flag is on.
+type Config struct {
+	TenantID  kernel.TenantID `json:"tenant_id"`
+	Rules     []Rule          `json:"rules"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Validate checks every rule
+func (c Config) Validate() error {
+	for i, rule := range c.Rules {
+		if err := rule.validate(); err != nil {
+			return engine.ErrInvalidFaultInjection().
+				WithDetail("rule", i).
+				WithDetail("reason", err.Error())
+		}
+	}
+	return nil
+}
+
+func (r Rule) validate() error {
+	if r.NodeType == "" {
+		return fmt.Errorf("node_type is required")
+	}
+	if r.Rate <= 0 || r.Rate > 1 {
+		return fmt.Errorf("rate must be greater than 0 and at most 1")
+	}
+	if r.DelayMs < 0 || time.Duration(r.DelayMs)*time.Millisecond > maxDelay {
+		return fmt.Errorf("delay_ms must be between 0 and %d", maxDelay.Milliseconds())
+	}
+
+	switch r.Action {
+	case ActionDelay:
+		if r.DelayMs == 0 {
+			return fmt.Errorf("delay_ms is required for delay rules")
+		}
+	case ActionFail:
+		switch r.FailureClass {
+		case "", channels.FailureAuth, channels.FailureRateLimited, channels.FailureTransient, channels.FailurePermanent:
+		default:
+			return fmt.Errorf("unknown failure_class %q", r.FailureClass)
+		}
+	default:
+		return fmt.Errorf("action must be %q or %q", ActionDelay, ActionFail)
+	}
+
+	return nil
+}
+
+// matches reports whether the rule targets the node. The node's config has
+// already been evaluated, so providers compare against resolved values.
+func (r Rule) matches(node engine.WorkflowNode) bool {
+	if r.NodeType != node.Type {
+		return false
+	}
+	if r.Provider == "" {
+		return true
+	}
+	return strings.EqualFold(r.Provider, nodeProvider(node))
+}
+
+// fires rolls the dice for one execution
+func (r Rule) fires() bool {
+	return rand.Float64() < r.Rate
+}
+
+func (r Rule) delay() time.Duration {
+	return time.Duration(r.DelayMs) * time.Millisecond
+}
+
+func (r Rule) failureClass() channels.FailureClass {
+	if r.FailureClass == "" {
+		return channels.FailureTransient
+	}
+	return r.FailureClass
+}
+
+func nodeProvider(node engine.WorkflowNode) string {
+	switch node.Type {
+	case engine.NodeTypeAIAgent:
+		provider, _ := node.Config["provider"].(string)
+		return provider
+	case engine.NodeTypeHTTP:
+		raw, _ := node.Config["url"].(string)
+		if u, err := url.Parse(raw); err == nil {
+			return u.Hostname()
+		}
+	case engine.NodeTypeSendMessage:
+		channelID, _ := node.Config["channel_id"].(string)
+		return channelID
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package faultinject
+
+import (
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// FaultInjectionHandler exposes the tenant's fault injection rules
+type FaultInjectionHandler struct {
+	service *FaultInjectionService
+}
+
+func NewFaultInjectionHandler(service *FaultInjectionService) *FaultInjectionHandler {
+	return &FaultInjectionHandler{
+		service: service,
+	}
+}
+
+// Get returns the tenant's rules and whether they are being applied
+// GET /api/fault-injection
+func (h *FaultInjectionHandler) Get(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	config, err := h.service.Get(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"fault_injection": config,
+		"active":          h.service.Active(c.Context(), authContext.TenantID),
+	})
+}
+
+// Update replaces the tenant's rules
+// PUT /api/fault-injection
+func (h *FaultInjectionHandler) Update(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req Config
+	if err := c.BodyParser(&req); err != nil {
+		return engine.ErrInvalidFaultInjection().WithDetail("reason", err.Error())
+	}
+
+	config, err := h.service.Update(c.Context(), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(config)
+}
+
+// Clear removes the tenant's rules
+// DELETE /api/fault-injection
+func (h *FaultInjectionHandler) Clear(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := h.service.Clear(c.Context(), authContext.TenantID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
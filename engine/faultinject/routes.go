@@ -0,0 +1,28 @@
+package faultinject
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+type FaultInjectionRoutes struct {
+	handler        *FaultInjectionHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+func NewFaultInjectionRoutes(handler *FaultInjectionHandler, authMiddleware *auth.AuthMiddleware) *FaultInjectionRoutes {
+	return &FaultInjectionRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers fault injection routes on an authenticated router.
+// Rules make real traffic fail, so every route requires an admin.
+func (r *FaultInjectionRoutes) RegisterRoutes(router fiber.Router) {
+	faults := router.Group("/fault-injection", r.authMiddleware.RequireAdmin())
+
+	faults.Get("/", r.handler.Get)
+	faults.Put("/", r.handler.Update)
+	faults.Delete("/", r.handler.Clear)
+}
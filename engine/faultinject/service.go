@@ -0,0 +1,171 @@
+package faultinject
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/featureflag"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// settingKey is the tenant_config key holding the JSON rules
+const settingKey = "fault_injection"
+
+// cacheTTL bounds how long a change made on another instance takes to apply.
+// Rules are checked before every node, so they are not read each time.
+const cacheTTL = 10 * time.Second
+
+type cachedConfig struct {
+	config    *Config // nil = no rules
+	expiresAt time.Time
+}
+
+// FaultInjectionService stores tenant fault rules and picks the one to apply
+// to a node. Injection needs both the environment switch (enabled) and the
+// tenant's fault_injection flag, so rules can be kept configured while off.
+type FaultInjectionService struct {
+	tenantConfigRepo tenant.TenantConfigRepository
+	flags            featureflag.Checker
+	enabled          bool
+
+	mu    sync.RWMutex
+	cache map[kernel.TenantID]cachedConfig
+}
+
+func NewFaultInjectionService(
+	tenantConfigRepo tenant.TenantConfigRepository,
+	flags featureflag.Checker,
+	enabled bool,
+) *FaultInjectionService {
+	return &FaultInjectionService{
+		tenantConfigRepo: tenantConfigRepo,
+		flags:            flags,
+		enabled:          enabled,
+		cache:            make(map[kernel.TenantID]cachedConfig),
+	}
+}
+
+// Active reports whether rules are applied for the tenant right now
+func (s *FaultInjectionService) Active(ctx context.Context, tenantID kernel.TenantID) bool {
+	return s.enabled && s.flags != nil && s.flags.IsEnabled(ctx, tenantID, featureflag.FlagFaultInjection)
+}
+
+// Get returns the tenant's rules, nil when none are configured
+func (s *FaultInjectionService) Get(ctx context.Context, tenantID kernel.TenantID) (*Config, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[tenantID]
+	s.mu.RUnlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.config, nil
+	}
+
+	config, err := s.load(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[tenantID] = cachedConfig{config: config, expiresAt: time.Now().Add(cacheTTL)}
+	s.mu.Unlock()
+
+	return config, nil
+}
+
+// Update validates and replaces the tenant's rules
+func (s *FaultInjectionService) Update(ctx context.Context, tenantID kernel.TenantID, config Config) (*Config, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	config.TenantID = tenantID
+	config.UpdatedAt = time.Now()
+	if config.Rules == nil {
+		config.Rules = []Rule{}
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to encode fault injection rules", errx.TypeInternal)
+	}
+
+	if err := s.tenantConfigRepo.SaveSetting(ctx, tenantID, settingKey, string(data)); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[tenantID] = cachedConfig{config: &config, expiresAt: time.Now().Add(cacheTTL)}
+	s.mu.Unlock()
+
+	log.Printf("🧪 Fault injection rules updated for tenant %s (%d rules)", tenantID, len(config.Rules))
+	return &config, nil
+}
+
+// Clear removes the tenant's rules
+func (s *FaultInjectionService) Clear(ctx context.Context, tenantID kernel.TenantID) error {
+	if err := s.tenantConfigRepo.DeleteSetting(ctx, tenantID, settingKey); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.cache, tenantID)
+	s.mu.Unlock()
+
+	log.Printf("🧪 Fault injection rules cleared for tenant %s", tenantID)
+	return nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// match returns the first rule that targets the node and fires. Lookup
+// errors disable injection rather than failing the node.
+func (s *FaultInjectionService) match(ctx context.Context, tenantID kernel.TenantID, node engine.WorkflowNode) (Rule, bool) {
+	if !s.Active(ctx, tenantID) {
+		return Rule{}, false
+	}
+
+	config, err := s.Get(ctx, tenantID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load fault injection rules for tenant %s: %v", tenantID, err)
+		return Rule{}, false
+	}
+	if config == nil {
+		return Rule{}, false
+	}
+
+	for _, rule := range config.Rules {
+		if rule.matches(node) && rule.fires() {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+func (s *FaultInjectionService) load(ctx context.Context, tenantID kernel.TenantID) (*Config, error) {
+	settings, err := s.tenantConfigRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, ok := settings[settingKey]
+	if !ok || raw == "" {
+		return nil, nil
+	}
+
+	var config Config
+	if err := json.Unmarshal([]byte(raw), &config); err != nil {
+		return nil, errx.Wrap(err, "failed to decode fault injection rules", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+	config.TenantID = tenantID
+
+	return &config, nil
+}
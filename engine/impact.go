@@ -0,0 +1,243 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/google/cel-go/common/ast"
+)
+
+// ============================================================================
+// Change Impact
+// ============================================================================
+
+// Paused runs resume on whatever definition is stored when they do, so
+// publishing a new definition can strand them: the node they resume at may
+// be gone, or the nodes after it may read outputs the run never produced.
+
+// PausedRunKind is what a paused run is waiting for
+type PausedRunKind string
+
+const (
+	PausedOnContinuation PausedRunKind = "continuation" // A DELAY, or a survey's timeout, in the delay scheduler
+	PausedOnSurvey       PausedRunKind = "survey"       // A SURVEY waiting for the contact's answer
+)
+
+// PausedRun is a run of a workflow waiting to resume
+type PausedRun struct {
+	Kind           PausedRunKind  `json:"kind"`
+	ID             string         `json:"id"`
+	NodeID         string         `json:"node_id"`        // Node that paused the run
+	ResumeNodeID   string         `json:"resume_node_id"` // Empty = the run ends when it resumes
+	ConversationID string         `json:"conversation_id,omitempty"`
+	ResumesAt      *time.Time     `json:"resumes_at,omitempty"`
+	NodeContext    map[string]any `json:"-"`
+}
+
+// StrandedRun is a paused run a definition cannot resume
+type StrandedRun struct {
+	PausedRun
+	Reason       string   `json:"reason"`
+	MissingNodes []string `json:"missing_nodes,omitempty"` // Outputs read after resuming that the run does not have
+	RenamedTo    string   `json:"renamed_to,omitempty"`    // Added node that looks like the removed resume node
+}
+
+// NodeChanges compares the nodes of two definitions by ID
+type NodeChanges struct {
+	Added   []string     `json:"added"`
+	Removed []string     `json:"removed"`
+	Renamed []NodeRename `json:"renamed"` // Removed and added nodes with the same type and name or config
+	Changed []string     `json:"changed"` // Same ID, another type, config or transition
+}
+
+// NodeRename is a node whose ID changed
+type NodeRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// RenamedTo returns the new ID of a renamed node
+func (c NodeChanges) RenamedTo(id string) (string, bool) {
+	for _, rename := range c.Renamed {
+		if rename.From == id {
+			return rename.To, true
+		}
+	}
+	return "", false
+}
+
+// DiffNodes compares the current nodes of a workflow with proposed ones
+func DiffNodes(current, proposed Workflow) NodeChanges {
+	changes := NodeChanges{Added: []string{}, Removed: []string{}, Renamed: []NodeRename{}, Changed: []string{}}
+
+	var added, removed []WorkflowNode
+	for _, node := range proposed.Nodes {
+		old := current.GetNodeByID(node.ID)
+		if old == nil {
+			added = append(added, node)
+			continue
+		}
+		if !sameNode(*old, node) {
+			changes.Changed = append(changes.Changed, node.ID)
+		}
+	}
+	for _, node := range current.Nodes {
+		if proposed.GetNodeByID(node.ID) == nil {
+			removed = append(removed, node)
+		}
+	}
+
+	// A removed node is taken as renamed to the first added node of its
+	// type with its name or its config
+	matched := make(map[string]bool)
+	for _, old := range removed {
+		i := slices.IndexFunc(added, func(node WorkflowNode) bool {
+			return !matched[node.ID] && node.Type == old.Type &&
+				((old.Name != "" && node.Name == old.Name) || sameJSON(node.Config, old.Config))
+		})
+		if i < 0 {
+			changes.Removed = append(changes.Removed, old.ID)
+			continue
+		}
+		matched[added[i].ID] = true
+		changes.Renamed = append(changes.Renamed, NodeRename{From: old.ID, To: added[i].ID})
+	}
+	for _, node := range added {
+		if !matched[node.ID] {
+			changes.Added = append(changes.Added, node.ID)
+		}
+	}
+
+	return changes
+}
+
+// CheckPausedRun reports why a definition cannot resume a paused run; ok is
+// false when it can
+func CheckPausedRun(workflow Workflow, changes NodeChanges, run PausedRun) (stranded StrandedRun, ok bool) {
+	if run.ResumeNodeID == "" {
+		return StrandedRun{}, false
+	}
+
+	stranded = StrandedRun{PausedRun: run}
+	if workflow.GetNodeByID(run.ResumeNodeID) == nil {
+		stranded.Reason = fmt.Sprintf("resume node %s is no longer in the workflow", run.ResumeNodeID)
+		stranded.RenamedTo, _ = changes.RenamedTo(run.ResumeNodeID)
+		return stranded, true
+	}
+
+	// Outputs read after resuming must be in the saved context or come from
+	// a node the run can still reach
+	reachable := ReachableNodes(workflow, run.ResumeNodeID)
+	missing := make(map[string]bool)
+	for id := range reachable {
+		node := workflow.GetNodeByID(id)
+		for _, ref := range nodeOutputRefs(workflow, *node) {
+			if _, saved := run.NodeContext[ref]; !saved && !reachable[ref] {
+				missing[ref] = true
+			}
+		}
+	}
+	if len(missing) > 0 {
+		stranded.Reason = "nodes after the resume node read outputs the paused run does not have"
+		stranded.MissingNodes = slices.Sorted(maps.Keys(missing))
+		return stranded, true
+	}
+
+	return StrandedRun{}, false
+}
+
+// ReachableNodes returns the nodes a run can execute starting at from
+func ReachableNodes(workflow Workflow, from string) map[string]bool {
+	reachable := make(map[string]bool)
+	pending := []string{from}
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if reachable[id] {
+			continue
+		}
+		node := workflow.GetNodeByID(id)
+		if node == nil {
+			continue
+		}
+		reachable[id] = true
+		pending = append(pending, NodeTargets(*node)...)
+	}
+	return reachable
+}
+
+// NodeTargets returns the IDs of the nodes a node can hand the run to: its
+// transitions and the branches in its config
+func NodeTargets(node WorkflowNode) []string {
+	targets := []string{node.OnSuccess, node.OnFailure}
+
+	switch node.Type {
+	case NodeTypeSwitch:
+		if config, err := ExtractSwitchConfig(node.Config); err == nil {
+			for _, key := range slices.Sorted(maps.Keys(config.Cases)) {
+				if target, ok := config.Cases[key].(string); ok {
+					targets = append(targets, target)
+				}
+			}
+		}
+	case NodeTypeBusinessHours:
+		if config, err := ExtractBusinessHoursConfig(node.Config); err == nil {
+			targets = append(targets, config.OpenNode, config.ClosedNode, config.HolidayNode)
+		}
+	case NodeTypeExperiment:
+		if config, err := ExtractExperimentConfig(node.Config); err == nil {
+			for _, variant := range config.Variants {
+				targets = append(targets, variant.NextNode)
+			}
+		}
+	case NodeTypeLoop:
+		if config, err := ExtractLoopConfig(node.Config); err == nil {
+			targets = append(targets, config.BodyNode)
+		}
+	case NodeTypeSurvey:
+		if config, err := ExtractSurveyConfig(node.Config); err == nil {
+			targets = append(targets, config.OnTimeout)
+		}
+	}
+
+	return slices.DeleteFunc(targets, func(id string) bool { return id == "" })
+}
+
+// nodeOutputRefs returns the other nodes of the workflow whose outputs a
+// node's templates read, e.g. {{fetch.output.name}}
+func nodeOutputRefs(workflow Workflow, node WorkflowNode) []string {
+	var refs []string
+	walkTemplateExprs(node.Config, "", func(e ast.Expr, _ string) {
+		if e.Kind() != ast.IdentKind {
+			return
+		}
+		id := e.AsIdent()
+		if id != node.ID && workflow.GetNodeByID(id) != nil && !slices.Contains(refs, id) {
+			refs = append(refs, id)
+		}
+	})
+	return refs
+}
+
+func sameNode(a, b WorkflowNode) bool {
+	return a.Type == b.Type && a.OnSuccess == b.OnSuccess && a.OnFailure == b.OnFailure &&
+		sameJSON(a.Config, b.Config) && sameJSON(a.Timeout, b.Timeout) && sameJSON(a.MaxOutputBytes, b.MaxOutputBytes)
+}
+
+// sameJSON compares values as they are stored
+func sameJSON(a, b any) bool {
+	normalize := func(v any) any {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		var out any
+		_ = json.Unmarshal(data, &out)
+		return out
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
@@ -0,0 +1,43 @@
+package impact
+
+import (
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImpactHandler analyzes proposed workflow definitions before they are
+// published
+type ImpactHandler struct {
+	service *ImpactService
+}
+
+func NewImpactHandler(service *ImpactService) *ImpactHandler {
+	return &ImpactHandler{
+		service: service,
+	}
+}
+
+// Analyze reports the paused runs, schedules and sequences a proposed
+// definition affects. Nothing is saved.
+// POST /api/workflows/:id/impact
+func (h *ImpactHandler) Analyze(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var proposal Proposal
+	if err := c.BodyParser(&proposal); err != nil {
+		return engine.ErrInvalidWorkflowConfig().WithDetail("reason", err.Error())
+	}
+
+	report, err := h.service.Analyze(c.Context(), kernel.NewWorkflowID(c.Params("id")), authContext.TenantID, proposal)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(report)
+}
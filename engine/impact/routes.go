@@ -0,0 +1,24 @@
+package impact
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ImpactRoutes struct {
+	handler        *ImpactHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+func NewImpactRoutes(handler *ImpactHandler, authMiddleware *auth.AuthMiddleware) *ImpactRoutes {
+	return &ImpactRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers the impact analysis on an authenticated router.
+// The report lists paused conversations, so it requires an admin.
+func (r *ImpactRoutes) RegisterRoutes(router fiber.Router) {
+	router.Post("/workflows/:id/impact", r.authMiddleware.RequireAdmin(), r.handler.Analyze)
+}
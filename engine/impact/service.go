@@ -0,0 +1,277 @@
+package impact
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/sequence"
+	"github.com/Abraxas-365/relay/survey"
+)
+
+// listPageSize is how many paused runs are loaded per page while analyzing
+const listPageSize = 200
+
+// ImpactService reports what publishing a new definition of a workflow does
+// to its paused runs, its schedules and the sequences that run it.
+//
+// Workflows are not pinned to a version: a paused run resumes on whatever
+// definition is stored when it wakes up. The analysis checks every paused run
+// against the proposed definition instead.
+type ImpactService struct {
+	workflowRepo   engine.WorkflowRepository
+	executor       engine.WorkflowExecutor
+	delayScheduler engine.DelayScheduler
+	surveyRepo     survey.SurveyRepository
+	scheduleRepo   engine.WorkflowScheduleRepository
+	sequenceRepo   sequence.SequenceRepository
+}
+
+func NewImpactService(
+	workflowRepo engine.WorkflowRepository,
+	executor engine.WorkflowExecutor,
+	delayScheduler engine.DelayScheduler,
+	surveyRepo survey.SurveyRepository,
+	scheduleRepo engine.WorkflowScheduleRepository,
+	sequenceRepo sequence.SequenceRepository,
+) *ImpactService {
+	return &ImpactService{
+		workflowRepo:   workflowRepo,
+		executor:       executor,
+		delayScheduler: delayScheduler,
+		surveyRepo:     surveyRepo,
+		scheduleRepo:   scheduleRepo,
+		sequenceRepo:   sequenceRepo,
+	}
+}
+
+// ============================================================================
+// DTOs
+// ============================================================================
+
+// Proposal is the definition about to be published. Trigger, Variables and
+// IsActive keep the current values when omitted.
+type Proposal struct {
+	Trigger   *engine.WorkflowTrigger `json:"trigger,omitempty"`
+	Nodes     []engine.WorkflowNode   `json:"nodes"`
+	Variables engine.VariableSchema   `json:"variables,omitempty"`
+	IsActive  *bool                   `json:"is_active,omitempty"`
+}
+
+// Report is what publishing a proposal would affect. Safe is false when a
+// paused run would be stranded.
+type Report struct {
+	WorkflowID     kernel.WorkflowID    `json:"workflow_id"`
+	CurrentVersion int                  `json:"current_version"`
+	Safe           bool                 `json:"safe"`
+	Nodes          engine.NodeChanges   `json:"nodes"`
+	PausedRuns     int                  `json:"paused_runs"`
+	Stranded       []engine.StrandedRun `json:"stranded"`
+	Schedules      []ScheduleRef        `json:"schedules"`
+	Sequences      []SequenceRef        `json:"sequences"`
+	Warnings       []string             `json:"warnings"`
+}
+
+// ScheduleRef is a schedule that runs the workflow
+type ScheduleRef struct {
+	ID        string              `json:"id"`
+	Type      engine.ScheduleType `json:"type"`
+	IsActive  bool                `json:"is_active"`
+	NextRunAt *time.Time          `json:"next_run_at,omitempty"`
+}
+
+// SequenceRef is a sequence with steps that run the workflow
+type SequenceRef struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	IsActive bool     `json:"is_active"`
+	StepIDs  []string `json:"step_ids"`
+}
+
+// ============================================================================
+// Analysis
+// ============================================================================
+
+// Analyze compares a proposal with the stored definition of a workflow
+func (s *ImpactService) Analyze(ctx context.Context, workflowID kernel.WorkflowID, tenantID kernel.TenantID, proposal Proposal) (*Report, error) {
+	current, err := s.workflowRepo.FindByID(ctx, workflowID)
+	if err != nil || current.TenantID != tenantID {
+		return nil, engine.ErrWorkflowNotFound().WithDetail("workflow_id", workflowID.String())
+	}
+
+	proposed := *current
+	proposed.Nodes = proposal.Nodes
+	if proposal.Trigger != nil {
+		proposed.Trigger = *proposal.Trigger
+	}
+	if proposal.Variables != nil {
+		proposed.Variables = proposal.Variables
+	}
+	if proposal.IsActive != nil {
+		proposed.IsActive = *proposal.IsActive
+	}
+	if err := s.executor.ValidateWorkflow(ctx, proposed); err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		WorkflowID:     current.ID,
+		CurrentVersion: current.Version,
+		Nodes:          engine.DiffNodes(*current, proposed),
+		Stranded:       []engine.StrandedRun{},
+		Schedules:      []ScheduleRef{},
+		Sequences:      []SequenceRef{},
+		Warnings:       []string{},
+	}
+
+	runs, err := s.pausedRuns(ctx, *current)
+	if err != nil {
+		return nil, err
+	}
+	report.PausedRuns = len(runs)
+	for _, run := range runs {
+		if stranded, ok := engine.CheckPausedRun(proposed, report.Nodes, run); ok {
+			report.Stranded = append(report.Stranded, stranded)
+		}
+	}
+
+	if err := s.addSchedules(ctx, *current, report); err != nil {
+		return nil, err
+	}
+	if err := s.addSequences(ctx, *current, report); err != nil {
+		return nil, err
+	}
+
+	if !proposed.IsActive {
+		for _, schedule := range report.Schedules {
+			if schedule.IsActive {
+				report.Warnings = append(report.Warnings, "the proposal deactivates the workflow: its active schedules will skip their runs")
+				break
+			}
+		}
+		for _, seq := range report.Sequences {
+			if seq.IsActive {
+				report.Warnings = append(report.Warnings, "the proposal deactivates the workflow: active sequences run it anyway")
+				break
+			}
+		}
+	}
+	if len(report.Stranded) > 0 {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("%d paused run(s) would resume on nodes or outputs the proposal no longer has", len(report.Stranded)))
+	}
+	report.Safe = len(report.Stranded) == 0
+
+	log.Printf("🔍 Impact of workflow %s: %d paused run(s), %d stranded, %d schedule(s), %d sequence(s)",
+		current.Name, report.PausedRuns, len(report.Stranded), len(report.Schedules), len(report.Sequences))
+	return report, nil
+}
+
+// pausedRuns loads the workflow's pending continuations and the surveys
+// waiting for an answer
+func (s *ImpactService) pausedRuns(ctx context.Context, workflow engine.Workflow) ([]engine.PausedRun, error) {
+	var runs []engine.PausedRun
+
+	for page := 1; ; page++ {
+		continuations, err := s.delayScheduler.ListPending(ctx, engine.ContinuationListRequest{
+			PaginationOptions: storex.PaginationOptions{Page: page, PageSize: listPageSize},
+			TenantID:          workflow.TenantID,
+			WorkflowID:        &workflow.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, continuation := range continuations.Data {
+			// Inactivity follow-ups run from the start, not from a node
+			if continuation.Kind != engine.ContinuationDelay {
+				continue
+			}
+			resumesAt := continuation.ScheduledFor
+			runs = append(runs, engine.PausedRun{
+				Kind:           engine.PausedOnContinuation,
+				ID:             continuation.ID,
+				NodeID:         continuation.NodeID,
+				ResumeNodeID:   continuation.NextNodeID,
+				ConversationID: continuation.ConversationID,
+				ResumesAt:      &resumesAt,
+				NodeContext:    continuation.NodeContext,
+			})
+		}
+		if len(continuations.Data) < listPageSize {
+			break
+		}
+	}
+
+	// A survey past its deadline resumes through its timeout continuation
+	now := time.Now()
+	for page := 1; ; page++ {
+		surveys, err := s.surveyRepo.List(ctx, survey.ListSurveysRequest{
+			PaginationOptions: storex.PaginationOptions{Page: page, PageSize: listPageSize},
+			TenantID:          workflow.TenantID,
+			WorkflowID:        workflow.ID.String(),
+			Status:            survey.StatusPending,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, pending := range surveys.Data {
+			if !now.Before(pending.ExpiresAt) {
+				continue
+			}
+			expiresAt := pending.ExpiresAt
+			runs = append(runs, engine.PausedRun{
+				Kind:           engine.PausedOnSurvey,
+				ID:             pending.ID,
+				NodeID:         pending.NodeID,
+				ResumeNodeID:   pending.NextNodeID,
+				ConversationID: pending.ConversationID,
+				ResumesAt:      &expiresAt,
+				NodeContext:    pending.NodeContext,
+			})
+		}
+		if len(surveys.Data) < listPageSize {
+			break
+		}
+	}
+
+	return runs, nil
+}
+
+func (s *ImpactService) addSchedules(ctx context.Context, workflow engine.Workflow, report *Report) error {
+	schedules, err := s.scheduleRepo.FindByWorkflow(ctx, workflow.ID)
+	if err != nil {
+		return err
+	}
+	for _, schedule := range schedules {
+		report.Schedules = append(report.Schedules, ScheduleRef{
+			ID:        schedule.ID,
+			Type:      schedule.ScheduleType,
+			IsActive:  schedule.IsActive,
+			NextRunAt: schedule.NextRunAt,
+		})
+	}
+	return nil
+}
+
+func (s *ImpactService) addSequences(ctx context.Context, workflow engine.Workflow, report *Report) error {
+	sequences, err := s.sequenceRepo.List(ctx, workflow.TenantID)
+	if err != nil {
+		return err
+	}
+	for _, seq := range sequences {
+		ref := SequenceRef{ID: seq.ID, Name: seq.Name, IsActive: seq.IsActive}
+		for _, step := range seq.Steps {
+			if step.Type == sequence.StepTriggerWorkflow && step.WorkflowID != nil && *step.WorkflowID == workflow.ID {
+				ref.StepIDs = append(ref.StepIDs, step.ID)
+			}
+		}
+		if len(ref.StepIDs) > 0 {
+			report.Sequences = append(report.Sequences, ref)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Inactivity Triggers
+// ============================================================================
+
+// A workflow with an INACTIVITY trigger runs when a contact has sent nothing
+// on a conversation for the configured time: every inbound message re-arms a
+// continuation in the delay scheduler, so replying first pushes it back.
+//
+// A conversation's session is its messaging window: it expires
+// DefaultMessagingWindow after the contact's last message, which publishes
+// EventSessionExpired and runs the SESSION_EXPIRED workflows.
+
+const (
+	// MinInactivityIdle bounds how soon a follow-up can run
+	MinInactivityIdle = time.Minute
+
+	// DefaultMessagingWindow is how long after the contact's last message a
+	// channel accepts free-form messages (WhatsApp's customer service window)
+	DefaultMessagingWindow = 24 * time.Hour
+)
+
+// ContinuationKind tells the delay scheduler's handler what a continuation
+// resumes
+type ContinuationKind string
+
+const (
+	ContinuationDelay      ContinuationKind = ""           // A paused run, resumed at NextNodeID
+	ContinuationInactivity ContinuationKind = "inactivity" // An INACTIVITY workflow, run from the start
+	ContinuationSession    ContinuationKind = "session"    // The expiry of a conversation's session
+)
+
+// InactivityConfig is the config of an INACTIVITY trigger
+type InactivityConfig struct {
+	IdleSeconds int `json:"idle_seconds"`           // Without a message from the contact
+	WindowHours int `json:"window_hours,omitempty"` // Messaging window; 0 = DefaultMessagingWindow
+}
+
+// Idle is how long the conversation must be quiet
+func (c *InactivityConfig) Idle() time.Duration {
+	return time.Duration(c.IdleSeconds) * time.Second
+}
+
+// Window is how long after the contact's last message the follow-up can
+// still be sent
+func (c *InactivityConfig) Window() time.Duration {
+	if c.WindowHours <= 0 {
+		return DefaultMessagingWindow
+	}
+	return time.Duration(c.WindowHours) * time.Hour
+}
+
+// Validate checks the follow-up runs inside the messaging window
+func (c *InactivityConfig) Validate() error {
+	if c.Idle() < MinInactivityIdle {
+		return ErrInvalidWorkflowConfig().
+			WithDetail("field", "trigger.config.idle_seconds").
+			WithDetail("min", int(MinInactivityIdle.Seconds()))
+	}
+	if c.WindowHours < 0 {
+		return ErrInvalidWorkflowConfig().WithDetail("field", "trigger.config.window_hours")
+	}
+	if c.Idle() >= c.Window() {
+		return ErrInvalidWorkflowConfig().
+			WithDetail("field", "trigger.config.idle_seconds").
+			WithDetail("reason", "the follow-up would run outside the messaging window")
+	}
+	return nil
+}
+
+// ExtractInactivityConfig extracts and validates an INACTIVITY trigger config
+func ExtractInactivityConfig(config map[string]any) (*InactivityConfig, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var inactivityConfig InactivityConfig
+	if err := json.Unmarshal(data, &inactivityConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal inactivity config: %w", err)
+	}
+
+	if err := inactivityConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &inactivityConfig, nil
+}
+
+// InactivityContinuationID identifies the follow-up of one workflow on one
+// conversation, so re-arming it replaces the pending one
+func InactivityContinuationID(tenantID kernel.TenantID, channelID kernel.ChannelID, senderID string, workflowID kernel.WorkflowID) string {
+	return fmt.Sprintf("inactivity:%s:%s:%s:%s", tenantID, channelID, senderID, workflowID)
+}
+
+// SessionContinuationID identifies the expiry of one conversation's session,
+// so every message from the contact replaces the pending one
+func SessionContinuationID(tenantID kernel.TenantID, channelID kernel.ChannelID, senderID string) string {
+	return fmt.Sprintf("session:%s:%s:%s", tenantID, channelID, senderID)
+}
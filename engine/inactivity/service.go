@@ -0,0 +1,267 @@
+package inactivity
+
+import (
+	"context"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/engine/triggerhandler"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// InactivityService runs INACTIVITY workflows on conversations where the
+// contact went quiet, and expires their sessions. Every inbound message arms
+// one continuation per workflow, and one for the session, in the delay
+// scheduler; the next message replaces them, so they only run when the
+// contact did not reply first.
+type InactivityService struct {
+	workflowRepo   engine.WorkflowRepository
+	delayScheduler engine.DelayScheduler
+	triggerHandler *triggerhandler.TriggerHandler
+	events         eventx.EventBus // nil = session.expired is not published
+}
+
+var _ channels.InboundListener = (*InactivityService)(nil)
+
+func NewInactivityService(
+	workflowRepo engine.WorkflowRepository,
+	delayScheduler engine.DelayScheduler,
+	triggerHandler *triggerhandler.TriggerHandler,
+) *InactivityService {
+	return &InactivityService{
+		workflowRepo:   workflowRepo,
+		delayScheduler: delayScheduler,
+		triggerHandler: triggerHandler,
+	}
+}
+
+// SetEventBus publishes session.expired when a conversation's session expires
+func (s *InactivityService) SetEventBus(bus eventx.EventBus) {
+	s.events = bus
+}
+
+// ============================================================================
+// Arming
+// ============================================================================
+
+// OnInboundMessage implements channels.InboundListener: it (re)arms the
+// conversation's session expiry and the follow-up of every INACTIVITY
+// workflow of the channel's tenant and environment
+func (s *InactivityService) OnInboundMessage(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage) {
+	if msg.SenderID == "" {
+		return
+	}
+
+	s.armSessionExpiry(ctx, channel, msg)
+
+	trigger := engine.WorkflowTrigger{Type: engine.TriggerTypeInactivity}
+	workflows, err := s.workflowRepo.FindActiveByTrigger(ctx, trigger, channel.TenantID)
+	if err != nil {
+		log.Printf("⚠️  Failed to find inactivity workflows for tenant %s: %v", channel.TenantID, err)
+		return
+	}
+
+	for _, wf := range workflows {
+		if !appliesTo(wf, channel.ID.String(), channel.Environment) {
+			continue
+		}
+		config, err := engine.ExtractInactivityConfig(wf.Trigger.Config)
+		if err != nil {
+			log.Printf("⚠️  Skipping inactivity workflow %s: %v", wf.Name, err)
+			continue
+		}
+		s.arm(ctx, wf, config, channel, msg)
+	}
+}
+
+func (s *InactivityService) arm(ctx context.Context, wf *engine.Workflow, config *engine.InactivityConfig, channel *channels.Channel, msg *channels.IncomingMessage) {
+	triggerData := channels.TriggerPayload(channel, msg)
+	triggerData["inactivity"] = map[string]any{
+		"idle_seconds":    config.IdleSeconds,
+		"last_message_at": time.Now(),
+	}
+
+	// The same ID replaces the pending follow-up, pushing it back
+	continuation := &engine.WorkflowContinuation{
+		ID:             engine.InactivityContinuationID(channel.TenantID, channel.ID, msg.SenderID, wf.ID),
+		WorkflowID:     wf.ID.String(),
+		TenantID:       channel.TenantID.String(),
+		NodeContext:    map[string]any{"trigger": triggerData},
+		ConversationID: msg.SenderID,
+		MessageID:      msg.MessageID.String(),
+		ChannelID:      channel.ID.String(),
+		Kind:           engine.ContinuationInactivity,
+	}
+	if err := s.delayScheduler.Schedule(context.WithoutCancel(ctx), continuation, config.Idle()); err != nil {
+		log.Printf("⚠️  Failed to arm follow-up %s for %s: %v", wf.Name, msg.SenderID, err)
+	}
+}
+
+// armSessionExpiry (re)arms the end of the conversation's messaging window
+func (s *InactivityService) armSessionExpiry(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage) {
+	continuation := &engine.WorkflowContinuation{
+		ID:             engine.SessionContinuationID(channel.TenantID, channel.ID, msg.SenderID),
+		TenantID:       channel.TenantID.String(),
+		NodeContext:    map[string]any{"trigger": channels.TriggerPayload(channel, msg)},
+		ConversationID: msg.SenderID,
+		MessageID:      msg.MessageID.String(),
+		ChannelID:      channel.ID.String(),
+		Kind:           engine.ContinuationSession,
+	}
+	if err := s.delayScheduler.Schedule(context.WithoutCancel(ctx), continuation, engine.DefaultMessagingWindow); err != nil {
+		log.Printf("⚠️  Failed to arm session expiry for %s: %v", msg.SenderID, err)
+	}
+}
+
+// appliesTo keeps the workflows of the channel's environment and, when the
+// trigger lists channel_ids, of those channels
+func appliesTo(wf *engine.Workflow, channelID string, environment kernel.Environment) bool {
+	if wf.Environment.OrDefault() != environment.OrDefault() {
+		return false
+	}
+
+	var channelIDs []string
+	switch ids := wf.Trigger.Filters["channel_ids"].(type) {
+	case []string:
+		channelIDs = ids
+	case []any:
+		for _, id := range ids {
+			if s, ok := id.(string); ok {
+				channelIDs = append(channelIDs, s)
+			}
+		}
+	}
+	return len(channelIDs) == 0 || slices.Contains(channelIDs, channelID)
+}
+
+// ============================================================================
+// Follow-up
+// ============================================================================
+
+// HandleContinuation handles the continuations the service armed, of kind
+// engine.ContinuationInactivity or engine.ContinuationSession
+func (s *InactivityService) HandleContinuation(ctx context.Context, continuation *engine.WorkflowContinuation) error {
+	if continuation.Kind == engine.ContinuationSession {
+		return s.expireSession(ctx, continuation)
+	}
+	return s.followUp(ctx, continuation)
+}
+
+// followUp runs an INACTIVITY workflow. It is skipped when the workflow
+// changed since it was armed or the messaging window has closed.
+func (s *InactivityService) followUp(ctx context.Context, continuation *engine.WorkflowContinuation) error {
+	tenantID := kernel.TenantID(continuation.TenantID)
+
+	wf, err := s.workflowRepo.FindByID(ctx, kernel.WorkflowID(continuation.WorkflowID))
+	if err != nil || wf.TenantID != tenantID {
+		return engine.ErrWorkflowNotFound().WithDetail("workflow_id", continuation.WorkflowID)
+	}
+	if !wf.IsActive || wf.Trigger.Type != engine.TriggerTypeInactivity {
+		log.Printf("ℹ️  Skipping follow-up %s: workflow is no longer an active inactivity workflow", wf.Name)
+		return nil
+	}
+
+	config, err := engine.ExtractInactivityConfig(wf.Trigger.Config)
+	if err != nil {
+		return err
+	}
+
+	// CreatedAt is the contact's last message: the follow-up is re-armed on
+	// every one
+	if rearmed(continuation) {
+		log.Printf("ℹ️  Skipping follow-up %s for %s: the contact wrote again", wf.Name, continuation.ConversationID)
+		return nil
+	}
+	if time.Since(continuation.CreatedAt) > config.Window() {
+		log.Printf("ℹ️  Skipping follow-up %s for %s: the messaging window has closed", wf.Name, continuation.ConversationID)
+		return nil
+	}
+
+	log.Printf("💤 Conversation %s idle for %s, running follow-up %s", continuation.ConversationID, config.Idle(), wf.Name)
+	s.triggerHandler.HandleConversationTrigger(ctx, wf, engine.TriggerTypeInactivity, tenantID, lastTrigger(continuation))
+	return nil
+}
+
+// ============================================================================
+// Session Expiry
+// ============================================================================
+
+// expireSession publishes session.expired and runs the SESSION_EXPIRED
+// workflows of the conversation's channel and environment
+func (s *InactivityService) expireSession(ctx context.Context, continuation *engine.WorkflowContinuation) error {
+	if rearmed(continuation) {
+		return nil
+	}
+
+	tenantID := kernel.TenantID(continuation.TenantID)
+	trigger := lastTrigger(continuation)
+	now := time.Now()
+
+	log.Printf("⌛ Session of conversation %s on channel %s expired", continuation.ConversationID, continuation.ChannelID)
+	s.publishExpired(ctx, engine.SessionExpiredEvent{
+		TenantID:       tenantID,
+		ChannelID:      continuation.ChannelID,
+		ConversationID: continuation.ConversationID,
+		LastMessageID:  continuation.MessageID,
+		LastMessageAt:  continuation.CreatedAt,
+		Context:        trigger,
+		At:             now,
+	})
+
+	workflows, err := s.workflowRepo.FindActiveByTrigger(ctx, engine.WorkflowTrigger{Type: engine.TriggerTypeSessionExpired}, tenantID)
+	if err != nil {
+		return err
+	}
+
+	environment, _ := trigger["environment"].(string)
+	trigger = maps.Clone(trigger)
+	trigger["session"] = map[string]any{
+		"last_message_at": continuation.CreatedAt,
+		"expired_at":      now,
+	}
+	for _, wf := range workflows {
+		if appliesTo(wf, continuation.ChannelID, kernel.Environment(environment)) {
+			s.triggerHandler.HandleConversationTrigger(ctx, wf, engine.TriggerTypeSessionExpired, tenantID, trigger)
+		}
+	}
+	return nil
+}
+
+// publishExpired never fails the expiry: consumers are best effort
+func (s *InactivityService) publishExpired(ctx context.Context, event engine.SessionExpiredEvent) {
+	if s.events == nil {
+		return
+	}
+
+	opts := eventx.DefaultEventOptions()
+	opts.Source = "engine"
+	opts.Metadata = map[string]any{
+		"tenant_id":       event.TenantID.String(),
+		"channel_id":      event.ChannelID,
+		"conversation_id": event.ConversationID,
+	}
+	if err := s.events.Publish(ctx, eventx.NewEvent(engine.EventSessionExpired, event, opts)); err != nil {
+		log.Printf("⚠️  Failed to publish %s event for %s: %v", engine.EventSessionExpired, event.ConversationID, err)
+	}
+}
+
+// lastTrigger is the trigger data of the contact's last message
+func lastTrigger(continuation *engine.WorkflowContinuation) map[string]any {
+	trigger, _ := continuation.NodeContext["trigger"].(map[string]any)
+	if trigger == nil {
+		trigger = make(map[string]any)
+	}
+	return trigger
+}
+
+// rearmed reports whether the contact wrote again after the worker claimed
+// the continuation. The scheduler's scores have second precision, so a
+// continuation can be claimed up to a second before ScheduledFor.
+func rearmed(continuation *engine.WorkflowContinuation) bool {
+	return time.Until(continuation.ScheduledFor) > time.Second
+}
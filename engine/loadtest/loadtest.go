@@ -0,0 +1,343 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/engine/node"
+	"github.com/Abraxas-365/relay/engine/workflowexec"
+	"github.com/Abraxas-365/relay/engine/workflowtest"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Config
+// ============================================================================
+
+// Config describes the synthetic traffic
+type Config struct {
+	Rate           int           // Messages per second
+	Duration       time.Duration // How long to send for
+	Tenants        int           // Tenants the traffic is spread over, one workflow each
+	Senders        int           // Distinct senders per tenant
+	Nodes          int           // Nodes per workflow, the last one sends the reply
+	MaxInFlight    int           // Messages executing at once; beyond it they are dropped
+	ChannelLatency time.Duration // Simulated provider latency per send
+}
+
+// DefaultConfig is a small run that finishes in seconds
+var DefaultConfig = Config{
+	Rate:           100,
+	Duration:       10 * time.Second,
+	Tenants:        10,
+	Senders:        100,
+	Nodes:          5,
+	MaxInFlight:    1000,
+	ChannelLatency: 50 * time.Millisecond,
+}
+
+func (c Config) validate() error {
+	if c.Rate <= 0 || c.Duration <= 0 || c.Tenants <= 0 || c.Senders <= 0 || c.Nodes <= 0 || c.MaxInFlight <= 0 {
+		return fmt.Errorf("rate, duration, tenants, senders, nodes and max in-flight must be positive")
+	}
+	return nil
+}
+
+// ============================================================================
+// Report
+// ============================================================================
+
+// Latencies are percentiles of a set of samples
+type Latencies struct {
+	Count int           `json:"count"`
+	P50   time.Duration `json:"p50"`
+	P95   time.Duration `json:"p95"`
+	P99   time.Duration `json:"p99"`
+	Max   time.Duration `json:"max"`
+}
+
+// Report is the outcome of a run. EndToEnd is measured from when a message
+// was due, so time spent waiting behind a saturated executor is included.
+type Report struct {
+	Config    Config                        `json:"config"`
+	Sent      int64                         `json:"sent"`
+	Succeeded int64                         `json:"succeeded"`
+	Failed    int64                         `json:"failed"`
+	Dropped   int64                         `json:"dropped"`
+	Elapsed   time.Duration                 `json:"elapsed"`
+	EndToEnd  Latencies                     `json:"end_to_end"`
+	Nodes     map[engine.NodeType]Latencies `json:"nodes"`
+}
+
+// Throughput is completed messages per second
+func (r *Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Succeeded+r.Failed) / r.Elapsed.Seconds()
+}
+
+// ============================================================================
+// Run
+// ============================================================================
+
+// Run replays synthetic traffic against an in-process executor with a fake
+// channel manager and reports latencies. It returns when every sent message
+// has finished or ctx is done.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	recorder := newRecorder()
+	executor := newExecutor(recorder, cfg.ChannelLatency)
+
+	workflows := make([]engine.Workflow, cfg.Tenants)
+	for i := range workflows {
+		workflows[i] = syntheticWorkflow(kernel.TenantID(fmt.Sprintf("loadtest-tenant-%d", i)), cfg.Nodes)
+		if err := executor.ValidateWorkflow(ctx, workflows[i]); err != nil {
+			return nil, fmt.Errorf("synthetic workflow is invalid: %w", err)
+		}
+	}
+
+	report := &Report{Config: cfg, Nodes: make(map[engine.NodeType]Latencies)}
+	var inFlight atomic.Int64
+	var wg sync.WaitGroup
+
+	interval := time.Second / time.Duration(cfg.Rate)
+	total := int(cfg.Duration / interval)
+	startTime := time.Now()
+
+	for i := range total {
+		due := startTime.Add(time.Duration(i) * interval)
+		if wait := time.Until(due); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		if inFlight.Load() >= int64(cfg.MaxInFlight) {
+			report.Dropped++
+			continue
+		}
+
+		wf := workflows[i%len(workflows)]
+		input := syntheticInput(wf.TenantID, rand.IntN(cfg.Senders))
+
+		report.Sent++
+		inFlight.Add(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer inFlight.Add(-1)
+
+			result, err := executor.Execute(ctx, wf, input)
+			recorder.endToEnd(time.Since(due))
+			if err != nil || !result.Success {
+				atomic.AddInt64(&report.Failed, 1)
+				return
+			}
+			atomic.AddInt64(&report.Succeeded, 1)
+		}()
+	}
+
+	wg.Wait()
+	report.Elapsed = time.Since(startTime)
+	report.EndToEnd = percentiles(recorder.e2e)
+	for nodeType, samples := range recorder.nodes {
+		report.Nodes[nodeType] = percentiles(samples)
+	}
+
+	return report, nil
+}
+
+// ============================================================================
+// Synthetic Traffic
+// ============================================================================
+
+// syntheticWorkflow chains condition and transform nodes, the kind that run
+// on every message, and ends by replying
+func syntheticWorkflow(tenantID kernel.TenantID, nodes int) engine.Workflow {
+	wf := engine.Workflow{
+		ID:       kernel.NewWorkflowID(uuid.NewString()),
+		TenantID: tenantID,
+		Name:     fmt.Sprintf("loadtest-%d-nodes", nodes),
+		IsActive: true,
+		Trigger:  engine.WorkflowTrigger{Type: engine.TriggerTypeChannelWebhook},
+	}
+
+	for i := range nodes - 1 {
+		id := fmt.Sprintf("step_%d", i)
+		if i%2 == 0 {
+			wf.Nodes = append(wf.Nodes, engine.WorkflowNode{
+				ID:   id,
+				Name: id,
+				Type: engine.NodeTypeCondition,
+				Config: map[string]any{
+					"logic": "or",
+					"conditions": []any{
+						map[string]any{"field": "trigger.text", "operator": "contains", "value": "order"},
+						map[string]any{"field": "trigger.text", "operator": "regex", "value": `^\d+$`},
+					},
+				},
+			})
+		} else {
+			wf.Nodes = append(wf.Nodes, engine.WorkflowNode{
+				ID:   id,
+				Name: id,
+				Type: engine.NodeTypeTransform,
+				Config: map[string]any{
+					"mappings": map[string]any{
+						"greeting": "{{trigger.sender_id}}",
+						"length":   "{{size(trigger.text)}}",
+					},
+				},
+			})
+		}
+	}
+
+	wf.Nodes = append(wf.Nodes, engine.WorkflowNode{
+		ID:   "reply",
+		Name: "reply",
+		Type: engine.NodeTypeSendMessage,
+		Config: map[string]any{
+			"channel_id":   "{{trigger.channel_id}}",
+			"recipient_id": "{{trigger.sender_id}}",
+			"text":         "Thanks, we got: {{trigger.text}}",
+		},
+	})
+
+	for i := range len(wf.Nodes) - 1 {
+		wf.Nodes[i].OnSuccess = wf.Nodes[i+1].ID
+	}
+
+	return wf
+}
+
+var syntheticTexts = []string{"hi", "where is my order", "12345", "I want to talk to a human", "thanks!"}
+
+func syntheticInput(tenantID kernel.TenantID, sender int) engine.WorkflowInput {
+	senderID := fmt.Sprintf("sender-%d", sender)
+	return engine.WorkflowInput{
+		TenantID: tenantID,
+		TriggerData: map[string]any{
+			"text":            syntheticTexts[rand.IntN(len(syntheticTexts))],
+			"message_id":      uuid.NewString(),
+			"channel_id":      "loadtest-channel",
+			"sender_id":       senderID,
+			"message_type":    "text",
+			"conversation_id": senderID,
+		},
+		Metadata: map[string]any{
+			"trigger_type": engine.TriggerTypeChannelWebhook,
+		},
+	}
+}
+
+// ============================================================================
+// Executor
+// ============================================================================
+
+func newExecutor(recorder *recorder, channelLatency time.Duration) engine.WorkflowExecutor {
+	evaluator := engine.NewCelEvaluator(nil, nil)
+	return workflowexec.NewDefaultWorkflowExecutor(evaluator, newNodeExecutors(recorder, evaluator, channelLatency)...)
+}
+
+// newNodeExecutors builds the executors of the node types the synthetic
+// workflow uses
+func newNodeExecutors(recorder *recorder, evaluator engine.ExpressionEvaluator, channelLatency time.Duration) []engine.NodeExecutor {
+	channelManager := &slowChannelManager{FakeChannelManager: workflowtest.NewFakeChannelManager(), latency: channelLatency}
+
+	return []engine.NodeExecutor{
+		&timedExecutor{NodeExecutor: node.NewConditionExecutor(), nodeType: engine.NodeTypeCondition, recorder: recorder},
+		&timedExecutor{NodeExecutor: node.NewTransformExecutor(evaluator), nodeType: engine.NodeTypeTransform, recorder: recorder},
+		&timedExecutor{NodeExecutor: node.NewSendMessageExecutor(channelManager, evaluator, nil, nil), nodeType: engine.NodeTypeSendMessage, recorder: recorder},
+	}
+}
+
+// timedExecutor records how long each node takes
+type timedExecutor struct {
+	engine.NodeExecutor
+	nodeType engine.NodeType
+	recorder *recorder
+}
+
+func (e *timedExecutor) Execute(ctx context.Context, n engine.WorkflowNode, input map[string]any) (*engine.NodeResult, error) {
+	startTime := time.Now()
+	result, err := e.NodeExecutor.Execute(ctx, n, input)
+	e.recorder.node(e.nodeType, time.Since(startTime))
+	return result, err
+}
+
+// slowChannelManager stands in for a provider that takes latency to answer
+type slowChannelManager struct {
+	*workflowtest.FakeChannelManager
+	latency time.Duration
+}
+
+func (m *slowChannelManager) SendMessage(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, msg channels.OutgoingMessage) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(m.latency):
+	}
+	// Replies are not kept, a long run would hold them all in memory
+	return nil
+}
+
+// ============================================================================
+// Latency Recording
+// ============================================================================
+
+type recorder struct {
+	mu    sync.Mutex
+	e2e   []time.Duration
+	nodes map[engine.NodeType][]time.Duration
+}
+
+func newRecorder() *recorder {
+	return &recorder{nodes: make(map[engine.NodeType][]time.Duration)}
+}
+
+func (r *recorder) endToEnd(d time.Duration) {
+	r.mu.Lock()
+	r.e2e = append(r.e2e, d)
+	r.mu.Unlock()
+}
+
+func (r *recorder) node(nodeType engine.NodeType, d time.Duration) {
+	r.mu.Lock()
+	r.nodes[nodeType] = append(r.nodes[nodeType], d)
+	r.mu.Unlock()
+}
+
+func percentiles(samples []time.Duration) Latencies {
+	if len(samples) == 0 {
+		return Latencies{}
+	}
+
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+
+	at := func(p float64) time.Duration {
+		return sorted[int(p*float64(len(sorted)-1))]
+	}
+
+	return Latencies{
+		Count: len(sorted),
+		P50:   at(0.50),
+		P95:   at(0.95),
+		P99:   at(0.99),
+		Max:   sorted[len(sorted)-1],
+	}
+}
@@ -0,0 +1,121 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// The executor logs every node; benchmarks would measure the logging
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// BenchmarkExecute measures one message through a workflow of increasing
+// complexity, with no simulated provider latency
+func BenchmarkExecute(b *testing.B) {
+	for _, nodes := range []int{1, 5, 10, 25} {
+		b.Run(fmt.Sprintf("nodes=%d", nodes), func(b *testing.B) {
+			executor := newExecutor(newRecorder(), 0)
+			wf := syntheticWorkflow(kernel.TenantID("bench-tenant"), nodes)
+			input := syntheticInput(wf.TenantID, 0)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				result, err := executor.Execute(ctx, wf, input)
+				if err != nil || !result.Success {
+					b.Fatalf("Execute() = %+v, %v", result, err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkExecuteParallel measures throughput with messages of several
+// tenants executing at once on one executor
+func BenchmarkExecuteParallel(b *testing.B) {
+	executor := newExecutor(newRecorder(), 0)
+	workflows := make([]engine.Workflow, 10)
+	for i := range workflows {
+		workflows[i] = syntheticWorkflow(kernel.TenantID(fmt.Sprintf("bench-tenant-%d", i)), DefaultConfig.Nodes)
+	}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			wf := workflows[i%len(workflows)]
+			result, err := executor.Execute(ctx, wf, syntheticInput(wf.TenantID, i))
+			if err != nil || !result.Success {
+				b.Errorf("Execute() = %+v, %v", result, err)
+				return
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkNode measures each node type of the synthetic workflow on its own,
+// with its config already evaluated as the executor would pass it
+func BenchmarkNode(b *testing.B) {
+	evaluator := engine.NewCelEvaluator(nil, nil)
+	executors := newNodeExecutors(newRecorder(), evaluator, 0)
+
+	wf := syntheticWorkflow(kernel.TenantID("bench-tenant"), 3)
+	input := syntheticInput(wf.TenantID, 0)
+	nodeContext := map[string]any{"trigger": input.TriggerData, "tenant_id": wf.TenantID.String()}
+	ctx := context.Background()
+
+	for _, n := range wf.Nodes {
+		b.Run(string(n.Type), func(b *testing.B) {
+			evaluated, err := evaluator.Evaluate(ctx, n.Config, nodeContext)
+			if err != nil {
+				b.Fatalf("Evaluate() error = %v", err)
+			}
+			n.Config = evaluated.(map[string]any)
+
+			var executor engine.NodeExecutor
+			for _, candidate := range executors {
+				if candidate.SupportsType(n.Type) {
+					executor = candidate
+				}
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				result, err := executor.Execute(ctx, n, nodeContext)
+				if err != nil || !result.Success {
+					b.Fatalf("Execute() = %+v, %v", result, err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkPercentiles measures building a report from a long run's samples
+func BenchmarkPercentiles(b *testing.B) {
+	samples := make([]time.Duration, 100_000)
+	for i := range samples {
+		samples[i] = time.Duration(rand.Int64N(int64(time.Second)))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		percentiles(samples)
+	}
+}
@@ -0,0 +1,28 @@
+package engine
+
+import "context"
+
+// ============================================================================
+// Executor Middleware
+// ============================================================================
+
+// NodeHooks adapts plain functions to ExecutorMiddleware; either may be nil
+type NodeHooks struct {
+	Before func(ctx context.Context, node WorkflowNode, nodeContext map[string]any) (context.Context, error)
+	After  func(ctx context.Context, node WorkflowNode, nodeContext map[string]any, result *NodeResult)
+}
+
+var _ ExecutorMiddleware = NodeHooks{}
+
+func (h NodeHooks) BeforeNode(ctx context.Context, node WorkflowNode, nodeContext map[string]any) (context.Context, error) {
+	if h.Before == nil {
+		return ctx, nil
+	}
+	return h.Before(ctx, node, nodeContext)
+}
+
+func (h NodeHooks) AfterNode(ctx context.Context, node WorkflowNode, nodeContext map[string]any, result *NodeResult) {
+	if h.After != nil {
+		h.After(ctx, node, nodeContext, result)
+	}
+}
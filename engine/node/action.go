@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strings"
 	"time"
 
 	"github.com/Abraxas-365/craftable/errx"
@@ -13,14 +12,16 @@ import (
 
 // ActionExecutor ejecuta acciones dentro de workflows
 type ActionExecutor struct {
-	// Puedes agregar dependencias aquí si necesitas
+	tagger engine.ConversationTagger // nil = la acción tag falla
 }
 
 var _ engine.NodeExecutor = (*ActionExecutor)(nil)
 
 // NewActionExecutor crea una nueva instancia del ejecutor de acciones
-func NewActionExecutor() *ActionExecutor {
-	return &ActionExecutor{}
+func NewActionExecutor(tagger engine.ConversationTagger) *ActionExecutor {
+	return &ActionExecutor{
+		tagger: tagger,
+	}
 }
 
 // Execute ejecuta una acción según su tipo
@@ -49,6 +50,10 @@ func (ae *ActionExecutor) Execute(ctx context.Context, node engine.WorkflowNode,
 		err = ae.executeConsoleLog(ctx, node, input, result)
 	case "set_context":
 		err = ae.executeSetContext(ctx, node, input, result)
+	case engine.ActionSetVariables:
+		err = ae.executeSetVariables(ctx, node, input, result)
+	case "tag":
+		err = ae.executeTag(ctx, node, input, result)
 	default:
 		result.Success = false
 		result.Error = fmt.Sprintf("unknown action type: %s", actionType)
@@ -68,11 +73,8 @@ func (ae *ActionExecutor) executeConsoleLog(ctx context.Context, node engine.Wor
 		return errx.New("missing message in console_log action", errx.TypeValidation)
 	}
 
-	// Reemplazar variables del input en el mensaje
-	formattedMessage := ae.interpolateVariables(message, input)
-
-	// Imprimir en consola con formato
-	log.Printf("🔹 [WORKFLOW ACTION] %s: %s", node.Name, formattedMessage)
+	// Las expresiones {{...}} ya fueron evaluadas por el executor
+	log.Printf("🔹 [WORKFLOW ACTION] %s: %s", node.Name, message)
 
 	// También imprimir input si está configurado
 	if printInput, ok := node.Config["print_input"].(bool); ok && printInput {
@@ -81,7 +83,7 @@ func (ae *ActionExecutor) executeConsoleLog(ctx context.Context, node engine.Wor
 
 	result.Success = true
 	result.Output = map[string]any{
-		"message":   formattedMessage,
+		"message":   message,
 		"logged_at": time.Now().Format(time.RFC3339),
 	}
 	return nil
@@ -96,33 +98,61 @@ func (ae *ActionExecutor) executeSetContext(ctx context.Context, node engine.Wor
 		return errx.New("missing context in set_context action", errx.TypeValidation)
 	}
 
-	// Interpolar variables
-	interpolatedContext := make(map[string]any)
-	for key, value := range contextData {
-		if strVal, ok := value.(string); ok {
-			interpolatedContext[key] = ae.interpolateVariables(strVal, input)
-		} else {
-			interpolatedContext[key] = value
-		}
+	// Los valores llegan ya evaluados por el executor
+	log.Printf("🔹 [WORKFLOW ACTION] %s: Setting context keys: %v", node.Name, getKeys(contextData))
+
+	result.Success = true
+	result.Output = map[string]any{
+		"context": contextData,
+	}
+	return nil
+}
+
+// executeSetVariables escribe variables declaradas por el workflow; el
+// executor las convierte a su tipo y las guarda en vars
+func (ae *ActionExecutor) executeSetVariables(ctx context.Context, node engine.WorkflowNode, input map[string]any, result *engine.NodeResult) error {
+	variables, ok := node.Config["variables"].(map[string]any)
+	if !ok {
+		result.Success = false
+		result.Error = "missing or invalid variables"
+		return errx.New("missing variables in set_variables action", errx.TypeValidation)
 	}
 
-	log.Printf("🔹 [WORKFLOW ACTION] %s: Setting context keys: %v", node.Name, getKeys(interpolatedContext))
+	log.Printf("🔹 [WORKFLOW ACTION] %s: Setting variables: %v", node.Name, getKeys(variables))
 
 	result.Success = true
 	result.Output = map[string]any{
-		"context": interpolatedContext,
+		"variables":                  variables,
+		engine.SetVariablesOutputKey: variables,
 	}
 	return nil
 }
 
-// interpolateVariables reemplaza variables tipo {{variable}} en el texto
-func (ae *ActionExecutor) interpolateVariables(text string, variables map[string]any) string {
-	result := text
-	for key, value := range variables {
-		placeholder := fmt.Sprintf("{{%s}}", key)
-		result = strings.ReplaceAll(result, placeholder, fmt.Sprint(value))
+// executeTag etiqueta la conversación y fija su disposición, igual que un
+// nodo TAG pero como parte de una acción
+func (ae *ActionExecutor) executeTag(ctx context.Context, node engine.WorkflowNode, input map[string]any, result *engine.NodeResult) error {
+	tagConfig, err := engine.ExtractTagConfig(node.Config)
+	if err != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("invalid tag action: %v", err)
+		return err
+	}
+
+	tags, err := applyTags(ctx, ae.tagger, NewFieldResolver(input, node.Config, nil), *tagConfig, "action")
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		return err
+	}
+
+	log.Printf("🔹 [WORKFLOW ACTION] %s: Tags %v, disposition %q", node.Name, tags.Tags, tags.Disposition)
+
+	result.Success = true
+	result.Output = map[string]any{
+		"tags":        tags.Tags,
+		"disposition": tags.Disposition,
 	}
-	return result
+	return nil
 }
 
 // getKeys obtiene las llaves de un map
@@ -155,6 +185,10 @@ func (ae *ActionExecutor) ValidateConfig(config map[string]any) error {
 		if _, ok := config["context"].(map[string]any); !ok {
 			return errx.New("context is required for set_context", errx.TypeValidation)
 		}
+	case engine.ActionSetVariables:
+		if _, ok := config["variables"].(map[string]any); !ok {
+			return errx.New("variables is required for set_variables", errx.TypeValidation)
+		}
 	case "delay":
 		if _, ok := config["duration_ms"]; !ok {
 			return errx.New("duration_ms is required for delay", errx.TypeValidation)
@@ -163,6 +197,10 @@ func (ae *ActionExecutor) ValidateConfig(config map[string]any) error {
 		if _, ok := config["text"].(string); !ok {
 			return errx.New("text is required for response", errx.TypeValidation)
 		}
+	case "tag":
+		if _, err := engine.ExtractTagConfig(config); err != nil {
+			return err
+		}
 	default:
 		return errx.New("unknown action type", errx.TypeValidation).
 			WithDetail("action_type", actionType)
@@ -2,33 +2,55 @@ package node
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"maps"
+	"strings"
 	"time"
 
 	"github.com/Abraxas-365/craftable/ai/llm"
 	"github.com/Abraxas-365/craftable/ai/llm/agentx"
+	"github.com/Abraxas-365/relay/channels"
 	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/featureflag"
 	"github.com/Abraxas-365/relay/pkg/agent"
+	"github.com/Abraxas-365/relay/pkg/circuitbreaker"
 	"github.com/Abraxas-365/relay/pkg/kernel"
 )
 
 type AIAgentExecutor struct {
 	agentChatRepo agent.AgentChatRepository
 	evaluator     engine.ExpressionEvaluator
+	flags         featureflag.Checker      // nil = AI always enabled
+	breakers      *circuitbreaker.Registry // One circuit per provider; nil = disabled
+	streamer      channels.MessageStreamer // nil = stream is ignored
 }
 
 func NewAIAgentExecutor(
 	agentChatRepo agent.AgentChatRepository,
 	evaluator engine.ExpressionEvaluator,
+	flags featureflag.Checker,
+	breakers *circuitbreaker.Registry,
+	streamer channels.MessageStreamer,
 ) *AIAgentExecutor {
 	return &AIAgentExecutor{
 		agentChatRepo: agentChatRepo,
 		evaluator:     evaluator,
+		flags:         flags,
+		breakers:      breakers,
+		streamer:      streamer,
 	}
 }
 
+// streamTarget is where a streamed reply is delivered
+type streamTarget struct {
+	tenantID  kernel.TenantID
+	channelID kernel.ChannelID
+	msg       channels.OutgoingMessage
+}
+
 func (e *AIAgentExecutor) Execute(ctx context.Context, node engine.WorkflowNode, input map[string]any) (*engine.NodeResult, error) {
 	startTime := time.Now()
 	result := &engine.NodeResult{
@@ -68,23 +90,59 @@ func (e *AIAgentExecutor) Execute(ctx context.Context, node engine.WorkflowNode,
 	// Get tenant ID
 	tenantID, _ := resolver.GetTenantID()
 
+	// The tenant may have AI switched off at runtime
+	if e.flags != nil && tenantID != "" && !e.flags.IsEnabled(ctx, tenantID, featureflag.FlagAIAgent) {
+		err := featureflag.ErrFeatureDisabled().WithDetail("flag", string(featureflag.FlagAIAgent))
+		result.Success = false
+		result.Error = "AI agent is disabled for this tenant"
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+
 	// Get conversation ID for memory
 	conversationID := resolver.GetString("conversation_id", "")
 	if conversationID == "" {
 		conversationID = resolver.GetString("sender_id", "")
 	}
 
-	log.Printf("🤖 AI Agent '%s' - Model: %s, Memory: %v", node.Name, aiConfig.Model, aiConfig.UseMemory)
+	// A streamed reply is delivered by the node itself
+	var target *streamTarget
+	if aiConfig.Stream {
+		if e.streamer == nil {
+			log.Printf("⚠️  AI Agent '%s' asks to stream but no channel streamer is configured", node.Name)
+		} else if target, err = e.resolveStreamTarget(node, resolver, tenantID); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			result.Duration = time.Since(startTime).Milliseconds()
+			return result, err
+		}
+	}
+
+	log.Printf("🤖 AI Agent '%s' - Model: %s, Memory: %v, Stream: %v", node.Name, aiConfig.Model, aiConfig.UseMemory, target != nil)
+
+	breaker := e.breakers.Breaker("ai:" + aiConfig.Provider)
+	if err := breaker.Allow(); err != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("circuit open for AI provider %s", aiConfig.Provider)
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
 
 	var responseText string
 	var metadata map[string]any
+	var deliveryErr error
 
 	// Execute with or without memory
-	if aiConfig.UseMemory && conversationID != "" && tenantID != "" {
+	useMemory := aiConfig.UseMemory && conversationID != "" && tenantID != ""
+	switch {
+	case target != nil:
+		responseText, metadata, deliveryErr, err = e.executeStreaming(ctx, aiConfig, userMessage, useMemory, conversationID, target, input)
+	case useMemory:
 		responseText, metadata, err = e.executeWithAgent(ctx, aiConfig, userMessage, string(tenantID), conversationID, input)
-	} else {
+	default:
 		responseText, metadata, err = e.executeWithLLM(ctx, aiConfig, userMessage, input)
 	}
+	breaker.Record(err != nil && ctx.Err() == nil)
 
 	if err != nil {
 		result.Success = false
@@ -93,6 +151,16 @@ func (e *AIAgentExecutor) Execute(ctx context.Context, node engine.WorkflowNode,
 		return result, err
 	}
 
+	// The reply was generated but the channel did not take it
+	if deliveryErr != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("failed to deliver AI response: %v", deliveryErr)
+		result.Output["error"] = sendFailure(deliveryErr, result.Error)
+		result.Output["ai_response"] = responseText
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, deliveryErr
+	}
+
 	result.Success = true
 	result.Output["ai_response"] = responseText
 	result.Output["response"] = responseText
@@ -180,6 +248,169 @@ func (e *AIAgentExecutor) executeWithAgent(
 	return response, metadata, nil
 }
 
+// executeStreaming generates the reply while the channel manager delivers it.
+// Plain LLM replies are streamed token by token; agent replies are only known
+// once the agent finishes, so they go out as a single part. Delivery errors
+// are returned apart from generation errors so they don't trip the provider's
+// circuit.
+func (e *AIAgentExecutor) executeStreaming(
+	ctx context.Context,
+	config *engine.AIAgentConfig,
+	userMessage string,
+	useMemory bool,
+	conversationID string,
+	target *streamTarget,
+	input map[string]any,
+) (string, map[string]any, error, error) {
+	// A failed delivery cancels the generation, which nobody would see
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	parts := make(chan string, 64)
+	delivered := make(chan error, 1)
+	go func() {
+		err := e.streamer.StreamMessage(ctx, target.tenantID, target.channelID, target.msg, parts)
+		if err != nil {
+			cancel()
+		}
+		delivered <- err
+	}()
+
+	var responseText string
+	var metadata map[string]any
+	var err error
+	if useMemory {
+		responseText, metadata, err = e.executeWithAgent(ctx, config, userMessage, string(target.tenantID), conversationID, input)
+		if err == nil {
+			sendPart(ctx, parts, responseText)
+		}
+	} else {
+		responseText, metadata, err = e.streamWithLLM(ctx, config, userMessage, parts)
+	}
+
+	// On a generation error the partial message is left as it is
+	if err != nil {
+		cancel()
+	}
+	close(parts)
+	deliveryErr := <-delivered
+
+	switch {
+	case deliveryErr != nil && (err == nil || errors.Is(err, context.Canceled)):
+		return responseText, metadata, deliveryErr, nil
+	case err != nil:
+		return "", nil, nil, err
+	}
+
+	metadata["streamed"] = true
+	metadata["delivered"] = true
+	metadata["channel_id"] = target.channelID.String()
+	metadata["recipient_id"] = target.msg.RecipientID
+	return responseText, metadata, nil, nil
+}
+
+// streamWithLLM streams a plain LLM reply into parts
+func (e *AIAgentExecutor) streamWithLLM(
+	ctx context.Context,
+	config *engine.AIAgentConfig,
+	userMessage string,
+	parts chan<- string,
+) (string, map[string]any, error) {
+	client := config.GetLLMClient()
+
+	messages := []llm.Message{
+		llm.NewSystemMessage(config.SystemPrompt),
+		llm.NewUserMessage(userMessage),
+	}
+
+	stream, err := client.ChatStream(ctx, messages, config.GetLLMOptions()...)
+	if err != nil {
+		return "", nil, err
+	}
+	defer stream.Close()
+
+	var text string
+	for {
+		chunk, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", nil, err
+		}
+
+		// Chunks carry the message accumulated so far; only the new tokens
+		// are handed to the channel
+		if len(chunk.Content) <= len(text) || !strings.HasPrefix(chunk.Content, text) {
+			continue
+		}
+		delta := chunk.Content[len(text):]
+		text = chunk.Content
+		if !sendPart(ctx, parts, delta) {
+			return "", nil, ctx.Err()
+		}
+	}
+
+	metadata := map[string]any{
+		"mode": "llm_stream",
+	}
+
+	return text, metadata, nil
+}
+
+// resolveStreamTarget builds the outgoing message of a streamed reply
+// (priority: config -> trigger)
+func (e *AIAgentExecutor) resolveStreamTarget(node engine.WorkflowNode, resolver *FieldResolver, tenantID kernel.TenantID) (*streamTarget, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required to stream the reply")
+	}
+
+	channelID := resolver.GetString("channel_id", "")
+	if channelID == "" {
+		return nil, fmt.Errorf("channel_id is required to stream the reply")
+	}
+
+	recipientID := resolver.GetString("recipient_id", "")
+	if recipientID == "" {
+		recipientID = resolver.GetString("sender_id", "")
+	}
+	if recipientID == "" {
+		return nil, fmt.Errorf("recipient_id is required to stream the reply")
+	}
+
+	msg := channels.OutgoingMessage{
+		RecipientID: recipientID,
+		Content:     channels.MessageContent{Type: "text"},
+		Metadata: map[string]any{
+			"origin":             "workflow",
+			"workflow_node_id":   node.ID,
+			"workflow_node_name": node.Name,
+			"timestamp":          time.Now().Unix(),
+		},
+	}
+
+	// Attribute the message to its workflow for conversation transcripts
+	if workflowID, err := resolver.GetWorkflowID(); err == nil {
+		msg.Metadata["workflow_id"] = workflowID.String()
+	}
+
+	return &streamTarget{
+		tenantID:  tenantID,
+		channelID: kernel.ChannelID(channelID),
+		msg:       msg,
+	}, nil
+}
+
+// sendPart hands a part to the streamer unless the context is done
+func sendPart(ctx context.Context, parts chan<- string, part string) bool {
+	select {
+	case parts <- part:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 func (e *AIAgentExecutor) SupportsType(nodeType engine.NodeType) bool {
 	return nodeType == engine.NodeTypeAIAgent
 }
@@ -0,0 +1,101 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+)
+
+// BusinessHoursExecutor branches on whether the tenant is open, on holiday or
+// closed according to its business hours
+type BusinessHoursExecutor struct {
+	provider engine.BusinessHoursProvider
+}
+
+var _ engine.NodeExecutor = (*BusinessHoursExecutor)(nil)
+
+func NewBusinessHoursExecutor(provider engine.BusinessHoursProvider) *BusinessHoursExecutor {
+	return &BusinessHoursExecutor{
+		provider: provider,
+	}
+}
+
+func (e *BusinessHoursExecutor) Execute(ctx context.Context, node engine.WorkflowNode, input map[string]any) (*engine.NodeResult, error) {
+	startTime := time.Now()
+	result := &engine.NodeResult{
+		NodeID:    node.ID,
+		NodeName:  node.Name,
+		Timestamp: startTime,
+		Output:    make(map[string]any),
+	}
+
+	hoursConfig, err := engine.ExtractBusinessHoursConfig(node.Config)
+	if err != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("invalid business hours config: %v", err)
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+
+	tenantID, err := NewFieldResolver(input, node.Config, nil).GetTenantID()
+	if err != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("tenant_id not found: %v", err)
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+
+	status, err := e.provider.Status(ctx, tenantID, startTime)
+	if err != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("failed to load business hours: %v", err)
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+
+	branch := "closed"
+	switch {
+	case status.Open:
+		branch = "open"
+	case status.Holiday:
+		branch = "holiday"
+	}
+
+	log.Printf("🕘 Business hours: tenant %s is %s (%s, %s)",
+		tenantID, branch, status.LocalTime.Format("Mon 15:04"), status.Timezone)
+
+	result.Success = true
+	result.Output["in_business_hours"] = status.Open
+	result.Output["holiday"] = status.Holiday
+	result.Output["configured"] = status.Configured
+	result.Output["timezone"] = status.Timezone
+	result.Output["local_time"] = status.LocalTime.Format(time.RFC3339)
+	result.Output["branch"] = branch
+	if status.NextOpenAt != nil {
+		result.Output["next_open_at"] = status.NextOpenAt.Format(time.RFC3339)
+	}
+
+	if nextNode := hoursConfig.NextNode(*status); nextNode != "" {
+		result.Output["next_node"] = nextNode
+		// Store in context for workflow executor
+		input["__next_node"] = nextNode
+	}
+
+	result.Duration = time.Since(startTime).Milliseconds()
+	return result, nil
+}
+
+func (e *BusinessHoursExecutor) SupportsType(nodeType engine.NodeType) bool {
+	return nodeType == engine.NodeTypeBusinessHours
+}
+
+func (e *BusinessHoursExecutor) ValidateConfig(config map[string]any) error {
+	hoursConfig, err := engine.ExtractBusinessHoursConfig(config)
+	if err != nil {
+		return err
+	}
+	return hoursConfig.Validate()
+}
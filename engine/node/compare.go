@@ -0,0 +1,163 @@
+package node
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Typed Comparison
+// ============================================================================
+//
+// Condition values come from JSON (float64, bool, string, nil) and from node
+// outputs (any Go type). Comparisons use the most specific type both sides
+// share instead of their fmt representation, so 10 == "10.0", "10" == "10.0",
+// true == "true" and "2025-01-02T10:00:00Z" < "2025-01-03T00:00:00Z".
+
+// compareTimeLayouts are the string forms accepted as timestamps
+var compareTimeLayouts = []string{time.RFC3339Nano, time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+// valuesEqual compares two values by type and agrees with compareValues:
+// numbers (numeric strings included) compare numerically and timestamps
+// chronologically. nil only equals nil, and two strings only compare as
+// booleans when neither is a number or a timestamp.
+func valuesEqual(a, b any, caseInsensitive bool) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	if x, ok := asNumber(a); ok {
+		if y, ok := asNumber(b); ok {
+			return x == y
+		}
+	}
+
+	if x, ok := asTime(a); ok {
+		if y, ok := asTime(b); ok {
+			return x.Equal(y)
+		}
+	}
+
+	if !isStringValue(a) || !isStringValue(b) {
+		if x, ok := asBool(a); ok {
+			if y, ok := asBool(b); ok {
+				return x == y
+			}
+		}
+	}
+
+	left, right := normalizeCase(fmt.Sprint(a), fmt.Sprint(b), caseInsensitive)
+	return left == right
+}
+
+// compareValues orders two values: numbers numerically, timestamps
+// chronologically, anything else as strings. ok is false when either side is
+// nil, so ordering operators never match missing values.
+func compareValues(a, b any, caseInsensitive bool) (result int, ok bool) {
+	if a == nil || b == nil {
+		return 0, false
+	}
+
+	if x, ok := asNumber(a); ok {
+		if y, ok := asNumber(b); ok {
+			return cmp.Compare(x, y), true
+		}
+	}
+
+	if x, ok := asTime(a); ok {
+		if y, ok := asTime(b); ok {
+			return x.Compare(y), true
+		}
+	}
+
+	left, right := normalizeCase(fmt.Sprint(a), fmt.Sprint(b), caseInsensitive)
+	return strings.Compare(left, right), true
+}
+
+func normalizeCase(a, b string, caseInsensitive bool) (string, string) {
+	if caseInsensitive {
+		return strings.ToLower(a), strings.ToLower(b)
+	}
+	return a, b
+}
+
+func isStringValue(v any) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+func asNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		// NaN and Inf parse as floats but are words, not numbers, in a condition
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil && !math.IsNaN(f) && !math.IsInf(f, 0)
+	}
+	return 0, false
+}
+
+func asBool(v any) (bool, bool) {
+	switch b := v.(type) {
+	case bool:
+		return b, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(b)) {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+func asTime(v any) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case *time.Time:
+		if t != nil {
+			return *t, true
+		}
+	case string:
+		s := strings.TrimSpace(t)
+		for _, layout := range compareTimeLayouts {
+			if parsed, err := time.Parse(layout, s); err == nil {
+				return parsed, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
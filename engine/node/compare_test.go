@@ -0,0 +1,136 @@
+package node
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestValuesEqual(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		a, b            any
+		caseInsensitive bool
+		want            bool
+	}{
+		// numeric
+		{name: "float and int", a: 10.0, b: 10, want: true},
+		{name: "different numbers", a: 10.0, b: 11, want: false},
+		{name: "json number and float", a: json.Number("2.5"), b: 2.5, want: true},
+		{name: "uint and int64", a: uint8(7), b: int64(7), want: true},
+
+		// string
+		{name: "same text", a: "hello", b: "hello", want: true},
+		{name: "different text", a: "hello", b: "world", want: false},
+		{name: "case sensitive", a: "Hello", b: "hello", want: false},
+		{name: "case insensitive", a: "Hello", b: "hello", caseInsensitive: true, want: true},
+		{name: "numeric strings", a: "10", b: "10.0", want: true},
+		{name: "numeric strings with spaces", a: " 10 ", b: "10", want: true},
+		{name: "different numeric strings", a: "10", b: "10.5", want: false},
+		{name: "NaN is text", a: "NaN", b: "NaN", want: true},
+		{name: "inf is text", a: "inf", b: "Infinity", want: false},
+		{name: "timestamp strings", a: "2025-01-02T10:00:00Z", b: "2025-01-02T05:00:00-05:00", want: true},
+		{name: "bool words stay text", a: "true", b: "TRUE", want: false},
+
+		// bool
+		{name: "same bool", a: true, b: true, want: true},
+		{name: "different bool", a: true, b: false, want: false},
+
+		// nil
+		{name: "both nil", a: nil, b: nil, want: true},
+		{name: "nil and empty string", a: nil, b: "", want: false},
+		{name: "nil and zero", a: 0, b: nil, want: false},
+
+		// mixed
+		{name: "string and float", a: "10", b: 10.0, want: true},
+		{name: "float and numeric string", a: 10.0, b: "10.0", want: true},
+		{name: "float and text", a: 10.0, b: "ten", want: false},
+		{name: "bool and string", a: true, b: "TRUE", want: true},
+		{name: "bool and number", a: true, b: 1, want: false},
+		{name: "time and string", a: ts, b: "2025-01-02T10:00:00Z", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := valuesEqual(tt.a, tt.b, tt.caseInsensitive); got != tt.want {
+				t.Errorf("valuesEqual(%#v, %#v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := valuesEqual(tt.b, tt.a, tt.caseInsensitive); got != tt.want {
+				t.Errorf("valuesEqual(%#v, %#v) = %v, want %v", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		name            string
+		a, b            any
+		caseInsensitive bool
+		want            int
+		wantOK          bool
+	}{
+		// numeric
+		{name: "less", a: 1, b: 2.5, want: -1, wantOK: true},
+		{name: "greater", a: 3.0, b: int64(2), want: 1, wantOK: true},
+		{name: "equal", a: 2, b: 2.0, want: 0, wantOK: true},
+
+		// string
+		{name: "numeric strings", a: "10", b: "9", want: 1, wantOK: true},
+		{name: "numeric strings equal", a: "10", b: "10.0", want: 0, wantOK: true},
+		{name: "text", a: "apple", b: "banana", want: -1, wantOK: true},
+		{name: "text case sensitive", a: "B", b: "a", want: -1, wantOK: true},
+		{name: "text case insensitive", a: "B", b: "a", caseInsensitive: true, want: 1, wantOK: true},
+		{name: "timestamps", a: "2025-01-02T10:00:00Z", b: "2025-01-03", want: -1, wantOK: true},
+
+		// bool
+		{name: "bools as text", a: false, b: true, want: -1, wantOK: true},
+
+		// nil
+		{name: "nil left", a: nil, b: 1, wantOK: false},
+		{name: "nil right", a: "a", b: nil, wantOK: false},
+		{name: "both nil", a: nil, b: nil, wantOK: false},
+
+		// mixed
+		{name: "numeric string and number", a: "10", b: 9, want: 1, wantOK: true},
+		{name: "number and text", a: 10, b: "abc", want: -1, wantOK: true},
+		{name: "time and string", a: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), b: "2025-01-02", want: 1, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := compareValues(tt.a, tt.b, tt.caseInsensitive)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("compareValues(%#v, %#v) = %d, %v, want %d, %v", tt.a, tt.b, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// eq must match exactly when gte and lte both match, so a value in a range
+// [x, x] is always equal to x.
+func TestValuesEqualAgreesWithCompareValues(t *testing.T) {
+	pairs := [][2]any{
+		{"10", "10.0"},
+		{"10", 10.0},
+		{" 3 ", "3"},
+		{"1e2", 100},
+		{"10", "11"},
+		{"2025-01-02T10:00:00Z", "2025-01-02T05:00:00-05:00"},
+		{"abc", "abc"},
+		{"abc", "abd"},
+		{json.Number("4"), "4.00"},
+	}
+
+	for _, p := range pairs {
+		result, ok := compareValues(p[0], p[1], false)
+		if !ok {
+			t.Fatalf("compareValues(%#v, %#v) not comparable", p[0], p[1])
+		}
+		if eq := valuesEqual(p[0], p[1], false); eq != (result == 0) {
+			t.Errorf("valuesEqual(%#v, %#v) = %v but compareValues = %d", p[0], p[1], eq, result)
+		}
+	}
+}
@@ -29,6 +29,22 @@ func (ce *ConditionExecutor) Execute(ctx context.Context, node engine.WorkflowNo
 		Output:    make(map[string]any),
 	}
 
+	// Grupos de condiciones (and/or anidados)
+	if isConditionGroup(node.Config) {
+		conditionMet, err := ce.evaluateGroupConfig(node.Config, input)
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			result.Duration = time.Since(startTime).Milliseconds()
+			return result, err
+		}
+
+		result.Success = true
+		result.Output["condition_met"] = conditionMet
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, nil
+	}
+
 	// Obtener configuración
 	conditionType, ok := node.Config["condition_type"].(string)
 	if !ok {
@@ -50,6 +66,9 @@ func (ce *ConditionExecutor) Execute(ctx context.Context, node engine.WorkflowNo
 		conditionMet, err = ce.evaluateExists(node.Config, input)
 	case "regex":
 		conditionMet, err = ce.evaluateRegex(node.Config, input)
+	case "not_equals", "in", "not_in", "starts_with", "ends_with", "is_empty",
+		"greater_than", "greater_or_equal", "less_than", "less_or_equal":
+		conditionMet, err = ce.evaluateOperator(engine.ConditionOperator(conditionType), node.Config, input)
 	default:
 		result.Success = false
 		result.Error = fmt.Sprintf("unknown condition type: %s", conditionType)
@@ -108,7 +127,8 @@ func (ce *ConditionExecutor) evaluateEquals(config map[string]any, input map[str
 		return false, nil
 	}
 
-	return fmt.Sprint(actualValue) == fmt.Sprint(expectedValue), nil
+	caseInsensitive, _ := config["case_insensitive"].(bool)
+	return valuesEqual(actualValue, expectedValue, caseInsensitive), nil
 }
 
 func (ce *ConditionExecutor) evaluateExists(config map[string]any, input map[string]any) (bool, error) {
@@ -122,8 +142,48 @@ func (ce *ConditionExecutor) evaluateExists(config map[string]any, input map[str
 }
 
 func (ce *ConditionExecutor) evaluateRegex(config map[string]any, input map[string]any) (bool, error) {
-	// TODO: Implementar evaluación de regex
-	return false, errx.New("regex evaluation not implemented", errx.TypeInternal)
+	field, ok := config["field"].(string)
+	if !ok {
+		return false, errx.New("missing field", errx.TypeValidation)
+	}
+
+	pattern, ok := config["pattern"].(string)
+	if !ok {
+		return false, errx.New("missing pattern", errx.TypeValidation)
+	}
+
+	caseInsensitive, _ := config["case_insensitive"].(bool)
+	return evaluateCondition(engine.Condition{
+		Field:           field,
+		Operator:        engine.OperatorRegex,
+		Value:           pattern,
+		CaseInsensitive: caseInsensitive,
+	}, input)
+}
+
+// evaluateOperator evalúa la forma simple (condition_type + field + value)
+// de los operadores que no tienen implementación propia
+func (ce *ConditionExecutor) evaluateOperator(operator engine.ConditionOperator, config map[string]any, input map[string]any) (bool, error) {
+	field, ok := config["field"].(string)
+	if !ok {
+		return false, errx.New("missing field", errx.TypeValidation)
+	}
+
+	caseInsensitive, _ := config["case_insensitive"].(bool)
+	return evaluateCondition(engine.Condition{
+		Field:           field,
+		Operator:        operator,
+		Value:           config["value"],
+		CaseInsensitive: caseInsensitive,
+	}, input)
+}
+
+func (ce *ConditionExecutor) evaluateGroupConfig(config map[string]any, input map[string]any) (bool, error) {
+	conditionConfig, err := engine.ExtractConditionConfig(config)
+	if err != nil {
+		return false, err
+	}
+	return evaluateCondition(conditionConfig.AsGroup(), input)
 }
 
 func (ce *ConditionExecutor) SupportsType(nodeType engine.NodeType) bool {
@@ -131,23 +191,196 @@ func (ce *ConditionExecutor) SupportsType(nodeType engine.NodeType) bool {
 }
 
 func (ce *ConditionExecutor) ValidateConfig(config map[string]any) error {
+	if isConditionGroup(config) {
+		_, err := engine.ExtractConditionConfig(config)
+		return err
+	}
+
 	conditionType, ok := config["condition_type"].(string)
 	if !ok {
 		return errx.New("condition_type is required", errx.TypeValidation)
 	}
 
 	switch conditionType {
-	case "contains", "equals", "exists":
+	case "contains", "equals", "exists", "not_equals", "in", "not_in", "starts_with", "ends_with", "is_empty",
+		"greater_than", "greater_or_equal", "less_than", "less_or_equal":
 		if _, ok := config["field"].(string); !ok {
 			return errx.New("field is required", errx.TypeValidation)
 		}
 	case "regex":
-		if _, ok := config["pattern"].(string); !ok {
+		pattern, ok := config["pattern"].(string)
+		if !ok {
 			return errx.New("pattern is required for regex", errx.TypeValidation)
 		}
+		if err := engine.ValidateRegex(pattern); err != nil {
+			return err
+		}
 	default:
 		return errx.New("unknown condition type", errx.TypeValidation)
 	}
 
 	return nil
 }
+
+// ============================================================================
+// Evaluación de grupos y operadores
+// ============================================================================
+
+// isConditionGroup indica si el nodo usa la forma agrupada (conditions + logic)
+func isConditionGroup(config map[string]any) bool {
+	if config["condition_type"] == "group" {
+		return true
+	}
+	_, ok := config["conditions"]
+	return ok
+}
+
+// evaluateCondition evalúa una condición simple o un grupo anidado.
+// Los grupos "and" y "or" cortan en cuanto el resultado es conocido.
+func evaluateCondition(condition engine.Condition, input map[string]any) (bool, error) {
+	if condition.IsGroup() {
+		isOr := condition.GetLogic() == engine.LogicOr
+		for _, child := range condition.Conditions {
+			met, err := evaluateCondition(child, input)
+			if err != nil {
+				return false, err
+			}
+			if met == isOr {
+				return isOr, nil
+			}
+		}
+		return !isOr, nil
+	}
+
+	actual, exists := lookupFieldValue(input, condition.Field)
+
+	switch condition.Operator {
+	case engine.OperatorExists:
+		return exists, nil
+
+	case engine.OperatorIsEmpty:
+		return !exists || isEmptyValue(actual), nil
+
+	case engine.OperatorEquals:
+		return exists && valuesEqual(actual, condition.Value, condition.CaseInsensitive), nil
+
+	case engine.OperatorNotEquals:
+		return !exists || !valuesEqual(actual, condition.Value, condition.CaseInsensitive), nil
+
+	case engine.OperatorContains:
+		if !exists {
+			return false, nil
+		}
+		// En listas, contains busca el elemento
+		if items, ok := actual.([]any); ok {
+			return containsValue(items, condition.Value, condition.CaseInsensitive), nil
+		}
+		text, value := normalizeCase(fmt.Sprint(actual), fmt.Sprint(condition.Value), condition.CaseInsensitive)
+		return strings.Contains(text, value), nil
+
+	case engine.OperatorIn, engine.OperatorNotIn:
+		items, ok := condition.Value.([]any)
+		if !ok {
+			return false, errx.New(fmt.Sprintf("operator '%s' requires a list value", condition.Operator), errx.TypeValidation)
+		}
+		found := exists && containsValue(items, actual, condition.CaseInsensitive)
+		if condition.Operator == engine.OperatorNotIn {
+			return !found, nil
+		}
+		return found, nil
+
+	case engine.OperatorGreaterThan, engine.OperatorGreaterOrEqual, engine.OperatorLessThan, engine.OperatorLessOrEqual:
+		if !exists {
+			return false, nil
+		}
+		result, comparable := compareValues(actual, condition.Value, condition.CaseInsensitive)
+		if !comparable {
+			return false, nil
+		}
+		switch condition.Operator {
+		case engine.OperatorGreaterThan:
+			return result > 0, nil
+		case engine.OperatorGreaterOrEqual:
+			return result >= 0, nil
+		case engine.OperatorLessThan:
+			return result < 0, nil
+		default:
+			return result <= 0, nil
+		}
+
+	case engine.OperatorStartsWith:
+		if !exists {
+			return false, nil
+		}
+		text, prefix := normalizeCase(fmt.Sprint(actual), fmt.Sprint(condition.Value), condition.CaseInsensitive)
+		return strings.HasPrefix(text, prefix), nil
+
+	case engine.OperatorEndsWith:
+		if !exists {
+			return false, nil
+		}
+		text, suffix := normalizeCase(fmt.Sprint(actual), fmt.Sprint(condition.Value), condition.CaseInsensitive)
+		return strings.HasSuffix(text, suffix), nil
+
+	case engine.OperatorRegex:
+		if !exists {
+			return false, nil
+		}
+		pattern, _ := condition.Value.(string)
+		if condition.CaseInsensitive {
+			pattern = "(?i)" + pattern
+		}
+		re, err := engine.CompileRegex(pattern)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(fmt.Sprint(actual)), nil
+
+	default:
+		return false, errx.New(fmt.Sprintf("unknown operator '%s'", condition.Operator), errx.TypeValidation)
+	}
+}
+
+// lookupFieldValue resuelve rutas anidadas (trigger.body.status) y distingue
+// un campo ausente de uno con valor nil
+func lookupFieldValue(input map[string]any, path string) (any, bool) {
+	if value, ok := input[path]; ok {
+		return value, true
+	}
+
+	current := any(input)
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+func containsValue(items []any, value any, caseInsensitive bool) bool {
+	for _, item := range items {
+		if valuesEqual(item, value, caseInsensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
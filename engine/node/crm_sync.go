@@ -0,0 +1,107 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+)
+
+// CRMSyncExecutor writes contacts, activities, deals and tickets to the CRM
+// the tenant connected. Its output's record_id can be linked by later nodes
+// through contact_id.
+type CRMSyncExecutor struct {
+	syncer engine.CRMSyncer
+}
+
+var _ engine.NodeExecutor = (*CRMSyncExecutor)(nil)
+
+func NewCRMSyncExecutor(syncer engine.CRMSyncer) *CRMSyncExecutor {
+	return &CRMSyncExecutor{
+		syncer: syncer,
+	}
+}
+
+func (e *CRMSyncExecutor) Execute(ctx context.Context, node engine.WorkflowNode, input map[string]any) (*engine.NodeResult, error) {
+	startTime := time.Now()
+	result := &engine.NodeResult{
+		NodeID:    node.ID,
+		NodeName:  node.Name,
+		Timestamp: startTime,
+		Output:    make(map[string]any),
+	}
+	fail := func(err error) (*engine.NodeResult, error) {
+		result.Success = false
+		result.Error = err.Error()
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+
+	crmConfig, err := engine.ExtractCRMSyncConfig(node.Config)
+	if err != nil {
+		return fail(fmt.Errorf("invalid CRM sync config: %w", err))
+	}
+	if e.syncer == nil {
+		return fail(fmt.Errorf("CRM sync is not configured"))
+	}
+
+	resolver := NewFieldResolver(input, node.Config, nil)
+	tenantID, err := resolver.GetTenantID()
+	if err != nil {
+		return fail(fmt.Errorf("tenant_id not found: %w", err))
+	}
+
+	req := engine.CRMSyncRequest{
+		Provider:   crmConfig.Provider,
+		Operation:  crmConfig.Operation,
+		Fields:     renderFields(resolver, crmConfig.Fields),
+		MatchField: crmConfig.GetMatchField(),
+		ContactID:  renderedOrEmpty(resolver, crmConfig.ContactID),
+	}
+	if crmConfig.Operation == engine.CRMUpsertContact && req.Fields[req.MatchField] == nil {
+		return fail(fmt.Errorf("match field %q has no value", req.MatchField))
+	}
+
+	synced, err := e.syncer.SyncCRM(ctx, tenantID, req)
+	if err != nil {
+		return fail(err)
+	}
+
+	result.Success = true
+	result.Output["provider"] = synced.Provider
+	result.Output["operation"] = string(synced.Operation)
+	result.Output["record_id"] = synced.RecordID
+	result.Output["created"] = synced.Created
+	result.Duration = time.Since(startTime).Milliseconds()
+	return result, nil
+}
+
+func (e *CRMSyncExecutor) SupportsType(nodeType engine.NodeType) bool {
+	return nodeType == engine.NodeTypeCRMSync
+}
+
+func (e *CRMSyncExecutor) ValidateConfig(config map[string]any) error {
+	_, err := engine.ExtractCRMSyncConfig(config)
+	return err
+}
+
+// renderFields renders templated field values, dropping the ones left empty
+// or unresolved so a missing variable never overwrites a CRM property
+func renderFields(resolver *FieldResolver, fields map[string]string) map[string]any {
+	rendered := make(map[string]any, len(fields))
+	for name, template := range fields {
+		if value := renderedOrEmpty(resolver, template); value != "" {
+			rendered[name] = value
+		}
+	}
+	return rendered
+}
+
+func renderedOrEmpty(resolver *FieldResolver, template string) string {
+	value := resolver.RenderTemplate(template)
+	if templatePlaceholderRegex.MatchString(value) {
+		return ""
+	}
+	return value
+}
@@ -3,11 +3,20 @@ package node
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Abraxas-365/relay/engine"
 )
 
+// maxDelay bounds every delay. It leaves room for next-business-day waits
+// over long weekends and holidays.
+const maxDelay = 31 * 24 * time.Hour
+
+// delayFields are the ways to say how long to wait, in order of precedence
+var delayFields = []string{"duration_ms", "duration", "duration_seconds", "until"}
+
 type DelayExecutor struct {
 	scheduler engine.DelayScheduler
 }
@@ -31,26 +40,15 @@ func (e *DelayExecutor) Execute(
 		Timestamp: time.Now(),
 	}
 
-	duration, err := e.parseDuration(node.Config)
+	duration, until, err := e.resolveDelay(node.Config, time.Now())
 	if err != nil {
 		result.Success = false
 		result.Error = err.Error()
 		return result, err
 	}
 
-	if duration < 0 {
-		err := fmt.Errorf("duration cannot be negative")
-		result.Success = false
-		result.Error = err.Error()
-		return result, err
-	}
-
-	maxDelay := 24 * time.Hour
-	if duration > maxDelay {
-		err := fmt.Errorf("delay exceeds maximum allowed (%v)", maxDelay)
-		result.Success = false
-		result.Error = err.Error()
-		return result, err
+	if until != nil {
+		result.Output["until"] = until.Format(time.RFC3339)
 	}
 
 	if !e.scheduler.ShouldUseAsync(duration) {
@@ -100,6 +98,11 @@ func (e *DelayExecutor) executeAsyncDelay(
 		NextNodeID:  node.OnSuccess,
 		NodeContext: input,
 	}
+	if trigger, ok := input["trigger"].(map[string]any); ok {
+		continuation.ConversationID, _ = trigger["conversation_id"].(string)
+		continuation.MessageID, _ = trigger["message_id"].(string)
+		continuation.ChannelID, _ = trigger["channel_id"].(string)
+	}
 
 	if err := e.scheduler.Schedule(ctx, continuation, duration); err != nil {
 		result.Success = false
@@ -118,28 +121,158 @@ func (e *DelayExecutor) executeAsyncDelay(
 	return result, nil
 }
 
+// resolveDelay works out how long to wait from the evaluated config. until
+// is set when the node waits for an instant; one already past waits nothing.
+func (e *DelayExecutor) resolveDelay(config map[string]any, now time.Time) (time.Duration, *time.Time, error) {
+	var duration time.Duration
+	var until *time.Time
+
+	if value, ok := config["until"]; ok {
+		timezone, _ := config["timezone"].(string)
+		at, err := parseUntil(value, timezone)
+		if err != nil {
+			return 0, nil, err
+		}
+		until = &at
+		duration = max(at.Sub(now), 0)
+	} else {
+		d, err := e.parseDuration(config)
+		if err != nil {
+			return 0, nil, err
+		}
+		if d < 0 {
+			return 0, nil, fmt.Errorf("duration cannot be negative")
+		}
+		duration = d
+	}
+
+	if duration > maxDelay {
+		return 0, nil, fmt.Errorf("delay exceeds maximum allowed (%v)", maxDelay)
+	}
+
+	return duration, until, nil
+}
+
 func (e *DelayExecutor) parseDuration(config map[string]any) (time.Duration, error) {
-	if durationMs, ok := config["duration_ms"].(float64); ok {
+	if value, ok := config["duration_ms"]; ok {
+		durationMs, ok := asNumber(value)
+		if !ok {
+			return 0, fmt.Errorf("duration_ms must be a number, got %v", value)
+		}
 		return time.Duration(durationMs) * time.Millisecond, nil
 	}
 
-	if durationStr, ok := config["duration"].(string); ok {
-		return time.ParseDuration(durationStr)
+	if value, ok := config["duration"]; ok {
+		switch d := value.(type) {
+		case time.Duration: // duration('5m') in CEL
+			return d, nil
+		case string:
+			return time.ParseDuration(d)
+		}
+		return 0, fmt.Errorf("duration must be a string such as 5m, got %v", value)
 	}
 
-	if durationSec, ok := config["duration_seconds"].(float64); ok {
+	if value, ok := config["duration_seconds"]; ok {
+		durationSec, ok := asNumber(value)
+		if !ok {
+			return 0, fmt.Errorf("duration_seconds must be a number, got %v", value)
+		}
 		return time.Duration(durationSec * float64(time.Second)), nil
 	}
 
-	return 0, fmt.Errorf("duration not found (try: duration_ms, duration, or duration_seconds)")
+	return 0, fmt.Errorf("duration not found (try: duration_ms, duration, duration_seconds, or until)")
+}
+
+// untilLocalLayouts are timestamps without an offset, read in the node's
+// timezone
+var untilLocalLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+// parseUntil accepts a timestamp (from CEL, e.g. next_business_day_at()) or a
+// string, RFC 3339 or local to timezone (UTC when empty)
+func parseUntil(value any, timezone string) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		s := strings.TrimSpace(v)
+		for _, layout := range []string{time.RFC3339Nano, time.RFC3339} {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timezone %q", timezone)
+		}
+		for _, layout := range untilLocalLayouts {
+			if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("until must be a timestamp such as 2025-01-02T09:00:00-05:00, got %v", value)
 }
 
 func (e *DelayExecutor) SupportsType(nodeType engine.NodeType) bool {
 	return nodeType == engine.NodeTypeDelay
 }
 
+// ValidateConfig runs when the workflow is saved. Expressions are only
+// checked for syntax; literal values are checked as they would be at run time.
 func (e *DelayExecutor) ValidateConfig(config map[string]any) error {
-	_, err := e.parseDuration(config)
+	var set []string
+	literal := make(map[string]any)
+	for _, field := range delayFields {
+		value, ok := config[field]
+		if !ok {
+			continue
+		}
+		set = append(set, field)
+
+		if s, ok := value.(string); ok && engine.IsTemplate(s) {
+			if err := engine.CheckTemplate(s); err != nil {
+				return err
+			}
+			continue
+		}
+		literal[field] = value
+	}
+
+	if len(set) == 0 {
+		return fmt.Errorf("duration not found (try: duration_ms, duration, duration_seconds, or until)")
+	}
+	if len(set) > 1 && slices.Contains(set, "until") {
+		return fmt.Errorf("until cannot be combined with %s", strings.Join(slices.DeleteFunc(set, func(f string) bool { return f == "until" }), ", "))
+	}
+
+	if timezone, ok := config["timezone"].(string); ok {
+		if engine.IsTemplate(timezone) {
+			if err := engine.CheckTemplate(timezone); err != nil {
+				return err
+			}
+		} else {
+			if _, err := time.LoadLocation(timezone); err != nil {
+				return fmt.Errorf("invalid timezone %q", timezone)
+			}
+			literal["timezone"] = timezone
+		}
+	}
+
+	field := set[0]
+	value, ok := literal[field]
+	if !ok {
+		return nil
+	}
+
+	// An until already past is fine at save time, it will be at run time too
+	if field == "until" {
+		timezone, _ := literal["timezone"].(string)
+		_, err := parseUntil(value, timezone)
+		return err
+	}
+
+	_, _, err := e.resolveDelay(map[string]any{field: value}, time.Now())
 	return err
 }
 
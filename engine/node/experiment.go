@@ -0,0 +1,125 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+)
+
+// ExperimentExecutor runs A/B tests. assign routes each contact to its
+// variant's next node, always the same one for the same contact, and
+// records the exposure; convert records that the contact reached a goal.
+// Recording failures are logged and never block the run.
+type ExperimentExecutor struct {
+	recorder engine.ExperimentRecorder // nil = events are not recorded
+}
+
+var _ engine.NodeExecutor = (*ExperimentExecutor)(nil)
+
+func NewExperimentExecutor(recorder engine.ExperimentRecorder) *ExperimentExecutor {
+	return &ExperimentExecutor{
+		recorder: recorder,
+	}
+}
+
+func (e *ExperimentExecutor) Execute(ctx context.Context, node engine.WorkflowNode, input map[string]any) (*engine.NodeResult, error) {
+	startTime := time.Now()
+	result := &engine.NodeResult{
+		NodeID:    node.ID,
+		NodeName:  node.Name,
+		Timestamp: startTime,
+		Output:    make(map[string]any),
+	}
+	fail := func(err error) (*engine.NodeResult, error) {
+		result.Success = false
+		result.Error = err.Error()
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+
+	experimentConfig, err := engine.ExtractExperimentConfig(node.Config)
+	if err != nil {
+		return fail(fmt.Errorf("invalid experiment config: %w", err))
+	}
+
+	resolver := NewFieldResolver(input, node.Config, nil)
+	tenantID, err := resolver.GetTenantID()
+	if err != nil {
+		return fail(fmt.Errorf("tenant_id not found: %w", err))
+	}
+
+	contactID := resolver.RenderTemplate(experimentConfig.ContactID)
+	if contactID == "" {
+		contactID = resolver.GetString("sender_id", "")
+	}
+	if contactID == "" {
+		return fail(fmt.Errorf("an experiment needs a contact_id or the trigger's sender_id"))
+	}
+
+	event := engine.ExperimentEvent{
+		TenantID:   tenantID,
+		Experiment: experimentConfig.Experiment,
+		ContactID:  contactID,
+		ChannelID:  resolver.GetString("channel_id", ""),
+	}
+	if workflowID, err := resolver.GetWorkflowID(); err == nil {
+		event.WorkflowID = workflowID.String()
+	}
+
+	result.Success = true
+	result.Output["experiment"] = experimentConfig.Experiment
+	result.Output["contact_id"] = contactID
+
+	if experimentConfig.GetAction() == engine.ExperimentActionConvert {
+		event.Goal = experimentConfig.Goal
+		event.Value = experimentConfig.Value
+		result.Output["goal"] = event.Goal
+		result.Output["recorded"] = false
+
+		if e.recorder != nil {
+			variant, err := e.recorder.RecordConversion(ctx, event)
+			if err != nil {
+				log.Printf("⚠️  Failed to record conversion of %s in experiment %s: %v", contactID, event.Experiment, err)
+			} else {
+				result.Output["variant"] = variant
+				result.Output["recorded"] = variant != ""
+			}
+		}
+
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, nil
+	}
+
+	variant := engine.AssignVariant(tenantID, experimentConfig.Experiment, contactID, experimentConfig.Variants)
+	event.Variant = variant.Name
+	result.Output["variant"] = variant.Name
+
+	if e.recorder != nil {
+		if err := e.recorder.RecordExposure(ctx, event); err != nil {
+			log.Printf("⚠️  Failed to record exposure of %s in experiment %s: %v", contactID, event.Experiment, err)
+		}
+	}
+
+	if variant.NextNode != "" {
+		result.Output["next_node"] = variant.NextNode
+		// Store in context for workflow executor
+		input["__next_node"] = variant.NextNode
+	}
+
+	log.Printf("🧪 Contact %s in variant %s of experiment %s", contactID, variant.Name, experimentConfig.Experiment)
+
+	result.Duration = time.Since(startTime).Milliseconds()
+	return result, nil
+}
+
+func (e *ExperimentExecutor) SupportsType(nodeType engine.NodeType) bool {
+	return nodeType == engine.NodeTypeExperiment
+}
+
+func (e *ExperimentExecutor) ValidateConfig(config map[string]any) error {
+	_, err := engine.ExtractExperimentConfig(config)
+	return err
+}
@@ -11,18 +11,21 @@ import (
 	"time"
 
 	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/circuitbreaker"
 	"slices"
 )
 
 type HTTPExecutor struct {
 	httpClient *http.Client
 	evaluator  engine.ExpressionEvaluator
+	breakers   *circuitbreaker.Registry // One circuit per host; nil = disabled
 }
 
-func NewHTTPExecutor(evaluator engine.ExpressionEvaluator) *HTTPExecutor {
+func NewHTTPExecutor(evaluator engine.ExpressionEvaluator, breakers *circuitbreaker.Registry) *HTTPExecutor {
 	return &HTTPExecutor{
 		httpClient: &http.Client{Timeout: 60 * time.Second},
 		evaluator:  evaluator,
+		breakers:   breakers,
 	}
 }
 
@@ -90,8 +93,19 @@ func (e *HTTPExecutor) Execute(ctx context.Context, node engine.WorkflowNode, in
 		req.Header.Set("Content-Type", "application/json")
 	}
 
+	// A host that keeps failing is short-circuited instead of holding the
+	// executor for the full timeout on every call
+	breaker := e.breakers.Breaker("http:" + req.URL.Host)
+	if err := breaker.Allow(); err != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("circuit open for %s", req.URL.Host)
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+
 	// Execute request
 	resp, err := e.httpClient.Do(req)
+	breaker.Record((err != nil && ctx.Err() == nil) || (err == nil && resp.StatusCode >= 500))
 	if err != nil {
 		result.Success = false
 		result.Error = fmt.Sprintf("request failed: %v", err)
@@ -289,11 +289,12 @@ func (r *FieldResolver) GetWorkflowID() (kernel.WorkflowID, error) {
 // Template Rendering
 // ============================================================================
 
+// templatePlaceholderRegex matches {{path}} placeholders
+var templatePlaceholderRegex = regexp.MustCompile(`\{\{(.+?)\}\}`)
+
 // RenderTemplate renders template strings like {{trigger.body.name}}
 func (r *FieldResolver) RenderTemplate(template string) string {
-	re := regexp.MustCompile(`\{\{(.+?)\}\}`)
-
-	result := re.ReplaceAllStringFunc(template, func(match string) string {
+	result := templatePlaceholderRegex.ReplaceAllStringFunc(template, func(match string) string {
 		path := strings.TrimSpace(match[2 : len(match)-2])
 
 		// Resolve the path
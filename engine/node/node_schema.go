@@ -66,18 +66,34 @@ type Dependency struct {
 // All Node Schemas
 // ============================================================================
 
+// GetAllNodeSchemas returns the built-in schemas plus those of registered plugins
 func GetAllNodeSchemas() map[string]NodeConfigSchema {
+	all := GetBuiltinNodeSchemas()
+	for nodeType, schema := range pluginSchemas() {
+		all[nodeType] = schema
+	}
+	return all
+}
+
+func GetBuiltinNodeSchemas() map[string]NodeConfigSchema {
 	return map[string]NodeConfigSchema{
-		"AI_AGENT":     GetAIAgentSchema(),
-		"HTTP":         GetHTTPSchema(),
-		"SEND_MESSAGE": GetSendMessageSchema(),
-		"TRANSFORM":    GetTransformSchema(),
-		"CONDITION":    GetConditionSchema(),
-		"SWITCH":       GetSwitchSchema(),
-		"LOOP":         GetLoopSchema(),
-		"VALIDATE":     GetValidateSchema(),
-		"DELAY":        GetDelaySchema(),
-		"ACTION":       GetActionSchema(),
+		"AI_AGENT":       GetAIAgentSchema(),
+		"HTTP":           GetHTTPSchema(),
+		"SEND_MESSAGE":   GetSendMessageSchema(),
+		"TRANSFORM":      GetTransformSchema(),
+		"CONDITION":      GetConditionSchema(),
+		"SWITCH":         GetSwitchSchema(),
+		"LOOP":           GetLoopSchema(),
+		"VALIDATE":       GetValidateSchema(),
+		"DELAY":          GetDelaySchema(),
+		"ACTION":         GetActionSchema(),
+		"BUSINESS_HOURS": GetBusinessHoursSchema(),
+		"TAG":            GetTagSchema(),
+		"SURVEY":         GetSurveySchema(),
+		"EXPERIMENT":     GetExperimentSchema(),
+		"CRM_SYNC":       GetCRMSyncSchema(),
+		"TICKET":         GetTicketSchema(),
+		"ORDER_LOOKUP":   GetOrderLookupSchema(),
 	}
 }
 
@@ -183,6 +199,38 @@ func GetAIAgentSchema() NodeConfigSchema {
 					Value: true,
 				},
 			},
+			{
+				Name:         "stream",
+				Label:        "Stream Reply",
+				Type:         FieldTypeBoolean,
+				Required:     false,
+				DefaultValue: false,
+				Description:  "Send the reply to the conversation while it is generated, editing it in place on channels that support it",
+			},
+			{
+				Name:        "channel_id",
+				Label:       "Channel ID",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Channel the streamed reply is sent through",
+				Placeholder: "{{trigger.body.channel_id}}",
+				DependsOn: &Dependency{
+					Field: "stream",
+					Value: true,
+				},
+			},
+			{
+				Name:        "recipient_id",
+				Label:       "Recipient",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Recipient of the streamed reply (defaults to the sender)",
+				Placeholder: "{{trigger.body.sender_id}}",
+				DependsOn: &Dependency{
+					Field: "stream",
+					Value: true,
+				},
+			},
 		},
 	}
 }
@@ -306,6 +354,45 @@ func GetSendMessageSchema() NodeConfigSchema {
 				Description: "Message content (supports {{variables}})",
 				Placeholder: "Hello {{trigger.body.user_name}}, your order is ready!",
 			},
+			{
+				Name:        "snippet_id",
+				Label:       "Snippet",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Send a canned reply instead of text; edits to the snippet apply to every workflow using it",
+			},
+			{
+				Name:        "snippet_variables",
+				Label:       "Snippet Variables",
+				Type:        FieldTypeKeyValue,
+				Required:    false,
+				Description: "Values for the snippet's {{placeholders}} (supports {{variables}})",
+				Placeholder: "customer_name: {{trigger.sender.name}}",
+			},
+			{
+				Name:        "template_key",
+				Label:       "Message Template",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Send a localized message template instead of text; the variant is picked by the contact's locale",
+				Placeholder: "order.shipped",
+			},
+			{
+				Name:        "template_variables",
+				Label:       "Template Variables",
+				Type:        FieldTypeKeyValue,
+				Required:    false,
+				Description: "Values for the template's {arguments} (supports {{variables}})",
+				Placeholder: "count: {{trigger.body.items}}",
+			},
+			{
+				Name:        "locale",
+				Label:       "Locale",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Locale to render the template in; defaults to the sender's locale, then the template's default",
+				Placeholder: "{{trigger.sender.locale}}",
+			},
 			{
 				Name:         "message_type",
 				Label:        "Message Type",
@@ -319,8 +406,42 @@ func GetSendMessageSchema() NodeConfigSchema {
 					{Value: "document", Label: "Document"},
 					{Value: "audio", Label: "Audio"},
 					{Value: "video", Label: "Video"},
+					{Value: "location", Label: "Location"},
+					{Value: "contacts", Label: "Contact Cards"},
 				},
 			},
+			{
+				Name:        "location",
+				Label:       "Location Pin",
+				Type:        FieldTypeJSON,
+				Required:    false,
+				Description: "Send a location pin instead of text; text becomes optional",
+				Placeholder: "{\"latitude\": -12.0464, \"longitude\": -77.0428, \"name\": \"Store\", \"address\": \"Av. Larco 123\"}",
+			},
+			{
+				Name:        "contacts",
+				Label:       "Contact Cards",
+				Type:        FieldTypeArray,
+				Required:    false,
+				Description: "Share contact cards instead of text; text becomes optional",
+				Placeholder: "[{\"name\": \"Ana Torres\", \"phone_number\": \"+51987654321\", \"organization\": \"Sales\"}]",
+			},
+			{
+				Name:        "reply_to_message_id",
+				Label:       "Quote Message",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Send as a quoted reply to this message",
+				Placeholder: "{{trigger.message_id}}",
+			},
+			{
+				Name:        "interactive",
+				Label:       "Buttons or List",
+				Type:        FieldTypeJSON,
+				Required:    false,
+				Description: "Reply buttons or a list picker; the chosen option arrives as trigger.postback",
+				Placeholder: "{\"type\": \"list\", \"body\": \"Pick a store\", \"button_text\": \"Stores\", \"sections\": [{\"title\": \"Lima\", \"items\": [{\"id\": \"miraflores\", \"title\": \"Miraflores\"}]}]}",
+			},
 			{
 				Name:        "attachments",
 				Label:       "Attachments",
@@ -380,7 +501,40 @@ func GetConditionSchema() NodeConfigSchema {
 					{Value: "contains", Label: "Contains", Description: "Check if text contains substring"},
 					{Value: "exists", Label: "Exists", Description: "Check if field exists"},
 					{Value: "regex", Label: "Regex", Description: "Match regular expression"},
+					{Value: "not_equals", Label: "Not Equals", Description: "Check if values differ"},
+					{Value: "in", Label: "In", Description: "Check if value is one of a list"},
+					{Value: "not_in", Label: "Not In", Description: "Check if value is none of a list"},
+					{Value: "starts_with", Label: "Starts With", Description: "Check if text starts with a prefix"},
+					{Value: "ends_with", Label: "Ends With", Description: "Check if text ends with a suffix"},
+					{Value: "is_empty", Label: "Is Empty", Description: "Check if field is missing, null or blank"},
+					{Value: "greater_than", Label: "Greater Than", Description: "Compare numbers, dates or text"},
+					{Value: "greater_or_equal", Label: "Greater or Equal", Description: "Compare numbers, dates or text"},
+					{Value: "less_than", Label: "Less Than", Description: "Compare numbers, dates or text"},
+					{Value: "less_or_equal", Label: "Less or Equal", Description: "Compare numbers, dates or text"},
+					{Value: "group", Label: "Group", Description: "Combine several conditions with AND/OR"},
+				},
+			},
+			{
+				Name:         "logic",
+				Label:        "Group Logic",
+				Type:         FieldTypeSelect,
+				Required:     false,
+				DefaultValue: "and",
+				Description:  "How the conditions of the group are combined",
+				Options: []FieldOption{
+					{Value: "and", Label: "AND", Description: "All conditions must match"},
+					{Value: "or", Label: "OR", Description: "At least one condition must match"},
 				},
+				DependsOn: &Dependency{Field: "condition_type", Value: "group"},
+			},
+			{
+				Name:        "conditions",
+				Label:       "Conditions",
+				Type:        FieldTypeJSON,
+				Required:    false,
+				Description: "List of {field, operator, value, case_insensitive} or nested {logic, conditions}",
+				Placeholder: `[{"field": "trigger.text", "operator": "starts_with", "value": "hi"}, {"logic": "or", "conditions": [...]}]`,
+				DependsOn:   &Dependency{Field: "condition_type", Value: "group"},
 			},
 			{
 				Name:        "field",
@@ -571,6 +725,22 @@ func GetDelaySchema() NodeConfigSchema {
 				Description: "Delay in seconds",
 				Placeholder: "300",
 			},
+			{
+				Name:        "until",
+				Label:       "Until",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Wait until a timestamp or expression (e.g., {{next_business_day_at('09:00', trigger.metadata.timezone)}})",
+				Placeholder: "2025-01-02T09:00:00-05:00",
+			},
+			{
+				Name:        "timezone",
+				Label:       "Timezone",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Timezone of an until without offset",
+				Placeholder: "America/Lima",
+			},
 		},
 	}
 }
@@ -596,6 +766,8 @@ func GetActionSchema() NodeConfigSchema {
 				Options: []FieldOption{
 					{Value: "console_log", Label: "Console Log", Description: "Log to console"},
 					{Value: "set_context", Label: "Set Context", Description: "Set workflow variables"},
+					{Value: "set_variables", Label: "Set Variables", Description: "Set the workflow's declared context variables"},
+					{Value: "tag", Label: "Tag Conversation", Description: "Add tags or set the disposition"},
 				},
 			},
 			{
@@ -622,6 +794,42 @@ func GetActionSchema() NodeConfigSchema {
 					Value: "set_context",
 				},
 			},
+			{
+				Name:        "variables",
+				Label:       "Variables",
+				Type:        FieldTypeKeyValue,
+				Required:    false,
+				Description: "Declared variables to set, converted to their type (for set_variables)",
+				Placeholder: "order_total: {{trigger.body.total}}",
+				DependsOn: &Dependency{
+					Field: "action_type",
+					Value: "set_variables",
+				},
+			},
+			{
+				Name:        "tags",
+				Label:       "Tags",
+				Type:        FieldTypeArray,
+				Required:    false,
+				Description: "Tags to add (for tag)",
+				Placeholder: `["sales-lead"]`,
+				DependsOn: &Dependency{
+					Field: "action_type",
+					Value: "tag",
+				},
+			},
+			{
+				Name:        "disposition",
+				Label:       "Disposition",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Conversation outcome (for tag)",
+				Placeholder: "resolved",
+				DependsOn: &Dependency{
+					Field: "action_type",
+					Value: "tag",
+				},
+			},
 			{
 				Name:         "print_input",
 				Label:        "Print Input Data",
@@ -637,3 +845,463 @@ func GetActionSchema() NodeConfigSchema {
 		},
 	}
 }
+
+// ============================================================================
+// 11. BUSINESS_HOURS Schema
+// ============================================================================
+
+func GetBusinessHoursSchema() NodeConfigSchema {
+	return NodeConfigSchema{
+		NodeType:    "BUSINESS_HOURS",
+		DisplayName: "Business Hours",
+		Description: "Route depending on whether the tenant is open, closed or on holiday",
+		Icon:        "🕘",
+		Category:    "Logic",
+		Fields: []FieldSchema{
+			{
+				Name:        "open_node",
+				Label:       "Open Node",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Node ID to run during business hours (e.g. live handoff)",
+				Placeholder: "handoff_to_agent",
+			},
+			{
+				Name:        "closed_node",
+				Label:       "Closed Node",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Node ID to run outside business hours (e.g. leave a message)",
+				Placeholder: "leave_message",
+			},
+			{
+				Name:        "holiday_node",
+				Label:       "Holiday Node",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Node ID to run on holidays; uses the closed node when empty",
+				Placeholder: "holiday_message",
+			},
+		},
+	}
+}
+
+// ============================================================================
+// 12. TAG Schema
+// ============================================================================
+
+func GetTagSchema() NodeConfigSchema {
+	return NodeConfigSchema{
+		NodeType:    "TAG",
+		DisplayName: "Tag Conversation",
+		Description: "Label the conversation and record its outcome for filtering and reports",
+		Icon:        "🏷️",
+		Category:    "Data",
+		Fields: []FieldSchema{
+			{
+				Name:        "tags",
+				Label:       "Tags",
+				Type:        FieldTypeArray,
+				Required:    false,
+				Description: "Tags to add (supports {{variables}})",
+				Placeholder: `["sales-lead", "{{trigger.metadata.campaign}}"]`,
+			},
+			{
+				Name:        "remove",
+				Label:       "Remove Tags",
+				Type:        FieldTypeArray,
+				Required:    false,
+				Description: "Tags to remove before adding",
+				Placeholder: `["pending"]`,
+			},
+			{
+				Name:        "disposition",
+				Label:       "Disposition",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Conversation outcome; replaces any previous one",
+				Placeholder: "resolved",
+			},
+		},
+	}
+}
+
+// ============================================================================
+// 13. SURVEY Schema
+// ============================================================================
+
+func GetSurveySchema() NodeConfigSchema {
+	return NodeConfigSchema{
+		NodeType:    "SURVEY",
+		DisplayName: "Survey",
+		Description: "Ask the contact for a CSAT or NPS rating and wait for the answer",
+		Icon:        "⭐",
+		Category:    "Communication",
+		Fields: []FieldSchema{
+			{
+				Name:        "question",
+				Label:       "Question",
+				Type:        FieldTypeTextarea,
+				Required:    true,
+				Description: "Rating question sent to the contact (supports {{variables}})",
+				Placeholder: "How would you rate our service today?",
+			},
+			{
+				Name:         "scale",
+				Label:        "Scale",
+				Type:         FieldTypeSelect,
+				Required:     false,
+				Description:  "Rating the contact picks",
+				DefaultValue: "csat",
+				Options: []FieldOption{
+					{Value: "csat", Label: "CSAT", Description: "1 to 5, sent as a list of options"},
+					{Value: "nps", Label: "NPS", Description: "0 to 10, answered as a number"},
+				},
+			},
+			{
+				Name:        "invalid_reply",
+				Label:       "Invalid Reply Message",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Sent when the answer is not a score on the scale",
+				Placeholder: "Please answer with a number from 1 to 5",
+			},
+			{
+				Name:         "max_attempts",
+				Label:        "Max Attempts",
+				Type:         FieldTypeNumber,
+				Required:     false,
+				Description:  "Invalid answers tolerated before the survey is abandoned",
+				DefaultValue: 2,
+			},
+			{
+				Name:         "timeout_seconds",
+				Label:        "Timeout (seconds)",
+				Type:         FieldTypeNumber,
+				Required:     false,
+				Description:  "How long to wait for the answer",
+				DefaultValue: 86400,
+			},
+			{
+				Name:        "on_timeout",
+				Label:       "On Timeout",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Node run when no valid answer arrives; the workflow ends when empty",
+			},
+			{
+				Name:        "agent_id",
+				Label:       "Agent",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Operator credited with the score; defaults to the one who owns the conversation",
+			},
+		},
+	}
+}
+
+// ============================================================================
+// 14. EXPERIMENT Schema
+// ============================================================================
+
+func GetExperimentSchema() NodeConfigSchema {
+	return NodeConfigSchema{
+		NodeType:    "EXPERIMENT",
+		DisplayName: "A/B Experiment",
+		Description: "Split contacts into variants and record which ones convert",
+		Icon:        "🧪",
+		Category:    "Logic",
+		Fields: []FieldSchema{
+			{
+				Name:        "experiment",
+				Label:       "Experiment",
+				Type:        FieldTypeString,
+				Required:    true,
+				Description: "Key shared by the assign and convert nodes of one experiment",
+				Placeholder: "welcome-message-v2",
+			},
+			{
+				Name:         "action",
+				Label:        "Action",
+				Type:         FieldTypeSelect,
+				Required:     false,
+				Description:  "Split traffic or record a conversion",
+				DefaultValue: "assign",
+				Options: []FieldOption{
+					{Value: "assign", Label: "Assign", Description: "Route the contact to its variant"},
+					{Value: "convert", Label: "Convert", Description: "Record that the contact reached a goal"},
+				},
+			},
+			{
+				Name:        "variants",
+				Label:       "Variants",
+				Type:        FieldTypeArray,
+				Required:    false,
+				Description: "Variants with their percent of traffic (adding up to 100) and next node",
+				Placeholder: `[{"name": "control", "weight": 50, "next_node": "msg_a"}, {"name": "short", "weight": 50, "next_node": "msg_b"}]`,
+				DependsOn:   &Dependency{Field: "action", Value: "assign"},
+			},
+			{
+				Name:        "goal",
+				Label:       "Goal",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Conversion goal; empty for the experiment's main goal",
+				Placeholder: "purchase",
+				DependsOn:   &Dependency{Field: "action", Value: "convert"},
+			},
+			{
+				Name:        "value",
+				Label:       "Value",
+				Type:        FieldTypeNumber,
+				Required:    false,
+				Description: "Value of the conversion, e.g. the order amount",
+				DependsOn:   &Dependency{Field: "action", Value: "convert"},
+			},
+			{
+				Name:        "contact_id",
+				Label:       "Contact",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Who is split; defaults to the trigger's sender",
+				Placeholder: "{{trigger.body.sender_id}}",
+			},
+		},
+	}
+}
+
+// ============================================================================
+// 15. CRM_SYNC Schema
+// ============================================================================
+
+func GetCRMSyncSchema() NodeConfigSchema {
+	return NodeConfigSchema{
+		NodeType:    "CRM_SYNC",
+		DisplayName: "CRM Sync",
+		Description: "Upsert contacts, log activities and create deals or tickets in HubSpot or Salesforce",
+		Icon:        "🤝",
+		Category:    "Integration",
+		Fields: []FieldSchema{
+			{
+				Name:        "provider",
+				Label:       "CRM",
+				Type:        FieldTypeSelect,
+				Required:    false,
+				Description: "Connected CRM to write to; may be left empty when only one is connected",
+				Options: []FieldOption{
+					{Value: "hubspot", Label: "HubSpot"},
+					{Value: "salesforce", Label: "Salesforce"},
+				},
+			},
+			{
+				Name:        "operation",
+				Label:       "Operation",
+				Type:        FieldTypeSelect,
+				Required:    true,
+				Description: "What to write",
+				Options: []FieldOption{
+					{Value: "upsert_contact", Label: "Upsert contact", Description: "Create or update the contact matching the match field"},
+					{Value: "log_activity", Label: "Log activity", Description: "Note (HubSpot) or completed task (Salesforce)"},
+					{Value: "create_deal", Label: "Create deal", Description: "Deal (HubSpot) or opportunity (Salesforce)"},
+					{Value: "create_ticket", Label: "Create ticket", Description: "Ticket (HubSpot) or case (Salesforce)"},
+				},
+			},
+			{
+				Name:        "fields",
+				Label:       "Fields",
+				Type:        FieldTypeKeyValue,
+				Required:    true,
+				Description: "Relay fields (email, phone, first_name, subject, ...) or CRM properties; the tenant's field mapping translates relay fields",
+				Placeholder: `{"email": "{{trigger.body.email}}", "phone": "{{trigger.body.sender_id}}"}`,
+			},
+			{
+				Name:         "match_field",
+				Label:        "Match Field",
+				Type:         FieldTypeString,
+				Required:     false,
+				Description:  "Field identifying an existing contact",
+				DefaultValue: "email",
+				DependsOn:    &Dependency{Field: "operation", Value: "upsert_contact"},
+			},
+			{
+				Name:        "contact_id",
+				Label:       "Contact",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "CRM contact to link the activity, deal or ticket to",
+				Placeholder: "{{upsert_contact.output.record_id}}",
+			},
+		},
+	}
+}
+
+// ============================================================================
+// 16. TICKET Schema
+// ============================================================================
+
+func GetTicketSchema() NodeConfigSchema {
+	return NodeConfigSchema{
+		NodeType:    "TICKET",
+		DisplayName: "Helpdesk Ticket",
+		Description: "Open a Zendesk or Freshdesk ticket with the conversation transcript attached; later runs append the new messages to the same ticket",
+		Icon:        "🎫",
+		Category:    "Integration",
+		Fields: []FieldSchema{
+			{
+				Name:        "provider",
+				Label:       "Helpdesk",
+				Type:        FieldTypeSelect,
+				Required:    false,
+				Description: "Connected helpdesk to write to; may be left empty when only one is connected",
+				Options: []FieldOption{
+					{Value: "zendesk", Label: "Zendesk"},
+					{Value: "freshdesk", Label: "Freshdesk"},
+				},
+			},
+			{
+				Name:        "subject",
+				Label:       "Subject",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Subject of new tickets",
+				Placeholder: "Conversation with {{trigger.body.sender_id}}",
+			},
+			{
+				Name:        "comment",
+				Label:       "Comment",
+				Type:        FieldTypeTextarea,
+				Required:    false,
+				Description: "Written above the attached transcript",
+				Placeholder: "{{summarize.output.response}}",
+			},
+			{
+				Name:        "tags",
+				Label:       "Ticket Tags",
+				Type:        FieldTypeArray,
+				Required:    false,
+				Description: "Tags set on new tickets",
+			},
+			{
+				Name:        "priority",
+				Label:       "Priority",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "low, normal, high or urgent; the rules below can only raise it",
+				Placeholder: "normal",
+			},
+			{
+				Name:        "priority_by_tag",
+				Label:       "Priority by Tag",
+				Type:        FieldTypeKeyValue,
+				Required:    false,
+				Description: "Priority of conversations carrying a tag; the most urgent match wins",
+				Placeholder: `{"complaint": "high", "vip": "urgent"}`,
+			},
+			{
+				Name:        "sentiment",
+				Label:       "Sentiment",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Sentiment label of the conversation, e.g. produced by an AI agent node",
+				Placeholder: "{{classify.output.sentiment}}",
+			},
+			{
+				Name:        "priority_by_sentiment",
+				Label:       "Priority by Sentiment",
+				Type:        FieldTypeKeyValue,
+				Required:    false,
+				Description: "Priority for each sentiment label",
+				Placeholder: `{"negative": "high", "angry": "urgent"}`,
+			},
+			{
+				Name:        "requester_name",
+				Label:       "Requester Name",
+				Type:        FieldTypeString,
+				Required:    false,
+				Description: "Defaults to the conversation ID",
+			},
+			{
+				Name:        "requester_email",
+				Label:       "Requester Email",
+				Type:        FieldTypeString,
+				Required:    false,
+				Placeholder: "{{trigger.body.email}}",
+			},
+			{
+				Name:        "requester_phone",
+				Label:       "Requester Phone",
+				Type:        FieldTypeString,
+				Required:    false,
+				Placeholder: "{{trigger.body.sender_id}}",
+			},
+			{
+				Name:         "new_ticket",
+				Label:        "Always Open a New Ticket",
+				Type:         FieldTypeBoolean,
+				Required:     false,
+				DefaultValue: false,
+				Description:  "Open a new ticket instead of appending to the conversation's open one",
+			},
+		},
+	}
+}
+
+// ============================================================================
+// 17. ORDER_LOOKUP Schema
+// ============================================================================
+
+func GetOrderLookupSchema() NodeConfigSchema {
+	return NodeConfigSchema{
+		NodeType:    "ORDER_LOOKUP",
+		DisplayName: "Order Lookup",
+		Description: "Find an order's status by number, or a contact's recent orders by email or phone, in Shopify or WooCommerce",
+		Icon:        "📦",
+		Category:    "Integration",
+		Fields: []FieldSchema{
+			{
+				Name:        "provider",
+				Label:       "Store",
+				Type:        FieldTypeSelect,
+				Required:    false,
+				Description: "Connected store to search; may be left empty when only one is connected",
+				Options: []FieldOption{
+					{Value: "shopify", Label: "Shopify"},
+					{Value: "woocommerce", Label: "WooCommerce"},
+				},
+			},
+			{
+				Name:        "by",
+				Label:       "Look Up By",
+				Type:        FieldTypeSelect,
+				Required:    true,
+				Description: "What identifies the orders",
+				Options: []FieldOption{
+					{Value: "order_number", Label: "Order number", Description: "The number shown to the customer, e.g. #1001"},
+					{Value: "email", Label: "Email", Description: "The contact's recent orders"},
+					{Value: "phone", Label: "Phone", Description: "The contact's recent orders"},
+				},
+			},
+			{
+				Name:        "value",
+				Label:       "Value",
+				Type:        FieldTypeString,
+				Required:    true,
+				Description: "Order number, email or phone to look up",
+				Placeholder: "{{trigger.body.sender_id}}",
+			},
+			{
+				Name:         "limit",
+				Label:        "Recent Orders",
+				Type:         FieldTypeNumber,
+				Required:     false,
+				Description:  "How many of the contact's most recent orders to return",
+				DefaultValue: 5,
+				Validation: &Validation{
+					Min:     ptrx.Float32(1),
+					Max:     ptrx.Float32(50),
+					Message: "Recent orders must be between 1 and 50",
+				},
+			},
+		},
+	}
+}
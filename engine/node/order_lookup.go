@@ -0,0 +1,113 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+)
+
+// OrderLookupExecutor finds orders in the store the tenant connected, by
+// order number or by the contact's email or phone. Its output's order is the
+// most recent match, for answers like {{lookup.output.order.status}}.
+type OrderLookupExecutor struct {
+	looker engine.OrderLooker
+}
+
+var _ engine.NodeExecutor = (*OrderLookupExecutor)(nil)
+
+func NewOrderLookupExecutor(looker engine.OrderLooker) *OrderLookupExecutor {
+	return &OrderLookupExecutor{
+		looker: looker,
+	}
+}
+
+func (e *OrderLookupExecutor) Execute(ctx context.Context, node engine.WorkflowNode, input map[string]any) (*engine.NodeResult, error) {
+	startTime := time.Now()
+	result := &engine.NodeResult{
+		NodeID:    node.ID,
+		NodeName:  node.Name,
+		Timestamp: startTime,
+		Output:    make(map[string]any),
+	}
+	fail := func(err error) (*engine.NodeResult, error) {
+		result.Success = false
+		result.Error = err.Error()
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+
+	lookupConfig, err := engine.ExtractOrderLookupConfig(node.Config)
+	if err != nil {
+		return fail(fmt.Errorf("invalid order lookup config: %w", err))
+	}
+	if e.looker == nil {
+		return fail(fmt.Errorf("order lookup is not configured"))
+	}
+
+	resolver := NewFieldResolver(input, node.Config, nil)
+	tenantID, err := resolver.GetTenantID()
+	if err != nil {
+		return fail(fmt.Errorf("tenant_id not found: %w", err))
+	}
+
+	value := strings.TrimSpace(renderedOrEmpty(resolver, lookupConfig.Value))
+	if value == "" {
+		return fail(fmt.Errorf("%s to look up has no value", lookupConfig.By))
+	}
+
+	found, err := e.looker.LookupOrders(ctx, tenantID, engine.OrderLookupRequest{
+		Provider: lookupConfig.Provider,
+		By:       lookupConfig.By,
+		Value:    value,
+		Limit:    lookupConfig.GetLimit(),
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	// Orders are exposed as plain maps so templates can reach their fields
+	orders := make([]any, 0, len(found.Orders))
+	for _, order := range found.Orders {
+		fields, err := orderFields(order)
+		if err != nil {
+			return fail(err)
+		}
+		orders = append(orders, fields)
+	}
+
+	result.Success = true
+	result.Output["provider"] = found.Provider
+	result.Output["found"] = len(orders) > 0
+	result.Output["count"] = len(orders)
+	result.Output["orders"] = orders
+	if len(orders) > 0 {
+		result.Output["order"] = orders[0]
+	}
+	result.Duration = time.Since(startTime).Milliseconds()
+	return result, nil
+}
+
+func (e *OrderLookupExecutor) SupportsType(nodeType engine.NodeType) bool {
+	return nodeType == engine.NodeTypeOrderLookup
+}
+
+func (e *OrderLookupExecutor) ValidateConfig(config map[string]any) error {
+	_, err := engine.ExtractOrderLookupConfig(config)
+	return err
+}
+
+func orderFields(order engine.Order) (map[string]any, error) {
+	data, err := json.Marshal(order)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal order: %w", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal order: %w", err)
+	}
+	return fields, nil
+}
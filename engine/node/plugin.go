@@ -0,0 +1,79 @@
+package node
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Abraxas-365/relay/engine"
+)
+
+// ============================================================================
+// Custom Node Plugins
+// ============================================================================
+
+// PluginExecutor executes node types the engine does not ship
+type PluginExecutor interface {
+	engine.NodeExecutor
+	engine.NodeTypeDeclarer
+}
+
+var (
+	pluginsMu sync.RWMutex
+	plugins   []PluginExecutor
+	schemas   = make(map[string]NodeConfigSchema)
+)
+
+// RegisterPlugin makes a custom node type available to every workflow
+// executor built afterwards, usually from the plugin package's init. Each
+// declared type needs a schema so editors can discover it. Like
+// database/sql.Register it panics on invalid or duplicate types.
+func RegisterPlugin(executor PluginExecutor, nodeSchemas ...NodeConfigSchema) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+
+	bySchema := make(map[string]NodeConfigSchema, len(nodeSchemas))
+	for _, schema := range nodeSchemas {
+		bySchema[schema.NodeType] = schema
+	}
+
+	for _, nodeType := range executor.NodeTypes() {
+		if err := engine.ValidateCustomNodeType(nodeType); err != nil {
+			panic(fmt.Sprintf("node: RegisterPlugin: %v", err))
+		}
+		if _, dup := schemas[string(nodeType)]; dup {
+			panic(fmt.Sprintf("node: RegisterPlugin called twice for node type %s", nodeType))
+		}
+		if _, ok := bySchema[string(nodeType)]; !ok {
+			panic(fmt.Sprintf("node: RegisterPlugin: no schema for node type %s", nodeType))
+		}
+	}
+
+	for _, nodeType := range executor.NodeTypes() {
+		schemas[string(nodeType)] = bySchema[string(nodeType)]
+	}
+	plugins = append(plugins, executor)
+}
+
+// Plugins returns the registered plugin executors, in registration order
+func Plugins() []engine.NodeExecutor {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+
+	executors := make([]engine.NodeExecutor, len(plugins))
+	for i, plugin := range plugins {
+		executors[i] = plugin
+	}
+	return executors
+}
+
+// pluginSchemas returns a copy of the registered plugin schemas by node type
+func pluginSchemas() map[string]NodeConfigSchema {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+
+	copied := make(map[string]NodeConfigSchema, len(schemas))
+	for nodeType, schema := range schemas {
+		copied[nodeType] = schema
+	}
+	return copied
+}
@@ -2,10 +2,13 @@ package node
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/Abraxas-365/craftable/errx"
 	"github.com/Abraxas-365/relay/channels"
 	"github.com/Abraxas-365/relay/engine"
 	"github.com/Abraxas-365/relay/pkg/kernel"
@@ -14,15 +17,21 @@ import (
 type SendMessageExecutor struct {
 	channelManager channels.ChannelManager
 	evaluator      engine.ExpressionEvaluator
+	snippets       engine.SnippetProvider         // nil = snippet_id is rejected
+	templates      engine.MessageTemplateRenderer // nil = template_key is rejected
 }
 
 func NewSendMessageExecutor(
 	channelManager channels.ChannelManager,
 	evaluator engine.ExpressionEvaluator,
+	snippets engine.SnippetProvider,
+	templates engine.MessageTemplateRenderer,
 ) *SendMessageExecutor {
 	return &SendMessageExecutor{
 		channelManager: channelManager,
 		evaluator:      evaluator,
+		snippets:       snippets,
+		templates:      templates,
 	}
 }
 
@@ -68,26 +77,80 @@ func (e *SendMessageExecutor) Execute(ctx context.Context, node engine.WorkflowN
 		return result, fmt.Errorf("recipient_id required")
 	}
 
+	messageType := resolver.GetString("message_type", "text")
+
+	// Location pins and contact cards come from the node config only; the
+	// trigger's are the ones the user shared
+	location, err := parseLocation(resolver, node.Config["location"])
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+	contacts := parseContacts(resolver, node.Config["contacts"])
+	switch {
+	case location != nil:
+		messageType = "location"
+	case len(contacts) > 0:
+		messageType = "contacts"
+	case messageType == "location" || messageType == "contacts":
+		messageType = "text"
+	}
+
+	interactive, err := parseInteractive(resolver, node.Config["interactive"])
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+
 	text := resolver.GetString("text", "")
 	if text == "" {
 		text = resolver.GetString("message", "") // Try 'message' as fallback
 	}
-	if text == "" {
+	// A snippet replaces the inline text, so common replies live in one place
+	if snippetID := resolver.RenderTemplate(getStringFromMap(node.Config, "snippet_id", "")); snippetID != "" {
+		text, err = e.renderSnippet(ctx, resolver, tenantID, snippetID, node.Config["snippet_variables"])
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			result.Duration = time.Since(startTime).Milliseconds()
+			return result, err
+		}
+	}
+	// A template replaces the inline text in the contact's language
+	var templateLocale string
+	if templateKey := resolver.RenderTemplate(getStringFromMap(node.Config, "template_key", "")); templateKey != "" {
+		text, templateLocale, err = e.renderMessageTemplate(ctx, resolver, tenantID, templateKey, node.Config)
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			result.Duration = time.Since(startTime).Milliseconds()
+			return result, err
+		}
+	}
+	if interactive != nil && interactive.Body != "" {
+		text = interactive.Body
+	}
+	if text == "" && location == nil && len(contacts) == 0 {
 		result.Success = false
 		result.Error = "text is required"
 		result.Duration = time.Since(startTime).Milliseconds()
 		return result, fmt.Errorf("text required")
 	}
 
-	messageType := resolver.GetString("message_type", "text")
-
 	log.Printf("💬 Sending message to %s via channel %s", recipientID, channelIDStr)
 	log.Printf("   📝 Text: %s", truncateString(text, 50))
 
 	// Build message
 	messageContent := channels.MessageContent{
-		Type: messageType,
-		Text: text,
+		Type:        messageType,
+		Text:        text,
+		Location:    location,
+		Contacts:    contacts,
+		Interactive: interactive,
 	}
 
 	// Handle attachments
@@ -118,15 +181,27 @@ func (e *SendMessageExecutor) Execute(ctx context.Context, node engine.WorkflowN
 		RecipientID: recipientID,
 		Content:     messageContent,
 		Metadata: map[string]any{
+			"origin":             "workflow",
 			"workflow_node_id":   node.ID,
 			"workflow_node_name": node.Name,
 			"timestamp":          time.Now().Unix(),
 		},
 	}
 
+	// Quote a message, typically {{trigger.message_id}}
+	if replyToID := resolver.RenderTemplate(getStringFromMap(node.Config, "reply_to_message_id", "")); replyToID != "" {
+		outgoingMsg.ReplyToID = replyToID
+	}
+
+	// Attribute the message to its workflow for conversation transcripts
+	if workflowID, err := resolver.GetWorkflowID(); err == nil {
+		outgoingMsg.Metadata["workflow_id"] = workflowID.String()
+	}
+
 	if err := e.channelManager.SendMessage(ctx, tenantID, kernel.ChannelID(channelIDStr), outgoingMsg); err != nil {
 		result.Success = false
 		result.Error = fmt.Sprintf("failed to send message: %v", err)
+		result.Output["error"] = sendFailure(err, result.Error)
 		result.Duration = time.Since(startTime).Milliseconds()
 		return result, err
 	}
@@ -136,6 +211,9 @@ func (e *SendMessageExecutor) Execute(ctx context.Context, node engine.WorkflowN
 	result.Output["channel_id"] = channelIDStr
 	result.Output["recipient_id"] = recipientID
 	result.Output["message_text"] = text
+	if templateLocale != "" {
+		result.Output["template_locale"] = templateLocale
+	}
 	result.Duration = time.Since(startTime).Milliseconds()
 
 	log.Printf("✅ Message sent successfully")
@@ -151,6 +229,189 @@ func (e *SendMessageExecutor) ValidateConfig(config map[string]any) error {
 	return nil
 }
 
+// renderSnippet renders the referenced snippet, filling its variables from
+// the node's snippet_variables after rendering their templates
+func (e *SendMessageExecutor) renderSnippet(ctx context.Context, resolver *FieldResolver, tenantID kernel.TenantID, snippetID string, value any) (string, error) {
+	if e.snippets == nil {
+		return "", fmt.Errorf("snippets are not configured")
+	}
+
+	var variables map[string]string
+	if config, ok := value.(map[string]any); ok {
+		variables = make(map[string]string, len(config))
+		for key, val := range resolver.RenderMap(config) {
+			variables[key] = fmt.Sprint(val)
+		}
+	}
+
+	text, err := e.snippets.RenderSnippet(ctx, tenantID, snippetID, variables)
+	if err != nil {
+		return "", fmt.Errorf("failed to render snippet %s: %w", snippetID, err)
+	}
+	return text, nil
+}
+
+// renderMessageTemplate renders the referenced template in the contact's
+// locale, filling its arguments from template_variables after rendering
+// their templates
+func (e *SendMessageExecutor) renderMessageTemplate(ctx context.Context, resolver *FieldResolver, tenantID kernel.TenantID, key string, config map[string]any) (string, string, error) {
+	if e.templates == nil {
+		return "", "", fmt.Errorf("message templates are not configured")
+	}
+
+	var variables map[string]any
+	if values, ok := config["template_variables"].(map[string]any); ok {
+		variables = resolver.RenderMap(values)
+	}
+
+	text, locale, err := e.templates.RenderMessageTemplate(ctx, tenantID, key, contactLocale(resolver, config), variables)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to render message template %s: %w", key, err)
+	}
+	return text, locale, nil
+}
+
+// contactLocale is the locale a template is rendered in: the node's locale,
+// then the one the trigger reports for the sender. Empty uses the
+// template's default locale.
+func contactLocale(resolver *FieldResolver, config map[string]any) string {
+	if locale := resolver.RenderTemplate(getStringFromMap(config, "locale", "")); locale != "" {
+		return locale
+	}
+	for _, path := range []string{"trigger.sender.locale", "trigger.locale", "trigger.body.locale"} {
+		if locale, ok := resolver.GetNestedValue(path).(string); ok && locale != "" {
+			return locale
+		}
+	}
+	return ""
+}
+
+// sendFailure describes a failed send so OnFailure branches can tell
+// transient failures (retry later) from permanent ones
+func sendFailure(err error, message string) map[string]any {
+	class := channels.ClassifyError(err)
+	failure := map[string]any{
+		"type":          "channel",
+		"message":       message,
+		"failure_class": string(class),
+		"retryable":     class.IsRetryable(),
+	}
+
+	var e *errx.Error
+	if errors.As(err, &e) {
+		failure["code"] = e.Code
+		if retryAfter, ok := e.Details[channels.DetailRetryAfterSeconds]; ok {
+			failure["retry_after_seconds"] = retryAfter
+		}
+	}
+
+	return failure
+}
+
+// parseLocation reads a {latitude, longitude, name, address} pin, rendering
+// templated values
+func parseLocation(resolver *FieldResolver, value any) (*channels.Location, error) {
+	pin, ok := value.(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	pin = resolver.RenderMap(pin)
+
+	_, hasLat := pin["latitude"]
+	_, hasLng := pin["longitude"]
+	if !hasLat || !hasLng {
+		return nil, fmt.Errorf("location requires latitude and longitude")
+	}
+
+	location := &channels.Location{
+		Latitude:  toFloat64(pin["latitude"]),
+		Longitude: toFloat64(pin["longitude"]),
+		Name:      getStringFromMap(pin, "name", ""),
+		Address:   getStringFromMap(pin, "address", ""),
+	}
+	if location.Latitude < -90 || location.Latitude > 90 || location.Longitude < -180 || location.Longitude > 180 {
+		return nil, fmt.Errorf("location coordinates out of range")
+	}
+	return location, nil
+}
+
+// parseContacts reads contact cards ({name, phone_number, email,
+// organization, title, url}), rendering templated values. Cards without a
+// name are skipped.
+func parseContacts(resolver *FieldResolver, value any) []channels.Contact {
+	cards, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+
+	contacts := make([]channels.Contact, 0, len(cards))
+	for _, item := range cards {
+		card, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		card = resolver.RenderMap(card)
+
+		contact := channels.Contact{
+			Name:         getStringFromMap(card, "name", ""),
+			FirstName:    getStringFromMap(card, "first_name", ""),
+			LastName:     getStringFromMap(card, "last_name", ""),
+			PhoneNumber:  getStringFromMap(card, "phone_number", ""),
+			Email:        getStringFromMap(card, "email", ""),
+			Organization: getStringFromMap(card, "organization", ""),
+			Title:        getStringFromMap(card, "title", ""),
+			URL:          getStringFromMap(card, "url", ""),
+		}
+		if contact.Name == "" {
+			continue
+		}
+		contacts = append(contacts, contact)
+	}
+	return contacts
+}
+
+// parseInteractive reads reply buttons or a list picker
+// ({type, header, body, footer, buttons, items, sections, button_text}),
+// rendering templated text
+func parseInteractive(resolver *FieldResolver, value any) (*channels.Interactive, error) {
+	config, ok := value.(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interactive config: %w", err)
+	}
+	var interactive channels.Interactive
+	if err := json.Unmarshal(raw, &interactive); err != nil {
+		return nil, fmt.Errorf("invalid interactive config: %w", err)
+	}
+
+	interactive.Header = resolver.RenderTemplate(interactive.Header)
+	interactive.Body = resolver.RenderTemplate(interactive.Body)
+	interactive.Footer = resolver.RenderTemplate(interactive.Footer)
+	for i := range interactive.Buttons {
+		interactive.Buttons[i].Title = resolver.RenderTemplate(interactive.Buttons[i].Title)
+	}
+	renderItems := func(items []channels.Item) {
+		for i := range items {
+			items[i].Title = resolver.RenderTemplate(items[i].Title)
+			items[i].Description = resolver.RenderTemplate(items[i].Description)
+		}
+	}
+	renderItems(interactive.Items)
+	for i := range interactive.Sections {
+		interactive.Sections[i].Title = resolver.RenderTemplate(interactive.Sections[i].Title)
+		renderItems(interactive.Sections[i].Items)
+	}
+
+	if len(interactive.Buttons) == 0 && len(interactive.ListItems()) == 0 {
+		return nil, fmt.Errorf("interactive messages need buttons or list items")
+	}
+	return &interactive, nil
+}
+
 func getStringFromMap(m map[string]any, key, defaultValue string) string {
 	if val, ok := m[key].(string); ok {
 		return val
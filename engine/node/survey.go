@@ -0,0 +1,217 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// csatLabels describe each CSAT option, lowest first
+var csatLabels = []string{"Very dissatisfied", "Dissatisfied", "Neutral", "Satisfied", "Very satisfied"}
+
+// SurveyExecutor asks the contact for a rating and pauses the run until they
+// answer. The answer resumes the run at on_success with the score in the
+// node's output; without one, the timeout resumes it at on_timeout.
+type SurveyExecutor struct {
+	channelManager channels.ChannelManager
+	scheduler      engine.DelayScheduler
+	recorder       engine.SurveyRecorder
+}
+
+var _ engine.NodeExecutor = (*SurveyExecutor)(nil)
+
+func NewSurveyExecutor(
+	channelManager channels.ChannelManager,
+	scheduler engine.DelayScheduler,
+	recorder engine.SurveyRecorder,
+) *SurveyExecutor {
+	return &SurveyExecutor{
+		channelManager: channelManager,
+		scheduler:      scheduler,
+		recorder:       recorder,
+	}
+}
+
+func (e *SurveyExecutor) Execute(ctx context.Context, node engine.WorkflowNode, input map[string]any) (*engine.NodeResult, error) {
+	startTime := time.Now()
+	result := &engine.NodeResult{
+		NodeID:    node.ID,
+		NodeName:  node.Name,
+		Timestamp: startTime,
+		Output:    make(map[string]any),
+	}
+	fail := func(err error) (*engine.NodeResult, error) {
+		result.Success = false
+		result.Error = err.Error()
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+
+	if e.recorder == nil || e.scheduler == nil {
+		return fail(fmt.Errorf("surveys are not configured"))
+	}
+
+	surveyConfig, err := engine.ExtractSurveyConfig(node.Config)
+	if err != nil {
+		return fail(fmt.Errorf("invalid survey config: %w", err))
+	}
+
+	resolver := NewFieldResolver(input, node.Config, nil)
+	tenantID, err := resolver.GetTenantID()
+	if err != nil {
+		return fail(fmt.Errorf("tenant_id not found: %w", err))
+	}
+	workflowID, err := resolver.GetWorkflowID()
+	if err != nil {
+		return fail(fmt.Errorf("workflow_id not found: %w", err))
+	}
+
+	// The answer is matched by the contact it comes from
+	channelID := resolver.GetString("channel_id", "")
+	contactID := resolver.GetString("recipient_id", "")
+	if contactID == "" {
+		contactID = resolver.GetString("sender_id", "")
+	}
+	if channelID == "" || contactID == "" {
+		return fail(fmt.Errorf("a survey needs the trigger's channel_id and sender_id"))
+	}
+
+	scale := surveyConfig.GetScale()
+	question := resolver.RenderTemplate(surveyConfig.Question)
+
+	outgoingMsg := channels.OutgoingMessage{
+		RecipientID: contactID,
+		Content:     surveyQuestion(question, scale, surveyConfig.ButtonText),
+		Metadata: map[string]any{
+			"origin":             "workflow",
+			"workflow_id":        workflowID.String(),
+			"workflow_node_id":   node.ID,
+			"workflow_node_name": node.Name,
+			"timestamp":          time.Now().Unix(),
+		},
+	}
+	err = e.channelManager.SendMessage(ctx, tenantID, kernel.ChannelID(channelID), outgoingMsg)
+	if errx.IsCode(err, channels.CodeFeatureNotSupported) && outgoingMsg.Content.Interactive != nil {
+		// Channels without lists get the scores in the text
+		outgoingMsg.Content = channels.MessageContent{Type: "text", Text: question + "\n\n" + surveyScaleHint(scale)}
+		err = e.channelManager.SendMessage(ctx, tenantID, kernel.ChannelID(channelID), outgoingMsg)
+	}
+	if err != nil {
+		result.Output["error"] = sendFailure(err, fmt.Sprintf("failed to send survey: %v", err))
+		return fail(fmt.Errorf("failed to send survey: %w", err))
+	}
+
+	// The timeout resumes the run as if the node had finished unanswered
+	timeoutContext := engine.DeepCopyMap(input)
+	timeoutContext[node.ID] = map[string]any{
+		"output": map[string]any{
+			"scale":    string(scale),
+			"status":   "EXPIRED",
+			"answered": false,
+		},
+		"success": true,
+	}
+	continuation := &engine.WorkflowContinuation{
+		WorkflowID:  workflowID.String(),
+		TenantID:    tenantID.String(),
+		NodeID:      node.ID,
+		NextNodeID:  surveyConfig.OnTimeout,
+		NodeContext: timeoutContext,
+	}
+	if trigger, ok := input["trigger"].(map[string]any); ok {
+		continuation.ConversationID, _ = trigger["conversation_id"].(string)
+		continuation.MessageID, _ = trigger["message_id"].(string)
+		continuation.ChannelID, _ = trigger["channel_id"].(string)
+	}
+	if err := e.scheduler.Schedule(ctx, continuation, surveyConfig.Timeout()); err != nil {
+		return fail(fmt.Errorf("failed to schedule survey timeout: %w", err))
+	}
+
+	surveyID, err := e.recorder.OpenSurvey(ctx, engine.PendingSurvey{
+		TenantID:       tenantID,
+		WorkflowID:     workflowID.String(),
+		NodeID:         node.ID,
+		NextNodeID:     node.OnSuccess,
+		ChannelID:      kernel.ChannelID(channelID),
+		ConversationID: contactID,
+		AgentID:        resolver.RenderTemplate(surveyConfig.AgentID),
+		Scale:          scale,
+		Question:       question,
+		InvalidReply:   resolver.RenderTemplate(surveyConfig.InvalidReply),
+		MaxAttempts:    surveyConfig.Attempts(),
+		ContinuationID: continuation.ID,
+		ExpiresAt:      continuation.ScheduledFor,
+		NodeContext:    engine.DeepCopyMap(input),
+	})
+	if err != nil {
+		if cancelErr := e.scheduler.Cancel(ctx, continuation.ID); cancelErr != nil {
+			log.Printf("⚠️  Failed to cancel timeout of unrecorded survey: %v", cancelErr)
+		}
+		return fail(fmt.Errorf("failed to record survey: %w", err))
+	}
+
+	log.Printf("⭐ Survey %s sent to %s, waiting until %s", surveyID, contactID, continuation.ScheduledFor.Format(time.RFC3339))
+
+	result.Success = true
+	result.Output["survey_id"] = surveyID
+	result.Output["scale"] = string(scale)
+	result.Output["continuation_id"] = continuation.ID
+	result.Output["expires_at"] = continuation.ScheduledFor.Format(time.RFC3339)
+	result.Output["__workflow_paused"] = true // Resumed by the answer or the timeout
+	result.Duration = time.Since(startTime).Milliseconds()
+	return result, nil
+}
+
+func (e *SurveyExecutor) SupportsType(nodeType engine.NodeType) bool {
+	return nodeType == engine.NodeTypeSurvey
+}
+
+func (e *SurveyExecutor) ValidateConfig(config map[string]any) error {
+	_, err := engine.ExtractSurveyConfig(config)
+	return err
+}
+
+// surveyQuestion builds the question: CSAT offers its five scores as a list
+// (WhatsApp caps reply buttons at three), NPS's eleven are typed
+func surveyQuestion(question string, scale engine.SurveyScale, buttonText string) channels.MessageContent {
+	if scale == engine.SurveyScaleNPS {
+		return channels.MessageContent{Type: "text", Text: question}
+	}
+
+	if buttonText == "" {
+		buttonText = "Rate"
+	}
+	low, high := scale.Range()
+	items := make([]channels.Item, 0, high-low+1)
+	for score := low; score <= high; score++ {
+		items = append(items, channels.Item{
+			ID:          engine.SurveyPostbackPrefix + strconv.Itoa(score),
+			Title:       strconv.Itoa(score),
+			Description: csatLabels[score-low],
+		})
+	}
+
+	return channels.MessageContent{
+		Type: "text",
+		Text: question,
+		Interactive: &channels.Interactive{
+			Type:       "list",
+			Body:       question,
+			Items:      items,
+			ButtonText: buttonText,
+		},
+	}
+}
+
+// surveyScaleHint tells the contact how to answer in plain text
+func surveyScaleHint(scale engine.SurveyScale) string {
+	low, high := scale.Range()
+	return fmt.Sprintf("Reply with a number from %d to %d.", low, high)
+}
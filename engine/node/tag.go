@@ -0,0 +1,110 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// TagExecutor labels the conversation that triggered the workflow and sets
+// its disposition, for filtering transcripts and reporting outcomes
+type TagExecutor struct {
+	tagger engine.ConversationTagger
+}
+
+var _ engine.NodeExecutor = (*TagExecutor)(nil)
+
+func NewTagExecutor(tagger engine.ConversationTagger) *TagExecutor {
+	return &TagExecutor{
+		tagger: tagger,
+	}
+}
+
+func (e *TagExecutor) Execute(ctx context.Context, node engine.WorkflowNode, input map[string]any) (*engine.NodeResult, error) {
+	startTime := time.Now()
+	result := &engine.NodeResult{
+		NodeID:    node.ID,
+		NodeName:  node.Name,
+		Timestamp: startTime,
+		Output:    make(map[string]any),
+	}
+
+	tagConfig, err := engine.ExtractTagConfig(node.Config)
+	if err != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("invalid tag config: %v", err)
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+
+	tags, err := applyTags(ctx, e.tagger, NewFieldResolver(input, node.Config, nil), *tagConfig, "workflow")
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+
+	result.Success = true
+	result.Output["tags"] = tags.Tags
+	result.Output["disposition"] = tags.Disposition
+	result.Duration = time.Since(startTime).Milliseconds()
+	return result, nil
+}
+
+func (e *TagExecutor) SupportsType(nodeType engine.NodeType) bool {
+	return nodeType == engine.NodeTypeTag
+}
+
+func (e *TagExecutor) ValidateConfig(config map[string]any) error {
+	_, err := engine.ExtractTagConfig(config)
+	return err
+}
+
+// applyTags tags the trigger's conversation, rendering templated tag names.
+// The conversation is trigger.conversation_id, or the sender's when absent.
+func applyTags(ctx context.Context, tagger engine.ConversationTagger, resolver *FieldResolver, config engine.TagConfig, source string) (*engine.ConversationTags, error) {
+	if tagger == nil {
+		return nil, fmt.Errorf("conversation tagging is not configured")
+	}
+
+	tenantID, err := resolver.GetTenantID()
+	if err != nil {
+		return nil, fmt.Errorf("tenant_id not found: %w", err)
+	}
+
+	channelID := resolver.GetString("channel_id", "")
+	conversationID := resolver.GetString("conversation_id", "")
+	if conversationID == "" {
+		conversationID = resolver.GetString("sender_id", "")
+	}
+	if channelID == "" || conversationID == "" {
+		return nil, fmt.Errorf("tagging needs the trigger's channel_id and conversation_id")
+	}
+
+	update := engine.TagUpdate{
+		Add:         renderAll(resolver, config.Tags),
+		Remove:      renderAll(resolver, config.Remove),
+		Disposition: resolver.RenderTemplate(config.Disposition),
+		Source:      source,
+	}
+	if workflowID, err := resolver.GetWorkflowID(); err == nil {
+		update.SetBy = workflowID.String()
+	}
+
+	return tagger.TagConversation(ctx, tenantID, kernel.ChannelID(channelID), conversationID, update)
+}
+
+// renderAll renders templated values, dropping the ones left empty
+func renderAll(resolver *FieldResolver, values []string) []string {
+	rendered := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = resolver.RenderTemplate(value); value != "" {
+			rendered = append(rendered, value)
+		}
+	}
+	return rendered
+}
@@ -0,0 +1,108 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// TicketExecutor opens a helpdesk ticket for the conversation with its
+// transcript attached. The ticket is remembered per conversation, so later
+// executions append the new messages to it until it is closed.
+type TicketExecutor struct {
+	syncer engine.TicketSyncer
+}
+
+var _ engine.NodeExecutor = (*TicketExecutor)(nil)
+
+func NewTicketExecutor(syncer engine.TicketSyncer) *TicketExecutor {
+	return &TicketExecutor{
+		syncer: syncer,
+	}
+}
+
+func (e *TicketExecutor) Execute(ctx context.Context, node engine.WorkflowNode, input map[string]any) (*engine.NodeResult, error) {
+	startTime := time.Now()
+	result := &engine.NodeResult{
+		NodeID:    node.ID,
+		NodeName:  node.Name,
+		Timestamp: startTime,
+		Output:    make(map[string]any),
+	}
+	fail := func(err error) (*engine.NodeResult, error) {
+		result.Success = false
+		result.Error = err.Error()
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+
+	ticketConfig, err := engine.ExtractTicketConfig(node.Config)
+	if err != nil {
+		return fail(fmt.Errorf("invalid ticket config: %w", err))
+	}
+	if e.syncer == nil {
+		return fail(fmt.Errorf("helpdesk tickets are not configured"))
+	}
+
+	resolver := NewFieldResolver(input, node.Config, nil)
+	tenantID, err := resolver.GetTenantID()
+	if err != nil {
+		return fail(fmt.Errorf("tenant_id not found: %w", err))
+	}
+
+	channelID := resolver.GetString("channel_id", "")
+	conversationID := resolver.GetString("conversation_id", "")
+	if conversationID == "" {
+		conversationID = resolver.GetString("sender_id", "")
+	}
+	if channelID == "" || conversationID == "" {
+		return fail(fmt.Errorf("tickets need the trigger's channel_id and conversation_id"))
+	}
+
+	priority := engine.TicketPriority(renderedOrEmpty(resolver, ticketConfig.Priority))
+	if priority != "" && !priority.IsValid() {
+		return fail(fmt.Errorf("priority %q is not one of %v", priority, engine.TicketPriorities))
+	}
+
+	synced, err := e.syncer.SyncTicket(ctx, tenantID, engine.TicketRequest{
+		Provider:            ticketConfig.Provider,
+		ChannelID:           kernel.ChannelID(channelID),
+		ConversationID:      conversationID,
+		Subject:             renderedOrEmpty(resolver, ticketConfig.Subject),
+		Comment:             renderedOrEmpty(resolver, ticketConfig.Comment),
+		Tags:                renderAll(resolver, ticketConfig.Tags),
+		Priority:            priority,
+		PriorityByTag:       ticketConfig.PriorityByTag,
+		Sentiment:           renderedOrEmpty(resolver, ticketConfig.Sentiment),
+		PriorityBySentiment: ticketConfig.PriorityBySentiment,
+		RequesterName:       renderedOrEmpty(resolver, ticketConfig.RequesterName),
+		RequesterEmail:      renderedOrEmpty(resolver, ticketConfig.RequesterEmail),
+		RequesterPhone:      renderedOrEmpty(resolver, ticketConfig.RequesterPhone),
+		NewTicket:           ticketConfig.NewTicket,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	result.Success = true
+	result.Output["provider"] = synced.Provider
+	result.Output["ticket_id"] = synced.TicketID
+	result.Output["url"] = synced.URL
+	result.Output["created"] = synced.Created
+	result.Output["priority"] = string(synced.Priority)
+	result.Output["messages"] = synced.Messages
+	result.Duration = time.Since(startTime).Milliseconds()
+	return result, nil
+}
+
+func (e *TicketExecutor) SupportsType(nodeType engine.NodeType) bool {
+	return nodeType == engine.NodeTypeTicket
+}
+
+func (e *TicketExecutor) ValidateConfig(config map[string]any) error {
+	_, err := engine.ExtractTicketConfig(config)
+	return err
+}
@@ -15,6 +15,8 @@ type ValidateExecutor struct{}
 
 var _ engine.NodeExecutor = (*ValidateExecutor)(nil)
 
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
 func NewValidateExecutor() *ValidateExecutor {
 	return &ValidateExecutor{}
 }
@@ -96,7 +98,6 @@ func (e *ValidateExecutor) validateField(field string, value any, rule string) e
 			if !ok {
 				return fmt.Errorf("field '%s' must be a string for email validation", field)
 			}
-			emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 			if !emailRegex.MatchString(str) {
 				return fmt.Errorf("field '%s' must be a valid email", field)
 			}
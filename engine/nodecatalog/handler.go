@@ -0,0 +1,68 @@
+package nodecatalog
+
+import (
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/engine/node"
+	"github.com/gofiber/fiber/v2"
+)
+
+// NodeTypeInfo is a node type workflows can use, with its config schema
+type NodeTypeInfo struct {
+	NodeType string                 `json:"node_type"`
+	Builtin  bool                   `json:"builtin"`
+	Schema   *node.NodeConfigSchema `json:"schema,omitempty"` // nil = the executor ships no schema
+}
+
+// NodeCatalogHandler lists the node types the workflow executor can run,
+// built-in and custom, so editors discover plugins without a release
+type NodeCatalogHandler struct {
+	registry engine.NodeTypeDeclarer
+}
+
+func NewNodeCatalogHandler(registry engine.NodeTypeDeclarer) *NodeCatalogHandler {
+	return &NodeCatalogHandler{
+		registry: registry,
+	}
+}
+
+// List returns every registered node type, sorted
+// GET /api/node-types
+func (h *NodeCatalogHandler) List(c *fiber.Ctx) error {
+	schemas := node.GetAllNodeSchemas()
+
+	nodeTypes := h.registry.NodeTypes()
+	infos := make([]NodeTypeInfo, 0, len(nodeTypes))
+	for _, nodeType := range nodeTypes {
+		infos = append(infos, describe(nodeType, schemas))
+	}
+
+	return c.JSON(fiber.Map{
+		"node_types": infos,
+		"total":      len(infos),
+	})
+}
+
+// Get returns one registered node type
+// GET /api/node-types/:type
+func (h *NodeCatalogHandler) Get(c *fiber.Ctx) error {
+	nodeType := engine.NodeType(c.Params("type"))
+
+	for _, registered := range h.registry.NodeTypes() {
+		if registered == nodeType {
+			return c.JSON(describe(nodeType, node.GetAllNodeSchemas()))
+		}
+	}
+
+	return engine.ErrNodeTypeNotFound().WithDetail("node_type", string(nodeType))
+}
+
+func describe(nodeType engine.NodeType, schemas map[string]node.NodeConfigSchema) NodeTypeInfo {
+	info := NodeTypeInfo{
+		NodeType: string(nodeType),
+		Builtin:  nodeType.IsBuiltin(),
+	}
+	if schema, ok := schemas[string(nodeType)]; ok {
+		info.Schema = &schema
+	}
+	return info
+}
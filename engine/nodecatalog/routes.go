@@ -0,0 +1,26 @@
+package nodecatalog
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+type NodeCatalogRoutes struct {
+	handler        *NodeCatalogHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+func NewNodeCatalogRoutes(handler *NodeCatalogHandler, authMiddleware *auth.AuthMiddleware) *NodeCatalogRoutes {
+	return &NodeCatalogRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers node type discovery routes on an authenticated router
+func (r *NodeCatalogRoutes) RegisterRoutes(router fiber.Router) {
+	nodeTypes := router.Group("/node-types")
+
+	nodeTypes.Get("/", r.handler.List)
+	nodeTypes.Get("/:type", r.handler.Get)
+}
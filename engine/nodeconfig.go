@@ -3,6 +3,10 @@ package engine
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/Abraxas-365/craftable/ai/llm"
 	"github.com/Abraxas-365/craftable/ai/providers/aiopenai"
@@ -36,6 +40,7 @@ type AIAgentConfig struct {
 	Tools              []string       `json:"tools,omitempty"`
 	MaxAutoIterations  *int           `json:"max_auto_iterations,omitempty"`
 	MaxTotalIterations *int           `json:"max_total_iterations,omitempty"`
+	Stream             bool           `json:"stream,omitempty"` // Deliver the reply to the conversation as it is generated
 	Metadata           map[string]any `json:"metadata,omitempty"`
 }
 
@@ -191,6 +196,148 @@ func (c HTTPConfig) GetMaxRetries() int {
 	return 0 // No retries by default
 }
 
+// ============================================================================
+// Condition Config
+// ============================================================================
+
+// ConditionOperator compares a field against a value
+type ConditionOperator string
+
+const (
+	OperatorEquals     ConditionOperator = "equals"
+	OperatorNotEquals  ConditionOperator = "not_equals"
+	OperatorContains   ConditionOperator = "contains"
+	OperatorIn         ConditionOperator = "in"
+	OperatorNotIn      ConditionOperator = "not_in"
+	OperatorStartsWith ConditionOperator = "starts_with"
+	OperatorEndsWith   ConditionOperator = "ends_with"
+	OperatorRegex      ConditionOperator = "regex"
+	OperatorExists     ConditionOperator = "exists"
+	OperatorIsEmpty    ConditionOperator = "is_empty"
+
+	// Ordering: numbers numerically, timestamps chronologically, else as text
+	OperatorGreaterThan    ConditionOperator = "greater_than"
+	OperatorGreaterOrEqual ConditionOperator = "greater_or_equal"
+	OperatorLessThan       ConditionOperator = "less_than"
+	OperatorLessOrEqual    ConditionOperator = "less_or_equal"
+)
+
+// ConditionLogic combines the conditions of a group
+type ConditionLogic string
+
+const (
+	LogicAnd ConditionLogic = "and"
+	LogicOr  ConditionLogic = "or"
+)
+
+// maxConditionDepth bounds group nesting
+const maxConditionDepth = 10
+
+// ConditionConfig is the grouped form of a CONDITION node:
+//
+//	{"logic": "or", "conditions": [
+//	    {"field": "trigger.text", "operator": "starts_with", "value": "hi"},
+//	    {"logic": "and", "conditions": [...]}
+//	]}
+type ConditionConfig struct {
+	Logic      ConditionLogic `json:"logic,omitempty"` // and (default) | or
+	Conditions []Condition    `json:"conditions"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// Condition is either a single comparison (Field/Operator/Value) or a nested
+// group (Logic/Conditions)
+type Condition struct {
+	Field           string            `json:"field,omitempty"`
+	Operator        ConditionOperator `json:"operator,omitempty"`
+	Value           any               `json:"value,omitempty"`
+	CaseInsensitive bool              `json:"case_insensitive,omitempty"`
+
+	Logic      ConditionLogic `json:"logic,omitempty"`
+	Conditions []Condition    `json:"conditions,omitempty"`
+}
+
+func (c ConditionConfig) Validate() error {
+	return Condition{Logic: c.Logic, Conditions: c.Conditions}.validate(0)
+}
+
+func (c ConditionConfig) GetType() NodeType {
+	return NodeTypeCondition
+}
+
+func (c ConditionConfig) GetTimeout() int {
+	return 5 // Fast operation
+}
+
+// AsGroup returns the top level of the config as a group condition
+func (c ConditionConfig) AsGroup() Condition {
+	return Condition{Logic: c.Logic, Conditions: c.Conditions}
+}
+
+// IsGroup reports whether the condition nests other conditions
+func (c Condition) IsGroup() bool {
+	return len(c.Conditions) > 0 || c.Logic != ""
+}
+
+// GetLogic returns the group logic with default
+func (c Condition) GetLogic() ConditionLogic {
+	if c.Logic == "" {
+		return LogicAnd
+	}
+	return c.Logic
+}
+
+func (c Condition) validate(depth int) error {
+	if depth > maxConditionDepth {
+		return ErrInvalidWorkflowNode().WithDetail("reason", fmt.Sprintf("conditions cannot be nested more than %d levels", maxConditionDepth))
+	}
+
+	if c.IsGroup() {
+		if logic := c.GetLogic(); logic != LogicAnd && logic != LogicOr {
+			return ErrInvalidWorkflowNode().WithDetail("reason", fmt.Sprintf("invalid logic '%s', expected 'and' or 'or'", c.Logic))
+		}
+		if len(c.Conditions) == 0 {
+			return ErrInvalidWorkflowNode().WithDetail("reason", "conditions cannot be empty")
+		}
+		for _, child := range c.Conditions {
+			if err := child.validate(depth + 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if c.Field == "" {
+		return ErrInvalidWorkflowNode().WithDetail("reason", "condition field is required")
+	}
+
+	switch c.Operator {
+	case OperatorExists, OperatorIsEmpty:
+		return nil
+	case OperatorEquals, OperatorNotEquals, OperatorContains, OperatorStartsWith, OperatorEndsWith,
+		OperatorGreaterThan, OperatorGreaterOrEqual, OperatorLessThan, OperatorLessOrEqual:
+		if c.Value == nil {
+			return ErrInvalidWorkflowNode().WithDetail("reason", fmt.Sprintf("value is required for operator '%s'", c.Operator))
+		}
+	case OperatorIn, OperatorNotIn:
+		if _, ok := c.Value.([]any); !ok {
+			return ErrInvalidWorkflowNode().WithDetail("reason", fmt.Sprintf("operator '%s' requires a list value", c.Operator))
+		}
+	case OperatorRegex:
+		pattern, ok := c.Value.(string)
+		if !ok {
+			return ErrInvalidWorkflowNode().WithDetail("reason", "operator 'regex' requires a pattern string")
+		}
+		if err := ValidateRegex(pattern); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidWorkflowNode().WithDetail("reason", fmt.Sprintf("unknown operator '%s'", c.Operator))
+	}
+
+	return nil
+}
+
 // ============================================================================
 // Switch Config
 // ============================================================================
@@ -342,6 +489,348 @@ func (c ValidateConfig) ShouldFailOnError() bool {
 	return c.FailOnError // Default is false (allow workflow to continue)
 }
 
+// ============================================================================
+// Business Hours Config
+// ============================================================================
+
+type BusinessHoursConfig struct {
+	OpenNode    string         `json:"open_node,omitempty"`    // Node ID when open (e.g. live handoff)
+	ClosedNode  string         `json:"closed_node,omitempty"`  // Node ID when closed (e.g. leave a message)
+	HolidayNode string         `json:"holiday_node,omitempty"` // Node ID on holidays, falls back to closed_node
+	Metadata    map[string]any `json:"metadata,omitempty"`
+}
+
+func (c BusinessHoursConfig) Validate() error {
+	if c.OpenNode == "" && c.ClosedNode == "" && c.HolidayNode == "" {
+		return ErrInvalidWorkflowNode().WithDetail("reason", "at least one of open_node, closed_node or holiday_node is required")
+	}
+	return nil
+}
+
+func (c BusinessHoursConfig) GetType() NodeType {
+	return NodeTypeBusinessHours
+}
+
+func (c BusinessHoursConfig) GetTimeout() int {
+	return 5 // Fast operation
+}
+
+// NextNode returns the branch to follow for a status; empty means continue
+// with on_success
+func (c BusinessHoursConfig) NextNode(status BusinessHoursStatus) string {
+	switch {
+	case status.Open:
+		return c.OpenNode
+	case status.Holiday && c.HolidayNode != "":
+		return c.HolidayNode
+	default:
+		return c.ClosedNode
+	}
+}
+
+// ============================================================================
+// Tag Config
+// ============================================================================
+
+type TagConfig struct {
+	Tags        []string       `json:"tags,omitempty"`        // Added; may use {{variables}}
+	Remove      []string       `json:"remove,omitempty"`      // Removed before adding
+	Disposition string         `json:"disposition,omitempty"` // Replaces the conversation's outcome
+	Metadata    map[string]any `json:"metadata,omitempty"`
+}
+
+func (c TagConfig) Validate() error {
+	if len(c.Tags) == 0 && len(c.Remove) == 0 && c.Disposition == "" {
+		return ErrInvalidWorkflowNode().WithDetail("reason", "at least one of tags, remove or disposition is required")
+	}
+	return nil
+}
+
+func (c TagConfig) GetType() NodeType {
+	return NodeTypeTag
+}
+
+func (c TagConfig) GetTimeout() int {
+	return 5 // Fast operation
+}
+
+// ============================================================================
+// Survey Config
+// ============================================================================
+
+type SurveyConfig struct {
+	Question       string         `json:"question"`                  // May use {{variables}}
+	Scale          SurveyScale    `json:"scale,omitempty"`           // csat (default) or nps
+	ButtonText     string         `json:"button_text,omitempty"`     // Opens the CSAT option list
+	InvalidReply   string         `json:"invalid_reply,omitempty"`   // Sent when the answer is off the scale
+	MaxAttempts    int            `json:"max_attempts,omitempty"`    // Invalid answers tolerated, default 2
+	TimeoutSeconds int            `json:"timeout_seconds,omitempty"` // Default 24 hours
+	OnTimeout      string         `json:"on_timeout,omitempty"`      // Node run without an answer; empty ends the run
+	AgentID        string         `json:"agent_id,omitempty"`        // Operator credited with the score
+	Metadata       map[string]any `json:"metadata,omitempty"`
+}
+
+func (c SurveyConfig) Validate() error {
+	if c.Question == "" {
+		return ErrInvalidWorkflowNode().WithDetail("reason", "question is required")
+	}
+	if c.Scale != "" && !c.Scale.IsValid() {
+		return ErrInvalidWorkflowNode().WithDetail("reason", "scale must be csat or nps")
+	}
+	if c.MaxAttempts < 0 {
+		return ErrInvalidWorkflowNode().WithDetail("reason", "max_attempts cannot be negative")
+	}
+	if c.TimeoutSeconds < 0 || c.Timeout() > MaxSurveyTimeout {
+		return ErrInvalidWorkflowNode().
+			WithDetail("reason", fmt.Sprintf("timeout_seconds must be between 0 and %d", int(MaxSurveyTimeout.Seconds())))
+	}
+	return nil
+}
+
+func (c SurveyConfig) GetType() NodeType {
+	return NodeTypeSurvey
+}
+
+func (c SurveyConfig) GetTimeout() int {
+	return 30 // Sends one message
+}
+
+// GetScale is the configured scale, CSAT by default
+func (c SurveyConfig) GetScale() SurveyScale {
+	if c.Scale == "" {
+		return SurveyScaleCSAT
+	}
+	return c.Scale
+}
+
+// Timeout is how long to wait for the answer
+func (c SurveyConfig) Timeout() time.Duration {
+	if c.TimeoutSeconds == 0 {
+		return DefaultSurveyTimeout
+	}
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// Attempts is how many invalid answers are tolerated
+func (c SurveyConfig) Attempts() int {
+	if c.MaxAttempts == 0 {
+		return DefaultSurveyAttempts
+	}
+	return c.MaxAttempts
+}
+
+// ============================================================================
+// Experiment Config
+// ============================================================================
+
+const (
+	ExperimentActionAssign  = "assign"  // Route the contact to its variant
+	ExperimentActionConvert = "convert" // Record that the contact reached a goal
+)
+
+type ExperimentConfig struct {
+	Experiment string              `json:"experiment"`           // Key shared by the nodes of one experiment
+	Action     string              `json:"action,omitempty"`     // assign (default) or convert
+	Variants   []ExperimentVariant `json:"variants,omitempty"`   // assign
+	Goal       string              `json:"goal,omitempty"`       // convert
+	Value      float64             `json:"value,omitempty"`      // convert
+	ContactID  string              `json:"contact_id,omitempty"` // Defaults to the trigger's sender
+	Metadata   map[string]any      `json:"metadata,omitempty"`
+}
+
+func (c ExperimentConfig) Validate() error {
+	if c.Experiment == "" {
+		return ErrInvalidWorkflowNode().WithDetail("reason", "experiment is required")
+	}
+
+	switch c.GetAction() {
+	case ExperimentActionConvert:
+		return nil
+	case ExperimentActionAssign:
+	default:
+		return ErrInvalidWorkflowNode().WithDetail("reason", "action must be assign or convert")
+	}
+
+	if len(c.Variants) < 2 {
+		return ErrInvalidWorkflowNode().WithDetail("reason", "an experiment needs at least two variants")
+	}
+	names := make(map[string]bool, len(c.Variants))
+	var total float64
+	for _, variant := range c.Variants {
+		if variant.Name == "" || names[variant.Name] {
+			return ErrInvalidWorkflowNode().WithDetail("reason", "variant names are required and must be unique")
+		}
+		names[variant.Name] = true
+		if variant.Weight < 0 {
+			return ErrInvalidWorkflowNode().
+				WithDetail("variant", variant.Name).
+				WithDetail("reason", "weight cannot be negative")
+		}
+		total += variant.Weight
+	}
+	if math.Abs(total-100) > 0.001 {
+		return ErrInvalidWorkflowNode().
+			WithDetail("reason", fmt.Sprintf("variant weights must add up to 100, got %g", total))
+	}
+	return nil
+}
+
+func (c ExperimentConfig) GetType() NodeType {
+	return NodeTypeExperiment
+}
+
+func (c ExperimentConfig) GetTimeout() int {
+	return 5 // Fast operation
+}
+
+// GetAction is the configured action, assign by default
+func (c ExperimentConfig) GetAction() string {
+	if c.Action == "" {
+		return ExperimentActionAssign
+	}
+	return c.Action
+}
+
+// ============================================================================
+// CRM Sync Config
+// ============================================================================
+
+type CRMSyncConfig struct {
+	Provider   string            `json:"provider,omitempty"` // hubspot or salesforce; empty = the tenant's only connection
+	Operation  CRMOperation      `json:"operation"`
+	Fields     map[string]string `json:"fields"`                // Relay field or CRM property → value; may use {{variables}}
+	MatchField string            `json:"match_field,omitempty"` // upsert_contact; defaults to email
+	ContactID  string            `json:"contact_id,omitempty"`  // CRM contact to link, e.g. {{upsert_contact.output.record_id}}
+}
+
+func (c CRMSyncConfig) Validate() error {
+	if !slices.Contains(CRMOperations, c.Operation) {
+		return ErrInvalidWorkflowNode().
+			WithDetail("field", "operation").
+			WithDetail("allowed", CRMOperations)
+	}
+	if len(c.Fields) == 0 {
+		return ErrInvalidWorkflowNode().WithDetail("reason", "fields are required")
+	}
+	if c.Operation == CRMUpsertContact {
+		if _, ok := c.Fields[c.GetMatchField()]; !ok {
+			return ErrInvalidWorkflowNode().
+				WithDetail("field", "match_field").
+				WithDetail("reason", "the match field must be one of fields")
+		}
+	}
+	return nil
+}
+
+func (c CRMSyncConfig) GetType() NodeType {
+	return NodeTypeCRMSync
+}
+
+func (c CRMSyncConfig) GetTimeout() int {
+	return 30 // Up to two API calls
+}
+
+// GetMatchField is the field identifying the contact, email by default
+func (c CRMSyncConfig) GetMatchField() string {
+	if c.MatchField == "" {
+		return DefaultCRMMatchField
+	}
+	return c.MatchField
+}
+
+// ============================================================================
+// Ticket Config
+// ============================================================================
+
+type TicketConfig struct {
+	Provider            string                    `json:"provider,omitempty"` // zendesk or freshdesk; empty = the tenant's only connection
+	Subject             string                    `json:"subject,omitempty"`  // May use {{variables}}
+	Comment             string                    `json:"comment,omitempty"`  // Written above the transcript; may use {{variables}}
+	Tags                []string                  `json:"tags,omitempty"`
+	Priority            string                    `json:"priority,omitempty"`              // low, normal, high, urgent; may use {{variables}}
+	PriorityByTag       map[string]TicketPriority `json:"priority_by_tag,omitempty"`       // Conversation tag → priority
+	Sentiment           string                    `json:"sentiment,omitempty"`             // Label, e.g. {{classify.output.sentiment}}
+	PriorityBySentiment map[string]TicketPriority `json:"priority_by_sentiment,omitempty"` // Sentiment label → priority
+	RequesterName       string                    `json:"requester_name,omitempty"`
+	RequesterEmail      string                    `json:"requester_email,omitempty"`
+	RequesterPhone      string                    `json:"requester_phone,omitempty"`
+	NewTicket           bool                      `json:"new_ticket,omitempty"` // Open a new ticket even when the conversation has one
+}
+
+func (c TicketConfig) Validate() error {
+	if c.Priority != "" && !strings.Contains(c.Priority, "{{") && !TicketPriority(c.Priority).IsValid() {
+		return ErrInvalidWorkflowNode().
+			WithDetail("field", "priority").
+			WithDetail("allowed", TicketPriorities)
+	}
+	for field, rules := range map[string]map[string]TicketPriority{
+		"priority_by_tag":       c.PriorityByTag,
+		"priority_by_sentiment": c.PriorityBySentiment,
+	} {
+		for match, priority := range rules {
+			if !priority.IsValid() {
+				return ErrInvalidWorkflowNode().
+					WithDetail("field", field).
+					WithDetail("match", match).
+					WithDetail("allowed", TicketPriorities)
+			}
+		}
+	}
+	return nil
+}
+
+func (c TicketConfig) GetType() NodeType {
+	return NodeTypeTicket
+}
+
+func (c TicketConfig) GetTimeout() int {
+	return 60 // Reads the transcript, uploads it and writes the ticket
+}
+
+// ============================================================================
+// Order Lookup Config
+// ============================================================================
+
+type OrderLookupConfig struct {
+	Provider string        `json:"provider,omitempty"` // shopify or woocommerce; empty = the tenant's only connection
+	By       OrderLookupBy `json:"by"`
+	Value    string        `json:"value"`           // May use {{variables}}, e.g. {{trigger.body.sender_id}}
+	Limit    int           `json:"limit,omitempty"` // Recent orders returned; defaults to 5
+}
+
+func (c OrderLookupConfig) Validate() error {
+	if !slices.Contains(OrderLookups, c.By) {
+		return ErrInvalidWorkflowNode().
+			WithDetail("field", "by").
+			WithDetail("allowed", OrderLookups)
+	}
+	if c.Value == "" {
+		return ErrInvalidWorkflowNode().WithDetail("reason", "value is required")
+	}
+	if c.Limit < 0 || c.Limit > MaxOrderLimit {
+		return ErrInvalidWorkflowNode().
+			WithDetail("field", "limit").
+			WithDetail("max", MaxOrderLimit)
+	}
+	return nil
+}
+
+func (c OrderLookupConfig) GetType() NodeType {
+	return NodeTypeOrderLookup
+}
+
+func (c OrderLookupConfig) GetTimeout() int {
+	return 30 // Up to two API calls
+}
+
+// GetLimit is the number of recent orders returned, DefaultOrderLimit by default
+func (c OrderLookupConfig) GetLimit() int {
+	if c.Limit == 0 {
+		return DefaultOrderLimit
+	}
+	return c.Limit
+}
+
 // ============================================================================
 // Helper Functions for Config Extraction
 // ============================================================================
@@ -385,6 +874,25 @@ func ExtractHTTPConfig(config map[string]any) (*HTTPConfig, error) {
 	return &httpConfig, nil
 }
 
+// ExtractConditionConfig extracts and validates grouped condition config
+func ExtractConditionConfig(config map[string]any) (*ConditionConfig, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var conditionConfig ConditionConfig
+	if err := json.Unmarshal(data, &conditionConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal condition config: %w", err)
+	}
+
+	if err := conditionConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &conditionConfig, nil
+}
+
 // ExtractSwitchConfig extracts and validates switch config
 func ExtractSwitchConfig(config map[string]any) (*SwitchConfig, error) {
 	data, err := json.Marshal(config)
@@ -460,3 +968,136 @@ func ExtractValidateConfig(config map[string]any) (*ValidateConfig, error) {
 
 	return &validateConfig, nil
 }
+
+// ExtractBusinessHoursConfig extracts and validates business hours config
+func ExtractBusinessHoursConfig(config map[string]any) (*BusinessHoursConfig, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var businessHoursConfig BusinessHoursConfig
+	if err := json.Unmarshal(data, &businessHoursConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal business hours config: %w", err)
+	}
+
+	if err := businessHoursConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &businessHoursConfig, nil
+}
+
+// ExtractTagConfig extracts and validates tag config
+func ExtractTagConfig(config map[string]any) (*TagConfig, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var tagConfig TagConfig
+	if err := json.Unmarshal(data, &tagConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tag config: %w", err)
+	}
+
+	if err := tagConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &tagConfig, nil
+}
+
+// ExtractSurveyConfig extracts and validates survey config
+func ExtractSurveyConfig(config map[string]any) (*SurveyConfig, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var surveyConfig SurveyConfig
+	if err := json.Unmarshal(data, &surveyConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal survey config: %w", err)
+	}
+
+	if err := surveyConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &surveyConfig, nil
+}
+
+// ExtractExperimentConfig extracts and validates experiment config
+func ExtractExperimentConfig(config map[string]any) (*ExperimentConfig, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var experimentConfig ExperimentConfig
+	if err := json.Unmarshal(data, &experimentConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal experiment config: %w", err)
+	}
+
+	if err := experimentConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &experimentConfig, nil
+}
+
+// ExtractCRMSyncConfig extracts and validates CRM sync config
+func ExtractCRMSyncConfig(config map[string]any) (*CRMSyncConfig, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var crmConfig CRMSyncConfig
+	if err := json.Unmarshal(data, &crmConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal CRM sync config: %w", err)
+	}
+
+	if err := crmConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &crmConfig, nil
+}
+
+// ExtractTicketConfig extracts and validates ticket config
+func ExtractTicketConfig(config map[string]any) (*TicketConfig, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var ticketConfig TicketConfig
+	if err := json.Unmarshal(data, &ticketConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ticket config: %w", err)
+	}
+
+	if err := ticketConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &ticketConfig, nil
+}
+
+// ExtractOrderLookupConfig extracts and validates order lookup config
+func ExtractOrderLookupConfig(config map[string]any) (*OrderLookupConfig, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var lookupConfig OrderLookupConfig
+	if err := json.Unmarshal(data, &lookupConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal order lookup config: %w", err)
+	}
+
+	if err := lookupConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &lookupConfig, nil
+}
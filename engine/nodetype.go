@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"regexp"
+	"slices"
+)
+
+// ============================================================================
+// Node Types
+// ============================================================================
+
+// BuiltinNodeTypes are the node types the engine ships executors for
+var BuiltinNodeTypes = []NodeType{
+	NodeTypeCondition,
+	NodeTypeAction,
+	NodeTypeDelay,
+	NodeTypeAIAgent,
+	NodeTypeSendMessage,
+	NodeTypeHTTP,
+	NodeTypeTransform,
+	NodeTypeSwitch,
+	NodeTypeLoop,
+	NodeTypeValidate,
+	NodeTypeBusinessHours,
+	NodeTypeTag,
+	NodeTypeSurvey,
+	NodeTypeExperiment,
+	NodeTypeCRMSync,
+	NodeTypeTicket,
+	NodeTypeOrderLookup,
+}
+
+// nodeTypePattern upper snake case, like the built-in types (e.g. ACME_SCORE)
+var nodeTypePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,63}$`)
+
+// IsBuiltin reports whether the engine ships an executor for the type
+func (t NodeType) IsBuiltin() bool {
+	return slices.Contains(BuiltinNodeTypes, t)
+}
+
+// ValidateCustomNodeType checks a type declared by a custom executor. Built-in
+// types cannot be redeclared: a plugin must not silently replace them.
+func ValidateCustomNodeType(nodeType NodeType) error {
+	if !nodeTypePattern.MatchString(string(nodeType)) {
+		return ErrInvalidNodeType().
+			WithDetail("node_type", string(nodeType)).
+			WithDetail("reason", "must be upper snake case, at most 64 characters")
+	}
+	if nodeType.IsBuiltin() {
+		return ErrInvalidNodeType().
+			WithDetail("node_type", string(nodeType)).
+			WithDetail("reason", "built-in node types cannot be redeclared")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Order Lookup
+// ============================================================================
+
+// OrderLookupBy is what identifies the orders an ORDER_LOOKUP node finds
+type OrderLookupBy string
+
+const (
+	OrderLookupByNumber OrderLookupBy = "order_number" // The order shown to the customer, e.g. #1001
+	OrderLookupByEmail  OrderLookupBy = "email"        // The contact's recent orders
+	OrderLookupByPhone  OrderLookupBy = "phone"        // The contact's recent orders
+)
+
+// OrderLookups are the lookups every store connector supports
+var OrderLookups = []OrderLookupBy{OrderLookupByNumber, OrderLookupByEmail, OrderLookupByPhone}
+
+// DefaultOrderLimit is how many recent orders are returned when unset
+const DefaultOrderLimit = 5
+
+// MaxOrderLimit bounds the recent orders of one lookup
+const MaxOrderLimit = 50
+
+// OrderLookupRequest finds orders in the tenant's store
+type OrderLookupRequest struct {
+	Provider string // Empty = the tenant's only connection
+	By       OrderLookupBy
+	Value    string
+	Limit    int // Most recent first; DefaultOrderLimit when 0
+}
+
+// Order is a store order in the shape shared by every connector. Fields holds
+// the values the tenant's response mapping extracts from the store's own
+// representation.
+type Order struct {
+	ID                string          `json:"id"`
+	Number            string          `json:"number"`
+	Status            string          `json:"status"` // Store's overall status, e.g. cancelled, fulfilled, processing
+	PaymentStatus     string          `json:"payment_status,omitempty"`
+	FulfillmentStatus string          `json:"fulfillment_status,omitempty"`
+	Total             string          `json:"total"`
+	Currency          string          `json:"currency"`
+	Email             string          `json:"email,omitempty"`
+	Phone             string          `json:"phone,omitempty"`
+	Items             []OrderItem     `json:"items"`
+	Tracking          []OrderTracking `json:"tracking,omitempty"`
+	StatusURL         string          `json:"status_url,omitempty"` // Page where the customer follows the order
+	CreatedAt         time.Time       `json:"created_at"`
+	Fields            map[string]any  `json:"fields,omitempty"`
+}
+
+// OrderItem is a line of an order
+type OrderItem struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	Total    string `json:"total"`
+}
+
+// OrderTracking is a shipment of an order
+type OrderTracking struct {
+	Company string `json:"company,omitempty"`
+	Number  string `json:"number,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+// OrderLookupResult is what a lookup found, most recent first
+type OrderLookupResult struct {
+	Provider string  `json:"provider"`
+	Orders   []Order `json:"orders"`
+}
@@ -0,0 +1,43 @@
+package engine
+
+import "unicode/utf8"
+
+// ============================================================================
+// Output Limits
+// ============================================================================
+
+// OutputPreviewBytes is how much of a truncated value is kept as a preview
+const OutputPreviewBytes = 512
+
+// TruncatedOutput is the marker that replaces a node output value over the
+// size limit. data is the value's JSON; storageKey is empty when the value
+// was not spilled to storage.
+func TruncatedOutput(data []byte, storageKey string) map[string]any {
+	preview := data
+	if len(preview) > OutputPreviewBytes {
+		preview = preview[:OutputPreviewBytes]
+		for len(preview) > 0 && !utf8.Valid(preview) {
+			preview = preview[:len(preview)-1]
+		}
+	}
+
+	marker := map[string]any{
+		"truncated":  true,
+		"size_bytes": len(data),
+		"preview":    string(preview),
+	}
+	if storageKey != "" {
+		marker["storage_key"] = storageKey
+	}
+	return marker
+}
+
+// IsTruncatedOutput reports whether an output value is a truncation marker
+func IsTruncatedOutput(value any) bool {
+	marker, ok := value.(map[string]any)
+	if !ok {
+		return false
+	}
+	truncated, _ := marker["truncated"].(bool)
+	return truncated
+}
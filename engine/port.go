@@ -15,9 +15,9 @@ import (
 type WorkflowRepository interface {
 	Save(ctx context.Context, wf Workflow) error
 	FindByID(ctx context.Context, id kernel.WorkflowID) (*Workflow, error)
-	FindByName(ctx context.Context, name string, tenantID kernel.TenantID) (*Workflow, error)
+	FindByName(ctx context.Context, name string, tenantID kernel.TenantID, environment kernel.Environment) (*Workflow, error)
 	Delete(ctx context.Context, id kernel.WorkflowID, tenantID kernel.TenantID) error
-	ExistsByName(ctx context.Context, name string, tenantID kernel.TenantID) (bool, error)
+	ExistsByName(ctx context.Context, name string, tenantID kernel.TenantID, environment kernel.Environment) (bool, error)
 
 	FindByTenant(ctx context.Context, tenantID kernel.TenantID) ([]*Workflow, error)
 	FindActive(ctx context.Context, tenantID kernel.TenantID) ([]*Workflow, error)
@@ -28,6 +28,28 @@ type WorkflowRepository interface {
 	BulkUpdateStatus(ctx context.Context, ids []kernel.WorkflowID, tenantID kernel.TenantID, isActive bool) error
 }
 
+// DeadLetterRepository persistence for failed triggers
+type DeadLetterRepository interface {
+	// Save inserts the letter or updates it after a retry
+	Save(ctx context.Context, dl DeadLetter) error
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*DeadLetter, error)
+	List(ctx context.Context, req DeadLetterListRequest) (DeadLetterListResponse, error)
+}
+
+// SnapshotRepository persistence for the runs of debug workflows and their
+// node snapshots
+type SnapshotRepository interface {
+	// SaveRun inserts the run or updates it when it finishes
+	SaveRun(ctx context.Context, run RecordedRun) error
+	SaveSnapshot(ctx context.Context, snapshot NodeSnapshot) error
+	FindRun(ctx context.Context, id string, tenantID kernel.TenantID) (*RecordedRun, error)
+	// FindSnapshots returns the run's snapshots in execution order
+	FindSnapshots(ctx context.Context, runID string) ([]NodeSnapshot, error)
+	ListRuns(ctx context.Context, req RecordedRunListRequest) (RecordedRunListResponse, error)
+	// DeleteBefore removes the runs started before the cutoff with their snapshots
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
 // ============================================================================
 // Executor Interfaces
 // ============================================================================
@@ -57,6 +79,23 @@ type NodeExecutor interface {
 	ValidateConfig(config map[string]any) error
 }
 
+// NodeTypeDeclarer is implemented by executors of node types the engine does
+// not ship. The workflow executor registers every declared type, so custom
+// nodes pass validation and are listed with the built-in ones.
+type NodeTypeDeclarer interface {
+	NodeTypes() []NodeType
+}
+
+// ExecutorMiddleware runs around every node the workflow executor runs, for
+// cross-cutting concerns (metrics, tracing, guardrails, quotas, tenant hooks).
+// BeforeNode may return a derived context for the node, or an error that
+// fails the node without running it. AfterNode sees every result, failed
+// ones included, and may change it before it reaches the execution context.
+type ExecutorMiddleware interface {
+	BeforeNode(ctx context.Context, node WorkflowNode, nodeContext map[string]any) (context.Context, error)
+	AfterNode(ctx context.Context, node WorkflowNode, nodeContext map[string]any, result *NodeResult)
+}
+
 // ============================================================================
 // Delay Scheduler Interface
 // ============================================================================
@@ -71,6 +110,18 @@ type WorkflowContinuation struct {
 	NodeContext  map[string]any `json:"node_context"`
 	ScheduledFor time.Time      `json:"scheduled_for"`
 	CreatedAt    time.Time      `json:"created_at"`
+
+	// ConversationID is the conversation that triggered the workflow, empty
+	// for triggers without one (schedules, webhooks)
+	ConversationID string `json:"conversation_id,omitempty"`
+
+	// MessageID and ChannelID identify the inbound message that triggered the
+	// workflow (its provider ID), so resuming can reload the real message
+	MessageID string `json:"message_id,omitempty"`
+	ChannelID string `json:"channel_id,omitempty"`
+
+	// Kind is what the continuation resumes; empty for paused runs
+	Kind ContinuationKind `json:"kind,omitempty"`
 }
 
 // ContinuationHandler is called when delayed execution is ready
@@ -84,7 +135,20 @@ type DelayScheduler interface {
 	StopWorker()
 	GetPendingCount(ctx context.Context) (int64, error)
 	GetContinuation(ctx context.Context, id string) (*WorkflowContinuation, error)
+
+	// Cancel drops a pending continuation. It fails with CONTINUATION_NOT_FOUND
+	// once the continuation is resuming or gone.
 	Cancel(ctx context.Context, id string) error
+
+	// ListPending returns the tenant's pending continuations, soonest first
+	ListPending(ctx context.Context, req ContinuationListRequest) (ContinuationListResponse, error)
+
+	// GetPendingCountByWorkflow breaks the tenant's pending continuations down
+	// by workflow ID
+	GetPendingCountByWorkflow(ctx context.Context, tenantID kernel.TenantID) (map[string]int64, error)
+
+	// ResumeNow makes a pending continuation due immediately
+	ResumeNow(ctx context.Context, id string) error
 }
 
 type WorkflowScheduleRepository interface {
@@ -99,3 +163,149 @@ type WorkflowScheduleRepository interface {
 	FindByTenant(ctx context.Context, tenantID kernel.TenantID) ([]*WorkflowSchedule, error)
 	CountByWorkflow(ctx context.Context, workflowID kernel.WorkflowID) (int, error)
 }
+
+// ============================================================================
+// Business Hours Interfaces
+// ============================================================================
+
+// BusinessHoursProvider answers "is the tenant open now" for nodes and CEL
+// expressions
+type BusinessHoursProvider interface {
+	Status(ctx context.Context, tenantID kernel.TenantID, at time.Time) (*BusinessHoursStatus, error)
+}
+
+// ============================================================================
+// Segment Interfaces
+// ============================================================================
+
+// SegmentChecker answers contact.in_segment("name") in CEL expressions: is
+// the contact of the conversation a member of the tenant's named segment
+type SegmentChecker interface {
+	InSegment(ctx context.Context, tenantID kernel.TenantID, conversationID, segment string) (bool, error)
+}
+
+// ============================================================================
+// Output Interfaces
+// ============================================================================
+
+// OutputSpiller stores a node output value too large to keep in the execution
+// context (object storage) and returns the key it can be read back from
+type OutputSpiller interface {
+	SpillOutput(ctx context.Context, tenantID kernel.TenantID, nodeID, field string, data []byte) (string, error)
+}
+
+// ============================================================================
+// Resource Interfaces
+// ============================================================================
+
+// ResourceResolver reports whether a resource referenced by a workflow
+// exists for the tenant, so broken references are caught by validation
+// instead of failing the node at run time
+type ResourceResolver interface {
+	ResourceExists(ctx context.Context, tenantID kernel.TenantID, kind ResourceKind, id string) (bool, error)
+}
+
+// ============================================================================
+// Snippet Interfaces
+// ============================================================================
+
+// SnippetProvider renders a tenant's canned reply, so SEND_MESSAGE nodes can
+// reference a snippet instead of duplicating its text
+type SnippetProvider interface {
+	RenderSnippet(ctx context.Context, tenantID kernel.TenantID, snippetID string, variables map[string]string) (string, error)
+}
+
+// ============================================================================
+// Message Template Interfaces
+// ============================================================================
+
+// MessageTemplateRenderer renders a tenant's localized message template in
+// the contact's locale. It returns the text and the locale of the variant
+// used, which falls back to the template's default locale.
+type MessageTemplateRenderer interface {
+	RenderMessageTemplate(ctx context.Context, tenantID kernel.TenantID, key, locale string, variables map[string]any) (text, usedLocale string, err error)
+}
+
+// ============================================================================
+// Tagging Interfaces
+// ============================================================================
+
+// ConversationTagger applies tags and dispositions to a conversation and
+// returns its tags afterwards
+type ConversationTagger interface {
+	TagConversation(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string, update TagUpdate) (*ConversationTags, error)
+}
+
+// ============================================================================
+// Survey Interfaces
+// ============================================================================
+
+// SurveyRecorder stores the question a SURVEY node sent, so the contact's
+// answer resumes the paused run. It returns the survey's ID.
+type SurveyRecorder interface {
+	OpenSurvey(ctx context.Context, survey PendingSurvey) (string, error)
+}
+
+// ============================================================================
+// Experiment Interfaces
+// ============================================================================
+
+// ExperimentRecorder stores the exposures and conversions of EXPERIMENT
+// nodes. RecordConversion returns the variant credited, empty when the
+// contact was never exposed.
+type ExperimentRecorder interface {
+	RecordExposure(ctx context.Context, event ExperimentEvent) error
+	RecordConversion(ctx context.Context, event ExperimentEvent) (string, error)
+}
+
+// ============================================================================
+// CRM Interfaces
+// ============================================================================
+
+// CRMSyncer writes to the CRM a tenant connected (HubSpot, Salesforce)
+type CRMSyncer interface {
+	SyncCRM(ctx context.Context, tenantID kernel.TenantID, req CRMSyncRequest) (*CRMSyncResult, error)
+}
+
+// ============================================================================
+// Helpdesk Interfaces
+// ============================================================================
+
+// TicketSyncer opens and updates tickets in the helpdesk a tenant connected
+// (Zendesk, Freshdesk), keeping one ticket per conversation
+type TicketSyncer interface {
+	SyncTicket(ctx context.Context, tenantID kernel.TenantID, req TicketRequest) (*TicketResult, error)
+}
+
+// ============================================================================
+// Commerce Interfaces
+// ============================================================================
+
+// OrderLooker finds orders in the store a tenant connected (Shopify,
+// WooCommerce)
+type OrderLooker interface {
+	LookupOrders(ctx context.Context, tenantID kernel.TenantID, req OrderLookupRequest) (*OrderLookupResult, error)
+}
+
+// ============================================================================
+// Execution Serialization
+// ============================================================================
+
+// ConversationLock serializes the executions triggered by one conversation so
+// messages sent in quick succession are processed one after another
+type ConversationLock interface {
+	// Acquire waits until the conversation is free or ctx is done. release
+	// must be called when processing finishes.
+	Acquire(ctx context.Context, key string) (release func(), err error)
+}
+
+// ============================================================================
+// Fallback Interfaces
+// ============================================================================
+
+// FallbackResolver decides what happens to a channel message no workflow
+// matched. It counts the message and returns the workflow to run instead, or
+// nil when it replied with a fallback message or nothing is configured.
+type FallbackResolver interface {
+	ResolveFallback(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, environment kernel.Environment, triggerData map[string]any) *Workflow
+}
@@ -0,0 +1,43 @@
+package promotion
+
+import (
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PromotionHandler exposes workflow promotion over HTTP
+type PromotionHandler struct {
+	service *PromotionService
+}
+
+func NewPromotionHandler(service *PromotionService) *PromotionHandler {
+	return &PromotionHandler{
+		service: service,
+	}
+}
+
+// Promote copies a sandbox workflow to production
+// POST /api/workflows/:id/promote
+func (h *PromotionHandler) Promote(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req PromoteRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return engine.ErrInvalidPromotion().WithDetail("reason", err.Error())
+		}
+	}
+
+	response, err := h.service.Promote(c.Context(), authContext.TenantID, kernel.NewWorkflowID(c.Params("id")), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response)
+}
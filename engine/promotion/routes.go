@@ -0,0 +1,26 @@
+package promotion
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PromotionRoutes struct {
+	handler        *PromotionHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+func NewPromotionRoutes(handler *PromotionHandler, authMiddleware *auth.AuthMiddleware) *PromotionRoutes {
+	return &PromotionRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers promotion routes on an authenticated router.
+// Promoting changes what production traffic runs, so it requires an admin.
+func (r *PromotionRoutes) RegisterRoutes(router fiber.Router) {
+	workflows := router.Group("/workflows")
+
+	workflows.Post("/:id/promote", r.authMiddleware.RequireAdmin(), r.handler.Promote)
+}
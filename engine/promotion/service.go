@@ -0,0 +1,183 @@
+package promotion
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// PromotionService copies sandbox workflows to production. The production
+// copy is matched by name, and channel references are re-linked from the
+// sandbox channels to their production counterparts.
+type PromotionService struct {
+	workflowRepo engine.WorkflowRepository
+	channelRepo  channels.ChannelRepository
+	validator    engine.WorkflowExecutor
+}
+
+func NewPromotionService(
+	workflowRepo engine.WorkflowRepository,
+	channelRepo channels.ChannelRepository,
+	validator engine.WorkflowExecutor,
+) *PromotionService {
+	return &PromotionService{
+		workflowRepo: workflowRepo,
+		channelRepo:  channelRepo,
+		validator:    validator,
+	}
+}
+
+// PromoteRequest tunes a promotion
+type PromoteRequest struct {
+	// ChannelMap pairs sandbox channel IDs with production channel IDs. A
+	// sandbox channel missing from it is paired with the production channel
+	// of the same name.
+	ChannelMap map[string]string `json:"channel_map,omitempty"`
+
+	// Activate turns the production workflow on. Otherwise a new copy is
+	// created inactive and an existing one keeps its state.
+	Activate bool `json:"activate,omitempty"`
+}
+
+// Relink is one channel reference rewritten by a promotion
+type Relink struct {
+	Channel string `json:"channel"` // Name of the sandbox channel
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+// PromoteResponse is the production workflow after the promotion
+type PromoteResponse struct {
+	Workflow engine.Workflow `json:"workflow"`
+	Created  bool            `json:"created"`
+	Relinked []Relink        `json:"relinked"`
+}
+
+// Promote copies a sandbox workflow to production, creating the production
+// workflow of the same name or replacing its trigger and nodes
+func (s *PromotionService) Promote(ctx context.Context, tenantID kernel.TenantID, workflowID kernel.WorkflowID, req PromoteRequest) (*PromoteResponse, error) {
+	source, err := s.workflowRepo.FindByID(ctx, workflowID)
+	if err != nil || source.TenantID != tenantID {
+		return nil, engine.ErrWorkflowNotFound().WithDetail("workflow_id", workflowID.String())
+	}
+	if source.Environment.OrDefault() != kernel.EnvironmentSandbox {
+		return nil, engine.ErrInvalidPromotion().
+			WithDetail("workflow_id", workflowID.String()).
+			WithDetail("reason", "only sandbox workflows can be promoted")
+	}
+
+	channelIDs, relinked, err := s.pairChannels(ctx, *source, req.ChannelMap)
+	if err != nil {
+		return nil, err
+	}
+
+	promoted := engine.RelinkResourceRefs(*source, engine.ResourceChannel, channelIDs)
+	promoted.Environment = kernel.EnvironmentProduction
+	promoted.UpdatedAt = time.Now()
+
+	target, err := s.workflowRepo.FindByName(ctx, source.Name, tenantID, kernel.EnvironmentProduction)
+	created := err != nil
+	if errx.IsCode(err, engine.CodeWorkflowNotFound) {
+		promoted.ID = kernel.NewWorkflowID(uuid.NewString())
+		promoted.IsActive = req.Activate
+		promoted.Debug = false // Recording is switched on per environment
+		promoted.Version = 1
+		promoted.CreatedAt = promoted.UpdatedAt
+	} else if err != nil {
+		return nil, errx.Wrap(err, "failed to find production workflow", errx.TypeInternal)
+	} else {
+		promoted.ID = target.ID
+		promoted.IsActive = target.IsActive || req.Activate
+		promoted.Debug = target.Debug
+		promoted.Version = target.Version
+		promoted.CreatedAt = target.CreatedAt
+	}
+
+	if err := s.validator.ValidateWorkflow(ctx, promoted); err != nil {
+		return nil, err
+	}
+	if err := s.workflowRepo.Save(ctx, promoted); err != nil {
+		return nil, err
+	}
+	if !created {
+		promoted.Version++
+	}
+
+	log.Printf("🚀 Promoted workflow %s to production as %s (created=%v, %d channel(s) re-linked)",
+		source.Name, promoted.ID.String(), created, len(relinked))
+
+	return &PromoteResponse{
+		Workflow: promoted,
+		Created:  created,
+		Relinked: relinked,
+	}, nil
+}
+
+// pairChannels maps every channel the workflow references to a production
+// channel. All the channels that cannot be paired are reported at once.
+func (s *PromotionService) pairChannels(ctx context.Context, workflow engine.Workflow, overrides map[string]string) (map[string]string, []Relink, error) {
+	pairs := make(map[string]string)
+	relinked := []Relink{}
+	var unpaired []string
+
+	for _, ref := range engine.CollectResourceRefs(workflow) {
+		if ref.Kind != engine.ResourceChannel {
+			continue
+		}
+		if _, done := pairs[ref.ID]; done {
+			continue
+		}
+
+		sandbox, err := s.channelRepo.FindByID(ctx, kernel.ChannelID(ref.ID), workflow.TenantID)
+		if err != nil {
+			unpaired = append(unpaired, ref.ID)
+			continue
+		}
+
+		// Production channels referenced directly need no pairing
+		if sandbox.Environment.OrDefault() == kernel.EnvironmentProduction {
+			pairs[ref.ID] = ref.ID
+			continue
+		}
+
+		production, err := s.productionChannel(ctx, sandbox, overrides[ref.ID])
+		if err != nil {
+			unpaired = append(unpaired, sandbox.Name)
+			continue
+		}
+
+		pairs[ref.ID] = production.ID.String()
+		relinked = append(relinked, Relink{Channel: sandbox.Name, From: ref.ID, To: production.ID.String()})
+	}
+
+	if len(unpaired) > 0 {
+		return nil, nil, engine.ErrInvalidPromotion().
+			WithDetail("workflow_id", workflow.ID.String()).
+			WithDetail("reason", "channels without a production counterpart").
+			WithDetail("channels", unpaired)
+	}
+	return pairs, relinked, nil
+}
+
+// productionChannel resolves the counterpart of a sandbox channel: the
+// override when given, else the production channel with the same name
+func (s *PromotionService) productionChannel(ctx context.Context, sandbox *channels.Channel, override string) (*channels.Channel, error) {
+	if override == "" {
+		return s.channelRepo.FindByName(ctx, sandbox.Name, sandbox.TenantID, kernel.EnvironmentProduction)
+	}
+
+	channel, err := s.channelRepo.FindByID(ctx, kernel.ChannelID(override), sandbox.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if channel.Environment.OrDefault() != kernel.EnvironmentProduction {
+		return nil, engine.ErrInvalidPromotion().WithDetail("channel_id", override)
+	}
+	return channel, nil
+}
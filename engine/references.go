@@ -0,0 +1,176 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Resource References
+// ============================================================================
+
+// ResourceKind names a tenant resource a workflow can reference by ID
+type ResourceKind string
+
+const (
+	ResourceChannel         ResourceKind = "channel"
+	ResourceSnippet         ResourceKind = "snippet"
+	ResourceMessageTemplate ResourceKind = "message_template" // Referenced by key
+)
+
+// ResourceRef is one reference found in a workflow
+type ResourceRef struct {
+	Kind   ResourceKind `json:"kind"`
+	ID     string       `json:"id"`
+	NodeID string       `json:"node_id,omitempty"` // Empty for the trigger
+	Field  string       `json:"field"`
+}
+
+// nodeReferenceFields lists, per node type, the config fields that hold a
+// resource ID
+var nodeReferenceFields = map[NodeType]map[string]ResourceKind{
+	NodeTypeSendMessage: {
+		"channel_id":   ResourceChannel,
+		"snippet_id":   ResourceSnippet,
+		"template_key": ResourceMessageTemplate,
+	},
+	NodeTypeAIAgent: {
+		"channel_id": ResourceChannel,
+	},
+}
+
+// ResourceLookup reports whether one kind of resource exists for the tenant
+type ResourceLookup func(ctx context.Context, tenantID kernel.TenantID, id string) (bool, error)
+
+// ResourceResolvers resolves references with one lookup per kind. Kinds
+// without a lookup are not checked.
+type ResourceResolvers map[ResourceKind]ResourceLookup
+
+var _ ResourceResolver = ResourceResolvers(nil)
+
+func (r ResourceResolvers) ResourceExists(ctx context.Context, tenantID kernel.TenantID, kind ResourceKind, id string) (bool, error) {
+	lookup, ok := r[kind]
+	if !ok {
+		return true, nil
+	}
+	return lookup(ctx, tenantID, id)
+}
+
+// CollectResourceRefs returns the references in the workflow's trigger
+// filters and node configs. Templated values only resolve at run time and
+// are skipped.
+func CollectResourceRefs(workflow Workflow) []ResourceRef {
+	var refs []ResourceRef
+
+	for i, id := range stringList(workflow.Trigger.Filters["channel_ids"]) {
+		if id != "" && !IsTemplate(id) {
+			refs = append(refs, ResourceRef{
+				Kind:  ResourceChannel,
+				ID:    id,
+				Field: fmt.Sprintf("trigger.filters.channel_ids[%d]", i),
+			})
+		}
+	}
+
+	for _, node := range workflow.Nodes {
+		fields := nodeReferenceFields[node.Type]
+		for _, field := range slices.Sorted(maps.Keys(fields)) {
+			id, _ := node.Config[field].(string)
+			if id == "" || IsTemplate(id) {
+				continue
+			}
+			refs = append(refs, ResourceRef{Kind: fields[field], ID: id, NodeID: node.ID, Field: field})
+		}
+	}
+
+	return refs
+}
+
+// CheckResourceRefs resolves every reference of the workflow and reports all
+// the missing ones in a single error
+func CheckResourceRefs(ctx context.Context, resolver ResourceResolver, workflow Workflow) error {
+	var missing []ResourceRef
+	checked := make(map[ResourceRef]bool)
+
+	for _, ref := range CollectResourceRefs(workflow) {
+		key := ResourceRef{Kind: ref.Kind, ID: ref.ID}
+		exists, seen := checked[key]
+		if !seen {
+			var err error
+			exists, err = resolver.ResourceExists(ctx, workflow.TenantID, ref.Kind, ref.ID)
+			if err != nil {
+				return err
+			}
+			checked[key] = exists
+		}
+		if !exists {
+			missing = append(missing, ref)
+		}
+	}
+
+	if len(missing) > 0 {
+		return ErrMissingResources().
+			WithDetail("workflow_id", workflow.ID.String()).
+			WithDetail("missing", missing)
+	}
+	return nil
+}
+
+// RelinkResourceRefs returns a copy of the workflow with the references of
+// one kind rewritten through ids. IDs missing from the map are kept.
+func RelinkResourceRefs(workflow Workflow, kind ResourceKind, ids map[string]string) Workflow {
+	relink := func(id string) string {
+		if to, ok := ids[id]; ok {
+			return to
+		}
+		return id
+	}
+
+	relinked := workflow
+	relinked.Trigger.Config = DeepCopyMap(workflow.Trigger.Config)
+	relinked.Trigger.Filters = DeepCopyMap(workflow.Trigger.Filters)
+	if kind == ResourceChannel {
+		if list := stringList(workflow.Trigger.Filters["channel_ids"]); list != nil {
+			channelIDs := make([]string, len(list))
+			for i, id := range list {
+				channelIDs[i] = relink(id)
+			}
+			relinked.Trigger.Filters["channel_ids"] = channelIDs
+		}
+	}
+
+	relinked.Nodes = make([]WorkflowNode, len(workflow.Nodes))
+	for i, node := range workflow.Nodes {
+		node.Config = DeepCopyMap(node.Config)
+		for field, fieldKind := range nodeReferenceFields[node.Type] {
+			if id, ok := node.Config[field].(string); ok && fieldKind == kind {
+				node.Config[field] = relink(id)
+			}
+		}
+		relinked.Nodes[i] = node
+	}
+
+	return relinked
+}
+
+// stringList reads a filter value that may hold []string or, once decoded
+// from JSON, []any
+func stringList(value any) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []any:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sync"
+)
+
+// ============================================================================
+// Compiled Regex Cache
+// ============================================================================
+
+const (
+	// maxRegexPatternLength rejects patterns nobody writes by hand
+	maxRegexPatternLength = 1024
+
+	// maxRegexProgramSize bounds the compiled program. Go's RE2 engine never
+	// backtracks, so matching is linear in the input, but counted repetitions
+	// expand into one instruction per copy ((\w{1,50}\s){1,20} is ~2000) and
+	// the per-match cost grows with the program.
+	maxRegexProgramSize = 5000
+
+	// maxCachedRegexes bounds the cache; when full it is cleared rather than
+	// tracking recency, since hot patterns are recompiled right away
+	maxCachedRegexes = 1024
+)
+
+var regexCache = struct {
+	sync.RWMutex
+	compiled map[string]*regexp.Regexp
+}{compiled: make(map[string]*regexp.Regexp)}
+
+// CompileRegex returns the compiled pattern, compiling it once per process.
+// Patterns that fail to compile or exceed the complexity limits return
+// ErrRegexCompileFailed.
+func CompileRegex(pattern string) (*regexp.Regexp, error) {
+	regexCache.RLock()
+	re, ok := regexCache.compiled[pattern]
+	regexCache.RUnlock()
+	if ok {
+		return re, nil
+	}
+
+	if err := ValidateRegex(pattern); err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, regexCompileError(pattern, err.Error())
+	}
+
+	regexCache.Lock()
+	if len(regexCache.compiled) >= maxCachedRegexes {
+		regexCache.compiled = make(map[string]*regexp.Regexp)
+	}
+	regexCache.compiled[pattern] = re
+	regexCache.Unlock()
+
+	return re, nil
+}
+
+// ValidateRegex checks that a pattern compiles and is within the complexity
+// limits, so workflows are rejected when validated instead of failing on
+// every message
+func ValidateRegex(pattern string) error {
+	if len(pattern) > maxRegexPatternLength {
+		return regexCompileError(pattern, fmt.Sprintf("pattern longer than %d characters", maxRegexPatternLength))
+	}
+
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return regexCompileError(pattern, err.Error())
+	}
+
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return regexCompileError(pattern, err.Error())
+	}
+	if len(prog.Inst) > maxRegexProgramSize {
+		return regexCompileError(pattern, "pattern is too complex")
+	}
+
+	return nil
+}
+
+func regexCompileError(pattern, reason string) error {
+	return ErrRegexCompileFailed().
+		WithDetail("pattern", pattern).
+		WithDetail("reason", reason)
+}
@@ -0,0 +1,133 @@
+package replay
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// ReplayHandler exposes the tenant's recorded runs and replays them
+type ReplayHandler struct {
+	service *ReplayService
+}
+
+func NewReplayHandler(service *ReplayService) *ReplayHandler {
+	return &ReplayHandler{
+		service: service,
+	}
+}
+
+// List returns the tenant's recorded runs, newest first
+// GET /api/workflow-runs?workflow_id=&page=1&page_size=50
+func (h *ReplayHandler) List(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", defaultPageSize)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	req := engine.RecordedRunListRequest{
+		PaginationOptions: storex.PaginationOptions{
+			Page:     page,
+			PageSize: pageSize,
+		},
+		TenantID: authContext.TenantID,
+	}
+	if workflowID := c.Query("workflow_id"); workflowID != "" {
+		id := kernel.NewWorkflowID(workflowID)
+		req.WorkflowID = &id
+	}
+
+	runs, err := h.service.ListRuns(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(runs)
+}
+
+// Get returns a recorded run with the snapshot of every node
+// GET /api/workflow-runs/:id
+func (h *ReplayHandler) Get(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	details, err := h.service.GetRun(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(details)
+}
+
+// Replay re-executes a recorded run from one of its node boundaries. Nothing
+// reaches a provider; the report compares every node with the recording.
+// POST /api/workflow-runs/:id/replay
+func (h *ReplayHandler) Replay(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req Request
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return engine.ErrInvalidReplay().WithDetail("reason", err.Error())
+		}
+	}
+
+	report, err := h.service.Replay(c.Context(), c.Params("id"), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(report)
+}
+
+// SetDebug switches the recording of a workflow's runs on or off
+// PUT /api/workflows/:id/debug
+func (h *ReplayHandler) SetDebug(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return engine.ErrInvalidWorkflowConfig().WithDetail("reason", err.Error())
+	}
+
+	wf, err := h.service.SetDebug(c.Context(), kernel.NewWorkflowID(c.Params("id")), authContext.TenantID, req.Enabled)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"workflow_id": wf.ID,
+		"debug":       wf.Debug,
+		"version":     wf.Version,
+	})
+}
@@ -0,0 +1,31 @@
+package replay
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReplayRoutes struct {
+	handler        *ReplayHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+func NewReplayRoutes(handler *ReplayHandler, authMiddleware *auth.AuthMiddleware) *ReplayRoutes {
+	return &ReplayRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers recorded run routes on an authenticated router.
+// Replaying runs workflow code and recording stores whole conversations, so
+// both require an admin.
+func (r *ReplayRoutes) RegisterRoutes(router fiber.Router) {
+	runs := router.Group("/workflow-runs")
+
+	runs.Get("/", r.handler.List)
+	runs.Get("/:id", r.handler.Get)
+	runs.Post("/:id/replay", r.authMiddleware.RequireAdmin(), r.handler.Replay)
+
+	router.Put("/workflows/:id/debug", r.authMiddleware.RequireAdmin(), r.handler.SetDebug)
+}
@@ -0,0 +1,301 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// PruneJobKind is the recurring system job that deletes old recorded runs
+const PruneJobKind = "engine.prune_snapshots"
+
+// replayTimeout bounds a whole replay
+const replayTimeout = 2 * time.Minute
+
+// ReplayService exposes the runs recorded for workflows with debug on and
+// replays them. Runs are only visible to their own tenant.
+type ReplayService struct {
+	snapshots    engine.SnapshotRepository
+	workflowRepo engine.WorkflowRepository
+	executor     engine.WorkflowExecutor
+	retention    time.Duration
+}
+
+func NewReplayService(
+	snapshots engine.SnapshotRepository,
+	workflowRepo engine.WorkflowRepository,
+	executor engine.WorkflowExecutor,
+	retention time.Duration,
+) *ReplayService {
+	return &ReplayService{
+		snapshots:    snapshots,
+		workflowRepo: workflowRepo,
+		executor:     executor,
+		retention:    retention,
+	}
+}
+
+// ============================================================================
+// DTOs
+// ============================================================================
+
+// RunDetails is a recorded run with its node snapshots in execution order
+type RunDetails struct {
+	Run       *engine.RecordedRun   `json:"run"`
+	Snapshots []engine.NodeSnapshot `json:"snapshots"`
+}
+
+// Request picks the node boundary a replay starts at: the snapshot with
+// Sequence, or else the one of FromNodeID, or else the first of the run
+type Request struct {
+	FromNodeID string `json:"from_node_id,omitempty"`
+	Sequence   int    `json:"sequence,omitempty"`
+}
+
+// Report is the outcome of a replay next to what the recorded run did
+type Report struct {
+	RunID           string            `json:"run_id"`
+	WorkflowID      kernel.WorkflowID `json:"workflow_id"`
+	FromNodeID      string            `json:"from_node_id"`
+	FromSequence    int               `json:"from_sequence"`
+	RecordedVersion int               `json:"recorded_version"`
+	CurrentVersion  int               `json:"current_version"` // Replays run the current definition
+	Success         bool              `json:"success"`
+	Error           string            `json:"error,omitempty"`
+	Nodes           []ReplayedNode    `json:"nodes"`
+	Mocked          []string          `json:"mocked"`    // Nodes answered from the recording
+	Responses       []string          `json:"responses"` // Texts SEND_MESSAGE nodes would have sent
+	DurationMs      int64             `json:"duration_ms"`
+}
+
+// ReplayedNode is one node of the replay. Changed is set when a node that ran
+// again produced another output than in the recorded run.
+type ReplayedNode struct {
+	NodeID   string             `json:"node_id"`
+	Success  bool               `json:"success"`
+	Error    string             `json:"error,omitempty"`
+	Output   map[string]any     `json:"output,omitempty"`
+	Mocked   bool               `json:"mocked"`
+	Changed  bool               `json:"changed"`
+	Recorded *engine.NodeResult `json:"recorded,omitempty"` // Nil for nodes the recorded run did not reach
+}
+
+// ============================================================================
+// Runs
+// ============================================================================
+
+// ListRuns returns the tenant's recorded runs, newest first
+func (s *ReplayService) ListRuns(ctx context.Context, req engine.RecordedRunListRequest) (engine.RecordedRunListResponse, error) {
+	return s.snapshots.ListRuns(ctx, req)
+}
+
+// GetRun returns one of the tenant's recorded runs with its snapshots
+func (s *ReplayService) GetRun(ctx context.Context, id string, tenantID kernel.TenantID) (*RunDetails, error) {
+	run, err := s.snapshots.FindRun(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := s.snapshots.FindSnapshots(ctx, run.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &RunDetails{Run: run, Snapshots: snapshots}, nil
+}
+
+// SetDebug switches the recording of a workflow's runs on or off
+func (s *ReplayService) SetDebug(ctx context.Context, workflowID kernel.WorkflowID, tenantID kernel.TenantID, enabled bool) (*engine.Workflow, error) {
+	wf, err := s.workflowRepo.FindByID(ctx, workflowID)
+	if err != nil || wf.TenantID != tenantID {
+		return nil, engine.ErrWorkflowNotFound().WithDetail("workflow_id", workflowID.String())
+	}
+	if wf.Debug == enabled {
+		return wf, nil
+	}
+
+	wf.Debug = enabled
+	wf.UpdatedAt = time.Now()
+	if err := s.workflowRepo.Save(ctx, *wf); err != nil {
+		return nil, err
+	}
+	wf.Version++
+
+	state := "off"
+	if enabled {
+		state = "on"
+	}
+	log.Printf("🎞️  Recording of workflow %s switched %s", wf.Name, state)
+	return wf, nil
+}
+
+// ============================================================================
+// Replay
+// ============================================================================
+
+// Replay re-executes a recorded run from a node boundary with the context
+// recorded there. Nodes that only compute from the context run again against
+// the current workflow definition; nodes with side effects return their
+// recorded result, and outgoing messages are captured instead of sent.
+func (s *ReplayService) Replay(ctx context.Context, runID string, tenantID kernel.TenantID, req Request) (*Report, error) {
+	details, err := s.GetRun(ctx, runID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	run := details.Run
+
+	from, err := pickSnapshot(details.Snapshots, req)
+	if err != nil {
+		return nil, err
+	}
+
+	wf, err := s.workflowRepo.FindByID(ctx, run.WorkflowID)
+	if err != nil || wf.TenantID != tenantID {
+		return nil, engine.ErrWorkflowNotFound().WithDetail("workflow_id", run.WorkflowID.String())
+	}
+	if wf.GetNodeByID(from.NodeID) == nil {
+		return nil, engine.ErrInvalidReplay().
+			WithDetail("node_id", from.NodeID).
+			WithDetail("reason", "node is no longer in the workflow")
+	}
+
+	metadata := engine.DeepCopyMap(run.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]any)
+	}
+	metadata["simulation"] = true
+	metadata["replay_of"] = run.ID
+
+	input := engine.WorkflowInput{
+		TriggerData: run.TriggerData,
+		TenantID:    run.TenantID,
+		Metadata:    metadata,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, replayTimeout)
+	defer cancel()
+
+	session := engine.NewReplaySession(details.Snapshots)
+	ctx = engine.WithReplay(ctx, session)
+	ctx, outbox := channels.WithOutbox(ctx)
+
+	startTime := time.Now()
+	result, err := s.executor.ResumeFromNode(ctx, *wf, input, from.NodeID, from.Context)
+	if err != nil {
+		return nil, err
+	}
+	mocked := session.Mocked()
+
+	report := &Report{
+		RunID:           run.ID,
+		WorkflowID:      run.WorkflowID,
+		FromNodeID:      from.NodeID,
+		FromSequence:    from.Sequence,
+		RecordedVersion: run.WorkflowVersion,
+		CurrentVersion:  wf.Version,
+		Success:         result.Success,
+		Error:           result.ErrorMessage,
+		Nodes:           compareNodes(details.Snapshots, result.ExecutedNodes, mocked),
+		Mocked:          mocked,
+		Responses:       []string{},
+		DurationMs:      time.Since(startTime).Milliseconds(),
+	}
+	for _, captured := range outbox.Messages() {
+		report.Responses = append(report.Responses, captured.Message.Content.Text)
+	}
+
+	log.Printf("🎞️  Replayed run %s from node %s: %d node(s), %d mocked (success=%v)",
+		run.ID, from.NodeID, len(report.Nodes), len(report.Mocked), report.Success)
+	return report, nil
+}
+
+func pickSnapshot(snapshots []engine.NodeSnapshot, req Request) (*engine.NodeSnapshot, error) {
+	if len(snapshots) == 0 {
+		return nil, engine.ErrInvalidReplay().WithDetail("reason", "run has no snapshots")
+	}
+
+	for i := range snapshots {
+		switch {
+		case req.Sequence > 0:
+			if snapshots[i].Sequence == req.Sequence {
+				return &snapshots[i], nil
+			}
+		case req.FromNodeID != "":
+			if snapshots[i].NodeID == req.FromNodeID {
+				return &snapshots[i], nil
+			}
+		default:
+			return &snapshots[i], nil
+		}
+	}
+
+	return nil, engine.ErrInvalidReplay().
+		WithDetail("from_node_id", req.FromNodeID).
+		WithDetail("sequence", req.Sequence).
+		WithDetail("reason", "the recorded run has no snapshot there")
+}
+
+// compareNodes pairs every replayed node with its recorded result
+func compareNodes(snapshots []engine.NodeSnapshot, executed []engine.NodeResult, mocked []string) []ReplayedNode {
+	recorded := make(map[string]engine.NodeResult, len(snapshots))
+	for _, snapshot := range snapshots {
+		if _, ok := recorded[snapshot.NodeID]; !ok {
+			recorded[snapshot.NodeID] = snapshot.Result
+		}
+	}
+
+	nodes := make([]ReplayedNode, 0, len(executed))
+	for _, result := range executed {
+		node := ReplayedNode{
+			NodeID:  result.NodeID,
+			Success: result.Success,
+			Error:   result.Error,
+			Output:  result.Output,
+		}
+		node.Mocked = slices.Contains(mocked, result.NodeID)
+		if original, ok := recorded[result.NodeID]; ok {
+			node.Recorded = &original
+			node.Changed = !node.Mocked &&
+				(original.Success != result.Success || !sameJSON(original.Output, result.Output))
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// sameJSON compares outputs the way they are stored: recorded ones went
+// through JSON, so numbers are float64
+func sameJSON(a, b map[string]any) bool {
+	normalize := func(m map[string]any) any {
+		data, err := json.Marshal(m)
+		if err != nil {
+			return nil
+		}
+		var v any
+		_ = json.Unmarshal(data, &v)
+		return v
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+// ============================================================================
+// Retention
+// ============================================================================
+
+// RunPruneJob is the handler of PruneJobKind
+func (s *ReplayService) RunPruneJob(ctx context.Context, _ jobs.Job) error {
+	deleted, err := s.snapshots.DeleteBefore(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("🎞️  Pruned %d recorded runs older than %s", deleted, s.retention)
+	}
+	return nil
+}
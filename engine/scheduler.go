@@ -26,6 +26,11 @@ type WorkflowSchedule struct {
 	Timezone string         `db:"timezone" json:"timezone"`
 	Metadata map[string]any `db:"metadata" json:"metadata,omitempty"`
 
+	// Payload is handed to the workflow as trigger data, e.g. the contact a
+	// reminder is for. Its keys are available as trigger.<key> and the whole
+	// map as trigger.payload.
+	Payload map[string]any `db:"payload" json:"payload,omitempty"`
+
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
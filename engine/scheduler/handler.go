@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ScheduleHandler exposes one-off scheduled runs
+type ScheduleHandler struct {
+	service *ScheduleService
+}
+
+func NewScheduleHandler(service *ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{service: service}
+}
+
+// RunAt schedules one run of the workflow with a payload
+// POST /api/workflows/:id/run-at
+func (h *ScheduleHandler) RunAt(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req engine.RunAtRequest
+	if err := c.BodyParser(&req); err != nil {
+		return engine.ErrInvalidScheduleConfig().WithDetail("reason", err.Error())
+	}
+	if req.RunAt == "" {
+		return engine.ErrInvalidScheduleConfig().WithDetail("reason", "run_at is required")
+	}
+
+	schedule, err := h.service.ScheduleRun(
+		c.Context(),
+		authContext.TenantID,
+		kernel.NewWorkflowID(c.Params("id")),
+		req,
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(schedule)
+}
@@ -0,0 +1,22 @@
+package scheduler
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type ScheduleRoutes struct {
+	handler *ScheduleHandler
+}
+
+func NewScheduleRoutes(handler *ScheduleHandler) *ScheduleRoutes {
+	return &ScheduleRoutes{
+		handler: handler,
+	}
+}
+
+// RegisterRoutes registers schedule routes on an authenticated router
+func (r *ScheduleRoutes) RegisterRoutes(router fiber.Router) {
+	workflows := router.Group("/workflows")
+
+	workflows.Post("/:id/run-at", r.handler.RunAt)
+}
@@ -98,27 +98,13 @@ func (s *WorkflowScheduler) processDueSchedules(ctx context.Context) {
 func (s *WorkflowScheduler) executeSchedule(ctx context.Context, schedule *engine.WorkflowSchedule) {
 	log.Printf("▶️  Executing schedule: %s (workflow: %s)", schedule.ID, schedule.WorkflowID)
 
-	// Prepare trigger data
-	triggerData := map[string]any{
-		"schedule_id":    schedule.ID,
-		"schedule_type":  schedule.ScheduleType,
-		"execution_time": time.Now().Unix(),
-		"run_count":      schedule.RunCount + 1,
-	}
-
-	if schedule.CronExpression != nil {
-		triggerData["cron_expression"] = *schedule.CronExpression
-	}
-	if schedule.IntervalSeconds != nil {
-		triggerData["interval_seconds"] = *schedule.IntervalSeconds
-	}
-
 	// Trigger workflow
-	err := s.triggerHandler.HandleScheduleTrigger(
+	err := s.triggerHandler.HandleScheduledRun(
 		ctx,
 		schedule.TenantID,
+		schedule.WorkflowID,
 		schedule.ID,
-		triggerData,
+		scheduleTriggerData(schedule),
 	)
 
 	if err != nil {
@@ -191,3 +177,36 @@ func (s *WorkflowScheduler) calculateIntervalNextRun(schedule *engine.WorkflowSc
 	next := after.Add(interval)
 	return &next, nil
 }
+
+// scheduleTriggerData builds the trigger of a scheduled run. Payload keys are
+// promoted so templates read them as they would a message's (trigger.sender_id,
+// trigger.channel_id); the schedule's own keys win over the payload's.
+func scheduleTriggerData(schedule *engine.WorkflowSchedule) map[string]any {
+	triggerData := make(map[string]any, len(schedule.Payload)+8)
+	for key, value := range schedule.Payload {
+		triggerData[key] = value
+	}
+
+	triggerData["schedule_id"] = schedule.ID
+	triggerData["schedule_type"] = schedule.ScheduleType
+	triggerData["execution_time"] = time.Now().Unix()
+	triggerData["run_count"] = schedule.RunCount + 1
+
+	if schedule.CronExpression != nil {
+		triggerData["cron_expression"] = *schedule.CronExpression
+	}
+	if schedule.IntervalSeconds != nil {
+		triggerData["interval_seconds"] = *schedule.IntervalSeconds
+	}
+	if schedule.ScheduledAt != nil {
+		triggerData["scheduled_at"] = schedule.ScheduledAt.Format(time.RFC3339)
+	}
+	if schedule.Payload != nil {
+		triggerData["payload"] = schedule.Payload
+	}
+	if schedule.Metadata != nil {
+		triggerData["metadata"] = schedule.Metadata
+	}
+
+	return triggerData
+}
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/Abraxas-365/relay/engine"
@@ -210,6 +211,97 @@ func (s *ScheduleService) CreateOnceSchedule(
 	return schedule, nil
 }
 
+// maxRunAtHorizon bounds how far ahead a one-off run can be scheduled
+const maxRunAtHorizon = 366 * 24 * time.Hour
+
+// runAtLocalLayouts are accepted for run_at without an offset, read in the
+// request's timezone
+var runAtLocalLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+// ScheduleRun schedules one run of a workflow with a payload, e.g. "send the
+// reminder to contact X at 5pm". The workflow runs whatever its trigger type.
+func (s *ScheduleService) ScheduleRun(
+	ctx context.Context,
+	tenantID kernel.TenantID,
+	workflowID kernel.WorkflowID,
+	req engine.RunAtRequest,
+) (*engine.WorkflowSchedule, error) {
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, engine.ErrInvalidScheduleConfig().
+			WithDetail("timezone", timezone).
+			WithCause(err)
+	}
+
+	runAt, err := parseRunAt(req.RunAt, loc)
+	if err != nil {
+		return nil, engine.ErrInvalidScheduleConfig().
+			WithDetail("run_at", req.RunAt).
+			WithDetail("reason", "expected RFC 3339 or 2006-01-02T15:04 in timezone")
+	}
+	if runAt.After(time.Now().Add(maxRunAtHorizon)) {
+		return nil, engine.ErrInvalidScheduleConfig().
+			WithDetail("run_at", req.RunAt).
+			WithDetail("reason", "run_at is more than a year away")
+	}
+
+	// Validate workflow exists
+	workflow, err := s.workflowRepo.FindByID(ctx, workflowID)
+	if err != nil {
+		return nil, engine.ErrWorkflowNotFound().
+			WithDetail("workflow_id", workflowID.String())
+	}
+
+	if workflow.TenantID != tenantID {
+		return nil, engine.ErrWorkflowNotFound().
+			WithDetail("workflow_id", workflowID.String()).
+			WithDetail("reason", "workflow does not belong to tenant")
+	}
+
+	if runAt.Before(time.Now()) {
+		return nil, engine.ErrScheduleInPast().
+			WithDetail("scheduled_at", runAt).
+			WithDetail("current_time", time.Now())
+	}
+
+	schedule := &engine.WorkflowSchedule{
+		ID:           uuid.New().String(),
+		TenantID:     tenantID,
+		WorkflowID:   workflowID,
+		ScheduleType: engine.ScheduleTypeOnce,
+		ScheduledAt:  &runAt,
+		IsActive:     true,
+		NextRunAt:    &runAt,
+		Timezone:     timezone,
+		Metadata:     req.Metadata,
+		Payload:      req.Payload,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := s.scheduleRepo.Save(ctx, *schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func parseRunAt(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range runAtLocalLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", value)
+}
+
 // UpdateSchedule updates an existing schedule
 func (s *ScheduleService) UpdateSchedule(
 	ctx context.Context,
@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// pausedSchedulesSetting records in tenant_config the schedules stopped by a
+// suspension so reactivation only resumes those
+const pausedSchedulesSetting = "lifecycle.paused_schedules"
+
+// TenantLifecycleHook stops the schedules of suspended or deleted tenants
+type TenantLifecycleHook struct {
+	scheduleService  *ScheduleService
+	tenantConfigRepo tenant.TenantConfigRepository
+}
+
+// NewTenantLifecycleHook creates the schedule hook for tenant lifecycle events
+func NewTenantLifecycleHook(scheduleService *ScheduleService, tenantConfigRepo tenant.TenantConfigRepository) *TenantLifecycleHook {
+	return &TenantLifecycleHook{
+		scheduleService:  scheduleService,
+		tenantConfigRepo: tenantConfigRepo,
+	}
+}
+
+// OnTenantLifecycle implements tenant.LifecycleHook
+func (h *TenantLifecycleHook) OnTenantLifecycle(ctx context.Context, tenantID kernel.TenantID, event tenant.LifecycleEvent) error {
+	switch event {
+	case tenant.LifecycleSuspended:
+		return h.pause(ctx, tenantID, true)
+	case tenant.LifecycleDeleted:
+		// Stop them right away so the scheduler doesn't fire while rows are deleted
+		return h.pause(ctx, tenantID, false)
+	case tenant.LifecycleReactivated:
+		return h.resume(ctx, tenantID)
+	}
+	return nil
+}
+
+func (h *TenantLifecycleHook) pause(ctx context.Context, tenantID kernel.TenantID, remember bool) error {
+	schedules, err := h.scheduleService.scheduleRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	var paused []string
+	for _, schedule := range schedules {
+		if schedule.IsActive {
+			paused = append(paused, schedule.ID)
+		}
+	}
+	if len(paused) == 0 {
+		return nil
+	}
+
+	// Remember before deactivating so a partial failure can still be resumed
+	if remember {
+		if err := h.tenantConfigRepo.SaveSetting(ctx, tenantID, pausedSchedulesSetting, strings.Join(paused, ",")); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range paused {
+		if err := h.scheduleService.DeactivateSchedule(ctx, id, tenantID); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("⏸️  Paused %d schedules of tenant %s", len(paused), tenantID)
+	return nil
+}
+
+func (h *TenantLifecycleHook) resume(ctx context.Context, tenantID kernel.TenantID) error {
+	config, err := h.tenantConfigRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	paused := config[pausedSchedulesSetting]
+	if paused == "" {
+		return nil
+	}
+
+	resumed := 0
+	for _, id := range strings.Split(paused, ",") {
+		if err := h.scheduleService.ActivateSchedule(ctx, id, tenantID); err != nil {
+			// Deleted while suspended, or a one-time schedule already in the past
+			log.Printf("⚠️  Could not resume schedule %s of tenant %s: %v", id, tenantID, err)
+			continue
+		}
+		resumed++
+	}
+
+	log.Printf("▶️  Resumed %d schedules of tenant %s", resumed, tenantID)
+	return h.tenantConfigRepo.DeleteSetting(ctx, tenantID, pausedSchedulesSetting)
+}
@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Execution Snapshots
+// ============================================================================
+
+// RecordedRun is a run of a workflow with debug on. Its snapshots hold the
+// context at every node boundary, so the run can be inspected and replayed.
+type RecordedRun struct {
+	ID              string            `json:"id"`
+	TenantID        kernel.TenantID   `json:"tenant_id"`
+	WorkflowID      kernel.WorkflowID `json:"workflow_id"`
+	WorkflowVersion int               `json:"workflow_version"`
+	TriggerData     map[string]any    `json:"trigger_data"`
+	Metadata        map[string]any    `json:"metadata,omitempty"`
+	ResumedAt       string            `json:"resumed_at,omitempty"` // Node a delayed run resumed at
+	Success         bool              `json:"success"`
+	Error           string            `json:"error,omitempty"`
+	StartedAt       time.Time         `json:"started_at"`
+	FinishedAt      *time.Time        `json:"finished_at,omitempty"` // Nil while running
+}
+
+// NodeSnapshot is the context one node ran with and what it returned
+type NodeSnapshot struct {
+	RunID      string         `json:"run_id"`
+	Sequence   int            `json:"sequence"` // Order within the run, from 1
+	NodeID     string         `json:"node_id"`
+	NodeType   NodeType       `json:"node_type"`
+	Context    map[string]any `json:"context"`             // Before the node ran
+	Config     map[string]any `json:"config,omitempty"`    // After expressions; nil when they failed
+	NextNode   string         `json:"next_node,omitempty"` // Branch the node chose, if it routed
+	Result     NodeResult     `json:"result"`
+	RecordedAt time.Time      `json:"recorded_at"`
+}
+
+// ============================================================================
+// Replay
+// ============================================================================
+
+// replayedNodeTypes re-run during a replay: they only compute from the
+// context, or their sends are captured. Every other type has side effects
+// (HTTP calls, LLM calls, tags, delays) and answers from the recording.
+var replayedNodeTypes = map[NodeType]bool{
+	NodeTypeCondition:   true,
+	NodeTypeSwitch:      true,
+	NodeTypeTransform:   true,
+	NodeTypeValidate:    true,
+	NodeTypeLoop:        true,
+	NodeTypeSendMessage: true,
+}
+
+// replayedActions are the ACTION types without side effects
+var replayedActions = map[string]bool{
+	"console_log":      true,
+	"set_context":      true,
+	ActionSetVariables: true,
+}
+
+// ReplaySession makes the executor answer the nodes with side effects from
+// a recorded run instead of running them
+type ReplaySession struct {
+	recorded map[string]NodeSnapshot
+
+	mu     sync.Mutex
+	mocked []string
+}
+
+// NewReplaySession keeps the first snapshot of every node of the run
+func NewReplaySession(snapshots []NodeSnapshot) *ReplaySession {
+	recorded := make(map[string]NodeSnapshot, len(snapshots))
+	for _, snapshot := range snapshots {
+		if _, ok := recorded[snapshot.NodeID]; !ok {
+			recorded[snapshot.NodeID] = snapshot
+		}
+	}
+	return &ReplaySession{recorded: recorded}
+}
+
+// Replays reports whether the node runs again rather than being mocked
+func Replays(node WorkflowNode) bool {
+	if node.Type == NodeTypeAction {
+		actionType, _ := node.Config["action_type"].(string)
+		return replayedActions[actionType]
+	}
+	return replayedNodeTypes[node.Type]
+}
+
+// Mock returns the recorded result of a node with side effects and restores
+// the branch it chose. A node the recorded run never reached fails, since
+// running it would reach the outside world. ok is false for nodes that run
+// again.
+func (s *ReplaySession) Mock(node WorkflowNode, nodeContext map[string]any) (result *NodeResult, ok bool) {
+	if Replays(node) {
+		return nil, false
+	}
+
+	s.mu.Lock()
+	s.mocked = append(s.mocked, node.ID)
+	s.mu.Unlock()
+
+	snapshot, recorded := s.recorded[node.ID]
+	if !recorded {
+		return &NodeResult{
+			NodeID:    node.ID,
+			NodeName:  node.Name,
+			Success:   false,
+			Error:     fmt.Sprintf("node %s was not reached by the recorded run and has side effects, so it is not replayed", node.ID),
+			Timestamp: time.Now(),
+		}, true
+	}
+
+	if snapshot.NextNode != "" {
+		nodeContext["__next_node"] = snapshot.NextNode
+	}
+	mocked := snapshot.Result
+	mocked.Output = DeepCopyMap(snapshot.Result.Output)
+	mocked.Timestamp = time.Now()
+	return &mocked, true
+}
+
+// Mocked lists the nodes answered from the recording, in order
+func (s *ReplaySession) Mocked() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.mocked...)
+}
+
+type replayKey struct{}
+
+// WithReplay runs the executor in replay mode for ctx
+func WithReplay(ctx context.Context, session *ReplaySession) context.Context {
+	return context.WithValue(ctx, replayKey{}, session)
+}
+
+// ReplayFromContext returns the replay session of ctx, if any
+func ReplayFromContext(ctx context.Context) (*ReplaySession, bool) {
+	session, ok := ctx.Value(replayKey{}).(*ReplaySession)
+	return session, ok
+}
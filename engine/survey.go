@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Surveys
+// ============================================================================
+
+const (
+	// DefaultSurveyTimeout is how long a SURVEY node waits for the answer
+	DefaultSurveyTimeout = 24 * time.Hour
+
+	// MaxSurveyTimeout bounds the wait, like the longest delay
+	MaxSurveyTimeout = 31 * 24 * time.Hour
+
+	// DefaultSurveyAttempts is how many invalid answers are tolerated
+	DefaultSurveyAttempts = 2
+
+	// SurveyPostbackPrefix starts the ID of each score option, followed by
+	// the score: "survey_score:4"
+	SurveyPostbackPrefix = "survey_score:"
+)
+
+// SurveyScale is the rating a SURVEY node asks for
+type SurveyScale string
+
+const (
+	SurveyScaleCSAT SurveyScale = "csat" // 1 to 5
+	SurveyScaleNPS  SurveyScale = "nps"  // 0 to 10
+)
+
+// IsValid checks the scale is known
+func (s SurveyScale) IsValid() bool {
+	return s == SurveyScaleCSAT || s == SurveyScaleNPS
+}
+
+// Range is the lowest and highest score of the scale
+func (s SurveyScale) Range() (int, int) {
+	if s == SurveyScaleNPS {
+		return 0, 10
+	}
+	return 1, 5
+}
+
+// PendingSurvey is a question a SURVEY node sent. The run stays paused
+// until the contact answers or ContinuationID, the timeout, comes due.
+type PendingSurvey struct {
+	TenantID       kernel.TenantID
+	WorkflowID     string
+	NodeID         string
+	NextNodeID     string // Resumed with the answer
+	ChannelID      kernel.ChannelID
+	ConversationID string
+	AgentID        string // Empty = the operator who owns the conversation, if any
+	Scale          SurveyScale
+	Question       string
+	InvalidReply   string
+	MaxAttempts    int
+	ContinuationID string
+	ExpiresAt      time.Time
+
+	// NodeContext is the run's context when the question was sent
+	NodeContext map[string]any
+}
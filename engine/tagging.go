@@ -0,0 +1,29 @@
+package engine
+
+// ============================================================================
+// Conversation Tagging
+// ============================================================================
+
+// TagUpdate changes a conversation's tags. Disposition, when set, replaces
+// the conversation's outcome ("resolved", "refund", ...).
+type TagUpdate struct {
+	Add         []string `json:"add,omitempty"`
+	Remove      []string `json:"remove,omitempty"`
+	Disposition string   `json:"disposition,omitempty"`
+
+	// Source and SetBy attribute the change: a workflow node, an action or
+	// an operator
+	Source string `json:"source"`
+	SetBy  string `json:"set_by,omitempty"`
+}
+
+// IsEmpty reports whether the update changes nothing
+func (u TagUpdate) IsEmpty() bool {
+	return len(u.Add) == 0 && len(u.Remove) == 0 && u.Disposition == ""
+}
+
+// ConversationTags is a conversation's tags after an update
+type ConversationTags struct {
+	Tags        []string `json:"tags"`
+	Disposition string   `json:"disposition,omitempty"`
+}
@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Helpdesk Tickets
+// ============================================================================
+
+// TicketPriority is the urgency of a helpdesk ticket, from low to urgent
+type TicketPriority string
+
+const (
+	TicketPriorityLow    TicketPriority = "low"
+	TicketPriorityNormal TicketPriority = "normal"
+	TicketPriorityHigh   TicketPriority = "high"
+	TicketPriorityUrgent TicketPriority = "urgent"
+)
+
+// TicketPriorities in increasing urgency
+var TicketPriorities = []TicketPriority{TicketPriorityLow, TicketPriorityNormal, TicketPriorityHigh, TicketPriorityUrgent}
+
+func (p TicketPriority) IsValid() bool {
+	return p.Rank() > 0
+}
+
+// Rank orders priorities by urgency; 0 for an unknown priority
+func (p TicketPriority) Rank() int {
+	for i, priority := range TicketPriorities {
+		if p == priority {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// TicketRequest opens a ticket for a conversation, or appends to the one
+// opened before. The priority is the most urgent of Priority and the rules
+// the conversation's tags and sentiment match, normal when none applies.
+type TicketRequest struct {
+	Provider       string // Empty = the tenant's only connection
+	ChannelID      kernel.ChannelID
+	ConversationID string
+
+	Subject string // New tickets only
+	Comment string // Written above the attached transcript
+	Tags    []string
+
+	Priority            TicketPriority            // Empty = from the rules
+	PriorityByTag       map[string]TicketPriority // Conversation tag → priority
+	Sentiment           string                    // Label, e.g. negative
+	PriorityBySentiment map[string]TicketPriority // Sentiment label → priority
+
+	RequesterName  string
+	RequesterEmail string
+	RequesterPhone string
+
+	NewTicket bool // Open a new ticket even when the conversation has one
+}
+
+// TicketResult is the ticket written
+type TicketResult struct {
+	Provider string         `json:"provider"`
+	TicketID string         `json:"ticket_id"`
+	URL      string         `json:"url"`
+	Created  bool           `json:"created"` // false when the messages were appended to the existing ticket
+	Priority TicketPriority `json:"priority"`
+	Messages int            `json:"messages"` // Transcript messages attached
+}
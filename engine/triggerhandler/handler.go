@@ -2,49 +2,74 @@ package triggerhandler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/Abraxas-365/relay/engine"
 	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
 )
 
 // TriggerHandler handles workflow triggers
 type TriggerHandler struct {
 	workflowRepo     engine.WorkflowRepository
 	workflowExecutor engine.WorkflowExecutor
+	conversationLock engine.ConversationLock     // nil = channel messages run concurrently
+	deadLetters      engine.DeadLetterRepository // nil = failures are only logged
+	fallback         engine.FallbackResolver     // nil = unmatched channel messages are ignored
 }
 
 func NewTriggerHandler(
 	workflowRepo engine.WorkflowRepository,
 	workflowExecutor engine.WorkflowExecutor,
+	conversationLock engine.ConversationLock,
+	deadLetters engine.DeadLetterRepository,
 ) *TriggerHandler {
 	return &TriggerHandler{
 		workflowRepo:     workflowRepo,
 		workflowExecutor: workflowExecutor,
+		conversationLock: conversationLock,
+		deadLetters:      deadLetters,
 	}
 }
 
+// SetFallbackResolver hands channel messages no workflow matched to the
+// tenant's fallback workflow or message
+func (h *TriggerHandler) SetFallbackResolver(resolver engine.FallbackResolver) {
+	h.fallback = resolver
+}
+
 // HandleWebhookTrigger handles generic webhook triggers
 func (h *TriggerHandler) HandleWebhookTrigger(
 	ctx context.Context,
 	tenantID kernel.TenantID,
 	triggerData map[string]any,
 ) error {
-	return h.executeTrigger(ctx, engine.TriggerTypeWebhook, tenantID, triggerData, nil)
+	return h.executeTrigger(ctx, engine.TriggerTypeWebhook, tenantID, triggerData, nil, "")
 }
 
-// HandleChannelWebhookTrigger handles channel message triggers
+// HandleChannelWebhookTrigger handles channel message triggers. Messages from
+// the same sender on the same channel are processed one at a time: the call
+// blocks until the previous message's workflows have finished. Only workflows
+// of the channel's environment run.
 func (h *TriggerHandler) HandleChannelWebhookTrigger(
 	ctx context.Context,
 	tenantID kernel.TenantID,
 	channelID kernel.ChannelID,
+	environment kernel.Environment,
 	triggerData map[string]any,
 ) error {
 	filters := map[string]any{
 		"channel_ids": []string{channelID.String()},
+		"environment": environment.OrDefault().String(),
 	}
-	return h.executeTrigger(ctx, engine.TriggerTypeChannelWebhook, tenantID, triggerData, filters)
+
+	lockKey := conversationLockKey(tenantID, channelID.String(), triggerData)
+
+	return h.executeTrigger(ctx, engine.TriggerTypeChannelWebhook, tenantID, triggerData, filters, lockKey)
 }
 
 // HandleScheduleTrigger handles scheduled triggers
@@ -57,7 +82,56 @@ func (h *TriggerHandler) HandleScheduleTrigger(
 	filters := map[string]any{
 		"schedule_id": scheduleID,
 	}
-	return h.executeTrigger(ctx, engine.TriggerTypeSchedule, tenantID, triggerData, filters)
+	return h.executeTrigger(ctx, engine.TriggerTypeSchedule, tenantID, triggerData, filters, "")
+}
+
+// HandleScheduledRun runs the workflow a schedule belongs to, whatever its
+// trigger type. Inactive workflows are skipped like any other trigger would.
+func (h *TriggerHandler) HandleScheduledRun(
+	ctx context.Context,
+	tenantID kernel.TenantID,
+	workflowID kernel.WorkflowID,
+	scheduleID string,
+	triggerData map[string]any,
+) error {
+	workflow, err := h.workflowRepo.FindByID(ctx, workflowID)
+	if err != nil || workflow.TenantID != tenantID {
+		return engine.ErrWorkflowNotFound().
+			WithDetail("workflow_id", workflowID.String()).
+			WithDetail("schedule_id", scheduleID)
+	}
+
+	if !workflow.IsActive {
+		log.Printf("ℹ️  Skipping schedule %s: workflow %s is not active", scheduleID, workflow.Name)
+		return nil
+	}
+
+	go h.executeWorkflow(ctx, workflow, engine.TriggerTypeSchedule, tenantID, triggerData)
+	return nil
+}
+
+// HandleConversationTrigger runs an INACTIVITY or SESSION_EXPIRED workflow
+// for a conversation. It waits for the run, under the conversation lock so
+// it does not interleave with a message arriving meanwhile.
+func (h *TriggerHandler) HandleConversationTrigger(
+	ctx context.Context,
+	workflow *engine.Workflow,
+	triggerType engine.TriggerType,
+	tenantID kernel.TenantID,
+	triggerData map[string]any,
+) {
+	channelID, _ := triggerData["channel_id"].(string)
+	lockKey := conversationLockKey(tenantID, channelID, triggerData)
+	if lockKey != "" && h.conversationLock != nil {
+		release, err := h.conversationLock.Acquire(ctx, lockKey)
+		if err != nil {
+			log.Printf("⚠️  Running follow-up %s without conversation lock: %v", lockKey, err)
+			release = func() {}
+		}
+		defer release()
+	}
+
+	h.executeWorkflow(ctx, workflow, triggerType, tenantID, triggerData)
 }
 
 // HandleManualTrigger handles manual workflow execution
@@ -81,6 +155,7 @@ func (h *TriggerHandler) HandleManualTrigger(
 		TenantID:    tenantID,
 		Metadata: map[string]any{
 			"trigger_type": engine.TriggerTypeManual,
+			"workflow_id":  workflow.ID.String(),
 		},
 	}
 
@@ -93,13 +168,86 @@ func (h *TriggerHandler) HandleManualTrigger(
 	return nil
 }
 
-// executeTrigger is the core trigger execution logic
+// SimulateChannelTrigger runs the workflows a channel message would trigger,
+// synchronously, and returns every execution result. When workflowID is set
+// only that workflow runs, even if it is not active yet.
+func (h *TriggerHandler) SimulateChannelTrigger(
+	ctx context.Context,
+	tenantID kernel.TenantID,
+	channelID kernel.ChannelID,
+	environment kernel.Environment,
+	workflowID *kernel.WorkflowID,
+	triggerData map[string]any,
+) ([]engine.WorkflowExecutionResponse, error) {
+	var workflows []*engine.Workflow
+
+	if workflowID != nil {
+		workflow, err := h.workflowRepo.FindByID(ctx, *workflowID)
+		if err != nil || workflow.TenantID != tenantID {
+			return nil, engine.ErrWorkflowNotFound().
+				WithDetail("workflow_id", workflowID.String())
+		}
+		workflows = append(workflows, workflow)
+	} else {
+		trigger := engine.WorkflowTrigger{
+			Type: engine.TriggerTypeChannelWebhook,
+			Filters: map[string]any{
+				"channel_ids": []string{channelID.String()},
+				"environment": environment.OrDefault().String(),
+			},
+		}
+
+		found, err := h.workflowRepo.FindActiveByTrigger(ctx, trigger, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find workflows: %w", err)
+		}
+		workflows = inEnvironment(found, trigger.Filters)
+	}
+
+	log.Printf("🧪 Simulating channel message on %s (%d workflow(s))", channelID.String(), len(workflows))
+
+	responses := make([]engine.WorkflowExecutionResponse, 0, len(workflows))
+	for _, wf := range workflows {
+		input := engine.WorkflowInput{
+			TriggerData: triggerData,
+			TenantID:    tenantID,
+			Metadata: map[string]any{
+				"trigger_type": engine.TriggerTypeChannelWebhook,
+				"workflow_id":  wf.ID.String(),
+				"simulation":   true,
+			},
+		}
+
+		response := engine.WorkflowExecutionResponse{WorkflowID: wf.ID}
+
+		result, err := h.workflowExecutor.Execute(ctx, *wf, input)
+		if result != nil {
+			response.Success = result.Success
+			response.Output = result.Output
+			response.Error = result.ErrorMessage
+			response.ExecutedNodes = result.ExecutedNodes
+		}
+		if err != nil {
+			response.Success = false
+			response.Error = err.Error()
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+// executeTrigger is the core trigger execution logic. With a lockKey the
+// matching workflows run under the conversation lock and the call waits for
+// them; otherwise they run in the background.
 func (h *TriggerHandler) executeTrigger(
 	ctx context.Context,
 	triggerType engine.TriggerType,
 	tenantID kernel.TenantID,
 	triggerData map[string]any,
 	filters map[string]any,
+	lockKey string,
 ) error {
 	log.Printf("🔔 Handling trigger: type=%s, tenant=%s", triggerType, tenantID.String())
 
@@ -112,9 +260,15 @@ func (h *TriggerHandler) executeTrigger(
 	// Find matching workflows
 	workflows, err := h.workflowRepo.FindActiveByTrigger(ctx, trigger, tenantID)
 	if err != nil {
-		return fmt.Errorf("failed to find workflows: %w", err)
+		err = fmt.Errorf("failed to find workflows: %w", err)
+		h.deadLetter(ctx, triggerType, tenantID, nil, filters, triggerData, err)
+		return err
 	}
+	workflows = inEnvironment(workflows, filters)
 
+	if len(workflows) == 0 {
+		workflows = h.fallbackWorkflows(ctx, triggerType, tenantID, filters, triggerData)
+	}
 	if len(workflows) == 0 {
 		log.Printf("ℹ️  No active workflows found for trigger type: %s", triggerType)
 		return nil
@@ -122,30 +276,227 @@ func (h *TriggerHandler) executeTrigger(
 
 	log.Printf("📋 Found %d matching workflow(s)", len(workflows))
 
-	// Execute each matching workflow (async to not block)
+	if lockKey == "" || h.conversationLock == nil {
+		// Execute each matching workflow (async to not block)
+		for _, workflow := range workflows {
+			go h.executeWorkflow(ctx, workflow, triggerType, tenantID, triggerData)
+		}
+		return nil
+	}
+
+	release, err := h.conversationLock.Acquire(ctx, lockKey)
+	if err != nil {
+		// Better out of order than dropped
+		log.Printf("⚠️  Processing %s without conversation lock: %v", lockKey, err)
+		release = func() {}
+	}
+	defer release()
+
+	// Workflows of one message still run in parallel
+	var wg sync.WaitGroup
 	for _, workflow := range workflows {
+		wg.Add(1)
 		go func(wf *engine.Workflow) {
-			log.Printf("▶️  Executing workflow: %s", wf.Name)
-
-			input := engine.WorkflowInput{
-				TriggerData: triggerData,
-				TenantID:    tenantID,
-				Metadata: map[string]any{
-					"trigger_type": triggerType,
-					"workflow_id":  wf.ID.String(),
-				},
-			}
-
-			result, err := h.workflowExecutor.Execute(ctx, *wf, input)
-			if err != nil {
-				log.Printf("❌ Workflow %s execution failed: %v", wf.Name, err)
-				return
-			}
-
-			log.Printf("✅ Workflow %s executed (success=%v, nodes=%d)",
-				wf.Name, result.Success, len(result.ExecutedNodes))
+			defer wg.Done()
+			h.executeWorkflow(ctx, wf, triggerType, tenantID, triggerData)
 		}(workflow)
 	}
+	wg.Wait()
 
 	return nil
 }
+
+// inEnvironment keeps the workflows of the environment the trigger came from.
+// Triggers without one (webhooks, schedules) match every environment.
+func inEnvironment(workflows []*engine.Workflow, filters map[string]any) []*engine.Workflow {
+	environment, _ := filters["environment"].(string)
+	if environment == "" {
+		return workflows
+	}
+
+	matched := make([]*engine.Workflow, 0, len(workflows))
+	for _, wf := range workflows {
+		if wf.Environment.OrDefault().String() == environment {
+			matched = append(matched, wf)
+		}
+	}
+	return matched
+}
+
+// fallbackWorkflows returns the fallback workflow of a channel message no
+// workflow matched; it runs as if it had matched
+func (h *TriggerHandler) fallbackWorkflows(
+	ctx context.Context,
+	triggerType engine.TriggerType,
+	tenantID kernel.TenantID,
+	filters map[string]any,
+	triggerData map[string]any,
+) []*engine.Workflow {
+	if h.fallback == nil || triggerType != engine.TriggerTypeChannelWebhook {
+		return nil
+	}
+	channelIDs, _ := filters["channel_ids"].([]string)
+	if len(channelIDs) == 0 {
+		return nil
+	}
+	environment, _ := filters["environment"].(string)
+
+	wf := h.fallback.ResolveFallback(ctx, tenantID, kernel.ChannelID(channelIDs[0]), kernel.Environment(environment), triggerData)
+	if wf == nil {
+		return nil
+	}
+	log.Printf("↪️  No workflow matched, running fallback workflow %s", wf.Name)
+	return []*engine.Workflow{wf}
+}
+
+func (h *TriggerHandler) executeWorkflow(
+	ctx context.Context,
+	wf *engine.Workflow,
+	triggerType engine.TriggerType,
+	tenantID kernel.TenantID,
+	triggerData map[string]any,
+) {
+	if err := h.runWorkflow(ctx, wf, triggerType, tenantID, triggerData); err != nil {
+		h.deadLetter(ctx, triggerType, tenantID, &wf.ID, nil, triggerData, err)
+	}
+}
+
+// runWorkflow executes one workflow and returns an error when the message was
+// not processed: the execution failed, or a node failed with no OnFailure
+// branch to handle it
+func (h *TriggerHandler) runWorkflow(
+	ctx context.Context,
+	wf *engine.Workflow,
+	triggerType engine.TriggerType,
+	tenantID kernel.TenantID,
+	triggerData map[string]any,
+) error {
+	log.Printf("▶️  Executing workflow: %s", wf.Name)
+
+	input := engine.WorkflowInput{
+		TriggerData: triggerData,
+		TenantID:    tenantID,
+		Metadata: map[string]any{
+			"trigger_type": triggerType,
+			"workflow_id":  wf.ID.String(),
+		},
+	}
+
+	result, err := h.workflowExecutor.Execute(ctx, *wf, input)
+	if err != nil {
+		log.Printf("❌ Workflow %s execution failed: %v", wf.Name, err)
+		return err
+	}
+
+	log.Printf("✅ Workflow %s executed (success=%v, nodes=%d)",
+		wf.Name, result.Success, len(result.ExecutedNodes))
+
+	// A failure routed through OnFailure ends on the handler node; ending on
+	// the failed node means nothing handled it
+	if n := len(result.ExecutedNodes); !result.Success && n > 0 && !result.ExecutedNodes[n-1].Success {
+		last := result.ExecutedNodes[n-1]
+		return engine.ErrWorkflowExecutionFailed().
+			WithDetail("workflow_id", wf.ID.String()).
+			WithDetail("node_id", last.NodeID).
+			WithDetail("reason", last.Error)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Dead Letters
+// ============================================================================
+
+// Reprocess runs a dead-lettered trigger again, synchronously. A letter
+// recorded for one workflow reruns only that workflow from its first node; a
+// letter recorded before any workflow matched goes through matching again.
+// Failures are returned instead of creating another letter.
+func (h *TriggerHandler) Reprocess(ctx context.Context, dl *engine.DeadLetter) error {
+	log.Printf("🔁 Reprocessing dead letter %s (type=%s, tenant=%s)", dl.ID, dl.TriggerType, dl.TenantID)
+
+	var workflows []*engine.Workflow
+	if dl.WorkflowID != nil {
+		wf, err := h.workflowRepo.FindByID(ctx, *dl.WorkflowID)
+		if err != nil || wf.TenantID != dl.TenantID {
+			return engine.ErrWorkflowNotFound().WithDetail("workflow_id", dl.WorkflowID.String())
+		}
+		workflows = append(workflows, wf)
+	} else {
+		trigger := engine.WorkflowTrigger{Type: dl.TriggerType, Filters: dl.Filters}
+		found, err := h.workflowRepo.FindActiveByTrigger(ctx, trigger, dl.TenantID)
+		if err != nil {
+			return fmt.Errorf("failed to find workflows: %w", err)
+		}
+		workflows = inEnvironment(found, dl.Filters)
+	}
+
+	// Channel messages keep their place in the conversation
+	channelID, _ := dl.Payload["channel_id"].(string)
+	lockKey := conversationLockKey(dl.TenantID, channelID, dl.Payload)
+	if dl.TriggerType == engine.TriggerTypeChannelWebhook && lockKey != "" && h.conversationLock != nil {
+		release, err := h.conversationLock.Acquire(ctx, lockKey)
+		if err != nil {
+			log.Printf("⚠️  Reprocessing %s without conversation lock: %v", lockKey, err)
+			release = func() {}
+		}
+		defer release()
+	}
+
+	var errs []error
+	for _, wf := range workflows {
+		if err := h.runWorkflow(ctx, wf, dl.TriggerType, dl.TenantID, dl.Payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deadLetter keeps a failed trigger so it can be reprocessed
+func (h *TriggerHandler) deadLetter(
+	ctx context.Context,
+	triggerType engine.TriggerType,
+	tenantID kernel.TenantID,
+	workflowID *kernel.WorkflowID,
+	filters map[string]any,
+	triggerData map[string]any,
+	cause error,
+) {
+	if h.deadLetters == nil {
+		return
+	}
+
+	now := time.Now()
+	dl := engine.DeadLetter{
+		ID:          uuid.NewString(),
+		TenantID:    tenantID,
+		TriggerType: triggerType,
+		WorkflowID:  workflowID,
+		Filters:     filters,
+		Payload:     triggerData,
+		Error:       cause.Error(),
+		Status:      engine.DeadLetterPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	// The trigger ctx may be the one that failed
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := h.deadLetters.Save(saveCtx, dl); err != nil {
+		log.Printf("❌ Failed to dead-letter %s trigger for tenant %s, message lost: %v", triggerType, tenantID, err)
+		return
+	}
+	log.Printf("📮 Trigger dead-lettered as %s: %v", dl.ID, cause)
+}
+
+// conversationLockKey identifies the conversation of a channel message, empty
+// when the sender is unknown
+func conversationLockKey(tenantID kernel.TenantID, channelID string, triggerData map[string]any) string {
+	senderID, _ := triggerData["sender_id"].(string)
+	if senderID == "" || channelID == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s:%s", tenantID, channelID, senderID)
+}
@@ -0,0 +1,465 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+)
+
+// ============================================================================
+// Context Variables
+// ============================================================================
+
+// VariablesContextKey is where a run keeps its declared variables, read as
+// {{vars.<name>}}
+const VariablesContextKey = "vars"
+
+// SetVariablesOutputKey is the node output field holding the variables a node
+// writes. The executor coerces them against the workflow's schema, stores
+// them under vars and drops the field from the output.
+const SetVariablesOutputKey = "__set_variables"
+
+// ActionSetVariables is the ACTION type that writes declared variables
+const ActionSetVariables = "set_variables"
+
+// VariableType is the type of a declared context variable
+type VariableType string
+
+const (
+	VariableString  VariableType = "string"
+	VariableNumber  VariableType = "number"
+	VariableInteger VariableType = "integer"
+	VariableBoolean VariableType = "boolean"
+	VariableObject  VariableType = "object"
+	VariableArray   VariableType = "array"
+)
+
+// variableNamePattern matches names usable as {{vars.<name>}}
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+
+// ContextVariable declares one variable a workflow's nodes read and write
+type ContextVariable struct {
+	Name        string       `json:"name"`
+	Type        VariableType `json:"type"`
+	Default     any          `json:"default,omitempty"` // Nil = the type's zero value
+	Description string       `json:"description,omitempty"`
+}
+
+// VariableSchema is the set of variables a workflow declares
+type VariableSchema []ContextVariable
+
+// Validate checks names are unique identifiers, types are known and defaults
+// match their type
+func (s VariableSchema) Validate() error {
+	seen := make(map[string]bool, len(s))
+	for _, variable := range s {
+		if !variableNamePattern.MatchString(variable.Name) {
+			return ErrInvalidVariableSchema().
+				WithDetail("variable", variable.Name).
+				WithDetail("reason", "names are letters, digits and '_', not starting with a digit")
+		}
+		if seen[variable.Name] {
+			return ErrInvalidVariableSchema().
+				WithDetail("variable", variable.Name).
+				WithDetail("reason", "duplicate variable")
+		}
+		seen[variable.Name] = true
+
+		if !variable.Type.IsValid() {
+			return ErrInvalidVariableSchema().
+				WithDetail("variable", variable.Name).
+				WithDetail("type", string(variable.Type)).
+				WithDetail("reason", "type must be string, number, integer, boolean, object or array")
+		}
+		if variable.Default != nil {
+			if _, err := variable.Coerce(variable.Default); err != nil {
+				return ErrInvalidVariableSchema().
+					WithDetail("variable", variable.Name).
+					WithDetail("reason", "default does not match the type")
+			}
+		}
+	}
+	return nil
+}
+
+// Lookup returns the declared variable with the name
+func (s VariableSchema) Lookup(name string) (ContextVariable, bool) {
+	for _, variable := range s {
+		if variable.Name == name {
+			return variable, true
+		}
+	}
+	return ContextVariable{}, false
+}
+
+// Initial returns every variable set to its default, the vars a run starts
+// with
+func (s VariableSchema) Initial() map[string]any {
+	vars := make(map[string]any, len(s))
+	for _, variable := range s {
+		vars[variable.Name] = variable.DefaultValue()
+	}
+	return vars
+}
+
+// Restore re-coerces the vars saved by a paused run, whose numbers come back
+// as float64 from JSON. Variables declared since are set to their default
+// and the ones no longer declared are dropped.
+func (s VariableSchema) Restore(saved map[string]any) map[string]any {
+	vars := s.Initial()
+	for name, value := range saved {
+		variable, ok := s.Lookup(name)
+		if !ok {
+			continue
+		}
+		if coerced, err := variable.Coerce(value); err == nil {
+			vars[name] = coerced
+		}
+	}
+	return vars
+}
+
+// Assign coerces values against the schema and writes them to vars. Nothing
+// is written when any value is undeclared or of the wrong type.
+func (s VariableSchema) Assign(vars, values map[string]any) error {
+	coerced := make(map[string]any, len(values))
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		variable, ok := s.Lookup(name)
+		if !ok {
+			return ErrUndeclaredVariable().WithDetail("variables", []string{name})
+		}
+		value, err := variable.Coerce(values[name])
+		if err != nil {
+			return err
+		}
+		coerced[name] = value
+	}
+	maps.Copy(vars, coerced)
+	return nil
+}
+
+// IsValid reports whether the type is one of the supported ones
+func (t VariableType) IsValid() bool {
+	switch t {
+	case VariableString, VariableNumber, VariableInteger, VariableBoolean, VariableObject, VariableArray:
+		return true
+	}
+	return false
+}
+
+// DefaultValue is the declared default, or the type's zero value
+func (v ContextVariable) DefaultValue() any {
+	if v.Default != nil {
+		if value, err := v.Coerce(v.Default); err == nil {
+			return value
+		}
+	}
+	switch v.Type {
+	case VariableString:
+		return ""
+	case VariableNumber:
+		return float64(0)
+	case VariableInteger:
+		return int64(0)
+	case VariableBoolean:
+		return false
+	case VariableObject:
+		return map[string]any{}
+	case VariableArray:
+		return []any{}
+	}
+	return nil
+}
+
+// Coerce converts a value to the variable's type. Strings holding numbers
+// or booleans are parsed, since templates render to strings. A nil value
+// resets the variable to its default.
+func (v ContextVariable) Coerce(value any) (any, error) {
+	if value == nil {
+		return v.DefaultValue(), nil
+	}
+
+	var (
+		coerced any
+		ok      bool
+	)
+	switch v.Type {
+	case VariableString:
+		coerced, ok = coerceString(value)
+	case VariableNumber:
+		coerced, ok = coerceNumber(value)
+	case VariableInteger:
+		coerced, ok = coerceInteger(value)
+	case VariableBoolean:
+		coerced, ok = coerceBoolean(value)
+	case VariableObject:
+		coerced, ok = value.(map[string]any)
+	case VariableArray:
+		coerced, ok = coerceArray(value)
+	}
+
+	if !ok {
+		return nil, ErrInvalidVariableValue().
+			WithDetail("variable", v.Name).
+			WithDetail("type", string(v.Type)).
+			WithDetail("value", fmt.Sprintf("%v", value))
+	}
+	return coerced, nil
+}
+
+func coerceString(value any) (any, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool, int, int32, int64, uint, uint32, uint64, float32, float64, json.Number:
+		return fmt.Sprint(v), true
+	}
+	return nil, false
+}
+
+func coerceNumber(value any) (any, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return nil, false
+}
+
+func coerceInteger(value any) (any, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case float32:
+		return coerceInteger(float64(v))
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > math.MaxInt64 {
+			return nil, false
+		}
+		return int64(v), true
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		return i, err == nil
+	}
+	return nil, false
+}
+
+func coerceBoolean(value any) (any, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		return b, err == nil
+	}
+	return nil, false
+}
+
+func coerceArray(value any) (any, bool) {
+	if list, ok := value.([]any); ok {
+		return list, true
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	list := make([]any, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list, true
+}
+
+// ============================================================================
+// Variable References
+// ============================================================================
+
+// VariableRef is one use of a context variable found in a workflow
+type VariableRef struct {
+	Name   string `json:"name"`
+	NodeID string `json:"node_id"`
+	Field  string `json:"field"`
+}
+
+// CollectVariableRefs returns the variables the workflow's nodes read in
+// their templates ({{vars.name}} or {{vars['name']}}) and the ones
+// set_variables actions write
+func CollectVariableRefs(workflow Workflow) []VariableRef {
+	var refs []VariableRef
+	for _, node := range workflow.Nodes {
+		collectTemplateVariables(node.Config, "", func(name, field string) {
+			refs = append(refs, VariableRef{Name: name, NodeID: node.ID, Field: field})
+		})
+
+		if node.Type == NodeTypeAction && node.Config["action_type"] == ActionSetVariables {
+			values, _ := node.Config["variables"].(map[string]any)
+			for _, name := range slices.Sorted(maps.Keys(values)) {
+				refs = append(refs, VariableRef{Name: name, NodeID: node.ID, Field: "variables." + name})
+			}
+		}
+	}
+	return refs
+}
+
+// CheckVariableRefs reports, in a single error, every variable the workflow
+// uses without declaring it, and the literal values set_variables actions
+// write that do not match their variable's type. Workflows that declare no
+// variables and have a node with the ID "vars" predate the schema and are
+// not checked.
+func CheckVariableRefs(workflow Workflow) error {
+	if len(workflow.Variables) == 0 && workflow.GetNodeByID(VariablesContextKey) != nil {
+		return nil
+	}
+
+	var undeclared []VariableRef
+	for _, ref := range CollectVariableRefs(workflow) {
+		if _, ok := workflow.Variables.Lookup(ref.Name); !ok {
+			undeclared = append(undeclared, ref)
+		}
+	}
+	if len(undeclared) > 0 {
+		return ErrUndeclaredVariable().
+			WithDetail("workflow_id", workflow.ID.String()).
+			WithDetail("undeclared", undeclared)
+	}
+
+	// Templated values are only known at run time, when the executor checks
+	// them
+	for _, node := range workflow.Nodes {
+		if node.Type != NodeTypeAction || node.Config["action_type"] != ActionSetVariables {
+			continue
+		}
+		values, _ := node.Config["variables"].(map[string]any)
+		for _, name := range slices.Sorted(maps.Keys(values)) {
+			if s, ok := values[name].(string); ok && IsTemplate(s) {
+				continue
+			}
+			variable, _ := workflow.Variables.Lookup(name)
+			if _, err := variable.Coerce(values[name]); err != nil {
+				var xerr *errx.Error
+				if errors.As(err, &xerr) {
+					return xerr.WithDetail("node_id", node.ID)
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// collectTemplateVariables calls found for every vars.<name> read by the
+// templates in a config value
+func collectTemplateVariables(value any, path string, found func(name, field string)) {
+	walkTemplateExprs(value, path, func(e ast.Expr, field string) {
+		if name, ok := variableAccess(e); ok {
+			found(name, field)
+		}
+	})
+}
+
+// walkTemplateExprs visits every CEL expression node of the templates in a
+// config value, with the dotted path of the field holding the template
+func walkTemplateExprs(value any, path string, visit func(e ast.Expr, field string)) {
+	switch v := value.(type) {
+	case string:
+		if !strings.Contains(v, "{{") {
+			return
+		}
+		env, err := templateEnv()
+		if err != nil {
+			return
+		}
+		for _, match := range templatePattern.FindAllStringSubmatch(v, -1) {
+			parsed, issues := env.Parse(strings.TrimSpace(match[1]))
+			if issues != nil && issues.Err() != nil {
+				continue // Reported by CheckConfigTemplates
+			}
+			ast.PreOrderVisit(parsed.NativeRep().Expr(), ast.NewExprVisitor(func(e ast.Expr) {
+				visit(e, path)
+			}))
+		}
+
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			field := key
+			if path != "" {
+				field = path + "." + key
+			}
+			walkTemplateExprs(v[key], field, visit)
+		}
+
+	case []any:
+		for i, item := range v {
+			walkTemplateExprs(item, fmt.Sprintf("%s[%d]", path, i), visit)
+		}
+	}
+}
+
+// variableAccess matches vars.name and vars['name']
+func variableAccess(e ast.Expr) (string, bool) {
+	switch e.Kind() {
+	case ast.SelectKind:
+		sel := e.AsSelect()
+		if isVarsIdent(sel.Operand()) {
+			return sel.FieldName(), true
+		}
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.FunctionName() != operators.Index || len(call.Args()) != 2 || !isVarsIdent(call.Args()[0]) {
+			return "", false
+		}
+		if key := call.Args()[1]; key.Kind() == ast.LiteralKind {
+			if name, ok := key.AsLiteral().Value().(string); ok {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+func isVarsIdent(e ast.Expr) bool {
+	return e.Kind() == ast.IdentKind && e.AsIdent() == VariablesContextKey
+}
@@ -0,0 +1,86 @@
+package workflowexec
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/relay/engine"
+)
+
+// SetEventBus publishes workflow.started, node.completed and
+// workflow.completed / workflow.failed for every run that is not a
+// simulation. A run resumed after a delay does not start again.
+func (e *DefaultWorkflowExecutor) SetEventBus(bus eventx.EventBus) {
+	e.events = bus
+}
+
+// runEvents publishes the events of one run with the attributes they share
+type runEvents struct {
+	bus  eventx.EventBus // nil = the run publishes nothing
+	base engine.ExecutionEvent
+}
+
+func (e *DefaultWorkflowExecutor) runEvents(workflow engine.Workflow, input engine.WorkflowInput) *runEvents {
+	events := &runEvents{base: engine.NewExecutionEvent(workflow, input)}
+	if simulation, _ := input.Metadata["simulation"].(bool); !simulation {
+		events.bus = e.events
+	}
+	return events
+}
+
+func (r *runEvents) workflowStarted(ctx context.Context) {
+	event := r.base
+	event.Success = true
+	r.publish(ctx, engine.EventWorkflowStarted, event)
+}
+
+func (r *runEvents) nodeCompleted(ctx context.Context, node engine.WorkflowNode, result *engine.NodeResult) {
+	event := r.base
+	event.NodeID = node.ID
+	event.NodeType = node.Type
+	event.Success = result.Success
+	event.Error = result.Error
+	event.DurationMs = result.Duration
+	r.publish(ctx, engine.EventNodeCompleted, event)
+}
+
+func (r *runEvents) workflowFinished(ctx context.Context, result *engine.ExecutionResult, duration time.Duration) {
+	event := r.base
+	event.Success = result.Success
+	event.Error = result.ErrorMessage
+	event.DurationMs = duration.Milliseconds()
+
+	eventType := engine.EventWorkflowCompleted
+	if !result.Success {
+		eventType = engine.EventWorkflowFailed
+	}
+	r.publish(ctx, eventType, event)
+}
+
+// workflowRejected reports a run that failed before its first node
+func (r *runEvents) workflowRejected(ctx context.Context, err error) {
+	event := r.base
+	event.Error = err.Error()
+	r.publish(ctx, engine.EventWorkflowFailed, event)
+}
+
+// publish never fails the run: consumers are best effort
+func (r *runEvents) publish(ctx context.Context, eventType string, event engine.ExecutionEvent) {
+	if r.bus == nil {
+		return
+	}
+	event.At = time.Now()
+
+	opts := eventx.DefaultEventOptions()
+	opts.Source = "engine"
+	opts.Metadata = map[string]any{
+		"tenant_id":       event.TenantID.String(),
+		"workflow_id":     event.WorkflowID.String(),
+		"conversation_id": event.ConversationID,
+	}
+	if err := r.bus.Publish(ctx, eventx.NewEvent(eventType, event, opts)); err != nil {
+		log.Printf("⚠️  Failed to publish %s event for workflow %s: %v", eventType, event.WorkflowID, err)
+	}
+}
@@ -2,20 +2,31 @@ package workflowexec
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"runtime/debug"
+	"slices"
 	"time"
 
 	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/eventx"
 	"github.com/Abraxas-365/relay/engine"
 )
 
 type DefaultWorkflowExecutor struct {
 	nodeExecutors       map[engine.NodeType]engine.NodeExecutor
 	expressionEvaluator engine.ExpressionEvaluator
+	resourceResolver    engine.ResourceResolver   // nil = references are not checked
+	maxOutputBytes      int                       // 0 = node outputs are not limited
+	outputSpiller       engine.OutputSpiller      // nil = oversized fields are only truncated
+	events              eventx.EventBus           // nil = runs publish no events
+	snapshots           engine.SnapshotRepository // nil = debug runs are not recorded
+	middleware          []engine.ExecutorMiddleware
 }
 
 var _ engine.WorkflowExecutor = (*DefaultWorkflowExecutor)(nil)
+var _ engine.NodeTypeDeclarer = (*DefaultWorkflowExecutor)(nil)
 
 func NewDefaultWorkflowExecutor(
 	expressionEvaluator engine.ExpressionEvaluator,
@@ -33,25 +44,47 @@ func NewDefaultWorkflowExecutor(
 	return executor
 }
 
+// SetResourceResolver makes ValidateWorkflow check that the channels,
+// snippets and other resources the workflow references exist
+func (e *DefaultWorkflowExecutor) SetResourceResolver(resolver engine.ResourceResolver) {
+	e.resourceResolver = resolver
+}
+
 func (e *DefaultWorkflowExecutor) RegisterNodeExecutor(executor engine.NodeExecutor) {
 	// Register for all supported types
-	for _, nodeType := range []engine.NodeType{
-		engine.NodeTypeCondition,
-		engine.NodeTypeAction,
-		engine.NodeTypeDelay,
-		engine.NodeTypeAIAgent,
-		engine.NodeTypeSendMessage,
-		engine.NodeTypeHTTP,
-		engine.NodeTypeTransform,
-		engine.NodeTypeSwitch,
-		engine.NodeTypeLoop,
-		engine.NodeTypeValidate,
-	} {
+	for _, nodeType := range engine.BuiltinNodeTypes {
 		if executor.SupportsType(nodeType) {
 			e.nodeExecutors[nodeType] = executor
 			log.Printf("✅ Registered executor for node type: %s", nodeType)
 		}
 	}
+
+	// Custom executors declare their own types
+	declarer, ok := executor.(engine.NodeTypeDeclarer)
+	if !ok {
+		return
+	}
+	for _, nodeType := range declarer.NodeTypes() {
+		if err := engine.ValidateCustomNodeType(nodeType); err != nil {
+			log.Printf("⚠️  Skipping custom node type %q: %v", nodeType, err)
+			continue
+		}
+		if _, exists := e.nodeExecutors[nodeType]; exists {
+			log.Printf("⚠️  Custom node type %s is already registered, replacing its executor", nodeType)
+		}
+		e.nodeExecutors[nodeType] = executor
+		log.Printf("🧩 Registered executor for custom node type: %s", nodeType)
+	}
+}
+
+// NodeTypes returns every node type with a registered executor, sorted
+func (e *DefaultWorkflowExecutor) NodeTypes() []engine.NodeType {
+	types := make([]engine.NodeType, 0, len(e.nodeExecutors))
+	for nodeType := range e.nodeExecutors {
+		types = append(types, nodeType)
+	}
+	slices.Sort(types)
+	return types
 }
 
 // ============================================================================
@@ -72,12 +105,18 @@ func (e *DefaultWorkflowExecutor) Execute(
 		ExecutedNodes: []engine.NodeResult{},
 	}
 
+	events := e.runEvents(workflow, input)
 	if err := e.ValidateWorkflow(ctx, workflow); err != nil {
+		events.workflowRejected(ctx, err)
 		return nil, errx.Wrap(err, "workflow validation failed", errx.TypeValidation)
 	}
+	events.workflowStarted(ctx)
+
+	recorder := e.startRecording(ctx, workflow, input, "")
+	result.RunID = recorder.runID()
 
 	// Prepare initial context from input
-	nodeContext := e.prepareInitialContext(input)
+	nodeContext := e.prepareInitialContext(workflow, input)
 	log.Printf("📦 Initial context keys: %v", getMapKeys(nodeContext))
 
 	// Start from first node
@@ -91,16 +130,21 @@ func (e *DefaultWorkflowExecutor) Execute(
 
 	for currentNodeID != "" && len(result.ExecutedNodes) < maxNodes {
 		if visitedNodes[currentNodeID] {
-			return nil, engine.ErrCyclicWorkflow().
+			err := engine.ErrCyclicWorkflow().
 				WithDetail("node_id", currentNodeID).
 				WithDetail("workflow_id", workflow.ID.String())
+			recorder.abort(ctx, err)
+			return nil, err
 		}
 		visitedNodes[currentNodeID] = true
 
 		node := workflow.GetNodeByID(currentNodeID)
 		if node == nil {
-			return nil, engine.ErrNodeNotFound().WithDetail("node_id", currentNodeID)
+			err := engine.ErrNodeNotFound().WithDetail("node_id", currentNodeID)
+			recorder.abort(ctx, err)
+			return nil, err
 		}
+		before := recorder.capture(nodeContext)
 
 		log.Printf("\n🔹 Processing node: %s (ID: %s, Type: %s)", node.Name, node.ID, node.Type)
 		log.Printf("   📋 Node context keys before eval: %v", getMapKeys(nodeContext))
@@ -113,26 +157,30 @@ func (e *DefaultWorkflowExecutor) Execute(
 			log.Printf("   📋 Available context keys: %v", getMapKeys(nodeContext))
 			log.Printf("   🔍 Context dump: %+v", nodeContext)
 
-			nodeResult := &engine.NodeResult{
-				NodeID:    node.ID,
-				NodeName:  node.Name,
-				Success:   false,
-				Error:     fmt.Sprintf("expression evaluation failed: %v", err),
-				Timestamp: time.Now(),
-			}
+			nodeResult := e.expressionFailure(*node, err)
 			result.ExecutedNodes = append(result.ExecutedNodes, *nodeResult)
-			result.Success = false
-			result.ErrorMessage = nodeResult.Error
+			events.nodeCompleted(ctx, *node, nodeResult)
+			recorder.record(ctx, *node, before, nil, nodeContext, nodeResult)
+			e.recordFailure(nodeContext, result, *node, nodeResult)
+
+			if node.OnFailure != "" {
+				log.Printf("   ↪️  Jumping to failure node: %s", node.OnFailure)
+				currentNodeID = node.OnFailure
+				continue
+			}
+			log.Printf("   🛑 No failure handler, stopping workflow")
 			break
 		}
 
 		log.Printf("   ✅ Config after eval: %+v", evaluatedConfig)
 
+		// Copy so executors can't reach the stored workflow or context values
+		// that expressions resolved to
 		nodeForExecution := *node
-		nodeForExecution.Config = evaluatedConfig
+		nodeForExecution.Config = engine.DeepCopyMap(evaluatedConfig)
 
 		// Execute node
-		nodeResult, err := e.executeNodeInternal(ctx, nodeForExecution, nodeContext, result)
+		nodeResult, err := e.executeNodeInternal(ctx, nodeForExecution, workflow.Variables, nodeContext, result)
 		if err != nil && nodeResult == nil {
 			nodeResult = &engine.NodeResult{
 				NodeID: node.ID, NodeName: node.Name, Success: false,
@@ -144,19 +192,20 @@ func (e *DefaultWorkflowExecutor) Execute(
 		log.Printf("   📤 Node output keys: %v", getMapKeys(nodeResult.Output))
 
 		result.ExecutedNodes = append(result.ExecutedNodes, *nodeResult)
+		events.nodeCompleted(ctx, *node, nodeResult)
+		recorder.record(ctx, *node, before, evaluatedConfig, nodeContext, nodeResult)
 
 		// Check for workflow pause (async delay)
 		if paused, ok := nodeResult.Output["__workflow_paused"].(bool); ok && paused {
 			log.Printf("⏸️  Workflow paused for async delay")
 			result.Success = true
+			recorder.finish(ctx, result)
 			return result, nil
 		}
 
 		if !nodeResult.Success {
 			log.Printf("❌ Node %s failed with error: %s", node.Name, nodeResult.Error)
-			result.Success = false
-			result.Error = fmt.Errorf("node %s failed: %s", node.Name, nodeResult.Error)
-			result.ErrorMessage = nodeResult.Error
+			e.recordFailure(nodeContext, result, *node, nodeResult)
 
 			if node.OnFailure != "" {
 				log.Printf("   ↪️  Jumping to failure node: %s", node.OnFailure)
@@ -198,6 +247,8 @@ func (e *DefaultWorkflowExecutor) Execute(
 	}
 
 	duration := time.Since(startTime)
+	events.workflowFinished(ctx, result, duration)
+	recorder.finish(ctx, result)
 	log.Printf("✅ Workflow execution completed: %s in %v (success=%v)", workflow.Name, duration, result.Success)
 
 	return result, nil
@@ -223,7 +274,9 @@ func (e *DefaultWorkflowExecutor) ResumeFromNode(
 		ExecutedNodes: []engine.NodeResult{},
 	}
 
+	events := e.runEvents(workflow, input)
 	if err := e.ValidateWorkflow(ctx, workflow); err != nil {
+		events.workflowRejected(ctx, err)
 		return nil, errx.Wrap(err, "workflow validation failed", errx.TypeValidation)
 	}
 
@@ -232,15 +285,24 @@ func (e *DefaultWorkflowExecutor) ResumeFromNode(
 		return nil, engine.ErrNodeNotFound().WithDetail("node_id", startNodeID)
 	}
 
-	// Use saved context or create new
-	nodeContext := savedNodeContext
+	recorder := e.startRecording(ctx, workflow, input, startNodeID)
+	result.RunID = recorder.runID()
+
+	// Use a copy of the saved context or create new
+	nodeContext := engine.DeepCopyMap(savedNodeContext)
 	if nodeContext == nil {
-		nodeContext = e.prepareInitialContext(input)
+		nodeContext = e.prepareInitialContext(workflow, input)
 	}
 
 	// Ensure trigger data is available
 	if _, ok := nodeContext["trigger"]; !ok {
-		nodeContext["trigger"] = input.TriggerData
+		nodeContext["trigger"] = engine.DeepCopyMap(input.TriggerData)
+	}
+
+	// Saved vars went through JSON and the schema may have changed since
+	if len(workflow.Variables) > 0 {
+		saved, _ := nodeContext[engine.VariablesContextKey].(map[string]any)
+		nodeContext[engine.VariablesContextKey] = workflow.Variables.Restore(saved)
 	}
 
 	currentNodeID := startNodeID
@@ -249,33 +311,40 @@ func (e *DefaultWorkflowExecutor) ResumeFromNode(
 
 	for currentNodeID != "" && len(result.ExecutedNodes) < maxNodes {
 		if visitedNodes[currentNodeID] {
-			return nil, engine.ErrCyclicWorkflow().
+			err := engine.ErrCyclicWorkflow().
 				WithDetail("node_id", currentNodeID).
 				WithDetail("workflow_id", workflow.ID.String())
+			recorder.abort(ctx, err)
+			return nil, err
 		}
 		visitedNodes[currentNodeID] = true
 
 		node := workflow.GetNodeByID(currentNodeID)
 		if node == nil {
-			return nil, engine.ErrNodeNotFound().WithDetail("node_id", currentNodeID)
+			err := engine.ErrNodeNotFound().WithDetail("node_id", currentNodeID)
+			recorder.abort(ctx, err)
+			return nil, err
 		}
+		before := recorder.capture(nodeContext)
 
 		evaluatedConfig, err := e.evaluateNodeConfig(ctx, node.Config, nodeContext)
 		if err != nil {
-			nodeResult := &engine.NodeResult{
-				NodeID: node.ID, NodeName: node.Name, Success: false,
-				Error: fmt.Sprintf("expression evaluation failed: %v", err), Timestamp: time.Now(),
-			}
+			nodeResult := e.expressionFailure(*node, err)
 			result.ExecutedNodes = append(result.ExecutedNodes, *nodeResult)
-			result.Success = false
-			result.ErrorMessage = nodeResult.Error
+			events.nodeCompleted(ctx, *node, nodeResult)
+			recorder.record(ctx, *node, before, nil, nodeContext, nodeResult)
+			e.recordFailure(nodeContext, result, *node, nodeResult)
+			if node.OnFailure != "" {
+				currentNodeID = node.OnFailure
+				continue
+			}
 			break
 		}
 
 		nodeForExecution := *node
-		nodeForExecution.Config = evaluatedConfig
+		nodeForExecution.Config = engine.DeepCopyMap(evaluatedConfig)
 
-		nodeResult, err := e.executeNodeInternal(ctx, nodeForExecution, nodeContext, result)
+		nodeResult, err := e.executeNodeInternal(ctx, nodeForExecution, workflow.Variables, nodeContext, result)
 		if err != nil && nodeResult == nil {
 			nodeResult = &engine.NodeResult{
 				NodeID: node.ID, NodeName: node.Name, Success: false,
@@ -284,11 +353,18 @@ func (e *DefaultWorkflowExecutor) ResumeFromNode(
 		}
 
 		result.ExecutedNodes = append(result.ExecutedNodes, *nodeResult)
+		events.nodeCompleted(ctx, *node, nodeResult)
+		recorder.record(ctx, *node, before, evaluatedConfig, nodeContext, nodeResult)
+
+		// A delay in the resumed part pauses the run again
+		if paused, ok := nodeResult.Output["__workflow_paused"].(bool); ok && paused {
+			log.Printf("⏸️  Resumed workflow paused for async delay")
+			recorder.finish(ctx, result)
+			return result, nil
+		}
 
 		if !nodeResult.Success {
-			result.Success = false
-			result.Error = fmt.Errorf("node %s failed: %s", node.Name, nodeResult.Error)
-			result.ErrorMessage = nodeResult.Error
+			e.recordFailure(nodeContext, result, *node, nodeResult)
 			if node.OnFailure != "" {
 				currentNodeID = node.OnFailure
 				continue
@@ -319,6 +395,8 @@ func (e *DefaultWorkflowExecutor) ResumeFromNode(
 	}
 
 	duration := time.Since(startTime)
+	events.workflowFinished(ctx, result, duration)
+	recorder.finish(ctx, result)
 	log.Printf("✅ Workflow resume completed: %s in %v", workflow.Name, duration)
 
 	return result, nil
@@ -331,6 +409,7 @@ func (e *DefaultWorkflowExecutor) ResumeFromNode(
 func (e *DefaultWorkflowExecutor) executeNodeInternal(
 	ctx context.Context,
 	node engine.WorkflowNode,
+	variables engine.VariableSchema,
 	nodeContext map[string]any,
 	workflowResult *engine.ExecutionResult,
 ) (*engine.NodeResult, error) {
@@ -351,12 +430,28 @@ func (e *DefaultWorkflowExecutor) executeNodeInternal(
 		Timestamp: startTime,
 	}
 
-	var err error
-
-	// Check for registered executor
-	if executor, ok := e.nodeExecutors[node.Type]; ok {
+	// Middleware may stop the node before it runs
+	ctx, err := e.beforeNode(ctx, node, nodeContext)
+	if err != nil {
+		log.Printf("🛑 Node %s stopped by middleware: %v", node.Name, err)
+	} else if mocked, ok := mockFromReplay(ctx, node, nodeContext); ok {
+		// Replays answer nodes with side effects from the recorded run
+		log.Printf("🎞️  Replaying recorded result of node %s", node.Name)
+		nodeResult = mocked
+	} else if executor, ok := e.nodeExecutors[node.Type]; ok {
+		// Check for registered executor
 		input := nodeContext // Pass entire context as input
-		nodeResult, err = executor.Execute(ctx, node, input)
+		var executed *engine.NodeResult
+		executed, err = runNodeExecutor(ctx, executor, node, input)
+
+		// Plugins may answer (nil, err); keep the prepared result then
+		if executed != nil {
+			nodeResult = executed
+		} else if err == nil {
+			err = engine.ErrNodeExecutionFailed().
+				WithDetail("node_id", node.ID).
+				WithDetail("reason", "executor returned no result")
+		}
 
 		if nodeResult.NodeID == "" {
 			nodeResult.NodeID = node.ID
@@ -365,11 +460,11 @@ func (e *DefaultWorkflowExecutor) executeNodeInternal(
 			nodeResult.NodeName = node.Name
 		}
 
-		if err == nil && nodeResult.Output != nil {
-			for key, value := range nodeResult.Output {
-				workflowResult.Output[key] = value
-			}
+		if err == nil && nodeResult.Success {
+			err = assignVariables(variables, nodeContext, nodeResult)
 		}
+
+		e.limitOutput(ctx, node, nodeContext, nodeResult)
 	} else {
 		log.Printf("❌ No executor found for node type: %s", node.Type)
 		err = engine.ErrInvalidWorkflowNode().
@@ -382,9 +477,20 @@ func (e *DefaultWorkflowExecutor) executeNodeInternal(
 	if err != nil {
 		nodeResult.Success = false
 		nodeResult.Error = err.Error()
+	}
+
+	e.afterNode(ctx, node, nodeContext, nodeResult)
+
+	if err != nil {
 		return nodeResult, err
 	}
 
+	if nodeResult.Output != nil {
+		for key, value := range nodeResult.Output {
+			workflowResult.Output[key] = value
+		}
+	}
+
 	return nodeResult, nil
 }
 
@@ -392,23 +498,134 @@ func (e *DefaultWorkflowExecutor) executeNodeInternal(
 // Helper Functions
 // ============================================================================
 
-func (e *DefaultWorkflowExecutor) prepareInitialContext(input engine.WorkflowInput) map[string]any {
+// runNodeExecutor runs a node executor, turning a panic into a node error.
+// Workflows run in their own goroutines, so a panicking plugin node would
+// otherwise take the whole server down.
+func runNodeExecutor(
+	ctx context.Context,
+	executor engine.NodeExecutor,
+	node engine.WorkflowNode,
+	input map[string]any,
+) (result *engine.NodeResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("💥 Node %s (type: %s) panicked: %v\n%s", node.Name, node.Type, r, debug.Stack())
+			result = nil
+			err = engine.ErrNodeExecutionFailed().
+				WithDetail("node_id", node.ID).
+				WithDetail("reason", fmt.Sprintf("executor panicked: %v", r))
+		}
+	}()
+
+	return executor.Execute(ctx, node, input)
+}
+
+func (e *DefaultWorkflowExecutor) prepareInitialContext(workflow engine.Workflow, input engine.WorkflowInput) map[string]any {
 	context := make(map[string]any)
 
-	// Add trigger data
-	context["trigger"] = input.TriggerData
+	// Add trigger data (copied, the caller may share it with other executions)
+	context["trigger"] = engine.DeepCopyMap(input.TriggerData)
 	context["tenant_id"] = input.TenantID.String()
 
+	// Declared variables start at their defaults
+	if len(workflow.Variables) > 0 {
+		context[engine.VariablesContextKey] = workflow.Variables.Initial()
+	}
+
 	// Add metadata
 	if input.Metadata != nil {
 		for key, value := range input.Metadata {
-			context[key] = value
+			context[key] = engine.DeepCopy(value)
 		}
 	}
 
 	return context
 }
 
+// assignVariables stores the variables a node wrote, coerced to their
+// declared types. A value that is undeclared or does not fit its type fails
+// the node.
+func assignVariables(variables engine.VariableSchema, nodeContext map[string]any, nodeResult *engine.NodeResult) error {
+	values, ok := nodeResult.Output[engine.SetVariablesOutputKey].(map[string]any)
+	if !ok {
+		return nil
+	}
+	delete(nodeResult.Output, engine.SetVariablesOutputKey)
+
+	vars, ok := nodeContext[engine.VariablesContextKey].(map[string]any)
+	if !ok {
+		vars = make(map[string]any)
+	}
+	if err := variables.Assign(vars, values); err != nil {
+		return err
+	}
+	nodeContext[engine.VariablesContextKey] = vars
+
+	log.Printf("   🧮 Set context variables: %v", getMapKeys(values))
+	return nil
+}
+
+// expressionFailure turns a config evaluation error into a failed node result
+// so it follows the node's on_failure edge like any other failure
+func (e *DefaultWorkflowExecutor) expressionFailure(node engine.WorkflowNode, err error) *engine.NodeResult {
+	errorInfo := map[string]any{
+		"type":    "expression",
+		"message": err.Error(),
+	}
+
+	var xerr *errx.Error
+	if errors.As(err, &xerr) && xerr.Details != nil {
+		if expression, ok := xerr.Details["expression"]; ok {
+			errorInfo["expression"] = expression
+		}
+		if variable, ok := xerr.Details["variable"]; ok {
+			errorInfo["variable"] = variable
+		}
+		if reason, ok := xerr.Details["reason"]; ok {
+			errorInfo["message"] = reason
+		}
+	}
+
+	return &engine.NodeResult{
+		NodeID:    node.ID,
+		NodeName:  node.Name,
+		Success:   false,
+		Error:     fmt.Sprintf("expression evaluation failed: %v", err),
+		Output:    map[string]any{"error": errorInfo},
+		Timestamp: time.Now(),
+	}
+}
+
+// recordFailure marks the execution as failed and exposes the error to the
+// failure branch as {{error.message}} and {{<node_id>.error.message}}
+func (e *DefaultWorkflowExecutor) recordFailure(
+	nodeContext map[string]any,
+	result *engine.ExecutionResult,
+	node engine.WorkflowNode,
+	nodeResult *engine.NodeResult,
+) {
+	result.Success = false
+	result.Error = fmt.Errorf("node %s failed: %s", node.Name, nodeResult.Error)
+	result.ErrorMessage = nodeResult.Error
+
+	errorInfo, ok := nodeResult.Output["error"].(map[string]any)
+	if !ok {
+		errorInfo = map[string]any{
+			"type":    "node",
+			"message": nodeResult.Error,
+		}
+	}
+	errorInfo["node_id"] = node.ID
+
+	nodeContext[node.ID] = map[string]any{
+		"output":      nodeResult.Output,
+		"success":     false,
+		"duration_ms": nodeResult.Duration,
+		"error":       errorInfo,
+	}
+	nodeContext["error"] = errorInfo
+}
+
 func (e *DefaultWorkflowExecutor) evaluateNodeConfig(
 	ctx context.Context,
 	config map[string]any,
@@ -449,6 +666,17 @@ func (e *DefaultWorkflowExecutor) ValidateWorkflow(ctx context.Context, workflow
 		return engine.ErrInvalidWorkflowConfig().WithDetail("reason", "workflow has no nodes")
 	}
 
+	if err := workflow.Variables.Validate(); err != nil {
+		return err
+	}
+
+	if workflow.Trigger.Type == engine.TriggerTypeInactivity {
+		if _, err := engine.ExtractInactivityConfig(workflow.Trigger.Config); err != nil {
+			return errx.Wrap(err, "trigger config validation failed", errx.TypeValidation).
+				WithDetail("trigger_type", string(workflow.Trigger.Type))
+		}
+	}
+
 	nodeIDs := make(map[string]bool)
 	for _, node := range workflow.Nodes {
 		if node.ID == "" {
@@ -459,14 +687,30 @@ func (e *DefaultWorkflowExecutor) ValidateWorkflow(ctx context.Context, workflow
 				WithDetail("node_id", node.ID).
 				WithDetail("reason", "duplicate node ID")
 		}
+		if node.ID == engine.VariablesContextKey && len(workflow.Variables) > 0 {
+			return engine.ErrInvalidWorkflowNode().
+				WithDetail("node_id", node.ID).
+				WithDetail("reason", "node ID is reserved for context variables")
+		}
 		nodeIDs[node.ID] = true
 
-		if executor, ok := e.nodeExecutors[node.Type]; ok {
-			if err := executor.ValidateConfig(node.Config); err != nil {
-				return errx.Wrap(err, "node config validation failed", errx.TypeValidation).
-					WithDetail("node_id", node.ID).
-					WithDetail("node_name", node.Name)
-			}
+		if err := engine.CheckConfigTemplates(node.Config); err != nil {
+			return errx.Wrap(err, "node config template is invalid", errx.TypeValidation).
+				WithDetail("node_id", node.ID).
+				WithDetail("node_name", node.Name)
+		}
+
+		executor, ok := e.nodeExecutors[node.Type]
+		if !ok {
+			return engine.ErrInvalidWorkflowNode().
+				WithDetail("node_id", node.ID).
+				WithDetail("node_type", string(node.Type)).
+				WithDetail("reason", "unknown node type")
+		}
+		if err := executor.ValidateConfig(node.Config); err != nil {
+			return errx.Wrap(err, "node config validation failed", errx.TypeValidation).
+				WithDetail("node_id", node.ID).
+				WithDetail("node_name", node.Name)
 		}
 	}
 
@@ -485,6 +729,16 @@ func (e *DefaultWorkflowExecutor) ValidateWorkflow(ctx context.Context, workflow
 		}
 	}
 
+	if err := engine.CheckVariableRefs(workflow); err != nil {
+		return err
+	}
+
+	if e.resourceResolver != nil {
+		if err := engine.CheckResourceRefs(ctx, e.resourceResolver, workflow); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,195 @@
+package workflowexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+const nodeTypeMutate engine.NodeType = "TEST_MUTATE"
+
+// identityEvaluator returns the config it is given, so executors receive the
+// stored workflow's maps unless the executor copies them
+type identityEvaluator struct{}
+
+func (identityEvaluator) Evaluate(_ context.Context, data any, _ map[string]any) (any, error) {
+	return data, nil
+}
+
+// mutatingExecutor writes to every nested level of its config and input, as
+// a careless node implementation would
+type mutatingExecutor struct {
+	runs atomic.Int64
+}
+
+func (m *mutatingExecutor) NodeTypes() []engine.NodeType { return []engine.NodeType{nodeTypeMutate} }
+
+func (m *mutatingExecutor) SupportsType(nodeType engine.NodeType) bool {
+	return nodeType == nodeTypeMutate
+}
+
+func (m *mutatingExecutor) ValidateConfig(map[string]any) error { return nil }
+
+func (m *mutatingExecutor) Execute(_ context.Context, node engine.WorkflowNode, input map[string]any) (*engine.NodeResult, error) {
+	value := fmt.Sprintf("run-%d", m.runs.Add(1))
+
+	headers := node.Config["headers"].(map[string]any)
+	headers["x-run"] = value
+	node.Config["tags"].([]any)[0] = value
+	node.Config["tags"] = append(node.Config["tags"].([]any), value)
+
+	trigger := input["trigger"].(map[string]any)
+	trigger["message"].(map[string]any)["text"] = value
+	trigger["items"].([]any)[0].(map[string]any)["qty"] = value
+	input["meta"].(map[string]any)["seen"] = value
+
+	return &engine.NodeResult{
+		Success: true,
+		Output:  map[string]any{node.ID: value},
+	}, nil
+}
+
+func mutationWorkflow() engine.Workflow {
+	nodeConfig := func() map[string]any {
+		return map[string]any{
+			"headers": map[string]any{"x-source": "relay"},
+			"tags":    []any{"stored"},
+		}
+	}
+	return engine.Workflow{
+		ID:       kernel.NewWorkflowID("wf-1"),
+		TenantID: kernel.NewTenantID("tenant-1"),
+		Name:     "mutation",
+		Nodes: []engine.WorkflowNode{
+			{ID: "first", Name: "first", Type: nodeTypeMutate, Config: nodeConfig(), OnSuccess: "second"},
+			{ID: "second", Name: "second", Type: nodeTypeMutate, Config: nodeConfig()},
+		},
+	}
+}
+
+func mutationInput() engine.WorkflowInput {
+	return engine.WorkflowInput{
+		TenantID: kernel.NewTenantID("tenant-1"),
+		TriggerData: map[string]any{
+			"message": map[string]any{"text": "hello"},
+			"items":   []any{map[string]any{"qty": 1.0}},
+		},
+		Metadata: map[string]any{
+			"meta": map[string]any{"seen": "never"},
+		},
+	}
+}
+
+// Parallel executions of one workflow share the stored workflow and, like
+// the workflows a message triggers, the same trigger data. Run with -race:
+// executors mutating nested config or context must not reach shared maps.
+func TestExecuteParallelRunsDoNotShareState(t *testing.T) {
+	executor := NewDefaultWorkflowExecutor(identityEvaluator{}, &mutatingExecutor{})
+
+	workflow := mutationWorkflow()
+	input := mutationInput()
+	saved := map[string]any{
+		"trigger": input.TriggerData,
+		"meta":    input.Metadata["meta"],
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := executor.Execute(context.Background(), workflow, input); err != nil {
+				t.Errorf("Execute() error = %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := executor.ResumeFromNode(context.Background(), workflow, input, "second", saved); err != nil {
+				t.Errorf("ResumeFromNode() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !reflect.DeepEqual(workflow, mutationWorkflow()) {
+		t.Errorf("stored workflow was mutated: %#v", workflow.Nodes)
+	}
+	if !reflect.DeepEqual(input, mutationInput()) {
+		t.Errorf("workflow input was mutated: %#v", input)
+	}
+	want := map[string]any{
+		"trigger": mutationInput().TriggerData,
+		"meta":    mutationInput().Metadata["meta"],
+	}
+	if !reflect.DeepEqual(saved, want) {
+		t.Errorf("saved node context was mutated: %#v", saved)
+	}
+}
+
+const nodeTypeMisbehaving engine.NodeType = "TEST_MISBEHAVING"
+
+// misbehavingExecutor stands in for a third-party plugin node
+type misbehavingExecutor struct {
+	execute func() (*engine.NodeResult, error)
+}
+
+func (m *misbehavingExecutor) NodeTypes() []engine.NodeType {
+	return []engine.NodeType{nodeTypeMisbehaving}
+}
+
+func (m *misbehavingExecutor) SupportsType(nodeType engine.NodeType) bool {
+	return nodeType == nodeTypeMisbehaving
+}
+
+func (m *misbehavingExecutor) ValidateConfig(map[string]any) error { return nil }
+
+func (m *misbehavingExecutor) Execute(context.Context, engine.WorkflowNode, map[string]any) (*engine.NodeResult, error) {
+	return m.execute()
+}
+
+// A plugin returning no result or panicking fails its node instead of
+// crashing the server, and the failure handler still runs
+func TestExecuteMisbehavingPluginFailsNode(t *testing.T) {
+	tests := []struct {
+		name    string
+		execute func() (*engine.NodeResult, error)
+	}{
+		{name: "nil result with error", execute: func() (*engine.NodeResult, error) { return nil, errors.New("upstream down") }},
+		{name: "nil result without error", execute: func() (*engine.NodeResult, error) { return nil, nil }},
+		{name: "panic", execute: func() (*engine.NodeResult, error) { panic("plugin bug") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := NewDefaultWorkflowExecutor(identityEvaluator{},
+				&misbehavingExecutor{execute: tt.execute},
+				&mutatingExecutor{},
+			)
+
+			workflow := mutationWorkflow()
+			workflow.Nodes[0].Type = nodeTypeMisbehaving
+			workflow.Nodes[0].OnSuccess = ""
+			workflow.Nodes[0].OnFailure = "second"
+
+			result, err := executor.Execute(context.Background(), workflow, mutationInput())
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if len(result.ExecutedNodes) != 2 {
+				t.Fatalf("executed %d nodes, want the plugin node and its failure handler", len(result.ExecutedNodes))
+			}
+
+			first := result.ExecutedNodes[0]
+			if first.Success || first.Error == "" || first.NodeID != "first" {
+				t.Errorf("plugin node result = %+v, want a failure of node first", first)
+			}
+		})
+	}
+}
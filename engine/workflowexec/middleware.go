@@ -0,0 +1,36 @@
+package workflowexec
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/engine"
+)
+
+// Use registers middleware run around every node. BeforeNode hooks run in
+// registration order and AfterNode hooks in reverse, so the first
+// middleware registered wraps all the others.
+func (e *DefaultWorkflowExecutor) Use(middleware ...engine.ExecutorMiddleware) {
+	e.middleware = append(e.middleware, middleware...)
+}
+
+// beforeNode runs the BeforeNode hooks; the first error stops the chain and
+// fails the node
+func (e *DefaultWorkflowExecutor) beforeNode(ctx context.Context, node engine.WorkflowNode, nodeContext map[string]any) (context.Context, error) {
+	for _, m := range e.middleware {
+		next, err := m.BeforeNode(ctx, node, nodeContext)
+		if err != nil {
+			return ctx, err
+		}
+		if next != nil {
+			ctx = next
+		}
+	}
+	return ctx, nil
+}
+
+// afterNode runs the AfterNode hooks, last registered first
+func (e *DefaultWorkflowExecutor) afterNode(ctx context.Context, node engine.WorkflowNode, nodeContext map[string]any, result *engine.NodeResult) {
+	for i := len(e.middleware) - 1; i >= 0; i-- {
+		e.middleware[i].AfterNode(ctx, node, nodeContext, result)
+	}
+}
@@ -0,0 +1,80 @@
+package workflowexec
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// SetOutputLimit caps the JSON size of each node's output. Past the limit
+// the largest fields are replaced by a truncation marker, after being
+// spilled to storage when a spiller is set. Zero disables the limit.
+func (e *DefaultWorkflowExecutor) SetOutputLimit(maxBytes int, spiller engine.OutputSpiller) {
+	e.maxOutputBytes = maxBytes
+	e.outputSpiller = spiller
+}
+
+// limitOutput enforces the node's output limit before the output reaches the
+// execution context, the execution result and any saved continuation
+func (e *DefaultWorkflowExecutor) limitOutput(ctx context.Context, node engine.WorkflowNode, nodeContext map[string]any, result *engine.NodeResult) {
+	limit := e.maxOutputBytes
+	if node.MaxOutputBytes != nil {
+		limit = *node.MaxOutputBytes
+	}
+	if limit <= 0 || len(result.Output) == 0 {
+		return
+	}
+
+	encoded := make(map[string][]byte, len(result.Output))
+	keys := make([]string, 0, len(result.Output))
+	total := 0
+	for key, value := range result.Output {
+		data, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		encoded[key] = data
+		keys = append(keys, key)
+		total += len(data)
+	}
+	if total <= limit {
+		return
+	}
+
+	// Largest fields go first so small ones (status codes, flags) stay usable
+	sort.Slice(keys, func(i, j int) bool {
+		return len(encoded[keys[i]]) > len(encoded[keys[j]])
+	})
+
+	tenantID, _ := nodeContext["tenant_id"].(string)
+	for _, key := range keys {
+		if total <= limit {
+			break
+		}
+		data := encoded[key]
+		if len(data) <= engine.OutputPreviewBytes {
+			break
+		}
+
+		storageKey := ""
+		if e.outputSpiller != nil {
+			var err error
+			storageKey, err = e.outputSpiller.SpillOutput(ctx, kernel.TenantID(tenantID), node.ID, key, data)
+			if err != nil {
+				log.Printf("⚠️  Failed to spill output %s of node %s: %v", key, node.ID, err)
+			}
+		}
+
+		marker := engine.TruncatedOutput(data, storageKey)
+		markerData, _ := json.Marshal(marker)
+		result.Output[key] = marker
+		result.Truncated = append(result.Truncated, key)
+		total += len(markerData) - len(data)
+	}
+
+	log.Printf("✂️  Output of node %s over %d bytes, truncated fields: %v", node.Name, limit, result.Truncated)
+}
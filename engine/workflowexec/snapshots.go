@@ -0,0 +1,138 @@
+package workflowexec
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/google/uuid"
+)
+
+// SetSnapshotRepository records the runs of workflows with debug on: the
+// context before every node, its evaluated config and its result, so the run
+// can be inspected and replayed. Simulations and replays are not recorded.
+func (e *DefaultWorkflowExecutor) SetSnapshotRepository(repo engine.SnapshotRepository) {
+	e.snapshots = repo
+}
+
+// runRecorder stores the snapshots of one run. A nil recorder records
+// nothing, so the loops call it unconditionally.
+type runRecorder struct {
+	repo     engine.SnapshotRepository
+	run      engine.RecordedRun
+	sequence int
+}
+
+func (e *DefaultWorkflowExecutor) startRecording(ctx context.Context, workflow engine.Workflow, input engine.WorkflowInput, resumedAt string) *runRecorder {
+	if e.snapshots == nil || !workflow.Debug {
+		return nil
+	}
+	if simulation, _ := input.Metadata["simulation"].(bool); simulation {
+		return nil
+	}
+	if _, replaying := engine.ReplayFromContext(ctx); replaying {
+		return nil
+	}
+
+	r := &runRecorder{
+		repo: e.snapshots,
+		run: engine.RecordedRun{
+			ID:              uuid.NewString(),
+			TenantID:        input.TenantID,
+			WorkflowID:      workflow.ID,
+			WorkflowVersion: workflow.Version,
+			TriggerData:     engine.DeepCopyMap(input.TriggerData),
+			Metadata:        engine.DeepCopyMap(input.Metadata),
+			ResumedAt:       resumedAt,
+			Success:         true,
+			StartedAt:       time.Now(),
+		},
+	}
+	if err := r.repo.SaveRun(context.WithoutCancel(ctx), r.run); err != nil {
+		log.Printf("⚠️  Failed to record run of workflow %s: %v", workflow.ID, err)
+		return nil
+	}
+	log.Printf("🎞️  Recording run %s of workflow %s", r.run.ID, workflow.Name)
+	return r
+}
+
+func (r *runRecorder) runID() string {
+	if r == nil {
+		return ""
+	}
+	return r.run.ID
+}
+
+// capture copies the context before a node runs, since the node and the
+// loop change it in place
+func (r *runRecorder) capture(nodeContext map[string]any) map[string]any {
+	if r == nil {
+		return nil
+	}
+	return engine.DeepCopyMap(nodeContext)
+}
+
+// record stores one node boundary. config is nil when the node's expressions
+// failed to evaluate.
+func (r *runRecorder) record(ctx context.Context, node engine.WorkflowNode, before, config, nodeContext map[string]any, result *engine.NodeResult) {
+	if r == nil {
+		return
+	}
+	r.sequence++
+
+	nextNode, _ := nodeContext["__next_node"].(string)
+	snapshot := engine.NodeSnapshot{
+		RunID:      r.run.ID,
+		Sequence:   r.sequence,
+		NodeID:     node.ID,
+		NodeType:   node.Type,
+		Context:    before,
+		Config:     engine.DeepCopyMap(config),
+		NextNode:   nextNode,
+		Result:     *result,
+		RecordedAt: time.Now(),
+	}
+	snapshot.Result.Output = engine.DeepCopyMap(result.Output)
+
+	if err := r.repo.SaveSnapshot(context.WithoutCancel(ctx), snapshot); err != nil {
+		log.Printf("⚠️  Failed to record snapshot of node %s in run %s: %v", node.ID, r.run.ID, err)
+	}
+}
+
+// finish stores the outcome of a run that ended or paused
+func (r *runRecorder) finish(ctx context.Context, result *engine.ExecutionResult) {
+	if r == nil {
+		return
+	}
+	r.save(ctx, result.Success, result.ErrorMessage)
+}
+
+// abort stores the outcome of a run the executor stopped with an error
+func (r *runRecorder) abort(ctx context.Context, err error) {
+	if r == nil {
+		return
+	}
+	r.save(ctx, false, err.Error())
+}
+
+func (r *runRecorder) save(ctx context.Context, success bool, errorMessage string) {
+	now := time.Now()
+	r.run.Success = success
+	r.run.Error = errorMessage
+	r.run.FinishedAt = &now
+
+	if err := r.repo.SaveRun(context.WithoutCancel(ctx), r.run); err != nil {
+		log.Printf("⚠️  Failed to finish recorded run %s: %v", r.run.ID, err)
+	}
+}
+
+// mockFromReplay answers a node with side effects from the recorded run when
+// ctx is a replay
+func mockFromReplay(ctx context.Context, node engine.WorkflowNode, nodeContext map[string]any) (*engine.NodeResult, bool) {
+	session, ok := engine.ReplayFromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	return session.Mock(node, nodeContext)
+}
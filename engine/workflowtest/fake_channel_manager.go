@@ -0,0 +1,67 @@
+package workflowtest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// FakeChannelManager is a ChannelManager for Go tests that never reaches a
+// provider: sends go to the context's outbox when there is one (as the runner
+// sets up) and are otherwise kept in Sent
+type FakeChannelManager struct {
+	mu   sync.Mutex
+	sent []channels.CapturedMessage
+
+	// SendErr, when set, is returned by every SendMessage to exercise
+	// OnFailure branches
+	SendErr error
+}
+
+var _ channels.ChannelManager = (*FakeChannelManager)(nil)
+
+func NewFakeChannelManager() *FakeChannelManager {
+	return &FakeChannelManager{}
+}
+
+func (m *FakeChannelManager) SendMessage(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, msg channels.OutgoingMessage) error {
+	if m.SendErr != nil {
+		return m.SendErr
+	}
+
+	if outbox, ok := channels.OutboxFromContext(ctx); ok {
+		outbox.Capture(channelID, msg)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, channels.CapturedMessage{ChannelID: channelID, Message: msg})
+	return nil
+}
+
+// Sent returns the messages sent outside a runner turn
+func (m *FakeChannelManager) Sent() []channels.CapturedMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sent := make([]channels.CapturedMessage, len(m.sent))
+	copy(sent, m.sent)
+	return sent
+}
+
+func (m *FakeChannelManager) RegisterChannel(ctx context.Context, channel channels.Channel) error {
+	return nil
+}
+
+func (m *FakeChannelManager) ProcessIncomingMessage(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, msg channels.IncomingMessage) error {
+	return nil
+}
+
+func (m *FakeChannelManager) GetAdapter(channelID kernel.ChannelID) (channels.ChannelAdapter, error) {
+	return nil, channels.ErrChannelNotFound().WithDetail("channel_id", channelID.String())
+}
+
+func (m *FakeChannelManager) UnregisterChannel(channelID kernel.ChannelID) {}
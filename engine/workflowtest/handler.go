@@ -0,0 +1,59 @@
+package workflowtest
+
+import (
+	"context"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// runTimeout bounds a whole script run
+const runTimeout = 2 * time.Minute
+
+// WorkflowTestHandler runs conversation scripts against stored workflows
+type WorkflowTestHandler struct {
+	workflowRepo engine.WorkflowRepository
+	runner       *Runner
+}
+
+func NewWorkflowTestHandler(workflowRepo engine.WorkflowRepository, runner *Runner) *WorkflowTestHandler {
+	return &WorkflowTestHandler{
+		workflowRepo: workflowRepo,
+		runner:       runner,
+	}
+}
+
+// Run plays a script against the workflow, active or not. Nothing reaches a
+// provider; failed expectations are reported in the body with status 200.
+// POST /api/workflows/:id/tests
+func (h *WorkflowTestHandler) Run(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	workflowID := kernel.NewWorkflowID(c.Params("id"))
+	wf, err := h.workflowRepo.FindByID(c.Context(), workflowID)
+	if err != nil || wf.TenantID != authContext.TenantID {
+		return engine.ErrWorkflowNotFound().WithDetail("workflow_id", workflowID.String())
+	}
+
+	var script Script
+	if err := c.BodyParser(&script); err != nil {
+		return engine.ErrInvalidWorkflowConfig().WithDetail("reason", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
+	defer cancel()
+
+	report, err := h.runner.Run(ctx, *wf, script)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(report)
+}
@@ -0,0 +1,22 @@
+package workflowtest
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type WorkflowTestRoutes struct {
+	handler *WorkflowTestHandler
+}
+
+func NewWorkflowTestRoutes(handler *WorkflowTestHandler) *WorkflowTestRoutes {
+	return &WorkflowTestRoutes{
+		handler: handler,
+	}
+}
+
+// RegisterRoutes registers workflow test routes on an authenticated router
+func (r *WorkflowTestRoutes) RegisterRoutes(router fiber.Router) {
+	workflows := router.Group("/workflows")
+
+	workflows.Post("/:id/tests", r.handler.Run)
+}
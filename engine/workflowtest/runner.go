@@ -0,0 +1,312 @@
+package workflowtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Scripts
+// ============================================================================
+
+// defaultChannelID is used when a script does not name a channel. Sends are
+// captured, so the channel does not need to exist.
+const defaultChannelID = "workflow-test"
+
+// Script is a scripted conversation: user messages in order, each with what
+// the bot is expected to do in response
+type Script struct {
+	Name      string           `json:"name,omitempty"`
+	SenderID  string           `json:"sender_id,omitempty"`
+	ChannelID kernel.ChannelID `json:"channel_id,omitempty"`
+	Turns     []Turn           `json:"turns" validate:"required,min=1"`
+}
+
+// Turn is one user message and its expectations
+type Turn struct {
+	Message  string         `json:"message"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Expect   Expectation    `json:"expect"`
+}
+
+// Expectation describes a turn's outcome. Empty fields are not checked.
+type Expectation struct {
+	Responses  []string `json:"responses,omitempty"`   // Exact texts sent, in order
+	Contains   []string `json:"contains,omitempty"`    // Each must appear in some response
+	NoResponse bool     `json:"no_response,omitempty"` // Nothing may be sent
+	Nodes      []string `json:"nodes,omitempty"`       // Node IDs that must have run
+	Success    *bool    `json:"success,omitempty"`
+
+	// State maps dotted paths to expected values. Paths start at "output"
+	// (the merged output of the successful nodes) or "nodes.<node_id>" (the
+	// output of one node, failed ones included), e.g.
+	// "nodes.send.error.failure_class".
+	State map[string]any `json:"state,omitempty"`
+}
+
+// ============================================================================
+// Reports
+// ============================================================================
+
+// Report is the outcome of running a script
+type Report struct {
+	Name       string            `json:"name,omitempty"`
+	WorkflowID kernel.WorkflowID `json:"workflow_id"`
+	Passed     bool              `json:"passed"`
+	Turns      []TurnResult      `json:"turns"`
+	DurationMs int64             `json:"duration_ms"`
+}
+
+// TurnResult is what happened on one turn and which expectations failed
+type TurnResult struct {
+	Message       string                    `json:"message"`
+	Responses     []string                  `json:"responses"`
+	ExecutedNodes []string                  `json:"executed_nodes"`
+	Success       bool                      `json:"success"`
+	Error         string                    `json:"error,omitempty"`
+	Output        map[string]any            `json:"output,omitempty"`
+	NodeOutputs   map[string]map[string]any `json:"node_outputs,omitempty"`
+	Failures      []string                  `json:"failures,omitempty"`
+}
+
+// TB is the part of testing.TB the report needs, so this package does not
+// import testing
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Assert reports every failed expectation to a Go test
+func (r *Report) Assert(t TB) {
+	t.Helper()
+	for i, turn := range r.Turns {
+		for _, failure := range turn.Failures {
+			t.Errorf("turn %d (%q): %s", i+1, turn.Message, failure)
+		}
+	}
+}
+
+// Err returns the failed expectations as one error, nil when the script passed
+func (r *Report) Err() error {
+	var errs []error
+	for i, turn := range r.Turns {
+		for _, failure := range turn.Failures {
+			errs = append(errs, fmt.Errorf("turn %d (%q): %s", i+1, turn.Message, failure))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ============================================================================
+// Runner
+// ============================================================================
+
+// Runner plays scripts against a workflow. Every turn runs the workflow as an
+// inbound channel message would, with outgoing messages captured instead of
+// sent, so any executor wired with a ChannelManager can be used.
+type Runner struct {
+	executor engine.WorkflowExecutor
+}
+
+func NewRunner(executor engine.WorkflowExecutor) *Runner {
+	return &Runner{executor: executor}
+}
+
+// Run plays every turn in order. Turns keep going after a failed expectation
+// so the report shows the whole conversation; only an invalid workflow stops
+// the run.
+func (r *Runner) Run(ctx context.Context, wf engine.Workflow, script Script) (*Report, error) {
+	if len(script.Turns) == 0 {
+		return nil, engine.ErrInvalidWorkflowConfig().WithDetail("reason", "script has no turns")
+	}
+	if err := r.executor.ValidateWorkflow(ctx, wf); err != nil {
+		return nil, err
+	}
+
+	senderID := script.SenderID
+	if senderID == "" {
+		senderID = "workflow-test-" + uuid.NewString()[:8]
+	}
+	channelID := script.ChannelID
+	if channelID.IsEmpty() {
+		channelID = kernel.NewChannelID(defaultChannelID)
+	}
+
+	startTime := time.Now()
+	report := &Report{
+		Name:       script.Name,
+		WorkflowID: wf.ID,
+		Passed:     true,
+		Turns:      make([]TurnResult, 0, len(script.Turns)),
+	}
+
+	for _, turn := range script.Turns {
+		result := r.runTurn(ctx, wf, channelID, senderID, turn)
+		if len(result.Failures) > 0 {
+			report.Passed = false
+		}
+		report.Turns = append(report.Turns, result)
+	}
+
+	report.DurationMs = time.Since(startTime).Milliseconds()
+	return report, nil
+}
+
+func (r *Runner) runTurn(
+	ctx context.Context,
+	wf engine.Workflow,
+	channelID kernel.ChannelID,
+	senderID string,
+	turn Turn,
+) TurnResult {
+	ctx, outbox := channels.WithOutbox(ctx)
+
+	input := engine.WorkflowInput{
+		TriggerData: triggerData(channelID, senderID, turn),
+		TenantID:    wf.TenantID,
+		Metadata: map[string]any{
+			"trigger_type": engine.TriggerTypeChannelWebhook,
+			"workflow_id":  wf.ID.String(),
+			"simulation":   true,
+		},
+	}
+
+	result := TurnResult{Message: turn.Message, NodeOutputs: make(map[string]map[string]any)}
+
+	execution, err := r.executor.Execute(ctx, wf, input)
+	if execution != nil {
+		result.Success = execution.Success
+		result.Error = execution.ErrorMessage
+		result.Output = execution.Output
+		for _, node := range execution.ExecutedNodes {
+			result.ExecutedNodes = append(result.ExecutedNodes, node.NodeID)
+			result.NodeOutputs[node.NodeID] = node.Output
+		}
+	}
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+	}
+
+	for _, captured := range outbox.Messages() {
+		result.Responses = append(result.Responses, captured.Message.Content.Text)
+	}
+
+	result.Failures = check(turn.Expect, result)
+	return result
+}
+
+// triggerData mirrors the payload of a real inbound channel message
+func triggerData(channelID kernel.ChannelID, senderID string, turn Turn) map[string]any {
+	data := map[string]any{
+		"text":            turn.Message,
+		"message_id":      uuid.NewString(),
+		"channel_id":      channelID.String(),
+		"sender_id":       senderID,
+		"message_type":    "text",
+		"conversation_id": senderID,
+	}
+	if turn.Metadata != nil {
+		data["metadata"] = turn.Metadata
+	}
+	return data
+}
+
+// ============================================================================
+// Expectations
+// ============================================================================
+
+func check(expect Expectation, result TurnResult) []string {
+	var failures []string
+
+	if expect.Responses != nil && !slices.Equal(expect.Responses, result.Responses) {
+		failures = append(failures, fmt.Sprintf("expected responses %q, got %q", expect.Responses, result.Responses))
+	}
+
+	for _, want := range expect.Contains {
+		if !slices.ContainsFunc(result.Responses, func(text string) bool { return strings.Contains(text, want) }) {
+			failures = append(failures, fmt.Sprintf("no response contains %q", want))
+		}
+	}
+
+	if expect.NoResponse && len(result.Responses) > 0 {
+		failures = append(failures, fmt.Sprintf("expected no response, got %q", result.Responses))
+	}
+
+	for _, nodeID := range expect.Nodes {
+		if !slices.Contains(result.ExecutedNodes, nodeID) {
+			failures = append(failures, fmt.Sprintf("node %s did not run (ran %v)", nodeID, result.ExecutedNodes))
+		}
+	}
+
+	if expect.Success != nil && *expect.Success != result.Success {
+		failures = append(failures, fmt.Sprintf("expected success=%v, got %v (%s)", *expect.Success, result.Success, result.Error))
+	}
+
+	state := map[string]any{"output": result.Output, "nodes": nodeOutputs(result.NodeOutputs)}
+	for path, want := range expect.State {
+		got, ok := lookup(state, path)
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s is missing", path))
+			continue
+		}
+		if !sameJSON(want, got) {
+			failures = append(failures, fmt.Sprintf("%s: expected %v, got %v", path, want, got))
+		}
+	}
+
+	return failures
+}
+
+func nodeOutputs(outputs map[string]map[string]any) map[string]any {
+	nodes := make(map[string]any, len(outputs))
+	for id, output := range outputs {
+		nodes[id] = output
+	}
+	return nodes
+}
+
+// lookup resolves a dotted path such as "output.json.order.status"
+func lookup(data map[string]any, path string) (any, bool) {
+	var current any = data
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// sameJSON compares values by their JSON form, so 3 and 3.0 or []string and
+// []any with the same items are equal
+func sameJSON(a, b any) bool {
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+func normalize(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
@@ -0,0 +1,31 @@
+package experiment
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// StatsRequest request for an experiment's performance on one goal, for
+// events in a period
+type StatsRequest struct {
+	TenantID   kernel.TenantID `json:"tenant_id" validate:"required"`
+	Experiment string          `json:"experiment" validate:"required"`
+	Goal       string          `json:"goal,omitempty"`     // Empty = the main goal
+	Baseline   string          `json:"baseline,omitempty"` // Variant lift is measured against
+	From       *time.Time      `json:"from,omitempty"`
+	To         *time.Time      `json:"to,omitempty"`
+}
+
+// ConversionRequest reports a conversion that happened outside a workflow,
+// such as an order placed on the website
+type ConversionRequest struct {
+	ContactID string  `json:"contact_id" validate:"required"`
+	ChannelID string  `json:"channel_id,omitempty"`
+	Goal      string  `json:"goal,omitempty"`
+	Value     float64 `json:"value,omitempty"`
+}
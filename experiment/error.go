@@ -0,0 +1,39 @@
+package experiment
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("EXPERIMENT")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeExperimentNotFound = ErrRegistry.Register("EXPERIMENT_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Experiment has no events")
+	CodeNotExposed         = ErrRegistry.Register("NOT_EXPOSED", errx.TypeBusiness, http.StatusConflict, "Contact was never exposed to the experiment")
+	CodeInvalidEvent       = ErrRegistry.Register("INVALID_EVENT", errx.TypeValidation, http.StatusBadRequest, "Invalid experiment event")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrExperimentNotFound() *errx.Error {
+	return ErrRegistry.New(CodeExperimentNotFound)
+}
+
+func ErrNotExposed() *errx.Error {
+	return ErrRegistry.New(CodeNotExposed)
+}
+
+func ErrInvalidEvent() *errx.Error {
+	return ErrRegistry.New(CodeInvalidEvent)
+}
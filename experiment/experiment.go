@@ -0,0 +1,134 @@
+package experiment
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Experiment Events
+// ============================================================================
+
+// Event is an exposure of a contact to a variant or a conversion credited
+// to the variant the contact saw last
+type Event struct {
+	ID         string                     `json:"id"`
+	TenantID   kernel.TenantID            `json:"tenant_id"`
+	WorkflowID string                     `json:"workflow_id,omitempty"`
+	Experiment string                     `json:"experiment"`
+	Variant    string                     `json:"variant"`
+	Kind       engine.ExperimentEventKind `json:"kind"`
+	ContactID  string                     `json:"contact_id"`
+	ChannelID  string                     `json:"channel_id,omitempty"`
+	Goal       string                     `json:"goal,omitempty"`
+	Value      float64                    `json:"value,omitempty"`
+	CreatedAt  time.Time                  `json:"created_at"`
+}
+
+// NewEvent records what an EXPERIMENT node or the API reported
+func NewEvent(id string, kind engine.ExperimentEventKind, source engine.ExperimentEvent, now time.Time) *Event {
+	return &Event{
+		ID:         id,
+		TenantID:   source.TenantID,
+		WorkflowID: source.WorkflowID,
+		Experiment: source.Experiment,
+		Variant:    source.Variant,
+		Kind:       kind,
+		ContactID:  source.ContactID,
+		ChannelID:  source.ChannelID,
+		Goal:       source.Goal,
+		Value:      source.Value,
+		CreatedAt:  now,
+	}
+}
+
+// ============================================================================
+// Performance
+// ============================================================================
+
+// VariantCount is the raw aggregate of one variant, as counted by the
+// repository. Exposed and Converted count distinct contacts.
+type VariantCount struct {
+	Variant     string  `db:"variant"`
+	Exposed     int     `db:"exposed"`
+	Converted   int     `db:"converted"`
+	Conversions int     `db:"conversions"`
+	Value       float64 `db:"value"`
+}
+
+// VariantStats is how one variant performed. Lift is the relative change of
+// its conversion rate against the baseline variant.
+type VariantStats struct {
+	Variant         string   `json:"variant"`
+	Exposed         int      `json:"exposed"`
+	Converted       int      `json:"converted"`
+	Conversions     int      `json:"conversions"`
+	ConversionRate  float64  `json:"conversion_rate"`
+	Value           float64  `json:"value"`
+	ValuePerExposed float64  `json:"value_per_exposed"`
+	Lift            *float64 `json:"lift,omitempty"`
+}
+
+// Stats is the performance of an experiment's variants for one goal
+type Stats struct {
+	Experiment string         `json:"experiment"`
+	Goal       string         `json:"goal,omitempty"`
+	Baseline   string         `json:"baseline,omitempty"`
+	Variants   []VariantStats `json:"variants"`
+}
+
+// NewStats derives rates and lift from the raw counts. Without a baseline
+// the first variant in the counts is used.
+func NewStats(experiment, goal, baseline string, counts []VariantCount) *Stats {
+	stats := &Stats{
+		Experiment: experiment,
+		Goal:       goal,
+		Variants:   make([]VariantStats, len(counts)),
+	}
+
+	for i, count := range counts {
+		variant := VariantStats{
+			Variant:     count.Variant,
+			Exposed:     count.Exposed,
+			Converted:   count.Converted,
+			Conversions: count.Conversions,
+			Value:       count.Value,
+		}
+		if count.Exposed > 0 {
+			variant.ConversionRate = float64(count.Converted) / float64(count.Exposed)
+			variant.ValuePerExposed = count.Value / float64(count.Exposed)
+		}
+		stats.Variants[i] = variant
+	}
+
+	if baseline == "" && len(counts) > 0 {
+		baseline = counts[0].Variant
+	}
+	var baseRate float64
+	for _, variant := range stats.Variants {
+		if variant.Variant == baseline {
+			stats.Baseline = baseline
+			baseRate = variant.ConversionRate
+		}
+	}
+	if baseRate == 0 {
+		return stats
+	}
+	for i := range stats.Variants {
+		lift := (stats.Variants[i].ConversionRate - baseRate) / baseRate
+		stats.Variants[i].Lift = &lift
+	}
+	return stats
+}
+
+// Summary is an experiment seen in a tenant's events
+type Summary struct {
+	Experiment  string    `db:"experiment" json:"experiment"`
+	Variants    int       `db:"variants" json:"variants"`
+	Exposed     int       `db:"exposed" json:"exposed"`
+	Conversions int       `db:"conversions" json:"conversions"`
+	FirstSeen   time.Time `db:"first_seen" json:"first_seen"`
+	LastSeen    time.Time `db:"last_seen" json:"last_seen"`
+}
@@ -0,0 +1,95 @@
+package experimentapi
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/experiment"
+	"github.com/Abraxas-365/relay/experiment/experimentsrv"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExperimentHandler exposes the A/B tests run by workflows
+type ExperimentHandler struct {
+	service *experimentsrv.ExperimentService
+}
+
+// NewExperimentHandler creates a new experiment handler
+func NewExperimentHandler(service *experimentsrv.ExperimentService) *ExperimentHandler {
+	return &ExperimentHandler{
+		service: service,
+	}
+}
+
+// List returns the tenant's experiments with their reach
+// GET /api/experiments
+func (h *ExperimentHandler) List(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	experiments, err := h.service.List(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"experiments": experiments})
+}
+
+// Stats returns each variant's performance on ?goal= (the main goal when
+// empty), with lift against ?baseline=, for events between ?from= and ?to=
+// GET /api/experiments/:experiment/stats
+func (h *ExperimentHandler) Stats(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	req := experiment.StatsRequest{
+		TenantID:   authContext.TenantID,
+		Experiment: c.Params("experiment"),
+		Goal:       c.Query("goal"),
+		Baseline:   c.Query("baseline"),
+	}
+	for param, target := range map[string]**time.Time{"from": &req.From, "to": &req.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return experiment.ErrInvalidEvent().WithDetail(param, "must be an RFC 3339 timestamp")
+		}
+		*target = &parsed
+	}
+
+	stats, err := h.service.Stats(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(stats)
+}
+
+// Convert records a conversion that happened outside a workflow
+// POST /api/experiments/:experiment/conversions
+func (h *ExperimentHandler) Convert(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req experiment.ConversionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return experiment.ErrInvalidEvent().WithDetail("reason", err.Error())
+	}
+
+	event, err := h.service.Convert(c.Context(), authContext.TenantID, c.Params("experiment"), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(event)
+}
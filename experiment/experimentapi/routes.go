@@ -0,0 +1,29 @@
+package experimentapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExperimentRoutes handles experiment route setup
+type ExperimentRoutes struct {
+	handler        *ExperimentHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewExperimentRoutes creates a new experiment routes instance
+func NewExperimentRoutes(handler *ExperimentHandler, authMiddleware *auth.AuthMiddleware) *ExperimentRoutes {
+	return &ExperimentRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers experiment routes on an authenticated router
+func (r *ExperimentRoutes) RegisterRoutes(router fiber.Router) {
+	experiments := router.Group("/experiments")
+
+	experiments.Get("/", r.handler.List)
+	experiments.Get("/:experiment/stats", r.handler.Stats)
+	experiments.Post("/:experiment/conversions", r.handler.Convert)
+}
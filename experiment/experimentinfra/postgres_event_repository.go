@@ -0,0 +1,120 @@
+package experimentinfra
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/experiment"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresEventRepository struct {
+	db *sqlx.DB
+}
+
+var _ experiment.EventRepository = (*PostgresEventRepository)(nil)
+
+func NewPostgresEventRepository(db *sqlx.DB) *PostgresEventRepository {
+	return &PostgresEventRepository{db: db}
+}
+
+func (r *PostgresEventRepository) Save(ctx context.Context, event experiment.Event) error {
+	query := `
+		INSERT INTO experiment_events (
+			id, tenant_id, workflow_id, experiment, variant, kind,
+			contact_id, channel_id, goal, value, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		event.ID, event.TenantID.String(), event.WorkflowID, event.Experiment, event.Variant, string(event.Kind),
+		event.ContactID, event.ChannelID, event.Goal, event.Value, event.CreatedAt,
+	)
+	if err != nil {
+		return errx.Wrap(err, "failed to save experiment event", errx.TypeInternal).
+			WithDetail("experiment", event.Experiment)
+	}
+
+	return nil
+}
+
+func (r *PostgresEventRepository) LatestVariant(ctx context.Context, tenantID kernel.TenantID, experimentKey, contactID string) (string, error) {
+	query := `
+		SELECT variant FROM experiment_events
+		WHERE tenant_id = $1 AND experiment = $2 AND contact_id = $3 AND kind = 'EXPOSURE'
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	var variant string
+	if err := r.db.GetContext(ctx, &variant, query, tenantID.String(), experimentKey, contactID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", errx.Wrap(err, "failed to find contact's variant", errx.TypeInternal).
+			WithDetail("experiment", experimentKey)
+	}
+
+	return variant, nil
+}
+
+func (r *PostgresEventRepository) Stats(ctx context.Context, req experiment.StatsRequest) ([]experiment.VariantCount, error) {
+	conditions := []string{"tenant_id = $1", "experiment = $2"}
+	args := []any{req.TenantID.String(), req.Experiment, req.Goal}
+	argPos := 4
+
+	if req.From != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argPos))
+		args = append(args, *req.From)
+		argPos++
+	}
+	if req.To != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", argPos))
+		args = append(args, *req.To)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			variant,
+			COUNT(DISTINCT contact_id) FILTER (WHERE kind = 'EXPOSURE') AS exposed,
+			COUNT(DISTINCT contact_id) FILTER (WHERE kind = 'CONVERSION' AND goal = $3) AS converted,
+			COUNT(*) FILTER (WHERE kind = 'CONVERSION' AND goal = $3) AS conversions,
+			COALESCE(SUM(value) FILTER (WHERE kind = 'CONVERSION' AND goal = $3), 0) AS value
+		FROM experiment_events
+		WHERE %s
+		GROUP BY variant
+		ORDER BY MIN(created_at) ASC, variant ASC`,
+		strings.Join(conditions, " AND "))
+
+	var counts []experiment.VariantCount
+	if err := r.db.SelectContext(ctx, &counts, query, args...); err != nil {
+		return nil, errx.Wrap(err, "failed to aggregate experiment events", errx.TypeInternal).
+			WithDetail("experiment", req.Experiment)
+	}
+
+	return counts, nil
+}
+
+func (r *PostgresEventRepository) List(ctx context.Context, tenantID kernel.TenantID) ([]experiment.Summary, error) {
+	query := `
+		SELECT
+			experiment,
+			COUNT(DISTINCT variant) AS variants,
+			COUNT(DISTINCT contact_id) FILTER (WHERE kind = 'EXPOSURE') AS exposed,
+			COUNT(*) FILTER (WHERE kind = 'CONVERSION') AS conversions,
+			MIN(created_at) AS first_seen,
+			MAX(created_at) AS last_seen
+		FROM experiment_events
+		WHERE tenant_id = $1
+		GROUP BY experiment
+		ORDER BY last_seen DESC`
+
+	var summaries []experiment.Summary
+	if err := r.db.SelectContext(ctx, &summaries, query, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to list experiments", errx.TypeInternal)
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,110 @@
+package experimentsrv
+
+import (
+	"context"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/experiment"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// ExperimentService records the exposures and conversions of A/B tests run
+// by EXPERIMENT nodes and reports how each variant performs
+type ExperimentService struct {
+	eventRepo experiment.EventRepository
+}
+
+var _ engine.ExperimentRecorder = (*ExperimentService)(nil)
+
+func NewExperimentService(eventRepo experiment.EventRepository) *ExperimentService {
+	return &ExperimentService{
+		eventRepo: eventRepo,
+	}
+}
+
+// ============================================================================
+// Recording
+// ============================================================================
+
+// RecordExposure implements engine.ExperimentRecorder
+func (s *ExperimentService) RecordExposure(ctx context.Context, source engine.ExperimentEvent) error {
+	event := experiment.NewEvent(uuid.NewString(), engine.ExperimentExposure, source, time.Now())
+	return s.eventRepo.Save(ctx, *event)
+}
+
+// RecordConversion implements engine.ExperimentRecorder. The conversion is
+// credited to the contact's latest variant; contacts never exposed are not
+// recorded and an empty variant is returned.
+func (s *ExperimentService) RecordConversion(ctx context.Context, source engine.ExperimentEvent) (string, error) {
+	event, err := s.recordConversion(ctx, source)
+	if err != nil || event == nil {
+		return "", err
+	}
+	return event.Variant, nil
+}
+
+// Convert records a conversion reported through the API, failing when the
+// contact never saw the experiment
+func (s *ExperimentService) Convert(ctx context.Context, tenantID kernel.TenantID, experimentKey string, req experiment.ConversionRequest) (*experiment.Event, error) {
+	if req.ContactID == "" {
+		return nil, experiment.ErrInvalidEvent().WithDetail("reason", "contact_id is required")
+	}
+
+	event, err := s.recordConversion(ctx, engine.ExperimentEvent{
+		TenantID:   tenantID,
+		Experiment: experimentKey,
+		ContactID:  req.ContactID,
+		ChannelID:  req.ChannelID,
+		Goal:       req.Goal,
+		Value:      req.Value,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, experiment.ErrNotExposed().
+			WithDetail("experiment", experimentKey).
+			WithDetail("contact_id", req.ContactID)
+	}
+	return event, nil
+}
+
+// recordConversion stores the conversion under the contact's latest
+// variant; nil when the contact was never exposed
+func (s *ExperimentService) recordConversion(ctx context.Context, source engine.ExperimentEvent) (*experiment.Event, error) {
+	variant, err := s.eventRepo.LatestVariant(ctx, source.TenantID, source.Experiment, source.ContactID)
+	if err != nil || variant == "" {
+		return nil, err
+	}
+
+	source.Variant = variant
+	event := experiment.NewEvent(uuid.NewString(), engine.ExperimentConversion, source, time.Now())
+	if err := s.eventRepo.Save(ctx, *event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// ============================================================================
+// Reporting
+// ============================================================================
+
+// List returns the experiments the tenant has run, most recently active first
+func (s *ExperimentService) List(ctx context.Context, tenantID kernel.TenantID) ([]experiment.Summary, error) {
+	return s.eventRepo.List(ctx, tenantID)
+}
+
+// Stats reports each variant's exposures, conversions and lift for a goal
+func (s *ExperimentService) Stats(ctx context.Context, req experiment.StatsRequest) (*experiment.Stats, error) {
+	counts, err := s.eventRepo.Stats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(counts) == 0 {
+		return nil, experiment.ErrExperimentNotFound().WithDetail("experiment", req.Experiment)
+	}
+
+	return experiment.NewStats(req.Experiment, req.Goal, req.Baseline, counts), nil
+}
@@ -0,0 +1,23 @@
+package experiment
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// EventRepository persists experiment exposures and conversions
+type EventRepository interface {
+	Save(ctx context.Context, event Event) error
+
+	// LatestVariant returns the variant of the contact's latest exposure,
+	// empty when the contact was never exposed
+	LatestVariant(ctx context.Context, tenantID kernel.TenantID, experiment, contactID string) (string, error)
+
+	Stats(ctx context.Context, req StatsRequest) ([]VariantCount, error)
+	List(ctx context.Context, tenantID kernel.TenantID) ([]Summary, error)
+}
@@ -0,0 +1,67 @@
+package fallback
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// SaveSettingsRequest replaces the tenant's or a channel's settings
+type SaveSettingsRequest struct {
+	WorkflowID *kernel.WorkflowID `json:"workflow_id,omitempty"`
+	Message    string             `json:"message,omitempty"`
+}
+
+// Settings builds the settings of the request
+func (r SaveSettingsRequest) Settings(tenantID kernel.TenantID, channelID *kernel.ChannelID) *Settings {
+	workflowID := r.WorkflowID
+	if workflowID != nil && workflowID.IsEmpty() {
+		workflowID = nil
+	}
+	return &Settings{
+		TenantID:   tenantID,
+		ChannelID:  channelID,
+		WorkflowID: workflowID,
+		Message:    strings.TrimSpace(r.Message),
+		UpdatedAt:  time.Now(),
+	}
+}
+
+// StatsRequest request to count the unmatched messages of a period. From
+// and To default to the last DefaultStatsDays days.
+type StatsRequest struct {
+	TenantID  kernel.TenantID   `json:"tenant_id" validate:"required"`
+	ChannelID *kernel.ChannelID `json:"channel_id,omitempty"`
+	From      *time.Time        `json:"from,omitempty"`
+	To        *time.Time        `json:"to,omitempty"`
+}
+
+// DefaultStatsDays is the period counted when a stats request has no From
+const DefaultStatsDays = 30
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+// SettingsResponse is what a tenant configured
+type SettingsResponse struct {
+	Tenant   *Settings  `json:"tenant"`   // nil = unmatched messages are ignored
+	Channels []Settings `json:"channels"` // Channel overrides
+}
+
+// StatsResponse is the tenant's unmatched messages in a period, per channel
+// and day, with the totals
+type StatsResponse struct {
+	From         time.Time    `json:"from"`
+	To           time.Time    `json:"to"`
+	Unmatched    int          `json:"unmatched"`
+	WorkflowRuns int          `json:"workflow_runs"`
+	MessagesSent int          `json:"messages_sent"`
+	Unhandled    int          `json:"unhandled"` // Got no fallback at all
+	Days         []DailyCount `json:"days"`
+}
@@ -0,0 +1,39 @@
+package fallback
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("FALLBACK")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeInvalidSettings  = ErrRegistry.Register("INVALID_SETTINGS", errx.TypeValidation, http.StatusBadRequest, "Invalid fallback settings")
+	CodeSettingsNotFound = ErrRegistry.Register("SETTINGS_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Fallback settings not found")
+	CodeInvalidFilter    = ErrRegistry.Register("INVALID_FILTER", errx.TypeValidation, http.StatusBadRequest, "Invalid unmatched message filter")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrInvalidSettings() *errx.Error {
+	return ErrRegistry.New(CodeInvalidSettings)
+}
+
+func ErrSettingsNotFound() *errx.Error {
+	return ErrRegistry.New(CodeSettingsNotFound)
+}
+
+func ErrInvalidFilter() *errx.Error {
+	return ErrRegistry.New(CodeInvalidFilter)
+}
@@ -0,0 +1,132 @@
+package fallbackapi
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/fallback"
+	"github.com/Abraxas-365/relay/fallback/fallbacksrv"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// FallbackHandler exposes the tenant's fallback settings and its unmatched
+// message counts
+type FallbackHandler struct {
+	service *fallbacksrv.FallbackService
+}
+
+// NewFallbackHandler creates a new fallback handler
+func NewFallbackHandler(service *fallbacksrv.FallbackService) *FallbackHandler {
+	return &FallbackHandler{
+		service: service,
+	}
+}
+
+// GetSettings returns the tenant's settings and its channel overrides
+// GET /api/fallback/settings
+func (h *FallbackHandler) GetSettings(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	settings, err := h.service.GetSettings(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(settings)
+}
+
+// SaveTenantSettings replaces the settings of every channel without an override
+// PUT /api/fallback/settings
+func (h *FallbackHandler) SaveTenantSettings(c *fiber.Ctx) error {
+	return h.save(c, nil)
+}
+
+// DeleteTenantSettings leaves unmatched messages unanswered
+// DELETE /api/fallback/settings
+func (h *FallbackHandler) DeleteTenantSettings(c *fiber.Ctx) error {
+	return h.delete(c, nil)
+}
+
+// SaveChannelSettings overrides the tenant's settings on one channel
+// PUT /api/fallback/settings/channels/:channel_id
+func (h *FallbackHandler) SaveChannelSettings(c *fiber.Ctx) error {
+	channelID := kernel.ChannelID(c.Params("channel_id"))
+	return h.save(c, &channelID)
+}
+
+// DeleteChannelSettings removes a channel's override
+// DELETE /api/fallback/settings/channels/:channel_id
+func (h *FallbackHandler) DeleteChannelSettings(c *fiber.Ctx) error {
+	channelID := kernel.ChannelID(c.Params("channel_id"))
+	return h.delete(c, &channelID)
+}
+
+// Stats counts the messages no workflow matched, per channel and day
+// GET /api/fallback/stats?channel_id=&from=&to=
+func (h *FallbackHandler) Stats(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	req := fallback.StatsRequest{TenantID: authContext.TenantID}
+	if channelID := c.Query("channel_id"); channelID != "" {
+		id := kernel.ChannelID(channelID)
+		req.ChannelID = &id
+	}
+	for param, target := range map[string]**time.Time{"from": &req.From, "to": &req.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fallback.ErrInvalidFilter().WithDetail(param, "must be an RFC 3339 timestamp")
+		}
+		*target = &parsed
+	}
+
+	stats, err := h.service.Stats(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(stats)
+}
+
+func (h *FallbackHandler) save(c *fiber.Ctx, channelID *kernel.ChannelID) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req fallback.SaveSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fallback.ErrInvalidSettings().WithDetail("reason", err.Error())
+	}
+
+	settings, err := h.service.SaveSettings(c.Context(), authContext.TenantID, channelID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(settings)
+}
+
+func (h *FallbackHandler) delete(c *fiber.Ctx, channelID *kernel.ChannelID) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := h.service.DeleteSettings(c.Context(), authContext.TenantID, channelID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
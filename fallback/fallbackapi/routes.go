@@ -0,0 +1,35 @@
+package fallbackapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// FallbackRoutes handles fallback route setup
+type FallbackRoutes struct {
+	handler        *FallbackHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewFallbackRoutes creates a new fallback routes instance
+func NewFallbackRoutes(handler *FallbackHandler, authMiddleware *auth.AuthMiddleware) *FallbackRoutes {
+	return &FallbackRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers fallback routes on an authenticated router.
+// Changing the settings requires an admin.
+func (r *FallbackRoutes) RegisterRoutes(router fiber.Router) {
+	fallback := router.Group("/fallback")
+
+	fallback.Get("/stats", r.handler.Stats)
+
+	settings := fallback.Group("/settings")
+	settings.Get("/", r.handler.GetSettings)
+	settings.Put("/", r.authMiddleware.RequireAdmin(), r.handler.SaveTenantSettings)
+	settings.Delete("/", r.authMiddleware.RequireAdmin(), r.handler.DeleteTenantSettings)
+	settings.Put("/channels/:channel_id", r.authMiddleware.RequireAdmin(), r.handler.SaveChannelSettings)
+	settings.Delete("/channels/:channel_id", r.authMiddleware.RequireAdmin(), r.handler.DeleteChannelSettings)
+}
@@ -0,0 +1,84 @@
+package fallbackinfra
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/fallback"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresCounterRepository is the PostgreSQL implementation of fallback.CounterRepository
+type PostgresCounterRepository struct {
+	db *sqlx.DB
+}
+
+var _ fallback.CounterRepository = (*PostgresCounterRepository)(nil)
+
+func NewPostgresCounterRepository(db *sqlx.DB) *PostgresCounterRepository {
+	return &PostgresCounterRepository{db: db}
+}
+
+func (r *PostgresCounterRepository) Increment(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, outcome fallback.Outcome, at time.Time) error {
+	var workflowRuns, messagesSent int
+	switch outcome {
+	case fallback.OutcomeWorkflow:
+		workflowRuns = 1
+	case fallback.OutcomeMessage:
+		messagesSent = 1
+	}
+
+	query := `
+		INSERT INTO unmatched_message_counts (tenant_id, channel_id, day, unmatched, workflow_runs, messages_sent)
+		VALUES ($1, $2, $3, 1, $4, $5)
+		ON CONFLICT (tenant_id, channel_id, day) DO UPDATE SET
+			unmatched = unmatched_message_counts.unmatched + 1,
+			workflow_runs = unmatched_message_counts.workflow_runs + EXCLUDED.workflow_runs,
+			messages_sent = unmatched_message_counts.messages_sent + EXCLUDED.messages_sent`
+
+	day := at.UTC().Format(time.DateOnly)
+	if _, err := r.db.ExecContext(ctx, query, tenantID.String(), channelID.String(), day, workflowRuns, messagesSent); err != nil {
+		return errx.Wrap(err, "failed to count unmatched message", errx.TypeInternal).
+			WithDetail("channel_id", channelID.String())
+	}
+
+	return nil
+}
+
+func (r *PostgresCounterRepository) Daily(ctx context.Context, req fallback.StatsRequest) ([]fallback.DailyCount, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.ChannelID != nil {
+		conditions = append(conditions, fmt.Sprintf("channel_id = $%d", argPos))
+		args = append(args, req.ChannelID.String())
+		argPos++
+	}
+	if req.From != nil {
+		conditions = append(conditions, fmt.Sprintf("day >= $%d", argPos))
+		args = append(args, req.From.UTC().Format(time.DateOnly))
+		argPos++
+	}
+	if req.To != nil {
+		conditions = append(conditions, fmt.Sprintf("day <= $%d", argPos))
+		args = append(args, req.To.UTC().Format(time.DateOnly))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT day, channel_id, unmatched, workflow_runs, messages_sent
+		FROM unmatched_message_counts
+		WHERE %s
+		ORDER BY day, channel_id`, strings.Join(conditions, " AND "))
+
+	counts := []fallback.DailyCount{}
+	if err := r.db.SelectContext(ctx, &counts, query, args...); err != nil {
+		return nil, errx.Wrap(err, "failed to list unmatched message counts", errx.TypeInternal)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,94 @@
+package fallbackinfra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/fallback"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresSettingsRepository is the PostgreSQL implementation of fallback.SettingsRepository
+type PostgresSettingsRepository struct {
+	db *sqlx.DB
+}
+
+var _ fallback.SettingsRepository = (*PostgresSettingsRepository)(nil)
+
+func NewPostgresSettingsRepository(db *sqlx.DB) *PostgresSettingsRepository {
+	return &PostgresSettingsRepository{db: db}
+}
+
+const settingsColumns = `tenant_id, channel_id, workflow_id, message, updated_at`
+
+func (r *PostgresSettingsRepository) FindEffective(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID) (*fallback.Settings, error) {
+	// The channel's row sorts before the tenant default
+	query := `SELECT ` + settingsColumns + ` FROM fallback_settings
+		WHERE tenant_id = $1 AND (channel_id = $2 OR channel_id IS NULL)
+		ORDER BY channel_id NULLS LAST
+		LIMIT 1`
+
+	var settings fallback.Settings
+	if err := r.db.GetContext(ctx, &settings, query, tenantID.String(), channelID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errx.Wrap(err, "failed to find fallback settings", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	return &settings, nil
+}
+
+func (r *PostgresSettingsRepository) List(ctx context.Context, tenantID kernel.TenantID) ([]fallback.Settings, error) {
+	query := `SELECT ` + settingsColumns + ` FROM fallback_settings
+		WHERE tenant_id = $1
+		ORDER BY channel_id NULLS FIRST`
+
+	settings := []fallback.Settings{}
+	if err := r.db.SelectContext(ctx, &settings, query, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to list fallback settings", errx.TypeInternal)
+	}
+
+	return settings, nil
+}
+
+func (r *PostgresSettingsRepository) Save(ctx context.Context, settings fallback.Settings) error {
+	query := `
+		INSERT INTO fallback_settings (` + settingsColumns + `)
+		VALUES (:tenant_id, :channel_id, :workflow_id, :message, :updated_at)
+		ON CONFLICT (tenant_id, (COALESCE(channel_id, ''))) DO UPDATE SET
+			workflow_id = EXCLUDED.workflow_id,
+			message = EXCLUDED.message,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.NamedExecContext(ctx, query, settings); err != nil {
+		return errx.Wrap(err, "failed to save fallback settings", errx.TypeInternal).
+			WithDetail("tenant_id", settings.TenantID.String())
+	}
+
+	return nil
+}
+
+func (r *PostgresSettingsRepository) Delete(ctx context.Context, tenantID kernel.TenantID, channelID *kernel.ChannelID) error {
+	query := `DELETE FROM fallback_settings WHERE tenant_id = $1 AND channel_id IS NULL`
+	args := []any{tenantID.String()}
+	if channelID != nil {
+		query = `DELETE FROM fallback_settings WHERE tenant_id = $1 AND channel_id = $2`
+		args = append(args, channelID.String())
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errx.Wrap(err, "failed to delete fallback settings", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fallback.ErrSettingsNotFound()
+	}
+
+	return nil
+}
@@ -0,0 +1,273 @@
+package fallbacksrv
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/fallback"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// settingsCacheTTL how long effective settings are reused on the inbound path
+const settingsCacheTTL = time.Minute
+
+type cacheKey struct {
+	tenantID  kernel.TenantID
+	channelID kernel.ChannelID
+}
+
+type cachedSettings struct {
+	settings  *fallback.Settings // nil = nothing configured
+	expiresAt time.Time
+}
+
+// FallbackService handles channel messages no workflow matched: it runs the
+// tenant's fallback workflow or sends the fallback message, and counts every
+// unmatched message so tenants see the gaps in their coverage
+type FallbackService struct {
+	settingsRepo   fallback.SettingsRepository
+	counterRepo    fallback.CounterRepository
+	channelRepo    channels.ChannelRepository
+	workflowRepo   engine.WorkflowRepository
+	channelManager channels.ChannelManager
+
+	mu    sync.RWMutex
+	cache map[cacheKey]cachedSettings
+}
+
+var _ engine.FallbackResolver = (*FallbackService)(nil)
+
+func NewFallbackService(
+	settingsRepo fallback.SettingsRepository,
+	counterRepo fallback.CounterRepository,
+	channelRepo channels.ChannelRepository,
+	workflowRepo engine.WorkflowRepository,
+	channelManager channels.ChannelManager,
+) *FallbackService {
+	return &FallbackService{
+		settingsRepo:   settingsRepo,
+		counterRepo:    counterRepo,
+		channelRepo:    channelRepo,
+		workflowRepo:   workflowRepo,
+		channelManager: channelManager,
+		cache:          make(map[cacheKey]cachedSettings),
+	}
+}
+
+// ============================================================================
+// Fallback
+// ============================================================================
+
+// ResolveFallback implements engine.FallbackResolver. A fallback workflow that
+// is gone, inactive or of another environment falls back to the message.
+func (s *FallbackService) ResolveFallback(
+	ctx context.Context,
+	tenantID kernel.TenantID,
+	channelID kernel.ChannelID,
+	environment kernel.Environment,
+	triggerData map[string]any,
+) *engine.Workflow {
+	settings := s.effectiveSettings(ctx, tenantID, channelID)
+
+	var wf *engine.Workflow
+	outcome := fallback.OutcomeNone
+	if settings != nil {
+		if settings.WorkflowID != nil {
+			wf = s.fallbackWorkflow(ctx, settings, environment)
+		}
+		switch {
+		case wf != nil:
+			outcome = fallback.OutcomeWorkflow
+		case settings.Message != "" && s.sendMessage(ctx, tenantID, channelID, triggerData, settings.Message):
+			outcome = fallback.OutcomeMessage
+		}
+	}
+
+	if err := s.counterRepo.Increment(context.WithoutCancel(ctx), tenantID, channelID, outcome, time.Now()); err != nil {
+		log.Printf("⚠️  Failed to count unmatched message on channel %s: %v", channelID, err)
+	}
+
+	return wf
+}
+
+// fallbackWorkflow loads the configured workflow when it can run for the
+// channel
+func (s *FallbackService) fallbackWorkflow(ctx context.Context, settings *fallback.Settings, environment kernel.Environment) *engine.Workflow {
+	wf, err := s.workflowRepo.FindByID(ctx, *settings.WorkflowID)
+	if err != nil || wf.TenantID != settings.TenantID {
+		log.Printf("⚠️  Fallback workflow %s of tenant %s not found", settings.WorkflowID, settings.TenantID)
+		return nil
+	}
+	if !wf.IsActive {
+		log.Printf("ℹ️  Fallback workflow %s is not active", wf.Name)
+		return nil
+	}
+	if wf.Environment.OrDefault() != environment.OrDefault() {
+		log.Printf("ℹ️  Fallback workflow %s is not in the %s environment", wf.Name, environment.OrDefault())
+		return nil
+	}
+	return wf
+}
+
+// sendMessage replies to the sender of the unmatched message
+func (s *FallbackService) sendMessage(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, triggerData map[string]any, text string) bool {
+	senderID, _ := triggerData["sender_id"].(string)
+	if senderID == "" || s.channelManager == nil {
+		return false
+	}
+
+	reply := channels.OutgoingMessage{
+		RecipientID: senderID,
+		Content: channels.MessageContent{
+			Type: "text",
+			Text: text,
+		},
+		Metadata: map[string]any{
+			"origin":   string(conversation.OriginSystem),
+			"fallback": true,
+		},
+	}
+	if err := s.channelManager.SendMessage(ctx, tenantID, channelID, reply); err != nil {
+		log.Printf("⚠️  Failed to send fallback reply to %s: %v", senderID, err)
+		return false
+	}
+	return true
+}
+
+// effectiveSettings returns the channel's or the tenant's settings, nil when
+// neither is stored, reusing them for settingsCacheTTL
+func (s *FallbackService) effectiveSettings(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID) *fallback.Settings {
+	key := cacheKey{tenantID: tenantID, channelID: channelID}
+
+	s.mu.RLock()
+	cached, ok := s.cache[key]
+	s.mu.RUnlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.settings
+	}
+
+	settings, err := s.settingsRepo.FindEffective(ctx, tenantID, channelID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load fallback settings for tenant %s: %v", tenantID, err)
+	}
+
+	s.mu.Lock()
+	s.cache[key] = cachedSettings{settings: settings, expiresAt: time.Now().Add(settingsCacheTTL)}
+	s.mu.Unlock()
+
+	return settings
+}
+
+// ============================================================================
+// Settings
+// ============================================================================
+
+// GetSettings returns the tenant's settings and its channel overrides
+func (s *FallbackService) GetSettings(ctx context.Context, tenantID kernel.TenantID) (*fallback.SettingsResponse, error) {
+	all, err := s.settingsRepo.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &fallback.SettingsResponse{Channels: []fallback.Settings{}}
+	for i := range all {
+		if all[i].ChannelID == nil {
+			view.Tenant = &all[i]
+		} else {
+			view.Channels = append(view.Channels, all[i])
+		}
+	}
+
+	return view, nil
+}
+
+// SaveSettings replaces the tenant's settings, or a channel's when channelID is set
+func (s *FallbackService) SaveSettings(ctx context.Context, tenantID kernel.TenantID, channelID *kernel.ChannelID, req fallback.SaveSettingsRequest) (*fallback.Settings, error) {
+	settings := req.Settings(tenantID, channelID)
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	if channelID != nil {
+		if _, err := s.channelRepo.FindByID(ctx, *channelID, tenantID); err != nil {
+			return nil, err
+		}
+	}
+	if settings.WorkflowID != nil {
+		wf, err := s.workflowRepo.FindByID(ctx, *settings.WorkflowID)
+		if err != nil || wf.TenantID != tenantID {
+			return nil, engine.ErrWorkflowNotFound().WithDetail("workflow_id", settings.WorkflowID.String())
+		}
+	}
+
+	if err := s.settingsRepo.Save(ctx, *settings); err != nil {
+		return nil, err
+	}
+	s.invalidate(tenantID)
+
+	log.Printf("✅ Fallback settings updated for tenant %s (channel=%v workflow=%v message=%v)",
+		tenantID, channelID != nil, settings.WorkflowID != nil, settings.Message != "")
+
+	return settings, nil
+}
+
+// DeleteSettings removes the tenant's settings, or a channel's override
+func (s *FallbackService) DeleteSettings(ctx context.Context, tenantID kernel.TenantID, channelID *kernel.ChannelID) error {
+	if err := s.settingsRepo.Delete(ctx, tenantID, channelID); err != nil {
+		return err
+	}
+	s.invalidate(tenantID)
+	return nil
+}
+
+// invalidate drops every cached channel of the tenant, since tenant
+// settings apply to all of them
+func (s *FallbackService) invalidate(tenantID kernel.TenantID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.cache {
+		if key.tenantID == tenantID {
+			delete(s.cache, key)
+		}
+	}
+}
+
+// ============================================================================
+// Stats
+// ============================================================================
+
+// Stats counts the tenant's unmatched messages per channel and day
+func (s *FallbackService) Stats(ctx context.Context, req fallback.StatsRequest) (*fallback.StatsResponse, error) {
+	now := time.Now()
+	if req.To == nil {
+		req.To = &now
+	}
+	if req.From == nil {
+		from := req.To.AddDate(0, 0, -fallback.DefaultStatsDays)
+		req.From = &from
+	}
+	if req.From.After(*req.To) {
+		return nil, fallback.ErrInvalidFilter().WithDetail("reason", "from must be before to")
+	}
+
+	days, err := s.counterRepo.Daily(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &fallback.StatsResponse{From: *req.From, To: *req.To, Days: days}
+	for _, day := range days {
+		response.Unmatched += day.Unmatched
+		response.WorkflowRuns += day.WorkflowRuns
+		response.MessagesSent += day.MessagesSent
+		response.Unhandled += day.Unhandled()
+	}
+
+	return response, nil
+}
@@ -0,0 +1,64 @@
+package fallback
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Fallback Settings
+// ============================================================================
+
+// MaxMessageLength bounds the reply sent to unmatched messages
+const MaxMessageLength = 1000
+
+// Settings decide what happens to a channel message no workflow matched: the
+// fallback workflow runs as if it had matched, else the fallback message is
+// sent back. A channel's settings override the tenant's.
+type Settings struct {
+	TenantID   kernel.TenantID    `db:"tenant_id" json:"tenant_id"`
+	ChannelID  *kernel.ChannelID  `db:"channel_id" json:"channel_id,omitempty"`   // nil = tenant default
+	WorkflowID *kernel.WorkflowID `db:"workflow_id" json:"workflow_id,omitempty"` // Any workflow of the tenant, whatever its trigger
+	Message    string             `db:"message" json:"message"`                   // Sent when there is no usable workflow
+	UpdatedAt  time.Time          `db:"updated_at" json:"updated_at"`
+}
+
+// Validate checks the settings do something
+func (s *Settings) Validate() error {
+	if s.WorkflowID == nil && s.Message == "" {
+		return ErrInvalidSettings().WithDetail("reason", "a workflow_id or a message is required")
+	}
+	if len(s.Message) > MaxMessageLength {
+		return ErrInvalidSettings().WithDetail("field", "message").WithDetail("max_length", MaxMessageLength)
+	}
+	return nil
+}
+
+// ============================================================================
+// Unmatched Messages
+// ============================================================================
+
+// Outcome is what the fallback did with an unmatched message
+type Outcome string
+
+const (
+	OutcomeWorkflow Outcome = "workflow" // The fallback workflow ran
+	OutcomeMessage  Outcome = "message"  // The fallback message was sent
+	OutcomeNone     Outcome = "none"     // Nothing configured, or the fallback failed
+)
+
+// DailyCount is how many messages of one channel matched no workflow in a
+// day (UTC), and what the fallback did with them
+type DailyCount struct {
+	Day          time.Time        `db:"day" json:"day"`
+	ChannelID    kernel.ChannelID `db:"channel_id" json:"channel_id"`
+	Unmatched    int              `db:"unmatched" json:"unmatched"`
+	WorkflowRuns int              `db:"workflow_runs" json:"workflow_runs"`
+	MessagesSent int              `db:"messages_sent" json:"messages_sent"`
+}
+
+// Unhandled is how many unmatched messages got no fallback at all
+func (c DailyCount) Unhandled() int {
+	return c.Unmatched - c.WorkflowRuns - c.MessagesSent
+}
@@ -0,0 +1,39 @@
+package featureflag
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("FEATURE_FLAG")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeUnknownFlag     = ErrRegistry.Register("UNKNOWN_FLAG", errx.TypeNotFound, http.StatusNotFound, "Unknown feature flag")
+	CodeInvalidRequest  = ErrRegistry.Register("INVALID_REQUEST", errx.TypeValidation, http.StatusBadRequest, "Invalid feature flag request")
+	CodeFeatureDisabled = ErrRegistry.Register("FEATURE_DISABLED", errx.TypeBusiness, http.StatusForbidden, "Feature is disabled for this tenant")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrUnknownFlag() *errx.Error {
+	return ErrRegistry.New(CodeUnknownFlag)
+}
+
+func ErrInvalidRequest() *errx.Error {
+	return ErrRegistry.New(CodeInvalidRequest)
+}
+
+func ErrFeatureDisabled() *errx.Error {
+	return ErrRegistry.New(CodeFeatureDisabled)
+}
@@ -0,0 +1,195 @@
+package featureflag
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Flags
+// ============================================================================
+
+// Flag identifies a runtime-toggleable feature
+type Flag string
+
+const (
+	// FlagMessageBuffering lets channels that have buffering configured group
+	// bursts of incoming messages. Disabling it delivers every message at once.
+	FlagMessageBuffering Flag = "message_buffering"
+
+	// FlagAIAgent allows AI_AGENT nodes to call the model
+	FlagAIAgent Flag = "ai_agent"
+
+	// FlagFaultInjection applies the tenant's fault injection rules. It has no
+	// effect unless injection is enabled for the environment.
+	FlagFaultInjection Flag = "fault_injection"
+
+	// FlagImageUnderstanding lets inbound images be read by a vision provider.
+	// Workflows still have to opt in, since every image is a billed call.
+	FlagImageUnderstanding Flag = "image_understanding"
+)
+
+// channelFlagPrefix namespaces the per-adapter flags, e.g. "channel.instagram"
+const channelFlagPrefix = "channel."
+
+// TenantSettingPrefix prefixes the tenant_config keys that override a flag,
+// e.g. "feature.ai_agent" = "false"
+const TenantSettingPrefix = "feature."
+
+// ChannelFlag gates the adapter of a channel type. Adapters are enabled unless
+// switched off, so new channel types need no registration here.
+func ChannelFlag(channelType string) Flag {
+	return Flag(channelFlagPrefix + strings.ToLower(channelType))
+}
+
+// TenantSettingKey returns the tenant_config key that overrides flag
+func TenantSettingKey(flag Flag) string {
+	return TenantSettingPrefix + string(flag)
+}
+
+// ============================================================================
+// Definitions
+// ============================================================================
+
+// Definition describes a flag and its value when nothing overrides it
+type Definition struct {
+	Flag        Flag   `json:"flag"`
+	Description string `json:"description"`
+	Default     bool   `json:"default"`
+}
+
+var definitions = map[Flag]Definition{
+	FlagMessageBuffering: {
+		Flag:        FlagMessageBuffering,
+		Description: "Buffer bursts of incoming messages on channels that configure it",
+		Default:     true,
+	},
+	FlagAIAgent: {
+		Flag:        FlagAIAgent,
+		Description: "Run AI_AGENT workflow nodes",
+		Default:     true,
+	},
+	FlagFaultInjection: {
+		Flag:        FlagFaultInjection,
+		Description: "Apply fault injection rules to workflow nodes",
+		Default:     false,
+	},
+	FlagImageUnderstanding: {
+		Flag:        FlagImageUnderstanding,
+		Description: "Extract text and a description from inbound images for workflows that opt in",
+		Default:     false,
+	},
+}
+
+// Lookup returns the definition of a known flag. Channel flags are always known.
+func Lookup(flag Flag) (Definition, bool) {
+	if def, ok := definitions[flag]; ok {
+		return def, true
+	}
+	if name, ok := strings.CutPrefix(string(flag), channelFlagPrefix); ok && name != "" {
+		return Definition{
+			Flag:        flag,
+			Description: "Enable the " + name + " channel adapter",
+			Default:     true,
+		}, true
+	}
+	return Definition{}, false
+}
+
+// Definitions returns the registered flags sorted by name
+func Definitions() []Definition {
+	defs := make([]Definition, 0, len(definitions))
+	for _, def := range definitions {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Flag < defs[j].Flag })
+	return defs
+}
+
+// ============================================================================
+// Resolution
+// ============================================================================
+
+// Source tells which layer decided a flag's value
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceGlobal  Source = "global"
+	SourceTenant  Source = "tenant"
+)
+
+// State is the effective value of a flag for a tenant
+type State struct {
+	Flag        Flag   `json:"flag"`
+	Enabled     bool   `json:"enabled"`
+	Source      Source `json:"source"`
+	Description string `json:"description,omitempty"`
+}
+
+// Set holds the resolved flags of a tenant
+type Set map[Flag]State
+
+// Resolve layers the global rows and the tenant's "feature.*" settings over the
+// built-in defaults. Unparseable tenant values are ignored.
+func Resolve(global map[Flag]bool, tenantSettings map[string]string) Set {
+	set := make(Set, len(definitions)+len(global))
+
+	for _, def := range definitions {
+		set[def.Flag] = State{Flag: def.Flag, Enabled: def.Default, Source: SourceDefault, Description: def.Description}
+	}
+
+	for flag, enabled := range global {
+		set[flag] = State{Flag: flag, Enabled: enabled, Source: SourceGlobal, Description: describe(flag)}
+	}
+
+	for key, value := range tenantSettings {
+		name, ok := strings.CutPrefix(key, TenantSettingPrefix)
+		if !ok || name == "" {
+			continue
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			continue
+		}
+		flag := Flag(name)
+		set[flag] = State{Flag: flag, Enabled: enabled, Source: SourceTenant, Description: describe(flag)}
+	}
+
+	return set
+}
+
+// Enabled reports the flag's value; flags absent from the set fall back to
+// their definition, and unknown flags are off
+func (s Set) Enabled(flag Flag) bool {
+	if state, ok := s[flag]; ok {
+		return state.Enabled
+	}
+	def, ok := Lookup(flag)
+	return ok && def.Default
+}
+
+// States returns the set sorted by flag name
+func (s Set) States() []State {
+	states := make([]State, 0, len(s))
+	for _, state := range s {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Flag < states[j].Flag })
+	return states
+}
+
+// ============================================================================
+// Requests
+// ============================================================================
+
+// SetFlagRequest overrides a flag for the caller's tenant
+type SetFlagRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+func describe(flag Flag) string {
+	def, _ := Lookup(flag)
+	return def.Description
+}
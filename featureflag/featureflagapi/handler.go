@@ -0,0 +1,78 @@
+package featureflagapi
+
+import (
+	"github.com/Abraxas-365/relay/featureflag"
+	"github.com/Abraxas-365/relay/featureflag/featureflagsrv"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// FeatureFlagHandler exposes the tenant's feature flags
+type FeatureFlagHandler struct {
+	service *featureflagsrv.FlagService
+}
+
+// NewFeatureFlagHandler creates a new feature flag handler
+func NewFeatureFlagHandler(service *featureflagsrv.FlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		service: service,
+	}
+}
+
+// List returns the effective flags of the tenant and where each value comes from
+// GET /api/features
+func (h *FeatureFlagHandler) List(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	flags, err := h.service.List(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"flags": flags,
+	})
+}
+
+// Set overrides a flag for the tenant
+// PUT /api/features/:flag
+func (h *FeatureFlagHandler) Set(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req featureflag.SetFlagRequest
+	if err := c.BodyParser(&req); err != nil {
+		return featureflag.ErrInvalidRequest().WithDetail("reason", err.Error())
+	}
+	if req.Enabled == nil {
+		return featureflag.ErrInvalidRequest().WithDetail("reason", "enabled is required")
+	}
+
+	state, err := h.service.SetTenantFlag(c.Context(), authContext.TenantID, featureflag.Flag(c.Params("flag")), *req.Enabled)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(state)
+}
+
+// Clear removes the tenant override of a flag
+// DELETE /api/features/:flag
+func (h *FeatureFlagHandler) Clear(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := h.service.ClearTenantFlag(c.Context(), authContext.TenantID, featureflag.Flag(c.Params("flag"))); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,30 @@
+package featureflagapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// FeatureFlagRoutes handles feature flag route setup
+type FeatureFlagRoutes struct {
+	handler        *FeatureFlagHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewFeatureFlagRoutes creates a new feature flag routes instance
+func NewFeatureFlagRoutes(handler *FeatureFlagHandler, authMiddleware *auth.AuthMiddleware) *FeatureFlagRoutes {
+	return &FeatureFlagRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers feature flag routes on an authenticated router.
+// Overriding a flag requires an admin.
+func (r *FeatureFlagRoutes) RegisterRoutes(router fiber.Router) {
+	features := router.Group("/features")
+
+	features.Get("/", r.handler.List)
+	features.Put("/:flag", r.authMiddleware.RequireAdmin(), r.handler.Set)
+	features.Delete("/:flag", r.authMiddleware.RequireAdmin(), r.handler.Clear)
+}
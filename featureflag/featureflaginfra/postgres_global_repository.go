@@ -0,0 +1,37 @@
+package featureflaginfra
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/featureflag"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresGlobalRepository is the PostgreSQL implementation of featureflag.GlobalRepository
+type PostgresGlobalRepository struct {
+	db *sqlx.DB
+}
+
+var _ featureflag.GlobalRepository = (*PostgresGlobalRepository)(nil)
+
+func NewPostgresGlobalRepository(db *sqlx.DB) *PostgresGlobalRepository {
+	return &PostgresGlobalRepository{db: db}
+}
+
+func (r *PostgresGlobalRepository) FindAll(ctx context.Context) (map[featureflag.Flag]bool, error) {
+	var rows []struct {
+		Flag    string `db:"flag"`
+		Enabled bool   `db:"enabled"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, `SELECT flag, enabled FROM feature_flags`); err != nil {
+		return nil, errx.Wrap(err, "failed to load global feature flags", errx.TypeInternal)
+	}
+
+	flags := make(map[featureflag.Flag]bool, len(rows))
+	for _, row := range rows {
+		flags[featureflag.Flag(row.Flag)] = row.Enabled
+	}
+
+	return flags, nil
+}
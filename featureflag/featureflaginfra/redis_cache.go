@@ -0,0 +1,101 @@
+package featureflaginfra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/featureflag"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/go-redis/redis/v8"
+)
+
+// invalidationChannel carries the ID of the tenant whose flags changed
+const invalidationChannel = "featureflags:invalidate"
+
+// RedisCache is the Redis implementation of featureflag.Cache. Entries expire
+// after ttl, which bounds how long a direct edit of the feature_flags table
+// takes to reach every instance.
+type RedisCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+var _ featureflag.Cache = (*RedisCache)(nil)
+
+func NewRedisCache(client *redis.Client, ttl time.Duration) *RedisCache {
+	return &RedisCache{client: client, ttl: ttl}
+}
+
+func (c *RedisCache) Get(ctx context.Context, tenantID kernel.TenantID) (featureflag.Set, bool, error) {
+	data, err := c.client.Get(ctx, c.key(tenantID)).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errx.Wrap(err, "failed to read feature flag cache", errx.TypeUnavailable)
+	}
+
+	var set featureflag.Set
+	if err := json.Unmarshal(data, &set); err != nil {
+		// A corrupt entry is treated as a miss and overwritten
+		return nil, false, nil
+	}
+
+	return set, true, nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, tenantID kernel.TenantID, set featureflag.Set) error {
+	data, err := json.Marshal(set)
+	if err != nil {
+		return errx.Wrap(err, "failed to encode feature flags", errx.TypeInternal)
+	}
+
+	if err := c.client.Set(ctx, c.key(tenantID), data, c.ttl).Err(); err != nil {
+		return errx.Wrap(err, "failed to write feature flag cache", errx.TypeUnavailable)
+	}
+
+	return nil
+}
+
+func (c *RedisCache) Invalidate(ctx context.Context, tenantID kernel.TenantID) error {
+	if err := c.client.Del(ctx, c.key(tenantID)).Err(); err != nil {
+		return errx.Wrap(err, "failed to invalidate feature flag cache", errx.TypeUnavailable)
+	}
+
+	if err := c.client.Publish(ctx, invalidationChannel, tenantID.String()).Err(); err != nil {
+		return errx.Wrap(err, "failed to publish feature flag invalidation", errx.TypeUnavailable)
+	}
+
+	return nil
+}
+
+func (c *RedisCache) Subscribe(ctx context.Context, handler func(tenantID kernel.TenantID)) error {
+	pubsub := c.client.Subscribe(ctx, invalidationChannel)
+	defer pubsub.Close()
+
+	// Wait for the subscription so invalidations published right after
+	// startup are not missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return errx.Wrap(err, "failed to subscribe to feature flag invalidations", errx.TypeUnavailable)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			handler(kernel.TenantID(msg.Payload))
+		}
+	}
+}
+
+func (c *RedisCache) key(tenantID kernel.TenantID) string {
+	return fmt.Sprintf("featureflags:tenant:%s", tenantID)
+}
@@ -0,0 +1,258 @@
+package featureflagsrv
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/relay/featureflag"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+const (
+	// localCacheTTL bounds staleness when an invalidation is missed (Redis down,
+	// subscription reconnecting). Normally entries are dropped on invalidation.
+	localCacheTTL = 30 * time.Second
+
+	// resubscribeDelay wait before re-subscribing after the connection dropped
+	resubscribeDelay = 5 * time.Second
+)
+
+type cachedSet struct {
+	set       featureflag.Set
+	expiresAt time.Time
+}
+
+// FlagService resolves feature flags per tenant. Lookups go through an
+// in-process cache, then the shared cache, then the database; writes
+// invalidate every instance through the shared cache.
+type FlagService struct {
+	globalRepo       featureflag.GlobalRepository
+	tenantConfigRepo tenant.TenantConfigRepository
+	cache            featureflag.Cache // nil = in-process cache only
+
+	mu    sync.RWMutex
+	local map[kernel.TenantID]cachedSet
+
+	listenersMu sync.RWMutex
+	listeners   []featureflag.ChangeListener
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+var _ featureflag.Checker = (*FlagService)(nil)
+
+func NewFlagService(
+	globalRepo featureflag.GlobalRepository,
+	tenantConfigRepo tenant.TenantConfigRepository,
+	cache featureflag.Cache,
+) *FlagService {
+	return &FlagService{
+		globalRepo:       globalRepo,
+		tenantConfigRepo: tenantConfigRepo,
+		cache:            cache,
+		local:            make(map[kernel.TenantID]cachedSet),
+		stopChan:         make(chan struct{}),
+	}
+}
+
+// AddListener registers a component to notify when a tenant's flags change
+func (s *FlagService) AddListener(listener featureflag.ChangeListener) {
+	s.listenersMu.Lock()
+	s.listeners = append(s.listeners, listener)
+	s.listenersMu.Unlock()
+}
+
+// IsEnabled implements featureflag.Checker
+func (s *FlagService) IsEnabled(ctx context.Context, tenantID kernel.TenantID, flag featureflag.Flag) bool {
+	set, err := s.Resolve(ctx, tenantID)
+	if err != nil {
+		log.Printf("⚠️  Failed to resolve feature flags for tenant %s, using defaults: %v", tenantID, err)
+		return featureflag.Set(nil).Enabled(flag)
+	}
+	return set.Enabled(flag)
+}
+
+// Resolve returns the tenant's effective flags
+func (s *FlagService) Resolve(ctx context.Context, tenantID kernel.TenantID) (featureflag.Set, error) {
+	s.mu.RLock()
+	cached, ok := s.local[tenantID]
+	s.mu.RUnlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.set, nil
+	}
+
+	set, err := s.load(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.local[tenantID] = cachedSet{set: set, expiresAt: time.Now().Add(localCacheTTL)}
+	s.mu.Unlock()
+
+	return set, nil
+}
+
+// List returns the tenant's effective flags sorted by name
+func (s *FlagService) List(ctx context.Context, tenantID kernel.TenantID) ([]featureflag.State, error) {
+	set, err := s.Resolve(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return set.States(), nil
+}
+
+// SetTenantFlag overrides a flag for one tenant
+func (s *FlagService) SetTenantFlag(ctx context.Context, tenantID kernel.TenantID, flag featureflag.Flag, enabled bool) (*featureflag.State, error) {
+	if _, ok := featureflag.Lookup(flag); !ok {
+		return nil, featureflag.ErrUnknownFlag().WithDetail("flag", string(flag))
+	}
+
+	if err := s.tenantConfigRepo.SaveSetting(ctx, tenantID, featureflag.TenantSettingKey(flag), strconv.FormatBool(enabled)); err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx, tenantID)
+
+	log.Printf("🚩 Feature flag %s set to %v for tenant %s", flag, enabled, tenantID)
+
+	set, err := s.Resolve(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	state := set[flag]
+	return &state, nil
+}
+
+// ClearTenantFlag removes a tenant override so the global or default value applies
+func (s *FlagService) ClearTenantFlag(ctx context.Context, tenantID kernel.TenantID, flag featureflag.Flag) error {
+	if _, ok := featureflag.Lookup(flag); !ok {
+		return featureflag.ErrUnknownFlag().WithDetail("flag", string(flag))
+	}
+
+	if err := s.tenantConfigRepo.DeleteSetting(ctx, tenantID, featureflag.TenantSettingKey(flag)); err != nil {
+		return err
+	}
+	s.invalidate(ctx, tenantID)
+
+	log.Printf("🚩 Feature flag %s override cleared for tenant %s", flag, tenantID)
+	return nil
+}
+
+// Start listens for invalidations from other instances until Stop is called
+// or ctx is done
+func (s *FlagService) Start(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.stopChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	log.Println("🚩 Listening for feature flag invalidations...")
+	for {
+		if err := s.cache.Subscribe(ctx, s.onInvalidation); err != nil {
+			log.Printf("⚠️  Feature flag subscription failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("⏹️  Feature flag listener stopped")
+			return
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+// Stop stops the invalidation listener
+func (s *FlagService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+	})
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func (s *FlagService) load(ctx context.Context, tenantID kernel.TenantID) (featureflag.Set, error) {
+	if s.cache != nil {
+		set, ok, err := s.cache.Get(ctx, tenantID)
+		if err != nil {
+			log.Printf("⚠️  Feature flag cache unavailable: %v", err)
+		} else if ok {
+			return set, nil
+		}
+	}
+
+	global, err := s.globalRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.tenantConfigRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	set := featureflag.Resolve(global, settings)
+
+	if s.cache != nil {
+		if err := s.cache.Set(ctx, tenantID, set); err != nil {
+			log.Printf("⚠️  Failed to cache feature flags for tenant %s: %v", tenantID, err)
+		}
+	}
+
+	return set, nil
+}
+
+// invalidate drops the tenant's flags everywhere. Listeners on this instance
+// are notified through the subscription like everyone else; if the broadcast
+// fails they are notified directly.
+func (s *FlagService) invalidate(ctx context.Context, tenantID kernel.TenantID) {
+	s.dropLocal(tenantID)
+
+	if s.cache != nil {
+		err := s.cache.Invalidate(ctx, tenantID)
+		if err == nil {
+			return
+		}
+		log.Printf("⚠️  Failed to broadcast feature flag change for tenant %s: %v", tenantID, err)
+	}
+
+	s.notify(tenantID)
+}
+
+func (s *FlagService) onInvalidation(tenantID kernel.TenantID) {
+	s.dropLocal(tenantID)
+	s.notify(tenantID)
+}
+
+func (s *FlagService) dropLocal(tenantID kernel.TenantID) {
+	s.mu.Lock()
+	delete(s.local, tenantID)
+	s.mu.Unlock()
+}
+
+func (s *FlagService) notify(tenantID kernel.TenantID) {
+	s.listenersMu.RLock()
+	listeners := s.listeners
+	s.listenersMu.RUnlock()
+
+	ctx := context.Background()
+	for _, listener := range listeners {
+		listener.OnFlagsChanged(ctx, tenantID)
+	}
+}
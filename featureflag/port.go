@@ -0,0 +1,48 @@
+package featureflag
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Consumer Interfaces
+// ============================================================================
+
+// Checker is what engine and channel components consult at runtime. It never
+// fails: lookup errors fall back to the flag's default.
+type Checker interface {
+	IsEnabled(ctx context.Context, tenantID kernel.TenantID, flag Flag) bool
+}
+
+// ChangeListener is notified when a tenant's flags change on any instance, so
+// components that cache flag-dependent state can rebuild it
+type ChangeListener interface {
+	OnFlagsChanged(ctx context.Context, tenantID kernel.TenantID)
+}
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// GlobalRepository reads the platform-wide flag values
+type GlobalRepository interface {
+	// FindAll returns every flag with a global value
+	FindAll(ctx context.Context) (map[Flag]bool, error)
+}
+
+// Cache shares resolved flag sets between instances and broadcasts
+// invalidations so every instance drops its local copy
+type Cache interface {
+	// Get returns the cached set; ok is false on a miss
+	Get(ctx context.Context, tenantID kernel.TenantID) (set Set, ok bool, err error)
+
+	Set(ctx context.Context, tenantID kernel.TenantID, set Set) error
+
+	// Invalidate removes the tenant's entry and notifies every subscriber
+	Invalidate(ctx context.Context, tenantID kernel.TenantID) error
+
+	// Subscribe calls handler for each invalidation until ctx is done
+	Subscribe(ctx context.Context, handler func(tenantID kernel.TenantID)) error
+}
@@ -0,0 +1,22 @@
+package helpdesk
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// ConnectRequest connects a helpdesk account with API credentials
+type ConnectRequest struct {
+	Domain   string `json:"domain"`          // acme, acme.zendesk.com or acme.freshdesk.com
+	Email    string `json:"email,omitempty"` // Zendesk agent the API token belongs to
+	APIToken string `json:"api_token"`       // Zendesk API token or Freshdesk API key
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+// ConnectionsResponse is what a tenant connected and what it can connect
+type ConnectionsResponse struct {
+	Connections []Connection `json:"connections"`
+	Available   []Provider   `json:"available"`
+}
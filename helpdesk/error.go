@@ -0,0 +1,74 @@
+package helpdesk
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("HELPDESK")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeConnectionNotFound    = ErrRegistry.Register("CONNECTION_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Helpdesk is not connected")
+	CodeAmbiguousConnection   = ErrRegistry.Register("AMBIGUOUS_CONNECTION", errx.TypeValidation, http.StatusBadRequest, "Several helpdesks are connected; choose a provider")
+	CodeInvalidProvider       = ErrRegistry.Register("INVALID_PROVIDER", errx.TypeValidation, http.StatusBadRequest, "Invalid helpdesk provider")
+	CodeProviderNotConfigured = ErrRegistry.Register("PROVIDER_NOT_CONFIGURED", errx.TypeBusiness, http.StatusServiceUnavailable, "Helpdesk provider is not configured on this server")
+	CodeInvalidConnection     = ErrRegistry.Register("INVALID_CONNECTION", errx.TypeValidation, http.StatusBadRequest, "Invalid helpdesk connection")
+	CodeInvalidTicket         = ErrRegistry.Register("INVALID_TICKET", errx.TypeValidation, http.StatusBadRequest, "Invalid ticket request")
+	CodeLinkNotFound          = ErrRegistry.Register("LINK_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Conversation has no ticket")
+	CodeTicketClosed          = ErrRegistry.Register("TICKET_CLOSED", errx.TypeConflict, http.StatusConflict, "Ticket is closed or was deleted")
+	CodeCredentialsRejected   = ErrRegistry.Register("CREDENTIALS_REJECTED", errx.TypeAuthorization, http.StatusUnauthorized, "Helpdesk rejected the credentials")
+	CodeRequestFailed         = ErrRegistry.Register("REQUEST_FAILED", errx.TypeExternal, http.StatusBadGateway, "Helpdesk request failed")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrConnectionNotFound() *errx.Error {
+	return ErrRegistry.New(CodeConnectionNotFound)
+}
+
+func ErrAmbiguousConnection() *errx.Error {
+	return ErrRegistry.New(CodeAmbiguousConnection)
+}
+
+func ErrInvalidProvider() *errx.Error {
+	return ErrRegistry.New(CodeInvalidProvider)
+}
+
+func ErrProviderNotConfigured() *errx.Error {
+	return ErrRegistry.New(CodeProviderNotConfigured)
+}
+
+func ErrInvalidConnection() *errx.Error {
+	return ErrRegistry.New(CodeInvalidConnection)
+}
+
+func ErrInvalidTicket() *errx.Error {
+	return ErrRegistry.New(CodeInvalidTicket)
+}
+
+func ErrLinkNotFound() *errx.Error {
+	return ErrRegistry.New(CodeLinkNotFound)
+}
+
+func ErrTicketClosed() *errx.Error {
+	return ErrRegistry.New(CodeTicketClosed)
+}
+
+func ErrCredentialsRejected() *errx.Error {
+	return ErrRegistry.New(CodeCredentialsRejected)
+}
+
+func ErrRequestFailed() *errx.Error {
+	return ErrRegistry.New(CodeRequestFailed)
+}
@@ -0,0 +1,121 @@
+package helpdesk
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Providers
+// ============================================================================
+
+// Provider is a helpdesk a tenant can connect
+type Provider string
+
+const (
+	ProviderZendesk   Provider = "zendesk"
+	ProviderFreshdesk Provider = "freshdesk"
+)
+
+func (p Provider) IsValid() bool {
+	return p == ProviderZendesk || p == ProviderFreshdesk
+}
+
+// ============================================================================
+// Connection
+// ============================================================================
+
+// domainPattern is a helpdesk account: a subdomain (acme) or a host
+// (acme.zendesk.com, support.acme.com)
+var domainPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+
+// Connection is a tenant's API access to one helpdesk account. Credentials
+// are never serialized; the repository stores them sealed with the tenant's
+// key.
+type Connection struct {
+	TenantID    kernel.TenantID `json:"tenant_id"`
+	Provider    Provider        `json:"provider"`
+	Domain      string          `json:"domain"` // Account subdomain or host
+	Credentials Credentials     `json:"-"`
+	ConnectedAt time.Time       `json:"connected_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// Credentials authenticate API calls: Zendesk uses an agent's email with an
+// API token, Freshdesk an API key alone
+type Credentials struct {
+	Email    string `json:"email,omitempty"`
+	APIToken string `json:"api_token"`
+}
+
+// Host is the account's API host; a bare subdomain is completed with the
+// provider's domain
+func (c Connection) Host() string {
+	if strings.Contains(c.Domain, ".") {
+		return c.Domain
+	}
+	switch c.Provider {
+	case ProviderZendesk:
+		return c.Domain + ".zendesk.com"
+	case ProviderFreshdesk:
+		return c.Domain + ".freshdesk.com"
+	}
+	return c.Domain
+}
+
+// NormalizeDomain lowercases a domain and strips a scheme and path pasted
+// with it, so https://acme.zendesk.com/ is acme.zendesk.com
+func NormalizeDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	domain = strings.TrimPrefix(domain, "https://")
+	domain = strings.TrimPrefix(domain, "http://")
+	domain, _, _ = strings.Cut(domain, "/")
+	return domain
+}
+
+func ValidDomain(domain string) bool {
+	return len(domain) <= 253 && domainPattern.MatchString(domain)
+}
+
+// ============================================================================
+// Tickets
+// ============================================================================
+
+// TicketLink is the ticket a conversation's messages are written to. It is
+// replaced when the ticket is closed in the helpdesk.
+type TicketLink struct {
+	TenantID       kernel.TenantID       `json:"tenant_id"`
+	ChannelID      kernel.ChannelID      `json:"channel_id"`
+	ConversationID string                `json:"conversation_id"`
+	Provider       Provider              `json:"provider"`
+	TicketID       string                `json:"ticket_id"`
+	Priority       engine.TicketPriority `json:"priority"`
+	SyncedUntil    time.Time             `json:"synced_until"` // Creation time of the last message written to the ticket
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+// Ticket is what a connector writes: a new ticket, or a private comment
+// appended to an existing one
+type Ticket struct {
+	Subject   string // New tickets only
+	Body      string
+	Priority  engine.TicketPriority // Empty = unchanged
+	Tags      []string              // New tickets only
+	Requester Requester             // New tickets only
+
+	Transcript     []byte // Plain text attached to the ticket or comment; nil = none
+	TranscriptName string
+}
+
+// Requester is the contact a new ticket is opened for
+type Requester struct {
+	Name       string
+	Email      string
+	Phone      string
+	ExternalID string // The conversation ID, when neither email nor phone is known
+}
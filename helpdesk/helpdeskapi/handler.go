@@ -0,0 +1,73 @@
+package helpdeskapi
+
+import (
+	"github.com/Abraxas-365/relay/helpdesk"
+	"github.com/Abraxas-365/relay/helpdesk/helpdesksrv"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HelpdeskHandler connects tenants to their helpdesk
+type HelpdeskHandler struct {
+	service *helpdesksrv.HelpdeskService
+}
+
+// NewHelpdeskHandler creates a new helpdesk handler
+func NewHelpdeskHandler(service *helpdesksrv.HelpdeskService) *HelpdeskHandler {
+	return &HelpdeskHandler{
+		service: service,
+	}
+}
+
+// ListConnections returns the tenant's connections and the providers it can connect
+// GET /api/helpdesk/connections
+func (h *HelpdeskHandler) ListConnections(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	connections, err := h.service.ListConnections(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(connections)
+}
+
+// Connect verifies API credentials and saves the connection
+// PUT /api/helpdesk/connections/:provider
+func (h *HelpdeskHandler) Connect(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req helpdesk.ConnectRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helpdesk.ErrInvalidConnection().WithDetail("reason", err.Error())
+	}
+
+	connection, err := h.service.Connect(c.Context(), authContext.TenantID, helpdesk.Provider(c.Params("provider")), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(connection)
+}
+
+// Disconnect forgets the tenant's credentials for the helpdesk
+// DELETE /api/helpdesk/connections/:provider
+func (h *HelpdeskHandler) Disconnect(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := h.service.Disconnect(c.Context(), authContext.TenantID, helpdesk.Provider(c.Params("provider"))); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,30 @@
+package helpdeskapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HelpdeskRoutes handles helpdesk route setup
+type HelpdeskRoutes struct {
+	handler        *HelpdeskHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewHelpdeskRoutes creates a new helpdesk routes instance
+func NewHelpdeskRoutes(handler *HelpdeskHandler, authMiddleware *auth.AuthMiddleware) *HelpdeskRoutes {
+	return &HelpdeskRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers helpdesk routes on an authenticated router.
+// Connecting and disconnecting requires an admin.
+func (r *HelpdeskRoutes) RegisterRoutes(router fiber.Router) {
+	connections := router.Group("/helpdesk/connections")
+
+	connections.Get("/", r.handler.ListConnections)
+	connections.Put("/:provider", r.authMiddleware.RequireAdmin(), r.handler.Connect)
+	connections.Delete("/:provider", r.authMiddleware.RequireAdmin(), r.handler.Disconnect)
+}
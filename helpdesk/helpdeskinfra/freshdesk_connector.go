@@ -0,0 +1,164 @@
+package helpdeskinfra
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/helpdesk"
+)
+
+// Freshdesk ticket fields
+const (
+	freshdeskStatusOpen   = 2
+	freshdeskStatusClosed = 5
+	freshdeskSourceChat   = 7
+)
+
+// freshdeskPriorities maps relay priorities to Freshdesk's
+var freshdeskPriorities = map[engine.TicketPriority]int{
+	engine.TicketPriorityLow:    1,
+	engine.TicketPriorityNormal: 2, // Medium
+	engine.TicketPriorityHigh:   3,
+	engine.TicketPriorityUrgent: 4,
+}
+
+// FreshdeskConnector writes tickets through the Freshdesk API v2,
+// authenticating with an API key. Comments are private notes and transcripts
+// are attached as files.
+type FreshdeskConnector struct {
+	api apiClient
+}
+
+var _ helpdesk.Connector = (*FreshdeskConnector)(nil)
+
+func NewFreshdeskConnector() *FreshdeskConnector {
+	return &FreshdeskConnector{
+		api: newAPIClient(),
+	}
+}
+
+func (c *FreshdeskConnector) Provider() helpdesk.Provider {
+	return helpdesk.ProviderFreshdesk
+}
+
+func (c *FreshdeskConnector) Verify(ctx context.Context, connection helpdesk.Connection) error {
+	return c.api.doJSON(ctx, http.MethodGet, c.url(connection, "/agents/me"), c.authorization(connection), nil, nil)
+}
+
+// ============================================================================
+// Tickets
+// ============================================================================
+
+type freshdeskTicket struct {
+	ID     int64 `json:"id"`
+	Status int   `json:"status"`
+}
+
+func (c *FreshdeskConnector) Create(ctx context.Context, connection helpdesk.Connection, ticket helpdesk.Ticket) (string, error) {
+	fields := []formField{
+		{"subject", ticket.Subject},
+		{"description", toHTML(ticket.Body)},
+		{"status", strconv.Itoa(freshdeskStatusOpen)},
+		{"source", strconv.Itoa(freshdeskSourceChat)},
+	}
+	if priority, ok := freshdeskPriorities[ticket.Priority]; ok {
+		fields = append(fields, formField{"priority", strconv.Itoa(priority)})
+	}
+	for _, tag := range ticket.Tags {
+		fields = append(fields, formField{"tags[]", tag})
+	}
+	fields = append(fields, c.requester(ticket.Requester)...)
+
+	var created freshdeskTicket
+	if err := c.post(ctx, connection, "/tickets", fields, ticket, &created); err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(created.ID, 10), nil
+}
+
+func (c *FreshdeskConnector) Append(ctx context.Context, connection helpdesk.Connection, ticketID string, ticket helpdesk.Ticket) error {
+	path := "/tickets/" + url.PathEscape(ticketID)
+
+	var current freshdeskTicket
+	if err := c.api.doJSON(ctx, http.MethodGet, c.url(connection, path), c.authorization(connection), nil, &current); err != nil {
+		if responseStatus(err) == http.StatusNotFound {
+			return helpdesk.ErrTicketClosed().WithDetail("ticket_id", ticketID)
+		}
+		return err
+	}
+	if current.Status == freshdeskStatusClosed {
+		return helpdesk.ErrTicketClosed().WithDetail("ticket_id", ticketID)
+	}
+
+	fields := []formField{
+		{"body", toHTML(ticket.Body)},
+		{"private", "true"},
+	}
+	if err := c.post(ctx, connection, path+"/notes", fields, ticket, nil); err != nil {
+		return err
+	}
+
+	if priority, ok := freshdeskPriorities[ticket.Priority]; ok {
+		update := map[string]any{"priority": priority}
+		return c.api.doJSON(ctx, http.MethodPut, c.url(connection, path), c.authorization(connection), update, nil)
+	}
+	return nil
+}
+
+func (c *FreshdeskConnector) TicketURL(connection helpdesk.Connection, ticketID string) string {
+	return fmt.Sprintf("https://%s/a/tickets/%s", connection.Host(), url.PathEscape(ticketID))
+}
+
+// post sends fields as a multipart form with the ticket's transcript attached
+func (c *FreshdeskConnector) post(ctx context.Context, connection helpdesk.Connection, path string, fields []formField, ticket helpdesk.Ticket, out any) error {
+	var files []formFile
+	if ticket.Transcript != nil {
+		files = append(files, formFile{
+			field:       "attachments[]",
+			filename:    ticket.TranscriptName,
+			contentType: "text/plain",
+			content:     ticket.Transcript,
+		})
+	}
+
+	contentType, body, err := multipartForm(fields, files...)
+	if err != nil {
+		return err
+	}
+	return c.api.do(ctx, http.MethodPost, c.url(connection, path), c.authorization(connection), contentType, body, out)
+}
+
+// requester identifies the contact by email, then phone, then the
+// conversation ID; Freshdesk creates the contact when it does not exist
+func (c *FreshdeskConnector) requester(requester helpdesk.Requester) []formField {
+	fields := []formField{{"name", requester.Name}}
+	switch {
+	case requester.Email != "":
+		return append(fields, formField{"email", requester.Email})
+	case requester.Phone != "":
+		return append(fields, formField{"phone", requester.Phone})
+	default:
+		return append(fields, formField{"unique_external_id", requester.ExternalID})
+	}
+}
+
+func (c *FreshdeskConnector) url(connection helpdesk.Connection, path string) string {
+	return "https://" + connection.Host() + "/api/v2" + path
+}
+
+func (c *FreshdeskConnector) authorization(connection helpdesk.Connection) string {
+	// Freshdesk ignores the password of API key authentication
+	return basicAuth(connection.Credentials.APIToken, "X")
+}
+
+// toHTML escapes plain text for Freshdesk's HTML descriptions and notes
+func toHTML(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
@@ -0,0 +1,166 @@
+package helpdeskinfra
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/helpdesk"
+)
+
+// requestTimeout bounds every call to a helpdesk; uploads included
+const requestTimeout = 30 * time.Second
+
+// maxResponseBytes bounds what is read from a helpdesk response
+const maxResponseBytes = 1 << 20
+
+// apiClient sends requests to a helpdesk's REST API
+type apiClient struct {
+	http *http.Client
+}
+
+func newAPIClient() apiClient {
+	return apiClient{http: &http.Client{Timeout: requestTimeout}}
+}
+
+// doJSON sends body as JSON and decodes the response into out when it is
+// not nil
+func (c apiClient) doJSON(ctx context.Context, method, endpoint, authorization string, body, out any) error {
+	var data []byte
+	contentType := ""
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+		contentType = "application/json"
+	}
+	return c.do(ctx, method, endpoint, authorization, contentType, data, out)
+}
+
+// do sends body with its content type. A 401 or 403 fails with
+// helpdesk.ErrCredentialsRejected, other failures with
+// helpdesk.ErrRequestFailed carrying the status.
+func (c apiClient) do(ctx context.Context, method, endpoint, authorization, contentType string, body []byte, out any) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return helpdesk.ErrRequestFailed().WithDetail("reason", err.Error())
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return helpdesk.ErrRequestFailed().WithDetail("reason", err.Error())
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return helpdesk.ErrCredentialsRejected().WithDetail("status", resp.StatusCode)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return helpdesk.ErrRequestFailed().
+			WithDetail("status", resp.StatusCode).
+			WithDetail("response", truncate(string(data), 500))
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return helpdesk.ErrRequestFailed().WithDetail("reason", fmt.Sprintf("invalid response: %v", err))
+	}
+	return nil
+}
+
+// responseStatus is the HTTP status a failed request answered with, 0 when
+// it got no answer
+func responseStatus(err error) int {
+	var e *errx.Error
+	if errors.As(err, &e) {
+		status, _ := e.Details["status"].(int)
+		return status
+	}
+	return 0
+}
+
+func basicAuth(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+// ============================================================================
+// Multipart Forms
+// ============================================================================
+
+// formField is a text field of a multipart form; repeated names are allowed
+type formField struct {
+	name  string
+	value string
+}
+
+// formFile is a file of a multipart form
+type formFile struct {
+	field       string
+	filename    string
+	contentType string
+	content     []byte
+}
+
+// multipartForm encodes fields and files, returning the content type with
+// its boundary and the body
+func multipartForm(fields []formField, files ...formFile) (string, []byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for _, field := range fields {
+		if err := writer.WriteField(field.name, field.value); err != nil {
+			return "", nil, err
+		}
+	}
+	for _, file := range files {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, file.field, file.filename))
+		header.Set("Content-Type", file.contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := part.Write(file.content); err != nil {
+			return "", nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", nil, err
+	}
+
+	return writer.FormDataContentType(), buf.Bytes(), nil
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
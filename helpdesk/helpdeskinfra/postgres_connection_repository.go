@@ -0,0 +1,143 @@
+package helpdeskinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/encryption"
+	"github.com/Abraxas-365/relay/helpdesk"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresConnectionRepository is the PostgreSQL implementation of
+// helpdesk.ConnectionRepository. Credentials are sealed with the tenant's
+// data key when the tenant has encryption enabled.
+type PostgresConnectionRepository struct {
+	db     *sqlx.DB
+	cipher *encryption.FieldCipher
+}
+
+var _ helpdesk.ConnectionRepository = (*PostgresConnectionRepository)(nil)
+
+func NewPostgresConnectionRepository(db *sqlx.DB, cipher *encryption.FieldCipher) *PostgresConnectionRepository {
+	return &PostgresConnectionRepository{db: db, cipher: cipher}
+}
+
+const connectionColumns = `tenant_id, provider, domain, credentials, connected_at, updated_at`
+
+type connectionRow struct {
+	TenantID    string    `db:"tenant_id"`
+	Provider    string    `db:"provider"`
+	Domain      string    `db:"domain"`
+	Credentials []byte    `db:"credentials"`
+	ConnectedAt time.Time `db:"connected_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (r *PostgresConnectionRepository) Find(ctx context.Context, tenantID kernel.TenantID, provider helpdesk.Provider) (*helpdesk.Connection, error) {
+	query := `SELECT ` + connectionColumns + ` FROM helpdesk_connections WHERE tenant_id = $1 AND provider = $2`
+
+	var row connectionRow
+	if err := r.db.GetContext(ctx, &row, query, tenantID.String(), string(provider)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helpdesk.ErrConnectionNotFound().WithDetail("provider", string(provider))
+		}
+		return nil, errx.Wrap(err, "failed to find helpdesk connection", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	return r.toConnection(ctx, row)
+}
+
+func (r *PostgresConnectionRepository) List(ctx context.Context, tenantID kernel.TenantID) ([]helpdesk.Connection, error) {
+	query := `SELECT ` + connectionColumns + ` FROM helpdesk_connections WHERE tenant_id = $1 ORDER BY provider`
+
+	var rows []connectionRow
+	if err := r.db.SelectContext(ctx, &rows, query, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to list helpdesk connections", errx.TypeInternal)
+	}
+
+	connections := make([]helpdesk.Connection, 0, len(rows))
+	for _, row := range rows {
+		connection, err := r.toConnection(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		connections = append(connections, *connection)
+	}
+
+	return connections, nil
+}
+
+func (r *PostgresConnectionRepository) Save(ctx context.Context, connection helpdesk.Connection) error {
+	credentials, err := json.Marshal(connection.Credentials)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal helpdesk credentials", errx.TypeInternal)
+	}
+	credentials, err = r.cipher.EncryptJSON(ctx, connection.TenantID, credentials)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO helpdesk_connections (` + connectionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (tenant_id, provider) DO UPDATE SET
+			domain = EXCLUDED.domain,
+			credentials = EXCLUDED.credentials,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.ExecContext(ctx, query,
+		connection.TenantID.String(),
+		string(connection.Provider),
+		connection.Domain,
+		credentials,
+		connection.ConnectedAt,
+		connection.UpdatedAt,
+	); err != nil {
+		return errx.Wrap(err, "failed to save helpdesk connection", errx.TypeInternal).
+			WithDetail("tenant_id", connection.TenantID.String()).
+			WithDetail("provider", string(connection.Provider))
+	}
+
+	return nil
+}
+
+func (r *PostgresConnectionRepository) Delete(ctx context.Context, tenantID kernel.TenantID, provider helpdesk.Provider) error {
+	query := `DELETE FROM helpdesk_connections WHERE tenant_id = $1 AND provider = $2`
+
+	result, err := r.db.ExecContext(ctx, query, tenantID.String(), string(provider))
+	if err != nil {
+		return errx.Wrap(err, "failed to delete helpdesk connection", errx.TypeInternal)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return helpdesk.ErrConnectionNotFound().WithDetail("provider", string(provider))
+	}
+
+	return nil
+}
+
+func (r *PostgresConnectionRepository) toConnection(ctx context.Context, row connectionRow) (*helpdesk.Connection, error) {
+	tenantID := kernel.TenantID(row.TenantID)
+	connection := &helpdesk.Connection{
+		TenantID:    tenantID,
+		Provider:    helpdesk.Provider(row.Provider),
+		Domain:      row.Domain,
+		ConnectedAt: row.ConnectedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+
+	credentials, err := r.cipher.DecryptJSON(ctx, tenantID, row.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(credentials, &connection.Credentials); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal helpdesk credentials", errx.TypeInternal)
+	}
+
+	return connection, nil
+}
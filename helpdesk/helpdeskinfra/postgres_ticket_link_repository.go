@@ -0,0 +1,97 @@
+package helpdeskinfra
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/helpdesk"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresTicketLinkRepository is the PostgreSQL implementation of
+// helpdesk.TicketLinkRepository
+type PostgresTicketLinkRepository struct {
+	db *sqlx.DB
+}
+
+var _ helpdesk.TicketLinkRepository = (*PostgresTicketLinkRepository)(nil)
+
+func NewPostgresTicketLinkRepository(db *sqlx.DB) *PostgresTicketLinkRepository {
+	return &PostgresTicketLinkRepository{db: db}
+}
+
+const ticketLinkColumns = `tenant_id, channel_id, conversation_id, provider, ticket_id, priority, synced_until, created_at, updated_at`
+
+type ticketLinkRow struct {
+	TenantID       string    `db:"tenant_id"`
+	ChannelID      string    `db:"channel_id"`
+	ConversationID string    `db:"conversation_id"`
+	Provider       string    `db:"provider"`
+	TicketID       string    `db:"ticket_id"`
+	Priority       string    `db:"priority"`
+	SyncedUntil    time.Time `db:"synced_until"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+func (r *PostgresTicketLinkRepository) Find(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string, provider helpdesk.Provider) (*helpdesk.TicketLink, error) {
+	query := `
+		SELECT ` + ticketLinkColumns + `
+		FROM helpdesk_tickets
+		WHERE tenant_id = $1 AND channel_id = $2 AND conversation_id = $3 AND provider = $4`
+
+	var row ticketLinkRow
+	if err := r.db.GetContext(ctx, &row, query, tenantID.String(), channelID.String(), conversationID, string(provider)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helpdesk.ErrLinkNotFound().WithDetail("conversation_id", conversationID)
+		}
+		return nil, errx.Wrap(err, "failed to find conversation ticket", errx.TypeInternal).
+			WithDetail("conversation_id", conversationID)
+	}
+
+	return &helpdesk.TicketLink{
+		TenantID:       kernel.TenantID(row.TenantID),
+		ChannelID:      kernel.ChannelID(row.ChannelID),
+		ConversationID: row.ConversationID,
+		Provider:       helpdesk.Provider(row.Provider),
+		TicketID:       row.TicketID,
+		Priority:       engine.TicketPriority(row.Priority),
+		SyncedUntil:    row.SyncedUntil,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}, nil
+}
+
+func (r *PostgresTicketLinkRepository) Save(ctx context.Context, link helpdesk.TicketLink) error {
+	query := `
+		INSERT INTO helpdesk_tickets (` + ticketLinkColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (tenant_id, channel_id, conversation_id, provider) DO UPDATE SET
+			ticket_id = EXCLUDED.ticket_id,
+			priority = EXCLUDED.priority,
+			synced_until = EXCLUDED.synced_until,
+			created_at = EXCLUDED.created_at,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.ExecContext(ctx, query,
+		link.TenantID.String(),
+		link.ChannelID.String(),
+		link.ConversationID,
+		string(link.Provider),
+		link.TicketID,
+		string(link.Priority),
+		link.SyncedUntil,
+		link.CreatedAt,
+		link.UpdatedAt,
+	); err != nil {
+		return errx.Wrap(err, "failed to save conversation ticket", errx.TypeInternal).
+			WithDetail("conversation_id", link.ConversationID).
+			WithDetail("ticket_id", link.TicketID)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package helpdeskinfra
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Abraxas-365/relay/helpdesk"
+)
+
+// zendeskClosed is the status of a ticket Zendesk no longer lets update
+const zendeskClosed = "closed"
+
+// ZendeskConnector writes tickets through the Zendesk Support API,
+// authenticating as an agent with an API token. Transcripts are uploaded
+// and attached to the ticket's comments.
+type ZendeskConnector struct {
+	api apiClient
+}
+
+var _ helpdesk.Connector = (*ZendeskConnector)(nil)
+
+func NewZendeskConnector() *ZendeskConnector {
+	return &ZendeskConnector{
+		api: newAPIClient(),
+	}
+}
+
+func (c *ZendeskConnector) Provider() helpdesk.Provider {
+	return helpdesk.ProviderZendesk
+}
+
+func (c *ZendeskConnector) Verify(ctx context.Context, connection helpdesk.Connection) error {
+	var me struct {
+		User struct {
+			ID *int64 `json:"id"`
+		} `json:"user"`
+	}
+	if err := c.api.doJSON(ctx, http.MethodGet, c.url(connection, "/users/me.json"), c.authorization(connection), nil, &me); err != nil {
+		return err
+	}
+	// Zendesk answers anonymous requests with a user without ID
+	if me.User.ID == nil {
+		return helpdesk.ErrCredentialsRejected()
+	}
+	return nil
+}
+
+// ============================================================================
+// Tickets
+// ============================================================================
+
+type zendeskTicket struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+func (c *ZendeskConnector) Create(ctx context.Context, connection helpdesk.Connection, ticket helpdesk.Ticket) (string, error) {
+	comment, err := c.comment(ctx, connection, ticket)
+	if err != nil {
+		return "", err
+	}
+
+	fields := map[string]any{
+		"subject": ticket.Subject,
+		"comment": comment,
+	}
+	if ticket.Priority != "" {
+		fields["priority"] = string(ticket.Priority)
+	}
+	if len(ticket.Tags) > 0 {
+		fields["tags"] = ticket.Tags
+	}
+	if requester := c.requester(ticket.Requester); requester != nil {
+		fields["requester"] = requester
+	}
+
+	var created struct {
+		Ticket zendeskTicket `json:"ticket"`
+	}
+	body := map[string]any{"ticket": fields}
+	if err := c.api.doJSON(ctx, http.MethodPost, c.url(connection, "/tickets.json"), c.authorization(connection), body, &created); err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(created.Ticket.ID, 10), nil
+}
+
+func (c *ZendeskConnector) Append(ctx context.Context, connection helpdesk.Connection, ticketID string, ticket helpdesk.Ticket) error {
+	endpoint := c.url(connection, "/tickets/"+url.PathEscape(ticketID)+".json")
+
+	var current struct {
+		Ticket zendeskTicket `json:"ticket"`
+	}
+	if err := c.api.doJSON(ctx, http.MethodGet, endpoint, c.authorization(connection), nil, &current); err != nil {
+		if responseStatus(err) == http.StatusNotFound {
+			return helpdesk.ErrTicketClosed().WithDetail("ticket_id", ticketID)
+		}
+		return err
+	}
+	if current.Ticket.Status == zendeskClosed {
+		return helpdesk.ErrTicketClosed().WithDetail("ticket_id", ticketID)
+	}
+
+	comment, err := c.comment(ctx, connection, ticket)
+	if err != nil {
+		return err
+	}
+	comment["public"] = false
+
+	fields := map[string]any{"comment": comment}
+	if ticket.Priority != "" {
+		fields["priority"] = string(ticket.Priority)
+	}
+	return c.api.doJSON(ctx, http.MethodPut, endpoint, c.authorization(connection), map[string]any{"ticket": fields}, nil)
+}
+
+func (c *ZendeskConnector) TicketURL(connection helpdesk.Connection, ticketID string) string {
+	return fmt.Sprintf("https://%s/agent/tickets/%s", connection.Host(), url.PathEscape(ticketID))
+}
+
+// comment is the ticket's body with the transcript uploaded as an attachment
+func (c *ZendeskConnector) comment(ctx context.Context, connection helpdesk.Connection, ticket helpdesk.Ticket) (map[string]any, error) {
+	comment := map[string]any{"body": ticket.Body}
+	if ticket.Transcript == nil {
+		return comment, nil
+	}
+
+	endpoint := c.url(connection, "/uploads.json?filename="+url.QueryEscape(ticket.TranscriptName))
+	var uploaded struct {
+		Upload struct {
+			Token string `json:"token"`
+		} `json:"upload"`
+	}
+	if err := c.api.do(ctx, http.MethodPost, endpoint, c.authorization(connection), "text/plain", ticket.Transcript, &uploaded); err != nil {
+		return nil, err
+	}
+
+	comment["uploads"] = []string{uploaded.Upload.Token}
+	return comment, nil
+}
+
+// requester identifies the contact by email, creating the user when Zendesk
+// does not know it; without email the ticket is opened by the API agent
+func (c *ZendeskConnector) requester(requester helpdesk.Requester) map[string]any {
+	if requester.Email == "" {
+		return nil
+	}
+	fields := map[string]any{"email": requester.Email}
+	if requester.Name != "" {
+		fields["name"] = requester.Name
+	}
+	return fields
+}
+
+func (c *ZendeskConnector) url(connection helpdesk.Connection, path string) string {
+	return "https://" + connection.Host() + "/api/v2" + path
+}
+
+func (c *ZendeskConnector) authorization(connection helpdesk.Connection) string {
+	return basicAuth(connection.Credentials.Email+"/token", connection.Credentials.APIToken)
+}
@@ -0,0 +1,476 @@
+package helpdesksrv
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/helpdesk"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/tool"
+)
+
+const (
+	// transcriptPageSize is how many messages are read per query
+	transcriptPageSize = 200
+
+	// maxTranscriptMessages bounds a transcript to the latest messages of
+	// very long conversations
+	maxTranscriptMessages = 2000
+)
+
+// unsafeFilenameChars are replaced in the transcript file name
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9_+-]`)
+
+// HelpdeskService connects tenants to their helpdesk and writes the tickets
+// of TICKET nodes and tools. Each conversation keeps one ticket: later
+// writes append the new messages to it until it is closed.
+type HelpdeskService struct {
+	connectionRepo helpdesk.ConnectionRepository
+	linkRepo       helpdesk.TicketLinkRepository
+	messageRepo    conversation.MessageRepository
+	tagRepo        conversation.TagRepository
+	connectors     map[helpdesk.Provider]helpdesk.Connector
+}
+
+var _ engine.TicketSyncer = (*HelpdeskService)(nil)
+
+// NewHelpdeskService creates the service; only the providers with a
+// connector can be connected
+func NewHelpdeskService(
+	connectionRepo helpdesk.ConnectionRepository,
+	linkRepo helpdesk.TicketLinkRepository,
+	messageRepo conversation.MessageRepository,
+	tagRepo conversation.TagRepository,
+	connectors ...helpdesk.Connector,
+) *HelpdeskService {
+	s := &HelpdeskService{
+		connectionRepo: connectionRepo,
+		linkRepo:       linkRepo,
+		messageRepo:    messageRepo,
+		tagRepo:        tagRepo,
+		connectors:     make(map[helpdesk.Provider]helpdesk.Connector, len(connectors)),
+	}
+	for _, connector := range connectors {
+		s.connectors[connector.Provider()] = connector
+	}
+	return s
+}
+
+// ============================================================================
+// Connections
+// ============================================================================
+
+// Available lists the providers configured on this server
+func (s *HelpdeskService) Available() []helpdesk.Provider {
+	providers := make([]helpdesk.Provider, 0, len(s.connectors))
+	for provider := range s.connectors {
+		providers = append(providers, provider)
+	}
+	slices.Sort(providers)
+	return providers
+}
+
+// ListConnections returns the tenant's connections, without credentials
+func (s *HelpdeskService) ListConnections(ctx context.Context, tenantID kernel.TenantID) (*helpdesk.ConnectionsResponse, error) {
+	connections, err := s.connectionRepo.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &helpdesk.ConnectionsResponse{Connections: connections, Available: s.Available()}, nil
+}
+
+// Connect verifies the credentials against the account and saves the
+// connection, replacing the tenant's previous one to the provider
+func (s *HelpdeskService) Connect(ctx context.Context, tenantID kernel.TenantID, provider helpdesk.Provider, req helpdesk.ConnectRequest) (*helpdesk.Connection, error) {
+	connector, err := s.connector(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	domain := helpdesk.NormalizeDomain(req.Domain)
+	switch {
+	case !helpdesk.ValidDomain(domain):
+		return nil, helpdesk.ErrInvalidConnection().WithDetail("field", "domain")
+	case req.APIToken == "":
+		return nil, helpdesk.ErrInvalidConnection().WithDetail("field", "api_token")
+	case provider == helpdesk.ProviderZendesk && req.Email == "":
+		return nil, helpdesk.ErrInvalidConnection().
+			WithDetail("field", "email").
+			WithDetail("reason", "Zendesk API tokens belong to an agent")
+	}
+
+	now := time.Now()
+	connection := &helpdesk.Connection{
+		TenantID: tenantID,
+		Provider: provider,
+		Domain:   domain,
+		Credentials: helpdesk.Credentials{
+			Email:    strings.TrimSpace(req.Email),
+			APIToken: strings.TrimSpace(req.APIToken),
+		},
+		ConnectedAt: now,
+		UpdatedAt:   now,
+	}
+	if existing, err := s.connectionRepo.Find(ctx, tenantID, provider); err == nil {
+		connection.ConnectedAt = existing.ConnectedAt
+	}
+
+	if err := connector.Verify(ctx, *connection); err != nil {
+		return nil, err
+	}
+	if err := s.connectionRepo.Save(ctx, *connection); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✅ Tenant %s connected %s account %s", tenantID, provider, domain)
+	return connection, nil
+}
+
+// Disconnect forgets the tenant's credentials for provider. Conversations
+// keep their tickets, so reconnecting appends to them again.
+func (s *HelpdeskService) Disconnect(ctx context.Context, tenantID kernel.TenantID, provider helpdesk.Provider) error {
+	return s.connectionRepo.Delete(ctx, tenantID, provider)
+}
+
+// ============================================================================
+// Tickets
+// ============================================================================
+
+// SyncTicket implements engine.TicketSyncer. The conversation's ticket gets
+// the messages received since the last write; a new ticket, with the whole
+// transcript, is opened when there is none, it was closed or the request
+// asks for one.
+func (s *HelpdeskService) SyncTicket(ctx context.Context, tenantID kernel.TenantID, req engine.TicketRequest) (*engine.TicketResult, error) {
+	if req.ChannelID == "" || req.ConversationID == "" {
+		return nil, helpdesk.ErrInvalidTicket().WithDetail("reason", "channel_id and conversation_id are required")
+	}
+	if req.Priority != "" && !req.Priority.IsValid() {
+		return nil, helpdesk.ErrInvalidTicket().
+			WithDetail("priority", string(req.Priority)).
+			WithDetail("allowed", engine.TicketPriorities)
+	}
+
+	connection, err := s.resolveConnection(ctx, tenantID, helpdesk.Provider(req.Provider))
+	if err != nil {
+		return nil, err
+	}
+	connector, err := s.connector(connection.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	priority, err := s.priority(ctx, tenantID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.linkRepo.Find(ctx, tenantID, req.ChannelID, req.ConversationID, connection.Provider)
+	switch {
+	case err == nil && !req.NewTicket:
+		result, err := s.append(ctx, connection, connector, link, req, priority)
+		if !errx.IsCode(err, helpdesk.CodeTicketClosed) {
+			return result, err
+		}
+		log.Printf("🎫 %s ticket %s of conversation %s is closed, opening a new one", connection.Provider, link.TicketID, req.ConversationID)
+	case err != nil && !errx.IsCode(err, helpdesk.CodeLinkNotFound):
+		return nil, err
+	}
+
+	return s.create(ctx, connection, connector, req, priority)
+}
+
+// ExecuteTool runs a tool of type tool.ToolTypeTicket: input holds
+// channel_id and conversation_id plus the optional subject, comment,
+// priority, sentiment and requester_* fields
+func (s *HelpdeskService) ExecuteTool(ctx context.Context, t *tool.Tool, input map[string]any) (map[string]any, error) {
+	if t.Type != tool.ToolTypeTicket {
+		return nil, tool.ErrInvalidToolType().WithDetail("type", string(t.Type))
+	}
+	if !t.IsActive {
+		return nil, tool.ErrToolInactive()
+	}
+
+	field := func(name string) string {
+		value, _ := input[name].(string)
+		return value
+	}
+	newTicket, _ := input["new_ticket"].(bool)
+
+	result, err := s.SyncTicket(ctx, t.TenantID, engine.TicketRequest{
+		Provider:       t.Config.TicketProvider,
+		ChannelID:      kernel.ChannelID(field("channel_id")),
+		ConversationID: field("conversation_id"),
+		Subject:        field("subject"),
+		Comment:        field("comment"),
+		Priority:       engine.TicketPriority(field("priority")),
+		Sentiment:      field("sentiment"),
+		RequesterName:  field("requester_name"),
+		RequesterEmail: field("requester_email"),
+		RequesterPhone: field("requester_phone"),
+		NewTicket:      newTicket,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"provider":  result.Provider,
+		"ticket_id": result.TicketID,
+		"url":       result.URL,
+		"created":   result.Created,
+		"priority":  string(result.Priority),
+		"messages":  result.Messages,
+	}, nil
+}
+
+// create opens a ticket with the conversation's transcript and links it to
+// the conversation
+func (s *HelpdeskService) create(ctx context.Context, connection *helpdesk.Connection, connector helpdesk.Connector, req engine.TicketRequest, priority engine.TicketPriority) (*engine.TicketResult, error) {
+	messages, err := s.transcript(ctx, connection.TenantID, req.ChannelID, req.ConversationID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	name := req.RequesterName
+	if name == "" {
+		name = req.ConversationID
+	}
+	subject := req.Subject
+	if subject == "" {
+		subject = "Conversation with " + name
+	}
+
+	ticket := s.ticket(req, messages)
+	ticket.Subject = subject
+	ticket.Priority = priority
+	ticket.Tags = req.Tags
+	ticket.Requester = helpdesk.Requester{
+		Name:       name,
+		Email:      req.RequesterEmail,
+		Phone:      req.RequesterPhone,
+		ExternalID: req.ConversationID,
+	}
+
+	ticketID, err := connector.Create(ctx, *connection, ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	s.saveLink(ctx, helpdesk.TicketLink{
+		TenantID:       connection.TenantID,
+		ChannelID:      req.ChannelID,
+		ConversationID: req.ConversationID,
+		Provider:       connection.Provider,
+		TicketID:       ticketID,
+		Priority:       priority,
+		SyncedUntil:    syncedUntil(messages, time.Time{}),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+
+	log.Printf("🎫 Opened %s ticket %s for conversation %s (%s, %d messages)", connection.Provider, ticketID, req.ConversationID, priority, len(messages))
+	return s.result(connection, connector, ticketID, true, priority, len(messages)), nil
+}
+
+// append writes the messages received since the last write as a private
+// comment, raising the ticket's priority when the rules now ask for more
+func (s *HelpdeskService) append(ctx context.Context, connection *helpdesk.Connection, connector helpdesk.Connector, link *helpdesk.TicketLink, req engine.TicketRequest, priority engine.TicketPriority) (*engine.TicketResult, error) {
+	messages, err := s.transcript(ctx, connection.TenantID, req.ChannelID, req.ConversationID, link.SyncedUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	raise := priority.Rank() > link.Priority.Rank()
+	if len(messages) == 0 && req.Comment == "" && !raise {
+		return s.result(connection, connector, link.TicketID, false, link.Priority, 0), nil
+	}
+
+	ticket := s.ticket(req, messages)
+	if raise {
+		ticket.Priority = priority
+		link.Priority = priority
+	}
+	if err := connector.Append(ctx, *connection, link.TicketID, ticket); err != nil {
+		return nil, err
+	}
+
+	link.SyncedUntil = syncedUntil(messages, link.SyncedUntil)
+	link.UpdatedAt = time.Now()
+	s.saveLink(ctx, *link)
+
+	return s.result(connection, connector, link.TicketID, false, link.Priority, len(messages)), nil
+}
+
+// ticket is the comment and transcript attachment written for messages
+func (s *HelpdeskService) ticket(req engine.TicketRequest, messages []conversation.Message) helpdesk.Ticket {
+	var body []string
+	if req.Comment != "" {
+		body = append(body, req.Comment)
+	}
+	ticket := helpdesk.Ticket{}
+	if len(messages) > 0 {
+		count := fmt.Sprintf("%d messages", len(messages))
+		if len(messages) == 1 {
+			count = "1 message"
+		}
+		body = append(body, fmt.Sprintf("Transcript attached: %s of conversation %s.", count, req.ConversationID))
+		ticket.Transcript = helpdesk.RenderTranscript(req.ConversationID, messages)
+		ticket.TranscriptName = fmt.Sprintf("transcript-%s-%s.txt",
+			unsafeFilenameChars.ReplaceAllString(req.ConversationID, "_"),
+			time.Now().UTC().Format("20060102-150405"))
+	}
+	if len(body) == 0 {
+		body = append(body, fmt.Sprintf("Conversation %s has no messages yet.", req.ConversationID))
+	}
+	ticket.Body = strings.Join(body, "\n\n")
+	return ticket
+}
+
+// priority is the most urgent of the requested priority and the rules the
+// conversation's tags and sentiment match, normal when none applies
+func (s *HelpdeskService) priority(ctx context.Context, tenantID kernel.TenantID, req engine.TicketRequest) (engine.TicketPriority, error) {
+	priority := req.Priority
+	raise := func(candidate engine.TicketPriority) {
+		if candidate.Rank() > priority.Rank() {
+			priority = candidate
+		}
+	}
+
+	if len(req.PriorityByTag) > 0 && s.tagRepo != nil {
+		tags, err := s.tagRepo.ListByConversation(ctx, tenantID, &req.ChannelID, req.ConversationID)
+		if err != nil {
+			return "", err
+		}
+		for rule, candidate := range req.PriorityByTag {
+			name, err := conversation.NormalizeTag(rule)
+			if err != nil {
+				continue
+			}
+			if slices.ContainsFunc(tags, func(tag conversation.Tag) bool { return tag.Name == name }) {
+				raise(candidate)
+			}
+		}
+	}
+
+	if sentiment := strings.TrimSpace(req.Sentiment); sentiment != "" {
+		for label, candidate := range req.PriorityBySentiment {
+			if strings.EqualFold(strings.TrimSpace(label), sentiment) {
+				raise(candidate)
+			}
+		}
+	}
+
+	if priority == "" {
+		return engine.TicketPriorityNormal, nil
+	}
+	return priority, nil
+}
+
+// transcript returns the conversation's messages created after since, in
+// chronological order, reading backwards from the latest so only the last
+// maxTranscriptMessages are kept
+func (s *HelpdeskService) transcript(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string, since time.Time) ([]conversation.Message, error) {
+	req := conversation.ListMessagesRequest{
+		PaginationOptions: storex.PaginationOptions{Page: 1, PageSize: transcriptPageSize},
+		TenantID:          tenantID,
+		ConversationID:    conversationID,
+		ChannelID:         &channelID,
+	}
+	page, err := s.messageRepo.ListByConversation(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var newest []conversation.Message
+	for number := page.Page.Pages; number >= 1; number-- {
+		if number != page.Page.Number {
+			req.Page = number
+			if page, err = s.messageRepo.ListByConversation(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+		for i := len(page.Data) - 1; i >= 0; i-- {
+			if !page.Data[i].CreatedAt.After(since) || len(newest) == maxTranscriptMessages {
+				slices.Reverse(newest)
+				return newest, nil
+			}
+			newest = append(newest, page.Data[i])
+		}
+	}
+
+	slices.Reverse(newest)
+	return newest, nil
+}
+
+// saveLink remembers the conversation's ticket. The ticket was written
+// either way, so a failure is only logged.
+func (s *HelpdeskService) saveLink(ctx context.Context, link helpdesk.TicketLink) {
+	if err := s.linkRepo.Save(context.WithoutCancel(ctx), link); err != nil {
+		log.Printf("⚠️  Failed to link %s ticket %s to conversation %s: %v", link.Provider, link.TicketID, link.ConversationID, err)
+	}
+}
+
+func (s *HelpdeskService) result(connection *helpdesk.Connection, connector helpdesk.Connector, ticketID string, created bool, priority engine.TicketPriority, messages int) *engine.TicketResult {
+	return &engine.TicketResult{
+		Provider: string(connection.Provider),
+		TicketID: ticketID,
+		URL:      connector.TicketURL(*connection, ticketID),
+		Created:  created,
+		Priority: priority,
+		Messages: messages,
+	}
+}
+
+// syncedUntil is the creation time of the last message, previous when there
+// are none
+func syncedUntil(messages []conversation.Message, previous time.Time) time.Time {
+	if len(messages) == 0 {
+		return previous
+	}
+	return messages[len(messages)-1].CreatedAt
+}
+
+// resolveConnection finds the connection to provider, or the tenant's only
+// connection when provider is empty
+func (s *HelpdeskService) resolveConnection(ctx context.Context, tenantID kernel.TenantID, provider helpdesk.Provider) (*helpdesk.Connection, error) {
+	if provider != "" {
+		if !provider.IsValid() {
+			return nil, helpdesk.ErrInvalidProvider().WithDetail("provider", string(provider))
+		}
+		return s.connectionRepo.Find(ctx, tenantID, provider)
+	}
+
+	connections, err := s.connectionRepo.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	switch len(connections) {
+	case 0:
+		return nil, helpdesk.ErrConnectionNotFound()
+	case 1:
+		return &connections[0], nil
+	default:
+		return nil, helpdesk.ErrAmbiguousConnection()
+	}
+}
+
+func (s *HelpdeskService) connector(provider helpdesk.Provider) (helpdesk.Connector, error) {
+	if !provider.IsValid() {
+		return nil, helpdesk.ErrInvalidProvider().WithDetail("provider", string(provider))
+	}
+	connector, ok := s.connectors[provider]
+	if !ok {
+		return nil, helpdesk.ErrProviderNotConfigured().WithDetail("provider", string(provider))
+	}
+	return connector, nil
+}
@@ -0,0 +1,59 @@
+package helpdesk
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// ConnectionRepository persists tenants' helpdesk connections
+type ConnectionRepository interface {
+	// Find returns the tenant's connection to provider, ErrConnectionNotFound
+	// when there is none
+	Find(ctx context.Context, tenantID kernel.TenantID, provider Provider) (*Connection, error)
+
+	List(ctx context.Context, tenantID kernel.TenantID) ([]Connection, error)
+
+	// Save creates or replaces the tenant's connection to the provider
+	Save(ctx context.Context, connection Connection) error
+
+	Delete(ctx context.Context, tenantID kernel.TenantID, provider Provider) error
+}
+
+// TicketLinkRepository remembers the ticket of each conversation
+type TicketLinkRepository interface {
+	// Find returns the conversation's ticket in provider, ErrLinkNotFound
+	// when there is none
+	Find(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string, provider Provider) (*TicketLink, error)
+
+	// Save creates or replaces the conversation's ticket in the provider
+	Save(ctx context.Context, link TicketLink) error
+}
+
+// ============================================================================
+// Connector Interfaces
+// ============================================================================
+
+// Connector talks to one helpdesk's REST API. Calls fail with
+// ErrCredentialsRejected when the credentials are no longer valid.
+type Connector interface {
+	Provider() Provider
+
+	// Verify checks the connection's credentials against the account
+	Verify(ctx context.Context, connection Connection) error
+
+	// Create opens a ticket and returns its ID
+	Create(ctx context.Context, connection Connection, ticket Ticket) (string, error)
+
+	// Append adds the ticket's body and transcript as a private comment,
+	// raising the priority when set. It fails with ErrTicketClosed when the
+	// ticket can no longer be updated.
+	Append(ctx context.Context, connection Connection, ticketID string, ticket Ticket) error
+
+	// TicketURL is where agents open the ticket
+	TicketURL(connection Connection, ticketID string) string
+}
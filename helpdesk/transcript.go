@@ -0,0 +1,72 @@
+package helpdesk
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Abraxas-365/relay/conversation"
+)
+
+// ============================================================================
+// Transcript
+// ============================================================================
+
+// transcriptTimeFormat is the timestamp of each transcript line, in UTC
+const transcriptTimeFormat = "2006-01-02 15:04:05"
+
+// RenderTranscript writes messages as plain text, one per line, for agents
+// to read in the helpdesk
+func RenderTranscript(conversationID string, messages []conversation.Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Conversation %s\n\n", conversationID)
+
+	for _, msg := range messages {
+		fmt.Fprintf(&buf, "[%s UTC] %s: %s\n",
+			msg.CreatedAt.UTC().Format(transcriptTimeFormat),
+			speaker(msg.Origin),
+			messageText(msg),
+		)
+	}
+	return buf.Bytes()
+}
+
+func speaker(origin conversation.Origin) string {
+	switch origin {
+	case conversation.OriginContact:
+		return "Contact"
+	case conversation.OriginWorkflow:
+		return "Bot"
+	case conversation.OriginManual:
+		return "Agent"
+	case conversation.OriginSequence:
+		return "Sequence"
+	default:
+		return "System"
+	}
+}
+
+// messageText is the message's text, or a description of what was sent when
+// it has none, followed by its media URLs
+func messageText(msg conversation.Message) string {
+	text := msg.Content.Text
+	if text == "" {
+		text = msg.Content.Caption
+	}
+	if text == "" {
+		switch {
+		case msg.Content.Location != nil:
+			text = fmt.Sprintf("[location %f,%f]", msg.Content.Location.Latitude, msg.Content.Location.Longitude)
+		case msg.Content.Postback != nil:
+			text = fmt.Sprintf("[selected %s]", msg.Content.Postback.Title)
+		default:
+			text = fmt.Sprintf("[%s]", msg.Content.Type)
+		}
+	}
+	text = strings.ReplaceAll(text, "\n", "\n    ")
+
+	if urls := msg.AttachmentURLs(); len(urls) > 0 {
+		text += " " + strings.Join(urls, " ")
+	}
+	return text
+}
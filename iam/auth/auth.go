@@ -2,6 +2,7 @@ package auth
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Abraxas-365/craftable/errx"
@@ -21,6 +22,8 @@ type RefreshToken struct {
 	ExpiresAt time.Time       `db:"expires_at" json:"expires_at"`
 	CreatedAt time.Time       `db:"created_at" json:"created_at"`
 	IsRevoked bool            `db:"is_revoked" json:"is_revoked"`
+	AMR       string          `db:"amr" json:"amr"` // Métodos de autenticación separados por coma
+	SessionID string          `db:"session_id" json:"session_id,omitempty"`
 }
 
 // UserSession representa una sesión de usuario
@@ -38,12 +41,24 @@ type UserSession struct {
 
 // PasswordResetToken representa un token para resetear contraseña
 type PasswordResetToken struct {
-	ID        string        `db:"id" json:"id"`
-	Token     string        `db:"token" json:"token"`
-	UserID    kernel.UserID `db:"user_id" json:"user_id"`
-	ExpiresAt time.Time     `db:"expires_at" json:"expires_at"`
-	CreatedAt time.Time     `db:"created_at" json:"created_at"`
-	IsUsed    bool          `db:"is_used" json:"is_used"`
+	ID        string          `db:"id" json:"id"`
+	Token     string          `db:"token" json:"token"`
+	UserID    kernel.UserID   `db:"user_id" json:"user_id"`
+	TenantID  kernel.TenantID `db:"tenant_id" json:"tenant_id"`
+	ExpiresAt time.Time       `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	IsUsed    bool            `db:"is_used" json:"is_used"`
+}
+
+// EmailVerificationToken representa un token para verificar el email
+type EmailVerificationToken struct {
+	ID        string          `db:"id" json:"id"`
+	Token     string          `db:"token" json:"token"`
+	UserID    kernel.UserID   `db:"user_id" json:"user_id"`
+	TenantID  kernel.TenantID `db:"tenant_id" json:"tenant_id"`
+	ExpiresAt time.Time       `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	IsUsed    bool            `db:"is_used" json:"is_used"`
 }
 
 // TokenClaims representa los claims de un JWT
@@ -53,6 +68,8 @@ type TokenClaims struct {
 	Email     string          `json:"email"`
 	Name      string          `json:"name"`
 	IsAdmin   bool            `json:"is_admin"`
+	AMR       []string        `json:"amr,omitempty"`
+	SessionID string          `json:"sid,omitempty"`
 	IssuedAt  time.Time       `json:"iat"`
 	ExpiresAt time.Time       `json:"exp"`
 }
@@ -71,6 +88,14 @@ func (r *RefreshToken) IsValid() bool {
 	return !r.IsRevoked && !r.IsExpired()
 }
 
+// Methods retorna los métodos de autenticación de la sesión original
+func (r *RefreshToken) Methods() []string {
+	if r.AMR == "" {
+		return nil
+	}
+	return strings.Split(r.AMR, ",")
+}
+
 // IsExpired verifica si la sesión ha expirado
 func (s *UserSession) IsExpired() bool {
 	return time.Now().After(s.ExpiresAt)
@@ -96,6 +121,11 @@ func (p *PasswordResetToken) MarkAsUsed() {
 	p.IsUsed = true
 }
 
+// IsValid verifica si el token de verificación es válido
+func (e *EmailVerificationToken) IsValid() bool {
+	return !e.IsUsed && time.Now().Before(e.ExpiresAt)
+}
+
 // ============================================================================
 // Error Registry - Errores específicos de Auth
 // ============================================================================
@@ -112,6 +142,19 @@ var (
 	CodeTokenGenerationFailed    = ErrRegistry.Register("TOKEN_GENERATION_FAILED", errx.TypeInternal, http.StatusInternalServerError, "Error al generar token")
 	CodeTokenValidationFailed    = ErrRegistry.Register("TOKEN_VALIDATION_FAILED", errx.TypeAuthorization, http.StatusUnauthorized, "Error al validar token")
 	CodeOAuthCallbackError       = ErrRegistry.Register("OAUTH_CALLBACK_ERROR", errx.TypeExternal, http.StatusBadRequest, "Error en el callback OAuth")
+
+	// Email/contraseña
+	CodeInvalidCredentials       = ErrRegistry.Register("INVALID_CREDENTIALS", errx.TypeAuthorization, http.StatusUnauthorized, "Email o contraseña incorrectos")
+	CodeAccountLocked            = ErrRegistry.Register("ACCOUNT_LOCKED", errx.TypeBusiness, http.StatusLocked, "Cuenta bloqueada temporalmente")
+	CodeTooManyLoginAttempts     = ErrRegistry.Register("TOO_MANY_LOGIN_ATTEMPTS", errx.TypeBusiness, http.StatusTooManyRequests, "Demasiados intentos de login")
+	CodeWeakPassword             = ErrRegistry.Register("WEAK_PASSWORD", errx.TypeValidation, http.StatusBadRequest, "La contraseña no cumple los requisitos")
+	CodeInvalidResetToken        = ErrRegistry.Register("INVALID_RESET_TOKEN", errx.TypeValidation, http.StatusBadRequest, "Token de reset inválido o expirado")
+	CodeInvalidVerificationToken = ErrRegistry.Register("INVALID_VERIFICATION_TOKEN", errx.TypeValidation, http.StatusBadRequest, "Token de verificación inválido o expirado")
+	CodeSignupDisabled           = ErrRegistry.Register("SIGNUP_DISABLED", errx.TypeAuthorization, http.StatusForbidden, "La empresa no admite el registro con contraseña")
+
+	// Sesiones
+	CodeSessionRevoked  = ErrRegistry.Register("SESSION_REVOKED", errx.TypeAuthorization, http.StatusUnauthorized, "La sesión fue revocada o expiró")
+	CodeSessionNotFound = ErrRegistry.Register("SESSION_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Sesión no encontrada")
 )
 
 // Helper functions para crear errores
@@ -146,3 +189,39 @@ func ErrTokenValidationFailed() *errx.Error {
 func ErrOAuthCallbackError() *errx.Error {
 	return ErrRegistry.New(CodeOAuthCallbackError)
 }
+
+func ErrInvalidCredentials() *errx.Error {
+	return ErrRegistry.New(CodeInvalidCredentials)
+}
+
+func ErrAccountLocked() *errx.Error {
+	return ErrRegistry.New(CodeAccountLocked)
+}
+
+func ErrTooManyLoginAttempts() *errx.Error {
+	return ErrRegistry.New(CodeTooManyLoginAttempts)
+}
+
+func ErrWeakPassword() *errx.Error {
+	return ErrRegistry.New(CodeWeakPassword)
+}
+
+func ErrInvalidResetToken() *errx.Error {
+	return ErrRegistry.New(CodeInvalidResetToken)
+}
+
+func ErrInvalidVerificationToken() *errx.Error {
+	return ErrRegistry.New(CodeInvalidVerificationToken)
+}
+
+func ErrSignupDisabled() *errx.Error {
+	return ErrRegistry.New(CodeSignupDisabled)
+}
+
+func ErrSessionRevoked() *errx.Error {
+	return ErrRegistry.New(CodeSessionRevoked)
+}
+
+func ErrSessionNotFound() *errx.Error {
+	return ErrRegistry.New(CodeSessionNotFound)
+}
@@ -0,0 +1,32 @@
+package authinfra
+
+import (
+	"crypto/cipher"
+
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/aesgcm"
+)
+
+// AESSecretCipher cifra secretos con AES-256-GCM; el nonce va prefijado
+type AESSecretCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAESSecretCipher crea el cifrador derivando la clave de 32 bytes con SHA-256
+func NewAESSecretCipher(key string) auth.SecretCipher {
+	return &AESSecretCipher{aead: aesgcm.NewFromSecret(key)}
+}
+
+// Encrypt cifra y codifica en base64
+func (c *AESSecretCipher) Encrypt(plaintext string) (string, error) {
+	return aesgcm.SealBase64(c.aead, []byte(plaintext), nil)
+}
+
+// Decrypt decodifica y descifra un valor generado por Encrypt
+func (c *AESSecretCipher) Decrypt(ciphertext string) (string, error) {
+	plaintext, err := aesgcm.OpenBase64(c.aead, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
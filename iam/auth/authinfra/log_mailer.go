@@ -0,0 +1,36 @@
+package authinfra
+
+import (
+	"context"
+	"log"
+	"net/url"
+
+	"github.com/Abraxas-365/relay/iam/auth"
+)
+
+// LogMailer implementación de AuthMailer que escribe los enlaces en el log.
+// Útil en desarrollo hasta configurar un proveedor de email real.
+type LogMailer struct {
+	baseURL string
+}
+
+// NewLogMailer crea un nuevo mailer de log
+func NewLogMailer(baseURL string) auth.AuthMailer {
+	return &LogMailer{
+		baseURL: baseURL,
+	}
+}
+
+// SendVerificationEmail registra el enlace de verificación de email
+func (m *LogMailer) SendVerificationEmail(ctx context.Context, email, name, token string) error {
+	log.Printf("📧 Verification email for %s <%s>: %s/auth/verify-email?token=%s",
+		name, email, m.baseURL, url.QueryEscape(token))
+	return nil
+}
+
+// SendPasswordResetEmail registra el enlace de reset de contraseña
+func (m *LogMailer) SendPasswordResetEmail(ctx context.Context, email, name, token string) error {
+	log.Printf("📧 Password reset email for %s <%s>: %s/auth/reset-password?token=%s",
+		name, email, m.baseURL, url.QueryEscape(token))
+	return nil
+}
@@ -0,0 +1,84 @@
+package authinfra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresEmailVerificationRepository implementación de PostgreSQL para EmailVerificationRepository
+type PostgresEmailVerificationRepository struct {
+	db *sqlx.DB
+}
+
+// NewPostgresEmailVerificationRepository crea una nueva instancia del repositorio de verificación de email
+func NewPostgresEmailVerificationRepository(db *sqlx.DB) auth.EmailVerificationRepository {
+	return &PostgresEmailVerificationRepository{
+		db: db,
+	}
+}
+
+// SaveVerificationToken guarda un token de verificación de email
+func (r *PostgresEmailVerificationRepository) SaveVerificationToken(ctx context.Context, token auth.EmailVerificationToken) error {
+	query := `
+		INSERT INTO email_verification_tokens (
+			id, token, user_id, tenant_id, expires_at, created_at, is_used
+		) VALUES (
+			:id, :token, :user_id, :tenant_id, :expires_at, :created_at, :is_used
+		)`
+
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		return errx.Wrap(err, "failed to save verification token", errx.TypeInternal).
+			WithDetail("user_id", token.UserID.String())
+	}
+
+	return nil
+}
+
+// FindVerificationToken busca un token de verificación vigente por su valor
+func (r *PostgresEmailVerificationRepository) FindVerificationToken(ctx context.Context, tokenValue string) (*auth.EmailVerificationToken, error) {
+	query := `
+		SELECT 
+			id, token, user_id, tenant_id, expires_at, created_at, is_used
+		FROM email_verification_tokens 
+		WHERE token = $1 AND is_used = false AND expires_at > NOW()`
+
+	var token auth.EmailVerificationToken
+	err := r.db.GetContext(ctx, &token, query, tokenValue)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errx.New("verification token not found or invalid", errx.TypeNotFound)
+		}
+		return nil, errx.Wrap(err, "failed to find verification token", errx.TypeInternal)
+	}
+
+	return &token, nil
+}
+
+// ConsumeVerificationToken marca un token como usado
+func (r *PostgresEmailVerificationRepository) ConsumeVerificationToken(ctx context.Context, tokenValue string) error {
+	query := `
+		UPDATE email_verification_tokens 
+		SET is_used = true 
+		WHERE token = $1 AND is_used = false AND expires_at > NOW()`
+
+	result, err := r.db.ExecContext(ctx, query, tokenValue)
+	if err != nil {
+		return errx.Wrap(err, "failed to consume verification token", errx.TypeInternal)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+
+	if rowsAffected == 0 {
+		return errx.New("verification token not found or already used", errx.TypeNotFound)
+	}
+
+	return nil
+}
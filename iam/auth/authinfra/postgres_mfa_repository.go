@@ -0,0 +1,106 @@
+package authinfra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// PostgresMFARepository implementación de PostgreSQL para MFARepository
+type PostgresMFARepository struct {
+	db *sqlx.DB
+}
+
+// NewPostgresMFARepository crea una nueva instancia del repositorio de 2FA
+func NewPostgresMFARepository(db *sqlx.DB) auth.MFARepository {
+	return &PostgresMFARepository{
+		db: db,
+	}
+}
+
+// dbUserMFA fila con los códigos de respaldo como array de Postgres
+type dbUserMFA struct {
+	auth.UserMFA
+	BackupCodesArray pq.StringArray `db:"backup_codes"`
+}
+
+// FindByUser busca la configuración 2FA de un usuario
+func (r *PostgresMFARepository) FindByUser(ctx context.Context, userID kernel.UserID) (*auth.UserMFA, error) {
+	query := `
+		SELECT 
+			user_id, secret_encrypted, enabled, backup_codes, last_used_step,
+			confirmed_at, created_at, updated_at
+		FROM user_mfa 
+		WHERE user_id = $1`
+
+	var row dbUserMFA
+	err := r.db.GetContext(ctx, &row, query, userID.String())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, auth.ErrMFANotEnrolled().WithDetail("user_id", userID.String())
+		}
+		return nil, errx.Wrap(err, "failed to find user mfa", errx.TypeInternal).
+			WithDetail("user_id", userID.String())
+	}
+
+	mfa := row.UserMFA
+	mfa.BackupCodes = []string(row.BackupCodesArray)
+	return &mfa, nil
+}
+
+// Save crea o actualiza la configuración 2FA de un usuario
+func (r *PostgresMFARepository) Save(ctx context.Context, mfa auth.UserMFA) error {
+	backupCodes := mfa.BackupCodes
+	if backupCodes == nil {
+		backupCodes = []string{}
+	}
+
+	query := `
+		INSERT INTO user_mfa (
+			user_id, secret_encrypted, enabled, backup_codes, last_used_step,
+			confirmed_at, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+		ON CONFLICT (user_id) DO UPDATE SET
+			secret_encrypted = EXCLUDED.secret_encrypted,
+			enabled = EXCLUDED.enabled,
+			backup_codes = EXCLUDED.backup_codes,
+			last_used_step = EXCLUDED.last_used_step,
+			confirmed_at = EXCLUDED.confirmed_at,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.ExecContext(ctx, query,
+		mfa.UserID.String(),
+		mfa.SecretEncrypted,
+		mfa.Enabled,
+		pq.Array(backupCodes),
+		mfa.LastUsedStep,
+		mfa.ConfirmedAt,
+		mfa.CreatedAt,
+		mfa.UpdatedAt,
+	)
+	if err != nil {
+		return errx.Wrap(err, "failed to save user mfa", errx.TypeInternal).
+			WithDetail("user_id", mfa.UserID.String())
+	}
+
+	return nil
+}
+
+// Delete elimina la configuración 2FA de un usuario
+func (r *PostgresMFARepository) Delete(ctx context.Context, userID kernel.UserID) error {
+	query := `DELETE FROM user_mfa WHERE user_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, userID.String()); err != nil {
+		return errx.Wrap(err, "failed to delete user mfa", errx.TypeInternal).
+			WithDetail("user_id", userID.String())
+	}
+
+	return nil
+}
@@ -25,9 +25,9 @@ func NewPostgresPasswordResetRepository(db *sqlx.DB) auth.PasswordResetRepositor
 func (r *PostgresPasswordResetRepository) SaveResetToken(ctx context.Context, token auth.PasswordResetToken) error {
 	query := `
 		INSERT INTO password_reset_tokens (
-			id, token, user_id, expires_at, created_at, is_used
+			id, token, user_id, tenant_id, expires_at, created_at, is_used
 		) VALUES (
-			:id, :token, :user_id, :expires_at, :created_at, :is_used
+			:id, :token, :user_id, :tenant_id, :expires_at, :created_at, :is_used
 		)`
 
 	_, err := r.db.NamedExecContext(ctx, query, token)
@@ -43,7 +43,7 @@ func (r *PostgresPasswordResetRepository) SaveResetToken(ctx context.Context, to
 func (r *PostgresPasswordResetRepository) FindResetToken(ctx context.Context, tokenValue string) (*auth.PasswordResetToken, error) {
 	query := `
 		SELECT 
-			id, token, user_id, expires_at, created_at, is_used
+			id, token, user_id, tenant_id, expires_at, created_at, is_used
 		FROM password_reset_tokens 
 		WHERE token = $1 AND is_used = false AND expires_at > NOW()`
 
@@ -56,7 +56,7 @@ func (r *PostgresSessionRepository) FindSession(ctx context.Context, sessionID s
 	err := r.db.GetContext(ctx, &session, query, sessionID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errx.New("session not found", errx.TypeNotFound).
+			return nil, auth.ErrSessionNotFound().
 				WithDetail("session_id", sessionID)
 		}
 		return nil, errx.Wrap(err, "failed to find session", errx.TypeInternal).
@@ -132,7 +132,7 @@ func (r *PostgresSessionRepository) UpdateSessionActivity(ctx context.Context, s
 	}
 
 	if rowsAffected == 0 {
-		return errx.New("session not found or expired", errx.TypeNotFound).
+		return auth.ErrSessionNotFound().
 			WithDetail("session_id", sessionID)
 	}
 
@@ -155,7 +155,7 @@ func (r *PostgresSessionRepository) RevokeSession(ctx context.Context, sessionID
 	}
 
 	if rowsAffected == 0 {
-		return errx.New("session not found", errx.TypeNotFound).
+		return auth.ErrSessionNotFound().
 			WithDetail("session_id", sessionID)
 	}
 
@@ -175,6 +175,43 @@ func (r *PostgresSessionRepository) RevokeAllUserSessions(ctx context.Context, u
 	return nil
 }
 
+// FindTenantSessions busca todas las sesiones activas emitidas para un tenant
+func (r *PostgresSessionRepository) FindTenantSessions(ctx context.Context, tenantID kernel.TenantID) ([]*auth.UserSession, error) {
+	query := `
+		SELECT 
+			id, user_id, tenant_id, session_token, ip_address,
+			user_agent, expires_at, created_at, last_activity
+		FROM user_sessions 
+		WHERE tenant_id = $1 AND expires_at > NOW()`
+
+	var sessions []auth.UserSession
+	err := r.db.SelectContext(ctx, &sessions, query, tenantID.String())
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to find tenant sessions", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	result := make([]*auth.UserSession, len(sessions))
+	for i := range sessions {
+		result[i] = &sessions[i]
+	}
+
+	return result, nil
+}
+
+// RevokeAllTenantSessions revoca todas las sesiones emitidas para un tenant
+func (r *PostgresSessionRepository) RevokeAllTenantSessions(ctx context.Context, tenantID kernel.TenantID) error {
+	query := `DELETE FROM user_sessions WHERE tenant_id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, tenantID.String())
+	if err != nil {
+		return errx.Wrap(err, "failed to revoke all tenant sessions", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	return nil
+}
+
 // CleanExpiredSessions elimina sesiones expiradas (para mantenimiento)
 func (r *PostgresSessionRepository) CleanExpiredSessions(ctx context.Context) error {
 	query := `DELETE FROM user_sessions WHERE expires_at < NOW()`
@@ -208,7 +245,7 @@ func (r *PostgresSessionRepository) ExtendSession(ctx context.Context, sessionID
 	}
 
 	if rowsAffected == 0 {
-		return errx.New("session not found or expired", errx.TypeNotFound).
+		return auth.ErrSessionNotFound().
 			WithDetail("session_id", sessionID)
 	}
 
@@ -0,0 +1,151 @@
+package authinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// PostgresSSOConfigRepository implementación de PostgreSQL para SSOConfigRepository
+type PostgresSSOConfigRepository struct {
+	db *sqlx.DB
+}
+
+// NewPostgresSSOConfigRepository crea una nueva instancia del repositorio de configuración SSO
+func NewPostgresSSOConfigRepository(db *sqlx.DB) auth.SSOConfigRepository {
+	return &PostgresSSOConfigRepository{
+		db: db,
+	}
+}
+
+// dbSSOConfig fila con las columnas JSONB/array sin decodificar
+type dbSSOConfig struct {
+	auth.SSOConfig
+	RoleMappingsJSON []byte         `db:"role_mappings"`
+	AdminGroupsArray pq.StringArray `db:"admin_groups"`
+}
+
+// FindByTenant busca la configuración SSO de un tenant
+func (r *PostgresSSOConfigRepository) FindByTenant(ctx context.Context, tenantID kernel.TenantID) (*auth.SSOConfig, error) {
+	query := `
+		SELECT 
+			tenant_id, protocol, is_active, issuer_url, client_id, client_secret,
+			idp_entity_id, idp_sso_url, idp_certificate, groups_claim,
+			role_mappings, admin_groups, jit_provisioning, created_at, updated_at
+		FROM tenant_sso_configs 
+		WHERE tenant_id = $1`
+
+	var row dbSSOConfig
+	err := r.db.GetContext(ctx, &row, query, tenantID.String())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, auth.ErrSSONotConfigured().WithDetail("tenant_id", tenantID.String())
+		}
+		return nil, errx.Wrap(err, "failed to find sso config", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	config := row.SSOConfig
+	config.AdminGroups = []string(row.AdminGroupsArray)
+	config.RoleMappings = make(map[string]kernel.RoleID)
+	if len(row.RoleMappingsJSON) > 0 {
+		if err := json.Unmarshal(row.RoleMappingsJSON, &config.RoleMappings); err != nil {
+			return nil, errx.Wrap(err, "failed to decode sso role mappings", errx.TypeInternal).
+				WithDetail("tenant_id", tenantID.String())
+		}
+	}
+
+	return &config, nil
+}
+
+// Save crea o actualiza la configuración SSO de un tenant
+func (r *PostgresSSOConfigRepository) Save(ctx context.Context, config auth.SSOConfig) error {
+	roleMappings := config.RoleMappings
+	if roleMappings == nil {
+		roleMappings = map[string]kernel.RoleID{}
+	}
+	mappingsJSON, err := json.Marshal(roleMappings)
+	if err != nil {
+		return errx.Wrap(err, "failed to encode sso role mappings", errx.TypeInternal)
+	}
+
+	adminGroups := config.AdminGroups
+	if adminGroups == nil {
+		adminGroups = []string{}
+	}
+
+	query := `
+		INSERT INTO tenant_sso_configs (
+			tenant_id, protocol, is_active, issuer_url, client_id, client_secret,
+			idp_entity_id, idp_sso_url, idp_certificate, groups_claim,
+			role_mappings, admin_groups, jit_provisioning, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+		)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			protocol = EXCLUDED.protocol,
+			is_active = EXCLUDED.is_active,
+			issuer_url = EXCLUDED.issuer_url,
+			client_id = EXCLUDED.client_id,
+			client_secret = EXCLUDED.client_secret,
+			idp_entity_id = EXCLUDED.idp_entity_id,
+			idp_sso_url = EXCLUDED.idp_sso_url,
+			idp_certificate = EXCLUDED.idp_certificate,
+			groups_claim = EXCLUDED.groups_claim,
+			role_mappings = EXCLUDED.role_mappings,
+			admin_groups = EXCLUDED.admin_groups,
+			jit_provisioning = EXCLUDED.jit_provisioning,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = r.db.ExecContext(ctx, query,
+		config.TenantID.String(),
+		string(config.Protocol),
+		config.IsActive,
+		config.IssuerURL,
+		config.ClientID,
+		config.ClientSecret,
+		config.IdPEntityID,
+		config.IdPSSOURL,
+		config.IdPCertificate,
+		config.GetGroupsClaim(),
+		mappingsJSON,
+		pq.Array(adminGroups),
+		config.JITProvisioning,
+		config.CreatedAt,
+		config.UpdatedAt,
+	)
+	if err != nil {
+		return errx.Wrap(err, "failed to save sso config", errx.TypeInternal).
+			WithDetail("tenant_id", config.TenantID.String())
+	}
+
+	return nil
+}
+
+// Delete elimina la configuración SSO de un tenant
+func (r *PostgresSSOConfigRepository) Delete(ctx context.Context, tenantID kernel.TenantID) error {
+	query := `DELETE FROM tenant_sso_configs WHERE tenant_id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, tenantID.String())
+	if err != nil {
+		return errx.Wrap(err, "failed to delete sso config", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+
+	if rowsAffected == 0 {
+		return auth.ErrSSONotConfigured().WithDetail("tenant_id", tenantID.String())
+	}
+
+	return nil
+}
@@ -26,9 +26,9 @@ func NewPostgresTokenRepository(db *sqlx.DB) auth.TokenRepository {
 func (r *PostgresTokenRepository) SaveRefreshToken(ctx context.Context, token auth.RefreshToken) error {
 	query := `
 		INSERT INTO refresh_tokens (
-			id, token, user_id, tenant_id, expires_at, created_at, is_revoked
+			id, token, user_id, tenant_id, expires_at, created_at, is_revoked, amr, session_id
 		) VALUES (
-			:id, :token, :user_id, :tenant_id, :expires_at, :created_at, :is_revoked
+			:id, :token, :user_id, :tenant_id, :expires_at, :created_at, :is_revoked, :amr, NULLIF(:session_id, '')
 		)`
 
 	_, err := r.db.NamedExecContext(ctx, query, token)
@@ -44,7 +44,8 @@ func (r *PostgresTokenRepository) SaveRefreshToken(ctx context.Context, token au
 func (r *PostgresTokenRepository) FindRefreshToken(ctx context.Context, tokenValue string) (*auth.RefreshToken, error) {
 	query := `
 		SELECT 
-			id, token, user_id, tenant_id, expires_at, created_at, is_revoked
+			id, token, user_id, tenant_id, expires_at, created_at, is_revoked, amr,
+			COALESCE(session_id, '') AS session_id
 		FROM refresh_tokens 
 		WHERE token = $1 AND is_revoked = false`
 
@@ -100,6 +101,22 @@ func (r *PostgresTokenRepository) RevokeAllUserTokens(ctx context.Context, userI
 	return nil
 }
 
+// RevokeAllTenantTokens revoca todos los refresh tokens emitidos para un tenant
+func (r *PostgresTokenRepository) RevokeAllTenantTokens(ctx context.Context, tenantID kernel.TenantID) error {
+	query := `
+		UPDATE refresh_tokens 
+		SET is_revoked = true 
+		WHERE tenant_id = $1 AND is_revoked = false`
+
+	_, err := r.db.ExecContext(ctx, query, tenantID.String())
+	if err != nil {
+		return errx.Wrap(err, "failed to revoke all tenant tokens", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	return nil
+}
+
 // CleanExpiredTokens elimina tokens expirados (para mantenimiento)
 func (r *PostgresTokenRepository) CleanExpiredTokens(ctx context.Context) error {
 	query := `
@@ -135,7 +152,8 @@ func (r *PostgresTokenRepository) CountActiveTokens(ctx context.Context, userID
 func (r *PostgresTokenRepository) GetActiveTokensByUser(ctx context.Context, userID kernel.UserID) ([]*auth.RefreshToken, error) {
 	query := `
 		SELECT 
-			id, token, user_id, tenant_id, expires_at, created_at, is_revoked
+			id, token, user_id, tenant_id, expires_at, created_at, is_revoked, amr,
+			COALESCE(session_id, '') AS session_id
 		FROM refresh_tokens 
 		WHERE user_id = $1 AND is_revoked = false AND expires_at > NOW()
 		ORDER BY created_at DESC`
@@ -0,0 +1,66 @@
+package authinfra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisLoginRateLimiter limita intentos de login con una ventana fija en Redis
+type RedisLoginRateLimiter struct {
+	client *redis.Client
+	limit  int
+	window time.Duration
+}
+
+// NewRedisLoginRateLimiter crea un nuevo rate limiter de login
+func NewRedisLoginRateLimiter(client *redis.Client, limit int, window time.Duration) auth.LoginRateLimiter {
+	return &RedisLoginRateLimiter{
+		client: client,
+		limit:  limit,
+		window: window,
+	}
+}
+
+// Allow registra un intento y verifica si está dentro del límite
+func (rl *RedisLoginRateLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	if rl.limit <= 0 {
+		return true, 0, nil
+	}
+
+	redisKey := fmt.Sprintf("login_attempts:%s", key)
+
+	count, err := rl.client.Incr(ctx, redisKey).Result()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to increment login attempts: %w", err)
+	}
+
+	// Primer intento de la ventana: fijar expiración
+	if count == 1 {
+		if err := rl.client.Expire(ctx, redisKey, rl.window).Err(); err != nil {
+			return false, 0, fmt.Errorf("failed to set login attempts expiry: %w", err)
+		}
+	}
+
+	if count > int64(rl.limit) {
+		ttl, err := rl.client.TTL(ctx, redisKey).Result()
+		if err != nil || ttl < 0 {
+			ttl = rl.window
+		}
+		return false, ttl, nil
+	}
+
+	return true, 0, nil
+}
+
+// Reset limpia el contador tras un login exitoso
+func (rl *RedisLoginRateLimiter) Reset(ctx context.Context, key string) error {
+	redisKey := fmt.Sprintf("login_attempts:%s", key)
+	if err := rl.client.Del(ctx, redisKey).Err(); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
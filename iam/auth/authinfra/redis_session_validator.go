@@ -0,0 +1,93 @@
+package authinfra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	sessionActive  = "1"
+	sessionRevoked = "0"
+)
+
+// RedisSessionValidator valida sesiones contra Postgres cacheando el resultado
+// en Redis para no consultar la base de datos en cada request
+type RedisSessionValidator struct {
+	client      *redis.Client
+	sessionRepo auth.SessionRepository
+	ttl         time.Duration
+}
+
+// NewRedisSessionValidator crea un validador de sesiones con caché en Redis.
+// El ttl acota cuánto tarda una revocación hecha fuera de Invalidate en aplicarse
+func NewRedisSessionValidator(client *redis.Client, sessionRepo auth.SessionRepository, ttl time.Duration) auth.SessionValidator {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &RedisSessionValidator{
+		client:      client,
+		sessionRepo: sessionRepo,
+		ttl:         ttl,
+	}
+}
+
+// IsActive verifica si la sesión existe y no ha expirado
+func (v *RedisSessionValidator) IsActive(ctx context.Context, sessionID string) (bool, error) {
+	key := v.key(sessionID)
+
+	cached, err := v.client.Get(ctx, key).Result()
+	switch {
+	case err == nil:
+		return cached == sessionActive, nil
+	case err != redis.Nil:
+		// Redis no disponible: consultar directamente la base de datos
+		log.Printf("⚠️  Session cache unavailable, falling back to database: %v", err)
+	}
+
+	session, err := v.sessionRepo.FindSession(ctx, sessionID)
+	if err != nil {
+		if !errx.IsCode(err, auth.CodeSessionNotFound) {
+			return false, err
+		}
+		v.store(ctx, key, sessionRevoked, v.ttl)
+		return false, nil
+	}
+
+	if session.IsExpired() {
+		v.store(ctx, key, sessionRevoked, v.ttl)
+		return false, nil
+	}
+
+	// No cachear más allá de la expiración de la sesión
+	ttl := v.ttl
+	if remaining := time.Until(session.ExpiresAt); remaining < ttl {
+		ttl = remaining
+	}
+	v.store(ctx, key, sessionActive, ttl)
+
+	return true, nil
+}
+
+// Invalidate marca la sesión como revocada de forma inmediata
+func (v *RedisSessionValidator) Invalidate(ctx context.Context, sessionID string) error {
+	if err := v.client.Set(ctx, v.key(sessionID), sessionRevoked, v.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate session cache: %w", err)
+	}
+	return nil
+}
+
+func (v *RedisSessionValidator) key(sessionID string) string {
+	return fmt.Sprintf("session_active:%s", sessionID)
+}
+
+func (v *RedisSessionValidator) store(ctx context.Context, key, value string, ttl time.Duration) {
+	if err := v.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		log.Printf("⚠️  Failed to cache session state: %v", err)
+	}
+}
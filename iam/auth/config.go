@@ -3,6 +3,7 @@ package auth
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Abraxas-365/craftable/errx"
@@ -10,8 +11,11 @@ import (
 
 // Config configuración completa del módulo de autenticación
 type Config struct {
-	JWT   JWTConfig    `json:"jwt" yaml:"jwt"`
-	OAuth OAuthConfigs `json:"oauth" yaml:"oauth"`
+	JWT      JWTConfig      `json:"jwt" yaml:"jwt"`
+	OAuth    OAuthConfigs   `json:"oauth" yaml:"oauth"`
+	Password PasswordConfig `json:"password" yaml:"password"`
+	SSO      SSOSettings    `json:"sso" yaml:"sso"`
+	MFA      MFAConfig      `json:"mfa" yaml:"mfa"`
 }
 
 // JWTConfig configuración para JWT
@@ -22,6 +26,62 @@ type JWTConfig struct {
 	Issuer          string        `json:"issuer" yaml:"issuer"`
 }
 
+// PasswordConfig configuración para login con email/contraseña
+type PasswordConfig struct {
+	MinLength            int           `json:"min_length" yaml:"min_length"`
+	MaxFailedAttempts    int           `json:"max_failed_attempts" yaml:"max_failed_attempts"`
+	LockoutDuration      time.Duration `json:"lockout_duration" yaml:"lockout_duration"`
+	LoginRateLimit       int           `json:"login_rate_limit" yaml:"login_rate_limit"`
+	LoginRateWindow      time.Duration `json:"login_rate_window" yaml:"login_rate_window"`
+	ResetTokenTTL        time.Duration `json:"reset_token_ttl" yaml:"reset_token_ttl"`
+	VerificationTokenTTL time.Duration `json:"verification_token_ttl" yaml:"verification_token_ttl"`
+	AppBaseURL           string        `json:"app_base_url" yaml:"app_base_url"`
+}
+
+// DefaultPasswordConfig retorna la configuración por defecto de contraseñas
+func DefaultPasswordConfig() PasswordConfig {
+	return PasswordConfig{
+		MinLength:            8,
+		MaxFailedAttempts:    5,
+		LockoutDuration:      15 * time.Minute,
+		LoginRateLimit:       10,
+		LoginRateWindow:      time.Minute,
+		ResetTokenTTL:        time.Hour,
+		VerificationTokenTTL: 48 * time.Hour,
+		AppBaseURL:           "http://localhost:8080",
+	}
+}
+
+// SSOSettings configuración del lado SP para el SSO empresarial
+type SSOSettings struct {
+	BaseURL    string `json:"base_url" yaml:"base_url"`         // URL pública del servidor
+	SPEntityID string `json:"sp_entity_id" yaml:"sp_entity_id"` // Entity ID SAML del SP
+}
+
+// OIDCCallbackURL URL de callback OIDC registrada en los IdPs
+func (s SSOSettings) OIDCCallbackURL() string {
+	return strings.TrimSuffix(s.BaseURL, "/") + "/auth/sso/oidc/callback"
+}
+
+// SAMLACSURL URL del Assertion Consumer Service
+func (s SSOSettings) SAMLACSURL() string {
+	return strings.TrimSuffix(s.BaseURL, "/") + "/auth/sso/saml/acs"
+}
+
+// GetSPEntityID retorna el entity ID, por defecto la URL del metadata
+func (s SSOSettings) GetSPEntityID() string {
+	if s.SPEntityID != "" {
+		return s.SPEntityID
+	}
+	return strings.TrimSuffix(s.BaseURL, "/") + "/auth/sso/saml/metadata"
+}
+
+// MFAConfig configuración de la verificación en dos pasos (TOTP)
+type MFAConfig struct {
+	Issuer        string `json:"issuer" yaml:"issuer"`                 // Nombre mostrado en la app autenticadora
+	EncryptionKey string `json:"encryption_key" yaml:"encryption_key"` // Clave para cifrar los secretos TOTP
+}
+
 // OAuthConfig configuración base para OAuth
 type OAuthConfig struct {
 	ClientID     string   `json:"client_id"`
@@ -52,6 +112,13 @@ func DefaultConfig() Config {
 				Scopes: []string{"openid", "email", "profile", "User.Read"},
 			},
 		},
+		Password: DefaultPasswordConfig(),
+		SSO: SSOSettings{
+			BaseURL: "http://localhost:8080",
+		},
+		MFA: MFAConfig{
+			Issuer: "Relay",
+		},
 	}
 }
 
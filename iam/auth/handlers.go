@@ -2,9 +2,11 @@ package auth
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/Abraxas-365/craftable/errx"
 	"github.com/Abraxas-365/craftable/ptrx"
 	"github.com/Abraxas-365/relay/iam"
 	"github.com/Abraxas-365/relay/iam/tenant"
@@ -23,6 +25,26 @@ type AuthHandlers struct {
 	tokenRepo     TokenRepository
 	sessionRepo   SessionRepository
 	stateManager  StateManager
+
+	// Revocación de sesiones (opcional)
+	sessionValidator SessionValidator
+
+	// Multi-tenant
+	membershipRepo user.MembershipRepository
+
+	// 2FA (opcional)
+	mfaService *MFAService
+
+	// Política de registro del tenant (opcional; sin él nadie se registra solo)
+	tenantConfigRepo tenant.TenantConfigRepository
+
+	// Email/contraseña
+	passwordService  user.PasswordService
+	resetRepo        PasswordResetRepository
+	verificationRepo EmailVerificationRepository
+	rateLimiter      LoginRateLimiter
+	mailer           AuthMailer
+	passwordConfig   PasswordConfig
 }
 
 // NewAuthHandlers crea un nuevo handler de autenticación
@@ -33,19 +55,43 @@ func NewAuthHandlers(
 	tenantRepo tenant.TenantRepository,
 	tokenRepo TokenRepository,
 	sessionRepo SessionRepository,
+	sessionValidator SessionValidator,
 	stateManager StateManager,
+	membershipRepo user.MembershipRepository,
+	passwordService user.PasswordService,
+	resetRepo PasswordResetRepository,
+	verificationRepo EmailVerificationRepository,
+	rateLimiter LoginRateLimiter,
+	mailer AuthMailer,
+	passwordConfig PasswordConfig,
+	mfaService *MFAService,
 ) *AuthHandlers {
 	return &AuthHandlers{
-		oauthServices: oauthServices,
-		tokenService:  tokenService,
-		userRepo:      userRepo,
-		tenantRepo:    tenantRepo,
-		tokenRepo:     tokenRepo,
-		sessionRepo:   sessionRepo,
-		stateManager:  stateManager,
+		oauthServices:    oauthServices,
+		tokenService:     tokenService,
+		userRepo:         userRepo,
+		tenantRepo:       tenantRepo,
+		tokenRepo:        tokenRepo,
+		sessionRepo:      sessionRepo,
+		sessionValidator: sessionValidator,
+		stateManager:     stateManager,
+		membershipRepo:   membershipRepo,
+		passwordService:  passwordService,
+		resetRepo:        resetRepo,
+		verificationRepo: verificationRepo,
+		rateLimiter:      rateLimiter,
+		mailer:           mailer,
+		passwordConfig:   passwordConfig,
+		mfaService:       mfaService,
 	}
 }
 
+// SetTenantConfigRepository habilita la política de registro por tenant. Sin
+// ella el registro con contraseña queda cerrado en todos los tenants
+func (ah *AuthHandlers) SetTenantConfigRepository(repo tenant.TenantConfigRepository) {
+	ah.tenantConfigRepo = repo
+}
+
 // LoginRequest estructura para iniciar login OAuth
 type LoginRequest struct {
 	Provider  iam.OAuthProvider `json:"provider"`
@@ -66,6 +112,7 @@ type TokenResponse struct {
 	ExpiresIn    int                     `json:"expires_in"`
 	User         user.UserDetailsDTO     `json:"user"`
 	Tenant       tenant.TenantDetailsDTO `json:"tenant"`
+	BackupCodes  []string                `json:"backup_codes,omitempty"` // Solo al completar un enrolamiento 2FA
 }
 
 // RefreshTokenRequest estructura para renovar token
@@ -82,6 +129,32 @@ func (ah *AuthHandlers) RegisterRoutes(app *fiber.App) {
 	auth.Post("/refresh", ah.RefreshToken)
 	auth.Post("/logout", ah.Logout)
 	auth.Get("/me", ah.GetCurrentUser) // Nueva ruta para obtener usuario actual
+
+	// Email/contraseña
+	auth.Post("/register", ah.Register)
+	auth.Post("/login/password", ah.PasswordLogin)
+	auth.Get("/verify-email", ah.VerifyEmail)
+	auth.Post("/verify-email", ah.VerifyEmail)
+	auth.Post("/forgot-password", ah.ForgotPassword)
+	auth.Post("/reset-password", ah.ResetPassword)
+
+	// Multi-tenant
+	auth.Get("/tenants", ah.ListTenants)
+	auth.Post("/switch-tenant", ah.SwitchTenant)
+
+	// Sesiones por dispositivo
+	auth.Get("/sessions", ah.ListSessions)
+	auth.Delete("/sessions/:id", ah.RevokeSession)
+
+	// 2FA (TOTP)
+	if ah.mfaService != nil {
+		auth.Get("/mfa", ah.GetMFAStatus)
+		auth.Post("/mfa/enroll", ah.EnrollMFA)
+		auth.Post("/mfa/confirm", ah.ConfirmMFA)
+		auth.Post("/mfa/verify", ah.VerifyMFA)
+		auth.Post("/mfa/backup-codes", ah.RegenerateBackupCodes)
+		auth.Post("/mfa/disable", ah.DisableMFA)
+	}
 }
 
 // InitiateLogin inicia el proceso de login OAuth
@@ -203,23 +276,56 @@ func (ah *AuthHandlers) HandleCallback(c *fiber.Ctx) error {
 		})
 	}
 
+	response, err := ah.completeLogin(c, userEntity, tenantEntity, []string{AMRFederated})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(response)
+}
+
+// issueSession genera tokens, registra la sesión y setea las cookies
+func (ah *AuthHandlers) issueSession(c *fiber.Ctx, userEntity *user.User, tenantEntity *tenant.Tenant, amr []string) (*TokenResponse, error) {
+	// El rol de admin depende del tenant al que se emite la sesión
+	isAdmin, err := ah.isAdminIn(c.Context(), userEntity, tenantEntity.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Crear sesión de usuario: respalda los tokens y permite revocarlos por dispositivo
+	session := UserSession{
+		ID:           generateID(),
+		UserID:       userEntity.ID,
+		TenantID:     tenantEntity.ID,
+		SessionToken: generateID(),
+		IPAddress:    c.IP(),
+		UserAgent:    c.Get("User-Agent"),
+		ExpiresAt:    time.Now().Add(7 * 24 * time.Hour), // Misma vida que el refresh token
+		CreatedAt:    time.Now(),
+		LastActivity: time.Now(),
+	}
+
+	if err := ah.sessionRepo.SaveSession(c.Context(), session); err != nil {
+		return nil, err
+	}
+
 	// Generar tokens de nuestra aplicación
 	accessToken, err := ah.tokenService.GenerateAccessToken(userEntity.ID, tenantEntity.ID, map[string]any{
 		"email":    userEntity.Email,
 		"name":     userEntity.Name,
-		"is_admin": userEntity.IsAdmin,
+		"is_admin": isAdmin,
+		"amr":      amr,
+		"sid":      session.ID,
 	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return nil, err
 	}
 
 	refreshTokenStr, err := ah.tokenService.GenerateRefreshToken(userEntity.ID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return nil, err
 	}
 
 	// Guardar refresh token en base de datos
@@ -231,30 +337,12 @@ func (ah *AuthHandlers) HandleCallback(c *fiber.Ctx) error {
 		ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
 		CreatedAt: time.Now(),
 		IsRevoked: false,
+		AMR:       strings.Join(amr, ","),
+		SessionID: session.ID,
 	}
 
 	if err := ah.tokenRepo.SaveRefreshToken(c.Context(), refreshToken); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to save refresh token",
-		})
-	}
-
-	// Crear sesión de usuario
-	session := UserSession{
-		ID:           generateID(),
-		UserID:       userEntity.ID,
-		TenantID:     tenantEntity.ID,
-		SessionToken: generateID(),
-		IPAddress:    c.IP(),
-		UserAgent:    c.Get("User-Agent"),
-		ExpiresAt:    time.Now().Add(24 * time.Hour),
-		CreatedAt:    time.Now(),
-		LastActivity: time.Now(),
-	}
-
-	if err := ah.sessionRepo.SaveSession(c.Context(), session); err != nil {
-		// Log error pero no fallar la autenticación
-		// logger.Error("Failed to save session", err)
+		return nil, fmt.Errorf("failed to save refresh token")
 	}
 
 	// Actualizar último login del usuario
@@ -266,12 +354,15 @@ func (ah *AuthHandlers) HandleCallback(c *fiber.Ctx) error {
 
 	// En desarrollo, puedes devolver JSON directamente
 	// En producción, probablemente quieras hacer redirect con los tokens en cookies o URL
-	response := TokenResponse{
+	userDTO := userEntity.ToDTO()
+	userDTO.IsAdmin = isAdmin
+
+	response := &TokenResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshTokenStr,
 		TokenType:    "Bearer",
 		ExpiresIn:    int(15 * time.Minute / time.Second),
-		User:         userEntity.ToDTO(),
+		User:         userDTO,
 		Tenant:       tenantEntity.ToDTO(),
 	}
 
@@ -294,7 +385,7 @@ func (ah *AuthHandlers) HandleCallback(c *fiber.Ctx) error {
 		SameSite: "Lax",
 	})
 
-	return c.JSON(response)
+	return response, nil
 }
 
 // RefreshToken renueva un access token usando refresh token
@@ -362,11 +453,32 @@ func (ah *AuthHandlers) RefreshToken(c *fiber.Ctx) error {
 		})
 	}
 
+	isAdmin, err := ah.isAdminIn(c.Context(), userEntity, tenantEntity.ID)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// La sesión debe seguir activa; cada refresh cuenta como actividad del dispositivo
+	if refreshToken.SessionID != "" {
+		if err := ah.sessionRepo.UpdateSessionActivity(c.Context(), refreshToken.SessionID); err != nil {
+			if errx.IsCode(err, CodeSessionNotFound) {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error": ErrSessionRevoked().Error(),
+				})
+			}
+			return err
+		}
+	}
+
 	// Generar nuevo access token
 	accessToken, err := ah.tokenService.GenerateAccessToken(userEntity.ID, tenantEntity.ID, map[string]any{
 		"email":    userEntity.Email,
 		"name":     userEntity.Name,
-		"is_admin": userEntity.IsAdmin,
+		"is_admin": isAdmin,
+		"amr":      refreshToken.Methods(),
+		"sid":      refreshToken.SessionID,
 	})
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -393,40 +505,11 @@ func (ah *AuthHandlers) RefreshToken(c *fiber.Ctx) error {
 
 // Logout invalida tokens y sesiones del usuario
 func (ah *AuthHandlers) Logout(c *fiber.Ctx) error {
-	// Intentar obtener contexto de auth del middleware
-	authContext, ok := GetAuthContext(c)
-	if !ok {
-		// Fallback: intentar decodificar el token desde Authorization o cookie
-		var token string
-		authHeader := c.Get("Authorization")
-		if authHeader != "" {
-			parts := strings.SplitN(authHeader, " ", 2)
-			if len(parts) == 2 && parts[0] == "Bearer" && parts[1] != "" {
-				token = parts[1]
-			}
-		}
-		if token == "" {
-			token = c.Cookies("access_token")
-		}
-		if token == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": iam.ErrUnauthorized().Error(),
-			})
-		}
-		claims, err := ah.tokenService.ValidateAccessToken(token)
-		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": iam.ErrUnauthorized().Error(),
-			})
-		}
-		// Construir contexto de autenticación a partir de los claims
-		authContext = &kernel.AuthContext{
-			UserID:   claims.UserID,
-			TenantID: claims.TenantID,
-			IsAdmin:  claims.IsAdmin,
-			Email:    claims.Email,
-			Name:     claims.Name,
-		}
+	authContext, err := ah.authContextFromRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
 	}
 
 	// Revocar todos los refresh tokens del usuario
@@ -436,25 +519,13 @@ func (ah *AuthHandlers) Logout(c *fiber.Ctx) error {
 	}
 
 	// Revocar todas las sesiones del usuario
-	if err := ah.sessionRepo.RevokeAllUserSessions(c.Context(), authContext.UserID); err != nil {
+	if err := ah.revokeAllSessions(c.Context(), authContext.UserID); err != nil {
 		// Log error pero no fallar
 		// logger.Error("Failed to revoke user sessions", err)
 	}
 
 	// Clear cookies
-	c.Cookie(&fiber.Cookie{
-		Name:     "access_token",
-		Value:    "",
-		Expires:  time.Now().Add(-time.Hour),
-		HTTPOnly: true,
-	})
-
-	c.Cookie(&fiber.Cookie{
-		Name:     "refresh_token",
-		Value:    "",
-		Expires:  time.Now().Add(-time.Hour),
-		HTTPOnly: true,
-	})
+	clearAuthCookies(c)
 
 	return c.JSON(fiber.Map{
 		"message": "Logged out successfully",
@@ -463,38 +534,11 @@ func (ah *AuthHandlers) Logout(c *fiber.Ctx) error {
 
 // GetCurrentUser obtiene la información del usuario autenticado
 func (ah *AuthHandlers) GetCurrentUser(c *fiber.Ctx) error {
-	authContext, ok := GetAuthContext(c)
-	if !ok {
-		// Fallback: intentar decodificar el token desde Authorization o cookie
-		var token string
-		authHeader := c.Get("Authorization")
-		if authHeader != "" {
-			parts := strings.SplitN(authHeader, " ", 2)
-			if len(parts) == 2 && parts[0] == "Bearer" && parts[1] != "" {
-				token = parts[1]
-			}
-		}
-		if token == "" {
-			token = c.Cookies("access_token")
-		}
-		if token == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": iam.ErrUnauthorized().Error(),
-			})
-		}
-		claims, err := ah.tokenService.ValidateAccessToken(token)
-		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": iam.ErrUnauthorized().Error(),
-			})
-		}
-		authContext = &kernel.AuthContext{
-			UserID:   claims.UserID,
-			TenantID: claims.TenantID,
-			IsAdmin:  claims.IsAdmin,
-			Email:    claims.Email,
-			Name:     claims.Name,
-		}
+	authContext, err := ah.authContextFromRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
 	}
 
 	// Buscar usuario completo
@@ -522,26 +566,10 @@ func (ah *AuthHandlers) GetCurrentUser(c *fiber.Ctx) error {
 // findOrCreateUser busca o crea un usuario basado en la información OAuth
 // (Esta función permanece igual que en la versión anterior)
 func (ah *AuthHandlers) findOrCreateUser(ctx context.Context, userInfo *OAuthUserInfo, provider iam.OAuthProvider, stateData map[string]interface{}) (*user.User, *tenant.Tenant, error) {
-	// Buscar tenant si se especificó RUC
-	var tenantEntity *tenant.Tenant
-	var err error
-
-	if tenantRUC, ok := stateData["tenant_ruc"].(string); ok && tenantRUC != "" {
-		tenantEntity, err = ah.tenantRepo.FindByRUC(ctx, tenantRUC)
-		if err != nil {
-			return nil, nil, tenant.ErrTenantNotFound()
-		}
-	}
-
-	// Si no hay tenant específico, buscar por email del usuario
-	if tenantEntity == nil {
-		// Por ahora, crear un tenant automático o usar el primer tenant activo
-		// En producción, esto debería manejarse diferente
-		tenants, err := ah.tenantRepo.FindActive(ctx)
-		if err != nil || len(tenants) == 0 {
-			return nil, nil, tenant.ErrTenantNotFound()
-		}
-		tenantEntity = tenants[0] // Usar el primer tenant activo
+	tenantRUC, _ := stateData["tenant_ruc"].(string)
+	tenantEntity, err := ah.resolveTenant(ctx, tenantRUC)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Buscar usuario existente
@@ -602,6 +630,26 @@ func (ah *AuthHandlers) findOrCreateUser(ctx context.Context, userInfo *OAuthUse
 	return newUser, tenantEntity, nil
 }
 
+// resolveTenant busca el tenant por RUC o usa el primer tenant activo
+func (ah *AuthHandlers) resolveTenant(ctx context.Context, tenantRUC string) (*tenant.Tenant, error) {
+	// Buscar tenant si se especificó RUC
+	if tenantRUC != "" {
+		tenantEntity, err := ah.tenantRepo.FindByRUC(ctx, tenantRUC)
+		if err != nil {
+			return nil, tenant.ErrTenantNotFound()
+		}
+		return tenantEntity, nil
+	}
+
+	// Por ahora, usar el primer tenant activo
+	// En producción, esto debería manejarse diferente
+	tenants, err := ah.tenantRepo.FindActive(ctx)
+	if err != nil || len(tenants) == 0 {
+		return nil, tenant.ErrTenantNotFound()
+	}
+	return tenants[0], nil
+}
+
 // Helper functions
 func generateID() string {
 	// Implementar generación de ID único (UUID, nanoid, etc.)
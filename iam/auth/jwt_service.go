@@ -43,6 +43,8 @@ type JWTClaims struct {
 	Email    string          `json:"email"`
 	Name     string          `json:"name"`
 	IsAdmin  bool            `json:"is_admin"`
+	AMR      []string        `json:"amr,omitempty"`
+	SID      string          `json:"sid,omitempty"` // Sesión que respalda el token
 	jwt.RegisteredClaims
 }
 
@@ -54,6 +56,8 @@ func (j *JWTService) GenerateAccessToken(userID kernel.UserID, tenantID kernel.T
 	email, _ := claims["email"].(string)
 	name, _ := claims["name"].(string)
 	isAdmin, _ := claims["is_admin"].(bool)
+	amr, _ := claims["amr"].([]string)
+	sid, _ := claims["sid"].(string)
 
 	jwtClaims := JWTClaims{
 		UserID:   userID,
@@ -61,6 +65,8 @@ func (j *JWTService) GenerateAccessToken(userID kernel.UserID, tenantID kernel.T
 		Email:    email,
 		Name:     name,
 		IsAdmin:  isAdmin,
+		AMR:      amr,
+		SID:      sid,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    j.issuer,
 			Subject:   userID.String(),
@@ -110,6 +116,8 @@ func (j *JWTService) ValidateAccessToken(tokenString string) (*TokenClaims, erro
 		Email:     jwtClaims.Email,
 		Name:      jwtClaims.Name,
 		IsAdmin:   jwtClaims.IsAdmin,
+		AMR:       jwtClaims.AMR,
+		SessionID: jwtClaims.SID,
 		IssuedAt:  jwtClaims.IssuedAt.Time,
 		ExpiresAt: jwtClaims.ExpiresAt.Time,
 	}, nil
@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Authentication Methods (claim amr, RFC 8176)
+// ============================================================================
+
+const (
+	AMRPassword  = "pwd" // Email/contraseña
+	AMRFederated = "fed" // OAuth o SSO empresarial
+	AMROTP       = "otp" // Código TOTP o de respaldo
+	AMRMFA       = "mfa" // Se usó más de un factor
+)
+
+// TenantSettingRequireAdminMFA clave de tenant_settings que obliga 2FA a los admins
+const TenantSettingRequireAdminMFA = "security.require_admin_2fa"
+
+const backupCodeCount = 10
+
+// ============================================================================
+// MFA Entity
+// ============================================================================
+
+// UserMFA configuración TOTP de un usuario. El secreto se guarda cifrado y
+// los códigos de respaldo como hash SHA-256.
+type UserMFA struct {
+	UserID          kernel.UserID `db:"user_id" json:"user_id"`
+	SecretEncrypted string        `db:"secret_encrypted" json:"-"`
+	Enabled         bool          `db:"enabled" json:"enabled"`
+	BackupCodes     []string      `db:"-" json:"-"`
+	LastUsedStep    int64         `db:"last_used_step" json:"-"`
+	ConfirmedAt     *time.Time    `db:"confirmed_at" json:"confirmed_at,omitempty"`
+	CreatedAt       time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+// MFAStatus estado de 2FA expuesto al usuario
+type MFAStatus struct {
+	Enabled              bool       `json:"enabled"`
+	Required             bool       `json:"required"`
+	BackupCodesRemaining int        `json:"backup_codes_remaining"`
+	ConfirmedAt          *time.Time `json:"confirmed_at,omitempty"`
+}
+
+// MFAEnrollment datos para que el usuario registre el secreto en su app
+type MFAEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"` // Renderizar como QR en el cliente
+}
+
+// MFAChallenge login pendiente del segundo factor
+type MFAChallenge struct {
+	UserID   kernel.UserID
+	TenantID kernel.TenantID
+	AMR      []string
+	Attempts int
+}
+
+// ============================================================================
+// MFA Domain Methods
+// ============================================================================
+
+// Enable activa 2FA con un nuevo set de códigos de respaldo (hasheados)
+func (m *UserMFA) Enable(step int64, hashedBackupCodes []string) {
+	now := time.Now()
+	m.Enabled = true
+	m.LastUsedStep = step
+	m.BackupCodes = hashedBackupCodes
+	m.ConfirmedAt = &now
+	m.UpdatedAt = now
+}
+
+// ConsumeBackupCode valida y elimina un código de respaldo
+func (m *UserMFA) ConsumeBackupCode(code string) bool {
+	hashed := hashBackupCode(code)
+	for i, stored := range m.BackupCodes {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hashed)) == 1 {
+			m.BackupCodes = append(m.BackupCodes[:i], m.BackupCodes[i+1:]...)
+			m.UpdatedAt = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
+// Status construye el estado visible para el usuario
+func (m *UserMFA) Status(required bool) MFAStatus {
+	if m == nil {
+		return MFAStatus{Required: required}
+	}
+	return MFAStatus{
+		Enabled:              m.Enabled,
+		Required:             required,
+		BackupCodesRemaining: len(m.BackupCodes),
+		ConfirmedAt:          m.ConfirmedAt,
+	}
+}
+
+// HasAMR verifica si la lista de métodos incluye el método dado
+func HasAMR(amr []string, method string) bool {
+	for _, m := range amr {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// generateBackupCodes genera códigos legibles (xxxxx-xxxxx) y sus hashes
+func generateBackupCodes() ([]string, []string, error) {
+	// 32 símbolos sin i/l/o para evitar confusiones y sesgo de módulo
+	const alphabet = "abcdefghjkmnpqrstuvwxyz234567890"
+
+	codes := make([]string, backupCodeCount)
+	hashes := make([]string, backupCodeCount)
+	for i := range codes {
+		raw := make([]byte, 10)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		for j := range raw {
+			raw[j] = alphabet[raw[j]%32]
+		}
+		codes[i] = string(raw[:5]) + "-" + string(raw[5:])
+		hashes[i] = hashBackupCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// hashBackupCode normaliza y hashea un código de respaldo
+func hashBackupCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// ============================================================================
+// MFA Errors
+// ============================================================================
+
+var (
+	CodeInvalidMFACode     = ErrRegistry.Register("INVALID_MFA_CODE", errx.TypeAuthorization, http.StatusUnauthorized, "Código de verificación inválido")
+	CodeInvalidMFAToken    = ErrRegistry.Register("INVALID_MFA_TOKEN", errx.TypeAuthorization, http.StatusUnauthorized, "Sesión de verificación inválida o expirada")
+	CodeMFANotEnrolled     = ErrRegistry.Register("MFA_NOT_ENROLLED", errx.TypeBusiness, http.StatusBadRequest, "La verificación en dos pasos no está configurada")
+	CodeMFAAlreadyEnabled  = ErrRegistry.Register("MFA_ALREADY_ENABLED", errx.TypeConflict, http.StatusConflict, "La verificación en dos pasos ya está activa")
+	CodeMFARequiredByAdmin = ErrRegistry.Register("MFA_REQUIRED_BY_POLICY", errx.TypeBusiness, http.StatusForbidden, "La política de la empresa exige verificación en dos pasos")
+)
+
+func ErrInvalidMFACode() *errx.Error {
+	return ErrRegistry.New(CodeInvalidMFACode)
+}
+
+func ErrInvalidMFAToken() *errx.Error {
+	return ErrRegistry.New(CodeInvalidMFAToken)
+}
+
+func ErrMFANotEnrolled() *errx.Error {
+	return ErrRegistry.New(CodeMFANotEnrolled)
+}
+
+func ErrMFAAlreadyEnabled() *errx.Error {
+	return ErrRegistry.New(CodeMFAAlreadyEnabled)
+}
+
+func ErrMFARequiredByPolicy() *errx.Error {
+	return ErrRegistry.New(CodeMFARequiredByAdmin)
+}
@@ -0,0 +1,314 @@
+package auth
+
+import (
+	"log"
+
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/iam/user"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ============================================================================
+// 2FA DTOs
+// ============================================================================
+
+// MFAChallengeResponse respuesta de login cuando falta el segundo factor
+type MFAChallengeResponse struct {
+	MFARequired        bool   `json:"mfa_required"`
+	MFAToken           string `json:"mfa_token"`
+	EnrollmentRequired bool   `json:"enrollment_required"`
+}
+
+// MFACodeRequest solicitud con un código TOTP o de respaldo
+type MFACodeRequest struct {
+	Code string `json:"code"`
+}
+
+// MFAVerifyRequest segundo paso del login
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+// MFAEnrollRequest permite enrolarse durante el login cuando la política lo exige
+type MFAEnrollRequest struct {
+	MFAToken string `json:"mfa_token,omitempty"`
+}
+
+// MFAPolicyRequest política de 2FA del tenant
+type MFAPolicyRequest struct {
+	RequireAdmin2FA bool `json:"require_admin_2fa"`
+}
+
+// ============================================================================
+// Login
+// ============================================================================
+
+// completeLogin emite la sesión o, si aplica 2FA y aún no se verificó, un
+// challenge para el segundo paso (/auth/mfa/verify)
+func (ah *AuthHandlers) completeLogin(c *fiber.Ctx, userEntity *user.User, tenantEntity *tenant.Tenant, amr []string) (any, error) {
+	if ah.mfaService != nil && !HasAMR(amr, AMROTP) {
+		isAdmin, err := ah.isAdminIn(c.Context(), userEntity, tenantEntity.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		required, enrollment, err := ah.mfaService.Requirement(c.Context(), userEntity.ID, tenantEntity.ID, isAdmin)
+		if err != nil {
+			return nil, err
+		}
+
+		if required {
+			// Persistir cambios previos al challenge (ej. reset de intentos fallidos)
+			if err := ah.userRepo.Save(c.Context(), *userEntity); err != nil {
+				log.Printf("⚠️  Failed to save user before mfa challenge: %v", err)
+			}
+
+			token, err := ah.mfaService.CreateChallenge(c.Context(), MFAChallenge{
+				UserID:   userEntity.ID,
+				TenantID: tenantEntity.ID,
+				AMR:      amr,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			return &MFAChallengeResponse{
+				MFARequired:        true,
+				MFAToken:           token,
+				EnrollmentRequired: enrollment,
+			}, nil
+		}
+	}
+
+	return ah.issueSession(c, userEntity, tenantEntity, amr)
+}
+
+// VerifyMFA completa el login con el segundo factor
+// POST /auth/mfa/verify
+func (ah *AuthHandlers) VerifyMFA(c *fiber.Ctx) error {
+	var req MFAVerifyRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return ErrInvalidMFACode()
+	}
+
+	challenge, backupCodes, err := ah.mfaService.CompleteChallenge(c.Context(), req.MFAToken, req.Code)
+	if err != nil {
+		return err
+	}
+
+	userEntity, err := ah.userRepo.FindByID(c.Context(), challenge.UserID, challenge.TenantID)
+	if err != nil {
+		return ErrInvalidMFAToken()
+	}
+
+	tenantEntity, err := ah.tenantRepo.FindByID(c.Context(), challenge.TenantID)
+	if err != nil {
+		return tenant.ErrTenantNotFound()
+	}
+
+	if !userEntity.CanLogin() {
+		return user.ErrUserSuspended()
+	}
+
+	if !tenantEntity.IsActive() {
+		return tenant.ErrTenantSuspended()
+	}
+
+	amr := append(challenge.AMR, AMROTP, AMRMFA)
+	response, err := ah.issueSession(c, userEntity, tenantEntity, amr)
+	if err != nil {
+		return ErrTokenGenerationFailed().WithCause(err)
+	}
+
+	// Enrolamiento forzado: los códigos de respaldo se muestran una sola vez
+	response.BackupCodes = backupCodes
+
+	return c.JSON(response)
+}
+
+// ============================================================================
+// Gestión de 2FA del usuario
+// ============================================================================
+
+// GetMFAStatus retorna el estado de 2FA del usuario autenticado
+// GET /auth/mfa
+func (ah *AuthHandlers) GetMFAStatus(c *fiber.Ctx) error {
+	authContext, err := ah.authContextFromRequest(c)
+	if err != nil {
+		return err
+	}
+
+	status, err := ah.mfaService.Status(c.Context(), authContext.UserID, authContext.TenantID, authContext.IsAdmin)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(status)
+}
+
+// EnrollMFA genera el secreto TOTP (otpauth URI para el QR). Acepta un
+// access token o el mfa_token de un login con enrolamiento obligatorio.
+// POST /auth/mfa/enroll
+func (ah *AuthHandlers) EnrollMFA(c *fiber.Ctx) error {
+	var req MFAEnrollRequest
+	_ = c.BodyParser(&req)
+
+	var userEntity *user.User
+	if req.MFAToken != "" {
+		challenge, err := ah.mfaService.GetChallenge(c.Context(), req.MFAToken)
+		if err != nil {
+			return err
+		}
+		userEntity, err = ah.userRepo.FindByID(c.Context(), challenge.UserID, challenge.TenantID)
+		if err != nil {
+			return ErrInvalidMFAToken()
+		}
+	} else {
+		authContext, err := ah.authContextFromRequest(c)
+		if err != nil {
+			return err
+		}
+		userEntity, err = ah.userRepo.FindByID(c.Context(), authContext.UserID, authContext.TenantID)
+		if err != nil {
+			return user.ErrUserNotFound()
+		}
+	}
+
+	enrollment, err := ah.mfaService.BeginEnrollment(c.Context(), userEntity)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(enrollment)
+}
+
+// ConfirmMFA activa 2FA con el primer código y retorna los códigos de respaldo
+// POST /auth/mfa/confirm
+func (ah *AuthHandlers) ConfirmMFA(c *fiber.Ctx) error {
+	authContext, err := ah.authContextFromRequest(c)
+	if err != nil {
+		return err
+	}
+
+	var req MFACodeRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return ErrInvalidMFACode()
+	}
+
+	backupCodes, err := ah.mfaService.ConfirmEnrollment(c.Context(), authContext.UserID, req.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"enabled":      true,
+		"backup_codes": backupCodes,
+	})
+}
+
+// RegenerateBackupCodes reemplaza los códigos de respaldo
+// POST /auth/mfa/backup-codes
+func (ah *AuthHandlers) RegenerateBackupCodes(c *fiber.Ctx) error {
+	authContext, err := ah.authContextFromRequest(c)
+	if err != nil {
+		return err
+	}
+
+	var req MFACodeRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return ErrInvalidMFACode()
+	}
+
+	backupCodes, err := ah.mfaService.RegenerateBackupCodes(c.Context(), authContext.UserID, req.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"backup_codes": backupCodes,
+	})
+}
+
+// DisableMFA desactiva 2FA; no se permite si la política del tenant lo exige
+// POST /auth/mfa/disable
+func (ah *AuthHandlers) DisableMFA(c *fiber.Ctx) error {
+	authContext, err := ah.authContextFromRequest(c)
+	if err != nil {
+		return err
+	}
+
+	if authContext.IsAdmin && ah.mfaService.TenantRequiresAdminMFA(c.Context(), authContext.TenantID) {
+		return ErrMFARequiredByPolicy()
+	}
+
+	var req MFACodeRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return ErrInvalidMFACode()
+	}
+
+	if err := ah.mfaService.Disable(c.Context(), authContext.UserID, req.Code); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"enabled": false})
+}
+
+// ============================================================================
+// Política del tenant (admin)
+// ============================================================================
+
+// RegisterAdminRoutes registra la administración de las políticas de registro y de 2FA
+func (ah *AuthHandlers) RegisterAdminRoutes(router fiber.Router, authMiddleware *AuthMiddleware) {
+	if ah.tenantConfigRepo != nil {
+		signup := router.Group("/signup", authMiddleware.RequireAdmin())
+
+		signup.Get("/policy", ah.GetSignupPolicy)
+		signup.Put("/policy", ah.UpdateSignupPolicy)
+	}
+
+	if ah.mfaService == nil {
+		return
+	}
+
+	mfa := router.Group("/mfa", authMiddleware.RequireAdmin())
+
+	mfa.Get("/policy", ah.GetMFAPolicy)
+	mfa.Put("/policy", ah.UpdateMFAPolicy)
+}
+
+// GetMFAPolicy retorna la política de 2FA del tenant
+// GET /api/mfa/policy
+func (ah *AuthHandlers) GetMFAPolicy(c *fiber.Ctx) error {
+	authContext, ok := GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	return c.JSON(MFAPolicyRequest{
+		RequireAdmin2FA: ah.mfaService.TenantRequiresAdminMFA(c.Context(), authContext.TenantID),
+	})
+}
+
+// UpdateMFAPolicy actualiza la política de 2FA del tenant
+// PUT /api/mfa/policy
+func (ah *AuthHandlers) UpdateMFAPolicy(c *fiber.Ctx) error {
+	authContext, ok := GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req MFAPolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := ah.mfaService.SetTenantPolicy(c.Context(), authContext.TenantID, req.RequireAdmin2FA); err != nil {
+		return err
+	}
+
+	return c.JSON(req)
+}
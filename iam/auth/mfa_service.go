@@ -0,0 +1,380 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/iam/user"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// maxMFAAttempts intentos de código permitidos por challenge
+const maxMFAAttempts = 5
+
+// MFAService gestiona el enrolamiento TOTP, los challenges de login y la
+// política de 2FA por tenant
+type MFAService struct {
+	mfaRepo          MFARepository
+	tenantConfigRepo tenant.TenantConfigRepository
+	cipher           SecretCipher
+	stateManager     StateManager
+	issuer           string
+}
+
+// NewMFAService crea una nueva instancia del servicio de 2FA
+func NewMFAService(
+	mfaRepo MFARepository,
+	tenantConfigRepo tenant.TenantConfigRepository,
+	cipher SecretCipher,
+	stateManager StateManager,
+	issuer string,
+) *MFAService {
+	if issuer == "" {
+		issuer = "Relay"
+	}
+
+	return &MFAService{
+		mfaRepo:          mfaRepo,
+		tenantConfigRepo: tenantConfigRepo,
+		cipher:           cipher,
+		stateManager:     stateManager,
+		issuer:           issuer,
+	}
+}
+
+// ============================================================================
+// Política
+// ============================================================================
+
+// TenantRequiresAdminMFA indica si el tenant exige 2FA a sus admins
+func (s *MFAService) TenantRequiresAdminMFA(ctx context.Context, tenantID kernel.TenantID) bool {
+	settings, err := s.tenantConfigRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return false
+	}
+	return settings[TenantSettingRequireAdminMFA] == "true"
+}
+
+// SetTenantPolicy activa o desactiva la exigencia de 2FA para admins
+func (s *MFAService) SetTenantPolicy(ctx context.Context, tenantID kernel.TenantID, requireAdminMFA bool) error {
+	return s.tenantConfigRepo.SaveSetting(ctx, tenantID, TenantSettingRequireAdminMFA, fmt.Sprint(requireAdminMFA))
+}
+
+// Requirement determina si el login necesita segundo factor y si además
+// el usuario debe enrolarse primero
+func (s *MFAService) Requirement(ctx context.Context, userID kernel.UserID, tenantID kernel.TenantID, isAdmin bool) (required bool, enrollment bool, err error) {
+	mfa, err := s.findMFA(ctx, userID)
+	if err != nil {
+		return false, false, err
+	}
+
+	if mfa != nil && mfa.Enabled {
+		return true, false, nil
+	}
+
+	if isAdmin && s.TenantRequiresAdminMFA(ctx, tenantID) {
+		return true, true, nil
+	}
+
+	return false, false, nil
+}
+
+// Status retorna el estado de 2FA del usuario
+func (s *MFAService) Status(ctx context.Context, userID kernel.UserID, tenantID kernel.TenantID, isAdmin bool) (MFAStatus, error) {
+	mfa, err := s.findMFA(ctx, userID)
+	if err != nil {
+		return MFAStatus{}, err
+	}
+
+	required := isAdmin && s.TenantRequiresAdminMFA(ctx, tenantID)
+	if mfa != nil && !mfa.Enabled {
+		mfa = nil // Enrolamiento sin confirmar
+	}
+
+	return mfa.Status(required), nil
+}
+
+// ============================================================================
+// Challenges de login
+// ============================================================================
+
+// CreateChallenge guarda el login pendiente y retorna su token opaco
+func (s *MFAService) CreateChallenge(ctx context.Context, challenge MFAChallenge) (string, error) {
+	token := s.stateManager.GenerateState()
+	if err := s.storeChallenge(ctx, token, challenge); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// GetChallenge lee un challenge sin consumirlo
+func (s *MFAService) GetChallenge(ctx context.Context, token string) (*MFAChallenge, error) {
+	challenge, err := s.takeChallenge(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storeChallenge(ctx, token, *challenge); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// CompleteChallenge valida el código del challenge. En éxito el challenge se
+// consume; en fallo se conserva hasta agotar los intentos.
+// Si el usuario tenía un enrolamiento pendiente, se confirma y se retornan
+// los códigos de respaldo.
+func (s *MFAService) CompleteChallenge(ctx context.Context, token, code string) (*MFAChallenge, []string, error) {
+	challenge, err := s.takeChallenge(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mfa, err := s.findMFA(ctx, challenge.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var backupCodes []string
+	switch {
+	case mfa == nil:
+		err = ErrMFANotEnrolled()
+	case !mfa.Enabled:
+		backupCodes, err = s.confirm(ctx, mfa, code)
+	default:
+		err = s.verify(ctx, mfa, code)
+	}
+
+	if err != nil {
+		// Sin enrolamiento aún no es un intento fallido: el usuario debe enrolarse
+		if !errx.IsCode(err, CodeMFANotEnrolled) {
+			challenge.Attempts++
+		}
+		if challenge.Attempts < maxMFAAttempts {
+			s.storeChallenge(ctx, token, *challenge)
+		}
+		return nil, nil, err
+	}
+
+	return challenge, backupCodes, nil
+}
+
+func (s *MFAService) storeChallenge(ctx context.Context, token string, challenge MFAChallenge) error {
+	data := map[string]any{
+		"type":      "mfa_challenge",
+		"user_id":   challenge.UserID.String(),
+		"tenant_id": challenge.TenantID.String(),
+		"amr":       challenge.AMR,
+		"attempts":  challenge.Attempts,
+	}
+	if err := s.stateManager.StoreState(ctx, token, data); err != nil {
+		return errx.Wrap(err, "failed to store mfa challenge", errx.TypeInternal)
+	}
+	return nil
+}
+
+func (s *MFAService) takeChallenge(ctx context.Context, token string) (*MFAChallenge, error) {
+	if token == "" {
+		return nil, ErrInvalidMFAToken()
+	}
+
+	data, err := s.stateManager.GetStateData(ctx, token)
+	if err != nil {
+		return nil, ErrInvalidMFAToken()
+	}
+
+	if t, _ := data["type"].(string); t != "mfa_challenge" {
+		return nil, ErrInvalidMFAToken()
+	}
+
+	userID, _ := data["user_id"].(string)
+	tenantID, _ := data["tenant_id"].(string)
+	attempts, _ := data["attempts"].(float64) // JSON decodifica números como float64
+
+	challenge := &MFAChallenge{
+		UserID:   kernel.NewUserID(userID),
+		TenantID: kernel.NewTenantID(tenantID),
+		Attempts: int(attempts),
+	}
+	switch amr := data["amr"].(type) {
+	case []any:
+		for _, m := range amr {
+			if method, ok := m.(string); ok {
+				challenge.AMR = append(challenge.AMR, method)
+			}
+		}
+	case []string:
+		challenge.AMR = amr
+	}
+
+	return challenge, nil
+}
+
+// ============================================================================
+// Enrolamiento y verificación
+// ============================================================================
+
+// BeginEnrollment genera un nuevo secreto (pendiente de confirmar)
+func (s *MFAService) BeginEnrollment(ctx context.Context, userEntity *user.User) (*MFAEnrollment, error) {
+	existing, err := s.findMFA(ctx, userEntity.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Enabled {
+		return nil, ErrMFAAlreadyEnabled()
+	}
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to generate totp secret", errx.TypeInternal)
+	}
+
+	encrypted, err := s.cipher.Encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	mfa := UserMFA{
+		UserID:          userEntity.ID,
+		SecretEncrypted: encrypted,
+		Enabled:         false,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := s.mfaRepo.Save(ctx, mfa); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollment{
+		Secret:     secret,
+		OTPAuthURI: TOTPProvisioningURI(s.issuer, userEntity.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment activa 2FA con el primer código válido
+func (s *MFAService) ConfirmEnrollment(ctx context.Context, userID kernel.UserID, code string) ([]string, error) {
+	mfa, err := s.findMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return nil, ErrMFANotEnrolled()
+	}
+	if mfa.Enabled {
+		return nil, ErrMFAAlreadyEnabled()
+	}
+
+	return s.confirm(ctx, mfa, code)
+}
+
+// VerifyCode valida un código TOTP o de respaldo de un usuario con 2FA activo
+func (s *MFAService) VerifyCode(ctx context.Context, userID kernel.UserID, code string) error {
+	mfa, err := s.findMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if mfa == nil || !mfa.Enabled {
+		return ErrMFANotEnrolled()
+	}
+
+	return s.verify(ctx, mfa, code)
+}
+
+// RegenerateBackupCodes reemplaza los códigos de respaldo (requiere código)
+func (s *MFAService) RegenerateBackupCodes(ctx context.Context, userID kernel.UserID, code string) ([]string, error) {
+	mfa, err := s.findMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil || !mfa.Enabled {
+		return nil, ErrMFANotEnrolled()
+	}
+
+	if err := s.verify(ctx, mfa, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateBackupCodes()
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to generate backup codes", errx.TypeInternal)
+	}
+
+	mfa.BackupCodes = hashes
+	mfa.UpdatedAt = time.Now()
+	if err := s.mfaRepo.Save(ctx, *mfa); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable desactiva 2FA (requiere código)
+func (s *MFAService) Disable(ctx context.Context, userID kernel.UserID, code string) error {
+	if err := s.VerifyCode(ctx, userID, code); err != nil {
+		return err
+	}
+	return s.mfaRepo.Delete(ctx, userID)
+}
+
+// confirm valida el primer código y activa 2FA
+func (s *MFAService) confirm(ctx context.Context, mfa *UserMFA, code string) ([]string, error) {
+	secret, err := s.cipher.Decrypt(mfa.SecretEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	step, ok := ValidateTOTP(secret, code, time.Now(), mfa.LastUsedStep)
+	if !ok {
+		return nil, ErrInvalidMFACode()
+	}
+
+	codes, hashes, err := generateBackupCodes()
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to generate backup codes", errx.TypeInternal)
+	}
+
+	mfa.Enable(step, hashes)
+	if err := s.mfaRepo.Save(ctx, *mfa); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// verify acepta un código TOTP o consume un código de respaldo
+func (s *MFAService) verify(ctx context.Context, mfa *UserMFA, code string) error {
+	secret, err := s.cipher.Decrypt(mfa.SecretEncrypted)
+	if err != nil {
+		return err
+	}
+
+	if step, ok := ValidateTOTP(secret, code, time.Now(), mfa.LastUsedStep); ok {
+		mfa.LastUsedStep = step
+		mfa.UpdatedAt = time.Now()
+		return s.mfaRepo.Save(ctx, *mfa)
+	}
+
+	if mfa.ConsumeBackupCode(code) {
+		return s.mfaRepo.Save(ctx, *mfa)
+	}
+
+	return ErrInvalidMFACode()
+}
+
+// findMFA retorna nil si el usuario no tiene 2FA configurado
+func (s *MFAService) findMFA(ctx context.Context, userID kernel.UserID) (*UserMFA, error) {
+	mfa, err := s.mfaRepo.FindByUser(ctx, userID)
+	if err != nil {
+		if errx.IsCode(err, CodeMFANotEnrolled) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return mfa, nil
+}
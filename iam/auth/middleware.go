@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"strings"
 
 	"github.com/Abraxas-365/relay/iam"
@@ -10,13 +11,15 @@ import (
 
 // AuthMiddleware middleware para autenticación JWT con Fiber
 type AuthMiddleware struct {
-	tokenService TokenService
+	tokenService     TokenService
+	sessionValidator SessionValidator // Opcional: rechaza tokens de sesiones revocadas
 }
 
 // NewAuthMiddleware crea un nuevo middleware de autenticación
-func NewAuthMiddleware(tokenService TokenService) *AuthMiddleware {
+func NewAuthMiddleware(tokenService TokenService, sessionValidator SessionValidator) *AuthMiddleware {
 	return &AuthMiddleware{
-		tokenService: tokenService,
+		tokenService:     tokenService,
+		sessionValidator: sessionValidator,
 	}
 }
 
@@ -59,13 +62,22 @@ func (am *AuthMiddleware) Authenticate() fiber.Handler {
 			})
 		}
 
+		// Verificar que la sesión que respalda el token no haya sido revocada
+		if err := checkSession(c.Context(), am.sessionValidator, claims.SessionID); err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
 		// Crear contexto de autenticación
 		authContext := &kernel.AuthContext{
-			UserID:   claims.UserID,
-			TenantID: claims.TenantID,
-			IsAdmin:  claims.IsAdmin,
-			Email:    claims.Email,
-			Name:     claims.Name,
+			UserID:    claims.UserID,
+			TenantID:  claims.TenantID,
+			IsAdmin:   claims.IsAdmin,
+			Email:     claims.Email,
+			Name:      claims.Name,
+			AMR:       claims.AMR,
+			SessionID: claims.SessionID,
 		}
 
 		// Agregar al contexto de Fiber
@@ -120,3 +132,21 @@ func GetAuthContext(c *fiber.Ctx) (*kernel.AuthContext, bool) {
 	authContext, ok := c.Locals("auth").(*kernel.AuthContext)
 	return authContext, ok && authContext != nil && authContext.IsValid()
 }
+
+// checkSession valida la sesión de un token. Los tokens emitidos antes del
+// claim de sesión no la traen y se aceptan hasta que expiren
+func checkSession(ctx context.Context, validator SessionValidator, sessionID string) error {
+	if validator == nil || sessionID == "" {
+		return nil
+	}
+
+	active, err := validator.IsActive(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return ErrSessionRevoked()
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// discoveryCacheTTL tiempo que se reutiliza un discovery document
+const discoveryCacheTTL = time.Hour
+
+// OIDCDiscoveryDocument subconjunto del documento .well-known/openid-configuration
+type OIDCDiscoveryDocument struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	UserInfoEndpoint      string   `json:"userinfo_endpoint"`
+	ScopesSupported       []string `json:"scopes_supported"`
+}
+
+type cachedDiscovery struct {
+	doc       *OIDCDiscoveryDocument
+	fetchedAt time.Time
+}
+
+// OIDCClient cliente OIDC genérico; la configuración llega por tenant
+type OIDCClient struct {
+	httpClient  *http.Client
+	redirectURL string
+
+	mu    sync.RWMutex
+	cache map[string]cachedDiscovery
+}
+
+// NewOIDCClient crea un cliente OIDC con la URL de callback del SP
+func NewOIDCClient(redirectURL string) *OIDCClient {
+	return &OIDCClient{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		redirectURL: redirectURL,
+		cache:       make(map[string]cachedDiscovery),
+	}
+}
+
+// Discover obtiene (y cachea) el discovery document del issuer
+func (o *OIDCClient) Discover(ctx context.Context, issuerURL string) (*OIDCDiscoveryDocument, error) {
+	issuer := strings.TrimSuffix(issuerURL, "/")
+
+	o.mu.RLock()
+	cached, ok := o.cache[issuer]
+	o.mu.RUnlock()
+	if ok && time.Since(cached.fetchedAt) < discoveryCacheTTL {
+		return cached.doc, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to create discovery request", errx.TypeInternal)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to fetch discovery document", errx.TypeExternal).
+			WithDetail("issuer", issuer)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrOAuthAuthorizationFailed().
+			WithDetail("status_code", resp.StatusCode).
+			WithDetail("issuer", issuer).
+			WithDetail("endpoint", "discovery")
+	}
+
+	var doc OIDCDiscoveryDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, errx.Wrap(err, "failed to decode discovery document", errx.TypeExternal)
+	}
+
+	// El issuer del documento debe coincidir con el configurado
+	if strings.TrimSuffix(doc.Issuer, "/") != issuer {
+		return nil, ErrInvalidSSOConfig().
+			WithDetail("reason", "issuer mismatch").
+			WithDetail("expected", issuer).
+			WithDetail("actual", doc.Issuer)
+	}
+
+	o.mu.Lock()
+	o.cache[issuer] = cachedDiscovery{doc: &doc, fetchedAt: time.Now()}
+	o.mu.Unlock()
+
+	return &doc, nil
+}
+
+// GetAuthURL genera la URL de autorización del IdP del tenant
+func (o *OIDCClient) GetAuthURL(ctx context.Context, config *SSOConfig, state, nonce string) (string, error) {
+	doc, err := o.Discover(ctx, config.IssuerURL)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"client_id":     {config.ClientID},
+		"redirect_uri":  {o.redirectURL},
+		"scope":         {strings.Join(o.scopes(doc, config), " ")},
+		"response_type": {"code"},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+
+	separator := "?"
+	if strings.Contains(doc.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return doc.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// ExchangeToken intercambia el código de autorización por tokens
+func (o *OIDCClient) ExchangeToken(ctx context.Context, config *SSOConfig, code string) (*OAuthTokenResponse, error) {
+	doc, err := o.Discover(ctx, config.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	data := url.Values{
+		"client_id":     {config.ClientID},
+		"client_secret": {config.ClientSecret},
+		"code":          {code},
+		"grant_type":    {"authorization_code"},
+		"redirect_uri":  {o.redirectURL},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", doc.TokenEndpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to create token request", errx.TypeInternal)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to exchange token", errx.TypeExternal)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrOAuthAuthorizationFailed().
+			WithDetail("status_code", resp.StatusCode).
+			WithDetail("provider", "oidc").
+			WithDetail("issuer", config.IssuerURL)
+	}
+
+	var tokenResp OAuthTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return nil, errx.Wrap(err, "failed to decode token response", errx.TypeExternal)
+	}
+
+	return &tokenResp, nil
+}
+
+// GetIdentity obtiene la identidad del usuario desde el userinfo endpoint.
+// Se usa userinfo (y no el id_token) porque la respuesta viene directamente
+// del IdP sobre TLS con el access token recién emitido.
+func (o *OIDCClient) GetIdentity(ctx context.Context, config *SSOConfig, accessToken string) (*SSOIdentity, error) {
+	doc, err := o.Discover(ctx, config.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.UserInfoEndpoint == "" {
+		return nil, ErrInvalidSSOConfig().WithDetail("reason", "issuer has no userinfo_endpoint")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", doc.UserInfoEndpoint, nil)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to create user info request", errx.TypeInternal)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to get user info", errx.TypeExternal)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrOAuthAuthorizationFailed().
+			WithDetail("status_code", resp.StatusCode).
+			WithDetail("provider", "oidc").
+			WithDetail("endpoint", "userinfo")
+	}
+
+	var claims map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&claims); err != nil {
+		return nil, errx.Wrap(err, "failed to decode user info", errx.TypeExternal)
+	}
+
+	identity := &SSOIdentity{
+		Subject:       stringClaim(claims, "sub"),
+		Email:         stringClaim(claims, "email"),
+		Name:          stringClaim(claims, "name"),
+		EmailVerified: boolClaim(claims, "email_verified"),
+		Groups:        stringListClaim(claims, config.GetGroupsClaim()),
+	}
+
+	if identity.Subject == "" || identity.Email == "" {
+		return nil, ErrSSOAssertionInvalid().WithDetail("reason", "sub and email claims are required")
+	}
+
+	return identity, nil
+}
+
+// scopes retorna los scopes a pedir; groups solo si el IdP lo anuncia
+func (o *OIDCClient) scopes(doc *OIDCDiscoveryDocument, config *SSOConfig) []string {
+	scopes := []string{"openid", "email", "profile"}
+	for _, s := range doc.ScopesSupported {
+		if s == config.GetGroupsClaim() {
+			scopes = append(scopes, s)
+			break
+		}
+	}
+	return scopes
+}
+
+// ============================================================================
+// Claim helpers
+// ============================================================================
+
+func stringClaim(claims map[string]any, key string) string {
+	if v, ok := claims[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func boolClaim(claims map[string]any, key string) bool {
+	switch v := claims[key].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+// stringListClaim acepta arrays o strings separados por comas/espacios
+func stringListClaim(claims map[string]any, key string) []string {
+	switch v := claims[key].(type) {
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+		return values
+	case string:
+		return strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+	return nil
+}
@@ -0,0 +1,486 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/iam/user"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TenantSettingPasswordSignup clave de tenant_settings que permite a
+// cualquiera registrarse con contraseña en el tenant
+const TenantSettingPasswordSignup = "auth.password_signup"
+
+// RegisterRequest estructura para registro con email/contraseña
+type RegisterRequest struct {
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	Name      string `json:"name"`
+	TenantRUC string `json:"tenant_ruc"`
+}
+
+// SignupPolicyRequest política de registro con contraseña del tenant
+type SignupPolicyRequest struct {
+	PasswordSignup bool `json:"password_signup"`
+}
+
+// PasswordLoginRequest estructura para login con email/contraseña
+type PasswordLoginRequest struct {
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	TenantRUC string `json:"tenant_ruc,omitempty"`
+}
+
+// ForgotPasswordRequest estructura para solicitar reset de contraseña
+type ForgotPasswordRequest struct {
+	Email     string `json:"email"`
+	TenantRUC string `json:"tenant_ruc,omitempty"`
+}
+
+// ResetPasswordRequest estructura para resetear la contraseña
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// VerifyEmailRequest estructura para verificar el email
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// Register crea un usuario con email/contraseña pendiente de verificación
+func (ah *AuthHandlers) Register(c *fiber.Ctx) error {
+	var req RegisterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	email := normalizeEmail(req.Email)
+	if email == "" || strings.TrimSpace(req.Name) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "email and name are required",
+		})
+	}
+
+	if err := ah.validatePassword(req.Password); err != nil {
+		return err
+	}
+
+	// Solo en tenants que aceptan registros abiertos; nunca en uno por defecto
+	if strings.TrimSpace(req.TenantRUC) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "tenant_ruc is required",
+		})
+	}
+	tenantEntity, err := ah.tenantRepo.FindByRUC(c.Context(), strings.TrimSpace(req.TenantRUC))
+	if err != nil {
+		return tenant.ErrTenantNotFound()
+	}
+	if !ah.passwordSignupEnabled(c.Context(), tenantEntity.ID) {
+		return ErrSignupDisabled()
+	}
+
+	// Nunca adjuntar contraseña a una cuenta existente (ej. OAuth)
+	exists, err := ah.userRepo.ExistsByEmail(c.Context(), email, tenantEntity.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return user.ErrUserAlreadyExists().WithDetail("email", email)
+	}
+
+	if !tenantEntity.CanAddUser() {
+		return tenant.ErrMaxUsersReached()
+	}
+
+	hash, err := ah.passwordService.HashPassword(req.Password)
+	if err != nil {
+		return ErrTokenGenerationFailed().WithDetail("reason", "failed to hash password")
+	}
+
+	userID := kernel.NewUserID(generateID())
+	newUser := &user.User{
+		ID:              userID,
+		TenantID:        tenantEntity.ID,
+		Email:           email,
+		Name:            strings.TrimSpace(req.Name),
+		Status:          user.UserStatusPending,
+		OAuthProvider:   iam.OAuthProviderPassword,
+		OAuthProviderID: userID.String(),
+		EmailVerified:   false,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	newUser.SetPassword(hash)
+
+	if err := ah.userRepo.Save(c.Context(), *newUser); err != nil {
+		return err
+	}
+
+	// Incrementar contador de usuarios del tenant
+	if err := tenantEntity.AddUser(); err != nil {
+		ah.userRepo.Delete(c.Context(), newUser.ID, tenantEntity.ID)
+		return err
+	}
+	if err := ah.tenantRepo.Save(c.Context(), *tenantEntity); err != nil {
+		log.Printf("⚠️  Failed to update tenant user count: %v", err)
+	}
+
+	if err := ah.sendVerificationEmail(c.Context(), newUser); err != nil {
+		log.Printf("⚠️  Failed to send verification email to %s: %v", newUser.Email, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Registration successful, check your email to verify your account",
+		"user":    newUser.ToDTO(),
+	})
+}
+
+// PasswordLogin autentica con email/contraseña y emite tokens
+func (ah *AuthHandlers) PasswordLogin(c *fiber.Ctx) error {
+	var req PasswordLoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	email := normalizeEmail(req.Email)
+	if email == "" || req.Password == "" {
+		return ErrInvalidCredentials()
+	}
+
+	// Rate limiting por IP + email
+	limiterKey := c.IP() + ":" + email
+	if ah.rateLimiter != nil {
+		allowed, retryAfter, err := ah.rateLimiter.Allow(c.Context(), limiterKey)
+		if err != nil {
+			log.Printf("⚠️  Login rate limiter unavailable: %v", err)
+		} else if !allowed {
+			c.Set(fiber.HeaderRetryAfter, formatSeconds(retryAfter))
+			return ErrTooManyLoginAttempts().WithDetail("retry_after_seconds", int(retryAfter.Seconds()))
+		}
+	}
+
+	tenantEntity, err := ah.resolveTenant(c.Context(), req.TenantRUC)
+	if err != nil {
+		return ErrInvalidCredentials()
+	}
+
+	userEntity, err := ah.userRepo.FindByEmail(c.Context(), email, tenantEntity.ID)
+	if err != nil {
+		return ErrInvalidCredentials()
+	}
+
+	if userEntity.IsLocked() {
+		return ErrAccountLocked().WithDetail("locked_until", userEntity.LockedUntil)
+	}
+
+	if !userEntity.HasPassword() || !ah.passwordService.VerifyPassword(*userEntity.PasswordHash, req.Password) {
+		userEntity.RegisterFailedLogin(ah.passwordConfig.MaxFailedAttempts, ah.passwordConfig.LockoutDuration)
+		if err := ah.userRepo.Save(c.Context(), *userEntity); err != nil {
+			log.Printf("⚠️  Failed to record failed login for %s: %v", userEntity.ID, err)
+		}
+		if userEntity.IsLocked() {
+			return ErrAccountLocked().WithDetail("locked_until", userEntity.LockedUntil)
+		}
+		return ErrInvalidCredentials()
+	}
+
+	if !userEntity.EmailVerified {
+		return user.ErrEmailNotVerified()
+	}
+	if !userEntity.CanLogin() {
+		return user.ErrUserSuspended()
+	}
+
+	if !tenantEntity.IsActive() {
+		return tenant.ErrTenantSuspended()
+	}
+
+	userEntity.ResetFailedLogins()
+
+	response, err := ah.completeLogin(c, userEntity, tenantEntity, []string{AMRPassword})
+	if err != nil {
+		return ErrTokenGenerationFailed().WithCause(err)
+	}
+
+	if ah.rateLimiter != nil {
+		if err := ah.rateLimiter.Reset(c.Context(), limiterKey); err != nil {
+			log.Printf("⚠️  Failed to reset login rate limiter: %v", err)
+		}
+	}
+
+	return c.JSON(response)
+}
+
+// VerifyEmail verifica el email usando el token enviado por correo
+func (ah *AuthHandlers) VerifyEmail(c *fiber.Ctx) error {
+	tokenValue := c.Query("token")
+	if tokenValue == "" {
+		var req VerifyEmailRequest
+		if err := c.BodyParser(&req); err == nil {
+			tokenValue = req.Token
+		}
+	}
+	if tokenValue == "" {
+		return ErrInvalidVerificationToken()
+	}
+
+	token, err := ah.verificationRepo.FindVerificationToken(c.Context(), tokenValue)
+	if err != nil || !token.IsValid() {
+		return ErrInvalidVerificationToken()
+	}
+
+	userEntity, err := ah.userRepo.FindByID(c.Context(), token.UserID, token.TenantID)
+	if err != nil {
+		return ErrInvalidVerificationToken()
+	}
+
+	if err := ah.verificationRepo.ConsumeVerificationToken(c.Context(), tokenValue); err != nil {
+		return ErrInvalidVerificationToken()
+	}
+
+	userEntity.VerifyEmail()
+	if err := ah.userRepo.Save(c.Context(), *userEntity); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Email verified successfully",
+	})
+}
+
+// ForgotPassword envía un enlace de reset. Siempre responde igual para no
+// revelar qué emails están registrados.
+func (ah *AuthHandlers) ForgotPassword(c *fiber.Ctx) error {
+	var req ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	response := fiber.Map{
+		"message": "If the email is registered, a reset link has been sent",
+	}
+
+	email := normalizeEmail(req.Email)
+	if email == "" {
+		return c.JSON(response)
+	}
+
+	tenantEntity, err := ah.resolveTenant(c.Context(), req.TenantRUC)
+	if err != nil {
+		return c.JSON(response)
+	}
+
+	userEntity, err := ah.userRepo.FindByEmail(c.Context(), email, tenantEntity.ID)
+	if err != nil || userEntity.Status == user.UserStatusSuspended {
+		return c.JSON(response)
+	}
+
+	tokenValue, err := generateSecureToken()
+	if err != nil {
+		log.Printf("⚠️  Failed to generate reset token: %v", err)
+		return c.JSON(response)
+	}
+
+	resetToken := PasswordResetToken{
+		ID:        generateID(),
+		Token:     tokenValue,
+		UserID:    userEntity.ID,
+		TenantID:  userEntity.TenantID,
+		ExpiresAt: time.Now().Add(ah.passwordConfig.ResetTokenTTL),
+		CreatedAt: time.Now(),
+	}
+
+	if err := ah.resetRepo.SaveResetToken(c.Context(), resetToken); err != nil {
+		log.Printf("⚠️  Failed to save reset token for %s: %v", userEntity.ID, err)
+		return c.JSON(response)
+	}
+
+	if err := ah.mailer.SendPasswordResetEmail(c.Context(), userEntity.Email, userEntity.Name, tokenValue); err != nil {
+		log.Printf("⚠️  Failed to send reset email to %s: %v", userEntity.Email, err)
+	}
+
+	return c.JSON(response)
+}
+
+// ResetPassword cambia la contraseña usando un token de reset
+func (ah *AuthHandlers) ResetPassword(c *fiber.Ctx) error {
+	var req ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.Token == "" {
+		return ErrInvalidResetToken()
+	}
+
+	if err := ah.validatePassword(req.NewPassword); err != nil {
+		return err
+	}
+
+	token, err := ah.resetRepo.FindResetToken(c.Context(), req.Token)
+	if err != nil || !token.IsValid() {
+		return ErrInvalidResetToken()
+	}
+
+	userEntity, err := ah.userRepo.FindByID(c.Context(), token.UserID, token.TenantID)
+	if err != nil {
+		return ErrInvalidResetToken()
+	}
+
+	if err := ah.resetRepo.ConsumeResetToken(c.Context(), req.Token); err != nil {
+		return ErrInvalidResetToken()
+	}
+
+	hash, err := ah.passwordService.HashPassword(req.NewPassword)
+	if err != nil {
+		return ErrTokenGenerationFailed().WithDetail("reason", "failed to hash password")
+	}
+
+	userEntity.SetPassword(hash)
+
+	// El enlace llegó al email, por lo que queda verificado
+	if !userEntity.EmailVerified {
+		userEntity.VerifyEmail()
+	}
+
+	if err := ah.userRepo.Save(c.Context(), *userEntity); err != nil {
+		return err
+	}
+
+	// Cerrar todas las sesiones existentes
+	if err := ah.tokenRepo.RevokeAllUserTokens(c.Context(), userEntity.ID); err != nil {
+		log.Printf("⚠️  Failed to revoke tokens for %s: %v", userEntity.ID, err)
+	}
+	if err := ah.revokeAllSessions(c.Context(), userEntity.ID); err != nil {
+		log.Printf("⚠️  Failed to revoke sessions for %s: %v", userEntity.ID, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Password reset successfully",
+	})
+}
+
+// ============================================================================
+// Política de registro del tenant (admin)
+// ============================================================================
+
+// GetSignupPolicy retorna la política de registro con contraseña del tenant
+// GET /api/signup/policy
+func (ah *AuthHandlers) GetSignupPolicy(c *fiber.Ctx) error {
+	authContext, ok := GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	return c.JSON(SignupPolicyRequest{
+		PasswordSignup: ah.passwordSignupEnabled(c.Context(), authContext.TenantID),
+	})
+}
+
+// UpdateSignupPolicy abre o cierra el registro con contraseña en el tenant
+// PUT /api/signup/policy
+func (ah *AuthHandlers) UpdateSignupPolicy(c *fiber.Ctx) error {
+	authContext, ok := GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req SignupPolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := ah.tenantConfigRepo.SaveSetting(c.Context(), authContext.TenantID, TenantSettingPasswordSignup, strconv.FormatBool(req.PasswordSignup)); err != nil {
+		return err
+	}
+
+	return c.JSON(req)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// passwordSignupEnabled indica si el tenant permite registrarse con contraseña
+func (ah *AuthHandlers) passwordSignupEnabled(ctx context.Context, tenantID kernel.TenantID) bool {
+	if ah.tenantConfigRepo == nil {
+		return false
+	}
+	settings, err := ah.tenantConfigRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return false
+	}
+	return settings[TenantSettingPasswordSignup] == "true"
+}
+
+// validatePassword valida los requisitos mínimos de la contraseña
+func (ah *AuthHandlers) validatePassword(password string) error {
+	if len(password) < ah.passwordConfig.MinLength {
+		return ErrWeakPassword().WithDetail("min_length", ah.passwordConfig.MinLength)
+	}
+	// bcrypt ignora todo después de 72 bytes
+	if len(password) > 72 {
+		return ErrWeakPassword().WithDetail("max_length", 72)
+	}
+	return nil
+}
+
+// sendVerificationEmail genera un token de verificación y lo envía
+func (ah *AuthHandlers) sendVerificationEmail(ctx context.Context, userEntity *user.User) error {
+	tokenValue, err := generateSecureToken()
+	if err != nil {
+		return err
+	}
+
+	token := EmailVerificationToken{
+		ID:        generateID(),
+		Token:     tokenValue,
+		UserID:    userEntity.ID,
+		TenantID:  userEntity.TenantID,
+		ExpiresAt: time.Now().Add(ah.passwordConfig.VerificationTokenTTL),
+		CreatedAt: time.Now(),
+	}
+
+	if err := ah.verificationRepo.SaveVerificationToken(ctx, token); err != nil {
+		return err
+	}
+
+	return ah.mailer.SendVerificationEmail(ctx, userEntity.Email, userEntity.Name, tokenValue)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.Itoa(int(d.Seconds()))
+}
+
+// generateSecureToken genera un token aleatorio de 256 bits
+func generateSecureToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"time"
 
 	"github.com/Abraxas-365/relay/pkg/kernel"
 )
@@ -12,6 +13,7 @@ type TokenRepository interface {
 	FindRefreshToken(ctx context.Context, tokenValue string) (*RefreshToken, error)
 	RevokeRefreshToken(ctx context.Context, tokenValue string) error
 	RevokeAllUserTokens(ctx context.Context, userID kernel.UserID) error
+	RevokeAllTenantTokens(ctx context.Context, tenantID kernel.TenantID) error
 	CleanExpiredTokens(ctx context.Context) error
 }
 
@@ -23,9 +25,17 @@ type SessionRepository interface {
 	UpdateSessionActivity(ctx context.Context, sessionID string) error
 	RevokeSession(ctx context.Context, sessionID string) error
 	RevokeAllUserSessions(ctx context.Context, userID kernel.UserID) error
+	FindTenantSessions(ctx context.Context, tenantID kernel.TenantID) ([]*UserSession, error)
+	RevokeAllTenantSessions(ctx context.Context, tenantID kernel.TenantID) error
 	CleanExpiredSessions(ctx context.Context) error
 }
 
+// SessionValidator verifica que la sesión que respalda un access token siga activa
+type SessionValidator interface {
+	IsActive(ctx context.Context, sessionID string) (bool, error)
+	Invalidate(ctx context.Context, sessionID string) error
+}
+
 // PasswordResetRepository define el contrato para tokens de reset de contraseña
 type PasswordResetRepository interface {
 	SaveResetToken(ctx context.Context, token PasswordResetToken) error
@@ -34,6 +44,46 @@ type PasswordResetRepository interface {
 	CleanExpiredResetTokens(ctx context.Context) error
 }
 
+// EmailVerificationRepository define el contrato para tokens de verificación de email
+type EmailVerificationRepository interface {
+	SaveVerificationToken(ctx context.Context, token EmailVerificationToken) error
+	FindVerificationToken(ctx context.Context, tokenValue string) (*EmailVerificationToken, error)
+	ConsumeVerificationToken(ctx context.Context, tokenValue string) error
+}
+
+// SSOConfigRepository define el contrato para la configuración de SSO por tenant
+type SSOConfigRepository interface {
+	FindByTenant(ctx context.Context, tenantID kernel.TenantID) (*SSOConfig, error)
+	Save(ctx context.Context, config SSOConfig) error
+	Delete(ctx context.Context, tenantID kernel.TenantID) error
+}
+
+// MFARepository define el contrato para la configuración TOTP de usuarios
+type MFARepository interface {
+	FindByUser(ctx context.Context, userID kernel.UserID) (*UserMFA, error)
+	Save(ctx context.Context, mfa UserMFA) error
+	Delete(ctx context.Context, userID kernel.UserID) error
+}
+
+// SecretCipher cifra secretos que deben poder recuperarse (ej. semillas TOTP)
+type SecretCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+// LoginRateLimiter limita los intentos de login por clave (IP + email)
+type LoginRateLimiter interface {
+	// Allow registra un intento y retorna false con el tiempo de espera si se excedió el límite
+	Allow(ctx context.Context, key string) (bool, time.Duration, error)
+	Reset(ctx context.Context, key string) error
+}
+
+// AuthMailer envía los emails transaccionales de autenticación
+type AuthMailer interface {
+	SendVerificationEmail(ctx context.Context, email, name, token string) error
+	SendPasswordResetEmail(ctx context.Context, email, name, token string) error
+}
+
 // TokenService define el contrato para el manejo de tokens JWT
 type TokenService interface {
 	GenerateAccessToken(userID kernel.UserID, tenantID kernel.TenantID, claims map[string]any) (string, error)
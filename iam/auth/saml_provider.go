@@ -0,0 +1,325 @@
+package auth
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/google/uuid"
+)
+
+const (
+	nsSAMLAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	nsSAMLProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+
+	samlStatusSuccess      = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	samlBearerConfirmation = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	samlBindingPOST        = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	samlNameIDEmail        = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+
+	// samlClockSkew tolerancia de reloj con el IdP
+	samlClockSkew = 2 * time.Minute
+)
+
+// Nombres de atributo habituales (Okta, Azure AD/ADFS, OID LDAP)
+var (
+	samlEmailAttributes = []string{
+		"email", "mail", "emailaddress",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress",
+		"urn:oid:0.9.2342.19200300.100.1.3",
+	}
+	samlNameAttributes = []string{
+		"name", "displayname",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/name",
+		"urn:oid:2.16.840.1.113730.3.1.241",
+	}
+)
+
+// SAMLServiceProvider SP SAML 2.0 (HTTP-Redirect para AuthnRequest y
+// HTTP-POST para la respuesta). Un único SP sirve a todos los tenants.
+type SAMLServiceProvider struct {
+	entityID string
+	acsURL   string
+}
+
+// NewSAMLServiceProvider crea el SP con su entity ID y la URL del ACS
+func NewSAMLServiceProvider(entityID, acsURL string) *SAMLServiceProvider {
+	return &SAMLServiceProvider{
+		entityID: entityID,
+		acsURL:   acsURL,
+	}
+}
+
+// BuildAuthnRequestURL genera la URL de redirección al IdP y el ID de la
+// solicitud, que debe volver como InResponseTo
+func (sp *SAMLServiceProvider) BuildAuthnRequestURL(config *SSOConfig, relayState string) (string, string, error) {
+	requestID := "_" + uuid.NewString()
+
+	var request bytes.Buffer
+	request.WriteString(`<samlp:AuthnRequest xmlns:samlp="` + nsSAMLProtocol + `" xmlns:saml="` + nsSAMLAssertion + `"`)
+	writeXMLAttr(&request, "ID", requestID)
+	request.WriteString(` Version="2.0"`)
+	writeXMLAttr(&request, "IssueInstant", time.Now().UTC().Format(time.RFC3339))
+	writeXMLAttr(&request, "Destination", config.IdPSSOURL)
+	writeXMLAttr(&request, "AssertionConsumerServiceURL", sp.acsURL)
+	writeXMLAttr(&request, "ProtocolBinding", samlBindingPOST)
+	request.WriteString(`><saml:Issuer>`)
+	xml.EscapeText(&request, []byte(sp.entityID))
+	request.WriteString(`</saml:Issuer><samlp:NameIDPolicy AllowCreate="true"`)
+	writeXMLAttr(&request, "Format", samlNameIDEmail)
+	request.WriteString(`/></samlp:AuthnRequest>`)
+
+	// HTTP-Redirect binding: DEFLATE + base64 + URL encode
+	var compressed bytes.Buffer
+	writer, err := flate.NewWriter(&compressed, flate.DefaultCompression)
+	if err != nil {
+		return "", "", errx.Wrap(err, "failed to create deflate writer", errx.TypeInternal)
+	}
+	if _, err := writer.Write(request.Bytes()); err != nil {
+		return "", "", errx.Wrap(err, "failed to deflate authn request", errx.TypeInternal)
+	}
+	if err := writer.Close(); err != nil {
+		return "", "", errx.Wrap(err, "failed to deflate authn request", errx.TypeInternal)
+	}
+
+	params := url.Values{
+		"SAMLRequest": {base64.StdEncoding.EncodeToString(compressed.Bytes())},
+		"RelayState":  {relayState},
+	}
+
+	separator := "?"
+	if strings.Contains(config.IdPSSOURL, "?") {
+		separator = "&"
+	}
+
+	return config.IdPSSOURL + separator + params.Encode(), requestID, nil
+}
+
+// ParseResponse valida un SAMLResponse (HTTP-POST) y extrae la identidad.
+// Solo se leen datos del elemento cuya firma fue verificada.
+func (sp *SAMLServiceProvider) ParseResponse(config *SSOConfig, encodedResponse, expectedRequestID string) (*SSOIdentity, error) {
+	cert, err := parseIdPCertificate(config.IdPCertificate)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(compactBase64(encodedResponse))
+	if err != nil {
+		return nil, ErrSSOAssertionInvalid().WithDetail("reason", "SAMLResponse is not base64")
+	}
+
+	root, err := parseXMLTree(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if !root.is(nsSAMLProtocol, "Response") {
+		return nil, ErrSSOAssertionInvalid().WithDetail("reason", "root element is not a SAML Response")
+	}
+
+	if root.child(nsSAMLAssertion, "EncryptedAssertion") != nil {
+		return nil, ErrSSOAssertionInvalid().WithDetail("reason", "encrypted assertions are not supported")
+	}
+
+	assertions := root.childrenNamed(nsSAMLAssertion, "Assertion")
+	if len(assertions) != 1 {
+		return nil, ErrSSOAssertionInvalid().WithDetail("reason", "response must contain exactly one assertion")
+	}
+	assertion := assertions[0]
+
+	// Se acepta firma en el Response o en la Assertion
+	if root.child(nsXMLDSig, "Signature") != nil {
+		err = verifyEnvelopedSignature(root, root, cert)
+	} else {
+		err = verifyEnvelopedSignature(root, assertion, cert)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sp.validateResponse(root, config, expectedRequestID); err != nil {
+		return nil, err
+	}
+
+	if err := sp.validateAssertion(assertion, config, expectedRequestID); err != nil {
+		return nil, err
+	}
+
+	return sp.extractIdentity(assertion, config)
+}
+
+// Metadata genera el metadata XML del SP para registrarlo en el IdP
+func (sp *SAMLServiceProvider) Metadata() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata"`)
+	writeXMLAttr(&buf, "entityID", sp.entityID)
+	buf.WriteString(`><md:SPSSODescriptor AuthnRequestsSigned="false" WantAssertionsSigned="true" protocolSupportEnumeration="` + nsSAMLProtocol + `">`)
+	buf.WriteString(`<md:NameIDFormat>` + samlNameIDEmail + `</md:NameIDFormat>`)
+	buf.WriteString(`<md:AssertionConsumerService index="0" isDefault="true"`)
+	writeXMLAttr(&buf, "Binding", samlBindingPOST)
+	writeXMLAttr(&buf, "Location", sp.acsURL)
+	buf.WriteString(`/></md:SPSSODescriptor></md:EntityDescriptor>`)
+	return buf.Bytes()
+}
+
+// ============================================================================
+// Validaciones
+// ============================================================================
+
+func (sp *SAMLServiceProvider) validateResponse(response *xmlNode, config *SSOConfig, expectedRequestID string) error {
+	status := response.child(nsSAMLProtocol, "Status")
+	if status == nil {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "missing status")
+	}
+	if code := status.child(nsSAMLProtocol, "StatusCode"); code == nil || code.attr("Value") != samlStatusSuccess {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "idp returned a non-success status")
+	}
+
+	if destination := response.attr("Destination"); destination != "" && destination != sp.acsURL {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "destination mismatch")
+	}
+
+	if inResponseTo := response.attr("InResponseTo"); inResponseTo != "" && inResponseTo != expectedRequestID {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "response does not match the authn request")
+	}
+
+	if issuer := response.child(nsSAMLAssertion, "Issuer"); issuer != nil && issuer.text() != config.IdPEntityID {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "issuer mismatch")
+	}
+
+	return nil
+}
+
+func (sp *SAMLServiceProvider) validateAssertion(assertion *xmlNode, config *SSOConfig, expectedRequestID string) error {
+	now := time.Now()
+
+	if assertion.child(nsSAMLAssertion, "Issuer").text() != config.IdPEntityID {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "assertion issuer mismatch")
+	}
+
+	conditions := assertion.child(nsSAMLAssertion, "Conditions")
+	if conditions == nil {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "missing conditions")
+	}
+	if err := checkTimeWindow(conditions.attr("NotBefore"), conditions.attr("NotOnOrAfter"), now); err != nil {
+		return err
+	}
+
+	audienceOK := false
+	for _, restriction := range conditions.childrenNamed(nsSAMLAssertion, "AudienceRestriction") {
+		for _, audience := range restriction.childrenNamed(nsSAMLAssertion, "Audience") {
+			if audience.text() == sp.entityID {
+				audienceOK = true
+			}
+		}
+	}
+	if !audienceOK {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "audience mismatch")
+	}
+
+	subject := assertion.child(nsSAMLAssertion, "Subject")
+	if subject == nil {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "missing subject")
+	}
+
+	for _, confirmation := range subject.childrenNamed(nsSAMLAssertion, "SubjectConfirmation") {
+		if confirmation.attr("Method") != samlBearerConfirmation {
+			continue
+		}
+		data := confirmation.child(nsSAMLAssertion, "SubjectConfirmationData")
+		if data == nil {
+			continue
+		}
+		if data.attr("Recipient") != sp.acsURL {
+			continue
+		}
+		if inResponseTo := data.attr("InResponseTo"); inResponseTo != "" && inResponseTo != expectedRequestID {
+			continue
+		}
+		if checkTimeWindow("", data.attr("NotOnOrAfter"), now) != nil {
+			continue
+		}
+		return nil
+	}
+
+	return ErrSSOAssertionInvalid().WithDetail("reason", "no valid bearer subject confirmation")
+}
+
+func (sp *SAMLServiceProvider) extractIdentity(assertion *xmlNode, config *SSOConfig) (*SSOIdentity, error) {
+	attributes := make(map[string][]string)
+	for _, statement := range assertion.childrenNamed(nsSAMLAssertion, "AttributeStatement") {
+		for _, attribute := range statement.childrenNamed(nsSAMLAssertion, "Attribute") {
+			name := strings.ToLower(attribute.attr("Name"))
+			for _, value := range attribute.childrenNamed(nsSAMLAssertion, "AttributeValue") {
+				attributes[name] = append(attributes[name], value.text())
+			}
+		}
+	}
+
+	nameID := assertion.child(nsSAMLAssertion, "Subject").child(nsSAMLAssertion, "NameID").text()
+
+	identity := &SSOIdentity{
+		Subject:       nameID,
+		Email:         firstAttribute(attributes, samlEmailAttributes),
+		Name:          firstAttribute(attributes, samlNameAttributes),
+		EmailVerified: true, // El IdP del tenant es la fuente de verdad
+		Groups:        attributes[strings.ToLower(config.GetGroupsClaim())],
+	}
+
+	if identity.Email == "" && strings.Contains(nameID, "@") {
+		identity.Email = nameID
+	}
+
+	if identity.Subject == "" || identity.Email == "" {
+		return nil, ErrSSOAssertionInvalid().WithDetail("reason", "assertion has no NameID or email")
+	}
+
+	if identity.Name == "" {
+		identity.Name = identity.Email
+	}
+
+	return identity, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// checkTimeWindow valida NotBefore/NotOnOrAfter con tolerancia de reloj
+func checkTimeWindow(notBefore, notOnOrAfter string, now time.Time) error {
+	if notBefore != "" {
+		t, err := time.Parse(time.RFC3339, notBefore)
+		if err != nil || now.Add(samlClockSkew).Before(t) {
+			return ErrSSOAssertionInvalid().WithDetail("reason", "assertion is not yet valid")
+		}
+	}
+	if notOnOrAfter != "" {
+		t, err := time.Parse(time.RFC3339, notOnOrAfter)
+		if err != nil || !now.Add(-samlClockSkew).Before(t) {
+			return ErrSSOAssertionInvalid().WithDetail("reason", "assertion has expired")
+		}
+	}
+	return nil
+}
+
+func firstAttribute(attributes map[string][]string, names []string) string {
+	for _, name := range names {
+		if values := attributes[name]; len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+func writeXMLAttr(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(fmt.Sprintf(` %s="`, name))
+	xml.EscapeText(buf, []byte(value))
+	buf.WriteByte('"')
+}
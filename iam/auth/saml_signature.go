@@ -0,0 +1,457 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	_ "crypto/sha1" // registra los hashes usados por crypto.Hash.New
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// XML-DSig (solo lo que usan los IdPs SAML: enveloped + exc-c14n + RSA)
+// ============================================================================
+
+const (
+	nsXMLDSig = "http://www.w3.org/2000/09/xmldsig#"
+	nsXML     = "http://www.w3.org/XML/1998/namespace"
+
+	algExcC14N         = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	algEnvelopedSig    = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	algSHA1            = "http://www.w3.org/2000/09/xmldsig#sha1"
+	algSHA256          = "http://www.w3.org/2001/04/xmlenc#sha256"
+	algSHA512          = "http://www.w3.org/2001/04/xmlenc#sha512"
+	algRSASHA1         = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+	algRSASHA256       = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	algRSASHA512       = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha512"
+	nsExcC14NInclusive = "http://www.w3.org/2001/10/xml-exc-c14n#"
+)
+
+// xmlNode árbol mínimo que conserva los prefijos originales, necesario para
+// canonicalizar exactamente lo que firmó el IdP
+type xmlNode struct {
+	prefix   string
+	local    string
+	attrs    []xml.Attr
+	children []xmlChild
+	parent   *xmlNode
+}
+
+type xmlChild struct {
+	node *xmlNode
+	text string
+}
+
+// parseXMLTree parsea el documento sin traducir namespaces. Rechaza DTDs.
+func parseXMLTree(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root, current *xmlNode
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrSSOAssertionInvalid().WithDetail("reason", "malformed xml")
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{
+				prefix: t.Name.Space,
+				local:  t.Name.Local,
+				attrs:  append([]xml.Attr(nil), t.Attr...),
+				parent: current,
+			}
+			if current == nil {
+				if root != nil {
+					return nil, ErrSSOAssertionInvalid().WithDetail("reason", "multiple root elements")
+				}
+				root = node
+			} else {
+				current.children = append(current.children, xmlChild{node: node})
+			}
+			current = node
+		case xml.EndElement:
+			if current == nil || current.prefix != t.Name.Space || current.local != t.Name.Local {
+				return nil, ErrSSOAssertionInvalid().WithDetail("reason", "mismatched xml tags")
+			}
+			current = current.parent
+		case xml.CharData:
+			if current != nil {
+				current.children = append(current.children, xmlChild{text: string(t)})
+			}
+		case xml.Directive:
+			return nil, ErrSSOAssertionInvalid().WithDetail("reason", "xml directives are not allowed")
+		}
+	}
+
+	if root == nil || current != nil {
+		return nil, ErrSSOAssertionInvalid().WithDetail("reason", "incomplete xml document")
+	}
+
+	return root, nil
+}
+
+// lookupNS resuelve un prefijo en el scope del nodo
+func (n *xmlNode) lookupNS(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return nsXML, true
+	}
+	for node := n; node != nil; node = node.parent {
+		for _, attr := range node.attrs {
+			if prefix == "" && attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+				return attr.Value, true
+			}
+			if prefix != "" && attr.Name.Space == "xmlns" && attr.Name.Local == prefix {
+				return attr.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ns retorna el namespace del elemento
+func (n *xmlNode) ns() string {
+	uri, _ := n.lookupNS(n.prefix)
+	return uri
+}
+
+// is verifica namespace y nombre local del elemento
+func (n *xmlNode) is(ns, local string) bool {
+	return n.local == local && n.ns() == ns
+}
+
+// attr retorna el valor de un atributo sin prefijo
+func (n *xmlNode) attr(name string) string {
+	for _, a := range n.attrs {
+		if a.Name.Space == "" && a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// child retorna el primer hijo directo con el nombre dado
+func (n *xmlNode) child(ns, local string) *xmlNode {
+	for _, c := range n.children {
+		if c.node != nil && c.node.is(ns, local) {
+			return c.node
+		}
+	}
+	return nil
+}
+
+// childrenNamed retorna todos los hijos directos con el nombre dado
+func (n *xmlNode) childrenNamed(ns, local string) []*xmlNode {
+	var result []*xmlNode
+	for _, c := range n.children {
+		if c.node != nil && c.node.is(ns, local) {
+			result = append(result, c.node)
+		}
+	}
+	return result
+}
+
+// text retorna el texto concatenado del elemento
+func (n *xmlNode) text() string {
+	if n == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, c := range n.children {
+		if c.node == nil {
+			sb.WriteString(c.text)
+		} else {
+			sb.WriteString(c.node.text())
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// countByID cuenta los elementos del árbol con el atributo ID dado
+func (n *xmlNode) countByID(id string) int {
+	count := 0
+	if n.attr("ID") == id {
+		count++
+	}
+	for _, c := range n.children {
+		if c.node != nil {
+			count += c.node.countByID(id)
+		}
+	}
+	return count
+}
+
+// ============================================================================
+// Exclusive XML Canonicalization (sin comentarios)
+// ============================================================================
+
+// canonicalize serializa el nodo con exc-c14n omitiendo el nodo excluido
+// (transformación enveloped-signature)
+func canonicalize(n *xmlNode, exclude *xmlNode, inclusivePrefixes []string) []byte {
+	var buf bytes.Buffer
+	writeCanonical(&buf, n, exclude, inclusivePrefixes, map[string]string{})
+	return buf.Bytes()
+}
+
+func writeCanonical(buf *bytes.Buffer, n *xmlNode, exclude *xmlNode, inclusive []string, rendered map[string]string) {
+	// Prefijos visiblemente usados por el elemento y sus atributos
+	used := map[string]bool{n.prefix: true}
+	var attrs []xml.Attr
+	for _, a := range n.attrs {
+		if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+			continue
+		}
+		attrs = append(attrs, a)
+		if a.Name.Space != "" && a.Name.Space != "xml" {
+			used[a.Name.Space] = true
+		}
+	}
+	for _, p := range inclusive {
+		if _, ok := n.lookupNS(p); ok {
+			used[p] = true
+		}
+	}
+
+	childRendered := make(map[string]string, len(rendered))
+	for k, v := range rendered {
+		childRendered[k] = v
+	}
+
+	var prefixes []string
+	for p := range used {
+		uri, _ := n.lookupNS(p)
+		prev, wasRendered := rendered[p]
+		if p == "" && uri == "" && (!wasRendered || prev == "") {
+			continue
+		}
+		if wasRendered && prev == uri {
+			continue
+		}
+		childRendered[p] = uri
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+
+	sort.SliceStable(attrs, func(i, j int) bool {
+		nsI := attrNamespace(n, attrs[i])
+		nsJ := attrNamespace(n, attrs[j])
+		if nsI != nsJ {
+			return nsI < nsJ
+		}
+		return attrs[i].Name.Local < attrs[j].Name.Local
+	})
+
+	name := qualifiedName(n.prefix, n.local)
+	buf.WriteByte('<')
+	buf.WriteString(name)
+	for _, p := range prefixes {
+		if p == "" {
+			buf.WriteString(` xmlns="`)
+		} else {
+			buf.WriteString(` xmlns:` + p + `="`)
+		}
+		buf.WriteString(escapeC14NAttr(childRendered[p]))
+		buf.WriteByte('"')
+	}
+	for _, a := range attrs {
+		buf.WriteByte(' ')
+		buf.WriteString(qualifiedName(a.Name.Space, a.Name.Local))
+		buf.WriteString(`="`)
+		buf.WriteString(escapeC14NAttr(a.Value))
+		buf.WriteByte('"')
+	}
+	buf.WriteByte('>')
+
+	for _, c := range n.children {
+		if c.node == nil {
+			buf.WriteString(escapeC14NText(c.text))
+			continue
+		}
+		if c.node == exclude {
+			continue
+		}
+		writeCanonical(buf, c.node, exclude, inclusive, childRendered)
+	}
+
+	buf.WriteString("</" + name + ">")
+}
+
+func attrNamespace(n *xmlNode, a xml.Attr) string {
+	if a.Name.Space == "" {
+		return ""
+	}
+	uri, _ := n.lookupNS(a.Name.Space)
+	return uri
+}
+
+func qualifiedName(prefix, local string) string {
+	if prefix == "" {
+		return local
+	}
+	return prefix + ":" + local
+}
+
+var (
+	c14nTextReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	c14nAttrReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+func escapeC14NText(s string) string { return c14nTextReplacer.Replace(s) }
+func escapeC14NAttr(s string) string { return c14nAttrReplacer.Replace(s) }
+
+// ============================================================================
+// Signature verification
+// ============================================================================
+
+// verifyEnvelopedSignature verifica la firma enveloped de un elemento con el
+// certificado configurado del IdP. Nunca confía en el KeyInfo del mensaje.
+func verifyEnvelopedSignature(root, signed *xmlNode, cert *x509.Certificate) error {
+	signature := signed.child(nsXMLDSig, "Signature")
+	if signature == nil {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "element is not signed")
+	}
+
+	id := signed.attr("ID")
+	if id == "" || root.countByID(id) != 1 {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "signed element id is missing or duplicated")
+	}
+
+	signedInfo := signature.child(nsXMLDSig, "SignedInfo")
+	if signedInfo == nil {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "missing SignedInfo")
+	}
+
+	c14nMethod := signedInfo.child(nsXMLDSig, "CanonicalizationMethod")
+	if c14nMethod == nil || c14nMethod.attr("Algorithm") != algExcC14N {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "unsupported canonicalization method")
+	}
+
+	references := signedInfo.childrenNamed(nsXMLDSig, "Reference")
+	if len(references) != 1 || references[0].attr("URI") != "#"+id {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "signature does not reference the signed element")
+	}
+	reference := references[0]
+
+	// Transforms permitidos: enveloped-signature + exc-c14n
+	var inclusive []string
+	if transforms := reference.child(nsXMLDSig, "Transforms"); transforms != nil {
+		for _, transform := range transforms.childrenNamed(nsXMLDSig, "Transform") {
+			switch transform.attr("Algorithm") {
+			case algEnvelopedSig:
+			case algExcC14N:
+				if ns := transform.child(nsExcC14NInclusive, "InclusiveNamespaces"); ns != nil {
+					inclusive = strings.Fields(ns.attr("PrefixList"))
+				}
+			default:
+				return ErrSSOAssertionInvalid().WithDetail("reason", "unsupported transform")
+			}
+		}
+	}
+
+	digestHash, ok := hashForDigest(reference.child(nsXMLDSig, "DigestMethod"))
+	if !ok {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "unsupported digest method")
+	}
+
+	h := digestHash.New()
+	h.Write(canonicalize(signed, signature, inclusive))
+	expectedDigest, err := base64.StdEncoding.DecodeString(compactBase64(reference.child(nsXMLDSig, "DigestValue").text()))
+	if err != nil || !bytes.Equal(h.Sum(nil), expectedDigest) {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "digest mismatch")
+	}
+
+	var signedInfoInclusive []string
+	if ns := c14nMethod.child(nsExcC14NInclusive, "InclusiveNamespaces"); ns != nil {
+		signedInfoInclusive = strings.Fields(ns.attr("PrefixList"))
+	}
+
+	signatureMethod := signedInfo.child(nsXMLDSig, "SignatureMethod")
+	sigHash, ok := hashForSignature(signatureMethod)
+	if !ok {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "unsupported signature method")
+	}
+
+	signatureValue, err := base64.StdEncoding.DecodeString(compactBase64(signature.child(nsXMLDSig, "SignatureValue").text()))
+	if err != nil {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "malformed signature value")
+	}
+
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return ErrInvalidSSOConfig().WithDetail("reason", "idp certificate must use an RSA key")
+	}
+
+	sh := sigHash.New()
+	sh.Write(canonicalize(signedInfo, nil, signedInfoInclusive))
+	if err := rsa.VerifyPKCS1v15(publicKey, sigHash, sh.Sum(nil), signatureValue); err != nil {
+		return ErrSSOAssertionInvalid().WithDetail("reason", "invalid signature")
+	}
+
+	return nil
+}
+
+func hashForDigest(method *xmlNode) (crypto.Hash, bool) {
+	if method == nil {
+		return 0, false
+	}
+	switch method.attr("Algorithm") {
+	case algSHA1:
+		return crypto.SHA1, true
+	case algSHA256:
+		return crypto.SHA256, true
+	case algSHA512:
+		return crypto.SHA512, true
+	}
+	return 0, false
+}
+
+func hashForSignature(method *xmlNode) (crypto.Hash, bool) {
+	if method == nil {
+		return 0, false
+	}
+	switch method.attr("Algorithm") {
+	case algRSASHA1:
+		return crypto.SHA1, true
+	case algRSASHA256:
+		return crypto.SHA256, true
+	case algRSASHA512:
+		return crypto.SHA512, true
+	}
+	return 0, false
+}
+
+// parseIdPCertificate acepta el certificado en PEM o como base64 del DER
+func parseIdPCertificate(value string) (*x509.Certificate, error) {
+	var der []byte
+	if block, _ := pem.Decode([]byte(value)); block != nil {
+		der = block.Bytes
+	} else {
+		decoded, err := base64.StdEncoding.DecodeString(compactBase64(value))
+		if err != nil {
+			return nil, ErrInvalidSSOConfig().WithDetail("reason", "idp_certificate is not valid PEM or base64")
+		}
+		der = decoded
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, ErrInvalidSSOConfig().WithDetail("reason", "idp_certificate could not be parsed")
+	}
+	return cert, nil
+}
+
+// compactBase64 elimina los saltos de línea y espacios del base64 de XML
+func compactBase64(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
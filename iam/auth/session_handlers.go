@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ============================================================================
+// Session DTOs
+// ============================================================================
+
+// SessionDTO sesión activa del usuario (un dispositivo con sesión iniciada)
+type SessionDTO struct {
+	ID           string          `json:"id"`
+	TenantID     kernel.TenantID `json:"tenant_id"`
+	Device       string          `json:"device"`
+	UserAgent    string          `json:"user_agent"`
+	IPAddress    string          `json:"ip_address"`
+	CreatedAt    time.Time       `json:"created_at"`
+	LastActivity time.Time       `json:"last_activity"`
+	ExpiresAt    time.Time       `json:"expires_at"`
+	IsCurrent    bool            `json:"is_current"`
+}
+
+// ToDTO convierte la sesión a DTO marcando si es la del request actual
+func (s *UserSession) ToDTO(currentSessionID string) SessionDTO {
+	return SessionDTO{
+		ID:           s.ID,
+		TenantID:     s.TenantID,
+		Device:       describeDevice(s.UserAgent),
+		UserAgent:    s.UserAgent,
+		IPAddress:    s.IPAddress,
+		CreatedAt:    s.CreatedAt,
+		LastActivity: s.LastActivity,
+		ExpiresAt:    s.ExpiresAt,
+		IsCurrent:    s.ID == currentSessionID,
+	}
+}
+
+// ============================================================================
+// Session Handlers
+// ============================================================================
+
+// ListSessions lista las sesiones activas del usuario autenticado
+// GET /auth/sessions
+func (ah *AuthHandlers) ListSessions(c *fiber.Ctx) error {
+	authContext, err := ah.authContextFromRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	sessions, err := ah.sessionRepo.FindUserSessions(c.Context(), authContext.UserID)
+	if err != nil {
+		return err
+	}
+
+	dtos := make([]SessionDTO, len(sessions))
+	for i, session := range sessions {
+		dtos[i] = session.ToDTO(authContext.SessionID)
+	}
+
+	return c.JSON(fiber.Map{
+		"sessions": dtos,
+		"total":    len(dtos),
+	})
+}
+
+// RevokeSession cierra una sesión específica del usuario; sus refresh tokens
+// se eliminan en cascada y sus access tokens dejan de ser aceptados
+// DELETE /auth/sessions/:id
+func (ah *AuthHandlers) RevokeSession(c *fiber.Ctx) error {
+	authContext, err := ah.authContextFromRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	sessionID := c.Params("id")
+
+	// Solo se pueden revocar sesiones propias; las ajenas se reportan como inexistentes
+	session, err := ah.sessionRepo.FindSession(c.Context(), sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != authContext.UserID {
+		return ErrSessionNotFound().WithDetail("session_id", sessionID)
+	}
+
+	if err := ah.sessionRepo.RevokeSession(c.Context(), sessionID); err != nil {
+		return err
+	}
+	ah.invalidateSession(c.Context(), sessionID)
+
+	// Revocar la sesión actual equivale a un logout en este dispositivo
+	if sessionID == authContext.SessionID {
+		clearAuthCookies(c)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Session revoked successfully",
+	})
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// revokeAllSessions cierra todas las sesiones del usuario invalidando la caché
+// de cada una para que sus access tokens se rechacen de inmediato
+func (ah *AuthHandlers) revokeAllSessions(ctx context.Context, userID kernel.UserID) error {
+	sessions, err := ah.sessionRepo.FindUserSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := ah.sessionRepo.RevokeAllUserSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		ah.invalidateSession(ctx, session.ID)
+	}
+
+	return nil
+}
+
+// invalidateSession propaga la revocación a la caché del validador
+func (ah *AuthHandlers) invalidateSession(ctx context.Context, sessionID string) {
+	if ah.sessionValidator == nil {
+		return
+	}
+	if err := ah.sessionValidator.Invalidate(ctx, sessionID); err != nil {
+		log.Printf("⚠️  Failed to invalidate session %s: %v", sessionID, err)
+	}
+}
+
+// clearAuthCookies elimina las cookies de sesión del navegador
+func clearAuthCookies(c *fiber.Ctx) {
+	c.Cookie(&fiber.Cookie{
+		Name:     "access_token",
+		Value:    "",
+		Expires:  time.Now().Add(-time.Hour),
+		HTTPOnly: true,
+	})
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "refresh_token",
+		Value:    "",
+		Expires:  time.Now().Add(-time.Hour),
+		HTTPOnly: true,
+	})
+}
+
+// describeDevice genera una descripción legible ("Chrome on macOS") a partir del User-Agent
+func describeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown device"
+	}
+
+	var browser string
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"), strings.Contains(userAgent, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	}
+
+	var platform string
+	switch {
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"):
+		platform = "iOS"
+	case strings.Contains(userAgent, "Android"):
+		platform = "Android"
+	case strings.Contains(userAgent, "Windows"):
+		platform = "Windows"
+	case strings.Contains(userAgent, "Macintosh"), strings.Contains(userAgent, "Mac OS X"):
+		platform = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		platform = "Linux"
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	}
+
+	// Clientes no navegador (curl, SDKs, apps): usar el nombre del producto
+	product, _, _ := strings.Cut(userAgent, "/")
+	return strings.TrimSpace(product)
+}
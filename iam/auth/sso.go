@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// SSO Types
+// ============================================================================
+
+// SSOProtocol protocolo de SSO empresarial configurado por el tenant
+type SSOProtocol string
+
+const (
+	SSOProtocolOIDC SSOProtocol = "OIDC"
+	SSOProtocolSAML SSOProtocol = "SAML"
+)
+
+// SSOConfig configuración de SSO con el IdP propio de un tenant
+type SSOConfig struct {
+	TenantID kernel.TenantID `db:"tenant_id" json:"tenant_id"`
+	Protocol SSOProtocol     `db:"protocol" json:"protocol"`
+	IsActive bool            `db:"is_active" json:"is_active"`
+
+	// OIDC: el resto de endpoints se obtiene del discovery document
+	IssuerURL    string `db:"issuer_url" json:"issuer_url,omitempty"`
+	ClientID     string `db:"client_id" json:"client_id,omitempty"`
+	ClientSecret string `db:"client_secret" json:"-"`
+
+	// SAML: datos del IdP (metadata)
+	IdPEntityID    string `db:"idp_entity_id" json:"idp_entity_id,omitempty"`
+	IdPSSOURL      string `db:"idp_sso_url" json:"idp_sso_url,omitempty"`
+	IdPCertificate string `db:"idp_certificate" json:"idp_certificate,omitempty"`
+
+	// Provisioning y mapeo de grupos del IdP a roles
+	GroupsClaim     string                   `db:"groups_claim" json:"groups_claim"`
+	RoleMappings    map[string]kernel.RoleID `db:"-" json:"role_mappings"`
+	AdminGroups     []string                 `db:"-" json:"admin_groups"`
+	JITProvisioning bool                     `db:"jit_provisioning" json:"jit_provisioning"`
+
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// SSOIdentity identidad del usuario afirmada por el IdP
+type SSOIdentity struct {
+	Subject       string   `json:"subject"`
+	Email         string   `json:"email"`
+	Name          string   `json:"name"`
+	EmailVerified bool     `json:"email_verified"`
+	Groups        []string `json:"groups"`
+}
+
+// DefaultGroupsClaim claim/atributo de grupos usado si el tenant no define otro
+const DefaultGroupsClaim = "groups"
+
+// ============================================================================
+// SSO Domain Methods
+// ============================================================================
+
+// Validate valida que la configuración tenga los campos del protocolo elegido
+func (c *SSOConfig) Validate() error {
+	switch c.Protocol {
+	case SSOProtocolOIDC:
+		if c.IssuerURL == "" || c.ClientID == "" || c.ClientSecret == "" {
+			return ErrInvalidSSOConfig().WithDetail("reason", "issuer_url, client_id and client_secret are required for OIDC")
+		}
+	case SSOProtocolSAML:
+		if c.IdPEntityID == "" || c.IdPSSOURL == "" || c.IdPCertificate == "" {
+			return ErrInvalidSSOConfig().WithDetail("reason", "idp_entity_id, idp_sso_url and idp_certificate are required for SAML")
+		}
+	default:
+		return ErrInvalidSSOConfig().WithDetail("protocol", string(c.Protocol))
+	}
+	return nil
+}
+
+// GetGroupsClaim retorna el claim de grupos efectivo
+func (c *SSOConfig) GetGroupsClaim() string {
+	if c.GroupsClaim == "" {
+		return DefaultGroupsClaim
+	}
+	return c.GroupsClaim
+}
+
+// ResolveRoles mapea los grupos del IdP a roles y al flag de admin
+func (c *SSOConfig) ResolveRoles(groups []string) ([]kernel.RoleID, bool) {
+	var roles []kernel.RoleID
+	isAdmin := false
+	seen := make(map[kernel.RoleID]bool)
+
+	for _, group := range groups {
+		for mappedGroup, roleID := range c.RoleMappings {
+			if strings.EqualFold(group, mappedGroup) && !seen[roleID] {
+				seen[roleID] = true
+				roles = append(roles, roleID)
+			}
+		}
+		for _, adminGroup := range c.AdminGroups {
+			if strings.EqualFold(group, adminGroup) {
+				isAdmin = true
+			}
+		}
+	}
+
+	return roles, isAdmin
+}
+
+// MappedRoles retorna todos los roles gestionados por el IdP
+func (c *SSOConfig) MappedRoles() map[kernel.RoleID]bool {
+	managed := make(map[kernel.RoleID]bool, len(c.RoleMappings))
+	for _, roleID := range c.RoleMappings {
+		managed[roleID] = true
+	}
+	return managed
+}
+
+// ============================================================================
+// SSO Errors
+// ============================================================================
+
+var (
+	CodeSSONotConfigured       = ErrRegistry.Register("SSO_NOT_CONFIGURED", errx.TypeNotFound, http.StatusNotFound, "SSO no configurado para la empresa")
+	CodeInvalidSSOConfig       = ErrRegistry.Register("INVALID_SSO_CONFIG", errx.TypeValidation, http.StatusBadRequest, "Configuración de SSO inválida")
+	CodeSSOAssertionInvalid    = ErrRegistry.Register("SSO_ASSERTION_INVALID", errx.TypeAuthorization, http.StatusUnauthorized, "Respuesta del proveedor de identidad inválida")
+	CodeSSOProvisioningBlocked = ErrRegistry.Register("SSO_PROVISIONING_DISABLED", errx.TypeAuthorization, http.StatusForbidden, "El usuario no existe y el aprovisionamiento automático está deshabilitado")
+)
+
+func ErrSSONotConfigured() *errx.Error {
+	return ErrRegistry.New(CodeSSONotConfigured)
+}
+
+func ErrInvalidSSOConfig() *errx.Error {
+	return ErrRegistry.New(CodeInvalidSSOConfig)
+}
+
+func ErrSSOAssertionInvalid() *errx.Error {
+	return ErrRegistry.New(CodeSSOAssertionInvalid)
+}
+
+func ErrSSOProvisioningDisabled() *errx.Error {
+	return ErrRegistry.New(CodeSSOProvisioningBlocked)
+}
@@ -0,0 +1,491 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/role"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/iam/user"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SSOHandlers maneja el SSO empresarial (OIDC y SAML) por tenant
+type SSOHandlers struct {
+	authHandlers *AuthHandlers
+	ssoRepo      SSOConfigRepository
+	userRoleRepo user.UserRoleRepository
+	roleRepo     role.RoleRepository
+	oidcClient   *OIDCClient
+	samlSP       *SAMLServiceProvider
+}
+
+// NewSSOHandlers crea un nuevo handler de SSO
+func NewSSOHandlers(
+	authHandlers *AuthHandlers,
+	ssoRepo SSOConfigRepository,
+	userRoleRepo user.UserRoleRepository,
+	roleRepo role.RoleRepository,
+	oidcClient *OIDCClient,
+	samlSP *SAMLServiceProvider,
+) *SSOHandlers {
+	return &SSOHandlers{
+		authHandlers: authHandlers,
+		ssoRepo:      ssoRepo,
+		userRoleRepo: userRoleRepo,
+		roleRepo:     roleRepo,
+		oidcClient:   oidcClient,
+		samlSP:       samlSP,
+	}
+}
+
+// SSOConfigRequest solicitud para configurar el SSO del tenant
+type SSOConfigRequest struct {
+	Protocol        SSOProtocol              `json:"protocol"`
+	IsActive        bool                     `json:"is_active"`
+	IssuerURL       string                   `json:"issuer_url"`
+	ClientID        string                   `json:"client_id"`
+	ClientSecret    string                   `json:"client_secret"`
+	IdPEntityID     string                   `json:"idp_entity_id"`
+	IdPSSOURL       string                   `json:"idp_sso_url"`
+	IdPCertificate  string                   `json:"idp_certificate"`
+	GroupsClaim     string                   `json:"groups_claim"`
+	RoleMappings    map[string]kernel.RoleID `json:"role_mappings"`
+	AdminGroups     []string                 `json:"admin_groups"`
+	JITProvisioning bool                     `json:"jit_provisioning"`
+}
+
+// RegisterRoutes registra las rutas públicas de SSO
+func (sh *SSOHandlers) RegisterRoutes(app *fiber.App) {
+	sso := app.Group("/auth/sso")
+
+	sso.Get("/oidc/callback", sh.HandleOIDCCallback)
+	sso.Post("/saml/acs", sh.HandleSAMLResponse)
+	sso.Get("/saml/metadata", sh.SAMLMetadata)
+	sso.Get("/:ruc", sh.InitiateSSO)
+}
+
+// RegisterAdminRoutes registra la administración del SSO del tenant
+func (sh *SSOHandlers) RegisterAdminRoutes(router fiber.Router, authMiddleware *AuthMiddleware) {
+	sso := router.Group("/sso", authMiddleware.RequireAdmin())
+
+	sso.Get("/", sh.GetConfig)
+	sso.Put("/", sh.SaveConfig)
+	sso.Delete("/", sh.DeleteConfig)
+}
+
+// ============================================================================
+// Login
+// ============================================================================
+
+// InitiateSSO redirige al IdP configurado por el tenant
+// GET /auth/sso/:ruc
+func (sh *SSOHandlers) InitiateSSO(c *fiber.Ctx) error {
+	tenantEntity, err := sh.authHandlers.tenantRepo.FindByRUC(c.Context(), c.Params("ruc"))
+	if err != nil {
+		return tenant.ErrTenantNotFound()
+	}
+
+	config, err := sh.activeConfig(c.Context(), tenantEntity.ID)
+	if err != nil {
+		return err
+	}
+
+	state := sh.authHandlers.stateManager.GenerateState()
+	stateData := map[string]any{
+		"tenant_id": tenantEntity.ID.String(),
+		"protocol":  string(config.Protocol),
+	}
+
+	var redirectURL string
+	switch config.Protocol {
+	case SSOProtocolOIDC:
+		nonce := sh.authHandlers.stateManager.GenerateState()
+		stateData["nonce"] = nonce
+		redirectURL, err = sh.oidcClient.GetAuthURL(c.Context(), config, state, nonce)
+	case SSOProtocolSAML:
+		var requestID string
+		redirectURL, requestID, err = sh.samlSP.BuildAuthnRequestURL(config, state)
+		stateData["request_id"] = requestID
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := sh.authHandlers.stateManager.StoreState(c.Context(), state, stateData); err != nil {
+		return errx.Wrap(err, "failed to store sso state", errx.TypeInternal)
+	}
+
+	if c.Query("redirect") == "false" {
+		return c.JSON(LoginResponse{AuthURL: redirectURL, State: state})
+	}
+
+	return c.Redirect(redirectURL, fiber.StatusFound)
+}
+
+// HandleOIDCCallback completa el login OIDC
+// GET /auth/sso/oidc/callback
+func (sh *SSOHandlers) HandleOIDCCallback(c *fiber.Ctx) error {
+	if idpError := c.Query("error"); idpError != "" {
+		return ErrOAuthCallbackError().WithDetail("error", idpError)
+	}
+
+	tenantEntity, config, _, err := sh.consumeState(c.Context(), c.Query("state"), SSOProtocolOIDC)
+	if err != nil {
+		return err
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		return ErrOAuthCallbackError().WithDetail("reason", "missing code")
+	}
+
+	tokenResp, err := sh.oidcClient.ExchangeToken(c.Context(), config, code)
+	if err != nil {
+		return err
+	}
+
+	identity, err := sh.oidcClient.GetIdentity(c.Context(), config, tokenResp.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	return sh.completeLogin(c, tenantEntity, config, identity, iam.OAuthProviderOIDC)
+}
+
+// HandleSAMLResponse procesa la respuesta del IdP (Assertion Consumer Service)
+// POST /auth/sso/saml/acs
+func (sh *SSOHandlers) HandleSAMLResponse(c *fiber.Ctx) error {
+	tenantEntity, config, stateData, err := sh.consumeState(c.Context(), c.FormValue("RelayState"), SSOProtocolSAML)
+	if err != nil {
+		return err
+	}
+
+	requestID, _ := stateData["request_id"].(string)
+
+	identity, err := sh.samlSP.ParseResponse(config, c.FormValue("SAMLResponse"), requestID)
+	if err != nil {
+		log.Printf("⚠️  SAML response rejected for tenant %s: %v", tenantEntity.ID, err)
+		return err
+	}
+
+	return sh.completeLogin(c, tenantEntity, config, identity, iam.OAuthProviderSAML)
+}
+
+// SAMLMetadata expone el metadata del SP
+// GET /auth/sso/saml/metadata
+func (sh *SSOHandlers) SAMLMetadata(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "application/samlmetadata+xml")
+	return c.Send(sh.samlSP.Metadata())
+}
+
+// ============================================================================
+// Administración
+// ============================================================================
+
+// GetConfig retorna la configuración de SSO del tenant
+// GET /api/sso
+func (sh *SSOHandlers) GetConfig(c *fiber.Ctx) error {
+	authContext, ok := GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	config, err := sh.ssoRepo.FindByTenant(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(config)
+}
+
+// SaveConfig crea o reemplaza la configuración de SSO del tenant
+// PUT /api/sso
+func (sh *SSOHandlers) SaveConfig(c *fiber.Ctx) error {
+	authContext, ok := GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req SSOConfigRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ErrInvalidSSOConfig().WithDetail("reason", err.Error())
+	}
+
+	config := &SSOConfig{
+		TenantID:        authContext.TenantID,
+		Protocol:        SSOProtocol(strings.ToUpper(string(req.Protocol))),
+		IsActive:        req.IsActive,
+		IssuerURL:       strings.TrimSuffix(req.IssuerURL, "/"),
+		ClientID:        req.ClientID,
+		ClientSecret:    req.ClientSecret,
+		IdPEntityID:     req.IdPEntityID,
+		IdPSSOURL:       req.IdPSSOURL,
+		IdPCertificate:  req.IdPCertificate,
+		GroupsClaim:     req.GroupsClaim,
+		RoleMappings:    req.RoleMappings,
+		AdminGroups:     req.AdminGroups,
+		JITProvisioning: req.JITProvisioning,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	// Conservar el secreto si no se envía uno nuevo
+	if existing, err := sh.ssoRepo.FindByTenant(c.Context(), authContext.TenantID); err == nil {
+		config.CreatedAt = existing.CreatedAt
+		if config.ClientSecret == "" && existing.Protocol == config.Protocol {
+			config.ClientSecret = existing.ClientSecret
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	// Solo se pueden mapear roles del propio tenant
+	for group, roleID := range config.RoleMappings {
+		if _, err := sh.roleRepo.FindByID(c.Context(), roleID, authContext.TenantID); err != nil {
+			return ErrInvalidSSOConfig().
+				WithDetail("reason", "role_mappings references an unknown role").
+				WithDetail("group", group).
+				WithDetail("role_id", roleID.String())
+		}
+	}
+
+	if config.Protocol == SSOProtocolSAML {
+		if _, err := parseIdPCertificate(config.IdPCertificate); err != nil {
+			return err
+		}
+	}
+
+	if config.Protocol == SSOProtocolOIDC {
+		if _, err := sh.oidcClient.Discover(c.Context(), config.IssuerURL); err != nil {
+			return err
+		}
+	}
+
+	if err := sh.ssoRepo.Save(c.Context(), *config); err != nil {
+		return err
+	}
+
+	return c.JSON(config)
+}
+
+// DeleteConfig elimina la configuración de SSO del tenant
+// DELETE /api/sso
+func (sh *SSOHandlers) DeleteConfig(c *fiber.Ctx) error {
+	authContext, ok := GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := sh.ssoRepo.Delete(c.Context(), authContext.TenantID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// activeConfig retorna la configuración de SSO activa del tenant
+func (sh *SSOHandlers) activeConfig(ctx context.Context, tenantID kernel.TenantID) (*SSOConfig, error) {
+	config, err := sh.ssoRepo.FindByTenant(ctx, tenantID)
+	if err != nil || !config.IsActive {
+		return nil, ErrSSONotConfigured().WithDetail("tenant_id", tenantID.String())
+	}
+	return config, nil
+}
+
+// consumeState valida el estado (uso único) y carga tenant y configuración
+func (sh *SSOHandlers) consumeState(ctx context.Context, state string, protocol SSOProtocol) (*tenant.Tenant, *SSOConfig, map[string]any, error) {
+	if state == "" {
+		return nil, nil, nil, ErrInvalidState()
+	}
+
+	stateData, err := sh.authHandlers.stateManager.GetStateData(ctx, state)
+	if err != nil {
+		return nil, nil, nil, ErrInvalidState()
+	}
+
+	if p, _ := stateData["protocol"].(string); p != string(protocol) {
+		return nil, nil, nil, ErrInvalidState()
+	}
+
+	tenantIDStr, _ := stateData["tenant_id"].(string)
+	tenantID := kernel.NewTenantID(tenantIDStr)
+
+	tenantEntity, err := sh.authHandlers.tenantRepo.FindByID(ctx, tenantID)
+	if err != nil {
+		return nil, nil, nil, tenant.ErrTenantNotFound()
+	}
+
+	if !tenantEntity.IsActive() {
+		return nil, nil, nil, tenant.ErrTenantSuspended()
+	}
+
+	config, err := sh.activeConfig(ctx, tenantID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return tenantEntity, config, stateData, nil
+}
+
+// completeLogin aprovisiona el usuario, sincroniza roles y emite la sesión
+func (sh *SSOHandlers) completeLogin(c *fiber.Ctx, tenantEntity *tenant.Tenant, config *SSOConfig, identity *SSOIdentity, provider iam.OAuthProvider) error {
+	userEntity, err := sh.provisionUser(c.Context(), tenantEntity, config, identity, provider)
+	if err != nil {
+		return err
+	}
+
+	if !userEntity.CanLogin() {
+		return user.ErrUserSuspended()
+	}
+
+	response, err := sh.authHandlers.completeLogin(c, userEntity, tenantEntity, []string{AMRFederated})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response)
+}
+
+// provisionUser busca el usuario por email o lo crea (Just-In-Time)
+func (sh *SSOHandlers) provisionUser(ctx context.Context, tenantEntity *tenant.Tenant, config *SSOConfig, identity *SSOIdentity, provider iam.OAuthProvider) (*user.User, error) {
+	roleIDs, isAdmin := config.ResolveRoles(identity.Groups)
+	email := normalizeEmail(identity.Email)
+
+	userEntity, err := sh.authHandlers.userRepo.FindByEmail(ctx, email, tenantEntity.ID)
+	switch {
+	case err == nil && !userEntity.IsHomeTenant(tenantEntity.ID):
+		// Miembro invitado: la cuenta pertenece a otra empresa, así que el IdP
+		// de este tenant no la vincula ni la modifica; solo gobierna el rol
+		// admin de la membresía
+		if err := sh.syncMembershipAdmin(ctx, userEntity, tenantEntity.ID, config, isAdmin); err != nil {
+			return nil, err
+		}
+
+	case err == nil:
+		// El IdP es la fuente de verdad del rol admin en el tenant principal
+		if len(config.AdminGroups) > 0 {
+			userEntity.IsAdmin = isAdmin
+		}
+		userEntity.OAuthProvider = provider
+		userEntity.OAuthProviderID = identity.Subject
+		if userEntity.Status == user.UserStatusPending {
+			userEntity.Activate()
+		}
+		userEntity.EmailVerified = true
+		userEntity.UpdatedAt = time.Now()
+
+		if err := sh.authHandlers.userRepo.Save(ctx, *userEntity); err != nil {
+			return nil, err
+		}
+
+	case errx.IsCode(err, user.CodeUserNotFound):
+		if !config.JITProvisioning {
+			return nil, ErrSSOProvisioningDisabled().WithDetail("email", email)
+		}
+
+		if !tenantEntity.CanAddUser() {
+			return nil, tenant.ErrMaxUsersReached()
+		}
+
+		userEntity = &user.User{
+			ID:              kernel.NewUserID(generateID()),
+			TenantID:        tenantEntity.ID,
+			Email:           email,
+			Name:            identity.Name,
+			Status:          user.UserStatusActive,
+			IsAdmin:         isAdmin,
+			OAuthProvider:   provider,
+			OAuthProviderID: identity.Subject,
+			EmailVerified:   true,
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+
+		if err := sh.authHandlers.userRepo.Save(ctx, *userEntity); err != nil {
+			return nil, err
+		}
+
+		if err := tenantEntity.AddUser(); err == nil {
+			sh.authHandlers.tenantRepo.Save(ctx, *tenantEntity)
+		}
+
+		log.Printf("👤 JIT provisioned user %s in tenant %s via %s", userEntity.ID, tenantEntity.ID, provider)
+
+	default:
+		return nil, err
+	}
+
+	if len(config.RoleMappings) > 0 {
+		if err := sh.syncRoles(ctx, userEntity.ID, config, roleIDs); err != nil {
+			log.Printf("⚠️  Failed to sync SSO roles for user %s: %v", userEntity.ID, err)
+		}
+	}
+
+	return userEntity, nil
+}
+
+// syncMembershipAdmin aplica el rol admin del IdP a la membresía del usuario
+// en el tenant. Una membresía suspendida no puede entrar por SSO
+func (sh *SSOHandlers) syncMembershipAdmin(ctx context.Context, userEntity *user.User, tenantID kernel.TenantID, config *SSOConfig, isAdmin bool) error {
+	membership, err := sh.authHandlers.membershipRepo.Find(ctx, userEntity.ID, tenantID)
+	if err != nil {
+		return err
+	}
+
+	if !membership.IsActive() {
+		return user.ErrUserSuspended().WithDetail("tenant_id", tenantID.String())
+	}
+
+	if len(config.AdminGroups) == 0 || membership.IsAdmin == isAdmin {
+		return nil
+	}
+
+	membership.IsAdmin = isAdmin
+	return sh.authHandlers.membershipRepo.Save(ctx, *membership)
+}
+
+// syncRoles ajusta los roles gestionados por el IdP; los roles asignados a
+// mano fuera del mapeo no se tocan
+func (sh *SSOHandlers) syncRoles(ctx context.Context, userID kernel.UserID, config *SSOConfig, desired []kernel.RoleID) error {
+	current, err := sh.userRoleRepo.FindRolesByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	has := make(map[kernel.RoleID]bool, len(current))
+	for _, roleID := range current {
+		has[roleID] = true
+	}
+
+	want := make(map[kernel.RoleID]bool, len(desired))
+	for _, roleID := range desired {
+		want[roleID] = true
+		if !has[roleID] {
+			if err := sh.userRoleRepo.AssignUserToRole(ctx, userID, roleID); err != nil {
+				return err
+			}
+		}
+	}
+
+	for roleID := range config.MappedRoles() {
+		if has[roleID] && !want[roleID] {
+			if err := sh.userRoleRepo.RemoveUserFromRole(ctx, userID, roleID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/iam/user"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ============================================================================
+// Multi-tenant DTOs
+// ============================================================================
+
+// SwitchTenantRequest solicitud para cambiar de tenant
+type SwitchTenantRequest struct {
+	TenantID kernel.TenantID `json:"tenant_id"`
+}
+
+// UserTenantDTO tenant accesible por el usuario autenticado
+type UserTenantDTO struct {
+	Tenant    tenant.TenantDetailsDTO `json:"tenant"`
+	IsAdmin   bool                    `json:"is_admin"`
+	IsHome    bool                    `json:"is_home"`
+	IsCurrent bool                    `json:"is_current"`
+}
+
+// ============================================================================
+// Multi-tenant Handlers
+// ============================================================================
+
+// ListTenants lista los tenants a los que el usuario puede cambiar
+// GET /auth/tenants
+func (ah *AuthHandlers) ListTenants(c *fiber.Ctx) error {
+	authContext, err := ah.authContextFromRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	userEntity, err := ah.userRepo.FindByID(c.Context(), authContext.UserID, authContext.TenantID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	memberships, err := ah.membershipRepo.FindByUser(c.Context(), userEntity.ID)
+	if err != nil {
+		return err
+	}
+
+	tenants := make([]UserTenantDTO, 0, len(memberships)+1)
+	for _, access := range user.BuildTenantAccess(userEntity, memberships) {
+		tenantEntity, err := ah.tenantRepo.FindByID(c.Context(), access.TenantID)
+		if err != nil {
+			continue // Skip tenants eliminados
+		}
+		tenants = append(tenants, UserTenantDTO{
+			Tenant:    tenantEntity.ToDTO(),
+			IsAdmin:   access.IsAdmin,
+			IsHome:    access.IsHome,
+			IsCurrent: access.TenantID == authContext.TenantID,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"tenants": tenants,
+		"total":   len(tenants),
+	})
+}
+
+// SwitchTenant emite una nueva sesión con el access token acotado al tenant elegido
+// POST /auth/switch-tenant
+func (ah *AuthHandlers) SwitchTenant(c *fiber.Ctx) error {
+	authContext, err := ah.authContextFromRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var req SwitchTenantRequest
+	if err := c.BodyParser(&req); err != nil || req.TenantID.IsEmpty() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "tenant_id is required",
+		})
+	}
+
+	// Resolver el usuario en el tenant destino: falla si no tiene membresía
+	userEntity, err := ah.userRepo.FindByID(c.Context(), authContext.UserID, req.TenantID)
+	if err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": user.ErrUserNotInTenant().Error(),
+		})
+	}
+
+	if !userEntity.CanLogin() {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User cannot login",
+		})
+	}
+
+	tenantEntity, err := ah.tenantRepo.FindByID(c.Context(), req.TenantID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Tenant not found",
+		})
+	}
+
+	if !tenantEntity.IsActive() {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Tenant is not active",
+		})
+	}
+
+	// Una membresía suspendida no permite entrar al tenant
+	if _, err := ah.isAdminIn(c.Context(), userEntity, tenantEntity.ID); err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// La sesión conserva los factores ya verificados; si el tenant destino
+	// exige 2FA y no se usó, se pide el segundo factor
+	response, err := ah.completeLogin(c, userEntity, tenantEntity, authContext.AMR)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// La nueva sesión reemplaza a la anterior en este dispositivo
+	if _, issued := response.(*TokenResponse); issued && authContext.SessionID != "" {
+		if err := ah.sessionRepo.RevokeSession(c.Context(), authContext.SessionID); err != nil {
+			log.Printf("⚠️  Failed to revoke previous session %s: %v", authContext.SessionID, err)
+		}
+		ah.invalidateSession(c.Context(), authContext.SessionID)
+	}
+
+	return c.JSON(response)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// authContextFromRequest obtiene el contexto del middleware o, en rutas
+// públicas, decodifica el token desde Authorization o cookie
+func (ah *AuthHandlers) authContextFromRequest(c *fiber.Ctx) (*kernel.AuthContext, error) {
+	if authContext, ok := GetAuthContext(c); ok {
+		return authContext, nil
+	}
+
+	var token string
+	authHeader := c.Get("Authorization")
+	if authHeader != "" {
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) == 2 && parts[0] == "Bearer" && parts[1] != "" {
+			token = parts[1]
+		}
+	}
+	if token == "" {
+		token = c.Cookies("access_token")
+	}
+	if token == "" {
+		return nil, iam.ErrUnauthorized()
+	}
+
+	claims, err := ah.tokenService.ValidateAccessToken(token)
+	if err != nil {
+		return nil, iam.ErrUnauthorized()
+	}
+
+	if err := checkSession(c.Context(), ah.sessionValidator, claims.SessionID); err != nil {
+		return nil, err
+	}
+
+	// Construir contexto de autenticación a partir de los claims
+	return &kernel.AuthContext{
+		UserID:    claims.UserID,
+		TenantID:  claims.TenantID,
+		IsAdmin:   claims.IsAdmin,
+		Email:     claims.Email,
+		Name:      claims.Name,
+		AMR:       claims.AMR,
+		SessionID: claims.SessionID,
+	}, nil
+}
+
+// isAdminIn resuelve si el usuario es admin en el tenant dado: en su tenant
+// principal usa User.IsAdmin, en otros el flag de la membresía. Falla si la
+// membresía está suspendida
+func (ah *AuthHandlers) isAdminIn(ctx context.Context, userEntity *user.User, tenantID kernel.TenantID) (bool, error) {
+	if userEntity.IsHomeTenant(tenantID) {
+		return userEntity.IsAdmin, nil
+	}
+
+	membership, err := ah.membershipRepo.Find(ctx, userEntity.ID, tenantID)
+	if err != nil {
+		return false, err
+	}
+
+	if !membership.IsActive() {
+		return false, user.ErrUserSuspended().WithDetail("tenant_id", tenantID.String())
+	}
+
+	return membership.IsAdmin, nil
+}
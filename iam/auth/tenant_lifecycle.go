@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// TenantSessionHook expira las sesiones de un tenant al suspenderlo o borrarlo
+type TenantSessionHook struct {
+	sessionRepo      SessionRepository
+	tokenRepo        TokenRepository
+	sessionValidator SessionValidator
+}
+
+// NewTenantSessionHook crea el hook de sesiones del ciclo de vida del tenant
+func NewTenantSessionHook(sessionRepo SessionRepository, tokenRepo TokenRepository, sessionValidator SessionValidator) *TenantSessionHook {
+	return &TenantSessionHook{
+		sessionRepo:      sessionRepo,
+		tokenRepo:        tokenRepo,
+		sessionValidator: sessionValidator,
+	}
+}
+
+// OnTenantLifecycle implementa tenant.LifecycleHook
+func (h *TenantSessionHook) OnTenantLifecycle(ctx context.Context, tenantID kernel.TenantID, event tenant.LifecycleEvent) error {
+	switch event {
+	case tenant.LifecycleSuspended, tenant.LifecycleDeleted:
+		return h.expireSessions(ctx, tenantID)
+	}
+	// Al reactivar los usuarios vuelven a iniciar sesión
+	return nil
+}
+
+func (h *TenantSessionHook) expireSessions(ctx context.Context, tenantID kernel.TenantID) error {
+	sessions, err := h.sessionRepo.FindTenantSessions(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.sessionRepo.RevokeAllTenantSessions(ctx, tenantID); err != nil {
+		return err
+	}
+
+	// Refresh tokens emitidos antes de que existieran sesiones por dispositivo
+	if err := h.tokenRepo.RevokeAllTenantTokens(ctx, tenantID); err != nil {
+		return err
+	}
+
+	if h.sessionValidator != nil {
+		for _, session := range sessions {
+			if err := h.sessionValidator.Invalidate(ctx, session.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -40,6 +40,9 @@ const (
 	OAuthProviderGoogle    OAuthProvider = "GOOGLE"
 	OAuthProviderMicrosoft OAuthProvider = "MICROSOFT"
 	OAuthProviderAuth0     OAuthProvider = "AUTH0"
+	OAuthProviderPassword  OAuthProvider = "PASSWORD" // Email/contraseña, sin OAuth
+	OAuthProviderOIDC      OAuthProvider = "OIDC"     // SSO empresarial con el IdP del tenant
+	OAuthProviderSAML      OAuthProvider = "SAML"     // SSO empresarial con el IdP del tenant
 )
 
 // GetProviderName retorna el nombre legible del proveedor
@@ -51,6 +54,12 @@ func (p OAuthProvider) GetProviderName() string {
 		return "Microsoft"
 	case OAuthProviderAuth0:
 		return "Auth0"
+	case OAuthProviderPassword:
+		return "Password"
+	case OAuthProviderOIDC:
+		return "OpenID Connect"
+	case OAuthProviderSAML:
+		return "SAML"
 	default:
 		return "Unknown"
 	}
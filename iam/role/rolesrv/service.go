@@ -33,6 +33,12 @@ func NewRoleService(
 	}
 }
 
+// SetUserRoleRepository configura el repositorio de asignaciones usuario-rol;
+// DeleteRole lo usa para no eliminar roles asignados
+func (s *RoleService) SetUserRoleRepository(userRoleRepo user.UserRoleRepository) {
+	s.userRoleRepo = userRoleRepo
+}
+
 // CreateRole crea un nuevo rol
 func (s *RoleService) CreateRole(ctx context.Context, req role.CreateRoleRequest) (*role.Role, error) {
 	// Verificar que el tenant exista y esté activo
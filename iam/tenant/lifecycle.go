@@ -0,0 +1,115 @@
+package tenant
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Lifecycle Events
+// ============================================================================
+
+// LifecycleEvent evento del ciclo de vida de un tenant
+type LifecycleEvent string
+
+const (
+	LifecycleSuspended   LifecycleEvent = "tenant.suspended"
+	LifecycleReactivated LifecycleEvent = "tenant.reactivated"
+	LifecycleDeleted     LifecycleEvent = "tenant.deleted" // Se emite antes de borrar los datos
+)
+
+// ============================================================================
+// Data Export (portabilidad antes del offboarding)
+// ============================================================================
+
+// ExportStatus estado de un job de exportación
+type ExportStatus string
+
+const (
+	ExportStatusPending   ExportStatus = "PENDING"
+	ExportStatusRunning   ExportStatus = "RUNNING"
+	ExportStatusCompleted ExportStatus = "COMPLETED"
+	ExportStatusFailed    ExportStatus = "FAILED"
+)
+
+// ExportFormat formato de las secciones tabulares (contactos y transcripciones).
+// Las definiciones (workflows, tools, canales) siempre se exportan en JSON
+type ExportFormat string
+
+const (
+	ExportFormatJSON ExportFormat = "json"
+	ExportFormatCSV  ExportFormat = "csv"
+)
+
+// IsValid verifica si el formato es soportado
+func (f ExportFormat) IsValid() bool {
+	return f == ExportFormatJSON || f == ExportFormatCSV
+}
+
+// DataExport job de exportación completa de los datos de un tenant
+type DataExport struct {
+	ID          string          `db:"id" json:"id"`
+	TenantID    kernel.TenantID `db:"tenant_id" json:"tenant_id"`
+	RequestedBy kernel.UserID   `db:"requested_by" json:"requested_by"`
+	Format      ExportFormat    `db:"format" json:"format"`
+	Status      ExportStatus    `db:"status" json:"status"`
+	FilePath    string          `db:"file_path" json:"-"`
+	SizeBytes   int64           `db:"size_bytes" json:"size_bytes"`
+	Error       string          `db:"error" json:"error,omitempty"`
+	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
+	StartedAt   *time.Time      `db:"started_at" json:"started_at,omitempty"`
+	CompletedAt *time.Time      `db:"completed_at" json:"completed_at,omitempty"`
+}
+
+// NewDataExport crea un job de exportación pendiente
+func NewDataExport(tenantID kernel.TenantID, requestedBy kernel.UserID, format ExportFormat) *DataExport {
+	return &DataExport{
+		ID:          uuid.NewString(),
+		TenantID:    tenantID,
+		RequestedBy: requestedBy,
+		Format:      format,
+		Status:      ExportStatusPending,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Start marca el job en ejecución
+func (e *DataExport) Start() {
+	now := time.Now()
+	e.Status = ExportStatusRunning
+	e.StartedAt = &now
+}
+
+// Complete marca el job como terminado con el archivo generado
+func (e *DataExport) Complete(filePath string, sizeBytes int64) {
+	now := time.Now()
+	e.Status = ExportStatusCompleted
+	e.FilePath = filePath
+	e.SizeBytes = sizeBytes
+	e.CompletedAt = &now
+}
+
+// Fail marca el job como fallido
+func (e *DataExport) Fail(err error) {
+	now := time.Now()
+	e.Status = ExportStatusFailed
+	e.Error = err.Error()
+	e.CompletedAt = &now
+}
+
+// IsReady verifica si el archivo puede descargarse
+func (e *DataExport) IsReady() bool {
+	return e.Status == ExportStatusCompleted && e.FilePath != ""
+}
+
+// RequestExportRequest solicitud de exportación de datos
+type RequestExportRequest struct {
+	Format ExportFormat `json:"format,omitempty"`
+}
+
+// DeleteTenantRequest confirmación de borrado definitivo del tenant
+type DeleteTenantRequest struct {
+	ConfirmRUC string `json:"confirm_ruc"` // Debe coincidir con el RUC del tenant
+}
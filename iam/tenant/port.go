@@ -2,6 +2,7 @@ package tenant
 
 import (
 	"context"
+	"io"
 
 	"github.com/Abraxas-365/relay/pkg/kernel"
 )
@@ -23,3 +24,21 @@ type TenantConfigRepository interface {
 	SaveSetting(ctx context.Context, tenantID kernel.TenantID, key, value string) error
 	DeleteSetting(ctx context.Context, tenantID kernel.TenantID, key string) error
 }
+
+// LifecycleHook reacciona a cambios de estado del tenant (deshabilitar canales,
+// detener schedules, expirar sesiones, notificar sistemas externos)
+type LifecycleHook interface {
+	OnTenantLifecycle(ctx context.Context, tenantID kernel.TenantID, event LifecycleEvent) error
+}
+
+// DataExportRepository define el contrato para los jobs de exportación
+type DataExportRepository interface {
+	Save(ctx context.Context, export DataExport) error
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*DataExport, error)
+	FindByTenant(ctx context.Context, tenantID kernel.TenantID) ([]*DataExport, error)
+}
+
+// DataExporter escribe el archivo con todos los datos del tenant
+type DataExporter interface {
+	Export(ctx context.Context, tenantID kernel.TenantID, format ExportFormat, w io.Writer) error
+}
@@ -32,6 +32,10 @@ const (
 	PlanEnterprise   SubscriptionPlan = "ENTERPRISE"
 )
 
+// TenantSettingDataRegion guarda la región donde viven los mensajes, sesiones
+// de agentes y contactos del tenant. Se fija al crearlo y no se puede cambiar
+const TenantSettingDataRegion = "data.region"
+
 // Tenant es la entidad rica que representa una empresa en el sistema
 type Tenant struct {
 	ID                    kernel.TenantID  `db:"id" json:"id"`
@@ -184,6 +188,7 @@ type CreateTenantRequest struct {
 	CompanyName      string           `json:"company_name" validate:"required,min=2"`
 	RUC              string           `json:"ruc" validate:"required,len=11"`
 	SubscriptionPlan SubscriptionPlan `json:"subscription_plan"`
+	DataRegion       string           `json:"data_region,omitempty"` // Vacío = región principal
 }
 
 // UpdateTenantRequest representa la petición para actualizar un tenant
@@ -332,6 +337,17 @@ var (
 	CodeTooManyUsersForPlan = ErrRegistry.Register("TOO_MANY_USERS_FOR_PLAN", errx.TypeBusiness, http.StatusBadRequest, "El nuevo plan no permite tantos usuarios")
 	CodeTenantHasUsers      = ErrRegistry.Register("TENANT_HAS_USERS", errx.TypeBusiness, http.StatusConflict, "No se puede eliminar tenant con usuarios activos")
 	CodeInvalidPlanUpgrade  = ErrRegistry.Register("INVALID_PLAN_UPGRADE", errx.TypeBusiness, http.StatusBadRequest, "Actualización de plan inválida")
+
+	// Offboarding
+	CodeExportNotFound       = ErrRegistry.Register("EXPORT_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Exportación no encontrada")
+	CodeExportNotReady       = ErrRegistry.Register("EXPORT_NOT_READY", errx.TypeBusiness, http.StatusConflict, "La exportación aún no está disponible")
+	CodeInvalidExportFormat  = ErrRegistry.Register("INVALID_EXPORT_FORMAT", errx.TypeValidation, http.StatusBadRequest, "Formato de exportación no soportado")
+	CodeDeleteNotConfirmed   = ErrRegistry.Register("DELETE_NOT_CONFIRMED", errx.TypeValidation, http.StatusBadRequest, "Confirme el borrado indicando el RUC de la empresa")
+	CodeExportAlreadyRunning = ErrRegistry.Register("EXPORT_ALREADY_RUNNING", errx.TypeConflict, http.StatusConflict, "Ya hay una exportación en curso")
+
+	// Residencia de datos
+	CodeInvalidDataRegion = ErrRegistry.Register("INVALID_DATA_REGION", errx.TypeValidation, http.StatusBadRequest, "Región de datos no disponible")
+	CodeDataRegionLocked  = ErrRegistry.Register("DATA_REGION_LOCKED", errx.TypeConflict, http.StatusConflict, "La región de datos no se puede cambiar")
 )
 
 // Helper functions para crear errores
@@ -370,3 +386,31 @@ func ErrTenantHasUsers() *errx.Error {
 func ErrInvalidPlanUpgrade() *errx.Error {
 	return ErrRegistry.New(CodeInvalidPlanUpgrade)
 }
+
+func ErrExportNotFound() *errx.Error {
+	return ErrRegistry.New(CodeExportNotFound)
+}
+
+func ErrExportNotReady() *errx.Error {
+	return ErrRegistry.New(CodeExportNotReady)
+}
+
+func ErrInvalidExportFormat() *errx.Error {
+	return ErrRegistry.New(CodeInvalidExportFormat)
+}
+
+func ErrDeleteNotConfirmed() *errx.Error {
+	return ErrRegistry.New(CodeDeleteNotConfirmed)
+}
+
+func ErrExportAlreadyRunning() *errx.Error {
+	return ErrRegistry.New(CodeExportAlreadyRunning)
+}
+
+func ErrInvalidDataRegion() *errx.Error {
+	return ErrRegistry.New(CodeInvalidDataRegion)
+}
+
+func ErrDataRegionLocked() *errx.Error {
+	return ErrRegistry.New(CodeDataRegionLocked)
+}
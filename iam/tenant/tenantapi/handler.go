@@ -0,0 +1,130 @@
+package tenantapi
+
+import (
+	"strings"
+
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/iam/tenant/tenantsrv"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TenantHandler maneja el offboarding del tenant autenticado: exportación y borrado
+type TenantHandler struct {
+	tenantService *tenantsrv.TenantService
+	exportService *tenantsrv.ExportService
+}
+
+// NewTenantHandler crea un nuevo handler de tenant
+func NewTenantHandler(tenantService *tenantsrv.TenantService, exportService *tenantsrv.ExportService) *TenantHandler {
+	return &TenantHandler{
+		tenantService: tenantService,
+		exportService: exportService,
+	}
+}
+
+// RequestExport inicia una exportación completa de los datos del tenant
+// POST /api/tenant/exports
+func (h *TenantHandler) RequestExport(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req tenant.RequestExportRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return tenant.ErrInvalidExportFormat().WithDetail("reason", err.Error())
+		}
+	}
+
+	export, err := h.exportService.RequestExport(c.Context(), authContext.TenantID, authContext.UserID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(export)
+}
+
+// ListExports lista las exportaciones del tenant
+// GET /api/tenant/exports
+func (h *TenantHandler) ListExports(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	exports, err := h.exportService.ListExports(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"exports": exports,
+		"total":   len(exports),
+	})
+}
+
+// GetExport obtiene el estado de una exportación
+// GET /api/tenant/exports/:id
+func (h *TenantHandler) GetExport(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	export, err := h.exportService.GetExport(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(export)
+}
+
+// DownloadExport descarga el archivo zip de una exportación terminada
+// GET /api/tenant/exports/:id/download
+func (h *TenantHandler) DownloadExport(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	id := c.Params("id")
+	path, err := h.exportService.ArchivePath(c.Context(), id, authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.Download(path, "relay-export-"+id+".zip")
+}
+
+// DeleteTenant borra definitivamente el tenant y todos sus datos
+// DELETE /api/tenant
+func (h *TenantHandler) DeleteTenant(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req tenant.DeleteTenantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return tenant.ErrDeleteNotConfirmed()
+	}
+
+	current, err := h.tenantService.GetTenantByID(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	// Confirmación explícita: el borrado no se puede deshacer
+	if strings.TrimSpace(req.ConfirmRUC) != current.Tenant.RUC {
+		return tenant.ErrDeleteNotConfirmed()
+	}
+
+	if err := h.tenantService.DeleteTenant(c.Context(), authContext.TenantID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
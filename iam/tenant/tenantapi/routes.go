@@ -0,0 +1,32 @@
+package tenantapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TenantRoutes configura las rutas de offboarding del tenant
+type TenantRoutes struct {
+	handler        *TenantHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewTenantRoutes crea una nueva instancia de rutas de tenant
+func NewTenantRoutes(handler *TenantHandler, authMiddleware *auth.AuthMiddleware) *TenantRoutes {
+	return &TenantRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registra las rutas en un router autenticado; solo administradores
+func (r *TenantRoutes) RegisterRoutes(router fiber.Router) {
+	tenant := router.Group("/tenant", r.authMiddleware.RequireAdmin())
+
+	tenant.Delete("/", r.handler.DeleteTenant)
+
+	tenant.Post("/exports", r.handler.RequestExport)
+	tenant.Get("/exports", r.handler.ListExports)
+	tenant.Get("/exports/:id", r.handler.GetExport)
+	tenant.Get("/exports/:id/download", r.handler.DownloadExport)
+}
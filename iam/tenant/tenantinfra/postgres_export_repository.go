@@ -0,0 +1,97 @@
+package tenantinfra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresDataExportRepository implementación de PostgreSQL para DataExportRepository
+type PostgresDataExportRepository struct {
+	db *sqlx.DB
+}
+
+// NewPostgresDataExportRepository crea una nueva instancia del repositorio de exportaciones
+func NewPostgresDataExportRepository(db *sqlx.DB) tenant.DataExportRepository {
+	return &PostgresDataExportRepository{
+		db: db,
+	}
+}
+
+// Save crea o actualiza un job de exportación
+func (r *PostgresDataExportRepository) Save(ctx context.Context, export tenant.DataExport) error {
+	query := `
+		INSERT INTO tenant_data_exports (
+			id, tenant_id, requested_by, format, status, file_path,
+			size_bytes, error, created_at, started_at, completed_at
+		) VALUES (
+			:id, :tenant_id, :requested_by, :format, :status, :file_path,
+			:size_bytes, :error, :created_at, :started_at, :completed_at
+		)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			file_path = EXCLUDED.file_path,
+			size_bytes = EXCLUDED.size_bytes,
+			error = EXCLUDED.error,
+			started_at = EXCLUDED.started_at,
+			completed_at = EXCLUDED.completed_at`
+
+	_, err := r.db.NamedExecContext(ctx, query, export)
+	if err != nil {
+		return errx.Wrap(err, "failed to save data export", errx.TypeInternal).
+			WithDetail("export_id", export.ID)
+	}
+
+	return nil
+}
+
+// FindByID busca una exportación del tenant
+func (r *PostgresDataExportRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*tenant.DataExport, error) {
+	query := `
+		SELECT 
+			id, tenant_id, requested_by, format, status, file_path,
+			size_bytes, error, created_at, started_at, completed_at
+		FROM tenant_data_exports 
+		WHERE id = $1 AND tenant_id = $2`
+
+	var export tenant.DataExport
+	err := r.db.GetContext(ctx, &export, query, id, tenantID.String())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, tenant.ErrExportNotFound().WithDetail("export_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find data export", errx.TypeInternal).
+			WithDetail("export_id", id)
+	}
+
+	return &export, nil
+}
+
+// FindByTenant lista las exportaciones del tenant, más recientes primero
+func (r *PostgresDataExportRepository) FindByTenant(ctx context.Context, tenantID kernel.TenantID) ([]*tenant.DataExport, error) {
+	query := `
+		SELECT 
+			id, tenant_id, requested_by, format, status, file_path,
+			size_bytes, error, created_at, started_at, completed_at
+		FROM tenant_data_exports 
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC`
+
+	var exports []tenant.DataExport
+	err := r.db.SelectContext(ctx, &exports, query, tenantID.String())
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to find data exports", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	result := make([]*tenant.DataExport, len(exports))
+	for i := range exports {
+		result[i] = &exports[i]
+	}
+
+	return result, nil
+}
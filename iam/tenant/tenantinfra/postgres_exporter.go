@@ -0,0 +1,427 @@
+package tenantinfra
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/encryption"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+// exportSection un archivo del export. Todas las consultas reciben el tenant como $1
+type exportSection struct {
+	name      string
+	tabular   bool // Respeta el formato pedido (CSV); el resto siempre es JSON
+	query     string
+	encrypted []string // Columnas JSONB que pueden estar cifradas en reposo
+	routed    bool     // Se lee de la base de la región de datos del tenant
+
+	// Columnas CSV calculadas en Go a partir de una columna cifrada, ya que
+	// Postgres no puede extraerlas (content->>'text' es NULL si está cifrado)
+	derived []derivedColumn
+}
+
+// derivedColumn columna CSV = campo de primer nivel del JSON descifrado de source
+type derivedColumn struct {
+	column string
+	source string
+	field  string
+}
+
+// Las credenciales de canales y tools (columna config) no se exportan
+var exportSections = []exportSection{
+	{
+		name: "tenant",
+		query: `
+			SELECT t.id, t.company_name, t.ruc, t.status, t.subscription_plan, t.created_at,
+				COALESCE((SELECT json_object_agg(c.key, c.value) FROM tenant_config c WHERE c.tenant_id = t.id), '{}') AS config
+			FROM tenants t
+			WHERE t.id = $1`,
+	},
+	{
+		name: "users",
+		query: `
+			SELECT u.id, u.email, u.name, u.status, u.is_admin, u.email_verified, u.last_login_at, u.created_at
+			FROM users u
+			WHERE u.tenant_id = $1
+			   OR EXISTS (SELECT 1 FROM user_tenant_memberships m WHERE m.user_id = u.id AND m.tenant_id = $1)
+			ORDER BY u.created_at`,
+	},
+	{
+		name: "channels",
+		query: `
+			SELECT id, type, name, description, is_active, webhook_url, created_at, updated_at
+			FROM channels
+			WHERE tenant_id = $1
+			ORDER BY created_at`,
+	},
+	{
+		name: "workflows",
+		query: `
+			SELECT id, name, description, trigger, nodes, variables, is_active, created_at, updated_at
+			FROM workflows
+			WHERE tenant_id = $1
+			ORDER BY created_at`,
+	},
+	{
+		name: "schedules",
+		query: `
+			SELECT id, workflow_id, schedule_type, cron_expression, interval_seconds, scheduled_at,
+				is_active, last_run_at, run_count, timezone, metadata, created_at
+			FROM workflow_schedules
+			WHERE tenant_id = $1
+			ORDER BY created_at`,
+	},
+	{
+		name: "tools",
+		query: `
+			SELECT id, name, description, type, input_schema, output_schema, is_active, created_at, updated_at
+			FROM tools
+			WHERE tenant_id = $1
+			ORDER BY created_at`,
+	},
+	{
+		name:    "contacts",
+		tabular: true,
+		routed:  true,
+		query: `
+			SELECT m.channel_id, m.conversation_id,
+				MIN(m.created_at) AS first_seen_at,
+				MAX(m.created_at) AS last_seen_at,
+				COUNT(*) AS message_count,
+				EXISTS (
+					SELECT 1 FROM contact_suppressions s
+					WHERE s.tenant_id = m.tenant_id
+					  AND s.recipient_id = m.conversation_id
+					  AND (s.channel_id IS NULL OR s.channel_id = m.channel_id)
+				) AS suppressed
+			FROM messages m
+			WHERE m.tenant_id = $1
+			GROUP BY m.tenant_id, m.channel_id, m.conversation_id
+			ORDER BY last_seen_at DESC`,
+	},
+	{
+		name:    "suppressions",
+		tabular: true,
+		routed:  true,
+		query: `
+			SELECT id, channel_id, recipient_id, reason, created_at
+			FROM contact_suppressions
+			WHERE tenant_id = $1
+			ORDER BY created_at`,
+	},
+	{
+		name:    "transcripts",
+		tabular: true,
+		routed:  true,
+		query: `
+			SELECT id, channel_id, conversation_id, sender_id, direction, origin, status,
+				content->>'type' AS type, content->>'text' AS text, content, context,
+				workflow_id, node_id, created_at
+			FROM messages
+			WHERE tenant_id = $1
+			ORDER BY conversation_id, created_at`,
+		encrypted: []string{"content", "context"},
+		derived: []derivedColumn{
+			{column: "type", source: "content", field: "type"},
+			{column: "text", source: "content", field: "text"},
+		},
+	},
+	{
+		name:    "ai_transcripts",
+		tabular: true,
+		routed:  true,
+		query: `
+			SELECT id, session_id, role, content, model_used, tokens_used, created_at
+			FROM agent_messages
+			WHERE tenant_id = $1
+			ORDER BY session_id, created_at`,
+	},
+}
+
+// exportManifest describe el contenido del archivo
+type exportManifest struct {
+	TenantID    kernel.TenantID     `json:"tenant_id"`
+	Format      tenant.ExportFormat `json:"format"`
+	GeneratedAt time.Time           `json:"generated_at"`
+	Files       map[string]int      `json:"files"` // archivo -> cantidad de registros
+}
+
+// PostgresDataExporter genera un zip con todos los datos del tenant
+type PostgresDataExporter struct {
+	db     *sqlx.DB
+	router *database.Router
+	cipher *encryption.FieldCipher
+}
+
+// NewPostgresDataExporter crea un nuevo exportador de datos. Los mensajes
+// cifrados se exportan en claro usando cipher (puede ser nil); las secciones
+// regionales se leen de la base que elija router (nil = todas en db)
+func NewPostgresDataExporter(db *sqlx.DB, router *database.Router, cipher *encryption.FieldCipher) tenant.DataExporter {
+	return &PostgresDataExporter{
+		db:     db,
+		router: router,
+		cipher: cipher,
+	}
+}
+
+// Export escribe el zip en w sección por sección, sin cargar tablas completas en memoria
+func (e *PostgresDataExporter) Export(ctx context.Context, tenantID kernel.TenantID, format tenant.ExportFormat, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	manifest := exportManifest{
+		TenantID:    tenantID,
+		Format:      format,
+		GeneratedAt: time.Now().UTC(),
+		Files:       make(map[string]int, len(exportSections)),
+	}
+
+	for _, section := range exportSections {
+		var (
+			fileName string
+			count    int
+			err      error
+		)
+
+		if section.tabular && format == tenant.ExportFormatCSV {
+			fileName = section.name + ".csv"
+			count, err = e.writeCSV(ctx, zw, fileName, section, tenantID)
+		} else {
+			fileName = section.name + ".json"
+			count, err = e.writeJSON(ctx, zw, fileName, section, tenantID)
+		}
+		if err != nil {
+			return errx.Wrap(err, "failed to export section", errx.TypeInternal).
+				WithDetail("section", section.name).
+				WithDetail("tenant_id", tenantID.String())
+		}
+
+		manifest.Files[fileName] = count
+	}
+
+	mw, err := zw.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(mw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// writeJSON escribe un array JSON; Postgres serializa cada fila con sus tipos
+func (e *PostgresDataExporter) writeJSON(ctx context.Context, zw *zip.Writer, fileName string, section exportSection, tenantID kernel.TenantID) (int, error) {
+	db, err := e.conn(ctx, section, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT row_to_json(t)::text FROM (%s) t", section.query), tenantID.String())
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	fw, err := zw.Create(fileName)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := io.WriteString(fw, "["); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return count, err
+		}
+		if row, err = e.decryptJSONRow(ctx, tenantID, section, row); err != nil {
+			return count, err
+		}
+
+		sep := ",\n"
+		if count == 0 {
+			sep = "\n"
+		}
+		if _, err := io.WriteString(fw, sep+row); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+
+	_, err = io.WriteString(fw, "\n]\n")
+	return count, err
+}
+
+// writeCSV escribe las filas con una cabecera tomada de las columnas de la consulta
+func (e *PostgresDataExporter) writeCSV(ctx context.Context, zw *zip.Writer, fileName string, section exportSection, tenantID kernel.TenantID) (int, error) {
+	db, err := e.conn(ctx, section, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := db.QueryContext(ctx, section.query, tenantID.String())
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	fw, err := zw.Create(fileName)
+	if err != nil {
+		return 0, err
+	}
+
+	cw := csv.NewWriter(fw)
+	if err := cw.Write(columns); err != nil {
+		return 0, err
+	}
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	record := make([]string, len(columns))
+
+	count := 0
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return count, err
+		}
+		for i, v := range values {
+			record[i] = v.String
+		}
+		if err := e.decryptCSVRecord(ctx, tenantID, section, columns, record); err != nil {
+			return count, err
+		}
+		if err := cw.Write(record); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+
+	cw.Flush()
+	return count, cw.Error()
+}
+
+// conn devuelve la base de la que se lee la sección
+func (e *PostgresDataExporter) conn(ctx context.Context, section exportSection, tenantID kernel.TenantID) (*sqlx.DB, error) {
+	if !section.routed || e.router == nil {
+		return e.db, nil
+	}
+	return e.router.For(ctx, tenantID)
+}
+
+// decryptJSONRow reemplaza las columnas cifradas de una fila serializada por Postgres
+func (e *PostgresDataExporter) decryptJSONRow(ctx context.Context, tenantID kernel.TenantID, section exportSection, row string) (string, error) {
+	if e.cipher == nil || len(section.encrypted) == 0 {
+		return row, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(row), &fields); err != nil {
+		return "", err
+	}
+
+	changed := false
+	for _, column := range section.encrypted {
+		raw, ok := fields[column]
+		if !ok {
+			continue
+		}
+		if _, sealed := encryption.ParseEnvelope(raw); !sealed {
+			continue
+		}
+
+		plaintext, err := e.cipher.DecryptJSON(ctx, tenantID, raw)
+		if err != nil {
+			return "", err
+		}
+		fields[column] = plaintext
+		changed = true
+
+		for _, d := range section.derived {
+			if d.source == column {
+				fields[d.column] = derivedValue(plaintext, d.field)
+			}
+		}
+	}
+	if !changed {
+		return row, nil
+	}
+
+	out, err := json.Marshal(fields)
+	return string(out), err
+}
+
+// decryptCSVRecord descifra en el registro las columnas cifradas y completa las derivadas
+func (e *PostgresDataExporter) decryptCSVRecord(ctx context.Context, tenantID kernel.TenantID, section exportSection, columns, record []string) error {
+	if e.cipher == nil || len(section.encrypted) == 0 {
+		return nil
+	}
+
+	for _, column := range section.encrypted {
+		i := slices.Index(columns, column)
+		if i < 0 {
+			continue
+		}
+		if _, sealed := encryption.ParseEnvelope([]byte(record[i])); !sealed {
+			continue
+		}
+
+		plaintext, err := e.cipher.DecryptJSON(ctx, tenantID, []byte(record[i]))
+		if err != nil {
+			return err
+		}
+		record[i] = string(plaintext)
+
+		for _, d := range section.derived {
+			if j := slices.Index(columns, d.column); j >= 0 && d.source == column {
+				var value string
+				json.Unmarshal(derivedValue(plaintext, d.field), &value)
+				record[j] = value
+			}
+		}
+	}
+
+	return nil
+}
+
+// derivedValue extrae un campo string de primer nivel de un objeto JSON
+func derivedValue(object []byte, field string) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(object, &fields); err != nil {
+		return json.RawMessage("null")
+	}
+	if value, ok := fields[field]; ok {
+		return value
+	}
+	return json.RawMessage("null")
+}
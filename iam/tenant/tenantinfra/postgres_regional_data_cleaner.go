@@ -0,0 +1,62 @@
+package tenantinfra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// regionalTables tablas que viven en la base de la región del tenant. No tienen
+// clave foránea a tenants, así que el borrado del tenant no las alcanza
+var regionalTables = []string{
+	"messages",
+	"conversation_tags",
+	"agent_messages",
+	"contact_suppressions",
+	"contacts",
+}
+
+// PostgresRegionalDataCleaner borra los datos regionales de un tenant eliminado
+type PostgresRegionalDataCleaner struct {
+	router *database.Router
+}
+
+// NewPostgresRegionalDataCleaner crea el hook de borrado de datos regionales
+func NewPostgresRegionalDataCleaner(router *database.Router) *PostgresRegionalDataCleaner {
+	return &PostgresRegionalDataCleaner{router: router}
+}
+
+var _ tenant.LifecycleHook = (*PostgresRegionalDataCleaner)(nil)
+
+// OnTenantLifecycle implementa tenant.LifecycleHook. Corre antes de borrar el
+// tenant, cuando su región todavía se puede resolver
+func (c *PostgresRegionalDataCleaner) OnTenantLifecycle(ctx context.Context, tenantID kernel.TenantID, event tenant.LifecycleEvent) error {
+	if event != tenant.LifecycleDeleted {
+		return nil
+	}
+
+	db, err := c.router.For(ctx, tenantID)
+	if err != nil {
+		return errx.Wrap(err, "failed to resolve tenant database", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errx.Wrap(err, "failed to begin transaction", errx.TypeInternal)
+	}
+	defer tx.Rollback()
+
+	for _, table := range regionalTables {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE tenant_id = $1", table), tenantID.String()); err != nil {
+			return errx.Wrap(err, "failed to delete regional tenant data", errx.TypeInternal).
+				WithDetail("table", table)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,81 @@
+package tenantinfra
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// SignatureHeader cabecera con la firma HMAC-SHA256 del cuerpo
+const SignatureHeader = "X-Relay-Signature"
+
+// lifecyclePayload cuerpo enviado al webhook
+type lifecyclePayload struct {
+	Event      tenant.LifecycleEvent `json:"event"`
+	TenantID   kernel.TenantID       `json:"tenant_id"`
+	OccurredAt time.Time             `json:"occurred_at"`
+}
+
+// WebhookLifecycleNotifier notifica los eventos del ciclo de vida del tenant a una URL externa
+type WebhookLifecycleNotifier struct {
+	url        string
+	secret     string
+	httpClient *http.Client
+}
+
+// NewWebhookLifecycleNotifier crea un notificador; sin secret las peticiones no se firman
+func NewWebhookLifecycleNotifier(url, secret string) *WebhookLifecycleNotifier {
+	return &WebhookLifecycleNotifier{
+		url:        url,
+		secret:     secret,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// OnTenantLifecycle implementa tenant.LifecycleHook
+func (n *WebhookLifecycleNotifier) OnTenantLifecycle(ctx context.Context, tenantID kernel.TenantID, event tenant.LifecycleEvent) error {
+	body, err := json.Marshal(lifecyclePayload{
+		Event:      event,
+		TenantID:   tenantID,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return errx.Wrap(err, "failed to build lifecycle webhook request", errx.TypeInternal)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if n.secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return errx.Wrap(err, "failed to deliver lifecycle webhook", errx.TypeExternal).
+			WithDetail("event", string(event))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return errx.New(fmt.Sprintf("lifecycle webhook returned status %d", resp.StatusCode), errx.TypeExternal).
+			WithDetail("event", string(event))
+	}
+
+	return nil
+}
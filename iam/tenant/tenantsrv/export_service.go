@@ -0,0 +1,179 @@
+package tenantsrv
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// exportTimeout tiempo máximo para generar un archivo de exportación
+const exportTimeout = 30 * time.Minute
+
+// ExportService gestiona los jobs de exportación de datos del tenant (portabilidad GDPR)
+type ExportService struct {
+	exportRepo tenant.DataExportRepository
+	exporter   tenant.DataExporter
+	tenantRepo tenant.TenantRepository
+	exportDir  string
+}
+
+// NewExportService crea una nueva instancia del servicio de exportación
+func NewExportService(
+	exportRepo tenant.DataExportRepository,
+	exporter tenant.DataExporter,
+	tenantRepo tenant.TenantRepository,
+	exportDir string,
+) *ExportService {
+	return &ExportService{
+		exportRepo: exportRepo,
+		exporter:   exporter,
+		tenantRepo: tenantRepo,
+		exportDir:  exportDir,
+	}
+}
+
+// RequestExport registra un job de exportación y lo ejecuta en segundo plano
+func (s *ExportService) RequestExport(ctx context.Context, tenantID kernel.TenantID, requestedBy kernel.UserID, req tenant.RequestExportRequest) (*tenant.DataExport, error) {
+	format := req.Format
+	if format == "" {
+		format = tenant.ExportFormatJSON
+	}
+	if !format.IsValid() {
+		return nil, tenant.ErrInvalidExportFormat().WithDetail("format", string(format))
+	}
+
+	if _, err := s.tenantRepo.FindByID(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	// Un solo job a la vez por tenant: cada uno recorre todas las tablas
+	existing, err := s.exportRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range existing {
+		if e.Status == tenant.ExportStatusPending || e.Status == tenant.ExportStatusRunning {
+			return nil, tenant.ErrExportAlreadyRunning().WithDetail("export_id", e.ID)
+		}
+	}
+
+	export := tenant.NewDataExport(tenantID, requestedBy, format)
+	if err := s.exportRepo.Save(ctx, *export); err != nil {
+		return nil, err
+	}
+
+	// El job sobrevive a la petición HTTP
+	go s.run(*export)
+
+	return export, nil
+}
+
+// GetExport obtiene un job de exportación del tenant
+func (s *ExportService) GetExport(ctx context.Context, id string, tenantID kernel.TenantID) (*tenant.DataExport, error) {
+	return s.exportRepo.FindByID(ctx, id, tenantID)
+}
+
+// ListExports lista los jobs de exportación del tenant
+func (s *ExportService) ListExports(ctx context.Context, tenantID kernel.TenantID) ([]*tenant.DataExport, error) {
+	return s.exportRepo.FindByTenant(ctx, tenantID)
+}
+
+// ArchivePath devuelve la ruta del archivo de una exportación terminada
+func (s *ExportService) ArchivePath(ctx context.Context, id string, tenantID kernel.TenantID) (string, error) {
+	export, err := s.exportRepo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return "", err
+	}
+	if !export.IsReady() {
+		return "", tenant.ErrExportNotReady().WithDetail("status", string(export.Status))
+	}
+
+	if _, err := os.Stat(export.FilePath); err != nil {
+		return "", tenant.ErrExportNotFound().WithDetail("export_id", id)
+	}
+
+	return export.FilePath, nil
+}
+
+// OnTenantLifecycle implementa tenant.LifecycleHook: al borrar el tenant se
+// eliminan también sus archivos de exportación
+func (s *ExportService) OnTenantLifecycle(ctx context.Context, tenantID kernel.TenantID, event tenant.LifecycleEvent) error {
+	if event != tenant.LifecycleDeleted {
+		return nil
+	}
+
+	if err := os.RemoveAll(s.tenantDir(tenantID)); err != nil {
+		return errx.Wrap(err, "failed to remove tenant exports", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func (s *ExportService) run(export tenant.DataExport) {
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	export.Start()
+	if err := s.exportRepo.Save(ctx, export); err != nil {
+		log.Printf("⚠️  Failed to start export %s: %v", export.ID, err)
+		return
+	}
+
+	path, size, err := s.writeArchive(ctx, export)
+	if err != nil {
+		log.Printf("⚠️  Export %s of tenant %s failed: %v", export.ID, export.TenantID, err)
+		export.Fail(err)
+	} else {
+		log.Printf("✅ Export %s of tenant %s completed (%d bytes)", export.ID, export.TenantID, size)
+		export.Complete(path, size)
+	}
+
+	if err := s.exportRepo.Save(ctx, export); err != nil {
+		log.Printf("⚠️  Failed to save export %s: %v", export.ID, err)
+	}
+}
+
+func (s *ExportService) writeArchive(ctx context.Context, export tenant.DataExport) (string, int64, error) {
+	dir := s.tenantDir(export.TenantID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", 0, err
+	}
+
+	path := filepath.Join(dir, export.ID+".zip")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := s.exporter.Export(ctx, export.TenantID, export.Format, file); err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", 0, err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return "", 0, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return path, info.Size(), nil
+}
+
+func (s *ExportService) tenantDir(tenantID kernel.TenantID) string {
+	return filepath.Join(s.exportDir, tenantID.String())
+}
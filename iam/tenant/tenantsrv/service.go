@@ -2,6 +2,9 @@ package tenantsrv
 
 import (
 	"context"
+	"log"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Abraxas-365/craftable/errx"
@@ -16,6 +19,13 @@ type TenantService struct {
 	tenantRepo       tenant.TenantRepository
 	tenantConfigRepo tenant.TenantConfigRepository
 	userRepo         user.UserRepository
+
+	// Cascadas del ciclo de vida (canales, schedules, sesiones, webhooks)
+	lifecycleHooks []tenant.LifecycleHook
+
+	// Regiones de datos disponibles; sin regiones solo se acepta la principal
+	primaryRegion string
+	dataRegions   []string
 }
 
 // NewTenantService crea una nueva instancia del servicio de tenants
@@ -31,8 +41,26 @@ func NewTenantService(
 	}
 }
 
+// AddLifecycleHook registra hooks que se ejecutan al suspender, reactivar o
+// borrar un tenant. Se registran después de construir el resto de servicios
+func (s *TenantService) AddLifecycleHook(hooks ...tenant.LifecycleHook) {
+	s.lifecycleHooks = append(s.lifecycleHooks, hooks...)
+}
+
+// SetDataRegions indica la región principal y las regiones adicionales en las
+// que se pueden crear tenants
+func (s *TenantService) SetDataRegions(primary string, regions []string) {
+	s.primaryRegion = primary
+	s.dataRegions = regions
+}
+
 // CreateTenant crea un nuevo tenant
 func (s *TenantService) CreateTenant(ctx context.Context, req tenant.CreateTenantRequest) (*tenant.Tenant, error) {
+	region := strings.ToLower(strings.TrimSpace(req.DataRegion))
+	if !s.isDataRegion(region) {
+		return nil, tenant.ErrInvalidDataRegion().WithDetail("data_region", req.DataRegion)
+	}
+
 	// Verificar que no exista un tenant con el mismo RUC
 	exists, err := s.tenantRepo.ExistsByRUC(ctx, req.RUC)
 	if err != nil {
@@ -72,6 +100,14 @@ func (s *TenantService) CreateTenant(ctx context.Context, req tenant.CreateTenan
 		return nil, errx.Wrap(err, "failed to save tenant", errx.TypeInternal)
 	}
 
+	// Si se indicó, la región se guarda aunque sea la principal: así el tenant
+	// no cambia de base si luego cambia la región principal del despliegue
+	if region != "" {
+		if err := s.tenantConfigRepo.SaveSetting(ctx, newTenant.ID, tenant.TenantSettingDataRegion, region); err != nil {
+			return nil, errx.Wrap(err, "failed to save tenant data region", errx.TypeInternal)
+		}
+	}
+
 	return newTenant, nil
 }
 
@@ -174,11 +210,14 @@ func (s *TenantService) UpdateTenant(ctx context.Context, tenantID kernel.Tenant
 	if req.CompanyName != nil {
 		tenantEntity.CompanyName = *req.CompanyName
 	}
-	if req.Status != nil {
+	var event tenant.LifecycleEvent
+	if req.Status != nil && *req.Status != tenantEntity.Status {
 		switch *req.Status {
 		case tenant.TenantStatusActive:
+			event = reactivationEvent(tenantEntity)
 			tenantEntity.Activate()
 		case tenant.TenantStatusSuspended:
+			event = tenant.LifecycleSuspended
 			tenantEntity.Suspend("Updated by admin")
 		}
 	}
@@ -190,6 +229,10 @@ func (s *TenantService) UpdateTenant(ctx context.Context, tenantID kernel.Tenant
 		return nil, errx.Wrap(err, "failed to update tenant", errx.TypeInternal)
 	}
 
+	if event != "" {
+		s.runLifecycleHooks(ctx, tenantID, event)
+	}
+
 	return tenantEntity, nil
 }
 
@@ -200,8 +243,18 @@ func (s *TenantService) SuspendTenant(ctx context.Context, tenantID kernel.Tenan
 		return tenant.ErrTenantNotFound()
 	}
 
+	if tenantEntity.Status == tenant.TenantStatusSuspended {
+		return nil
+	}
+
 	tenantEntity.Suspend(reason)
-	return s.tenantRepo.Save(ctx, *tenantEntity)
+	if err := s.tenantRepo.Save(ctx, *tenantEntity); err != nil {
+		return err
+	}
+
+	// Deshabilitar canales, detener schedules y expirar sesiones
+	s.runLifecycleHooks(ctx, tenantID, tenant.LifecycleSuspended)
+	return nil
 }
 
 // ActivateTenant activa un tenant
@@ -211,8 +264,18 @@ func (s *TenantService) ActivateTenant(ctx context.Context, tenantID kernel.Tena
 		return tenant.ErrTenantNotFound()
 	}
 
+	event := reactivationEvent(tenantEntity)
+
 	tenantEntity.Activate()
-	return s.tenantRepo.Save(ctx, *tenantEntity)
+	if err := s.tenantRepo.Save(ctx, *tenantEntity); err != nil {
+		return err
+	}
+
+	// Restaurar lo que se pausó al suspender
+	if event != "" {
+		s.runLifecycleHooks(ctx, tenantID, event)
+	}
+	return nil
 }
 
 // UpgradeTenantPlan mejora el plan de suscripción de un tenant
@@ -259,6 +322,10 @@ func (s *TenantService) SetTenantConfig(ctx context.Context, tenantID kernel.Ten
 		return tenant.ErrTenantNotFound()
 	}
 
+	if key == tenant.TenantSettingDataRegion {
+		return tenant.ErrDataRegionLocked()
+	}
+
 	return s.tenantConfigRepo.SaveSetting(ctx, tenantID, key, value)
 }
 
@@ -289,6 +356,10 @@ func (s *TenantService) DeleteTenantConfig(ctx context.Context, tenantID kernel.
 		return tenant.ErrTenantNotFound()
 	}
 
+	if key == tenant.TenantSettingDataRegion {
+		return tenant.ErrDataRegionLocked()
+	}
+
 	return s.tenantConfigRepo.DeleteSetting(ctx, tenantID, key)
 }
 
@@ -361,7 +432,10 @@ func (s *TenantService) GetTenantUsage(ctx context.Context, tenantID kernel.Tena
 	return usage, nil
 }
 
-// DeleteTenant elimina un tenant (soft delete recomendado)
+// DeleteTenant elimina definitivamente un tenant y todos sus datos. Primero se
+// suspende (bloquea logins y webhooks), luego corren las cascadas y al final
+// el borrado elimina en cascada usuarios, canales, workflows y mensajes.
+// La exportación de datos debe solicitarse antes
 func (s *TenantService) DeleteTenant(ctx context.Context, tenantID kernel.TenantID) error {
 	// Verificar que el tenant existe
 	tenantEntity, err := s.tenantRepo.FindByID(ctx, tenantID)
@@ -369,15 +443,19 @@ func (s *TenantService) DeleteTenant(ctx context.Context, tenantID kernel.Tenant
 		return tenant.ErrTenantNotFound()
 	}
 
-	// Verificar que no tenga usuarios activos
-	users, err := s.userRepo.FindByTenant(ctx, tenantID)
-	if err == nil && len(users) > 0 {
-		return tenant.ErrTenantHasUsers()
+	tenantEntity.Suspend("Tenant deleted")
+	if err := s.tenantRepo.Save(ctx, *tenantEntity); err != nil {
+		return errx.Wrap(err, "failed to suspend tenant before deletion", errx.TypeInternal)
 	}
 
-	// En lugar de eliminar, suspender permanentemente
-	tenantEntity.Suspend("Tenant deleted")
-	return s.tenantRepo.Save(ctx, *tenantEntity)
+	s.runLifecycleHooks(ctx, tenantID, tenant.LifecycleDeleted)
+
+	if err := s.tenantRepo.Delete(ctx, tenantID); err != nil {
+		return err
+	}
+
+	log.Printf("🗑️  Tenant %s (%s) deleted", tenantEntity.CompanyName, tenantID)
+	return nil
 }
 
 // BulkSuspendTenants suspende múltiples tenants
@@ -419,6 +497,33 @@ func (s *TenantService) BulkActivateTenants(ctx context.Context, tenantIDs []ker
 }
 
 // Helper methods
+
+// runLifecycleHooks ejecuta todos los hooks; un hook fallido no detiene al resto
+// porque el cambio de estado del tenant ya fue persistido
+func (s *TenantService) runLifecycleHooks(ctx context.Context, tenantID kernel.TenantID, event tenant.LifecycleEvent) {
+	for _, hook := range s.lifecycleHooks {
+		if err := hook.OnTenantLifecycle(ctx, tenantID, event); err != nil {
+			log.Printf("⚠️  Tenant lifecycle hook failed (%s, tenant %s): %v", event, tenantID, err)
+		}
+	}
+}
+
+// isDataRegion indica si el tenant se puede crear en la región; vacío = principal
+func (s *TenantService) isDataRegion(region string) bool {
+	if region == "" || region == s.primaryRegion {
+		return true
+	}
+	return slices.Contains(s.dataRegions, region)
+}
+
+// reactivationEvent solo reactiva lo pausado si el tenant venía suspendido
+func reactivationEvent(t *tenant.Tenant) tenant.LifecycleEvent {
+	if t.Status == tenant.TenantStatusSuspended {
+		return tenant.LifecycleReactivated
+	}
+	return ""
+}
+
 func (s *TenantService) getMaxUsersForPlan(plan tenant.SubscriptionPlan) int {
 	switch plan {
 	case tenant.PlanTrial, tenant.PlanBasic:
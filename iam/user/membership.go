@@ -0,0 +1,101 @@
+package user
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Membership Entity
+// ============================================================================
+
+// Membership da acceso a un usuario a un tenant distinto de su tenant principal.
+// El tenant principal (User.TenantID) no necesita membresía. El rol de admin
+// y el estado de la membresía solo valen en su tenant: el registro global del
+// usuario solo lo modifica su tenant principal.
+type Membership struct {
+	UserID    kernel.UserID   `db:"user_id" json:"user_id"`
+	TenantID  kernel.TenantID `db:"tenant_id" json:"tenant_id"`
+	IsAdmin   bool            `db:"is_admin" json:"is_admin"`
+	Status    UserStatus      `db:"status" json:"status"` // ACTIVE o SUSPENDED
+	InvitedBy *kernel.UserID  `db:"invited_by" json:"invited_by,omitempty"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+// NewMembership crea una membresía activa para un usuario en un tenant
+func NewMembership(userID kernel.UserID, tenantID kernel.TenantID, isAdmin bool, invitedBy *kernel.UserID) *Membership {
+	return &Membership{
+		UserID:    userID,
+		TenantID:  tenantID,
+		IsAdmin:   isAdmin,
+		Status:    UserStatusActive,
+		InvitedBy: invitedBy,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsActive verifica si la membresía permite entrar al tenant
+func (m *Membership) IsActive() bool {
+	return m.Status == UserStatusActive
+}
+
+// Activate reactiva una membresía suspendida
+func (m *Membership) Activate() error {
+	if m.Status != UserStatusSuspended {
+		return ErrInvalidStatus().WithDetail("current_status", m.Status)
+	}
+	m.Status = UserStatusActive
+	return nil
+}
+
+// Suspend suspende el acceso del usuario a este tenant, sin tocar los demás
+func (m *Membership) Suspend() error {
+	if !m.IsActive() {
+		return ErrInvalidStatus().WithDetail("current_status", m.Status)
+	}
+	m.Status = UserStatusSuspended
+	return nil
+}
+
+// InTenant devuelve el usuario tal como lo ve el tenant de la membresía
+func (m *Membership) InTenant(u User) User {
+	u.IsAdmin = m.IsAdmin
+	u.Status = m.Status
+	return u
+}
+
+// IsHomeTenant verifica si el tenant es el tenant principal del usuario
+func (u *User) IsHomeTenant(tenantID kernel.TenantID) bool {
+	return u.TenantID == tenantID
+}
+
+// ============================================================================
+// Membership DTOs
+// ============================================================================
+
+// TenantAccess describe un tenant al que el usuario puede cambiar
+type TenantAccess struct {
+	TenantID kernel.TenantID `json:"tenant_id"`
+	IsAdmin  bool            `json:"is_admin"`
+	IsHome   bool            `json:"is_home"`
+}
+
+// BuildTenantAccess combina el tenant principal con las membresías del usuario
+func BuildTenantAccess(u *User, memberships []*Membership) []TenantAccess {
+	access := []TenantAccess{{
+		TenantID: u.TenantID,
+		IsAdmin:  u.IsAdmin,
+		IsHome:   true,
+	}}
+	for _, m := range memberships {
+		if m.TenantID == u.TenantID || !m.IsActive() {
+			continue
+		}
+		access = append(access, TenantAccess{
+			TenantID: m.TenantID,
+			IsAdmin:  m.IsAdmin,
+		})
+	}
+	return access
+}
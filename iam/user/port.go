@@ -26,6 +26,14 @@ type UserRoleRepository interface {
 	CountUsersByRole(ctx context.Context, roleID kernel.RoleID) (int, error)
 }
 
+// MembershipRepository define el contrato para las membresías usuario-tenant
+type MembershipRepository interface {
+	Find(ctx context.Context, userID kernel.UserID, tenantID kernel.TenantID) (*Membership, error)
+	FindByUser(ctx context.Context, userID kernel.UserID) ([]*Membership, error)
+	Save(ctx context.Context, m Membership) error
+	Delete(ctx context.Context, userID kernel.UserID, tenantID kernel.TenantID) error
+}
+
 // PasswordService define el contrato para el manejo de contraseñas
 type PasswordService interface {
 	HashPassword(password string) (string, error)
@@ -36,6 +36,9 @@ type User struct {
 	OAuthProvider   iam.OAuthProvider `db:"oauth_provider" json:"oauth_provider"`
 	OAuthProviderID string            `db:"oauth_provider_id" json:"oauth_provider_id"`
 	EmailVerified   bool              `db:"email_verified" json:"email_verified"`
+	PasswordHash    *string           `db:"password_hash" json:"-"`
+	FailedLogins    int               `db:"failed_login_attempts" json:"-"`
+	LockedUntil     *time.Time        `db:"locked_until" json:"locked_until,omitempty"`
 	LastLoginAt     *time.Time        `db:"last_login_at" json:"last_login_at,omitempty"`
 	CreatedAt       time.Time         `db:"created_at" json:"created_at"`
 	UpdatedAt       time.Time         `db:"updated_at" json:"updated_at"`
@@ -95,6 +98,52 @@ func (u *User) UpdateProfile(name, picture string) {
 	u.UpdatedAt = time.Now()
 }
 
+// HasPassword verifica si el usuario tiene contraseña local
+func (u *User) HasPassword() bool {
+	return u.PasswordHash != nil && *u.PasswordHash != ""
+}
+
+// SetPassword asigna un nuevo hash de contraseña y desbloquea la cuenta
+func (u *User) SetPassword(hash string) {
+	u.PasswordHash = ptrx.String(hash)
+	u.FailedLogins = 0
+	u.LockedUntil = nil
+	u.UpdatedAt = time.Now()
+}
+
+// IsLocked verifica si la cuenta está bloqueada por intentos fallidos
+func (u *User) IsLocked() bool {
+	return u.LockedUntil != nil && time.Now().Before(*u.LockedUntil)
+}
+
+// RegisterFailedLogin registra un intento fallido y bloquea la cuenta
+// al alcanzar maxAttempts
+func (u *User) RegisterFailedLogin(maxAttempts int, lockout time.Duration) {
+	u.FailedLogins++
+	if maxAttempts > 0 && u.FailedLogins >= maxAttempts {
+		lockedUntil := time.Now().Add(lockout)
+		u.LockedUntil = &lockedUntil
+		u.FailedLogins = 0
+	}
+	u.UpdatedAt = time.Now()
+}
+
+// ResetFailedLogins limpia los intentos fallidos tras un login exitoso
+func (u *User) ResetFailedLogins() {
+	u.FailedLogins = 0
+	u.LockedUntil = nil
+	u.UpdatedAt = time.Now()
+}
+
+// VerifyEmail marca el email como verificado y activa al usuario pendiente
+func (u *User) VerifyEmail() {
+	u.EmailVerified = true
+	if u.Status == UserStatusPending {
+		u.Status = UserStatusActive
+	}
+	u.UpdatedAt = time.Now()
+}
+
 // MakeAdmin convierte al usuario en administrador
 func (u *User) MakeAdmin() {
 	u.IsAdmin = true
@@ -158,10 +207,12 @@ type UpdateUserRequest struct {
 	IsAdmin  *bool           `json:"is_admin,omitempty"`
 }
 
-// InviteUserRequest para invitar usuarios a un tenant
+// InviteUserRequest para dar acceso al tenant a un usuario de otra empresa,
+// identificado por su email y el RUC de su tenant principal
 type InviteUserRequest struct {
-	Email   string `json:"email" validate:"required,email"`
-	IsAdmin bool   `json:"is_admin"`
+	Email         string `json:"email" validate:"required,email"`
+	HomeTenantRUC string `json:"home_tenant_ruc" validate:"required,len=11"`
+	IsAdmin       bool   `json:"is_admin"`
 }
 
 // UserResponse representa la respuesta completa de un usuario con sus roles
@@ -247,6 +298,7 @@ var (
 	CodeUserSuspended      = ErrRegistry.Register("SUSPENDED", errx.TypeBusiness, http.StatusForbidden, "Usuario suspendido")
 	CodeOnboardingRequired = ErrRegistry.Register("ONBOARDING_REQUIRED", errx.TypeBusiness, http.StatusPreconditionRequired, "Se requiere completar el onboarding")
 	CodeInvalidStatus      = ErrRegistry.Register("INVALID_STATUS", errx.TypeBusiness, http.StatusBadRequest, "Estado de usuario inválido para esta operación")
+	CodeNotHomeTenant      = ErrRegistry.Register("NOT_HOME_TENANT", errx.TypeAuthorization, http.StatusForbidden, "Solo la empresa principal del usuario puede modificar su perfil")
 )
 
 // Helper functions para crear errores
@@ -277,3 +329,7 @@ func ErrOnboardingRequired() *errx.Error {
 func ErrInvalidStatus() *errx.Error {
 	return ErrRegistry.New(CodeInvalidStatus)
 }
+
+func ErrNotHomeTenant() *errx.Error {
+	return ErrRegistry.New(CodeNotHomeTenant)
+}
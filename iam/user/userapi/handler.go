@@ -0,0 +1,68 @@
+package userapi
+
+import (
+	"strings"
+
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/iam/user"
+	"github.com/Abraxas-365/relay/iam/user/usersrv"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MemberHandler maneja los miembros invitados al tenant autenticado
+type MemberHandler struct {
+	userService *usersrv.UserService
+}
+
+// NewMemberHandler crea un nuevo handler de miembros
+func NewMemberHandler(userService *usersrv.UserService) *MemberHandler {
+	return &MemberHandler{
+		userService: userService,
+	}
+}
+
+// InviteMember da acceso al tenant a un usuario de otra empresa
+// POST /api/tenant/members
+func (h *MemberHandler) InviteMember(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req user.InviteUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	req.Email = strings.TrimSpace(req.Email)
+	req.HomeTenantRUC = strings.TrimSpace(req.HomeTenantRUC)
+	if req.Email == "" || req.HomeTenantRUC == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "email and home_tenant_ruc are required")
+	}
+
+	membership, err := h.userService.AddUserToTenant(c.Context(), authContext.TenantID, authContext.UserID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(membership)
+}
+
+// RemoveMember quita el acceso al tenant de un usuario invitado. No aplica a
+// los usuarios cuyo tenant principal es este
+// DELETE /api/tenant/members/:user_id
+func (h *MemberHandler) RemoveMember(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	userID := kernel.UserID(c.Params("user_id"))
+	if err := h.userService.RemoveUserFromTenant(c.Context(), userID, authContext.TenantID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,28 @@
+package userapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MemberRoutes configura las rutas de miembros invitados al tenant
+type MemberRoutes struct {
+	handler        *MemberHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewMemberRoutes crea una nueva instancia de rutas de miembros
+func NewMemberRoutes(handler *MemberHandler, authMiddleware *auth.AuthMiddleware) *MemberRoutes {
+	return &MemberRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registra las rutas en un router autenticado; solo administradores
+func (r *MemberRoutes) RegisterRoutes(router fiber.Router) {
+	members := router.Group("/tenant/members", r.authMiddleware.RequireAdmin())
+
+	members.Post("/", r.handler.InviteMember)
+	members.Delete("/:user_id", r.handler.RemoveMember)
+}
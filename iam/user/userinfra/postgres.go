@@ -30,9 +30,15 @@ func (r *PostgresUserRepository) FindByID(ctx context.Context, id kernel.UserID,
 		SELECT 
 			id, tenant_id, email, name, picture, status, is_admin,
 			oauth_provider, oauth_provider_id, email_verified, 
+			password_hash, failed_login_attempts, locked_until,
 			last_login_at, created_at, updated_at
 		FROM users 
-		WHERE id = $1 AND tenant_id = $2`
+		WHERE id = $1 AND (
+			tenant_id = $2 OR EXISTS(
+				SELECT 1 FROM user_tenant_memberships m
+				WHERE m.user_id = users.id AND m.tenant_id = $2
+			)
+		)`
 
 	var u user.User
 	err := r.db.GetContext(ctx, &u, query, id.String(), tenantID.String())
@@ -55,9 +61,17 @@ func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string,
 		SELECT 
 			id, tenant_id, email, name, picture, status, is_admin,
 			oauth_provider, oauth_provider_id, email_verified, 
+			password_hash, failed_login_attempts, locked_until,
 			last_login_at, created_at, updated_at
 		FROM users 
-		WHERE email = $1 AND tenant_id = $2`
+		WHERE email = $1 AND (
+			tenant_id = $2 OR EXISTS(
+				SELECT 1 FROM user_tenant_memberships m
+				WHERE m.user_id = users.id AND m.tenant_id = $2
+			)
+		)
+		ORDER BY (tenant_id = $2) DESC
+		LIMIT 1`
 
 	var u user.User
 	err := r.db.GetContext(ctx, &u, query, email, tenantID.String())
@@ -79,9 +93,13 @@ func (r *PostgresUserRepository) FindByTenant(ctx context.Context, tenantID kern
 		SELECT 
 			id, tenant_id, email, name, picture, status, is_admin,
 			oauth_provider, oauth_provider_id, email_verified, 
+			password_hash, failed_login_attempts, locked_until,
 			last_login_at, created_at, updated_at
 		FROM users 
-		WHERE tenant_id = $1
+		WHERE tenant_id = $1 OR EXISTS(
+			SELECT 1 FROM user_tenant_memberships m
+			WHERE m.user_id = users.id AND m.tenant_id = $1
+		)
 		ORDER BY name ASC`
 
 	var users []user.User
@@ -120,10 +138,12 @@ func (r *PostgresUserRepository) create(ctx context.Context, u user.User) error
 		INSERT INTO users (
 			id, tenant_id, email, name, picture, status, is_admin,
 			oauth_provider, oauth_provider_id, email_verified, 
+			password_hash, failed_login_attempts, locked_until,
 			last_login_at, created_at, updated_at
 		) VALUES (
 			:id, :tenant_id, :email, :name, :picture, :status, :is_admin,
 			:oauth_provider, :oauth_provider_id, :email_verified, 
+			:password_hash, :failed_login_attempts, :locked_until,
 			:last_login_at, :created_at, :updated_at
 		)`
 
@@ -157,6 +177,9 @@ func (r *PostgresUserRepository) update(ctx context.Context, u user.User) error
 			oauth_provider = :oauth_provider,
 			oauth_provider_id = :oauth_provider_id,
 			email_verified = :email_verified,
+			password_hash = :password_hash,
+			failed_login_attempts = :failed_login_attempts,
+			locked_until = :locked_until,
 			last_login_at = :last_login_at,
 			updated_at = :updated_at
 		WHERE id = :id AND tenant_id = :tenant_id`
@@ -212,7 +235,16 @@ func (r *PostgresUserRepository) Delete(ctx context.Context, id kernel.UserID, t
 
 // ExistsByEmail verifica si existe un usuario con el email dado en el tenant
 func (r *PostgresUserRepository) ExistsByEmail(ctx context.Context, email string, tenantID kernel.TenantID) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 AND tenant_id = $2)`
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM users
+			WHERE email = $1 AND (
+				tenant_id = $2 OR EXISTS(
+					SELECT 1 FROM user_tenant_memberships m
+					WHERE m.user_id = users.id AND m.tenant_id = $2
+				)
+			)
+		)`
 
 	var exists bool
 	err := r.db.GetContext(ctx, &exists, query, email, tenantID.String())
@@ -246,6 +278,7 @@ func (r *PostgresUserRepository) FindByStatus(ctx context.Context, status user.U
 		SELECT 
 			id, tenant_id, email, name, picture, status, is_admin,
 			oauth_provider, oauth_provider_id, email_verified, 
+			password_hash, failed_login_attempts, locked_until,
 			last_login_at, created_at, updated_at
 		FROM users 
 		WHERE status = $1 AND tenant_id = $2
@@ -293,6 +326,7 @@ func (r *PostgresUserRepository) FindByOAuthProvider(ctx context.Context, provid
 		SELECT 
 			id, tenant_id, email, name, picture, status, is_admin,
 			oauth_provider, oauth_provider_id, email_verified, 
+			password_hash, failed_login_attempts, locked_until,
 			last_login_at, created_at, updated_at
 		FROM users 
 		WHERE oauth_provider = $1 AND oauth_provider_id = $2 AND tenant_id = $3`
@@ -507,3 +541,107 @@ func (r *PostgresUserRoleRepository) HasRole(ctx context.Context, userID kernel.
 
 	return exists, nil
 }
+
+// ============================================================================
+// PostgresMembershipRepository
+// ============================================================================
+
+// PostgresMembershipRepository implementación de PostgreSQL para MembershipRepository
+type PostgresMembershipRepository struct {
+	db *sqlx.DB
+}
+
+// NewPostgresMembershipRepository crea una nueva instancia del repositorio de membresías
+func NewPostgresMembershipRepository(db *sqlx.DB) user.MembershipRepository {
+	return &PostgresMembershipRepository{
+		db: db,
+	}
+}
+
+// Find busca la membresía de un usuario en un tenant
+func (r *PostgresMembershipRepository) Find(ctx context.Context, userID kernel.UserID, tenantID kernel.TenantID) (*user.Membership, error) {
+	query := `
+		SELECT user_id, tenant_id, is_admin, status, invited_by, created_at
+		FROM user_tenant_memberships
+		WHERE user_id = $1 AND tenant_id = $2`
+
+	var m user.Membership
+	err := r.db.GetContext(ctx, &m, query, userID.String(), tenantID.String())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrUserNotInTenant().
+				WithDetail("user_id", userID.String()).
+				WithDetail("tenant_id", tenantID.String())
+		}
+		return nil, errx.Wrap(err, "failed to find membership", errx.TypeInternal).
+			WithDetail("user_id", userID.String()).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	return &m, nil
+}
+
+// FindByUser busca todas las membresías adicionales de un usuario
+func (r *PostgresMembershipRepository) FindByUser(ctx context.Context, userID kernel.UserID) ([]*user.Membership, error) {
+	query := `
+		SELECT user_id, tenant_id, is_admin, status, invited_by, created_at
+		FROM user_tenant_memberships
+		WHERE user_id = $1
+		ORDER BY created_at ASC`
+
+	var memberships []user.Membership
+	if err := r.db.SelectContext(ctx, &memberships, query, userID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to find user memberships", errx.TypeInternal).
+			WithDetail("user_id", userID.String())
+	}
+
+	result := make([]*user.Membership, len(memberships))
+	for i := range memberships {
+		result[i] = &memberships[i]
+	}
+
+	return result, nil
+}
+
+// Save crea o actualiza una membresía
+func (r *PostgresMembershipRepository) Save(ctx context.Context, m user.Membership) error {
+	query := `
+		INSERT INTO user_tenant_memberships (user_id, tenant_id, is_admin, status, invited_by, created_at)
+		VALUES (:user_id, :tenant_id, :is_admin, :status, :invited_by, :created_at)
+		ON CONFLICT (user_id, tenant_id) DO UPDATE SET
+			is_admin = EXCLUDED.is_admin,
+			status = EXCLUDED.status`
+
+	if _, err := r.db.NamedExecContext(ctx, query, m); err != nil {
+		return errx.Wrap(err, "failed to save membership", errx.TypeInternal).
+			WithDetail("user_id", m.UserID.String()).
+			WithDetail("tenant_id", m.TenantID.String())
+	}
+
+	return nil
+}
+
+// Delete elimina la membresía de un usuario en un tenant
+func (r *PostgresMembershipRepository) Delete(ctx context.Context, userID kernel.UserID, tenantID kernel.TenantID) error {
+	query := `DELETE FROM user_tenant_memberships WHERE user_id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, userID.String(), tenantID.String())
+	if err != nil {
+		return errx.Wrap(err, "failed to delete membership", errx.TypeInternal).
+			WithDetail("user_id", userID.String()).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+
+	if rowsAffected == 0 {
+		return user.ErrUserNotInTenant().
+			WithDetail("user_id", userID.String()).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	return nil
+}
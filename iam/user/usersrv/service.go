@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/iam"
 	"github.com/Abraxas-365/relay/iam/role"
 	"github.com/Abraxas-365/relay/iam/tenant"
 	"github.com/Abraxas-365/relay/iam/user"
@@ -19,6 +20,8 @@ type UserService struct {
 	tenantRepo   tenant.TenantRepository
 	roleRepo     role.RoleRepository
 	passwordSvc  user.PasswordService
+
+	membershipRepo user.MembershipRepository
 }
 
 // NewUserService crea una nueva instancia del servicio de usuarios
@@ -28,13 +31,15 @@ func NewUserService(
 	tenantRepo tenant.TenantRepository,
 	roleRepo role.RoleRepository,
 	passwordSvc user.PasswordService,
+	membershipRepo user.MembershipRepository,
 ) *UserService {
 	return &UserService{
-		userRepo:     userRepo,
-		userRoleRepo: userRoleRepo,
-		tenantRepo:   tenantRepo,
-		roleRepo:     roleRepo,
-		passwordSvc:  passwordSvc,
+		userRepo:       userRepo,
+		userRoleRepo:   userRoleRepo,
+		tenantRepo:     tenantRepo,
+		roleRepo:       roleRepo,
+		passwordSvc:    passwordSvc,
+		membershipRepo: membershipRepo,
 	}
 }
 
@@ -158,13 +163,18 @@ func (s *UserService) GetUsersByTenant(ctx context.Context, tenantID kernel.Tena
 	}, nil
 }
 
-// UpdateUser actualiza un usuario
+// UpdateUser actualiza un usuario. Desde un tenant que no es el principal del
+// usuario solo se cambian el estado y el rol de admin de su membresía
 func (s *UserService) UpdateUser(ctx context.Context, userID kernel.UserID, req user.UpdateUserRequest, updaterID kernel.UserID) (*user.User, error) {
 	userEntity, err := s.userRepo.FindByID(ctx, userID, req.TenantID)
 	if err != nil {
 		return nil, user.ErrUserNotFound()
 	}
 
+	if !userEntity.IsHomeTenant(req.TenantID) {
+		return s.updateMembership(ctx, userEntity, req)
+	}
+
 	// Actualizar campos si se proporcionaron
 	if req.Name != nil {
 		userEntity.Name = *req.Name
@@ -199,13 +209,63 @@ func (s *UserService) UpdateUser(ctx context.Context, userID kernel.UserID, req
 	return userEntity, nil
 }
 
-// ActivateUser activa un usuario pendiente
+// updateMembership aplica una actualización sobre la membresía del usuario en
+// req.TenantID, sin tocar su registro global
+func (s *UserService) updateMembership(ctx context.Context, userEntity *user.User, req user.UpdateUserRequest) (*user.User, error) {
+	if req.Name != nil {
+		return nil, user.ErrNotHomeTenant()
+	}
+
+	membership, err := s.membershipRepo.Find(ctx, userEntity.ID, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Status != nil {
+		switch *req.Status {
+		case user.UserStatusActive:
+			if err := membership.Activate(); err != nil {
+				return nil, err
+			}
+		case user.UserStatusSuspended:
+			if err := membership.Suspend(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, user.ErrInvalidStatus().WithDetail("status", *req.Status)
+		}
+	}
+	if req.IsAdmin != nil {
+		membership.IsAdmin = *req.IsAdmin
+	}
+
+	if err := s.membershipRepo.Save(ctx, *membership); err != nil {
+		return nil, errx.Wrap(err, "failed to update membership", errx.TypeInternal)
+	}
+
+	inTenant := membership.InTenant(*userEntity)
+	return &inTenant, nil
+}
+
+// ActivateUser activa un usuario pendiente. Desde un tenant que no es el
+// principal del usuario reactiva su membresía
 func (s *UserService) ActivateUser(ctx context.Context, userID kernel.UserID, tenantID kernel.TenantID) error {
 	userEntity, err := s.userRepo.FindByID(ctx, userID, tenantID)
 	if err != nil {
 		return user.ErrUserNotFound()
 	}
 
+	if !userEntity.IsHomeTenant(tenantID) {
+		membership, err := s.membershipRepo.Find(ctx, userID, tenantID)
+		if err != nil {
+			return err
+		}
+		if err := membership.Activate(); err != nil {
+			return err
+		}
+		return s.membershipRepo.Save(ctx, *membership)
+	}
+
 	if err := userEntity.Activate(); err != nil {
 		return err
 	}
@@ -213,13 +273,25 @@ func (s *UserService) ActivateUser(ctx context.Context, userID kernel.UserID, te
 	return s.userRepo.Save(ctx, *userEntity)
 }
 
-// SuspendUser suspende un usuario
+// SuspendUser suspende un usuario. Desde un tenant que no es el principal del
+// usuario solo suspende su membresía
 func (s *UserService) SuspendUser(ctx context.Context, userID kernel.UserID, tenantID kernel.TenantID, reason string) error {
 	userEntity, err := s.userRepo.FindByID(ctx, userID, tenantID)
 	if err != nil {
 		return user.ErrUserNotFound()
 	}
 
+	if !userEntity.IsHomeTenant(tenantID) {
+		membership, err := s.membershipRepo.Find(ctx, userID, tenantID)
+		if err != nil {
+			return err
+		}
+		if err := membership.Suspend(); err != nil {
+			return err
+		}
+		return s.membershipRepo.Save(ctx, *membership)
+	}
+
 	if err := userEntity.Suspend(reason); err != nil {
 		return err
 	}
@@ -283,11 +355,16 @@ func (s *UserService) GetUserRoles(ctx context.Context, userID kernel.UserID, te
 // DeleteUser elimina un usuario
 func (s *UserService) DeleteUser(ctx context.Context, userID kernel.UserID, tenantID kernel.TenantID) error {
 	// Verificar que el usuario existe
-	_, err := s.userRepo.FindByID(ctx, userID, tenantID)
+	userEntity, err := s.userRepo.FindByID(ctx, userID, tenantID)
 	if err != nil {
 		return user.ErrUserNotFound()
 	}
 
+	// Si el tenant no es el principal, solo se quita la membresía
+	if !userEntity.IsHomeTenant(tenantID) {
+		return s.RemoveUserFromTenant(ctx, userID, tenantID)
+	}
+
 	// Remover todos los roles del usuario
 	if err := s.userRoleRepo.RemoveAllUserRoles(ctx, userID); err != nil {
 		// Log error pero continúar
@@ -308,6 +385,125 @@ func (s *UserService) DeleteUser(ctx context.Context, userID kernel.UserID, tena
 	return nil
 }
 
+// ============================================================================
+// Membresías
+// ============================================================================
+
+// AddUserToTenant da acceso a un usuario existente de otra empresa al tenant
+// del invitador, que debe ser admin en ese tenant
+func (s *UserService) AddUserToTenant(ctx context.Context, tenantID kernel.TenantID, inviterID kernel.UserID, req user.InviteUserRequest) (*user.Membership, error) {
+	if err := s.requireAdminIn(ctx, inviterID, tenantID); err != nil {
+		return nil, err
+	}
+
+	// El usuario se identifica por su email en su empresa principal
+	homeTenant, err := s.tenantRepo.FindByRUC(ctx, req.HomeTenantRUC)
+	if err != nil {
+		return nil, user.ErrUserNotFound().WithDetail("email", req.Email)
+	}
+
+	userEntity, err := s.userRepo.FindByEmail(ctx, req.Email, homeTenant.ID)
+	if err != nil || !userEntity.IsHomeTenant(homeTenant.ID) {
+		return nil, user.ErrUserNotFound().WithDetail("email", req.Email)
+	}
+
+	if userEntity.IsHomeTenant(tenantID) {
+		return nil, user.ErrUserAlreadyExists().WithDetail("tenant_id", tenantID.String())
+	}
+
+	tenantEntity, err := s.tenantRepo.FindByID(ctx, tenantID)
+	if err != nil {
+		return nil, tenant.ErrTenantNotFound()
+	}
+
+	if !tenantEntity.IsActive() {
+		return nil, tenant.ErrTenantSuspended()
+	}
+
+	// Una membresía ya existente solo actualiza el flag de admin y conserva
+	// su estado
+	membership, err := s.membershipRepo.Find(ctx, userEntity.ID, tenantID)
+	isNew := err != nil
+
+	if isNew {
+		if !tenantEntity.CanAddUser() {
+			return nil, tenant.ErrMaxUsersReached()
+		}
+		membership = user.NewMembership(userEntity.ID, tenantID, req.IsAdmin, &inviterID)
+	} else {
+		membership.IsAdmin = req.IsAdmin
+	}
+
+	if err := s.membershipRepo.Save(ctx, *membership); err != nil {
+		return nil, errx.Wrap(err, "failed to save membership", errx.TypeInternal)
+	}
+
+	if isNew {
+		if err := tenantEntity.AddUser(); err == nil {
+			s.tenantRepo.Save(ctx, *tenantEntity)
+		}
+	}
+
+	return membership, nil
+}
+
+// RemoveUserFromTenant quita el acceso de un usuario a un tenant adicional
+func (s *UserService) RemoveUserFromTenant(ctx context.Context, userID kernel.UserID, tenantID kernel.TenantID) error {
+	if err := s.membershipRepo.Delete(ctx, userID, tenantID); err != nil {
+		return err
+	}
+
+	// Decrementar contador de usuarios del tenant
+	tenantEntity, err := s.tenantRepo.FindByID(ctx, tenantID)
+	if err == nil {
+		tenantEntity.RemoveUser()
+		s.tenantRepo.Save(ctx, *tenantEntity)
+	}
+
+	return nil
+}
+
+// GetUserTenants lista todos los tenants a los que el usuario tiene acceso,
+// empezando por su tenant principal
+func (s *UserService) GetUserTenants(ctx context.Context, userID kernel.UserID, tenantID kernel.TenantID) ([]user.TenantAccess, error) {
+	userEntity, err := s.userRepo.FindByID(ctx, userID, tenantID)
+	if err != nil {
+		return nil, user.ErrUserNotFound()
+	}
+
+	memberships, err := s.membershipRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return user.BuildTenantAccess(userEntity, memberships), nil
+}
+
+// requireAdminIn verifica que el usuario sea admin en el tenant, ya sea su
+// tenant principal o uno al que pertenece con una membresía activa
+func (s *UserService) requireAdminIn(ctx context.Context, userID kernel.UserID, tenantID kernel.TenantID) error {
+	userEntity, err := s.userRepo.FindByID(ctx, userID, tenantID)
+	if err != nil {
+		return user.ErrUserNotInTenant().WithDetail("tenant_id", tenantID.String())
+	}
+
+	if userEntity.IsHomeTenant(tenantID) {
+		if !userEntity.IsAdmin || !userEntity.IsActive() {
+			return iam.ErrAccessDenied()
+		}
+		return nil
+	}
+
+	membership, err := s.membershipRepo.Find(ctx, userID, tenantID)
+	if err != nil {
+		return err
+	}
+	if !membership.IsAdmin || !membership.IsActive() {
+		return iam.ErrAccessDenied()
+	}
+	return nil
+}
+
 // Helper function to assign multiple roles to user
 func (s *UserService) assignRolesToUser(ctx context.Context, userID kernel.UserID, roleIDs []kernel.RoleID) error {
 	for _, roleID := range roleIDs {
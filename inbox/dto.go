@@ -0,0 +1,14 @@
+package inbox
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// SendMessageRequest is an operator's reply in a conversation: either Text
+// or a snippet rendered with Variables
+type SendMessageRequest struct {
+	Text      string            `json:"text,omitempty"`
+	SnippetID string            `json:"snippet_id,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+	ReplyToID string            `json:"reply_to_id,omitempty"`
+}
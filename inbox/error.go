@@ -0,0 +1,54 @@
+package inbox
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("INBOX")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeConversationClaimed = ErrRegistry.Register("CONVERSATION_CLAIMED", errx.TypeConflict, http.StatusConflict, "Conversation is claimed by another operator")
+	CodeClaimNotFound       = ErrRegistry.Register("CLAIM_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Conversation is not claimed")
+	CodeNotClaimOwner       = ErrRegistry.Register("NOT_CLAIM_OWNER", errx.TypeAuthorization, http.StatusForbidden, "Conversation is claimed by another operator")
+	CodeInvalidConversation = ErrRegistry.Register("INVALID_CONVERSATION", errx.TypeValidation, http.StatusBadRequest, "Invalid conversation")
+	CodeInvalidMessage      = ErrRegistry.Register("INVALID_MESSAGE", errx.TypeValidation, http.StatusBadRequest, "Invalid message")
+	CodeSendingUnavailable  = ErrRegistry.Register("SENDING_UNAVAILABLE", errx.TypeInternal, http.StatusServiceUnavailable, "Sending is not configured")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrConversationClaimed() *errx.Error {
+	return ErrRegistry.New(CodeConversationClaimed)
+}
+
+func ErrClaimNotFound() *errx.Error {
+	return ErrRegistry.New(CodeClaimNotFound)
+}
+
+func ErrNotClaimOwner() *errx.Error {
+	return ErrRegistry.New(CodeNotClaimOwner)
+}
+
+func ErrInvalidConversation() *errx.Error {
+	return ErrRegistry.New(CodeInvalidConversation)
+}
+
+func ErrInvalidMessage() *errx.Error {
+	return ErrRegistry.New(CodeInvalidMessage)
+}
+
+func ErrSendingUnavailable() *errx.Error {
+	return ErrRegistry.New(CodeSendingUnavailable)
+}
@@ -0,0 +1,59 @@
+package inbox
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Claims
+// ============================================================================
+
+// Claim is an operator taking over a conversation. A conversation is the
+// contact's thread on one channel, so it is keyed by channel and contact.
+type Claim struct {
+	TenantID       kernel.TenantID  `json:"tenant_id"`
+	ChannelID      kernel.ChannelID `json:"channel_id"`
+	ConversationID string           `json:"conversation_id"`
+	UserID         kernel.UserID    `json:"user_id"`
+	ClaimedAt      time.Time        `json:"claimed_at"`
+}
+
+// ============================================================================
+// Live Events
+// ============================================================================
+
+// EventKind is what happened in a conversation
+type EventKind string
+
+const (
+	EventMessage    EventKind = "message"    // A contact wrote in a claimed conversation
+	EventAssignment EventKind = "assignment" // A conversation was claimed or released
+	EventTyping     EventKind = "typing"     // An operator is typing in a conversation
+)
+
+// Event is one live update streamed to operators
+type Event struct {
+	Kind           EventKind        `json:"kind"`
+	TenantID       kernel.TenantID  `json:"tenant_id"`
+	ChannelID      kernel.ChannelID `json:"channel_id"`
+	ConversationID string           `json:"conversation_id"`
+
+	// AssignedTo is the operator owning the conversation; empty once released
+	AssignedTo kernel.UserID `json:"assigned_to,omitempty"`
+
+	// UserID is the operator behind an assignment or typing event
+	UserID kernel.UserID `json:"user_id,omitempty"`
+
+	// Message is the canonical inbound payload workflows also receive
+	Message map[string]any `json:"message,omitempty"`
+
+	At time.Time `json:"at"`
+}
+
+// Topic is the event bus topic carrying a tenant's inbox events, so one
+// tenant's operators never see another's conversations
+func Topic(tenantID kernel.TenantID) string {
+	return "inbox." + tenantID.String()
+}
@@ -0,0 +1,207 @@
+package inboxapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/inbox"
+	"github.com/Abraxas-365/relay/inbox/inboxsrv"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// heartbeatInterval keeps idle streams open through proxies that close
+// silent connections
+const heartbeatInterval = 25 * time.Second
+
+// InboxHandler exposes conversation claims and the operators' live stream
+type InboxHandler struct {
+	service *inboxsrv.InboxService
+	hub     *inboxsrv.Hub
+}
+
+// NewInboxHandler creates a new inbox handler
+func NewInboxHandler(service *inboxsrv.InboxService, hub *inboxsrv.Hub) *InboxHandler {
+	return &InboxHandler{
+		service: service,
+		hub:     hub,
+	}
+}
+
+// ============================================================================
+// Live Stream
+// ============================================================================
+
+// Stream sends the operator's inbox events as Server-Sent Events. Browsers'
+// EventSource cannot set headers, so the access_token cookie authenticates
+// it as well as a Bearer token.
+// GET /api/inbox/stream?channel_id=&conversation_id=
+func (h *InboxHandler) Stream(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	sub := &inboxsrv.Subscription{
+		TenantID:       authContext.TenantID,
+		UserID:         authContext.UserID,
+		ChannelID:      kernel.ChannelID(c.Query("channel_id")),
+		ConversationID: c.Query("conversation_id"),
+	}
+	if err := h.hub.Subscribe(c.Context(), sub); err != nil {
+		return err
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	// The request context is released when the handler returns, so the
+	// writer only touches what was captured above
+	hub := h.hub
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer hub.Unsubscribe(context.Background(), sub)
+
+		log.Printf("📡 Inbox stream opened for user %s", sub.UserID.String())
+		fmt.Fprint(w, "retry: 3000\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					log.Printf("⚠️  Failed to encode inbox event: %v", err)
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Kind, data)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+
+			// A failed flush means the client went away
+			if err := w.Flush(); err != nil {
+				log.Printf("📡 Inbox stream closed for user %s", sub.UserID.String())
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// ============================================================================
+// Claims
+// ============================================================================
+
+// ListClaims returns the tenant's claimed conversations; ?mine=true keeps
+// only the caller's
+// GET /api/inbox/claims
+func (h *InboxHandler) ListClaims(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var userID kernel.UserID
+	if c.QueryBool("mine") {
+		userID = authContext.UserID
+	}
+
+	claims, err := h.service.ListClaims(c.Context(), authContext.TenantID, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"claims": claims})
+}
+
+// Claim assigns the conversation to the caller
+// POST /api/inbox/conversations/:channel_id/:conversation_id/claim
+func (h *InboxHandler) Claim(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	claim, err := h.service.Claim(c.Context(), authContext.TenantID,
+		kernel.ChannelID(c.Params("channel_id")), c.Params("conversation_id"), authContext.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(claim)
+}
+
+// Release frees the conversation; admins can release anyone's
+// DELETE /api/inbox/conversations/:channel_id/:conversation_id/claim
+func (h *InboxHandler) Release(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	err := h.service.Release(c.Context(), authContext.TenantID,
+		kernel.ChannelID(c.Params("channel_id")), c.Params("conversation_id"),
+		authContext.UserID, authContext.IsAdmin)
+	if err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Send replies to the contact with text or a snippet
+// POST /api/inbox/conversations/:channel_id/:conversation_id/messages
+func (h *InboxHandler) Send(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req inbox.SendMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return inbox.ErrInvalidMessage().WithDetail("reason", err.Error())
+	}
+
+	response, err := h.service.Send(c.Context(), authContext.TenantID,
+		kernel.ChannelID(c.Params("channel_id")), c.Params("conversation_id"),
+		authContext.UserID, authContext.IsAdmin, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response)
+}
+
+// Typing tells the other operators on the conversation the caller is typing
+// POST /api/inbox/conversations/:channel_id/:conversation_id/typing
+func (h *InboxHandler) Typing(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	err := h.service.Typing(c.Context(), authContext.TenantID,
+		kernel.ChannelID(c.Params("channel_id")), c.Params("conversation_id"), authContext.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusAccepted)
+}
@@ -0,0 +1,34 @@
+package inboxapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// InboxRoutes handles agent inbox route setup
+type InboxRoutes struct {
+	handler        *InboxHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewInboxRoutes creates a new inbox routes instance
+func NewInboxRoutes(handler *InboxHandler, authMiddleware *auth.AuthMiddleware) *InboxRoutes {
+	return &InboxRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers inbox routes on an authenticated router
+func (r *InboxRoutes) RegisterRoutes(router fiber.Router) {
+	inbox := router.Group("/inbox")
+
+	inbox.Get("/stream", r.handler.Stream)
+	inbox.Get("/claims", r.handler.ListClaims)
+
+	conversation := inbox.Group("/conversations/:channel_id/:conversation_id")
+	conversation.Post("/claim", r.handler.Claim)
+	conversation.Delete("/claim", r.handler.Release)
+	conversation.Post("/messages", r.handler.Send)
+	conversation.Post("/typing", r.handler.Typing)
+}
@@ -0,0 +1,129 @@
+package inboxinfra
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/inbox"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresClaimRepository struct {
+	db *sqlx.DB
+}
+
+var _ inbox.ClaimRepository = (*PostgresClaimRepository)(nil)
+
+func NewPostgresClaimRepository(db *sqlx.DB) *PostgresClaimRepository {
+	return &PostgresClaimRepository{db: db}
+}
+
+// dbClaim is an intermediate struct for database operations
+type dbClaim struct {
+	TenantID       string    `db:"tenant_id"`
+	ChannelID      string    `db:"channel_id"`
+	ConversationID string    `db:"conversation_id"`
+	UserID         string    `db:"user_id"`
+	ClaimedAt      time.Time `db:"claimed_at"`
+}
+
+const claimColumns = `tenant_id, channel_id, conversation_id, user_id, claimed_at`
+
+func (r *PostgresClaimRepository) Claim(ctx context.Context, claim inbox.Claim) error {
+	// The conflict update only matches the owner's own row, so a claim held
+	// by someone else returns no row
+	query := `
+		INSERT INTO conversation_claims (tenant_id, channel_id, conversation_id, user_id, claimed_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, channel_id, conversation_id) DO UPDATE
+			SET updated_at = NOW()
+			WHERE conversation_claims.user_id = EXCLUDED.user_id
+		RETURNING user_id`
+
+	var owner string
+	err := r.db.QueryRowContext(ctx, query,
+		claim.TenantID.String(), claim.ChannelID.String(), claim.ConversationID,
+		claim.UserID.String(), claim.ClaimedAt,
+	).Scan(&owner)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return inbox.ErrConversationClaimed().
+				WithDetail("channel_id", claim.ChannelID.String()).
+				WithDetail("conversation_id", claim.ConversationID)
+		}
+		return errx.Wrap(err, "failed to claim conversation", errx.TypeInternal).
+			WithDetail("conversation_id", claim.ConversationID)
+	}
+
+	return nil
+}
+
+func (r *PostgresClaimRepository) Release(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string) error {
+	query := `DELETE FROM conversation_claims WHERE tenant_id = $1 AND channel_id = $2 AND conversation_id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, tenantID.String(), channelID.String(), conversationID)
+	if err != nil {
+		return errx.Wrap(err, "failed to release conversation", errx.TypeInternal).
+			WithDetail("conversation_id", conversationID)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errx.Wrap(err, "failed to read release result", errx.TypeInternal)
+	}
+	if affected == 0 {
+		return inbox.ErrClaimNotFound().WithDetail("conversation_id", conversationID)
+	}
+
+	return nil
+}
+
+func (r *PostgresClaimRepository) FindByConversation(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string) (*inbox.Claim, error) {
+	query := `SELECT ` + claimColumns + ` FROM conversation_claims
+		WHERE tenant_id = $1 AND channel_id = $2 AND conversation_id = $3`
+
+	var row dbClaim
+	if err := r.db.GetContext(ctx, &row, query, tenantID.String(), channelID.String(), conversationID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errx.Wrap(err, "failed to find conversation claim", errx.TypeInternal).
+			WithDetail("conversation_id", conversationID)
+	}
+
+	return toDomainClaim(&row), nil
+}
+
+func (r *PostgresClaimRepository) List(ctx context.Context, tenantID kernel.TenantID, userID kernel.UserID) ([]*inbox.Claim, error) {
+	query := `SELECT ` + claimColumns + ` FROM conversation_claims WHERE tenant_id = $1`
+	args := []any{tenantID.String()}
+	if userID != "" {
+		query += ` AND user_id = $2`
+		args = append(args, userID.String())
+	}
+	query += ` ORDER BY claimed_at DESC`
+
+	var rows []dbClaim
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, errx.Wrap(err, "failed to list conversation claims", errx.TypeInternal)
+	}
+
+	claims := make([]*inbox.Claim, len(rows))
+	for i := range rows {
+		claims[i] = toDomainClaim(&rows[i])
+	}
+	return claims, nil
+}
+
+func toDomainClaim(row *dbClaim) *inbox.Claim {
+	return &inbox.Claim{
+		TenantID:       kernel.TenantID(row.TenantID),
+		ChannelID:      kernel.ChannelID(row.ChannelID),
+		ConversationID: row.ConversationID,
+		UserID:         kernel.UserID(row.UserID),
+		ClaimedAt:      row.ClaimedAt,
+	}
+}
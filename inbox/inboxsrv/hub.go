@@ -0,0 +1,148 @@
+package inboxsrv
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/relay/inbox"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// subscriberBuffer is how many events a slow operator can fall behind
+// before newer ones are dropped for them
+const subscriberBuffer = 64
+
+// Subscription is one operator's open stream
+type Subscription struct {
+	TenantID kernel.TenantID
+	UserID   kernel.UserID
+
+	// ChannelID and ConversationID, when set, also stream the messages and
+	// typing of a conversation the operator is watching but does not own
+	ChannelID      kernel.ChannelID
+	ConversationID string
+
+	events chan inbox.Event
+}
+
+// Events delivers the subscription's events until it is closed
+func (s *Subscription) Events() <-chan inbox.Event {
+	return s.events
+}
+
+// wants decides whether an event reaches this operator. Assignment changes
+// go to every operator of the tenant so their queues stay current; messages
+// and typing only to the owner and whoever watches the conversation.
+func (s *Subscription) wants(event inbox.Event) bool {
+	if event.Kind == inbox.EventAssignment {
+		return true
+	}
+	if event.Kind == inbox.EventTyping && event.UserID == s.UserID {
+		return false // The typist's own keystrokes
+	}
+	if event.AssignedTo != "" && event.AssignedTo == s.UserID {
+		return true
+	}
+	return s.ConversationID != "" &&
+		s.ChannelID == event.ChannelID &&
+		s.ConversationID == event.ConversationID
+}
+
+// Hub fans a tenant's inbox topic out to its connected operators. It holds
+// one bus subscription per tenant with operators online, dropped when the
+// last one leaves.
+type Hub struct {
+	bus eventx.EventBus
+
+	mu      sync.Mutex
+	tenants map[kernel.TenantID]map[*Subscription]struct{}
+}
+
+func NewHub(bus eventx.EventBus) *Hub {
+	return &Hub{
+		bus:     bus,
+		tenants: make(map[kernel.TenantID]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe opens a stream; callers must Unsubscribe when the client leaves
+func (h *Hub) Subscribe(ctx context.Context, sub *Subscription) error {
+	sub.events = make(chan inbox.Event, subscriberBuffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs, ok := h.tenants[sub.TenantID]
+	if !ok {
+		if err := h.bus.Subscribe(ctx, inbox.Topic(sub.TenantID), h.deliver); err != nil {
+			return err
+		}
+		subs = make(map[*Subscription]struct{})
+		h.tenants[sub.TenantID] = subs
+	}
+	subs[sub] = struct{}{}
+
+	return nil
+}
+
+// Unsubscribe closes a stream
+func (h *Hub) Unsubscribe(ctx context.Context, sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs, ok := h.tenants[sub.TenantID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	close(sub.events)
+
+	if len(subs) == 0 {
+		delete(h.tenants, sub.TenantID)
+		// The hub is the topic's only handler, so dropping them all is safe
+		if err := h.bus.Unsubscribe(ctx, inbox.Topic(sub.TenantID)); err != nil {
+			log.Printf("⚠️  Failed to unsubscribe inbox topic for tenant %s: %v", sub.TenantID.String(), err)
+		}
+	}
+}
+
+// Connected counts the open streams
+func (h *Hub) Connected() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	total := 0
+	for _, subs := range h.tenants {
+		total += len(subs)
+	}
+	return total
+}
+
+// deliver handles a bus event without blocking the publisher: an operator
+// whose buffer is full misses the event rather than stalling the webhook
+func (h *Hub) deliver(e eventx.Event) error {
+	event, ok := e.Payload().(inbox.Event)
+	if !ok {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.tenants[event.TenantID] {
+		if !sub.wants(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			log.Printf("⚠️  Inbox stream of user %s is full, dropped %s event", sub.UserID.String(), event.Kind)
+		}
+	}
+	return nil
+}
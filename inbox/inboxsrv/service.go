@@ -0,0 +1,222 @@
+package inboxsrv
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/inbox"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// InboxService lets operators claim conversations, reply in them and
+// publishes what happens in them to the tenant's inbox topic
+type InboxService struct {
+	claimRepo inbox.ClaimRepository
+	bus       eventx.EventBus
+	sender    inbox.MessageSender   // nil = replies are rejected
+	snippets  inbox.SnippetRenderer // nil = snippet replies are rejected
+}
+
+var _ channels.InboundListener = (*InboxService)(nil)
+
+func NewInboxService(
+	claimRepo inbox.ClaimRepository,
+	bus eventx.EventBus,
+	sender inbox.MessageSender,
+	snippets inbox.SnippetRenderer,
+) *InboxService {
+	return &InboxService{
+		claimRepo: claimRepo,
+		bus:       bus,
+		sender:    sender,
+		snippets:  snippets,
+	}
+}
+
+// ============================================================================
+// Claims
+// ============================================================================
+
+// Claim assigns the conversation to the operator
+func (s *InboxService) Claim(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string, userID kernel.UserID) (*inbox.Claim, error) {
+	if err := validateConversation(channelID, conversationID); err != nil {
+		return nil, err
+	}
+
+	claim := inbox.Claim{
+		TenantID:       tenantID,
+		ChannelID:      channelID,
+		ConversationID: conversationID,
+		UserID:         userID,
+		ClaimedAt:      time.Now(),
+	}
+	if err := s.claimRepo.Claim(ctx, claim); err != nil {
+		return nil, err
+	}
+
+	log.Printf("🙋 User %s claimed conversation %s on channel %s", userID.String(), conversationID, channelID.String())
+	s.publish(ctx, inbox.Event{
+		Kind:           inbox.EventAssignment,
+		TenantID:       tenantID,
+		ChannelID:      channelID,
+		ConversationID: conversationID,
+		AssignedTo:     userID,
+		UserID:         userID,
+	})
+
+	return &claim, nil
+}
+
+// Release frees the conversation. Only its owner or an admin can release it.
+func (s *InboxService) Release(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string, userID kernel.UserID, isAdmin bool) error {
+	if err := validateConversation(channelID, conversationID); err != nil {
+		return err
+	}
+
+	claim, err := s.claimRepo.FindByConversation(ctx, tenantID, channelID, conversationID)
+	if err != nil {
+		return err
+	}
+	if claim == nil {
+		return inbox.ErrClaimNotFound().WithDetail("conversation_id", conversationID)
+	}
+	if claim.UserID != userID && !isAdmin {
+		return inbox.ErrNotClaimOwner().WithDetail("conversation_id", conversationID)
+	}
+
+	if err := s.claimRepo.Release(ctx, tenantID, channelID, conversationID); err != nil {
+		return err
+	}
+
+	log.Printf("👋 User %s released conversation %s on channel %s", userID.String(), conversationID, channelID.String())
+	s.publish(ctx, inbox.Event{
+		Kind:           inbox.EventAssignment,
+		TenantID:       tenantID,
+		ChannelID:      channelID,
+		ConversationID: conversationID,
+		UserID:         userID,
+	})
+
+	return nil
+}
+
+// ListClaims returns the tenant's claims, only userID's when it is not empty
+func (s *InboxService) ListClaims(ctx context.Context, tenantID kernel.TenantID, userID kernel.UserID) ([]*inbox.Claim, error) {
+	return s.claimRepo.List(ctx, tenantID, userID)
+}
+
+// ============================================================================
+// Replies
+// ============================================================================
+
+// Send replies to the contact as the operator, with text or a snippet. A
+// conversation claimed by someone else only takes replies from its owner
+// or an admin.
+func (s *InboxService) Send(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string, userID kernel.UserID, isAdmin bool, req inbox.SendMessageRequest) (*channels.SendMessageResponse, error) {
+	if err := validateConversation(channelID, conversationID); err != nil {
+		return nil, err
+	}
+	if s.sender == nil {
+		return nil, inbox.ErrSendingUnavailable()
+	}
+
+	claim, err := s.claimRepo.FindByConversation(ctx, tenantID, channelID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if claim != nil && claim.UserID != userID && !isAdmin {
+		return nil, inbox.ErrNotClaimOwner().WithDetail("conversation_id", conversationID)
+	}
+
+	text := req.Text
+	if req.SnippetID != "" {
+		if s.snippets == nil {
+			return nil, inbox.ErrSendingUnavailable().WithDetail("reason", "snippets are not configured")
+		}
+		if text, err = s.snippets.RenderSnippet(ctx, tenantID, req.SnippetID, req.Variables); err != nil {
+			return nil, err
+		}
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, inbox.ErrInvalidMessage().WithDetail("reason", "text or snippet_id is required")
+	}
+
+	return s.sender.SendManualMessage(ctx, tenantID, userID, channels.SendMessageRequest{
+		ChannelID:   channelID,
+		RecipientID: conversationID,
+		Content:     channels.MessageContent{Type: "text", Text: text},
+		ReplyToID:   req.ReplyToID,
+	})
+}
+
+// ============================================================================
+// Live Events
+// ============================================================================
+
+// Typing tells the other operators on the conversation that userID is typing
+func (s *InboxService) Typing(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string, userID kernel.UserID) error {
+	if err := validateConversation(channelID, conversationID); err != nil {
+		return err
+	}
+
+	claim, err := s.claimRepo.FindByConversation(ctx, tenantID, channelID, conversationID)
+	if err != nil {
+		return err
+	}
+
+	event := inbox.Event{
+		Kind:           inbox.EventTyping,
+		TenantID:       tenantID,
+		ChannelID:      channelID,
+		ConversationID: conversationID,
+		UserID:         userID,
+	}
+	if claim != nil {
+		event.AssignedTo = claim.UserID
+	}
+	s.publish(ctx, event)
+
+	return nil
+}
+
+// OnInboundMessage implements channels.InboundListener: a message in a
+// claimed conversation is streamed to its operator
+func (s *InboxService) OnInboundMessage(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage) {
+	claim, err := s.claimRepo.FindByConversation(ctx, channel.TenantID, channel.ID, msg.SenderID)
+	if err != nil {
+		log.Printf("⚠️  Failed to look up inbox claim for %s: %v", msg.SenderID, err)
+		return
+	}
+	if claim == nil {
+		return
+	}
+
+	s.publish(ctx, inbox.Event{
+		Kind:           inbox.EventMessage,
+		TenantID:       channel.TenantID,
+		ChannelID:      channel.ID,
+		ConversationID: msg.SenderID,
+		AssignedTo:     claim.UserID,
+		Message:        channels.TriggerPayload(channel, msg),
+	})
+}
+
+// publish puts the event on the tenant's topic; a failure only costs the
+// live update, never the operation behind it
+func (s *InboxService) publish(ctx context.Context, event inbox.Event) {
+	event.At = time.Now()
+	if err := s.bus.Publish(ctx, eventx.NewEvent(inbox.Topic(event.TenantID), event)); err != nil {
+		log.Printf("⚠️  Failed to publish inbox %s event: %v", event.Kind, err)
+	}
+}
+
+func validateConversation(channelID kernel.ChannelID, conversationID string) error {
+	if channelID == "" || strings.TrimSpace(conversationID) == "" {
+		return inbox.ErrInvalidConversation().WithDetail("reason", "channel_id and conversation_id are required")
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package inbox
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// ClaimRepository persists which operator owns each conversation
+type ClaimRepository interface {
+	// Claim assigns the conversation to claim.UserID; ErrConversationClaimed
+	// when another operator already owns it. Claiming one's own conversation
+	// again is a no-op.
+	Claim(ctx context.Context, claim Claim) error
+
+	// Release frees the conversation; ErrClaimNotFound when it is not claimed
+	Release(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string) error
+
+	// FindByConversation returns the conversation's claim, or nil when no
+	// operator owns it
+	FindByConversation(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string) (*Claim, error)
+
+	// List returns the tenant's claims, only userID's when it is not empty
+	List(ctx context.Context, tenantID kernel.TenantID, userID kernel.UserID) ([]*Claim, error)
+}
+
+// ============================================================================
+// Service Interfaces
+// ============================================================================
+
+// MessageSender sends an operator's message, honoring opt-outs and marking
+// it as manual in the transcript
+type MessageSender interface {
+	SendManualMessage(ctx context.Context, tenantID kernel.TenantID, userID kernel.UserID, req channels.SendMessageRequest) (*channels.SendMessageResponse, error)
+}
+
+// SnippetRenderer renders a tenant's canned reply
+type SnippetRenderer interface {
+	RenderSnippet(ctx context.Context, tenantID kernel.TenantID, snippetID string, variables map[string]string) (string, error)
+}
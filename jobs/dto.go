@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// EnqueueRequest queues a one-off job
+type EnqueueRequest struct {
+	Kind        string
+	TenantID    *kernel.TenantID // nil = system job
+	Payload     any              // Marshalled to JSON
+	RunAt       *time.Time       // nil = now
+	MaxAttempts int              // Zero = DefaultMaxAttempts
+}
+
+// ScheduleRequest creates or updates a recurring job
+type ScheduleRequest struct {
+	Kind        string
+	TenantID    *kernel.TenantID // nil = system job
+	UniqueKey   string           // Empty = Kind, one job per kind
+	Schedule    string           // Cron expression or @every <duration>
+	Payload     any
+	MaxAttempts int
+}
+
+// ListJobsRequest lists the tenant's jobs and, with IncludeSystem, the
+// system jobs
+type ListJobsRequest struct {
+	storex.PaginationOptions
+	TenantID      kernel.TenantID `json:"tenant_id" validate:"required"`
+	IncludeSystem bool            `json:"include_system"`
+	Status        *Status         `json:"status,omitempty"`
+	Kind          string          `json:"kind,omitempty"`
+}
+
+func (r ListJobsRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+// ListRunsRequest lists a job's run history, newest first
+type ListRunsRequest struct {
+	storex.PaginationOptions
+	JobID string `json:"job_id" validate:"required"`
+}
+
+func (r ListRunsRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+type JobListResponse = storex.Paginated[Job]
+
+type RunListResponse = storex.Paginated[Run]
@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("JOBS")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeJobNotFound    = ErrRegistry.Register("JOB_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Job not found")
+	CodeInvalidJob     = ErrRegistry.Register("INVALID_JOB", errx.TypeValidation, http.StatusBadRequest, "Invalid job")
+	CodeJobNotRunnable = ErrRegistry.Register("JOB_NOT_RUNNABLE", errx.TypeBusiness, http.StatusConflict, "Job cannot be run now")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrJobNotFound() *errx.Error {
+	return ErrRegistry.New(CodeJobNotFound)
+}
+
+func ErrInvalidJob() *errx.Error {
+	return ErrRegistry.New(CodeInvalidJob)
+}
+
+func ErrJobNotRunnable() *errx.Error {
+	return ErrRegistry.New(CodeJobNotRunnable)
+}
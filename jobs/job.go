@@ -0,0 +1,262 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+)
+
+// ============================================================================
+// Jobs
+// ============================================================================
+
+// DefaultMaxAttempts is used when a job does not set MaxAttempts
+const DefaultMaxAttempts = 3
+
+// MaxBackoff caps the delay between retries
+const MaxBackoff = time.Hour
+
+// Status is where a job is in its lifecycle. Recurring jobs go back to
+// pending after every run and never reach succeeded or failed.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// IsValid checks the status is known
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusPending, StatusRunning, StatusSucceeded, StatusFailed:
+		return true
+	}
+	return false
+}
+
+// Job is a unit of background work. Kind selects the handler that runs it;
+// a job without TenantID is a system job (retention purge, run pruning).
+// Recurring jobs have a Schedule and exist once per UniqueKey.
+type Job struct {
+	ID          string           `json:"id"`
+	Kind        string           `json:"kind"`
+	TenantID    *kernel.TenantID `json:"tenant_id,omitempty"`
+	UniqueKey   string           `json:"unique_key,omitempty"`
+	Payload     json.RawMessage  `json:"payload,omitempty"`
+	Schedule    string           `json:"schedule,omitempty"` // Cron expression or @every <duration>
+	Status      Status           `json:"status"`
+	Attempts    int              `json:"attempts"`
+	MaxAttempts int              `json:"max_attempts"`
+	RunAt       time.Time        `json:"run_at"`
+	LockedBy    string           `json:"locked_by,omitempty"`
+	LockedUntil *time.Time       `json:"locked_until,omitempty"`
+	LastError   string           `json:"last_error,omitempty"`
+	LastRunAt   *time.Time       `json:"last_run_at,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// NewJob creates a pending job due at runAt
+func NewJob(kind string, tenantID *kernel.TenantID, payload json.RawMessage, runAt time.Time, maxAttempts int) *Job {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	now := time.Now()
+	return &Job{
+		ID:          uuid.NewString(),
+		Kind:        kind,
+		TenantID:    tenantID,
+		Payload:     payload,
+		Status:      StatusPending,
+		MaxAttempts: maxAttempts,
+		RunAt:       runAt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// IsRecurring reports whether the job runs on a schedule
+func (j Job) IsRecurring() bool {
+	return j.Schedule != ""
+}
+
+// IsSystem reports whether the job belongs to no tenant
+func (j Job) IsSystem() bool {
+	return j.TenantID == nil
+}
+
+// Decode unmarshals the payload into v
+func (j Job) Decode(v any) error {
+	if len(j.Payload) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(j.Payload, v); err != nil {
+		return ErrInvalidJob().WithDetail("job_id", j.ID).WithDetail("reason", err.Error())
+	}
+	return nil
+}
+
+// Succeed records a successful attempt. Recurring jobs go back to pending
+// at their next occurrence with a fresh attempt count.
+func (j *Job) Succeed(now time.Time) {
+	j.LastError = ""
+	if j.IsRecurring() {
+		j.reschedule(now)
+		return
+	}
+	j.Status = StatusSucceeded
+}
+
+// Fail records a failed attempt. The job is retried with exponential
+// backoff until MaxAttempts; then a one-off job fails for good and a
+// recurring one waits for its next occurrence.
+func (j *Job) Fail(err error, now time.Time) {
+	j.LastError = err.Error()
+	if j.Attempts < j.MaxAttempts {
+		j.Status = StatusPending
+		j.RunAt = now.Add(Backoff(j.Attempts))
+		return
+	}
+	if j.IsRecurring() {
+		j.reschedule(now)
+		return
+	}
+	j.Status = StatusFailed
+}
+
+// Release gives back an attempt interrupted by a shutdown, so the job runs
+// again right away without counting it
+func (j *Job) Release(now time.Time) {
+	j.Status = StatusPending
+	j.RunAt = now
+	if j.Attempts > 0 {
+		j.Attempts--
+	}
+}
+
+func (j *Job) reschedule(now time.Time) {
+	next, err := NextRun(j.Schedule, now)
+	if err != nil {
+		// Only reachable if the stored schedule was edited by hand
+		j.Status = StatusFailed
+		j.LastError = err.Error()
+		return
+	}
+	j.Status = StatusPending
+	j.Attempts = 0
+	j.RunAt = next
+}
+
+// Backoff is the delay before retrying after the given attempt: 30s, 1m,
+// 2m... up to MaxBackoff
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := 30 * time.Second
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= MaxBackoff {
+			return MaxBackoff
+		}
+	}
+	return delay
+}
+
+// ============================================================================
+// Schedules
+// ============================================================================
+
+// scheduleParser accepts five-field cron expressions and descriptors such
+// as @hourly or @every 30s, like the workflow scheduler plus @every
+var scheduleParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ValidateSchedule checks a schedule can be parsed
+func ValidateSchedule(schedule string) error {
+	if _, err := scheduleParser.Parse(schedule); err != nil {
+		return ErrInvalidJob().WithDetail("schedule", schedule).WithDetail("reason", err.Error())
+	}
+	return nil
+}
+
+// NextRun returns the first occurrence of the schedule after the given time
+func NextRun(schedule string, after time.Time) (time.Time, error) {
+	parsed, err := scheduleParser.Parse(schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+	return parsed.Next(after), nil
+}
+
+// ============================================================================
+// Runs
+// ============================================================================
+
+// RunStatus is the outcome of one attempt
+type RunStatus string
+
+const (
+	RunRunning     RunStatus = "running"
+	RunSucceeded   RunStatus = "succeeded"
+	RunFailed      RunStatus = "failed"
+	RunInterrupted RunStatus = "interrupted" // The worker stopped or lost its lease
+)
+
+// Run is one attempt at a job, kept as its history
+type Run struct {
+	ID         string     `json:"id"`
+	JobID      string     `json:"job_id"`
+	Attempt    int        `json:"attempt"`
+	Worker     string     `json:"worker"`
+	Status     RunStatus  `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	DurationMs *int64     `json:"duration_ms,omitempty"`
+}
+
+// NewRun starts the history entry of a claimed job
+func NewRun(job Job, worker string) *Run {
+	return &Run{
+		ID:        uuid.NewString(),
+		JobID:     job.ID,
+		Attempt:   job.Attempts,
+		Worker:    worker,
+		Status:    RunRunning,
+		StartedAt: time.Now(),
+	}
+}
+
+// Finish records the outcome of the attempt
+func (r *Run) Finish(status RunStatus, err error) {
+	now := time.Now()
+	duration := now.Sub(r.StartedAt).Milliseconds()
+	r.Status = status
+	r.FinishedAt = &now
+	r.DurationMs = &duration
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// ============================================================================
+// Handlers
+// ============================================================================
+
+// Handler runs one job. Returning an error counts as a failed attempt.
+type Handler func(ctx context.Context, job Job) error
+
+// Options configures how a runner executes a kind of job
+type Options struct {
+	Timeout time.Duration // Zero = DefaultTimeout
+}
+
+// DefaultTimeout bounds a job whose kind sets no timeout
+const DefaultTimeout = 10 * time.Minute
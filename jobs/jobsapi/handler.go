@@ -0,0 +1,138 @@
+package jobsapi
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/jobs/jobssrv"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// JobHandler exposes background jobs and their run history
+type JobHandler struct {
+	service *jobssrv.JobService
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(service *jobssrv.JobService) *JobHandler {
+	return &JobHandler{
+		service: service,
+	}
+}
+
+// List returns the tenant's jobs and the system jobs, most recently updated first
+// GET /api/jobs?status=failed&kind=transcript.export&system=true&page=1&page_size=50
+func (h *JobHandler) List(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	page, pageSize := pagination(c)
+	req := jobs.ListJobsRequest{
+		PaginationOptions: storex.PaginationOptions{
+			Page:     page,
+			PageSize: pageSize,
+		},
+		TenantID:      authContext.TenantID,
+		IncludeSystem: c.QueryBool("system", true),
+		Kind:          c.Query("kind"),
+	}
+
+	if status := c.Query("status"); status != "" {
+		s := jobs.Status(status)
+		if !s.IsValid() {
+			return jobs.ErrInvalidJob().WithDetail("status", status)
+		}
+		req.Status = &s
+	}
+
+	result, err := h.service.List(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(result)
+}
+
+// Get returns a job
+// GET /api/jobs/:id
+func (h *JobHandler) Get(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	job, err := h.service.Get(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(job)
+}
+
+// ListRuns returns a job's run history, newest first
+// GET /api/jobs/:id/runs?page=1&page_size=50
+func (h *JobHandler) ListRuns(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	page, pageSize := pagination(c)
+	req := jobs.ListRunsRequest{
+		PaginationOptions: storex.PaginationOptions{
+			Page:     page,
+			PageSize: pageSize,
+		},
+		JobID: c.Params("id"),
+	}
+
+	runs, err := h.service.ListRuns(c.Context(), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(runs)
+}
+
+// RunNow makes one of the tenant's jobs due immediately
+// POST /api/jobs/:id/run
+func (h *JobHandler) RunNow(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	job, err := h.service.RunNow(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func pagination(c *fiber.Ctx) (int, int) {
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", defaultPageSize)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
@@ -0,0 +1,31 @@
+package jobsapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// JobRoutes handles job route setup
+type JobRoutes struct {
+	handler        *JobHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewJobRoutes creates a new job routes instance
+func NewJobRoutes(handler *JobHandler, authMiddleware *auth.AuthMiddleware) *JobRoutes {
+	return &JobRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers job routes on an authenticated router. Jobs
+// expose system internals, so every route requires an admin.
+func (r *JobRoutes) RegisterRoutes(router fiber.Router) {
+	jobs := router.Group("/jobs", r.authMiddleware.RequireAdmin())
+
+	jobs.Get("/", r.handler.List)
+	jobs.Get("/:id", r.handler.Get)
+	jobs.Get("/:id/runs", r.handler.ListRuns)
+	jobs.Post("/:id/run", r.handler.RunNow)
+}
@@ -0,0 +1,441 @@
+package jobsinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PostgresJobRepository struct {
+	db *sqlx.DB
+}
+
+var _ jobs.Repository = (*PostgresJobRepository)(nil)
+
+func NewPostgresJobRepository(db *sqlx.DB) *PostgresJobRepository {
+	return &PostgresJobRepository{db: db}
+}
+
+// dbJob is an intermediate struct for database operations
+type dbJob struct {
+	ID          string          `db:"id"`
+	Kind        string          `db:"kind"`
+	TenantID    sql.NullString  `db:"tenant_id"`
+	UniqueKey   sql.NullString  `db:"unique_key"`
+	Payload     json.RawMessage `db:"payload"`
+	Schedule    string          `db:"schedule"`
+	Status      string          `db:"status"`
+	Attempts    int             `db:"attempts"`
+	MaxAttempts int             `db:"max_attempts"`
+	RunAt       time.Time       `db:"run_at"`
+	LockedBy    sql.NullString  `db:"locked_by"`
+	LockedUntil *time.Time      `db:"locked_until"`
+	LastError   string          `db:"last_error"`
+	LastRunAt   *time.Time      `db:"last_run_at"`
+	CreatedAt   time.Time       `db:"created_at"`
+	UpdatedAt   time.Time       `db:"updated_at"`
+}
+
+const jobColumns = `
+	id, kind, tenant_id, unique_key, payload, schedule, status, attempts, max_attempts,
+	run_at, locked_by, locked_until, last_error, last_run_at, created_at, updated_at`
+
+// dbRun is an intermediate struct for database operations
+type dbRun struct {
+	ID         string     `db:"id"`
+	JobID      string     `db:"job_id"`
+	Attempt    int        `db:"attempt"`
+	Worker     string     `db:"worker"`
+	Status     string     `db:"status"`
+	Error      string     `db:"error"`
+	StartedAt  time.Time  `db:"started_at"`
+	FinishedAt *time.Time `db:"finished_at"`
+	DurationMs *int64     `db:"duration_ms"`
+}
+
+const runColumns = `id, job_id, attempt, worker, status, error, started_at, finished_at, duration_ms`
+
+// ============================================================================
+// Jobs
+// ============================================================================
+
+func (r *PostgresJobRepository) Create(ctx context.Context, job jobs.Job) error {
+	query := `
+		INSERT INTO jobs (
+			id, kind, tenant_id, unique_key, payload, schedule, status, attempts,
+			max_attempts, run_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		job.ID, job.Kind, tenantParam(job.TenantID), nullString(job.UniqueKey), payloadParam(job.Payload),
+		job.Schedule, string(job.Status), job.Attempts, job.MaxAttempts, job.RunAt,
+		job.CreatedAt, job.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return jobs.ErrInvalidJob().
+				WithDetail("unique_key", job.UniqueKey).
+				WithDetail("reason", "a job with this key already exists")
+		}
+		return errx.Wrap(err, "failed to create job", errx.TypeInternal).
+			WithDetail("kind", job.Kind)
+	}
+
+	return nil
+}
+
+func (r *PostgresJobRepository) UpsertRecurring(ctx context.Context, job jobs.Job) (*jobs.Job, error) {
+	query := `
+		INSERT INTO jobs (
+			id, kind, tenant_id, unique_key, payload, schedule, status, attempts,
+			max_attempts, run_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (unique_key) DO UPDATE SET
+			kind = EXCLUDED.kind,
+			payload = EXCLUDED.payload,
+			max_attempts = EXCLUDED.max_attempts,
+			run_at = CASE
+				WHEN jobs.schedule <> EXCLUDED.schedule AND jobs.status <> 'running' THEN EXCLUDED.run_at
+				ELSE jobs.run_at
+			END,
+			-- A recurring job left failed by a bad schedule comes back
+			status = CASE WHEN jobs.status = 'failed' THEN 'pending' ELSE jobs.status END,
+			schedule = EXCLUDED.schedule,
+			updated_at = NOW()
+		RETURNING ` + jobColumns
+
+	var row dbJob
+	err := r.db.GetContext(ctx, &row, query,
+		job.ID, job.Kind, tenantParam(job.TenantID), job.UniqueKey, payloadParam(job.Payload),
+		job.Schedule, string(job.Status), job.Attempts, job.MaxAttempts, job.RunAt,
+		job.CreatedAt, job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to schedule job", errx.TypeInternal).
+			WithDetail("unique_key", job.UniqueKey)
+	}
+
+	return toDomainJob(&row), nil
+}
+
+func (r *PostgresJobRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*jobs.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs WHERE id = $1 AND (tenant_id = $2 OR tenant_id IS NULL)`
+
+	var row dbJob
+	if err := r.db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, jobs.ErrJobNotFound().WithDetail("job_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find job", errx.TypeInternal).
+			WithDetail("job_id", id)
+	}
+
+	return toDomainJob(&row), nil
+}
+
+func (r *PostgresJobRepository) List(ctx context.Context, req jobs.ListJobsRequest) (jobs.JobListResponse, error) {
+	conditions := []string{"tenant_id = $1"}
+	if req.IncludeSystem {
+		conditions[0] = "(tenant_id = $1 OR tenant_id IS NULL)"
+	}
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argPos))
+		args = append(args, string(*req.Status))
+		argPos++
+	}
+	if req.Kind != "" {
+		conditions = append(conditions, fmt.Sprintf("kind = $%d", argPos))
+		args = append(args, req.Kind)
+		argPos++
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM jobs WHERE %s", whereClause)
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return jobs.JobListResponse{}, errx.Wrap(err, "failed to count jobs", errx.TypeInternal)
+	}
+
+	dataQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM jobs
+		WHERE %s
+		ORDER BY updated_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`,
+		jobColumns, whereClause, argPos, argPos+1)
+
+	args = append(args, req.PageSize, req.GetOffset())
+
+	var rows []dbJob
+	if err := r.db.SelectContext(ctx, &rows, dataQuery, args...); err != nil {
+		return jobs.JobListResponse{}, errx.Wrap(err, "failed to list jobs", errx.TypeInternal)
+	}
+
+	result := make([]jobs.Job, 0, len(rows))
+	for i := range rows {
+		result = append(result, *toDomainJob(&rows[i]))
+	}
+
+	return storex.NewPaginated(result, req.Page, req.PageSize, total), nil
+}
+
+// ============================================================================
+// Claiming
+// ============================================================================
+
+func (r *PostgresJobRepository) Claim(ctx context.Context, worker string, kinds []string, now, leaseUntil time.Time, limit int) ([]*jobs.Job, error) {
+	if len(kinds) == 0 || limit <= 0 {
+		return nil, nil
+	}
+
+	// SKIP LOCKED lets several instances claim disjoint batches
+	query := `
+		UPDATE jobs
+		SET status = 'running', attempts = attempts + 1, locked_by = $1, locked_until = $3,
+			last_run_at = $2, updated_at = NOW()
+		WHERE id IN (
+			SELECT id
+			FROM jobs
+			WHERE kind = ANY($4)
+				AND run_at <= $2
+				AND (status = 'pending' OR (status = 'running' AND locked_until < $2))
+			ORDER BY run_at ASC
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	var rows []dbJob
+	if err := r.db.SelectContext(ctx, &rows, query, worker, now, leaseUntil, pq.Array(kinds), limit); err != nil {
+		return nil, errx.Wrap(err, "failed to claim jobs", errx.TypeInternal)
+	}
+
+	claimed := make([]*jobs.Job, 0, len(rows))
+	for i := range rows {
+		claimed = append(claimed, toDomainJob(&rows[i]))
+	}
+
+	return claimed, nil
+}
+
+func (r *PostgresJobRepository) Extend(ctx context.Context, id, worker string, leaseUntil time.Time) error {
+	query := `
+		UPDATE jobs SET locked_until = $1
+		WHERE id = $2 AND locked_by = $3 AND status = 'running'`
+
+	if _, err := r.db.ExecContext(ctx, query, leaseUntil, id, worker); err != nil {
+		return errx.Wrap(err, "failed to extend job lease", errx.TypeInternal).
+			WithDetail("job_id", id)
+	}
+	return nil
+}
+
+func (r *PostgresJobRepository) Complete(ctx context.Context, job jobs.Job, worker string) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET status = $1, attempts = $2, run_at = $3, last_error = $4,
+			locked_by = NULL, locked_until = NULL, updated_at = NOW()
+		WHERE id = $5 AND locked_by = $6 AND status = 'running'`
+
+	result, err := r.db.ExecContext(ctx, query,
+		string(job.Status), job.Attempts, job.RunAt, job.LastError, job.ID, worker,
+	)
+	if err != nil {
+		return false, errx.Wrap(err, "failed to complete job", errx.TypeInternal).
+			WithDetail("job_id", job.ID)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *PostgresJobRepository) MarkDue(ctx context.Context, id string, now time.Time) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET status = 'pending', attempts = 0, run_at = $1, updated_at = NOW()
+		WHERE id = $2 AND status <> 'running'`
+
+	result, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return false, errx.Wrap(err, "failed to mark job due", errx.TypeInternal).
+			WithDetail("job_id", id)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+
+	return rows > 0, nil
+}
+
+// ============================================================================
+// Runs
+// ============================================================================
+
+func (r *PostgresJobRepository) StartRun(ctx context.Context, run jobs.Run) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errx.Wrap(err, "failed to begin transaction", errx.TypeInternal)
+	}
+	defer tx.Rollback()
+
+	// A run still open belongs to a worker that lost the lease
+	interrupt := `
+		UPDATE job_runs
+		SET status = 'interrupted', finished_at = NOW(),
+			duration_ms = (EXTRACT(EPOCH FROM (NOW() - started_at)) * 1000)::BIGINT
+		WHERE job_id = $1 AND status = 'running'`
+	if _, err := tx.ExecContext(ctx, interrupt, run.JobID); err != nil {
+		return errx.Wrap(err, "failed to interrupt stale runs", errx.TypeInternal).
+			WithDetail("job_id", run.JobID)
+	}
+
+	insert := `
+		INSERT INTO job_runs (id, job_id, attempt, worker, status, started_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+	if _, err := tx.ExecContext(ctx, insert,
+		run.ID, run.JobID, run.Attempt, run.Worker, string(run.Status), run.StartedAt,
+	); err != nil {
+		return errx.Wrap(err, "failed to record job run", errx.TypeInternal).
+			WithDetail("job_id", run.JobID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errx.Wrap(err, "failed to commit transaction", errx.TypeInternal)
+	}
+	return nil
+}
+
+func (r *PostgresJobRepository) FinishRun(ctx context.Context, run jobs.Run) error {
+	query := `
+		UPDATE job_runs
+		SET status = $1, error = $2, finished_at = $3, duration_ms = $4
+		WHERE id = $5`
+
+	if _, err := r.db.ExecContext(ctx, query,
+		string(run.Status), run.Error, run.FinishedAt, run.DurationMs, run.ID,
+	); err != nil {
+		return errx.Wrap(err, "failed to finish job run", errx.TypeInternal).
+			WithDetail("run_id", run.ID)
+	}
+	return nil
+}
+
+func (r *PostgresJobRepository) ListRuns(ctx context.Context, req jobs.ListRunsRequest) (jobs.RunListResponse, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM job_runs WHERE job_id = $1`, req.JobID); err != nil {
+		return jobs.RunListResponse{}, errx.Wrap(err, "failed to count job runs", errx.TypeInternal)
+	}
+
+	query := `
+		SELECT ` + runColumns + `
+		FROM job_runs
+		WHERE job_id = $1
+		ORDER BY started_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
+
+	var rows []dbRun
+	if err := r.db.SelectContext(ctx, &rows, query, req.JobID, req.PageSize, req.GetOffset()); err != nil {
+		return jobs.RunListResponse{}, errx.Wrap(err, "failed to list job runs", errx.TypeInternal)
+	}
+
+	runs := make([]jobs.Run, 0, len(rows))
+	for _, row := range rows {
+		runs = append(runs, jobs.Run{
+			ID:         row.ID,
+			JobID:      row.JobID,
+			Attempt:    row.Attempt,
+			Worker:     row.Worker,
+			Status:     jobs.RunStatus(row.Status),
+			Error:      row.Error,
+			StartedAt:  row.StartedAt,
+			FinishedAt: row.FinishedAt,
+			DurationMs: row.DurationMs,
+		})
+	}
+
+	return storex.NewPaginated(runs, req.Page, req.PageSize, total), nil
+}
+
+func (r *PostgresJobRepository) PruneRuns(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM job_runs WHERE started_at < $1 AND status <> 'running'`, before)
+	if err != nil {
+		return 0, errx.Wrap(err, "failed to prune job runs", errx.TypeInternal)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+	return rows, nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func toDomainJob(row *dbJob) *jobs.Job {
+	job := &jobs.Job{
+		ID:          row.ID,
+		Kind:        row.Kind,
+		UniqueKey:   row.UniqueKey.String,
+		Payload:     row.Payload,
+		Schedule:    row.Schedule,
+		Status:      jobs.Status(row.Status),
+		Attempts:    row.Attempts,
+		MaxAttempts: row.MaxAttempts,
+		RunAt:       row.RunAt,
+		LockedBy:    row.LockedBy.String,
+		LockedUntil: row.LockedUntil,
+		LastError:   row.LastError,
+		LastRunAt:   row.LastRunAt,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+	if row.TenantID.Valid {
+		tenantID := kernel.TenantID(row.TenantID.String)
+		job.TenantID = &tenantID
+	}
+	return job
+}
+
+func tenantParam(tenantID *kernel.TenantID) any {
+	if tenantID == nil {
+		return nil
+	}
+	return tenantID.String()
+}
+
+func nullString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func payloadParam(payload json.RawMessage) []byte {
+	if len(payload) == 0 {
+		return []byte("{}")
+	}
+	return payload
+}
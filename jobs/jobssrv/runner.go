@@ -0,0 +1,274 @@
+package jobssrv
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/google/uuid"
+)
+
+const (
+	// leaseDuration is how long a claimed job stays locked without a
+	// heartbeat; a crashed worker's jobs are claimed again after it
+	leaseDuration = 2 * time.Minute
+
+	// heartbeatInterval renews the lease of running jobs
+	heartbeatInterval = leaseDuration / 4
+)
+
+type registration struct {
+	handler jobs.Handler
+	options jobs.Options
+}
+
+// Runner claims due jobs of the registered kinds and runs them with bounded
+// concurrency. Every instance runs one; claims never overlap.
+type Runner struct {
+	repo         jobs.Repository
+	worker       string
+	concurrency  int
+	pollInterval time.Duration
+
+	mu       sync.RWMutex
+	handlers map[string]registration
+
+	slots    chan struct{}
+	inFlight sync.WaitGroup
+	cancel   context.CancelFunc
+	stopChan chan struct{}
+	stopping bool
+	running  bool
+}
+
+func NewRunner(repo jobs.Repository, concurrency int, pollInterval time.Duration) *Runner {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	return &Runner{
+		repo:         repo,
+		worker:       workerID(),
+		concurrency:  concurrency,
+		pollInterval: pollInterval,
+		handlers:     make(map[string]registration),
+		slots:        make(chan struct{}, concurrency),
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Register sets the handler of a kind of job. Only registered kinds are
+// claimed, so instances with different features can share the queue.
+func (r *Runner) Register(kind string, handler jobs.Handler, options jobs.Options) {
+	if options.Timeout <= 0 {
+		options.Timeout = jobs.DefaultTimeout
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[kind] = registration{handler: handler, options: options}
+}
+
+// Kinds returns the registered kinds, sorted
+func (r *Runner) Kinds() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	kinds := make([]string, 0, len(r.handlers))
+	for kind := range r.handlers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Start polls for due jobs until Stop is called or ctx is done
+func (r *Runner) Start(ctx context.Context) {
+	if r.running {
+		log.Println("⚠️  Job runner already running")
+		return
+	}
+
+	r.running = true
+	ctx, r.cancel = context.WithCancel(ctx)
+	log.Printf("🧰 Starting job runner %s (%d slots, every %s, kinds %v)...",
+		r.worker, r.concurrency, r.pollInterval, r.Kinds())
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("⏹️  Job runner stopped (context done)")
+			return
+		case <-r.stopChan:
+			log.Println("⏹️  Job runner stopped")
+			return
+		case <-ticker.C:
+			r.poll(ctx)
+		}
+	}
+}
+
+// Stop stops claiming jobs and waits for the running ones to be released.
+// Their handlers are cancelled; the jobs run again without losing an attempt.
+func (r *Runner) Stop() {
+	if !r.running {
+		return
+	}
+	r.mu.Lock()
+	r.stopping = true
+	r.mu.Unlock()
+
+	close(r.stopChan)
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.inFlight.Wait()
+	r.running = false
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func (r *Runner) poll(ctx context.Context) {
+	free := r.concurrency - len(r.slots)
+	if free <= 0 {
+		return
+	}
+
+	now := time.Now()
+	claimed, err := r.repo.Claim(ctx, r.worker, r.Kinds(), now, now.Add(leaseDuration), free)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("❌ Failed to claim jobs: %v", err)
+		}
+		return
+	}
+
+	for _, job := range claimed {
+		r.slots <- struct{}{}
+		r.inFlight.Add(1)
+		go func(job *jobs.Job) {
+			defer func() {
+				<-r.slots
+				r.inFlight.Done()
+			}()
+			r.execute(ctx, job)
+		}(job)
+	}
+}
+
+func (r *Runner) execute(ctx context.Context, job *jobs.Job) {
+	r.mu.RLock()
+	reg, ok := r.handlers[job.Kind]
+	r.mu.RUnlock()
+
+	run := jobs.NewRun(*job, r.worker)
+	if err := r.repo.StartRun(ctx, *run); err != nil {
+		log.Printf("⚠️  Failed to record run of job %s: %v", job.ID, err)
+	}
+
+	var err error
+	switch {
+	case !ok:
+		err = fmt.Errorf("no handler registered for %s", job.Kind)
+	case job.Attempts > job.MaxAttempts:
+		// Previous attempts were interrupted by crashes
+		err = fmt.Errorf("interrupted %d times", job.Attempts-1)
+	default:
+		err = r.call(ctx, job, reg)
+	}
+
+	// The run's outcome is saved even when ctx was cancelled by Stop
+	saveCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	status := jobs.RunSucceeded
+	switch {
+	case err != nil && r.isStopping():
+		status = jobs.RunInterrupted
+		job.Release(now)
+	case err != nil:
+		status = jobs.RunFailed
+		job.Fail(err, now)
+		log.Printf("❌ Job %s (%s) failed, attempt %d/%d: %v", job.ID, job.Kind, job.Attempts, job.MaxAttempts, err)
+	default:
+		job.Succeed(now)
+	}
+
+	run.Finish(status, err)
+	if err := r.repo.FinishRun(saveCtx, *run); err != nil {
+		log.Printf("⚠️  Failed to finish run of job %s: %v", job.ID, err)
+	}
+
+	held, err := r.repo.Complete(saveCtx, *job, r.worker)
+	if err != nil {
+		log.Printf("⚠️  Failed to complete job %s: %v", job.ID, err)
+		return
+	}
+	if !held {
+		log.Printf("⚠️  Job %s (%s) lost its lease while running", job.ID, job.Kind)
+	}
+}
+
+// call runs the handler under its timeout, renewing the lease meanwhile
+func (r *Runner) call(ctx context.Context, job *jobs.Job, reg registration) (err error) {
+	jobCtx, cancel := context.WithTimeout(ctx, reg.options.Timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	defer close(done)
+	go r.heartbeat(jobCtx, job.ID, done)
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return reg.handler(jobCtx, *job)
+}
+
+func (r *Runner) heartbeat(ctx context.Context, jobID string, done <-chan struct{}) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.repo.Extend(ctx, jobID, r.worker, time.Now().Add(leaseDuration)); err != nil {
+				log.Printf("⚠️  Failed to extend lease of job %s: %v", jobID, err)
+			}
+		}
+	}
+}
+
+func (r *Runner) isStopping() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.stopping
+}
+
+// workerID identifies this process in locks and run history
+func workerID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "relay"
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8])
+}
@@ -0,0 +1,170 @@
+package jobssrv
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// KindPruneRuns is the system job that deletes old run history
+const KindPruneRuns = "jobs.prune_runs"
+
+// JobService queues and schedules jobs and exposes them with their history
+type JobService struct {
+	repo       jobs.Repository
+	runHistory time.Duration
+}
+
+var _ jobs.Queue = (*JobService)(nil)
+
+func NewJobService(repo jobs.Repository, runHistory time.Duration) *JobService {
+	return &JobService{
+		repo:       repo,
+		runHistory: runHistory,
+	}
+}
+
+// ============================================================================
+// Queueing
+// ============================================================================
+
+// Enqueue queues a one-off job, due now unless RunAt is set
+func (s *JobService) Enqueue(ctx context.Context, req jobs.EnqueueRequest) (*jobs.Job, error) {
+	if strings.TrimSpace(req.Kind) == "" {
+		return nil, jobs.ErrInvalidJob().WithDetail("reason", "kind is required")
+	}
+
+	payload, err := marshalPayload(req.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	runAt := time.Now()
+	if req.RunAt != nil {
+		runAt = *req.RunAt
+	}
+
+	job := jobs.NewJob(req.Kind, req.TenantID, payload, runAt, req.MaxAttempts)
+	if err := s.repo.Create(ctx, *job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Schedule creates or updates a recurring job. Calling it on every startup
+// is safe: one job exists per unique key and its next run is kept.
+func (s *JobService) Schedule(ctx context.Context, req jobs.ScheduleRequest) (*jobs.Job, error) {
+	if strings.TrimSpace(req.Kind) == "" {
+		return nil, jobs.ErrInvalidJob().WithDetail("reason", "kind is required")
+	}
+	if err := jobs.ValidateSchedule(req.Schedule); err != nil {
+		return nil, err
+	}
+
+	payload, err := marshalPayload(req.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	runAt, err := jobs.NextRun(req.Schedule, time.Now())
+	if err != nil {
+		return nil, jobs.ErrInvalidJob().WithDetail("schedule", req.Schedule)
+	}
+
+	job := jobs.NewJob(req.Kind, req.TenantID, payload, runAt, req.MaxAttempts)
+	job.Schedule = req.Schedule
+	job.UniqueKey = req.UniqueKey
+	if job.UniqueKey == "" {
+		job.UniqueKey = req.Kind
+	}
+
+	return s.repo.UpsertRecurring(ctx, *job)
+}
+
+// ============================================================================
+// Visibility
+// ============================================================================
+
+// List returns the tenant's jobs together with the system jobs
+func (s *JobService) List(ctx context.Context, req jobs.ListJobsRequest) (jobs.JobListResponse, error) {
+	return s.repo.List(ctx, req)
+}
+
+// Get returns a job of the tenant or a system job
+func (s *JobService) Get(ctx context.Context, id string, tenantID kernel.TenantID) (*jobs.Job, error) {
+	return s.repo.FindByID(ctx, id, tenantID)
+}
+
+// ListRuns returns the run history of a job the tenant can see
+func (s *JobService) ListRuns(ctx context.Context, tenantID kernel.TenantID, req jobs.ListRunsRequest) (jobs.RunListResponse, error) {
+	if _, err := s.repo.FindByID(ctx, req.JobID, tenantID); err != nil {
+		return jobs.RunListResponse{}, err
+	}
+	return s.repo.ListRuns(ctx, req)
+}
+
+// RunNow makes one of the tenant's jobs due immediately, retrying a failed
+// job or bringing a recurring one forward. System jobs are read-only.
+func (s *JobService) RunNow(ctx context.Context, id string, tenantID kernel.TenantID) (*jobs.Job, error) {
+	job, err := s.repo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if job.IsSystem() {
+		return nil, jobs.ErrJobNotRunnable().
+			WithDetail("job_id", id).
+			WithDetail("reason", "system jobs cannot be run from the API")
+	}
+
+	ok, err := s.repo.MarkDue(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, jobs.ErrJobNotRunnable().
+			WithDetail("job_id", id).
+			WithDetail("reason", "job is running")
+	}
+
+	return s.repo.FindByID(ctx, id, tenantID)
+}
+
+// ============================================================================
+// Maintenance
+// ============================================================================
+
+// PruneRuns is the handler of KindPruneRuns
+func (s *JobService) PruneRuns(ctx context.Context, _ jobs.Job) error {
+	deleted, err := s.repo.PruneRuns(ctx, time.Now().Add(-s.runHistory))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("🧰 Pruned %d job runs older than %s", deleted, s.runHistory)
+	}
+	return nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func marshalPayload(payload any) (json.RawMessage, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	if raw, ok := payload.(json.RawMessage); ok {
+		return raw, nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, jobs.ErrInvalidJob().WithDetail("reason", "payload is not valid JSON: "+err.Error())
+	}
+	return data, nil
+}
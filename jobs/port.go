@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// Repository persists jobs and their run history
+type Repository interface {
+	// Create inserts a one-off job
+	Create(ctx context.Context, job Job) error
+
+	// UpsertRecurring creates the recurring job of job.UniqueKey or updates
+	// its schedule and payload. The next run is kept unless the schedule
+	// changed.
+	UpsertRecurring(ctx context.Context, job Job) (*Job, error)
+
+	// FindByID returns a job of the tenant or a system job
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*Job, error)
+
+	// List returns jobs, most recently updated first
+	List(ctx context.Context, req ListJobsRequest) (JobListResponse, error)
+
+	// Claim locks up to limit due jobs of the given kinds for the worker
+	// until leaseUntil and counts an attempt on each. Running jobs whose
+	// lease expired are claimed again.
+	Claim(ctx context.Context, worker string, kinds []string, now, leaseUntil time.Time, limit int) ([]*Job, error)
+
+	// Extend pushes the lease of a job the worker still holds
+	Extend(ctx context.Context, id, worker string, leaseUntil time.Time) error
+
+	// Complete saves the outcome of an attempt and releases the lock; false
+	// when the worker lost the lease in the meantime
+	Complete(ctx context.Context, job Job, worker string) (bool, error)
+
+	// MarkDue makes a job that is not running due now with a fresh attempt
+	// count; false when the job is running
+	MarkDue(ctx context.Context, id string, now time.Time) (bool, error)
+
+	// StartRun records a new attempt and marks the job's unfinished runs as
+	// interrupted
+	StartRun(ctx context.Context, run Run) error
+
+	// FinishRun saves the outcome of an attempt
+	FinishRun(ctx context.Context, run Run) error
+
+	// ListRuns returns a job's runs, newest first
+	ListRuns(ctx context.Context, req ListRunsRequest) (RunListResponse, error)
+
+	// PruneRuns deletes finished runs started before the given time
+	PruneRuns(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ============================================================================
+// Queue
+// ============================================================================
+
+// Queue is what features use to hand work to the job runner
+type Queue interface {
+	// Enqueue queues a one-off job
+	Enqueue(ctx context.Context, req EnqueueRequest) (*Job, error)
+}
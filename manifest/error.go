@@ -0,0 +1,39 @@
+package manifest
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("MANIFEST")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeInvalidManifest = ErrRegistry.Register("INVALID_MANIFEST", errx.TypeValidation, http.StatusBadRequest, "Invalid manifest")
+	CodeInvalidResource = ErrRegistry.Register("INVALID_RESOURCE", errx.TypeValidation, http.StatusUnprocessableEntity, "Manifest resource is invalid for this tenant")
+	CodeUnknownChannel  = ErrRegistry.Register("UNKNOWN_CHANNEL_REF", errx.TypeValidation, http.StatusUnprocessableEntity, "Workflow references an unknown channel")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrInvalidManifest() *errx.Error {
+	return ErrRegistry.New(CodeInvalidManifest)
+}
+
+func ErrInvalidResource() *errx.Error {
+	return ErrRegistry.New(CodeInvalidResource)
+}
+
+func ErrUnknownChannel() *errx.Error {
+	return ErrRegistry.New(CodeUnknownChannel)
+}
@@ -0,0 +1,169 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"gopkg.in/yaml.v3"
+)
+
+// ============================================================================
+// Manifest
+// ============================================================================
+
+// Manifest is the declarative configuration of a tenant. Resources are
+// matched to the stored ones by name, which is unique per tenant for every
+// kind. Channels and workflows are also scoped to the manifest's
+// environment, so a sandbox manifest never touches production resources;
+// roles are shared by both environments.
+type Manifest struct {
+	Environment kernel.Environment `json:"environment,omitempty"` // Defaults to production
+	Channels    []ChannelSpec      `json:"channels,omitempty"`
+	Roles       []RoleSpec         `json:"roles,omitempty"`
+	Workflows   []WorkflowSpec     `json:"workflows,omitempty"`
+}
+
+// ChannelSpec declares a channel. The type cannot change once created.
+type ChannelSpec struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Type        channels.ChannelType `json:"type"`
+	Config      map[string]any       `json:"config"`
+	IsActive    *bool                `json:"is_active,omitempty"` // Defaults to true
+}
+
+// RoleSpec declares a role and the full set of its permissions
+type RoleSpec struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	IsActive    *bool    `json:"is_active,omitempty"` // Defaults to true
+}
+
+// WorkflowSpec declares a workflow. Trigger filters and node configs may
+// reference a channel of the manifest or of the tenant as "@channel:<name>";
+// the reference is replaced by the channel's ID when the plan is computed.
+type WorkflowSpec struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Trigger     engine.WorkflowTrigger `json:"trigger"`
+	Nodes       []engine.WorkflowNode  `json:"nodes"`
+	Variables   engine.VariableSchema  `json:"variables,omitempty"`
+	IsActive    *bool                  `json:"is_active,omitempty"` // Defaults to true
+	Debug       *bool                  `json:"debug,omitempty"`     // Records runs for replay; unset keeps the stored value
+}
+
+// ChannelRefPrefix marks a channel referenced by name in a workflow
+const ChannelRefPrefix = "@channel:"
+
+// ============================================================================
+// Parsing
+// ============================================================================
+
+// Parse decodes a YAML or JSON manifest. YAML is converted to JSON first so
+// both formats share the json tags of the specs and of the engine types.
+func Parse(data []byte) (*Manifest, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, ErrInvalidManifest().WithDetail("reason", err.Error())
+	}
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, ErrInvalidManifest().WithDetail("reason", err.Error())
+	}
+
+	var m Manifest
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&m); err != nil {
+		return nil, ErrInvalidManifest().WithDetail("reason", err.Error())
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate checks what can be checked without the tenant's state: required
+// fields and duplicate names. Channel configs and workflows are validated by
+// their own services when the plan is computed.
+func (m *Manifest) Validate() error {
+	if m.Environment != "" && !m.Environment.IsValid() {
+		return ErrInvalidManifest().
+			WithDetail("environment", string(m.Environment)).
+			WithDetail("reason", "environment must be sandbox or production")
+	}
+
+	seen := make(map[string]bool)
+	check := func(kind Kind, name string) error {
+		if strings.TrimSpace(name) == "" {
+			return ErrInvalidManifest().WithDetail("kind", string(kind)).WithDetail("reason", "name is required")
+		}
+		key := string(kind) + "/" + name
+		if seen[key] {
+			return ErrInvalidManifest().
+				WithDetail("kind", string(kind)).
+				WithDetail("name", name).
+				WithDetail("reason", "duplicate name")
+		}
+		seen[key] = true
+		return nil
+	}
+
+	for _, spec := range m.Channels {
+		if err := check(KindChannel, spec.Name); err != nil {
+			return err
+		}
+		if spec.Type == "" {
+			return ErrInvalidManifest().
+				WithDetail("kind", string(KindChannel)).
+				WithDetail("name", spec.Name).
+				WithDetail("reason", "type is required")
+		}
+	}
+	for _, spec := range m.Roles {
+		if err := check(KindRole, spec.Name); err != nil {
+			return err
+		}
+	}
+	for _, spec := range m.Workflows {
+		if err := check(KindWorkflow, spec.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envPattern matches ${NAME}; a bare $ is left alone because workflow
+// templates and CEL expressions use it
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandEnv replaces ${NAME} with the environment variable so secrets such as
+// provider tokens stay out of the file. Unset variables are an error rather
+// than an empty credential.
+func ExpandEnv(data []byte) ([]byte, error) {
+	var missing []string
+	expanded := envPattern.ReplaceAllFunc(data, func(match []byte) []byte {
+		name := string(envPattern.FindSubmatch(match)[1])
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+			return match
+		}
+		return []byte(value)
+	})
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("unset environment variables: %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
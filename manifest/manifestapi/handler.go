@@ -0,0 +1,69 @@
+package manifestapi
+
+import (
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/manifest"
+	"github.com/Abraxas-365/relay/manifest/manifestsrv"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ManifestHandler plans and applies declarative tenant configuration. The
+// body is the manifest itself, as YAML or JSON.
+type ManifestHandler struct {
+	service *manifestsrv.ManifestService
+}
+
+// NewManifestHandler creates a new manifest handler
+func NewManifestHandler(service *manifestsrv.ManifestService) *ManifestHandler {
+	return &ManifestHandler{
+		service: service,
+	}
+}
+
+// Plan returns the changes applying the manifest would make, without
+// making them
+// POST /api/manifest/plan?prune=true
+func (h *ManifestHandler) Plan(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	m, err := manifest.Parse(c.Body())
+	if err != nil {
+		return err
+	}
+
+	plan, err := h.service.Plan(c.Context(), authContext.TenantID, *m, c.QueryBool("prune"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(plan)
+}
+
+// Apply reconciles the tenant with the manifest. A change that fails stops
+// the apply; the response lists it with the changes applied before it.
+// POST /api/manifest/apply?prune=true
+func (h *ManifestHandler) Apply(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	m, err := manifest.Parse(c.Body())
+	if err != nil {
+		return err
+	}
+
+	result, err := h.service.Apply(c.Context(), authContext.TenantID, *m, c.QueryBool("prune"))
+	if err != nil {
+		return err
+	}
+
+	if result.Failed != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(result)
+	}
+	return c.JSON(result)
+}
@@ -0,0 +1,29 @@
+package manifestapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ManifestRoutes handles manifest route setup
+type ManifestRoutes struct {
+	handler        *ManifestHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewManifestRoutes creates a new manifest routes instance
+func NewManifestRoutes(handler *ManifestHandler, authMiddleware *auth.AuthMiddleware) *ManifestRoutes {
+	return &ManifestRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers manifest routes on an authenticated router. Both
+// require an admin: the plan shows every channel's configuration.
+func (r *ManifestRoutes) RegisterRoutes(router fiber.Router) {
+	manifests := router.Group("/manifest", r.authMiddleware.RequireAdmin())
+
+	manifests.Post("/plan", r.handler.Plan)
+	manifests.Post("/apply", r.handler.Apply)
+}
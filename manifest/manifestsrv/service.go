@@ -0,0 +1,768 @@
+package manifestsrv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/channelsrv"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/iam/role"
+	"github.com/Abraxas-365/relay/iam/role/rolesrv"
+	"github.com/Abraxas-365/relay/manifest"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// pendingChannelRef stands in for channels the apply has not created yet.
+// It is a template, so plan-time validation checks the workflow's structure
+// and skips the existence check; the apply validates again with the real ID.
+const pendingChannelRef = `{{ "pending-channel" }}`
+
+// ManifestService reconciles a tenant's channels, roles and workflows with a
+// declarative manifest
+type ManifestService struct {
+	channelRepo    channels.ChannelRepository
+	channelService *channelsrv.ChannelService
+	roleRepo       role.RoleRepository
+	rolePermRepo   role.RolePermissionRepository
+	roleService    *rolesrv.RoleService
+	workflowRepo   engine.WorkflowRepository
+	validator      engine.WorkflowExecutor
+}
+
+// NewManifestService creates a new manifest service
+func NewManifestService(
+	channelRepo channels.ChannelRepository,
+	channelService *channelsrv.ChannelService,
+	roleRepo role.RoleRepository,
+	rolePermRepo role.RolePermissionRepository,
+	roleService *rolesrv.RoleService,
+	workflowRepo engine.WorkflowRepository,
+	validator engine.WorkflowExecutor,
+) *ManifestService {
+	return &ManifestService{
+		channelRepo:    channelRepo,
+		channelService: channelService,
+		roleRepo:       roleRepo,
+		rolePermRepo:   rolePermRepo,
+		roleService:    roleService,
+		workflowRepo:   workflowRepo,
+		validator:      validator,
+	}
+}
+
+// state is what the tenant has stored, by name
+type state struct {
+	channels  map[string]*channels.Channel
+	roles     map[string]*role.Role
+	rolePerms map[kernel.RoleID][]string
+	workflows map[string]*engine.Workflow
+}
+
+// step is one planned change and how to apply it. channelIDs maps channel
+// names to IDs and grows as channels are created.
+type step struct {
+	change manifest.Change
+	apply  func(ctx context.Context, channelIDs map[string]kernel.ChannelID) error
+}
+
+// ============================================================================
+// Plan & Apply
+// ============================================================================
+
+// Plan computes the changes that applying m would make. With prune, resources
+// missing from the manifest are deleted.
+func (s *ManifestService) Plan(ctx context.Context, tenantID kernel.TenantID, m manifest.Manifest, prune bool) (*manifest.Plan, error) {
+	p, err := s.plan(ctx, tenantID, m, prune)
+	if err != nil {
+		return nil, err
+	}
+	return p.plan, nil
+}
+
+// Apply computes the plan against the current state and applies it. It stops
+// at the first failed change; the result reports what was already applied.
+func (s *ManifestService) Apply(ctx context.Context, tenantID kernel.TenantID, m manifest.Manifest, prune bool) (*manifest.ApplyResult, error) {
+	p, err := s.plan(ctx, tenantID, m, prune)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &manifest.ApplyResult{Plan: *p.plan, Applied: []manifest.Change{}}
+	for _, st := range p.steps {
+		if err := st.apply(ctx, p.channelIDs); err != nil {
+			failed := st.change
+			result.Failed = &failed
+			result.Error = err.Error()
+			log.Printf("❌ Manifest apply for tenant %s failed at %s %q: %v", tenantID, failed.Kind, failed.Name, err)
+			return result, nil
+		}
+		result.Applied = append(result.Applied, st.change)
+	}
+
+	log.Printf("📋 Manifest applied for tenant %s: %s", tenantID, p.plan.Summary)
+	return result, nil
+}
+
+// planned is a plan with the steps that apply it. channelIDs holds the
+// channels that survive the apply; created ones are added as they are.
+type planned struct {
+	plan       *manifest.Plan
+	steps      []step
+	channelIDs map[string]kernel.ChannelID
+}
+
+func (s *ManifestService) plan(ctx context.Context, tenantID kernel.TenantID, m manifest.Manifest, prune bool) (*planned, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	environment := m.Environment.OrDefault()
+	current, err := s.loadState(ctx, tenantID, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &planned{
+		plan:       &manifest.Plan{Environment: environment, Changes: []manifest.Change{}, Prune: prune},
+		channelIDs: make(map[string]kernel.ChannelID, len(current.channels)),
+	}
+	add := func(st step) {
+		p.plan.Add(st.change)
+		if st.change.Action != manifest.ActionUnchanged {
+			p.steps = append(p.steps, st)
+		}
+	}
+
+	declared := make(map[string]bool, len(m.Channels))
+	for _, spec := range m.Channels {
+		declared[spec.Name] = true
+	}
+	for name, channel := range current.channels {
+		if declared[name] || !prune {
+			p.channelIDs[name] = channel.ID
+		}
+	}
+
+	// Channels the manifest creates have no ID until applied
+	planIDs := maps.Clone(p.channelIDs)
+	for _, spec := range m.Channels {
+		if _, ok := planIDs[spec.Name]; !ok {
+			planIDs[spec.Name] = pendingChannelRef
+		}
+	}
+
+	for _, spec := range m.Channels {
+		st, err := s.planChannel(tenantID, environment, spec, current.channels[spec.Name])
+		if err != nil {
+			return nil, withResource(err, manifest.KindChannel, spec.Name)
+		}
+		add(st)
+	}
+	for _, spec := range m.Roles {
+		existing := current.roles[spec.Name]
+		var perms []string
+		if existing != nil {
+			perms = current.rolePerms[existing.ID]
+		}
+		add(s.planRole(tenantID, spec, existing, perms))
+	}
+	for _, spec := range m.Workflows {
+		st, err := s.planWorkflow(ctx, tenantID, environment, spec, current.workflows[spec.Name], planIDs)
+		if err != nil {
+			return nil, withResource(err, manifest.KindWorkflow, spec.Name)
+		}
+		add(st)
+	}
+
+	if prune {
+		for _, st := range s.planDeletes(tenantID, m, current) {
+			add(st)
+		}
+	}
+
+	return p, nil
+}
+
+// loadState reads the tenant's roles and the channels and workflows of one
+// environment
+func (s *ManifestService) loadState(ctx context.Context, tenantID kernel.TenantID, environment kernel.Environment) (*state, error) {
+	current := &state{
+		channels:  make(map[string]*channels.Channel),
+		roles:     make(map[string]*role.Role),
+		rolePerms: make(map[kernel.RoleID][]string),
+		workflows: make(map[string]*engine.Workflow),
+	}
+
+	channelList, err := s.channelRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to load channels", errx.TypeInternal)
+	}
+	for _, channel := range channelList {
+		if channel.Environment.OrDefault() == environment {
+			current.channels[channel.Name] = channel
+		}
+	}
+
+	roles, err := s.roleRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to load roles", errx.TypeInternal)
+	}
+	for _, r := range roles {
+		perms, err := s.rolePermRepo.FindPermissionsByRole(ctx, r.ID)
+		if err != nil {
+			return nil, errx.Wrap(err, "failed to load role permissions", errx.TypeInternal)
+		}
+		current.roles[r.Name] = r
+		current.rolePerms[r.ID] = perms
+	}
+
+	workflows, err := s.workflowRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to load workflows", errx.TypeInternal)
+	}
+	for _, wf := range workflows {
+		if wf.Environment.OrDefault() == environment {
+			current.workflows[wf.Name] = wf
+		}
+	}
+
+	return current, nil
+}
+
+// ============================================================================
+// Channels
+// ============================================================================
+
+func (s *ManifestService) planChannel(tenantID kernel.TenantID, environment kernel.Environment, spec manifest.ChannelSpec, existing *channels.Channel) (step, error) {
+	raw, err := json.Marshal(spec.Config)
+	if err != nil {
+		return step{}, manifest.ErrInvalidResource().WithDetail("reason", err.Error())
+	}
+	config, err := channels.ParseChannelConfig(spec.Type, raw)
+	if err != nil {
+		return step{}, manifest.ErrInvalidResource().WithDetail("reason", err.Error())
+	}
+	if err := config.Validate(); err != nil {
+		return step{}, err
+	}
+	active := spec.IsActive == nil || *spec.IsActive
+
+	change := manifest.Change{Kind: manifest.KindChannel, Name: spec.Name}
+
+	if existing == nil {
+		change.Action = manifest.ActionCreate
+		return step{change: change, apply: func(ctx context.Context, channelIDs map[string]kernel.ChannelID) error {
+			created, err := s.channelService.CreateChannel(ctx, channels.CreateChannelRequest{
+				TenantID:    tenantID,
+				Name:        spec.Name,
+				Description: spec.Description,
+				Type:        spec.Type,
+				Config:      config,
+				Environment: environment,
+			})
+			if err != nil {
+				return err
+			}
+			channelIDs[spec.Name] = created.ID
+			if !active {
+				return s.channelService.DeactivateChannel(ctx, created.ID, tenantID)
+			}
+			return nil
+		}}, nil
+	}
+
+	if existing.Type != spec.Type {
+		return step{}, manifest.ErrInvalidResource().
+			WithDetail("reason", "channel type cannot change; delete the channel or give the new one another name").
+			WithDetail("stored_type", string(existing.Type))
+	}
+
+	change.ID = existing.ID.String()
+	if existing.Description != spec.Description {
+		change.Diff = append(change.Diff, manifest.FieldDiff{Field: "description", Before: existing.Description, After: spec.Description})
+	}
+	if existing.IsActive != active {
+		change.Diff = append(change.Diff, manifest.FieldDiff{Field: "is_active", Before: existing.IsActive, After: active})
+	}
+
+	desired, err := json.Marshal(config)
+	if err != nil {
+		return step{}, errx.Wrap(err, "failed to encode channel config", errx.TypeInternal)
+	}
+	configDiff := diffConfig(existing.Config, desired)
+	change.Diff = append(change.Diff, configDiff...)
+
+	if len(change.Diff) == 0 {
+		change.Action = manifest.ActionUnchanged
+		return step{change: change}, nil
+	}
+
+	change.Action = manifest.ActionUpdate
+	return step{change: change, apply: func(ctx context.Context, _ map[string]kernel.ChannelID) error {
+		req := channels.UpdateChannelRequest{
+			Description: &spec.Description,
+			IsActive:    &active,
+		}
+		if len(configDiff) > 0 {
+			req.Config = desired
+		}
+		_, err := s.channelService.UpdateChannel(ctx, existing.ID, req, tenantID)
+		return err
+	}}, nil
+}
+
+// diffConfig compares two channel configs key by key, masking credentials
+func diffConfig(stored, desired []byte) []manifest.FieldDiff {
+	var before, after map[string]any
+	_ = json.Unmarshal(stored, &before)
+	_ = json.Unmarshal(desired, &after)
+
+	var diff []manifest.FieldDiff
+	diffValues("config", before, after, &diff)
+	for i, d := range diff {
+		key := d.Field[strings.LastIndex(d.Field, ".")+1:]
+		if manifest.IsSecretKey(key) {
+			diff[i].Before, diff[i].After = maskValue(d.Before), maskValue(d.After)
+		}
+	}
+	return diff
+}
+
+// diffValues descends into maps present on both sides and reports every
+// other difference as one field
+func diffValues(field string, before, after any, diff *[]manifest.FieldDiff) {
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	if !beforeIsMap || !afterIsMap {
+		*diff = append(*diff, manifest.FieldDiff{Field: field, Before: before, After: after})
+		return
+	}
+
+	keys := make(map[string]bool, len(beforeMap)+len(afterMap))
+	for key := range beforeMap {
+		keys[key] = true
+	}
+	for key := range afterMap {
+		keys[key] = true
+	}
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		diffValues(field+"."+key, beforeMap[key], afterMap[key], diff)
+	}
+}
+
+func maskValue(value any) any {
+	if value == nil || value == "" {
+		return value
+	}
+	return manifest.MaskedValue
+}
+
+// ============================================================================
+// Roles
+// ============================================================================
+
+func (s *ManifestService) planRole(tenantID kernel.TenantID, spec manifest.RoleSpec, existing *role.Role, storedPerms []string) step {
+	active := spec.IsActive == nil || *spec.IsActive
+	perms := sortedSet(spec.Permissions)
+	change := manifest.Change{Kind: manifest.KindRole, Name: spec.Name}
+
+	if existing == nil {
+		change.Action = manifest.ActionCreate
+		return step{change: change, apply: func(ctx context.Context, _ map[string]kernel.ChannelID) error {
+			created, err := s.roleService.CreateRole(ctx, role.CreateRoleRequest{
+				TenantID:    tenantID,
+				Name:        spec.Name,
+				Description: spec.Description,
+			})
+			if err != nil {
+				return err
+			}
+			// CreateRole ignores permission errors; SetRolePermissions reports them
+			if err := s.roleService.SetRolePermissions(ctx, created.ID, perms, tenantID); err != nil {
+				return err
+			}
+			if !active {
+				return s.roleService.DeactivateRole(ctx, created.ID, tenantID)
+			}
+			return nil
+		}}
+	}
+
+	change.ID = existing.ID.String()
+	if existing.Description != spec.Description {
+		change.Diff = append(change.Diff, manifest.FieldDiff{Field: "description", Before: existing.Description, After: spec.Description})
+	}
+	if existing.IsActive != active {
+		change.Diff = append(change.Diff, manifest.FieldDiff{Field: "is_active", Before: existing.IsActive, After: active})
+	}
+	stored := sortedSet(storedPerms)
+	permsChanged := !slices.Equal(stored, perms)
+	if permsChanged {
+		change.Diff = append(change.Diff, manifest.FieldDiff{Field: "permissions", Before: stored, After: perms})
+	}
+
+	if len(change.Diff) == 0 {
+		change.Action = manifest.ActionUnchanged
+		return step{change: change}
+	}
+
+	change.Action = manifest.ActionUpdate
+	return step{change: change, apply: func(ctx context.Context, _ map[string]kernel.ChannelID) error {
+		if _, err := s.roleService.UpdateRole(ctx, existing.ID, role.UpdateRoleRequest{
+			TenantID:    tenantID,
+			Description: &spec.Description,
+			IsActive:    &active,
+		}); err != nil {
+			return err
+		}
+		if permsChanged {
+			return s.roleService.SetRolePermissions(ctx, existing.ID, perms, tenantID)
+		}
+		return nil
+	}}
+}
+
+func sortedSet(values []string) []string {
+	set := slices.Clone(values)
+	slices.Sort(set)
+	set = slices.Compact(set)
+	if set == nil {
+		set = []string{}
+	}
+	return set
+}
+
+// ============================================================================
+// Workflows
+// ============================================================================
+
+func (s *ManifestService) planWorkflow(
+	ctx context.Context,
+	tenantID kernel.TenantID,
+	environment kernel.Environment,
+	spec manifest.WorkflowSpec,
+	existing *engine.Workflow,
+	planIDs map[string]kernel.ChannelID,
+) (step, error) {
+	active := spec.IsActive == nil || *spec.IsActive
+	debug := existing != nil && existing.Debug
+	if spec.Debug != nil {
+		debug = *spec.Debug
+	}
+
+	build := func(channelIDs map[string]kernel.ChannelID) (engine.Workflow, error) {
+		wf := engine.Workflow{
+			TenantID:    tenantID,
+			Name:        spec.Name,
+			Description: spec.Description,
+			Variables:   spec.Variables,
+			IsActive:    active,
+			Debug:       debug,
+			Environment: environment,
+		}
+		var err error
+		if wf.Trigger, err = resolveTrigger(spec.Trigger, channelIDs); err != nil {
+			return wf, err
+		}
+		wf.Nodes = make([]engine.WorkflowNode, len(spec.Nodes))
+		for i, node := range spec.Nodes {
+			wf.Nodes[i] = node
+			if wf.Nodes[i].Config, err = resolveMap(node.Config, channelIDs); err != nil {
+				return wf, err
+			}
+		}
+		if existing != nil {
+			wf.ID = existing.ID
+		} else {
+			wf.ID = kernel.NewWorkflowID("new")
+		}
+		return wf, nil
+	}
+
+	desired, err := build(planIDs)
+	if err != nil {
+		return step{}, err
+	}
+	if err := s.validator.ValidateWorkflow(ctx, desired); err != nil {
+		return step{}, err
+	}
+
+	change := manifest.Change{Kind: manifest.KindWorkflow, Name: spec.Name}
+
+	// save validates again with the real IDs of the channels created so far
+	save := func(ctx context.Context, wf engine.Workflow) error {
+		if err := s.validator.ValidateWorkflow(ctx, wf); err != nil {
+			return err
+		}
+		return s.workflowRepo.Save(ctx, wf)
+	}
+
+	if existing == nil {
+		change.Action = manifest.ActionCreate
+		return step{change: change, apply: func(ctx context.Context, channelIDs map[string]kernel.ChannelID) error {
+			wf, err := build(channelIDs)
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			wf.ID = kernel.NewWorkflowID(uuid.NewString())
+			wf.Version = 1
+			wf.CreatedAt = now
+			wf.UpdatedAt = now
+			return save(ctx, wf)
+		}}, nil
+	}
+
+	change.ID = existing.ID.String()
+	change.Diff = diffWorkflow(*existing, desired)
+	if len(change.Diff) == 0 {
+		change.Action = manifest.ActionUnchanged
+		return step{change: change}, nil
+	}
+
+	change.Action = manifest.ActionUpdate
+	return step{change: change, apply: func(ctx context.Context, channelIDs map[string]kernel.ChannelID) error {
+		wf, err := build(channelIDs)
+		if err != nil {
+			return err
+		}
+		// The stored version guards against edits made since the plan
+		wf.Version = existing.Version
+		wf.CreatedAt = existing.CreatedAt
+		wf.UpdatedAt = time.Now()
+		return save(ctx, wf)
+	}}, nil
+}
+
+// diffWorkflow reports every changed field; nodes are matched by ID, so an
+// added or removed node is a single entry
+func diffWorkflow(stored, desired engine.Workflow) []manifest.FieldDiff {
+	var diff []manifest.FieldDiff
+	if stored.Description != desired.Description {
+		diff = append(diff, manifest.FieldDiff{Field: "description", Before: stored.Description, After: desired.Description})
+	}
+	if stored.IsActive != desired.IsActive {
+		diff = append(diff, manifest.FieldDiff{Field: "is_active", Before: stored.IsActive, After: desired.IsActive})
+	}
+	if stored.Debug != desired.Debug {
+		diff = append(diff, manifest.FieldDiff{Field: "debug", Before: stored.Debug, After: desired.Debug})
+	}
+	diffValues("trigger", normalize(stored.Trigger), normalize(desired.Trigger), &diff)
+	if len(stored.Variables) > 0 || len(desired.Variables) > 0 {
+		diffValues("variables", normalize(stored.Variables), normalize(desired.Variables), &diff)
+	}
+
+	storedNodes := make(map[string]engine.WorkflowNode, len(stored.Nodes))
+	for _, node := range stored.Nodes {
+		storedNodes[node.ID] = node
+	}
+	for _, node := range desired.Nodes {
+		old, ok := storedNodes[node.ID]
+		delete(storedNodes, node.ID)
+		if !ok {
+			diff = append(diff, manifest.FieldDiff{Field: "nodes." + node.ID, After: normalize(node)})
+			continue
+		}
+		diffValues("nodes."+node.ID, normalize(old), normalize(node), &diff)
+	}
+	for _, id := range slices.Sorted(maps.Keys(storedNodes)) {
+		diff = append(diff, manifest.FieldDiff{Field: "nodes." + id, Before: normalize(storedNodes[id])})
+	}
+
+	// Reordering nodes changes nothing at run time but is still a change to
+	// the stored definition
+	if len(diff) == 0 && !slices.EqualFunc(stored.Nodes, desired.Nodes, func(a, b engine.WorkflowNode) bool { return a.ID == b.ID }) {
+		diff = append(diff, manifest.FieldDiff{Field: "nodes", Before: nodeIDs(stored.Nodes), After: nodeIDs(desired.Nodes)})
+	}
+	return diff
+}
+
+func nodeIDs(nodes []engine.WorkflowNode) []string {
+	ids := make([]string, len(nodes))
+	for i, node := range nodes {
+		ids[i] = node.ID
+	}
+	return ids
+}
+
+// normalize round-trips a value through JSON so stored and declared values
+// compare equal regardless of number types or nil versus empty maps
+func normalize(value any) any {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var out any
+	_ = json.Unmarshal(raw, &out)
+	if m, ok := out.(map[string]any); ok {
+		for key, v := range m {
+			if sub, ok := v.(map[string]any); ok && len(sub) == 0 {
+				delete(m, key)
+			}
+		}
+	}
+	return out
+}
+
+// ============================================================================
+// Channel References
+// ============================================================================
+
+func resolveTrigger(trigger engine.WorkflowTrigger, channelIDs map[string]kernel.ChannelID) (engine.WorkflowTrigger, error) {
+	var err error
+	if trigger.Config, err = resolveMap(trigger.Config, channelIDs); err != nil {
+		return trigger, err
+	}
+	trigger.Filters, err = resolveMap(trigger.Filters, channelIDs)
+	return trigger, err
+}
+
+// resolveMap returns a copy of m with every "@channel:<name>" string replaced
+// by the channel's ID
+func resolveMap(m map[string]any, channelIDs map[string]kernel.ChannelID) (map[string]any, error) {
+	if m == nil {
+		return nil, nil
+	}
+	resolved, err := resolveValue(m, channelIDs)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(map[string]any), nil
+}
+
+func resolveValue(value any, channelIDs map[string]kernel.ChannelID) (any, error) {
+	switch v := value.(type) {
+	case string:
+		name, ok := strings.CutPrefix(v, manifest.ChannelRefPrefix)
+		if !ok {
+			return v, nil
+		}
+		id, found := channelIDs[name]
+		if !found {
+			return nil, manifest.ErrUnknownChannel().WithDetail("channel", name)
+		}
+		return id.String(), nil
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			resolved, err := resolveValue(item, channelIDs)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = resolved
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			resolved, err := resolveValue(item, channelIDs)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// ============================================================================
+// Prune
+// ============================================================================
+
+// planDeletes removes what the manifest does not declare: workflows first,
+// since they reference channels, then roles and channels
+func (s *ManifestService) planDeletes(tenantID kernel.TenantID, m manifest.Manifest, current *state) []step {
+	declared := func(kind manifest.Kind) map[string]bool {
+		names := make(map[string]bool)
+		switch kind {
+		case manifest.KindChannel:
+			for _, spec := range m.Channels {
+				names[spec.Name] = true
+			}
+		case manifest.KindRole:
+			for _, spec := range m.Roles {
+				names[spec.Name] = true
+			}
+		case manifest.KindWorkflow:
+			for _, spec := range m.Workflows {
+				names[spec.Name] = true
+			}
+		}
+		return names
+	}
+
+	var steps []step
+
+	keepWorkflows := declared(manifest.KindWorkflow)
+	for _, name := range slices.Sorted(maps.Keys(current.workflows)) {
+		if keepWorkflows[name] {
+			continue
+		}
+		wf := current.workflows[name]
+		steps = append(steps, step{
+			change: manifest.Change{Kind: manifest.KindWorkflow, Name: name, Action: manifest.ActionDelete, ID: wf.ID.String()},
+			apply: func(ctx context.Context, _ map[string]kernel.ChannelID) error {
+				return s.workflowRepo.Delete(ctx, wf.ID, tenantID)
+			},
+		})
+	}
+
+	keepRoles := declared(manifest.KindRole)
+	for _, name := range slices.Sorted(maps.Keys(current.roles)) {
+		if keepRoles[name] {
+			continue
+		}
+		r := current.roles[name]
+		steps = append(steps, step{
+			change: manifest.Change{Kind: manifest.KindRole, Name: name, Action: manifest.ActionDelete, ID: r.ID.String()},
+			apply: func(ctx context.Context, _ map[string]kernel.ChannelID) error {
+				return s.roleService.DeleteRole(ctx, r.ID, tenantID)
+			},
+		})
+	}
+
+	keepChannels := declared(manifest.KindChannel)
+	for _, name := range slices.Sorted(maps.Keys(current.channels)) {
+		if keepChannels[name] {
+			continue
+		}
+		channel := current.channels[name]
+		steps = append(steps, step{
+			change: manifest.Change{Kind: manifest.KindChannel, Name: name, Action: manifest.ActionDelete, ID: channel.ID.String()},
+			apply: func(ctx context.Context, _ map[string]kernel.ChannelID) error {
+				return s.channelService.DeleteChannel(ctx, channel.ID, tenantID)
+			},
+		})
+	}
+
+	return steps
+}
+
+// withResource names the manifest resource an error comes from
+func withResource(err error, kind manifest.Kind, name string) error {
+	var xerr *errx.Error
+	if errors.As(err, &xerr) {
+		return xerr.WithDetail("kind", string(kind)).WithDetail("name", name)
+	}
+	return manifest.ErrInvalidResource().
+		WithDetail("kind", string(kind)).
+		WithDetail("name", name).
+		WithDetail("reason", fmt.Sprint(err))
+}
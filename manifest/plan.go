@@ -0,0 +1,117 @@
+package manifest
+
+import (
+	"fmt"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Plan
+// ============================================================================
+
+// Kind is a resource kind a manifest can declare
+type Kind string
+
+const (
+	KindChannel  Kind = "channel"
+	KindRole     Kind = "role"
+	KindWorkflow Kind = "workflow"
+)
+
+// Action is what applying the plan does to one resource
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionDelete    Action = "delete"
+	ActionUnchanged Action = "unchanged"
+)
+
+// Change is the planned action on one resource
+type Change struct {
+	Kind   Kind        `json:"kind"`
+	Name   string      `json:"name"`
+	Action Action      `json:"action"`
+	ID     string      `json:"id,omitempty"` // Empty for creates
+	Diff   []FieldDiff `json:"diff,omitempty"`
+}
+
+// FieldDiff is one field that differs between the stored resource and the
+// manifest. Secret-looking channel config values are masked.
+type FieldDiff struct {
+	Field  string `json:"field"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// Plan lists the changes in the order they are applied: channels first so
+// workflows can reference them, then roles, then workflows. Deletes come
+// last, in reverse order.
+type Plan struct {
+	Environment kernel.Environment `json:"environment"`
+	Changes     []Change           `json:"changes"`
+	Summary     Summary            `json:"summary"`
+	Prune       bool               `json:"prune"`
+}
+
+// Summary counts the changes by action
+type Summary struct {
+	Create    int `json:"create"`
+	Update    int `json:"update"`
+	Delete    int `json:"delete"`
+	Unchanged int `json:"unchanged"`
+}
+
+// Add appends a change and counts it
+func (p *Plan) Add(change Change) {
+	p.Changes = append(p.Changes, change)
+	switch change.Action {
+	case ActionCreate:
+		p.Summary.Create++
+	case ActionUpdate:
+		p.Summary.Update++
+	case ActionDelete:
+		p.Summary.Delete++
+	default:
+		p.Summary.Unchanged++
+	}
+}
+
+// HasChanges reports whether applying the plan modifies anything
+func (p *Plan) HasChanges() bool {
+	return p.Summary.Create+p.Summary.Update+p.Summary.Delete > 0
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("%d to create, %d to update, %d to delete, %d unchanged",
+		s.Create, s.Update, s.Delete, s.Unchanged)
+}
+
+// ============================================================================
+// Apply Result
+// ============================================================================
+
+// ApplyResult is the outcome of applying a plan. Changes are applied one by
+// one and the apply stops at the first failure; Applied lists what was
+// already done at that point.
+type ApplyResult struct {
+	Plan    Plan     `json:"plan"`
+	Applied []Change `json:"applied"`
+	Failed  *Change  `json:"failed,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// ============================================================================
+// Secrets
+// ============================================================================
+
+// MaskedValue replaces secret values in a diff, as the channel API does
+const MaskedValue = channels.RedactedSecret
+
+// IsSecretKey reports whether a config key holds a credential
+func IsSecretKey(key string) bool {
+	return channels.IsSecretConfigKey(key)
+}
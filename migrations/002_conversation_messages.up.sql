@@ -0,0 +1,18 @@
+-- ============================================================================
+-- CONVERSATION MESSAGES (inbound + outbound transcript)
+-- ============================================================================
+
+ALTER TABLE messages
+    ADD COLUMN conversation_id VARCHAR(255),
+    ADD COLUMN direction VARCHAR(20) NOT NULL DEFAULT 'INBOUND' CHECK (direction IN ('INBOUND', 'OUTBOUND')),
+    ADD COLUMN origin VARCHAR(50) NOT NULL DEFAULT 'contact',
+    ADD COLUMN provider_message_id VARCHAR(255),
+    ADD COLUMN workflow_id TEXT,
+    ADD COLUMN node_id VARCHAR(255);
+
+-- Existing rows were all inbound, the conversation is the sender
+UPDATE messages SET conversation_id = sender_id WHERE conversation_id IS NULL;
+
+ALTER TABLE messages ALTER COLUMN conversation_id SET NOT NULL;
+
+CREATE INDEX idx_messages_conversation ON messages(tenant_id, conversation_id, created_at);
@@ -0,0 +1,16 @@
+-- ============================================================================
+-- CONTACT SUPPRESSIONS (do-not-contact list)
+-- ============================================================================
+
+CREATE TABLE contact_suppressions (
+    id TEXT PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    channel_id TEXT REFERENCES channels(id) ON DELETE CASCADE, -- NULL = all channels
+    recipient_id VARCHAR(255) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_contact_suppressions_unique
+    ON contact_suppressions(tenant_id, recipient_id, COALESCE(channel_id, ''));
+CREATE INDEX idx_contact_suppressions_lookup ON contact_suppressions(tenant_id, recipient_id);
@@ -0,0 +1,36 @@
+-- ============================================================================
+-- EMAIL/PASSWORD AUTHENTICATION
+-- ============================================================================
+
+ALTER TABLE users
+    ADD COLUMN password_hash TEXT,
+    ADD COLUMN failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN locked_until TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_oauth_provider_check;
+ALTER TABLE users ADD CONSTRAINT users_oauth_provider_check
+    CHECK (oauth_provider IN ('GOOGLE', 'MICROSOFT', 'AUTH0', 'PASSWORD'));
+
+-- Reset tokens need the tenant to resolve the user
+ALTER TABLE password_reset_tokens
+    ADD COLUMN tenant_id TEXT REFERENCES tenants(id) ON DELETE CASCADE;
+
+UPDATE password_reset_tokens p
+SET tenant_id = u.tenant_id
+FROM users u
+WHERE u.id = p.user_id AND p.tenant_id IS NULL;
+
+ALTER TABLE password_reset_tokens ALTER COLUMN tenant_id SET NOT NULL;
+
+-- Email verification tokens table
+CREATE TABLE email_verification_tokens (
+    id TEXT PRIMARY KEY DEFAULT uuid_generate_v4(),
+    token TEXT UNIQUE NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    is_used BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE INDEX idx_email_verification_tokens_user ON email_verification_tokens(user_id);
@@ -0,0 +1,16 @@
+-- ============================================================================
+-- USER TENANT MEMBERSHIPS (one user, many tenants)
+-- ============================================================================
+
+-- Additional tenants a user can access. The home tenant (users.tenant_id)
+-- is implicit and has no row here.
+CREATE TABLE user_tenant_memberships (
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    is_admin BOOLEAN NOT NULL DEFAULT false,
+    invited_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, tenant_id)
+);
+
+CREATE INDEX idx_user_tenant_memberships_tenant ON user_tenant_memberships(tenant_id);
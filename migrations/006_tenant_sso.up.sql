@@ -0,0 +1,34 @@
+-- ============================================================================
+-- ENTERPRISE SSO (OIDC / SAML) PER TENANT
+-- ============================================================================
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_oauth_provider_check;
+ALTER TABLE users ADD CONSTRAINT users_oauth_provider_check
+    CHECK (oauth_provider IN ('GOOGLE', 'MICROSOFT', 'AUTH0', 'PASSWORD', 'OIDC', 'SAML'));
+
+CREATE TABLE tenant_sso_configs (
+    tenant_id TEXT PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    protocol TEXT NOT NULL CHECK (protocol IN ('OIDC', 'SAML')),
+    is_active BOOLEAN NOT NULL DEFAULT false,
+
+    -- OIDC
+    issuer_url TEXT NOT NULL DEFAULT '',
+    client_id TEXT NOT NULL DEFAULT '',
+    client_secret TEXT NOT NULL DEFAULT '',
+
+    -- SAML
+    idp_entity_id TEXT NOT NULL DEFAULT '',
+    idp_sso_url TEXT NOT NULL DEFAULT '',
+    idp_certificate TEXT NOT NULL DEFAULT '',
+
+    -- Provisioning: IdP group -> role id
+    groups_claim TEXT NOT NULL DEFAULT 'groups',
+    role_mappings JSONB NOT NULL DEFAULT '{}',
+    admin_groups TEXT[] NOT NULL DEFAULT '{}',
+    jit_provisioning BOOLEAN NOT NULL DEFAULT false,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_tenant_sso_configs_updated_at BEFORE UPDATE ON tenant_sso_configs FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
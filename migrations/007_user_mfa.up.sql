@@ -0,0 +1,19 @@
+-- ============================================================================
+-- TWO-FACTOR AUTHENTICATION (TOTP)
+-- ============================================================================
+
+CREATE TABLE user_mfa (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret_encrypted TEXT NOT NULL,           -- AES-GCM, never stored in clear
+    enabled BOOLEAN NOT NULL DEFAULT false,   -- false until the first code is confirmed
+    backup_codes TEXT[] NOT NULL DEFAULT '{}', -- SHA-256 hashes, removed on use
+    last_used_step BIGINT NOT NULL DEFAULT 0, -- rejects replay of an accepted code
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_user_mfa_updated_at BEFORE UPDATE ON user_mfa FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Authentication methods of the session that issued the refresh token
+ALTER TABLE refresh_tokens ADD COLUMN amr TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,10 @@
+-- ============================================================================
+-- SESSION DEVICE MANAGEMENT
+-- ============================================================================
+
+-- Refresh tokens belong to the session that issued them; revoking (deleting)
+-- the session invalidates its refresh tokens as well
+ALTER TABLE refresh_tokens
+    ADD COLUMN session_id TEXT REFERENCES user_sessions(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_refresh_tokens_session_id ON refresh_tokens(session_id);
@@ -0,0 +1,19 @@
+-- ============================================================================
+-- TENANT DATA EXPORTS (portability before offboarding)
+-- ============================================================================
+
+CREATE TABLE tenant_data_exports (
+    id TEXT PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    requested_by TEXT NOT NULL,                -- No FK: the requesting user may be deleted later
+    format VARCHAR(10) NOT NULL DEFAULT 'json' CHECK (format IN ('json', 'csv')),
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'RUNNING', 'COMPLETED', 'FAILED')),
+    file_path TEXT NOT NULL DEFAULT '',        -- Zip archive on the export volume
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_tenant_data_exports_tenant ON tenant_data_exports(tenant_id, created_at DESC);
@@ -0,0 +1,28 @@
+-- ============================================================================
+-- DATA RETENTION (per-tenant policies + anonymization markers)
+-- ============================================================================
+
+CREATE TABLE retention_policies (
+    tenant_id TEXT PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    message_retention_days INTEGER NOT NULL DEFAULT 0 CHECK (message_retention_days >= 0),     -- 0 = keep forever
+    session_retention_days INTEGER NOT NULL DEFAULT 0 CHECK (session_retention_days >= 0),
+    execution_retention_days INTEGER NOT NULL DEFAULT 0 CHECK (execution_retention_days >= 0),
+    action VARCHAR(20) NOT NULL DEFAULT 'ANONYMIZE' CHECK (action IN ('DELETE', 'ANONYMIZE')),
+    redact_pii BOOLEAN NOT NULL DEFAULT false,
+    anonymization_salt TEXT NOT NULL DEFAULT md5(random()::text || clock_timestamp()::text), -- Never leaves the database
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_retention_policies_updated_at
+    BEFORE UPDATE ON retention_policies
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Anonymized rows are skipped by later purge runs
+ALTER TABLE messages ADD COLUMN redacted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE agent_messages ADD COLUMN redacted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE workflow_executions ADD COLUMN redacted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_messages_retention ON messages(tenant_id, created_at) WHERE redacted_at IS NULL;
+CREATE INDEX idx_agent_messages_retention ON agent_messages(tenant_id, created_at) WHERE redacted_at IS NULL;
+CREATE INDEX idx_workflow_executions_retention ON workflow_executions(tenant_id, started_at) WHERE redacted_at IS NULL;
@@ -0,0 +1,17 @@
+-- ============================================================================
+-- FIELD ENCRYPTION (per-tenant data keys wrapped by the server master key)
+-- ============================================================================
+
+CREATE TABLE tenant_encryption_keys (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL CHECK (version > 0),
+    wrapped_key TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'RETIRED')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    retired_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (tenant_id, version)
+);
+
+-- At most one active key per tenant
+CREATE UNIQUE INDEX idx_tenant_encryption_keys_active
+    ON tenant_encryption_keys(tenant_id) WHERE status = 'ACTIVE';
@@ -0,0 +1,16 @@
+-- ============================================================================
+-- FEATURE FLAGS (platform-wide values; tenant overrides live in tenant_config
+-- under "feature.<flag>")
+-- ============================================================================
+
+CREATE TABLE feature_flags (
+    flag VARCHAR(100) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_feature_flags_updated_at
+    BEFORE UPDATE ON feature_flags
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,6 @@
+-- ============================================================================
+-- WORKFLOW VERSIONING (optimistic concurrency: updates must carry the version
+-- they were read at and bump it, so concurrent editors detect conflicts)
+-- ============================================================================
+
+ALTER TABLE workflows ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
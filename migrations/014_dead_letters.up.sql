@@ -0,0 +1,24 @@
+-- ============================================================================
+-- DEAD LETTERS (triggers whose processing failed, kept for reprocessing)
+-- ============================================================================
+
+CREATE TABLE dead_letters (
+    id TEXT PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    trigger_type VARCHAR(50) NOT NULL,
+    workflow_id TEXT,                          -- NULL = failed before matching workflows
+    filters JSONB NOT NULL DEFAULT '{}',       -- Trigger filters used to match workflows again
+    payload JSONB NOT NULL,                    -- Trigger data as received
+    error TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'REPROCESSED')),
+    retry_count INTEGER NOT NULL DEFAULT 0,
+    last_retried_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_dead_letters_tenant ON dead_letters(tenant_id, status, created_at DESC);
+
+CREATE TRIGGER update_dead_letters_updated_at
+    BEFORE UPDATE ON dead_letters
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,6 @@
+-- ============================================================================
+-- MESSAGE LOOKUP BY PROVIDER ID (resumed workflows reload their trigger message)
+-- ============================================================================
+
+CREATE INDEX idx_messages_provider_message ON messages(tenant_id, channel_id, provider_message_id)
+    WHERE provider_message_id IS NOT NULL;
@@ -0,0 +1,5 @@
+-- ============================================================================
+-- SCHEDULE PAYLOAD (trigger data for scheduled runs, e.g. one-off reminders)
+-- ============================================================================
+
+ALTER TABLE workflow_schedules ADD COLUMN payload JSONB;
@@ -0,0 +1,59 @@
+-- ============================================================================
+-- DRIP SEQUENCES (timed steps per contact, exiting on reply or opt-out)
+-- ============================================================================
+
+CREATE TABLE sequences (
+    id TEXT PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    steps JSONB NOT NULL,                      -- [{id, type, after_seconds, text, template_id, variables, workflow_id}]
+    exit_on_reply BOOLEAN NOT NULL DEFAULT true,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, name)
+);
+
+CREATE TRIGGER update_sequences_updated_at
+    BEFORE UPDATE ON sequences
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE sequence_enrollments (
+    id TEXT PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    sequence_id TEXT NOT NULL REFERENCES sequences(id) ON DELETE CASCADE,
+    contact_id VARCHAR(255) NOT NULL,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    context JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'COMPLETED', 'EXITED')),
+    exit_reason VARCHAR(50) NOT NULL DEFAULT '',
+    next_step INTEGER NOT NULL DEFAULT 0,      -- Index of the step that runs at next_step_at
+    next_step_at TIMESTAMP WITH TIME ZONE,
+    enrolled_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- A contact is in a sequence at most once at a time
+CREATE UNIQUE INDEX idx_sequence_enrollments_active
+    ON sequence_enrollments(sequence_id, contact_id) WHERE status = 'ACTIVE';
+CREATE INDEX idx_sequence_enrollments_due
+    ON sequence_enrollments(next_step_at) WHERE status = 'ACTIVE';
+CREATE INDEX idx_sequence_enrollments_contact
+    ON sequence_enrollments(tenant_id, channel_id, contact_id) WHERE status = 'ACTIVE';
+CREATE INDEX idx_sequence_enrollments_sequence ON sequence_enrollments(sequence_id, status, enrolled_at DESC);
+
+CREATE TABLE sequence_step_runs (
+    id TEXT PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    sequence_id TEXT NOT NULL REFERENCES sequences(id) ON DELETE CASCADE,
+    enrollment_id TEXT NOT NULL REFERENCES sequence_enrollments(id) ON DELETE CASCADE,
+    step_id VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('SENT', 'FAILED')),
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_sequence_step_runs_sequence ON sequence_step_runs(sequence_id, step_id, status);
@@ -0,0 +1,23 @@
+-- ============================================================================
+-- INBOUND ATTACHMENTS (provider media copied to tenant-scoped storage)
+-- ============================================================================
+
+CREATE TABLE attachments (
+    id TEXT PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    channel_id TEXT NOT NULL,
+    provider_message_id VARCHAR(255) NOT NULL DEFAULT '',
+    type VARCHAR(20) NOT NULL DEFAULT '',
+    mime_type VARCHAR(255) NOT NULL DEFAULT '',
+    filename VARCHAR(512) NOT NULL DEFAULT '',
+    size BIGINT NOT NULL DEFAULT 0,
+    sha256 CHAR(64) NOT NULL,
+    storage_key TEXT NOT NULL DEFAULT '',      -- Empty when an infected file was discarded
+    scan_status VARCHAR(20) NOT NULL CHECK (scan_status IN ('PENDING', 'CLEAN', 'INFECTED', 'FAILED', 'SKIPPED')),
+    scan_detail TEXT NOT NULL DEFAULT '',
+    scanned_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_attachments_message ON attachments(tenant_id, channel_id, provider_message_id);
+CREATE INDEX idx_attachments_withheld ON attachments(tenant_id, created_at) WHERE scan_status = 'FAILED';
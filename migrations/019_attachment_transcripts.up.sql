@@ -0,0 +1,5 @@
+-- ============================================================================
+-- VOICE NOTE TRANSCRIPTS
+-- ============================================================================
+
+ALTER TABLE attachments ADD COLUMN transcript JSONB; -- {text, language, confidence, provider, low_confidence}
@@ -0,0 +1,5 @@
+-- ============================================================================
+-- IMAGE UNDERSTANDING
+-- ============================================================================
+
+ALTER TABLE attachments ADD COLUMN image_analysis JSONB; -- {text, description, provider}
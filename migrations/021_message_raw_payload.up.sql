@@ -0,0 +1,7 @@
+-- ============================================================================
+-- RAW PROVIDER PAYLOADS
+-- ============================================================================
+
+-- Webhook event as the provider sent it, kept for debugging inbound messages.
+-- Encrypted like content for tenants with encryption enabled.
+ALTER TABLE messages ADD COLUMN raw_payload JSONB;
@@ -0,0 +1,25 @@
+-- ============================================================================
+-- RAW WEBHOOK EVENTS (provider events no adapter handles yet, kept for reprocessing)
+-- ============================================================================
+
+CREATE TABLE raw_webhook_events (
+    id TEXT PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,             -- Channel type: WHATSAPP, INSTAGRAM, ...
+    event_type VARCHAR(255) NOT NULL,          -- As the adapter names it, e.g. message:order
+    payload JSONB NOT NULL,                    -- Webhook body as received (signature verified)
+    error TEXT,                                -- Outcome of the last reprocess
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'REPROCESSED')),
+    reprocess_count INTEGER NOT NULL DEFAULT 0,
+    last_reprocess_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_raw_webhook_events_tenant ON raw_webhook_events(tenant_id, status, created_at DESC);
+CREATE INDEX idx_raw_webhook_events_type ON raw_webhook_events(provider, event_type, status);
+
+CREATE TRIGGER update_raw_webhook_events_updated_at
+    BEFORE UPDATE ON raw_webhook_events
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
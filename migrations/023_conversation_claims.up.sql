@@ -0,0 +1,19 @@
+-- ============================================================================
+-- CONVERSATION CLAIMS (operator owning a conversation in the agent inbox)
+-- ============================================================================
+
+CREATE TABLE conversation_claims (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    conversation_id VARCHAR(255) NOT NULL,     -- Contact's id on the channel
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    claimed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, channel_id, conversation_id)
+);
+
+CREATE INDEX idx_conversation_claims_user ON conversation_claims(tenant_id, user_id, claimed_at DESC);
+
+CREATE TRIGGER update_conversation_claims_updated_at
+    BEFORE UPDATE ON conversation_claims
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
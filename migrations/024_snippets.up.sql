@@ -0,0 +1,22 @@
+-- ============================================================================
+-- SNIPPETS (canned replies shared by operators and workflows)
+-- ============================================================================
+
+CREATE TABLE snippets (
+    id TEXT PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    category VARCHAR(100) NOT NULL DEFAULT '',
+    content TEXT NOT NULL,                     -- Text with {{variable}} placeholders
+    variables JSONB NOT NULL DEFAULT '[]',     -- [{name, description, default, required}]
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, name)
+);
+
+CREATE INDEX idx_snippets_category ON snippets(tenant_id, category, name);
+
+CREATE TRIGGER update_snippets_updated_at
+    BEFORE UPDATE ON snippets
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
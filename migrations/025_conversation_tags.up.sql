@@ -0,0 +1,23 @@
+-- ============================================================================
+-- CONVERSATION TAGS (labels and dispositions set by workflows and operators)
+-- ============================================================================
+
+CREATE TABLE conversation_tags (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    conversation_id VARCHAR(255) NOT NULL,     -- Contact's id on the channel
+    tag VARCHAR(64) NOT NULL,                  -- Normalized: sales-lead, refund, vip
+    kind VARCHAR(20) NOT NULL DEFAULT 'TAG' CHECK (kind IN ('TAG', 'DISPOSITION')),
+    source VARCHAR(20) NOT NULL CHECK (source IN ('workflow', 'action', 'operator')),
+    set_by TEXT NOT NULL DEFAULT '',           -- User or workflow ID
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, channel_id, conversation_id, kind, tag)
+);
+
+-- One disposition per conversation
+CREATE UNIQUE INDEX idx_conversation_tags_disposition
+    ON conversation_tags(tenant_id, channel_id, conversation_id)
+    WHERE kind = 'DISPOSITION';
+
+CREATE INDEX idx_conversation_tags_tag ON conversation_tags(tenant_id, kind, tag, created_at DESC);
+CREATE INDEX idx_conversation_tags_contact ON conversation_tags(tenant_id, conversation_id);
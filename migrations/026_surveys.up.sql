@@ -0,0 +1,43 @@
+-- ============================================================================
+-- SURVEYS (CSAT and NPS ratings asked by SURVEY nodes)
+-- ============================================================================
+
+CREATE TABLE surveys (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    workflow_id TEXT NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    node_id VARCHAR(255) NOT NULL,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    conversation_id VARCHAR(255) NOT NULL,     -- Contact's id on the channel
+    agent_id TEXT NOT NULL DEFAULT '',         -- Operator credited with the score
+    scale VARCHAR(10) NOT NULL CHECK (scale IN ('csat', 'nps')),
+    question TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'ANSWERED', 'EXPIRED')),
+    score INTEGER,
+    answer TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 0,
+    invalid_reply TEXT NOT NULL DEFAULT '',
+
+    -- Resuming the paused run
+    next_node_id VARCHAR(255) NOT NULL DEFAULT '',
+    continuation_id TEXT NOT NULL,
+    node_context JSONB NOT NULL DEFAULT '{}',
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    answered_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Matching a contact's reply to the survey it answers
+CREATE INDEX idx_surveys_pending
+    ON surveys(tenant_id, channel_id, conversation_id, created_at DESC)
+    WHERE status = 'PENDING';
+
+CREATE INDEX idx_surveys_workflow ON surveys(tenant_id, workflow_id, created_at DESC);
+CREATE INDEX idx_surveys_agent ON surveys(tenant_id, agent_id, created_at DESC);
+
+CREATE TRIGGER update_surveys_updated_at
+    BEFORE UPDATE ON surveys
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,21 @@
+-- ============================================================================
+-- EXPERIMENT EVENTS (exposures and conversions of EXPERIMENT nodes)
+-- ============================================================================
+
+CREATE TABLE experiment_events (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    workflow_id TEXT NOT NULL DEFAULT '',      -- Empty for conversions reported through the API
+    experiment VARCHAR(255) NOT NULL,
+    variant VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('EXPOSURE', 'CONVERSION')),
+    contact_id VARCHAR(255) NOT NULL,
+    channel_id TEXT NOT NULL DEFAULT '',
+    goal VARCHAR(255) NOT NULL DEFAULT '',     -- Empty = the experiment's main goal
+    value DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_experiment_events_experiment ON experiment_events(tenant_id, experiment, kind, created_at);
+CREATE INDEX idx_experiment_events_contact ON experiment_events(tenant_id, experiment, contact_id, created_at DESC)
+    WHERE kind = 'EXPOSURE';
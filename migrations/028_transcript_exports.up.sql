@@ -0,0 +1,24 @@
+-- ============================================================================
+-- TRANSCRIPT EXPORTS (filtered transcript files for compliance and datasets)
+-- ============================================================================
+
+CREATE TABLE transcript_exports (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    requested_by TEXT NOT NULL,                -- No FK: the requesting user may be deleted later
+    format VARCHAR(10) NOT NULL CHECK (format IN ('jsonl', 'csv')),
+    filters JSONB NOT NULL DEFAULT '{}',       -- {from, to, channel_id, tags}
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'RUNNING', 'COMPLETED', 'FAILED')),
+    total_messages INTEGER NOT NULL DEFAULT 0,
+    processed_messages INTEGER NOT NULL DEFAULT 0,
+    conversations INTEGER NOT NULL DEFAULT 0,
+    storage_key TEXT NOT NULL DEFAULT '',      -- Key in the attachment storage
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_transcript_exports_tenant ON transcript_exports(tenant_id, created_at DESC);
+CREATE INDEX idx_transcript_exports_active ON transcript_exports(status) WHERE status IN ('PENDING', 'RUNNING');
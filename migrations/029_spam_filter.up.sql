@@ -0,0 +1,35 @@
+-- ============================================================================
+-- SPAM FILTER (per-tenant inbound policies + blocked senders)
+-- ============================================================================
+
+CREATE TABLE spam_filter_policies (
+    tenant_id TEXT PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    action VARCHAR(20) NOT NULL DEFAULT 'FLAG' CHECK (action IN ('FLAG', 'DROP', 'BLOCK')),
+    max_messages_per_minute INTEGER NOT NULL DEFAULT 0 CHECK (max_messages_per_minute >= 0), -- Per sender and channel; 0 = unlimited
+    blocked_keywords TEXT[] NOT NULL DEFAULT '{}',
+    block_links BOOLEAN NOT NULL DEFAULT false,
+    allowed_domains TEXT[] NOT NULL DEFAULT '{}',
+    use_classifier BOOLEAN NOT NULL DEFAULT false,
+    classifier_threshold DOUBLE PRECISION NOT NULL DEFAULT 0.9 CHECK (classifier_threshold > 0 AND classifier_threshold <= 1),
+    block_minutes INTEGER NOT NULL DEFAULT 0 CHECK (block_minutes >= 0),                 -- 0 = until unblocked
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_spam_filter_policies_updated_at
+    BEFORE UPDATE ON spam_filter_policies
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE spam_blocked_senders (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    sender_id VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    blocked_by VARCHAR(255) NOT NULL,          -- User ID, or 'filter' for automatic blocks
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE,       -- NULL = until unblocked
+    PRIMARY KEY (tenant_id, channel_id, sender_id)
+);
+
+CREATE INDEX idx_spam_blocked_senders_recent ON spam_blocked_senders(tenant_id, created_at DESC);
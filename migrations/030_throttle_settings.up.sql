@@ -0,0 +1,17 @@
+-- ============================================================================
+-- SENDER THROTTLING (messages per sender that trigger workflows)
+-- ============================================================================
+
+CREATE TABLE throttle_settings (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    channel_id TEXT REFERENCES channels(id) ON DELETE CASCADE,     -- NULL = tenant default
+    max_per_minute INTEGER NOT NULL DEFAULT 0 CHECK (max_per_minute >= 0), -- 0 = unlimited
+    burst INTEGER NOT NULL DEFAULT 0 CHECK (burst >= 0),
+    cooldown_seconds INTEGER NOT NULL DEFAULT 60 CHECK (cooldown_seconds >= 0),
+    cooldown_message TEXT NOT NULL DEFAULT '',                      -- Empty = no reply to throttled senders
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_throttle_settings_unique
+    ON throttle_settings(tenant_id, (COALESCE(channel_id, '')));
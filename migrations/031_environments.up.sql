@@ -0,0 +1,23 @@
+-- ============================================================================
+-- ENVIRONMENTS (sandbox vs production channels and workflows)
+-- ============================================================================
+
+-- Existing rows are production; names are unique per environment so a
+-- sandbox workflow can be promoted under the same name.
+ALTER TABLE channels
+    ADD COLUMN environment VARCHAR(20) NOT NULL DEFAULT 'production'
+    CHECK (environment IN ('sandbox', 'production'));
+
+ALTER TABLE channels DROP CONSTRAINT channels_name_tenant_id_key;
+ALTER TABLE channels
+    ADD CONSTRAINT channels_tenant_environment_name_key UNIQUE (tenant_id, environment, name);
+
+ALTER TABLE workflows
+    ADD COLUMN environment VARCHAR(20) NOT NULL DEFAULT 'production'
+    CHECK (environment IN ('sandbox', 'production'));
+
+ALTER TABLE workflows DROP CONSTRAINT workflows_name_tenant_id_key;
+ALTER TABLE workflows
+    ADD CONSTRAINT workflows_tenant_environment_name_key UNIQUE (tenant_id, environment, name);
+
+CREATE INDEX idx_workflows_environment ON workflows(tenant_id, environment, is_active);
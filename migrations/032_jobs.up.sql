@@ -0,0 +1,40 @@
+-- ============================================================================
+-- BACKGROUND JOBS (shared queue for one-off and recurring work, with run history)
+-- ============================================================================
+
+CREATE TABLE jobs (
+    id TEXT PRIMARY KEY,
+    kind VARCHAR(100) NOT NULL,                     -- Handler name, e.g. retention.purge
+    tenant_id TEXT REFERENCES tenants(id) ON DELETE CASCADE, -- NULL = system job
+    unique_key TEXT UNIQUE,                          -- Recurring jobs exist once per key
+    payload JSONB NOT NULL DEFAULT '{}',
+    schedule TEXT NOT NULL DEFAULT '',               -- Cron expression or @every <duration>; empty = one-off
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 3 CHECK (max_attempts > 0),
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_by TEXT,
+    locked_until TIMESTAMP WITH TIME ZONE,           -- A running job past its lease is claimed again
+    last_error TEXT NOT NULL DEFAULT '',
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_jobs_due ON jobs(run_at) WHERE status IN ('pending', 'running');
+CREATE INDEX idx_jobs_tenant ON jobs(tenant_id, created_at DESC);
+
+CREATE TABLE job_runs (
+    id TEXT PRIMARY KEY,
+    job_id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    worker TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('running', 'succeeded', 'failed', 'interrupted')),
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE,
+    duration_ms BIGINT
+);
+
+CREATE INDEX idx_job_runs_job ON job_runs(job_id, started_at DESC);
+CREATE INDEX idx_job_runs_started ON job_runs(started_at);
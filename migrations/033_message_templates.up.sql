@@ -0,0 +1,20 @@
+-- ============================================================================
+-- MESSAGE TEMPLATES (keyed, localized messages referenced by workflows)
+-- ============================================================================
+
+CREATE TABLE message_templates (
+    id TEXT PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    key VARCHAR(100) NOT NULL,                 -- e.g. order.shipped
+    description TEXT NOT NULL DEFAULT '',
+    default_locale VARCHAR(35) NOT NULL,       -- Variant used when no locale matches
+    variants JSONB NOT NULL DEFAULT '[]',      -- [{locale, content}], content in ICU message format
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, key)
+);
+
+CREATE TRIGGER update_message_templates_updated_at
+    BEFORE UPDATE ON message_templates
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
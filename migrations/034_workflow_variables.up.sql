@@ -0,0 +1,6 @@
+-- ============================================================================
+-- WORKFLOW CONTEXT VARIABLES (typed schema read as {{vars.<name>}})
+-- ============================================================================
+
+ALTER TABLE workflows
+    ADD COLUMN variables JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,37 @@
+-- ============================================================================
+-- WORKFLOW SNAPSHOTS (context at every node of debug runs, for replay)
+-- ============================================================================
+
+ALTER TABLE workflows
+    ADD COLUMN debug BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE workflow_runs (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    workflow_id TEXT NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    workflow_version INTEGER NOT NULL,          -- Version the run executed
+    trigger_data JSONB NOT NULL DEFAULT '{}',
+    metadata JSONB NOT NULL DEFAULT '{}',
+    resumed_at TEXT,                            -- Node a delayed run resumed at, NULL = started by a trigger
+    success BOOLEAN NOT NULL DEFAULT TRUE,
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE        -- NULL = still running
+);
+
+CREATE INDEX idx_workflow_runs_tenant ON workflow_runs(tenant_id, started_at DESC);
+CREATE INDEX idx_workflow_runs_workflow ON workflow_runs(workflow_id, started_at DESC);
+CREATE INDEX idx_workflow_runs_started ON workflow_runs(started_at);
+
+CREATE TABLE workflow_run_snapshots (
+    run_id TEXT NOT NULL REFERENCES workflow_runs(id) ON DELETE CASCADE,
+    sequence INTEGER NOT NULL,                  -- Order of the node within the run
+    node_id TEXT NOT NULL,
+    node_type VARCHAR(50) NOT NULL,
+    context JSONB NOT NULL,                     -- Context before the node ran
+    config JSONB,                               -- Evaluated config, NULL = expressions failed
+    next_node TEXT,                             -- Branch chosen by routing nodes
+    result JSONB NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (run_id, sequence)
+);
@@ -0,0 +1,28 @@
+-- ============================================================================
+-- FALLBACK (channel messages no workflow matched)
+-- ============================================================================
+
+CREATE TABLE fallback_settings (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    channel_id TEXT REFERENCES channels(id) ON DELETE CASCADE,       -- NULL = tenant default
+    workflow_id TEXT REFERENCES workflows(id) ON DELETE SET NULL,    -- Runs as if it had matched
+    message TEXT NOT NULL DEFAULT '',                                 -- Sent when there is no usable workflow
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_fallback_settings_unique
+    ON fallback_settings(tenant_id, (COALESCE(channel_id, '')));
+
+-- Unmatched messages per channel and day (UTC)
+CREATE TABLE unmatched_message_counts (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    unmatched INTEGER NOT NULL DEFAULT 0,
+    workflow_runs INTEGER NOT NULL DEFAULT 0,   -- Handled by the fallback workflow
+    messages_sent INTEGER NOT NULL DEFAULT 0,   -- Answered with the fallback message
+    PRIMARY KEY (tenant_id, channel_id, day)
+);
+
+CREATE INDEX idx_unmatched_message_counts_day ON unmatched_message_counts(tenant_id, day);
@@ -0,0 +1,14 @@
+-- ============================================================================
+-- CRM CONNECTIONS (HubSpot, Salesforce)
+-- ============================================================================
+
+CREATE TABLE crm_connections (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,                          -- hubspot, salesforce
+    account_id TEXT NOT NULL DEFAULT '',             -- HubSpot portal or Salesforce org
+    credentials JSONB NOT NULL,                      -- OAuth tokens; sealed when the tenant has encryption enabled
+    field_mapping JSONB NOT NULL DEFAULT '{}',       -- Relay field → CRM property, per record type
+    connected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, provider)
+);
@@ -0,0 +1,31 @@
+-- ============================================================================
+-- HELPDESK CONNECTIONS (Zendesk, Freshdesk)
+-- ============================================================================
+
+CREATE TABLE helpdesk_connections (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,                          -- zendesk, freshdesk
+    domain TEXT NOT NULL,                            -- Account subdomain or host
+    credentials JSONB NOT NULL,                      -- API token or key; sealed when the tenant has encryption enabled
+    connected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, provider)
+);
+
+-- ============================================================================
+-- CONVERSATION TICKETS
+-- ============================================================================
+
+-- The ticket each conversation's messages are appended to, until it is closed
+CREATE TABLE helpdesk_tickets (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    conversation_id TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    ticket_id TEXT NOT NULL,
+    priority TEXT NOT NULL DEFAULT 'normal',
+    synced_until TIMESTAMP WITH TIME ZONE NOT NULL,  -- Last message written to the ticket
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, channel_id, conversation_id, provider)
+);
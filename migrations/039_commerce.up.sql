@@ -0,0 +1,14 @@
+-- ============================================================================
+-- COMMERCE CONNECTIONS (Shopify, WooCommerce)
+-- ============================================================================
+
+CREATE TABLE commerce_connections (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,                          -- shopify, woocommerce
+    domain TEXT NOT NULL,                            -- Store name or host
+    credentials JSONB NOT NULL,                      -- Access token or consumer key; sealed when the tenant has encryption enabled
+    response_mapping JSONB NOT NULL DEFAULT '{}',    -- Output field -> dotted path in the store's order
+    connected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, provider)
+);
@@ -0,0 +1,9 @@
+-- ============================================================================
+-- RCS CHANNELS
+-- ============================================================================
+
+-- Channel types are validated by the application; the original list also
+-- missed TEST_HTTP
+ALTER TABLE channels DROP CONSTRAINT IF EXISTS channels_type_check;
+ALTER TABLE channels ADD CONSTRAINT channels_type_check
+    CHECK (type IN ('WHATSAPP', 'INSTAGRAM', 'TELEGRAM', 'INFOBIP', 'EMAIL', 'SMS', 'WEBCHAT', 'VOICE', 'TEST_HTTP', 'RCS'));
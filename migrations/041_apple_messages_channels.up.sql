@@ -0,0 +1,7 @@
+-- ============================================================================
+-- APPLE MESSAGES FOR BUSINESS CHANNELS
+-- ============================================================================
+
+ALTER TABLE channels DROP CONSTRAINT IF EXISTS channels_type_check;
+ALTER TABLE channels ADD CONSTRAINT channels_type_check
+    CHECK (type IN ('WHATSAPP', 'INSTAGRAM', 'TELEGRAM', 'INFOBIP', 'EMAIL', 'SMS', 'WEBCHAT', 'VOICE', 'TEST_HTTP', 'RCS', 'APPLE_MESSAGES'));
@@ -0,0 +1,58 @@
+-- ============================================================================
+-- CONTACTS (people a tenant talks to, imported from CSV/XLSX files)
+-- ============================================================================
+
+CREATE TABLE contacts (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    external_id TEXT NOT NULL DEFAULT '',      -- ID in the tenant's own systems
+    name TEXT NOT NULL DEFAULT '',
+    phone VARCHAR(16) NOT NULL DEFAULT '',     -- E.164
+    email TEXT NOT NULL DEFAULT '',            -- Lowercase
+    attributes JSONB NOT NULL DEFAULT '{}',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Imports match rows by external ID, else phone, else email
+CREATE UNIQUE INDEX idx_contacts_external_id ON contacts(tenant_id, external_id) WHERE external_id <> '';
+CREATE INDEX idx_contacts_phone ON contacts(tenant_id, phone) WHERE phone <> '';
+CREATE INDEX idx_contacts_email ON contacts(tenant_id, email) WHERE email <> '';
+CREATE INDEX idx_contacts_tenant ON contacts(tenant_id, created_at DESC);
+CREATE INDEX idx_contacts_tags ON contacts USING GIN (tags);
+
+CREATE TRIGGER update_contacts_updated_at
+    BEFORE UPDATE ON contacts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================================================
+-- CONTACT IMPORTS (one background job per uploaded file)
+-- ============================================================================
+
+CREATE TABLE contact_imports (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    requested_by TEXT NOT NULL,                -- No FK: the requesting user may be deleted later
+    filename TEXT NOT NULL,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'xlsx')),
+    mapping JSONB NOT NULL DEFAULT '{}',       -- {external_id, name, phone, email, tags, attributes}
+    options JSONB NOT NULL DEFAULT '{}',       -- {default_country_code, tags}
+    cohort_tag TEXT NOT NULL,                  -- Added to every imported contact
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'RUNNING', 'COMPLETED', 'FAILED')),
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    created_count INTEGER NOT NULL DEFAULT 0,
+    updated_count INTEGER NOT NULL DEFAULT 0,
+    unchanged_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    row_errors JSONB NOT NULL DEFAULT '[]',    -- [{row, column, error}], capped
+    storage_key TEXT NOT NULL DEFAULT '',      -- Uploaded file in the attachment storage, deleted once finished
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_contact_imports_tenant ON contact_imports(tenant_id, created_at DESC);
+CREATE INDEX idx_contact_imports_active ON contact_imports(status) WHERE status IN ('PENDING', 'RUNNING');
@@ -0,0 +1,37 @@
+-- ============================================================================
+-- SEGMENTS (contacts matching a CEL predicate over their fields and stats)
+-- ============================================================================
+
+CREATE TABLE segments (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,                 -- Used by contact.in_segment("name")
+    description TEXT NOT NULL DEFAULT '',
+    predicate TEXT NOT NULL,                   -- CEL over contact, attributes, tags and stats
+    evaluated_at TIMESTAMP WITH TIME ZONE,     -- Last full rebuild; NULL = never
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, name)
+);
+
+-- The hourly refresh rebuilds the segments evaluated longest ago
+CREATE INDEX idx_segments_evaluated ON segments(evaluated_at NULLS FIRST);
+
+CREATE TRIGGER update_segments_updated_at
+    BEFORE UPDATE ON segments
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================================================
+-- SEGMENT MEMBERS (materialized, kept in sync as contacts change)
+-- ============================================================================
+
+CREATE TABLE segment_members (
+    segment_id TEXT NOT NULL REFERENCES segments(id) ON DELETE CASCADE,
+    contact_id TEXT NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    added_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (segment_id, contact_id)
+);
+
+CREATE INDEX idx_segment_members_list ON segment_members(segment_id, added_at DESC);
+CREATE INDEX idx_segment_members_contact ON segment_members(contact_id);
@@ -0,0 +1,100 @@
+-- ============================================================================
+-- MESSAGE PARTITIONS (messages split by calendar month, in UTC)
+-- ============================================================================
+
+-- Postgres cannot turn a table into a partitioned one in place: the messages
+-- are copied into a new partitioned table and the old one dropped. The
+-- partition key must be part of the primary key, which becomes (id, created_at).
+ALTER TABLE messages RENAME TO messages_unpartitioned;
+ALTER INDEX messages_pkey RENAME TO messages_unpartitioned_pkey;
+
+-- A foreign key to a partitioned table needs its whole primary key, and old
+-- months are dropped once archived: executions keep the message ID only
+ALTER TABLE workflow_executions DROP CONSTRAINT IF EXISTS workflow_executions_message_id_fkey;
+
+CREATE TABLE messages (
+    LIKE messages_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS,
+    PRIMARY KEY (id, created_at),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE
+) PARTITION BY RANGE (created_at);
+
+-- Catches rows outside every monthly partition, e.g. timestamps far in the future
+CREATE TABLE messages_default PARTITION OF messages DEFAULT;
+
+-- Creates messages_YYYY_MM for the month starting at month_start. Rows of that
+-- month already in the default partition are moved into it first, otherwise
+-- attaching it would fail. Returns NULL when the partition exists.
+CREATE OR REPLACE FUNCTION create_message_partition(month_start DATE)
+RETURNS TEXT AS $$
+DECLARE
+    partition_name TEXT := 'messages_' || to_char(month_start, 'YYYY_MM');
+    range_start TIMESTAMPTZ := date_trunc('month', month_start)::timestamp AT TIME ZONE 'UTC';
+    range_end TIMESTAMPTZ := (date_trunc('month', month_start) + INTERVAL '1 month')::timestamp AT TIME ZONE 'UTC';
+BEGIN
+    IF to_regclass(partition_name) IS NOT NULL THEN
+        RETURN NULL;
+    END IF;
+
+    EXECUTE format('CREATE TABLE %I (LIKE messages INCLUDING DEFAULTS INCLUDING CONSTRAINTS)', partition_name);
+    EXECUTE format(
+        'WITH moved AS (DELETE FROM messages_default WHERE created_at >= %L AND created_at < %L RETURNING *)
+         INSERT INTO %I SELECT * FROM moved',
+        range_start, range_end, partition_name);
+    EXECUTE format('ALTER TABLE messages ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)',
+        partition_name, range_start, range_end);
+
+    RETURN partition_name;
+END;
+$$ LANGUAGE plpgsql;
+
+-- One partition per month from the oldest message through two months ahead;
+-- the archival job keeps creating them from then on
+DO $$
+DECLARE
+    month_start DATE := date_trunc('month',
+        COALESCE((SELECT MIN(created_at) FROM messages_unpartitioned), NOW()) AT TIME ZONE 'UTC')::date;
+    last_month DATE := (date_trunc('month', NOW() AT TIME ZONE 'UTC') + INTERVAL '2 months')::date;
+BEGIN
+    WHILE month_start <= last_month LOOP
+        PERFORM create_message_partition(month_start);
+        month_start := (month_start + INTERVAL '1 month')::date;
+    END LOOP;
+END $$;
+
+INSERT INTO messages SELECT * FROM messages_unpartitioned;
+DROP TABLE messages_unpartitioned;
+
+-- Indexes on the parent are created on every partition, present and future
+CREATE INDEX idx_messages_tenant_id ON messages(tenant_id);
+CREATE INDEX idx_messages_channel_id ON messages(channel_id);
+CREATE INDEX idx_messages_sender_id ON messages(sender_id);
+CREATE INDEX idx_messages_status ON messages(status);
+CREATE INDEX idx_messages_created_at ON messages(created_at);
+CREATE INDEX idx_messages_channel_sender ON messages(channel_id, sender_id);
+CREATE INDEX idx_messages_content ON messages USING GIN (content);
+CREATE INDEX idx_messages_conversation ON messages(tenant_id, conversation_id, created_at);
+CREATE INDEX idx_messages_retention ON messages(tenant_id, created_at) WHERE redacted_at IS NULL;
+CREATE INDEX idx_messages_provider_message ON messages(tenant_id, channel_id, provider_message_id)
+    WHERE provider_message_id IS NOT NULL;
+
+CREATE TRIGGER update_messages_updated_at
+    BEFORE UPDATE ON messages
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================================================
+-- MESSAGE ARCHIVES (a tenant's messages of a dropped month, as a Parquet file)
+-- ============================================================================
+
+CREATE TABLE message_archives (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    month DATE NOT NULL,                       -- First day of the archived month (UTC)
+    storage_key TEXT NOT NULL,                 -- File in the attachment storage
+    message_count INTEGER NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, month)
+);
+
+CREATE INDEX idx_message_archives_month ON message_archives(month);
@@ -0,0 +1,34 @@
+-- ============================================================================
+-- MESSAGE SEARCH (full-text index over the text of messages)
+-- ============================================================================
+
+-- Words of a message's text and caption. The 'simple' configuration keeps
+-- every word as written, without language-specific stemming or stop words,
+-- since tenants talk to customers in many languages. Encrypted content has
+-- neither key and gets an empty vector: it is never searchable.
+CREATE OR REPLACE FUNCTION message_search_vector(content JSONB)
+RETURNS TSVECTOR AS $$
+    SELECT to_tsvector('simple', COALESCE(content->>'text', '') || ' ' || COALESCE(content->>'caption', ''))
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION update_message_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector := message_search_vector(NEW.content);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE messages ADD COLUMN search_vector TSVECTOR;
+
+-- Existing messages keep their updated_at
+ALTER TABLE messages DISABLE TRIGGER update_messages_updated_at;
+UPDATE messages SET search_vector = message_search_vector(content);
+ALTER TABLE messages ENABLE TRIGGER update_messages_updated_at;
+
+-- Redaction, anonymization and encryption rewrite content, and the vector with it
+CREATE TRIGGER update_messages_search_vector
+    BEFORE INSERT OR UPDATE OF content ON messages
+    FOR EACH ROW EXECUTE FUNCTION update_message_search_vector();
+
+CREATE INDEX idx_messages_search ON messages USING GIN (search_vector);
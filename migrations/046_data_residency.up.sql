@@ -0,0 +1,25 @@
+-- ============================================================================
+-- DATA RESIDENCY (messages, agent sessions and contacts in regional databases)
+-- ============================================================================
+
+-- This migration runs against the primary database and against every regional
+-- one. Regional databases hold the tenant's messages, conversation tags, agent
+-- messages, contacts and suppressions, but not the tenants or channels they
+-- belong to, so those tables lose their foreign keys everywhere. Deleting a
+-- tenant or a channel now removes their rows explicitly.
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_tenant_id_fkey;
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_channel_id_fkey;
+
+ALTER TABLE conversation_tags DROP CONSTRAINT IF EXISTS conversation_tags_tenant_id_fkey;
+ALTER TABLE conversation_tags DROP CONSTRAINT IF EXISTS conversation_tags_channel_id_fkey;
+
+ALTER TABLE agent_messages DROP CONSTRAINT IF EXISTS agent_messages_tenant_id_fkey;
+
+ALTER TABLE contacts DROP CONSTRAINT IF EXISTS contacts_tenant_id_fkey;
+
+ALTER TABLE contact_suppressions DROP CONSTRAINT IF EXISTS contact_suppressions_tenant_id_fkey;
+ALTER TABLE contact_suppressions DROP CONSTRAINT IF EXISTS contact_suppressions_channel_id_fkey;
+
+-- Segment memberships stay in the primary database and keep contact IDs only.
+-- Deleting a contact removes its memberships; the next rebuild drops any left.
+ALTER TABLE segment_members DROP CONSTRAINT IF EXISTS segment_members_contact_id_fkey;
@@ -0,0 +1,8 @@
+-- ============================================================================
+-- MEMBERSHIP STATUS (suspending a user in one tenant only)
+-- ============================================================================
+
+-- A tenant that a user only belongs to through a membership suspends the
+-- membership, never the user's own row, which their home tenant owns.
+ALTER TABLE user_tenant_memberships
+    ADD COLUMN status VARCHAR(50) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'SUSPENDED'));
@@ -0,0 +1,39 @@
+package msgtemplate
+
+import "github.com/Abraxas-365/relay/pkg/kernel"
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// SaveTemplateRequest creates or replaces a template with all its variants
+type SaveTemplateRequest struct {
+	Key           string    `json:"key" validate:"required"`
+	Description   string    `json:"description,omitempty"`
+	DefaultLocale string    `json:"default_locale,omitempty"` // Defaults to the first variant's
+	Variants      []Variant `json:"variants" validate:"required"`
+}
+
+// ListTemplatesRequest filters a tenant's templates. Search matches the key
+// or description; Locale keeps templates with a variant in that locale.
+type ListTemplatesRequest struct {
+	TenantID kernel.TenantID `json:"tenant_id"`
+	Search   string          `json:"search,omitempty"`
+	Locale   string          `json:"locale,omitempty"`
+}
+
+// RenderTemplateRequest previews a template in a locale with the given values
+type RenderTemplateRequest struct {
+	Locale    string         `json:"locale,omitempty"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+// RenderTemplateResponse is a rendered template and the variant it used
+type RenderTemplateResponse struct {
+	Text   string `json:"text"`
+	Locale string `json:"locale"`
+}
@@ -0,0 +1,44 @@
+package msgtemplate
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("MESSAGE_TEMPLATE")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeTemplateNotFound = ErrRegistry.Register("TEMPLATE_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Message template not found")
+	CodeInvalidTemplate  = ErrRegistry.Register("INVALID_TEMPLATE", errx.TypeValidation, http.StatusBadRequest, "Invalid message template")
+	CodeTemplateKeyTaken = ErrRegistry.Register("TEMPLATE_KEY_TAKEN", errx.TypeConflict, http.StatusConflict, "A message template with this key already exists")
+	CodeMissingVariables = ErrRegistry.Register("MISSING_VARIABLES", errx.TypeValidation, http.StatusBadRequest, "Message template variables are missing")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrTemplateNotFound() *errx.Error {
+	return ErrRegistry.New(CodeTemplateNotFound)
+}
+
+func ErrInvalidTemplate() *errx.Error {
+	return ErrRegistry.New(CodeInvalidTemplate)
+}
+
+func ErrTemplateKeyTaken() *errx.Error {
+	return ErrRegistry.New(CodeTemplateKeyTaken)
+}
+
+func ErrMissingVariables() *errx.Error {
+	return ErrRegistry.New(CodeMissingVariables)
+}
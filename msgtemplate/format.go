@@ -0,0 +1,517 @@
+package msgtemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ============================================================================
+// Message Format
+// ============================================================================
+//
+// Variant content uses a subset of ICU MessageFormat:
+//
+//	Hello {name}
+//	You have {count, plural, =0 {no orders} one {# order} other {# orders}}
+//	{gender, select, female {She} male {He} other {They}} replied
+//	{total, number}
+//
+// Plural arguments accept "offset:n". Inside a plural branch # is the
+// number minus the offset. A quote escapes syntax: '{' is a literal brace
+// and '' a literal quote.
+
+// part is one piece of a parsed message: literal text, # or an argument
+type part struct {
+	text  string
+	pound bool
+	arg   *argument
+}
+
+type argumentKind string
+
+const (
+	argSimple argumentKind = ""
+	argNumber argumentKind = "number"
+	argPlural argumentKind = "plural"
+	argSelect argumentKind = "select"
+)
+
+type argument struct {
+	name     string
+	kind     argumentKind
+	offset   float64
+	branches map[string][]part
+}
+
+// Message is a parsed variant, ready to be formatted many times
+type Message struct {
+	parts []part
+}
+
+// Parse parses a message in the supported ICU subset
+func Parse(content string) (*Message, error) {
+	p := &parser{src: []rune(content)}
+	parts, err := p.parseParts(false, false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected }")
+	}
+	return &Message{parts: parts}, nil
+}
+
+// Arguments lists the argument names the message uses, sorted
+func (m *Message) Arguments() []string {
+	seen := make(map[string]bool)
+	collectArguments(m.parts, seen)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Format renders the message for a locale. Every argument the chosen
+// branches use must have a value.
+func (m *Message) Format(locale string, values map[string]any) (string, error) {
+	var b strings.Builder
+	var missing []string
+	formatParts(&b, m.parts, locale, values, 0, &missing)
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", ErrMissingVariables().WithDetail("variables", missing)
+	}
+	return b.String(), nil
+}
+
+// ============================================================================
+// Formatting
+// ============================================================================
+
+func formatParts(b *strings.Builder, parts []part, locale string, values map[string]any, pound float64, missing *[]string) {
+	for _, p := range parts {
+		switch {
+		case p.pound:
+			b.WriteString(formatNumber(pound))
+		case p.arg != nil:
+			formatArgument(b, p.arg, locale, values, pound, missing)
+		default:
+			b.WriteString(p.text)
+		}
+	}
+}
+
+func formatArgument(b *strings.Builder, arg *argument, locale string, values map[string]any, pound float64, missing *[]string) {
+	value, ok := values[arg.name]
+	if !ok || value == nil {
+		*missing = appendUnique(*missing, arg.name)
+		return
+	}
+
+	switch arg.kind {
+	case argSimple:
+		b.WriteString(fmt.Sprint(value))
+
+	case argNumber:
+		if n, ok := toNumber(value); ok {
+			b.WriteString(formatNumber(n))
+		} else {
+			b.WriteString(fmt.Sprint(value))
+		}
+
+	case argPlural:
+		n, ok := toNumber(value)
+		if !ok {
+			*missing = appendUnique(*missing, arg.name)
+			return
+		}
+		branch, ok := arg.branches["="+formatNumber(n)]
+		if !ok {
+			branch, ok = arg.branches[PluralCategory(locale, n-arg.offset)]
+		}
+		if !ok {
+			branch = arg.branches["other"]
+		}
+		formatParts(b, branch, locale, values, n-arg.offset, missing)
+
+	case argSelect:
+		branch, ok := arg.branches[fmt.Sprint(value)]
+		if !ok {
+			branch = arg.branches["other"]
+		}
+		formatParts(b, branch, locale, values, pound, missing)
+	}
+}
+
+func collectArguments(parts []part, seen map[string]bool) {
+	for _, p := range parts {
+		if p.arg == nil {
+			continue
+		}
+		seen[p.arg.name] = true
+		for _, branch := range p.arg.branches {
+			collectArguments(branch, seen)
+		}
+	}
+}
+
+// toNumber reads numeric variables, which arrive as JSON numbers or as
+// rendered template strings
+func toNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+// ============================================================================
+// Plural Rules
+// ============================================================================
+
+// PluralCategory returns the CLDR cardinal category (zero, one, two, few,
+// many, other) of n for the locale's language. Languages without a rule
+// here use the English one.
+func PluralCategory(locale string, n float64) string {
+	integer := n == math.Trunc(n)
+	i := int64(math.Abs(n))
+	mod10, mod100 := i%10, i%100
+
+	switch baseLanguage(locale) {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms", "lo", "my":
+		return "other"
+
+	case "fr", "pt", "hi", "bn":
+		if i == 0 || i == 1 {
+			return "one"
+		}
+		return "other"
+
+	case "ru", "uk", "be":
+		switch {
+		case !integer:
+			return "other"
+		case mod10 == 1 && mod100 != 11:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+
+	case "pl":
+		switch {
+		case !integer:
+			return "other"
+		case i == 1:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+
+	case "cs", "sk":
+		switch {
+		case !integer:
+			return "many"
+		case i == 1:
+			return "one"
+		case i >= 2 && i <= 4:
+			return "few"
+		default:
+			return "other"
+		}
+
+	case "ar":
+		switch {
+		case !integer:
+			return "other"
+		case i == 0:
+			return "zero"
+		case i == 1:
+			return "one"
+		case i == 2:
+			return "two"
+		case mod100 >= 3 && mod100 <= 10:
+			return "few"
+		case mod100 >= 11:
+			return "many"
+		default:
+			return "other"
+		}
+	}
+
+	if integer && i == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// ============================================================================
+// Parser
+// ============================================================================
+
+type parser struct {
+	src []rune
+	pos int
+}
+
+// parseParts reads until the end of input or, inside a branch, until its
+// closing brace. inPlural enables #.
+func (p *parser) parseParts(inBranch, inPlural bool) ([]part, error) {
+	var parts []part
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			parts = append(parts, part{text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\'':
+			p.readQuoted(&text, inPlural)
+
+		case c == '{':
+			flush()
+			arg, err := p.parseArgument(inPlural)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, part{arg: arg})
+
+		case c == '}':
+			if !inBranch {
+				return nil, p.errorf("unexpected }")
+			}
+			flush()
+			return parts, nil
+
+		case c == '#' && inPlural:
+			flush()
+			parts = append(parts, part{pound: true})
+			p.pos++
+
+		default:
+			text.WriteRune(c)
+			p.pos++
+		}
+	}
+
+	if inBranch {
+		return nil, p.errorf("unclosed branch")
+	}
+	flush()
+	return parts, nil
+}
+
+// readQuoted handles apostrophes: a doubled one is a literal quote, and
+// one before a syntax character starts literal text up to the next one
+func (p *parser) readQuoted(text *strings.Builder, inPlural bool) {
+	p.pos++
+	if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+		text.WriteRune('\'')
+		p.pos++
+		return
+	}
+	if p.pos >= len(p.src) || !isSyntax(p.src[p.pos], inPlural) {
+		text.WriteRune('\'')
+		return
+	}
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		if c != '\'' {
+			text.WriteRune(c)
+			continue
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+			text.WriteRune('\'')
+			p.pos++
+			continue
+		}
+		return
+	}
+}
+
+func isSyntax(c rune, inPlural bool) bool {
+	return c == '{' || c == '}' || (inPlural && c == '#')
+}
+
+// parseArgument reads {name}, {name, number} or {name, plural|select, ...}.
+// A select nested in a plural keeps #.
+func (p *parser) parseArgument(inPlural bool) (*argument, error) {
+	p.pos++ // {
+	p.skipSpace()
+
+	name := p.readIdentifier()
+	if name == "" {
+		return nil, p.errorf("argument name expected")
+	}
+	arg := &argument{name: name}
+
+	p.skipSpace()
+	if p.consume('}') {
+		return arg, nil
+	}
+	if !p.consume(',') {
+		return nil, p.errorf("expected , or } after %s", name)
+	}
+
+	p.skipSpace()
+	kind := argumentKind(p.readIdentifier())
+	p.skipSpace()
+
+	switch kind {
+	case argNumber:
+		arg.kind = argNumber
+		if !p.consume('}') {
+			return nil, p.errorf("number arguments take no style")
+		}
+		return arg, nil
+
+	case argPlural, argSelect:
+		arg.kind = kind
+		if !p.consume(',') {
+			return nil, p.errorf("expected , after %s", kind)
+		}
+		if err := p.parseBranches(arg, inPlural); err != nil {
+			return nil, err
+		}
+		return arg, nil
+	}
+
+	return nil, p.errorf("unsupported argument type %q", string(kind))
+}
+
+func (p *parser) parseBranches(arg *argument, inPlural bool) error {
+	arg.branches = make(map[string][]part)
+
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return p.errorf("unclosed %s argument %s", arg.kind, arg.name)
+		}
+		if p.consume('}') {
+			break
+		}
+
+		selector := p.readSelector()
+		if selector == "" {
+			return p.errorf("branch selector expected in %s", arg.name)
+		}
+
+		if arg.kind == argPlural && strings.HasPrefix(selector, "offset:") {
+			offset, err := strconv.ParseFloat(strings.TrimPrefix(selector, "offset:"), 64)
+			if err != nil || len(arg.branches) > 0 {
+				return p.errorf("invalid offset in %s", arg.name)
+			}
+			arg.offset = offset
+			continue
+		}
+		if strings.HasPrefix(selector, "=") {
+			if _, err := strconv.ParseFloat(selector[1:], 64); err != nil || arg.kind != argPlural {
+				return p.errorf("invalid selector %s in %s", selector, arg.name)
+			}
+		}
+		if _, dup := arg.branches[selector]; dup {
+			return p.errorf("duplicate selector %s in %s", selector, arg.name)
+		}
+
+		p.skipSpace()
+		if !p.consume('{') {
+			return p.errorf("expected { after selector %s", selector)
+		}
+		branch, err := p.parseParts(true, inPlural || arg.kind == argPlural)
+		if err != nil {
+			return err
+		}
+		p.pos++ // }
+		arg.branches[selector] = branch
+	}
+
+	if _, ok := arg.branches["other"]; !ok {
+		return p.errorf("%s argument %s requires an other branch", arg.kind, arg.name)
+	}
+	return nil
+}
+
+func (p *parser) readIdentifier() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' {
+			break
+		}
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *parser) readSelector() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if unicode.IsSpace(c) || c == '{' || c == '}' {
+			break
+		}
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) consume(c rune) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("at position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,138 @@
+package msgtemplateapi
+
+import (
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/msgtemplate"
+	"github.com/Abraxas-365/relay/msgtemplate/msgtemplatesrv"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TemplateHandler exposes the tenant's localized message templates
+type TemplateHandler struct {
+	service *msgtemplatesrv.TemplateService
+}
+
+// NewTemplateHandler creates a new message template handler
+func NewTemplateHandler(service *msgtemplatesrv.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		service: service,
+	}
+}
+
+// List returns the tenant's templates, optionally matching ?q= in the key or
+// description or having a ?locale= variant
+// GET /api/message-templates
+func (h *TemplateHandler) List(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	templates, err := h.service.List(c.Context(), msgtemplate.ListTemplatesRequest{
+		TenantID: authContext.TenantID,
+		Search:   c.Query("q"),
+		Locale:   c.Query("locale"),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"templates": templates})
+}
+
+// Create defines a new template
+// POST /api/message-templates
+func (h *TemplateHandler) Create(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req msgtemplate.SaveTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return msgtemplate.ErrInvalidTemplate().WithDetail("reason", err.Error())
+	}
+
+	t, err := h.service.Create(c.Context(), authContext.TenantID, authContext.UserID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(t)
+}
+
+// Get returns a template
+// GET /api/message-templates/:id
+func (h *TemplateHandler) Get(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	t, err := h.service.Get(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(t)
+}
+
+// Update replaces a template and all its variants
+// PUT /api/message-templates/:id
+func (h *TemplateHandler) Update(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req msgtemplate.SaveTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return msgtemplate.ErrInvalidTemplate().WithDetail("reason", err.Error())
+	}
+
+	t, err := h.service.Update(c.Context(), c.Params("id"), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(t)
+}
+
+// Delete removes a template
+// DELETE /api/message-templates/:id
+func (h *TemplateHandler) Delete(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := h.service.Delete(c.Context(), c.Params("id"), authContext.TenantID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Render previews a template in a locale with the given variables
+// POST /api/message-templates/:id/render
+func (h *TemplateHandler) Render(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req msgtemplate.RenderTemplateRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return msgtemplate.ErrInvalidTemplate().WithDetail("reason", err.Error())
+		}
+	}
+
+	rendered, err := h.service.Render(c.Context(), c.Params("id"), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(rendered)
+}
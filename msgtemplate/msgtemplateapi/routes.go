@@ -0,0 +1,33 @@
+package msgtemplateapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TemplateRoutes handles message template route setup
+type TemplateRoutes struct {
+	handler        *TemplateHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewTemplateRoutes creates a new message template routes instance
+func NewTemplateRoutes(handler *TemplateHandler, authMiddleware *auth.AuthMiddleware) *TemplateRoutes {
+	return &TemplateRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers message template routes on an authenticated
+// router. Managing templates requires an admin; previewing them does not.
+func (r *TemplateRoutes) RegisterRoutes(router fiber.Router) {
+	templates := router.Group("/message-templates")
+
+	templates.Get("/", r.handler.List)
+	templates.Post("/", r.authMiddleware.RequireAdmin(), r.handler.Create)
+	templates.Get("/:id", r.handler.Get)
+	templates.Put("/:id", r.authMiddleware.RequireAdmin(), r.handler.Update)
+	templates.Delete("/:id", r.authMiddleware.RequireAdmin(), r.handler.Delete)
+	templates.Post("/:id/render", r.handler.Render)
+}
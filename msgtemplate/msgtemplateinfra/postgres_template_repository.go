@@ -0,0 +1,186 @@
+package msgtemplateinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/msgtemplate"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PostgresTemplateRepository struct {
+	db *sqlx.DB
+}
+
+var _ msgtemplate.TemplateRepository = (*PostgresTemplateRepository)(nil)
+
+func NewPostgresTemplateRepository(db *sqlx.DB) *PostgresTemplateRepository {
+	return &PostgresTemplateRepository{db: db}
+}
+
+// dbTemplate is an intermediate struct for database operations
+type dbTemplate struct {
+	ID            string          `db:"id"`
+	TenantID      string          `db:"tenant_id"`
+	Key           string          `db:"key"`
+	Description   string          `db:"description"`
+	DefaultLocale string          `db:"default_locale"`
+	Variants      json.RawMessage `db:"variants"`
+	CreatedBy     sql.NullString  `db:"created_by"`
+	CreatedAt     time.Time       `db:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at"`
+}
+
+const templateColumns = `
+	id, tenant_id, key, description, default_locale, variants,
+	created_by, created_at, updated_at`
+
+func (r *PostgresTemplateRepository) Save(ctx context.Context, t msgtemplate.Template) error {
+	variants := t.Variants
+	if variants == nil {
+		variants = []msgtemplate.Variant{}
+	}
+	variantsJSON, err := json.Marshal(variants)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal template variants", errx.TypeInternal)
+	}
+
+	var createdBy sql.NullString
+	if t.CreatedBy != "" {
+		createdBy = sql.NullString{String: t.CreatedBy.String(), Valid: true}
+	}
+
+	query := `
+		INSERT INTO message_templates (
+			id, tenant_id, key, description, default_locale, variants, created_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			key = EXCLUDED.key,
+			description = EXCLUDED.description,
+			default_locale = EXCLUDED.default_locale,
+			variants = EXCLUDED.variants`
+
+	_, err = r.db.ExecContext(ctx, query,
+		t.ID, t.TenantID.String(), t.Key, t.Description, t.DefaultLocale, variantsJSON,
+		createdBy, t.CreatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return msgtemplate.ErrTemplateKeyTaken().WithDetail("key", t.Key)
+		}
+		return errx.Wrap(err, "failed to save message template", errx.TypeInternal).
+			WithDetail("template_id", t.ID)
+	}
+
+	return nil
+}
+
+func (r *PostgresTemplateRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*msgtemplate.Template, error) {
+	query := `SELECT ` + templateColumns + ` FROM message_templates WHERE id = $1 AND tenant_id = $2`
+	return r.findOne(ctx, query, "template_id", id, tenantID)
+}
+
+func (r *PostgresTemplateRepository) FindByKey(ctx context.Context, key string, tenantID kernel.TenantID) (*msgtemplate.Template, error) {
+	query := `SELECT ` + templateColumns + ` FROM message_templates WHERE key = $1 AND tenant_id = $2`
+	return r.findOne(ctx, query, "template_key", strings.ToLower(strings.TrimSpace(key)), tenantID)
+}
+
+func (r *PostgresTemplateRepository) List(ctx context.Context, req msgtemplate.ListTemplatesRequest) ([]*msgtemplate.Template, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.Search != "" {
+		conditions = append(conditions, fmt.Sprintf("(key ILIKE $%d OR description ILIKE $%d)", argPos, argPos))
+		args = append(args, "%"+req.Search+"%")
+		argPos++
+	}
+	if req.Locale != "" {
+		conditions = append(conditions, fmt.Sprintf("variants @> jsonb_build_array(jsonb_build_object('locale', $%d::text))", argPos))
+		args = append(args, req.Locale)
+		argPos++
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM message_templates WHERE %s ORDER BY key ASC`,
+		templateColumns, strings.Join(conditions, " AND "))
+
+	var rows []dbTemplate
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, errx.Wrap(err, "failed to list message templates", errx.TypeInternal)
+	}
+
+	templates := make([]*msgtemplate.Template, 0, len(rows))
+	for i := range rows {
+		t, err := toDomainTemplate(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+
+	return templates, nil
+}
+
+func (r *PostgresTemplateRepository) Delete(ctx context.Context, id string, tenantID kernel.TenantID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM message_templates WHERE id = $1 AND tenant_id = $2`, id, tenantID.String())
+	if err != nil {
+		return errx.Wrap(err, "failed to delete message template", errx.TypeInternal).
+			WithDetail("template_id", id)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+	if rows == 0 {
+		return msgtemplate.ErrTemplateNotFound().WithDetail("template_id", id)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func (r *PostgresTemplateRepository) findOne(ctx context.Context, query, detail, value string, tenantID kernel.TenantID) (*msgtemplate.Template, error) {
+	var row dbTemplate
+	if err := r.db.GetContext(ctx, &row, query, value, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, msgtemplate.ErrTemplateNotFound().WithDetail(detail, value)
+		}
+		return nil, errx.Wrap(err, "failed to find message template", errx.TypeInternal).
+			WithDetail(detail, value)
+	}
+
+	return toDomainTemplate(&row)
+}
+
+func toDomainTemplate(row *dbTemplate) (*msgtemplate.Template, error) {
+	t := &msgtemplate.Template{
+		ID:            row.ID,
+		TenantID:      kernel.TenantID(row.TenantID),
+		Key:           row.Key,
+		Description:   row.Description,
+		DefaultLocale: row.DefaultLocale,
+		CreatedBy:     kernel.UserID(row.CreatedBy.String),
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}
+
+	if len(row.Variants) > 0 {
+		if err := json.Unmarshal(row.Variants, &t.Variants); err != nil {
+			return nil, errx.Wrap(err, "failed to unmarshal template variants", errx.TypeInternal).
+				WithDetail("template_id", row.ID)
+		}
+	}
+
+	return t, nil
+}
@@ -0,0 +1,116 @@
+package msgtemplatesrv
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/msgtemplate"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// TemplateService manages localized message templates and renders them for
+// workflows
+type TemplateService struct {
+	repo msgtemplate.TemplateRepository
+}
+
+var _ engine.MessageTemplateRenderer = (*TemplateService)(nil)
+
+func NewTemplateService(repo msgtemplate.TemplateRepository) *TemplateService {
+	return &TemplateService{repo: repo}
+}
+
+// ============================================================================
+// Templates
+// ============================================================================
+
+// Create stores a new template
+func (s *TemplateService) Create(ctx context.Context, tenantID kernel.TenantID, userID kernel.UserID, req msgtemplate.SaveTemplateRequest) (*msgtemplate.Template, error) {
+	now := time.Now()
+	t := &msgtemplate.Template{
+		ID:        uuid.NewString(),
+		TenantID:  tenantID,
+		CreatedBy: userID,
+		CreatedAt: now,
+	}
+	return s.save(ctx, t, req, now)
+}
+
+// Update replaces a template's key, description and variants. Renaming the
+// key breaks the workflows referencing the old one, which validation reports.
+func (s *TemplateService) Update(ctx context.Context, id string, tenantID kernel.TenantID, req msgtemplate.SaveTemplateRequest) (*msgtemplate.Template, error) {
+	t, err := s.repo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, t, req, time.Now())
+}
+
+func (s *TemplateService) save(ctx context.Context, t *msgtemplate.Template, req msgtemplate.SaveTemplateRequest, now time.Time) (*msgtemplate.Template, error) {
+	t.Key = req.Key
+	t.Description = strings.TrimSpace(req.Description)
+	t.DefaultLocale = req.DefaultLocale
+	t.Variants = req.Variants
+	t.UpdatedAt = now
+
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(ctx, *t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (s *TemplateService) Get(ctx context.Context, id string, tenantID kernel.TenantID) (*msgtemplate.Template, error) {
+	return s.repo.FindByID(ctx, id, tenantID)
+}
+
+func (s *TemplateService) List(ctx context.Context, req msgtemplate.ListTemplatesRequest) ([]*msgtemplate.Template, error) {
+	if req.Locale != "" {
+		locale, ok := msgtemplate.NormalizeLocale(req.Locale)
+		if !ok {
+			return nil, msgtemplate.ErrInvalidTemplate().WithDetail("locale", req.Locale)
+		}
+		req.Locale = locale
+	}
+	return s.repo.List(ctx, req)
+}
+
+// Delete removes a template; workflow nodes still referencing it fail to send
+func (s *TemplateService) Delete(ctx context.Context, id string, tenantID kernel.TenantID) error {
+	return s.repo.Delete(ctx, id, tenantID)
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+// Render previews a template in a locale
+func (s *TemplateService) Render(ctx context.Context, id string, tenantID kernel.TenantID, req msgtemplate.RenderTemplateRequest) (*msgtemplate.RenderTemplateResponse, error) {
+	t, err := s.repo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	text, locale, err := t.Render(req.Locale, req.Variables)
+	if err != nil {
+		return nil, err
+	}
+	return &msgtemplate.RenderTemplateResponse{Text: text, Locale: locale}, nil
+}
+
+// RenderMessageTemplate implements engine.MessageTemplateRenderer: the
+// variant closest to the locale with its arguments filled in
+func (s *TemplateService) RenderMessageTemplate(ctx context.Context, tenantID kernel.TenantID, key, locale string, variables map[string]any) (string, string, error) {
+	t, err := s.repo.FindByKey(ctx, key, tenantID)
+	if err != nil {
+		return "", "", err
+	}
+	return t.Render(locale, variables)
+}
@@ -0,0 +1,21 @@
+package msgtemplate
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// TemplateRepository persists a tenant's message templates
+type TemplateRepository interface {
+	// Save creates or replaces a template; keys are unique per tenant
+	Save(ctx context.Context, t Template) error
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*Template, error)
+	FindByKey(ctx context.Context, key string, tenantID kernel.TenantID) (*Template, error)
+	List(ctx context.Context, req ListTemplatesRequest) ([]*Template, error)
+	Delete(ctx context.Context, id string, tenantID kernel.TenantID) error
+}
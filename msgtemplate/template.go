@@ -0,0 +1,204 @@
+package msgtemplate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Message Templates
+// ============================================================================
+
+// MaxContentLength bounds each variant's text; channels cap messages lower
+const MaxContentLength = 4096
+
+var (
+	// keyPattern matches template keys such as "order.shipped"
+	keyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,99}$`)
+
+	// localePattern matches BCP 47 tags such as "es", "pt-BR" or "zh-Hant-TW"
+	localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+)
+
+// Template is a message a tenant defines once, keyed, with one variant per
+// locale. SEND_MESSAGE nodes reference it by key and the contact's locale
+// picks the variant, so one workflow serves every language.
+type Template struct {
+	ID            string          `json:"id"`
+	TenantID      kernel.TenantID `json:"tenant_id"`
+	Key           string          `json:"key"` // Unique per tenant
+	Description   string          `json:"description,omitempty"`
+	DefaultLocale string          `json:"default_locale"` // Used when no variant matches
+	Variants      []Variant       `json:"variants"`
+	CreatedBy     kernel.UserID   `json:"created_by,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// Variant is the template's text in one locale, in ICU message format
+type Variant struct {
+	Locale  string `json:"locale"`
+	Content string `json:"content"`
+}
+
+// Validate normalizes the key and locales and checks every variant parses
+func (t *Template) Validate() error {
+	t.Key = strings.ToLower(strings.TrimSpace(t.Key))
+	if !keyPattern.MatchString(t.Key) {
+		return ErrInvalidTemplate().
+			WithDetail("key", t.Key).
+			WithDetail("reason", "keys are lowercase letters, digits, '.', '_' and '-'")
+	}
+	if len(t.Variants) == 0 {
+		return ErrInvalidTemplate().WithDetail("reason", "at least one variant is required")
+	}
+
+	seen := make(map[string]bool, len(t.Variants))
+	for i := range t.Variants {
+		variant := &t.Variants[i]
+
+		locale, ok := NormalizeLocale(variant.Locale)
+		if !ok {
+			return ErrInvalidTemplate().
+				WithDetail("locale", variant.Locale).
+				WithDetail("reason", "locales are BCP 47 tags such as es or pt-BR")
+		}
+		if seen[locale] {
+			return ErrInvalidTemplate().
+				WithDetail("locale", locale).
+				WithDetail("reason", "each locale can only have one variant")
+		}
+		seen[locale] = true
+		variant.Locale = locale
+
+		if strings.TrimSpace(variant.Content) == "" {
+			return ErrInvalidTemplate().
+				WithDetail("locale", locale).
+				WithDetail("reason", "content is required")
+		}
+		if len(variant.Content) > MaxContentLength {
+			return ErrInvalidTemplate().
+				WithDetail("locale", locale).
+				WithDetail("reason", fmt.Sprintf("content is limited to %d bytes", MaxContentLength))
+		}
+		if _, err := Parse(variant.Content); err != nil {
+			return ErrInvalidTemplate().
+				WithDetail("locale", locale).
+				WithDetail("reason", err.Error())
+		}
+	}
+
+	if t.DefaultLocale == "" {
+		t.DefaultLocale = t.Variants[0].Locale
+	}
+	defaultLocale, ok := NormalizeLocale(t.DefaultLocale)
+	if !ok || !seen[defaultLocale] {
+		return ErrInvalidTemplate().
+			WithDetail("default_locale", t.DefaultLocale).
+			WithDetail("reason", "default_locale must have a variant")
+	}
+	t.DefaultLocale = defaultLocale
+
+	return nil
+}
+
+// Resolve picks the variant for a locale: the exact tag, then its base
+// language ("es-MX" uses "es"), then another region of that language, then
+// the default locale
+func (t *Template) Resolve(locale string) Variant {
+	if normalized, ok := NormalizeLocale(locale); ok {
+		base := baseLanguage(normalized)
+
+		var sameLanguage *Variant
+		for i := range t.Variants {
+			variant := &t.Variants[i]
+			if variant.Locale == normalized {
+				return *variant
+			}
+			if variant.Locale == base {
+				sameLanguage = variant
+			} else if sameLanguage == nil && baseLanguage(variant.Locale) == base {
+				sameLanguage = variant
+			}
+		}
+		if sameLanguage != nil {
+			return *sameLanguage
+		}
+	}
+
+	for _, variant := range t.Variants {
+		if variant.Locale == t.DefaultLocale {
+			return variant
+		}
+	}
+	return t.Variants[0]
+}
+
+// Render formats the variant of the locale with the given values. It
+// returns the text and the locale of the variant used.
+func (t *Template) Render(locale string, values map[string]any) (string, string, error) {
+	variant := t.Resolve(locale)
+
+	message, err := Parse(variant.Content)
+	if err != nil {
+		return "", "", ErrInvalidTemplate().
+			WithDetail("template_key", t.Key).
+			WithDetail("locale", variant.Locale).
+			WithDetail("reason", err.Error())
+	}
+
+	text, err := message.Format(variant.Locale, values)
+	if err != nil {
+		return "", "", err
+	}
+	return text, variant.Locale, nil
+}
+
+// Locales lists the locales of the variants, in order
+func (t *Template) Locales() []string {
+	locales := make([]string, len(t.Variants))
+	for i, variant := range t.Variants {
+		locales[i] = variant.Locale
+	}
+	return locales
+}
+
+// ============================================================================
+// Locales
+// ============================================================================
+
+// NormalizeLocale canonicalizes a locale tag: "pt_br" becomes "pt-BR" and
+// "zh-hant-tw" becomes "zh-Hant-TW"
+func NormalizeLocale(locale string) (string, bool) {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	if !localePattern.MatchString(locale) {
+		return "", false
+	}
+
+	subtags := strings.Split(locale, "-")
+	subtags[0] = strings.ToLower(subtags[0])
+	for i := 1; i < len(subtags); i++ {
+		switch len(subtags[i]) {
+		case 2:
+			subtags[i] = strings.ToUpper(subtags[i]) // Region
+		case 4:
+			subtags[i] = strings.ToUpper(subtags[i][:1]) + strings.ToLower(subtags[i][1:]) // Script
+		default:
+			subtags[i] = strings.ToLower(subtags[i])
+		}
+	}
+	return strings.Join(subtags, "-"), true
+}
+
+// baseLanguage returns the language subtag of a locale
+func baseLanguage(locale string) string {
+	locale = strings.ReplaceAll(locale, "_", "-")
+	if i := strings.IndexByte(locale, '-'); i >= 0 {
+		locale = locale[:i]
+	}
+	return strings.ToLower(locale)
+}
@@ -0,0 +1,85 @@
+package aesgcm
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// AES-GCM
+// ============================================================================
+//
+// Formato común de los valores sellados: nonce aleatorio prefijado al
+// ciphertext. Los cifradores del repo solo eligen la clave y los datos
+// asociados; el manejo del nonce y los errores vive aquí.
+
+// New crea un AEAD AES-GCM con una clave de 16, 24 o 32 bytes
+func New(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// NewFromSecret crea un AEAD AES-256-GCM derivando la clave de 32 bytes del
+// secreto configurado con SHA-256
+func NewFromSecret(secret string) cipher.AEAD {
+	derived := sha256.Sum256([]byte(secret))
+
+	aead, err := New(derived[:])
+	if err != nil {
+		// Con una clave de 32 bytes aes.NewCipher y cipher.NewGCM no fallan
+		panic(err)
+	}
+	return aead
+}
+
+// Seal cifra plaintext con un nonce aleatorio y lo devuelve prefijado
+func Seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errx.Wrap(err, "failed to generate nonce", errx.TypeInternal)
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// Open descifra un valor generado por Seal. Falla si la clave o los datos
+// asociados no coinciden, o si el valor fue alterado
+func Open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errx.New("ciphertext too short", errx.TypeInternal)
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], additionalData)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to decrypt ciphertext", errx.TypeInternal)
+	}
+
+	return plaintext, nil
+}
+
+// SealBase64 es Seal con el resultado codificado en base64
+func SealBase64(aead cipher.AEAD, plaintext, additionalData []byte) (string, error) {
+	sealed, err := Seal(aead, plaintext, additionalData)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenBase64 decodifica y descifra un valor generado por SealBase64
+func OpenBase64(aead cipher.AEAD, encoded string, additionalData []byte) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to decode ciphertext", errx.TypeInternal)
+	}
+	return Open(aead, sealed, additionalData)
+}
@@ -8,13 +8,15 @@ import (
 	"github.com/Abraxas-365/craftable/errx"
 	"github.com/Abraxas-365/craftable/logx"
 	"github.com/Abraxas-365/relay/pkg/agent"
+	"github.com/Abraxas-365/relay/pkg/database"
 	"github.com/Abraxas-365/relay/pkg/kernel"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
 type PostgresAgentChatRepository struct {
-	db *sqlx.DB
+	db     *sqlx.DB
+	router *database.Router // nil = every tenant's sessions are in db
 }
 
 var _ agent.AgentChatRepository = (*PostgresAgentChatRepository)(nil)
@@ -23,6 +25,11 @@ func NewPostgresAgentChatRepository(db *sqlx.DB) *PostgresAgentChatRepository {
 	return &PostgresAgentChatRepository{db: db}
 }
 
+// SetRouter keeps each tenant's session history in the database of its data region
+func (r *PostgresAgentChatRepository) SetRouter(router *database.Router) {
+	r.router = router
+}
+
 // dbAgentMessage is an intermediate struct for database operations
 type dbAgentMessage struct {
 	ID               string          `db:"id"`
@@ -146,19 +153,24 @@ func toDomainAgentMessage(db *dbAgentMessage) (*agent.AgentMessage, error) {
 }
 
 // GetAllMessagesBySession retrieves all messages for a session ordered by creation time
-func (r *PostgresAgentChatRepository) GetAllMessagesBySession(ctx context.Context, sessionID kernel.SessionID) ([]agent.AgentMessage, error) {
+func (r *PostgresAgentChatRepository) GetAllMessagesBySession(ctx context.Context, tenantID kernel.TenantID, sessionID kernel.SessionID) ([]agent.AgentMessage, error) {
 	query := `
 		SELECT 
 			id, tenant_id, session_id, role, content, name, function_call, tool_calls, 
 			tool_call_id, metadata, message_type, processing_time_ms, 
 			model_used, tokens_used, created_at, updated_at
 		FROM agent_messages
-		WHERE session_id = $1
+		WHERE tenant_id = $1 AND session_id = $2
 		ORDER BY created_at ASC, id ASC
 	` // ✅ ADDED tenant_id to SELECT
 
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
 	var dbMessages []dbAgentMessage
-	err := r.db.SelectContext(ctx, &dbMessages, query, sessionID.String())
+	err = db.SelectContext(ctx, &dbMessages, query, tenantID.String(), sessionID.String())
 	if err != nil {
 		return nil, errx.Wrap(err, "failed to get messages by session", errx.TypeInternal).
 			WithDetail("session_id", sessionID.String())
@@ -230,7 +242,12 @@ func (r *PostgresAgentChatRepository) CreateMessage(ctx context.Context, req age
 		)
 	`
 
-	_, err = r.db.NamedExecContext(ctx, query, dbMsg)
+	db, err := r.conn(ctx, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.NamedExecContext(ctx, query, dbMsg)
 	if err != nil {
 		logx.Error("Error inserting agent message: %v", err)
 		return nil, errx.Wrap(err, "failed to create message", errx.TypeInternal).
@@ -242,22 +259,27 @@ func (r *PostgresAgentChatRepository) CreateMessage(ctx context.Context, req age
 }
 
 // ClearSessionMessages deletes all messages for a session, optionally keeping system prompts
-func (r *PostgresAgentChatRepository) ClearSessionMessages(ctx context.Context, sessionID kernel.SessionID, keepSystemPrompt bool) error {
+func (r *PostgresAgentChatRepository) ClearSessionMessages(ctx context.Context, tenantID kernel.TenantID, sessionID kernel.SessionID, keepSystemPrompt bool) error {
 	var query string
 
 	if keepSystemPrompt {
 		query = `
 			DELETE FROM agent_messages
-			WHERE session_id = $1 AND role != 'system'
+			WHERE tenant_id = $1 AND session_id = $2 AND role != 'system'
 		`
 	} else {
 		query = `
 			DELETE FROM agent_messages
-			WHERE session_id = $1
+			WHERE tenant_id = $1 AND session_id = $2
 		`
 	}
 
-	result, err := r.db.ExecContext(ctx, query, sessionID.String())
+	db, err := r.conn(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.ExecContext(ctx, query, tenantID.String(), sessionID.String())
 	if err != nil {
 		return errx.Wrap(err, "failed to clear session messages", errx.TypeInternal).
 			WithDetail("session_id", sessionID.String()).
@@ -269,3 +291,16 @@ func (r *PostgresAgentChatRepository) ClearSessionMessages(ctx context.Context,
 
 	return nil
 }
+
+// conn is the database holding the tenant's session history
+func (r *PostgresAgentChatRepository) conn(ctx context.Context, tenantID kernel.TenantID) (*sqlx.DB, error) {
+	if r.router == nil {
+		return r.db, nil
+	}
+	db, err := r.router.For(ctx, tenantID)
+	if err != nil {
+		return nil, errx.Wrap(err, "failed to resolve tenant database", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+	return db, nil
+}
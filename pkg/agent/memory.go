@@ -51,7 +51,7 @@ func (m *SessionMemory) Messages() ([]llm.Message, error) {
 		messages = append(messages, m.contextMsgs...)
 	}
 
-	storedMessages, err := m.repo.GetAllMessagesBySession(m.ctx, m.sessionID)
+	storedMessages, err := m.repo.GetAllMessagesBySession(m.ctx, m.tenantID, m.sessionID)
 	if err != nil {
 		log.Printf("⚠️  Failed to load stored messages: %v", err)
 		m.cachedMessages = messages
@@ -117,7 +117,7 @@ func (m *SessionMemory) Add(msg llm.Message) error {
 
 func (m *SessionMemory) Clear() error {
 	m.cachedMessages = nil
-	return m.repo.ClearSessionMessages(m.ctx, m.sessionID, true)
+	return m.repo.ClearSessionMessages(m.ctx, m.tenantID, m.sessionID, true)
 }
 
 // ✅ FIX: Properly convert map[string]any to llm.FunctionCall and llm.ToolCall
@@ -7,7 +7,7 @@ import (
 )
 
 type AgentChatRepository interface {
-	GetAllMessagesBySession(ctx context.Context, sessionID kernel.SessionID) ([]AgentMessage, error)
+	GetAllMessagesBySession(ctx context.Context, tenantID kernel.TenantID, sessionID kernel.SessionID) ([]AgentMessage, error)
 	CreateMessage(ctx context.Context, req CreateMessageRequest) (*AgentMessage, error)
-	ClearSessionMessages(ctx context.Context, sessionID kernel.SessionID, keepSystemPrompt bool) error
+	ClearSessionMessages(ctx context.Context, tenantID kernel.TenantID, sessionID kernel.SessionID, keepSystemPrompt bool) error
 }
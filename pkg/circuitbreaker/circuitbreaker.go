@@ -0,0 +1,299 @@
+package circuitbreaker
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Estados y configuración
+// ============================================================================
+
+// State estado de un circuito
+type State string
+
+const (
+	StateClosed   State = "closed"    // Las llamadas pasan
+	StateOpen     State = "open"      // Las llamadas se rechazan sin llegar al proveedor
+	StateHalfOpen State = "half_open" // Se dejan pasar unas pocas llamadas de prueba
+)
+
+// Settings configuración de los circuitos
+type Settings struct {
+	FailureThreshold int           // Fallos consecutivos que abren el circuito
+	OpenTimeout      time.Duration // Tiempo abierto antes de probar de nuevo
+	HalfOpenMaxCalls int           // Llamadas de prueba simultáneas en half_open
+}
+
+// DefaultSettings configuración por defecto
+var DefaultSettings = Settings{
+	FailureThreshold: 5,
+	OpenTimeout:      30 * time.Second,
+	HalfOpenMaxCalls: 1,
+}
+
+// ============================================================================
+// Eventos
+// ============================================================================
+
+// EventStateChanged tipo del evento que se publica en cada cambio de estado
+const EventStateChanged = "circuit_breaker.state_changed"
+
+// StateChange cambio de estado de un circuito
+type StateChange struct {
+	Name     string    `json:"name"`
+	From     State     `json:"from"`
+	To       State     `json:"to"`
+	Failures int       `json:"failures"`
+	At       time.Time `json:"at"`
+}
+
+// Listener recibe los cambios de estado; se llama fuera del lock del circuito
+type Listener func(change StateChange)
+
+// ============================================================================
+// Errores
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("CIRCUIT_BREAKER")
+
+var (
+	CodeCircuitOpen = ErrRegistry.Register("OPEN", errx.TypeUnavailable, http.StatusServiceUnavailable, "El proveedor está fallando, llamada rechazada")
+)
+
+func ErrCircuitOpen() *errx.Error {
+	return ErrRegistry.New(CodeCircuitOpen)
+}
+
+// ============================================================================
+// Registry
+// ============================================================================
+
+// Registry circuitos por nombre ("channel:<id>", "http:<host>", "ai:<provider>").
+// Un Registry nil no crea circuitos: todas las llamadas pasan.
+type Registry struct {
+	settings Settings
+
+	mu        sync.RWMutex
+	breakers  map[string]*Breaker
+	listeners []Listener
+}
+
+// NewRegistry crea un registro de circuitos con la misma configuración
+func NewRegistry(settings Settings) *Registry {
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = DefaultSettings.FailureThreshold
+	}
+	if settings.OpenTimeout <= 0 {
+		settings.OpenTimeout = DefaultSettings.OpenTimeout
+	}
+	if settings.HalfOpenMaxCalls <= 0 {
+		settings.HalfOpenMaxCalls = DefaultSettings.HalfOpenMaxCalls
+	}
+
+	return &Registry{
+		settings: settings,
+		breakers: make(map[string]*Breaker),
+	}
+}
+
+// OnStateChange registra un listener de cambios de estado
+func (r *Registry) OnStateChange(listener Listener) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, listener)
+}
+
+// Breaker retorna el circuito con ese nombre, creándolo la primera vez
+func (r *Registry) Breaker(name string) *Breaker {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	breaker, ok := r.breakers[name]
+	r.mu.RUnlock()
+	if ok {
+		return breaker
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if breaker, ok := r.breakers[name]; ok {
+		return breaker
+	}
+	breaker = &Breaker{name: name, settings: r.settings, registry: r, state: StateClosed}
+	r.breakers[name] = breaker
+	return breaker
+}
+
+// BreakerStatus estado expuesto en health y debug
+type BreakerStatus struct {
+	State    State `json:"state"`
+	Failures int   `json:"failures"`
+}
+
+// Snapshot estado de los circuitos que no están cerrados o que acumulan fallos
+func (r *Registry) Snapshot() map[string]BreakerStatus {
+	snapshot := make(map[string]BreakerStatus)
+	if r == nil {
+		return snapshot
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for name, breaker := range r.breakers {
+		breaker.mu.Lock()
+		if breaker.state != StateClosed || breaker.failures > 0 {
+			snapshot[name] = BreakerStatus{State: breaker.state, Failures: breaker.failures}
+		}
+		breaker.mu.Unlock()
+	}
+	return snapshot
+}
+
+func (r *Registry) notify(change StateChange) {
+	r.mu.RLock()
+	listeners := r.listeners
+	r.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(change)
+	}
+}
+
+// ============================================================================
+// Breaker
+// ============================================================================
+
+// Breaker circuito de un proveedor. Cada Allow exitoso debe ir seguido de un
+// Record con el resultado de la llamada. Un Breaker nil deja pasar todo.
+type Breaker struct {
+	name     string
+	settings Settings
+	registry *Registry
+
+	mu            sync.Mutex
+	state         State
+	failures      int
+	openedAt      time.Time
+	halfOpenCalls int
+}
+
+// Allow indica si la llamada puede hacerse. Con el circuito abierto retorna
+// ErrCircuitOpen con el tiempo restante en retry_after_seconds.
+func (b *Breaker) Allow() *errx.Error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	var change *StateChange
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.settings.OpenTimeout {
+		change = b.transition(StateHalfOpen)
+	}
+
+	var err *errx.Error
+	switch b.state {
+	case StateOpen:
+		retryAfter := b.settings.OpenTimeout - time.Since(b.openedAt)
+		err = b.openError(retryAfter)
+	case StateHalfOpen:
+		if b.halfOpenCalls >= b.settings.HalfOpenMaxCalls {
+			err = b.openError(0)
+		} else {
+			b.halfOpenCalls++
+		}
+	}
+	b.mu.Unlock()
+
+	if change != nil {
+		b.registry.notify(*change)
+	}
+	return err
+}
+
+// Record registra el resultado de una llamada permitida. failed debe ser true
+// solo para fallos del proveedor (red, timeouts, 5xx); una respuesta de error
+// de negocio indica que el proveedor está respondiendo.
+func (b *Breaker) Record(failed bool) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	var change *StateChange
+	switch {
+	case b.state == StateHalfOpen && failed:
+		b.failures++
+		change = b.transition(StateOpen)
+	case b.state == StateHalfOpen:
+		b.failures = 0
+		change = b.transition(StateClosed)
+	case failed:
+		b.failures++
+		if b.state == StateClosed && b.failures >= b.settings.FailureThreshold {
+			change = b.transition(StateOpen)
+		}
+	default:
+		b.failures = 0
+	}
+	b.mu.Unlock()
+
+	if change != nil {
+		b.registry.notify(*change)
+	}
+}
+
+// State estado actual del circuito
+func (b *Breaker) State() State {
+	if b == nil {
+		return StateClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// transition cambia de estado; se llama con el lock tomado
+func (b *Breaker) transition(to State) *StateChange {
+	change := &StateChange{
+		Name:     b.name,
+		From:     b.state,
+		To:       to,
+		Failures: b.failures,
+		At:       time.Now(),
+	}
+
+	b.state = to
+	b.halfOpenCalls = 0
+	if to == StateOpen {
+		b.openedAt = change.At
+	}
+
+	switch to {
+	case StateOpen:
+		log.Printf("🔴 Circuit %s opened after %d consecutive failures", b.name, b.failures)
+	case StateHalfOpen:
+		log.Printf("🟡 Circuit %s half-open, probing provider", b.name)
+	case StateClosed:
+		log.Printf("🟢 Circuit %s closed, provider recovered", b.name)
+	}
+
+	return change
+}
+
+func (b *Breaker) openError(retryAfter time.Duration) *errx.Error {
+	err := ErrCircuitOpen().WithDetail("circuit", b.name)
+	if seconds := int(retryAfter.Round(time.Second).Seconds()); seconds > 0 {
+		err = err.WithDetail("retry_after_seconds", seconds)
+	}
+	return err
+}
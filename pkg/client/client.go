@@ -0,0 +1,192 @@
+// Package client es el cliente Go tipado de la API de Relay. Sus métodos
+// siguen las operaciones anotadas del documento OpenAPI
+// (GET /api/openapi.json) y reutilizan los mismos DTOs que el servidor, así
+// que un cambio de contrato rompe la compilación en lugar de las integraciones.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout timeout del http.Client por defecto
+const DefaultTimeout = 30 * time.Second
+
+// Client cliente de la API de Relay
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	userAgent  string
+}
+
+// Option configura el cliente
+type Option func(*Client)
+
+// WithToken autentica las peticiones con un access token (Bearer)
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithHTTPClient usa un http.Client propio (proxies, TLS, timeouts)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent identifica al integrador en los logs del servidor
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New crea un cliente para baseURL, p. ej. https://relay.example.com
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		userAgent:  "relay-go-client",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SetToken cambia el access token, p. ej. tras Login o Refresh
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
+// ============================================================================
+// Errores
+// ============================================================================
+
+// APIError error devuelto por la API (cuerpo de errxfiber)
+type APIError struct {
+	Status  int            `json:"-"`
+	Code    string         `json:"code"`
+	Type    string         `json:"type"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("relay: HTTP %d: %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("relay: HTTP %d: %s: %s", e.Status, e.Code, e.Message)
+}
+
+// IsNotFound indica si el error es un 404 de la API
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+// ============================================================================
+// Transporte
+// ============================================================================
+
+// Do envía una petición JSON y decodifica la respuesta en out (si no es nil).
+// Sirve para las rutas que aún no tienen método tipado.
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var reader io.Reader
+	contentType := ""
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("relay: encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+		contentType = "application/json"
+	}
+	return c.send(ctx, method, path, query, contentType, reader, out)
+}
+
+// doRaw envía el cuerpo tal cual, p. ej. un manifiesto YAML o JSON
+func (c *Client) doRaw(ctx context.Context, method, path string, query url.Values, body []byte, out any) error {
+	return c.send(ctx, method, path, query, "application/yaml", bytes.NewReader(body), out)
+}
+
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader, out any) error {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		// Algunas rutas devuelven un resultado parcial junto con el error
+		if out != nil {
+			_ = json.Unmarshal(raw, out)
+		}
+		return decodeError(resp.StatusCode, raw)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("relay: decode %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// decodeError lee el cuerpo {"error": {...}}; algunas rutas de auth
+// devuelven {"error": "mensaje"}
+func decodeError(status int, raw []byte) error {
+	apiErr := &APIError{Status: status, Message: http.StatusText(status)}
+
+	var envelope struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(raw, &envelope) == nil && len(envelope.Error) > 0 {
+		var message string
+		if json.Unmarshal(envelope.Error, &message) == nil {
+			apiErr.Message = message
+		} else {
+			_ = json.Unmarshal(envelope.Error, apiErr)
+		}
+	} else if text := strings.TrimSpace(string(raw)); text != "" {
+		apiErr.Message = text
+	}
+
+	return apiErr
+}
+
+func pathID(id string) string {
+	return url.PathEscape(id)
+}
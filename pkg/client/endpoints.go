@@ -0,0 +1,338 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/channels/channelapi"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/engine/continuation"
+	"github.com/Abraxas-365/relay/engine/nodecatalog"
+	"github.com/Abraxas-365/relay/engine/promotion"
+	"github.com/Abraxas-365/relay/engine/workflowtest"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/manifest"
+)
+
+// ============================================================================
+// Auth
+// ============================================================================
+
+// Login inicia sesión con email y contraseña y usa el token devuelto
+func (c *Client) Login(ctx context.Context, req auth.PasswordLoginRequest) (*auth.TokenResponse, error) {
+	var out auth.TokenResponse
+	if err := c.Do(ctx, http.MethodPost, "/auth/login/password", nil, req, &out); err != nil {
+		return nil, err
+	}
+	if out.AccessToken != "" {
+		c.SetToken(out.AccessToken)
+	}
+	return &out, nil
+}
+
+// Refresh renueva el access token y usa el nuevo
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (*auth.TokenResponse, error) {
+	var out auth.TokenResponse
+	req := auth.RefreshTokenRequest{RefreshToken: refreshToken}
+	if err := c.Do(ctx, http.MethodPost, "/auth/refresh", nil, req, &out); err != nil {
+		return nil, err
+	}
+	c.SetToken(out.AccessToken)
+	return &out, nil
+}
+
+// ListTenants tenants a los que el usuario tiene acceso
+func (c *Client) ListTenants(ctx context.Context) ([]auth.UserTenantDTO, error) {
+	var out struct {
+		Tenants []auth.UserTenantDTO `json:"tenants"`
+	}
+	if err := c.Do(ctx, http.MethodGet, "/auth/tenants", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Tenants, nil
+}
+
+// ============================================================================
+// Channels
+// ============================================================================
+
+// ListChannelsOptions filtros de ListChannels; los vacíos no se envían
+type ListChannelsOptions struct {
+	Page     int
+	PageSize int
+	Type     channels.ChannelType
+	IsActive *bool
+	Provider string
+	Search   string
+}
+
+func (o ListChannelsOptions) query() url.Values {
+	query := pageQuery(o.Page, o.PageSize)
+	if o.Type != "" {
+		query.Set("type", string(o.Type))
+	}
+	if o.IsActive != nil {
+		query.Set("is_active", strconv.FormatBool(*o.IsActive))
+	}
+	if o.Provider != "" {
+		query.Set("provider", o.Provider)
+	}
+	if o.Search != "" {
+		query.Set("search", o.Search)
+	}
+	return query
+}
+
+func (c *Client) ListChannels(ctx context.Context, opts ListChannelsOptions) (*channels.ChannelListResponse, error) {
+	var out channels.ChannelListResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/channels", opts.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) CreateChannel(ctx context.Context, req channels.CreateChannelRequest) (*channels.Channel, error) {
+	var out channels.Channel
+	if err := c.Do(ctx, http.MethodPost, "/api/channels", nil, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) ValidateChannelConfig(ctx context.Context, req channels.ValidateChannelConfigRequest) (*channels.ValidateChannelConfigResponse, error) {
+	var out channels.ValidateChannelConfigResponse
+	if err := c.Do(ctx, http.MethodPost, "/api/channels/validate", nil, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) GetChannel(ctx context.Context, channelID string) (*channels.ChannelResponse, error) {
+	var out channels.ChannelResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/channels/"+pathID(channelID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) UpdateChannel(ctx context.Context, channelID string, req channels.UpdateChannelRequest) (*channels.Channel, error) {
+	var out channels.Channel
+	if err := c.Do(ctx, http.MethodPut, "/api/channels/"+pathID(channelID), nil, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) DeleteChannel(ctx context.Context, channelID string) error {
+	return c.Do(ctx, http.MethodDelete, "/api/channels/"+pathID(channelID), nil, nil, nil)
+}
+
+// TestChannel prueba la conexión con el proveedor; un fallo de conexión es
+// un resultado válido (Success=false), no un error
+func (c *Client) TestChannel(ctx context.Context, channelID string) (*channels.TestChannelResponse, error) {
+	var out channels.TestChannelResponse
+	if err := c.Do(ctx, http.MethodPost, "/api/channels/"+pathID(channelID)+"/test", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) GetChannelFeatures(ctx context.Context, channelID string) (*channels.ChannelFeaturesResponse, error) {
+	var out channels.ChannelFeaturesResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/channels/"+pathID(channelID)+"/features", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) GetChannelWebhook(ctx context.Context, channelID string) (*channels.ChannelWebhookResponse, error) {
+	var out channels.ChannelWebhookResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/channels/"+pathID(channelID)+"/webhook", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendMessage envía un mensaje de operador; el canal sale del path
+func (c *Client) SendMessage(ctx context.Context, channelID string, req channels.SendMessageRequest) (*channels.SendMessageResponse, error) {
+	var out channels.SendMessageResponse
+	if err := c.Do(ctx, http.MethodPost, "/api/channels/"+pathID(channelID)+"/messages", nil, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ============================================================================
+// Simulation & Workflows
+// ============================================================================
+
+// Simulate inyecta un mensaje sintético en un canal TEST_HTTP
+func (c *Client) Simulate(ctx context.Context, req channelapi.SimulateRequest) (*channelapi.SimulateResponse, error) {
+	var out channelapi.SimulateResponse
+	if err := c.Do(ctx, http.MethodPost, "/api/simulate", nil, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunWorkflowTest ejecuta un script de conversación contra el workflow
+func (c *Client) RunWorkflowTest(ctx context.Context, workflowID string, script workflowtest.Script) (*workflowtest.Report, error) {
+	var out workflowtest.Report
+	if err := c.Do(ctx, http.MethodPost, "/api/workflows/"+pathID(workflowID)+"/tests", nil, script, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PromoteWorkflow copia un workflow sandbox a producción
+func (c *Client) PromoteWorkflow(ctx context.Context, workflowID string, req promotion.PromoteRequest) (*promotion.PromoteResponse, error) {
+	var out promotion.PromoteResponse
+	if err := c.Do(ctx, http.MethodPost, "/api/workflows/"+pathID(workflowID)+"/promote", nil, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListNodeTypes tipos de nodo registrados, incluidos los de plugins
+func (c *Client) ListNodeTypes(ctx context.Context) ([]nodecatalog.NodeTypeInfo, error) {
+	var out struct {
+		NodeTypes []nodecatalog.NodeTypeInfo `json:"node_types"`
+	}
+	if err := c.Do(ctx, http.MethodGet, "/api/node-types", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.NodeTypes, nil
+}
+
+func (c *Client) GetNodeType(ctx context.Context, nodeType string) (*nodecatalog.NodeTypeInfo, error) {
+	var out nodecatalog.NodeTypeInfo
+	if err := c.Do(ctx, http.MethodGet, "/api/node-types/"+pathID(nodeType), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ============================================================================
+// Continuations
+// ============================================================================
+
+func (c *Client) ListContinuations(ctx context.Context, page, pageSize int) (*engine.ContinuationListResponse, error) {
+	var out engine.ContinuationListResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/continuations", pageQuery(page, pageSize), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) ContinuationStats(ctx context.Context) (*continuation.Stats, error) {
+	var out continuation.Stats
+	if err := c.Do(ctx, http.MethodGet, "/api/continuations/stats", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) GetContinuation(ctx context.Context, id string) (*engine.WorkflowContinuation, error) {
+	var out engine.WorkflowContinuation
+	if err := c.Do(ctx, http.MethodGet, "/api/continuations/"+pathID(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ============================================================================
+// Jobs
+// ============================================================================
+
+// ListJobsOptions filtros de ListJobs; los vacíos no se envían
+type ListJobsOptions struct {
+	Page     int
+	PageSize int
+	Status   jobs.Status
+	Kind     string
+}
+
+func (o ListJobsOptions) query() url.Values {
+	query := pageQuery(o.Page, o.PageSize)
+	if o.Status != "" {
+		query.Set("status", string(o.Status))
+	}
+	if o.Kind != "" {
+		query.Set("kind", o.Kind)
+	}
+	return query
+}
+
+// ListJobs lista los trabajos del tenant y los del sistema
+func (c *Client) ListJobs(ctx context.Context, opts ListJobsOptions) (*jobs.JobListResponse, error) {
+	var out jobs.JobListResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/jobs", opts.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) ListJobRuns(ctx context.Context, jobID string, page, pageSize int) (*jobs.RunListResponse, error) {
+	var out jobs.RunListResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/jobs/"+pathID(jobID)+"/runs", pageQuery(page, pageSize), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunJob hace que un trabajo del tenant se ejecute ya
+func (c *Client) RunJob(ctx context.Context, jobID string) (*jobs.Job, error) {
+	var out jobs.Job
+	if err := c.Do(ctx, http.MethodPost, "/api/jobs/"+pathID(jobID)+"/run", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ============================================================================
+// Manifests
+// ============================================================================
+
+// PlanManifest calcula los cambios que aplicaría el manifiesto (YAML o JSON)
+func (c *Client) PlanManifest(ctx context.Context, manifestData []byte, prune bool) (*manifest.Plan, error) {
+	var out manifest.Plan
+	if err := c.doRaw(ctx, http.MethodPost, "/api/manifest/plan", pruneQuery(prune), manifestData, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ApplyManifest aplica el manifiesto. Si un cambio falla devuelve el
+// resultado parcial junto con el *APIError (422)
+func (c *Client) ApplyManifest(ctx context.Context, manifestData []byte, prune bool) (*manifest.ApplyResult, error) {
+	var out manifest.ApplyResult
+	err := c.doRaw(ctx, http.MethodPost, "/api/manifest/apply", pruneQuery(prune), manifestData, &out)
+	if err != nil && out.Failed == nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func pruneQuery(prune bool) url.Values {
+	query := url.Values{}
+	if prune {
+		query.Set("prune", "true")
+	}
+	return query
+}
+
+// pageQuery parámetros de paginación; 0 = valor por defecto del servidor
+func pageQuery(page, pageSize int) url.Values {
+	query := url.Values{}
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	if pageSize > 0 {
+		query.Set("page_size", strconv.Itoa(pageSize))
+	}
+	return query
+}
@@ -3,17 +3,34 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/circuitbreaker"
+	"github.com/Abraxas-365/relay/pkg/ratelimit"
 )
 
 // Config configuración principal de la aplicación
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Auth     auth.Config
+	Server       ServerConfig
+	Database     DatabaseConfig
+	Residency    ResidencyConfig
+	Redis        RedisConfig
+	Auth         auth.Config
+	Tenant       TenantConfig
+	Retention    RetentionConfig
+	Encryption   EncryptionConfig
+	RateLimit    RateLimitConfig
+	MessageBatch MessageBatchConfig
+	Channels     ChannelsConfig
+	Attachments  AttachmentConfig
+	SpamFilter   SpamFilterConfig
+	Engine       EngineConfig
+	Jobs         JobsConfig
+	Integrations IntegrationsConfig
 }
 
 // ServerConfig configuración del servidor HTTP
@@ -23,6 +40,9 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
+	MigrationsDir   string // Migraciones que /readyz exige aplicadas; vacío = no se comprueban
+	DebugEnabled    bool   // Expone /debug; por defecto solo en development
+	DebugToken      string // Si no está vacío, /debug exige "Authorization: Bearer <token>"
 }
 
 // DatabaseConfig configuración de PostgreSQL
@@ -36,6 +56,16 @@ type DatabaseConfig struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration // Cierra conexiones ociosas para liberar slots del servidor
+	SimpleProtocol  bool          // Compatible con pgbouncer en modo transaction pooling
+}
+
+// ResidencyConfig bases de datos regionales. Los mensajes, sesiones de agentes
+// y contactos de un tenant viven en la base de su región (configuración
+// data.region del tenant); IAM y el resto de tablas siguen en la principal
+type ResidencyConfig struct {
+	PrimaryRegion string                    // Región de la base principal (DB_*); vacío = sin nombre
+	Regions       map[string]DatabaseConfig // DATA_REGIONS=eu,...; cada una con DB_<REGION>_*
 }
 
 // RedisConfig configuración de Redis
@@ -46,6 +76,112 @@ type RedisConfig struct {
 	DB       int
 }
 
+// TenantConfig configuración del ciclo de vida de tenants
+type TenantConfig struct {
+	ExportDir              string // Directorio de los archivos de exportación
+	LifecycleWebhookURL    string // Vacío = sin notificaciones
+	LifecycleWebhookSecret string // Firma HMAC de las notificaciones
+}
+
+// RetentionConfig configuración del worker de retención de datos
+type RetentionConfig struct {
+	PurgeInterval      time.Duration
+	MessageArchiveDays int // Meses enteros más antiguos que esto se archivan como Parquet; 0 = nunca
+}
+
+// JobsConfig configuración del runner de trabajos en segundo plano
+type JobsConfig struct {
+	Concurrency  int           // Trabajos simultáneos por instancia
+	PollInterval time.Duration // Cada cuánto se buscan trabajos pendientes
+	RunHistory   time.Duration // Tiempo que se conserva el historial de ejecuciones
+}
+
+// EncryptionConfig configuración del cifrado de campos en reposo
+type EncryptionConfig struct {
+	MasterKey string // Vacío = cifrado por tenant no disponible
+}
+
+// RateLimitConfig límites de peticiones por ámbito (token bucket en Redis)
+type RateLimitConfig struct {
+	Enabled       bool
+	AuthIP        ratelimit.Rule // Endpoints /auth por IP
+	WebhookIP     ratelimit.Rule // Webhooks públicos por IP
+	WebhookTenant ratelimit.Rule // Webhooks públicos por tenant
+	APIIP         ratelimit.Rule // API REST por IP, antes de autenticar
+	APITenant     ratelimit.Rule // API REST por tenant autenticado
+	Sender        ratelimit.Rule // Mensajes por remitente y canal que disparan workflows, salvo configuración del tenant
+}
+
+// MessageBatchConfig escritura agrupada de mensajes de conversación
+type MessageBatchConfig struct {
+	Enabled  bool
+	MaxSize  int           // Mensajes por lote antes de escribir
+	MaxDelay time.Duration // Latencia máxima añadida a cada mensaje
+}
+
+// ChannelsConfig configuración de las llamadas salientes a proveedores
+type ChannelsConfig struct {
+	HTTPProxy             string // Vacío = HTTP_PROXY/HTTPS_PROXY del entorno
+	CircuitBreakerEnabled bool
+	CircuitBreaker        circuitbreaker.Settings // Por canal, por host de nodos HTTP y por proveedor de IA
+}
+
+// AttachmentConfig almacenamiento y escaneo de los adjuntos entrantes
+type AttachmentConfig struct {
+	Storage       string        // local, s3, gcs; vacío = los adjuntos no se descargan
+	LocalDir      string        // Directorio de la storage local
+	PublicURL     string        // URL base de este servidor para los enlaces firmados locales
+	SigningSecret string        // Firma de los enlaces locales
+	LinkTTL       time.Duration // Vigencia de los enlaces firmados
+	S3Endpoint    string        // Vacío = AWS (s3) o storage.googleapis.com (gcs)
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string // Claves HMAC en GCS
+	S3SecretKey   string
+	S3PathStyle   bool
+	Scanner       string // clamav, http; vacío = sin escaneo
+	ClamAVAddr    string // host:port de clamd
+	ScannerURL    string // Servicio externo que responde {"clean": bool, "threat": "..."}
+	ScannerToken  string
+	ScanTimeout   time.Duration
+
+	Transcriber             string  // whisper, deepgram; vacío = las notas de voz no se transcriben
+	TranscriptionAPIKey     string  // Vacío con whisper = OPENAI_API_KEY
+	TranscriptionModel      string  // Vacío = modelo por defecto del proveedor
+	TranscriptionLanguage   string  // Vacío = detección automática
+	TranscriptMinConfidence float64 // Por debajo se marca low_confidence
+
+	Vision       string // openai, google; vacío = las imágenes no se analizan
+	VisionAPIKey string // Vacío con openai = OPENAI_API_KEY
+	VisionModel  string // Vacío = modelo por defecto del proveedor
+}
+
+// SpamFilterConfig clasificador externo del filtro de spam entrante
+type SpamFilterConfig struct {
+	ClassifierURL     string // Servicio que responde {"score": 0..1}; vacío = sin clasificador
+	ClassifierToken   string
+	ClassifierTimeout time.Duration
+}
+
+// EngineConfig configuración del motor de workflows
+type EngineConfig struct {
+	FaultInjectionEnabled bool          // Permite que los tenants con el flag fault_injection inyecten fallos
+	MaxNodeOutputBytes    int           // Tamaño máximo (JSON) de la salida de un nodo; 0 = sin límite
+	SpillNodeOutputs      bool          // Guarda en el storage de adjuntos los campos truncados
+	SnapshotRetention     time.Duration // Cuánto se guardan las ejecuciones grabadas de workflows en modo debug
+}
+
+// IntegrationsConfig aplicaciones OAuth de los conectores de terceros. Un
+// proveedor sin client ID no se puede conectar.
+type IntegrationsConfig struct {
+	PublicURL              string // URL base de este servidor para los callbacks OAuth
+	HubSpotClientID        string
+	HubSpotClientSecret    string
+	SalesforceClientID     string
+	SalesforceClientSecret string
+	SalesforceLoginURL     string // Vacío = login.salesforce.com; test.salesforce.com para sandboxes
+}
+
 // Load carga la configuración desde variables de entorno
 func Load() (*Config, error) {
 	// Cargar .env si existe
@@ -57,18 +193,11 @@ func Load() (*Config, error) {
 			ReadTimeout:     getDurationEnv("READ_TIMEOUT", 10*time.Second),
 			WriteTimeout:    getDurationEnv("WRITE_TIMEOUT", 10*time.Second),
 			ShutdownTimeout: getDurationEnv("SHUTDOWN_TIMEOUT", 30*time.Second),
+			MigrationsDir:   getEnv("MIGRATIONS_DIR", "migrations"),
+			DebugToken:      getEnv("DEBUG_TOKEN", ""),
 		},
-		Database: DatabaseConfig{
-			Host:            getEnv("DB_HOST", getEnv("POSTGRES_HOST", "localhost")),
-			Port:            getEnv("DB_PORT", getEnv("POSTGRES_PORT", "5432")),
-			User:            getEnv("DB_USER", getEnv("POSTGRES_USER", "postgres")),
-			Password:        getEnv("DB_PASSWORD", getEnv("POSTGRES_PASSWORD", "postgres")),
-			DBName:          getEnv("DB_NAME", getEnv("POSTGRES_DB", "facturamelo")),
-			SSLMode:         getEnv("DB_SSLMODE", "disable"),
-			MaxOpenConns:    getIntEnv("DB_MAX_OPEN_CONNS", 25),
-			MaxIdleConns:    getIntEnv("DB_MAX_IDLE_CONNS", 5),
-			ConnMaxLifetime: getDurationEnv("DB_CONN_MAX_LIFETIME", 5*time.Minute),
-		},
+		Database:  LoadDatabaseConfig(),
+		Residency: LoadResidencyConfig(),
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
 			Port:     getEnv("REDIS_PORT", "6379"),
@@ -76,7 +205,100 @@ func Load() (*Config, error) {
 			DB:       getIntEnv("REDIS_DB", 0),
 		},
 		Auth: LoadAuthConfig(),
+		Tenant: TenantConfig{
+			ExportDir:              getEnv("TENANT_EXPORT_DIR", filepath.Join(os.TempDir(), "relay-exports")),
+			LifecycleWebhookURL:    getEnv("TENANT_LIFECYCLE_WEBHOOK_URL", ""),
+			LifecycleWebhookSecret: getEnv("TENANT_LIFECYCLE_WEBHOOK_SECRET", ""),
+		},
+		Retention: RetentionConfig{
+			PurgeInterval:      getDurationEnv("RETENTION_PURGE_INTERVAL", time.Hour),
+			MessageArchiveDays: getIntEnv("MESSAGE_ARCHIVE_DAYS", 0),
+		},
+		Encryption: EncryptionConfig{
+			MasterKey: getEnv("FIELD_ENCRYPTION_KEY", ""),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:       getEnv("RATE_LIMIT_ENABLED", "true") == "true",
+			AuthIP:        getRateLimitRule("RATE_LIMIT_AUTH_IP", 30, 10),
+			WebhookIP:     getRateLimitRule("RATE_LIMIT_WEBHOOK_IP", 1200, 200),
+			WebhookTenant: getRateLimitRule("RATE_LIMIT_WEBHOOK_TENANT", 3000, 500),
+			APIIP:         getRateLimitRule("RATE_LIMIT_API_IP", 1200, 200),
+			APITenant:     getRateLimitRule("RATE_LIMIT_API_TENANT", 600, 100),
+			Sender:        getRateLimitRule("RATE_LIMIT_SENDER", 30, 10),
+		},
+		MessageBatch: MessageBatchConfig{
+			Enabled:  getEnv("MESSAGE_BATCH_ENABLED", "true") == "true",
+			MaxSize:  getIntEnv("MESSAGE_BATCH_MAX_SIZE", 200),
+			MaxDelay: getDurationEnv("MESSAGE_BATCH_MAX_DELAY", 10*time.Millisecond),
+		},
+		Channels: ChannelsConfig{
+			HTTPProxy:             getEnv("CHANNEL_HTTP_PROXY", ""),
+			CircuitBreakerEnabled: getEnv("CIRCUIT_BREAKER_ENABLED", "true") == "true",
+			CircuitBreaker: circuitbreaker.Settings{
+				FailureThreshold: getIntEnv("CIRCUIT_BREAKER_FAILURE_THRESHOLD", 5),
+				OpenTimeout:      getDurationEnv("CIRCUIT_BREAKER_OPEN_TIMEOUT", 30*time.Second),
+				HalfOpenMaxCalls: getIntEnv("CIRCUIT_BREAKER_HALF_OPEN_MAX_CALLS", 1),
+			},
+		},
+		Attachments: AttachmentConfig{
+			Storage:       getEnv("ATTACHMENT_STORAGE", "local"),
+			LocalDir:      getEnv("ATTACHMENT_LOCAL_DIR", filepath.Join(os.TempDir(), "relay-attachments")),
+			PublicURL:     getEnv("ATTACHMENT_PUBLIC_URL", "http://localhost:"+getEnv("PORT", "8080")),
+			SigningSecret: getEnv("ATTACHMENT_SIGNING_SECRET", getEnv("JWT_SECRET", "default-secret-change-in-production")),
+			LinkTTL:       getDurationEnv("ATTACHMENT_LINK_TTL", time.Hour),
+			S3Endpoint:    getEnv("ATTACHMENT_S3_ENDPOINT", ""),
+			S3Region:      getEnv("ATTACHMENT_S3_REGION", "us-east-1"),
+			S3Bucket:      getEnv("ATTACHMENT_S3_BUCKET", ""),
+			S3AccessKey:   getEnv("ATTACHMENT_S3_ACCESS_KEY", ""),
+			S3SecretKey:   getEnv("ATTACHMENT_S3_SECRET_KEY", ""),
+			S3PathStyle:   getEnv("ATTACHMENT_S3_PATH_STYLE", "false") == "true",
+			Scanner:       getEnv("ATTACHMENT_SCANNER", ""),
+			ClamAVAddr:    getEnv("CLAMAV_ADDR", "localhost:3310"),
+			ScannerURL:    getEnv("ATTACHMENT_SCANNER_URL", ""),
+			ScannerToken:  getEnv("ATTACHMENT_SCANNER_TOKEN", ""),
+			ScanTimeout:   getDurationEnv("ATTACHMENT_SCAN_TIMEOUT", time.Minute),
+
+			Transcriber:             getEnv("TRANSCRIPTION_PROVIDER", ""),
+			TranscriptionAPIKey:     getEnv("TRANSCRIPTION_API_KEY", ""),
+			TranscriptionModel:      getEnv("TRANSCRIPTION_MODEL", ""),
+			TranscriptionLanguage:   getEnv("TRANSCRIPTION_LANGUAGE", ""),
+			TranscriptMinConfidence: getFloatEnv("TRANSCRIPTION_MIN_CONFIDENCE", 0.6),
+
+			Vision:       getEnv("VISION_PROVIDER", ""),
+			VisionAPIKey: getEnv("VISION_API_KEY", ""),
+			VisionModel:  getEnv("VISION_MODEL", ""),
+		},
+		SpamFilter: SpamFilterConfig{
+			ClassifierURL:     getEnv("SPAM_CLASSIFIER_URL", ""),
+			ClassifierToken:   getEnv("SPAM_CLASSIFIER_TOKEN", ""),
+			ClassifierTimeout: getDurationEnv("SPAM_CLASSIFIER_TIMEOUT", 2*time.Second),
+		},
+		Engine: EngineConfig{
+			FaultInjectionEnabled: getEnv("FAULT_INJECTION_ENABLED", "false") == "true",
+			MaxNodeOutputBytes:    getIntEnv("MAX_NODE_OUTPUT_BYTES", 256*1024),
+			SpillNodeOutputs:      getEnv("SPILL_NODE_OUTPUTS", "false") == "true",
+			SnapshotRetention:     getDurationEnv("WORKFLOW_SNAPSHOT_RETENTION", 72*time.Hour),
+		},
+		Jobs: JobsConfig{
+			Concurrency:  getIntEnv("JOBS_CONCURRENCY", 4),
+			PollInterval: getDurationEnv("JOBS_POLL_INTERVAL", 5*time.Second),
+			RunHistory:   getDurationEnv("JOBS_RUN_HISTORY", 7*24*time.Hour),
+		},
+		Integrations: IntegrationsConfig{
+			PublicURL:              getEnv("INTEGRATIONS_PUBLIC_URL", "http://localhost:"+getEnv("PORT", "8080")),
+			HubSpotClientID:        getEnv("HUBSPOT_CLIENT_ID", ""),
+			HubSpotClientSecret:    getEnv("HUBSPOT_CLIENT_SECRET", ""),
+			SalesforceClientID:     getEnv("SALESFORCE_CLIENT_ID", ""),
+			SalesforceClientSecret: getEnv("SALESFORCE_CLIENT_SECRET", ""),
+			SalesforceLoginURL:     getEnv("SALESFORCE_LOGIN_URL", ""),
+		},
+	}
+
+	defaultDebug := "false"
+	if config.Server.Environment == "development" {
+		defaultDebug = "true"
 	}
+	config.Server.DebugEnabled = getEnv("DEBUG_ENDPOINTS_ENABLED", defaultDebug) == "true"
 
 	if err := config.Validate(); err != nil {
 		return nil, err
@@ -96,6 +318,12 @@ func (c *Config) Validate() error {
 	if c.Database.DBName == "" {
 		return fmt.Errorf("DB_NAME is required")
 	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		return fmt.Errorf("DB_MAX_IDLE_CONNS (%d) cannot exceed DB_MAX_OPEN_CONNS (%d)", c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	}
+	if err := c.Residency.Validate(); err != nil {
+		return err
+	}
 
 	// Validar configuración de Auth
 	if err := c.Auth.Validate(); err != nil {
@@ -145,6 +373,86 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getRateLimitRule lee <prefix>_PER_MINUTE y <prefix>_BURST; 0 por minuto desactiva el límite
+func getRateLimitRule(prefix string, perMinute, burst int) ratelimit.Rule {
+	return ratelimit.Rule{
+		Requests: getIntEnv(prefix+"_PER_MINUTE", perMinute),
+		Period:   time.Minute,
+		Burst:    getIntEnv(prefix+"_BURST", burst),
+	}
+}
+
+// LoadDatabaseConfig carga solo la configuración de PostgreSQL, para las
+// herramientas (relay migrations) que no necesitan el resto
+func LoadDatabaseConfig() DatabaseConfig {
+	return DatabaseConfig{
+		Host:            getEnv("DB_HOST", getEnv("POSTGRES_HOST", "localhost")),
+		Port:            getEnv("DB_PORT", getEnv("POSTGRES_PORT", "5432")),
+		User:            getEnv("DB_USER", getEnv("POSTGRES_USER", "postgres")),
+		Password:        getEnv("DB_PASSWORD", getEnv("POSTGRES_PASSWORD", "postgres")),
+		DBName:          getEnv("DB_NAME", getEnv("POSTGRES_DB", "facturamelo")),
+		SSLMode:         getEnv("DB_SSLMODE", "disable"),
+		MaxOpenConns:    getIntEnv("DB_MAX_OPEN_CONNS", 25),
+		MaxIdleConns:    getIntEnv("DB_MAX_IDLE_CONNS", 5),
+		ConnMaxLifetime: getDurationEnv("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+		ConnMaxIdleTime: getDurationEnv("DB_CONN_MAX_IDLE_TIME", time.Minute),
+		SimpleProtocol:  getEnv("DB_SIMPLE_PROTOCOL", "false") == "true",
+	}
+}
+
+// LoadResidencyConfig carga las regiones de DATA_REGIONS. Cada región toma
+// DB_<REGION>_HOST, _PORT, _USER, _PASSWORD, _NAME y _SSLMODE; lo que falte
+// (salvo el host) y el pool se heredan de la base principal
+func LoadResidencyConfig() ResidencyConfig {
+	primary := LoadDatabaseConfig()
+	cfg := ResidencyConfig{
+		PrimaryRegion: strings.ToLower(getEnv("DATA_PRIMARY_REGION", "")),
+		Regions:       make(map[string]DatabaseConfig),
+	}
+
+	for _, name := range strings.Split(getEnv("DATA_REGIONS", ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "DB_" + strings.ToUpper(name) + "_"
+
+		region := primary
+		region.Host = getEnv(prefix+"HOST", "")
+		region.Port = getEnv(prefix+"PORT", primary.Port)
+		region.User = getEnv(prefix+"USER", primary.User)
+		region.Password = getEnv(prefix+"PASSWORD", primary.Password)
+		region.DBName = getEnv(prefix+"NAME", primary.DBName)
+		region.SSLMode = getEnv(prefix+"SSLMODE", primary.SSLMode)
+		cfg.Regions[name] = region
+	}
+
+	return cfg
+}
+
+// Validate exige el host de cada región: heredar el de la principal dejaría
+// los datos regionales en la base principal
+func (c *ResidencyConfig) Validate() error {
+	for name, region := range c.Regions {
+		if name == c.PrimaryRegion {
+			return fmt.Errorf("DATA_REGIONS cannot include the primary region %q", name)
+		}
+		if region.Host == "" {
+			return fmt.Errorf("DB_%s_HOST is required for data region %q", strings.ToUpper(name), name)
+		}
+	}
+	return nil
+}
+
 // LoadAuthConfig carga la configuración desde variables de entorno
 func LoadAuthConfig() auth.Config {
 	return auth.Config{
@@ -168,5 +476,23 @@ func LoadAuthConfig() auth.Config {
 				Scopes:       []string{"openid", "email", "profile", "User.Read"},
 			},
 		},
+		Password: auth.PasswordConfig{
+			MinLength:            getIntEnv("PASSWORD_MIN_LENGTH", 8),
+			MaxFailedAttempts:    getIntEnv("LOGIN_MAX_FAILED_ATTEMPTS", 5),
+			LockoutDuration:      getDurationEnv("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+			LoginRateLimit:       getIntEnv("LOGIN_RATE_LIMIT", 10),
+			LoginRateWindow:      getDurationEnv("LOGIN_RATE_WINDOW", time.Minute),
+			ResetTokenTTL:        getDurationEnv("PASSWORD_RESET_TOKEN_TTL", time.Hour),
+			VerificationTokenTTL: getDurationEnv("EMAIL_VERIFICATION_TOKEN_TTL", 48*time.Hour),
+			AppBaseURL:           getEnv("APP_BASE_URL", "http://localhost:8080"),
+		},
+		SSO: auth.SSOSettings{
+			BaseURL:    getEnv("SSO_BASE_URL", getEnv("APP_BASE_URL", "http://localhost:8080")),
+			SPEntityID: getEnv("SAML_SP_ENTITY_ID", ""),
+		},
+		MFA: auth.MFAConfig{
+			Issuer:        getEnv("MFA_ISSUER", "Relay"),
+			EncryptionKey: getEnv("MFA_ENCRYPTION_KEY", ""),
+		},
 	}
 }
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ============================================================================
+// Migraciones
+// ============================================================================
+
+// Migration archivo NNN_nombre.up.sql; la versión es el prefijo NNN
+type Migration struct {
+	Version string
+	Path    string
+}
+
+// MigrationStatus compara los archivos con la tabla schema_migrations
+type MigrationStatus struct {
+	Tracked bool     `json:"tracked"` // false = la tabla no existe (base migrada con 'make migrate')
+	Applied int      `json:"applied"`
+	Total   int      `json:"total"`
+	Pending []string `json:"pending,omitempty"`
+}
+
+// LoadMigrations lee los *.up.sql de dir ordenados por versión
+func LoadMigrations(dir string) ([]Migration, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.up.sql files in %s", dir)
+	}
+
+	migrations := make([]Migration, 0, len(paths))
+	seen := make(map[string]string, len(paths))
+	for _, path := range paths {
+		version, _, ok := strings.Cut(filepath.Base(path), "_")
+		if !ok {
+			return nil, fmt.Errorf("%s: expected NNN_name.up.sql", path)
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("migrations %s and %s share version %s", other, path, version)
+		}
+		seen[version] = path
+		migrations = append(migrations, Migration{Version: version, Path: path})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// EnsureMigrationsTable crea la tabla de control si no existe
+func EnsureMigrationsTable(ctx context.Context, db *sqlx.DB) error {
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    TEXT PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// AppliedMigrations devuelve las versiones ya aplicadas
+func AppliedMigrations(ctx context.Context, db *sqlx.DB) (map[string]bool, error) {
+	var versions []string
+	if err := db.SelectContext(ctx, &versions, `SELECT version FROM schema_migrations`); err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	applied := make(map[string]bool, len(versions))
+	for _, version := range versions {
+		applied[version] = true
+	}
+	return applied, nil
+}
+
+// ApplyMigration ejecuta el archivo y lo registra en la misma transacción
+func ApplyMigration(ctx context.Context, db *sqlx.DB, m Migration) error {
+	script, err := os.ReadFile(m.Path)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(m.Path), err)
+	}
+	if err := RecordMigration(ctx, tx, m.Version); err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(m.Path), err)
+	}
+	return tx.Commit()
+}
+
+// RecordMigration marca una versión como aplicada sin ejecutarla
+func RecordMigration(ctx context.Context, db sqlx.ExecerContext, version string) error {
+	_, err := db.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version)
+	return err
+}
+
+// CheckMigrations compara los archivos de dir con la base sin modificarla
+func CheckMigrations(ctx context.Context, db *sqlx.DB, dir string) (*MigrationStatus, error) {
+	migrations, err := LoadMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+	status := &MigrationStatus{Total: len(migrations)}
+
+	var tracked bool
+	if err := db.GetContext(ctx, &tracked, `SELECT to_regclass('schema_migrations') IS NOT NULL`); err != nil {
+		return nil, fmt.Errorf("failed to look up schema_migrations: %w", err)
+	}
+	if !tracked {
+		return status, nil
+	}
+	status.Tracked = true
+
+	applied, err := AppliedMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range migrations {
+		if applied[m.Version] {
+			status.Applied++
+		} else {
+			status.Pending = append(status.Pending, filepath.Base(m.Path))
+		}
+	}
+	return status, nil
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -12,7 +13,7 @@ import (
 
 // NewPostgresDB crea una nueva conexión a PostgreSQL
 func NewPostgresDB(cfg config.DatabaseConfig) (*sqlx.DB, error) {
-	db, err := sqlx.Connect("postgres", cfg.GetDSN())
+	db, err := openPostgres(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -21,18 +22,34 @@ func NewPostgresDB(cfg config.DatabaseConfig) (*sqlx.DB, error) {
 	db.SetMaxOpenConns(cfg.MaxOpenConns)
 	db.SetMaxIdleConns(cfg.MaxIdleConns)
 	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
 	// Verificar conexión
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
 	return db, nil
 }
 
+// openPostgres abre el pool; en modo simple protocol las consultas van en un
+// solo viaje para que pgbouncer pueda multiplexar conexiones por transacción
+func openPostgres(cfg config.DatabaseConfig) (*sqlx.DB, error) {
+	if !cfg.SimpleProtocol {
+		return sqlx.Open("postgres", cfg.GetDSN())
+	}
+
+	connector, err := newSimpleProtocolConnector(cfg.GetDSN())
+	if err != nil {
+		return nil, err
+	}
+	return sqlx.NewDb(sql.OpenDB(connector), "postgres"), nil
+}
+
 // CloseDB cierra la conexión a la base de datos
 func CloseDB(db *sqlx.DB) error {
 	if db != nil {
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/Abraxas-365/relay/pkg/config"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/jmoiron/sqlx"
+)
+
+// ============================================================================
+// Residencia de datos
+// ============================================================================
+
+// RegionResolver devuelve la región de datos de un tenant; vacío = región principal
+type RegionResolver func(ctx context.Context, tenantID kernel.TenantID) (string, error)
+
+// Router elige la base de datos de cada tenant para los datos que deben
+// quedarse en su región: mensajes, sesiones de agentes y contactos. IAM y el
+// resto de tablas compartidas siguen siempre en la base principal.
+//
+// La región de un tenant no cambia después de crearlo, así que se guarda en
+// caché sin expiración; los errores del resolver no se guardan
+type Router struct {
+	primaryRegion string
+	primary       *sqlx.DB
+	regions       map[string]*sqlx.DB
+	resolve       RegionResolver
+
+	mu    sync.RWMutex
+	cache map[kernel.TenantID]string
+}
+
+// NewRouter crea el router. Sin regiones o sin resolver todo va a la principal
+func NewRouter(primaryRegion string, primary *sqlx.DB, regions map[string]*sqlx.DB, resolve RegionResolver) *Router {
+	if regions == nil {
+		regions = make(map[string]*sqlx.DB)
+	}
+	return &Router{
+		primaryRegion: primaryRegion,
+		primary:       primary,
+		regions:       regions,
+		resolve:       resolve,
+		cache:         make(map[kernel.TenantID]string),
+	}
+}
+
+// OpenRegions abre un pool por región; si alguna falla cierra las ya abiertas
+func OpenRegions(cfg config.ResidencyConfig) (map[string]*sqlx.DB, error) {
+	regions := make(map[string]*sqlx.DB, len(cfg.Regions))
+	for name, dbCfg := range cfg.Regions {
+		db, err := NewPostgresDB(dbCfg)
+		if err != nil {
+			for _, opened := range regions {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("data region %s: %w", name, err)
+		}
+		regions[name] = db
+	}
+	return regions, nil
+}
+
+// For devuelve la base de datos del tenant. Una región que este despliegue no
+// tiene configurada es un error: caer a la principal sacaría los datos de su región
+func (r *Router) For(ctx context.Context, tenantID kernel.TenantID) (*sqlx.DB, error) {
+	region, err := r.Region(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if region == "" || region == r.primaryRegion {
+		return r.primary, nil
+	}
+
+	db, ok := r.regions[region]
+	if !ok {
+		return nil, fmt.Errorf("data region %q of tenant %s is not configured", region, tenantID)
+	}
+	return db, nil
+}
+
+// Region devuelve la región de datos del tenant; vacío = región principal
+func (r *Router) Region(ctx context.Context, tenantID kernel.TenantID) (string, error) {
+	if r.resolve == nil || len(r.regions) == 0 {
+		return "", nil
+	}
+
+	r.mu.RLock()
+	region, ok := r.cache[tenantID]
+	r.mu.RUnlock()
+	if ok {
+		return region, nil
+	}
+
+	region, err := r.resolve(ctx, tenantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve data region of tenant %s: %w", tenantID, err)
+	}
+
+	r.mu.Lock()
+	r.cache[tenantID] = region
+	r.mu.Unlock()
+	return region, nil
+}
+
+// Primary devuelve la base principal
+func (r *Router) Primary() *sqlx.DB {
+	return r.primary
+}
+
+// RegionNames devuelve las regiones configuradas, sin la principal
+func (r *Router) RegionNames() []string {
+	return slices.Sorted(maps.Keys(r.regions))
+}
+
+// Regional devuelve la base de una región configurada
+func (r *Router) Regional(name string) (*sqlx.DB, bool) {
+	db, ok := r.regions[name]
+	return db, ok
+}
+
+// All devuelve la principal seguida de cada región, para el mantenimiento que
+// recorre a todos los tenants (particiones, archivado)
+func (r *Router) All() []*sqlx.DB {
+	all := []*sqlx.DB{r.primary}
+	for _, name := range r.RegionNames() {
+		all = append(all, r.regions[name])
+	}
+	return all
+}
+
+// Close cierra los pools regionales; la principal la cierra quien la abrió
+func (r *Router) Close() {
+	for _, db := range r.regions {
+		db.Close()
+	}
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/lib/pq"
+)
+
+// simpleProtocolConnector abre conexiones compatibles con pgbouncer en modo
+// transaction pooling.
+//
+// Con binary_parameters=yes lib/pq envía Parse/Bind/Execute en un solo viaje
+// en lugar de preparar la sentencia en un viaje aparte, así que pgbouncer no
+// puede cambiar de servidor entre la preparación y la ejecución. En ese modo
+// lib/pq manda los []byte en formato binario, que jsonb y text no aceptan; por
+// eso cada parámetro []byte se convierte a string (no hay columnas bytea).
+type simpleProtocolConnector struct {
+	connector *pq.Connector
+}
+
+func newSimpleProtocolConnector(dsn string) (*simpleProtocolConnector, error) {
+	connector, err := pq.NewConnector(dsn + " binary_parameters=yes")
+	if err != nil {
+		return nil, err
+	}
+	return &simpleProtocolConnector{connector: connector}, nil
+}
+
+func (c *simpleProtocolConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &simpleProtocolConn{Conn: conn}, nil
+}
+
+func (c *simpleProtocolConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// simpleProtocolConn reenvía todo a la conexión de lib/pq y solo cambia la
+// conversión de parámetros
+type simpleProtocolConn struct {
+	driver.Conn
+}
+
+// CheckNamedValue aplica la conversión estándar y pasa los []byte como texto
+func (c *simpleProtocolConn) CheckNamedValue(nv *driver.NamedValue) error {
+	value, err := driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	if err != nil {
+		return err
+	}
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	nv.Value = value
+	return nil
+}
+
+func (c *simpleProtocolConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if conn, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return conn.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *simpleProtocolConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if conn, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return conn.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *simpleProtocolConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if conn, ok := c.Conn.(driver.QueryerContext); ok {
+		return conn.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *simpleProtocolConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if conn, ok := c.Conn.(driver.ExecerContext); ok {
+		return conn.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *simpleProtocolConn) Ping(ctx context.Context) error {
+	if conn, ok := c.Conn.(driver.Pinger); ok {
+		return conn.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *simpleProtocolConn) ResetSession(ctx context.Context) error {
+	if conn, ok := c.Conn.(driver.SessionResetter); ok {
+		return conn.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *simpleProtocolConn) IsValid() bool {
+	if conn, ok := c.Conn.(driver.Validator); ok {
+		return conn.IsValid()
+	}
+	return true
+}
@@ -6,11 +6,13 @@ package kernel
 
 // AuthContext es el contexto de autenticación que se inyecta en cada request
 type AuthContext struct {
-	UserID   UserID   `json:"user_id"`
-	TenantID TenantID `json:"tenant_id"`
-	IsAdmin  bool     `json:"is_admin"`
-	Email    string   `json:"email"`
-	Name     string   `json:"name"`
+	UserID    UserID   `json:"user_id"`
+	TenantID  TenantID `json:"tenant_id"`
+	IsAdmin   bool     `json:"is_admin"`
+	Email     string   `json:"email"`
+	Name      string   `json:"name"`
+	AMR       []string `json:"amr,omitempty"`        // Métodos de autenticación (pwd, fed, otp, mfa)
+	SessionID string   `json:"session_id,omitempty"` // Sesión (dispositivo) que emitió el token
 }
 
 // IsValid verifica si el AuthContext es válido
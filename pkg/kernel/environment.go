@@ -0,0 +1,32 @@
+package kernel
+
+// ============================================================================
+// Environment - Separación sandbox / producción
+// ============================================================================
+
+// Environment entorno al que pertenece un canal o un workflow. Los mensajes
+// de un canal sandbox solo disparan workflows sandbox y viceversa.
+type Environment string
+
+const (
+	EnvironmentProduction Environment = "production"
+	EnvironmentSandbox    Environment = "sandbox"
+)
+
+// IsValid verifica si el entorno es conocido
+func (e Environment) IsValid() bool {
+	return e == EnvironmentProduction || e == EnvironmentSandbox
+}
+
+// OrDefault devuelve producción cuando el entorno está vacío (registros
+// anteriores a la separación de entornos)
+func (e Environment) OrDefault() Environment {
+	if e == "" {
+		return EnvironmentProduction
+	}
+	return e
+}
+
+func (e Environment) String() string {
+	return string(e)
+}
@@ -0,0 +1,68 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ============================================================================
+// Handlers
+// ============================================================================
+
+// Handler sirve el documento JSON. Se genera en la primera petición, cuando
+// ya están registradas todas las rutas de la app.
+func Handler(spec *Spec, app *fiber.App) fiber.Handler {
+	var (
+		once sync.Once
+		body []byte
+		err  error
+	)
+
+	return func(c *fiber.Ctx) error {
+		once.Do(func() {
+			doc := spec.Build(app.GetRoutes(true))
+			body, err = json.Marshal(doc)
+			if err == nil {
+				log.Printf("📘 OpenAPI document generated (%s)", doc)
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+		return c.Send(body)
+	}
+}
+
+// SwaggerUI sirve Swagger UI apuntando al documento en specURL
+func SwaggerUI(title, specURL string) fiber.Handler {
+	page := fmt.Sprintf(swaggerUITemplate, html.EscapeString(title), html.EscapeString(specURL))
+
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.SendString(page)
+	}
+}
+
+const swaggerUITemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%s</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "%s", dom_id: "#swagger-ui", persistAuthorization: true });
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,316 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ============================================================================
+// Anotaciones
+// ============================================================================
+
+// Annotation completa lo que Fiber sabe de una ruta (método, path y
+// parámetros) con la forma de la petición y de la respuesta
+type Annotation struct {
+	Summary     string
+	Description string
+	Tags        []string    // Vacío = el primer segmento tras /api
+	Query       []Parameter // Parámetros de query; los de path salen de Fiber
+	Request     any         // Valor del tipo del cuerpo JSON, p. ej. channels.CreateChannelRequest{}
+	Response    any         // Valor del tipo de la respuesta JSON; nil = sin cuerpo documentado
+	Status      int         // Código de éxito; 0 = 200
+}
+
+// QueryParam parámetro de query opcional
+func QueryParam(name, schemaType, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: schemaType}}
+}
+
+// ============================================================================
+// Spec
+// ============================================================================
+
+const (
+	securityScheme = "bearerAuth"
+	errorComponent = "ErrorResponse"
+)
+
+// Spec genera el documento OpenAPI a partir de las rutas registradas en
+// Fiber. Las rutas sin anotación se documentan igual, solo sin esquemas.
+type Spec struct {
+	info        Info
+	servers     []Server
+	public      []string
+	annotations map[string]Annotation
+
+	mu         sync.Mutex
+	components map[string]*Schema
+}
+
+func NewSpec(info Info) *Spec {
+	return &Spec{
+		info:        info,
+		annotations: make(map[string]Annotation),
+	}
+}
+
+// Server añade una URL base al documento
+func (s *Spec) Server(url, description string) *Spec {
+	s.servers = append(s.servers, Server{URL: url, Description: description})
+	return s
+}
+
+// Public marca los prefijos que no requieren autenticación
+func (s *Spec) Public(prefixes ...string) *Spec {
+	s.public = append(s.public, prefixes...)
+	return s
+}
+
+// Describe anota una ruta; path con la sintaxis de Fiber (/api/channels/:id)
+func (s *Spec) Describe(method, path string, annotation Annotation) *Spec {
+	s.annotations[routeKey(method, path)] = annotation
+	return s
+}
+
+// Build genera el documento para las rutas dadas, normalmente
+// app.GetRoutes(true) una vez registradas todas
+func (s *Spec) Build(routes []fiber.Route) *Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.components = map[string]*Schema{errorComponent: errorSchema()}
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    s.info,
+		Servers: s.servers,
+		Paths:   make(map[string]*PathItem),
+	}
+
+	tags := make(map[string]bool)
+	operationIDs := make(map[string]int)
+
+	for _, route := range routes {
+		path := normalizePath(route.Path)
+		if path == "" {
+			continue
+		}
+
+		item, ok := doc.Paths[openAPIPath(path)]
+		if !ok {
+			item = &PathItem{}
+		}
+		slot := item.operation(route.Method)
+		if slot == nil || *slot != nil {
+			continue // HEAD/OPTIONS automáticos o ruta repetida
+		}
+
+		op := s.operation(route.Method, path, route.Params)
+		operationIDs[op.OperationID]++
+		if n := operationIDs[op.OperationID]; n > 1 {
+			op.OperationID += strconv.Itoa(n)
+		}
+		for _, tag := range op.Tags {
+			tags[tag] = true
+		}
+
+		*slot = op
+		doc.Paths[openAPIPath(path)] = item
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+
+	doc.Components = Components{
+		Schemas: s.components,
+		SecuritySchemes: map[string]*SecurityScheme{
+			securityScheme: {
+				Type:         "http",
+				Scheme:       "bearer",
+				BearerFormat: "JWT",
+				Description:  "Access token from /auth; the access_token cookie is accepted too",
+			},
+		},
+	}
+
+	return doc
+}
+
+func (s *Spec) operation(method, path string, params []string) *Operation {
+	annotation := s.annotations[routeKey(method, path)]
+
+	op := &Operation{
+		OperationID: operationID(method, path),
+		Summary:     annotation.Summary,
+		Description: annotation.Description,
+		Tags:        annotation.Tags,
+		Responses:   make(map[string]*Response),
+	}
+	if op.Summary == "" {
+		op.Summary = method + " " + path
+	}
+	if len(op.Tags) == 0 {
+		op.Tags = []string{defaultTag(path)}
+	}
+
+	for _, param := range params {
+		if strings.HasPrefix(param, "*") || strings.HasPrefix(param, "+") {
+			param = "wildcard" // Fiber los nombra *1, +1...
+		}
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     param,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	op.Parameters = append(op.Parameters, annotation.Query...)
+
+	if annotation.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(s.schemaOf(reflect.TypeOf(annotation.Request))),
+		}
+	}
+
+	status := annotation.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	if annotation.Response != nil && status != http.StatusNoContent {
+		success.Content = jsonContent(s.schemaOf(reflect.TypeOf(annotation.Response)))
+	}
+	op.Responses[strconv.Itoa(status)] = success
+	op.Responses["default"] = &Response{
+		Description: "Error",
+		Content:     jsonContent(&Schema{Ref: "#/components/schemas/" + errorComponent}),
+	}
+
+	if !s.isPublic(path) {
+		op.Security = []SecurityRequirement{{securityScheme: {}}}
+	}
+
+	return op
+}
+
+func (s *Spec) isPublic(path string) bool {
+	for _, prefix := range s.public {
+		if path == prefix {
+			return true
+		}
+		if prefix != "/" && strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// errorSchema cuerpo de error de errxfiber
+func errorSchema() *Schema {
+	return &Schema{
+		Type:     "object",
+		Required: []string{"error"},
+		Properties: map[string]*Schema{
+			"error": {
+				Type:     "object",
+				Required: []string{"code", "type", "message"},
+				Properties: map[string]*Schema{
+					"code":    {Type: "string"},
+					"type":    {Type: "string"},
+					"message": {Type: "string"},
+					"details": {Type: "object", AdditionalProperties: &Schema{}},
+				},
+			},
+		},
+	}
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{fiber.MIMEApplicationJSON: {Schema: schema}}
+}
+
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + normalizePath(path)
+}
+
+// normalizePath quita la barra final que dejan los grupos; "" = no documentar
+func normalizePath(path string) string {
+	if path == "" || path == "*" || path == "/*" {
+		return ""
+	}
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+// openAPIPath convierte /channels/:id a /channels/{id}
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			segments[i] = "{" + strings.TrimSuffix(segment[1:], "?") + "}"
+		case segment == "*" || segment == "+":
+			segments[i] = "{wildcard}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID p. ej. GET /api/channels/:id → getChannelsById
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "/api"), "/") {
+		if segment == "" || segment == "*" || segment == "+" {
+			continue
+		}
+		if strings.HasPrefix(segment, ":") {
+			b.WriteString("By")
+			segment = strings.TrimSuffix(segment[1:], "?")
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+// defaultTag primer segmento tras /api, o el primero de la ruta
+func defaultTag(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 1 && segments[0] == "api" {
+		return segments[1]
+	}
+	if segments[0] == "" {
+		return "root"
+	}
+	return segments[0]
+}
+
+// String resumen para logs
+func (d *Document) String() string {
+	operations := 0
+	for _, item := range d.Paths {
+		for _, op := range []*Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch} {
+			if op != nil {
+				operations++
+			}
+		}
+	}
+	return fmt.Sprintf("%d paths, %d operations, %d schemas", len(d.Paths), operations, len(d.Components.Schemas))
+}
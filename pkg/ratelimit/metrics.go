@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ScopeMetrics contadores de un ámbito de rate limiting
+type ScopeMetrics struct {
+	Allowed   uint64 `json:"allowed"`
+	Throttled uint64 `json:"throttled"`
+	Errors    uint64 `json:"errors"` // Fallos de Redis; la petición se deja pasar
+}
+
+type scopeCounters struct {
+	allowed   atomic.Uint64
+	throttled atomic.Uint64
+	errors    atomic.Uint64
+}
+
+// Metrics acumula contadores por ámbito (auth_ip, webhook_tenant, ...)
+type Metrics struct {
+	scopes sync.Map // string -> *scopeCounters
+}
+
+// NewMetrics crea un acumulador de métricas vacío
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// Snapshot devuelve una copia de los contadores actuales
+func (m *Metrics) Snapshot() map[string]ScopeMetrics {
+	snapshot := make(map[string]ScopeMetrics)
+	if m == nil {
+		return snapshot
+	}
+
+	m.scopes.Range(func(key, value any) bool {
+		counters := value.(*scopeCounters)
+		snapshot[key.(string)] = ScopeMetrics{
+			Allowed:   counters.allowed.Load(),
+			Throttled: counters.throttled.Load(),
+			Errors:    counters.errors.Load(),
+		}
+		return true
+	})
+
+	return snapshot
+}
+
+func (m *Metrics) counters(scope string) *scopeCounters {
+	if counters, ok := m.scopes.Load(scope); ok {
+		return counters.(*scopeCounters)
+	}
+	counters, _ := m.scopes.LoadOrStore(scope, &scopeCounters{})
+	return counters.(*scopeCounters)
+}
+
+func (m *Metrics) recordAllowed(scope string) {
+	if m != nil {
+		m.counters(scope).allowed.Add(1)
+	}
+}
+
+func (m *Metrics) recordThrottled(scope string) {
+	if m != nil {
+		m.counters(scope).throttled.Add(1)
+	}
+}
+
+func (m *Metrics) recordError(scope string) {
+	if m != nil {
+		m.counters(scope).errors.Add(1)
+	}
+}
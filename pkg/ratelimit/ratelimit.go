@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Rules
+// ============================================================================
+
+// Rule define un token bucket: Requests peticiones sostenidas por Period, con
+// ráfagas de hasta Burst peticiones. Burst en 0 equivale a Requests.
+type Rule struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// Enabled indica si la regla limita algo; Requests o Period en 0 la desactivan
+func (r Rule) Enabled() bool {
+	return r.Requests > 0 && r.Period > 0
+}
+
+// Capacity tamaño del bucket
+func (r Rule) Capacity() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return r.Requests
+}
+
+// RatePerMillisecond tokens que se recuperan por milisegundo
+func (r Rule) RatePerMillisecond() float64 {
+	return float64(r.Requests) / float64(r.Period.Milliseconds())
+}
+
+// Policy valor de la cabecera RateLimit-Policy, p.ej. "600;w=60;burst=100"
+func (r Rule) Policy() string {
+	return fmt.Sprintf("%d;w=%d;burst=%d", r.Requests, int(r.Period.Seconds()), r.Capacity())
+}
+
+// ============================================================================
+// Limiter
+// ============================================================================
+
+// Result resultado de consumir un token
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // Hasta que el bucket vuelve a estar lleno
+	RetryAfter time.Duration // Hasta el siguiente token, solo si !Allowed
+}
+
+// Limiter consume tokens del bucket identificado por key
+type Limiter interface {
+	Allow(ctx context.Context, key string, rule Rule) (Result, error)
+}
+
+// ============================================================================
+// Errors
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("RATE_LIMIT")
+
+var (
+	CodeRateLimited = ErrRegistry.Register("RATE_LIMITED", errx.TypeRateLimit, http.StatusTooManyRequests, "Too many requests")
+)
+
+func ErrRateLimited() *errx.Error {
+	return ErrRegistry.New(CodeRateLimited)
+}
@@ -0,0 +1,34 @@
+package retention
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("RETENTION")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeInvalidPolicy = ErrRegistry.Register("INVALID_POLICY", errx.TypeValidation, http.StatusBadRequest, "Invalid retention policy")
+	CodePurgeFailed   = ErrRegistry.Register("PURGE_FAILED", errx.TypeInternal, http.StatusInternalServerError, "Failed to purge expired data")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrInvalidPolicy() *errx.Error {
+	return ErrRegistry.New(CodeInvalidPolicy)
+}
+
+func ErrPurgeFailed() *errx.Error {
+	return ErrRegistry.New(CodePurgeFailed)
+}
@@ -0,0 +1,133 @@
+package retention
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Retention Policy
+// ============================================================================
+
+// Action is what the purge worker does with data older than the retention window
+type Action string
+
+const (
+	// ActionDelete removes expired rows
+	ActionDelete Action = "DELETE"
+	// ActionAnonymize keeps the rows for analytics but strips content and
+	// replaces contact identifiers with a salted hash
+	ActionAnonymize Action = "ANONYMIZE"
+)
+
+// IsValid reports whether the action is supported
+func (a Action) IsValid() bool {
+	return a == ActionDelete || a == ActionAnonymize
+}
+
+// MaxRetentionDays upper bound accepted for any retention window (10 years)
+const MaxRetentionDays = 3650
+
+// Policy is a tenant's data-retention configuration. A zero retention window
+// means the data is kept indefinitely.
+type Policy struct {
+	TenantID               kernel.TenantID `db:"tenant_id" json:"tenant_id"`
+	MessageRetentionDays   int             `db:"message_retention_days" json:"message_retention_days"`
+	SessionRetentionDays   int             `db:"session_retention_days" json:"session_retention_days"`
+	ExecutionRetentionDays int             `db:"execution_retention_days" json:"execution_retention_days"`
+	Action                 Action          `db:"action" json:"action"`
+	RedactPII              bool            `db:"redact_pii" json:"redact_pii"`
+	UpdatedAt              time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+// DefaultPolicy is applied to tenants that never configured retention:
+// keep everything and persist content as received
+func DefaultPolicy(tenantID kernel.TenantID) *Policy {
+	return &Policy{
+		TenantID: tenantID,
+		Action:   ActionAnonymize,
+	}
+}
+
+// Validate checks the retention windows and action
+func (p *Policy) Validate() error {
+	for field, days := range map[string]int{
+		"message_retention_days":   p.MessageRetentionDays,
+		"session_retention_days":   p.SessionRetentionDays,
+		"execution_retention_days": p.ExecutionRetentionDays,
+	} {
+		if days < 0 || days > MaxRetentionDays {
+			return ErrInvalidPolicy().
+				WithDetail("field", field).
+				WithDetail("max_days", MaxRetentionDays)
+		}
+	}
+
+	if !p.Action.IsValid() {
+		return ErrInvalidPolicy().WithDetail("action", string(p.Action))
+	}
+
+	return nil
+}
+
+// HasRetention reports whether any category expires
+func (p *Policy) HasRetention() bool {
+	return p.MessageRetentionDays > 0 || p.SessionRetentionDays > 0 || p.ExecutionRetentionDays > 0
+}
+
+// Cutoff returns the instant before which data of a category with the given
+// window is expired, or nil when the window is unlimited
+func Cutoff(days int, now time.Time) *time.Time {
+	if days <= 0 {
+		return nil
+	}
+	cutoff := now.AddDate(0, 0, -days)
+	return &cutoff
+}
+
+// ============================================================================
+// DTOs
+// ============================================================================
+
+// UpdatePolicyRequest partially updates a tenant's retention policy
+type UpdatePolicyRequest struct {
+	MessageRetentionDays   *int    `json:"message_retention_days,omitempty"`
+	SessionRetentionDays   *int    `json:"session_retention_days,omitempty"`
+	ExecutionRetentionDays *int    `json:"execution_retention_days,omitempty"`
+	Action                 *Action `json:"action,omitempty"`
+	RedactPII              *bool   `json:"redact_pii,omitempty"`
+}
+
+// Apply copies the set fields onto the policy
+func (r UpdatePolicyRequest) Apply(p *Policy) {
+	if r.MessageRetentionDays != nil {
+		p.MessageRetentionDays = *r.MessageRetentionDays
+	}
+	if r.SessionRetentionDays != nil {
+		p.SessionRetentionDays = *r.SessionRetentionDays
+	}
+	if r.ExecutionRetentionDays != nil {
+		p.ExecutionRetentionDays = *r.ExecutionRetentionDays
+	}
+	if r.Action != nil {
+		p.Action = *r.Action
+	}
+	if r.RedactPII != nil {
+		p.RedactPII = *r.RedactPII
+	}
+}
+
+// PurgeResult counts the rows affected by a purge run
+type PurgeResult struct {
+	TenantID   kernel.TenantID `json:"tenant_id"`
+	Action     Action          `json:"action"`
+	Messages   int64           `json:"messages"`
+	Sessions   int64           `json:"sessions"`
+	Executions int64           `json:"executions"`
+}
+
+// Total number of rows affected
+func (r PurgeResult) Total() int64 {
+	return r.Messages + r.Sessions + r.Executions
+}
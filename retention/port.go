@@ -0,0 +1,37 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// PolicyRepository persists tenant retention policies
+type PolicyRepository interface {
+	// FindByTenant returns the tenant's policy, or DefaultPolicy when none is stored
+	FindByTenant(ctx context.Context, tenantID kernel.TenantID) (*Policy, error)
+
+	// Save creates or replaces a tenant's policy
+	Save(ctx context.Context, policy Policy) error
+
+	// FindWithRetention returns every policy with at least one finite window
+	FindWithRetention(ctx context.Context) ([]*Policy, error)
+}
+
+// Purger removes or anonymizes expired data. Each method processes rows
+// created before the cutoff in batches and returns how many were affected.
+type Purger interface {
+	// PurgeMessages handles conversation transcripts
+	PurgeMessages(ctx context.Context, tenantID kernel.TenantID, before time.Time, action Action) (int64, error)
+
+	// PurgeSessions handles AI agent session history
+	PurgeSessions(ctx context.Context, tenantID kernel.TenantID, before time.Time, action Action) (int64, error)
+
+	// PurgeExecutions handles workflow execution traces
+	PurgeExecutions(ctx context.Context, tenantID kernel.TenantID, before time.Time, action Action) (int64, error)
+}
@@ -0,0 +1,148 @@
+package retention
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// PII Redaction
+// ============================================================================
+
+// Replacement tokens written in place of detected PII
+const (
+	RedactedEmail = "[REDACTED_EMAIL]"
+	RedactedCard  = "[REDACTED_CARD]"
+	RedactedPhone = "[REDACTED_PHONE]"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+	// 13-19 digits optionally grouped by spaces or dashes; confirmed with Luhn
+	cardPattern = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+
+	// At least 8 digits with the usual phone separators, optional leading +
+	phonePattern = regexp.MustCompile(`(?:\+|\(|\b)\d(?:[ ().\-]{0,2}\d){7,14}\b`)
+
+	// ISO dates share the phone shape and are not personal data on their own
+	datePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// Redactor masks personal data in free text before it is persisted long-term.
+// It is deliberately conservative: it targets emails, payment card numbers and
+// phone numbers, which is what contacts most often type into a conversation.
+type Redactor struct{}
+
+// NewRedactor creates a redactor with the built-in patterns
+func NewRedactor() *Redactor {
+	return &Redactor{}
+}
+
+// Redact returns s with every detected email, card and phone number replaced
+func (r *Redactor) Redact(s string) string {
+	if s == "" {
+		return s
+	}
+
+	s = emailPattern.ReplaceAllString(s, RedactedEmail)
+
+	// Cards before phones: a card number would otherwise match as a phone
+	s = cardPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if luhnValid(match) {
+			return RedactedCard
+		}
+		return match
+	})
+
+	s = phonePattern.ReplaceAllStringFunc(s, func(match string) string {
+		if countDigits(match) < 8 || datePattern.MatchString(match) {
+			return match
+		}
+		return RedactedPhone
+	})
+
+	return s
+}
+
+// RedactValue walks maps and slices (as decoded from JSON) and redacts every
+// string it finds. Other values are returned unchanged.
+func (r *Redactor) RedactValue(v any) any {
+	switch val := v.(type) {
+	case string:
+		return r.Redact(val)
+	case map[string]any:
+		return r.RedactMap(val)
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = r.RedactValue(item)
+		}
+		return out
+	case []map[string]any:
+		out := make([]map[string]any, len(val))
+		for i, item := range val {
+			out[i] = r.RedactMap(item)
+		}
+		return out
+	case []string:
+		out := make([]string, len(val))
+		for i, item := range val {
+			out[i] = r.Redact(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// RedactMap returns a redacted copy of m; the input is not modified
+func (r *Redactor) RedactMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = r.RedactValue(v)
+	}
+	return out
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func countDigits(s string) int {
+	n := 0
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+func luhnValid(s string) bool {
+	digits := strings.Map(func(c rune) rune {
+		if c >= '0' && c <= '9' {
+			return c
+		}
+		return -1
+	}, s)
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
@@ -0,0 +1,74 @@
+package retentionapi
+
+import (
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/retention"
+	"github.com/Abraxas-365/relay/retention/retentionsrv"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RetentionHandler exposes the tenant's data-retention policy
+type RetentionHandler struct {
+	service *retentionsrv.RetentionService
+}
+
+// NewRetentionHandler creates a new retention handler
+func NewRetentionHandler(service *retentionsrv.RetentionService) *RetentionHandler {
+	return &RetentionHandler{
+		service: service,
+	}
+}
+
+// GetPolicy returns the tenant's retention policy
+// GET /api/retention/policy
+func (h *RetentionHandler) GetPolicy(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	policy, err := h.service.GetPolicy(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(policy)
+}
+
+// UpdatePolicy changes the tenant's retention windows, purge action or redaction
+// PUT /api/retention/policy
+func (h *RetentionHandler) UpdatePolicy(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req retention.UpdatePolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return retention.ErrInvalidPolicy().WithDetail("reason", err.Error())
+	}
+
+	policy, err := h.service.UpdatePolicy(c.Context(), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(policy)
+}
+
+// Purge applies the tenant's policy immediately instead of waiting for the worker
+// POST /api/retention/purge
+func (h *RetentionHandler) Purge(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	result, err := h.service.PurgeTenant(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(result)
+}
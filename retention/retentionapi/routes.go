@@ -0,0 +1,30 @@
+package retentionapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RetentionRoutes handles retention route setup
+type RetentionRoutes struct {
+	handler        *RetentionHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewRetentionRoutes creates a new retention routes instance
+func NewRetentionRoutes(handler *RetentionHandler, authMiddleware *auth.AuthMiddleware) *RetentionRoutes {
+	return &RetentionRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers retention routes on an authenticated router.
+// Changing the policy or purging requires an admin.
+func (r *RetentionRoutes) RegisterRoutes(router fiber.Router) {
+	retention := router.Group("/retention")
+
+	retention.Get("/policy", r.handler.GetPolicy)
+	retention.Put("/policy", r.authMiddleware.RequireAdmin(), r.handler.UpdatePolicy)
+	retention.Post("/purge", r.authMiddleware.RequireAdmin(), r.handler.Purge)
+}
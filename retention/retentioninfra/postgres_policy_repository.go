@@ -0,0 +1,81 @@
+package retentioninfra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/retention"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresPolicyRepository is the PostgreSQL implementation of retention.PolicyRepository
+type PostgresPolicyRepository struct {
+	db *sqlx.DB
+}
+
+var _ retention.PolicyRepository = (*PostgresPolicyRepository)(nil)
+
+func NewPostgresPolicyRepository(db *sqlx.DB) *PostgresPolicyRepository {
+	return &PostgresPolicyRepository{db: db}
+}
+
+const policyColumns = `
+	tenant_id, message_retention_days, session_retention_days,
+	execution_retention_days, action, redact_pii, updated_at`
+
+func (r *PostgresPolicyRepository) FindByTenant(ctx context.Context, tenantID kernel.TenantID) (*retention.Policy, error) {
+	query := `SELECT ` + policyColumns + ` FROM retention_policies WHERE tenant_id = $1`
+
+	var policy retention.Policy
+	if err := r.db.GetContext(ctx, &policy, query, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return retention.DefaultPolicy(tenantID), nil
+		}
+		return nil, errx.Wrap(err, "failed to find retention policy", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	return &policy, nil
+}
+
+func (r *PostgresPolicyRepository) Save(ctx context.Context, policy retention.Policy) error {
+	// The salt keeps its generated default on updates so anonymized IDs stay stable
+	query := `
+		INSERT INTO retention_policies (
+			tenant_id, message_retention_days, session_retention_days,
+			execution_retention_days, action, redact_pii
+		) VALUES (
+			:tenant_id, :message_retention_days, :session_retention_days,
+			:execution_retention_days, :action, :redact_pii
+		)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			message_retention_days = EXCLUDED.message_retention_days,
+			session_retention_days = EXCLUDED.session_retention_days,
+			execution_retention_days = EXCLUDED.execution_retention_days,
+			action = EXCLUDED.action,
+			redact_pii = EXCLUDED.redact_pii`
+
+	if _, err := r.db.NamedExecContext(ctx, query, policy); err != nil {
+		return errx.Wrap(err, "failed to save retention policy", errx.TypeInternal).
+			WithDetail("tenant_id", policy.TenantID.String())
+	}
+
+	return nil
+}
+
+func (r *PostgresPolicyRepository) FindWithRetention(ctx context.Context) ([]*retention.Policy, error) {
+	query := `
+		SELECT ` + policyColumns + `
+		FROM retention_policies
+		WHERE message_retention_days > 0 OR session_retention_days > 0 OR execution_retention_days > 0
+		ORDER BY tenant_id`
+
+	var policies []*retention.Policy
+	if err := r.db.SelectContext(ctx, &policies, query); err != nil {
+		return nil, errx.Wrap(err, "failed to list retention policies", errx.TypeInternal)
+	}
+
+	return policies, nil
+}
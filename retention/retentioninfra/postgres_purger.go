@@ -0,0 +1,181 @@
+package retentioninfra
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/retention"
+	"github.com/jmoiron/sqlx"
+)
+
+// defaultPurgeBatchSize rows touched per statement, to keep locks short
+const defaultPurgeBatchSize = 1000
+
+// anonymizedID replaces a contact identifier with a per-tenant salted hash, so
+// rows of the same conversation stay grouped without being linkable to a person.
+// The salt ($4) is read from the main database, which the purged table may not be in.
+const anonymizedID = `'anon:' || md5($4 || %s)`
+
+// PostgresPurger is the PostgreSQL implementation of retention.Purger
+type PostgresPurger struct {
+	db        *sqlx.DB
+	router    *database.Router // nil = every tenant's messages and sessions are in db
+	batchSize int
+}
+
+var _ retention.Purger = (*PostgresPurger)(nil)
+
+func NewPostgresPurger(db *sqlx.DB) *PostgresPurger {
+	return &PostgresPurger{db: db, batchSize: defaultPurgeBatchSize}
+}
+
+// SetRouter purges messages and sessions in the database of the tenant's data region
+func (p *PostgresPurger) SetRouter(router *database.Router) {
+	p.router = router
+}
+
+// purgeTarget describes how one category is deleted or anonymized
+type purgeTarget struct {
+	table      string
+	timeColumn string
+	anonymize  string // SET clause
+	salted     bool   // anonymize hashes identifiers with the tenant's salt
+	routed     bool   // In the database of the tenant's data region
+}
+
+var (
+	messagesTarget = purgeTarget{
+		table:      "messages",
+		timeColumn: "created_at",
+		anonymize: fmt.Sprintf(`
+			conversation_id = `+anonymizedID+`,
+			sender_id = CASE WHEN direction = 'INBOUND' THEN `+anonymizedID+` ELSE sender_id END,
+			content = jsonb_build_object('type', COALESCE(content->'type', '"text"'::jsonb)),
+			context = '{}'::jsonb,
+			provider_message_id = NULL`, "conversation_id", "sender_id"),
+		salted: true,
+		routed: true,
+	}
+
+	sessionsTarget = purgeTarget{
+		table:      "agent_messages",
+		timeColumn: "created_at",
+		anonymize: fmt.Sprintf(`
+			session_id = `+anonymizedID+`,
+			content = NULL,
+			name = NULL,
+			function_call = NULL,
+			tool_calls = NULL,
+			metadata = '{}'::jsonb`, "session_id"),
+		salted: true,
+		routed: true,
+	}
+
+	executionsTarget = purgeTarget{
+		table:      "workflow_executions",
+		timeColumn: "started_at",
+		anonymize: `
+			response = NULL,
+			context = NULL,
+			executed_nodes = NULL,
+			error = NULL`,
+	}
+)
+
+func (p *PostgresPurger) PurgeMessages(ctx context.Context, tenantID kernel.TenantID, before time.Time, action retention.Action) (int64, error) {
+	return p.purge(ctx, messagesTarget, tenantID, before, action)
+}
+
+func (p *PostgresPurger) PurgeSessions(ctx context.Context, tenantID kernel.TenantID, before time.Time, action retention.Action) (int64, error) {
+	return p.purge(ctx, sessionsTarget, tenantID, before, action)
+}
+
+func (p *PostgresPurger) PurgeExecutions(ctx context.Context, tenantID kernel.TenantID, before time.Time, action retention.Action) (int64, error) {
+	return p.purge(ctx, executionsTarget, tenantID, before, action)
+}
+
+// purge runs batched statements until a batch touches fewer rows than the limit
+func (p *PostgresPurger) purge(ctx context.Context, target purgeTarget, tenantID kernel.TenantID, before time.Time, action retention.Action) (int64, error) {
+	db, err := p.conn(ctx, target, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	var query string
+	args := []any{tenantID.String(), before, p.batchSize}
+	switch action {
+	case retention.ActionDelete:
+		query = fmt.Sprintf(`
+			DELETE FROM %[1]s
+			WHERE id IN (
+				SELECT id FROM %[1]s
+				WHERE tenant_id = $1 AND %[2]s < $2
+				LIMIT $3
+			)`, target.table, target.timeColumn)
+	case retention.ActionAnonymize:
+		query = fmt.Sprintf(`
+			UPDATE %[1]s SET %[3]s,
+				redacted_at = NOW()
+			WHERE id IN (
+				SELECT id FROM %[1]s
+				WHERE tenant_id = $1 AND %[2]s < $2 AND redacted_at IS NULL
+				LIMIT $3
+			)`, target.table, target.timeColumn, target.anonymize)
+
+		if target.salted {
+			var salt sql.NullString
+			if err := p.db.GetContext(ctx, &salt, `SELECT anonymization_salt FROM retention_policies WHERE tenant_id = $1`, tenantID.String()); err != nil && err != sql.ErrNoRows {
+				return 0, retention.ErrPurgeFailed().
+					WithDetail("tenant_id", tenantID.String()).
+					WithCause(err)
+			}
+			args = append(args, salt)
+		}
+	default:
+		return 0, retention.ErrInvalidPolicy().WithDetail("action", string(action))
+	}
+
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		result, err := db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return total, retention.ErrPurgeFailed().
+				WithDetail("table", target.table).
+				WithDetail("tenant_id", tenantID.String()).
+				WithCause(err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return total, retention.ErrPurgeFailed().WithCause(err)
+		}
+
+		total += affected
+		if affected < int64(p.batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// conn is the database holding the target's rows of the tenant
+func (p *PostgresPurger) conn(ctx context.Context, target purgeTarget, tenantID kernel.TenantID) (*sqlx.DB, error) {
+	if !target.routed || p.router == nil {
+		return p.db, nil
+	}
+	db, err := p.router.For(ctx, tenantID)
+	if err != nil {
+		return nil, retention.ErrPurgeFailed().
+			WithDetail("table", target.table).
+			WithDetail("tenant_id", tenantID.String()).
+			WithCause(err)
+	}
+	return db, nil
+}
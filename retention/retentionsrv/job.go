@@ -0,0 +1,15 @@
+package retentionsrv
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/jobs"
+)
+
+// PurgeJobKind is the recurring system job that applies retention policies
+const PurgeJobKind = "retention.purge"
+
+// RunPurgeJob is the handler of PurgeJobKind
+func (s *RetentionService) RunPurgeJob(ctx context.Context, _ jobs.Job) error {
+	return s.PurgeExpired(ctx)
+}
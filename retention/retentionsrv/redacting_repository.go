@@ -0,0 +1,115 @@
+package retentionsrv
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/pkg/agent"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/retention"
+)
+
+// ============================================================================
+// Transcripts
+// ============================================================================
+
+// RedactingMessageRepository redacts PII from transcripts before they are
+// stored, for tenants whose policy enables it. Reads pass through.
+type RedactingMessageRepository struct {
+	conversation.MessageRepository
+	service *RetentionService
+}
+
+var _ conversation.MessageRepository = (*RedactingMessageRepository)(nil)
+
+func NewRedactingMessageRepository(next conversation.MessageRepository, service *RetentionService) *RedactingMessageRepository {
+	return &RedactingMessageRepository{
+		MessageRepository: next,
+		service:           service,
+	}
+}
+
+func (r *RedactingMessageRepository) Save(ctx context.Context, msg conversation.Message) error {
+	if r.service.ShouldRedact(ctx, msg.TenantID) {
+		redactor := r.service.Redactor()
+		msg.Content = redactContent(redactor, msg.Content)
+		msg.Context = redactor.RedactMap(msg.Context)
+	}
+	return r.MessageRepository.Save(ctx, msg)
+}
+
+// redactContent returns a copy of the content with free text redacted
+func redactContent(redactor *retention.Redactor, content channels.MessageContent) channels.MessageContent {
+	content.Text = redactor.Redact(content.Text)
+	content.Caption = redactor.Redact(content.Caption)
+	content.Metadata = redactor.RedactMap(content.Metadata)
+
+	if len(content.Attachments) > 0 {
+		attachments := make([]channels.Attachment, len(content.Attachments))
+		for i, att := range content.Attachments {
+			att.Caption = redactor.Redact(att.Caption)
+			attachments[i] = att
+		}
+		content.Attachments = attachments
+	}
+
+	if content.Contact != nil {
+		contact := *content.Contact
+		if contact.PhoneNumber != "" {
+			contact.PhoneNumber = retention.RedactedPhone
+		}
+		if contact.Email != "" {
+			contact.Email = retention.RedactedEmail
+		}
+		content.Contact = &contact
+	}
+
+	if content.Interactive != nil {
+		interactive := *content.Interactive
+		interactive.Body = redactor.Redact(interactive.Body)
+		content.Interactive = &interactive
+	}
+
+	return content
+}
+
+// ============================================================================
+// AI Session History
+// ============================================================================
+
+// RedactingAgentChatRepository redacts PII from AI session history before it
+// is stored, for tenants whose policy enables it
+type RedactingAgentChatRepository struct {
+	agent.AgentChatRepository
+	service *RetentionService
+}
+
+var _ agent.AgentChatRepository = (*RedactingAgentChatRepository)(nil)
+
+func NewRedactingAgentChatRepository(next agent.AgentChatRepository, service *RetentionService) *RedactingAgentChatRepository {
+	return &RedactingAgentChatRepository{
+		AgentChatRepository: next,
+		service:             service,
+	}
+}
+
+func (r *RedactingAgentChatRepository) CreateMessage(ctx context.Context, req agent.CreateMessageRequest) (*agent.AgentMessage, error) {
+	if r.shouldRedact(ctx, req.TenantID) {
+		redactor := r.service.Redactor()
+		if req.Content != nil {
+			content := redactor.Redact(*req.Content)
+			req.Content = &content
+		}
+		req.FunctionCall = redactor.RedactMap(req.FunctionCall)
+		if req.ToolCalls != nil {
+			req.ToolCalls = redactor.RedactValue(req.ToolCalls).([]map[string]any)
+		}
+		req.Metadata = redactor.RedactMap(req.Metadata)
+	}
+	return r.AgentChatRepository.CreateMessage(ctx, req)
+}
+
+func (r *RedactingAgentChatRepository) shouldRedact(ctx context.Context, tenantID kernel.TenantID) bool {
+	return tenantID != "" && r.service.ShouldRedact(ctx, tenantID)
+}
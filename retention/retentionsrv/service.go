@@ -0,0 +1,179 @@
+package retentionsrv
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/retention"
+)
+
+// policyCacheTTL how long a policy is reused on the persistence hot path
+const policyCacheTTL = time.Minute
+
+type cachedPolicy struct {
+	policy    *retention.Policy
+	expiresAt time.Time
+}
+
+// RetentionService manages tenant retention policies and applies them
+type RetentionService struct {
+	policyRepo retention.PolicyRepository
+	purger     retention.Purger
+	redactor   *retention.Redactor
+
+	mu    sync.RWMutex
+	cache map[kernel.TenantID]cachedPolicy
+}
+
+var _ conversation.RetentionWindow = (*RetentionService)(nil)
+
+func NewRetentionService(policyRepo retention.PolicyRepository, purger retention.Purger) *RetentionService {
+	return &RetentionService{
+		policyRepo: policyRepo,
+		purger:     purger,
+		redactor:   retention.NewRedactor(),
+		cache:      make(map[kernel.TenantID]cachedPolicy),
+	}
+}
+
+// GetPolicy returns the tenant's stored or default policy
+func (s *RetentionService) GetPolicy(ctx context.Context, tenantID kernel.TenantID) (*retention.Policy, error) {
+	return s.policyRepo.FindByTenant(ctx, tenantID)
+}
+
+// UpdatePolicy validates and stores a partial policy update
+func (s *RetentionService) UpdatePolicy(ctx context.Context, tenantID kernel.TenantID, req retention.UpdatePolicyRequest) (*retention.Policy, error) {
+	policy, err := s.policyRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Apply(policy)
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	policy.UpdatedAt = time.Now()
+	if err := s.policyRepo.Save(ctx, *policy); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	delete(s.cache, tenantID)
+	s.mu.Unlock()
+
+	log.Printf("✅ Retention policy updated for tenant %s (messages=%dd sessions=%dd executions=%dd action=%s redact=%v)",
+		tenantID, policy.MessageRetentionDays, policy.SessionRetentionDays,
+		policy.ExecutionRetentionDays, policy.Action, policy.RedactPII)
+
+	return policy, nil
+}
+
+// ShouldRedact reports whether content of the tenant must be redacted before
+// persistence. Lookup errors fall back to the stored content being kept as is.
+func (s *RetentionService) ShouldRedact(ctx context.Context, tenantID kernel.TenantID) bool {
+	policy, ok := s.policyFor(ctx, tenantID)
+	return ok && policy.RedactPII
+}
+
+// MessageCutoff implements conversation.RetentionWindow. Lookup errors fall
+// back to no cutoff, leaving every stored message readable.
+func (s *RetentionService) MessageCutoff(ctx context.Context, tenantID kernel.TenantID) *time.Time {
+	policy, ok := s.policyFor(ctx, tenantID)
+	if !ok {
+		return nil
+	}
+	return retention.Cutoff(policy.MessageRetentionDays, time.Now())
+}
+
+// Redactor returns the redaction helper used before long-term persistence
+func (s *RetentionService) Redactor() *retention.Redactor {
+	return s.redactor
+}
+
+// PurgeTenant applies the tenant's policy now
+func (s *RetentionService) PurgeTenant(ctx context.Context, tenantID kernel.TenantID) (*retention.PurgeResult, error) {
+	policy, err := s.policyRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.purge(ctx, policy, time.Now())
+}
+
+// PurgeExpired applies every tenant policy with a finite window
+func (s *RetentionService) PurgeExpired(ctx context.Context) error {
+	policies, err := s.policyRepo.FindWithRetention(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, policy := range policies {
+		result, err := s.purge(ctx, policy, now)
+		if err != nil {
+			// One tenant failing must not block the others
+			log.Printf("⚠️  Retention purge failed for tenant %s: %v", policy.TenantID, err)
+			continue
+		}
+		if result.Total() > 0 {
+			log.Printf("🗑️  Retention %s for tenant %s: %d messages, %d session messages, %d executions",
+				result.Action, result.TenantID, result.Messages, result.Sessions, result.Executions)
+		}
+	}
+
+	return nil
+}
+
+// policyFor returns the tenant's policy from the hot path cache; false
+// when it could not be loaded
+func (s *RetentionService) policyFor(ctx context.Context, tenantID kernel.TenantID) (*retention.Policy, bool) {
+	s.mu.RLock()
+	cached, ok := s.cache[tenantID]
+	s.mu.RUnlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.policy, true
+	}
+
+	policy, err := s.policyRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load retention policy for tenant %s: %v", tenantID, err)
+		return nil, false
+	}
+
+	s.mu.Lock()
+	s.cache[tenantID] = cachedPolicy{policy: policy, expiresAt: time.Now().Add(policyCacheTTL)}
+	s.mu.Unlock()
+
+	return policy, true
+}
+
+func (s *RetentionService) purge(ctx context.Context, policy *retention.Policy, now time.Time) (*retention.PurgeResult, error) {
+	result := &retention.PurgeResult{
+		TenantID: policy.TenantID,
+		Action:   policy.Action,
+	}
+
+	var err error
+	if cutoff := retention.Cutoff(policy.MessageRetentionDays, now); cutoff != nil {
+		if result.Messages, err = s.purger.PurgeMessages(ctx, policy.TenantID, *cutoff, policy.Action); err != nil {
+			return result, err
+		}
+	}
+	if cutoff := retention.Cutoff(policy.SessionRetentionDays, now); cutoff != nil {
+		if result.Sessions, err = s.purger.PurgeSessions(ctx, policy.TenantID, *cutoff, policy.Action); err != nil {
+			return result, err
+		}
+	}
+	if cutoff := retention.Cutoff(policy.ExecutionRetentionDays, now); cutoff != nil {
+		if result.Executions, err = s.purger.PurgeExecutions(ctx, policy.TenantID, *cutoff, policy.Action); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
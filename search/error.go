@@ -0,0 +1,29 @@
+package search
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("SEARCH")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeInvalidQuery = ErrRegistry.Register("INVALID_QUERY", errx.TypeValidation, http.StatusBadRequest, "Invalid search query")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrInvalidQuery() *errx.Error {
+	return ErrRegistry.New(CodeInvalidQuery)
+}
@@ -0,0 +1,16 @@
+package search
+
+import (
+	"context"
+)
+
+// ============================================================================
+// Index Interfaces
+// ============================================================================
+
+// MessageIndex finds messages by their text
+type MessageIndex interface {
+	// SearchMessages returns a page of the messages matching the query, best
+	// matches first and the most recent among equals
+	SearchMessages(ctx context.Context, query MessageQuery) (MessageHitListResponse, error)
+}
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// MaxQueryLength bounds the text of a search
+const MaxQueryLength = 256
+
+// ============================================================================
+// Message Search
+// ============================================================================
+
+// MessageQuery finds a tenant's messages by their text and caption, web
+// search style: every word is required, "quoted words" must appear in that
+// order, "or" separates alternatives and -word excludes. Words match as
+// written, without stemming; case does not matter. A conversation matches
+// Tags when it carries all of them.
+type MessageQuery struct {
+	storex.PaginationOptions
+
+	TenantID  kernel.TenantID   `json:"tenant_id" validate:"required"`
+	Text      string            `json:"q"`
+	ChannelID *kernel.ChannelID `json:"channel_id,omitempty"`
+	SenderID  string            `json:"sender_id,omitempty"`
+	From      *time.Time        `json:"from,omitempty"`
+	To        *time.Time        `json:"to,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+}
+
+func (q MessageQuery) GetOffset() int {
+	return (q.Page - 1) * q.PageSize
+}
+
+// Validate trims the text and checks the query can run
+func (q *MessageQuery) Validate() error {
+	q.Text = strings.TrimSpace(q.Text)
+	q.SenderID = strings.TrimSpace(q.SenderID)
+
+	if q.Text == "" {
+		return ErrInvalidQuery().WithDetail("q", "is required")
+	}
+	if len(q.Text) > MaxQueryLength {
+		return ErrInvalidQuery().WithDetail("q", "is too long")
+	}
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		return ErrInvalidQuery().WithDetail("to", "must be after from")
+	}
+	return nil
+}
+
+// MessageFilter is the conversation store filter for everything but the text
+func (q MessageQuery) MessageFilter() conversation.MessageFilter {
+	return conversation.MessageFilter{
+		TenantID:  q.TenantID,
+		ChannelID: q.ChannelID,
+		SenderID:  q.SenderID,
+		From:      q.From,
+		To:        q.To,
+		Tags:      q.Tags,
+	}
+}
+
+// MessageHit a matching message, with an excerpt of its text where the
+// matching words are wrapped in <b></b>
+type MessageHit struct {
+	conversation.Message
+	Highlight string  `json:"highlight"`
+	Rank      float64 `json:"rank"`
+}
+
+// MessageHitListResponse paginated search results, best matches first
+type MessageHitListResponse = storex.Paginated[MessageHit]
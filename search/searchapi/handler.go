@@ -0,0 +1,98 @@
+package searchapi
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/search"
+	"github.com/Abraxas-365/relay/search/searchsrv"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// SearchHandler exposes full-text search over conversation transcripts
+type SearchHandler struct {
+	service *searchsrv.SearchService
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(service *searchsrv.SearchService) *SearchHandler {
+	return &SearchHandler{
+		service: service,
+	}
+}
+
+// SearchMessages finds messages by their text, filtered by channel, sender,
+// period (RFC 3339 from/to) and conversation tags
+// GET /api/search/messages?q=invoice 4412&channel_id=&sender_id=&from=&to=&tags=vip,refund
+func (h *SearchHandler) SearchMessages(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	query := search.MessageQuery{
+		PaginationOptions: paginationFromQuery(c),
+		TenantID:          authContext.TenantID,
+		Text:              c.Query("q"),
+		SenderID:          c.Query("sender_id"),
+	}
+	if channelID := c.Query("channel_id"); channelID != "" {
+		id := kernel.NewChannelID(channelID)
+		query.ChannelID = &id
+	}
+	for param, target := range map[string]**time.Time{"from": &query.From, "to": &query.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return search.ErrInvalidQuery().WithDetail(param, "must be an RFC 3339 timestamp")
+		}
+		*target = &parsed
+	}
+	for _, tag := range strings.Split(c.Query("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			query.Tags = append(query.Tags, tag)
+		}
+	}
+
+	hits, err := h.service.SearchMessages(c.Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(hits)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func paginationFromQuery(c *fiber.Ctx) storex.PaginationOptions {
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", defaultPageSize)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	return storex.PaginationOptions{
+		Page:     page,
+		PageSize: pageSize,
+	}
+}
@@ -0,0 +1,24 @@
+package searchapi
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// SearchRoutes handles search route setup
+type SearchRoutes struct {
+	handler *SearchHandler
+}
+
+// NewSearchRoutes creates a new search routes instance
+func NewSearchRoutes(handler *SearchHandler) *SearchRoutes {
+	return &SearchRoutes{
+		handler: handler,
+	}
+}
+
+// RegisterRoutes registers search routes on an authenticated router
+func (r *SearchRoutes) RegisterRoutes(router fiber.Router) {
+	searches := router.Group("/search")
+
+	searches.Get("/messages", r.handler.SearchMessages)
+}
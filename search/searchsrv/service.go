@@ -0,0 +1,24 @@
+package searchsrv
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/search"
+)
+
+// SearchService validates searches and runs them against the index
+type SearchService struct {
+	messages search.MessageIndex
+}
+
+func NewSearchService(messages search.MessageIndex) *SearchService {
+	return &SearchService{messages: messages}
+}
+
+// SearchMessages returns a page of the tenant's messages matching the query
+func (s *SearchService) SearchMessages(ctx context.Context, query search.MessageQuery) (search.MessageHitListResponse, error) {
+	if err := query.Validate(); err != nil {
+		return search.MessageHitListResponse{}, err
+	}
+	return s.messages.SearchMessages(ctx, query)
+}
@@ -0,0 +1,46 @@
+package segment
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/contact"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// SaveSegmentRequest creates a segment or replaces one's definition
+type SaveSegmentRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description,omitempty"`
+	Predicate   string `json:"predicate" validate:"required"`
+}
+
+// ListMembersRequest pages through a segment's members
+type ListMembersRequest struct {
+	storex.PaginationOptions
+
+	TenantID  kernel.TenantID `json:"tenant_id" validate:"required"`
+	SegmentID string          `json:"segment_id" validate:"required"`
+}
+
+func (r ListMembersRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+// EnrollRequest enrolls every member of a segment into a sequence. Members
+// are enrolled by phone; members without one are skipped. ChannelID and
+// Context apply to every enrollment, like in sequence.EnrollRequest.
+type EnrollRequest struct {
+	SequenceID string           `json:"sequence_id" validate:"required"`
+	ChannelID  kernel.ChannelID `json:"channel_id,omitempty"`
+	Context    map[string]any   `json:"context,omitempty"`
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+// MemberListResponse paginated list of a segment's member contacts
+type MemberListResponse = storex.Paginated[contact.Contact]
@@ -0,0 +1,49 @@
+package segment
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("SEGMENT")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeSegmentNotFound        = ErrRegistry.Register("SEGMENT_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Segment not found")
+	CodeInvalidSegment         = ErrRegistry.Register("INVALID_SEGMENT", errx.TypeValidation, http.StatusBadRequest, "Invalid segment")
+	CodeSegmentNameTaken       = ErrRegistry.Register("SEGMENT_NAME_TAKEN", errx.TypeConflict, http.StatusConflict, "Another segment has this name")
+	CodeInvalidPredicate       = ErrRegistry.Register("INVALID_PREDICATE", errx.TypeValidation, http.StatusBadRequest, "Invalid segment predicate")
+	CodeEnrollmentNotAvailable = ErrRegistry.Register("ENROLLMENT_NOT_AVAILABLE", errx.TypeBusiness, http.StatusServiceUnavailable, "Segments cannot be enrolled into sequences")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrSegmentNotFound() *errx.Error {
+	return ErrRegistry.New(CodeSegmentNotFound)
+}
+
+func ErrInvalidSegment() *errx.Error {
+	return ErrRegistry.New(CodeInvalidSegment)
+}
+
+func ErrSegmentNameTaken() *errx.Error {
+	return ErrRegistry.New(CodeSegmentNameTaken)
+}
+
+func ErrInvalidPredicate() *errx.Error {
+	return ErrRegistry.New(CodeInvalidPredicate)
+}
+
+func ErrEnrollmentNotAvailable() *errx.Error {
+	return ErrRegistry.New(CodeEnrollmentNotAvailable)
+}
@@ -0,0 +1,68 @@
+package segment
+
+import (
+	"context"
+	"time"
+
+	"github.com/Abraxas-365/relay/contact"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/sequence"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// SegmentRepository persists segment definitions
+type SegmentRepository interface {
+	// Save creates or updates a segment's definition; ErrSegmentNameTaken
+	// when another segment of the tenant has its name
+	Save(ctx context.Context, segment Segment) error
+
+	// MarkEvaluated records when the segment was last rebuilt
+	MarkEvaluated(ctx context.Context, id string, at time.Time) error
+
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*Segment, error)
+
+	FindByName(ctx context.Context, name string, tenantID kernel.TenantID) (*Segment, error)
+
+	// List returns the tenant's segments by name
+	List(ctx context.Context, tenantID kernel.TenantID) ([]Segment, error)
+
+	// ListStale returns up to limit segments of every tenant that were
+	// never evaluated or last evaluated before the time, oldest first
+	ListStale(ctx context.Context, before time.Time, limit int) ([]Segment, error)
+
+	Delete(ctx context.Context, id string, tenantID kernel.TenantID) error
+}
+
+// MemberRepository persists which contacts are in which segments
+type MemberRepository interface {
+	// ContactIDs returns the IDs of every member of the segment
+	ContactIDs(ctx context.Context, segmentID string) ([]string, error)
+
+	// Add adds contacts to the segment; members already in it are kept
+	Add(ctx context.Context, tenantID kernel.TenantID, segmentID string, contactIDs []string) error
+
+	Remove(ctx context.Context, segmentID string, contactIDs []string) error
+
+	IsMember(ctx context.Context, segmentID, contactID string) (bool, error)
+
+	// List pages through the segment's members, newest member first
+	List(ctx context.Context, req ListMembersRequest) (MemberListResponse, error)
+}
+
+// ============================================================================
+// Service Interfaces
+// ============================================================================
+
+// StatsProvider summarizes the conversations of contacts, keyed by contact
+// ID. Contacts without conversations get zero stats.
+type StatsProvider interface {
+	ConversationStats(ctx context.Context, tenantID kernel.TenantID, contacts []contact.Contact) (map[string]ConversationStats, error)
+}
+
+// SequenceEnroller enrolls a contact into a drip sequence
+type SequenceEnroller interface {
+	Enroll(ctx context.Context, tenantID kernel.TenantID, sequenceID string, req sequence.EnrollRequest) (*sequence.Enrollment, error)
+}
@@ -0,0 +1,150 @@
+package segment
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/relay/contact"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// ============================================================================
+// Predicates
+// ============================================================================
+
+// predicateCostLimit bounds the work of evaluating a predicate on one
+// contact, so a predicate cannot stall a rebuild
+const predicateCostLimit = 100_000
+
+// ConversationStats summarizes a contact's conversations on every channel
+type ConversationStats struct {
+	InboundCount     int
+	OutboundCount    int
+	LastInboundAt    *time.Time // nil = never wrote
+	LastOutboundAt   *time.Time // nil = never written to
+	ConversationTags []string   // Tags and dispositions of its conversations
+}
+
+// Predicate is a compiled segment predicate. A predicate is a CEL
+// expression returning bool over:
+//
+//	contact     map: id, external_id, name, phone, email, created_at
+//	attributes  map of the contact's attributes
+//	tags        list of the contact's tags
+//	stats       map: inbound_count, outbound_count, last_inbound_at,
+//	            last_outbound_at, conversation_tags
+//	now()       the current time
+//
+// Times the contact has no value for are the Unix epoch, so
+//
+//	stats.last_inbound_at > now() - duration("720h") && "vip" in tags
+//
+// matches VIPs who wrote in the last 30 days.
+type Predicate struct {
+	program cel.Program
+}
+
+// predicateEnv declares the variables and functions of predicates
+var predicateEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("contact", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("attributes", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("tags", cel.ListType(cel.StringType)),
+		cel.Variable("stats", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Function("now",
+			cel.Overload("now", []*cel.Type{}, cel.TimestampType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					return types.Timestamp{Time: time.Now()}
+				}),
+			),
+		),
+	)
+})
+
+// Compile parses and type-checks a predicate; ErrInvalidPredicate when it
+// is not a bool expression over the declared variables
+func Compile(expression string) (*Predicate, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, ErrInvalidPredicate().WithDetail("reason", "predicate is required")
+	}
+
+	env, err := predicateEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, ErrInvalidPredicate().WithDetail("reason", issues.Err().Error())
+	}
+	if !ast.OutputType().IsExactType(cel.BoolType) {
+		return nil, ErrInvalidPredicate().
+			WithDetail("reason", "predicate must return bool, not "+ast.OutputType().String())
+	}
+
+	program, err := env.Program(ast, cel.CostLimit(predicateCostLimit))
+	if err != nil {
+		return nil, ErrInvalidPredicate().WithDetail("reason", err.Error())
+	}
+	return &Predicate{program: program}, nil
+}
+
+// Matches evaluates the predicate on a contact. Errors, like a missing map
+// key, are returned; callers leave the contact out.
+func (p *Predicate) Matches(c contact.Contact, stats ConversationStats) (bool, error) {
+	out, _, err := p.program.Eval(activation(c, stats))
+	if err != nil {
+		return false, err
+	}
+	matched, ok := out.Value().(bool)
+	return ok && matched, nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func activation(c contact.Contact, stats ConversationStats) map[string]any {
+	attributes := c.Attributes
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	tags := c.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	conversationTags := stats.ConversationTags
+	if conversationTags == nil {
+		conversationTags = []string{}
+	}
+
+	return map[string]any{
+		"contact": map[string]any{
+			"id":          c.ID,
+			"external_id": c.ExternalID,
+			"name":        c.Name,
+			"phone":       c.Phone,
+			"email":       c.Email,
+			"created_at":  c.CreatedAt,
+		},
+		"attributes": attributes,
+		"tags":       tags,
+		"stats": map[string]any{
+			"inbound_count":     stats.InboundCount,
+			"outbound_count":    stats.OutboundCount,
+			"last_inbound_at":   timeOrEpoch(stats.LastInboundAt),
+			"last_outbound_at":  timeOrEpoch(stats.LastOutboundAt),
+			"conversation_tags": conversationTags,
+		},
+	}
+}
+
+func timeOrEpoch(t *time.Time) time.Time {
+	if t == nil {
+		return time.Unix(0, 0).UTC()
+	}
+	return *t
+}
@@ -0,0 +1,68 @@
+package segment
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Segments
+// ============================================================================
+
+// namePattern matches segment names, as workflows write them in
+// contact.in_segment("vip")
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Segment is the set of a tenant's contacts its predicate matches. Members
+// are materialized: a rebuild evaluates every contact, and a contact is
+// evaluated again when it writes or is imported.
+type Segment struct {
+	ID          string          `json:"id"`
+	TenantID    kernel.TenantID `json:"tenant_id"`
+	Name        string          `json:"name"` // Unique per tenant
+	Description string          `json:"description,omitempty"`
+	Predicate   string          `json:"predicate"` // CEL, see Compile
+	MemberCount int             `json:"member_count"`
+	EvaluatedAt *time.Time      `json:"evaluated_at,omitempty"` // Last rebuild; nil = never
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// NewSegment creates a segment of the tenant with no members yet
+func NewSegment(tenantID kernel.TenantID) *Segment {
+	now := time.Now()
+	return &Segment{
+		ID:        uuid.NewString(),
+		TenantID:  tenantID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// NormalizeName lowercases a segment name
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Validate checks the name and compiles the predicate
+func (s *Segment) Validate() error {
+	if !namePattern.MatchString(s.Name) {
+		return ErrInvalidSegment().
+			WithDetail("name", s.Name).
+			WithDetail("reason", "names are lowercase letters, digits, '-' and '_', up to 64")
+	}
+	_, err := Compile(s.Predicate)
+	return err
+}
+
+// RebuildResult is what a rebuild changed
+type RebuildResult struct {
+	Evaluated int `json:"evaluated"`
+	Added     int `json:"added"`
+	Removed   int `json:"removed"`
+	Failed    int `json:"failed"` // Contacts the predicate failed on, left out
+}
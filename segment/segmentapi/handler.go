@@ -0,0 +1,200 @@
+package segmentapi
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/segment"
+	"github.com/Abraxas-365/relay/segment/segmentsrv"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// SegmentHandler exposes segments and their members
+type SegmentHandler struct {
+	segmentService *segmentsrv.SegmentService
+}
+
+// NewSegmentHandler creates a new segment handler
+func NewSegmentHandler(segmentService *segmentsrv.SegmentService) *SegmentHandler {
+	return &SegmentHandler{segmentService: segmentService}
+}
+
+// ============================================================================
+// Segments
+// ============================================================================
+
+// List returns the tenant's segments
+// GET /api/segments
+func (h *SegmentHandler) List(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	segments, err := h.segmentService.List(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"segments": segments})
+}
+
+// Create defines a segment; its members are computed in the background
+// POST /api/segments
+func (h *SegmentHandler) Create(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req segment.SaveSegmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return segment.ErrInvalidSegment().WithDetail("error", err.Error())
+	}
+
+	seg, err := h.segmentService.Create(c.Context(), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(seg)
+}
+
+// Get returns a segment
+// GET /api/segments/:id
+func (h *SegmentHandler) Get(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	seg, err := h.segmentService.Get(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(seg)
+}
+
+// Update replaces a segment's definition
+// PUT /api/segments/:id
+func (h *SegmentHandler) Update(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req segment.SaveSegmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return segment.ErrInvalidSegment().WithDetail("error", err.Error())
+	}
+
+	seg, err := h.segmentService.Update(c.Context(), c.Params("id"), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(seg)
+}
+
+// Delete removes a segment
+// DELETE /api/segments/:id
+func (h *SegmentHandler) Delete(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := h.segmentService.Delete(c.Context(), c.Params("id"), authContext.TenantID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ============================================================================
+// Members
+// ============================================================================
+
+// ListMembers returns the segment's member contacts
+// GET /api/segments/:id/members
+func (h *SegmentHandler) ListMembers(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	req := segment.ListMembersRequest{
+		PaginationOptions: paginationOptions(c),
+		TenantID:          authContext.TenantID,
+		SegmentID:         c.Params("id"),
+	}
+
+	members, err := h.segmentService.ListMembers(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(members)
+}
+
+// Refresh queues a rebuild of the segment's members
+// POST /api/segments/:id/refresh
+func (h *SegmentHandler) Refresh(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	seg, err := h.segmentService.Refresh(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(seg)
+}
+
+// Enroll queues enrolling the segment's members into a sequence
+// POST /api/segments/:id/enroll
+func (h *SegmentHandler) Enroll(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req segment.EnrollRequest
+	if err := c.BodyParser(&req); err != nil {
+		return segment.ErrInvalidSegment().WithDetail("error", err.Error())
+	}
+
+	seg, err := h.segmentService.Enroll(c.Context(), c.Params("id"), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(seg)
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func paginationOptions(c *fiber.Ctx) storex.PaginationOptions {
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", defaultPageSize)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return storex.PaginationOptions{Page: page, PageSize: pageSize}
+}
@@ -0,0 +1,37 @@
+package segmentapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SegmentRoutes handles segment route setup
+type SegmentRoutes struct {
+	handler        *SegmentHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewSegmentRoutes creates a new segment routes instance
+func NewSegmentRoutes(handler *SegmentHandler, authMiddleware *auth.AuthMiddleware) *SegmentRoutes {
+	return &SegmentRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers segment routes on an authenticated router.
+// Defining segments and enrolling them requires an admin; reading them does
+// not.
+func (r *SegmentRoutes) RegisterRoutes(router fiber.Router) {
+	segments := router.Group("/segments")
+
+	segments.Get("/", r.handler.List)
+	segments.Post("/", r.authMiddleware.RequireAdmin(), r.handler.Create)
+	segments.Get("/:id", r.handler.Get)
+	segments.Put("/:id", r.authMiddleware.RequireAdmin(), r.handler.Update)
+	segments.Delete("/:id", r.authMiddleware.RequireAdmin(), r.handler.Delete)
+
+	segments.Get("/:id/members", r.handler.ListMembers)
+	segments.Post("/:id/refresh", r.authMiddleware.RequireAdmin(), r.handler.Refresh)
+	segments.Post("/:id/enroll", r.authMiddleware.RequireAdmin(), r.handler.Enroll)
+}
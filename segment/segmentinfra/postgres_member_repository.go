@@ -0,0 +1,187 @@
+package segmentinfra
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/contact"
+	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/segment"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// PostgresMemberRepository keeps memberships in the main database. Member
+// contacts are read from the database of the tenant's data region, so
+// memberships have no foreign key to contacts: deleting a contact deletes
+// them explicitly, and a rebuild removes any left behind.
+type PostgresMemberRepository struct {
+	db     *sqlx.DB
+	router *database.Router // nil = every tenant's contacts are in db
+}
+
+var _ segment.MemberRepository = (*PostgresMemberRepository)(nil)
+
+func NewPostgresMemberRepository(db *sqlx.DB) *PostgresMemberRepository {
+	return &PostgresMemberRepository{db: db}
+}
+
+// SetRouter reads member contacts from the database of the tenant's data region
+func (r *PostgresMemberRepository) SetRouter(router *database.Router) {
+	r.router = router
+}
+
+// dbMember is a member contact as read from the contacts table
+type dbMember struct {
+	ID         string          `db:"id"`
+	TenantID   string          `db:"tenant_id"`
+	ExternalID string          `db:"external_id"`
+	Name       string          `db:"name"`
+	Phone      string          `db:"phone"`
+	Email      string          `db:"email"`
+	Attributes json.RawMessage `db:"attributes"`
+	Tags       pq.StringArray  `db:"tags"`
+	CreatedAt  time.Time       `db:"created_at"`
+	UpdatedAt  time.Time       `db:"updated_at"`
+}
+
+func (r *PostgresMemberRepository) ContactIDs(ctx context.Context, segmentID string) ([]string, error) {
+	var ids []string
+	if err := r.db.SelectContext(ctx, &ids, `SELECT contact_id FROM segment_members WHERE segment_id = $1`, segmentID); err != nil {
+		return nil, errx.Wrap(err, "failed to list segment members", errx.TypeInternal).
+			WithDetail("segment_id", segmentID)
+	}
+	return ids, nil
+}
+
+func (r *PostgresMemberRepository) Add(ctx context.Context, tenantID kernel.TenantID, segmentID string, contactIDs []string) error {
+	if len(contactIDs) == 0 {
+		return nil
+	}
+
+	// Contacts deleted since they were read are removed by the next rebuild
+	query := `
+		INSERT INTO segment_members (segment_id, contact_id, tenant_id)
+		SELECT $1, id, $2 FROM unnest($3::text[]) AS id
+		ON CONFLICT (segment_id, contact_id) DO NOTHING`
+
+	if _, err := r.db.ExecContext(ctx, query, segmentID, tenantID.String(), pq.Array(contactIDs)); err != nil {
+		return errx.Wrap(err, "failed to add segment members", errx.TypeInternal).
+			WithDetail("segment_id", segmentID)
+	}
+	return nil
+}
+
+func (r *PostgresMemberRepository) Remove(ctx context.Context, segmentID string, contactIDs []string) error {
+	if len(contactIDs) == 0 {
+		return nil
+	}
+
+	query := `DELETE FROM segment_members WHERE segment_id = $1 AND contact_id = ANY($2)`
+	if _, err := r.db.ExecContext(ctx, query, segmentID, pq.Array(contactIDs)); err != nil {
+		return errx.Wrap(err, "failed to remove segment members", errx.TypeInternal).
+			WithDetail("segment_id", segmentID)
+	}
+	return nil
+}
+
+func (r *PostgresMemberRepository) IsMember(ctx context.Context, segmentID, contactID string) (bool, error) {
+	var member bool
+	query := `SELECT EXISTS (SELECT 1 FROM segment_members WHERE segment_id = $1 AND contact_id = $2)`
+	if err := r.db.GetContext(ctx, &member, query, segmentID, contactID); err != nil {
+		return false, errx.Wrap(err, "failed to check segment membership", errx.TypeInternal).
+			WithDetail("segment_id", segmentID)
+	}
+	return member, nil
+}
+
+func (r *PostgresMemberRepository) List(ctx context.Context, req segment.ListMembersRequest) (segment.MemberListResponse, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM segment_members WHERE segment_id = $1 AND tenant_id = $2`
+	if err := r.db.GetContext(ctx, &total, countQuery, req.SegmentID, req.TenantID.String()); err != nil {
+		return segment.MemberListResponse{}, errx.Wrap(err, "failed to count segment members", errx.TypeInternal)
+	}
+
+	query := `
+		SELECT contact_id FROM segment_members
+		WHERE segment_id = $1 AND tenant_id = $2
+		ORDER BY added_at DESC, contact_id ASC
+		LIMIT $3 OFFSET $4`
+
+	var ids []string
+	if err := r.db.SelectContext(ctx, &ids, query, req.SegmentID, req.TenantID.String(), req.PageSize, req.GetOffset()); err != nil {
+		return segment.MemberListResponse{}, errx.Wrap(err, "failed to list segment members", errx.TypeInternal)
+	}
+
+	rows, err := r.memberContacts(ctx, req.TenantID, ids)
+	if err != nil {
+		return segment.MemberListResponse{}, err
+	}
+
+	// Page order; a contact deleted since it joined is skipped
+	contacts := make([]contact.Contact, 0, len(ids))
+	for _, id := range ids {
+		row, ok := rows[id]
+		if !ok {
+			continue
+		}
+
+		c := contact.Contact{
+			ID:         row.ID,
+			TenantID:   kernel.TenantID(row.TenantID),
+			ExternalID: row.ExternalID,
+			Name:       row.Name,
+			Phone:      row.Phone,
+			Email:      row.Email,
+			Attributes: map[string]string{},
+			Tags:       []string(row.Tags),
+			CreatedAt:  row.CreatedAt,
+			UpdatedAt:  row.UpdatedAt,
+		}
+		if len(row.Attributes) > 0 {
+			if err := json.Unmarshal(row.Attributes, &c.Attributes); err != nil {
+				return segment.MemberListResponse{}, errx.Wrap(err, "failed to unmarshal contact attributes", errx.TypeInternal).
+					WithDetail("contact_id", row.ID)
+			}
+		}
+		contacts = append(contacts, c)
+	}
+
+	return storex.NewPaginated(contacts, req.Page, req.PageSize, total), nil
+}
+
+// memberContacts reads the contacts by ID from the tenant's database
+func (r *PostgresMemberRepository) memberContacts(ctx context.Context, tenantID kernel.TenantID, ids []string) (map[string]dbMember, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	db := r.db
+	if r.router != nil {
+		var err error
+		if db, err = r.router.For(ctx, tenantID); err != nil {
+			return nil, errx.Wrap(err, "failed to resolve tenant database", errx.TypeInternal).
+				WithDetail("tenant_id", tenantID.String())
+		}
+	}
+
+	query := `
+		SELECT id, tenant_id, external_id, name, phone, email, attributes, tags, created_at, updated_at
+		FROM contacts
+		WHERE tenant_id = $1 AND id = ANY($2)`
+
+	var rows []dbMember
+	if err := db.SelectContext(ctx, &rows, query, tenantID.String(), pq.Array(ids)); err != nil {
+		return nil, errx.Wrap(err, "failed to read segment member contacts", errx.TypeInternal)
+	}
+
+	byID := make(map[string]dbMember, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = row
+	}
+	return byID, nil
+}
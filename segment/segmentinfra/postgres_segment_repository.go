@@ -0,0 +1,174 @@
+package segmentinfra
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/segment"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PostgresSegmentRepository struct {
+	db *sqlx.DB
+}
+
+var _ segment.SegmentRepository = (*PostgresSegmentRepository)(nil)
+
+func NewPostgresSegmentRepository(db *sqlx.DB) *PostgresSegmentRepository {
+	return &PostgresSegmentRepository{db: db}
+}
+
+// dbSegment is an intermediate struct for database operations
+type dbSegment struct {
+	ID          string       `db:"id"`
+	TenantID    string       `db:"tenant_id"`
+	Name        string       `db:"name"`
+	Description string       `db:"description"`
+	Predicate   string       `db:"predicate"`
+	MemberCount int          `db:"member_count"`
+	EvaluatedAt sql.NullTime `db:"evaluated_at"`
+	CreatedAt   time.Time    `db:"created_at"`
+	UpdatedAt   time.Time    `db:"updated_at"`
+}
+
+const segmentColumns = `
+	s.id, s.tenant_id, s.name, s.description, s.predicate,
+	(SELECT COUNT(*) FROM segment_members m WHERE m.segment_id = s.id) AS member_count,
+	s.evaluated_at, s.created_at, s.updated_at`
+
+func (r *PostgresSegmentRepository) Save(ctx context.Context, seg segment.Segment) error {
+	query := `
+		INSERT INTO segments (id, tenant_id, name, description, predicate, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			predicate = EXCLUDED.predicate`
+
+	_, err := r.db.ExecContext(ctx, query,
+		seg.ID, seg.TenantID.String(), seg.Name, seg.Description, seg.Predicate, seg.CreatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return segment.ErrSegmentNameTaken().WithDetail("name", seg.Name)
+		}
+		return errx.Wrap(err, "failed to save segment", errx.TypeInternal).
+			WithDetail("segment_id", seg.ID)
+	}
+
+	return nil
+}
+
+func (r *PostgresSegmentRepository) MarkEvaluated(ctx context.Context, id string, at time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE segments SET evaluated_at = $2 WHERE id = $1`, id, at); err != nil {
+		return errx.Wrap(err, "failed to mark segment evaluated", errx.TypeInternal).
+			WithDetail("segment_id", id)
+	}
+	return nil
+}
+
+func (r *PostgresSegmentRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*segment.Segment, error) {
+	query := `SELECT ` + segmentColumns + ` FROM segments s WHERE s.id = $1 AND s.tenant_id = $2`
+
+	var row dbSegment
+	if err := r.db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, segment.ErrSegmentNotFound().WithDetail("segment_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find segment", errx.TypeInternal).
+			WithDetail("segment_id", id)
+	}
+
+	return toDomainSegment(&row), nil
+}
+
+func (r *PostgresSegmentRepository) FindByName(ctx context.Context, name string, tenantID kernel.TenantID) (*segment.Segment, error) {
+	query := `SELECT ` + segmentColumns + ` FROM segments s WHERE s.name = $1 AND s.tenant_id = $2`
+
+	var row dbSegment
+	if err := r.db.GetContext(ctx, &row, query, name, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, segment.ErrSegmentNotFound().WithDetail("name", name)
+		}
+		return nil, errx.Wrap(err, "failed to find segment", errx.TypeInternal).
+			WithDetail("name", name)
+	}
+
+	return toDomainSegment(&row), nil
+}
+
+func (r *PostgresSegmentRepository) List(ctx context.Context, tenantID kernel.TenantID) ([]segment.Segment, error) {
+	query := `SELECT ` + segmentColumns + ` FROM segments s WHERE s.tenant_id = $1 ORDER BY s.name ASC`
+
+	var rows []dbSegment
+	if err := r.db.SelectContext(ctx, &rows, query, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to list segments", errx.TypeInternal)
+	}
+
+	return toDomainSegments(rows), nil
+}
+
+func (r *PostgresSegmentRepository) ListStale(ctx context.Context, before time.Time, limit int) ([]segment.Segment, error) {
+	query := `SELECT ` + segmentColumns + ` FROM segments s
+		WHERE s.evaluated_at IS NULL OR s.evaluated_at < $1
+		ORDER BY s.evaluated_at ASC NULLS FIRST
+		LIMIT $2`
+
+	var rows []dbSegment
+	if err := r.db.SelectContext(ctx, &rows, query, before, limit); err != nil {
+		return nil, errx.Wrap(err, "failed to list stale segments", errx.TypeInternal)
+	}
+
+	return toDomainSegments(rows), nil
+}
+
+func (r *PostgresSegmentRepository) Delete(ctx context.Context, id string, tenantID kernel.TenantID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM segments WHERE id = $1 AND tenant_id = $2`, id, tenantID.String())
+	if err != nil {
+		return errx.Wrap(err, "failed to delete segment", errx.TypeInternal).
+			WithDetail("segment_id", id)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+	if rows == 0 {
+		return segment.ErrSegmentNotFound().WithDetail("segment_id", id)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func toDomainSegment(row *dbSegment) *segment.Segment {
+	seg := &segment.Segment{
+		ID:          row.ID,
+		TenantID:    kernel.TenantID(row.TenantID),
+		Name:        row.Name,
+		Description: row.Description,
+		Predicate:   row.Predicate,
+		MemberCount: row.MemberCount,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+	if row.EvaluatedAt.Valid {
+		seg.EvaluatedAt = &row.EvaluatedAt.Time
+	}
+	return seg
+}
+
+func toDomainSegments(rows []dbSegment) []segment.Segment {
+	segments := make([]segment.Segment, 0, len(rows))
+	for i := range rows {
+		segments = append(segments, *toDomainSegment(&rows[i]))
+	}
+	return segments
+}
@@ -0,0 +1,124 @@
+package segmentinfra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/contact"
+	"github.com/Abraxas-365/relay/pkg/database"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/segment"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// PostgresStatsProvider summarizes contacts' conversations from the
+// messages and conversation_tags tables. A contact's conversations are the
+// ones keyed by any of its contact.ConversationIDs, on every channel.
+type PostgresStatsProvider struct {
+	db     *sqlx.DB
+	router *database.Router // nil = every tenant's conversations are in db
+}
+
+var _ segment.StatsProvider = (*PostgresStatsProvider)(nil)
+
+func NewPostgresStatsProvider(db *sqlx.DB) *PostgresStatsProvider {
+	return &PostgresStatsProvider{db: db}
+}
+
+// SetRouter reads each tenant's conversations from the database of its data region
+func (p *PostgresStatsProvider) SetRouter(router *database.Router) {
+	p.router = router
+}
+
+type dbMessageStats struct {
+	ContactID      string       `db:"contact_id"`
+	InboundCount   int          `db:"inbound_count"`
+	OutboundCount  int          `db:"outbound_count"`
+	LastInboundAt  sql.NullTime `db:"last_inbound_at"`
+	LastOutboundAt sql.NullTime `db:"last_outbound_at"`
+}
+
+type dbTagStats struct {
+	ContactID string         `db:"contact_id"`
+	Tags      pq.StringArray `db:"tags"`
+}
+
+// conversationKeys pairs every conversation ID with the contact it is
+// one of, so both queries join the messages of a whole batch at once
+const conversationKeys = `
+	WITH keys AS (
+		SELECT * FROM unnest($2::text[], $3::text[]) AS k(conversation_id, contact_id)
+	)`
+
+func (p *PostgresStatsProvider) ConversationStats(ctx context.Context, tenantID kernel.TenantID, contacts []contact.Contact) (map[string]segment.ConversationStats, error) {
+	stats := make(map[string]segment.ConversationStats, len(contacts))
+	var conversationIDs, contactIDs []string
+	for _, c := range contacts {
+		stats[c.ID] = segment.ConversationStats{}
+		for _, id := range c.ConversationIDs() {
+			conversationIDs = append(conversationIDs, id)
+			contactIDs = append(contactIDs, c.ID)
+		}
+	}
+	if len(conversationIDs) == 0 {
+		return stats, nil
+	}
+
+	db := p.db
+	if p.router != nil {
+		var err error
+		if db, err = p.router.For(ctx, tenantID); err != nil {
+			return nil, errx.Wrap(err, "failed to resolve tenant database", errx.TypeInternal).
+				WithDetail("tenant_id", tenantID.String())
+		}
+	}
+
+	messageQuery := conversationKeys + `
+		SELECT k.contact_id,
+			COUNT(*) FILTER (WHERE m.direction = 'INBOUND') AS inbound_count,
+			COUNT(*) FILTER (WHERE m.direction = 'OUTBOUND') AS outbound_count,
+			MAX(m.created_at) FILTER (WHERE m.direction = 'INBOUND') AS last_inbound_at,
+			MAX(m.created_at) FILTER (WHERE m.direction = 'OUTBOUND') AS last_outbound_at
+		FROM keys k
+		JOIN messages m ON m.tenant_id = $1 AND m.conversation_id = k.conversation_id
+		GROUP BY k.contact_id`
+
+	var messageRows []dbMessageStats
+	if err := db.SelectContext(ctx, &messageRows, messageQuery,
+		tenantID.String(), pq.Array(conversationIDs), pq.Array(contactIDs)); err != nil {
+		return nil, errx.Wrap(err, "failed to summarize contact messages", errx.TypeInternal)
+	}
+	for _, row := range messageRows {
+		s := stats[row.ContactID]
+		s.InboundCount = row.InboundCount
+		s.OutboundCount = row.OutboundCount
+		if row.LastInboundAt.Valid {
+			s.LastInboundAt = &row.LastInboundAt.Time
+		}
+		if row.LastOutboundAt.Valid {
+			s.LastOutboundAt = &row.LastOutboundAt.Time
+		}
+		stats[row.ContactID] = s
+	}
+
+	tagQuery := conversationKeys + `
+		SELECT k.contact_id, array_agg(DISTINCT t.tag) AS tags
+		FROM keys k
+		JOIN conversation_tags t ON t.tenant_id = $1 AND t.conversation_id = k.conversation_id
+		GROUP BY k.contact_id`
+
+	var tagRows []dbTagStats
+	if err := db.SelectContext(ctx, &tagRows, tagQuery,
+		tenantID.String(), pq.Array(conversationIDs), pq.Array(contactIDs)); err != nil {
+		return nil, errx.Wrap(err, "failed to summarize contact conversation tags", errx.TypeInternal)
+	}
+	for _, row := range tagRows {
+		s := stats[row.ContactID]
+		s.ConversationTags = []string(row.Tags)
+		stats[row.ContactID] = s
+	}
+
+	return stats, nil
+}
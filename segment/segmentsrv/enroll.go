@@ -0,0 +1,136 @@
+package segmentsrv
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/contact"
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/segment"
+	"github.com/Abraxas-365/relay/sequence"
+)
+
+// EnrollJobKind is the background job that enrolls a segment's members
+// into a sequence
+const EnrollJobKind = "segment.enroll"
+
+const (
+	// EnrollTimeout bounds a single enroll job
+	EnrollTimeout = 30 * time.Minute
+
+	// enrollAttempts is how many times an enroll job is tried; a retry
+	// skips the members enrolled before
+	enrollAttempts = 2
+)
+
+// Enroll queues enrolling every current member of the segment into a drip
+// sequence, making the segment the sequence's audience
+func (s *SegmentService) Enroll(ctx context.Context, id string, tenantID kernel.TenantID, req segment.EnrollRequest) (*segment.Segment, error) {
+	if s.enroller == nil {
+		return nil, segment.ErrEnrollmentNotAvailable()
+	}
+	if req.SequenceID == "" {
+		return nil, segment.ErrInvalidSegment().WithDetail("sequence_id", "is required")
+	}
+
+	seg, err := s.segmentRepo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.queue.Enqueue(ctx, jobs.EnqueueRequest{
+		Kind:     EnrollJobKind,
+		TenantID: &tenantID,
+		Payload: enrollPayload{
+			SegmentID:  seg.ID,
+			SequenceID: req.SequenceID,
+			ChannelID:  req.ChannelID,
+			Context:    req.Context,
+		},
+		MaxAttempts: enrollAttempts,
+	}); err != nil {
+		return nil, err
+	}
+
+	return seg, nil
+}
+
+// RunEnrollJob is the handler of EnrollJobKind. Members are enrolled by
+// phone, the ID their WhatsApp and SMS conversations have; members without
+// one, already in the sequence or opted out are skipped. A missing or
+// inactive sequence fails the job.
+func (s *SegmentService) RunEnrollJob(ctx context.Context, job jobs.Job) error {
+	var payload enrollPayload
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+	if job.TenantID == nil {
+		return jobs.ErrInvalidJob().WithDetail("job_id", job.ID).WithDetail("reason", "tenant_id is required")
+	}
+	if s.enroller == nil {
+		return segment.ErrEnrollmentNotAvailable()
+	}
+	tenantID := *job.TenantID
+
+	seg, err := s.segmentRepo.FindByID(ctx, payload.SegmentID, tenantID)
+	if err != nil {
+		return err
+	}
+	memberIDs, err := s.memberRepo.ContactIDs(ctx, seg.ID)
+	if err != nil {
+		return err
+	}
+
+	enrolled, skipped := 0, 0
+	for _, contactID := range memberIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		c, err := s.contactRepo.FindByID(ctx, contactID, tenantID)
+		if err != nil {
+			if errx.IsCode(err, contact.CodeContactNotFound) {
+				skipped++
+				continue
+			}
+			return err
+		}
+		if c.Phone == "" {
+			skipped++
+			continue
+		}
+
+		_, err = s.enroller.Enroll(ctx, tenantID, payload.SequenceID, sequence.EnrollRequest{
+			ContactID: strings.TrimPrefix(c.Phone, "+"),
+			ChannelID: payload.ChannelID,
+			Context:   payload.Context,
+		})
+		switch {
+		case err == nil:
+			enrolled++
+		case errx.IsCode(err, sequence.CodeSequenceNotFound), errx.IsCode(err, sequence.CodeSequenceInactive):
+			return err
+		default:
+			skipped++
+			if !errx.IsCode(err, sequence.CodeAlreadyEnrolled) {
+				log.Printf("⚠️  Failed to enroll contact %s of segment %s: %v", c.ID, seg.Name, err)
+			}
+		}
+	}
+
+	log.Printf("🎯 Segment %s enrolled into sequence %s: %d enrolled, %d skipped",
+		seg.Name, payload.SequenceID, enrolled, skipped)
+	return nil
+}
+
+// enrollPayload is the payload of an EnrollJobKind job
+type enrollPayload struct {
+	SegmentID  string           `json:"segment_id"`
+	SequenceID string           `json:"sequence_id"`
+	ChannelID  kernel.ChannelID `json:"channel_id,omitempty"`
+	Context    map[string]any   `json:"context,omitempty"`
+}
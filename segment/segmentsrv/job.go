@@ -0,0 +1,179 @@
+package segmentsrv
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/segment"
+)
+
+const (
+	// RebuildJobKind is the background job that rebuilds one segment
+	RebuildJobKind = "segment.rebuild"
+
+	// RefreshJobKind is the recurring system job that rebuilds the segments
+	// evaluated longest ago
+	RefreshJobKind = "segment.refresh"
+)
+
+const (
+	// RebuildTimeout bounds a single rebuild or refresh job
+	RebuildTimeout = 30 * time.Minute
+
+	// rebuildBatch is how many contacts are evaluated per query
+	rebuildBatch = 500
+
+	// staleAfter is how long a segment goes without a rebuild before the
+	// refresh job rebuilds it
+	staleAfter = time.Hour
+
+	// refreshBatch bounds the segments rebuilt per refresh run
+	refreshBatch = 50
+)
+
+// RunRebuildJob is the handler of RebuildJobKind. A segment deleted since
+// it was queued is skipped.
+func (s *SegmentService) RunRebuildJob(ctx context.Context, job jobs.Job) error {
+	var payload rebuildPayload
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+	if job.TenantID == nil {
+		return jobs.ErrInvalidJob().WithDetail("job_id", job.ID).WithDetail("reason", "tenant_id is required")
+	}
+
+	seg, err := s.segmentRepo.FindByID(ctx, payload.SegmentID, *job.TenantID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Rebuild(ctx, seg)
+	return err
+}
+
+// RunRefreshJob is the handler of RefreshJobKind. Rebuilds run one after
+// the other; a failing segment does not hold back the rest.
+func (s *SegmentService) RunRefreshJob(ctx context.Context, _ jobs.Job) error {
+	segments, err := s.segmentRepo.ListStale(ctx, time.Now().Add(-staleAfter), refreshBatch)
+	if err != nil {
+		return err
+	}
+
+	for i := range segments {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := s.Rebuild(ctx, &segments[i]); err != nil {
+			log.Printf("⚠️  Failed to refresh segment %s of tenant %s: %v", segments[i].Name, segments[i].TenantID, err)
+		}
+	}
+	return nil
+}
+
+// Rebuild evaluates every contact of the tenant and writes only the
+// membership changes, so members keep the time they were added
+func (s *SegmentService) Rebuild(ctx context.Context, seg *segment.Segment) (*segment.RebuildResult, error) {
+	predicate, err := s.predicate(seg)
+	if err != nil {
+		return nil, err
+	}
+
+	startedAt := time.Now()
+	memberIDs, err := s.memberRepo.ContactIDs(ctx, seg.ID)
+	if err != nil {
+		return nil, err
+	}
+	// What is left once every contact was seen are members whose contact is
+	// gone
+	stale := make(map[string]struct{}, len(memberIDs))
+	for _, id := range memberIDs {
+		stale[id] = struct{}{}
+	}
+
+	result := &segment.RebuildResult{}
+	afterID := ""
+	for {
+		batch, err := s.contactRepo.ListAfter(ctx, seg.TenantID, afterID, rebuildBatch)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		stats, err := s.stats.ConversationStats(ctx, seg.TenantID, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		var added, removed []string
+		for _, c := range batch {
+			_, member := stale[c.ID]
+			delete(stale, c.ID)
+
+			matched, err := predicate.Matches(c, stats[c.ID])
+			if err != nil {
+				result.Failed++
+			}
+			switch {
+			case matched && !member:
+				added = append(added, c.ID)
+			case !matched && member:
+				removed = append(removed, c.ID)
+			}
+		}
+
+		if err := s.memberRepo.Add(ctx, seg.TenantID, seg.ID, added); err != nil {
+			return nil, err
+		}
+		if err := s.memberRepo.Remove(ctx, seg.ID, removed); err != nil {
+			return nil, err
+		}
+		result.Evaluated += len(batch)
+		result.Added += len(added)
+		result.Removed += len(removed)
+
+		if len(batch) < rebuildBatch {
+			break
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+
+	gone := make([]string, 0, len(stale))
+	for id := range stale {
+		gone = append(gone, id)
+	}
+	if err := s.memberRepo.Remove(ctx, seg.ID, gone); err != nil {
+		return nil, err
+	}
+	result.Removed += len(gone)
+
+	if err := s.segmentRepo.MarkEvaluated(ctx, seg.ID, startedAt); err != nil {
+		return nil, err
+	}
+
+	log.Printf("🎯 Segment %s of tenant %s rebuilt: %d contacts, +%d -%d members, %d failed",
+		seg.Name, seg.TenantID, result.Evaluated, result.Added, result.Removed, result.Failed)
+	return result, nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// rebuildPayload is the payload of a RebuildJobKind job
+type rebuildPayload struct {
+	SegmentID string `json:"segment_id"`
+}
+
+func (s *SegmentService) enqueueRebuild(ctx context.Context, seg *segment.Segment) error {
+	tenantID := seg.TenantID
+	_, err := s.queue.Enqueue(ctx, jobs.EnqueueRequest{
+		Kind:     RebuildJobKind,
+		TenantID: &tenantID,
+		Payload:  rebuildPayload{SegmentID: seg.ID},
+	})
+	return err
+}
@@ -0,0 +1,278 @@
+package segmentsrv
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/contact"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/segment"
+)
+
+// SegmentService manages segments and keeps their members in sync: saving
+// a segment rebuilds it in the background, an inbound message re-evaluates
+// its sender, an import rebuilds the tenant's segments, and an hourly job
+// rebuilds the rest so time-based predicates catch up.
+type SegmentService struct {
+	segmentRepo segment.SegmentRepository
+	memberRepo  segment.MemberRepository
+	contactRepo contact.ContactRepository
+	stats       segment.StatsProvider
+	queue       jobs.Queue
+	enroller    segment.SequenceEnroller // nil = segments cannot be enrolled
+
+	mu         sync.Mutex
+	predicates map[string]compiledPredicate // By segment ID
+}
+
+var (
+	_ channels.InboundListener = (*SegmentService)(nil)
+	_ contact.ImportObserver   = (*SegmentService)(nil)
+	_ engine.SegmentChecker    = (*SegmentService)(nil)
+)
+
+func NewSegmentService(
+	segmentRepo segment.SegmentRepository,
+	memberRepo segment.MemberRepository,
+	contactRepo contact.ContactRepository,
+	stats segment.StatsProvider,
+	queue jobs.Queue,
+) *SegmentService {
+	return &SegmentService{
+		segmentRepo: segmentRepo,
+		memberRepo:  memberRepo,
+		contactRepo: contactRepo,
+		stats:       stats,
+		queue:       queue,
+		predicates:  make(map[string]compiledPredicate),
+	}
+}
+
+// SetEnroller lets segments be enrolled into drip sequences
+func (s *SegmentService) SetEnroller(enroller segment.SequenceEnroller) {
+	s.enroller = enroller
+}
+
+// ============================================================================
+// Segments
+// ============================================================================
+
+// Create saves a segment and queues its first rebuild
+func (s *SegmentService) Create(ctx context.Context, tenantID kernel.TenantID, req segment.SaveSegmentRequest) (*segment.Segment, error) {
+	seg := segment.NewSegment(tenantID)
+	seg.Name = segment.NormalizeName(req.Name)
+	seg.Description = strings.TrimSpace(req.Description)
+	seg.Predicate = strings.TrimSpace(req.Predicate)
+	if err := seg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.segmentRepo.Save(ctx, *seg); err != nil {
+		return nil, err
+	}
+	if err := s.enqueueRebuild(ctx, seg); err != nil {
+		return nil, err
+	}
+
+	log.Printf("🎯 Segment %s created for tenant %s", seg.Name, tenantID)
+	return seg, nil
+}
+
+// Update replaces a segment's definition; a new predicate queues a rebuild
+func (s *SegmentService) Update(ctx context.Context, id string, tenantID kernel.TenantID, req segment.SaveSegmentRequest) (*segment.Segment, error) {
+	seg, err := s.segmentRepo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := seg.Predicate
+	seg.Name = segment.NormalizeName(req.Name)
+	seg.Description = strings.TrimSpace(req.Description)
+	seg.Predicate = strings.TrimSpace(req.Predicate)
+	if err := seg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.segmentRepo.Save(ctx, *seg); err != nil {
+		return nil, err
+	}
+	if seg.Predicate != previous {
+		if err := s.enqueueRebuild(ctx, seg); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.segmentRepo.FindByID(ctx, id, tenantID)
+}
+
+func (s *SegmentService) Get(ctx context.Context, id string, tenantID kernel.TenantID) (*segment.Segment, error) {
+	return s.segmentRepo.FindByID(ctx, id, tenantID)
+}
+
+// List returns the tenant's segments by name
+func (s *SegmentService) List(ctx context.Context, tenantID kernel.TenantID) ([]segment.Segment, error) {
+	return s.segmentRepo.List(ctx, tenantID)
+}
+
+func (s *SegmentService) Delete(ctx context.Context, id string, tenantID kernel.TenantID) error {
+	if err := s.segmentRepo.Delete(ctx, id, tenantID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.predicates, id)
+	s.mu.Unlock()
+	return nil
+}
+
+// ListMembers pages through a segment's member contacts
+func (s *SegmentService) ListMembers(ctx context.Context, req segment.ListMembersRequest) (segment.MemberListResponse, error) {
+	if _, err := s.segmentRepo.FindByID(ctx, req.SegmentID, req.TenantID); err != nil {
+		return segment.MemberListResponse{}, err
+	}
+	return s.memberRepo.List(ctx, req)
+}
+
+// Refresh queues a rebuild of the segment
+func (s *SegmentService) Refresh(ctx context.Context, id string, tenantID kernel.TenantID) (*segment.Segment, error) {
+	seg, err := s.segmentRepo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.enqueueRebuild(ctx, seg); err != nil {
+		return nil, err
+	}
+	return seg, nil
+}
+
+// ============================================================================
+// Membership
+// ============================================================================
+
+// InSegment implements engine.SegmentChecker. Conversations without a
+// contact are in no segment; an unknown segment is an error so a misspelled
+// name fails the workflow instead of silently being false.
+func (s *SegmentService) InSegment(ctx context.Context, tenantID kernel.TenantID, conversationID, name string) (bool, error) {
+	seg, err := s.segmentRepo.FindByName(ctx, segment.NormalizeName(name), tenantID)
+	if err != nil {
+		return false, err
+	}
+
+	c, err := s.contactRepo.FindByIdentity(ctx, tenantID, contact.IdentityOf(conversationID))
+	if err != nil {
+		if errx.IsCode(err, contact.CodeContactNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return s.memberRepo.IsMember(ctx, seg.ID, c.ID)
+}
+
+// OnInboundMessage implements channels.InboundListener: the sender's
+// contact is re-evaluated against every segment of the tenant. The message
+// may not be stored yet, so it is taken as the last inbound one; its count
+// catches up on the next rebuild.
+func (s *SegmentService) OnInboundMessage(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage) {
+	if msg.SenderID == "" {
+		return
+	}
+
+	segments, err := s.segmentRepo.List(ctx, channel.TenantID)
+	if err != nil {
+		log.Printf("⚠️  Failed to list segments of tenant %s: %v", channel.TenantID, err)
+		return
+	}
+	if len(segments) == 0 {
+		return
+	}
+
+	c, err := s.contactRepo.FindByIdentity(ctx, channel.TenantID, contact.IdentityOf(msg.SenderID))
+	if err != nil {
+		if !errx.IsCode(err, contact.CodeContactNotFound) {
+			log.Printf("⚠️  Failed to find contact of %s: %v", msg.SenderID, err)
+		}
+		return
+	}
+
+	stats, err := s.stats.ConversationStats(ctx, channel.TenantID, []contact.Contact{*c})
+	if err != nil {
+		log.Printf("⚠️  Failed to summarize conversations of contact %s: %v", c.ID, err)
+		return
+	}
+	contactStats := stats[c.ID]
+	now := time.Now()
+	if contactStats.LastInboundAt == nil || contactStats.LastInboundAt.Before(now) {
+		contactStats.LastInboundAt = &now
+	}
+
+	for i := range segments {
+		if err := s.evaluateContact(ctx, &segments[i], *c, contactStats); err != nil {
+			log.Printf("⚠️  Failed to re-evaluate contact %s in segment %s: %v", c.ID, segments[i].Name, err)
+		}
+	}
+}
+
+// ContactsImported implements contact.ImportObserver: every segment of the
+// tenant is rebuilt, since an import may touch any number of contacts
+func (s *SegmentService) ContactsImported(ctx context.Context, imp contact.Import) {
+	segments, err := s.segmentRepo.List(ctx, imp.TenantID)
+	if err != nil {
+		log.Printf("⚠️  Failed to list segments of tenant %s: %v", imp.TenantID, err)
+		return
+	}
+
+	for i := range segments {
+		if err := s.enqueueRebuild(ctx, &segments[i]); err != nil {
+			log.Printf("⚠️  Failed to queue rebuild of segment %s: %v", segments[i].Name, err)
+		}
+	}
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// compiledPredicate caches a segment's predicate until it changes
+type compiledPredicate struct {
+	source    string
+	predicate *segment.Predicate
+}
+
+func (s *SegmentService) predicate(seg *segment.Segment) (*segment.Predicate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cached, ok := s.predicates[seg.ID]; ok && cached.source == seg.Predicate {
+		return cached.predicate, nil
+	}
+
+	predicate, err := segment.Compile(seg.Predicate)
+	if err != nil {
+		return nil, err
+	}
+	s.predicates[seg.ID] = compiledPredicate{source: seg.Predicate, predicate: predicate}
+	return predicate, nil
+}
+
+// evaluateContact adds the contact to the segment or removes it from it.
+// A predicate that fails on the contact leaves it out.
+func (s *SegmentService) evaluateContact(ctx context.Context, seg *segment.Segment, c contact.Contact, stats segment.ConversationStats) error {
+	predicate, err := s.predicate(seg)
+	if err != nil {
+		return err
+	}
+
+	matched, _ := predicate.Matches(c, stats)
+	if matched {
+		return s.memberRepo.Add(ctx, seg.TenantID, seg.ID, []string{c.ID})
+	}
+	return s.memberRepo.Remove(ctx, seg.ID, []string{c.ID})
+}
@@ -0,0 +1,82 @@
+package sequence
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// SaveSequenceRequest creates or replaces a sequence definition. Steps of a
+// replaced sequence apply to contacts already in it by position.
+type SaveSequenceRequest struct {
+	Name        string           `json:"name" validate:"required"`
+	Description string           `json:"description,omitempty"`
+	ChannelID   kernel.ChannelID `json:"channel_id" validate:"required"`
+	Steps       []Step           `json:"steps" validate:"required,min=1"`
+	ExitOnReply *bool            `json:"exit_on_reply,omitempty"` // Defaults to true
+	IsActive    *bool            `json:"is_active,omitempty"`     // Defaults to true
+}
+
+// EnrollRequest puts a contact into a sequence. ChannelID defaults to the
+// sequence's channel; Context is available to step texts and workflows.
+type EnrollRequest struct {
+	ContactID string           `json:"contact_id" validate:"required"`
+	ChannelID kernel.ChannelID `json:"channel_id,omitempty"`
+	Context   map[string]any   `json:"context,omitempty"`
+}
+
+type ListEnrollmentsRequest struct {
+	storex.PaginationOptions
+	TenantID   kernel.TenantID   `json:"tenant_id" validate:"required"`
+	SequenceID string            `json:"sequence_id" validate:"required"`
+	Status     *EnrollmentStatus `json:"status,omitempty"`
+}
+
+func (r ListEnrollmentsRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+type EnrollmentListResponse = storex.Paginated[Enrollment]
+
+// EnrollmentCount is a group of enrollments as stored
+type EnrollmentCount struct {
+	Status     EnrollmentStatus `db:"status"`
+	ExitReason ExitReason       `db:"exit_reason"`
+	NextStep   int              `db:"next_step"`
+	Count      int              `db:"count"`
+}
+
+// StepRunCount is a group of step runs as stored
+type StepRunCount struct {
+	StepID string        `db:"step_id"`
+	Status StepRunStatus `db:"status"`
+	Count  int           `db:"count"`
+}
+
+// StepStats is how one step performed. Waiting are contacts it is due for
+// next; Exited left while waiting for it.
+type StepStats struct {
+	StepID  string         `json:"step_id"`
+	Type    StepType       `json:"type"`
+	Sent    int            `json:"sent"`
+	Failed  int            `json:"failed"`
+	Waiting int            `json:"waiting"`
+	Exited  map[string]int `json:"exited"`
+}
+
+// SequenceStats summarizes a sequence's enrollments and steps
+type SequenceStats struct {
+	SequenceID string         `json:"sequence_id"`
+	Enrolled   int            `json:"enrolled"`
+	Active     int            `json:"active"`
+	Completed  int            `json:"completed"`
+	Exited     map[string]int `json:"exited"`
+	Steps      []StepStats    `json:"steps"`
+}
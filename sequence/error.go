@@ -0,0 +1,59 @@
+package sequence
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("SEQUENCE")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeSequenceNotFound   = ErrRegistry.Register("SEQUENCE_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Sequence not found")
+	CodeInvalidSequence    = ErrRegistry.Register("INVALID_SEQUENCE", errx.TypeValidation, http.StatusBadRequest, "Invalid sequence")
+	CodeSequenceNameTaken  = ErrRegistry.Register("SEQUENCE_NAME_TAKEN", errx.TypeConflict, http.StatusConflict, "A sequence with this name already exists")
+	CodeSequenceInactive   = ErrRegistry.Register("SEQUENCE_INACTIVE", errx.TypeBusiness, http.StatusConflict, "Sequence is not active")
+	CodeEnrollmentNotFound = ErrRegistry.Register("ENROLLMENT_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Enrollment not found")
+	CodeAlreadyEnrolled    = ErrRegistry.Register("ALREADY_ENROLLED", errx.TypeConflict, http.StatusConflict, "Contact is already in the sequence")
+	CodeInvalidEnrollment  = ErrRegistry.Register("INVALID_ENROLLMENT", errx.TypeValidation, http.StatusBadRequest, "Invalid enrollment")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrSequenceNotFound() *errx.Error {
+	return ErrRegistry.New(CodeSequenceNotFound)
+}
+
+func ErrInvalidSequence() *errx.Error {
+	return ErrRegistry.New(CodeInvalidSequence)
+}
+
+func ErrSequenceNameTaken() *errx.Error {
+	return ErrRegistry.New(CodeSequenceNameTaken)
+}
+
+func ErrSequenceInactive() *errx.Error {
+	return ErrRegistry.New(CodeSequenceInactive)
+}
+
+func ErrEnrollmentNotFound() *errx.Error {
+	return ErrRegistry.New(CodeEnrollmentNotFound)
+}
+
+func ErrAlreadyEnrolled() *errx.Error {
+	return ErrRegistry.New(CodeAlreadyEnrolled)
+}
+
+func ErrInvalidEnrollment() *errx.Error {
+	return ErrRegistry.New(CodeInvalidEnrollment)
+}
@@ -0,0 +1,59 @@
+package sequence
+
+import (
+	"context"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// SequenceRepository persists sequence definitions
+type SequenceRepository interface {
+	// Save creates or replaces a sequence; names are unique per tenant
+	Save(ctx context.Context, seq Sequence) error
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*Sequence, error)
+	List(ctx context.Context, tenantID kernel.TenantID) ([]*Sequence, error)
+
+	// Delete removes the sequence with its enrollments and stats
+	Delete(ctx context.Context, id string, tenantID kernel.TenantID) error
+}
+
+// EnrollmentRepository persists contacts' progress through sequences and the
+// steps run for them
+type EnrollmentRepository interface {
+	// Create enrolls a contact; ErrAlreadyEnrolled when the contact is
+	// already active in the sequence
+	Create(ctx context.Context, enrollment Enrollment) error
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*Enrollment, error)
+	List(ctx context.Context, req ListEnrollmentsRequest) (EnrollmentListResponse, error)
+
+	// ClaimDue returns active enrollments of active sequences whose next step
+	// is due and pushes their NextStepAt to leaseUntil, so other instances
+	// skip them while the step runs
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*Enrollment, error)
+
+	// Advance stores the progress made by running step, unless the enrollment
+	// moved on meanwhile (it exited, or another run advanced it). Reports
+	// whether it was stored.
+	Advance(ctx context.Context, enrollment Enrollment, step int) (bool, error)
+
+	// Exit ends an active enrollment early. Reports whether it was active.
+	Exit(ctx context.Context, id string, tenantID kernel.TenantID, reason ExitReason) (bool, error)
+
+	// ExitOnReply ends the contact's active enrollments on the channel in
+	// sequences that exit on reply, and returns how many
+	ExitOnReply(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, contactID string) (int64, error)
+
+	RecordStepRun(ctx context.Context, run StepRun) error
+
+	// CountEnrollments groups the sequence's enrollments by status, exit
+	// reason and next step
+	CountEnrollments(ctx context.Context, sequenceID string, tenantID kernel.TenantID) ([]EnrollmentCount, error)
+
+	// CountStepRuns groups the sequence's step runs by step and outcome
+	CountStepRuns(ctx context.Context, sequenceID string, tenantID kernel.TenantID) ([]StepRunCount, error)
+}
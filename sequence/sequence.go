@@ -0,0 +1,199 @@
+package sequence
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Sequences
+// ============================================================================
+
+// MaxSteps bounds the steps of one sequence
+const MaxSteps = 50
+
+// MaxStepOffset is the latest a step can run after enrollment
+const MaxStepOffset = 365 * 24 * time.Hour
+
+// StepType is what a step does when it is due
+type StepType string
+
+const (
+	StepSendMessage     StepType = "send_message"     // Sends Text (or a provider template) to the contact
+	StepTriggerWorkflow StepType = "trigger_workflow" // Runs WorkflowID with the contact as trigger
+)
+
+// IsValid checks the step type is known
+func (t StepType) IsValid() bool {
+	return t == StepSendMessage || t == StepTriggerWorkflow
+}
+
+// Step is one message of a drip sequence. AfterSeconds is measured from
+// enrollment, so "day 0, day 2, day 7" is 0, 172800 and 604800.
+type Step struct {
+	ID           string             `json:"id"`
+	Type         StepType           `json:"type"`
+	AfterSeconds int64              `json:"after_seconds"`
+	Text         string             `json:"text,omitempty"`        // send_message, may use {{contact.id}} or {{context.<key>}}
+	TemplateID   string             `json:"template_id,omitempty"` // send_message, for providers that require templates
+	Variables    map[string]string  `json:"variables,omitempty"`   // send_message, template variables
+	WorkflowID   *kernel.WorkflowID `json:"workflow_id,omitempty"` // trigger_workflow
+}
+
+// After is the step's offset from enrollment
+func (s Step) After() time.Duration {
+	return time.Duration(s.AfterSeconds) * time.Second
+}
+
+// Sequence is a series of timed steps a contact goes through after being
+// enrolled. Contacts leave when the last step ran, when they reply (if
+// ExitOnReply) or when they opt out.
+type Sequence struct {
+	ID          string           `db:"id" json:"id"`
+	TenantID    kernel.TenantID  `db:"tenant_id" json:"tenant_id"`
+	Name        string           `db:"name" json:"name"`
+	Description string           `db:"description" json:"description,omitempty"`
+	ChannelID   kernel.ChannelID `db:"channel_id" json:"channel_id"`
+	Steps       []Step           `db:"-" json:"steps"`
+	ExitOnReply bool             `db:"exit_on_reply" json:"exit_on_reply"`
+	IsActive    bool             `db:"is_active" json:"is_active"`
+	CreatedAt   time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+// Validate checks the steps can run in order
+func (s *Sequence) Validate() error {
+	if s.Name == "" {
+		return ErrInvalidSequence().WithDetail("reason", "name is required")
+	}
+	if s.ChannelID.IsEmpty() {
+		return ErrInvalidSequence().WithDetail("reason", "channel_id is required")
+	}
+	if len(s.Steps) == 0 || len(s.Steps) > MaxSteps {
+		return ErrInvalidSequence().
+			WithDetail("reason", fmt.Sprintf("a sequence needs between 1 and %d steps", MaxSteps))
+	}
+
+	seen := make(map[string]bool, len(s.Steps))
+	var previous int64
+	for i, step := range s.Steps {
+		if step.ID == "" || seen[step.ID] {
+			return ErrInvalidSequence().
+				WithDetail("step", i).
+				WithDetail("reason", "step ids are required and must be unique")
+		}
+		seen[step.ID] = true
+
+		if step.AfterSeconds < previous || step.After() > MaxStepOffset {
+			return ErrInvalidSequence().
+				WithDetail("step_id", step.ID).
+				WithDetail("reason", "after_seconds must not decrease and be at most a year")
+		}
+		previous = step.AfterSeconds
+
+		switch step.Type {
+		case StepSendMessage:
+			if step.Text == "" && step.TemplateID == "" {
+				return ErrInvalidSequence().
+					WithDetail("step_id", step.ID).
+					WithDetail("reason", "text or template_id is required")
+			}
+		case StepTriggerWorkflow:
+			if step.WorkflowID == nil || step.WorkflowID.IsEmpty() {
+				return ErrInvalidSequence().
+					WithDetail("step_id", step.ID).
+					WithDetail("reason", "workflow_id is required")
+			}
+		default:
+			return ErrInvalidSequence().
+				WithDetail("step_id", step.ID).
+				WithDetail("type", string(step.Type))
+		}
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Enrollments
+// ============================================================================
+
+// EnrollmentStatus is where a contact is in a sequence
+type EnrollmentStatus string
+
+const (
+	EnrollmentActive    EnrollmentStatus = "ACTIVE"    // Waiting for its next step
+	EnrollmentCompleted EnrollmentStatus = "COMPLETED" // Every step ran
+	EnrollmentExited    EnrollmentStatus = "EXITED"    // Left early, see ExitReason
+)
+
+// IsValid checks the status is known
+func (s EnrollmentStatus) IsValid() bool {
+	return s == EnrollmentActive || s == EnrollmentCompleted || s == EnrollmentExited
+}
+
+// ExitReason is why a contact left a sequence early
+type ExitReason string
+
+const (
+	ExitReplied  ExitReason = "replied"   // The contact sent a message
+	ExitOptedOut ExitReason = "opted_out" // The contact is on the suppression list
+	ExitManual   ExitReason = "manual"    // Removed through the API
+)
+
+// Enrollment is one contact going through a sequence. NextStep is the index
+// of the step that runs at NextStepAt.
+type Enrollment struct {
+	ID         string           `json:"id"`
+	TenantID   kernel.TenantID  `json:"tenant_id"`
+	SequenceID string           `json:"sequence_id"`
+	ContactID  string           `json:"contact_id"`
+	ChannelID  kernel.ChannelID `json:"channel_id"`
+	Context    map[string]any   `json:"context,omitempty"`
+	Status     EnrollmentStatus `json:"status"`
+	ExitReason ExitReason       `json:"exit_reason,omitempty"`
+	NextStep   int              `json:"next_step"`
+	NextStepAt *time.Time       `json:"next_step_at,omitempty"`
+	EnrolledAt time.Time        `json:"enrolled_at"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// Advance moves the enrollment past its current step, completing it after
+// the last one
+func (e *Enrollment) Advance(seq *Sequence, now time.Time) {
+	e.NextStep++
+	e.UpdatedAt = now
+
+	if e.NextStep >= len(seq.Steps) {
+		e.Status = EnrollmentCompleted
+		e.NextStepAt = nil
+		e.FinishedAt = &now
+		return
+	}
+
+	next := e.EnrolledAt.Add(seq.Steps[e.NextStep].After())
+	e.NextStepAt = &next
+}
+
+// StepRunStatus is the outcome of running a step for one contact
+type StepRunStatus string
+
+const (
+	StepRunSent   StepRunStatus = "SENT"   // Message sent or workflow run
+	StepRunFailed StepRunStatus = "FAILED" // The send or the workflow failed; the contact moves on
+)
+
+// StepRun records a step that ran for an enrollment, for per-step stats
+type StepRun struct {
+	ID           string          `json:"id"`
+	TenantID     kernel.TenantID `json:"tenant_id"`
+	SequenceID   string          `json:"sequence_id"`
+	EnrollmentID string          `json:"enrollment_id"`
+	StepID       string          `json:"step_id"`
+	Status       StepRunStatus   `json:"status"`
+	Error        string          `json:"error,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
@@ -0,0 +1,222 @@
+package sequenceapi
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/sequence"
+	"github.com/Abraxas-365/relay/sequence/sequencesrv"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// SequenceHandler exposes drip sequences, their enrollments and stats
+type SequenceHandler struct {
+	service *sequencesrv.SequenceService
+}
+
+// NewSequenceHandler creates a new sequence handler
+func NewSequenceHandler(service *sequencesrv.SequenceService) *SequenceHandler {
+	return &SequenceHandler{
+		service: service,
+	}
+}
+
+// ============================================================================
+// Sequences
+// ============================================================================
+
+// List returns the tenant's sequences
+// GET /api/sequences
+func (h *SequenceHandler) List(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	sequences, err := h.service.List(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"sequences": sequences})
+}
+
+// Create defines a new sequence
+// POST /api/sequences
+func (h *SequenceHandler) Create(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req sequence.SaveSequenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sequence.ErrInvalidSequence().WithDetail("reason", err.Error())
+	}
+
+	seq, err := h.service.Create(c.Context(), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(seq)
+}
+
+// Get returns a sequence with its steps
+// GET /api/sequences/:id
+func (h *SequenceHandler) Get(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	seq, err := h.service.Get(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(seq)
+}
+
+// Update replaces a sequence's definition
+// PUT /api/sequences/:id
+func (h *SequenceHandler) Update(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req sequence.SaveSequenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sequence.ErrInvalidSequence().WithDetail("reason", err.Error())
+	}
+
+	seq, err := h.service.Update(c.Context(), c.Params("id"), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(seq)
+}
+
+// Delete removes a sequence with its enrollments
+// DELETE /api/sequences/:id
+func (h *SequenceHandler) Delete(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := h.service.Delete(c.Context(), c.Params("id"), authContext.TenantID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Stats returns enrollment counts and per-step outcomes
+// GET /api/sequences/:id/stats
+func (h *SequenceHandler) Stats(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	stats, err := h.service.Stats(c.Context(), authContext.TenantID, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(stats)
+}
+
+// ============================================================================
+// Enrollments
+// ============================================================================
+
+// Enroll puts a contact into the sequence
+// POST /api/sequences/:id/enrollments
+func (h *SequenceHandler) Enroll(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req sequence.EnrollRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sequence.ErrInvalidEnrollment().WithDetail("reason", err.Error())
+	}
+
+	enrollment, err := h.service.Enroll(c.Context(), authContext.TenantID, c.Params("id"), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(enrollment)
+}
+
+// ListEnrollments returns the sequence's enrollments, newest first
+// GET /api/sequences/:id/enrollments?status=ACTIVE&page=1&page_size=50
+func (h *SequenceHandler) ListEnrollments(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", defaultPageSize)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	req := sequence.ListEnrollmentsRequest{
+		PaginationOptions: storex.PaginationOptions{
+			Page:     page,
+			PageSize: pageSize,
+		},
+		TenantID:   authContext.TenantID,
+		SequenceID: c.Params("id"),
+	}
+
+	if status := c.Query("status"); status != "" {
+		s := sequence.EnrollmentStatus(status)
+		if !s.IsValid() {
+			return sequence.ErrInvalidEnrollment().WithDetail("status", status)
+		}
+		req.Status = &s
+	}
+
+	enrollments, err := h.service.ListEnrollments(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(enrollments)
+}
+
+// Unenroll removes a contact from the sequence
+// DELETE /api/sequences/:id/enrollments/:enrollmentId
+func (h *SequenceHandler) Unenroll(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	enrollment, err := h.service.Unenroll(c.Context(), authContext.TenantID, c.Params("id"), c.Params("enrollmentId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(enrollment)
+}
@@ -0,0 +1,37 @@
+package sequenceapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SequenceRoutes handles sequence route setup
+type SequenceRoutes struct {
+	handler        *SequenceHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewSequenceRoutes creates a new sequence routes instance
+func NewSequenceRoutes(handler *SequenceHandler, authMiddleware *auth.AuthMiddleware) *SequenceRoutes {
+	return &SequenceRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers sequence routes on an authenticated router.
+// Defining sequences requires an admin; enrolling contacts does not.
+func (r *SequenceRoutes) RegisterRoutes(router fiber.Router) {
+	sequences := router.Group("/sequences")
+
+	sequences.Get("/", r.handler.List)
+	sequences.Post("/", r.authMiddleware.RequireAdmin(), r.handler.Create)
+	sequences.Get("/:id", r.handler.Get)
+	sequences.Put("/:id", r.authMiddleware.RequireAdmin(), r.handler.Update)
+	sequences.Delete("/:id", r.authMiddleware.RequireAdmin(), r.handler.Delete)
+	sequences.Get("/:id/stats", r.handler.Stats)
+
+	sequences.Get("/:id/enrollments", r.handler.ListEnrollments)
+	sequences.Post("/:id/enrollments", r.handler.Enroll)
+	sequences.Delete("/:id/enrollments/:enrollmentId", r.handler.Unenroll)
+}
@@ -0,0 +1,336 @@
+package sequenceinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/sequence"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PostgresEnrollmentRepository struct {
+	db *sqlx.DB
+}
+
+var _ sequence.EnrollmentRepository = (*PostgresEnrollmentRepository)(nil)
+
+func NewPostgresEnrollmentRepository(db *sqlx.DB) *PostgresEnrollmentRepository {
+	return &PostgresEnrollmentRepository{db: db}
+}
+
+// dbEnrollment is an intermediate struct for database operations
+type dbEnrollment struct {
+	ID         string          `db:"id"`
+	TenantID   string          `db:"tenant_id"`
+	SequenceID string          `db:"sequence_id"`
+	ContactID  string          `db:"contact_id"`
+	ChannelID  string          `db:"channel_id"`
+	Context    json.RawMessage `db:"context"`
+	Status     string          `db:"status"`
+	ExitReason string          `db:"exit_reason"`
+	NextStep   int             `db:"next_step"`
+	NextStepAt *time.Time      `db:"next_step_at"`
+	EnrolledAt time.Time       `db:"enrolled_at"`
+	FinishedAt *time.Time      `db:"finished_at"`
+	UpdatedAt  time.Time       `db:"updated_at"`
+}
+
+const enrollmentColumns = `
+	id, tenant_id, sequence_id, contact_id, channel_id, context, status,
+	exit_reason, next_step, next_step_at, enrolled_at, finished_at, updated_at`
+
+func (r *PostgresEnrollmentRepository) Create(ctx context.Context, enrollment sequence.Enrollment) error {
+	contextJSON, err := json.Marshal(orEmpty(enrollment.Context))
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal enrollment context", errx.TypeInternal)
+	}
+
+	query := `
+		INSERT INTO sequence_enrollments (
+			id, tenant_id, sequence_id, contact_id, channel_id, context, status,
+			next_step, next_step_at, enrolled_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		enrollment.ID, enrollment.TenantID.String(), enrollment.SequenceID, enrollment.ContactID,
+		enrollment.ChannelID.String(), contextJSON, string(enrollment.Status),
+		enrollment.NextStep, enrollment.NextStepAt, enrollment.EnrolledAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return sequence.ErrAlreadyEnrolled().
+				WithDetail("sequence_id", enrollment.SequenceID).
+				WithDetail("contact_id", enrollment.ContactID)
+		}
+		return errx.Wrap(err, "failed to create enrollment", errx.TypeInternal).
+			WithDetail("sequence_id", enrollment.SequenceID)
+	}
+
+	return nil
+}
+
+func (r *PostgresEnrollmentRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*sequence.Enrollment, error) {
+	query := `SELECT ` + enrollmentColumns + ` FROM sequence_enrollments WHERE id = $1 AND tenant_id = $2`
+
+	var row dbEnrollment
+	if err := r.db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sequence.ErrEnrollmentNotFound().WithDetail("enrollment_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find enrollment", errx.TypeInternal).
+			WithDetail("enrollment_id", id)
+	}
+
+	return toDomainEnrollment(&row)
+}
+
+func (r *PostgresEnrollmentRepository) List(ctx context.Context, req sequence.ListEnrollmentsRequest) (sequence.EnrollmentListResponse, error) {
+	conditions := []string{"tenant_id = $1", "sequence_id = $2"}
+	args := []any{req.TenantID.String(), req.SequenceID}
+	argPos := 3
+
+	if req.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argPos))
+		args = append(args, string(*req.Status))
+		argPos++
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM sequence_enrollments WHERE %s", whereClause)
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return sequence.EnrollmentListResponse{}, errx.Wrap(err, "failed to count enrollments", errx.TypeInternal)
+	}
+
+	dataQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM sequence_enrollments
+		WHERE %s
+		ORDER BY enrolled_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`,
+		enrollmentColumns, whereClause, argPos, argPos+1)
+
+	args = append(args, req.PageSize, req.GetOffset())
+
+	var rows []dbEnrollment
+	if err := r.db.SelectContext(ctx, &rows, dataQuery, args...); err != nil {
+		return sequence.EnrollmentListResponse{}, errx.Wrap(err, "failed to list enrollments", errx.TypeInternal)
+	}
+
+	enrollments := make([]sequence.Enrollment, 0, len(rows))
+	for i := range rows {
+		enrollment, err := toDomainEnrollment(&rows[i])
+		if err != nil {
+			return sequence.EnrollmentListResponse{}, err
+		}
+		enrollments = append(enrollments, *enrollment)
+	}
+
+	return storex.NewPaginated(enrollments, req.Page, req.PageSize, total), nil
+}
+
+// ============================================================================
+// Step Processing
+// ============================================================================
+
+func (r *PostgresEnrollmentRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*sequence.Enrollment, error) {
+	// SKIP LOCKED lets several instances claim disjoint batches
+	query := `
+		UPDATE sequence_enrollments
+		SET next_step_at = $2, updated_at = NOW()
+		WHERE id IN (
+			SELECT e.id
+			FROM sequence_enrollments e
+			JOIN sequences s ON s.id = e.sequence_id
+			WHERE e.status = 'ACTIVE'
+				AND e.next_step_at <= $1
+				AND s.is_active = true
+			ORDER BY e.next_step_at ASC
+			LIMIT $3
+			FOR UPDATE OF e SKIP LOCKED
+		)
+		RETURNING ` + enrollmentColumns
+
+	var rows []dbEnrollment
+	if err := r.db.SelectContext(ctx, &rows, query, now, leaseUntil, limit); err != nil {
+		return nil, errx.Wrap(err, "failed to claim due enrollments", errx.TypeInternal)
+	}
+
+	enrollments := make([]*sequence.Enrollment, 0, len(rows))
+	for i := range rows {
+		enrollment, err := toDomainEnrollment(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		enrollments = append(enrollments, enrollment)
+	}
+
+	return enrollments, nil
+}
+
+func (r *PostgresEnrollmentRepository) Advance(ctx context.Context, enrollment sequence.Enrollment, step int) (bool, error) {
+	query := `
+		UPDATE sequence_enrollments
+		SET status = $1, next_step = $2, next_step_at = $3, finished_at = $4, updated_at = NOW()
+		WHERE id = $5 AND status = 'ACTIVE' AND next_step = $6`
+
+	result, err := r.db.ExecContext(ctx, query,
+		string(enrollment.Status), enrollment.NextStep, enrollment.NextStepAt, enrollment.FinishedAt,
+		enrollment.ID, step,
+	)
+	if err != nil {
+		return false, errx.Wrap(err, "failed to advance enrollment", errx.TypeInternal).
+			WithDetail("enrollment_id", enrollment.ID)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *PostgresEnrollmentRepository) Exit(ctx context.Context, id string, tenantID kernel.TenantID, reason sequence.ExitReason) (bool, error) {
+	query := `
+		UPDATE sequence_enrollments
+		SET status = 'EXITED', exit_reason = $1, next_step_at = NULL, finished_at = NOW(), updated_at = NOW()
+		WHERE id = $2 AND tenant_id = $3 AND status = 'ACTIVE'`
+
+	result, err := r.db.ExecContext(ctx, query, string(reason), id, tenantID.String())
+	if err != nil {
+		return false, errx.Wrap(err, "failed to exit enrollment", errx.TypeInternal).
+			WithDetail("enrollment_id", id)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *PostgresEnrollmentRepository) ExitOnReply(
+	ctx context.Context,
+	tenantID kernel.TenantID,
+	channelID kernel.ChannelID,
+	contactID string,
+) (int64, error) {
+	query := `
+		UPDATE sequence_enrollments e
+		SET status = 'EXITED', exit_reason = $1, next_step_at = NULL, finished_at = NOW(), updated_at = NOW()
+		FROM sequences s
+		WHERE s.id = e.sequence_id
+			AND s.exit_on_reply = true
+			AND e.tenant_id = $2
+			AND e.channel_id = $3
+			AND e.contact_id = $4
+			AND e.status = 'ACTIVE'`
+
+	result, err := r.db.ExecContext(ctx, query, string(sequence.ExitReplied), tenantID.String(), channelID.String(), contactID)
+	if err != nil {
+		return 0, errx.Wrap(err, "failed to exit enrollments on reply", errx.TypeInternal).
+			WithDetail("contact_id", contactID)
+	}
+
+	return result.RowsAffected()
+}
+
+// ============================================================================
+// Stats
+// ============================================================================
+
+func (r *PostgresEnrollmentRepository) RecordStepRun(ctx context.Context, run sequence.StepRun) error {
+	query := `
+		INSERT INTO sequence_step_runs (
+			id, tenant_id, sequence_id, enrollment_id, step_id, status, error, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		run.ID, run.TenantID.String(), run.SequenceID, run.EnrollmentID, run.StepID,
+		string(run.Status), run.Error, run.CreatedAt,
+	)
+	if err != nil {
+		return errx.Wrap(err, "failed to record step run", errx.TypeInternal).
+			WithDetail("enrollment_id", run.EnrollmentID)
+	}
+
+	return nil
+}
+
+func (r *PostgresEnrollmentRepository) CountEnrollments(ctx context.Context, sequenceID string, tenantID kernel.TenantID) ([]sequence.EnrollmentCount, error) {
+	query := `
+		SELECT status, exit_reason, next_step, COUNT(*) AS count
+		FROM sequence_enrollments
+		WHERE sequence_id = $1 AND tenant_id = $2
+		GROUP BY status, exit_reason, next_step`
+
+	var counts []sequence.EnrollmentCount
+	if err := r.db.SelectContext(ctx, &counts, query, sequenceID, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to count enrollments", errx.TypeInternal).
+			WithDetail("sequence_id", sequenceID)
+	}
+
+	return counts, nil
+}
+
+func (r *PostgresEnrollmentRepository) CountStepRuns(ctx context.Context, sequenceID string, tenantID kernel.TenantID) ([]sequence.StepRunCount, error) {
+	query := `
+		SELECT step_id, status, COUNT(*) AS count
+		FROM sequence_step_runs
+		WHERE sequence_id = $1 AND tenant_id = $2
+		GROUP BY step_id, status`
+
+	var counts []sequence.StepRunCount
+	if err := r.db.SelectContext(ctx, &counts, query, sequenceID, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to count step runs", errx.TypeInternal).
+			WithDetail("sequence_id", sequenceID)
+	}
+
+	return counts, nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func toDomainEnrollment(row *dbEnrollment) (*sequence.Enrollment, error) {
+	enrollment := &sequence.Enrollment{
+		ID:         row.ID,
+		TenantID:   kernel.TenantID(row.TenantID),
+		SequenceID: row.SequenceID,
+		ContactID:  row.ContactID,
+		ChannelID:  kernel.ChannelID(row.ChannelID),
+		Status:     sequence.EnrollmentStatus(row.Status),
+		ExitReason: sequence.ExitReason(row.ExitReason),
+		NextStep:   row.NextStep,
+		NextStepAt: row.NextStepAt,
+		EnrolledAt: row.EnrolledAt,
+		FinishedAt: row.FinishedAt,
+		UpdatedAt:  row.UpdatedAt,
+	}
+
+	if err := json.Unmarshal(row.Context, &enrollment.Context); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal enrollment context", errx.TypeInternal).
+			WithDetail("enrollment_id", row.ID)
+	}
+
+	return enrollment, nil
+}
+
+func orEmpty(m map[string]any) map[string]any {
+	if m == nil {
+		return map[string]any{}
+	}
+	return m
+}
@@ -0,0 +1,154 @@
+package sequenceinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/sequence"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PostgresSequenceRepository struct {
+	db *sqlx.DB
+}
+
+var _ sequence.SequenceRepository = (*PostgresSequenceRepository)(nil)
+
+func NewPostgresSequenceRepository(db *sqlx.DB) *PostgresSequenceRepository {
+	return &PostgresSequenceRepository{db: db}
+}
+
+// dbSequence is an intermediate struct for database operations
+type dbSequence struct {
+	ID          string          `db:"id"`
+	TenantID    string          `db:"tenant_id"`
+	Name        string          `db:"name"`
+	Description string          `db:"description"`
+	ChannelID   string          `db:"channel_id"`
+	Steps       json.RawMessage `db:"steps"`
+	ExitOnReply bool            `db:"exit_on_reply"`
+	IsActive    bool            `db:"is_active"`
+	CreatedAt   time.Time       `db:"created_at"`
+	UpdatedAt   time.Time       `db:"updated_at"`
+}
+
+const sequenceColumns = `
+	id, tenant_id, name, description, channel_id, steps,
+	exit_on_reply, is_active, created_at, updated_at`
+
+func (r *PostgresSequenceRepository) Save(ctx context.Context, seq sequence.Sequence) error {
+	steps, err := json.Marshal(seq.Steps)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal sequence steps", errx.TypeInternal)
+	}
+
+	query := `
+		INSERT INTO sequences (
+			id, tenant_id, name, description, channel_id, steps,
+			exit_on_reply, is_active, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			channel_id = EXCLUDED.channel_id,
+			steps = EXCLUDED.steps,
+			exit_on_reply = EXCLUDED.exit_on_reply,
+			is_active = EXCLUDED.is_active`
+
+	_, err = r.db.ExecContext(ctx, query,
+		seq.ID, seq.TenantID.String(), seq.Name, seq.Description, seq.ChannelID.String(), steps,
+		seq.ExitOnReply, seq.IsActive, seq.CreatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return sequence.ErrSequenceNameTaken().WithDetail("name", seq.Name)
+		}
+		return errx.Wrap(err, "failed to save sequence", errx.TypeInternal).
+			WithDetail("sequence_id", seq.ID)
+	}
+
+	return nil
+}
+
+func (r *PostgresSequenceRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*sequence.Sequence, error) {
+	query := `SELECT ` + sequenceColumns + ` FROM sequences WHERE id = $1 AND tenant_id = $2`
+
+	var row dbSequence
+	if err := r.db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sequence.ErrSequenceNotFound().WithDetail("sequence_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find sequence", errx.TypeInternal).
+			WithDetail("sequence_id", id)
+	}
+
+	return toDomainSequence(&row)
+}
+
+func (r *PostgresSequenceRepository) List(ctx context.Context, tenantID kernel.TenantID) ([]*sequence.Sequence, error) {
+	query := `SELECT ` + sequenceColumns + ` FROM sequences WHERE tenant_id = $1 ORDER BY name ASC`
+
+	var rows []dbSequence
+	if err := r.db.SelectContext(ctx, &rows, query, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to list sequences", errx.TypeInternal)
+	}
+
+	sequences := make([]*sequence.Sequence, 0, len(rows))
+	for i := range rows {
+		seq, err := toDomainSequence(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		sequences = append(sequences, seq)
+	}
+
+	return sequences, nil
+}
+
+func (r *PostgresSequenceRepository) Delete(ctx context.Context, id string, tenantID kernel.TenantID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM sequences WHERE id = $1 AND tenant_id = $2`, id, tenantID.String())
+	if err != nil {
+		return errx.Wrap(err, "failed to delete sequence", errx.TypeInternal).
+			WithDetail("sequence_id", id)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+	if rows == 0 {
+		return sequence.ErrSequenceNotFound().WithDetail("sequence_id", id)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func toDomainSequence(row *dbSequence) (*sequence.Sequence, error) {
+	seq := &sequence.Sequence{
+		ID:          row.ID,
+		TenantID:    kernel.TenantID(row.TenantID),
+		Name:        row.Name,
+		Description: row.Description,
+		ChannelID:   kernel.ChannelID(row.ChannelID),
+		ExitOnReply: row.ExitOnReply,
+		IsActive:    row.IsActive,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+
+	if err := json.Unmarshal(row.Steps, &seq.Steps); err != nil {
+		return nil, errx.Wrap(err, "failed to unmarshal sequence steps", errx.TypeInternal).
+			WithDetail("sequence_id", row.ID)
+	}
+
+	return seq, nil
+}
@@ -0,0 +1,24 @@
+package sequencesrv
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/jobs"
+)
+
+// StepsJobKind is the recurring system job that runs due sequence steps
+const StepsJobKind = "sequence.process_due"
+
+// RunStepsJob is the handler of StepsJobKind. It keeps going while full
+// batches come back so a backlog drains in one run.
+func (s *SequenceService) RunStepsJob(ctx context.Context, _ jobs.Job) error {
+	for {
+		ran, err := s.ProcessDue(ctx)
+		if err != nil {
+			return err
+		}
+		if ran < claimBatch {
+			return nil
+		}
+	}
+}
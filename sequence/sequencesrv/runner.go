@@ -0,0 +1,206 @@
+package sequencesrv
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/sequence"
+	"github.com/google/uuid"
+)
+
+const (
+	// claimBatch is how many due steps one pass claims
+	claimBatch = 100
+
+	// stepLease is how long a claimed step is hidden from other instances.
+	// A step still running after it may be claimed again.
+	stepLease = 5 * time.Minute
+
+	// stepConcurrency bounds the steps running at once
+	stepConcurrency = 10
+)
+
+// ProcessDue runs every step that is due and returns how many ran
+func (s *SequenceService) ProcessDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	enrollments, err := s.enrollmentRepo.ClaimDue(ctx, now, now.Add(stepLease), claimBatch)
+	if err != nil {
+		return 0, err
+	}
+	if len(enrollments) == 0 {
+		return 0, nil
+	}
+
+	log.Printf("📬 Running %d due sequence step(s)", len(enrollments))
+
+	sem := make(chan struct{}, stepConcurrency)
+	var wg sync.WaitGroup
+	for _, enrollment := range enrollments {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(enrollment *sequence.Enrollment) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.runStep(ctx, enrollment)
+		}(enrollment)
+	}
+	wg.Wait()
+
+	return len(enrollments), nil
+}
+
+// runStep runs the enrollment's next step and moves it on. A failed step is
+// recorded and skipped so one bad send does not stall the sequence.
+func (s *SequenceService) runStep(ctx context.Context, enrollment *sequence.Enrollment) {
+	seq, err := s.sequenceRepo.FindByID(ctx, enrollment.SequenceID, enrollment.TenantID)
+	if err != nil {
+		log.Printf("❌ Sequence %s of enrollment %s: %v", enrollment.SequenceID, enrollment.ID, err)
+		return
+	}
+
+	index := enrollment.NextStep
+	if index >= len(seq.Steps) {
+		// Steps were removed after the contact passed them
+		s.advance(ctx, seq, enrollment, index)
+		return
+	}
+	step := seq.Steps[index]
+
+	suppressed, err := s.isSuppressed(ctx, enrollment.TenantID, enrollment.ChannelID, enrollment.ContactID)
+	if err != nil {
+		log.Printf("⚠️  Suppression check failed for %s, retrying later: %v", enrollment.ContactID, err)
+		return
+	}
+	if suppressed {
+		if _, err := s.enrollmentRepo.Exit(ctx, enrollment.ID, enrollment.TenantID, sequence.ExitOptedOut); err != nil {
+			log.Printf("❌ Failed to exit opted-out enrollment %s: %v", enrollment.ID, err)
+		}
+		return
+	}
+
+	run := sequence.StepRun{
+		ID:           uuid.NewString(),
+		TenantID:     enrollment.TenantID,
+		SequenceID:   seq.ID,
+		EnrollmentID: enrollment.ID,
+		StepID:       step.ID,
+		Status:       sequence.StepRunSent,
+		CreatedAt:    time.Now(),
+	}
+
+	switch step.Type {
+	case sequence.StepSendMessage:
+		err = s.sendMessage(ctx, seq, step, enrollment)
+	case sequence.StepTriggerWorkflow:
+		err = s.triggerWorkflow(ctx, seq, step, enrollment)
+	default:
+		err = fmt.Errorf("unknown step type: %s", step.Type)
+	}
+	if err != nil {
+		log.Printf("⚠️  Sequence %s step %s failed for %s: %v", seq.Name, step.ID, enrollment.ContactID, err)
+		run.Status = sequence.StepRunFailed
+		run.Error = err.Error()
+	}
+
+	if err := s.enrollmentRepo.RecordStepRun(ctx, run); err != nil {
+		log.Printf("⚠️  Failed to record step run: %v", err)
+	}
+
+	s.advance(ctx, seq, enrollment, index)
+}
+
+func (s *SequenceService) advance(ctx context.Context, seq *sequence.Sequence, enrollment *sequence.Enrollment, index int) {
+	enrollment.Advance(seq, time.Now())
+	if _, err := s.enrollmentRepo.Advance(ctx, *enrollment, index); err != nil {
+		log.Printf("❌ Failed to advance enrollment %s: %v", enrollment.ID, err)
+	}
+}
+
+func (s *SequenceService) sendMessage(ctx context.Context, seq *sequence.Sequence, step sequence.Step, enrollment *sequence.Enrollment) error {
+	if s.channelManager == nil {
+		return fmt.Errorf("channels are not configured")
+	}
+
+	data := templateContext(seq, step, enrollment)
+
+	text, err := s.render(ctx, step.Text, data)
+	if err != nil {
+		return err
+	}
+
+	var variables map[string]string
+	if len(step.Variables) > 0 {
+		variables = make(map[string]string, len(step.Variables))
+		for key, value := range step.Variables {
+			if variables[key], err = s.render(ctx, value, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	msg := channels.OutgoingMessage{
+		RecipientID: enrollment.ContactID,
+		Content:     channels.MessageContent{Type: "text", Text: text},
+		TemplateID:  step.TemplateID,
+		Variables:   variables,
+		Metadata: map[string]any{
+			"origin":           string(conversation.OriginSequence),
+			"sequence_id":      seq.ID,
+			"sequence_step_id": step.ID,
+			"timestamp":        time.Now().Unix(),
+		},
+	}
+	if step.TemplateID != "" {
+		msg.Content.Type = "template"
+	}
+
+	return s.channelManager.SendMessage(ctx, enrollment.TenantID, enrollment.ChannelID, msg)
+}
+
+// triggerWorkflow runs the step's workflow as if the contact had written on
+// the enrollment's channel, with the sequence under trigger.sequence
+func (s *SequenceService) triggerWorkflow(ctx context.Context, seq *sequence.Sequence, step sequence.Step, enrollment *sequence.Enrollment) error {
+	triggerData := map[string]any{
+		"channel_id":      enrollment.ChannelID.String(),
+		"sender_id":       enrollment.ContactID,
+		"conversation_id": enrollment.ContactID,
+		"sequence": map[string]any{
+			"id":            seq.ID,
+			"name":          seq.Name,
+			"step_id":       step.ID,
+			"enrollment_id": enrollment.ID,
+		},
+		"context": enrollment.Context,
+	}
+
+	return s.triggerHandler.HandleManualTrigger(ctx, *step.WorkflowID, enrollment.TenantID, triggerData)
+}
+
+func (s *SequenceService) render(ctx context.Context, text string, data map[string]any) (string, error) {
+	if text == "" || s.evaluator == nil {
+		return text, nil
+	}
+	rendered, err := s.evaluator.Evaluate(ctx, text, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render %q: %w", text, err)
+	}
+	return fmt.Sprint(rendered), nil
+}
+
+// templateContext is what step texts can reference
+func templateContext(seq *sequence.Sequence, step sequence.Step, enrollment *sequence.Enrollment) map[string]any {
+	values := enrollment.Context
+	if values == nil {
+		values = map[string]any{}
+	}
+	return map[string]any{
+		"contact":  map[string]any{"id": enrollment.ContactID},
+		"context":  values,
+		"sequence": map[string]any{"id": seq.ID, "name": seq.Name, "step_id": step.ID},
+	}
+}
@@ -0,0 +1,303 @@
+package sequencesrv
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/engine/triggerhandler"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/sequence"
+	"github.com/google/uuid"
+)
+
+// SequenceService manages drip sequences, enrolls contacts and runs their
+// due steps
+type SequenceService struct {
+	sequenceRepo    sequence.SequenceRepository
+	enrollmentRepo  sequence.EnrollmentRepository
+	channelRepo     channels.ChannelRepository
+	channelManager  channels.ChannelManager        // nil = send_message steps fail
+	suppressionRepo channels.SuppressionRepository // nil = opt-outs are not checked
+	triggerHandler  *triggerhandler.TriggerHandler
+	evaluator       engine.ExpressionEvaluator
+}
+
+var _ channels.InboundListener = (*SequenceService)(nil)
+
+func NewSequenceService(
+	sequenceRepo sequence.SequenceRepository,
+	enrollmentRepo sequence.EnrollmentRepository,
+	channelRepo channels.ChannelRepository,
+	channelManager channels.ChannelManager,
+	suppressionRepo channels.SuppressionRepository,
+	triggerHandler *triggerhandler.TriggerHandler,
+	evaluator engine.ExpressionEvaluator,
+) *SequenceService {
+	return &SequenceService{
+		sequenceRepo:    sequenceRepo,
+		enrollmentRepo:  enrollmentRepo,
+		channelRepo:     channelRepo,
+		channelManager:  channelManager,
+		suppressionRepo: suppressionRepo,
+		triggerHandler:  triggerHandler,
+		evaluator:       evaluator,
+	}
+}
+
+// ============================================================================
+// Sequences
+// ============================================================================
+
+// Create stores a new sequence
+func (s *SequenceService) Create(ctx context.Context, tenantID kernel.TenantID, req sequence.SaveSequenceRequest) (*sequence.Sequence, error) {
+	now := time.Now()
+	seq := &sequence.Sequence{
+		ID:        uuid.NewString(),
+		TenantID:  tenantID,
+		CreatedAt: now,
+	}
+	return s.save(ctx, seq, req, now)
+}
+
+// Update replaces a sequence's definition. Contacts in it continue at the
+// same step position.
+func (s *SequenceService) Update(ctx context.Context, id string, tenantID kernel.TenantID, req sequence.SaveSequenceRequest) (*sequence.Sequence, error) {
+	seq, err := s.sequenceRepo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, seq, req, time.Now())
+}
+
+func (s *SequenceService) save(ctx context.Context, seq *sequence.Sequence, req sequence.SaveSequenceRequest, now time.Time) (*sequence.Sequence, error) {
+	seq.Name = req.Name
+	seq.Description = req.Description
+	seq.ChannelID = req.ChannelID
+	seq.Steps = req.Steps
+	seq.ExitOnReply = req.ExitOnReply == nil || *req.ExitOnReply
+	seq.IsActive = req.IsActive == nil || *req.IsActive
+	seq.UpdatedAt = now
+
+	if err := seq.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkChannel(ctx, seq.TenantID, seq.ChannelID); err != nil {
+		return nil, err
+	}
+
+	if err := s.sequenceRepo.Save(ctx, *seq); err != nil {
+		return nil, err
+	}
+
+	return seq, nil
+}
+
+func (s *SequenceService) Get(ctx context.Context, id string, tenantID kernel.TenantID) (*sequence.Sequence, error) {
+	return s.sequenceRepo.FindByID(ctx, id, tenantID)
+}
+
+func (s *SequenceService) List(ctx context.Context, tenantID kernel.TenantID) ([]*sequence.Sequence, error) {
+	return s.sequenceRepo.List(ctx, tenantID)
+}
+
+// Delete removes a sequence; contacts in it stop receiving steps
+func (s *SequenceService) Delete(ctx context.Context, id string, tenantID kernel.TenantID) error {
+	return s.sequenceRepo.Delete(ctx, id, tenantID)
+}
+
+// ============================================================================
+// Enrollments
+// ============================================================================
+
+// Enroll puts a contact at the start of a sequence. The first step is due
+// its after_seconds from now.
+func (s *SequenceService) Enroll(ctx context.Context, tenantID kernel.TenantID, sequenceID string, req sequence.EnrollRequest) (*sequence.Enrollment, error) {
+	if req.ContactID == "" {
+		return nil, sequence.ErrInvalidEnrollment().WithDetail("reason", "contact_id is required")
+	}
+
+	seq, err := s.sequenceRepo.FindByID(ctx, sequenceID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !seq.IsActive {
+		return nil, sequence.ErrSequenceInactive().WithDetail("sequence_id", sequenceID)
+	}
+
+	channelID := req.ChannelID
+	if channelID.IsEmpty() {
+		channelID = seq.ChannelID
+	} else if err := s.checkChannel(ctx, tenantID, channelID); err != nil {
+		return nil, err
+	}
+
+	suppressed, err := s.isSuppressed(ctx, tenantID, channelID, req.ContactID)
+	if err != nil {
+		return nil, err
+	}
+	if suppressed {
+		return nil, channels.ErrRecipientSuppressed().
+			WithDetail("channel_id", channelID.String()).
+			WithDetail("recipient_id", req.ContactID)
+	}
+
+	now := time.Now()
+	first := now.Add(seq.Steps[0].After())
+	enrollment := &sequence.Enrollment{
+		ID:         uuid.NewString(),
+		TenantID:   tenantID,
+		SequenceID: seq.ID,
+		ContactID:  req.ContactID,
+		ChannelID:  channelID,
+		Context:    req.Context,
+		Status:     sequence.EnrollmentActive,
+		NextStep:   0,
+		NextStepAt: &first,
+		EnrolledAt: now,
+		UpdatedAt:  now,
+	}
+
+	if err := s.enrollmentRepo.Create(ctx, *enrollment); err != nil {
+		return nil, err
+	}
+
+	log.Printf("📬 Contact %s enrolled in sequence %s", req.ContactID, seq.Name)
+	return enrollment, nil
+}
+
+// ListEnrollments returns a sequence's enrollments, newest first
+func (s *SequenceService) ListEnrollments(ctx context.Context, req sequence.ListEnrollmentsRequest) (sequence.EnrollmentListResponse, error) {
+	if _, err := s.sequenceRepo.FindByID(ctx, req.SequenceID, req.TenantID); err != nil {
+		return sequence.EnrollmentListResponse{}, err
+	}
+	return s.enrollmentRepo.List(ctx, req)
+}
+
+// Unenroll removes a contact from a sequence before it finished
+func (s *SequenceService) Unenroll(ctx context.Context, tenantID kernel.TenantID, sequenceID, enrollmentID string) (*sequence.Enrollment, error) {
+	enrollment, err := s.enrollmentRepo.FindByID(ctx, enrollmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment.SequenceID != sequenceID {
+		return nil, sequence.ErrEnrollmentNotFound().WithDetail("enrollment_id", enrollmentID)
+	}
+
+	exited, err := s.enrollmentRepo.Exit(ctx, enrollmentID, tenantID, sequence.ExitManual)
+	if err != nil {
+		return nil, err
+	}
+	if !exited {
+		return nil, sequence.ErrInvalidEnrollment().
+			WithDetail("enrollment_id", enrollmentID).
+			WithDetail("reason", "enrollment already finished")
+	}
+
+	return s.enrollmentRepo.FindByID(ctx, enrollmentID, tenantID)
+}
+
+// OnInboundMessage implements channels.InboundListener: a reply ends the
+// contact's enrollments in sequences that exit on reply
+func (s *SequenceService) OnInboundMessage(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage) {
+	exited, err := s.enrollmentRepo.ExitOnReply(ctx, channel.TenantID, channel.ID, msg.SenderID)
+	if err != nil {
+		log.Printf("⚠️  Failed to exit sequences on reply from %s: %v", msg.SenderID, err)
+		return
+	}
+	if exited > 0 {
+		log.Printf("📭 Contact %s replied, left %d sequence(s)", msg.SenderID, exited)
+	}
+}
+
+// ============================================================================
+// Stats
+// ============================================================================
+
+// Stats counts the sequence's enrollments and how each step performed
+func (s *SequenceService) Stats(ctx context.Context, tenantID kernel.TenantID, sequenceID string) (*sequence.SequenceStats, error) {
+	seq, err := s.sequenceRepo.FindByID(ctx, sequenceID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollments, err := s.enrollmentRepo.CountEnrollments(ctx, sequenceID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := s.enrollmentRepo.CountStepRuns(ctx, sequenceID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &sequence.SequenceStats{
+		SequenceID: seq.ID,
+		Exited:     make(map[string]int),
+		Steps:      make([]sequence.StepStats, len(seq.Steps)),
+	}
+	stepIndex := make(map[string]int, len(seq.Steps))
+	for i, step := range seq.Steps {
+		stats.Steps[i] = sequence.StepStats{StepID: step.ID, Type: step.Type, Exited: make(map[string]int)}
+		stepIndex[step.ID] = i
+	}
+
+	for _, count := range enrollments {
+		stats.Enrolled += count.Count
+
+		// Steps may have been removed since; those contacts only count overall
+		var step *sequence.StepStats
+		if count.NextStep >= 0 && count.NextStep < len(stats.Steps) {
+			step = &stats.Steps[count.NextStep]
+		}
+
+		switch count.Status {
+		case sequence.EnrollmentActive:
+			stats.Active += count.Count
+			if step != nil {
+				step.Waiting += count.Count
+			}
+		case sequence.EnrollmentCompleted:
+			stats.Completed += count.Count
+		case sequence.EnrollmentExited:
+			stats.Exited[string(count.ExitReason)] += count.Count
+			if step != nil {
+				step.Exited[string(count.ExitReason)] += count.Count
+			}
+		}
+	}
+
+	for _, count := range runs {
+		i, ok := stepIndex[count.StepID]
+		if !ok {
+			continue
+		}
+		switch count.Status {
+		case sequence.StepRunSent:
+			stats.Steps[i].Sent += count.Count
+		case sequence.StepRunFailed:
+			stats.Steps[i].Failed += count.Count
+		}
+	}
+
+	return stats, nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func (s *SequenceService) checkChannel(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID) error {
+	if _, err := s.channelRepo.FindByID(ctx, channelID, tenantID); err != nil {
+		return channels.ErrChannelNotFound().WithDetail("channel_id", channelID.String())
+	}
+	return nil
+}
+
+func (s *SequenceService) isSuppressed(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, contactID string) (bool, error) {
+	if s.suppressionRepo == nil {
+		return false, nil
+	}
+	return s.suppressionRepo.IsSuppressed(ctx, tenantID, channelID, contactID)
+}
@@ -0,0 +1,38 @@
+package snippet
+
+import "github.com/Abraxas-365/relay/pkg/kernel"
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// SaveSnippetRequest creates or replaces a snippet
+type SaveSnippetRequest struct {
+	Name      string     `json:"name" validate:"required"`
+	Category  string     `json:"category,omitempty"`
+	Content   string     `json:"content" validate:"required"`
+	Variables []Variable `json:"variables,omitempty"`
+}
+
+// ListSnippetsRequest filters a tenant's snippets. Search matches the name
+// or content.
+type ListSnippetsRequest struct {
+	TenantID kernel.TenantID `json:"tenant_id"`
+	Category string          `json:"category,omitempty"`
+	Search   string          `json:"search,omitempty"`
+}
+
+// RenderSnippetRequest previews a snippet with the given values
+type RenderSnippetRequest struct {
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+// CategoryCount is one category and how many snippets it holds
+type CategoryCount struct {
+	Category string `db:"category" json:"category"`
+	Count    int    `db:"count" json:"count"`
+}
@@ -0,0 +1,44 @@
+package snippet
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("SNIPPET")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeSnippetNotFound  = ErrRegistry.Register("SNIPPET_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Snippet not found")
+	CodeInvalidSnippet   = ErrRegistry.Register("INVALID_SNIPPET", errx.TypeValidation, http.StatusBadRequest, "Invalid snippet")
+	CodeSnippetNameTaken = ErrRegistry.Register("SNIPPET_NAME_TAKEN", errx.TypeConflict, http.StatusConflict, "A snippet with this name already exists")
+	CodeMissingVariables = ErrRegistry.Register("MISSING_VARIABLES", errx.TypeValidation, http.StatusBadRequest, "Snippet variables are missing")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrSnippetNotFound() *errx.Error {
+	return ErrRegistry.New(CodeSnippetNotFound)
+}
+
+func ErrInvalidSnippet() *errx.Error {
+	return ErrRegistry.New(CodeInvalidSnippet)
+}
+
+func ErrSnippetNameTaken() *errx.Error {
+	return ErrRegistry.New(CodeSnippetNameTaken)
+}
+
+func ErrMissingVariables() *errx.Error {
+	return ErrRegistry.New(CodeMissingVariables)
+}
@@ -0,0 +1,21 @@
+package snippet
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// SnippetRepository persists a tenant's canned replies
+type SnippetRepository interface {
+	// Save creates or replaces a snippet; names are unique per tenant
+	Save(ctx context.Context, s Snippet) error
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*Snippet, error)
+	List(ctx context.Context, req ListSnippetsRequest) ([]*Snippet, error)
+	Categories(ctx context.Context, tenantID kernel.TenantID) ([]CategoryCount, error)
+	Delete(ctx context.Context, id string, tenantID kernel.TenantID) error
+}
@@ -0,0 +1,124 @@
+package snippet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Snippets
+// ============================================================================
+
+// MaxContentLength bounds a snippet's text; channels cap messages lower
+const MaxContentLength = 4096
+
+// placeholderPattern matches {{name}} placeholders in snippet content
+var placeholderPattern = regexp.MustCompile(`{{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*}}`)
+
+// Snippet is a canned reply managed once per tenant and reused by operators
+// in the inbox and by workflows' SEND_MESSAGE nodes
+type Snippet struct {
+	ID        string          `json:"id"`
+	TenantID  kernel.TenantID `json:"tenant_id"`
+	Name      string          `json:"name"`               // Unique per tenant, typed as a shortcut
+	Category  string          `json:"category,omitempty"` // Free-form grouping, e.g. "billing"
+	Content   string          `json:"content"`            // Text with {{variable}} placeholders
+	Variables []Variable      `json:"variables,omitempty"`
+	CreatedBy kernel.UserID   `json:"created_by,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Variable documents a placeholder of the content. One without a default
+// must be supplied when the snippet is rendered.
+type Variable struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Validate checks the snippet can be rendered: every placeholder of the
+// content is declared, so operators know what to fill in
+func (s *Snippet) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return ErrInvalidSnippet().WithDetail("reason", "name is required")
+	}
+	if strings.TrimSpace(s.Content) == "" {
+		return ErrInvalidSnippet().WithDetail("reason", "content is required")
+	}
+	if len(s.Content) > MaxContentLength {
+		return ErrInvalidSnippet().
+			WithDetail("reason", fmt.Sprintf("content is limited to %d bytes", MaxContentLength))
+	}
+
+	declared := make(map[string]bool, len(s.Variables))
+	for _, variable := range s.Variables {
+		if !placeholderPattern.MatchString("{{" + variable.Name + "}}") {
+			return ErrInvalidSnippet().
+				WithDetail("variable", variable.Name).
+				WithDetail("reason", "variable names are letters, digits and underscores")
+		}
+		if declared[variable.Name] {
+			return ErrInvalidSnippet().
+				WithDetail("variable", variable.Name).
+				WithDetail("reason", "variables must be unique")
+		}
+		declared[variable.Name] = true
+	}
+
+	for _, name := range Placeholders(s.Content) {
+		if !declared[name] {
+			return ErrInvalidSnippet().
+				WithDetail("variable", name).
+				WithDetail("reason", "placeholder is not declared in variables")
+		}
+	}
+
+	return nil
+}
+
+// Render fills the placeholders with values, falling back to each
+// variable's default
+func (s *Snippet) Render(values map[string]string) (string, error) {
+	resolved := make(map[string]string, len(s.Variables))
+	var missing []string
+	for _, variable := range s.Variables {
+		value, ok := values[variable.Name]
+		if !ok || value == "" {
+			value = variable.Default
+		}
+		if value == "" && variable.Required {
+			missing = append(missing, variable.Name)
+		}
+		resolved[variable.Name] = value
+	}
+	if len(missing) > 0 {
+		return "", ErrMissingVariables().
+			WithDetail("snippet_id", s.ID).
+			WithDetail("variables", missing)
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(s.Content, func(placeholder string) string {
+		name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+		return resolved[name]
+	}), nil
+}
+
+// Placeholders lists the variable names the content references, in order
+// of first appearance
+func Placeholders(content string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range placeholderPattern.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
@@ -0,0 +1,154 @@
+package snippetapi
+
+import (
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/snippet"
+	"github.com/Abraxas-365/relay/snippet/snippetsrv"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SnippetHandler exposes the tenant's canned replies
+type SnippetHandler struct {
+	service *snippetsrv.SnippetService
+}
+
+// NewSnippetHandler creates a new snippet handler
+func NewSnippetHandler(service *snippetsrv.SnippetService) *SnippetHandler {
+	return &SnippetHandler{
+		service: service,
+	}
+}
+
+// List returns the tenant's snippets, optionally by category or matching
+// ?q= in the name or content
+// GET /api/snippets
+func (h *SnippetHandler) List(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	snippets, err := h.service.List(c.Context(), snippet.ListSnippetsRequest{
+		TenantID: authContext.TenantID,
+		Category: c.Query("category"),
+		Search:   c.Query("q"),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"snippets": snippets})
+}
+
+// Categories returns the tenant's categories with their snippet counts
+// GET /api/snippets/categories
+func (h *SnippetHandler) Categories(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	categories, err := h.service.Categories(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"categories": categories})
+}
+
+// Create defines a new snippet
+// POST /api/snippets
+func (h *SnippetHandler) Create(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req snippet.SaveSnippetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return snippet.ErrInvalidSnippet().WithDetail("reason", err.Error())
+	}
+
+	snip, err := h.service.Create(c.Context(), authContext.TenantID, authContext.UserID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(snip)
+}
+
+// Get returns a snippet
+// GET /api/snippets/:id
+func (h *SnippetHandler) Get(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	snip, err := h.service.Get(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(snip)
+}
+
+// Update replaces a snippet
+// PUT /api/snippets/:id
+func (h *SnippetHandler) Update(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req snippet.SaveSnippetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return snippet.ErrInvalidSnippet().WithDetail("reason", err.Error())
+	}
+
+	snip, err := h.service.Update(c.Context(), c.Params("id"), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(snip)
+}
+
+// Delete removes a snippet
+// DELETE /api/snippets/:id
+func (h *SnippetHandler) Delete(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := h.service.Delete(c.Context(), c.Params("id"), authContext.TenantID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Render previews a snippet with the given variables
+// POST /api/snippets/:id/render
+func (h *SnippetHandler) Render(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req snippet.RenderSnippetRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return snippet.ErrInvalidSnippet().WithDetail("reason", err.Error())
+		}
+	}
+
+	text, err := h.service.RenderSnippet(c.Context(), authContext.TenantID, c.Params("id"), req.Variables)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"text": text})
+}
@@ -0,0 +1,34 @@
+package snippetapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SnippetRoutes handles snippet route setup
+type SnippetRoutes struct {
+	handler        *SnippetHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewSnippetRoutes creates a new snippet routes instance
+func NewSnippetRoutes(handler *SnippetHandler, authMiddleware *auth.AuthMiddleware) *SnippetRoutes {
+	return &SnippetRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers snippet routes on an authenticated router.
+// Managing snippets requires an admin; using them does not.
+func (r *SnippetRoutes) RegisterRoutes(router fiber.Router) {
+	snippets := router.Group("/snippets")
+
+	snippets.Get("/", r.handler.List)
+	snippets.Get("/categories", r.handler.Categories)
+	snippets.Post("/", r.authMiddleware.RequireAdmin(), r.handler.Create)
+	snippets.Get("/:id", r.handler.Get)
+	snippets.Put("/:id", r.authMiddleware.RequireAdmin(), r.handler.Update)
+	snippets.Delete("/:id", r.authMiddleware.RequireAdmin(), r.handler.Delete)
+	snippets.Post("/:id/render", r.handler.Render)
+}
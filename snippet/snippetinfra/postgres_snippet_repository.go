@@ -0,0 +1,194 @@
+package snippetinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/snippet"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PostgresSnippetRepository struct {
+	db *sqlx.DB
+}
+
+var _ snippet.SnippetRepository = (*PostgresSnippetRepository)(nil)
+
+func NewPostgresSnippetRepository(db *sqlx.DB) *PostgresSnippetRepository {
+	return &PostgresSnippetRepository{db: db}
+}
+
+// dbSnippet is an intermediate struct for database operations
+type dbSnippet struct {
+	ID        string          `db:"id"`
+	TenantID  string          `db:"tenant_id"`
+	Name      string          `db:"name"`
+	Category  string          `db:"category"`
+	Content   string          `db:"content"`
+	Variables json.RawMessage `db:"variables"`
+	CreatedBy sql.NullString  `db:"created_by"`
+	CreatedAt time.Time       `db:"created_at"`
+	UpdatedAt time.Time       `db:"updated_at"`
+}
+
+const snippetColumns = `
+	id, tenant_id, name, category, content, variables,
+	created_by, created_at, updated_at`
+
+func (r *PostgresSnippetRepository) Save(ctx context.Context, s snippet.Snippet) error {
+	variables := s.Variables
+	if variables == nil {
+		variables = []snippet.Variable{}
+	}
+	variablesJSON, err := json.Marshal(variables)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal snippet variables", errx.TypeInternal)
+	}
+
+	var createdBy sql.NullString
+	if s.CreatedBy != "" {
+		createdBy = sql.NullString{String: s.CreatedBy.String(), Valid: true}
+	}
+
+	query := `
+		INSERT INTO snippets (
+			id, tenant_id, name, category, content, variables, created_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			category = EXCLUDED.category,
+			content = EXCLUDED.content,
+			variables = EXCLUDED.variables`
+
+	_, err = r.db.ExecContext(ctx, query,
+		s.ID, s.TenantID.String(), s.Name, s.Category, s.Content, variablesJSON,
+		createdBy, s.CreatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return snippet.ErrSnippetNameTaken().WithDetail("name", s.Name)
+		}
+		return errx.Wrap(err, "failed to save snippet", errx.TypeInternal).
+			WithDetail("snippet_id", s.ID)
+	}
+
+	return nil
+}
+
+func (r *PostgresSnippetRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*snippet.Snippet, error) {
+	query := `SELECT ` + snippetColumns + ` FROM snippets WHERE id = $1 AND tenant_id = $2`
+
+	var row dbSnippet
+	if err := r.db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, snippet.ErrSnippetNotFound().WithDetail("snippet_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find snippet", errx.TypeInternal).
+			WithDetail("snippet_id", id)
+	}
+
+	return toDomainSnippet(&row)
+}
+
+func (r *PostgresSnippetRepository) List(ctx context.Context, req snippet.ListSnippetsRequest) ([]*snippet.Snippet, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.Category != "" {
+		conditions = append(conditions, fmt.Sprintf("category = $%d", argPos))
+		args = append(args, req.Category)
+		argPos++
+	}
+	if req.Search != "" {
+		conditions = append(conditions, fmt.Sprintf("(name ILIKE $%d OR content ILIKE $%d)", argPos, argPos))
+		args = append(args, "%"+req.Search+"%")
+		argPos++
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM snippets WHERE %s ORDER BY category ASC, name ASC`,
+		snippetColumns, strings.Join(conditions, " AND "))
+
+	var rows []dbSnippet
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, errx.Wrap(err, "failed to list snippets", errx.TypeInternal)
+	}
+
+	snippets := make([]*snippet.Snippet, 0, len(rows))
+	for i := range rows {
+		s, err := toDomainSnippet(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		snippets = append(snippets, s)
+	}
+
+	return snippets, nil
+}
+
+func (r *PostgresSnippetRepository) Categories(ctx context.Context, tenantID kernel.TenantID) ([]snippet.CategoryCount, error) {
+	query := `
+		SELECT category, COUNT(*) AS count
+		FROM snippets
+		WHERE tenant_id = $1
+		GROUP BY category
+		ORDER BY category ASC`
+
+	var counts []snippet.CategoryCount
+	if err := r.db.SelectContext(ctx, &counts, query, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to list snippet categories", errx.TypeInternal)
+	}
+
+	return counts, nil
+}
+
+func (r *PostgresSnippetRepository) Delete(ctx context.Context, id string, tenantID kernel.TenantID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM snippets WHERE id = $1 AND tenant_id = $2`, id, tenantID.String())
+	if err != nil {
+		return errx.Wrap(err, "failed to delete snippet", errx.TypeInternal).
+			WithDetail("snippet_id", id)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errx.Wrap(err, "failed to get rows affected", errx.TypeInternal)
+	}
+	if rows == 0 {
+		return snippet.ErrSnippetNotFound().WithDetail("snippet_id", id)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func toDomainSnippet(row *dbSnippet) (*snippet.Snippet, error) {
+	s := &snippet.Snippet{
+		ID:        row.ID,
+		TenantID:  kernel.TenantID(row.TenantID),
+		Name:      row.Name,
+		Category:  row.Category,
+		Content:   row.Content,
+		CreatedBy: kernel.UserID(row.CreatedBy.String),
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+
+	if len(row.Variables) > 0 {
+		if err := json.Unmarshal(row.Variables, &s.Variables); err != nil {
+			return nil, errx.Wrap(err, "failed to unmarshal snippet variables", errx.TypeInternal).
+				WithDetail("snippet_id", row.ID)
+		}
+	}
+
+	return s, nil
+}
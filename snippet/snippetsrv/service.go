@@ -0,0 +1,100 @@
+package snippetsrv
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/snippet"
+	"github.com/google/uuid"
+)
+
+// SnippetService manages canned replies and renders them for the inbox and
+// for workflows
+type SnippetService struct {
+	repo snippet.SnippetRepository
+}
+
+var _ engine.SnippetProvider = (*SnippetService)(nil)
+
+func NewSnippetService(repo snippet.SnippetRepository) *SnippetService {
+	return &SnippetService{repo: repo}
+}
+
+// ============================================================================
+// Snippets
+// ============================================================================
+
+// Create stores a new snippet
+func (s *SnippetService) Create(ctx context.Context, tenantID kernel.TenantID, userID kernel.UserID, req snippet.SaveSnippetRequest) (*snippet.Snippet, error) {
+	now := time.Now()
+	snip := &snippet.Snippet{
+		ID:        uuid.NewString(),
+		TenantID:  tenantID,
+		CreatedBy: userID,
+		CreatedAt: now,
+	}
+	return s.save(ctx, snip, req, now)
+}
+
+// Update replaces a snippet's name, category, content and variables.
+// Workflows referencing it send the new content from then on.
+func (s *SnippetService) Update(ctx context.Context, id string, tenantID kernel.TenantID, req snippet.SaveSnippetRequest) (*snippet.Snippet, error) {
+	snip, err := s.repo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, snip, req, time.Now())
+}
+
+func (s *SnippetService) save(ctx context.Context, snip *snippet.Snippet, req snippet.SaveSnippetRequest, now time.Time) (*snippet.Snippet, error) {
+	snip.Name = strings.TrimSpace(req.Name)
+	snip.Category = strings.ToLower(strings.TrimSpace(req.Category))
+	snip.Content = req.Content
+	snip.Variables = req.Variables
+	snip.UpdatedAt = now
+
+	if err := snip.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(ctx, *snip); err != nil {
+		return nil, err
+	}
+
+	return snip, nil
+}
+
+func (s *SnippetService) Get(ctx context.Context, id string, tenantID kernel.TenantID) (*snippet.Snippet, error) {
+	return s.repo.FindByID(ctx, id, tenantID)
+}
+
+func (s *SnippetService) List(ctx context.Context, req snippet.ListSnippetsRequest) ([]*snippet.Snippet, error) {
+	req.Category = strings.ToLower(strings.TrimSpace(req.Category))
+	return s.repo.List(ctx, req)
+}
+
+func (s *SnippetService) Categories(ctx context.Context, tenantID kernel.TenantID) ([]snippet.CategoryCount, error) {
+	return s.repo.Categories(ctx, tenantID)
+}
+
+// Delete removes a snippet; workflow nodes still referencing it fail to send
+func (s *SnippetService) Delete(ctx context.Context, id string, tenantID kernel.TenantID) error {
+	return s.repo.Delete(ctx, id, tenantID)
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+// RenderSnippet implements engine.SnippetProvider: the snippet's content
+// with its placeholders filled in
+func (s *SnippetService) RenderSnippet(ctx context.Context, tenantID kernel.TenantID, snippetID string, variables map[string]string) (string, error) {
+	snip, err := s.repo.FindByID(ctx, snippetID, tenantID)
+	if err != nil {
+		return "", err
+	}
+	return snip.Render(variables)
+}
@@ -0,0 +1,43 @@
+package spamfilter
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Blocked Senders
+// ============================================================================
+
+// BlockedSender is a sender whose messages on a channel are dropped while
+// the tenant's filter is enabled
+type BlockedSender struct {
+	TenantID  kernel.TenantID  `db:"tenant_id" json:"tenant_id"`
+	ChannelID kernel.ChannelID `db:"channel_id" json:"channel_id"`
+	SenderID  string           `db:"sender_id" json:"sender_id"`
+	Reason    string           `db:"reason" json:"reason"`
+	BlockedBy string           `db:"blocked_by" json:"blocked_by"` // User ID, or "filter" for automatic blocks
+	CreatedAt time.Time        `db:"created_at" json:"created_at"`
+	ExpiresAt *time.Time       `db:"expires_at" json:"expires_at,omitempty"` // nil = until unblocked
+}
+
+// BlockedByFilter marks blocks made by the filter itself
+const BlockedByFilter = "filter"
+
+// NewBlockedSender blocks a sender for duration; zero blocks indefinitely
+func NewBlockedSender(tenantID kernel.TenantID, channelID kernel.ChannelID, senderID, reason, blockedBy string, duration time.Duration) *BlockedSender {
+	block := &BlockedSender{
+		TenantID:  tenantID,
+		ChannelID: channelID,
+		SenderID:  senderID,
+		Reason:    reason,
+		BlockedBy: blockedBy,
+		CreatedAt: time.Now(),
+	}
+	if duration > 0 {
+		expiresAt := block.CreatedAt.Add(duration)
+		block.ExpiresAt = &expiresAt
+	}
+	return block
+}
@@ -0,0 +1,37 @@
+package spamfilter
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// BlockSenderRequest blocks a sender by hand
+type BlockSenderRequest struct {
+	ChannelID kernel.ChannelID `json:"channel_id" validate:"required"`
+	SenderID  string           `json:"sender_id" validate:"required"`
+	Reason    string           `json:"reason,omitempty"`
+	Minutes   int              `json:"minutes,omitempty"` // 0 = until unblocked
+}
+
+// ListBlockedRequest pages through a tenant's active blocks
+type ListBlockedRequest struct {
+	storex.PaginationOptions
+
+	TenantID  kernel.TenantID   `json:"tenant_id" validate:"required"`
+	ChannelID *kernel.ChannelID `json:"channel_id,omitempty"`
+}
+
+func (r ListBlockedRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+// BlockedListResponse paginated list of blocked senders
+type BlockedListResponse = storex.Paginated[BlockedSender]
@@ -0,0 +1,44 @@
+package spamfilter
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("SPAM_FILTER")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeInvalidPolicy   = ErrRegistry.Register("INVALID_POLICY", errx.TypeValidation, http.StatusBadRequest, "Invalid spam filter policy")
+	CodeInvalidBlock    = ErrRegistry.Register("INVALID_BLOCK", errx.TypeValidation, http.StatusBadRequest, "Invalid sender block")
+	CodeBlockNotFound   = ErrRegistry.Register("BLOCK_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Sender is not blocked")
+	CodeClassifierUnset = ErrRegistry.Register("CLASSIFIER_NOT_CONFIGURED", errx.TypeValidation, http.StatusBadRequest, "No spam classifier is configured on this server")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrInvalidPolicy() *errx.Error {
+	return ErrRegistry.New(CodeInvalidPolicy)
+}
+
+func ErrInvalidBlock() *errx.Error {
+	return ErrRegistry.New(CodeInvalidBlock)
+}
+
+func ErrBlockNotFound() *errx.Error {
+	return ErrRegistry.New(CodeBlockNotFound)
+}
+
+func ErrClassifierNotConfigured() *errx.Error {
+	return ErrRegistry.New(CodeClassifierUnset)
+}
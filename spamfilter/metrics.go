@@ -0,0 +1,116 @@
+package spamfilter
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Metrics
+// ============================================================================
+
+// TenantMetrics counts what the filter did with a tenant's messages since
+// the process started
+type TenantMetrics struct {
+	Checked  uint64            `json:"checked"`
+	Flagged  uint64            `json:"flagged"`
+	Dropped  uint64            `json:"dropped"`
+	Blocked  uint64            `json:"blocked_senders"` // Senders blocked by the filter
+	ByReason map[Reason]uint64 `json:"by_reason"`
+}
+
+type tenantCounters struct {
+	checked  atomic.Uint64
+	flagged  atomic.Uint64
+	dropped  atomic.Uint64
+	blocked  atomic.Uint64
+	byReason sync.Map // Reason -> *atomic.Uint64
+}
+
+// Metrics accumulates counters per tenant
+type Metrics struct {
+	tenants sync.Map // kernel.TenantID -> *tenantCounters
+}
+
+// NewMetrics creates an empty metrics accumulator
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// RecordChecked counts a message screened by an enabled policy
+func (m *Metrics) RecordChecked(tenantID kernel.TenantID) {
+	if m != nil {
+		m.counters(tenantID).checked.Add(1)
+	}
+}
+
+// RecordFiltered counts a message that tripped a rule and what was done with it
+func (m *Metrics) RecordFiltered(tenantID kernel.TenantID, reason Reason, action Action) {
+	if m == nil {
+		return
+	}
+	counters := m.counters(tenantID)
+	switch action {
+	case ActionFlag:
+		counters.flagged.Add(1)
+	case ActionBlock:
+		counters.blocked.Add(1)
+		counters.dropped.Add(1)
+	default:
+		counters.dropped.Add(1)
+	}
+
+	count, _ := counters.byReason.LoadOrStore(reason, &atomic.Uint64{})
+	count.(*atomic.Uint64).Add(1)
+}
+
+// Snapshot returns a copy of every tenant's counters
+func (m *Metrics) Snapshot() map[kernel.TenantID]TenantMetrics {
+	snapshot := make(map[kernel.TenantID]TenantMetrics)
+	if m == nil {
+		return snapshot
+	}
+
+	m.tenants.Range(func(key, value any) bool {
+		snapshot[key.(kernel.TenantID)] = value.(*tenantCounters).snapshot()
+		return true
+	})
+
+	return snapshot
+}
+
+// Tenant returns a copy of one tenant's counters
+func (m *Metrics) Tenant(tenantID kernel.TenantID) TenantMetrics {
+	if m == nil {
+		return TenantMetrics{ByReason: map[Reason]uint64{}}
+	}
+	if counters, ok := m.tenants.Load(tenantID); ok {
+		return counters.(*tenantCounters).snapshot()
+	}
+	return TenantMetrics{ByReason: map[Reason]uint64{}}
+}
+
+func (m *Metrics) counters(tenantID kernel.TenantID) *tenantCounters {
+	if counters, ok := m.tenants.Load(tenantID); ok {
+		return counters.(*tenantCounters)
+	}
+	counters, _ := m.tenants.LoadOrStore(tenantID, &tenantCounters{})
+	return counters.(*tenantCounters)
+}
+
+func (c *tenantCounters) snapshot() TenantMetrics {
+	metrics := TenantMetrics{
+		Checked:  c.checked.Load(),
+		Flagged:  c.flagged.Load(),
+		Dropped:  c.dropped.Load(),
+		Blocked:  c.blocked.Load(),
+		ByReason: make(map[Reason]uint64),
+	}
+	c.byReason.Range(func(key, value any) bool {
+		metrics.ByReason[key.(Reason)] = value.(*atomic.Uint64).Load()
+		return true
+	})
+	return metrics
+}
@@ -0,0 +1,170 @@
+package spamfilter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/lib/pq"
+)
+
+// ============================================================================
+// Spam Filter Policy
+// ============================================================================
+
+// Action is what happens to a message that trips a rule
+type Action string
+
+const (
+	// ActionFlag lets the message through with the matched rule in its
+	// metadata, so workflows can route it (trigger.metadata.spam)
+	ActionFlag Action = "FLAG"
+	// ActionDrop discards the message before it is recorded
+	ActionDrop Action = "DROP"
+	// ActionBlock discards the message and blocks the sender on the channel
+	ActionBlock Action = "BLOCK"
+)
+
+// IsValid reports whether the action is supported
+func (a Action) IsValid() bool {
+	return a == ActionFlag || a == ActionDrop || a == ActionBlock
+}
+
+// Reason names the rule a message tripped
+type Reason string
+
+const (
+	ReasonBlocked    Reason = "blocked_sender"
+	ReasonRate       Reason = "rate"
+	ReasonKeyword    Reason = "keyword"
+	ReasonLink       Reason = "link"
+	ReasonClassifier Reason = "classifier"
+)
+
+const (
+	// MaxKeywords bounds the blocklist checked on every message
+	MaxKeywords = 500
+	// MaxMessagesPerMinute upper bound accepted for the sender rate
+	MaxMessagesPerMinute = 1000
+)
+
+// Policy is a tenant's spam filter configuration. A disabled policy lets
+// every message through, blocked senders included.
+type Policy struct {
+	TenantID             kernel.TenantID `db:"tenant_id" json:"tenant_id"`
+	Enabled              bool            `db:"enabled" json:"enabled"`
+	Action               Action          `db:"action" json:"action"`
+	MaxMessagesPerMinute int             `db:"max_messages_per_minute" json:"max_messages_per_minute"` // Per sender and channel; 0 = unlimited
+	BlockedKeywords      pq.StringArray  `db:"blocked_keywords" json:"blocked_keywords"`
+	BlockLinks           bool            `db:"block_links" json:"block_links"`
+	AllowedDomains       pq.StringArray  `db:"allowed_domains" json:"allowed_domains"` // Links to these domains and their subdomains pass BlockLinks
+	UseClassifier        bool            `db:"use_classifier" json:"use_classifier"`
+	ClassifierThreshold  float64         `db:"classifier_threshold" json:"classifier_threshold"` // Score from 0 to 1 at which a message is spam
+	BlockMinutes         int             `db:"block_minutes" json:"block_minutes"`               // How long BLOCK blocks a sender; 0 = until unblocked
+	UpdatedAt            time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+// DefaultPolicy is applied to tenants that never configured the filter
+func DefaultPolicy(tenantID kernel.TenantID) *Policy {
+	return &Policy{
+		TenantID:            tenantID,
+		Action:              ActionFlag,
+		BlockedKeywords:     pq.StringArray{},
+		AllowedDomains:      pq.StringArray{},
+		ClassifierThreshold: 0.9,
+	}
+}
+
+// Validate checks the thresholds and action
+func (p *Policy) Validate() error {
+	if !p.Action.IsValid() {
+		return ErrInvalidPolicy().WithDetail("action", string(p.Action))
+	}
+	if p.MaxMessagesPerMinute < 0 || p.MaxMessagesPerMinute > MaxMessagesPerMinute {
+		return ErrInvalidPolicy().
+			WithDetail("field", "max_messages_per_minute").
+			WithDetail("max", MaxMessagesPerMinute)
+	}
+	if len(p.BlockedKeywords) > MaxKeywords {
+		return ErrInvalidPolicy().
+			WithDetail("field", "blocked_keywords").
+			WithDetail("max", MaxKeywords)
+	}
+	if p.ClassifierThreshold <= 0 || p.ClassifierThreshold > 1 {
+		return ErrInvalidPolicy().WithDetail("field", "classifier_threshold").WithDetail("reason", "must be in (0, 1]")
+	}
+	if p.BlockMinutes < 0 {
+		return ErrInvalidPolicy().WithDetail("field", "block_minutes")
+	}
+	return nil
+}
+
+// Normalize lowercases and deduplicates the keyword and domain lists
+func (p *Policy) Normalize() {
+	p.BlockedKeywords = normalizeList(p.BlockedKeywords, func(s string) string { return s })
+	p.AllowedDomains = normalizeList(p.AllowedDomains, func(s string) string {
+		return strings.TrimPrefix(strings.TrimPrefix(s, "*."), "www.")
+	})
+}
+
+// BlockDuration is how long ActionBlock blocks a sender; zero means indefinitely
+func (p *Policy) BlockDuration() time.Duration {
+	return time.Duration(p.BlockMinutes) * time.Minute
+}
+
+// UpdatePolicyRequest changes some fields of a policy
+type UpdatePolicyRequest struct {
+	Enabled              *bool     `json:"enabled,omitempty"`
+	Action               *Action   `json:"action,omitempty"`
+	MaxMessagesPerMinute *int      `json:"max_messages_per_minute,omitempty"`
+	BlockedKeywords      *[]string `json:"blocked_keywords,omitempty"`
+	BlockLinks           *bool     `json:"block_links,omitempty"`
+	AllowedDomains       *[]string `json:"allowed_domains,omitempty"`
+	UseClassifier        *bool     `json:"use_classifier,omitempty"`
+	ClassifierThreshold  *float64  `json:"classifier_threshold,omitempty"`
+	BlockMinutes         *int      `json:"block_minutes,omitempty"`
+}
+
+// Apply copies the set fields onto the policy
+func (r UpdatePolicyRequest) Apply(p *Policy) {
+	if r.Enabled != nil {
+		p.Enabled = *r.Enabled
+	}
+	if r.Action != nil {
+		p.Action = *r.Action
+	}
+	if r.MaxMessagesPerMinute != nil {
+		p.MaxMessagesPerMinute = *r.MaxMessagesPerMinute
+	}
+	if r.BlockedKeywords != nil {
+		p.BlockedKeywords = *r.BlockedKeywords
+	}
+	if r.BlockLinks != nil {
+		p.BlockLinks = *r.BlockLinks
+	}
+	if r.AllowedDomains != nil {
+		p.AllowedDomains = *r.AllowedDomains
+	}
+	if r.UseClassifier != nil {
+		p.UseClassifier = *r.UseClassifier
+	}
+	if r.ClassifierThreshold != nil {
+		p.ClassifierThreshold = *r.ClassifierThreshold
+	}
+	if r.BlockMinutes != nil {
+		p.BlockMinutes = *r.BlockMinutes
+	}
+}
+
+func normalizeList(values []string, clean func(string) string) pq.StringArray {
+	seen := make(map[string]bool, len(values))
+	normalized := make(pq.StringArray, 0, len(values))
+	for _, value := range values {
+		value = clean(strings.ToLower(strings.TrimSpace(value)))
+		if value != "" && !seen[value] {
+			seen[value] = true
+			normalized = append(normalized, value)
+		}
+	}
+	return normalized
+}
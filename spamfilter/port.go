@@ -0,0 +1,44 @@
+package spamfilter
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// PolicyRepository persists tenant spam filter policies
+type PolicyRepository interface {
+	// FindByTenant returns the tenant's policy, or DefaultPolicy when none is stored
+	FindByTenant(ctx context.Context, tenantID kernel.TenantID) (*Policy, error)
+
+	// Save creates or replaces a tenant's policy
+	Save(ctx context.Context, policy Policy) error
+}
+
+// BlockRepository persists blocked senders. Expired blocks are ignored.
+type BlockRepository interface {
+	// IsBlocked reports whether the sender has an active block on the channel
+	IsBlocked(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, senderID string) (bool, error)
+
+	// Save creates or replaces the sender's block on the channel
+	Save(ctx context.Context, block BlockedSender) error
+
+	// Delete lifts a block
+	Delete(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, senderID string) error
+
+	// List pages through the tenant's active blocks, newest first
+	List(ctx context.Context, req ListBlockedRequest) (BlockedListResponse, error)
+}
+
+// ============================================================================
+// Classifier
+// ============================================================================
+
+// Classifier scores how likely a text is spam, from 0 to 1
+type Classifier interface {
+	Classify(ctx context.Context, text string) (float64, error)
+}
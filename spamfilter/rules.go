@@ -0,0 +1,65 @@
+package spamfilter
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// Content Rules
+// ============================================================================
+
+// linkPattern finds URLs and bare www. hosts in message text
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"']+`)
+
+// MatchKeyword returns the first blocked keyword found in the text
+func (p *Policy) MatchKeyword(text string) (string, bool) {
+	if len(p.BlockedKeywords) == 0 {
+		return "", false
+	}
+	lower := strings.ToLower(text)
+	for _, keyword := range p.BlockedKeywords {
+		if strings.Contains(lower, keyword) {
+			return keyword, true
+		}
+	}
+	return "", false
+}
+
+// MatchLink returns the first link in the text whose host is not allowed
+func (p *Policy) MatchLink(text string) (string, bool) {
+	if !p.BlockLinks {
+		return "", false
+	}
+	for _, link := range linkPattern.FindAllString(text, -1) {
+		if !p.domainAllowed(linkHost(link)) {
+			return link, true
+		}
+	}
+	return "", false
+}
+
+func (p *Policy) domainAllowed(host string) bool {
+	if host == "" {
+		return false
+	}
+	for _, domain := range p.AllowedDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// linkHost extracts the lowercased host of a matched link
+func linkHost(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+}
@@ -0,0 +1,154 @@
+package spamfilterapi
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/spamfilter"
+	"github.com/Abraxas-365/relay/spamfilter/spamfiltersrv"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// SpamFilterHandler exposes the tenant's spam filter policy and blocked senders
+type SpamFilterHandler struct {
+	service *spamfiltersrv.FilterService
+}
+
+// NewSpamFilterHandler creates a new spam filter handler
+func NewSpamFilterHandler(service *spamfiltersrv.FilterService) *SpamFilterHandler {
+	return &SpamFilterHandler{
+		service: service,
+	}
+}
+
+// GetPolicy returns the tenant's spam filter policy
+// GET /api/spam-filter/policy
+func (h *SpamFilterHandler) GetPolicy(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	policy, err := h.service.GetPolicy(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(policy)
+}
+
+// UpdatePolicy changes the tenant's rules, thresholds or action
+// PUT /api/spam-filter/policy
+func (h *SpamFilterHandler) UpdatePolicy(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req spamfilter.UpdatePolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return spamfilter.ErrInvalidPolicy().WithDetail("reason", err.Error())
+	}
+
+	policy, err := h.service.UpdatePolicy(c.Context(), authContext.TenantID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(policy)
+}
+
+// Metrics returns how many of the tenant's messages were flagged or dropped
+// since the server started
+// GET /api/spam-filter/metrics
+func (h *SpamFilterHandler) Metrics(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	return c.JSON(h.service.Metrics(authContext.TenantID))
+}
+
+// ListBlocked returns the tenant's blocked senders, filtered by ?channel_id=
+// GET /api/spam-filter/blocked
+func (h *SpamFilterHandler) ListBlocked(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", defaultPageSize)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	req := spamfilter.ListBlockedRequest{
+		PaginationOptions: storex.PaginationOptions{
+			Page:     page,
+			PageSize: pageSize,
+		},
+		TenantID: authContext.TenantID,
+	}
+	if channelID := c.Query("channel_id"); channelID != "" {
+		id := kernel.ChannelID(channelID)
+		req.ChannelID = &id
+	}
+
+	blocks, err := h.service.ListBlocked(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(blocks)
+}
+
+// BlockSender blocks a sender on a channel
+// POST /api/spam-filter/blocked
+func (h *SpamFilterHandler) BlockSender(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req spamfilter.BlockSenderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return spamfilter.ErrInvalidBlock().WithDetail("reason", err.Error())
+	}
+
+	block, err := h.service.BlockSender(c.Context(), authContext.TenantID, authContext.UserID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(block)
+}
+
+// UnblockSender lifts a sender's block
+// DELETE /api/spam-filter/blocked/:channel_id/:sender_id
+func (h *SpamFilterHandler) UnblockSender(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	channelID := kernel.ChannelID(c.Params("channel_id"))
+	if err := h.service.UnblockSender(c.Context(), authContext.TenantID, channelID, c.Params("sender_id")); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
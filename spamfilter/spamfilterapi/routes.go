@@ -0,0 +1,33 @@
+package spamfilterapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SpamFilterRoutes handles spam filter route setup
+type SpamFilterRoutes struct {
+	handler        *SpamFilterHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewSpamFilterRoutes creates a new spam filter routes instance
+func NewSpamFilterRoutes(handler *SpamFilterHandler, authMiddleware *auth.AuthMiddleware) *SpamFilterRoutes {
+	return &SpamFilterRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers spam filter routes on an authenticated router.
+// Changing the policy or the blocklist requires an admin.
+func (r *SpamFilterRoutes) RegisterRoutes(router fiber.Router) {
+	spam := router.Group("/spam-filter")
+
+	spam.Get("/policy", r.handler.GetPolicy)
+	spam.Put("/policy", r.authMiddleware.RequireAdmin(), r.handler.UpdatePolicy)
+	spam.Get("/metrics", r.handler.Metrics)
+	spam.Get("/blocked", r.handler.ListBlocked)
+	spam.Post("/blocked", r.authMiddleware.RequireAdmin(), r.handler.BlockSender)
+	spam.Delete("/blocked/:channel_id/:sender_id", r.authMiddleware.RequireAdmin(), r.handler.UnblockSender)
+}
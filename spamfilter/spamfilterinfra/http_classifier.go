@@ -0,0 +1,70 @@
+package spamfilterinfra
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Abraxas-365/relay/spamfilter"
+)
+
+// HTTPClassifier posts message text to an external classification service,
+// which answers with {"score": 0.97}
+type HTTPClassifier struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+var _ spamfilter.Classifier = (*HTTPClassifier)(nil)
+
+// NewHTTPClassifier sends texts to url, with token as a bearer token when set
+func NewHTTPClassifier(url, token string, timeout time.Duration) *HTTPClassifier {
+	return &HTTPClassifier{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *HTTPClassifier) Classify(ctx context.Context, text string) (float64, error) {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("classifier unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("classifier returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Score *float64 `json:"score"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&result); err != nil {
+		return 0, fmt.Errorf("invalid classifier response: %w", err)
+	}
+	if result.Score == nil || *result.Score < 0 || *result.Score > 1 {
+		return 0, fmt.Errorf("invalid classifier response: score must be between 0 and 1")
+	}
+
+	return *result.Score, nil
+}
@@ -0,0 +1,107 @@
+package spamfilterinfra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/spamfilter"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresBlockRepository is the PostgreSQL implementation of spamfilter.BlockRepository
+type PostgresBlockRepository struct {
+	db *sqlx.DB
+}
+
+var _ spamfilter.BlockRepository = (*PostgresBlockRepository)(nil)
+
+func NewPostgresBlockRepository(db *sqlx.DB) *PostgresBlockRepository {
+	return &PostgresBlockRepository{db: db}
+}
+
+const blockColumns = `tenant_id, channel_id, sender_id, reason, blocked_by, created_at, expires_at`
+
+// activeBlock matches blocks that have not expired
+const activeBlock = `(expires_at IS NULL OR expires_at > NOW())`
+
+func (r *PostgresBlockRepository) IsBlocked(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, senderID string) (bool, error) {
+	query := `SELECT EXISTS(
+		SELECT 1 FROM spam_blocked_senders
+		WHERE tenant_id = $1 AND channel_id = $2 AND sender_id = $3 AND ` + activeBlock + `)`
+
+	var blocked bool
+	if err := r.db.GetContext(ctx, &blocked, query, tenantID.String(), channelID.String(), senderID); err != nil {
+		return false, errx.Wrap(err, "failed to check blocked sender", errx.TypeInternal)
+	}
+
+	return blocked, nil
+}
+
+func (r *PostgresBlockRepository) Save(ctx context.Context, block spamfilter.BlockedSender) error {
+	query := `
+		INSERT INTO spam_blocked_senders (` + blockColumns + `)
+		VALUES (:tenant_id, :channel_id, :sender_id, :reason, :blocked_by, :created_at, :expires_at)
+		ON CONFLICT (tenant_id, channel_id, sender_id) DO UPDATE SET
+			reason = EXCLUDED.reason,
+			blocked_by = EXCLUDED.blocked_by,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at`
+
+	if _, err := r.db.NamedExecContext(ctx, query, block); err != nil {
+		return errx.Wrap(err, "failed to block sender", errx.TypeInternal).
+			WithDetail("sender_id", block.SenderID)
+	}
+
+	return nil
+}
+
+func (r *PostgresBlockRepository) Delete(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, senderID string) error {
+	query := `DELETE FROM spam_blocked_senders
+		WHERE tenant_id = $1 AND channel_id = $2 AND sender_id = $3 AND ` + activeBlock
+
+	result, err := r.db.ExecContext(ctx, query, tenantID.String(), channelID.String(), senderID)
+	if err != nil {
+		return errx.Wrap(err, "failed to unblock sender", errx.TypeInternal).
+			WithDetail("sender_id", senderID)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return spamfilter.ErrBlockNotFound().
+			WithDetail("channel_id", channelID.String()).
+			WithDetail("sender_id", senderID)
+	}
+
+	return nil
+}
+
+func (r *PostgresBlockRepository) List(ctx context.Context, req spamfilter.ListBlockedRequest) (spamfilter.BlockedListResponse, error) {
+	where := "tenant_id = $1 AND " + activeBlock
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.ChannelID != nil {
+		where += fmt.Sprintf(" AND channel_id = $%d", argPos)
+		args = append(args, req.ChannelID.String())
+		argPos++
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM spam_blocked_senders WHERE `+where, args...); err != nil {
+		return spamfilter.BlockedListResponse{}, errx.Wrap(err, "failed to count blocked senders", errx.TypeInternal)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM spam_blocked_senders WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d`, blockColumns, where, argPos, argPos+1)
+	args = append(args, req.PageSize, req.GetOffset())
+
+	blocks := []spamfilter.BlockedSender{}
+	if err := r.db.SelectContext(ctx, &blocks, query, args...); err != nil {
+		return spamfilter.BlockedListResponse{}, errx.Wrap(err, "failed to list blocked senders", errx.TypeInternal)
+	}
+
+	return storex.NewPaginated(blocks, req.Page, req.PageSize, total), nil
+}
@@ -0,0 +1,72 @@
+package spamfilterinfra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/spamfilter"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresPolicyRepository is the PostgreSQL implementation of spamfilter.PolicyRepository
+type PostgresPolicyRepository struct {
+	db *sqlx.DB
+}
+
+var _ spamfilter.PolicyRepository = (*PostgresPolicyRepository)(nil)
+
+func NewPostgresPolicyRepository(db *sqlx.DB) *PostgresPolicyRepository {
+	return &PostgresPolicyRepository{db: db}
+}
+
+const policyColumns = `
+	tenant_id, enabled, action, max_messages_per_minute, blocked_keywords,
+	block_links, allowed_domains, use_classifier, classifier_threshold,
+	block_minutes, updated_at`
+
+func (r *PostgresPolicyRepository) FindByTenant(ctx context.Context, tenantID kernel.TenantID) (*spamfilter.Policy, error) {
+	query := `SELECT ` + policyColumns + ` FROM spam_filter_policies WHERE tenant_id = $1`
+
+	var policy spamfilter.Policy
+	if err := r.db.GetContext(ctx, &policy, query, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return spamfilter.DefaultPolicy(tenantID), nil
+		}
+		return nil, errx.Wrap(err, "failed to find spam filter policy", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	return &policy, nil
+}
+
+func (r *PostgresPolicyRepository) Save(ctx context.Context, policy spamfilter.Policy) error {
+	query := `
+		INSERT INTO spam_filter_policies (
+			tenant_id, enabled, action, max_messages_per_minute, blocked_keywords,
+			block_links, allowed_domains, use_classifier, classifier_threshold,
+			block_minutes
+		) VALUES (
+			:tenant_id, :enabled, :action, :max_messages_per_minute, :blocked_keywords,
+			:block_links, :allowed_domains, :use_classifier, :classifier_threshold,
+			:block_minutes
+		)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			action = EXCLUDED.action,
+			max_messages_per_minute = EXCLUDED.max_messages_per_minute,
+			blocked_keywords = EXCLUDED.blocked_keywords,
+			block_links = EXCLUDED.block_links,
+			allowed_domains = EXCLUDED.allowed_domains,
+			use_classifier = EXCLUDED.use_classifier,
+			classifier_threshold = EXCLUDED.classifier_threshold,
+			block_minutes = EXCLUDED.block_minutes`
+
+	if _, err := r.db.NamedExecContext(ctx, query, policy); err != nil {
+		return errx.Wrap(err, "failed to save spam filter policy", errx.TypeInternal).
+			WithDetail("tenant_id", policy.TenantID.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package spamfiltersrv
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/pkg/ratelimit"
+	"github.com/Abraxas-365/relay/spamfilter"
+)
+
+// policyCacheTTL how long a policy is reused on the inbound hot path
+const policyCacheTTL = time.Minute
+
+type cachedPolicy struct {
+	policy    *spamfilter.Policy
+	expiresAt time.Time
+}
+
+// FilterService screens inbound messages against each tenant's spam filter
+// policy before they are recorded or trigger workflows
+type FilterService struct {
+	policyRepo spamfilter.PolicyRepository
+	blockRepo  spamfilter.BlockRepository
+	limiter    ratelimit.Limiter     // nil = the sender rate is not checked
+	classifier spamfilter.Classifier // nil = use_classifier is rejected
+	metrics    *spamfilter.Metrics
+
+	mu    sync.RWMutex
+	cache map[kernel.TenantID]cachedPolicy
+}
+
+var _ channels.InboundFilter = (*FilterService)(nil)
+
+func NewFilterService(
+	policyRepo spamfilter.PolicyRepository,
+	blockRepo spamfilter.BlockRepository,
+	limiter ratelimit.Limiter,
+	classifier spamfilter.Classifier,
+	metrics *spamfilter.Metrics,
+) *FilterService {
+	return &FilterService{
+		policyRepo: policyRepo,
+		blockRepo:  blockRepo,
+		limiter:    limiter,
+		classifier: classifier,
+		metrics:    metrics,
+		cache:      make(map[kernel.TenantID]cachedPolicy),
+	}
+}
+
+// ============================================================================
+// Filtering
+// ============================================================================
+
+// FilterInbound implements channels.InboundFilter. Lookup failures let the
+// message through: a broken filter must not silence every conversation.
+func (s *FilterService) FilterInbound(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage) channels.FilterVerdict {
+	policy := s.cachedPolicy(ctx, channel.TenantID)
+	if policy == nil || !policy.Enabled {
+		return channels.FilterAllow
+	}
+	s.metrics.RecordChecked(channel.TenantID)
+
+	blocked, err := s.blockRepo.IsBlocked(ctx, channel.TenantID, channel.ID, msg.SenderID)
+	if err != nil {
+		log.Printf("⚠️  Failed to check blocked sender %s: %v", msg.SenderID, err)
+	} else if blocked {
+		s.metrics.RecordFiltered(channel.TenantID, spamfilter.ReasonBlocked, spamfilter.ActionDrop)
+		return channels.FilterDrop
+	}
+
+	reason, detail := s.screen(ctx, policy, channel, msg)
+	if reason == "" {
+		return channels.FilterAllow
+	}
+
+	log.Printf("🚫 Message from %s on channel %s tripped the spam filter (%s: %s), action %s",
+		msg.SenderID, channel.ID, reason, detail, policy.Action)
+	s.metrics.RecordFiltered(channel.TenantID, reason, policy.Action)
+
+	switch policy.Action {
+	case spamfilter.ActionFlag:
+		if msg.Metadata == nil {
+			msg.Metadata = make(map[string]any)
+		}
+		msg.Metadata["spam"] = map[string]any{
+			"reason": string(reason),
+			"detail": detail,
+		}
+		return channels.FilterFlag
+
+	case spamfilter.ActionBlock:
+		block := spamfilter.NewBlockedSender(channel.TenantID, channel.ID, msg.SenderID,
+			fmt.Sprintf("%s: %s", reason, detail), spamfilter.BlockedByFilter, policy.BlockDuration())
+		if err := s.blockRepo.Save(ctx, *block); err != nil {
+			log.Printf("⚠️  Failed to block sender %s: %v", msg.SenderID, err)
+		}
+		return channels.FilterDrop
+
+	default:
+		return channels.FilterDrop
+	}
+}
+
+// screen runs the rules, cheapest first, and returns the first one tripped
+func (s *FilterService) screen(ctx context.Context, policy *spamfilter.Policy, channel *channels.Channel, msg *channels.IncomingMessage) (spamfilter.Reason, string) {
+	if policy.MaxMessagesPerMinute > 0 && s.limiter != nil {
+		key := fmt.Sprintf("spam:%s:%s:%s", channel.TenantID, channel.ID, msg.SenderID)
+		rule := ratelimit.Rule{Requests: policy.MaxMessagesPerMinute, Period: time.Minute}
+		result, err := s.limiter.Allow(ctx, key, rule)
+		if err != nil {
+			log.Printf("⚠️  Failed to check sender rate for %s: %v", msg.SenderID, err)
+		} else if !result.Allowed {
+			return spamfilter.ReasonRate, fmt.Sprintf("more than %d messages per minute", policy.MaxMessagesPerMinute)
+		}
+	}
+
+	text := strings.TrimSpace(msg.Content.Text + " " + msg.Content.Caption)
+	if text == "" {
+		return "", ""
+	}
+
+	if keyword, ok := policy.MatchKeyword(text); ok {
+		return spamfilter.ReasonKeyword, keyword
+	}
+	if link, ok := policy.MatchLink(text); ok {
+		return spamfilter.ReasonLink, link
+	}
+
+	if policy.UseClassifier && s.classifier != nil {
+		score, err := s.classifier.Classify(ctx, text)
+		if err != nil {
+			log.Printf("⚠️  Spam classifier failed for message from %s: %v", msg.SenderID, err)
+		} else if score >= policy.ClassifierThreshold {
+			return spamfilter.ReasonClassifier, fmt.Sprintf("score %.2f", score)
+		}
+	}
+
+	return "", ""
+}
+
+// cachedPolicy returns the tenant's policy, reusing it for policyCacheTTL
+func (s *FilterService) cachedPolicy(ctx context.Context, tenantID kernel.TenantID) *spamfilter.Policy {
+	s.mu.RLock()
+	cached, ok := s.cache[tenantID]
+	s.mu.RUnlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.policy
+	}
+
+	policy, err := s.policyRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load spam filter policy for tenant %s: %v", tenantID, err)
+		return nil
+	}
+
+	s.mu.Lock()
+	s.cache[tenantID] = cachedPolicy{policy: policy, expiresAt: time.Now().Add(policyCacheTTL)}
+	s.mu.Unlock()
+
+	return policy
+}
+
+// ============================================================================
+// Policy
+// ============================================================================
+
+// GetPolicy returns the tenant's stored or default policy
+func (s *FilterService) GetPolicy(ctx context.Context, tenantID kernel.TenantID) (*spamfilter.Policy, error) {
+	return s.policyRepo.FindByTenant(ctx, tenantID)
+}
+
+// UpdatePolicy validates and stores a partial policy update
+func (s *FilterService) UpdatePolicy(ctx context.Context, tenantID kernel.TenantID, req spamfilter.UpdatePolicyRequest) (*spamfilter.Policy, error) {
+	policy, err := s.policyRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Apply(policy)
+	policy.Normalize()
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	if policy.UseClassifier && s.classifier == nil {
+		return nil, spamfilter.ErrClassifierNotConfigured()
+	}
+
+	policy.UpdatedAt = time.Now()
+	if err := s.policyRepo.Save(ctx, *policy); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	delete(s.cache, tenantID)
+	s.mu.Unlock()
+
+	log.Printf("✅ Spam filter policy updated for tenant %s (enabled=%v action=%s rate=%d/min keywords=%d links=%v classifier=%v)",
+		tenantID, policy.Enabled, policy.Action, policy.MaxMessagesPerMinute,
+		len(policy.BlockedKeywords), policy.BlockLinks, policy.UseClassifier)
+
+	return policy, nil
+}
+
+// ============================================================================
+// Blocked Senders
+// ============================================================================
+
+// BlockSender blocks a sender by hand
+func (s *FilterService) BlockSender(ctx context.Context, tenantID kernel.TenantID, blockedBy kernel.UserID, req spamfilter.BlockSenderRequest) (*spamfilter.BlockedSender, error) {
+	senderID := strings.TrimSpace(req.SenderID)
+	if req.ChannelID == "" || senderID == "" {
+		return nil, spamfilter.ErrInvalidBlock().WithDetail("reason", "channel_id and sender_id are required")
+	}
+	if req.Minutes < 0 {
+		return nil, spamfilter.ErrInvalidBlock().WithDetail("minutes", "must not be negative")
+	}
+
+	block := spamfilter.NewBlockedSender(tenantID, req.ChannelID, senderID, req.Reason,
+		blockedBy.String(), time.Duration(req.Minutes)*time.Minute)
+	if err := s.blockRepo.Save(ctx, *block); err != nil {
+		return nil, err
+	}
+
+	log.Printf("🚫 Sender %s blocked on channel %s by %s", senderID, req.ChannelID, blockedBy)
+	return block, nil
+}
+
+// UnblockSender lifts a sender's block
+func (s *FilterService) UnblockSender(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, senderID string) error {
+	return s.blockRepo.Delete(ctx, tenantID, channelID, senderID)
+}
+
+// ListBlocked returns the tenant's active blocks, newest first
+func (s *FilterService) ListBlocked(ctx context.Context, req spamfilter.ListBlockedRequest) (spamfilter.BlockedListResponse, error) {
+	return s.blockRepo.List(ctx, req)
+}
+
+// Metrics returns the tenant's filtered volume since the process started
+func (s *FilterService) Metrics(tenantID kernel.TenantID) spamfilter.TenantMetrics {
+	return s.metrics.Tenant(tenantID)
+}
@@ -0,0 +1,87 @@
+package survey
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// ListSurveysRequest request to list a tenant's surveys, newest first
+type ListSurveysRequest struct {
+	storex.PaginationOptions
+
+	TenantID   kernel.TenantID `json:"tenant_id" validate:"required"`
+	WorkflowID string          `json:"workflow_id,omitempty"`
+	AgentID    string          `json:"agent_id,omitempty"`
+	Status     Status          `json:"status,omitempty"`
+}
+
+func (r ListSurveysRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+// StatsGroup is what survey scores are aggregated by
+type StatsGroup string
+
+const (
+	GroupByWorkflow StatsGroup = "workflow"
+	GroupByAgent    StatsGroup = "agent"
+)
+
+// StatsRequest request to aggregate the scores of surveys sent in a period
+type StatsRequest struct {
+	TenantID   kernel.TenantID `json:"tenant_id" validate:"required"`
+	GroupBy    StatsGroup      `json:"group_by"`
+	WorkflowID string          `json:"workflow_id,omitempty"`
+	AgentID    string          `json:"agent_id,omitempty"`
+	From       *time.Time      `json:"from,omitempty"`
+	To         *time.Time      `json:"to,omitempty"`
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+type SurveyListResponse = storex.Paginated[Survey]
+
+// ScoreCount is the raw aggregate of one group and scale, as counted by
+// the repository
+type ScoreCount struct {
+	Key        string             `db:"key"`
+	Scale      engine.SurveyScale `db:"scale"`
+	Sent       int                `db:"sent"`
+	Answered   int                `db:"answered"`
+	ScoreSum   int                `db:"score_sum"`
+	Satisfied  int                `db:"satisfied"`  // CSAT 4-5
+	Promoters  int                `db:"promoters"`  // NPS 9-10
+	Detractors int                `db:"detractors"` // NPS 0-6
+}
+
+// ScoreStats summarizes one workflow's or agent's scores on a scale. CSAT
+// is the share of satisfied answers and NPS promoters minus detractors,
+// both in percent.
+type ScoreStats struct {
+	Key          string             `json:"key"` // Workflow or agent ID; empty for surveys without an agent
+	Scale        engine.SurveyScale `json:"scale"`
+	Sent         int                `json:"sent"`
+	Answered     int                `json:"answered"`
+	ResponseRate float64            `json:"response_rate"`
+	Average      float64            `json:"average"`
+	CSAT         *float64           `json:"csat,omitempty"`
+	NPS          *float64           `json:"nps,omitempty"`
+	Promoters    int                `json:"promoters,omitempty"`
+	Passives     int                `json:"passives,omitempty"`
+	Detractors   int                `json:"detractors,omitempty"`
+}
+
+// StatsResponse is the aggregate scores of each group
+type StatsResponse struct {
+	GroupBy StatsGroup   `json:"group_by"`
+	Groups  []ScoreStats `json:"groups"`
+}
@@ -0,0 +1,34 @@
+package survey
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("SURVEY")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeSurveyNotFound = ErrRegistry.Register("SURVEY_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Survey not found")
+	CodeInvalidFilter  = ErrRegistry.Register("INVALID_FILTER", errx.TypeValidation, http.StatusBadRequest, "Invalid survey filter")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrSurveyNotFound() *errx.Error {
+	return ErrRegistry.New(CodeSurveyNotFound)
+}
+
+func ErrInvalidFilter() *errx.Error {
+	return ErrRegistry.New(CodeInvalidFilter)
+}
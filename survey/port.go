@@ -0,0 +1,25 @@
+package survey
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// SurveyRepository persists surveys and their answers
+type SurveyRepository interface {
+	// Save creates or updates a survey
+	Save(ctx context.Context, s Survey) error
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*Survey, error)
+
+	// FindPending returns the contact's latest survey still waiting for an
+	// answer, or nil when there is none
+	FindPending(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string) (*Survey, error)
+
+	List(ctx context.Context, req ListSurveysRequest) (SurveyListResponse, error)
+	Stats(ctx context.Context, req StatsRequest) ([]ScoreCount, error)
+}
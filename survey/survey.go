@@ -0,0 +1,160 @@
+package survey
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Surveys
+// ============================================================================
+
+// Status is where a survey is in its life
+type Status string
+
+const (
+	StatusPending  Status = "PENDING"  // Waiting for the contact's answer
+	StatusAnswered Status = "ANSWERED" // Scored
+	StatusExpired  Status = "EXPIRED"  // Timed out or abandoned after invalid answers
+)
+
+// Survey is a rating question a SURVEY node sent to a contact and, once
+// answered, its score. AgentID credits the operator who handled the
+// conversation, when there was one.
+type Survey struct {
+	ID             string             `json:"id"`
+	TenantID       kernel.TenantID    `json:"tenant_id"`
+	WorkflowID     string             `json:"workflow_id"`
+	NodeID         string             `json:"node_id"`
+	ChannelID      kernel.ChannelID   `json:"channel_id"`
+	ConversationID string             `json:"conversation_id"`
+	AgentID        string             `json:"agent_id,omitempty"`
+	Scale          engine.SurveyScale `json:"scale"`
+	Question       string             `json:"question"`
+	Status         Status             `json:"status"`
+	Score          *int               `json:"score,omitempty"`
+	Answer         string             `json:"answer,omitempty"` // What the contact sent
+	Attempts       int                `json:"attempts"`         // Invalid answers so far
+	CreatedAt      time.Time          `json:"created_at"`
+	ExpiresAt      time.Time          `json:"expires_at"`
+	AnsweredAt     *time.Time         `json:"answered_at,omitempty"`
+
+	// Resuming the paused run
+	NextNodeID     string         `json:"-"`
+	ContinuationID string         `json:"-"`
+	NodeContext    map[string]any `json:"-"`
+	InvalidReply   string         `json:"-"`
+	MaxAttempts    int            `json:"-"`
+}
+
+// NewSurvey records the question a SURVEY node sent
+func NewSurvey(id string, pending engine.PendingSurvey, now time.Time) *Survey {
+	return &Survey{
+		ID:             id,
+		TenantID:       pending.TenantID,
+		WorkflowID:     pending.WorkflowID,
+		NodeID:         pending.NodeID,
+		ChannelID:      pending.ChannelID,
+		ConversationID: pending.ConversationID,
+		AgentID:        pending.AgentID,
+		Scale:          pending.Scale,
+		Question:       pending.Question,
+		Status:         StatusPending,
+		CreatedAt:      now,
+		ExpiresAt:      pending.ExpiresAt,
+		NextNodeID:     pending.NextNodeID,
+		ContinuationID: pending.ContinuationID,
+		NodeContext:    pending.NodeContext,
+		InvalidReply:   pending.InvalidReply,
+		MaxAttempts:    pending.MaxAttempts,
+	}
+}
+
+// Refresh marks a pending survey past its deadline as expired; the timeout
+// continuation resumes the run without touching the survey
+func (s *Survey) Refresh(now time.Time) {
+	if s.Status == StatusPending && !now.Before(s.ExpiresAt) {
+		s.Status = StatusExpired
+	}
+}
+
+// Record stores a valid answer
+func (s *Survey) Record(score int, answer string, now time.Time) {
+	s.Status = StatusAnswered
+	s.Score = &score
+	s.Answer = answer
+	s.AnsweredAt = &now
+}
+
+// ParseScore reads the score from the chosen option or the typed number.
+// false when the answer is not on the survey's scale.
+func (s *Survey) ParseScore(content channels.MessageContent) (int, bool) {
+	value := strings.TrimSpace(content.Text)
+	if content.Postback != nil && strings.HasPrefix(content.Postback.ID, engine.SurveyPostbackPrefix) {
+		value = strings.TrimPrefix(content.Postback.ID, engine.SurveyPostbackPrefix)
+	}
+
+	// "4/5" and "8 / 10" count as the number before the slash
+	value, _, _ = strings.Cut(value, "/")
+	score, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+
+	low, high := s.Scale.Range()
+	return score, score >= low && score <= high
+}
+
+// InvalidReplyText is the message sent after an answer off the scale
+func (s *Survey) InvalidReplyText() string {
+	if s.InvalidReply != "" {
+		return s.InvalidReply
+	}
+	low, high := s.Scale.Range()
+	return "Please answer with a number from " + strconv.Itoa(low) + " to " + strconv.Itoa(high) + "."
+}
+
+// Output is what the SURVEY node hands to the nodes after it
+func (s *Survey) Output() map[string]any {
+	output := map[string]any{
+		"survey_id": s.ID,
+		"scale":     string(s.Scale),
+		"status":    string(s.Status),
+		"answered":  s.Status == StatusAnswered,
+	}
+	if s.Score != nil {
+		output["score"] = *s.Score
+		output["answer"] = s.Answer
+		if s.Scale == engine.SurveyScaleNPS {
+			output["category"] = NPSCategory(*s.Score)
+		} else {
+			output["satisfied"] = *s.Score >= SatisfiedScore
+		}
+	}
+	return output
+}
+
+// ============================================================================
+// Scoring
+// ============================================================================
+
+// SatisfiedScore is the lowest CSAT score counted as satisfied
+const SatisfiedScore = 4
+
+// NPSCategory classifies an NPS score: promoter (9-10), passive (7-8) or
+// detractor (0-6)
+func NPSCategory(score int) string {
+	switch {
+	case score >= 9:
+		return "promoter"
+	case score >= 7:
+		return "passive"
+	default:
+		return "detractor"
+	}
+}
@@ -0,0 +1,126 @@
+package surveyapi
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/survey"
+	"github.com/Abraxas-365/relay/survey/surveysrv"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// SurveyHandler exposes the surveys sent by workflows and their scores
+type SurveyHandler struct {
+	service *surveysrv.SurveyService
+}
+
+// NewSurveyHandler creates a new survey handler
+func NewSurveyHandler(service *surveysrv.SurveyService) *SurveyHandler {
+	return &SurveyHandler{
+		service: service,
+	}
+}
+
+// List returns the tenant's surveys, filtered by ?workflow_id=, ?agent_id=
+// and ?status=
+// GET /api/surveys
+func (h *SurveyHandler) List(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", defaultPageSize)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	req := survey.ListSurveysRequest{
+		PaginationOptions: storex.PaginationOptions{
+			Page:     page,
+			PageSize: pageSize,
+		},
+		TenantID:   authContext.TenantID,
+		WorkflowID: c.Query("workflow_id"),
+		AgentID:    c.Query("agent_id"),
+		Status:     survey.Status(c.Query("status")),
+	}
+	switch req.Status {
+	case "", survey.StatusPending, survey.StatusAnswered, survey.StatusExpired:
+	default:
+		return survey.ErrInvalidFilter().WithDetail("status", "must be PENDING, ANSWERED or EXPIRED")
+	}
+
+	surveys, err := h.service.List(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(surveys)
+}
+
+// Get returns one survey
+// GET /api/surveys/:id
+func (h *SurveyHandler) Get(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	record, err := h.service.Get(c.Context(), authContext.TenantID, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(record)
+}
+
+// Stats returns CSAT and NPS aggregates per workflow (?group_by=workflow,
+// the default) or per agent (?group_by=agent), for surveys sent between
+// ?from= and ?to=
+// GET /api/surveys/stats
+func (h *SurveyHandler) Stats(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	req := survey.StatsRequest{
+		TenantID:   authContext.TenantID,
+		GroupBy:    survey.StatsGroup(c.Query("group_by")),
+		WorkflowID: c.Query("workflow_id"),
+		AgentID:    c.Query("agent_id"),
+	}
+	for param, target := range map[string]**time.Time{"from": &req.From, "to": &req.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return survey.ErrInvalidFilter().WithDetail(param, "must be an RFC 3339 timestamp")
+		}
+		*target = &parsed
+	}
+
+	stats, err := h.service.Stats(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(stats)
+}
@@ -0,0 +1,29 @@
+package surveyapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SurveyRoutes handles survey route setup
+type SurveyRoutes struct {
+	handler        *SurveyHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewSurveyRoutes creates a new survey routes instance
+func NewSurveyRoutes(handler *SurveyHandler, authMiddleware *auth.AuthMiddleware) *SurveyRoutes {
+	return &SurveyRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers survey routes on an authenticated router
+func (r *SurveyRoutes) RegisterRoutes(router fiber.Router) {
+	surveys := router.Group("/surveys")
+
+	surveys.Get("/", r.handler.List)
+	surveys.Get("/stats", r.handler.Stats)
+	surveys.Get("/:id", r.handler.Get)
+}
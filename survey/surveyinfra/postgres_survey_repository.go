@@ -0,0 +1,282 @@
+package surveyinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/survey"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresSurveyRepository struct {
+	db *sqlx.DB
+}
+
+var _ survey.SurveyRepository = (*PostgresSurveyRepository)(nil)
+
+func NewPostgresSurveyRepository(db *sqlx.DB) *PostgresSurveyRepository {
+	return &PostgresSurveyRepository{db: db}
+}
+
+// dbSurvey is an intermediate struct for database operations
+type dbSurvey struct {
+	ID             string          `db:"id"`
+	TenantID       string          `db:"tenant_id"`
+	WorkflowID     string          `db:"workflow_id"`
+	NodeID         string          `db:"node_id"`
+	ChannelID      string          `db:"channel_id"`
+	ConversationID string          `db:"conversation_id"`
+	AgentID        string          `db:"agent_id"`
+	Scale          string          `db:"scale"`
+	Question       string          `db:"question"`
+	Status         string          `db:"status"`
+	Score          sql.NullInt64   `db:"score"`
+	Answer         string          `db:"answer"`
+	Attempts       int             `db:"attempts"`
+	MaxAttempts    int             `db:"max_attempts"`
+	InvalidReply   string          `db:"invalid_reply"`
+	NextNodeID     string          `db:"next_node_id"`
+	ContinuationID string          `db:"continuation_id"`
+	NodeContext    json.RawMessage `db:"node_context"`
+	CreatedAt      time.Time       `db:"created_at"`
+	ExpiresAt      time.Time       `db:"expires_at"`
+	AnsweredAt     sql.NullTime    `db:"answered_at"`
+}
+
+const surveyColumns = `
+	id, tenant_id, workflow_id, node_id, channel_id, conversation_id, agent_id,
+	scale, question, status, score, answer, attempts, max_attempts, invalid_reply,
+	next_node_id, continuation_id, node_context, created_at, expires_at, answered_at`
+
+func (r *PostgresSurveyRepository) Save(ctx context.Context, s survey.Survey) error {
+	nodeContext := s.NodeContext
+	if nodeContext == nil {
+		nodeContext = map[string]any{}
+	}
+	contextJSON, err := json.Marshal(nodeContext)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal survey context", errx.TypeInternal)
+	}
+
+	var score sql.NullInt64
+	if s.Score != nil {
+		score = sql.NullInt64{Int64: int64(*s.Score), Valid: true}
+	}
+	var answeredAt sql.NullTime
+	if s.AnsweredAt != nil {
+		answeredAt = sql.NullTime{Time: *s.AnsweredAt, Valid: true}
+	}
+
+	query := `
+		INSERT INTO surveys (
+			id, tenant_id, workflow_id, node_id, channel_id, conversation_id, agent_id,
+			scale, question, status, score, answer, attempts, max_attempts, invalid_reply,
+			next_node_id, continuation_id, node_context, created_at, expires_at, answered_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			score = EXCLUDED.score,
+			answer = EXCLUDED.answer,
+			attempts = EXCLUDED.attempts,
+			answered_at = EXCLUDED.answered_at`
+
+	_, err = r.db.ExecContext(ctx, query,
+		s.ID, s.TenantID.String(), s.WorkflowID, s.NodeID, s.ChannelID.String(), s.ConversationID, s.AgentID,
+		string(s.Scale), s.Question, string(s.Status), score, s.Answer, s.Attempts, s.MaxAttempts, s.InvalidReply,
+		s.NextNodeID, s.ContinuationID, contextJSON, s.CreatedAt, s.ExpiresAt, answeredAt,
+	)
+	if err != nil {
+		return errx.Wrap(err, "failed to save survey", errx.TypeInternal).
+			WithDetail("survey_id", s.ID)
+	}
+
+	return nil
+}
+
+func (r *PostgresSurveyRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*survey.Survey, error) {
+	query := `SELECT ` + surveyColumns + ` FROM surveys WHERE id = $1 AND tenant_id = $2`
+
+	var row dbSurvey
+	if err := r.db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, survey.ErrSurveyNotFound().WithDetail("survey_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find survey", errx.TypeInternal).
+			WithDetail("survey_id", id)
+	}
+
+	return toDomainSurvey(&row)
+}
+
+func (r *PostgresSurveyRepository) FindPending(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID, conversationID string) (*survey.Survey, error) {
+	query := `SELECT ` + surveyColumns + ` FROM surveys
+		WHERE tenant_id = $1 AND channel_id = $2 AND conversation_id = $3
+			AND status = 'PENDING' AND expires_at > NOW()
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	var row dbSurvey
+	if err := r.db.GetContext(ctx, &row, query, tenantID.String(), channelID.String(), conversationID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errx.Wrap(err, "failed to find pending survey", errx.TypeInternal).
+			WithDetail("conversation_id", conversationID)
+	}
+
+	return toDomainSurvey(&row)
+}
+
+func (r *PostgresSurveyRepository) List(ctx context.Context, req survey.ListSurveysRequest) (survey.SurveyListResponse, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.WorkflowID != "" {
+		conditions = append(conditions, fmt.Sprintf("workflow_id = $%d", argPos))
+		args = append(args, req.WorkflowID)
+		argPos++
+	}
+	if req.AgentID != "" {
+		conditions = append(conditions, fmt.Sprintf("agent_id = $%d", argPos))
+		args = append(args, req.AgentID)
+		argPos++
+	}
+	// Pending surveys past their deadline are reported as expired
+	switch req.Status {
+	case survey.StatusPending:
+		conditions = append(conditions, "status = 'PENDING' AND expires_at > NOW()")
+	case survey.StatusExpired:
+		conditions = append(conditions, "(status = 'EXPIRED' OR (status = 'PENDING' AND expires_at <= NOW()))")
+	case survey.StatusAnswered:
+		conditions = append(conditions, "status = 'ANSWERED'")
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM surveys WHERE `+where, args...); err != nil {
+		return survey.SurveyListResponse{}, errx.Wrap(err, "failed to count surveys", errx.TypeInternal)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM surveys WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d`, surveyColumns, where, argPos, argPos+1)
+	args = append(args, req.PageSize, req.GetOffset())
+
+	var rows []dbSurvey
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return survey.SurveyListResponse{}, errx.Wrap(err, "failed to list surveys", errx.TypeInternal)
+	}
+
+	now := time.Now()
+	surveys := make([]survey.Survey, 0, len(rows))
+	for i := range rows {
+		s, err := toDomainSurvey(&rows[i])
+		if err != nil {
+			return survey.SurveyListResponse{}, err
+		}
+		s.Refresh(now)
+		surveys = append(surveys, *s)
+	}
+
+	return storex.NewPaginated(surveys, req.Page, req.PageSize, total), nil
+}
+
+func (r *PostgresSurveyRepository) Stats(ctx context.Context, req survey.StatsRequest) ([]survey.ScoreCount, error) {
+	key := "workflow_id"
+	if req.GroupBy == survey.GroupByAgent {
+		key = "agent_id"
+	}
+
+	conditions := []string{"tenant_id = $1"}
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.WorkflowID != "" {
+		conditions = append(conditions, fmt.Sprintf("workflow_id = $%d", argPos))
+		args = append(args, req.WorkflowID)
+		argPos++
+	}
+	if req.AgentID != "" {
+		conditions = append(conditions, fmt.Sprintf("agent_id = $%d", argPos))
+		args = append(args, req.AgentID)
+		argPos++
+	}
+	if req.From != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argPos))
+		args = append(args, *req.From)
+		argPos++
+	}
+	if req.To != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", argPos))
+		args = append(args, *req.To)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			%[1]s AS key,
+			scale,
+			COUNT(*) AS sent,
+			COUNT(score) AS answered,
+			COALESCE(SUM(score), 0) AS score_sum,
+			COUNT(*) FILTER (WHERE score >= %[3]d) AS satisfied,
+			COUNT(*) FILTER (WHERE score >= 9) AS promoters,
+			COUNT(*) FILTER (WHERE score <= 6) AS detractors
+		FROM surveys
+		WHERE %[2]s
+		GROUP BY %[1]s, scale
+		ORDER BY sent DESC, key ASC`,
+		key, strings.Join(conditions, " AND "), survey.SatisfiedScore)
+
+	var counts []survey.ScoreCount
+	if err := r.db.SelectContext(ctx, &counts, query, args...); err != nil {
+		return nil, errx.Wrap(err, "failed to aggregate survey scores", errx.TypeInternal)
+	}
+
+	return counts, nil
+}
+
+func toDomainSurvey(row *dbSurvey) (*survey.Survey, error) {
+	s := &survey.Survey{
+		ID:             row.ID,
+		TenantID:       kernel.TenantID(row.TenantID),
+		WorkflowID:     row.WorkflowID,
+		NodeID:         row.NodeID,
+		ChannelID:      kernel.ChannelID(row.ChannelID),
+		ConversationID: row.ConversationID,
+		AgentID:        row.AgentID,
+		Scale:          engine.SurveyScale(row.Scale),
+		Question:       row.Question,
+		Status:         survey.Status(row.Status),
+		Answer:         row.Answer,
+		Attempts:       row.Attempts,
+		MaxAttempts:    row.MaxAttempts,
+		InvalidReply:   row.InvalidReply,
+		NextNodeID:     row.NextNodeID,
+		ContinuationID: row.ContinuationID,
+		CreatedAt:      row.CreatedAt,
+		ExpiresAt:      row.ExpiresAt,
+	}
+	if row.Score.Valid {
+		score := int(row.Score.Int64)
+		s.Score = &score
+	}
+	if row.AnsweredAt.Valid {
+		s.AnsweredAt = &row.AnsweredAt.Time
+	}
+	if len(row.NodeContext) > 0 {
+		if err := json.Unmarshal(row.NodeContext, &s.NodeContext); err != nil {
+			return nil, errx.Wrap(err, "failed to unmarshal survey context", errx.TypeInternal).
+				WithDetail("survey_id", row.ID)
+		}
+	}
+	return s, nil
+}
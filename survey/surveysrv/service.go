@@ -0,0 +1,267 @@
+package surveysrv
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/engine"
+	"github.com/Abraxas-365/relay/inbox"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/survey"
+	"github.com/google/uuid"
+)
+
+// SurveyService records the questions SURVEY nodes send, scores the
+// contacts' answers and resumes the paused runs with them
+type SurveyService struct {
+	surveyRepo     survey.SurveyRepository
+	scheduler      engine.DelayScheduler
+	channelManager channels.ChannelManager
+	resume         engine.ContinuationHandler
+	claimRepo      inbox.ClaimRepository // nil = scores are not credited to operators
+}
+
+var (
+	_ engine.SurveyRecorder       = (*SurveyService)(nil)
+	_ channels.InboundInterceptor = (*SurveyService)(nil)
+)
+
+func NewSurveyService(
+	surveyRepo survey.SurveyRepository,
+	scheduler engine.DelayScheduler,
+	channelManager channels.ChannelManager,
+	resume engine.ContinuationHandler,
+) *SurveyService {
+	return &SurveyService{
+		surveyRepo:     surveyRepo,
+		scheduler:      scheduler,
+		channelManager: channelManager,
+		resume:         resume,
+	}
+}
+
+// SetClaimRepository credits scores to the operator who owns the
+// conversation. The inbox is built after the engine, hence the setter.
+func (s *SurveyService) SetClaimRepository(claimRepo inbox.ClaimRepository) {
+	s.claimRepo = claimRepo
+}
+
+// ============================================================================
+// Recording
+// ============================================================================
+
+// OpenSurvey implements engine.SurveyRecorder. Without an explicit agent the
+// score is credited to the operator who owns the conversation.
+func (s *SurveyService) OpenSurvey(ctx context.Context, pending engine.PendingSurvey) (string, error) {
+	if pending.AgentID == "" && s.claimRepo != nil {
+		claim, err := s.claimRepo.FindByConversation(ctx, pending.TenantID, pending.ChannelID, pending.ConversationID)
+		if err != nil {
+			log.Printf("⚠️  Failed to look up the operator of %s for a survey: %v", pending.ConversationID, err)
+		} else if claim != nil {
+			pending.AgentID = claim.UserID.String()
+		}
+	}
+
+	record := survey.NewSurvey(uuid.NewString(), pending, time.Now())
+	if err := s.surveyRepo.Save(ctx, *record); err != nil {
+		return "", err
+	}
+	return record.ID, nil
+}
+
+// ============================================================================
+// Answers
+// ============================================================================
+
+// InterceptInbound implements channels.InboundInterceptor: a reply to a
+// pending survey is scored and resumes the run, without triggering
+// workflows. Invalid answers are re-asked until the survey is abandoned;
+// then the run takes its timeout path and the message is processed as usual.
+func (s *SurveyService) InterceptInbound(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage) bool {
+	pending, err := s.surveyRepo.FindPending(ctx, channel.TenantID, channel.ID, msg.SenderID)
+	if err != nil {
+		log.Printf("⚠️  Failed to look up pending survey for %s: %v", msg.SenderID, err)
+		return false
+	}
+	if pending == nil {
+		return false
+	}
+
+	score, ok := pending.ParseScore(msg.Content)
+	if !ok {
+		return s.rejectAnswer(ctx, pending, msg)
+	}
+
+	// Whoever drops the continuation first owns the run; losing means the
+	// timeout is already resuming it
+	if err := s.scheduler.Cancel(ctx, pending.ContinuationID); err != nil {
+		log.Printf("⏰ Survey %s answered after its timeout fired: %v", pending.ID, err)
+		s.expire(ctx, pending)
+		return false
+	}
+
+	now := time.Now()
+	pending.Record(score, msg.Content.Text, now)
+	if err := s.surveyRepo.Save(ctx, *pending); err != nil {
+		log.Printf("❌ Failed to save answer to survey %s: %v", pending.ID, err)
+	}
+	log.Printf("⭐ Survey %s answered by %s: %d (%s)", pending.ID, msg.SenderID, score, pending.Scale)
+
+	continuation := &engine.WorkflowContinuation{
+		ID:             pending.ContinuationID,
+		WorkflowID:     pending.WorkflowID,
+		TenantID:       pending.TenantID.String(),
+		NodeID:         pending.NodeID,
+		NextNodeID:     pending.NextNodeID,
+		NodeContext:    answeredContext(pending),
+		ScheduledFor:   now,
+		CreatedAt:      pending.CreatedAt,
+		ConversationID: pending.ConversationID,
+		ChannelID:      pending.ChannelID.String(),
+	}
+
+	// The run may be long; the webhook has to be answered now
+	go func() {
+		if err := s.resume(context.Background(), continuation); err != nil {
+			log.Printf("❌ Failed to resume workflow %s after survey %s: %v", pending.WorkflowID, pending.ID, err)
+		}
+	}()
+
+	return true
+}
+
+// rejectAnswer asks again for a score on the scale, or abandons the survey
+// once the contact used up their attempts
+func (s *SurveyService) rejectAnswer(ctx context.Context, pending *survey.Survey, msg *channels.IncomingMessage) bool {
+	pending.Attempts++
+	if pending.Attempts > pending.MaxAttempts {
+		log.Printf("🚫 Survey %s abandoned after %d invalid answers", pending.ID, pending.Attempts)
+		s.expire(ctx, pending)
+		if err := s.scheduler.ResumeNow(ctx, pending.ContinuationID); err != nil {
+			log.Printf("⚠️  Failed to resume timeout path of survey %s: %v", pending.ID, err)
+		}
+		return false
+	}
+
+	if err := s.surveyRepo.Save(ctx, *pending); err != nil {
+		log.Printf("❌ Failed to save survey %s: %v", pending.ID, err)
+	}
+
+	reply := channels.OutgoingMessage{
+		RecipientID: msg.SenderID,
+		Content: channels.MessageContent{
+			Type: "text",
+			Text: pending.InvalidReplyText(),
+		},
+		Metadata: map[string]any{
+			"origin":           "workflow",
+			"workflow_id":      pending.WorkflowID,
+			"workflow_node_id": pending.NodeID,
+			"survey_id":        pending.ID,
+		},
+	}
+	if err := s.channelManager.SendMessage(ctx, pending.TenantID, pending.ChannelID, reply); err != nil {
+		log.Printf("⚠️  Failed to ask again for survey %s: %v", pending.ID, err)
+	}
+
+	return true
+}
+
+func (s *SurveyService) expire(ctx context.Context, pending *survey.Survey) {
+	pending.Status = survey.StatusExpired
+	if err := s.surveyRepo.Save(ctx, *pending); err != nil {
+		log.Printf("❌ Failed to expire survey %s: %v", pending.ID, err)
+	}
+}
+
+// answeredContext is the run's saved context with the SURVEY node's output,
+// as if the node had finished with the answer
+func answeredContext(pending *survey.Survey) map[string]any {
+	nodeContext := engine.DeepCopyMap(pending.NodeContext)
+	if nodeContext == nil {
+		nodeContext = make(map[string]any)
+	}
+	nodeContext[pending.NodeID] = map[string]any{
+		"output":  pending.Output(),
+		"success": true,
+	}
+	return nodeContext
+}
+
+// ============================================================================
+// Queries
+// ============================================================================
+
+// Get returns one of the tenant's surveys
+func (s *SurveyService) Get(ctx context.Context, tenantID kernel.TenantID, id string) (*survey.Survey, error) {
+	record, err := s.surveyRepo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	record.Refresh(time.Now())
+	return record, nil
+}
+
+// List returns the tenant's surveys, newest first
+func (s *SurveyService) List(ctx context.Context, req survey.ListSurveysRequest) (survey.SurveyListResponse, error) {
+	return s.surveyRepo.List(ctx, req)
+}
+
+// Stats aggregates the scores of each workflow or agent. CSAT and NPS
+// surveys are reported separately since their scores don't mix.
+func (s *SurveyService) Stats(ctx context.Context, req survey.StatsRequest) (*survey.StatsResponse, error) {
+	switch req.GroupBy {
+	case "":
+		req.GroupBy = survey.GroupByWorkflow
+	case survey.GroupByWorkflow, survey.GroupByAgent:
+	default:
+		return nil, survey.ErrInvalidFilter().WithDetail("group_by", "must be workflow or agent")
+	}
+
+	counts, err := s.surveyRepo.Stats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &survey.StatsResponse{
+		GroupBy: req.GroupBy,
+		Groups:  make([]survey.ScoreStats, len(counts)),
+	}
+	for i, count := range counts {
+		response.Groups[i] = scoreStats(count)
+	}
+	return response, nil
+}
+
+func scoreStats(count survey.ScoreCount) survey.ScoreStats {
+	stats := survey.ScoreStats{
+		Key:      count.Key,
+		Scale:    count.Scale,
+		Sent:     count.Sent,
+		Answered: count.Answered,
+	}
+	if count.Sent > 0 {
+		stats.ResponseRate = float64(count.Answered) / float64(count.Sent)
+	}
+	if count.Answered == 0 {
+		return stats
+	}
+
+	answered := float64(count.Answered)
+	stats.Average = float64(count.ScoreSum) / answered
+
+	if count.Scale == engine.SurveyScaleNPS {
+		nps := 100 * float64(count.Promoters-count.Detractors) / answered
+		stats.NPS = &nps
+		stats.Promoters = count.Promoters
+		stats.Detractors = count.Detractors
+		stats.Passives = count.Answered - count.Promoters - count.Detractors
+		return stats
+	}
+
+	csat := 100 * float64(count.Satisfied) / answered
+	stats.CSAT = &csat
+	return stats
+}
@@ -0,0 +1,49 @@
+package throttle
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// SaveSettingsRequest replaces the tenant's or a channel's settings
+type SaveSettingsRequest struct {
+	MaxPerMinute    int    `json:"max_per_minute"`
+	Burst           int    `json:"burst,omitempty"`
+	CooldownSeconds *int   `json:"cooldown_seconds,omitempty"` // DefaultCooldownSeconds when omitted
+	CooldownMessage string `json:"cooldown_message,omitempty"`
+}
+
+// Settings builds the settings of the request
+func (r SaveSettingsRequest) Settings(tenantID kernel.TenantID, channelID *kernel.ChannelID) *Settings {
+	cooldown := DefaultCooldownSeconds
+	if r.CooldownSeconds != nil {
+		cooldown = *r.CooldownSeconds
+	}
+	return &Settings{
+		TenantID:        tenantID,
+		ChannelID:       channelID,
+		MaxPerMinute:    r.MaxPerMinute,
+		Burst:           r.Burst,
+		CooldownSeconds: cooldown,
+		CooldownMessage: strings.TrimSpace(r.CooldownMessage),
+		UpdatedAt:       time.Now(),
+	}
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+// SettingsResponse is what a tenant configured and what applies when it
+// configured nothing
+type SettingsResponse struct {
+	Default  *Settings  `json:"default"`  // Server default, used without tenant settings
+	Tenant   *Settings  `json:"tenant"`   // nil = the server default applies
+	Channels []Settings `json:"channels"` // Channel overrides
+}
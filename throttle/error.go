@@ -0,0 +1,34 @@
+package throttle
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("THROTTLE")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeInvalidSettings  = ErrRegistry.Register("INVALID_SETTINGS", errx.TypeValidation, http.StatusBadRequest, "Invalid throttle settings")
+	CodeSettingsNotFound = ErrRegistry.Register("SETTINGS_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Throttle settings not found")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrInvalidSettings() *errx.Error {
+	return ErrRegistry.New(CodeInvalidSettings)
+}
+
+func ErrSettingsNotFound() *errx.Error {
+	return ErrRegistry.New(CodeSettingsNotFound)
+}
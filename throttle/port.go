@@ -0,0 +1,27 @@
+package throttle
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// SettingsRepository persists tenant and channel throttle settings
+type SettingsRepository interface {
+	// FindEffective returns the channel's settings, else the tenant's, else
+	// nil when neither is stored
+	FindEffective(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID) (*Settings, error)
+
+	// List returns the tenant's settings, the tenant default first
+	List(ctx context.Context, tenantID kernel.TenantID) ([]Settings, error)
+
+	// Save creates or replaces settings; a nil ChannelID saves the tenant default
+	Save(ctx context.Context, settings Settings) error
+
+	// Delete removes settings; a nil channelID removes the tenant default
+	Delete(ctx context.Context, tenantID kernel.TenantID, channelID *kernel.ChannelID) error
+}
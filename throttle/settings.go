@@ -0,0 +1,82 @@
+package throttle
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/pkg/ratelimit"
+)
+
+// ============================================================================
+// Throttle Settings
+// ============================================================================
+
+const (
+	// MaxPerMinute upper bound accepted for a sender's rate
+	MaxPerMinute = 600
+	// MaxCooldownMessageLength bounds the reply sent to throttled senders
+	MaxCooldownMessageLength = 1000
+	// DefaultCooldownSeconds is the minimum time between two cooldown replies
+	// to the same sender
+	DefaultCooldownSeconds = 60
+)
+
+// Settings bound how many messages of one sender on one channel trigger
+// workflows per minute. Messages past the limit are still recorded, but no
+// workflow runs for them. A channel's settings override the tenant's, which
+// override the server default.
+type Settings struct {
+	TenantID        kernel.TenantID   `db:"tenant_id" json:"tenant_id"`
+	ChannelID       *kernel.ChannelID `db:"channel_id" json:"channel_id,omitempty"` // nil = tenant default
+	MaxPerMinute    int               `db:"max_per_minute" json:"max_per_minute"`   // 0 = unlimited
+	Burst           int               `db:"burst" json:"burst"`                     // 0 = max_per_minute
+	CooldownSeconds int               `db:"cooldown_seconds" json:"cooldown_seconds"`
+	CooldownMessage string            `db:"cooldown_message" json:"cooldown_message"` // Empty = throttled senders get no reply
+	UpdatedAt       time.Time         `db:"updated_at" json:"updated_at"`
+}
+
+// DefaultSettings applies the server-wide per-minute rule to a tenant that
+// configured nothing
+func DefaultSettings(tenantID kernel.TenantID, rule ratelimit.Rule) *Settings {
+	return &Settings{
+		TenantID:        tenantID,
+		MaxPerMinute:    rule.Requests,
+		Burst:           rule.Burst,
+		CooldownSeconds: DefaultCooldownSeconds,
+	}
+}
+
+// Rule is the token bucket the settings describe
+func (s *Settings) Rule() ratelimit.Rule {
+	return ratelimit.Rule{
+		Requests: s.MaxPerMinute,
+		Period:   time.Minute,
+		Burst:    s.Burst,
+	}
+}
+
+// CooldownRule lets one cooldown reply through per cooldown period
+func (s *Settings) CooldownRule() ratelimit.Rule {
+	return ratelimit.Rule{
+		Requests: 1,
+		Period:   time.Duration(max(s.CooldownSeconds, 1)) * time.Second,
+		Burst:    1,
+	}
+}
+
+// Validate checks the limits
+func (s *Settings) Validate() error {
+	if s.MaxPerMinute < 0 || s.MaxPerMinute > MaxPerMinute {
+		return ErrInvalidSettings().WithDetail("field", "max_per_minute").WithDetail("max", MaxPerMinute)
+	}
+	if s.Burst < 0 {
+		return ErrInvalidSettings().WithDetail("field", "burst")
+	}
+	if s.CooldownSeconds < 0 {
+		return ErrInvalidSettings().WithDetail("field", "cooldown_seconds")
+	}
+	if len(s.CooldownMessage) > MaxCooldownMessageLength {
+		return ErrInvalidSettings().WithDetail("field", "cooldown_message").WithDetail("max_length", MaxCooldownMessageLength)
+	}
+	return nil
+}
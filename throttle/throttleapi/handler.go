@@ -0,0 +1,97 @@
+package throttleapi
+
+import (
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/throttle"
+	"github.com/Abraxas-365/relay/throttle/throttlesrv"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ThrottleHandler exposes the tenant's sender throttling settings
+type ThrottleHandler struct {
+	service *throttlesrv.ThrottleService
+}
+
+// NewThrottleHandler creates a new throttle handler
+func NewThrottleHandler(service *throttlesrv.ThrottleService) *ThrottleHandler {
+	return &ThrottleHandler{
+		service: service,
+	}
+}
+
+// GetSettings returns the tenant's settings, its channel overrides and the
+// server default
+// GET /api/throttle/settings
+func (h *ThrottleHandler) GetSettings(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	settings, err := h.service.GetSettings(c.Context(), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(settings)
+}
+
+// SaveTenantSettings replaces the settings of every channel without an override
+// PUT /api/throttle/settings
+func (h *ThrottleHandler) SaveTenantSettings(c *fiber.Ctx) error {
+	return h.save(c, nil)
+}
+
+// DeleteTenantSettings goes back to the server default
+// DELETE /api/throttle/settings
+func (h *ThrottleHandler) DeleteTenantSettings(c *fiber.Ctx) error {
+	return h.delete(c, nil)
+}
+
+// SaveChannelSettings overrides the tenant's settings on one channel
+// PUT /api/throttle/settings/channels/:channel_id
+func (h *ThrottleHandler) SaveChannelSettings(c *fiber.Ctx) error {
+	channelID := kernel.ChannelID(c.Params("channel_id"))
+	return h.save(c, &channelID)
+}
+
+// DeleteChannelSettings removes a channel's override
+// DELETE /api/throttle/settings/channels/:channel_id
+func (h *ThrottleHandler) DeleteChannelSettings(c *fiber.Ctx) error {
+	channelID := kernel.ChannelID(c.Params("channel_id"))
+	return h.delete(c, &channelID)
+}
+
+func (h *ThrottleHandler) save(c *fiber.Ctx, channelID *kernel.ChannelID) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req throttle.SaveSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return throttle.ErrInvalidSettings().WithDetail("reason", err.Error())
+	}
+
+	settings, err := h.service.SaveSettings(c.Context(), authContext.TenantID, channelID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(settings)
+}
+
+func (h *ThrottleHandler) delete(c *fiber.Ctx, channelID *kernel.ChannelID) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	if err := h.service.DeleteSettings(c.Context(), authContext.TenantID, channelID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
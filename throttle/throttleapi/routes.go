@@ -0,0 +1,32 @@
+package throttleapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ThrottleRoutes handles throttle route setup
+type ThrottleRoutes struct {
+	handler        *ThrottleHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewThrottleRoutes creates a new throttle routes instance
+func NewThrottleRoutes(handler *ThrottleHandler, authMiddleware *auth.AuthMiddleware) *ThrottleRoutes {
+	return &ThrottleRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers throttle routes on an authenticated router.
+// Changing the settings requires an admin.
+func (r *ThrottleRoutes) RegisterRoutes(router fiber.Router) {
+	settings := router.Group("/throttle/settings")
+
+	settings.Get("/", r.handler.GetSettings)
+	settings.Put("/", r.authMiddleware.RequireAdmin(), r.handler.SaveTenantSettings)
+	settings.Delete("/", r.authMiddleware.RequireAdmin(), r.handler.DeleteTenantSettings)
+	settings.Put("/channels/:channel_id", r.authMiddleware.RequireAdmin(), r.handler.SaveChannelSettings)
+	settings.Delete("/channels/:channel_id", r.authMiddleware.RequireAdmin(), r.handler.DeleteChannelSettings)
+}
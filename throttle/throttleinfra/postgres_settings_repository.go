@@ -0,0 +1,98 @@
+package throttleinfra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/throttle"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresSettingsRepository is the PostgreSQL implementation of throttle.SettingsRepository
+type PostgresSettingsRepository struct {
+	db *sqlx.DB
+}
+
+var _ throttle.SettingsRepository = (*PostgresSettingsRepository)(nil)
+
+func NewPostgresSettingsRepository(db *sqlx.DB) *PostgresSettingsRepository {
+	return &PostgresSettingsRepository{db: db}
+}
+
+const settingsColumns = `
+	tenant_id, channel_id, max_per_minute, burst, cooldown_seconds,
+	cooldown_message, updated_at`
+
+func (r *PostgresSettingsRepository) FindEffective(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID) (*throttle.Settings, error) {
+	// The channel's row sorts before the tenant default
+	query := `SELECT ` + settingsColumns + ` FROM throttle_settings
+		WHERE tenant_id = $1 AND (channel_id = $2 OR channel_id IS NULL)
+		ORDER BY channel_id NULLS LAST
+		LIMIT 1`
+
+	var settings throttle.Settings
+	if err := r.db.GetContext(ctx, &settings, query, tenantID.String(), channelID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errx.Wrap(err, "failed to find throttle settings", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	return &settings, nil
+}
+
+func (r *PostgresSettingsRepository) List(ctx context.Context, tenantID kernel.TenantID) ([]throttle.Settings, error) {
+	query := `SELECT ` + settingsColumns + ` FROM throttle_settings
+		WHERE tenant_id = $1
+		ORDER BY channel_id NULLS FIRST`
+
+	settings := []throttle.Settings{}
+	if err := r.db.SelectContext(ctx, &settings, query, tenantID.String()); err != nil {
+		return nil, errx.Wrap(err, "failed to list throttle settings", errx.TypeInternal)
+	}
+
+	return settings, nil
+}
+
+func (r *PostgresSettingsRepository) Save(ctx context.Context, settings throttle.Settings) error {
+	query := `
+		INSERT INTO throttle_settings (` + settingsColumns + `)
+		VALUES (:tenant_id, :channel_id, :max_per_minute, :burst, :cooldown_seconds, :cooldown_message, :updated_at)
+		ON CONFLICT (tenant_id, (COALESCE(channel_id, ''))) DO UPDATE SET
+			max_per_minute = EXCLUDED.max_per_minute,
+			burst = EXCLUDED.burst,
+			cooldown_seconds = EXCLUDED.cooldown_seconds,
+			cooldown_message = EXCLUDED.cooldown_message,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.NamedExecContext(ctx, query, settings); err != nil {
+		return errx.Wrap(err, "failed to save throttle settings", errx.TypeInternal).
+			WithDetail("tenant_id", settings.TenantID.String())
+	}
+
+	return nil
+}
+
+func (r *PostgresSettingsRepository) Delete(ctx context.Context, tenantID kernel.TenantID, channelID *kernel.ChannelID) error {
+	query := `DELETE FROM throttle_settings WHERE tenant_id = $1 AND channel_id IS NULL`
+	args := []any{tenantID.String()}
+	if channelID != nil {
+		query = `DELETE FROM throttle_settings WHERE tenant_id = $1 AND channel_id = $2`
+		args = append(args, channelID.String())
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errx.Wrap(err, "failed to delete throttle settings", errx.TypeInternal).
+			WithDetail("tenant_id", tenantID.String())
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return throttle.ErrSettingsNotFound()
+	}
+
+	return nil
+}
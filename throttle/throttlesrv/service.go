@@ -0,0 +1,219 @@
+package throttlesrv
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/pkg/ratelimit"
+	"github.com/Abraxas-365/relay/throttle"
+)
+
+// settingsCacheTTL how long effective settings are reused on the inbound hot path
+const settingsCacheTTL = time.Minute
+
+type cacheKey struct {
+	tenantID  kernel.TenantID
+	channelID kernel.ChannelID
+}
+
+type cachedSettings struct {
+	settings  *throttle.Settings
+	expiresAt time.Time
+}
+
+// ThrottleService keeps a single sender from flooding workflows: past the
+// limit their messages are recorded but trigger nothing, and they get a
+// cooldown reply at most once per cooldown period
+type ThrottleService struct {
+	settingsRepo   throttle.SettingsRepository
+	channelRepo    channels.ChannelRepository
+	limiter        ratelimit.Limiter
+	channelManager channels.ChannelManager
+	defaultRule    ratelimit.Rule // Applies to tenants without settings; disabled = unlimited
+
+	mu    sync.RWMutex
+	cache map[cacheKey]cachedSettings
+}
+
+var _ channels.InboundInterceptor = (*ThrottleService)(nil)
+
+func NewThrottleService(
+	settingsRepo throttle.SettingsRepository,
+	channelRepo channels.ChannelRepository,
+	limiter ratelimit.Limiter,
+	channelManager channels.ChannelManager,
+	defaultRule ratelimit.Rule,
+) *ThrottleService {
+	return &ThrottleService{
+		settingsRepo:   settingsRepo,
+		channelRepo:    channelRepo,
+		limiter:        limiter,
+		channelManager: channelManager,
+		defaultRule:    defaultRule,
+		cache:          make(map[cacheKey]cachedSettings),
+	}
+}
+
+// ============================================================================
+// Throttling
+// ============================================================================
+
+// InterceptInbound implements channels.InboundInterceptor: a throttled
+// message is consumed so no workflow runs for it. Lookup and Redis failures
+// let the message through.
+func (s *ThrottleService) InterceptInbound(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage) bool {
+	settings := s.effectiveSettings(ctx, channel.TenantID, channel.ID)
+	rule := settings.Rule()
+	if !rule.Enabled() {
+		return false
+	}
+
+	key := fmt.Sprintf("throttle:%s:%s:%s", channel.TenantID, channel.ID, msg.SenderID)
+	result, err := s.limiter.Allow(ctx, key, rule)
+	if err != nil {
+		log.Printf("⚠️  Failed to check throttle for sender %s: %v", msg.SenderID, err)
+		return false
+	}
+	if result.Allowed {
+		return false
+	}
+
+	log.Printf("🐢 Sender %s on channel %s is over %d messages per minute, workflows skipped (retry in %s)",
+		msg.SenderID, channel.ID, settings.MaxPerMinute, result.RetryAfter.Round(time.Second))
+	s.sendCooldown(ctx, channel, msg, settings)
+
+	return true
+}
+
+// sendCooldown tells the sender to slow down, once per cooldown period
+func (s *ThrottleService) sendCooldown(ctx context.Context, channel *channels.Channel, msg *channels.IncomingMessage, settings *throttle.Settings) {
+	if settings.CooldownMessage == "" || s.channelManager == nil {
+		return
+	}
+
+	key := fmt.Sprintf("throttle-notice:%s:%s:%s", channel.TenantID, channel.ID, msg.SenderID)
+	result, err := s.limiter.Allow(ctx, key, settings.CooldownRule())
+	if err != nil || !result.Allowed {
+		return
+	}
+
+	reply := channels.OutgoingMessage{
+		RecipientID: msg.SenderID,
+		Content: channels.MessageContent{
+			Type: "text",
+			Text: settings.CooldownMessage,
+		},
+		Metadata: map[string]any{
+			"origin":    string(conversation.OriginSystem),
+			"throttled": true,
+		},
+	}
+	if err := s.channelManager.SendMessage(ctx, channel.TenantID, channel.ID, reply); err != nil {
+		log.Printf("⚠️  Failed to send cooldown reply to %s: %v", msg.SenderID, err)
+	}
+}
+
+// effectiveSettings returns the channel's, the tenant's or the default
+// settings, reusing them for settingsCacheTTL
+func (s *ThrottleService) effectiveSettings(ctx context.Context, tenantID kernel.TenantID, channelID kernel.ChannelID) *throttle.Settings {
+	key := cacheKey{tenantID: tenantID, channelID: channelID}
+
+	s.mu.RLock()
+	cached, ok := s.cache[key]
+	s.mu.RUnlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.settings
+	}
+
+	settings, err := s.settingsRepo.FindEffective(ctx, tenantID, channelID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load throttle settings for tenant %s: %v", tenantID, err)
+	}
+	if settings == nil {
+		settings = throttle.DefaultSettings(tenantID, s.defaultRule)
+	}
+
+	s.mu.Lock()
+	s.cache[key] = cachedSettings{settings: settings, expiresAt: time.Now().Add(settingsCacheTTL)}
+	s.mu.Unlock()
+
+	return settings
+}
+
+// ============================================================================
+// Settings
+// ============================================================================
+
+// GetSettings returns the tenant's settings and its channel overrides
+func (s *ThrottleService) GetSettings(ctx context.Context, tenantID kernel.TenantID) (*throttle.SettingsResponse, error) {
+	all, err := s.settingsRepo.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &throttle.SettingsResponse{
+		Default:  throttle.DefaultSettings(tenantID, s.defaultRule),
+		Channels: []throttle.Settings{},
+	}
+	for i := range all {
+		if all[i].ChannelID == nil {
+			view.Tenant = &all[i]
+		} else {
+			view.Channels = append(view.Channels, all[i])
+		}
+	}
+
+	return view, nil
+}
+
+// SaveSettings replaces the tenant's settings, or a channel's when channelID is set
+func (s *ThrottleService) SaveSettings(ctx context.Context, tenantID kernel.TenantID, channelID *kernel.ChannelID, req throttle.SaveSettingsRequest) (*throttle.Settings, error) {
+	settings := req.Settings(tenantID, channelID)
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	if channelID != nil {
+		if _, err := s.channelRepo.FindByID(ctx, *channelID, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.settingsRepo.Save(ctx, *settings); err != nil {
+		return nil, err
+	}
+	s.invalidate(tenantID)
+
+	log.Printf("✅ Throttle settings updated for tenant %s (channel=%v max=%d/min burst=%d cooldown=%ds)",
+		tenantID, channelID != nil, settings.MaxPerMinute, settings.Burst, settings.CooldownSeconds)
+
+	return settings, nil
+}
+
+// DeleteSettings removes the tenant's settings, or a channel's override
+func (s *ThrottleService) DeleteSettings(ctx context.Context, tenantID kernel.TenantID, channelID *kernel.ChannelID) error {
+	if err := s.settingsRepo.Delete(ctx, tenantID, channelID); err != nil {
+		return err
+	}
+	s.invalidate(tenantID)
+	return nil
+}
+
+// invalidate drops every cached channel of the tenant, since tenant
+// settings apply to all of them
+func (s *ThrottleService) invalidate(tenantID kernel.TenantID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.cache {
+		if key.tenantID == tenantID {
+			delete(s.cache, key)
+		}
+	}
+}
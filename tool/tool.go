@@ -29,10 +29,14 @@ type Tool struct {
 type ToolType string
 
 const (
-	ToolTypeHTTP     ToolType = "HTTP"
-	ToolTypeDatabase ToolType = "DATABASE"
-	ToolTypeEmail    ToolType = "EMAIL"
-	ToolTypeCustom   ToolType = "CUSTOM"
+	ToolTypeHTTP        ToolType = "HTTP"
+	ToolTypeDatabase    ToolType = "DATABASE"
+	ToolTypeEmail       ToolType = "EMAIL"
+	ToolTypeCustom      ToolType = "CUSTOM"
+	ToolTypeCRM         ToolType = "CRM"         // Escribe en el CRM conectado por el tenant (HubSpot, Salesforce)
+	ToolTypeTicket      ToolType = "TICKET"      // Abre o actualiza el ticket de la conversación en el helpdesk (Zendesk, Freshdesk)
+	ToolTypeShopify     ToolType = "SHOPIFY"     // Consulta pedidos de la tienda Shopify del tenant
+	ToolTypeWooCommerce ToolType = "WOOCOMMERCE" // Consulta pedidos de la tienda WooCommerce del tenant
 )
 
 // ToolConfig configuración específica por tipo de tool
@@ -59,6 +63,18 @@ type ToolConfig struct {
 	Runtime string `json:"runtime,omitempty"` // nodejs, python
 	Code    string `json:"code,omitempty"`
 	Memory  string `json:"memory,omitempty"` // 128mb, 256mb
+
+	// CRM: el input son los campos; contact_id enlaza el registro a un contacto
+	CRMProvider   string `json:"crm_provider,omitempty"`    // hubspot, salesforce; vacío = la única conexión del tenant
+	CRMOperation  string `json:"crm_operation,omitempty"`   // upsert_contact, log_activity, create_deal, create_ticket
+	CRMMatchField string `json:"crm_match_field,omitempty"` // upsert_contact; email por defecto
+
+	// Ticket: el input trae channel_id y conversation_id, más subject, comment, priority, sentiment y requester_*
+	TicketProvider string `json:"ticket_provider,omitempty"` // zendesk, freshdesk; vacío = la única conexión del tenant
+
+	// Shopify / WooCommerce: el input trae order_number, email o phone (o by y value), más limit
+	OrderLookupBy string `json:"order_lookup_by,omitempty"` // order_number, email, phone; vacío = según el input
+	OrderLimit    int    `json:"order_limit,omitempty"`     // Pedidos recientes a devolver; 5 por defecto
 }
 
 // ============================================================================
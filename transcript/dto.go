@@ -0,0 +1,48 @@
+package transcript
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Request DTOs
+// ============================================================================
+
+// CreateExportRequest request to export the transcripts matching the filters.
+// From and To are RFC 3339 timestamps; To is exclusive.
+type CreateExportRequest struct {
+	Format    Format            `json:"format,omitempty"` // jsonl by default
+	From      *time.Time        `json:"from,omitempty"`
+	To        *time.Time        `json:"to,omitempty"`
+	ChannelID *kernel.ChannelID `json:"channel_id,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+}
+
+// ListExportsRequest request to list a tenant's export jobs, newest first
+type ListExportsRequest struct {
+	storex.PaginationOptions
+
+	TenantID kernel.TenantID `json:"tenant_id" validate:"required"`
+	Status   Status          `json:"status,omitempty"`
+}
+
+func (r ListExportsRequest) GetOffset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+// ============================================================================
+// Response DTOs
+// ============================================================================
+
+// ExportResponse an export job with a fresh download link once it completed
+type ExportResponse struct {
+	*ExportJob
+	URL          string     `json:"url,omitempty"`
+	URLExpiresAt *time.Time `json:"url_expires_at,omitempty"`
+}
+
+// ExportListResponse paginated list of export jobs
+type ExportListResponse = storex.Paginated[ExportJob]
@@ -0,0 +1,49 @@
+package transcript
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// ============================================================================
+// Error Registry
+// ============================================================================
+
+var ErrRegistry = errx.NewRegistry("TRANSCRIPT")
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+var (
+	CodeExportNotFound       = ErrRegistry.Register("EXPORT_NOT_FOUND", errx.TypeNotFound, http.StatusNotFound, "Transcript export not found")
+	CodeExportNotReady       = ErrRegistry.Register("EXPORT_NOT_READY", errx.TypeBusiness, http.StatusConflict, "Transcript export is not ready yet")
+	CodeInvalidExport        = ErrRegistry.Register("INVALID_EXPORT", errx.TypeValidation, http.StatusBadRequest, "Invalid transcript export request")
+	CodeTooManyExports       = ErrRegistry.Register("TOO_MANY_EXPORTS", errx.TypeConflict, http.StatusConflict, "Too many transcript exports in progress")
+	CodeExportsNotConfigured = ErrRegistry.Register("EXPORTS_NOT_CONFIGURED", errx.TypeBusiness, http.StatusServiceUnavailable, "Transcript exports need file storage")
+)
+
+// ============================================================================
+// Error Constructor Functions
+// ============================================================================
+
+func ErrExportNotFound() *errx.Error {
+	return ErrRegistry.New(CodeExportNotFound)
+}
+
+func ErrExportNotReady() *errx.Error {
+	return ErrRegistry.New(CodeExportNotReady)
+}
+
+func ErrInvalidExport() *errx.Error {
+	return ErrRegistry.New(CodeInvalidExport)
+}
+
+func ErrTooManyExports() *errx.Error {
+	return ErrRegistry.New(CodeTooManyExports)
+}
+
+func ErrExportsNotConfigured() *errx.Error {
+	return ErrRegistry.New(CodeExportsNotConfigured)
+}
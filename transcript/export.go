@@ -0,0 +1,171 @@
+package transcript
+
+import (
+	"path"
+	"time"
+
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Export Jobs
+// ============================================================================
+
+// Format of an export file
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl" // One message per line, with its full content and context
+	FormatCSV   Format = "csv"   // One message per row, content and context as JSON columns
+)
+
+// IsValid checks the format is supported
+func (f Format) IsValid() bool {
+	return f == FormatJSONL || f == FormatCSV
+}
+
+// ContentType is the MIME type the file is stored with
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// Status of an export job
+type Status string
+
+const (
+	StatusPending   Status = "PENDING"
+	StatusRunning   Status = "RUNNING"
+	StatusCompleted Status = "COMPLETED"
+	StatusFailed    Status = "FAILED"
+)
+
+// IsActive reports whether the job has not finished yet
+func (s Status) IsActive() bool {
+	return s == StatusPending || s == StatusRunning
+}
+
+// Filters select the messages of an export. A conversation matches Tags when
+// it carries all of them.
+type Filters struct {
+	From      *time.Time        `json:"from,omitempty"`
+	To        *time.Time        `json:"to,omitempty"`
+	ChannelID *kernel.ChannelID `json:"channel_id,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+}
+
+// MessageFilter is the conversation store query for the filters
+func (f Filters) MessageFilter(tenantID kernel.TenantID) conversation.MessageFilter {
+	return conversation.MessageFilter{
+		TenantID:  tenantID,
+		ChannelID: f.ChannelID,
+		From:      f.From,
+		To:        f.To,
+		Tags:      f.Tags,
+	}
+}
+
+// Progress of a running export. TotalMessages is counted when the job
+// starts; messages received while it runs may push Processed past it.
+type Progress struct {
+	TotalMessages     int     `json:"total_messages"`
+	ProcessedMessages int     `json:"processed_messages"`
+	Conversations     int     `json:"conversations"`
+	Percent           float64 `json:"percent"`
+}
+
+// ExportJob produces a downloadable file with the transcripts selected by
+// its filters (compliance requests, fine-tuning datasets)
+type ExportJob struct {
+	ID          string          `json:"id"`
+	TenantID    kernel.TenantID `json:"tenant_id"`
+	RequestedBy kernel.UserID   `json:"requested_by"`
+	Format      Format          `json:"format"`
+	Filters     Filters         `json:"filters"`
+	Status      Status          `json:"status"`
+	Progress    Progress        `json:"progress"`
+	StorageKey  string          `json:"-"`
+	SizeBytes   int64           `json:"size_bytes"`
+	Error       string          `json:"error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+}
+
+// NewExportJob creates a pending export job
+func NewExportJob(tenantID kernel.TenantID, requestedBy kernel.UserID, format Format, filters Filters) *ExportJob {
+	return &ExportJob{
+		ID:          uuid.NewString(),
+		TenantID:    tenantID,
+		RequestedBy: requestedBy,
+		Format:      format,
+		Filters:     filters,
+		Status:      StatusPending,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// ============================================================================
+// Domain Methods
+// ============================================================================
+
+// Start marks the job running over total messages. A retry starts over.
+func (j *ExportJob) Start(total int) {
+	now := time.Now()
+	j.Status = StatusRunning
+	j.StartedAt = &now
+	j.CompletedAt = nil
+	j.Error = ""
+	j.Progress = Progress{TotalMessages: total}
+}
+
+// Advance records messages written to the file
+func (j *ExportJob) Advance(messages, conversations int) {
+	j.Progress.ProcessedMessages += messages
+	j.Progress.Conversations += conversations
+	j.RefreshPercent()
+}
+
+// RefreshPercent derives the progress percentage from the message counts
+func (j *ExportJob) RefreshPercent() {
+	switch {
+	case j.Status == StatusCompleted:
+		j.Progress.Percent = 100
+	case j.Progress.TotalMessages > 0:
+		percent := 100 * float64(j.Progress.ProcessedMessages) / float64(j.Progress.TotalMessages)
+		// Only completion reports 100
+		j.Progress.Percent = min(percent, 99)
+	}
+}
+
+// Complete marks the job finished with its stored file
+func (j *ExportJob) Complete(storageKey string, sizeBytes int64) {
+	now := time.Now()
+	j.Status = StatusCompleted
+	j.StorageKey = storageKey
+	j.SizeBytes = sizeBytes
+	j.Progress.Percent = 100
+	j.CompletedAt = &now
+}
+
+// Fail marks the job failed
+func (j *ExportJob) Fail(err error) {
+	now := time.Now()
+	j.Status = StatusFailed
+	j.Error = err.Error()
+	j.CompletedAt = &now
+}
+
+// IsReady reports whether the file can be downloaded
+func (j *ExportJob) IsReady() bool {
+	return j.Status == StatusCompleted && j.StorageKey != ""
+}
+
+// FileKey is where the job's file is stored
+func (j *ExportJob) FileKey() string {
+	return path.Join("exports", j.TenantID.String(), j.ID+"."+string(j.Format))
+}
@@ -0,0 +1,25 @@
+package transcript
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/relay/pkg/kernel"
+)
+
+// ============================================================================
+// Repository Interfaces
+// ============================================================================
+
+// ExportJobRepository persists transcript export jobs
+type ExportJobRepository interface {
+	// Save creates or updates a job
+	Save(ctx context.Context, job ExportJob) error
+
+	FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*ExportJob, error)
+
+	// List pages through the tenant's jobs, newest first
+	List(ctx context.Context, req ListExportsRequest) (ExportListResponse, error)
+
+	// CountActive counts the tenant's pending and running jobs
+	CountActive(ctx context.Context, tenantID kernel.TenantID) (int, error)
+}
@@ -0,0 +1,63 @@
+package transcript
+
+import (
+	"time"
+
+	"github.com/Abraxas-365/relay/channels"
+	"github.com/Abraxas-365/relay/conversation"
+)
+
+// ============================================================================
+// Export Records
+// ============================================================================
+
+// Record is one message as written to an export file
+type Record struct {
+	MessageID         string                  `json:"message_id"`
+	ChannelID         string                  `json:"channel_id"`
+	ConversationID    string                  `json:"conversation_id"`
+	SenderID          string                  `json:"sender_id"`
+	Direction         string                  `json:"direction"`
+	Origin            string                  `json:"origin"`
+	Type              string                  `json:"type"`
+	Text              string                  `json:"text"`
+	Status            string                  `json:"status"`
+	ProviderMessageID string                  `json:"provider_message_id,omitempty"`
+	WorkflowID        string                  `json:"workflow_id,omitempty"`
+	NodeID            string                  `json:"node_id,omitempty"`
+	Tags              []string                `json:"tags,omitempty"` // The conversation's tags when exported
+	AttachmentURLs    []string                `json:"attachment_urls,omitempty"`
+	Content           channels.MessageContent `json:"content"`
+	Context           map[string]any          `json:"context,omitempty"`
+	CreatedAt         time.Time               `json:"created_at"`
+}
+
+// NewRecord converts a stored message into its export record
+func NewRecord(msg conversation.Message, tags []string) Record {
+	return Record{
+		MessageID:         msg.ID,
+		ChannelID:         msg.ChannelID.String(),
+		ConversationID:    msg.ConversationID,
+		SenderID:          msg.SenderID,
+		Direction:         string(msg.Direction),
+		Origin:            string(msg.Origin),
+		Type:              msg.Content.Type,
+		Text:              msg.Content.Text,
+		Status:            string(msg.Status),
+		ProviderMessageID: msg.ProviderMessageID,
+		WorkflowID:        msg.WorkflowID,
+		NodeID:            msg.NodeID,
+		Tags:              tags,
+		AttachmentURLs:    msg.AttachmentURLs(),
+		Content:           msg.Content,
+		Context:           msg.Context,
+		CreatedAt:         msg.CreatedAt,
+	}
+}
+
+// CSVHeader is the header row of CSV exports
+var CSVHeader = []string{
+	"message_id", "channel_id", "conversation_id", "sender_id", "direction", "origin",
+	"type", "text", "status", "provider_message_id", "workflow_id", "node_id",
+	"tags", "attachment_urls", "content", "context", "created_at",
+}
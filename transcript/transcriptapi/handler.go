@@ -0,0 +1,109 @@
+package transcriptapi
+
+import (
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/iam"
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/Abraxas-365/relay/transcript"
+	"github.com/Abraxas-365/relay/transcript/transcriptsrv"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// TranscriptHandler exposes the transcript export jobs
+type TranscriptHandler struct {
+	service *transcriptsrv.ExportService
+}
+
+// NewTranscriptHandler creates a new transcript handler
+func NewTranscriptHandler(service *transcriptsrv.ExportService) *TranscriptHandler {
+	return &TranscriptHandler{
+		service: service,
+	}
+}
+
+// RequestExport starts exporting the transcripts matching the filters
+// POST /api/transcripts/exports
+func (h *TranscriptHandler) RequestExport(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var req transcript.CreateExportRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return transcript.ErrInvalidExport().WithDetail("reason", err.Error())
+		}
+	}
+
+	job, err := h.service.RequestExport(c.Context(), authContext.TenantID, authContext.UserID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// ListExports returns the tenant's export jobs, filtered by ?status=
+// GET /api/transcripts/exports
+func (h *TranscriptHandler) ListExports(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", defaultPageSize)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	req := transcript.ListExportsRequest{
+		PaginationOptions: storex.PaginationOptions{
+			Page:     page,
+			PageSize: pageSize,
+		},
+		TenantID: authContext.TenantID,
+		Status:   transcript.Status(c.Query("status")),
+	}
+	switch req.Status {
+	case "", transcript.StatusPending, transcript.StatusRunning, transcript.StatusCompleted, transcript.StatusFailed:
+	default:
+		return transcript.ErrInvalidExport().WithDetail("status", "must be PENDING, RUNNING, COMPLETED or FAILED")
+	}
+
+	jobs, err := h.service.ListExports(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(jobs)
+}
+
+// GetExport returns a job's progress and, once completed, a signed link to
+// its file
+// GET /api/transcripts/exports/:id
+func (h *TranscriptHandler) GetExport(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	job, err := h.service.GetExport(c.Context(), c.Params("id"), authContext.TenantID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(job)
+}
@@ -0,0 +1,30 @@
+package transcriptapi
+
+import (
+	"github.com/Abraxas-365/relay/iam/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TranscriptRoutes handles transcript route setup
+type TranscriptRoutes struct {
+	handler        *TranscriptHandler
+	authMiddleware *auth.AuthMiddleware
+}
+
+// NewTranscriptRoutes creates a new transcript routes instance
+func NewTranscriptRoutes(handler *TranscriptHandler, authMiddleware *auth.AuthMiddleware) *TranscriptRoutes {
+	return &TranscriptRoutes{
+		handler:        handler,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers transcript routes on an authenticated router.
+// Exports hold every matching conversation, so they require an admin.
+func (r *TranscriptRoutes) RegisterRoutes(router fiber.Router) {
+	exports := router.Group("/transcripts/exports", r.authMiddleware.RequireAdmin())
+
+	exports.Post("/", r.handler.RequestExport)
+	exports.Get("/", r.handler.ListExports)
+	exports.Get("/:id", r.handler.GetExport)
+}
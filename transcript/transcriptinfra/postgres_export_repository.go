@@ -0,0 +1,188 @@
+package transcriptinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/transcript"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresExportJobRepository struct {
+	db *sqlx.DB
+}
+
+var _ transcript.ExportJobRepository = (*PostgresExportJobRepository)(nil)
+
+func NewPostgresExportJobRepository(db *sqlx.DB) *PostgresExportJobRepository {
+	return &PostgresExportJobRepository{db: db}
+}
+
+// dbExportJob is an intermediate struct for database operations
+type dbExportJob struct {
+	ID                string          `db:"id"`
+	TenantID          string          `db:"tenant_id"`
+	RequestedBy       string          `db:"requested_by"`
+	Format            string          `db:"format"`
+	Filters           json.RawMessage `db:"filters"`
+	Status            string          `db:"status"`
+	TotalMessages     int             `db:"total_messages"`
+	ProcessedMessages int             `db:"processed_messages"`
+	Conversations     int             `db:"conversations"`
+	StorageKey        string          `db:"storage_key"`
+	SizeBytes         int64           `db:"size_bytes"`
+	Error             string          `db:"error"`
+	CreatedAt         time.Time       `db:"created_at"`
+	StartedAt         sql.NullTime    `db:"started_at"`
+	CompletedAt       sql.NullTime    `db:"completed_at"`
+}
+
+const exportJobColumns = `
+	id, tenant_id, requested_by, format, filters, status, total_messages,
+	processed_messages, conversations, storage_key, size_bytes, error,
+	created_at, started_at, completed_at`
+
+func (r *PostgresExportJobRepository) Save(ctx context.Context, job transcript.ExportJob) error {
+	filtersJSON, err := json.Marshal(job.Filters)
+	if err != nil {
+		return errx.Wrap(err, "failed to marshal export filters", errx.TypeInternal)
+	}
+
+	var startedAt, completedAt sql.NullTime
+	if job.StartedAt != nil {
+		startedAt = sql.NullTime{Time: *job.StartedAt, Valid: true}
+	}
+	if job.CompletedAt != nil {
+		completedAt = sql.NullTime{Time: *job.CompletedAt, Valid: true}
+	}
+
+	query := `
+		INSERT INTO transcript_exports (` + exportJobColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			total_messages = EXCLUDED.total_messages,
+			processed_messages = EXCLUDED.processed_messages,
+			conversations = EXCLUDED.conversations,
+			storage_key = EXCLUDED.storage_key,
+			size_bytes = EXCLUDED.size_bytes,
+			error = EXCLUDED.error,
+			started_at = EXCLUDED.started_at,
+			completed_at = EXCLUDED.completed_at`
+
+	_, err = r.db.ExecContext(ctx, query,
+		job.ID, job.TenantID.String(), job.RequestedBy.String(), string(job.Format), filtersJSON,
+		string(job.Status), job.Progress.TotalMessages, job.Progress.ProcessedMessages, job.Progress.Conversations,
+		job.StorageKey, job.SizeBytes, job.Error, job.CreatedAt, startedAt, completedAt,
+	)
+	if err != nil {
+		return errx.Wrap(err, "failed to save transcript export", errx.TypeInternal).
+			WithDetail("export_id", job.ID)
+	}
+
+	return nil
+}
+
+func (r *PostgresExportJobRepository) FindByID(ctx context.Context, id string, tenantID kernel.TenantID) (*transcript.ExportJob, error) {
+	query := `SELECT ` + exportJobColumns + ` FROM transcript_exports WHERE id = $1 AND tenant_id = $2`
+
+	var row dbExportJob
+	if err := r.db.GetContext(ctx, &row, query, id, tenantID.String()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, transcript.ErrExportNotFound().WithDetail("export_id", id)
+		}
+		return nil, errx.Wrap(err, "failed to find transcript export", errx.TypeInternal).
+			WithDetail("export_id", id)
+	}
+
+	return toDomainExportJob(&row)
+}
+
+func (r *PostgresExportJobRepository) List(ctx context.Context, req transcript.ListExportsRequest) (transcript.ExportListResponse, error) {
+	where := "tenant_id = $1"
+	args := []any{req.TenantID.String()}
+	argPos := 2
+
+	if req.Status != "" {
+		where += fmt.Sprintf(" AND status = $%d", argPos)
+		args = append(args, string(req.Status))
+		argPos++
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM transcript_exports WHERE `+where, args...); err != nil {
+		return transcript.ExportListResponse{}, errx.Wrap(err, "failed to count transcript exports", errx.TypeInternal)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM transcript_exports WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d`, exportJobColumns, where, argPos, argPos+1)
+	args = append(args, req.PageSize, req.GetOffset())
+
+	var rows []dbExportJob
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return transcript.ExportListResponse{}, errx.Wrap(err, "failed to list transcript exports", errx.TypeInternal)
+	}
+
+	jobs := make([]transcript.ExportJob, 0, len(rows))
+	for i := range rows {
+		job, err := toDomainExportJob(&rows[i])
+		if err != nil {
+			return transcript.ExportListResponse{}, err
+		}
+		jobs = append(jobs, *job)
+	}
+
+	return storex.NewPaginated(jobs, req.Page, req.PageSize, total), nil
+}
+
+func (r *PostgresExportJobRepository) CountActive(ctx context.Context, tenantID kernel.TenantID) (int, error) {
+	query := `SELECT COUNT(*) FROM transcript_exports
+		WHERE tenant_id = $1 AND status IN ('PENDING', 'RUNNING')`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, tenantID.String()); err != nil {
+		return 0, errx.Wrap(err, "failed to count active transcript exports", errx.TypeInternal)
+	}
+
+	return count, nil
+}
+
+func toDomainExportJob(row *dbExportJob) (*transcript.ExportJob, error) {
+	job := &transcript.ExportJob{
+		ID:          row.ID,
+		TenantID:    kernel.TenantID(row.TenantID),
+		RequestedBy: kernel.UserID(row.RequestedBy),
+		Format:      transcript.Format(row.Format),
+		Status:      transcript.Status(row.Status),
+		StorageKey:  row.StorageKey,
+		SizeBytes:   row.SizeBytes,
+		Error:       row.Error,
+		CreatedAt:   row.CreatedAt,
+	}
+	job.Progress = transcript.Progress{
+		TotalMessages:     row.TotalMessages,
+		ProcessedMessages: row.ProcessedMessages,
+		Conversations:     row.Conversations,
+	}
+	job.RefreshPercent()
+	if row.StartedAt.Valid {
+		job.StartedAt = &row.StartedAt.Time
+	}
+	if row.CompletedAt.Valid {
+		job.CompletedAt = &row.CompletedAt.Time
+	}
+	if len(row.Filters) > 0 {
+		if err := json.Unmarshal(row.Filters, &job.Filters); err != nil {
+			return nil, errx.Wrap(err, "failed to unmarshal export filters", errx.TypeInternal).
+				WithDetail("export_id", row.ID)
+		}
+	}
+	return job, nil
+}
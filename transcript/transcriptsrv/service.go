@@ -0,0 +1,355 @@
+package transcriptsrv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Abraxas-365/relay/attachment"
+	"github.com/Abraxas-365/relay/conversation"
+	"github.com/Abraxas-365/relay/iam/tenant"
+	"github.com/Abraxas-365/relay/jobs"
+	"github.com/Abraxas-365/relay/pkg/kernel"
+	"github.com/Abraxas-365/relay/transcript"
+)
+
+// ExportJobKind is the background job that writes one export file
+const ExportJobKind = "transcript.export"
+
+const (
+	// ExportTimeout bounds a single export job
+	ExportTimeout = 2 * time.Hour
+
+	// exportAttempts is how many times an export is tried; a retry starts
+	// the file over
+	exportAttempts = 2
+
+	// exportPageSize is how many messages are read per query; progress is
+	// saved after each page
+	exportPageSize = 500
+
+	// maxActiveExports bounds the pending and running jobs of a tenant, since
+	// each one scans the message store
+	maxActiveExports = 3
+)
+
+// ExportService runs transcript export jobs through the job queue and hands
+// out signed links to their files
+type ExportService struct {
+	jobRepo     transcript.ExportJobRepository
+	messageRepo conversation.MessageRepository
+	tagRepo     conversation.TagRepository
+	storage     attachment.Storage // nil = exports are unavailable
+	queue       jobs.Queue
+	linkTTL     time.Duration
+}
+
+var _ tenant.LifecycleHook = (*ExportService)(nil)
+
+func NewExportService(
+	jobRepo transcript.ExportJobRepository,
+	messageRepo conversation.MessageRepository,
+	tagRepo conversation.TagRepository,
+	storage attachment.Storage,
+	queue jobs.Queue,
+	linkTTL time.Duration,
+) *ExportService {
+	return &ExportService{
+		jobRepo:     jobRepo,
+		messageRepo: messageRepo,
+		tagRepo:     tagRepo,
+		storage:     storage,
+		queue:       queue,
+		linkTTL:     linkTTL,
+	}
+}
+
+// ============================================================================
+// Jobs
+// ============================================================================
+
+// RequestExport validates the filters, records a pending job and queues it
+func (s *ExportService) RequestExport(ctx context.Context, tenantID kernel.TenantID, requestedBy kernel.UserID, req transcript.CreateExportRequest) (*transcript.ExportJob, error) {
+	if s.storage == nil {
+		return nil, transcript.ErrExportsNotConfigured()
+	}
+
+	format := req.Format
+	if format == "" {
+		format = transcript.FormatJSONL
+	}
+	if !format.IsValid() {
+		return nil, transcript.ErrInvalidExport().WithDetail("format", "must be jsonl or csv")
+	}
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return nil, transcript.ErrInvalidExport().WithDetail("to", "must be after from")
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.jobRepo.CountActive(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if active >= maxActiveExports {
+		return nil, transcript.ErrTooManyExports().WithDetail("max_active", maxActiveExports)
+	}
+
+	job := transcript.NewExportJob(tenantID, requestedBy, format, transcript.Filters{
+		From:      req.From,
+		To:        req.To,
+		ChannelID: req.ChannelID,
+		Tags:      tags,
+	})
+	if err := s.jobRepo.Save(ctx, *job); err != nil {
+		return nil, err
+	}
+
+	// The job runner writes the file, outliving the HTTP request
+	if _, err := s.queue.Enqueue(ctx, jobs.EnqueueRequest{
+		Kind:        ExportJobKind,
+		TenantID:    &tenantID,
+		Payload:     exportPayload{ExportID: job.ID},
+		MaxAttempts: exportAttempts,
+	}); err != nil {
+		s.finish(job, err)
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// GetExport returns a job with a download link once its file is ready
+func (s *ExportService) GetExport(ctx context.Context, id string, tenantID kernel.TenantID) (*transcript.ExportResponse, error) {
+	job, err := s.jobRepo.FindByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &transcript.ExportResponse{ExportJob: job}
+	if !job.IsReady() || s.storage == nil {
+		return response, nil
+	}
+
+	url, err := s.storage.SignedURL(ctx, job.StorageKey, s.linkTTL)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(s.linkTTL)
+	response.URL = url
+	response.URLExpiresAt = &expiresAt
+
+	return response, nil
+}
+
+// ListExports returns the tenant's jobs, newest first
+func (s *ExportService) ListExports(ctx context.Context, req transcript.ListExportsRequest) (transcript.ExportListResponse, error) {
+	return s.jobRepo.List(ctx, req)
+}
+
+// RunExportJob is the handler of ExportJobKind. An export found running
+// was interrupted by a restart and starts over; a completed one is left
+// alone.
+func (s *ExportService) RunExportJob(ctx context.Context, job jobs.Job) error {
+	var payload exportPayload
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+	if job.TenantID == nil {
+		return jobs.ErrInvalidJob().WithDetail("job_id", job.ID).WithDetail("reason", "tenant_id is required")
+	}
+
+	export, err := s.jobRepo.FindByID(ctx, payload.ExportID, *job.TenantID)
+	if err != nil {
+		return err
+	}
+	if export.Status == transcript.StatusCompleted {
+		return nil
+	}
+
+	return s.run(ctx, *export)
+}
+
+// OnTenantLifecycle implements tenant.LifecycleHook: deleting a tenant
+// deletes its export files too
+func (s *ExportService) OnTenantLifecycle(ctx context.Context, tenantID kernel.TenantID, event tenant.LifecycleEvent) error {
+	if event != tenant.LifecycleDeleted || s.storage == nil {
+		return nil
+	}
+
+	req := transcript.ListExportsRequest{TenantID: tenantID, Status: transcript.StatusCompleted}
+	req.Page, req.PageSize = 1, 100
+	for {
+		page, err := s.jobRepo.List(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, job := range page.Data {
+			if err := s.storage.Delete(ctx, job.StorageKey); err != nil {
+				log.Printf("⚠️  Failed to delete transcript export %s: %v", job.ID, err)
+			}
+		}
+		if len(page.Data) < req.PageSize {
+			return nil
+		}
+		req.Page++
+	}
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// exportPayload is the payload of an ExportJobKind job
+type exportPayload struct {
+	ExportID string `json:"export_id"`
+}
+
+// run writes the export file; the error is returned too so the job queue
+// can retry it
+func (s *ExportService) run(ctx context.Context, job transcript.ExportJob) error {
+	filter := job.Filters.MessageFilter(job.TenantID)
+	total, err := s.messageRepo.CountMatching(ctx, filter)
+	if err != nil {
+		s.finish(&job, err)
+		return err
+	}
+
+	job.Start(total)
+	if err := s.jobRepo.Save(ctx, job); err != nil {
+		return err
+	}
+	log.Printf("📦 Exporting %d messages of tenant %s (%s)", total, job.TenantID, job.ID)
+
+	err = s.export(ctx, &job, filter)
+	s.finish(&job, err)
+	return err
+}
+
+// export writes the file aside, then stores it under the job's key
+func (s *ExportService) export(ctx context.Context, job *transcript.ExportJob, filter conversation.MessageFilter) error {
+	file, err := os.CreateTemp("", "relay-transcripts-*."+string(job.Format))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if err := s.writeRecords(ctx, job, filter, file); err != nil {
+		return err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	key := job.FileKey()
+	if err := s.storage.Put(ctx, key, file, size, job.Format.ContentType()); err != nil {
+		return fmt.Errorf("failed to store export file: %w", err)
+	}
+
+	job.Complete(key, size)
+	return nil
+}
+
+// writeRecords pages through the matching messages, one conversation after
+// the other, saving the job's progress after each page
+func (s *ExportService) writeRecords(ctx context.Context, job *transcript.ExportJob, filter conversation.MessageFilter, file *os.File) error {
+	writer, err := newRecordWriter(job.Format, file)
+	if err != nil {
+		return err
+	}
+
+	var cursor *conversation.MessageCursor
+	var tags []string
+	for {
+		messages, err := s.messageRepo.ListMatching(ctx, filter, cursor, exportPageSize)
+		if err != nil {
+			return err
+		}
+
+		conversations := 0
+		for _, msg := range messages {
+			// Messages come grouped by conversation; tags are looked up once each
+			if cursor == nil || cursor.ChannelID != msg.ChannelID || cursor.ConversationID != msg.ConversationID {
+				conversations++
+				if tags, err = s.conversationTags(ctx, msg); err != nil {
+					return err
+				}
+			}
+			if err := writer.Write(transcript.NewRecord(msg, tags)); err != nil {
+				return err
+			}
+			cursor = conversation.CursorOf(msg)
+		}
+
+		job.Advance(len(messages), conversations)
+		if err := s.jobRepo.Save(ctx, *job); err != nil {
+			log.Printf("⚠️  Failed to save progress of transcript export %s: %v", job.ID, err)
+		}
+
+		if len(messages) < exportPageSize {
+			return writer.Flush()
+		}
+	}
+}
+
+func (s *ExportService) conversationTags(ctx context.Context, msg conversation.Message) ([]string, error) {
+	tags, err := s.tagRepo.ListByConversation(ctx, msg.TenantID, &msg.ChannelID, msg.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag.Kind == conversation.TagKindTag {
+			names = append(names, tag.Name)
+		}
+	}
+	return names, nil
+}
+
+// finish records the outcome; the job's own context may be what expired
+func (s *ExportService) finish(job *transcript.ExportJob, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err != nil {
+		log.Printf("⚠️  Transcript export %s of tenant %s failed: %v", job.ID, job.TenantID, err)
+		job.Fail(err)
+	} else {
+		log.Printf("✅ Transcript export %s of tenant %s completed (%d messages, %d bytes)",
+			job.ID, job.TenantID, job.Progress.ProcessedMessages, job.SizeBytes)
+	}
+
+	if err := s.jobRepo.Save(ctx, *job); err != nil {
+		log.Printf("⚠️  Failed to save transcript export %s: %v", job.ID, err)
+	}
+}
+
+// normalizeTags normalizes and deduplicates the tag filter
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		name, err := conversation.NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,88 @@
+package transcriptsrv
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/relay/transcript"
+)
+
+// recordWriter writes export records in one of the export formats
+type recordWriter interface {
+	Write(record transcript.Record) error
+	Flush() error
+}
+
+func newRecordWriter(format transcript.Format, w io.Writer) (recordWriter, error) {
+	if format == transcript.FormatCSV {
+		writer := csv.NewWriter(w)
+		if err := writer.Write(transcript.CSVHeader); err != nil {
+			return nil, err
+		}
+		return &csvRecordWriter{writer: writer}, nil
+	}
+
+	buffered := bufio.NewWriter(w)
+	return &jsonlRecordWriter{buffered: buffered, encoder: json.NewEncoder(buffered)}, nil
+}
+
+// jsonlRecordWriter writes one JSON object per line
+type jsonlRecordWriter struct {
+	buffered *bufio.Writer
+	encoder  *json.Encoder
+}
+
+func (w *jsonlRecordWriter) Write(record transcript.Record) error {
+	return w.encoder.Encode(record)
+}
+
+func (w *jsonlRecordWriter) Flush() error {
+	return w.buffered.Flush()
+}
+
+// csvRecordWriter writes one row per message in CSVHeader's column order
+type csvRecordWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvRecordWriter) Write(record transcript.Record) error {
+	content, err := json.Marshal(record.Content)
+	if err != nil {
+		return err
+	}
+	msgContext := []byte("{}")
+	if len(record.Context) > 0 {
+		if msgContext, err = json.Marshal(record.Context); err != nil {
+			return err
+		}
+	}
+
+	return w.writer.Write([]string{
+		record.MessageID,
+		record.ChannelID,
+		record.ConversationID,
+		record.SenderID,
+		record.Direction,
+		record.Origin,
+		record.Type,
+		record.Text,
+		record.Status,
+		record.ProviderMessageID,
+		record.WorkflowID,
+		record.NodeID,
+		strings.Join(record.Tags, ";"),
+		strings.Join(record.AttachmentURLs, " "),
+		string(content),
+		string(msgContext),
+		record.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+func (w *csvRecordWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}